@@ -10,8 +10,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/grpcserver"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/client/rpcserver"
 	"decred.org/dcrdex/client/webserver"
@@ -31,8 +33,13 @@ const (
 	walletPairTwoHost  = "127.0.0.7"
 	defaultRPCPort     = "5757"
 	defaultWebPort     = "5758"
+	defaultGRPCPort    = "5759"
 	defaultLogLevel    = "debug"
 	configFilename     = "dexc.conf"
+
+	// defaultShutdownWait is how long to wait for active orders to settle
+	// when stopped with SIGTERM.
+	defaultShutdownWait = 10 * time.Minute
 )
 
 var (
@@ -47,6 +54,13 @@ type RPCConfig struct {
 	RPCPass string `long:"rpcpass" description:"RPC server password"`
 	RPCCert string `long:"rpccert" description:"RPC server certificate file location"`
 	RPCKey  string `long:"rpckey" description:"RPC server key file location"`
+	// RPCAuthMode and RPCClientCerts configure client certificate
+	// authentication for the RPC server.
+	RPCAuthMode    string `long:"rpcauthmode" choice:"password" choice:"cert" choice:"both" description:"RPC server authentication: password, client certificate (cert), or both. The cert modes require rpcclientcerts."`
+	RPCClientCerts string `long:"rpcclientcerts" description:"Path of the JSON allow-list of RPC client certificates and their permission scopes"`
+	// GRPCAddr is the gRPC server listen address. The gRPC server uses the
+	// RPC server's credentials and TLS key pair.
+	GRPCAddr string `long:"grpcaddr" description:"gRPC server listen address"`
 	// CertHosts is a list of hosts given to certgen.NewTLSCertPair for the
 	// "Subject Alternate Name" values of the generated TLS certificate. It is
 	// set automatically, not via the config file or cli args.
@@ -85,6 +99,8 @@ func (cfg *RPCConfig) RPC(c *core.Core, marketMaker *mm.MarketMaker, log dex.Log
 		Cert:        cfg.RPCCert,
 		Key:         cfg.RPCKey,
 		BWVersion:   bwVersion,
+		AuthMode:    cfg.RPCAuthMode,
+		ClientCerts: cfg.RPCClientCerts,
 		CertHosts: []string{
 			defaultTestnetHost, defaultSimnetHost, defaultMainnetHost,
 			walletPairOneHost, walletPairTwoHost,
@@ -92,6 +108,29 @@ func (cfg *RPCConfig) RPC(c *core.Core, marketMaker *mm.MarketMaker, log dex.Log
 	}
 }
 
+// GRPC creates a gRPC server configuration.
+func (cfg *RPCConfig) GRPC(c *core.Core, marketMaker *mm.MarketMaker, log dex.Logger) *grpcserver.Config {
+	grpcserver.SetLogger(log)
+	grpcCfg := &grpcserver.Config{
+		Core:    c,
+		Addr:    cfg.GRPCAddr,
+		User:    cfg.RPCUser,
+		Pass:    cfg.RPCPass,
+		Cert:    cfg.RPCCert,
+		Key:     cfg.RPCKey,
+		Version: Version,
+		CertHosts: []string{
+			defaultTestnetHost, defaultSimnetHost, defaultMainnetHost,
+			walletPairOneHost, walletPairTwoHost,
+		},
+	}
+	// Don't set a nil *mm.MarketMaker, which would be a non-nil interface.
+	if marketMaker != nil {
+		grpcCfg.MarketMaker = marketMaker
+	}
+	return grpcCfg
+}
+
 // CoreConfig encapsulates the settings specific to core.Core.
 type CoreConfig struct {
 	DBPath       string `long:"db" description:"Database filepath. Database will be created if it does not exist."`
@@ -105,9 +144,18 @@ type CoreConfig struct {
 
 	NoAutoWalletLock   bool `long:"no-wallet-lock" description:"Disable locking of wallets on shutdown or logout. Use this if you want your external wallets to stay unlocked after closing the DEX app."`
 	NoAutoDBBackup     bool `long:"no-db-backup" description:"Disable creation of a database backup on shutdown."`
+	NoWSCompression    bool `long:"no-ws-compression" description:"Do not negotiate websocket compression with DEX servers. Compression reduces bandwidth at the cost of CPU time."`
+	EnableQUIC         bool `long:"quic" description:"Connect over QUIC to DEX servers that support it, falling back to TCP. QUIC is not used if a proxy is set in the environment, or with Tor."`
+	NoCBOR             bool `long:"no-cbor" description:"Do not accept CBOR-encoded order books and candles from DEX servers that support it. All messages are JSON-encoded instead."`
 	UnlockCoinsOnLogin bool `long:"release-wallet-coins" description:"On login or wallet creation, instruct the wallet to release any coins that it may have locked."`
 
+	DBBackupInterval  time.Duration `long:"db-backup-interval" description:"Back up the database at this interval while running, e.g. 24h. Disabled by default."`
+	DBBackupDir       string        `long:"db-backup-dir" description:"Directory of the scheduled database backups. Default is the backup/scheduled folder next to the database."`
+	DBBackupRetention int           `long:"db-backup-retention" description:"Number of scheduled database backups to keep. Default is 7."`
+	BookRecordDir     string        `long:"book-record-dir" description:"Record the order books of subscribed markets to this directory, for replaying the book at a past moment. Disabled by default."`
+
 	ExtensionModeFile string `long:"extension-mode-file" description:"path to a file that specifies options for running core as an extension."`
+	TokenRegistryFile string `long:"token-registry" description:"path to a JSON file of additional token definitions to register on startup."`
 }
 
 // WebConfig encapsulates the configuration needed for the web server.
@@ -120,6 +168,15 @@ type WebConfig struct {
 	// Deprecated
 	Experimental bool `long:"experimental" description:"DEPRECATED: Enable experimental features"`
 	Tor          bool `long:"tor" description:"Enable tor hidden service"`
+
+	ACMEDomains   []string `long:"webacmedomain" description:"Use HTTPS with a certificate for this domain obtained and renewed automatically from an ACME provider (Let's Encrypt by default). The domain must resolve to this machine, and webaddr must be reachable on port 443, or webacmehttp on port 80. May be specified multiple times. Overrides webtls."`
+	ACMEEmail     string   `long:"webacmeemail" description:"Optional contact email for the ACME account."`
+	ACMEDirectory string   `long:"webacmedir" description:"ACME directory URL of the certificate authority. Default is Let's Encrypt."`
+	ACMEHTTPAddr  string   `long:"webacmehttp" description:"Address to listen on for ACME HTTP-01 challenges, e.g. :80. Other requests to this address are redirected to HTTPS."`
+
+	CORSOrigins []string `long:"webcorsorigin" description:"Allow cross-origin API requests from an alternative frontend hosted at this origin, e.g. https://ui.example.com. Requests authorized by the session cookie must send a CSRF token from /api/v1/csrf. Session cookies are only sent cross-site with HTTPS. May be specified multiple times."`
+
+	GuestPassword string `long:"webguestpass" description:"Enable read-only guest logins to the web UI with this password, for monitoring balances, orders, and bots from less trusted devices. Must not be the app password."`
 }
 
 // LogConfig encapsulates the logging-related settings.
@@ -154,39 +211,69 @@ type Config struct {
 	Testnet    bool   `long:"testnet" description:"use testnet"`
 	Simnet     bool   `long:"simnet" description:"use simnet"`
 	RPCOn      bool   `long:"rpc" description:"turn on the rpc server"`
+	GRPCOn     bool   `long:"grpc" description:"turn on the gRPC server"`
 	NoWeb      bool   `long:"noweb" description:"disable the web server."`
 	CPUProfile string `long:"cpuprofile" description:"File for CPU profiling."`
+	PIDFile    string `long:"pidfile" description:"Write the process ID to this file, and remove it on exit."`
 	ShowVer    bool   `short:"V" long:"version" description:"Display version information and exit"`
 	Language   string `long:"lang" description:"BCP 47 tag for preferred language, e.g. en-GB, fr, zh-CN"`
+
+	// ShutdownWait is how long a SIGTERM waits for active orders to settle
+	// before shutting down anyway.
+	ShutdownWait time.Duration `long:"shutdownwait" description:"When stopped with SIGTERM, e.g. by systemd, wait this long for active orders to settle before shutting down anyway. A second SIGTERM shuts down immediately."`
+
+	// Provision is a provisioning file for headless deployments. See
+	// Provisioning.
+	Provision string `long:"provision" description:"Path to a JSON provisioning file. Each start, bisonw initializes or logs in with the file's app password, creates missing wallets, posts bonds to hosts with no account, and starts the listed market making bots. The file must only be readable by its owner."`
+
+	// Networks runs multiple networks in one process. The first is the
+	// primary network, which the other settings apply to. ResolveConfig
+	// creates the configurations for the others.
+	Networks      []string `long:"networks" description:"Run these networks in one process, e.g. mainnet,testnet,simnet. The first network uses the configured addresses and paths. Each of the others has its own database and servers at the network's default addresses, and the web UI can be reached at /<network>/ on the first network's web server."`
+	extraNetworks []*Config
+	// serverDir is the directory for the web server's data and certificate,
+	// if not the app data directory.
+	serverDir string
 }
 
 // Web creates a configuration for the webserver. This is a Config method
 // instead of a WebConfig method because Language is an app-level setting used
 // by both core and rpcserver.
 func (cfg *Config) Web(c *core.Core, mm *mm.MarketMaker, log dex.Logger, utc bool) *webserver.Config {
-	addr := cfg.WebAddr
-	host, _, err := net.SplitHostPort(addr)
-	if err == nil && host != "" {
-		addr = host
-	} else {
-		// If SplitHostPort failed, IPv6 addresses may still have brackets.
-		addr = strings.Trim(addr, "[]")
-	}
-	ip := net.ParseIP(addr)
-
 	var mmCore webserver.MMCore
 	if mm != nil {
 		mmCore = mm
 	}
 
+	serverDir := cfg.AppData
+	if cfg.serverDir != "" {
+		serverDir = cfg.serverDir
+	}
+
 	var certFile, keyFile string
-	if cfg.WebTLS || (ip != nil && !ip.IsLoopback() && !ip.IsPrivate()) || (ip == nil && addr != "localhost") {
-		certFile = filepath.Join(cfg.AppData, "web.cert")
-		keyFile = filepath.Join(cfg.AppData, "web.key")
+	var acmeCfg *webserver.ACMEConfig
+	if len(cfg.ACMEDomains) > 0 {
+		acmeCfg = &webserver.ACMEConfig{
+			Domains:      cfg.ACMEDomains,
+			Email:        cfg.ACMEEmail,
+			DirectoryURL: cfg.ACMEDirectory,
+			HTTPAddr:     cfg.ACMEHTTPAddr,
+		}
+	} else if cfg.webCertRequired() {
+		certFile = filepath.Join(serverDir, "web.cert")
+		keyFile = filepath.Join(serverDir, "web.key")
+	}
+
+	var netURLs map[string]string
+	if len(cfg.extraNetworks) > 0 {
+		netURLs = make(map[string]string, len(cfg.extraNetworks))
+		for _, netCfg := range cfg.extraNetworks {
+			netURLs[netCfg.Net.String()] = netCfg.webURL()
+		}
 	}
 
 	return &webserver.Config{
-		DataDir:         filepath.Join(cfg.AppData, "srv"),
+		DataDir:         filepath.Join(serverDir, "srv"),
 		Core:            c,
 		MarketMaker:     mmCore,
 		Addr:            cfg.WebAddr,
@@ -195,13 +282,42 @@ func (cfg *Config) Web(c *core.Core, mm *mm.MarketMaker, log dex.Logger, utc boo
 		UTC:             utc,
 		CertFile:        certFile,
 		KeyFile:         keyFile,
+		ACME:            acmeCfg,
+		AllowedOrigins:  cfg.CORSOrigins,
+		GuestPassword:   cfg.GuestPassword,
 		NoEmbed:         cfg.NoEmbedSite,
 		HttpProf:        cfg.HTTPProfile,
 		AppVersion:      userAppVersion(Version),
 		Language:        cfg.Language,
 		Tor:             cfg.Tor,
 		MainLogFilePath: cfg.LogPath,
+		NetworkURLs:     netURLs,
+	}
+}
+
+// webCertRequired is true if the web server must use TLS with a self-signed
+// certificate because TLS is requested or the server is not on a loopback or
+// private address.
+func (cfg *Config) webCertRequired() bool {
+	addr := cfg.WebAddr
+	host, _, err := net.SplitHostPort(addr)
+	if err == nil && host != "" {
+		addr = host
+	} else {
+		// If SplitHostPort failed, IPv6 addresses may still have brackets.
+		addr = strings.Trim(addr, "[]")
 	}
+	ip := net.ParseIP(addr)
+	return cfg.WebTLS || (ip != nil && !ip.IsLoopback() && !ip.IsPrivate()) || (ip == nil && addr != "localhost")
+}
+
+// webURL is the URL of the web server.
+func (cfg *Config) webURL() string {
+	scheme := "http"
+	if len(cfg.ACMEDomains) > 0 || cfg.webCertRequired() {
+		scheme = "https"
+	}
+	return scheme + "://" + cfg.WebAddr
 }
 
 // Core creates a core.Core configuration. This is a Config method
@@ -219,7 +335,15 @@ func (cfg *Config) Core(log dex.Logger) *core.Config {
 		UnlockCoinsOnLogin: cfg.UnlockCoinsOnLogin,
 		NoAutoWalletLock:   cfg.NoAutoWalletLock,
 		NoAutoDBBackup:     cfg.NoAutoDBBackup,
+		NoWSCompression:    cfg.NoWSCompression,
+		EnableQUIC:         cfg.EnableQUIC,
+		NoCBOR:             cfg.NoCBOR,
+		DBBackupInterval:   cfg.DBBackupInterval,
+		DBBackupDir:        cfg.DBBackupDir,
+		DBBackupRetention:  cfg.DBBackupRetention,
+		BookRecordDir:      cfg.BookRecordDir,
 		ExtensionModeFile:  cfg.ExtensionModeFile,
+		TokenRegistryFile:  cfg.TokenRegistryFile,
 		TheOneHost:         cfg.TheOneHost,
 	}
 }
@@ -231,6 +355,7 @@ var DefaultConfig = Config{
 	RPCConfig: RPCConfig{
 		CertHosts: []string{defaultTestnetHost, defaultSimnetHost, defaultMainnetHost},
 	},
+	ShutdownWait: defaultShutdownWait,
 }
 
 // ParseCLIConfig parses the command-line arguments into the provided struct
@@ -305,6 +430,20 @@ func ResolveConfig(appData string, cfg *Config) error {
 		return fmt.Errorf("simnet and testnet cannot both be specified")
 	}
 
+	nets, err := parseNetworks(cfg.Networks)
+	if err != nil {
+		return err
+	}
+	// The additional networks are resolved from the settings before defaults
+	// are applied, with the paths and addresses left to their defaults.
+	unresolved := *cfg
+	if len(nets) > 0 {
+		if cfg.Simnet || cfg.Testnet {
+			return fmt.Errorf("testnet and simnet cannot be used with networks")
+		}
+		cfg.Testnet, cfg.Simnet = nets[0] == dex.Testnet, nets[0] == dex.Simnet
+	}
+
 	cfg.AppData = appData
 
 	var defaultDBPath, defaultLogPath, defaultMMEventLogDBPath, defaultMMConfigPath string
@@ -321,7 +460,7 @@ func ResolveConfig(appData string, cfg *Config) error {
 	}
 	defaultHost := DefaultHostByNetwork(cfg.Net)
 
-	// If web or RPC server addresses not set, use network specific
+	// If web, RPC or gRPC server addresses not set, use network specific
 	// defaults
 	if cfg.WebAddr == "" {
 		cfg.WebAddr = net.JoinHostPort(defaultHost, defaultWebPort)
@@ -329,6 +468,9 @@ func ResolveConfig(appData string, cfg *Config) error {
 	if cfg.RPCAddr == "" {
 		cfg.RPCAddr = net.JoinHostPort(defaultHost, defaultRPCPort)
 	}
+	if cfg.GRPCAddr == "" {
+		cfg.GRPCAddr = net.JoinHostPort(defaultHost, defaultGRPCPort)
+	}
 
 	if cfg.RPCCert == "" {
 		cfg.RPCCert = filepath.Join(appData, defaultRPCCertFile)
@@ -342,6 +484,14 @@ func ResolveConfig(appData string, cfg *Config) error {
 		cfg.DBPath = defaultDBPath
 	}
 
+	if cfg.DBBackupDir != "" {
+		cfg.DBBackupDir = dex.CleanAndExpandPath(cfg.DBBackupDir)
+	}
+
+	if cfg.BookRecordDir != "" {
+		cfg.BookRecordDir = dex.CleanAndExpandPath(cfg.BookRecordDir)
+	}
+
 	if cfg.LogPath == "" {
 		cfg.LogPath = defaultLogPath
 	}
@@ -354,9 +504,72 @@ func ResolveConfig(appData string, cfg *Config) error {
 		cfg.MMConfig.EventLogDBPath = defaultMMEventLogDBPath
 	}
 
+	cfg.extraNetworks = nil
+	for i := 1; i < len(nets); i++ {
+		network := nets[i]
+		netCfg := unresolved
+		netCfg.Networks, netCfg.extraNetworks = nil, nil
+		netCfg.Provision = ""
+		netCfg.Testnet, netCfg.Simnet = network == dex.Testnet, network == dex.Simnet
+		netCfg.DBPath, netCfg.MMConfig = "", MMConfig{}
+		// The scheduled backups of each network go in a subdirectory.
+		if netCfg.DBBackupDir != "" {
+			netCfg.DBBackupDir = filepath.Join(netCfg.DBBackupDir, network.String())
+		}
+		if netCfg.BookRecordDir != "" {
+			netCfg.BookRecordDir = filepath.Join(netCfg.BookRecordDir, network.String())
+		}
+		netCfg.WebAddr, netCfg.RPCAddr, netCfg.GRPCAddr = "", "", ""
+		// API keys and server certificates are not shared, since the
+		// certificates are for the primary network's addresses.
+		netCfg.serverDir = filepath.Join(appData, network.String())
+		netCfg.RPCCert = filepath.Join(netCfg.serverDir, defaultRPCCertFile)
+		netCfg.RPCKey = filepath.Join(netCfg.serverDir, defaultRPCKeyFile)
+		// A web server's hidden service and ACME certificate are for a single
+		// address.
+		netCfg.Tor, netCfg.ACMEDomains = false, nil
+		if err := ResolveConfig(appData, &netCfg); err != nil {
+			return fmt.Errorf("error configuring %s: %w", network, err)
+		}
+		// There is one log file for the process.
+		netCfg.LogPath = cfg.LogPath
+		cfg.extraNetworks = append(cfg.extraNetworks, &netCfg)
+	}
+
 	return nil
 }
 
+// ExtraNetworks are the configurations for the networks after the first when
+// running multiple networks in one process. See Config.Networks.
+func (cfg *Config) ExtraNetworks() []*Config {
+	return cfg.extraNetworks
+}
+
+// parseNetworks parses the --networks values, which may also be comma
+// separated.
+func parseNetworks(names []string) ([]dex.Network, error) {
+	var nets []dex.Network
+	seen := make(map[dex.Network]bool)
+	for _, name := range names {
+		for _, name := range strings.Split(name, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			net, err := dex.NetFromString(name)
+			if err != nil {
+				return nil, err
+			}
+			if seen[net] {
+				return nil, fmt.Errorf("network %s specified more than once", net)
+			}
+			seen[net] = true
+			nets = append(nets, net)
+		}
+	}
+	return nets, nil
+}
+
 // userAppVersion returns a simple user-facing version: maj.min.patch.
 func userAppVersion(fullVersion string) string {
 	parts := strings.Split(fullVersion, "-")
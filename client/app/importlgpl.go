@@ -10,8 +10,9 @@
 package app
 
 import (
-	_ "decred.org/dcrdex/client/asset/eth"     // register eth asset
-	_ "decred.org/dcrdex/client/asset/polygon" // register polygon network
+	_ "decred.org/dcrdex/client/asset/arbitrum" // register arbitrum network
+	_ "decred.org/dcrdex/client/asset/eth"      // register eth asset
+	_ "decred.org/dcrdex/client/asset/polygon"  // register polygon network
 	dexeth "decred.org/dcrdex/dex/networks/eth"
 	dexpolygon "decred.org/dcrdex/dex/networks/polygon"
 )
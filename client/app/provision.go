@@ -0,0 +1,230 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/dex"
+)
+
+// provisionRetryInterval is how often posting bonds and starting bots are
+// retried while wallets sync or wait for funds.
+const provisionRetryInterval = time.Minute
+
+// Provisioning is the content of a provisioning file, which sets up the client
+// without user interaction. Each step is skipped if it is already done, so the
+// same file can be used every time the client starts.
+type Provisioning struct {
+	// AppPassword is the app password to set on the first run, and to log in
+	// with on later runs.
+	AppPassword string `json:"appPassword"`
+	// Seed is a mnemonic or hex seed to restore from on the first run. If
+	// empty, a new seed is generated.
+	Seed string `json:"seed,omitempty"`
+	// Wallets are created if they do not exist. A token's parent asset wallet
+	// must be listed before the token wallet.
+	Wallets []*ProvisionWallet `json:"wallets"`
+	// Bonds are posted to DEX hosts with no account.
+	Bonds []*ProvisionBond `json:"bonds"`
+	// BotConfigPath is the market making configuration file for the bots. If
+	// empty, the default configuration is used.
+	BotConfigPath string `json:"botConfigPath,omitempty"`
+	// Bots are the market making bots to start, with the balances allocated
+	// to them. The bots must be in the market making configuration.
+	Bots []*mm.StartConfig `json:"bots"`
+}
+
+// ProvisionWallet is a wallet to create.
+type ProvisionWallet struct {
+	AssetID  uint32            `json:"assetID"`
+	Type     string            `json:"type"`
+	Config   map[string]string `json:"config"`
+	Password string            `json:"password,omitempty"`
+}
+
+// ProvisionBond is a bond to post to a DEX host.
+type ProvisionBond struct {
+	Host string `json:"host"`
+	// Cert is the path to the host's TLS certificate, if needed.
+	Cert    string `json:"cert,omitempty"`
+	AssetID uint32 `json:"assetID"`
+	// Tier is the trading tier to maintain. The default is 1.
+	Tier uint64 `json:"tier"`
+}
+
+// LoadProvisioning reads and checks a provisioning file.
+func LoadProvisioning(path string) (*Provisioning, error) {
+	path = dex.CleanAndExpandPath(path)
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading provisioning file: %w", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading provisioning file: %w", err)
+	}
+	p := new(Provisioning)
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("error parsing provisioning file: %w", err)
+	}
+	if p.AppPassword == "" {
+		return nil, errors.New("no app password in provisioning file")
+	}
+	// The file has the app password and maybe the seed.
+	if runtime.GOOS != "windows" && fi.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("provisioning file %s must only be readable by its owner", path)
+	}
+	for _, b := range p.Bonds {
+		if b.Host == "" {
+			return nil, errors.New("bond with no host in provisioning file")
+		}
+		if b.Tier == 0 {
+			b.Tier = 1
+		}
+	}
+	for _, bot := range p.Bots {
+		if bot.Alloc == nil {
+			return nil, fmt.Errorf("no balance allocation for %s bot in provisioning file", bot.MarketWithHost)
+		}
+	}
+	return p, nil
+}
+
+// Run sets up the client. Wallets are created right away. Posting bonds and
+// starting bots may need synced and funded wallets, so they are retried until
+// they succeed or the context is canceled.
+func (p *Provisioning) Run(ctx context.Context, c *core.Core, m *mm.MarketMaker, log dex.Logger) error {
+	pw := []byte(p.AppPassword)
+	if !c.IsInitialized() {
+		var seed *string
+		if p.Seed != "" {
+			seed = &p.Seed
+		}
+		if _, err := c.InitializeClient(pw, seed); err != nil {
+			return fmt.Errorf("error initializing client: %w", err)
+		}
+		if seed == nil {
+			log.Warnf("Initialized with a new app seed. Back it up with the appseed command or in the settings.")
+		} else {
+			log.Infof("Initialized with the provisioned app seed.")
+		}
+	}
+	if err := c.Login(pw); err != nil {
+		return fmt.Errorf("login error: %w", err)
+	}
+
+	for _, w := range p.Wallets {
+		if c.WalletState(w.AssetID) != nil {
+			continue
+		}
+		form := &core.WalletForm{
+			AssetID: w.AssetID,
+			Config:  w.Config,
+			Type:    w.Type,
+		}
+		if err := c.CreateWallet(pw, []byte(w.Password), form); err != nil {
+			return fmt.Errorf("error creating %s wallet: %w", dex.BipIDSymbol(w.AssetID), err)
+		}
+		log.Infof("Created %s wallet.", dex.BipIDSymbol(w.AssetID))
+	}
+
+	if len(p.Bots) > 0 && m == nil {
+		return errors.New("market making is not available to start bots")
+	}
+
+	bonds, bots := p.Bonds, p.Bots
+	for {
+		bonds = p.postBonds(c, bonds, log)
+		// Bots need accounts.
+		if len(bonds) == 0 {
+			bots = p.startBots(m, bots, log)
+		}
+		if len(bonds) == 0 && len(bots) == 0 {
+			log.Infof("Provisioning complete.")
+			return nil
+		}
+		select {
+		case <-time.After(provisionRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// postBonds posts the bonds to hosts with no account, and returns the bonds
+// that could not be posted yet.
+func (p *Provisioning) postBonds(c *core.Core, bonds []*ProvisionBond, log dex.Logger) (remaining []*ProvisionBond) {
+	for _, b := range bonds {
+		if _, err := c.Exchange(b.Host); err == nil {
+			continue
+		}
+		var cert any
+		if b.Cert != "" {
+			cert = b.Cert
+		}
+		xc, err := c.GetDEXConfig(b.Host, cert)
+		if err != nil {
+			log.Errorf("Error getting %s config. Will retry: %v", b.Host, err)
+			remaining = append(remaining, b)
+			continue
+		}
+		symbol := dex.BipIDSymbol(b.AssetID)
+		bondAsset, found := xc.BondAssets[symbol]
+		if !found {
+			log.Errorf("%s does not accept %s bonds", b.Host, symbol)
+			continue
+		}
+		assetID, maintain := b.AssetID, true
+		_, err = c.PostBond(&core.PostBondForm{
+			Addr:         b.Host,
+			AppPass:      []byte(p.AppPassword),
+			Asset:        &assetID,
+			Bond:         bondAsset.Amt * b.Tier,
+			MaintainTier: &maintain,
+			Cert:         cert,
+		})
+		if err != nil {
+			log.Errorf("Error posting %s bond to %s. Will retry: %v", symbol, b.Host, err)
+			remaining = append(remaining, b)
+			continue
+		}
+		log.Infof("Posted %s bond for tier %d to %s.", symbol, b.Tier, b.Host)
+	}
+	return remaining
+}
+
+// startBots starts the bots that are not running, and returns the bots that
+// could not be started yet.
+func (p *Provisioning) startBots(m *mm.MarketMaker, bots []*mm.StartConfig, log dex.Logger) (remaining []*mm.StartConfig) {
+	running := make(map[mm.MarketWithHost]bool)
+	for _, bot := range m.RunningBotsStatus().Bots {
+		running[mm.MarketWithHost{Host: bot.Config.Host, BaseID: bot.Config.BaseID, QuoteID: bot.Config.QuoteID}] = true
+	}
+	var cfgPath *string
+	if p.BotConfigPath != "" {
+		path := dex.CleanAndExpandPath(p.BotConfigPath)
+		cfgPath = &path
+	}
+	for _, bot := range bots {
+		if running[bot.MarketWithHost] {
+			continue
+		}
+		if err := m.StartBot(bot, cfgPath, []byte(p.AppPassword), false); err != nil {
+			log.Errorf("Error starting %s bot. Will retry: %v", bot.MarketWithHost, err)
+			remaining = append(remaining, bot)
+			continue
+		}
+		log.Infof("Started %s bot.", bot.MarketWithHost)
+	}
+	return remaining
+}
@@ -0,0 +1,185 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package arbitrum
+
+import (
+	"fmt"
+	"strconv"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/eth"
+	"decred.org/dcrdex/dex"
+	dexarb "decred.org/dcrdex/dex/networks/arbitrum"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func registerToken(tokenID uint32, desc string) {
+	token, found := dexarb.Tokens[tokenID]
+	if !found {
+		panic("token " + strconv.Itoa(int(tokenID)) + " not known")
+	}
+	netAddrs := make(map[dex.Network]string)
+	netVersions := make(map[dex.Network][]uint32, 2)
+	for net, netToken := range token.NetTokens {
+		netAddrs[net] = netToken.Address.String()
+		netVersions[net] = make([]uint32, 0, 1)
+		for ver := range netToken.SwapContracts {
+			netVersions[net] = append(netVersions[net], ver)
+		}
+	}
+	asset.RegisterToken(tokenID, token.Token, &asset.WalletDefinition{
+		Type:        walletTypeToken,
+		Tab:         "Arbitrum token",
+		Description: desc,
+	}, netAddrs, netVersions)
+}
+
+func init() {
+	asset.Register(BipID, &Driver{})
+	registerToken(usdcTokenID, "The native USDC Arbitrum ERC20 token.")
+	registerToken(usdtTokenID, "The USDT Arbitrum ERC20 token.")
+}
+
+const (
+	// BipID is the BIP-0044 asset ID for Arbitrum One.
+	BipID              = 9001
+	defaultGasFeeLimit = 10
+	walletTypeRPC      = "rpc"
+	walletTypeToken    = "token"
+)
+
+var (
+	usdcTokenID, _ = dex.BipSymbolID("usdc.arbitrum")
+	usdtTokenID, _ = dex.BipSymbolID("usdt.arbitrum")
+
+	walletOpts = []*asset.ConfigOption{
+		{
+			Key:         "gasfeelimit",
+			DisplayName: "Gas Fee Limit",
+			Description: "This is the highest network fee rate you are willing to " +
+				"pay on swap transactions. If gasfeelimit is lower than a market's " +
+				"maxfeerate, you will not be able to trade on that market with this " +
+				"wallet.  Units: gwei / gas",
+			DefaultValue: defaultGasFeeLimit,
+		},
+	}
+	// WalletInfo defines some general information about an Arbitrum wallet.
+	WalletInfo = asset.WalletInfo{
+		Name:              "Arbitrum",
+		SupportedVersions: []uint32{1},
+		UnitInfo:          dexarb.UnitInfo,
+		AvailableWallets: []*asset.WalletDefinition{
+			{
+				Type:        walletTypeRPC,
+				Tab:         "External",
+				Description: "Infrastructure providers (e.g. Infura) or local nodes",
+				ConfigOpts:  append(eth.RPCOpts, walletOpts...),
+				Seeded:      true,
+				NoAuth:      true,
+			},
+		},
+		IsAccountBased: true,
+	}
+)
+
+type Driver struct{}
+
+// Open opens the Arbitrum exchange wallet. Start the wallet with its Run
+// method.
+func (d *Driver) Open(cfg *asset.WalletConfig, logger dex.Logger, net dex.Network) (asset.Wallet, error) {
+	chainCfg, err := ChainConfig(net)
+	if err != nil {
+		return nil, err
+	}
+	compat, err := NetworkCompatibilityData(net)
+	if err != nil {
+		return nil, err
+	}
+	contracts := make(map[uint32]common.Address, 1)
+	for ver, netAddrs := range dexarb.ContractAddresses {
+		if addr, found := netAddrs[net]; found {
+			contracts[ver] = addr
+		}
+	}
+
+	var defaultProviders []string
+	switch net {
+	case dex.Testnet:
+		defaultProviders = []string{
+			"https://sepolia-rollup.arbitrum.io/rpc",
+			"https://arbitrum-sepolia-rpc.publicnode.com",
+		}
+	case dex.Mainnet:
+		defaultProviders = []string{
+			"https://arb1.arbitrum.io/rpc",
+			"https://arbitrum-one-rpc.publicnode.com",
+			"https://rpc.ankr.com/arbitrum",
+			"https://arbitrum.llamarpc.com",
+		}
+	}
+
+	return eth.NewEVMWallet(&eth.EVMWalletConfig{
+		BaseChainID:        BipID,
+		ChainCfg:           chainCfg,
+		AssetCfg:           cfg,
+		CompatData:         &compat,
+		VersionedGases:     dexarb.VersionedGases,
+		Tokens:             dexarb.Tokens,
+		FinalizeConfs:      20,
+		Logger:             logger,
+		BaseChainContracts: contracts,
+		MultiBalAddress:    dexarb.MultiBalanceAddresses[net],
+		WalletInfo:         WalletInfo,
+		Net:                net,
+		DefaultProviders:   defaultProviders,
+		MaxTxFeeGwei:       dexeth.GweiFactor / 10, // 0.1 ETH
+		L1GasEstimator:     estimateL1Gas,
+	})
+}
+
+func (d *Driver) DecodeCoinID(coinID []byte) (string, error) {
+	return (&eth.Driver{}).DecodeCoinID(coinID)
+}
+
+func (d *Driver) Info() *asset.WalletInfo {
+	wi := WalletInfo
+	return &wi
+}
+
+func (d *Driver) Exists(walletType, dataDir string, settings map[string]string, net dex.Network) (bool, error) {
+	if walletType != walletTypeRPC {
+		return false, fmt.Errorf("unknown wallet type %q", walletType)
+	}
+	return (&eth.Driver{}).Exists(walletType, dataDir, settings, net)
+}
+
+func (d *Driver) Create(cfg *asset.CreateWalletParams) error {
+	compat, err := NetworkCompatibilityData(cfg.Net)
+	if err != nil {
+		return fmt.Errorf("error finding compatibility data: %v", err)
+	}
+	chainID, found := dexarb.ChainIDs[cfg.Net]
+	if !found {
+		return fmt.Errorf("Arbitrum is not available on network %s", cfg.Net)
+	}
+	return eth.CreateEVMWallet(chainID, cfg, &compat, false)
+}
+
+var _ asset.EVMChainIdentifier = (*Driver)(nil)
+
+// EVMChainID is the Arbitrum chain ID on the network. Part of the
+// asset.EVMChainIdentifier interface.
+func (d *Driver) EVMChainID(net dex.Network) (int64, bool) {
+	chainID, found := dexarb.ChainIDs[net]
+	return chainID, found
+}
+
+var _ asset.CustomTokenRegistrar = (*Driver)(nil)
+
+// RegisterCustomToken prepares a user-defined Arbitrum ERC20 token.
+// Part of the asset.CustomTokenRegistrar interface.
+func (d *Driver) RegisterCustomToken(ct *asset.CustomToken, net dex.Network) (uint32, *asset.Token, error) {
+	return eth.RegisterCustomToken(ct, net, dexarb.Tokens, "Arbitrum token")
+}
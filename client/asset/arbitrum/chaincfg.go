@@ -0,0 +1,52 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package arbitrum
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/client/asset/eth"
+	"decred.org/dcrdex/dex"
+	dexarb "decred.org/dcrdex/dex/networks/arbitrum"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// There are no historical tx and block hashes, so the providers are
+	// checked with the token contract only.
+	mainnetCompatibilityData = eth.CompatibilityData{
+		Addr:      dexarb.TokenUSDC.NetTokens[dex.Mainnet].Address,
+		TokenAddr: dexarb.TokenUSDC.NetTokens[dex.Mainnet].Address,
+	}
+
+	testnetCompatibilityData = eth.CompatibilityData{
+		Addr:      dexarb.TokenUSDC.NetTokens[dex.Testnet].Address,
+		TokenAddr: dexarb.TokenUSDC.NetTokens[dex.Testnet].Address,
+	}
+)
+
+// NetworkCompatibilityData returns the CompatibilityData for the specified
+// network.
+func NetworkCompatibilityData(net dex.Network) (c eth.CompatibilityData, err error) {
+	switch net {
+	case dex.Mainnet:
+		return mainnetCompatibilityData, nil
+	case dex.Testnet:
+		return testnetCompatibilityData, nil
+	default:
+		return c, fmt.Errorf("no compatibility data for network %s", net)
+	}
+}
+
+// ChainConfig returns the core configuration for the blockchain.
+func ChainConfig(net dex.Network) (c *params.ChainConfig, err error) {
+	switch net {
+	case dex.Mainnet:
+		return dexarb.MainnetChainConfig, nil
+	case dex.Testnet:
+		return dexarb.TestnetChainConfig, nil
+	default:
+		return c, fmt.Errorf("Arbitrum is not available on network %s", net)
+	}
+}
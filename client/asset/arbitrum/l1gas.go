@@ -0,0 +1,75 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package arbitrum
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nodeInterfaceAddress is the address of Arbitrum's NodeInterface, a virtual
+// contract that is only available through eth_call.
+// https://docs.arbitrum.io/build-decentralized-apps/nodeinterface/reference
+var nodeInterfaceAddress = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+
+const nodeInterfaceABIJSON = `[{
+	"name": "gasEstimateL1Component",
+	"type": "function",
+	"stateMutability": "payable",
+	"inputs": [
+		{"name": "to", "type": "address"},
+		{"name": "contractCreation", "type": "bool"},
+		{"name": "data", "type": "bytes"}
+	],
+	"outputs": [
+		{"name": "gasEstimateForL1", "type": "uint64"},
+		{"name": "baseFee", "type": "uint256"},
+		{"name": "l1BaseFeeEstimate", "type": "uint256"}
+	]
+}]`
+
+var nodeInterfaceABI = mustParseABI(nodeInterfaceABIJSON)
+
+func mustParseABI(s string) *abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(fmt.Sprintf("error parsing NodeInterface ABI: %v", err))
+	}
+	return &parsed
+}
+
+// estimateL1Gas uses the NodeInterface to estimate the L2 gas that will be
+// charged for posting the transaction data to L1.
+func estimateL1Gas(ctx context.Context, cb bind.ContractCaller, to *common.Address, data []byte) (uint64, error) {
+	var toAddr common.Address
+	if to != nil {
+		toAddr = *to
+	}
+	callData, err := nodeInterfaceABI.Pack("gasEstimateL1Component", toAddr, to == nil, data)
+	if err != nil {
+		return 0, fmt.Errorf("error packing gasEstimateL1Component call: %w", err)
+	}
+	res, err := cb.CallContract(ctx, ethereum.CallMsg{
+		To:   &nodeInterfaceAddress,
+		Data: callData,
+	}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("gasEstimateL1Component error: %w", err)
+	}
+	outputs, err := nodeInterfaceABI.Unpack("gasEstimateL1Component", res)
+	if err != nil {
+		return 0, fmt.Errorf("error unpacking gasEstimateL1Component result: %w", err)
+	}
+	l1Gas, ok := outputs[0].(uint64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected gasEstimateL1Component result type %T", outputs[0])
+	}
+	return l1Gas, nil
+}
@@ -0,0 +1,61 @@
+package arbitrum
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type tContractCaller struct {
+	call *ethereum.CallMsg
+	res  []byte
+}
+
+func (c *tContractCaller) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *tContractCaller) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.call = &call
+	return c.res, nil
+}
+
+func TestEstimateL1Gas(t *testing.T) {
+	res, err := nodeInterfaceABI.Methods["gasEstimateL1Component"].Outputs.Pack(uint64(12345), big.NewInt(1e7), big.NewInt(1e9))
+	if err != nil {
+		t.Fatalf("error packing result: %v", err)
+	}
+	cb := &tContractCaller{res: res}
+	to := common.Address{0x01}
+	data := []byte{0x02, 0x03}
+
+	l1Gas, err := estimateL1Gas(context.Background(), cb, &to, data)
+	if err != nil {
+		t.Fatalf("estimateL1Gas error: %v", err)
+	}
+	if l1Gas != 12345 {
+		t.Fatalf("wrong L1 gas %d", l1Gas)
+	}
+	if *cb.call.To != nodeInterfaceAddress {
+		t.Fatalf("wrong call address %s", cb.call.To)
+	}
+	args, err := nodeInterfaceABI.Methods["gasEstimateL1Component"].Inputs.Unpack(cb.call.Data[4:])
+	if err != nil {
+		t.Fatalf("error unpacking call data: %v", err)
+	}
+	if args[0].(common.Address) != to || args[1].(bool) || string(args[2].([]byte)) != string(data) {
+		t.Fatalf("wrong call args %v", args)
+	}
+
+	// Contract deployment.
+	if _, err := estimateL1Gas(context.Background(), cb, nil, data); err != nil {
+		t.Fatalf("estimateL1Gas error for deployment: %v", err)
+	}
+	args, _ = nodeInterfaceABI.Methods["gasEstimateL1Component"].Inputs.Unpack(cb.call.Data[4:])
+	if args[0].(common.Address) != (common.Address{}) || !args[1].(bool) {
+		t.Fatalf("wrong call args for deployment %v", args)
+	}
+}
@@ -64,7 +64,7 @@ var (
 		Type:             walletTypeSPV,
 		Tab:              "Native",
 		Description:      "Use the built-in SPV wallet",
-		ConfigOpts:       btc.CommonConfigOpts("BCH", true),
+		ConfigOpts:       append(btc.CommonConfigOpts("BCH", true), btc.SPVPeerConfigOpts...),
 		Seeded:           true,
 		MultiFundingOpts: btc.MultiFundingOpts,
 	}
@@ -236,7 +236,7 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 		// then, they modified it from the old Bitcoin Core estimatefee by
 		// removing the confirmation target argument.
 		cloneCFG.FeeEstimator = estimateFee
-		return btc.BTCCloneWallet(cloneCFG)
+		return newFullNodeWallet(cloneCFG)
 	// case walletTypeElectrum:
 	// 	logger.Warnf("\n\nUNTESTED Bitcoin Cash ELECTRUM WALLET IMPLEMENTATION! DO NOT USE ON mainnet!\n\n")
 	// 	cloneCFG.FeeEstimator = nil        // Electrum can do it, use the feeRate method
@@ -244,6 +244,8 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 	// 	cloneCFG.Ports = dexbtc.NetPorts{} // no default ports for Electrum wallet
 	// 	return btc.ElectrumWallet(cloneCFG)
 	case walletTypeSPV:
+		// The *btc.ExchangeWalletSPV accelerates orders itself, signing with
+		// the clone's wallet.
 		return btc.OpenSPVWallet(cloneCFG, openSPVWallet)
 	}
 	return nil, fmt.Errorf("wallet type %q not known", cfg.Type)
@@ -0,0 +1,93 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bch
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	bchtxscript "github.com/gcash/bchd/txscript"
+)
+
+func TestWalletAccelerates(t *testing.T) {
+	for _, walletType := range []string{walletTypeRPC, walletTypeSPV} {
+		cfg := &asset.WalletConfig{
+			Type: walletType,
+			Settings: map[string]string{
+				"rpcuser":     "user",
+				"rpcpassword": "pass",
+			},
+			DataDir: t.TempDir(),
+			Emit:    asset.NewWalletEmitter(make(chan asset.WalletNotification, 1), BipID, dex.StdOutLogger("T", dex.LevelOff)),
+		}
+		w, err := NewWallet(cfg, dex.StdOutLogger("T", dex.LevelOff), dex.Regtest)
+		if err != nil {
+			t.Fatalf("NewWallet error for %s wallet: %v", walletType, err)
+		}
+		if _, is := w.(asset.Accelerator); !is {
+			t.Fatalf("bch %s wallet is not an asset.Accelerator", walletType)
+		}
+	}
+}
+
+// TestAccelerationTxSigning checks that a child-pays-for-parent transaction,
+// which spends the non-segwit order change to a new change output, is signed
+// with the ForkID signature hash and is no larger than the size that
+// btc.(*baseWallet).signedAccelerationTx budgets fees for.
+func TestAccelerationTxSigning(t *testing.T) {
+	privKey, _ := btcec.NewPrivateKey()
+	pubKey := privKey.PubKey().SerializeCompressed()
+	addr, _ := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey), &chaincfg.MainNetParams)
+	pkScript, _ := txscript.PayToAddrScript(addr)
+
+	const changeVal = 1e8
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{0x01}}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(changeVal-1e5, pkScript))
+
+	sig, err := rawTxInSigner(tx, 0, pkScript, txscript.SigHashAll, privKey, []int64{changeVal}, nil)
+	if err != nil {
+		t.Fatalf("rawTxInSigner error: %v", err)
+	}
+	if hashType := bchtxscript.SigHashType(sig[len(sig)-1]); hashType&bchtxscript.SigHashForkID == 0 {
+		t.Fatalf("signature hash type %x does not have the fork ID", hashType)
+	}
+	sigScript, err := txscript.NewScriptBuilder().AddData(sig).AddData(pubKey).Script()
+	if err != nil {
+		t.Fatalf("error building sigScript: %v", err)
+	}
+	tx.TxIn[0].SignatureScript = sigScript
+
+	if tx.HasWitness() {
+		t.Fatalf("acceleration tx has witness data")
+	}
+	if len(sigScript) > dexbtc.RedeemP2PKHSigScriptSize {
+		t.Fatalf("sigScript size %d > %d", len(sigScript), dexbtc.RedeemP2PKHSigScriptSize)
+	}
+	const budgetedSize = dexbtc.MinimumTxOverhead + dexbtc.RedeemP2PKHInputSize + dexbtc.P2PKHOutputSize
+	if size := dexbtc.MsgTxVBytes(tx); size > budgetedSize {
+		t.Fatalf("acceleration tx size %d > budgeted %d", size, budgetedSize)
+	}
+
+	bchTx, err := translateTx(tx)
+	if err != nil {
+		t.Fatalf("translateTx error: %v", err)
+	}
+	engine, err := bchtxscript.NewEngine(pkScript, bchTx, 0, bchtxscript.StandardVerifyFlags,
+		nil, nil, nil, changeVal)
+	if err != nil {
+		t.Fatalf("NewEngine error: %v", err)
+	}
+	if err := engine.Execute(); err != nil {
+		t.Fatalf("acceleration tx signature does not verify: %v", err)
+	}
+}
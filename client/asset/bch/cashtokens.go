@@ -0,0 +1,115 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bch
+
+import (
+	"context"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/btc"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// isTokenUnspent checks whether the listunspent result is an output that
+// carries a CashToken. Spending a token output in a transaction that doesn't
+// pass the token on to another output burns the token, so token outputs are
+// never used by the DEX.
+func isTokenUnspent(u *btc.ListUnspentResult) bool {
+	return len(u.TokenData) > 0 && string(u.TokenData) != "null"
+}
+
+func toSatoshi(v float64) uint64 {
+	amt, _ := btcutil.NewAmount(v) // zero if invalid
+	return uint64(amt)
+}
+
+// ExchangeWalletFullNode is a Bitcoin Cash Node wallet. Outputs carrying
+// CashTokens are not used to fund orders or pay fees, and their value is
+// reported as a locked CashTokens balance. Swaps can be accelerated.
+type ExchangeWalletFullNode struct {
+	*btc.ExchangeWalletAccelerator
+}
+
+var _ asset.UTXOLister = (*ExchangeWalletFullNode)(nil)
+var _ asset.Accelerator = (*ExchangeWalletFullNode)(nil)
+
+// newFullNodeWallet creates the Bitcoin Cash Node wallet.
+func newFullNodeWallet(cloneCFG *btc.BTCCloneCFG) (*ExchangeWalletFullNode, error) {
+	w := new(ExchangeWalletFullNode)
+	cloneCFG.ExcludeUnspent = isTokenUnspent
+	cloneCFG.BalanceFunc = w.balance
+	fn, err := btc.BTCCloneWallet(cloneCFG)
+	if err != nil {
+		return nil, err
+	}
+	w.ExchangeWalletAccelerator = &btc.ExchangeWalletAccelerator{ExchangeWalletFullNode: fn}
+	return w, nil
+}
+
+// listUnspent lists all of the wallet's unlocked outputs, including token
+// outputs.
+func (w *ExchangeWalletFullNode) listUnspent() ([]*btc.ListUnspentResult, error) {
+	var unspents []*btc.ListUnspentResult
+	if err := w.CallRPC("listunspent", []any{0}, &unspents); err != nil {
+		return nil, err
+	}
+	return unspents, nil
+}
+
+// balance is the BalanceFunc for Bitcoin Cash Node wallets. The wallet balance
+// includes the value of token outputs, but they can't be spent by the DEX, so
+// they are moved to the locked balance.
+func (w *ExchangeWalletFullNode) balance(_ context.Context, locked uint64) (*asset.Balance, error) {
+	var walletInfo btc.GetWalletInfoResult
+	if err := w.CallRPC("getwalletinfo", nil, &walletInfo); err != nil {
+		return nil, err
+	}
+	unspents, err := w.listUnspent()
+	if err != nil {
+		return nil, err
+	}
+	var tokens uint64
+	for _, u := range unspents {
+		if isTokenUnspent(u) {
+			tokens += toSatoshi(u.Amount)
+		}
+	}
+	var available uint64
+	if bal := toSatoshi(walletInfo.Balance + walletInfo.UnconfirmedBalance); bal > locked+tokens {
+		available = bal - locked - tokens
+	}
+	return &asset.Balance{
+		Available: available,
+		Immature:  toSatoshi(walletInfo.ImmatureBalance),
+		Locked:    locked + tokens,
+		Other: map[asset.BalanceCategory]asset.CustomBalance{
+			asset.BalanceCategoryCashTokens: {
+				Amount: tokens,
+				Locked: true,
+			},
+		},
+	}, nil
+}
+
+// ListUTXOs lists the wallet's unlocked outputs. Outputs carrying CashTokens
+// are flagged.
+// Part of the asset.UTXOLister interface.
+func (w *ExchangeWalletFullNode) ListUTXOs() ([]*asset.WalletUTXO, error) {
+	unspents, err := w.listUnspent()
+	if err != nil {
+		return nil, err
+	}
+	utxos := make([]*asset.WalletUTXO, 0, len(unspents))
+	for _, u := range unspents {
+		utxos = append(utxos, &asset.WalletUTXO{
+			TxID:          u.TxID,
+			Vout:          u.Vout,
+			Address:       u.Address,
+			Value:         toSatoshi(u.Amount),
+			Confirmations: u.Confirmations,
+			Token:         isTokenUnspent(u),
+		})
+	}
+	return utxos, nil
+}
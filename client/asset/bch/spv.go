@@ -70,6 +70,7 @@ var (
 type bchSPVWallet struct {
 	// This section is populated in openSPVWallet.
 	dir         string
+	cfg         *btc.WalletConfig
 	chainParams *bchchaincfg.Params
 	btcParams   *chaincfg.Params
 	log         dex.Logger
@@ -101,6 +102,7 @@ func openSPVWallet(dir string, cfg *btc.WalletConfig, btcParams *chaincfg.Params
 
 	w := &bchSPVWallet{
 		dir:         dir,
+		cfg:         cfg,
 		chainParams: bchParams,
 		btcParams:   btcParams,
 		log:         log,
@@ -186,6 +188,15 @@ func (w *bchSPVWallet) Start() (btc.SPVService, error) {
 	}
 	errCloser.Add(w.neutrinoDB.Close)
 
+	dnsSeeds := make([]string, 0, len(w.chainParams.DNSSeeds))
+	for _, seed := range w.chainParams.DNSSeeds {
+		dnsSeeds = append(dnsSeeds, seed.Host)
+	}
+	peerSettings, err := btc.NewSPVPeerSettings(w.cfg, w.chainParams.DefaultPort, dnsSeeds)
+	if err != nil {
+		return nil, err
+	}
+
 	w.log.Debug("Starting neutrino chain service...")
 	w.cl, err = neutrino.NewChainService(neutrino.Config{
 		DataDir:     w.dir,
@@ -193,6 +204,10 @@ func (w *bchSPVWallet) Start() (btc.SPVService, error) {
 		ChainParams: *w.chainParams,
 		// https://github.com/gcash/neutrino/pull/36
 		PersistToDisk: true, // keep cfilter headers on disk for efficient rescanning
+		AddPeers:      peerSettings.AddPeers,
+		ConnectPeers:  peerSettings.ConnectPeers,
+		Dialer:        peerSettings.Dialer,
+		NameResolver:  peerSettings.NameResolver,
 		// WARNING: PublishTransaction currently uses the entire duration
 		// because if an external bug, but even if the bug is resolved, a
 		// typical inv/getdata round trip is ~4 seconds, so we set this so
@@ -216,7 +231,10 @@ func (w *bchSPVWallet) Start() (btc.SPVService, error) {
 	case bchwire.TestNet, bchwire.SimNet: // plain "wire.TestNet" is regnet!
 		defaultPeers = []string{"127.0.0.1:21577"}
 	}
-	peerManager := btc.NewSPVPeerManager(&spvService{w.cl}, defaultPeers, w.dir, w.log, w.chainParams.DefaultPort)
+	if w.cfg.OnlyTrustedPeers {
+		defaultPeers = nil
+	}
+	peerManager := btc.NewSPVPeerManager(&spvService{w.cl}, defaultPeers, w.dir, w.log, w.chainParams.DefaultPort, peerSettings.NameResolver)
 	w.peerManager = peerManager
 
 	if err = w.chainClient.Start(); err != nil { // lazily starts connmgr
@@ -184,18 +184,28 @@ var (
 		Tab:               "External",
 		Description:       "Connect to bitcoind",
 		DefaultConfigPath: dexbtc.SystemConfigPath("bitcoin"),
-		ConfigOpts:        append(RPCConfigOpts("Bitcoin", "8332"), CommonConfigOpts("BTC", false)...),
+		ConfigOpts:        append(append(RPCConfigOpts("Bitcoin", "8332"), CommonConfigOpts("BTC", false)...), append(LightningConfigOpts, rbfOpt, batchRedeemsOpt, redeemAddressOpt, externalSigningOpt)...),
 		MultiFundingOpts:  MultiFundingOpts,
 	}
 	spvWalletDefinition = &asset.WalletDefinition{
 		Type:             walletTypeSPV,
 		Tab:              "Native",
 		Description:      "Use the built-in SPV wallet",
-		ConfigOpts:       CommonConfigOpts("BTC", true),
+		ConfigOpts:       append(append(append(CommonConfigOpts("BTC", true), SPVPeerConfigOpts...), fullNodeConfigOpts...), append(LightningConfigOpts, rbfOpt, batchRedeemsOpt, redeemAddressOpt)...),
 		Seeded:           true,
 		MultiFundingOpts: MultiFundingOpts,
 	}
 
+	watchOnlyWalletDefinition = WatchOnlyWalletDefinition("Bitcoin", "BTC", "8332", "bitcoin", rbfOpt)
+
+	multisigWalletDefinition = &asset.WalletDefinition{
+		Type:              walletTypeMultisig,
+		Tab:               "Multisig",
+		Description:       "Hold funds in an m-of-n multisig wallet with bitcoind. Sends are signed by the cosigners. Can't trade.",
+		DefaultConfigPath: dexbtc.SystemConfigPath("bitcoin"),
+		ConfigOpts:        append(append(RPCConfigOpts("Bitcoin", "8332"), CommonConfigOpts("BTC", false)...), append(MultisigConfigOpts, rbfOpt)...),
+	}
+
 	electrumWalletDefinition = &asset.WalletDefinition{
 		Type:        walletTypeElectrum,
 		Tab:         "Electrum (external)",
@@ -214,6 +224,8 @@ var (
 			spvWalletDefinition,
 			rpcWalletDefinition,
 			electrumWalletDefinition,
+			watchOnlyWalletDefinition,
+			multisigWalletDefinition,
 		},
 		LegacyWalletIndex: 1,
 	}
@@ -231,6 +243,25 @@ func apiFallbackOpt(defaultV bool) *asset.ConfigOption {
 	}
 }
 
+// rbfOpt enables BIP 125 replace-by-fee signaling on sends and withdrawals.
+var rbfOpt = &asset.ConfigOption{
+	Key:         "rbf",
+	DisplayName: "Replace-by-fee",
+	Description: "Signal replaceability (BIP 125) on sends and withdrawals, " +
+		"so that a transaction that is stuck in the mempool can be " +
+		"replaced with one paying a higher fee rate.",
+	IsBoolean: true,
+}
+
+// redeemAddressOpt configures an external address that redemptions pay to.
+var redeemAddressOpt = &asset.ConfigOption{
+	Key:         "redeemaddress",
+	DisplayName: "Redemption address",
+	Description: "An external address, e.g. for cold storage, that trade " +
+		"redemptions pay to directly instead of this wallet. Leave empty to " +
+		"redeem to this wallet.",
+}
+
 // CommonConfigOpts are the common options that the Wallets recognize.
 func CommonConfigOpts(symbol string /* upper-case */, withApiFallback bool) []*asset.ConfigOption {
 	opts := []*asset.ConfigOption{
@@ -273,7 +304,7 @@ func CommonConfigOpts(symbol string /* upper-case */, withApiFallback bool) []*a
 	}
 
 	if withApiFallback {
-		opts = append(opts, apiFallbackOpt(true))
+		opts = append(opts, apiFallbackOpt(true), feeEstimatorsOpt)
 	}
 	return opts
 }
@@ -340,6 +371,10 @@ type BTCCloneCFG struct {
 	// BalanceFunc is a custom function for getting the wallet's balance.
 	// BalanceFunc precludes any other methods of balance retrieval.
 	BalanceFunc func(ctx context.Context, locked uint64) (*asset.Balance, error)
+	// ExcludeUnspent, if provided, identifies RPC wallet outputs that cannot
+	// fund the wallet's transactions, e.g. Litecoin MWEB outputs. They are
+	// omitted from the results of ListUnspent.
+	ExcludeUnspent func(*ListUnspentResult) bool
 	// If segwit is false, legacy addresses and contracts will be used. This
 	// setting must match the configuration of the server's asset backend.
 	Segwit bool
@@ -456,10 +491,26 @@ type WalletConfig struct {
 	RedeemConfTarget uint64  `ini:"redeemconftarget"`
 	ActivelyUsed     bool    `ini:"special_activelyUsed"` // injected by core
 	ApiFeeFallback   bool    `ini:"apifeefallback"`
-}
-
-func readBaseWalletConfig(walletCfg *WalletConfig) (*baseWalletConfig, error) {
-	cfg := &baseWalletConfig{}
+	FeeEstimators    string  `ini:"feeestimators"`
+	LNDRESTHost      string  `ini:"lndresthost"`
+	LNDMacaroonPath  string  `ini:"lndmacaroonpath"`
+	LNDTLSCertPath   string  `ini:"lndtlscertpath"`
+	RBF              bool    `ini:"rbf"`
+	BatchRedeems     bool    `ini:"batchredeems"`
+	RedeemAddress    string  `ini:"redeemaddress"`
+	ExternalSigning  bool    `ini:"externalsigning"`
+	// TrustedPeers, OnlyTrustedPeers, and TorProxy are used by SPV wallets.
+	TrustedPeers     string `ini:"trustedpeers"`
+	OnlyTrustedPeers bool   `ini:"onlytrustedpeers"`
+	TorProxy         string `ini:"torproxy"`
+	// The FullNode settings configure a hybrid SPV wallet.
+	FullNodeRPCBind string `ini:"fullnoderpcbind"`
+	FullNodeRPCUser string `ini:"fullnoderpcuser"`
+	FullNodeRPCPass string `ini:"fullnoderpcpassword"`
+}
+
+func readBaseWalletConfig(walletCfg *WalletConfig) (cfg *baseWalletConfig, err error) {
+	cfg = &baseWalletConfig{}
 	// if values not specified, use defaults. As they are validated as BTC/KB,
 	// we need to convert first.
 	if walletCfg.FallbackFeeRate == 0 {
@@ -489,6 +540,19 @@ func readBaseWalletConfig(walletCfg *WalletConfig) (*baseWalletConfig, error) {
 	cfg.redeemConfTarget = walletCfg.RedeemConfTarget
 	cfg.useSplitTx = walletCfg.UseSplitTx
 	cfg.apiFeeFallback = walletCfg.ApiFeeFallback
+	cfg.rbf = walletCfg.RBF
+	cfg.batchRedeems = walletCfg.BatchRedeems
+	cfg.redeemAddress = strings.TrimSpace(walletCfg.RedeemAddress)
+	if cfg.feeEstimators, err = parseFeeEstimators(walletCfg.FeeEstimators); err != nil {
+		return nil, err
+	}
+
+	if walletCfg.LNDRESTHost != "" {
+		cfg.lnd, err = newLNDClient(walletCfg.LNDRESTHost, walletCfg.LNDMacaroonPath, walletCfg.LNDTLSCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("error configuring lnd client: %w", err)
+		}
+	}
 
 	return cfg, nil
 }
@@ -741,6 +805,15 @@ type baseWalletConfig struct {
 	redeemConfTarget uint64
 	useSplitTx       bool
 	apiFeeFallback   bool
+	rbf              bool
+	batchRedeems     bool
+	// redeemAddress is an external address that redemptions pay to instead
+	// of the wallet. Empty to redeem to the wallet.
+	redeemAddress string
+	// feeEstimators are the additional external fee rate APIs.
+	feeEstimators []*feeRateCache
+	// lnd is non-nil if an LND node is configured for Lightning transfers.
+	lnd *lndClient
 }
 
 // feeRateCache wraps a ExternalFeeEstimator function and caches results.
@@ -788,6 +861,7 @@ type baseWallet struct {
 	// 64-bit atomic variables first. See
 	// https://golang.org/pkg/sync/atomic/#pkg-note-BUG
 	tipAtConnect int64
+	rescanning   atomic.Bool
 
 	cfgV              atomic.Value // *baseWalletConfig
 	node              Wallet
@@ -851,6 +925,11 @@ type baseWallet struct {
 	txHistoryDB atomic.Value // *BadgerTxDB
 
 	ar *AddressRecycler
+
+	rb *redeemBatcher
+
+	// signer is non-nil if transactions are signed by an external signer.
+	signer *psbtSigner
 }
 
 func (w *baseWallet) fallbackFeeRate() uint64 {
@@ -877,6 +956,22 @@ func (w *baseWallet) apiFeeFallback() bool {
 	return w.cfgV.Load().(*baseWalletConfig).apiFeeFallback
 }
 
+func (w *baseWallet) feeEstimators() []*feeRateCache {
+	return w.cfgV.Load().(*baseWalletConfig).feeEstimators
+}
+
+func (w *baseWallet) redeemAddress() string {
+	return w.cfgV.Load().(*baseWalletConfig).redeemAddress
+}
+
+func (w *baseWallet) rbf() bool {
+	return w.cfgV.Load().(*baseWalletConfig).rbf
+}
+
+func (w *baseWallet) batchRedeems() bool {
+	return w.cfgV.Load().(*baseWalletConfig).batchRedeems
+}
+
 type intermediaryWallet struct {
 	*baseWallet
 	txFeeEstimator TxFeeEstimator
@@ -923,7 +1018,10 @@ var _ asset.Accelerator = (*ExchangeWalletAccelerator)(nil)
 var _ asset.Accelerator = (*ExchangeWalletSPV)(nil)
 var _ asset.Withdrawer = (*baseWallet)(nil)
 var _ asset.FeeRater = (*baseWallet)(nil)
+var _ asset.FeeBumper = (*baseWallet)(nil)
+var _ asset.ExternalSigner = (*baseWallet)(nil)
 var _ asset.Rescanner = (*ExchangeWalletSPV)(nil)
+var _ asset.Rescanner = (*intermediaryWallet)(nil)
 var _ asset.LogFiler = (*ExchangeWalletSPV)(nil)
 var _ asset.Recoverer = (*ExchangeWalletSPV)(nil)
 var _ asset.PeerManager = (*ExchangeWalletSPV)(nil)
@@ -936,6 +1034,7 @@ var _ asset.Authenticator = (*ExchangeWalletCustom)(nil)
 var _ asset.AddressReturner = (*baseWallet)(nil)
 var _ asset.WalletHistorian = (*ExchangeWalletSPV)(nil)
 var _ asset.NewAddresser = (*baseWallet)(nil)
+var _ asset.KeySweeper = (*baseWallet)(nil)
 
 // RecoveryCfg is the information that is transferred from the old wallet
 // to the new one when the wallet is recovered.
@@ -977,14 +1076,28 @@ func (btc *ExchangeWalletSPV) Move(backupDir string) error {
 }
 
 // Rescan satisfies the asset.Rescanner interface, and issues a rescan wallet
-// command if the backend is an SPV wallet.
-func (btc *ExchangeWalletSPV) Rescan(_ context.Context, _ /* bday already stored internally */ uint64) error {
+// command if the backend is an SPV wallet. The rescan's progress is emitted
+// until the wallet is synced.
+func (btc *ExchangeWalletSPV) Rescan(ctx context.Context, _ /* bday already stored internally */ uint64) error {
+	if !btc.rescanning.CompareAndSwap(false, true) {
+		return errors.New("rescan already in progress")
+	}
+	startHeight, err := btc.birthdayHeight(uint64(btc.spvNode.wallet.Birthday().Unix()))
+	if err != nil {
+		btc.log.Warnf("Error finding birthday block: %v", err)
+	}
+	btc.tipMtx.RLock()
+	tipHeight := btc.currentTip.Height
+	btc.tipMtx.RUnlock()
 	atomic.StoreInt64(&btc.tipAtConnect, 0) // for progress
 	// Caller should start calling SyncStatus on a ticker.
 	if err := btc.spvNode.wallet.RescanAsync(); err != nil {
+		btc.rescanning.Store(false)
 		return err
 	}
 	btc.receiveTxLastQuery.Store(0)
+	tracker := asset.NewRescanTracker(btc.emit, uint64(startHeight), uint64(tipHeight))
+	go btc.monitorSPVRescan(ctx, tracker)
 	// Rescan is occuring asynchronously, so there's probably no point in
 	// running checkPendingTxs.
 	return nil
@@ -1029,7 +1142,7 @@ func (btc *ExchangeWalletSPV) LogFilePath() string {
 // address.
 func (btc *ExchangeWalletSPV) WithdrawTx(ctx context.Context, walletPW []byte, addr btcutil.Address) (_ *wire.MsgTx, err error) {
 	btc.ctx = ctx
-	spvw := btc.node.(*spvWallet)
+	spvw := btc.spvNode
 	spvw.cl, err = spvw.wallet.Start()
 	if err != nil {
 		return nil, fmt.Errorf("error starting wallet")
@@ -1120,13 +1233,24 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, net dex.Network) (ass
 
 	switch cfg.Type {
 	case walletTypeSPV:
-		return OpenSPVWallet(cloneCFG, openSPVWallet)
+		spvWallet, err := OpenSPVWallet(cloneCFG, openSPVWallet)
+		if err != nil {
+			return nil, err
+		}
+		if ln := newLightningWallet(spvWallet.baseWallet); ln != nil {
+			return &ExchangeWalletSPVLightning{spvWallet, ln}, nil
+		}
+		return spvWallet, nil
 	case walletTypeRPC, walletTypeLegacy:
 		rpcWallet, err := BTCCloneWallet(cloneCFG)
 		if err != nil {
 			return nil, err
 		}
-		return &ExchangeWalletAccelerator{rpcWallet}, nil
+		accelerator := &ExchangeWalletAccelerator{rpcWallet}
+		if ln := newLightningWallet(rpcWallet.baseWallet); ln != nil {
+			return &ExchangeWalletLightning{accelerator, ln}, nil
+		}
+		return accelerator, nil
 	case walletTypeElectrum:
 		cloneCFG.Ports = dexbtc.NetPorts{} // no default ports
 		ver, err := dex.SemverFromString(needElectrumVersion)
@@ -1135,6 +1259,10 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, net dex.Network) (ass
 		}
 		cloneCFG.MinElectrumVersion = *ver
 		return ElectrumWallet(cloneCFG)
+	case WalletTypeWatchOnly:
+		return WatchOnlyWallet(cloneCFG)
+	case walletTypeMultisig:
+		return MultisigWallet(cloneCFG)
 	default:
 		makeCustomWallet, ok := customWalletConstructors[cfg.Type]
 		if !ok {
@@ -1220,10 +1348,24 @@ func newRPCWallet(requester RawRequester, cfg *BTCCloneCFG, parsedCfg *RPCWallet
 		legacyValidateAddressRPC: cfg.LegacyValidateAddressRPC,
 		omitRPCOptionsArg:        cfg.OmitRPCOptionsArg,
 		privKeyFunc:              cfg.PrivKeyFunc,
+		excludeUnspent:           cfg.ExcludeUnspent,
 	}
 	core.requesterV.Store(requester)
 	node := newRPCClient(core)
-	btc.setNode(node)
+	if parsedCfg.ExternalSigning {
+		if !cfg.Segwit {
+			return nil, errors.New("external signing requires a segwit wallet")
+		}
+		btc.signer = newPSBTSigner(btc.log, btc.emit)
+		btc.setNode(&psbtNode{
+			Wallet:        node,
+			signer:        btc.signer,
+			segwit:        btc.segwit,
+			deserializeTx: btc.deserializeTx,
+		})
+	} else {
+		btc.setNode(node)
+	}
 	w := &intermediaryWallet{
 		baseWallet:     btc,
 		txFeeEstimator: node,
@@ -1261,6 +1403,11 @@ func newUnconnectedWallet(cfg *BTCCloneCFG, walletCfg *WalletConfig) (*baseWalle
 	if cfg.AddressDecoder != nil {
 		addrDecoder = cfg.AddressDecoder
 	}
+	if baseCfg.redeemAddress != "" {
+		if _, err := addrDecoder(baseCfg.redeemAddress, cfg.ChainParams); err != nil {
+			return nil, fmt.Errorf("invalid redemption address %q: %w", baseCfg.redeemAddress, err)
+		}
+	}
 
 	nonSegwitSigner := rawTxInSig
 	if cfg.NonSegwitSigner != nil {
@@ -1359,6 +1506,7 @@ func newUnconnectedWallet(cfg *BTCCloneCFG, walletCfg *WalletConfig) (*baseWalle
 		ar:                addressRecyler,
 	}
 	w.cfgV.Store(baseCfg)
+	w.rb = newRedeemBatcher(w.redeem, w.log)
 
 	// Default to the BTC RPC estimator (see LTC). Consumers can use
 	// noLocalFeeRate or a similar dummy function to power feeRate() requests
@@ -1424,8 +1572,9 @@ func OpenSPVWallet(cfg *BTCCloneCFG, walletConstructor BTCWalletConstructor) (*E
 	}
 
 	// SPV wallets without a FeeEstimator will default to any enabled external
-	// fee estimator.
-	if cfg.FeeEstimator == nil {
+	// fee estimator. Hybrid wallets get estimates from the full node.
+	hybrid := walletCfg.FullNodeRPCBind != ""
+	if cfg.FeeEstimator == nil && !hybrid {
 		cfg.FeeEstimator = noLocalFeeRate
 	}
 
@@ -1447,13 +1596,24 @@ func OpenSPVWallet(cfg *BTCCloneCFG, walletConstructor BTCWalletConstructor) (*E
 
 	spvw.BlockFiltersScanner = NewBlockFiltersScanner(spvw, spvw.log)
 	spvw.wallet = walletConstructor(spvw.dir, spvw.cfg, spvw.chainParams, spvw.log)
-	btc.setNode(spvw)
+
+	var tipRedeemer TipRedemptionWallet = spvw
+	if hybrid {
+		hw, err := newHybridWallet(spvw, btc, cfg, walletCfg)
+		if err != nil {
+			return nil, err
+		}
+		btc.setNode(hw)
+		tipRedeemer = hw
+	} else {
+		btc.setNode(spvw)
+	}
 
 	w := &ExchangeWalletSPV{
 		intermediaryWallet: &intermediaryWallet{
 			baseWallet:     btc,
 			txFeeEstimator: spvw,
-			tipRedeemer:    spvw,
+			tipRedeemer:    tipRedeemer,
 		},
 		authAddOn: &authAddOn{spvw},
 		spvNode:   spvw,
@@ -1705,6 +1865,12 @@ func (btc *intermediaryWallet) Connect(ctx context.Context) (*sync.WaitGroup, er
 		btc.monitorPeers(ctx)
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		btc.watchPendingDeposits(ctx)
+	}()
+
 	wg.Add(1)
 	func() {
 		defer wg.Done()
@@ -1739,7 +1905,23 @@ func (btc *baseWallet) Reconfigure(ctx context.Context, cfg *asset.WalletConfig,
 	if err != nil {
 		return false, err
 	}
-	btc.cfgV.Store(newCfg) // probably won't matter if restart/reinit required
+	if newCfg.redeemAddress != "" {
+		if _, err := btc.decodeAddr(newCfg.redeemAddress, btc.chainParams); err != nil {
+			return false, fmt.Errorf("invalid redemption address %q: %w", newCfg.redeemAddress, err)
+		}
+	}
+	oldCfg := btc.cfgV.Swap(newCfg).(*baseWalletConfig) // probably won't matter if restart/reinit required
+
+	// Lightning transfers are enabled when the wallet is created.
+	if (newCfg.lnd != nil) != (oldCfg.lnd != nil) {
+		restart = true
+	}
+
+	// The node is wrapped for external signing when the wallet is created.
+	// Watch-only and multisig wallets always sign externally.
+	if cfg.Type != WalletTypeWatchOnly && cfg.Type != walletTypeMultisig && walletCfg.ExternalSigning != (btc.signer != nil) {
+		restart = true
+	}
 
 	return restart, nil
 }
@@ -1853,6 +2035,13 @@ func (btc *baseWallet) Balance() (*asset.Balance, error) {
 	bal.Available -= reserves
 	bal.Locked += reserves
 
+	if pending := min(btc.pendingDeposits(), bal.Immature); pending > 0 {
+		if bal.Other == nil {
+			bal.Other = make(map[asset.BalanceCategory]asset.CustomBalance)
+		}
+		bal.Other[asset.BalanceCategoryPendingDeposit] = asset.CustomBalance{Amount: pending}
+	}
+
 	return bal, nil
 }
 
@@ -1901,35 +2090,46 @@ func (btc *baseWallet) legacyBalance() (*asset.Balance, error) {
 }
 
 // feeRate returns the current optimal fee rate in sat / byte using the
-// estimatesmartfee RPC or an external API if configured and enabled.
+// estimatesmartfee RPC or external APIs if configured and enabled. If
+// additional fee rate APIs are configured, the local and external estimates
+// are blended.
 func (btc *baseWallet) feeRate(confTarget uint64) (feeRate uint64, err error) {
 	allowExternalFeeRate := btc.apiFeeFallback()
+	estimators := btc.feeEstimators()
+	var rates []uint64
 	// Because of the problems Bitcoin's unstable estimatesmartfee has caused,
 	// we won't use it.
 	if btc.symbol != "btc" || !allowExternalFeeRate {
 		feeRate, err := btc.localFeeRate(btc.ctx, btc.node, confTarget) // e.g. rpcFeeRate
 		if err == nil {
-			return feeRate, nil
+			if !allowExternalFeeRate || len(estimators) == 0 {
+				return feeRate, nil
+			}
+			rates = append(rates, feeRate)
 		} else if !allowExternalFeeRate {
 			return 0, fmt.Errorf("error getting local rate and external rates are disabled: %w", err)
 		}
 	}
 
-	if btc.feeCache == nil {
+	if btc.feeCache != nil {
+		estimators = append([]*feeRateCache{btc.feeCache}, estimators...)
+	}
+	if len(estimators) == 0 {
 		return 0, fmt.Errorf("external fee rate fetcher not configured")
 	}
 
-	// External estimate fallback. Error if it exceeds our limit, and the caller
-	// may use btc.fallbackFeeRate(), as in targetFeeRateWithFallback.
-	feeRate, err = btc.feeCache.rate(btc.ctx, btc.Network) // e.g. externalFeeRate
-	if err != nil {
-		btc.log.Meter("feeRate.rate.fail", time.Hour).Errorf("Failed to get fee rate from external API: %v", err)
+	// External estimate fallback. Error if they exceed our limit, and the
+	// caller may use btc.fallbackFeeRate(), as in targetFeeRateWithFallback.
+	externalRates := btc.externalFeeRates(estimators) // e.g. externalFeeRate
+	if len(externalRates) == 0 && len(rates) == 0 {
 		return 0, nil
 	}
-	if feeRate <= 0 || feeRate > btc.feeRateLimit() { // but fetcher shouldn't return <= 0 without error
-		return 0, fmt.Errorf("external fee rate %v exceeds configured limit", feeRate)
+	rates = append(rates, externalRates...)
+	feeRate = blendFeeRates(rates, btc.feeRateLimit())
+	if feeRate == 0 {
+		return 0, fmt.Errorf("fee rates %v exceed configured limit", rates)
 	}
-	btc.log.Tracef("Retrieved fee rate from external API: %v", feeRate)
+	btc.log.Tracef("Blended fee rate %v from %v", feeRate, rates)
 	return feeRate, nil
 }
 
@@ -1958,29 +2158,10 @@ func rpcFeeRate(ctx context.Context, rr RawRequester, confTarget uint64) (uint64
 // externalFeeRate gets the fee rate from the external API and returns it
 // in sats/vByte.
 func externalFeeRate(ctx context.Context, net dex.Network) (uint64, error) {
-	// https://mempool.space/docs/api
-	var uri string
 	if net == dex.Testnet {
-		uri = "https://mempool.space/testnet/api/v1/fees/recommended"
-	} else {
-		uri = "https://mempool.space/api/v1/fees/recommended"
-	}
-	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
-	defer cancel()
-	var resp struct {
-		Fastest  uint64 `json:"fastestFee"`
-		HalfHour uint64 `json:"halfHourFee"`
-		Hour     uint64 `json:"hourFee"`
-		Economy  uint64 `json:"economyFee"`
-		Minimum  uint64 `json:"minimumFee"`
-	}
-	if err := dexnet.Get(ctx, uri, &resp, dexnet.WithSizeLimit(1<<14)); err != nil {
-		return 0, err
+		return mempoolSpaceFeeRate(ctx, "https://mempool.space/testnet/api/v1/fees/recommended")
 	}
-	if resp.Fastest == 0 {
-		return 0, errors.New("no fee rate found")
-	}
-	return resp.Fastest, nil
+	return mempoolSpaceFeeRate(ctx, "https://mempool.space/api/v1/fees/recommended")
 }
 
 type amount uint64
@@ -3792,20 +3973,36 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 
 	refundAddrs := make([]btcutil.Address, 0, len(swaps.Contracts))
 
+	// With an external signer, the swap may be attempted several times before
+	// it is signed. The contracts must be the same each time, so the
+	// revocation addresses are reused.
+	var prevRefundAddrs []btcutil.Address
+	if btc.signer != nil {
+		prevRefundAddrs = btc.signer.swapRefundAddrs(inputsKey(baseTx))
+		if len(prevRefundAddrs) != len(swaps.Contracts) {
+			prevRefundAddrs = nil
+		}
+	}
+
 	// Add the contract outputs.
 	// TODO: Make P2WSH contract and P2WPKH change outputs instead of
 	// legacy/non-segwit swap contracts pkScripts.
-	for _, contract := range swaps.Contracts {
+	for i, contract := range swaps.Contracts {
 		totalOut += contract.Value
 		// revokeAddr is the address belonging to the key that may be used to
 		// sign and refund a swap past its encoded refund locktime.
-		revokeAddrStr, err := btc.recyclableAddress()
-		if err != nil {
-			return nil, nil, 0, fmt.Errorf("error creating revocation address: %w", err)
-		}
-		revokeAddr, err := btc.decodeAddr(revokeAddrStr, btc.chainParams)
-		if err != nil {
-			return nil, nil, 0, fmt.Errorf("refund address decode error: %v", err)
+		var revokeAddr btcutil.Address
+		if prevRefundAddrs != nil {
+			revokeAddr = prevRefundAddrs[i]
+		} else {
+			revokeAddrStr, err := btc.recyclableAddress()
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("error creating revocation address: %w", err)
+			}
+			revokeAddr, err = btc.decodeAddr(revokeAddrStr, btc.chainParams)
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("refund address decode error: %v", err)
+			}
 		}
 		refundAddrs = append(refundAddrs, revokeAddr)
 
@@ -3840,6 +4037,9 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 	if totalIn < totalOut {
 		return nil, nil, 0, fmt.Errorf("unfunded contract. %d < %d", totalIn, totalOut)
 	}
+	if btc.signer != nil {
+		btc.signer.setSwapRefundAddrs(inputsKey(baseTx), refundAddrs)
+	}
 
 	// Ensure we have enough outputs before broadcasting.
 	swapCount := len(swaps.Contracts)
@@ -3860,16 +4060,33 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 
 	// Sign, add change, but don't send the transaction yet until
 	// the individual swap refund txs are prepared and signed.
+	contractOuts := baseTx.TxOut
 	msgTx, change, fees, err := btc.signTxAndAddChange(baseTx, changeAddr, totalIn, totalOut, feeRate)
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	if btc.signer != nil && !sameContractOutputs(contractOuts, msgTx.TxOut) {
+		// The matches have changed since the transaction was signed, e.g. one
+		// was revoked. Request a new signature.
+		btc.signer.discard(inputsKey(msgTx))
+		return nil, nil, 0, errors.New("signed swap transaction does not match the swaps")
+	}
 	txHash := btc.hashTx(msgTx)
 
 	// Prepare the receipts.
 	receipts := make([]asset.Receipt, 0, swapCount)
 	for i, contract := range swaps.Contracts {
 		output := NewOutput(txHash, uint32(i), contract.Value)
+		if btc.signer != nil {
+			// The refund can't be signed until the swap is confirmed, since
+			// the external signer would need to sign it ahead of the swap.
+			receipts = append(receipts, &SwapReceipt{
+				Output:         output,
+				SwapContract:   contracts[i],
+				ExpirationTime: time.Unix(int64(contract.LockTime), 0).UTC(),
+			})
+			continue
+		}
 		refundAddr := refundAddrs[i]
 		signedRefundTx, err := btc.refundTx(output.txHash(), output.vout(), contracts[i],
 			contract.Value, refundAddr, swaps.FeeRate)
@@ -3902,6 +4119,10 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 		Fees:   fees,
 	}, txHash, true)
 
+	for _, contract := range contracts {
+		btc.watchContract(contract, time.Time{})
+	}
+
 	// If change is nil, return a nil asset.Coin.
 	var changeCoin asset.Coin
 	if change != nil {
@@ -3940,8 +4161,19 @@ func (btc *baseWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, ui
 	return receipts, changeCoin, fees, nil
 }
 
-// Redeem sends the redemption transaction, completing the atomic swap.
+// Redeem sends the redemption transaction, completing the atomic swap. If
+// the wallet is configured to batch redemptions, redemptions requested at
+// about the same time are combined into a single transaction.
 func (btc *baseWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error) {
+	if btc.batchRedeems() {
+		return btc.rb.add(form)
+	}
+	return btc.redeem(form)
+}
+
+// redeem creates and broadcasts a transaction spending all of the contracts
+// in the RedeemForm.
+func (btc *baseWallet) redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error) {
 	// Create a transaction that spends the referenced contract.
 	msgTx := wire.NewMsgTx(btc.txVersion())
 	var totalIn uint64
@@ -4015,10 +4247,21 @@ func (btc *baseWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin,
 		btc.log.Warnf("Ignoring fee bump (%s) resulting in fees > redemption", float64PtrStr(customCfg.FeeBump))
 	}
 
-	// Send the funds back to the exchange wallet.
-	redeemAddr, err := btc.node.ExternalAddress()
-	if err != nil {
-		return nil, nil, 0, fmt.Errorf("error getting new address from the wallet: %w", err)
+	// Send the funds back to the exchange wallet, or to the configured
+	// external address.
+	var redeemAddr btcutil.Address
+	var recipient *string
+	if addrStr := btc.redeemAddress(); addrStr != "" {
+		redeemAddr, err = btc.decodeAddr(addrStr, btc.chainParams)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("error decoding redemption address %s: %w", addrStr, err)
+		}
+		recipient = &addrStr
+	} else {
+		redeemAddr, err = btc.node.ExternalAddress()
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("error getting new address from the wallet: %w", err)
+		}
 	}
 	pkScript, err := txscript.PayToAddrScript(redeemAddr)
 	if err != nil {
@@ -4031,7 +4274,24 @@ func (btc *baseWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin,
 	}
 	msgTx.AddTxOut(txOut)
 
-	if btc.segwit {
+	if btc.signer != nil {
+		// Use the output that was signed, which may pay a different address
+		// or fee rate than the one just created.
+		if btc.signer.applySignedOutputs(msgTx) {
+			txOut = msgTx.TxOut[0]
+			if uint64(txOut.Value) > totalIn {
+				return nil, nil, 0, fmt.Errorf("signed redeem tx spends more than its inputs")
+			}
+			fee = totalIn - uint64(txOut.Value)
+		}
+		sigs, pubKeys, err := btc.signer.contractSigs(msgTx, contracts, values)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		for i, r := range form.Redemptions {
+			msgTx.TxIn[i].Witness = dexbtc.RedeemP2WSHContract(contracts[i], sigs[i], pubKeys[i], r.Secret)
+		}
+	} else if btc.segwit {
 		// NewTxSigHashes uses the PrevOutFetcher only for detecting a taproot
 		// output, so we can provide a dummy that always returns a wire.TxOut
 		// with a nil pkScript that so IsPayToTaproot returns false.
@@ -4065,10 +4325,11 @@ func (btc *baseWallet) Redeem(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin,
 	}
 
 	btc.addTxToHistory(&asset.WalletTransaction{
-		Type:   asset.Redeem,
-		ID:     txHash.String(),
-		Amount: totalIn,
-		Fees:   fee,
+		Type:      asset.Redeem,
+		ID:        txHash.String(),
+		Amount:    totalIn,
+		Fees:      fee,
+		Recipient: recipient,
 	}, txHash, true)
 
 	// Log the change output.
@@ -4213,6 +4474,10 @@ func (btc *baseWallet) AuditContract(coinID, contract, txData dex.Bytes, rebroad
 			contractHash, addr.ScriptAddress())
 	}
 
+	// Track the counterparty's contract so the wallet sees our redeem, or
+	// their refund. The contract may have been mined some time ago.
+	go btc.watchContract(contract, time.Now().Add(-ContractSearchLimit))
+
 	// Broadcast the transaction, but do not block because this is not required
 	// and does not affect the audit result.
 	if rebroadcast && tx != nil {
@@ -4387,7 +4652,14 @@ func (btc *baseWallet) refundTx(txHash *chainhash.Hash, vout uint32, contract de
 	}
 	msgTx.AddTxOut(txOut)
 
-	if btc.segwit {
+	if btc.signer != nil {
+		btc.signer.applySignedOutputs(msgTx)
+		sigs, pubKeys, err := btc.signer.contractSigs(msgTx, [][]byte{contract}, []int64{int64(val)})
+		if err != nil {
+			return nil, err
+		}
+		txIn.Witness = dexbtc.RefundP2WSHContract(contract, sigs[0], pubKeys[0])
+	} else if btc.segwit {
 		sigHashes := txscript.NewTxSigHashes(msgTx, new(txscript.CannedPrevOutputFetcher))
 		refundSig, refundPubKey, err := btc.createWitnessSig(msgTx, 0, contract, sender, int64(val), sigHashes)
 		if err != nil {
@@ -4553,30 +4825,43 @@ func (btc *baseWallet) ValidateSecret(secret, secretHash []byte) bool {
 // the fees will be subtracted from the value. If false, the fees are in
 // addition to the value. feeRate is in units of sats/byte.
 func (btc *baseWallet) send(address string, val uint64, feeRate uint64, subtract bool) (*chainhash.Hash, uint32, uint64, error) {
-	addr, err := btc.decodeAddr(address, btc.chainParams)
-	if err != nil {
-		return nil, 0, 0, fmt.Errorf("invalid address: %s", address)
-	}
+	var spAddr *silentPaymentAddress
 	var pay2script []byte
-	if scripter, is := addr.(PaymentScripter); is {
-		pay2script, err = scripter.PaymentScript()
+	if btc.isSilentPaymentAddress(address) {
+		var err error
+		spAddr, err = decodeSilentPaymentAddress(address, btc.chainParams)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid silent payment address %s: %w", address, err)
+		}
 	} else {
-		pay2script, err = txscript.PayToAddrScript(addr)
-	}
-	if err != nil {
-		return nil, 0, 0, fmt.Errorf("PayToAddrScript error: %w", err)
+		addr, err := btc.decodeAddr(address, btc.chainParams)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid address: %s", address)
+		}
+		if scripter, is := addr.(PaymentScripter); is {
+			pay2script, err = scripter.PaymentScript()
+		} else {
+			pay2script, err = txscript.PayToAddrScript(addr)
+		}
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("PayToAddrScript error: %w", err)
+		}
 	}
 
 	baseSize := dexbtc.MinimumTxOverhead
-	if btc.segwit {
+	switch {
+	case spAddr != nil:
+		// A P2TR output is the same size as a P2WSH output.
+		baseSize += dexbtc.P2WSHOutputSize + dexbtc.P2WPKHOutputSize
+	case btc.segwit:
 		baseSize += dexbtc.P2WPKHOutputSize * 2
-	} else {
+	default:
 		baseSize += dexbtc.P2PKHOutputSize * 2
 	}
 
 	enough := SendEnough(val, feeRate, subtract, uint64(baseSize), btc.segwit, true)
 	minConfs := uint32(0)
-	coins, _, _, _, inputsSize, _, err := btc.cm.Fund(btc.bondReserves.Load(), minConfs, false, enough)
+	coins, fundingCoins, _, _, inputsSize, _, err := btc.cm.Fund(btc.bondReserves.Load(), minConfs, false, enough)
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("error funding transaction: %w", err)
 	}
@@ -4585,6 +4870,18 @@ func (btc *baseWallet) send(address string, val uint64, feeRate uint64, subtract
 	if err != nil {
 		return nil, 0, 0, fmt.Errorf("error adding inputs to transaction: %w", err)
 	}
+	if spAddr != nil {
+		// The silent payment output script depends on the inputs.
+		pay2script, err = btc.silentPaymentScript(spAddr, fundedTx, fundingCoins)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("error deriving silent payment output: %w", err)
+		}
+	}
+	if btc.rbf() {
+		for _, txIn := range fundedTx.TxIn {
+			txIn.Sequence = rbfSequence
+		}
+	}
 
 	fees := feeRate * (inputsSize + uint64(baseSize))
 	var toSend uint64
@@ -4612,9 +4909,13 @@ func (btc *baseWallet) send(address string, val uint64, feeRate uint64, subtract
 		totalOut += uint64(txOut.Value)
 	}
 
-	selfSend, err := btc.OwnsDepositAddress(address)
-	if err != nil {
-		return nil, 0, 0, fmt.Errorf("error checking address ownership: %w", err)
+	// Ownership of a silent payment address can't be checked.
+	var selfSend bool
+	if spAddr == nil {
+		selfSend, err = btc.OwnsDepositAddress(address)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("error checking address ownership: %w", err)
+		}
 	}
 	txType := asset.Send
 	if selfSend {
@@ -4835,6 +5136,31 @@ func (btc *baseWallet) signTxAndAddChange(baseTx *wire.MsgTx, addr btcutil.Addre
 		return nil, nil, 0, fmt.Errorf(s, a...)
 	}
 
+	// If the external signer has signed a transaction spending the same
+	// inputs, use it as is. Its outputs are what was signed, so the change and
+	// fees are taken from it.
+	if btc.signer != nil {
+		changeIdx := len(baseTx.TxOut)
+		if btc.signer.applySignedOutputs(baseTx) {
+			msgTx, err := btc.node.SignTx(baseTx)
+			if err != nil {
+				return makeErr("error extracting signed tx: %v", err)
+			}
+			var signedOut uint64
+			for _, txOut := range msgTx.TxOut {
+				signedOut += uint64(txOut.Value)
+			}
+			if signedOut > totalIn {
+				return makeErr("signed tx spends %d, more than its inputs of %d", signedOut, totalIn)
+			}
+			var change *Output
+			if len(msgTx.TxOut) > changeIdx {
+				change = NewOutput(btc.hashTx(msgTx), uint32(changeIdx), uint64(msgTx.TxOut[changeIdx].Value))
+			}
+			return msgTx, change, totalIn - signedOut, nil
+		}
+	}
+
 	// Sign the transaction to get an initial size estimate and calculate whether
 	// a change output would be dust.
 	sigCycles := 1
@@ -4943,7 +5269,7 @@ func (btc *baseWallet) signTxAndAddChange(baseTx *wire.MsgTx, addr btcutil.Addre
 func (btc *baseWallet) broadcastTx(signedTx *wire.MsgTx) (*chainhash.Hash, error) {
 	txHash, err := btc.node.SendRawTransaction(signedTx)
 	if err != nil {
-		return nil, fmt.Errorf("sendrawtx error: %v, raw tx: %x", err, btc.wireBytes(signedTx))
+		return nil, fmt.Errorf("sendrawtx error: %w, raw tx: %x", err, btc.wireBytes(signedTx))
 	}
 	checkHash := btc.hashTx(signedTx)
 	if *txHash != *checkHash {
@@ -4999,6 +5325,10 @@ func (btc *baseWallet) createWitnessSig(tx *wire.MsgTx, idx int, pkScript []byte
 
 // ValidateAddress checks that the provided address is valid.
 func (btc *baseWallet) ValidateAddress(address string) bool {
+	if btc.isSilentPaymentAddress(address) {
+		_, err := decodeSilentPaymentAddress(address, btc.chainParams)
+		return err == nil
+	}
 	_, err := btc.decodeAddr(address, btc.chainParams)
 	return err == nil
 }
@@ -5018,7 +5348,11 @@ func (btc *intermediaryWallet) EstimateSendTxFee(address string, sendAmount, fee
 	}
 
 	var pkScript []byte
-	if addr, err := btc.decodeAddr(address, btc.chainParams); err == nil {
+	if btc.isSilentPaymentAddress(address) {
+		// The output script depends on the inputs, but is always P2TR.
+		_, err := decodeSilentPaymentAddress(address, btc.chainParams)
+		pkScript, isValidAddress = dummyP2TRScript, err == nil
+	} else if addr, err := btc.decodeAddr(address, btc.chainParams); err == nil {
 		pkScript, err = txscript.PayToAddrScript(addr)
 		if err != nil {
 			return 0, false, fmt.Errorf("error generating pubkey script: %w", err)
@@ -5111,6 +5445,9 @@ func (btc *baseWallet) MakeBondTx(ver uint16, amt, feeRate uint64, lockTime time
 	if ver != 0 {
 		return nil, nil, errors.New("only version 0 bonds supported")
 	}
+	if btc.signer != nil {
+		return nil, nil, errors.New("bonds are not supported with external signing")
+	}
 	if until := time.Until(lockTime); until >= 365*12*time.Hour /* ~6 months */ {
 		return nil, nil, fmt.Errorf("that lock time is nuts: %v", lockTime)
 	} else if until < 0 {
@@ -5325,8 +5662,8 @@ func (btc *baseWallet) makeBondRefundTxV0(txid *chainhash.Hash, vout uint32, amt
 }
 
 // RefundBond refunds a bond output to a new wallet address given the redeem
-// script and private key. After broadcasting, the output paying to the wallet
-// is returned.
+// script and private key. After broadcasting, the refund transaction's output
+// paying to the wallet is returned.
 func (btc *baseWallet) RefundBond(ctx context.Context, ver uint16, coinID, script []byte, amt uint64, privKey *secp256k1.PrivateKey) (asset.Coin, error) {
 	if ver != 0 {
 		return nil, errors.New("only version 0 bonds supported")
@@ -5367,7 +5704,7 @@ func (btc *baseWallet) RefundBond(ctx context.Context, ver uint16, coinID, scrip
 		},
 	}, txID, true)
 
-	return NewOutput(txHash, 0, uint64(msgTx.TxOut[0].Value)), nil
+	return NewOutput(txID, 0, uint64(msgTx.TxOut[0].Value)), nil
 }
 
 func (btc *baseWallet) decodeV0BondTx(msgTx *wire.MsgTx, txHash *chainhash.Hash, coinID []byte) (*asset.BondDetails, error) {
@@ -5929,6 +6266,7 @@ func (btc *baseWallet) addUnknownTransactionsToHistory(tip uint64) {
 		return
 	}
 
+	var deposits []*asset.WalletTransaction
 	for _, tx := range txs {
 		if btc.ctx.Err() != nil {
 			return
@@ -5959,7 +6297,11 @@ func (btc *baseWallet) addUnknownTransactionsToHistory(tip uint64) {
 		// Don't send notifications for the initial sync to avoid spamming the
 		// front end. A notification is sent at the end of the initial sync.
 		btc.addTxToHistory(wt, txHash, true, blockToQuery == 0)
+		if blockToQuery != 0 && wt.Type == asset.Receive && tx.BlockHeight == 0 {
+			deposits = append(deposits, wt)
+		}
 	}
+	btc.notifyPendingDeposits(deposits)
 
 	btc.receiveTxLastQuery.Store(tip)
 	err = txHistoryDB.SetLastReceiveTxQuery(tip)
@@ -6287,6 +6629,35 @@ func scriptHashAddress(segwit bool, contract []byte, chainParams *chaincfg.Param
 	return btcutil.NewAddressScriptHash(contract, chainParams)
 }
 
+// contractWatcher is satisfied by wallets that must be told to track swap
+// contracts, e.g. watch-only descriptor wallets.
+type contractWatcher interface {
+	watchContract(addr string, since time.Time) error
+}
+
+// watchContract tells the wallet to track the contract's address if the
+// wallet is a contractWatcher. Errors are logged, since the wallet can still
+// find the contract by other means.
+func (btc *baseWallet) watchContract(contract []byte, since time.Time) {
+	watcher, is := btc.node.(contractWatcher)
+	if !is {
+		return
+	}
+	addr, err := btc.scriptHashAddress(contract)
+	if err != nil {
+		btc.log.Errorf("Error encoding contract address: %v", err)
+		return
+	}
+	addrStr, err := btc.stringAddr(addr, btc.chainParams)
+	if err != nil {
+		btc.log.Errorf("Error stringifying contract address %v: %v", addr, err)
+		return
+	}
+	if err := watcher.watchContract(addrStr, since); err != nil {
+		btc.log.Errorf("Error importing contract address %s: %v", addrStr, err)
+	}
+}
+
 // ToCoinID converts the tx hash and vout to a coin ID, as a []byte.
 func ToCoinID(txHash *chainhash.Hash, vout uint32) []byte {
 	coinID := make([]byte, chainhash.HashSize+4)
@@ -6416,6 +6787,30 @@ func (btc *baseWallet) ConfirmRedemption(coinID dex.Bytes, redemption *asset.Red
 		return nil, fmt.Errorf("problem searching for redemption transaction %s: %w", txHash, err)
 	}
 
+	// A redemption to an external address is not a wallet transaction, but
+	// its output can be found until it is spent.
+	if addrStr := btc.redeemAddress(); addrStr != "" {
+		redeemAddr, err := btc.decodeAddr(addrStr, btc.chainParams)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding redemption address %s: %w", addrStr, err)
+		}
+		pkScript, err := txscript.PayToAddrScript(redeemAddr)
+		if err != nil {
+			return nil, fmt.Errorf("error creating redemption address script: %w", err)
+		}
+		txOut, confs, err := btc.node.GetTxOut(txHash, 0, pkScript, time.Now().Add(-ContractSearchLimit))
+		if err != nil {
+			return nil, fmt.Errorf("error finding redemption output %s:0: %w", txHash, err)
+		}
+		if txOut != nil {
+			return &asset.ConfirmRedemptionStatus{
+				Confs:  uint64(confs),
+				Req:    requiredRedeemConfirms,
+				CoinID: coinID,
+			}, nil
+		}
+	}
+
 	// Redemption transaction is missing from the point of view of our node!
 	// Unlikely, but possible it was redeemed by another transaction. Check
 	// if the contract is still an unspent output.
@@ -166,6 +166,14 @@ type testData struct {
 	ownedAddresses    map[string]bool
 	ownsAddress       bool
 	locked            bool
+
+	// descriptor wallets
+	importedDescriptors []string
+	importDescriptorErr string
+
+	scanUnspents   []*sweepUTXO
+	scanTxOutErr   error
+	scannedScripts []string
 }
 
 func newTestData() *testData {
@@ -322,7 +330,7 @@ func (c *tRawRequester) RawRequest(_ context.Context, method string, params []js
 		}
 		txB, _ := serializeMsgTx(msgTx)
 		return json.Marshal(hex.EncodeToString(txB))
-	case methodSignTx:
+	case methodSignTx, methodSignTxLegacy:
 		if c.signTxErr != nil {
 			return nil, c.signTxErr
 		}
@@ -470,6 +478,62 @@ func (c *tRawRequester) RawRequest(_ context.Context, method string, params []js
 		return json.Marshal(resp)
 	case methodGetWalletInfo:
 		return json.Marshal(&GetWalletInfoResult{UnlockedUntil: nil /* unencrypted -> unlocked */})
+	case methodGetDescriptorInfo:
+		var desc string
+		if err := json.Unmarshal(params[0], &desc); err != nil {
+			return nil, err
+		}
+		return json.Marshal(&getDescriptorInfoResult{Descriptor: desc + "#checksum"})
+	case methodListDescriptors:
+		descs := make([]map[string]string, 0, len(c.importedDescriptors))
+		for _, desc := range c.importedDescriptors {
+			descs = append(descs, map[string]string{"desc": desc})
+		}
+		return json.Marshal(map[string]any{"descriptors": descs})
+	case methodImportDescriptors:
+		var reqs []*importDescriptorRequest
+		if err := json.Unmarshal(params[0], &reqs); err != nil {
+			return nil, err
+		}
+		res := make([]*importDescriptorResult, 0, len(reqs))
+		for _, req := range reqs {
+			r := &importDescriptorResult{Success: c.importDescriptorErr == ""}
+			if r.Success {
+				c.importedDescriptors = append(c.importedDescriptors, req.Descriptor)
+			} else {
+				r.Error = &struct {
+					Message string `json:"message"`
+				}{c.importDescriptorErr}
+			}
+			res = append(res, r)
+		}
+		return json.Marshal(res)
+	case methodScanTxOutSet:
+		if c.scanTxOutErr != nil {
+			return nil, c.scanTxOutErr
+		}
+		if err := json.Unmarshal(params[1], &c.scannedScripts); err != nil {
+			return nil, err
+		}
+		type unspent struct {
+			TxID         string    `json:"txid"`
+			Vout         uint32    `json:"vout"`
+			ScriptPubKey dex.Bytes `json:"scriptPubKey"`
+			Amount       float64   `json:"amount"`
+		}
+		unspents := make([]*unspent, 0, len(c.scanUnspents))
+		for _, u := range c.scanUnspents {
+			unspents = append(unspents, &unspent{
+				TxID:         u.op.TxHash.String(),
+				Vout:         u.op.Vout,
+				ScriptPubKey: u.pkScript,
+				Amount:       toBTC(u.value),
+			})
+		}
+		return json.Marshal(&struct {
+			Success  bool       `json:"success"`
+			Unspents []*unspent `json:"unspents"`
+		}{true, unspents})
 	case methodGetAddressInfo:
 		var addr string
 		err := json.Unmarshal(params[0], &addr)
@@ -616,6 +680,13 @@ func makeSwapContract(segwit bool, lockTimeOffset time.Duration) (secret []byte,
 }
 
 func tNewWallet(segwit bool, walletType string) (*intermediaryWallet, *testData, func()) {
+	return tNewCloneWallet(segwit, walletType, nil)
+}
+
+// tNewCloneWallet is like tNewWallet, but allows the BTCCloneCFG to be
+// modified before the wallet is created, so that the behavior of clones can
+// be tested.
+func tNewCloneWallet(segwit bool, walletType string, modCfg func(*BTCCloneCFG)) (*intermediaryWallet, *testData, func()) {
 	if segwit {
 		tSwapSize = dexbtc.InitTxSizeSegwit
 		tSwapSizeBase = dexbtc.InitTxSizeBaseSegwit
@@ -650,6 +721,9 @@ func tNewWallet(segwit bool, walletType string) (*intermediaryWallet, *testData,
 		FeeEstimator:        rpcFeeRate,
 		AddressDecoder:      btcutil.DecodeAddress,
 	}
+	if modCfg != nil {
+		modCfg(cfg)
+	}
 
 	var wallet *intermediaryWallet
 	switch walletType {
@@ -3252,6 +3326,20 @@ func testRedeem(t *testing.T, segwit bool, walletType string) {
 	if err == nil {
 		t.Fatalf("no error for new address error")
 	}
+
+	// An external redemption address doesn't need a wallet address.
+	extAddr := btcAddr(!segwit)
+	node.walletCfg.redeemAddress = extAddr.String()
+	node.sentRawTx = nil
+	_, _, _, err = wallet.Redeem(redemptions)
+	if err != nil {
+		t.Fatalf("error redeeming to external address: %v", err)
+	}
+	extScript, _ := txscript.PayToAddrScript(extAddr)
+	if !bytes.Equal(node.sentRawTx.TxOut[0].PkScript, extScript) {
+		t.Fatalf("redemption not paid to external address")
+	}
+	node.walletCfg.redeemAddress = ""
 	node.newAddressErr = nil
 
 	// Missing priv key error
@@ -4228,6 +4316,135 @@ func testSendEdges(t *testing.T, segwit bool, walletType string) {
 	}
 }
 
+func TestBumpFee(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	node.signFunc = func(tx *wire.MsgTx) {
+		signFunc(tx, 0, true)
+	}
+
+	const sendVal, changeVal, origFee = 1e8, 5e6, 1000
+	recipient := tP2PKHAddr
+	recipientScript, _ := txscript.PayToAddrScript(btcAddr(false))
+	changeScript, _ := txscript.PayToAddrScript(btcAddr(true))
+	node.ownedAddresses = map[string]bool{tP2WPKHAddr: true}
+
+	newSend := func(sequence uint32, changeFirst bool) *wire.MsgTx {
+		tx := wire.NewMsgTx(wire.TxVersion)
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{Hash: *tTxHash},
+			Sequence:         sequence,
+		})
+		if changeFirst {
+			tx.AddTxOut(wire.NewTxOut(changeVal, changeScript))
+			tx.AddTxOut(wire.NewTxOut(sendVal, recipientScript))
+		} else {
+			tx.AddTxOut(wire.NewTxOut(sendVal, recipientScript))
+			tx.AddTxOut(wire.NewTxOut(changeVal, changeScript))
+		}
+		signFunc(tx, 0, true)
+		txHash := tx.TxHash()
+		txB, _ := serializeMsgTx(tx)
+		node.getTransactionMap[txHash.String()] = &GetTransactionResult{Bytes: txB}
+		wallet.pendingTxsMtx.Lock()
+		wallet.pendingTxs[txHash] = ExtendedWalletTx{
+			WalletTransaction: &asset.WalletTransaction{
+				Type:      asset.Send,
+				ID:        txHash.String(),
+				Amount:    sendVal,
+				Fees:      origFee,
+				Recipient: &recipient,
+			},
+		}
+		wallet.pendingTxsMtx.Unlock()
+		return tx
+	}
+
+	tx := newSend(rbfSequence, false)
+	txHash := tx.TxHash()
+	vSize := wallet.calcTxSize(tx)
+	feeRate := origFee/vSize + 5
+
+	ensureErr := func(tag string) {
+		t.Helper()
+		node.sentRawTx = nil
+		if _, err := wallet.BumpFee(txHash.String(), feeRate); err == nil {
+			t.Fatalf("%s: no error", tag)
+		}
+		if node.sentRawTx != nil {
+			t.Fatalf("%s: transaction sent", tag)
+		}
+	}
+
+	// Not a pending send.
+	if _, err := wallet.BumpFee(tTxID, feeRate); err == nil {
+		t.Fatalf("no error for unknown transaction")
+	}
+
+	// The change is funding an order.
+	wallet.cm.LockUTXOs([]*UTxO{{TxHash: &txHash, Vout: 1, Amount: changeVal}})
+	ensureErr("locked change")
+	wallet.cm.UnlockOutPoints([]OutPoint{NewOutPoint(&txHash, 1)})
+
+	// The change has been spent.
+	txOutRes := node.txOutRes
+	node.txOutRes = nil
+	ensureErr("spent change")
+	node.txOutRes = txOutRes
+
+	// No output pays the wallet.
+	node.ownedAddresses = nil
+	ensureErr("no change")
+	node.ownedAddresses = map[string]bool{tP2WPKHAddr: true}
+
+	// Fee increase too small.
+	feeRate = origFee / vSize
+	ensureErr("fee too low")
+	feeRate = origFee/vSize + 5
+
+	checkReplacement := func(tag string, newTxID string, changeIdx, sendIdx int) {
+		t.Helper()
+		replacement := node.sentRawTx
+		if replacement == nil {
+			t.Fatalf("%s: replacement not sent", tag)
+		}
+		if replacementHash := replacement.TxHash(); newTxID != replacementHash.String() {
+			t.Fatalf("%s: wrong replacement ID", tag)
+		}
+		if replacement.TxOut[sendIdx].Value != sendVal {
+			t.Fatalf("%s: recipient amount changed to %d", tag, replacement.TxOut[sendIdx].Value)
+		}
+		if fee := uint64(changeVal - replacement.TxOut[changeIdx].Value + origFee); fee != feeRate*vSize {
+			t.Fatalf("%s: wrong fee. wanted %d, got %d", tag, feeRate*vSize, fee)
+		}
+		if !signalsRBF(replacement) {
+			t.Fatalf("%s: replacement does not signal replaceability", tag)
+		}
+	}
+
+	newTxID, err := wallet.BumpFee(txHash.String(), feeRate)
+	if err != nil {
+		t.Fatalf("BumpFee error: %v", err)
+	}
+	checkReplacement("change last", newTxID, 1, 0)
+
+	// The change output is first.
+	tx = newSend(rbfSequence, true)
+	txHash = tx.TxHash()
+	node.sentRawTx = nil
+	newTxID, err = wallet.BumpFee(txHash.String(), feeRate)
+	if err != nil {
+		t.Fatalf("BumpFee error with change first: %v", err)
+	}
+	checkReplacement("change first", newTxID, 0, 1)
+
+	// A transaction that does not signal replaceability.
+	tx = newSend(wire.MaxTxInSequenceNum, false)
+	txHash = tx.TxHash()
+	ensureErr("not replaceable")
+}
+
 func TestSyncStatus(t *testing.T) {
 	runRubric(t, testSyncStatus)
 }
@@ -5553,6 +5770,194 @@ func testGetTxFee(t *testing.T, segwit bool, walletType string) {
 	}
 }
 
+// TestAccelerateOrderNonSegwitClone runs PreAccelerate and AccelerateOrder
+// against a wallet configured like Dogecoin, which is non-segwit, has fee
+// rates in the thousands of atoms/byte, and enforces a constant dust limit.
+func TestAccelerateOrderNonSegwitClone(t *testing.T) {
+	const (
+		dustLimit     = 1_000_000 // doge's DEFAULT_DUST_LIMIT
+		swapFeeRate   = 1_000     // doge's minimum relay fee rate
+		feeSuggestion = 4_000
+		newFeeRate    = 4_000
+		swapVal       = 10e8
+	)
+
+	w, node, shutdown := tNewCloneWallet(false, walletTypeRPC, func(cfg *BTCCloneCFG) {
+		cfg.Symbol = "doge"
+		cfg.ConstantDustLimit = dustLimit
+		cfg.LegacySignTxRPC = true
+		cfg.OmitAddressType = true
+		cfg.DefaultFallbackFee = feeSuggestion
+		cfg.DefaultFeeRateLimit = 50_000
+	})
+	defer shutdown()
+	wallet := &ExchangeWalletAccelerator{&ExchangeWalletFullNode{w, &authAddOn{w.node}}}
+
+	node.changeAddr = tP2PKHAddr
+	node.newAddress = tP2PKHAddr
+	node.signFunc = func(tx *wire.MsgTx) {
+		signFunc(tx, 0, false)
+	}
+
+	// The size of a one-input swap with change, which doesn't depend on the
+	// output values.
+	swapSize := dexbtc.MinimumTxOverhead + dexbtc.RedeemP2PKHInputSize + 2*dexbtc.P2PKHOutputSize
+	swapFee := uint64(swapSize * swapFeeRate)
+
+	// swapChain creates a confirmed funding tx and an unconfirmed swap with
+	// change at index 0, paying swapFeeRate.
+	swapChain := func(changeVal int64) (swapHash chainhash.Hash) {
+		fundingTx := &wire.MsgTx{
+			TxIn:  []*wire.TxIn{{PreviousOutPoint: wire.OutPoint{Hash: *tTxHash}}},
+			TxOut: []*wire.TxOut{{Value: changeVal + swapVal + int64(swapFee), PkScript: tP2PKH}},
+		}
+		fundingB, _ := serializeMsgTx(fundingTx)
+		node.getTransactionMap = map[string]*GetTransactionResult{
+			fundingTx.TxHash().String(): {Bytes: fundingB, Confirmations: 1},
+		}
+
+		swapTx := &wire.MsgTx{
+			TxIn: []*wire.TxIn{{PreviousOutPoint: wire.OutPoint{Hash: fundingTx.TxHash()}}},
+			TxOut: []*wire.TxOut{
+				{Value: changeVal, PkScript: tP2PKH},
+				{Value: swapVal, PkScript: tP2PKH},
+			},
+		}
+		signFunc(swapTx, 0, false)
+		if size := dexbtc.MsgTxVBytes(swapTx); size != uint64(swapSize) {
+			t.Fatalf("expected swap size %d, got %d", swapSize, size)
+		}
+		swapB, _ := serializeMsgTx(swapTx)
+		swapHash = swapTx.TxHash()
+		node.getTransactionMap[swapHash.String()] = &GetTransactionResult{
+			TxID:  swapHash.String(),
+			Bytes: swapB,
+			Time:  uint64(time.Now().Unix()) - minTimeBeforeAcceleration - 1000,
+		}
+		node.listUnspent = []*ListUnspentResult{{TxID: swapHash.String(), Vout: 0}}
+		node.listLockUnspent = []*RPCOutpoint{{TxID: swapHash.String(), Vout: 0}}
+		node.sentRawTx = nil
+		return swapHash
+	}
+
+	changeVal := int64(90e8)
+	swapHash := swapChain(changeVal)
+	swapCoins := []dex.Bytes{ToCoinID(&swapHash, 1)}
+	changeCoin := ToCoinID(&swapHash, 0)
+
+	// PreAccelerate
+	const requiredForRemainingSwaps uint64 = 20e8
+	currentRate, suggestedRange, early, err := wallet.PreAccelerate(swapCoins, nil, changeCoin, requiredForRemainingSwaps, feeSuggestion)
+	if err != nil {
+		t.Fatalf("PreAccelerate error: %v", err)
+	}
+	if currentRate != swapFeeRate {
+		t.Fatalf("expected current rate %d, got %d", swapFeeRate, currentRate)
+	}
+	if early != nil {
+		t.Fatalf("unexpected early acceleration: %+v", early)
+	}
+	if suggestedRange.Start.Y != swapFeeRate+1 || suggestedRange.End.Y != feeSuggestion*5 {
+		t.Fatalf("wrong suggested range %v - %v", suggestedRange.Start.Y, suggestedRange.End.Y)
+	}
+
+	// checkAcceleration checks the sent acceleration tx and returns the value
+	// of its change output, or -1 if it has none.
+	checkAcceleration := func(change asset.Coin, fees uint64) int64 {
+		t.Helper()
+		tx := node.sentRawTx
+		if tx == nil {
+			t.Fatalf("acceleration tx not sent")
+		}
+		if len(tx.TxIn) != 1 || tx.TxIn[0].PreviousOutPoint != (wire.OutPoint{Hash: swapHash, Index: 0}) {
+			t.Fatalf("acceleration tx does not spend only the order change")
+		}
+		if tx.HasWitness() || len(tx.TxIn[0].SignatureScript) != dexbtc.RedeemP2PKHSigScriptSize {
+			t.Fatalf("acceleration tx not signed with a p2pkh sigScript")
+		}
+		var out uint64
+		for _, txOut := range tx.TxOut {
+			out += uint64(txOut.Value)
+		}
+		if fee := uint64(changeVal) - out; fee != fees {
+			t.Fatalf("expected fees %d, got %d", fee, fees)
+		}
+		// The swap and acceleration together must pay at least the new rate.
+		totalSize := uint64(swapSize) + dexbtc.MsgTxVBytes(tx)
+		if effectiveRate := (swapFee + fees) / totalSize; effectiveRate < newFeeRate {
+			t.Fatalf("effective fee rate %d < %d", effectiveRate, newFeeRate)
+		}
+		if len(tx.TxOut) == 0 {
+			if change != nil {
+				t.Fatalf("change returned without a change output")
+			}
+			return -1
+		}
+		if len(tx.TxOut) != 1 {
+			t.Fatalf("expected 1 output, got %d", len(tx.TxOut))
+		}
+		if change == nil || change.Value() != uint64(tx.TxOut[0].Value) {
+			t.Fatalf("returned change does not match the change output")
+		}
+		return tx.TxOut[0].Value
+	}
+
+	// Funds are still required for the remaining swaps.
+	change, _, err := wallet.AccelerateOrder(swapCoins, nil, changeCoin, requiredForRemainingSwaps, newFeeRate)
+	if err != nil {
+		t.Fatalf("AccelerateOrder error: %v", err)
+	}
+	estimate, err := wallet.AccelerationEstimate(swapCoins, nil, changeCoin, requiredForRemainingSwaps, newFeeRate)
+	if err != nil {
+		t.Fatalf("AccelerationEstimate error: %v", err)
+	}
+	if changeOut := checkAcceleration(change, estimate); uint64(changeOut) < requiredForRemainingSwaps {
+		t.Fatalf("change %d < required %d", changeOut, requiredForRemainingSwaps)
+	}
+
+	// The fees required to accelerate the swap with a change-less tx.
+	accelSize := uint64(dexbtc.MinimumTxOverhead + dexbtc.RedeemP2PKHInputSize)
+	accelFees := (uint64(swapSize)+accelSize)*newFeeRate - swapFee
+
+	// Change left after the acceleration that is above the constant dust limit
+	// is kept, even though dexbtc.IsDust would consider it dust at this fee
+	// rate.
+	const leftover = dustLimit * 3 / 2
+	if !dexbtc.IsDust(&wire.TxOut{Value: leftover, PkScript: tP2PKH}, newFeeRate) {
+		t.Fatalf("test leftover is not dust by the standard formula")
+	}
+	changeVal = int64(accelFees + dexbtc.P2PKHOutputSize*newFeeRate + leftover)
+	swapHash = swapChain(changeVal)
+	swapCoins = []dex.Bytes{ToCoinID(&swapHash, 1)}
+	changeCoin = ToCoinID(&swapHash, 0)
+	node.listLockUnspent = nil // the order has no more swaps
+	change, _, err = wallet.AccelerateOrder(swapCoins, nil, changeCoin, 0, newFeeRate)
+	if err != nil {
+		t.Fatalf("AccelerateOrder (leftover) error: %v", err)
+	}
+	fees := uint64(changeVal)
+	for _, txOut := range node.sentRawTx.TxOut {
+		fees -= uint64(txOut.Value)
+	}
+	if changeOut := checkAcceleration(change, fees); changeOut < dustLimit {
+		t.Fatalf("expected change above the dust limit, got %d", changeOut)
+	}
+
+	// Change below the dust limit goes to the miners.
+	changeVal = int64(accelFees + dustLimit/2)
+	swapHash = swapChain(changeVal)
+	swapCoins = []dex.Bytes{ToCoinID(&swapHash, 1)}
+	changeCoin = ToCoinID(&swapHash, 0)
+	node.listLockUnspent = nil // the order has no more swaps
+	change, _, err = wallet.AccelerateOrder(swapCoins, nil, changeCoin, 0, newFeeRate)
+	if err != nil {
+		t.Fatalf("AccelerateOrder (dust) error: %v", err)
+	}
+	if changeOut := checkAcceleration(change, uint64(changeVal)); changeOut != -1 {
+		t.Fatalf("expected no change output, got %d", changeOut)
+	}
+}
+
 func TestTooEarlyToAccelerate(t *testing.T) {
 	type tx struct {
 		secondsBeforeNow uint64
@@ -5819,6 +6224,7 @@ func TestConfirmRedemption(t *testing.T) {
 		getTransactionResult *GetTransactionResult
 		txOutErr             error
 		getTransactionErr    error
+		redeemAddress        string
 	}{{
 		name:                 "ok and found",
 		coinID:               coinID,
@@ -5834,6 +6240,13 @@ func TestConfirmRedemption(t *testing.T) {
 		coinID:     coinID,
 		redemption: redemption,
 		txOutRes:   new(btcjson.GetTxOutResult),
+	}, {
+		name:          "ok redeemed to external address",
+		coinID:        coinID,
+		redemption:    redemption,
+		txOutRes:      &btcjson.GetTxOutResult{Confirmations: 2},
+		redeemAddress: tP2PKHAddr,
+		wantConfs:     2,
 	}, {
 		name:       "decode coin error",
 		redemption: redemption,
@@ -5874,6 +6287,7 @@ func TestConfirmRedemption(t *testing.T) {
 		node.txOutErr = test.txOutErr
 		node.getTransactionErr = test.getTransactionErr
 		node.getTransactionMap[tTxID] = test.getTransactionResult
+		node.walletCfg.redeemAddress = test.redeemAddress
 
 		status, err := wallet.ConfirmRedemption(test.coinID, test.redemption, 0)
 		if test.wantErr {
@@ -6483,3 +6897,116 @@ func TestFeeRateCache(t *testing.T) {
 		t.Fatal("counter not incremented for recovered rate")
 	}
 }
+
+func TestWatchContract(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	rpc := wallet.node.(*rpcClient)
+	contract := randBytes(97)
+	contractAddr, _ := btcutil.NewAddressWitnessScriptHash(hashContract(true, contract), &chaincfg.MainNetParams)
+	expDesc := "addr(" + contractAddr.String() + ")#checksum"
+
+	// Wallets with private keys don't import contracts.
+	wallet.watchContract(contract, time.Time{})
+	if len(node.importedDescriptors) != 0 {
+		t.Fatalf("descriptor imported for a wallet with private keys")
+	}
+
+	rpc.watchOnly = true
+	wallet.watchContract(contract, time.Time{})
+	if len(node.importedDescriptors) != 1 || node.importedDescriptors[0] != expDesc {
+		t.Fatalf("wrong imported descriptors %v, expected %s", node.importedDescriptors, expDesc)
+	}
+
+	node.importDescriptorErr = "rescan failed"
+	if err := rpc.watchContract(contractAddr.String(), time.Now()); err == nil {
+		t.Fatalf("no error for failed import")
+	}
+}
+
+func TestExcludeUnspent(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+	cl := wallet.node.(*rpcClient)
+
+	node.listUnspent = []*ListUnspentResult{
+		{TxID: tTxID, Address: "bc1qregular", Amount: 1},
+		{TxID: tTxID, Vout: 1, Address: "ltcmweb1excluded", Amount: 2},
+	}
+	unspents, err := cl.ListUnspent()
+	if err != nil {
+		t.Fatalf("ListUnspent error: %v", err)
+	}
+	if len(unspents) != 2 {
+		t.Fatalf("expected 2 unspents without a filter, got %d", len(unspents))
+	}
+
+	cl.excludeUnspent = func(u *ListUnspentResult) bool {
+		return strings.HasPrefix(u.Address, "ltcmweb1")
+	}
+	unspents, err = cl.ListUnspent()
+	if err != nil {
+		t.Fatalf("ListUnspent error: %v", err)
+	}
+	if len(unspents) != 1 || unspents[0].Vout != 0 {
+		t.Fatalf("excluded output returned")
+	}
+}
+
+func TestPendingDeposits(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	node.getBalances = &GetBalancesResult{}
+	node.getBalances.Mine.Trusted = 1
+	node.getBalances.Mine.Untrusted = 0.5
+
+	addPending := func(txType asset.TransactionType, amt uint64) *asset.WalletTransaction {
+		var txHash chainhash.Hash
+		copy(txHash[:], encode.RandomBytes(32))
+		wt := &asset.WalletTransaction{Type: txType, ID: txHash.String(), Amount: amt}
+		wallet.pendingTxsMtx.Lock()
+		wallet.pendingTxs[txHash] = ExtendedWalletTx{WalletTransaction: wt, Submitted: true}
+		wallet.pendingTxsMtx.Unlock()
+		return wt
+	}
+
+	checkPending := func(exp uint64) {
+		t.Helper()
+		bal, err := wallet.Balance()
+		if err != nil {
+			t.Fatalf("Balance error: %v", err)
+		}
+		cb, found := bal.Other[asset.BalanceCategoryPendingDeposit]
+		if exp == 0 {
+			if found {
+				t.Fatalf("unexpected pending deposit balance %d", cb.Amount)
+			}
+			return
+		}
+		if cb.Amount != exp {
+			t.Fatalf("wrong pending deposit balance. wanted %d, got %d", exp, cb.Amount)
+		}
+	}
+
+	checkPending(0)
+	// Sends aren't deposits.
+	addPending(asset.Send, 1e8)
+	checkPending(0)
+	deposit := addPending(asset.Receive, 3e7)
+	checkPending(3e7)
+	// The pending deposits can't be more than the unconfirmed balance.
+	addPending(asset.Receive, 3e7)
+	checkPending(5e7)
+
+	noteChan := make(chan asset.WalletNotification, 2)
+	wallet.emit = asset.NewWalletEmitter(noteChan, BipID, tLogger)
+	wallet.notifyPendingDeposits([]*asset.WalletTransaction{deposit})
+	if note, is := (<-noteChan).(*asset.PendingDepositNote); !is || note.Transaction.ID != deposit.ID {
+		t.Fatalf("expected a pending deposit note for the deposit")
+	}
+	if note, is := (<-noteChan).(*asset.BalanceChangeNote); !is || note.Balance.Other[asset.BalanceCategoryPendingDeposit].Amount != 5e7 {
+		t.Fatalf("expected a balance change note with the pending deposits")
+	}
+}
@@ -0,0 +1,80 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"context"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+// pendingDepositPollInterval is how often the wallet checks for incoming
+// mempool transactions between blocks.
+const pendingDepositPollInterval = 15 * time.Second
+
+// watchPendingDeposits periodically adds new wallet transactions to the
+// history, so that incoming payments are reported before they are mined.
+func (btc *intermediaryWallet) watchPendingDeposits(ctx context.Context) {
+	ticker := time.NewTicker(pendingDepositPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			btc.checkPendingDeposits()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkPendingDeposits looks for unknown transactions, unless the history is
+// being synced. Nothing is done before the initial history sync, since new
+// transactions are not reported for it.
+func (btc *intermediaryWallet) checkPendingDeposits() {
+	if btc.receiveTxLastQuery.Load() == 0 {
+		return
+	}
+	if !btc.syncingTxHistory.CompareAndSwap(false, true) {
+		return
+	}
+	defer btc.syncingTxHistory.Store(false)
+	btc.tipMtx.RLock()
+	tip := uint64(btc.currentTip.Height)
+	btc.tipMtx.RUnlock()
+	btc.addUnknownTransactionsToHistory(tip)
+}
+
+// notifyPendingDeposits emits a PendingDepositNote for each of the new
+// unconfirmed incoming transactions, and a BalanceChangeNote with the
+// provisional balance.
+func (btc *baseWallet) notifyPendingDeposits(deposits []*asset.WalletTransaction) {
+	if len(deposits) == 0 {
+		return
+	}
+	for _, wt := range deposits {
+		btc.log.Infof("Incoming %s transaction %s for %s is in the mempool",
+			btc.symbol, wt.ID, amount(wt.Amount))
+		btc.emit.PendingDeposit(wt)
+	}
+	bal, err := btc.Balance()
+	if err != nil {
+		btc.log.Errorf("Error getting balance after pending deposit: %v", err)
+		return
+	}
+	btc.emit.BalanceChange(bal)
+}
+
+// pendingDeposits is the total amount of the unconfirmed incoming
+// transactions in the history.
+func (btc *baseWallet) pendingDeposits() (total uint64) {
+	btc.pendingTxsMtx.RLock()
+	defer btc.pendingTxsMtx.RUnlock()
+	for _, tx := range btc.pendingTxs {
+		if tx.Type == asset.Receive && tx.BlockNumber == 0 {
+			total += tx.Amount
+		}
+	}
+	return total
+}
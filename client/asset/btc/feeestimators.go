@@ -0,0 +1,158 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/dexnet"
+)
+
+// maxFeeRateDisagreement is the largest ratio between a fee rate estimate and
+// the median of the estimates for which the estimate is used.
+const maxFeeRateDisagreement = 3
+
+// feeEstimatorsOpt is a list of additional fee rate APIs.
+var feeEstimatorsOpt = &asset.ConfigOption{
+	Key:         "feeestimators",
+	DisplayName: "Additional fee rate APIs",
+	Description: "Comma-separated URLs of mempool.space-compatible fee rate " +
+		"APIs, e.g. https://mempool.space/api/v1/fees/recommended. If " +
+		"external fee rate estimates are enabled, the estimates of the node " +
+		"and all APIs are combined. APIs that are down, or that disagree " +
+		"with the others, are ignored.",
+}
+
+// parseFeeEstimators parses the comma-separated fee rate API URLs, and
+// creates a cached estimator for each.
+func parseFeeEstimators(urls string) ([]*feeRateCache, error) {
+	var estimators []*feeRateCache
+	for _, uri := range strings.Split(urls, ",") {
+		if uri = strings.TrimSpace(uri); uri == "" {
+			continue
+		}
+		u, err := url.Parse(uri)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee rate API URL %q: %w", uri, err)
+		}
+		if u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+			return nil, fmt.Errorf("invalid fee rate API URL %q", uri)
+		}
+		estimators = append(estimators, &feeRateCache{
+			f: func(ctx context.Context, _ dex.Network) (uint64, error) {
+				return mempoolSpaceFeeRate(ctx, uri)
+			},
+		})
+	}
+	return estimators, nil
+}
+
+// mempoolSpaceFeeRate gets the fastest fee rate, in sats/vByte, from a
+// mempool.space-compatible recommended fees API.
+func mempoolSpaceFeeRate(ctx context.Context, uri string) (uint64, error) {
+	// https://mempool.space/docs/api
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
+	defer cancel()
+	var resp struct {
+		Fastest  uint64 `json:"fastestFee"`
+		HalfHour uint64 `json:"halfHourFee"`
+		Hour     uint64 `json:"hourFee"`
+		Economy  uint64 `json:"economyFee"`
+		Minimum  uint64 `json:"minimumFee"`
+	}
+	if err := dexnet.Get(ctx, uri, &resp, dexnet.WithSizeLimit(1<<14)); err != nil {
+		return 0, err
+	}
+	if resp.Fastest == 0 {
+		return 0, errors.New("no fee rate found")
+	}
+	return resp.Fastest, nil
+}
+
+// externalFeeRates gets the rates of the estimators concurrently. The rates
+// are in the order of the estimators, and the estimators that fail are
+// omitted.
+func (btc *baseWallet) externalFeeRates(estimators []*feeRateCache) []uint64 {
+	rates := make([]uint64, len(estimators))
+	var wg sync.WaitGroup
+	for i, c := range estimators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			feeRate, err := c.rate(btc.ctx, btc.Network)
+			if err != nil {
+				btc.log.Meter(fmt.Sprintf("feeRate.external.%d.fail", i), time.Hour).Errorf(
+					"Failed to get fee rate from external API: %v", err)
+				return
+			}
+			rates[i] = feeRate
+		}()
+	}
+	wg.Wait()
+	successful := rates[:0]
+	for _, r := range rates {
+		if r > 0 {
+			successful = append(successful, r)
+		}
+	}
+	return successful
+}
+
+// blendFeeRates combines the fee rate estimates of several sources, in order
+// of preference. Rates over the limit are discarded. With three or more
+// rates, the rates that are more than maxFeeRateDisagreement times from the
+// median are discarded, and the median of the rest is used. With fewer rates,
+// the rates are averaged if they agree, and the preferred rate is used if
+// they don't. Zero is returned if there are no acceptable rates.
+func blendFeeRates(rates []uint64, limit uint64) uint64 {
+	inBounds := make([]uint64, 0, len(rates))
+	for _, r := range rates {
+		if r > 0 && r <= limit {
+			inBounds = append(inBounds, r)
+		}
+	}
+	if len(inBounds) == 0 {
+		return 0
+	}
+	agrees := func(r, ref uint64) bool {
+		return r*maxFeeRateDisagreement >= ref && r <= ref*maxFeeRateDisagreement
+	}
+	if len(inBounds) < 3 {
+		for _, r := range inBounds[1:] {
+			if !agrees(r, inBounds[0]) {
+				return inBounds[0]
+			}
+		}
+		return median(inBounds)
+	}
+	ref := median(inBounds)
+	agreed := make([]uint64, 0, len(inBounds))
+	for _, r := range inBounds {
+		if agrees(r, ref) {
+			agreed = append(agreed, r)
+		}
+	}
+	return median(agreed)
+}
+
+// median is the median of the rates, rounded up. The rates must not be empty.
+func median(rates []uint64) uint64 {
+	sorted := make([]uint64, len(rates))
+	copy(sorted, rates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2] + 1) / 2
+}
@@ -0,0 +1,106 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBlendFeeRates(t *testing.T) {
+	const limit = 100
+	tests := []struct {
+		name  string
+		rates []uint64
+		exp   uint64
+	}{
+		{"none", nil, 0},
+		{"one", []uint64{10}, 10},
+		{"over limit", []uint64{200}, 0},
+		{"over limit discarded", []uint64{200, 10}, 10},
+		{"two agree", []uint64{10, 20}, 15},
+		{"two disagree", []uint64{10, 50}, 10},
+		{"two disagree, preferred first", []uint64{50, 10}, 50},
+		{"three agree", []uint64{10, 12, 20}, 12},
+		{"outlier", []uint64{10, 12, 90}, 11},
+		{"low outlier", []uint64{2, 30, 40}, 35},
+		{"four", []uint64{10, 11, 12, 13}, 12},
+	}
+	for _, tt := range tests {
+		if r := blendFeeRates(tt.rates, limit); r != tt.exp {
+			t.Fatalf("%s: wanted %d, got %d", tt.name, tt.exp, r)
+		}
+	}
+}
+
+func TestParseFeeEstimators(t *testing.T) {
+	estimators, err := parseFeeEstimators(" https://a.example.com/api/v1/fees/recommended, ,http://b.example.com/fees ")
+	if err != nil {
+		t.Fatalf("parseFeeEstimators error: %v", err)
+	}
+	if len(estimators) != 2 {
+		t.Fatalf("expected 2 estimators, got %d", len(estimators))
+	}
+	if estimators, _ := parseFeeEstimators(""); len(estimators) != 0 {
+		t.Fatalf("expected no estimators, got %d", len(estimators))
+	}
+	for _, bad := range []string{"a.example.com", "ftp://a.example.com", "https://"} {
+		if _, err := parseFeeEstimators(bad); err == nil {
+			t.Fatalf("no error for %q", bad)
+		}
+	}
+}
+
+func TestExternalFeeRates(t *testing.T) {
+	wallet, _, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	feeServer := func(rate uint64) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"fastestFee":%d,"halfHourFee":1,"hourFee":1,"economyFee":1,"minimumFee":1}`, rate)
+		}))
+	}
+	upSrv, otherSrv := feeServer(20), feeServer(30)
+	defer upSrv.Close()
+	defer otherSrv.Close()
+	downSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer downSrv.Close()
+
+	setEstimators := func(urls string) {
+		t.Helper()
+		cfg := *wallet.cfgV.Load().(*baseWalletConfig)
+		cfg.apiFeeFallback = true
+		var err error
+		if cfg.feeEstimators, err = parseFeeEstimators(urls); err != nil {
+			t.Fatalf("parseFeeEstimators error: %v", err)
+		}
+		wallet.cfgV.Store(&cfg)
+	}
+
+	// The local estimate is the preferred rate.
+	localRate := optimalFeeRate
+	wallet.symbol = "ltc"
+	wallet.ctx = tCtx
+
+	// A down API is skipped, and the local and working API's rates are
+	// blended.
+	setEstimators(downSrv.URL + "," + upSrv.URL)
+	if r, err := wallet.feeRate(1); err != nil || r != (localRate+20+1)/2 {
+		t.Fatalf("wrong blended rate %d, %v", r, err)
+	}
+
+	setEstimators(downSrv.URL + "," + upSrv.URL + "," + otherSrv.URL)
+	if r, err := wallet.feeRate(1); err != nil || r != localRate {
+		t.Fatalf("wrong blended rate %d, %v", r, err)
+	}
+
+	// With all APIs down, the local rate is used.
+	setEstimators(downSrv.URL)
+	if r, err := wallet.feeRate(1); err != nil || r != localRate {
+		t.Fatalf("wrong rate with APIs down %d, %v", r, err)
+	}
+}
@@ -0,0 +1,250 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// fullNodeRetryInterval is how long the hybrid wallet waits before trying an
+// unreachable full node again.
+const fullNodeRetryInterval = time.Minute
+
+// fullNodeConfigOpts are the settings for the full node used by a hybrid SPV
+// wallet.
+var fullNodeConfigOpts = []*asset.ConfigOption{
+	{
+		Key:         "fullnoderpcbind",
+		DisplayName: "Full node RPC address",
+		Description: "The RPC address of a local or remote bitcoind, e.g. " +
+			"127.0.0.1:8332. When set, the full node is used to look up " +
+			"transactions, blocks, and fee rates, falling back to the " +
+			"built-in SPV wallet when the node is unreachable. The SPV " +
+			"wallet holds the keys, so bitcoind does not need a wallet loaded.",
+	},
+	{
+		Key:         "fullnoderpcuser",
+		DisplayName: "Full node RPC username",
+		Description: "bitcoind's 'rpcuser' setting",
+	},
+	{
+		Key:         "fullnoderpcpassword",
+		DisplayName: "Full node RPC password",
+		Description: "bitcoind's 'rpcpassword' setting",
+		NoEcho:      true,
+	},
+}
+
+// hybridWallet is an SPV wallet that uses a full node's chain data when the
+// node is reachable. The built-in wallet holds the keys and the transaction
+// history, so the wallet has the same addresses and history regardless of
+// the node's availability. Chain queries that are slow with compact block
+// filters, such as finding a transaction output, are tried with the node
+// first.
+type hybridWallet struct {
+	*spvWallet
+	node *rpcClient
+	log  dex.Logger
+
+	mtx       sync.Mutex
+	downUntil time.Time
+}
+
+var _ TipRedemptionWallet = (*hybridWallet)(nil)
+
+// newHybridWallet creates a hybridWallet for the full node configured in
+// walletCfg.
+func newHybridWallet(spvw *spvWallet, btc *baseWallet, cfg *BTCCloneCFG, walletCfg *WalletConfig) (*hybridWallet, error) {
+	rpcCfg := &RPCWalletConfig{
+		RPCConfig: RPCConfig{
+			RPCConfig: dexbtc.RPCConfig{
+				RPCUser: walletCfg.FullNodeRPCUser,
+				RPCPass: walletCfg.FullNodeRPCPass,
+				RPCBind: walletCfg.FullNodeRPCBind,
+			},
+		},
+	}
+	if err := dexbtc.CheckRPCConfig(&rpcCfg.RPCConfig.RPCConfig, "full node", cfg.Network, cfg.Ports); err != nil {
+		return nil, err
+	}
+	// The node doesn't need a wallet, so don't use a wallet endpoint.
+	cl, err := newRPCConnection(rpcCfg, true)
+	if err != nil {
+		return nil, fmt.Errorf("error creating full node RPC client: %w", err)
+	}
+
+	blockDeserializer := cfg.BlockDeserializer
+	if blockDeserializer == nil {
+		blockDeserializer = deserializeBlock
+	}
+	log := cfg.Logger.SubLogger("NODE")
+	core := &rpcCore{
+		rpcConfig:         &rpcCfg.RPCConfig,
+		cloneParams:       cfg,
+		segwit:            cfg.Segwit,
+		decodeAddr:        btc.decodeAddr,
+		stringAddr:        btc.stringAddr,
+		deserializeBlock:  blockDeserializer,
+		minNetworkVersion: cfg.MinNetworkVersion,
+		log:               log,
+		chainParams:       cfg.ChainParams,
+		booleanGetBlock:   cfg.BooleanGetBlockRPC,
+
+		deserializeTx:      btc.deserializeTx,
+		serializeTx:        btc.serializeTx,
+		hashTx:             btc.hashTx,
+		numericGetRawTxRPC: cfg.NumericGetRawRPC,
+		manualMedianTime:   cfg.ManualMedianTime,
+	}
+	core.requesterV.Store(RawRequester(cl))
+
+	return &hybridWallet{
+		spvWallet: spvw,
+		node:      newRPCClient(core),
+		log:       log,
+	}, nil
+}
+
+// Connect connects the SPV wallet and checks the full node. An unreachable
+// node is not an error.
+func (w *hybridWallet) Connect(ctx context.Context, wg *sync.WaitGroup) error {
+	if err := w.spvWallet.Connect(ctx, wg); err != nil {
+		return err
+	}
+	w.node.ctx = ctx
+	chainInfo, err := w.node.getBlockchainInfo()
+	if err != nil {
+		w.log.Warnf("Full node unreachable. Using SPV until it is available: %v", err)
+		w.nodeFailed(err)
+		return nil
+	}
+	if !ChainOK(w.node.cloneParams.Network, chainInfo.Chain) {
+		return fmt.Errorf("full node is on the wrong network (%s)", chainInfo.Chain)
+	}
+	w.log.Infof("Connected to full node at %s", w.node.rpcConfig.RPCBind)
+	return nil
+}
+
+// nodeAvailable checks whether the full node should be tried.
+func (w *hybridWallet) nodeAvailable() bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return time.Now().After(w.downUntil)
+}
+
+// nodeFailed checks whether the error from a full node request means that the
+// node could not be reached, in which case the node is not tried again for
+// fullNodeRetryInterval and the request should be made with the SPV wallet.
+// Errors returned by the node itself are not connection errors.
+func (w *hybridWallet) nodeFailed(err error) bool {
+	var rpcErr *btcjson.RPCError
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+	w.mtx.Lock()
+	wasUp := time.Now().After(w.downUntil)
+	w.downUntil = time.Now().Add(fullNodeRetryInterval)
+	w.mtx.Unlock()
+	if wasUp {
+		w.log.Warnf("Full node request failed. Falling back to SPV: %v", err)
+	}
+	return true
+}
+
+// RawRequest sends the request to the full node. This enables fee estimates
+// from the node's mempool.
+func (w *hybridWallet) RawRequest(ctx context.Context, method string, params []json.RawMessage) (json.RawMessage, error) {
+	if !w.nodeAvailable() {
+		return nil, errors.New("full node unavailable")
+	}
+	res, err := w.node.requester().RawRequest(ctx, method, params)
+	if err != nil {
+		w.nodeFailed(err)
+	}
+	return res, err
+}
+
+// SendRawTransaction broadcasts the transaction with the full node, if
+// available, and with the SPV wallet, which records the transaction in the
+// wallet's history.
+func (w *hybridWallet) SendRawTransaction(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	var nodeHash *chainhash.Hash
+	if w.nodeAvailable() {
+		var err error
+		nodeHash, err = w.node.SendRawTransaction(tx)
+		if err != nil && !w.nodeFailed(err) {
+			// The node rejected the transaction.
+			return nil, err
+		}
+	}
+	txHash, err := w.spvWallet.SendRawTransaction(tx)
+	if err != nil && nodeHash != nil {
+		w.log.Warnf("Transaction %s was broadcast by the full node but the SPV wallet returned an error: %v", nodeHash, err)
+		return nodeHash, nil
+	}
+	return txHash, err
+}
+
+// GetTxOut looks up the output with the full node, which doesn't need to scan
+// block filters.
+func (w *hybridWallet) GetTxOut(txHash *chainhash.Hash, index uint32, pkScript []byte, startTime time.Time) (*wire.TxOut, uint32, error) {
+	if w.nodeAvailable() {
+		txOut, confs, err := w.node.GetTxOut(txHash, index, pkScript, startTime)
+		if err == nil || !w.nodeFailed(err) {
+			return txOut, confs, err
+		}
+	}
+	return w.spvWallet.GetTxOut(txHash, index, pkScript, startTime)
+}
+
+// SwapConfirmations checks for an unspent swap output with the full node.
+// Spent outputs are found with the SPV wallet.
+func (w *hybridWallet) SwapConfirmations(txHash *chainhash.Hash, vout uint32, contract []byte, startTime time.Time) (uint32, bool, error) {
+	if w.nodeAvailable() {
+		txOut, err := w.node.getTxOutput(txHash, vout)
+		if err == nil && txOut != nil {
+			return uint32(txOut.Confirmations), false, nil
+		}
+		if err != nil {
+			w.nodeFailed(err)
+		}
+	}
+	return w.spvWallet.SwapConfirmations(txHash, vout, contract, startTime)
+}
+
+// GetBlock gets the block from the full node, falling back to the SPV
+// wallet's peers.
+func (w *hybridWallet) GetBlock(blockHash chainhash.Hash) (*wire.MsgBlock, error) {
+	if w.nodeAvailable() {
+		blk, err := w.node.GetBlock(blockHash)
+		if err == nil || !w.nodeFailed(err) {
+			return blk, err
+		}
+	}
+	return w.spvWallet.GetBlock(blockHash)
+}
+
+// GetBlockHeader gets the header from the full node, falling back to the SPV
+// wallet.
+func (w *hybridWallet) GetBlockHeader(blockHash *chainhash.Hash) (*BlockHeader, bool, error) {
+	if w.nodeAvailable() {
+		hdr, mainchain, err := w.node.GetBlockHeader(blockHash)
+		if err == nil || !w.nodeFailed(err) {
+			return hdr, mainchain, err
+		}
+	}
+	return w.spvWallet.GetBlockHeader(blockHash)
+}
@@ -0,0 +1,71 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+func TestHybridWallet(t *testing.T) {
+	wallet, spvData, shutdown := tNewWallet(true, walletTypeSPV)
+	defer shutdown()
+	spvw := wallet.node.(*spvWallet)
+
+	nodeData := newTestData()
+	core := &rpcCore{
+		cloneParams: &BTCCloneCFG{},
+		log:         tLogger,
+	}
+	core.requesterV.Store(RawRequester(&tRawRequester{nodeData}))
+	node := newRPCClient(core)
+	node.ctx = tCtx
+	hw := &hybridWallet{
+		spvWallet: spvw,
+		node:      node,
+		log:       tLogger,
+	}
+
+	// The SPV wallet fails if it is used.
+	spvData.getTransactionErr = tErr
+	_, _, pkScript, _, _, _, _ := makeSwapContract(true, time.Hour*12)
+	txHash := new(chainhash.Hash)
+	checkNode := func(wantNode bool) {
+		t.Helper()
+		_, confs, err := hw.GetTxOut(txHash, 0, pkScript, time.Now())
+		if wantNode {
+			if err != nil || confs != 5 {
+				t.Fatalf("full node not used: confs = %d, err = %v", confs, err)
+			}
+		} else if err == nil {
+			t.Fatalf("SPV wallet not used")
+		}
+	}
+
+	nodeData.txOutRes = newTxOutResult(pkScript, 1e8, 5)
+	checkNode(true)
+
+	// An error from the node is returned without falling back.
+	nodeData.txOutErr = &btcjson.RPCError{Code: btcjson.ErrRPCInvalidParameter}
+	_, _, err := hw.GetTxOut(txHash, 0, pkScript, time.Now())
+	var rpcErr *btcjson.RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected node error, got %v", err)
+	}
+	if !hw.nodeAvailable() {
+		t.Fatalf("node marked unavailable after node error")
+	}
+
+	// A connection error falls back to SPV and the node isn't tried again
+	// until the retry interval passes.
+	nodeData.txOutErr = tErr
+	checkNode(false)
+	nodeData.txOutErr = nil
+	checkNode(false)
+	hw.downUntil = time.Now().Add(-time.Second)
+	checkNode(true)
+}
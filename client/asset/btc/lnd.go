@@ -0,0 +1,319 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+var errNoLightning = errors.New("no lightning node configured")
+
+// LightningConfigOpts are the settings used to connect to an LND node's REST
+// interface, which enables sending and receiving over the Lightning Network.
+var LightningConfigOpts = []*asset.ConfigOption{
+	{
+		Key:         "lndresthost",
+		DisplayName: "LND REST address",
+		Description: "Optional. The address of an LND node's REST interface, " +
+			"e.g. localhost:8080. If set, funds can be sent and received " +
+			"over the Lightning Network.",
+	},
+	{
+		Key:         "lndmacaroonpath",
+		DisplayName: "LND macaroon path",
+		Description: "The path to the LND admin.macaroon file.",
+	},
+	{
+		Key:         "lndtlscertpath",
+		DisplayName: "LND TLS certificate path",
+		Description: "The path to the LND tls.cert file. If not set, the " +
+			"system certificate pool is used.",
+	},
+}
+
+// lndClient is a client for the REST interface of an LND node.
+type lndClient struct {
+	url      string
+	macaroon string // hex
+	http     *http.Client
+}
+
+// newLNDClient creates a client for the LND REST interface at host. The
+// macaroon file is required. If certPath is empty, the system certificate
+// pool is used to verify the node's TLS certificate.
+func newLNDClient(host, macaroonPath, certPath string) (*lndClient, error) {
+	if macaroonPath == "" {
+		return nil, errors.New("no lnd macaroon path provided")
+	}
+	mac, err := os.ReadFile(macaroonPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading lnd macaroon: %w", err)
+	}
+
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if certPath != "" {
+		pem, err := os.ReadFile(certPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading lnd tls certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("invalid lnd tls certificate")
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	url := host
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		url = "https://" + url
+	}
+
+	return &lndClient{
+		url:      strings.TrimSuffix(url, "/"),
+		macaroon: hex.EncodeToString(mac),
+		http: &http.Client{
+			Timeout:   time.Minute,
+			Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		},
+	}, nil
+}
+
+func (c *lndClient) request(ctx context.Context, method, path string, body, resp any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Grpc-Metadata-macaroon", c.macaroon)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		var lndErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(b, &lndErr) == nil && lndErr.Message != "" {
+			return fmt.Errorf("lnd error: %s", lndErr.Message)
+		}
+		return fmt.Errorf("lnd error: %s", res.Status)
+	}
+	return json.Unmarshal(b, resp)
+}
+
+// LND encodes 64-bit integers as strings in JSON.
+func parseLNDInt(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// channelBalance is the amount that can be sent from the node's channels.
+func (c *lndClient) channelBalance(ctx context.Context) (uint64, error) {
+	var resp struct {
+		LocalBalance struct {
+			Sat string `json:"sat"`
+		} `json:"local_balance"`
+	}
+	if err := c.request(ctx, http.MethodGet, "/v1/balance/channels", nil, &resp); err != nil {
+		return 0, err
+	}
+	return parseLNDInt(resp.LocalBalance.Sat)
+}
+
+func (c *lndClient) addInvoice(ctx context.Context, amt uint64, memo string) (*asset.LightningInvoice, error) {
+	req := map[string]string{
+		"value": strconv.FormatUint(amt, 10),
+		"memo":  memo,
+	}
+	var resp struct {
+		RHash          string `json:"r_hash"` // base64
+		PaymentRequest string `json:"payment_request"`
+	}
+	if err := c.request(ctx, http.MethodPost, "/v1/invoices", req, &resp); err != nil {
+		return nil, err
+	}
+	hash, err := base64.StdEncoding.DecodeString(resp.RHash)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding payment hash: %w", err)
+	}
+	return &asset.LightningInvoice{
+		Invoice:     resp.PaymentRequest,
+		PaymentHash: hex.EncodeToString(hash),
+		Amount:      amt,
+	}, nil
+}
+
+func (c *lndClient) lookupInvoice(ctx context.Context, paymentHash string) (settled bool, amtPaid uint64, err error) {
+	if _, err := hex.DecodeString(paymentHash); err != nil {
+		return false, 0, fmt.Errorf("invalid payment hash: %w", err)
+	}
+	var resp struct {
+		State      string `json:"state"`
+		AmtPaidSat string `json:"amt_paid_sat"`
+	}
+	if err := c.request(ctx, http.MethodGet, "/v1/invoice/"+paymentHash, nil, &resp); err != nil {
+		return false, 0, err
+	}
+	amtPaid, err = parseLNDInt(resp.AmtPaidSat)
+	if err != nil {
+		return false, 0, err
+	}
+	return resp.State == "SETTLED", amtPaid, nil
+}
+
+func (c *lndClient) payInvoice(ctx context.Context, invoice string, maxFee uint64) (*asset.LightningPayment, error) {
+	req := map[string]any{
+		"payment_request": invoice,
+		"fee_limit": map[string]string{
+			"fixed": strconv.FormatUint(maxFee, 10),
+		},
+	}
+	var resp struct {
+		PaymentError string `json:"payment_error"`
+		PaymentHash  string `json:"payment_hash"` // base64
+		PaymentRoute struct {
+			TotalAmt  string `json:"total_amt"`
+			TotalFees string `json:"total_fees"`
+		} `json:"payment_route"`
+	}
+	if err := c.request(ctx, http.MethodPost, "/v1/channels/transactions", req, &resp); err != nil {
+		return nil, err
+	}
+	if resp.PaymentError != "" {
+		return nil, fmt.Errorf("payment failed: %s", resp.PaymentError)
+	}
+	hash, err := base64.StdEncoding.DecodeString(resp.PaymentHash)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding payment hash: %w", err)
+	}
+	totalAmt, err := parseLNDInt(resp.PaymentRoute.TotalAmt)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := parseLNDInt(resp.PaymentRoute.TotalFees)
+	if err != nil {
+		return nil, err
+	}
+	return &asset.LightningPayment{
+		PaymentHash: hex.EncodeToString(hash),
+		Amount:      totalAmt - fees,
+		Fees:        fees,
+	}, nil
+}
+
+// lightningWallet sends and receives over the Lightning Network with the LND
+// node configured for a BTC wallet.
+type lightningWallet struct {
+	base *baseWallet
+}
+
+// newLightningWallet returns a lightningWallet for the wallet, or nil if no
+// LND node is configured.
+func newLightningWallet(w *baseWallet) *lightningWallet {
+	if w.cfgV.Load().(*baseWalletConfig).lnd == nil {
+		return nil
+	}
+	return &lightningWallet{base: w}
+}
+
+// ExchangeWalletLightning is a BTC full node wallet with an LND node for
+// Lightning transfers.
+type ExchangeWalletLightning struct {
+	*ExchangeWalletAccelerator
+	*lightningWallet
+}
+
+// ExchangeWalletSPVLightning is a BTC SPV wallet with an LND node for
+// Lightning transfers.
+type ExchangeWalletSPVLightning struct {
+	*ExchangeWalletSPV
+	*lightningWallet
+}
+
+var _ asset.LightningTransferer = (*ExchangeWalletLightning)(nil)
+var _ asset.LightningTransferer = (*ExchangeWalletSPVLightning)(nil)
+
+func (w *lightningWallet) lndClient() (*lndClient, error) {
+	// The LND node may be reconfigured, but it can't be removed without a
+	// restart. See (*baseWallet).Reconfigure.
+	lnd := w.base.cfgV.Load().(*baseWalletConfig).lnd
+	if lnd == nil {
+		return nil, errNoLightning
+	}
+	return lnd, nil
+}
+
+// LightningBalance returns the amount that can be sent over the Lightning
+// Network. Part of the asset.LightningTransferer interface.
+func (w *lightningWallet) LightningBalance(ctx context.Context) (uint64, error) {
+	lnd, err := w.lndClient()
+	if err != nil {
+		return 0, err
+	}
+	return lnd.channelBalance(ctx)
+}
+
+// LightningInvoice creates an invoice to receive amt over the Lightning
+// Network. Part of the asset.LightningTransferer interface.
+func (w *lightningWallet) LightningInvoice(ctx context.Context, amt uint64, memo string) (*asset.LightningInvoice, error) {
+	lnd, err := w.lndClient()
+	if err != nil {
+		return nil, err
+	}
+	return lnd.addInvoice(ctx, amt, memo)
+}
+
+// LightningInvoiceStatus checks whether an invoice created by LightningInvoice
+// has been paid. Part of the asset.LightningTransferer interface.
+func (w *lightningWallet) LightningInvoiceStatus(ctx context.Context, paymentHash string) (settled bool, amtPaid uint64, err error) {
+	lnd, err := w.lndClient()
+	if err != nil {
+		return false, 0, err
+	}
+	return lnd.lookupInvoice(ctx, paymentHash)
+}
+
+// PayLightningInvoice pays an invoice over the Lightning Network, paying at
+// most maxFee in routing fees. Part of the asset.LightningTransferer
+// interface.
+func (w *lightningWallet) PayLightningInvoice(ctx context.Context, invoice string, maxFee uint64) (*asset.LightningPayment, error) {
+	lnd, err := w.lndClient()
+	if err != nil {
+		return nil, err
+	}
+	return lnd.payInvoice(ctx, invoice, maxFee)
+}
@@ -0,0 +1,178 @@
+//go:build !spvlive && !harness
+
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestLNDClient(t *testing.T) {
+	hash := []byte{0x01, 0x02, 0x03}
+	const macaroon = "abcd"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/balance/channels", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Grpc-Metadata-macaroon") != macaroon {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"message": "bad macaroon"})
+			return
+		}
+		w.Write([]byte(`{"local_balance":{"sat":"150000"}}`))
+	})
+	mux.HandleFunc("POST /v1/invoices", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["value"] != "5000" {
+			t.Errorf("wrong invoice value %q", req["value"])
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"r_hash":          base64.StdEncoding.EncodeToString(hash),
+			"payment_request": "lnbc1invoice",
+		})
+	})
+	mux.HandleFunc("GET /v1/invoice/{hash}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"state":"SETTLED","amt_paid_sat":"5000"}`))
+	})
+	mux.HandleFunc("POST /v1/channels/transactions", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			PaymentRequest string            `json:"payment_request"`
+			FeeLimit       map[string]string `json:"fee_limit"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.PaymentRequest == "bad" {
+			w.Write([]byte(`{"payment_error":"no route"}`))
+			return
+		}
+		if req.FeeLimit["fixed"] != "10" {
+			t.Errorf("wrong fee limit %v", req.FeeLimit)
+		}
+		w.Write([]byte(`{"payment_hash":"` + base64.StdEncoding.EncodeToString(hash) +
+			`","payment_route":{"total_amt":"5003","total_fees":"3"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := &lndClient{url: srv.URL, macaroon: macaroon, http: srv.Client()}
+	ctx := context.Background()
+
+	bal, err := c.channelBalance(ctx)
+	if err != nil {
+		t.Fatalf("channelBalance error: %v", err)
+	}
+	if bal != 150000 {
+		t.Fatalf("wrong balance %d", bal)
+	}
+
+	inv, err := c.addInvoice(ctx, 5000, "test")
+	if err != nil {
+		t.Fatalf("addInvoice error: %v", err)
+	}
+	if inv.Invoice != "lnbc1invoice" || inv.PaymentHash != hex.EncodeToString(hash) {
+		t.Fatalf("wrong invoice %+v", inv)
+	}
+
+	settled, paid, err := c.lookupInvoice(ctx, inv.PaymentHash)
+	if err != nil {
+		t.Fatalf("lookupInvoice error: %v", err)
+	}
+	if !settled || paid != 5000 {
+		t.Fatalf("wrong invoice status %t, %d", settled, paid)
+	}
+
+	payment, err := c.payInvoice(ctx, "lnbc1invoice", 10)
+	if err != nil {
+		t.Fatalf("payInvoice error: %v", err)
+	}
+	if payment.Amount != 5000 || payment.Fees != 3 {
+		t.Fatalf("wrong payment %+v", payment)
+	}
+
+	if _, err := c.payInvoice(ctx, "bad", 10); err == nil {
+		t.Fatalf("no error for failed payment")
+	}
+
+	c.macaroon = "wrong"
+	if _, err := c.channelBalance(ctx); err == nil {
+		t.Fatalf("no error for bad macaroon")
+	}
+}
+
+func TestLightningWalletTypes(t *testing.T) {
+	macPath := filepath.Join(t.TempDir(), "admin.macaroon")
+	if err := os.WriteFile(macPath, []byte{0xab, 0xcd}, 0600); err != nil {
+		t.Fatalf("error writing macaroon: %v", err)
+	}
+
+	newWallet := func(walletType string, lnd bool) asset.Wallet {
+		t.Helper()
+		settings := map[string]string{
+			"rpcuser":     "user",
+			"rpcpassword": "pass",
+		}
+		if lnd {
+			settings["lndresthost"] = "localhost:8080"
+			settings["lndmacaroonpath"] = macPath
+		}
+		w, err := NewWallet(&asset.WalletConfig{
+			Type:     walletType,
+			Settings: settings,
+			DataDir:  t.TempDir(),
+			Emit:     asset.NewWalletEmitter(make(chan asset.WalletNotification, 1), BipID, tLogger),
+		}, tLogger, dex.Regtest)
+		if err != nil {
+			t.Fatalf("NewWallet error: %v", err)
+		}
+		return w
+	}
+
+	for _, walletType := range []string{walletTypeRPC, walletTypeSPV} {
+		if _, is := newWallet(walletType, false).(asset.LightningTransferer); is {
+			t.Fatalf("%s wallet without lnd is a LightningTransferer", walletType)
+		}
+		w := newWallet(walletType, true)
+		if _, is := w.(asset.LightningTransferer); !is {
+			t.Fatalf("%s wallet with lnd is not a LightningTransferer", walletType)
+		}
+		// Lightning wallets keep the other optional interfaces.
+		if _, is := w.(asset.Accelerator); !is {
+			t.Fatalf("%s lightning wallet is not an Accelerator", walletType)
+		}
+	}
+
+	// BTC clones don't support lightning.
+	cloneWallet, err := BTCCloneWallet(&BTCCloneCFG{
+		WalletCFG: &asset.WalletConfig{
+			Type:     walletTypeRPC,
+			Settings: map[string]string{"rpcuser": "user", "rpcpassword": "pass"},
+			DataDir:  t.TempDir(),
+		},
+		Symbol:      "btc",
+		Logger:      tLogger,
+		ChainParams: &chaincfg.RegressionNetParams,
+		Network:     dex.Regtest,
+		WalletInfo:  WalletInfo,
+		Segwit:      true,
+	})
+	if err != nil {
+		t.Fatalf("BTCCloneWallet error: %v", err)
+	}
+	var cloneW asset.Wallet = &ExchangeWalletAccelerator{cloneWallet}
+	if _, is := cloneW.(asset.LightningTransferer); is {
+		t.Fatalf("clone wallet is a LightningTransferer")
+	}
+}
@@ -0,0 +1,96 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/config"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+const (
+	walletTypeMultisig = "multisigRPC"
+	// maxMultisigKeys is the maximum number of keys of a standard
+	// CHECKMULTISIG script.
+	maxMultisigKeys = 15
+)
+
+// errMultisigTrading is returned for trading with a multisig wallet. Swap
+// contracts are single-sig, and their refunds must pay to a single key.
+var errMultisigTrading = errors.New("multisig wallets cannot trade or post bonds")
+
+// MultisigConfigOpts are the settings of a multisig wallet, in addition to the
+// RPC and common settings.
+var MultisigConfigOpts = []*asset.ConfigOption{
+	{
+		Key:         "multisigxpubs",
+		DisplayName: "Cosigner extended public keys",
+		Description: "The comma-separated extended public keys of the " +
+			"cosigners' P2WSH multisig (BIP48) accounts. Any order.",
+		Required: true,
+	},
+	{
+		Key:         "multisigthreshold",
+		DisplayName: "Required signatures",
+		Description: "The number of cosigners that must sign a transaction.",
+		Required:    true,
+	},
+	birthdayOpt,
+}
+
+// MultisigWallet creates an m-of-n multisig wallet that watches the cosigners'
+// extended public keys in a watch-only descriptor wallet of a full node. The
+// wallet can receive, and sends are presented as PSBTs. Each cosigner signs
+// the PSBT and submits it, and the transaction is broadcast once it has the
+// threshold number of signatures. The wallet can't trade or post bonds.
+func MultisigWallet(cfg *BTCCloneCFG) (*ExchangeWalletMultisig, error) {
+	var woCfg watchOnlyConfig
+	if err := config.Unmapify(cfg.WalletCFG.Settings, &woCfg); err != nil {
+		return nil, fmt.Errorf("error parsing multisig wallet settings: %w", err)
+	}
+	if !woCfg.isMultisig() {
+		return nil, errors.New("no cosigner keys")
+	}
+	iw, err := newWatchOnlyRPCWallet(cfg, &woCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ExchangeWalletMultisig{iw}, nil
+}
+
+// ExchangeWalletMultisig is a multisig wallet for holding funds. Sends are
+// signed by the cosigners. Trading is not supported.
+type ExchangeWalletMultisig struct {
+	*intermediaryWallet
+}
+
+// FundOrder is not supported by multisig wallets.
+func (w *ExchangeWalletMultisig) FundOrder(*asset.Order) (asset.Coins, []dex.Bytes, uint64, error) {
+	return nil, nil, 0, errMultisigTrading
+}
+
+// FundMultiOrder is not supported by multisig wallets.
+func (w *ExchangeWalletMultisig) FundMultiOrder(*asset.MultiOrder, uint64) ([]asset.Coins, [][]dex.Bytes, uint64, error) {
+	return nil, nil, 0, errMultisigTrading
+}
+
+// MaxOrder is not supported by multisig wallets.
+func (w *ExchangeWalletMultisig) MaxOrder(*asset.MaxOrderForm) (*asset.SwapEstimate, error) {
+	return nil, errMultisigTrading
+}
+
+// PreSwap is not supported by multisig wallets.
+func (w *ExchangeWalletMultisig) PreSwap(*asset.PreSwapForm) (*asset.PreSwap, error) {
+	return nil, errMultisigTrading
+}
+
+// MakeBondTx is not supported by multisig wallets.
+func (w *ExchangeWalletMultisig) MakeBondTx(uint16, uint64, uint64, time.Time, *secp256k1.PrivateKey, []byte) (*asset.Bond, func(), error) {
+	return nil, nil, errMultisigTrading
+}
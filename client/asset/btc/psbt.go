@@ -0,0 +1,511 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// externalSigningOpt configures an RPC wallet to sign transactions with an
+// external signer using PSBTs.
+var externalSigningOpt = &asset.ConfigOption{
+	Key:         "externalsigning",
+	DisplayName: "External signing (PSBT)",
+	Description: "Sign transactions with an external device. Use with a " +
+		"watch-only bitcoind wallet that imports the device's descriptors. " +
+		"Transactions are presented as PSBTs, and matches will not progress " +
+		"until they are signed, so you must be present to sign before the " +
+		"swap deadlines. Fidelity bonds are not supported.",
+	IsBoolean: true,
+}
+
+// inputsKey identifies a transaction by the outpoints it spends. Transactions
+// are rebuilt when an action is retried, and their outputs may change, e.g.
+// if a new change address is used, but the inputs are the same.
+func inputsKey(tx *wire.MsgTx) string {
+	pts := make([]string, 0, len(tx.TxIn))
+	for _, txIn := range tx.TxIn {
+		pts = append(pts, txIn.PreviousOutPoint.String())
+	}
+	sort.Strings(pts)
+	return strings.Join(pts, ",")
+}
+
+type pendingPSBT struct {
+	req    *asset.PSBTRequest
+	packet *psbt.Packet
+}
+
+// psbtSigner tracks the transactions that have been sent to, and received
+// from, an external signer.
+type psbtSigner struct {
+	log  dex.Logger
+	emit *asset.WalletEmitter
+
+	mtx sync.Mutex
+	// drafts are the unsigned transactions that have been passed to SignTx,
+	// by inputs key. A draft is requested from the external signer when the
+	// wallet attempts to broadcast it.
+	drafts map[string]*wire.MsgTx
+	// pending are the requests that have not been signed, by inputs key.
+	pending map[string]*pendingPSBT
+	// signed are the signed PSBTs that have not been broadcast, by inputs
+	// key.
+	signed map[string]*psbt.Packet
+	// refundAddrs are the revocation addresses of swap contracts that have
+	// not been broadcast, by inputs key.
+	refundAddrs map[string][]btcutil.Address
+	// update, if set, adds the wallet's information about the inputs and
+	// outputs to a new PSBT, e.g. derivation paths, before it is requested.
+	update func(b64 string) (string, error)
+	// multisig is set for wallets whose inputs are signed by several
+	// cosigners. Partially signed PSBTs are combined until they have enough
+	// signatures.
+	multisig bool
+}
+
+func newPSBTSigner(log dex.Logger, emit *asset.WalletEmitter) *psbtSigner {
+	return &psbtSigner{
+		log:         log,
+		emit:        emit,
+		drafts:      make(map[string]*wire.MsgTx),
+		pending:     make(map[string]*pendingPSBT),
+		signed:      make(map[string]*psbt.Packet),
+		refundAddrs: make(map[string][]btcutil.Address),
+	}
+}
+
+// request creates a PSBT request for the unsigned transaction, unless one is
+// already pending for the same inputs. The pending request is not replaced
+// when the transaction is rebuilt with e.g. a new fee rate, since the outputs
+// of the signed transaction will be used in any case. prevOuts are the outputs
+// spent by each input. witnessScripts may be nil, or the scripts for P2WSH
+// inputs.
+func (s *psbtSigner) request(tx *wire.MsgTx, prevOuts []*wire.TxOut, witnessScripts [][]byte) error {
+	key := inputsKey(tx)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, found := s.pending[key]; found {
+		return nil
+	}
+	unsigned := tx.Copy()
+	for _, txIn := range unsigned.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+	packet, err := psbt.NewFromUnsignedTx(unsigned)
+	if err != nil {
+		return fmt.Errorf("error creating psbt: %w", err)
+	}
+	for i := range packet.Inputs {
+		packet.Inputs[i].WitnessUtxo = prevOuts[i]
+		packet.Inputs[i].SighashType = txscript.SigHashAll
+		if witnessScripts != nil {
+			packet.Inputs[i].WitnessScript = witnessScripts[i]
+		}
+	}
+	b64, err := packet.B64Encode()
+	if err != nil {
+		return fmt.Errorf("error encoding psbt: %w", err)
+	}
+	if s.update != nil {
+		if updated, err := s.update(b64); err != nil {
+			s.log.Warnf("Error adding wallet information to psbt: %v", err)
+		} else if p, err := psbt.NewFromRawBytes(strings.NewReader(updated), true); err != nil {
+			s.log.Warnf("Error decoding updated psbt: %v", err)
+		} else {
+			packet, b64 = p, updated
+		}
+	}
+	req := &asset.PSBTRequest{
+		ID:    unsigned.TxHash().String(),
+		PSBT:  b64,
+		Stamp: uint64(time.Now().UnixMilli()),
+	}
+	s.pending[key] = &pendingPSBT{req: req, packet: packet}
+	s.log.Infof("Transaction %s is waiting for an external signature", req.ID)
+	s.emit.ActionRequired(req.ID, asset.ActionIDSignPSBT, req)
+	return nil
+}
+
+// pendingRequests returns the requests that have not been signed.
+func (s *psbtSigner) pendingRequests() []*asset.PSBTRequest {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	reqs := make([]*asset.PSBTRequest, 0, len(s.pending))
+	for _, p := range s.pending {
+		reqs = append(reqs, p.req)
+	}
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Stamp < reqs[j].Stamp })
+	return reqs
+}
+
+// submit accepts a signed PSBT for a pending request. The unsigned
+// transaction must not have been modified.
+func (s *psbtSigner) submit(id, b64 string) error {
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(b64), true)
+	if err != nil {
+		return fmt.Errorf("error decoding psbt: %w", err)
+	}
+	key := inputsKey(packet.UnsignedTx)
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	p, found := s.pending[key]
+	if !found || p.req.ID != id {
+		return fmt.Errorf("no pending request with ID %s", id)
+	}
+	if packet.UnsignedTx.TxHash() != p.packet.UnsignedTx.TxHash() {
+		return errors.New("the signed transaction does not match the request")
+	}
+	if s.multisig {
+		return s.submitCosignerPSBT(key, p, packet)
+	}
+	for i, in := range packet.Inputs {
+		if len(in.PartialSigs) == 0 && len(in.FinalScriptWitness) == 0 && len(in.FinalScriptSig) == 0 {
+			return fmt.Errorf("input %d is not signed", i)
+		}
+	}
+	delete(s.pending, key)
+	s.signed[key] = packet
+	s.emit.ActionResolved(id)
+	return nil
+}
+
+// submitCosignerPSBT adds the signatures of a cosigner's PSBT to the pending
+// request. The request is signed once every input has the threshold number of
+// signatures. Otherwise, the request is updated with the signatures so far,
+// for the next cosigner. The mutex must be held.
+func (s *psbtSigner) submitCosignerPSBT(key string, p *pendingPSBT, packet *psbt.Packet) error {
+	var added int
+	for i := range packet.Inputs {
+		in, pendingIn := &packet.Inputs[i], &p.packet.Inputs[i]
+		if len(in.FinalScriptWitness) > 0 && len(pendingIn.FinalScriptWitness) == 0 {
+			pendingIn.FinalScriptWitness = in.FinalScriptWitness
+			pendingIn.FinalScriptSig = in.FinalScriptSig
+			added++
+			continue
+		}
+		if pendingIn.WitnessScript == nil {
+			pendingIn.WitnessScript = in.WitnessScript
+		}
+	next:
+		for _, sig := range in.PartialSigs {
+			for _, pendingSig := range pendingIn.PartialSigs {
+				if bytes.Equal(sig.PubKey, pendingSig.PubKey) {
+					continue next
+				}
+			}
+			pendingIn.PartialSigs = append(pendingIn.PartialSigs, sig)
+			added++
+		}
+	}
+	if added == 0 {
+		return errors.New("the psbt has no new signatures")
+	}
+	b64, err := p.packet.B64Encode()
+	if err != nil {
+		return fmt.Errorf("error encoding psbt: %w", err)
+	}
+	// Finalize a copy, so that the pending request keeps any extra
+	// signatures if it fails.
+	final, err := psbt.NewFromRawBytes(strings.NewReader(b64), true)
+	if err != nil {
+		return fmt.Errorf("error decoding psbt: %w", err)
+	}
+	if err := finalizeMultisig(final); err != nil {
+		s.log.Infof("Transaction %s needs more cosigner signatures: %v", p.req.ID, err)
+		p.req.PSBT = b64
+		s.emit.ActionRequired(p.req.ID, asset.ActionIDSignPSBT, p.req)
+		return nil
+	}
+	delete(s.pending, key)
+	s.signed[key] = final
+	s.emit.ActionResolved(p.req.ID)
+	return nil
+}
+
+// finalizeMultisig finalizes the inputs of a PSBT that spends multisig
+// outputs. Signatures beyond the threshold are dropped, since the finalizer
+// requires exactly the threshold number.
+func finalizeMultisig(packet *psbt.Packet) error {
+	for i := range packet.Inputs {
+		in := &packet.Inputs[i]
+		if len(in.FinalScriptWitness) > 0 {
+			continue
+		}
+		if in.WitnessScript == nil {
+			return fmt.Errorf("input %d has no witness script", i)
+		}
+		_, threshold, err := txscript.CalcMultiSigStats(in.WitnessScript)
+		if err != nil {
+			return fmt.Errorf("input %d is not multisig: %w", i, err)
+		}
+		if len(in.PartialSigs) < threshold {
+			return fmt.Errorf("input %d has %d of %d signatures", i, len(in.PartialSigs), threshold)
+		}
+		in.PartialSigs = in.PartialSigs[:threshold]
+	}
+	return psbt.MaybeFinalizeAll(packet)
+}
+
+func (s *psbtSigner) signedPacket(key string) *psbt.Packet {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.signed[key]
+}
+
+func (s *psbtSigner) swapRefundAddrs(key string) []btcutil.Address {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.refundAddrs[key]
+}
+
+func (s *psbtSigner) setSwapRefundAddrs(key string, addrs []btcutil.Address) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.refundAddrs[key] = addrs
+}
+
+// broadcasted removes the records for a transaction that has been broadcast.
+func (s *psbtSigner) broadcasted(key string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.signed, key)
+	delete(s.drafts, key)
+	delete(s.refundAddrs, key)
+}
+
+// discard removes a signed PSBT that can no longer be used.
+func (s *psbtSigner) discard(key string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.signed, key)
+}
+
+// sameContractOutputs checks that a signed swap transaction has the expected
+// contract outputs, followed by at most one change output.
+func sameContractOutputs(contractOuts, signedOuts []*wire.TxOut) bool {
+	if len(signedOuts) < len(contractOuts) || len(signedOuts) > len(contractOuts)+1 {
+		return false
+	}
+	for i, txOut := range contractOuts {
+		if txOut.Value != signedOuts[i].Value || !bytes.Equal(txOut.PkScript, signedOuts[i].PkScript) {
+			return false
+		}
+	}
+	return true
+}
+
+// applySignedOutputs replaces the outputs of the transaction with those of a
+// signed PSBT spending the same inputs, since the signatures commit to the
+// outputs. Returns true if there is a signed PSBT.
+func (s *psbtSigner) applySignedOutputs(tx *wire.MsgTx) bool {
+	packet := s.signedPacket(inputsKey(tx))
+	if packet == nil {
+		return false
+	}
+	tx.TxOut = make([]*wire.TxOut, 0, len(packet.UnsignedTx.TxOut))
+	for _, txOut := range packet.UnsignedTx.TxOut {
+		tx.TxOut = append(tx.TxOut, wire.NewTxOut(txOut.Value, txOut.PkScript))
+	}
+	tx.LockTime = packet.UnsignedTx.LockTime
+	return true
+}
+
+// contractSigs returns the signatures and public keys for transaction inputs
+// that spend P2WSH swap contracts. If the transaction has not been signed, a
+// PSBT is requested and asset.ErrAwaitingSignature is returned.
+func (s *psbtSigner) contractSigs(tx *wire.MsgTx, contracts [][]byte, values []int64) (sigs, pubKeys [][]byte, err error) {
+	packet := s.signedPacket(inputsKey(tx))
+	if packet == nil {
+		prevOuts := make([]*wire.TxOut, len(contracts))
+		for i, contract := range contracts {
+			pkScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).
+				AddData(hashContract(true, contract)).Script()
+			if err != nil {
+				return nil, nil, err
+			}
+			prevOuts[i] = wire.NewTxOut(values[i], pkScript)
+		}
+		if err := s.request(tx, prevOuts, contracts); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, asset.ErrAwaitingSignature
+	}
+	for i, in := range packet.Inputs {
+		if len(in.PartialSigs) == 0 {
+			return nil, nil, fmt.Errorf("no signature for contract input %d", i)
+		}
+		sigs = append(sigs, in.PartialSigs[0].Signature)
+		pubKeys = append(pubKeys, in.PartialSigs[0].PubKey)
+	}
+	return sigs, pubKeys, nil
+}
+
+// psbtNode wraps a Wallet that does not have the private keys for its
+// addresses. Instead of signing transactions, SignTx returns a copy with
+// placeholder signatures of the maximum size, which is sufficient for fee
+// calculations. When the transaction is broadcast, it is requested from the
+// external signer, and asset.ErrAwaitingSignature is returned. Once the
+// signed PSBT has been submitted, SignTx returns the signed transaction for
+// the same inputs.
+type psbtNode struct {
+	Wallet
+	signer        *psbtSigner
+	segwit        bool
+	deserializeTx func([]byte) (*wire.MsgTx, error)
+	// multisig is set if the wallet's outputs are P2WSH multisig.
+	multisig *multisigParams
+}
+
+// multisigParams describes a wallet's m-of-n multisig scripts.
+type multisigParams struct {
+	threshold int // m
+	keys      int // n
+}
+
+// placeholderWitness is a witness of the maximum size for spending the
+// multisig script: an empty item for the CHECKMULTISIG bug, the signatures,
+// and the witness script.
+func (p *multisigParams) placeholderWitness() wire.TxWitness {
+	witness := make(wire.TxWitness, 0, p.threshold+2)
+	witness = append(witness, nil)
+	for i := 0; i < p.threshold; i++ {
+		witness = append(witness, make([]byte, 73))
+	}
+	// OP_m <n pushes of 33-byte keys> OP_n OP_CHECKMULTISIG
+	return append(witness, make([]byte, 3+34*p.keys))
+}
+
+var _ Wallet = (*psbtNode)(nil)
+
+// SignTx returns the signed transaction if a signed PSBT spending the same
+// inputs has been submitted. Otherwise, a copy of the transaction with
+// placeholder signatures is returned.
+func (n *psbtNode) SignTx(tx *wire.MsgTx) (*wire.MsgTx, error) {
+	key := inputsKey(tx)
+	if packet := n.signer.signedPacket(key); packet != nil {
+		if err := psbt.MaybeFinalizeAll(packet); err != nil {
+			return nil, fmt.Errorf("error finalizing psbt: %w", err)
+		}
+		return psbt.Extract(packet)
+	}
+	n.signer.mtx.Lock()
+	n.signer.drafts[key] = tx.Copy()
+	n.signer.mtx.Unlock()
+
+	draft := tx.Copy()
+	for _, txIn := range draft.TxIn {
+		if n.multisig != nil {
+			txIn.Witness = n.multisig.placeholderWitness()
+		} else if n.segwit {
+			txIn.Witness = wire.TxWitness{make([]byte, 73), make([]byte, 33)}
+		} else {
+			txIn.SignatureScript = make([]byte, dexbtc.RedeemP2PKHSigScriptSize)
+		}
+	}
+	return draft, nil
+}
+
+// SendRawTransaction requests a signature for a transaction that was
+// returned with placeholder signatures from SignTx.
+func (n *psbtNode) SendRawTransaction(tx *wire.MsgTx) (*chainhash.Hash, error) {
+	key := inputsKey(tx)
+	n.signer.mtx.Lock()
+	draft, isDraft := n.signer.drafts[key]
+	_, signed := n.signer.signed[key]
+	n.signer.mtx.Unlock()
+	if !isDraft || signed {
+		txHash, err := n.Wallet.SendRawTransaction(tx)
+		if err == nil {
+			n.signer.broadcasted(key)
+		}
+		return txHash, err
+	}
+
+	prevOuts := make([]*wire.TxOut, 0, len(draft.TxIn))
+	for _, txIn := range draft.TxIn {
+		pt := txIn.PreviousOutPoint
+		gtr, err := n.GetWalletTransaction(&pt.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("error finding input %s: %w", pt, err)
+		}
+		prevTx, err := n.deserializeTx(gtr.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding input transaction %s: %w", pt.Hash, err)
+		}
+		if int(pt.Index) >= len(prevTx.TxOut) {
+			return nil, fmt.Errorf("input %s not found", pt)
+		}
+		prevOuts = append(prevOuts, prevTx.TxOut[pt.Index])
+	}
+	if err := n.signer.request(draft, prevOuts, nil); err != nil {
+		return nil, err
+	}
+	return nil, asset.ErrAwaitingSignature
+}
+
+// watchContract passes through to the wrapped wallet, if it is a
+// contractWatcher.
+func (n *psbtNode) watchContract(addr string, since time.Time) error {
+	if watcher, is := n.Wallet.(contractWatcher); is {
+		return watcher.watchContract(addr, since)
+	}
+	return nil
+}
+
+// rescanBlockchain passes through to the wrapped wallet, if it is a
+// blockchainRescanner.
+func (n *psbtNode) rescanBlockchain(startHeight int64) error {
+	if r, is := n.Wallet.(blockchainRescanner); is {
+		return r.rescanBlockchain(startHeight)
+	}
+	return errors.New("wallet does not support rescanning")
+}
+
+// rescanProgress passes through to the wrapped wallet, if it is a
+// blockchainRescanner.
+func (n *psbtNode) rescanProgress() (float64, bool, error) {
+	if r, is := n.Wallet.(blockchainRescanner); is {
+		return r.rescanProgress()
+	}
+	return 0, false, nil
+}
+
+// PendingPSBTs returns the transactions that are waiting to be signed by the
+// external signer. Part of the asset.ExternalSigner interface.
+func (btc *baseWallet) PendingPSBTs() []*asset.PSBTRequest {
+	if btc.signer == nil {
+		return nil
+	}
+	return btc.signer.pendingRequests()
+}
+
+// SubmitSignedPSBT submits a PSBT signed by the external signer. Part of the
+// asset.ExternalSigner interface.
+func (btc *baseWallet) SubmitSignedPSBT(id, signedPSBT string) error {
+	if btc.signer == nil {
+		return errors.New("wallet is not configured for external signing")
+	}
+	if _, err := base64.StdEncoding.DecodeString(signedPSBT); err != nil {
+		return fmt.Errorf("psbt is not base64 encoded: %w", err)
+	}
+	return btc.signer.submit(id, signedPSBT)
+}
@@ -0,0 +1,235 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestPSBTSigning(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	signer := newPSBTSigner(tLogger, wallet.emit)
+	pn := &psbtNode{
+		Wallet:        wallet.node,
+		signer:        signer,
+		segwit:        true,
+		deserializeTx: wallet.deserializeTx,
+	}
+	wallet.signer = signer
+	wallet.setNode(pn)
+
+	// The external signer's key.
+	privKey, _ := btcec.NewPrivateKey()
+	pubKey := privKey.PubKey().SerializeCompressed()
+	addr, _ := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pubKey), &chaincfg.MainNetParams)
+	pkScript, _ := txscript.PayToAddrScript(addr)
+
+	const prevVal = 1e8
+	prevTx := wire.NewMsgTx(wire.TxVersion)
+	prevTx.AddTxIn(wire.NewTxIn(new(wire.OutPoint), nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(prevVal, pkScript))
+	prevTxB, _ := serializeMsgTx(prevTx)
+	node.getTransactionMap[tTxID] = &GetTransactionResult{Bytes: prevTxB}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(tTxHash, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(prevVal-1000, pkScript))
+
+	// The draft has placeholder signatures of the maximum size.
+	draft, err := pn.SignTx(tx)
+	if err != nil {
+		t.Fatalf("SignTx error: %v", err)
+	}
+	if len(draft.TxIn[0].Witness) != 2 || len(draft.TxIn[0].Witness[0]) != 73 {
+		t.Fatalf("wrong placeholder witness")
+	}
+
+	// Broadcasting the draft requests a signature.
+	if _, err = pn.SendRawTransaction(draft); !errors.Is(err, asset.ErrAwaitingSignature) {
+		t.Fatalf("expected ErrAwaitingSignature, got %v", err)
+	}
+	if node.sentRawTx != nil {
+		t.Fatalf("draft was broadcast")
+	}
+	// Trying again doesn't create another request.
+	pn.SendRawTransaction(draft)
+	reqs := wallet.PendingPSBTs()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 pending psbt, got %d", len(reqs))
+	}
+	req := reqs[0]
+	if req.ID != tx.TxHash().String() {
+		t.Fatalf("wrong request ID")
+	}
+
+	// Sign it externally.
+	packet, err := psbt.NewFromRawBytes(strings.NewReader(req.PSBT), true)
+	if err != nil {
+		t.Fatalf("error decoding psbt: %v", err)
+	}
+	in := &packet.Inputs[0]
+	if in.WitnessUtxo == nil || in.WitnessUtxo.Value != prevVal {
+		t.Fatalf("wrong witness utxo")
+	}
+	fetcher := txscript.NewCannedPrevOutputFetcher(pkScript, prevVal)
+	sigHashes := txscript.NewTxSigHashes(packet.UnsignedTx, fetcher)
+	sig, err := txscript.RawTxInWitnessSignature(packet.UnsignedTx, sigHashes, 0, prevVal, pkScript, txscript.SigHashAll, privKey)
+	if err != nil {
+		t.Fatalf("error signing: %v", err)
+	}
+	in.PartialSigs = []*psbt.PartialSig{{PubKey: pubKey, Signature: sig}}
+	signedB64, _ := packet.B64Encode()
+
+	if err := wallet.SubmitSignedPSBT("abc", signedB64); err == nil {
+		t.Fatalf("no error for wrong ID")
+	}
+	if err := wallet.SubmitSignedPSBT(req.ID, signedB64); err != nil {
+		t.Fatalf("SubmitSignedPSBT error: %v", err)
+	}
+	if len(wallet.PendingPSBTs()) != 0 {
+		t.Fatalf("request still pending")
+	}
+
+	// Now SignTx returns the signed transaction, which is broadcast.
+	signedTx, err := pn.SignTx(tx)
+	if err != nil {
+		t.Fatalf("SignTx error: %v", err)
+	}
+	if len(signedTx.TxIn[0].Witness) != 2 || len(signedTx.TxIn[0].Witness[0]) != len(sig) {
+		t.Fatalf("transaction not signed")
+	}
+	if _, err := pn.SendRawTransaction(signedTx); err != nil {
+		t.Fatalf("SendRawTransaction error: %v", err)
+	}
+	if node.sentRawTx == nil || node.sentRawTx.TxHash() != tx.TxHash() {
+		t.Fatalf("signed transaction not broadcast")
+	}
+	if signer.signedPacket(inputsKey(tx)) != nil {
+		t.Fatalf("signed packet not removed after broadcast")
+	}
+}
+
+func TestPSBTMultisigCosigners(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	signer := newPSBTSigner(tLogger, wallet.emit)
+	signer.multisig = true
+	pn := &psbtNode{
+		Wallet:        wallet.node,
+		signer:        signer,
+		segwit:        true,
+		deserializeTx: wallet.deserializeTx,
+		multisig:      &multisigParams{threshold: 2, keys: 3},
+	}
+	wallet.signer = signer
+	wallet.setNode(pn)
+
+	// A 2-of-3 P2WSH multisig output.
+	privKeys := make([]*btcec.PrivateKey, 3)
+	pubKeys := make([]*btcutil.AddressPubKey, 3)
+	for i := range privKeys {
+		privKeys[i], _ = btcec.NewPrivateKey()
+		pubKeys[i], _ = btcutil.NewAddressPubKey(privKeys[i].PubKey().SerializeCompressed(), &chaincfg.MainNetParams)
+	}
+	witnessScript, _ := txscript.MultiSigScript(pubKeys, 2)
+	addr, _ := btcutil.NewAddressWitnessScriptHash(hashContract(true, witnessScript), &chaincfg.MainNetParams)
+	pkScript, _ := txscript.PayToAddrScript(addr)
+
+	const prevVal = 1e8
+	prevTx := wire.NewMsgTx(wire.TxVersion)
+	prevTx.AddTxIn(wire.NewTxIn(new(wire.OutPoint), nil, nil))
+	prevTx.AddTxOut(wire.NewTxOut(prevVal, pkScript))
+	prevTxB, _ := serializeMsgTx(prevTx)
+	node.getTransactionMap[tTxID] = &GetTransactionResult{Bytes: prevTxB}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(tTxHash, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(prevVal-1000, pkScript))
+
+	draft, err := pn.SignTx(tx)
+	if err != nil {
+		t.Fatalf("SignTx error: %v", err)
+	}
+	// Empty item, 2 signatures, and the witness script.
+	if w := draft.TxIn[0].Witness; len(w) != 4 || len(w[3]) != len(witnessScript) {
+		t.Fatalf("wrong placeholder witness")
+	}
+	if _, err = pn.SendRawTransaction(draft); !errors.Is(err, asset.ErrAwaitingSignature) {
+		t.Fatalf("expected ErrAwaitingSignature, got %v", err)
+	}
+	reqs := wallet.PendingPSBTs()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 pending psbt, got %d", len(reqs))
+	}
+	reqID := reqs[0].ID
+
+	// cosign signs the pending PSBT with a cosigner's key.
+	cosign := func(privKey *btcec.PrivateKey) string {
+		t.Helper()
+		packet, err := psbt.NewFromRawBytes(strings.NewReader(wallet.PendingPSBTs()[0].PSBT), true)
+		if err != nil {
+			t.Fatalf("error decoding psbt: %v", err)
+		}
+		in := &packet.Inputs[0]
+		// The node would add the witness script.
+		in.WitnessScript = witnessScript
+		fetcher := txscript.NewCannedPrevOutputFetcher(pkScript, prevVal)
+		sigHashes := txscript.NewTxSigHashes(packet.UnsignedTx, fetcher)
+		sig, err := txscript.RawTxInWitnessSignature(packet.UnsignedTx, sigHashes, 0, prevVal, witnessScript, txscript.SigHashAll, privKey)
+		if err != nil {
+			t.Fatalf("error signing: %v", err)
+		}
+		in.PartialSigs = append(in.PartialSigs, &psbt.PartialSig{PubKey: privKey.PubKey().SerializeCompressed(), Signature: sig})
+		b64, _ := packet.B64Encode()
+		return b64
+	}
+
+	// One signature isn't enough. The request is updated for the next
+	// cosigner.
+	firstSig := cosign(privKeys[2])
+	if err := wallet.SubmitSignedPSBT(reqID, firstSig); err != nil {
+		t.Fatalf("SubmitSignedPSBT error: %v", err)
+	}
+	reqs = wallet.PendingPSBTs()
+	if len(reqs) != 1 || reqs[0].PSBT != firstSig {
+		t.Fatalf("request not updated with the first signature")
+	}
+	// The same signature again is an error.
+	if err := wallet.SubmitSignedPSBT(reqID, firstSig); err == nil {
+		t.Fatalf("no error for a psbt without new signatures")
+	}
+
+	if err := wallet.SubmitSignedPSBT(reqID, cosign(privKeys[0])); err != nil {
+		t.Fatalf("SubmitSignedPSBT error: %v", err)
+	}
+	if len(wallet.PendingPSBTs()) != 0 {
+		t.Fatalf("request still pending")
+	}
+
+	signedTx, err := pn.SignTx(tx)
+	if err != nil {
+		t.Fatalf("SignTx error: %v", err)
+	}
+	prevOuts := txscript.NewCannedPrevOutputFetcher(pkScript, prevVal)
+	vm, err := txscript.NewEngine(pkScript, signedTx, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(signedTx, prevOuts), prevVal, prevOuts)
+	if err != nil {
+		t.Fatalf("NewEngine error: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("multisig input not valid: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// rbfSequence is the input sequence number used to signal replaceability
+	// under BIP 125. Any sequence below wire.MaxTxInSequenceNum - 1 signals,
+	// but this one does not enable relative lock times.
+	rbfSequence = wire.MaxTxInSequenceNum - 2
+	// minIncrementalRelayFeeRate is the rate in sats/vbyte by which a
+	// replacement must increase the absolute fee of the original, per BIP 125
+	// rule 4 with the default -incrementalrelayfee.
+	minIncrementalRelayFeeRate = 1
+)
+
+// signalsRBF checks whether any of the transaction's inputs signal
+// replaceability.
+func signalsRBF(tx *wire.MsgTx) bool {
+	for _, txIn := range tx.TxIn {
+		if txIn.Sequence < wire.MaxTxInSequenceNum-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// BumpFee replaces an unconfirmed send or withdrawal with a transaction that
+// pays feeRate. The increased fee is deducted from the change output, so the
+// recipient receives the same amount. The change output is identified by
+// address ownership, not by position. Because the replacement has a different
+// transaction ID, the original's change output must not have been used, e.g.
+// to fund an order or as an input to another transaction, since replacing the
+// original would invalidate those. BumpFee satisfies asset.FeeBumper.
+func (btc *baseWallet) BumpFee(txID string, feeRate uint64) (string, error) {
+	txHash, err := chainhash.NewHashFromStr(txID)
+	if err != nil {
+		return "", fmt.Errorf("invalid transaction ID %q: %w", txID, err)
+	}
+	if feeRate == 0 || feeRate > btc.feeRateLimit() {
+		return "", fmt.Errorf("fee rate %d is not between 1 and the limit of %d", feeRate, btc.feeRateLimit())
+	}
+
+	btc.pendingTxsMtx.RLock()
+	wt, found := btc.pendingTxs[*txHash]
+	btc.pendingTxsMtx.RUnlock()
+	if !found {
+		return "", fmt.Errorf("transaction %s is not a pending transaction", txHash)
+	}
+	if wt.Type != asset.Send && wt.Type != asset.SelfSend {
+		return "", fmt.Errorf("transaction %s is not a send", txHash)
+	}
+
+	gtr, err := btc.node.GetWalletTransaction(txHash)
+	if err != nil {
+		return "", fmt.Errorf("error finding transaction %s: %w", txHash, err)
+	}
+	if gtr.Confirmations > 0 {
+		return "", fmt.Errorf("transaction %s is already confirmed", txHash)
+	}
+	tx, err := btc.deserializeTx(gtr.Bytes)
+	if err != nil {
+		return "", fmt.Errorf("error decoding transaction %s: %w", txHash, err)
+	}
+	if !signalsRBF(tx) {
+		return "", fmt.Errorf("transaction %s does not signal replaceability", txHash)
+	}
+
+	changeIdx, err := btc.findChangeOutput(tx, wt.Recipient)
+	if err != nil {
+		return "", err
+	}
+	changeOut := tx.TxOut[changeIdx]
+	changePt := NewOutPoint(txHash, changeIdx)
+	if btc.cm.LockedOutput(changePt) != nil {
+		return "", errors.New("the change output is funding an order")
+	}
+	unspent, _, err := btc.node.GetTxOut(txHash, changeIdx, changeOut.PkScript, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("error checking change output: %w", err)
+	}
+	if unspent == nil {
+		return "", errors.New("the change output has been spent by another transaction")
+	}
+
+	vSize := btc.calcTxSize(tx)
+	newFee := feeRate * vSize
+	if minFee := wt.Fees + minIncrementalRelayFeeRate*vSize; newFee < minFee {
+		return "", fmt.Errorf("the new fee rate must be at least %d sats/vB", (minFee+vSize-1)/vSize)
+	}
+	feeIncrease := newFee - wt.Fees
+	if uint64(changeOut.Value) <= feeIncrease {
+		return "", fmt.Errorf("the change output of %d cannot cover the fee increase of %d", changeOut.Value, feeIncrease)
+	}
+	changeOut.Value -= int64(feeIncrease)
+	if btc.IsDust(changeOut, feeRate) {
+		return "", errors.New("the fee increase would leave a dust change output")
+	}
+
+	for _, txIn := range tx.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+	signedTx, err := btc.node.SignTx(tx)
+	if err != nil {
+		return "", fmt.Errorf("error signing replacement: %w", err)
+	}
+	newHash, err := btc.broadcastTx(signedTx)
+	if err != nil {
+		return "", err
+	}
+
+	btc.log.Infof("Replaced transaction %s with %s, increasing the fee from %d to %d sats",
+		txHash, newHash, wt.Fees, newFee)
+
+	btc.removeTxFromHistory(txHash)
+	replacement := *wt.WalletTransaction
+	replacement.ID = newHash.String()
+	replacement.Fees = newFee
+	btc.addTxToHistory(&replacement, newHash, true)
+
+	return newHash.String(), nil
+}
+
+// findChangeOutput finds the index of the change output of a send. The change
+// is the only output paying an address of the wallet other than the
+// recipient's. Its position is not assumed, since not every wallet puts it
+// last.
+func (btc *baseWallet) findChangeOutput(tx *wire.MsgTx, recipient *string) (uint32, error) {
+	changeIdx := -1
+	for i, txOut := range tx.TxOut {
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(txOut.PkScript, btc.chainParams)
+		if err != nil || len(addrs) != 1 {
+			continue
+		}
+		addr := addrs[0]
+		if recipient != nil {
+			addrStr, err := btc.stringAddr(addr, btc.chainParams)
+			if err == nil && addrStr == *recipient {
+				continue
+			}
+		}
+		owns, err := btc.node.OwnsAddress(addr)
+		if err != nil {
+			return 0, fmt.Errorf("error checking ownership of output %d: %w", i, err)
+		}
+		if !owns {
+			continue
+		}
+		if changeIdx >= 0 {
+			return 0, fmt.Errorf("cannot tell which of outputs %d and %d is the change", changeIdx, i)
+		}
+		changeIdx = i
+	}
+	if changeIdx < 0 {
+		return 0, errors.New("transaction has no change output to pay the increased fee")
+	}
+	return uint32(changeIdx), nil
+}
@@ -0,0 +1,168 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+// redeemBatchWindow is how long the first redemption in a batch waits for
+// others to join it. Core checks the matches of every trade of an asset at
+// the same time, so redemptions for different orders arrive together.
+const redeemBatchWindow = 2 * time.Second
+
+// batchRedeemsOpt enables combining redemptions for different orders into a
+// single transaction.
+var batchRedeemsOpt = &asset.ConfigOption{
+	Key:         "batchredeems",
+	DisplayName: "Batch redemptions",
+	Description: "Combine the redemptions of matches from different orders " +
+		"that are ready at the same time into a single transaction to save " +
+		"fees. Redemptions may be delayed by up to a few seconds.",
+	IsBoolean: true,
+}
+
+type redeemResult struct {
+	coinIDs []dex.Bytes
+	coin    asset.Coin
+	fees    uint64
+	err     error
+}
+
+// redeemBatch is a group of redemption requests that will be redeemed in a
+// single transaction.
+type redeemBatch struct {
+	forms   []*asset.RedeemForm
+	results []*redeemResult
+	done    chan struct{}
+}
+
+// redeemBatcher collects calls to Redeem that arrive within redeemBatchWindow
+// of each other. Only requests with the same options are batched together,
+// since the options can affect the fee rate.
+type redeemBatcher struct {
+	redeem func(*asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error)
+	log    dex.Logger
+	// afterFunc schedules the processing of a new batch. It is
+	// time.AfterFunc, except in tests, which process batches explicitly.
+	afterFunc func(time.Duration, func())
+
+	mtx     sync.Mutex
+	batches map[string]*redeemBatch
+}
+
+func newRedeemBatcher(redeem func(*asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error), log dex.Logger) *redeemBatcher {
+	return &redeemBatcher{
+		redeem:  redeem,
+		log:     log,
+		batches: make(map[string]*redeemBatch),
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+func optionsKey(opts map[string]string) string {
+	keys := make([]string, 0, len(opts))
+	for k, v := range opts {
+		keys = append(keys, k+"="+v)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// add adds the redemptions to a batch and waits for the batch to be redeemed.
+func (b *redeemBatcher) add(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error) {
+	key := optionsKey(form.Options)
+
+	b.mtx.Lock()
+	batch, found := b.batches[key]
+	if !found {
+		batch = &redeemBatch{done: make(chan struct{})}
+		b.batches[key] = batch
+		b.afterFunc(redeemBatchWindow, func() {
+			b.mtx.Lock()
+			delete(b.batches, key)
+			b.mtx.Unlock()
+			b.process(batch)
+		})
+	}
+	idx := len(batch.forms)
+	batch.forms = append(batch.forms, form)
+	b.mtx.Unlock()
+
+	<-batch.done
+	r := batch.results[idx]
+	return r.coinIDs, r.coin, r.fees, r.err
+}
+
+// process redeems all of the requests in the batch in one transaction. If
+// that fails, each request is redeemed separately, so that one bad redemption
+// doesn't cause the others to fail.
+func (b *redeemBatcher) process(batch *redeemBatch) {
+	defer close(batch.done)
+	batch.results = make([]*redeemResult, len(batch.forms))
+
+	redeemEach := func() {
+		for i, form := range batch.forms {
+			coinIDs, coin, fees, err := b.redeem(form)
+			batch.results[i] = &redeemResult{coinIDs, coin, fees, err}
+		}
+	}
+	if len(batch.forms) == 1 {
+		redeemEach()
+		return
+	}
+
+	combined := &asset.RedeemForm{Options: batch.forms[0].Options}
+	for _, form := range batch.forms {
+		combined.Redemptions = append(combined.Redemptions, form.Redemptions...)
+		if form.FeeSuggestion > combined.FeeSuggestion {
+			combined.FeeSuggestion = form.FeeSuggestion
+		}
+	}
+	coinIDs, coin, fees, err := b.redeem(combined)
+	if errors.Is(err, asset.ErrAwaitingSignature) {
+		// The combined transaction will be broadcast once it is signed.
+		for i := range batch.forms {
+			batch.results[i] = &redeemResult{err: err}
+		}
+		return
+	}
+	if err != nil {
+		b.log.Errorf("Error redeeming a batch of %d redemptions. Redeeming separately: %v",
+			len(combined.Redemptions), err)
+		redeemEach()
+		return
+	}
+	b.log.Infof("Redeemed %d contracts from %d requests in one transaction",
+		len(combined.Redemptions), len(batch.forms))
+
+	// Split the fees by the number of contracts redeemed, with any remainder
+	// going to the last request.
+	n := uint64(len(combined.Redemptions))
+	var start int
+	var feesAssigned uint64
+	for i, form := range batch.forms {
+		end := start + len(form.Redemptions)
+		formFees := fees * uint64(len(form.Redemptions)) / n
+		if i == len(batch.forms)-1 {
+			formFees = fees - feesAssigned
+		}
+		feesAssigned += formFees
+		batch.results[i] = &redeemResult{
+			coinIDs: coinIDs[start:end],
+			coin:    coin,
+			fees:    formFees,
+		}
+		start = end
+	}
+}
@@ -0,0 +1,133 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+func TestRedeemBatcher(t *testing.T) {
+	var callsMtx sync.Mutex
+	var calls []*asset.RedeemForm
+	var failBatch bool
+	redeem := func(form *asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error) {
+		callsMtx.Lock()
+		calls = append(calls, form)
+		callsMtx.Unlock()
+		if failBatch && len(form.Redemptions) > 1 {
+			return nil, nil, 0, errors.New("test error")
+		}
+		coinIDs := make([]dex.Bytes, len(form.Redemptions))
+		for i := range coinIDs {
+			coinIDs[i] = ToCoinID(tTxHash, uint32(i))
+		}
+		return coinIDs, NewOutput(tTxHash, 0, 1e8), 1000, nil
+	}
+	b := newRedeemBatcher(redeem, tLogger)
+	// Batches are processed when the test says so, not after a delay.
+	flushes := make(chan func(), 16)
+	b.afterFunc = func(_ time.Duration, f func()) {
+		flushes <- f
+	}
+	queued := func() (n int) {
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		for _, batch := range b.batches {
+			n += len(batch.forms)
+		}
+		return n
+	}
+
+	newForm := func(n int, feeSuggestion uint64, opts map[string]string) *asset.RedeemForm {
+		form := &asset.RedeemForm{FeeSuggestion: feeSuggestion, Options: opts}
+		for i := 0; i < n; i++ {
+			form.Redemptions = append(form.Redemptions, &asset.Redemption{Secret: []byte{byte(i)}})
+		}
+		return form
+	}
+
+	type result struct {
+		coinIDs []dex.Bytes
+		fees    uint64
+		err     error
+	}
+	run := func(forms ...*asset.RedeemForm) []*result {
+		calls = nil
+		results := make([]*result, len(forms))
+		var wg sync.WaitGroup
+		for i, form := range forms {
+			wg.Add(1)
+			go func(i int, form *asset.RedeemForm) {
+				defer wg.Done()
+				coinIDs, _, fees, err := b.add(form)
+				results[i] = &result{coinIDs, fees, err}
+			}(i, form)
+		}
+		// Process the batches once every request has joined one.
+		for queued() < len(forms) {
+			runtime.Gosched()
+		}
+		for len(flushes) > 0 {
+			(<-flushes)()
+		}
+		wg.Wait()
+		return results
+	}
+
+	// Three requests with the same options are redeemed together.
+	results := run(newForm(1, 10, nil), newForm(2, 20, nil), newForm(3, 15, nil))
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 redeem call, got %d", len(calls))
+	}
+	if n := len(calls[0].Redemptions); n != 6 {
+		t.Fatalf("expected 6 redemptions, got %d", n)
+	}
+	if calls[0].FeeSuggestion != 20 {
+		t.Fatalf("expected the highest fee suggestion, got %d", calls[0].FeeSuggestion)
+	}
+	var totalFees uint64
+	seen := make(map[string]bool)
+	for i, r := range results {
+		if r.err != nil {
+			t.Fatalf("result %d error: %v", i, r.err)
+		}
+		if len(r.coinIDs) != i+1 {
+			t.Fatalf("result %d has %d coin IDs, wanted %d", i, len(r.coinIDs), i+1)
+		}
+		for _, coinID := range r.coinIDs {
+			if seen[coinID.String()] {
+				t.Fatalf("duplicate coin ID")
+			}
+			seen[coinID.String()] = true
+		}
+		totalFees += r.fees
+	}
+	if totalFees != 1000 {
+		t.Fatalf("fees add up to %d, wanted 1000", totalFees)
+	}
+
+	// Different options are redeemed separately.
+	run(newForm(1, 10, nil), newForm(1, 10, map[string]string{"redeemfeebump": "1.5"}))
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 redeem calls for different options, got %d", len(calls))
+	}
+
+	// If the batch fails, each request is redeemed separately.
+	failBatch = true
+	results = run(newForm(1, 10, nil), newForm(1, 10, nil))
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 redeem calls after batch failure, got %d", len(calls))
+	}
+	for i, r := range results {
+		if r.err != nil {
+			t.Fatalf("result %d error after batch failure: %v", i, r.err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// rescanPollInterval is how often the progress of a rescan is checked.
+const rescanPollInterval = 3 * time.Second
+
+// blockchainRescanner is implemented by wallet backends that can rescan the
+// blockchain for the wallet's transactions.
+type blockchainRescanner interface {
+	// rescanBlockchain rescans from the height, and blocks until it is done.
+	rescanBlockchain(startHeight int64) error
+	// rescanProgress is the fraction of the blocks scanned by a running
+	// rescan. scanning is false if there is no rescan running.
+	rescanProgress() (progress float64, scanning bool, err error)
+}
+
+// birthdayHeight finds the height of the last block before the birthday.
+func (btc *intermediaryWallet) birthdayHeight(bday uint64) (int64, error) {
+	if bday == 0 {
+		bday = uint64(defaultWalletBirthdayUnix)
+	}
+	btc.tipMtx.RLock()
+	tipHeight := btc.currentTip.Height
+	btc.tipMtx.RUnlock()
+	var err error
+	firstBlockAfterBday := sort.Search(int(tipHeight), func(height int) bool {
+		if err != nil { // if we see any errors, just give up.
+			return false
+		}
+		var blockHash *chainhash.Hash
+		var hdr *BlockHeader
+		if blockHash, err = btc.tipRedeemer.GetBlockHash(int64(height)); err == nil {
+			hdr, _, err = btc.tipRedeemer.GetBlockHeader(blockHash)
+		}
+		if err != nil {
+			err = fmt.Errorf("error getting block header for height %d: %w", height, err)
+			return false
+		}
+		return uint64(hdr.Time) >= bday
+	})
+	if err != nil {
+		return 0, err
+	}
+	if firstBlockAfterBday == 0 {
+		return 0, nil
+	}
+	return int64(firstBlockAfterBday - 1), nil
+}
+
+// Rescan rescans the blockchain from the birthday, if the node supports it.
+// The rescan continues asynchronously, and its progress is emitted. Part of
+// the asset.Rescanner interface.
+func (btc *intermediaryWallet) Rescan(ctx context.Context, bday uint64) error {
+	rescanner, is := btc.node.(blockchainRescanner)
+	if !is {
+		return errors.New("wallet does not support rescanning")
+	}
+	if !btc.rescanning.CompareAndSwap(false, true) {
+		return errors.New("rescan already in progress")
+	}
+	startHeight, err := btc.birthdayHeight(bday)
+	if err != nil {
+		btc.rescanning.Store(false)
+		return err
+	}
+	btc.tipMtx.RLock()
+	tipHeight := btc.currentTip.Height
+	btc.tipMtx.RUnlock()
+	btc.log.Infof("Rescanning from block %d", startHeight)
+	tracker := asset.NewRescanTracker(btc.emit, uint64(startHeight), uint64(tipHeight))
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- rescanner.rescanBlockchain(startHeight)
+	}()
+	go func() {
+		defer btc.rescanning.Store(false)
+		ticker := time.NewTicker(rescanPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case err := <-errC:
+				if err != nil {
+					btc.log.Errorf("Rescan error: %v", err)
+				} else {
+					btc.log.Infof("Completed rescan from block %d", startHeight)
+					btc.receiveTxLastQuery.Store(0)
+				}
+				tracker.Done(err)
+				return
+			case <-ticker.C:
+				progress, scanning, err := rescanner.rescanProgress()
+				if err != nil {
+					btc.log.Warnf("Error checking rescan progress: %v", err)
+					continue
+				}
+				if scanning {
+					scanned := startHeight + int64(progress*float64(tipHeight-startHeight))
+					tracker.Update(uint64(scanned), uint64(tipHeight))
+				}
+			case <-ctx.Done():
+				tracker.Done(ctx.Err())
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// monitorSPVRescan emits the progress of an SPV wallet's rescan, which is a
+// resync of the wallet from its birthday, until the wallet is synced.
+func (btc *ExchangeWalletSPV) monitorSPVRescan(ctx context.Context, tracker *asset.RescanTracker) {
+	defer btc.rescanning.Store(false)
+	ticker := time.NewTicker(rescanPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ss, err := btc.node.SyncStatus()
+			if err != nil {
+				btc.log.Warnf("Error checking rescan progress: %v", err)
+				continue
+			}
+			if ss.Synced {
+				tracker.Done(nil)
+				return
+			}
+			tracker.Update(ss.Blocks, ss.TargetHeight)
+		case <-ctx.Done():
+			tracker.Done(ctx.Err())
+			return
+		}
+	}
+}
@@ -47,6 +47,12 @@ const (
 	methodGetWalletInfo        = "getwalletinfo"
 	methodGetAddressInfo       = "getaddressinfo"
 	methodListDescriptors      = "listdescriptors"
+	methodGetDescriptorInfo    = "getdescriptorinfo"
+	methodImportDescriptors    = "importdescriptors"
+	methodLoadWallet           = "loadwallet"
+	methodCreateWallet         = "createwallet"
+	methodWalletProcessPSBT    = "walletprocesspsbt"
+	methodRescanBlockchain     = "rescanblockchain"
 	methodValidateAddress      = "validateaddress"
 	methodEstimateSmartFee     = "estimatesmartfee"
 	methodSendRawTransaction   = "sendrawtransaction"
@@ -62,6 +68,7 @@ const (
 	methodFundRawTransaction   = "fundrawtransaction"
 	methodListSinceBlock       = "listsinceblock"
 	methodGetReceivedByAddress = "getreceivedbyaddress"
+	methodScanTxOutSet         = "scantxoutset"
 )
 
 // IsTxNotFoundErr will return true if the error indicates that the requested
@@ -125,6 +132,7 @@ type rpcCore struct {
 	legacyValidateAddressRPC bool
 	omitRPCOptionsArg        bool
 	privKeyFunc              func(addr string) (*btcec.PrivateKey, error)
+	excludeUnspent           func(*ListUnspentResult) bool
 }
 
 func (c *rpcCore) requester() RawRequester {
@@ -137,6 +145,11 @@ type rpcClient struct {
 	*rpcCore
 	ctx         context.Context
 	descriptors bool // set on connect like ctx
+	// watchOnly is set on connect for descriptor wallets that have private
+	// keys disabled, e.g. wallets with keys on an external signer.
+	watchOnly bool
+	// watchOnlyCfg is set for a watch-only wallet of an extended public key.
+	watchOnlyCfg *watchOnlyConfig
 }
 
 var _ Wallet = (*rpcClient)(nil)
@@ -183,6 +196,11 @@ func (wc *rpcClient) Connect(ctx context.Context, _ *sync.WaitGroup) error {
 		return errors.New("wrong net")
 	}
 	wiRes, err := wc.GetWalletInfo()
+	if err != nil && wc.watchOnlyCfg != nil && isWalletNotFoundErr(err) {
+		if err = wc.loadOrCreateWatchOnlyWallet(); err == nil {
+			wiRes, err = wc.GetWalletInfo()
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("getwalletinfo failure: %w", err)
 	}
@@ -192,7 +210,17 @@ func (wc *rpcClient) Connect(ctx context.Context, _ *sync.WaitGroup) error {
 			return fmt.Errorf("reported node version %d is less than minimum %d"+
 				" for descriptor wallets", netVer, minDescriptorVersion)
 		}
-		wc.log.Debug("Using a descriptor wallet.")
+		wc.watchOnly = !wiRes.PriveyKeysEnabled
+		if wc.watchOnly {
+			wc.log.Info("Using a watch-only descriptor wallet. Swap contracts will be imported.")
+		} else {
+			wc.log.Debug("Using a descriptor wallet.")
+		}
+	}
+	if wc.watchOnlyCfg != nil {
+		if err := wc.importXPub(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -206,6 +234,19 @@ func (wc *rpcClient) Reconfigure(cfg *asset.WalletConfig, currentAddress string)
 		restartRequired = true
 		return
 	}
+	if wc.watchOnlyCfg != nil {
+		// The extended public keys are imported on connect.
+		woCfg := new(watchOnlyConfig)
+		if err = config.Unmapify(cfg.Settings, woCfg); err != nil {
+			return
+		}
+		if err = woCfg.normalize(wc.chainParams); err != nil {
+			return
+		}
+		if *woCfg != *wc.watchOnlyCfg {
+			return true, nil
+		}
+	}
 	if wc.ctx == nil || wc.ctx.Err() != nil {
 		return true, nil // not connected, ok to reconfigure, but restart required
 	}
@@ -496,7 +537,19 @@ func (wc *rpcClient) Balances() (*GetBalancesResult, error) {
 func (wc *rpcClient) ListUnspent() ([]*ListUnspentResult, error) {
 	unspents := make([]*ListUnspentResult, 0)
 	// TODO: listunspent 0 9999999 []string{}, include_unsafe=false
-	return unspents, wc.call(methodListUnspent, anylist{uint8(0)}, &unspents)
+	if err := wc.call(methodListUnspent, anylist{uint8(0)}, &unspents); err != nil {
+		return nil, err
+	}
+	if wc.excludeUnspent == nil {
+		return unspents, nil
+	}
+	filtered := make([]*ListUnspentResult, 0, len(unspents))
+	for _, u := range unspents {
+		if !wc.excludeUnspent(u) {
+			filtered = append(filtered, u)
+		}
+	}
+	return filtered, nil
 }
 
 // LockUnspent locks and unlocks outputs for spending. An output that is part of
@@ -640,6 +693,82 @@ func (wc *rpcClient) listDescriptors(private bool) (*listDescriptorsResult, erro
 	return descriptors, wc.call(methodListDescriptors, anylist{private}, descriptors)
 }
 
+// watchContract imports a descriptor for a swap contract's address into a
+// watch-only descriptor wallet, so that the wallet tracks the contract output
+// and the transaction that spends it. The wallet rescans from since, or only
+// tracks new transactions if since is zero. Descriptor wallets with private
+// keys can't import descriptors without keys, and don't need to, since the
+// contract keys are derived from the wallet's own descriptors.
+func (wc *rpcClient) watchContract(addr string, since time.Time) error {
+	if !wc.watchOnly {
+		return nil
+	}
+	descInfo := new(getDescriptorInfoResult)
+	if err := wc.call(methodGetDescriptorInfo, anylist{"addr(" + addr + ")"}, descInfo); err != nil {
+		return fmt.Errorf("getdescriptorinfo error: %w", err)
+	}
+	req := &importDescriptorRequest{
+		Descriptor: descInfo.Descriptor,
+		Timestamp:  "now",
+		Label:      "dex swap contract",
+	}
+	if !since.IsZero() {
+		req.Timestamp = since.Unix()
+	}
+	var res []*importDescriptorResult
+	if err := wc.call(methodImportDescriptors, anylist{[]*importDescriptorRequest{req}}, &res); err != nil {
+		return fmt.Errorf("importdescriptors error: %w", err)
+	}
+	if len(res) != 1 {
+		return fmt.Errorf("expected 1 importdescriptors result, got %d", len(res))
+	}
+	if !res[0].Success {
+		if res[0].Error != nil {
+			return fmt.Errorf("error importing %s: %s", descInfo.Descriptor, res[0].Error.Message)
+		}
+		return fmt.Errorf("error importing %s", descInfo.Descriptor)
+	}
+	return nil
+}
+
+// processPSBT adds the wallet's information about the inputs and outputs of
+// the base64-encoded PSBT, e.g. witness scripts and BIP 32 derivation paths,
+// without signing it.
+func (wc *rpcClient) processPSBT(b64 string) (string, error) {
+	const sign, bip32Derivs = false, true
+	res := new(struct {
+		PSBT string `json:"psbt"`
+	})
+	if err := wc.call(methodWalletProcessPSBT, anylist{b64, sign, "ALL", bip32Derivs}, res); err != nil {
+		return "", fmt.Errorf("walletprocesspsbt error: %w", err)
+	}
+	return res.PSBT, nil
+}
+
+// rescanBlockchain rescans the blockchain from the height for the wallet's
+// transactions. It blocks until the rescan is done.
+func (wc *rpcClient) rescanBlockchain(startHeight int64) error {
+	return wc.call(methodRescanBlockchain, anylist{startHeight}, nil)
+}
+
+// rescanProgress is the progress of a running rescan, from getwalletinfo.
+func (wc *rpcClient) rescanProgress() (float64, bool, error) {
+	res := new(struct {
+		// Scanning is false, or an object if a rescan is running.
+		Scanning json.RawMessage `json:"scanning"`
+	})
+	if err := wc.call(methodGetWalletInfo, nil, res); err != nil {
+		return 0, false, err
+	}
+	var scanning struct {
+		Progress float64 `json:"progress"`
+	}
+	if len(res.Scanning) == 0 || json.Unmarshal(res.Scanning, &scanning) != nil {
+		return 0, false, nil
+	}
+	return scanning.Progress, true, nil
+}
+
 func (wc *rpcClient) ListTransactionsSinceBlock(blockHeight int32) ([]*ListTransactionsResult, error) {
 	blockHash, err := wc.GetBlockHash(int64(blockHeight))
 	if err != nil {
@@ -1190,6 +1319,44 @@ func (wc *rpcClient) AddressUsed(addr string) (bool, error) {
 	return recv != 0, nil
 }
 
+// scanUTXOs searches the node's UTXO set for outputs paying to any of the
+// pkScripts, which need not belong to the wallet. Unconfirmed outputs are not
+// found. Part of the utxoScanner interface.
+func (wc *rpcClient) scanUTXOs(pkScripts [][]byte) ([]*sweepUTXO, error) {
+	descs := make([]string, 0, len(pkScripts))
+	for _, pkScript := range pkScripts {
+		descs = append(descs, "raw("+hex.EncodeToString(pkScript)+")")
+	}
+	var res struct {
+		Success  bool `json:"success"`
+		Unspents []struct {
+			TxID         string    `json:"txid"`
+			Vout         uint32    `json:"vout"`
+			ScriptPubKey dex.Bytes `json:"scriptPubKey"`
+			Amount       float64   `json:"amount"`
+		} `json:"unspents"`
+	}
+	if err := wc.call(methodScanTxOutSet, anylist{"start", descs}, &res); err != nil {
+		return nil, err
+	}
+	if !res.Success {
+		return nil, errors.New("scantxoutset did not complete")
+	}
+	utxos := make([]*sweepUTXO, 0, len(res.Unspents))
+	for _, u := range res.Unspents {
+		txHash, err := chainhash.NewHashFromStr(u.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding txid %q: %w", u.TxID, err)
+		}
+		utxos = append(utxos, &sweepUTXO{
+			op:       NewOutPoint(txHash, u.Vout),
+			value:    toSatoshi(u.Amount),
+			pkScript: u.ScriptPubKey,
+		})
+	}
+	return utxos, nil
+}
+
 // call is used internally to marshal parameters and send requests to the RPC
 // server via (*rpcclient.Client).RawRequest. If thing is non-nil, the result
 // will be marshaled into thing.
@@ -0,0 +1,215 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// maxSilentPaymentAddrLen is the maximum length of a silent payment
+	// address, per BIP-352.
+	maxSilentPaymentAddrLen = 1023
+	// silentPaymentKeysLen is the length of the serialized scan and spend keys
+	// of a silent payment address.
+	silentPaymentKeysLen = 2 * btcec.PubKeyBytesLenCompressed
+)
+
+var (
+	silentPaymentInputsTag       = []byte("BIP0352/Inputs")
+	silentPaymentSharedSecretTag = []byte("BIP0352/SharedSecret")
+
+	// dummyP2TRScript is a 34-byte pay-to-taproot pkScript for estimating the
+	// fees of a silent payment, whose output script depends on the inputs.
+	dummyP2TRScript = append([]byte{txscript.OP_1, txscript.OP_DATA_32}, make([]byte, 32)...)
+)
+
+// silentPaymentAddress is a BIP-352 silent payment address. It encodes a scan
+// key and a spend key rather than an output script. The output script of a
+// payment is derived from the keys and the inputs of the sending transaction.
+type silentPaymentAddress struct {
+	scanKey  *btcec.PublicKey
+	spendKey *btcec.PublicKey
+}
+
+// silentPaymentHRP is the human-readable part of silent payment addresses on
+// the network.
+func silentPaymentHRP(net *chaincfg.Params) string {
+	if net.Net == wire.MainNet {
+		return "sp"
+	}
+	return "tsp"
+}
+
+// decodeSilentPaymentAddress decodes a bech32m silent payment address for the
+// network. Addresses of future versions are accepted, and only their first
+// 66 bytes are used, as BIP-352 requires of senders.
+func decodeSilentPaymentAddress(addr string, net *chaincfg.Params) (*silentPaymentAddress, error) {
+	if len(addr) > maxSilentPaymentAddrLen {
+		return nil, fmt.Errorf("silent payment address too long: %d", len(addr))
+	}
+	hrp, data, err := bech32.DecodeNoLimit(addr)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != silentPaymentHRP(net) {
+		return nil, fmt.Errorf("silent payment address is for the wrong network (%s)", hrp)
+	}
+	// DecodeNoLimit accepts both checksum variants, but silent payment
+	// addresses must be bech32m.
+	if encoded, err := bech32.EncodeM(hrp, data); err != nil || encoded != strings.ToLower(addr) {
+		return nil, errors.New("silent payment address is not bech32m")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty silent payment address")
+	}
+	version := data[0]
+	if version == 31 {
+		return nil, errors.New("unsupported silent payment address version 31")
+	}
+	keys, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) < silentPaymentKeysLen || (version == 0 && len(keys) != silentPaymentKeysLen) {
+		return nil, fmt.Errorf("invalid version %d silent payment address length %d", version, len(keys))
+	}
+	scanKey, err := btcec.ParsePubKey(keys[:btcec.PubKeyBytesLenCompressed])
+	if err != nil {
+		return nil, fmt.Errorf("invalid scan key: %w", err)
+	}
+	spendKey, err := btcec.ParsePubKey(keys[btcec.PubKeyBytesLenCompressed:silentPaymentKeysLen])
+	if err != nil {
+		return nil, fmt.Errorf("invalid spend key: %w", err)
+	}
+	return &silentPaymentAddress{scanKey: scanKey, spendKey: spendKey}, nil
+}
+
+// silentPaymentInput is a transaction input that contributes to the shared
+// secret of a silent payment.
+type silentPaymentInput struct {
+	op      OutPoint
+	privKey *btcec.PrivateKey
+}
+
+// silentPaymentOutputScript derives the P2TR output script paying the address
+// from the inputs of the transaction, per BIP-352. Only one output to the
+// address is supported, so the output index k is always 0.
+func silentPaymentOutputScript(addr *silentPaymentAddress, inputs []*silentPaymentInput) ([]byte, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no inputs")
+	}
+
+	// a is the sum of the input private keys, and outpoint_L is the
+	// lexicographically smallest serialized outpoint.
+	var a btcec.ModNScalar
+	defer a.Zero()
+	var lowestOutpoint []byte
+	for _, in := range inputs {
+		a.Add(&in.privKey.Key)
+		op := make([]byte, chainhash.HashSize+4)
+		copy(op, in.op.TxHash[:])
+		binary.LittleEndian.PutUint32(op[chainhash.HashSize:], in.op.Vout)
+		if lowestOutpoint == nil || bytes.Compare(op, lowestOutpoint) < 0 {
+			lowestOutpoint = op
+		}
+	}
+	if a.IsZero() {
+		return nil, errors.New("input keys sum to zero")
+	}
+
+	// input_hash = hash_BIP0352/Inputs(outpoint_L || A)
+	var A btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&a, &A)
+	A.ToAffine()
+	h := chainhash.TaggedHash(silentPaymentInputsTag, lowestOutpoint, btcec.NewPublicKey(&A.X, &A.Y).SerializeCompressed())
+	var inputHash btcec.ModNScalar
+	if overflow := inputHash.SetByteSlice(h[:]); overflow || inputHash.IsZero() {
+		return nil, errors.New("invalid input hash")
+	}
+
+	// ecdh_shared_secret = input_hash·a·B_scan
+	var secret btcec.ModNScalar
+	defer secret.Zero()
+	secret.Mul2(&inputHash, &a)
+	var scanKey, sharedSecret btcec.JacobianPoint
+	addr.scanKey.AsJacobian(&scanKey)
+	btcec.ScalarMultNonConst(&secret, &scanKey, &sharedSecret)
+	sharedSecret.ToAffine()
+
+	// t_k = hash_BIP0352/SharedSecret(serP(ecdh_shared_secret) || ser32(k))
+	var k [4]byte
+	h = chainhash.TaggedHash(silentPaymentSharedSecretTag, btcec.NewPublicKey(&sharedSecret.X, &sharedSecret.Y).SerializeCompressed(), k[:])
+	var tweak btcec.ModNScalar
+	if overflow := tweak.SetByteSlice(h[:]); overflow || tweak.IsZero() {
+		return nil, errors.New("invalid shared secret tweak")
+	}
+
+	// P_k = B_spend + t_k·G
+	var tweakPoint, spendKey, outputKey btcec.JacobianPoint
+	btcec.ScalarBaseMultNonConst(&tweak, &tweakPoint)
+	addr.spendKey.AsJacobian(&spendKey)
+	btcec.AddNonConst(&spendKey, &tweakPoint, &outputKey)
+	if outputKey.Z.IsZero() {
+		return nil, errors.New("output key is the point at infinity")
+	}
+	outputKey.ToAffine()
+	return txscript.PayToTaprootScript(btcec.NewPublicKey(&outputKey.X, &outputKey.Y))
+}
+
+// isSilentPaymentAddress is true if the address has the silent payment prefix
+// for the wallet's network. Only BTC supports silent payments.
+func (btc *baseWallet) isSilentPaymentAddress(addr string) bool {
+	return btc.cloneParams.AssetID == BipID &&
+		strings.HasPrefix(strings.ToLower(addr), silentPaymentHRP(btc.chainParams)+"1")
+}
+
+// silentPaymentScript derives the output script paying the silent payment
+// address from the inputs of the funded tx. The private keys of the inputs are
+// required, so silent payments can't be sent with an external signer.
+func (btc *baseWallet) silentPaymentScript(addr *silentPaymentAddress, tx *wire.MsgTx, utxos map[OutPoint]*UTxO) ([]byte, error) {
+	if btc.signer != nil {
+		return nil, errors.New("silent payments are not supported with an external signer")
+	}
+	inputs := make([]*silentPaymentInput, 0, len(tx.TxIn))
+	defer func() {
+		for _, in := range inputs {
+			in.privKey.Zero()
+		}
+	}()
+	for _, txIn := range tx.TxIn {
+		op := NewOutPoint(&txIn.PreviousOutPoint.Hash, txIn.PreviousOutPoint.Index)
+		utxo, found := utxos[op]
+		if !found {
+			return nil, fmt.Errorf("no funding coin for input %s", op)
+		}
+		inAddr, err := btc.decodeAddr(utxo.Address, btc.chainParams)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding input address %s: %w", utxo.Address, err)
+		}
+		switch inAddr.(type) {
+		case *btcutil.AddressWitnessPubKeyHash, *btcutil.AddressPubKeyHash:
+		default:
+			return nil, fmt.Errorf("silent payments from %T inputs are not supported", inAddr)
+		}
+		privKey, err := btc.node.PrivKeyForAddress(utxo.Address)
+		if err != nil {
+			return nil, fmt.Errorf("error getting private key for input %s: %w", op, err)
+		}
+		inputs = append(inputs, &silentPaymentInput{op: op, privKey: privKey})
+	}
+	return silentPaymentOutputScript(addr, inputs)
+}
@@ -0,0 +1,257 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func tEncodeSilentPaymentAddress(t *testing.T, hrp string, version byte, keys []byte, bech32m bool) string {
+	t.Helper()
+	data, err := bech32.ConvertBits(keys, 8, 5, true)
+	if err != nil {
+		t.Fatalf("ConvertBits error: %v", err)
+	}
+	data = append([]byte{version}, data...)
+	encode := bech32.Encode
+	if bech32m {
+		encode = bech32.EncodeM
+	}
+	addr, err := encode(hrp, data)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+	return addr
+}
+
+func tSilentPaymentKeys(t *testing.T) (scanPriv, spendPriv *btcec.PrivateKey, keys []byte) {
+	t.Helper()
+	scanPriv, _ = btcec.NewPrivateKey()
+	spendPriv, _ = btcec.NewPrivateKey()
+	keys = append(scanPriv.PubKey().SerializeCompressed(), spendPriv.PubKey().SerializeCompressed()...)
+	return
+}
+
+func TestDecodeSilentPaymentAddress(t *testing.T) {
+	scanPriv, spendPriv, keys := tSilentPaymentKeys(t)
+	net := &chaincfg.MainNetParams
+
+	addr := tEncodeSilentPaymentAddress(t, "sp", 0, keys, true)
+	spAddr, err := decodeSilentPaymentAddress(addr, net)
+	if err != nil {
+		t.Fatalf("error decoding address: %v", err)
+	}
+	if !spAddr.scanKey.IsEqual(scanPriv.PubKey()) || !spAddr.spendKey.IsEqual(spendPriv.PubKey()) {
+		t.Fatalf("wrong keys decoded")
+	}
+
+	// Future versions may append data.
+	if _, err := decodeSilentPaymentAddress(tEncodeSilentPaymentAddress(t, "sp", 1, append(keys, 1, 2, 3), true), net); err != nil {
+		t.Fatalf("error decoding version 1 address: %v", err)
+	}
+
+	for name, addr := range map[string]string{
+		"testnet":    tEncodeSilentPaymentAddress(t, "tsp", 0, keys, true),
+		"bech32":     tEncodeSilentPaymentAddress(t, "sp", 0, keys, false),
+		"v0 length":  tEncodeSilentPaymentAddress(t, "sp", 0, append(keys, 1), true),
+		"short":      tEncodeSilentPaymentAddress(t, "sp", 1, keys[:65], true),
+		"version 31": tEncodeSilentPaymentAddress(t, "sp", 31, keys, true),
+		"bad key":    tEncodeSilentPaymentAddress(t, "sp", 0, append([]byte{5}, keys[1:]...), true),
+	} {
+		if _, err := decodeSilentPaymentAddress(addr, net); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+
+	if _, err := decodeSilentPaymentAddress(tEncodeSilentPaymentAddress(t, "tsp", 0, keys, true), &chaincfg.RegressionNetParams); err != nil {
+		t.Fatalf("error decoding regtest address: %v", err)
+	}
+}
+
+func TestSilentPaymentOutputScript(t *testing.T) {
+	// Sending test vectors from BIP-352 with P2PKH and P2WPKH inputs and a
+	// single output. Transaction IDs are in RPC byte order.
+	const spAddrStr = "sp1qqgste7k9hx0qftg6qmwlkqtwuy6cycyavzmzj85c6qdfhjdpdjtdgqjuexzk6murw56suy3e0rd2cgqvycxttddwsvgxe2usfpxumr70xc9pkqwv"
+	type vectorInput struct {
+		txid    string
+		vout    uint32
+		privKey string
+	}
+	vectors := []struct {
+		name   string
+		inputs []vectorInput
+		output string // x-only output key
+	}{
+		{
+			name: "Simple send: two inputs",
+			inputs: []vectorInput{
+				{"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", 0, "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+				{"a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d", 0, "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+			},
+			output: "3e9fce73d4e77a4809908e3c3a2e54ee147b9312dc5044a193d1fc85de46e3c1",
+		},
+		{
+			name: "Simple send: two inputs, order reversed",
+			inputs: []vectorInput{
+				{"a1075db55d416d3ca199f55b6084e2115b9345e16c5cf302fc80e9d5fbf5d48d", 0, "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+				{"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", 0, "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+			},
+			output: "3e9fce73d4e77a4809908e3c3a2e54ee147b9312dc5044a193d1fc85de46e3c1",
+		},
+		{
+			name: "Simple send: two inputs from the same transaction",
+			inputs: []vectorInput{
+				{"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", 3, "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+				{"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", 7, "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+			},
+			output: "79e71baa2ba3fc66396de3a04f168c7bf24d6870ec88ca877754790c1db357b6",
+		},
+		{
+			name: "Outpoint ordering byte-lexicographically vs. vout integer",
+			inputs: []vectorInput{
+				{"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", 1, "eadc78165ff1f8ea94ad7cfdc54990738a4c53f6e0507b42154201b8e5dff3b1"},
+				{"f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16", 256, "93f5ed907ad5b2bdbbdcb5d9116ebc0a4e1f92f910d5260237fa45a9408aad16"},
+			},
+			output: "a85ef8701394b517a4b35217c4bd37ac01ebeed4b008f8d0879f9e09ba95319c",
+		},
+	}
+	vectorAddr, err := decodeSilentPaymentAddress(spAddrStr, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("error decoding test vector address: %v", err)
+	}
+	for _, v := range vectors {
+		inputs := make([]*silentPaymentInput, len(v.inputs))
+		for i, in := range v.inputs {
+			txHash, err := chainhash.NewHashFromStr(in.txid)
+			if err != nil {
+				t.Fatalf("%s: bad txid: %v", v.name, err)
+			}
+			b, err := hex.DecodeString(in.privKey)
+			if err != nil {
+				t.Fatalf("%s: bad private key: %v", v.name, err)
+			}
+			privKey, _ := btcec.PrivKeyFromBytes(b)
+			inputs[i] = &silentPaymentInput{op: NewOutPoint(txHash, in.vout), privKey: privKey}
+		}
+		pkScript, err := silentPaymentOutputScript(vectorAddr, inputs)
+		if err != nil {
+			t.Fatalf("%s: error deriving output script: %v", v.name, err)
+		}
+		if outputKey := hex.EncodeToString(pkScript[2:]); outputKey != v.output {
+			t.Fatalf("%s: wrong output key %s, expected %s", v.name, outputKey, v.output)
+		}
+	}
+
+	scanPriv, spendPriv, _ := tSilentPaymentKeys(t)
+	spAddr := &silentPaymentAddress{scanKey: scanPriv.PubKey(), spendKey: spendPriv.PubKey()}
+
+	inputs := make([]*silentPaymentInput, 3)
+	pubKeys := make([]*btcec.PublicKey, len(inputs))
+	lowest := wire.OutPoint{Hash: chainhash.Hash{0x01}, Index: 2}
+	for i := range inputs {
+		privKey, _ := btcec.NewPrivateKey()
+		pubKeys[i] = privKey.PubKey()
+		op := NewOutPoint(&chainhash.Hash{byte(3 - i)}, uint32(i))
+		inputs[i] = &silentPaymentInput{op: op, privKey: privKey}
+	}
+
+	pkScript, err := silentPaymentOutputScript(spAddr, inputs)
+	if err != nil {
+		t.Fatalf("error deriving output script: %v", err)
+	}
+
+	// The receiver finds the output from the input public keys with their scan
+	// key, and can spend it with b_spend + t_k.
+	var A btcec.JacobianPoint
+	for _, pubKey := range pubKeys {
+		var pt, sum btcec.JacobianPoint
+		pubKey.AsJacobian(&pt)
+		btcec.AddNonConst(&A, &pt, &sum)
+		A = sum
+	}
+	A.ToAffine()
+	opB := make([]byte, 36)
+	copy(opB, lowest.Hash[:])
+	opB[32] = byte(lowest.Index)
+	h := chainhash.TaggedHash(silentPaymentInputsTag, opB, btcec.NewPublicKey(&A.X, &A.Y).SerializeCompressed())
+	var inputHash btcec.ModNScalar
+	inputHash.SetByteSlice(h[:])
+	var sharedSecret btcec.JacobianPoint
+	btcec.ScalarMultNonConst(new(btcec.ModNScalar).Mul2(&inputHash, &scanPriv.Key), &A, &sharedSecret)
+	sharedSecret.ToAffine()
+	h = chainhash.TaggedHash(silentPaymentSharedSecretTag, btcec.NewPublicKey(&sharedSecret.X, &sharedSecret.Y).SerializeCompressed(), make([]byte, 4))
+	var outputPriv btcec.ModNScalar
+	outputPriv.SetByteSlice(h[:])
+	outputPriv.Add(&spendPriv.Key)
+	expScript, _ := txscript.PayToTaprootScript(btcec.PrivKeyFromScalar(&outputPriv).PubKey())
+	if string(pkScript) != string(expScript) {
+		t.Fatalf("receiver derived a different output script")
+	}
+
+	// The order of the inputs doesn't matter.
+	inputs[0], inputs[2] = inputs[2], inputs[0]
+	if reordered, _ := silentPaymentOutputScript(spAddr, inputs); string(reordered) != string(pkScript) {
+		t.Fatalf("output depends on input order")
+	}
+}
+
+func TestSendSilentPayment(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	node.signFunc = func(tx *wire.MsgTx) {
+		signFunc(tx, 0, wallet.segwit)
+	}
+	node.changeAddr = tP2WPKHAddr
+	privKey, _ := btcec.NewPrivateKey()
+	node.privKeyForAddr, _ = btcutil.NewWIF(privKey, &chaincfg.MainNetParams, true)
+
+	addr := btcAddr(true)
+	pkScript, _ := txscript.PayToAddrScript(addr)
+	txHash := chainhash.Hash{0x01}
+	node.listUnspent = []*ListUnspentResult{{
+		TxID:          txHash.String(),
+		Address:       addr.String(),
+		Amount:        1,
+		Confirmations: 1,
+		ScriptPubKey:  pkScript,
+		SafePtr:       boolPtr(true),
+		Spendable:     true,
+	}}
+
+	scanPriv, spendPriv, keys := tSilentPaymentKeys(t)
+	spAddrStr := tEncodeSilentPaymentAddress(t, "sp", 0, keys, true)
+	if !wallet.ValidateAddress(spAddrStr) {
+		t.Fatalf("silent payment address not valid")
+	}
+	if wallet.ValidateAddress(tEncodeSilentPaymentAddress(t, "sp", 0, keys, false)) {
+		t.Fatalf("bech32 silent payment address valid")
+	}
+	if _, valid, err := wallet.EstimateSendTxFee(spAddrStr, 1e7, optimalFeeRate, false, false); err != nil || !valid {
+		t.Fatalf("EstimateSendTxFee error: valid = %t, err = %v", valid, err)
+	}
+
+	if _, err := wallet.Send(spAddrStr, 1e7, optimalFeeRate); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	spAddr := &silentPaymentAddress{scanKey: scanPriv.PubKey(), spendKey: spendPriv.PubKey()}
+	expScript, _ := silentPaymentOutputScript(spAddr, []*silentPaymentInput{{op: NewOutPoint(&txHash, 0), privKey: privKey}})
+	if txOut := node.sentRawTx.TxOut[0]; string(txOut.PkScript) != string(expScript) || txOut.Value != 1e7 {
+		t.Fatalf("wrong silent payment output")
+	}
+
+	// Other assets don't support silent payments.
+	wallet.cloneParams.AssetID = 2
+	if wallet.ValidateAddress(spAddrStr) {
+		t.Fatalf("silent payment address valid for another asset")
+	}
+}
@@ -37,6 +37,7 @@ type btcSPVWallet struct {
 	chainParams *chaincfg.Params
 	log         dex.Logger
 	dir         string
+	cfg         *WalletConfig
 
 	// Below fields are populated in Start.
 	loader      *wallet.Loader
@@ -108,6 +109,7 @@ func openSPVWallet(dir string, cfg *WalletConfig,
 
 	w := &btcSPVWallet{
 		dir:         dir,
+		cfg:         cfg,
 		chainParams: chainParams,
 		log:         log,
 	}
@@ -163,14 +165,25 @@ func (w *btcSPVWallet) Start() (SPVService, error) {
 	}
 	errCloser.Add(w.neutrinoDB.Close)
 
+	dnsSeeds := make([]string, 0, len(w.chainParams.DNSSeeds))
+	for _, seed := range w.chainParams.DNSSeeds {
+		dnsSeeds = append(dnsSeeds, seed.Host)
+	}
+	peerSettings, err := NewSPVPeerSettings(w.cfg, w.chainParams.DefaultPort, dnsSeeds)
+	if err != nil {
+		return nil, err
+	}
+
 	w.log.Debug("Starting neutrino chain service...")
 	w.cl, err = neutrino.NewChainService(neutrino.Config{
 		DataDir:       w.dir,
 		Database:      w.neutrinoDB,
 		ChainParams:   *w.chainParams,
 		PersistToDisk: true, // keep cfilter headers on disk for efficient rescanning
-		// AddPeers:      addPeers,
-		// ConnectPeers:  connectPeers,
+		AddPeers:      peerSettings.AddPeers,
+		ConnectPeers:  peerSettings.ConnectPeers,
+		Dialer:        peerSettings.Dialer,
+		NameResolver:  peerSettings.NameResolver,
 		// WARNING: PublishTransaction currently uses the entire duration
 		// because if an external bug, but even if the resolved, a typical
 		// inv/getdata round trip is ~4 seconds, so we set this so neutrino does
@@ -189,7 +202,10 @@ func (w *btcSPVWallet) Start() (SPVService, error) {
 	case wire.TestNet, wire.SimNet: // plain "wire.TestNet" is regnet!
 		defaultPeers = []string{"127.0.0.1:20575"}
 	}
-	peerManager := NewSPVPeerManager(&btcChainService{w.cl}, defaultPeers, w.dir, w.log, w.chainParams.DefaultPort)
+	if w.cfg.OnlyTrustedPeers {
+		defaultPeers = nil
+	}
+	peerManager := NewSPVPeerManager(&btcChainService{w.cl}, defaultPeers, w.dir, w.log, w.chainParams.DefaultPort, peerSettings.NameResolver)
 	w.peerManager = peerManager
 
 	w.chainClient = chain.NewNeutrinoClient(w.chainParams, w.cl)
@@ -15,8 +15,126 @@ import (
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/dex"
+	"github.com/decred/go-socks/socks"
 )
 
+// SPVPeerConfigOpts are the settings that control which peers an SPV wallet
+// connects to.
+var SPVPeerConfigOpts = []*asset.ConfigOption{
+	{
+		Key:         "trustedpeers",
+		DisplayName: "Trusted peers",
+		Description: "A comma-separated list of full node peers (host:port) that " +
+			"the wallet always connects to. Tor .onion addresses require a Tor proxy.",
+	},
+	{
+		Key:         "onlytrustedpeers",
+		DisplayName: "Only use trusted peers",
+		Description: "Do not discover peers using DNS seeds or from other peers. " +
+			"The wallet only connects to the trusted peers and peers that you add.",
+		IsBoolean: true,
+	},
+	{
+		Key:         "torproxy",
+		DisplayName: "Tor proxy",
+		Description: "The address of a Tor SOCKS5 proxy, e.g. 127.0.0.1:9050, " +
+			"used for all peer connections. With a proxy, peers must be IP or " +
+			".onion addresses, since host names are not resolved.",
+	},
+}
+
+// SPVPeerSettings are the neutrino chain service settings for the wallet's
+// peers.
+type SPVPeerSettings struct {
+	// ConnectPeers are the only peers that will be connected to. Peer
+	// discovery is disabled if there are any.
+	ConnectPeers []string
+	// AddPeers are connected to in addition to discovered peers.
+	AddPeers []string
+	// DisableDNSSeed is true if the NameResolver refuses to look up the DNS
+	// seeds, so the chain service can't discover peers through them.
+	DisableDNSSeed bool
+	// Dialer is nil unless a proxy is configured. NameResolver is nil unless a
+	// proxy is configured or DNS seeding is disabled.
+	Dialer       func(net.Addr) (net.Conn, error)
+	NameResolver func(string) ([]net.IP, error)
+}
+
+// NewSPVPeerSettings parses the peer settings of the wallet configuration.
+// dnsSeeds are the host names of the network's DNS seeds. Seeding is disabled
+// per chain service by refusing to resolve them, since neutrino's
+// DisableDNSSeed is a package variable shared by every network's wallet.
+func NewSPVPeerSettings(cfg *WalletConfig, defaultPort string, dnsSeeds []string) (*SPVPeerSettings, error) {
+	var peers []string
+	var hasOnion bool
+	for _, addr := range strings.Split(cfg.TrustedPeers, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+			addr = net.JoinHostPort(addr, defaultPort)
+		}
+		if strings.HasSuffix(host, ".onion") {
+			hasOnion = true
+		} else if cfg.TorProxy != "" && net.ParseIP(host) == nil {
+			return nil, fmt.Errorf("trusted peer %s must be an IP or .onion address when using a proxy", addr)
+		}
+		peers = append(peers, addr)
+	}
+	if hasOnion && cfg.TorProxy == "" {
+		return nil, errors.New("a Tor proxy is required for .onion peers")
+	}
+
+	s := new(SPVPeerSettings)
+	if cfg.OnlyTrustedPeers {
+		if len(peers) == 0 {
+			return nil, errors.New("no trusted peers specified")
+		}
+		s.ConnectPeers = peers
+		s.DisableDNSSeed = true
+	} else {
+		s.AddPeers = peers
+	}
+
+	if cfg.TorProxy != "" {
+		proxy := &socks.Proxy{Addr: cfg.TorProxy}
+		s.Dialer = func(addr net.Addr) (net.Conn, error) {
+			return proxy.Dial("tcp", addr.String())
+		}
+		// Resolving host names would bypass the proxy.
+		s.NameResolver = func(host string) ([]net.IP, error) {
+			if ip := net.ParseIP(host); ip != nil {
+				return []net.IP{ip}, nil
+			}
+			return nil, fmt.Errorf("not resolving %s when using a proxy", host)
+		}
+		// DNS seeds can't be queried through the proxy.
+		s.DisableDNSSeed = true
+	} else if s.DisableDNSSeed {
+		s.NameResolver = net.LookupIP
+	}
+	if s.DisableDNSSeed {
+		s.NameResolver = refuseDNSSeeds(s.NameResolver, dnsSeeds)
+	}
+	return s, nil
+}
+
+// refuseDNSSeeds wraps the name resolver to refuse looking up the DNS seeds,
+// including the service-filtered subdomains (e.g. x49.seed.example.com).
+func refuseDNSSeeds(resolve func(string) ([]net.IP, error), dnsSeeds []string) func(string) ([]net.IP, error) {
+	return func(host string) ([]net.IP, error) {
+		for _, seed := range dnsSeeds {
+			if host == seed || strings.HasSuffix(host, "."+seed) {
+				return nil, fmt.Errorf("DNS seeding is disabled, not resolving %s", host)
+			}
+		}
+		return resolve(host)
+	}
+}
+
 type peerSource uint16
 
 const (
@@ -61,11 +179,19 @@ type SPVPeerManager struct {
 
 	defaultPort string
 
+	lookupIP func(string) ([]net.IP, error)
+
 	log dex.Logger
 }
 
-// NewSPVPeerManager creates a new SPVPeerManager.
-func NewSPVPeerManager(cs PeerManagerChainService, defaultPeers []string, dir string, log dex.Logger, defaultPort string) *SPVPeerManager {
+// NewSPVPeerManager creates a new SPVPeerManager. If lookupIP is nil, host
+// names are resolved with net.LookupIP.
+func NewSPVPeerManager(cs PeerManagerChainService, defaultPeers []string, dir string, log dex.Logger, defaultPort string,
+	lookupIP func(string) ([]net.IP, error)) *SPVPeerManager {
+
+	if lookupIP == nil {
+		lookupIP = net.LookupIP
+	}
 	return &SPVPeerManager{
 		cs:                 cs,
 		defaultPeers:       defaultPeers,
@@ -73,6 +199,7 @@ func NewSPVPeerManager(cs PeerManagerChainService, defaultPeers []string, dir st
 		savedPeersFilePath: filepath.Join(dir, "dexc-peers.json"), // peers.json is used by neutrino
 		log:                log,
 		defaultPort:        defaultPort,
+		lookupIP:           lookupIP,
 	}
 }
 
@@ -141,7 +268,7 @@ func (s *SPVPeerManager) resolveAddress(addr string) (string, error) {
 		return addr, nil
 	}
 
-	ips, err := net.LookupIP(host)
+	ips, err := s.lookupIP(host)
 	if err != nil {
 		return "", err
 	}
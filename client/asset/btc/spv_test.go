@@ -913,3 +913,86 @@ func TestGetBlockHeader(t *testing.T) {
 	}
 	node.mainchain = prevMainchain // clean up
 }
+
+func TestNewSPVPeerSettings(t *testing.T) {
+	const onion = "abcdefghijklmnop.onion"
+	tests := []struct {
+		name         string
+		cfg          *WalletConfig
+		wantErr      bool
+		connectPeers []string
+		addPeers     []string
+		noDNSSeed    bool
+		proxied      bool
+	}{{
+		name: "default",
+		cfg:  &WalletConfig{},
+	}, {
+		name:     "trusted peers",
+		cfg:      &WalletConfig{TrustedPeers: "1.2.3.4, node.example.com:18333"},
+		addPeers: []string{"1.2.3.4:8333", "node.example.com:18333"},
+	}, {
+		name:         "only trusted peers",
+		cfg:          &WalletConfig{TrustedPeers: "1.2.3.4", OnlyTrustedPeers: true},
+		connectPeers: []string{"1.2.3.4:8333"},
+		noDNSSeed:    true,
+	}, {
+		name:    "only trusted without peers",
+		cfg:     &WalletConfig{OnlyTrustedPeers: true},
+		wantErr: true,
+	}, {
+		name:    "onion without proxy",
+		cfg:     &WalletConfig{TrustedPeers: onion},
+		wantErr: true,
+	}, {
+		name:         "onion only",
+		cfg:          &WalletConfig{TrustedPeers: onion, OnlyTrustedPeers: true, TorProxy: "127.0.0.1:9050"},
+		connectPeers: []string{onion + ":8333"},
+		noDNSSeed:    true,
+		proxied:      true,
+	}, {
+		name:    "host name with proxy",
+		cfg:     &WalletConfig{TrustedPeers: "node.example.com", TorProxy: "127.0.0.1:9050"},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		s, err := NewSPVPeerSettings(tt.cfg, "8333", []string{"seed.example.com"})
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: no error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if fmt.Sprint(s.ConnectPeers) != fmt.Sprint(tt.connectPeers) {
+			t.Fatalf("%s: wrong connect peers %v", tt.name, s.ConnectPeers)
+		}
+		if fmt.Sprint(s.AddPeers) != fmt.Sprint(tt.addPeers) {
+			t.Fatalf("%s: wrong add peers %v", tt.name, s.AddPeers)
+		}
+		if s.DisableDNSSeed != tt.noDNSSeed {
+			t.Fatalf("%s: wrong DisableDNSSeed %t", tt.name, s.DisableDNSSeed)
+		}
+		if (s.Dialer != nil) != tt.proxied || (s.NameResolver != nil) != (tt.proxied || tt.noDNSSeed) {
+			t.Fatalf("%s: wrong proxy settings", tt.name)
+		}
+		if tt.noDNSSeed {
+			for _, seed := range []string{"seed.example.com", "x49.seed.example.com"} {
+				if _, err := s.NameResolver(seed); err == nil {
+					t.Fatalf("%s: DNS seed %s resolved", tt.name, seed)
+				}
+			}
+			if ips, err := s.NameResolver("1.2.3.4"); err != nil || len(ips) != 1 {
+				t.Fatalf("%s: IP not resolved: %v", tt.name, err)
+			}
+		}
+		if tt.proxied {
+			if _, err := s.NameResolver("node.example.com"); err == nil {
+				t.Fatalf("%s: host name resolved through proxy", tt.name)
+			}
+		}
+	}
+}
@@ -34,6 +34,7 @@ import (
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/config"
 	dexbtc "decred.org/dcrdex/dex/networks/btc"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcjson"
@@ -244,6 +245,20 @@ func (w *spvWallet) Reconfigure(cfg *asset.WalletConfig, currentAddress string)
 		return false, errors.New("wallet not found")
 	}
 
+	// Peer and full node settings are applied when the wallet is created.
+	newCfg := new(WalletConfig)
+	if err := config.Unmapify(cfg.Settings, newCfg); err != nil {
+		return false, err
+	}
+	if _, err := NewSPVPeerSettings(newCfg, w.chainParams.DefaultPort, nil); err != nil {
+		return false, err
+	}
+	if newCfg.TrustedPeers != w.cfg.TrustedPeers || newCfg.OnlyTrustedPeers != w.cfg.OnlyTrustedPeers ||
+		newCfg.TorProxy != w.cfg.TorProxy || newCfg.FullNodeRPCBind != w.cfg.FullNodeRPCBind ||
+		newCfg.FullNodeRPCUser != w.cfg.FullNodeRPCUser || newCfg.FullNodeRPCPass != w.cfg.FullNodeRPCPass {
+		return true, nil
+	}
+
 	return false, nil
 }
 
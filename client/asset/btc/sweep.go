@@ -0,0 +1,235 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// sweepUTXO is an unspent output paying to an external key.
+type sweepUTXO struct {
+	op       OutPoint
+	value    uint64
+	pkScript []byte
+}
+
+// utxoScanner is implemented by wallet backends that can find the unspent
+// outputs paying to any script, not only the wallet's own.
+type utxoScanner interface {
+	scanUTXOs(pkScripts [][]byte) ([]*sweepUTXO, error)
+}
+
+// sweepKey is an external private key and the scripts that pay to it.
+type sweepKey struct {
+	privKey *btcec.PrivateKey
+	pubKey  []byte
+	// p2wpkh is the P2WPKH pkScript, which is also the redeem script of the
+	// P2SH-P2WPKH pkScript. nil if the wallet isn't segwit or the key is
+	// uncompressed.
+	p2wpkh    []byte
+	pkScripts [][]byte
+}
+
+// decodeSweepKey decodes the WIF-encoded private key and prepares the scripts
+// that can pay to it.
+func (btc *baseWallet) decodeSweepKey(privKey string) (*sweepKey, error) {
+	wif, err := btcutil.DecodeWIF(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding private key: %w", err)
+	}
+	if !wif.IsForNet(btc.chainParams) {
+		return nil, fmt.Errorf("private key is not for %s", btc.chainParams.Name)
+	}
+	pubKey := wif.SerializePubKey()
+	pkh := btcutil.Hash160(pubKey)
+	p2pkhAddr, err := btcutil.NewAddressPubKeyHash(pkh, btc.chainParams)
+	if err != nil {
+		return nil, err
+	}
+	p2pkh, err := txscript.PayToAddrScript(p2pkhAddr)
+	if err != nil {
+		return nil, err
+	}
+	k := &sweepKey{
+		privKey:   wif.PrivKey,
+		pubKey:    pubKey,
+		pkScripts: [][]byte{p2pkh},
+	}
+	// Segwit outputs can only pay to compressed keys.
+	if btc.segwit && wif.CompressPubKey {
+		p2wpkhAddr, err := btcutil.NewAddressWitnessPubKeyHash(pkh, btc.chainParams)
+		if err != nil {
+			return nil, err
+		}
+		if k.p2wpkh, err = txscript.PayToAddrScript(p2wpkhAddr); err != nil {
+			return nil, err
+		}
+		p2shAddr, err := btcutil.NewAddressScriptHash(k.p2wpkh, btc.chainParams)
+		if err != nil {
+			return nil, err
+		}
+		p2sh, err := txscript.PayToAddrScript(p2shAddr)
+		if err != nil {
+			return nil, err
+		}
+		k.pkScripts = append(k.pkScripts, k.p2wpkh, p2sh)
+	}
+	return k, nil
+}
+
+// sweepUTXOs finds the unspent outputs paying to the external key.
+func (btc *baseWallet) sweepUTXOs(k *sweepKey) ([]*sweepUTXO, error) {
+	scanner, is := btc.node.(utxoScanner)
+	if !is {
+		return nil, errors.New("this wallet type cannot search for the funds of a private key")
+	}
+	utxos, err := scanner.scanUTXOs(k.pkScripts)
+	if err != nil {
+		return nil, fmt.Errorf("error searching for funds: %w", err)
+	}
+	if len(utxos) == 0 {
+		return nil, errors.New("no funds found for the private key")
+	}
+	return utxos, nil
+}
+
+// PrivateKeyFunds finds the confirmed unspent funds controlled by the
+// WIF-encoded private key. Only RPC wallets can search for the funds.
+// Part of the asset.KeySweeper interface.
+func (btc *baseWallet) PrivateKeyFunds(privKey string) (uint64, error) {
+	k, err := btc.decodeSweepKey(privKey)
+	if err != nil {
+		return 0, err
+	}
+	defer k.privKey.Zero()
+	utxos, err := btc.sweepUTXOs(k)
+	if err != nil {
+		return 0, err
+	}
+	var total uint64
+	for _, u := range utxos {
+		total += u.value
+	}
+	return total, nil
+}
+
+// SweepPrivateKey sends all of the confirmed funds controlled by the
+// WIF-encoded private key to a new address of the wallet in a single
+// transaction. Only RPC wallets can search for the funds.
+// Part of the asset.KeySweeper interface.
+func (btc *baseWallet) SweepPrivateKey(privKey string, feeRate uint64) (string, uint64, error) {
+	k, err := btc.decodeSweepKey(privKey)
+	if err != nil {
+		return "", 0, err
+	}
+	defer k.privKey.Zero()
+	utxos, err := btc.sweepUTXOs(k)
+	if err != nil {
+		return "", 0, err
+	}
+	feeRate = btc.feeRateWithFallback(feeRate)
+
+	addr, err := btc.node.ExternalAddress()
+	if err != nil {
+		return "", 0, fmt.Errorf("error getting deposit address: %w", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return "", 0, fmt.Errorf("error creating deposit script: %w", err)
+	}
+
+	msgTx := wire.NewMsgTx(btc.txVersion())
+	var totalIn uint64
+	for _, u := range utxos {
+		txIn := wire.NewTxIn(wire.NewOutPoint(&u.op.TxHash, u.op.Vout), nil, nil)
+		if btc.rbf() {
+			txIn.Sequence = rbfSequence
+		}
+		msgTx.AddTxIn(txIn)
+		totalIn += u.value
+	}
+	txOut := wire.NewTxOut(int64(totalIn), pkScript)
+	msgTx.AddTxOut(txOut)
+
+	// Sign once to measure the size. Signatures can vary by a byte, so pad
+	// the size by one byte per input.
+	if err := btc.signSweepTx(msgTx, utxos, k); err != nil {
+		return "", 0, err
+	}
+	fees := feeRate * (btc.calcTxSize(msgTx) + uint64(len(utxos)))
+	if fees >= totalIn {
+		return "", 0, fmt.Errorf("funds of %d are not enough to pay fees of %d", totalIn, fees)
+	}
+	txOut.Value = int64(totalIn - fees)
+	if btc.IsDust(txOut, feeRate) {
+		return "", 0, fmt.Errorf("swept output of %d would be dust", txOut.Value)
+	}
+	if err := btc.signSweepTx(msgTx, utxos, k); err != nil {
+		return "", 0, err
+	}
+
+	txHash, err := btc.broadcastTx(msgTx)
+	if err != nil {
+		return "", 0, err
+	}
+	received := uint64(txOut.Value)
+	btc.addTxToHistory(&asset.WalletTransaction{
+		Type:   asset.Receive,
+		ID:     txHash.String(),
+		Amount: received,
+		Fees:   fees,
+	}, txHash, true)
+
+	return txHash.String(), received, nil
+}
+
+// signSweepTx signs the inputs spending the external key's outputs.
+func (btc *baseWallet) signSweepTx(msgTx *wire.MsgTx, utxos []*sweepUTXO, k *sweepKey) error {
+	vals := make([]int64, len(utxos))
+	prevScripts := make([][]byte, len(utxos))
+	for i, u := range utxos {
+		vals[i] = int64(u.value)
+		prevScripts[i] = u.pkScript
+	}
+	sigHashes := txscript.NewTxSigHashes(msgTx, new(txscript.CannedPrevOutputFetcher))
+	for i, u := range utxos {
+		txIn := msgTx.TxIn[i]
+		switch txscript.GetScriptClass(u.pkScript) {
+		case txscript.PubKeyHashTy:
+			sig, err := btc.signNonSegwit(msgTx, i, u.pkScript, txscript.SigHashAll, k.privKey, vals, prevScripts)
+			if err != nil {
+				return fmt.Errorf("error signing input %d: %w", i, err)
+			}
+			txIn.SignatureScript, err = txscript.NewScriptBuilder().AddData(sig).AddData(k.pubKey).Script()
+			if err != nil {
+				return err
+			}
+		case txscript.WitnessV0PubKeyHashTy, txscript.ScriptHashTy:
+			if k.p2wpkh == nil {
+				return fmt.Errorf("cannot spend segwit output %s", u.op)
+			}
+			sig, err := txscript.RawTxInWitnessSignature(msgTx, sigHashes, i, int64(u.value), k.p2wpkh, txscript.SigHashAll, k.privKey)
+			if err != nil {
+				return fmt.Errorf("error signing input %d: %w", i, err)
+			}
+			txIn.Witness = wire.TxWitness{sig, k.pubKey}
+			if txscript.IsPayToScriptHash(u.pkScript) {
+				txIn.SignatureScript, err = txscript.NewScriptBuilder().AddData(k.p2wpkh).Script()
+				if err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unexpected script for output %s", u.op)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func TestSweepPrivateKey(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+	node.newAddress = tP2WPKHAddr
+
+	privKey, _ := btcec.NewPrivateKey()
+	wif, _ := btcutil.NewWIF(privKey, &chaincfg.MainNetParams, true)
+	k, err := wallet.decodeSweepKey(wif.String())
+	if err != nil {
+		t.Fatalf("decodeSweepKey error: %v", err)
+	}
+	if len(k.pkScripts) != 3 {
+		t.Fatalf("expected 3 scripts, got %d", len(k.pkScripts))
+	}
+
+	// One output of each script type.
+	node.scanUnspents = make([]*sweepUTXO, 0, len(k.pkScripts))
+	for i, pkScript := range k.pkScripts {
+		node.scanUnspents = append(node.scanUnspents, &sweepUTXO{
+			op:       NewOutPoint(tTxHash, uint32(i)),
+			value:    1e6,
+			pkScript: pkScript,
+		})
+	}
+
+	funds, err := wallet.PrivateKeyFunds(wif.String())
+	if err != nil {
+		t.Fatalf("PrivateKeyFunds error: %v", err)
+	}
+	if funds != 3e6 {
+		t.Fatalf("wrong funds %d", funds)
+	}
+	if len(node.scannedScripts) != 3 {
+		t.Fatalf("wrong scanned descriptors %v", node.scannedScripts)
+	}
+
+	const feeRate = 10
+	txID, received, err := wallet.SweepPrivateKey(wif.String(), feeRate)
+	if err != nil {
+		t.Fatalf("SweepPrivateKey error: %v", err)
+	}
+	tx := node.sentRawTx
+	if tx == nil || tx.TxHash().String() != txID {
+		t.Fatalf("sweep tx not sent")
+	}
+	if len(tx.TxIn) != 3 || len(tx.TxOut) != 1 || uint64(tx.TxOut[0].Value) != received {
+		t.Fatalf("wrong sweep tx")
+	}
+	fees := 3e6 - received
+	if vSize := wallet.calcTxSize(tx); fees < vSize*feeRate || fees > (vSize+6)*feeRate {
+		t.Fatalf("wrong fees %d for size %d", fees, vSize)
+	}
+
+	// Check the signatures.
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for _, u := range node.scanUnspents {
+		prevOuts.AddPrevOut(wire.OutPoint{Hash: u.op.TxHash, Index: u.op.Vout}, wire.NewTxOut(int64(u.value), u.pkScript))
+	}
+	sigHashes := txscript.NewTxSigHashes(tx, prevOuts)
+	for i, u := range node.scanUnspents {
+		vm, err := txscript.NewEngine(u.pkScript, tx, i, txscript.StandardVerifyFlags, nil, sigHashes, int64(u.value), prevOuts)
+		if err != nil {
+			t.Fatalf("NewEngine error: %v", err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Fatalf("input %d not valid: %v", i, err)
+		}
+	}
+
+	// Key for another network.
+	testnetWIF, _ := btcutil.NewWIF(privKey, &chaincfg.TestNet3Params, true)
+	if _, err := wallet.PrivateKeyFunds(testnetWIF.String()); err == nil {
+		t.Fatalf("no error for testnet key")
+	}
+
+	// Nothing to sweep.
+	node.scanUnspents = nil
+	if _, _, err := wallet.SweepPrivateKey(wif.String(), feeRate); err == nil {
+		t.Fatalf("no error for no funds")
+	}
+
+	// Too little to pay the fees.
+	node.scanUnspents = []*sweepUTXO{{op: NewOutPoint(tTxHash, 0), value: 500, pkScript: k.pkScripts[0]}}
+	if _, _, err := wallet.SweepPrivateKey(wif.String(), feeRate); err == nil {
+		t.Fatalf("no error for dust")
+	}
+
+	node.scanTxOutErr = errors.New("test error")
+	if _, err := wallet.PrivateKeyFunds(wif.String()); err == nil {
+		t.Fatalf("no error for scan error")
+	}
+}
@@ -4,6 +4,8 @@
 package btc
 
 import (
+	"encoding/json"
+
 	"decred.org/dcrdex/dex"
 )
 
@@ -34,6 +36,9 @@ type ListUnspentResult struct {
 	Spendable     bool      `json:"spendable"`
 	Solvable      bool      `json:"solvable"`
 	SafePtr       *bool     `json:"safe"`
+	// TokenData is set by Bitcoin Cash Node for outputs that carry a
+	// CashToken.
+	TokenData json.RawMessage `json:"tokenData,omitempty"`
 }
 
 func (l *ListUnspentResult) Safe() bool {
@@ -144,6 +149,31 @@ type GetAddressInfoResult struct {
 	HDMasterFingerprint string `json:"hdmasterfingerprint"` // e.g. "b940190e"
 }
 
+type getDescriptorInfoResult struct {
+	Descriptor string `json:"descriptor"` // with checksum
+}
+
+// importDescriptorRequest is a request for the importdescriptors RPC.
+type importDescriptorRequest struct {
+	Descriptor string `json:"desc"`
+	// Timestamp is the unix time to rescan from, or "now".
+	Timestamp any    `json:"timestamp"`
+	Label     string `json:"label,omitempty"`
+	// Active makes it the descriptor for new addresses of its type.
+	Active bool `json:"active,omitempty"`
+	// Internal is set for change address descriptors.
+	Internal bool `json:"internal,omitempty"`
+	// Range is the range of a ranged descriptor's indexes to watch.
+	Range []int64 `json:"range,omitempty"`
+}
+
+type importDescriptorResult struct {
+	Success bool `json:"success"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
 type listDescriptorsResult struct {
 	WalletName  string `json:"wallet_name"`
 	Descriptors []*struct {
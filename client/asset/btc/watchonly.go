@@ -0,0 +1,300 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex/config"
+	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/decred/dcrd/dcrjson/v4"
+)
+
+const (
+	// WalletTypeWatchOnly is the type of a watch-only wallet. BTC clones that
+	// support it register WatchOnlyWalletDefinition.
+	WalletTypeWatchOnly = "watchOnlyRPC"
+	// watchOnlyAddrRange is the number of addresses of each branch of the
+	// extended public key that the node watches. The node extends the range
+	// as addresses are used.
+	watchOnlyAddrRange = 1000
+)
+
+// birthdayOpt is the date from which the node rescans for the transactions
+// of imported extended public keys.
+var birthdayOpt = &asset.ConfigOption{
+	Key:         "birthday",
+	DisplayName: "Wallet birthday",
+	Description: "The date of the account's first transaction. The node " +
+		"rescans the blockchain from this date when the key is imported. " +
+		"If not set, the whole blockchain is rescanned.",
+	IsDate:   true,
+	MaxValue: "now",
+}
+
+// WatchOnlyConfigOpts are the settings of a watch-only wallet, in addition to
+// the RPC and common settings.
+var WatchOnlyConfigOpts = []*asset.ConfigOption{
+	{
+		Key:         "xpub",
+		DisplayName: "Extended public key",
+		Description: "The extended public key of the native segwit (BIP84) " +
+			"account to watch. xpub, tpub, zpub, and vpub keys are accepted. " +
+			"Transactions are signed with the device that holds the private " +
+			"keys, using PSBTs.",
+		Required: true,
+	},
+	birthdayOpt,
+}
+
+// WatchOnlyWalletDefinition is the definition of a watch-only wallet for an
+// asset whose node supports segwit and descriptor wallets. The asset's driver
+// opens the wallet type with WatchOnlyWallet. extraOpts are added to the RPC,
+// common, and watch-only settings.
+func WatchOnlyWalletDefinition(name, symbol /* upper-case */, rpcPort, nodeName string, extraOpts ...*asset.ConfigOption) *asset.WalletDefinition {
+	opts := append(RPCConfigOpts(name, rpcPort), CommonConfigOpts(symbol, false)...)
+	opts = append(opts, WatchOnlyConfigOpts...)
+	return &asset.WalletDefinition{
+		Type:              WalletTypeWatchOnly,
+		Tab:               "Watch-only",
+		Description:       "Watch an extended public key with " + nodeName + "d",
+		DefaultConfigPath: dexbtc.SystemConfigPath(nodeName),
+		ConfigOpts:        append(opts, extraOpts...),
+	}
+}
+
+// watchOnlyConfig is the configuration of a watch-only wallet.
+type watchOnlyConfig struct {
+	XPub string `ini:"xpub"`
+	// Birthday is the unix time of the account's first transaction.
+	Birthday int64 `ini:"birthday"`
+	// XPubs and Threshold are set for multisig wallets instead of XPub.
+	XPubs     string `ini:"multisigxpubs"`
+	Threshold int    `ini:"multisigthreshold"`
+}
+
+// parseWatchOnlyXPub checks that the extended key is public, and encodes it
+// with the network's version bytes, as the node expects in descriptors. SLIP
+// 132 keys, e.g. zpub, are re-encoded the same way.
+func parseWatchOnlyXPub(xpub string, chainParams *chaincfg.Params) (string, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return "", fmt.Errorf("invalid extended public key: %w", err)
+	}
+	if key.IsPrivate() {
+		return "", errors.New("extended key is private. provide the public key")
+	}
+	key, err = key.CloneWithVersion(chainParams.HDPublicKeyID[:])
+	if err != nil {
+		return "", fmt.Errorf("error encoding extended public key: %w", err)
+	}
+	return key.String(), nil
+}
+
+// normalize checks the extended keys and encodes them for the network. The
+// cosigners' keys are sorted, so that the descriptors don't depend on the
+// order in which they were entered.
+func (cfg *watchOnlyConfig) normalize(chainParams *chaincfg.Params) (err error) {
+	if !cfg.isMultisig() {
+		cfg.XPub, err = parseWatchOnlyXPub(cfg.XPub, chainParams)
+		return err
+	}
+	if cfg.XPub != "" {
+		return errors.New("a multisig wallet takes the cosigners' extended public keys, not xpub")
+	}
+	var xpubs []string
+	for _, xpub := range strings.Split(cfg.XPubs, ",") {
+		if xpub = strings.TrimSpace(xpub); xpub == "" {
+			continue
+		}
+		if xpub, err = parseWatchOnlyXPub(xpub, chainParams); err != nil {
+			return err
+		}
+		for _, k := range xpubs {
+			if k == xpub {
+				return fmt.Errorf("duplicate cosigner key %s", xpub)
+			}
+		}
+		xpubs = append(xpubs, xpub)
+	}
+	if len(xpubs) < 2 || len(xpubs) > maxMultisigKeys {
+		return fmt.Errorf("a multisig wallet needs 2 to %d cosigner keys, got %d", maxMultisigKeys, len(xpubs))
+	}
+	if cfg.Threshold < 1 || cfg.Threshold > len(xpubs) {
+		return fmt.Errorf("invalid multisig threshold %d for %d keys", cfg.Threshold, len(xpubs))
+	}
+	sort.Strings(xpubs)
+	cfg.XPubs = strings.Join(xpubs, ",")
+	return nil
+}
+
+func (cfg *watchOnlyConfig) isMultisig() bool {
+	return cfg.XPubs != ""
+}
+
+// descriptor is the output descriptor, without a checksum, of the receiving
+// (branch 0) or change (branch 1) addresses.
+func (cfg *watchOnlyConfig) descriptor(branch int) string {
+	if !cfg.isMultisig() {
+		return fmt.Sprintf("wpkh(%s/%d/*)", cfg.XPub, branch)
+	}
+	keys := strings.Split(cfg.XPubs, ",")
+	for i, xpub := range keys {
+		keys[i] = fmt.Sprintf("%s/%d/*", xpub, branch)
+	}
+	return fmt.Sprintf("wsh(sortedmulti(%d,%s))", cfg.Threshold, strings.Join(keys, ","))
+}
+
+// WatchOnlyWallet creates a wallet that watches the account of an extended
+// public key in a watch-only descriptor wallet of a full node. The node wallet
+// is created if it doesn't exist, and the key's descriptors are imported. The
+// wallet reports the account's balance and history, and can receive. Any
+// transaction that must be signed, e.g. a send or a swap, is presented as a
+// PSBT for the device that holds the keys. Fidelity bonds are not supported.
+// A BTC clone can use WatchOnlyWallet if its node supports descriptor
+// wallets.
+func WatchOnlyWallet(cfg *BTCCloneCFG) (*ExchangeWalletNoAuth, error) {
+	var woCfg watchOnlyConfig
+	if err := config.Unmapify(cfg.WalletCFG.Settings, &woCfg); err != nil {
+		return nil, fmt.Errorf("error parsing watch-only wallet settings: %w", err)
+	}
+	if woCfg.isMultisig() {
+		return nil, errors.New("use the multisig wallet type for cosigner keys")
+	}
+	iw, err := newWatchOnlyRPCWallet(cfg, &woCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ExchangeWalletNoAuth{iw}, nil
+}
+
+// newWatchOnlyRPCWallet creates an RPC wallet that signs externally, for the
+// descriptors of the watch-only configuration.
+func newWatchOnlyRPCWallet(cfg *BTCCloneCFG, woCfg *watchOnlyConfig) (*intermediaryWallet, error) {
+	if !cfg.Segwit {
+		return nil, errors.New("watch-only wallets require segwit")
+	}
+	if err := woCfg.normalize(cfg.ChainParams); err != nil {
+		return nil, err
+	}
+
+	clientCfg, client, err := parseRPCWalletConfig(cfg.WalletCFG.Settings, cfg.Symbol, cfg.Network, cfg.Ports, cfg.SingularWallet)
+	if err != nil {
+		return nil, err
+	}
+	if clientCfg.WalletName == "" {
+		return nil, errors.New("a wallet name is required for a watch-only wallet")
+	}
+	// The device signs all transactions.
+	clientCfg.ExternalSigning = true
+
+	iw, err := newRPCWallet(client, cfg, clientCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s watch-only wallet: %w", cfg.Symbol, err)
+	}
+	pn := iw.node.(*psbtNode)
+	rpc := pn.Wallet.(*rpcClient)
+	rpc.watchOnlyCfg = woCfg
+	// The node adds the derivation paths and scripts that the signers need.
+	iw.signer.update = rpc.processPSBT
+	if woCfg.isMultisig() {
+		n := len(strings.Split(woCfg.XPubs, ","))
+		pn.multisig = &multisigParams{threshold: woCfg.Threshold, keys: n}
+		iw.signer.multisig = true
+	}
+	return iw, nil
+}
+
+// isWalletNotFoundErr is true if the error is a bitcoind RPC error for a
+// wallet that doesn't exist or isn't loaded.
+func isWalletNotFoundErr(err error) bool {
+	var rpcErr *dcrjson.RPCError
+	return errors.As(err, &rpcErr) && int(rpcErr.Code) == int(btcjson.ErrRPCWalletNotFound)
+}
+
+// loadOrCreateWatchOnlyWallet loads the configured node wallet, or creates it
+// as a blank descriptor wallet with private keys disabled.
+func (wc *rpcClient) loadOrCreateWatchOnlyWallet() error {
+	name := wc.rpcConfig.WalletName
+	if err := wc.call(methodLoadWallet, anylist{name}, nil); err == nil {
+		return nil
+	}
+	const disablePrivateKeys, blank, passphrase, avoidReuse, descriptors = true, true, "", false, true
+	args := anylist{name, disablePrivateKeys, blank, passphrase, avoidReuse, descriptors}
+	if err := wc.call(methodCreateWallet, args, nil); err != nil {
+		return fmt.Errorf("error creating watch-only wallet %q: %w", name, err)
+	}
+	wc.log.Infof("Created watch-only wallet %q", name)
+	return nil
+}
+
+// importXPub imports the receiving and change descriptors of the configured
+// extended public key or multisig keys, unless they are already imported. The descriptors are
+// active, so the node derives new addresses from them.
+func (wc *rpcClient) importXPub() error {
+	if !wc.watchOnly {
+		return errors.New("a watch-only wallet requires a descriptor wallet with private keys disabled")
+	}
+	imported, err := wc.listDescriptors(false)
+	if err != nil {
+		return fmt.Errorf("listdescriptors error: %w", err)
+	}
+	has := func(desc string) bool {
+		for _, d := range imported.Descriptors {
+			if strings.Split(d.Descriptor, "#")[0] == desc {
+				return true
+			}
+		}
+		return false
+	}
+	reqs := make([]*importDescriptorRequest, 0, 2)
+	for _, internal := range []bool{false, true} {
+		branch := 0
+		if internal {
+			branch = 1
+		}
+		desc := wc.watchOnlyCfg.descriptor(branch)
+		if has(desc) {
+			continue
+		}
+		descInfo := new(getDescriptorInfoResult)
+		if err := wc.call(methodGetDescriptorInfo, anylist{desc}, descInfo); err != nil {
+			return fmt.Errorf("getdescriptorinfo error: %w", err)
+		}
+		reqs = append(reqs, &importDescriptorRequest{
+			Descriptor: descInfo.Descriptor,
+			Timestamp:  wc.watchOnlyCfg.Birthday,
+			Active:     true,
+			Internal:   internal,
+			Range:      []int64{0, watchOnlyAddrRange - 1},
+		})
+	}
+	if len(reqs) == 0 {
+		return nil
+	}
+	wc.log.Infof("Importing extended public key. The node will rescan the blockchain, which may take a while.")
+	var res []*importDescriptorResult
+	if err := wc.call(methodImportDescriptors, anylist{reqs}, &res); err != nil {
+		return fmt.Errorf("importdescriptors error: %w", err)
+	}
+	if len(res) != len(reqs) {
+		return fmt.Errorf("expected %d importdescriptors results, got %d", len(reqs), len(res))
+	}
+	for i, r := range res {
+		if !r.Success {
+			if r.Error != nil {
+				return fmt.Errorf("error importing %s: %s", reqs[i].Descriptor, r.Error.Message)
+			}
+			return fmt.Errorf("error importing %s", reqs[i].Descriptor)
+		}
+	}
+	return nil
+}
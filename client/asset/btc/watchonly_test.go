@@ -0,0 +1,115 @@
+//go:build !spvlive && !harness
+
+package btc
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestParseWatchOnlyXPub(t *testing.T) {
+	master, _ := hdkeychain.NewMaster(randBytes(32), &chaincfg.MainNetParams)
+	acct, _ := master.Derive(hdkeychain.HardenedKeyStart + 84)
+	xpub, _ := acct.Neuter()
+	// zpub version bytes.
+	zpub, _ := xpub.CloneWithVersion([]byte{0x04, 0xb2, 0x47, 0x46})
+
+	for _, k := range []*hdkeychain.ExtendedKey{xpub, zpub} {
+		s, err := parseWatchOnlyXPub(k.String(), &chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatalf("error parsing %s: %v", k, err)
+		}
+		if s != xpub.String() {
+			t.Fatalf("wrong encoding %s, expected %s", s, xpub)
+		}
+	}
+
+	if _, err := parseWatchOnlyXPub(acct.String(), &chaincfg.MainNetParams); err == nil {
+		t.Fatalf("no error for private key")
+	}
+	if _, err := parseWatchOnlyXPub("xpubnonsense", &chaincfg.MainNetParams); err == nil {
+		t.Fatalf("no error for invalid key")
+	}
+}
+
+func TestImportXPub(t *testing.T) {
+	wallet, node, shutdown := tNewWallet(true, walletTypeRPC)
+	defer shutdown()
+
+	const xpub = "xpub6CatWdiZiodmUeTDp8LT5or8nmbKNcuyvz7WyksVFkKB4RHwCD3XyuvPEbvqAQY3rAPshWcMLoP2fMFMKHPJ4ZeZXYVUhLv1VMrjPC7PW6V"
+	rpc := wallet.node.(*rpcClient)
+	rpc.watchOnlyCfg = &watchOnlyConfig{XPub: xpub, Birthday: 1700000000}
+
+	// Wallets with private keys can't watch the key.
+	if err := rpc.importXPub(); err == nil {
+		t.Fatalf("no error for a wallet with private keys")
+	}
+
+	rpc.watchOnly = true
+	if err := rpc.importXPub(); err != nil {
+		t.Fatalf("importXPub error: %v", err)
+	}
+	expDescs := []string{
+		"wpkh(" + xpub + "/0/*)#checksum",
+		"wpkh(" + xpub + "/1/*)#checksum",
+	}
+	checkDescs := func() {
+		t.Helper()
+		if len(node.importedDescriptors) != len(expDescs) {
+			t.Fatalf("wrong imported descriptors %v", node.importedDescriptors)
+		}
+		for i, desc := range expDescs {
+			if node.importedDescriptors[i] != desc {
+				t.Fatalf("wrong descriptor %s, expected %s", node.importedDescriptors[i], desc)
+			}
+		}
+	}
+	checkDescs()
+
+	// Already imported.
+	if err := rpc.importXPub(); err != nil {
+		t.Fatalf("importXPub error for imported descriptors: %v", err)
+	}
+	checkDescs()
+
+	node.importedDescriptors = nil
+	node.importDescriptorErr = "rescan failed"
+	if err := rpc.importXPub(); err == nil {
+		t.Fatalf("no error for failed import")
+	}
+}
+
+func TestMultisigDescriptors(t *testing.T) {
+	xpubs := make([]string, 3)
+	for i := range xpubs {
+		master, _ := hdkeychain.NewMaster(randBytes(32), &chaincfg.MainNetParams)
+		xpub, _ := master.Neuter()
+		xpubs[i] = xpub.String()
+	}
+	cfg := &watchOnlyConfig{XPubs: xpubs[2] + ", " + xpubs[0] + "," + xpubs[1], Threshold: 2}
+	if err := cfg.normalize(&chaincfg.MainNetParams); err != nil {
+		t.Fatalf("normalize error: %v", err)
+	}
+	sorted := append([]string(nil), xpubs...)
+	sort.Strings(sorted)
+	expDesc := fmt.Sprintf("wsh(sortedmulti(2,%s/1/*,%s/1/*,%s/1/*))", sorted[0], sorted[1], sorted[2])
+	if desc := cfg.descriptor(1); desc != expDesc {
+		t.Fatalf("wrong descriptor %s, expected %s", desc, expDesc)
+	}
+
+	for _, bad := range []*watchOnlyConfig{
+		{XPubs: xpubs[0], Threshold: 1},                                  // one key
+		{XPubs: xpubs[0] + "," + xpubs[1], Threshold: 3},                 // threshold > n
+		{XPubs: xpubs[0] + "," + xpubs[1], Threshold: 0},                 // no threshold
+		{XPubs: xpubs[0] + "," + xpubs[0], Threshold: 1},                 // duplicate
+		{XPubs: xpubs[0] + "," + xpubs[1], Threshold: 1, XPub: xpubs[2]}, // both
+	} {
+		if err := bad.normalize(&chaincfg.MainNetParams); err == nil {
+			t.Fatalf("no error for %+v", bad)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dcr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	dexdcr "decred.org/dcrdex/dex/networks/dcr"
+	"github.com/decred/dcrd/wire"
+)
+
+var _ asset.Accelerator = (*ExchangeWallet)(nil)
+
+const (
+	// minTimeBeforeAcceleration is the minimum number of seconds since the
+	// earliest unconfirmed swap or the latest acceleration before the order
+	// should be accelerated.
+	minTimeBeforeAcceleration uint64 = 3600 // 1 hour
+
+	// accelerationTxBaseSize is the size of an acceleration tx that spends
+	// only the order change, and has a single P2PKH change output.
+	accelerationTxBaseSize = dexdcr.MsgTxOverhead + dexdcr.P2PKHInputSize + dexdcr.P2PKHOutputSize
+)
+
+// FeesForRemainingSwaps returns the fees for a certain number of swaps at a
+// given feeRate. This is only accurate if each swap has a single input.
+// Accurate estimates should use PreSwap or FundOrder. Part of the
+// asset.Accelerator interface.
+func (dcr *ExchangeWallet) FeesForRemainingSwaps(n, feeRate uint64) uint64 {
+	return dexdcr.InitTxSize * n * feeRate
+}
+
+// getTransactions retrieves the wallet transactions that created coins. The
+// returned slice will be in the same order as the argument.
+func (dcr *ExchangeWallet) getTransactions(coins []dex.Bytes) ([]*WalletTransaction, error) {
+	txs := make([]*WalletTransaction, 0, len(coins))
+	for _, coinID := range coins {
+		txHash, _, err := decodeCoinID(coinID)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := dcr.wallet.GetTransaction(dcr.ctx, txHash)
+		if err != nil {
+			return nil, fmt.Errorf("error getting transaction %s: %w", txHash, err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// accelerationChange looks up the order change that must be spent by an
+// acceleration transaction.
+func (dcr *ExchangeWallet) accelerationChange(changeCoin dex.Bytes) (*output, error) {
+	txHash, vout, err := decodeCoinID(changeCoin)
+	if err != nil {
+		return nil, err
+	}
+	txOut, _, _, err := dcr.lookupTxOutput(dcr.ctx, txHash, vout)
+	if err != nil {
+		return nil, fmt.Errorf("error finding order change %s:%d: %w", txHash, vout, err)
+	}
+	return newOutput(txHash, vout, uint64(txOut.Value), wire.TxTreeRegular), nil
+}
+
+// sizeAndFeesOfUnconfirmedTxs returns the total size in bytes and the total
+// fees spent by the unconfirmed transactions in txs.
+func sizeAndFeesOfUnconfirmedTxs(txs []*WalletTransaction) (size, fees uint64, err error) {
+	for _, tx := range txs {
+		if tx.Confirmations > 0 {
+			continue
+		}
+		in, out, fee, _, sz := reduceMsgTx(tx.MsgTx)
+		if in < out {
+			return 0, 0, fmt.Errorf("tx %s has value of inputs %d < value of outputs %d",
+				tx.MsgTx.TxHash(), in, out)
+		}
+		fees += fee
+		size += sz
+	}
+	return size, fees, nil
+}
+
+// additionalFeesRequired calculates the additional atoms that need to be sent
+// to miners in order to increase the average fee rate of unconfirmed
+// transactions to newFeeRate. An error is returned if no additional fees are
+// required.
+func additionalFeesRequired(txs []*WalletTransaction, newFeeRate uint64) (uint64, error) {
+	size, fees, err := sizeAndFeesOfUnconfirmedTxs(txs)
+	if err != nil {
+		return 0, err
+	}
+	if fees >= size*newFeeRate {
+		return 0, fmt.Errorf("extra fees are not needed. %d would be needed "+
+			"for a fee rate of %d, but %d was already paid",
+			size*newFeeRate, newFeeRate, fees)
+	}
+	return size*newFeeRate - fees, nil
+}
+
+// changeCanBeAccelerated returns nil if the change can be accelerated,
+// otherwise it returns an error containing the reason why it cannot. The
+// fundingMtx must be held.
+func (dcr *ExchangeWallet) changeCanBeAccelerated(change *output, remainingSwaps bool) error {
+	if _, locked := dcr.fundingCoins[change.pt]; locked {
+		if !remainingSwaps {
+			return errors.New("change locked by another order")
+		}
+		// change is locked by this order
+		return nil
+	}
+	_, err := dcr.wallet.UnspentOutput(dcr.ctx, change.txHash(), change.vout(), change.tree)
+	if errors.Is(err, asset.CoinNotFoundError) {
+		return errors.New("change already spent")
+	}
+	return err
+}
+
+// signedAccelerationTx returns a signed transaction that spends the order
+// change, and sends funds to a change address controlled by this wallet. The
+// new transaction will have a fee high enough to make the average fee rate of
+// the unmined previousTxs and the new transaction newFeeRate. The change
+// output of the new transaction will have at least requiredForRemainingSwaps.
+// Additional wallet outputs are spent if the order change is not enough. The
+// fundingMtx must be held.
+func (dcr *ExchangeWallet) signedAccelerationTx(previousTxs []*WalletTransaction, orderChange *output,
+	requiredForRemainingSwaps, newFeeRate uint64) (*wire.MsgTx, *output, string, uint64, error) {
+
+	makeError := func(err error) (*wire.MsgTx, *output, string, uint64, error) {
+		return nil, nil, "", 0, err
+	}
+
+	err := dcr.changeCanBeAccelerated(orderChange, requiredForRemainingSwaps > 0)
+	if err != nil {
+		return makeError(err)
+	}
+
+	additionalFees, err := additionalFeesRequired(previousTxs, newFeeRate)
+	if err != nil {
+		return makeError(err)
+	}
+
+	// enough reports whether the inputs cover the fees and the funds required
+	// for the remaining swaps, with a change output that is not dust.
+	enough := func(inputsVal, inputsSize uint64) bool {
+		fees := additionalFees + (accelerationTxBaseSize+inputsSize)*newFeeRate
+		totalVal := orderChange.value + inputsVal
+		if totalVal < fees+requiredForRemainingSwaps {
+			return false
+		}
+		return !dexdcr.IsDustVal(dexdcr.P2PKHOutputSize, totalVal-fees, newFeeRate)
+	}
+
+	var additionalInputs asset.Coins
+	var additionalSize uint64
+	if !enough(0, 0) {
+		// If the change is not enough, use other UTXOs.
+		utxos, err := dcr.spendableUTXOs()
+		if err != nil {
+			return makeError(err)
+		}
+		others := make([]*compositeUTXO, 0, len(utxos))
+		for _, utxo := range utxos {
+			if utxo.rpc.TxID == orderChange.txHash().String() && utxo.rpc.Vout == orderChange.vout() {
+				continue
+			}
+			others = append(others, utxo)
+		}
+		enoughWith := func(sum uint64, size uint32, utxo *compositeUTXO) (bool, uint64) {
+			return enough(sum+toAtoms(utxo.rpc.Amount), uint64(size+utxo.input.Size())), 0
+		}
+		var size uint64
+		additionalInputs, _, _, _, size, err = dcr.fundInternalWithUTXOs(others, dcr.bondReserves.Load(), enoughWith, false)
+		if err != nil {
+			return makeError(fmt.Errorf("failed to fund acceleration tx: %w", err))
+		}
+		additionalSize = size
+	}
+
+	baseTx := wire.NewMsgTx()
+	totalIn, err := dcr.addInputCoins(baseTx, append(asset.Coins{orderChange}, additionalInputs...))
+	if err != nil {
+		return makeError(err)
+	}
+	fees := additionalFees + (accelerationTxBaseSize+additionalSize)*newFeeRate
+	if totalIn < fees+requiredForRemainingSwaps {
+		return makeError(fmt.Errorf("insufficient funds for acceleration: %s < %s",
+			amount(totalIn), amount(fees+requiredForRemainingSwaps)))
+	}
+
+	// Change that will fund more swaps goes to the trading account, as in
+	// Swap.
+	changeAcct := dcr.depositAccount()
+	if tradingAcct := dcr.wallet.Accounts().TradingAccount; requiredForRemainingSwaps > 0 && tradingAcct != "" {
+		changeAcct = tradingAcct
+	}
+	changeVal := totalIn - fees
+	changeOut, changeAddr, err := dcr.makeChangeOut(changeAcct, changeVal)
+	if err != nil {
+		return makeError(err)
+	}
+	baseTx.AddTxOut(changeOut)
+
+	msgTx, err := dcr.wallet.SignRawTransaction(dcr.ctx, baseTx)
+	if err != nil {
+		return makeError(fmt.Errorf("error signing acceleration tx: %w", err))
+	}
+	// The input sizes are estimated with the largest signatures, so the
+	// signed tx can only be smaller.
+	if _, _, _, _, size := reduceMsgTx(msgTx); size > accelerationTxBaseSize+additionalSize {
+		return makeError(fmt.Errorf("acceleration tx size %d is larger than the estimated %d",
+			size, accelerationTxBaseSize+additionalSize))
+	}
+
+	change := newOutput(msgTx.CachedTxHash(), 0, changeVal, wire.TxTreeRegular)
+	return msgTx, change, changeAddr.String(), fees, nil
+}
+
+// AccelerateOrder uses the Child-Pays-For-Parent technique to accelerate a
+// chain of swap transactions and previous accelerations. It broadcasts a new
+// transaction with a fee high enough so that the average fee of all the
+// unconfirmed transactions in the chain and the new transaction will have
+// an average fee rate of newFeeRate. The changeCoin argument is the latest
+// change in the order. It must be the input in the acceleration transaction
+// in order for the order to be accelerated. requiredForRemainingSwaps is the
+// amount of funds required to complete the rest of the swaps in the order.
+// The change output of the acceleration transaction will have at least
+// this amount. Part of the asset.Accelerator interface.
+func (dcr *ExchangeWallet) AccelerateOrder(swapCoins, accelerationCoins []dex.Bytes, changeCoin dex.Bytes, requiredForRemainingSwaps, newFeeRate uint64) (asset.Coin, string, error) {
+	orderChange, err := dcr.accelerationChange(changeCoin)
+	if err != nil {
+		return nil, "", err
+	}
+	previousTxs, err := dcr.getTransactions(append(swapCoins, accelerationCoins...))
+	if err != nil {
+		return nil, "", err
+	}
+
+	dcr.fundingMtx.Lock()
+	defer dcr.fundingMtx.Unlock()
+
+	signedTx, newChange, changeAddr, fees, err :=
+		dcr.signedAccelerationTx(previousTxs, orderChange, requiredForRemainingSwaps, newFeeRate)
+	if err != nil {
+		return nil, "", err
+	}
+
+	txHash, err := dcr.broadcastTx(signedTx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dcr.addTxToHistory(&asset.WalletTransaction{
+		Type: asset.Acceleration,
+		ID:   txHash.String(),
+		Fees: fees,
+	}, txHash, true)
+
+	// Unlock the spent order change.
+	if _, locked := dcr.fundingCoins[orderChange.pt]; locked {
+		if _, err := dcr.returnCoins(asset.Coins{orderChange}); err != nil {
+			dcr.log.Errorf("Error unlocking accelerated order change %s: %v", orderChange, err)
+		}
+	}
+
+	// Lock the new change if it will fund the remaining swaps. If
+	// requiredForRemainingSwaps is zero, but the old change was locked,
+	// changeCanBeAccelerated would have returned an error since it was locked
+	// by another order.
+	if requiredForRemainingSwaps > 0 {
+		err = dcr.lockFundingCoins([]*fundingCoin{{
+			op:   newChange,
+			addr: changeAddr,
+		}})
+		if err != nil {
+			// The transaction is already broadcasted, so don't fail now.
+			dcr.log.Errorf("Failed to lock acceleration change %s: %v", newChange, err)
+		}
+	}
+
+	// Return a nil error since the tx is already broadcast, and core needs to
+	// update the change coin.
+	return newChange, txHash.String(), nil
+}
+
+// AccelerationEstimate takes the same parameters as AccelerateOrder, but
+// instead of broadcasting the acceleration transaction, it just returns the
+// amount of funds that will need to be spent in order to increase the average
+// fee rate to the desired amount. Part of the asset.Accelerator interface.
+func (dcr *ExchangeWallet) AccelerationEstimate(swapCoins, accelerationCoins []dex.Bytes, changeCoin dex.Bytes, requiredForRemainingSwaps, newFeeRate uint64) (uint64, error) {
+	previousTxs, err := dcr.getTransactions(append(swapCoins, accelerationCoins...))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	orderChange, err := dcr.accelerationChange(changeCoin)
+	if err != nil {
+		return 0, err
+	}
+
+	dcr.fundingMtx.Lock()
+	defer dcr.fundingMtx.Unlock()
+
+	_, _, _, fees, err := dcr.signedAccelerationTx(previousTxs, orderChange, requiredForRemainingSwaps, newFeeRate)
+	if err != nil {
+		return 0, err
+	}
+	return fees, nil
+}
+
+// tooEarlyToAccelerate returns an asset.EarlyAcceleration if
+// minTimeBeforeAcceleration has not passed since either the earliest
+// unconfirmed swap transaction, or the latest acceleration transaction.
+func tooEarlyToAccelerate(swapTxs, accelerationTxs []*WalletTransaction) (*asset.EarlyAcceleration, error) {
+	var latestAcceleration, earliestUnconfirmed uint64 = 0, math.MaxUint64
+	for _, tx := range swapTxs {
+		if tx.Confirmations > 0 {
+			continue
+		}
+		if t := uint64(tx.Time); t < earliestUnconfirmed {
+			earliestUnconfirmed = t
+		}
+	}
+	for _, tx := range accelerationTxs {
+		if tx.Confirmations > 0 {
+			continue
+		}
+		if t := uint64(tx.Time); t > latestAcceleration {
+			latestAcceleration = t
+		}
+	}
+
+	var actionTime uint64
+	var wasAccelerated bool
+	if latestAcceleration == 0 && earliestUnconfirmed == math.MaxUint64 {
+		return nil, fmt.Errorf("no need to accelerate because all tx are confirmed")
+	} else if earliestUnconfirmed > latestAcceleration && earliestUnconfirmed < math.MaxUint64 {
+		actionTime = earliestUnconfirmed
+	} else {
+		actionTime = latestAcceleration
+		wasAccelerated = true
+	}
+
+	currentTime := uint64(time.Now().Unix())
+	if actionTime+minTimeBeforeAcceleration > currentTime {
+		return &asset.EarlyAcceleration{
+			TimePast:       currentTime - actionTime,
+			WasAccelerated: wasAccelerated,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// maxAccelerationRate returns the max rate to which an order can be
+// accelerated, if the max rate is less than rateNeeded. If the max rate is
+// greater than rateNeeded, rateNeeded is returned.
+func (dcr *ExchangeWallet) maxAccelerationRate(orderChange *output, feesAlreadyPaid, orderTxSize, requiredForRemainingSwaps, rateNeeded uint64) (uint64, error) {
+	txSize := uint64(accelerationTxBaseSize)
+	var additionalUtxosVal uint64
+
+	calcFeeRate := func() uint64 {
+		totalValue := orderChange.value + feesAlreadyPaid + additionalUtxosVal
+		if totalValue < requiredForRemainingSwaps {
+			return 0
+		}
+		totalValue -= requiredForRemainingSwaps
+		return totalValue / (txSize + orderTxSize)
+	}
+
+	if calcFeeRate() >= rateNeeded {
+		return rateNeeded, nil
+	}
+
+	// If necessary, use as many additional utxos as needed.
+	utxos, err := dcr.spendableUTXOs()
+	if err != nil {
+		return 0, err
+	}
+	for i := len(utxos) - 1; i >= 0; i-- { // largest first
+		utxo := utxos[i]
+		if utxo.confs < 1 || (utxo.rpc.TxID == orderChange.txHash().String() && utxo.rpc.Vout == orderChange.vout()) {
+			continue
+		}
+		txSize += uint64(utxo.input.Size())
+		additionalUtxosVal += toAtoms(utxo.rpc.Amount)
+		if calcFeeRate() >= rateNeeded {
+			return rateNeeded, nil
+		}
+	}
+
+	return calcFeeRate(), nil
+}
+
+// PreAccelerate returns the current average fee rate of the unmined swap
+// initiation and acceleration transactions, and also returns a suggested
+// range that the fee rate should be increased to in order to expedite mining.
+// The feeSuggestion argument is the current prevailing network rate. It is
+// used to help determine the suggestedRange, which is a range meant to give
+// the user a good amount of flexibility in determining the post acceleration
+// effective fee rate, but still not allowing them to pick something
+// outrageously high. Part of the asset.Accelerator interface.
+func (dcr *ExchangeWallet) PreAccelerate(swapCoins, accelerationCoins []dex.Bytes, changeCoin dex.Bytes, requiredForRemainingSwaps, feeSuggestion uint64) (uint64, *asset.XYRange, *asset.EarlyAcceleration, error) {
+	makeError := func(err error) (uint64, *asset.XYRange, *asset.EarlyAcceleration, error) {
+		return 0, &asset.XYRange{}, nil, err
+	}
+
+	orderChange, err := dcr.accelerationChange(changeCoin)
+	if err != nil {
+		return makeError(err)
+	}
+
+	dcr.fundingMtx.RLock()
+	err = dcr.changeCanBeAccelerated(orderChange, requiredForRemainingSwaps > 0)
+	dcr.fundingMtx.RUnlock()
+	if err != nil {
+		return makeError(err)
+	}
+
+	txs, err := dcr.getTransactions(append(swapCoins, accelerationCoins...))
+	if err != nil {
+		return makeError(fmt.Errorf("failed to get transactions: %w", err))
+	}
+
+	existingTxSize, feesAlreadyPaid, err := sizeAndFeesOfUnconfirmedTxs(txs)
+	if err != nil {
+		return makeError(err)
+	}
+	if feesAlreadyPaid == 0 {
+		return makeError(fmt.Errorf("all transactions are already confirmed, no need to accelerate"))
+	}
+
+	earlyAcceleration, err := tooEarlyToAccelerate(txs[:len(swapCoins)], txs[len(swapCoins):])
+	if err != nil {
+		return makeError(err)
+	}
+
+	// The suggested range is the min and max of the slider displayed on the
+	// UI, as with BTC. The min is 1 higher than the current effective rate of
+	// the swap transactions, and the max is 5x the greater of the current
+	// effective rate and the prevailing network rate.
+	const scalingFactor = 5
+	currentEffectiveRate := feesAlreadyPaid / existingTxSize
+	maxSuggestion := currentEffectiveRate * scalingFactor
+	if feeSuggestion > currentEffectiveRate {
+		maxSuggestion = feeSuggestion * scalingFactor
+	}
+
+	// The wallet must be able to fund an acceleration to the max suggestion,
+	// otherwise the max suggestion is lowered to the max rate that the wallet
+	// can fund.
+	maxRate, err := dcr.maxAccelerationRate(orderChange, feesAlreadyPaid, existingTxSize, requiredForRemainingSwaps, maxSuggestion)
+	if err != nil {
+		return makeError(err)
+	}
+	if maxRate <= currentEffectiveRate {
+		return makeError(fmt.Errorf("cannot accelerate, max rate %v <= current rate %v", maxRate, currentEffectiveRate))
+	}
+	if maxRate < maxSuggestion {
+		maxSuggestion = maxRate
+	}
+
+	suggestedRange := asset.XYRange{
+		Start: asset.XYRangePoint{
+			Label: "Min",
+			X:     float64(currentEffectiveRate+1) / float64(currentEffectiveRate),
+			Y:     float64(currentEffectiveRate + 1),
+		},
+		End: asset.XYRangePoint{
+			Label: "Max",
+			X:     float64(maxSuggestion) / float64(currentEffectiveRate),
+			Y:     float64(maxSuggestion),
+		},
+		XUnit: "X",
+		YUnit: "atoms/B",
+	}
+
+	return currentEffectiveRate, &suggestedRange, earlyAcceleration, nil
+}
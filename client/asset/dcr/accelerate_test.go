@@ -0,0 +1,139 @@
+//go:build !harness && !vspd
+
+package dcr
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	dexdcr "decred.org/dcrdex/dex/networks/dcr"
+	walletjson "decred.org/dcrwallet/v5/rpc/jsonrpc/types"
+	"github.com/decred/dcrd/wire"
+)
+
+func TestAccelerateOrder(t *testing.T) {
+	wallet, node, shutdown := tNewWallet()
+	defer shutdown()
+
+	node.changeAddr = tPKHAddr
+	node.signFunc = func(msgTx *wire.MsgTx) (*wire.MsgTx, bool, error) {
+		return signFunc(msgTx, dexdcr.P2PKHSigScriptSize)
+	}
+
+	// An unconfirmed swap paying 1 atom/B, with a change output.
+	const swapVal = 5e7
+	swapTx := wire.NewMsgTx()
+	swapTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(tTxHash, 0, wire.TxTreeRegular),
+		ValueIn:          1e8,
+		SignatureScript:  randBytes(dexdcr.P2PKHSigScriptSize),
+	})
+	swapTx.AddTxOut(newTxOut(swapVal, 0, tP2PKHScript))
+	swapTx.AddTxOut(newTxOut(0, 0, tP2PKHScript))
+	swapSize := uint64(swapTx.SerializeSize())
+	changeVal := uint64(1e8 - swapVal - swapSize)
+	swapTx.TxOut[1].Value = int64(changeVal)
+	swapTxHash := swapTx.TxHash()
+
+	swapTxTime := time.Now().Add(-2 * time.Hour).Unix()
+	node.walletTxFn = func() (*walletjson.GetTransactionResult, error) {
+		b, _ := swapTx.Bytes()
+		return &walletjson.GetTransactionResult{
+			Hex:  hex.EncodeToString(b),
+			Time: swapTxTime,
+		}, nil
+	}
+	changePt := newOutPoint(&swapTxHash, 1)
+	node.txOutRes[changePt] = newTxOutResult(tP2PKHScript, changeVal, 0)
+
+	swapCoins := []dex.Bytes{toCoinID(&swapTxHash, 0)}
+	changeCoin := toCoinID(&swapTxHash, 1)
+
+	const newFeeRate = 20
+	checkTx := func(tx *wire.MsgTx, numInputs int) {
+		t.Helper()
+		if len(tx.TxIn) != numInputs {
+			t.Fatalf("expected %d inputs, got %d", numInputs, len(tx.TxIn))
+		}
+		if tx.TxIn[0].PreviousOutPoint.Hash != swapTxHash || tx.TxIn[0].PreviousOutPoint.Index != 1 {
+			t.Fatalf("acceleration does not spend the order change")
+		}
+		_, _, fees, _, size := reduceMsgTx(tx)
+		if rate := (fees + swapSize) / (size + swapSize); rate < newFeeRate {
+			t.Fatalf("effective fee rate %d < %d", rate, newFeeRate)
+		}
+	}
+
+	// The effective rate of the swap is reported, and it is not too early to
+	// accelerate.
+	rate, suggestedRange, early, err := wallet.PreAccelerate(swapCoins, nil, changeCoin, 0, 10)
+	if err != nil {
+		t.Fatalf("PreAccelerate error: %v", err)
+	}
+	if rate != 1 || early != nil || suggestedRange.End.Y != 50 {
+		t.Fatalf("wrong PreAccelerate result: rate = %d, early = %v, max = %f", rate, early, suggestedRange.End.Y)
+	}
+
+	fees, err := wallet.AccelerationEstimate(swapCoins, nil, changeCoin, 0, newFeeRate)
+	if err != nil {
+		t.Fatalf("AccelerationEstimate error: %v", err)
+	}
+	if node.sentRawTx != nil {
+		t.Fatalf("AccelerationEstimate broadcast a tx")
+	}
+
+	newChange, txID, err := wallet.AccelerateOrder(swapCoins, nil, changeCoin, 0, newFeeRate)
+	if err != nil {
+		t.Fatalf("AccelerateOrder error: %v", err)
+	}
+	checkTx(node.sentRawTx, 1)
+	if txID != node.sentRawTx.TxHash().String() || newChange == nil || newChange.Value() != changeVal-fees {
+		t.Fatalf("wrong acceleration result")
+	}
+
+	// A recent swap is too early to accelerate.
+	swapTxTime = time.Now().Unix()
+	if _, _, early, err = wallet.PreAccelerate(swapCoins, nil, changeCoin, 0, 10); err != nil || early == nil {
+		t.Fatalf("no early acceleration for recent swap, err = %v", err)
+	}
+
+	// The change locked by another order can't be accelerated.
+	wallet.fundingCoins[changePt] = &fundingCoin{op: newOutput(&swapTxHash, 1, changeVal, wire.TxTreeRegular)}
+	if _, _, err = wallet.AccelerateOrder(swapCoins, nil, changeCoin, 0, newFeeRate); err == nil {
+		t.Fatalf("no error accelerating with change locked by another order")
+	}
+
+	// But the order's own locked change funds the remaining swaps, and the
+	// new change is locked.
+	node.sentRawTx = nil
+	required := changeVal / 2
+	newChange, _, err = wallet.AccelerateOrder(swapCoins, nil, changeCoin, required, newFeeRate)
+	if err != nil {
+		t.Fatalf("AccelerateOrder error with remaining swaps: %v", err)
+	}
+	checkTx(node.sentRawTx, 1)
+	if _, locked := wallet.fundingCoins[changePt]; locked {
+		t.Fatalf("spent change still locked")
+	}
+	if _, locked := wallet.fundingCoins[newChange.(*output).pt]; !locked || newChange.Value() < required {
+		t.Fatalf("new change not locked, or too small")
+	}
+
+	// Other outputs are spent if the change is not enough.
+	node.unspent = []walletjson.ListUnspentResult{{
+		TxID:          tTxID,
+		Address:       tPKHAddr.String(),
+		Account:       tAcctName,
+		Amount:        1,
+		Confirmations: 5,
+		ScriptPubKey:  hex.EncodeToString(tP2PKHScript),
+		Spendable:     true,
+	}}
+	node.sentRawTx = nil
+	if _, _, err = wallet.AccelerateOrder(swapCoins, nil, changeCoin, changeVal, newFeeRate); err != nil {
+		t.Fatalf("AccelerateOrder error with additional inputs: %v", err)
+	}
+	checkTx(node.sentRawTx, 2)
+}
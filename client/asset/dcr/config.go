@@ -38,6 +38,8 @@ type walletConfig struct {
 	ActivelyUsed     bool    `ini:"special_activelyUsed"` //injected by core
 	ApiFeeFallback   bool    `ini:"apifeefallback"`
 	GapLimit         uint32  `ini:"gaplimit"`
+	BackupVSPs       string  `ini:"backupvsps"`
+	MaxVSPFee        float64 `ini:"maxvspfee"`
 }
 
 type rpcConfig struct {
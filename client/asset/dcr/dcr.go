@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -180,6 +181,23 @@ var (
 		},
 	}
 
+	// vspOpts are the ticket buyer settings for the native wallet.
+	vspOpts = []*asset.ConfigOption{
+		{
+			Key:         "backupvsps",
+			DisplayName: "Backup VSPs",
+			Description: "A comma-separated list of VSP URLs, in order of " +
+				"priority. If the selected VSP can't be reached or its fee is " +
+				"too high, tickets are purchased from the first usable backup.",
+		},
+		{
+			Key:         "maxvspfee",
+			DisplayName: "Highest acceptable VSP fee",
+			Description: "Tickets won't be purchased from a VSP with a higher " +
+				"fee. Zero for no limit. Units: percent",
+		},
+	}
+
 	rpcOpts = []*asset.ConfigOption{
 		{
 			Key:         "account",
@@ -276,7 +294,7 @@ var (
 				Type:             walletTypeSPV,
 				Tab:              "Native",
 				Description:      "Use the built-in SPV wallet",
-				ConfigOpts:       WalletOpts,
+				ConfigOpts:       append(WalletOpts, vspOpts...),
 				Seeded:           true,
 				MultiFundingOpts: multiFundingOpts,
 			},
@@ -288,6 +306,18 @@ var (
 				ConfigOpts:        append(rpcOpts, WalletOpts...),
 				MultiFundingOpts:  multiFundingOpts,
 			},
+			{
+				Type:        WalletTypeWatchOnly,
+				Tab:         "Watch-only",
+				Description: "Monitor an account from its extended public key. Funds can't be spent.",
+				ConfigOpts:  watchOnlyOpts,
+			},
+			{
+				Type:        WalletTypeMultisig,
+				Tab:         "Multisig",
+				Description: "Hold funds in an m-of-n multisig address. Sends are signed by the cosigners. Can't trade.",
+				ConfigOpts:  multisigOpts,
+			},
 		},
 	}
 	swapFeeBumpKey      = "swapfeebump"
@@ -598,6 +628,8 @@ type exchangeWalletConfig struct {
 	feeRateLimit     uint64
 	redeemConfTarget uint64
 	apiFeeFallback   bool
+	backupVSPs       []string
+	maxVSPFee        float64
 }
 
 type mempoolRedeem struct {
@@ -768,6 +800,23 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 		if err != nil {
 			return nil, err
 		}
+	case WalletTypeWatchOnly:
+		dcr.wallet, err = openWatchOnlyWallet(cfg.Settings, cfg.DataDir, walletCfg.GapLimit, chainParams, logger)
+		if err != nil {
+			return nil, err
+		}
+		w = &WatchOnlyWallet{dcr}
+	case WalletTypeMultisig:
+		msw, err := openMultisigWallet(cfg.Settings, cfg.DataDir, walletCfg.GapLimit, chainParams, logger)
+		if err != nil {
+			return nil, err
+		}
+		dcr.wallet = msw
+		w = &MultisigWallet{
+			ExchangeWallet: dcr,
+			ms:             msw,
+			pending:        make(map[string]*multisigRequest),
+		}
 	default:
 		makeCustomWallet, ok := customWalletConstructors[cfg.Type]
 		if !ok {
@@ -811,12 +860,24 @@ func getExchangeWalletCfg(dcrCfg *walletConfig, logger dex.Logger) (*exchangeWal
 	}
 	logger.Tracef("Redeem conf target set to %d blocks", redeemConfTarget)
 
+	var backupVSPs []string
+	for _, u := range strings.Split(dcrCfg.BackupVSPs, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			backupVSPs = append(backupVSPs, u)
+		}
+	}
+	if dcrCfg.MaxVSPFee < 0 {
+		return nil, fmt.Errorf("negative max VSP fee %v", dcrCfg.MaxVSPFee)
+	}
+
 	return &exchangeWalletConfig{
 		fallbackFeeRate:  fallbackFeesPerByte,
 		feeRateLimit:     feesLimitPerByte,
 		redeemConfTarget: redeemConfTarget,
 		useSplitTx:       dcrCfg.UseSplitTx,
 		apiFeeFallback:   dcrCfg.ApiFeeFallback,
+		backupVSPs:       backupVSPs,
+		maxVSPFee:        dcrCfg.MaxVSPFee,
 	}, nil
 }
 
@@ -1094,6 +1155,12 @@ func (dcr *ExchangeWallet) Connect(ctx context.Context) (*sync.WaitGroup, error)
 		dcr.monitorPeers(ctx)
 	}()
 
+	dcr.wg.Add(1)
+	go func() {
+		defer dcr.wg.Done()
+		dcr.watchPendingDeposits(ctx)
+	}()
+
 	dcr.wg.Add(1)
 	go func() {
 		defer dcr.wg.Done()
@@ -1237,6 +1304,10 @@ func (dcr *ExchangeWallet) Balance() (*asset.Balance, error) {
 	bal.Available -= reserves
 	bal.Locked += reserves
 
+	if pending := min(dcr.pendingDeposits(), bal.Available+bal.Immature); pending > 0 {
+		bal.Other[asset.BalanceCategoryPendingDeposit] = asset.CustomBalance{Amount: pending}
+	}
+
 	return bal, nil
 }
 
@@ -5542,6 +5613,53 @@ func vspInfo(ctx context.Context, uri string) (*vspdjson.VspInfoResponse, error)
 	return &info, dexnet.Get(ctx, path, &info)
 }
 
+// vspCandidates returns the URLs of the VSPs to purchase tickets from, in order
+// of priority. The VSP set with SetVSP is first, followed by the configured
+// backup VSPs.
+func (dcr *ExchangeWallet) vspCandidates() []string {
+	var urls []string
+	if v := dcr.vspV.Load(); v != nil {
+		urls = append(urls, v.(*vsp).URL)
+	}
+	for _, u := range dcr.config().backupVSPs {
+		if !slices.Contains(urls, u) {
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// chooseVSP returns the first VSP that can be reached, is open, and has a fee
+// no higher than maxFee, if maxFee is non-zero. skip is called for each VSP
+// that is passed over.
+func chooseVSP(ctx context.Context, urls []string, maxFee float64,
+	getInfo func(context.Context, string) (*vspdjson.VspInfoResponse, error),
+	skip func(url string, err error)) (*vsp, error) {
+
+	if len(urls) == 0 {
+		return nil, errors.New("no vsp set")
+	}
+	for _, url := range urls {
+		info, err := getInfo(ctx, url)
+		switch {
+		case err != nil:
+			err = fmt.Errorf("vsp unreachable: %w", err)
+		case info.VspClosed:
+			err = fmt.Errorf("vsp closed: %s", info.VspClosedMsg)
+		case maxFee > 0 && info.FeePercentage > maxFee:
+			err = fmt.Errorf("fee %.2f%% is higher than the limit of %.2f%%", info.FeePercentage, maxFee)
+		default:
+			return &vsp{
+				URL:           url,
+				PubKey:        base64.StdEncoding.EncodeToString(info.PubKey),
+				FeePercentage: info.FeePercentage,
+			}, nil
+		}
+		skip(url, err)
+	}
+	return nil, fmt.Errorf("none of %d VSPs are usable", len(urls))
+}
+
 // SetVSP sets the VSP provider. Ability to set can be checked with StakeStatus
 // first. Only non-RPC (internal) wallets can be set. Part of the
 // asset.TicketBuyer interface.
@@ -5628,6 +5746,7 @@ const ticketDataRoute = "ticketPurchaseUpdate"
 // loop.
 type TicketPurchaseUpdate struct {
 	Err       string             `json:"err,omitempty"`
+	Warn      string             `json:"warn,omitempty"`
 	Remaining uint32             `json:"remaining"`
 	Tickets   []*asset.Ticket    `json:"tickets"`
 	Stats     *asset.TicketStats `json:"stats,omitempty"`
@@ -5702,11 +5821,15 @@ func (dcr *ExchangeWallet) runTicketBuyer() {
 	if !dcr.isNative() {
 		tickets, err = dcr.wallet.PurchaseTickets(dcr.ctx, int(remain), "", "", false)
 	} else {
-		v := dcr.vspV.Load()
-		if v == nil {
-			err = errors.New("no vsp set")
-		} else {
-			vInfo := v.(*vsp)
+		var vInfo *vsp
+		vInfo, err = chooseVSP(dcr.ctx, dcr.vspCandidates(), dcr.config().maxVSPFee, vspInfo, func(url string, err error) {
+			dcr.log.Warnf("Skipping VSP %s for ticket purchase: %v", url, err)
+			dcr.emit.Data(ticketDataRoute, &TicketPurchaseUpdate{
+				Warn:      fmt.Sprintf("skipping VSP %s: %v", url, err),
+				Remaining: uint32(remain),
+			})
+		})
+		if err == nil {
 			tickets, err = dcr.wallet.PurchaseTickets(dcr.ctx, int(remain), vInfo.URL, vInfo.PubKey, dcr.mixing.Load())
 		}
 	}
@@ -6309,6 +6432,7 @@ func (dcr *ExchangeWallet) addUnknownTransactionsToHistory(tip uint64) {
 		return
 	}
 
+	var deposits []*asset.WalletTransaction
 	for _, tx := range txs {
 		if dcr.ctx.Err() != nil {
 			return
@@ -6340,7 +6464,11 @@ func (dcr *ExchangeWallet) addUnknownTransactionsToHistory(tip uint64) {
 		// Don't send notifications for the initial sync to avoid spamming the
 		// front end. A notification is sent at the end of the initial sync.
 		dcr.addTxToHistory(wt, txHash, true, blockToQuery == 0)
+		if blockToQuery != 0 && wt.Type == asset.Receive && (tx.BlockIndex == nil || *tx.BlockIndex == 0) {
+			deposits = append(deposits, wt)
+		}
 	}
+	dcr.notifyPendingDeposits(deposits)
 
 	dcr.receiveTxLastQuery.Store(tip)
 	err = txHistoryDB.SetLastReceiveTxQuery(tip)
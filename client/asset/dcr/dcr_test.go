@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -42,6 +43,7 @@ import (
 	"github.com/decred/dcrd/txscript/v4"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/dcrd/wire"
+	vspdjson "github.com/decred/vspd/types/v2"
 )
 
 var (
@@ -4546,6 +4548,75 @@ func TestPurchaseTickets(t *testing.T) {
 	checkRemains(1, 0)
 }
 
+func TestChooseVSP(t *testing.T) {
+	infos := map[string]*vspdjson.VspInfoResponse{
+		"https://open.vsp":   {PubKey: []byte{0x01}, FeePercentage: 2},
+		"https://cheap.vsp":  {PubKey: []byte{0x02}, FeePercentage: 0.5},
+		"https://closed.vsp": {VspClosed: true},
+	}
+	getInfo := func(_ context.Context, url string) (*vspdjson.VspInfoResponse, error) {
+		if info, found := infos[url]; found {
+			return info, nil
+		}
+		return nil, errors.New("connection refused")
+	}
+
+	tests := []struct {
+		name    string
+		urls    []string
+		maxFee  float64
+		wantURL string
+		skipped int
+		wantErr bool
+	}{{
+		name:    "primary",
+		urls:    []string{"https://open.vsp", "https://cheap.vsp"},
+		wantURL: "https://open.vsp",
+	}, {
+		name:    "primary unreachable",
+		urls:    []string{"https://down.vsp", "https://open.vsp"},
+		wantURL: "https://open.vsp",
+		skipped: 1,
+	}, {
+		name:    "fee too high",
+		urls:    []string{"https://open.vsp", "https://closed.vsp", "https://cheap.vsp"},
+		maxFee:  1,
+		wantURL: "https://cheap.vsp",
+		skipped: 2,
+	}, {
+		name:    "none usable",
+		urls:    []string{"https://down.vsp", "https://closed.vsp"},
+		skipped: 2,
+		wantErr: true,
+	}, {
+		name:    "no vsp",
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		var skipped int
+		v, err := chooseVSP(tCtx, tt.urls, tt.maxFee, getInfo, func(string, error) { skipped++ })
+		if skipped != tt.skipped {
+			t.Fatalf("%s: expected %d skipped VSPs, got %d", tt.name, tt.skipped, skipped)
+		}
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if v.URL != tt.wantURL {
+			t.Fatalf("%s: expected VSP %s, got %s", tt.name, tt.wantURL, v.URL)
+		}
+		if v.PubKey != base64.StdEncoding.EncodeToString(infos[tt.wantURL].PubKey) {
+			t.Fatalf("%s: wrong pubkey %s", tt.name, v.PubKey)
+		}
+	}
+}
+
 func TestFindBond(t *testing.T) {
 	wallet, node, shutdown := tNewWallet()
 	defer shutdown()
@@ -0,0 +1,76 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dcr
+
+import (
+	"context"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+// pendingDepositPollInterval is how often the wallet checks for incoming
+// mempool transactions between blocks.
+const pendingDepositPollInterval = 15 * time.Second
+
+// watchPendingDeposits periodically adds new wallet transactions to the
+// history, so that incoming payments are reported before they are mined.
+func (dcr *ExchangeWallet) watchPendingDeposits(ctx context.Context) {
+	ticker := time.NewTicker(pendingDepositPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			dcr.checkPendingDeposits()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkPendingDeposits looks for unknown transactions, unless the history is
+// being synced. Nothing is done before the initial history sync, since new
+// transactions are not reported for it.
+func (dcr *ExchangeWallet) checkPendingDeposits() {
+	if dcr.receiveTxLastQuery.Load() == 0 {
+		return
+	}
+	if !dcr.syncingTxHistory.CompareAndSwap(false, true) {
+		return
+	}
+	defer dcr.syncingTxHistory.Store(false)
+	dcr.addUnknownTransactionsToHistory(uint64(dcr.cachedBestBlock().height))
+}
+
+// notifyPendingDeposits emits a PendingDepositNote for each of the new
+// unconfirmed incoming transactions, and a BalanceChangeNote with the
+// provisional balance.
+func (dcr *ExchangeWallet) notifyPendingDeposits(deposits []*asset.WalletTransaction) {
+	if len(deposits) == 0 {
+		return
+	}
+	for _, wt := range deposits {
+		dcr.log.Infof("Incoming DCR transaction %s for %s is in the mempool", wt.ID, amount(wt.Amount))
+		dcr.emit.PendingDeposit(wt)
+	}
+	bal, err := dcr.Balance()
+	if err != nil {
+		dcr.log.Errorf("Error getting balance after pending deposit: %v", err)
+		return
+	}
+	dcr.emit.BalanceChange(bal)
+}
+
+// pendingDeposits is the total amount of the unconfirmed incoming
+// transactions in the history.
+func (dcr *ExchangeWallet) pendingDeposits() (total uint64) {
+	dcr.pendingTxsMtx.RLock()
+	defer dcr.pendingTxsMtx.RUnlock()
+	for _, tx := range dcr.pendingTxs {
+		if tx.Type == asset.Receive && tx.BlockNumber == 0 {
+			total += tx.Amount
+		}
+	}
+	return total
+}
@@ -0,0 +1,689 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dcr
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/config"
+	dexdcr "decred.org/dcrdex/dex/networks/dcr"
+	walleterrors "decred.org/dcrwallet/v5/errors"
+	"decred.org/dcrwallet/v5/wallet"
+	"decred.org/dcrwallet/v5/wallet/udb"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/txscript/v4/stdscript"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	WalletTypeMultisig = "multisig"
+	// maxMultisigKeys is the maximum number of keys of a multisig script
+	// whose signature script is still standard.
+	maxMultisigKeys = 15
+)
+
+var (
+	// errMultisigTrading is returned for trading with a multisig wallet. Swap
+	// contracts are single-sig, and their refunds must pay to a single key.
+	errMultisigTrading = errors.New("multisig wallets cannot trade, post bonds, or stake")
+
+	multisigOpts = []*asset.ConfigOption{
+		{
+			Key:         "multisigxpubs",
+			DisplayName: "Cosigner extended public keys",
+			Description: "The comma-separated extended public keys of the " +
+				"cosigners' accounts, e.g. from dcrwallet's getmasterpubkey. " +
+				"Any order.",
+			Required: true,
+		},
+		{
+			Key:         "multisigthreshold",
+			DisplayName: "Required signatures",
+			Description: "The number of cosigners that must sign a transaction.",
+			Required:    true,
+		},
+	}
+)
+
+// multisigConfig is the configuration for a multisig wallet.
+type multisigConfig struct {
+	XPubs     string `ini:"multisigxpubs"`
+	Threshold int    `ini:"multisigthreshold"`
+}
+
+// multisigScript is an m-of-n P2SH multisig script. The script's keys are the
+// first external address keys of the cosigners' accounts, sorted, so that
+// the script doesn't depend on the order in which they were entered.
+type multisigScript struct {
+	script    []byte
+	addr      *stdaddr.AddressScriptHashV0
+	threshold int
+	pubKeys   []*secp256k1.PublicKey
+	// xpubs are the cosigners' extended public keys, sorted.
+	xpubs []string
+}
+
+// newMultisigScript parses the multisig wallet settings and creates the
+// script.
+func newMultisigScript(settings map[string]string, chainParams *chaincfg.Params) (*multisigScript, error) {
+	var cfg multisigConfig
+	if err := config.Unmapify(settings, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing multisig wallet settings: %w", err)
+	}
+	var xpubs []string
+	for _, xpub := range strings.Split(cfg.XPubs, ",") {
+		if xpub = strings.TrimSpace(xpub); xpub == "" {
+			continue
+		}
+		for _, k := range xpubs {
+			if k == xpub {
+				return nil, fmt.Errorf("duplicate cosigner key %s", xpub)
+			}
+		}
+		xpubs = append(xpubs, xpub)
+	}
+	if len(xpubs) < 2 || len(xpubs) > maxMultisigKeys {
+		return nil, fmt.Errorf("a multisig wallet needs 2 to %d cosigner keys, got %d", maxMultisigKeys, len(xpubs))
+	}
+	if cfg.Threshold < 1 || cfg.Threshold > len(xpubs) {
+		return nil, fmt.Errorf("invalid multisig threshold %d for %d keys", cfg.Threshold, len(xpubs))
+	}
+	sort.Strings(xpubs)
+
+	serializedKeys := make([][]byte, 0, len(xpubs))
+	for _, xpub := range xpubs {
+		key, err := parseXPub(xpub, chainParams)
+		if err != nil {
+			return nil, err
+		}
+		branch, err := key.Child(udb.ExternalBranch)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving external branch of %s: %w", xpub, err)
+		}
+		child, err := branch.Child(0)
+		if err != nil {
+			return nil, fmt.Errorf("error deriving address key of %s: %w", xpub, err)
+		}
+		serializedKeys = append(serializedKeys, child.SerializedPubKey())
+	}
+	sort.Slice(serializedKeys, func(i, j int) bool {
+		return bytes.Compare(serializedKeys[i], serializedKeys[j]) < 0
+	})
+
+	script, err := stdscript.MultiSigScriptV0(cfg.Threshold, serializedKeys...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating multisig script: %w", err)
+	}
+	addr, err := stdaddr.NewAddressScriptHashV0(script, chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("error creating multisig address: %w", err)
+	}
+	pubKeys := make([]*secp256k1.PublicKey, 0, len(serializedKeys))
+	for _, b := range serializedKeys {
+		pubKey, err := secp256k1.ParsePubKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing public key: %w", err)
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return &multisigScript{
+		script:    script,
+		addr:      addr,
+		threshold: cfg.Threshold,
+		pubKeys:   pubKeys,
+		xpubs:     xpubs,
+	}, nil
+}
+
+// inputSize is the size of an input spending the script, with the threshold
+// number of signatures.
+func (s *multisigScript) inputSize() uint64 {
+	scriptPush := len(s.script) + 1
+	if len(s.script) > txscript.OP_DATA_75 {
+		scriptPush++ // OP_PUSHDATA1
+	}
+	sigScriptSize := s.threshold*(1+73) + scriptPush
+	return dexdcr.TxInOverhead + uint64(wire.VarIntSerializeSize(uint64(sigScriptSize))+sigScriptSize)
+}
+
+// addSignatures adds the valid signatures in sigScript to the signature script
+// of input idx. The signatures are ordered by the keys of the multisig script,
+// as OP_CHECKMULTISIG requires, and the redeem script is pushed last. Pushes
+// that aren't a signature for one of the keys are ignored, so sigScript can be
+// a partial signature script from e.g. dcrwallet's signrawtransaction. The
+// number of new signatures is returned, and whether the input has the
+// threshold number.
+func (s *multisigScript) addSignatures(tx *wire.MsgTx, idx int, sigScript []byte) (added int, complete bool, err error) {
+	hash, err := txscript.CalcSignatureHash(s.script, txscript.SigHashAll, tx, idx, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("error calculating signature hash: %w", err)
+	}
+	sigs := make([][]byte, len(s.pubKeys))
+	addSig := func(data []byte) bool {
+		if len(data) < 2 || txscript.SigHashType(data[len(data)-1]) != txscript.SigHashAll {
+			return false
+		}
+		sig, err := ecdsa.ParseDERSignature(data[:len(data)-1])
+		if err != nil {
+			return false
+		}
+		for i, pubKey := range s.pubKeys {
+			if sigs[i] == nil && sig.Verify(hash, pubKey) {
+				sigs[i] = data
+				return true
+			}
+		}
+		return false
+	}
+	pushes := func(script []byte) ([][]byte, error) {
+		var data [][]byte
+		tokenizer := txscript.MakeScriptTokenizer(0, script)
+		for tokenizer.Next() {
+			if d := tokenizer.Data(); len(d) > 0 {
+				data = append(data, d)
+			}
+		}
+		return data, tokenizer.Err()
+	}
+
+	existing, err := pushes(tx.TxIn[idx].SignatureScript)
+	if err != nil {
+		return 0, false, fmt.Errorf("error parsing signature script of input %d: %w", idx, err)
+	}
+	for _, data := range existing {
+		addSig(data)
+	}
+	submitted, err := pushes(sigScript)
+	if err != nil {
+		return 0, false, fmt.Errorf("error parsing submitted signature script of input %d: %w", idx, err)
+	}
+	for _, data := range submitted {
+		if addSig(data) {
+			added++
+		}
+	}
+
+	builder := txscript.NewScriptBuilder()
+	var n int
+	for _, sig := range sigs {
+		if sig == nil {
+			continue
+		}
+		builder.AddData(sig)
+		if n++; n == s.threshold {
+			break
+		}
+	}
+	builder.AddData(s.script)
+	tx.TxIn[idx].SignatureScript, err = builder.Script()
+	if err != nil {
+		return 0, false, fmt.Errorf("error building signature script: %w", err)
+	}
+	return added, n == s.threshold, nil
+}
+
+// multisigDir is the directory of the wallet database for the multisig
+// script.
+func multisigDir(dataDir string, script []byte, chainParams *chaincfg.Params) string {
+	h := sha256.Sum256(script)
+	return filepath.Join(dataDir, chainParams.Name, "multisig", hex.EncodeToString(h[:8]))
+}
+
+// createMultisigWallet creates the wallet database for the multisig script.
+// dcrwallet only tracks the outputs of an imported multisig script if it has
+// one of the script's keys, so the database is a watch-only wallet of one of
+// the cosigners' accounts, with the first external address derived.
+func createMultisigWallet(walletDir string, ms *multisigScript, gapLimit uint32, chainParams *chaincfg.Params, log dex.Logger) (err error) {
+	if err := createWatchOnlyWallet(walletDir, ms.xpubs[0], chainParams, log); err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(walletDir)
+		}
+	}()
+	db, err := wallet.OpenDB(dbDriver, filepath.Join(walletDir, walletDbName))
+	if err != nil {
+		return fmt.Errorf("wallet.OpenDB error: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Errorf("Error closing database: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	dcrw, err := wallet.Open(ctx, newWalletConfig(db, chainParams, gapLimit))
+	if err != nil {
+		return fmt.Errorf("wallet.Open error: %w", err)
+	}
+	if err := dcrw.SyncLastReturnedAddress(ctx, defaultAcct, udb.ExternalBranch, 0); err != nil {
+		return fmt.Errorf("error deriving the account's first address: %w", err)
+	}
+	if err := dcrw.ImportScript(ctx, ms.script); err != nil && !errors.Is(err, walleterrors.Exist) {
+		return fmt.Errorf("error importing multisig script: %w", err)
+	}
+	return nil
+}
+
+// multisigCreditLister is satisfied by *extendedWallet.
+type multisigCreditLister interface {
+	UnspentMultisigCreditsForAddress(ctx context.Context, p2shAddr *stdaddr.AddressScriptHashV0) ([]*udb.MultisigCredit, error)
+}
+
+// multisigSPVWallet is an SPV wallet that watches a multisig script.
+type multisigSPVWallet struct {
+	*spvWallet
+	ms *multisigScript
+}
+
+var _ Wallet = (*multisigSPVWallet)(nil)
+
+// openMultisigWallet opens the SPV wallet for the configured multisig script,
+// creating it on first use.
+func openMultisigWallet(settings map[string]string, dataDir string, gapLimit uint32, chainParams *chaincfg.Params, log dex.Logger) (*multisigSPVWallet, error) {
+	ms, err := newMultisigScript(settings, chainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := multisigDir(dataDir, ms.script, chainParams)
+	exists, err := walletExists(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := createMultisigWallet(dir, ms, gapLimit, chainParams, log); err != nil {
+			return nil, err
+		}
+	}
+
+	spvw := &spvWallet{
+		dir:         dir,
+		chainParams: chainParams,
+		log:         log.SubLogger("SPV"),
+		blockCache: blockCache{
+			blocks: make(map[chainhash.Hash]*cachedBlock),
+		},
+		tipChan:  make(chan *block, 16),
+		gapLimit: gapLimit,
+	}
+	spvw.setAccounts(false)
+	return &multisigSPVWallet{spvWallet: spvw, ms: ms}, nil
+}
+
+// AccountUnlocked is always false. The wallet has no private keys.
+func (w *multisigSPVWallet) AccountUnlocked(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+// Reconfigure requires a restart if the wallet type or multisig script
+// changes.
+func (w *multisigSPVWallet) Reconfigure(_ context.Context, cfg *asset.WalletConfig, _ dex.Network, _ string) (bool, error) {
+	if cfg.Type != WalletTypeMultisig {
+		return true, nil
+	}
+	ms, err := newMultisigScript(cfg.Settings, w.chainParams)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(ms.script, w.ms.script), nil
+}
+
+// unspentCredits are the unspent outputs of the multisig script.
+func (w *multisigSPVWallet) unspentCredits(ctx context.Context) ([]*udb.MultisigCredit, error) {
+	lister, is := w.dcrWallet.(multisigCreditLister)
+	if !is {
+		return nil, errors.New("wallet does not track multisig outputs")
+	}
+	return lister.UnspentMultisigCreditsForAddress(ctx, w.ms.addr)
+}
+
+// multisigRequest is a send that is waiting for the cosigners' signatures.
+type multisigRequest struct {
+	req *asset.PSBTRequest
+	// tx has the signatures collected so far.
+	tx        *wire.MsgTx
+	recipient string
+	amount    uint64
+	fees      uint64
+}
+
+// MultisigWallet is a Decred wallet for an m-of-n multisig address of the
+// cosigners' accounts. The wallet can receive, and sends are presented to the
+// cosigners as unsigned transactions, through the same requests as the
+// external signer of a Bitcoin wallet. Each cosigner signs the transaction,
+// e.g. with dcrwallet's signrawtransaction after importing the multisig
+// script, and submits it. The transaction is broadcast once every input has
+// the threshold number of signatures. The wallet can't trade, post bonds, or
+// buy tickets.
+type MultisigWallet struct {
+	*ExchangeWallet
+	ms *multisigSPVWallet
+
+	mtx     sync.Mutex
+	pending map[string]*multisigRequest
+}
+
+var _ asset.ExternalSigner = (*MultisigWallet)(nil)
+
+// Unlock is a no-op. A multisig wallet has no keys to unlock.
+func (w *MultisigWallet) Unlock([]byte) error {
+	return nil
+}
+
+// Lock is a no-op. A multisig wallet has no keys to lock.
+func (w *MultisigWallet) Lock() error {
+	return nil
+}
+
+// Locked is always false. A multisig wallet has no keys to unlock.
+func (w *MultisigWallet) Locked() bool {
+	return false
+}
+
+// reserved are the outputs spent by pending requests. The mutex must be held.
+func (w *MultisigWallet) reserved() map[wire.OutPoint]bool {
+	ops := make(map[wire.OutPoint]bool)
+	for _, r := range w.pending {
+		for _, txIn := range r.tx.TxIn {
+			ops[txIn.PreviousOutPoint] = true
+		}
+	}
+	return ops
+}
+
+// Balance is the value of the unspent outputs of the multisig script. Outputs
+// that are spent by a transaction waiting for signatures are locked.
+func (w *MultisigWallet) Balance() (*asset.Balance, error) {
+	credits, err := w.ms.unspentCredits(w.ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.mtx.Lock()
+	reserved := w.reserved()
+	w.mtx.Unlock()
+	bal := &asset.Balance{Other: make(map[asset.BalanceCategory]asset.CustomBalance)}
+	for _, c := range credits {
+		if reserved[*c.OutPoint] {
+			bal.Locked += uint64(c.Amount)
+		} else {
+			bal.Available += uint64(c.Amount)
+		}
+	}
+	return bal, nil
+}
+
+// DepositAddress returns the multisig address.
+func (w *MultisigWallet) DepositAddress() (string, error) {
+	return w.ms.ms.addr.String(), nil
+}
+
+// NewAddress returns the multisig address.
+func (w *MultisigWallet) NewAddress() (string, error) {
+	return w.DepositAddress()
+}
+
+// OwnsDepositAddress is true for the multisig address.
+func (w *MultisigWallet) OwnsDepositAddress(address string) (bool, error) {
+	if _, err := stdaddr.DecodeAddress(address, w.chainParams); err != nil {
+		return false, err
+	}
+	return address == w.ms.ms.addr.String(), nil
+}
+
+// buildTx creates an unsigned transaction that pays value to the address from
+// the multisig outputs that aren't spent by a pending request. Change is paid
+// back to the multisig address. If subtract is true, the fees are subtracted
+// from value. The mutex must be held.
+func (w *MultisigWallet) buildTx(address string, value, feeRate uint64, subtract bool) (tx *wire.MsgTx, sent, fees uint64, err error) {
+	if value == 0 {
+		return nil, 0, 0, errors.New("cannot send zero")
+	}
+	addr, err := stdaddr.DecodeAddress(address, w.chainParams)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid address: %s", address)
+	}
+	credits, err := w.ms.unspentCredits(w.ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	reserved := w.reserved()
+	sort.Slice(credits, func(i, j int) bool { return credits[i].Amount > credits[j].Amount })
+
+	payScriptVer, payScript := addr.PaymentScript()
+	tx = wire.NewMsgTx()
+	tx.AddTxOut(newTxOut(int64(value), payScriptVer, payScript))
+	size := uint64(tx.SerializeSize())
+	inputSize := w.ms.ms.inputSize()
+
+	var sum uint64
+	for _, c := range credits {
+		if reserved[*c.OutPoint] {
+			continue
+		}
+		tx.AddTxIn(wire.NewTxIn(c.OutPoint, int64(c.Amount), nil))
+		sum += uint64(c.Amount)
+		size += inputSize
+		if subtract && sum >= value || !subtract && sum >= value+size*feeRate {
+			break
+		}
+	}
+	fees = size * feeRate
+	if subtract {
+		if sum < value {
+			return nil, 0, 0, fmt.Errorf("%w: %s available, %s requested", asset.ErrInsufficientBalance, amount(sum), amount(value))
+		}
+		if fees >= value || dexdcr.IsDustVal(uint64(tx.TxOut[0].SerializeSize()), value-fees, feeRate) {
+			return nil, 0, 0, fmt.Errorf("fees of %s leave dust from %s", amount(fees), amount(value))
+		}
+		tx.TxOut[0].Value = int64(value - fees)
+	} else if sum < value+fees {
+		return nil, 0, 0, fmt.Errorf("%w: %s available, %s plus %s fees requested", asset.ErrInsufficientBalance, amount(sum), amount(value), amount(fees))
+	}
+
+	sent = uint64(tx.TxOut[0].Value)
+	changeScriptVer, changeScript := w.ms.ms.addr.PaymentScript()
+	if remaining := sum - sent - fees; remaining > dexdcr.P2SHOutputSize*feeRate {
+		change := remaining - dexdcr.P2SHOutputSize*feeRate
+		if !dexdcr.IsDustVal(dexdcr.P2SHOutputSize, change, feeRate) {
+			tx.AddTxOut(newTxOut(int64(change), changeScriptVer, changeScript))
+			fees += dexdcr.P2SHOutputSize * feeRate
+			return tx, sent, fees, nil
+		}
+	}
+	return tx, sent, sum - sent, nil
+}
+
+// request adds a request for the cosigners' signatures of a send. The
+// transaction is broadcast when the last required signature is submitted, so
+// asset.ErrAwaitingSignature is returned.
+func (w *MultisigWallet) request(address string, value, feeRate uint64, subtract bool) (asset.Coin, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	tx, sent, fees, err := w.buildTx(address, value, w.feeRateWithFallback(feeRate), subtract)
+	if err != nil {
+		return nil, err
+	}
+	txHex, err := msgTxToHex(tx)
+	if err != nil {
+		return nil, err
+	}
+	req := &asset.PSBTRequest{
+		ID:    tx.TxHash().String(),
+		PSBT:  txHex,
+		Stamp: uint64(time.Now().UnixMilli()),
+	}
+	w.pending[req.ID] = &multisigRequest{
+		req:       req,
+		tx:        tx,
+		recipient: address,
+		amount:    sent,
+		fees:      fees,
+	}
+	w.log.Infof("Send of %s to %s is waiting for cosigner signatures in transaction %s", amount(sent), address, req.ID)
+	w.emit.ActionRequired(req.ID, asset.ActionIDSignPSBT, req)
+	return nil, asset.ErrAwaitingSignature
+}
+
+// Send requests the cosigners' signatures of a transaction that sends the
+// exact value to the address. asset.ErrAwaitingSignature is returned.
+func (w *MultisigWallet) Send(address string, value, feeRate uint64) (asset.Coin, error) {
+	return w.request(address, value, feeRate, false)
+}
+
+// Withdraw requests the cosigners' signatures of a transaction that sends the
+// value, less fees, to the address. asset.ErrAwaitingSignature is returned.
+func (w *MultisigWallet) Withdraw(address string, value, feeRate uint64) (asset.Coin, error) {
+	return w.request(address, value, feeRate, true)
+}
+
+// EstimateSendTxFee estimates the fees of a send from the multisig outputs.
+func (w *MultisigWallet) EstimateSendTxFee(address string, sendAmount, feeRate uint64, subtract, _ bool) (fee uint64, isValidAddress bool, err error) {
+	isValidAddress = w.ValidateAddress(address)
+	if !isValidAddress {
+		address = w.ms.ms.addr.String()
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	_, _, fee, err = w.buildTx(address, sendAmount, w.feeRateWithFallback(feeRate), subtract)
+	return fee, isValidAddress, err
+}
+
+// PendingPSBTs returns the sends that are waiting for cosigner signatures.
+// Each request's PSBT field is the hex-encoded transaction, with the
+// signatures collected so far. Part of the asset.ExternalSigner interface.
+func (w *MultisigWallet) PendingPSBTs() []*asset.PSBTRequest {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	reqs := make([]*asset.PSBTRequest, 0, len(w.pending))
+	for _, r := range w.pending {
+		reqs = append(reqs, r.req)
+	}
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Stamp < reqs[j].Stamp })
+	return reqs
+}
+
+// SubmitSignedPSBT adds a cosigner's signatures to a pending send. signedTx
+// is the hex-encoded transaction. The transaction is broadcast once it has the
+// threshold number of signatures. Otherwise, the request is updated with the
+// signatures so far, for the next cosigner. Part of the asset.ExternalSigner
+// interface.
+func (w *MultisigWallet) SubmitSignedPSBT(id, signedTx string) error {
+	tx, err := msgTxFromHex(signedTx)
+	if err != nil {
+		return fmt.Errorf("error decoding transaction: %w", err)
+	}
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	r, found := w.pending[id]
+	if !found {
+		return fmt.Errorf("no pending request with ID %s", id)
+	}
+	// The hash of a Decred transaction doesn't commit to the signature
+	// scripts.
+	if tx.TxHash() != r.tx.TxHash() {
+		return errors.New("the signed transaction does not match the request")
+	}
+
+	merged := r.tx.Copy()
+	var added int
+	complete := true
+	for i, txIn := range tx.TxIn {
+		n, inputComplete, err := w.ms.ms.addSignatures(merged, i, txIn.SignatureScript)
+		if err != nil {
+			return err
+		}
+		added += n
+		complete = complete && inputComplete
+	}
+	if added == 0 {
+		return errors.New("the transaction has no new signatures")
+	}
+	r.tx = merged
+	if !complete {
+		txHex, err := msgTxToHex(merged)
+		if err != nil {
+			return err
+		}
+		w.log.Infof("Transaction %s needs more cosigner signatures", id)
+		r.req.PSBT = txHex
+		w.emit.ActionRequired(id, asset.ActionIDSignPSBT, r.req)
+		return nil
+	}
+
+	txHash, err := w.wallet.SendRawTransaction(w.ctx, merged, false)
+	if err != nil {
+		return fmt.Errorf("error broadcasting signed transaction: %w", err)
+	}
+	delete(w.pending, id)
+	w.emit.ActionResolved(id)
+
+	txType := asset.Send
+	if r.recipient == w.ms.ms.addr.String() {
+		txType = asset.SelfSend
+	}
+	w.addTxToHistory(&asset.WalletTransaction{
+		Type:      txType,
+		ID:        txHash.String(),
+		Amount:    r.amount,
+		Fees:      r.fees,
+		Recipient: &r.recipient,
+	}, txHash, true)
+	return nil
+}
+
+// FundOrder is not supported by multisig wallets.
+func (w *MultisigWallet) FundOrder(*asset.Order) (asset.Coins, []dex.Bytes, uint64, error) {
+	return nil, nil, 0, errMultisigTrading
+}
+
+// FundMultiOrder is not supported by multisig wallets.
+func (w *MultisigWallet) FundMultiOrder(*asset.MultiOrder, uint64) ([]asset.Coins, [][]dex.Bytes, uint64, error) {
+	return nil, nil, 0, errMultisigTrading
+}
+
+// MaxOrder is not supported by multisig wallets.
+func (w *MultisigWallet) MaxOrder(*asset.MaxOrderForm) (*asset.SwapEstimate, error) {
+	return nil, errMultisigTrading
+}
+
+// PreSwap is not supported by multisig wallets.
+func (w *MultisigWallet) PreSwap(*asset.PreSwapForm) (*asset.PreSwap, error) {
+	return nil, errMultisigTrading
+}
+
+// MakeBondTx is not supported by multisig wallets.
+func (w *MultisigWallet) MakeBondTx(uint16, uint64, uint64, time.Time, *secp256k1.PrivateKey, []byte) (*asset.Bond, func(), error) {
+	return nil, nil, errMultisigTrading
+}
+
+// PurchaseTickets is not supported by multisig wallets.
+func (w *MultisigWallet) PurchaseTickets(int, uint64) error {
+	return errMultisigTrading
+}
+
+// SetVotingPreferences is not supported by multisig wallets.
+func (w *MultisigWallet) SetVotingPreferences(map[string]string, map[string]string, map[string]string) error {
+	return errMultisigTrading
+}
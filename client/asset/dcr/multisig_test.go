@@ -0,0 +1,275 @@
+//go:build !harness && !vspd
+
+package dcr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrwallet/v5/wallet/udb"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/sign"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+)
+
+// tCosigners creates the account keys of n cosigners.
+func tCosigners(t *testing.T, n int, chainParams *chaincfg.Params) []*hdkeychain.ExtendedKey {
+	t.Helper()
+	keys := make([]*hdkeychain.ExtendedKey, n)
+	for i := range keys {
+		seed := make([]byte, hdkeychain.RecommendedSeedLen)
+		seed[0] = byte(i + 1)
+		master, err := hdkeychain.NewMaster(seed, chainParams)
+		if err != nil {
+			t.Fatalf("NewMaster error: %v", err)
+		}
+		keys[i] = master
+	}
+	return keys
+}
+
+func tMultisigSettings(keys []*hdkeychain.ExtendedKey, threshold string) map[string]string {
+	xpubs := make([]string, len(keys))
+	for i, k := range keys {
+		xpubs[i] = k.Neuter().String()
+	}
+	return map[string]string{
+		"multisigxpubs":     strings.Join(xpubs, ","),
+		"multisigthreshold": threshold,
+	}
+}
+
+func TestNewMultisigScript(t *testing.T) {
+	keys := tCosigners(t, 3, tChainParams)
+
+	ms, err := newMultisigScript(tMultisigSettings(keys, "2"), tChainParams)
+	if err != nil {
+		t.Fatalf("newMultisigScript error: %v", err)
+	}
+	if ms.threshold != 2 || len(ms.pubKeys) != 3 {
+		t.Fatalf("wrong script: threshold = %d, keys = %d", ms.threshold, len(ms.pubKeys))
+	}
+	// The script doesn't depend on the order of the keys.
+	reversed, err := newMultisigScript(tMultisigSettings([]*hdkeychain.ExtendedKey{keys[2], keys[1], keys[0]}, "2"), tChainParams)
+	if err != nil {
+		t.Fatalf("newMultisigScript error: %v", err)
+	}
+	if reversed.addr.String() != ms.addr.String() {
+		t.Fatalf("address depends on key order: %s != %s", reversed.addr, ms.addr)
+	}
+
+	for name, settings := range map[string]map[string]string{
+		"one key":           tMultisigSettings(keys[:1], "1"),
+		"zero threshold":    tMultisigSettings(keys, "0"),
+		"threshold > keys":  tMultisigSettings(keys, "4"),
+		"duplicate key":     tMultisigSettings([]*hdkeychain.ExtendedKey{keys[0], keys[0]}, "1"),
+		"private key":       {"multisigxpubs": keys[0].String() + "," + keys[1].Neuter().String(), "multisigthreshold": "1"},
+		"invalid threshold": tMultisigSettings(keys, "two"),
+	} {
+		if _, err := newMultisigScript(settings, tChainParams); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
+
+func TestOpenMultisigWallet(t *testing.T) {
+	chainParams := chaincfg.SimNetParams()
+	keys := tCosigners(t, 3, chainParams)
+	settings := tMultisigSettings(keys, "2")
+	dataDir := t.TempDir()
+
+	w, err := openMultisigWallet(settings, dataDir, 0, chainParams, tLogger)
+	if err != nil {
+		t.Fatalf("error creating multisig wallet: %v", err)
+	}
+	if exists, err := walletExists(w.dir); err != nil || !exists {
+		t.Fatalf("wallet database not created: exists = %t, err = %v", exists, err)
+	}
+	// Opening again uses the existing database.
+	if _, err := openMultisigWallet(settings, dataDir, 0, chainParams, tLogger); err != nil {
+		t.Fatalf("error reopening multisig wallet: %v", err)
+	}
+
+	reconfigure := func(walletType string, settings map[string]string) (bool, error) {
+		return w.Reconfigure(tCtx, &asset.WalletConfig{
+			Type:     walletType,
+			Settings: settings,
+		}, 0, "")
+	}
+	if restart, err := reconfigure(WalletTypeMultisig, settings); err != nil || restart {
+		t.Fatalf("unexpected restart or error for same script: restart = %t, err = %v", restart, err)
+	}
+	if restart, _ := reconfigure(WalletTypeWatchOnly, settings); !restart {
+		t.Fatal("no restart for new wallet type")
+	}
+	if restart, err := reconfigure(WalletTypeMultisig, tMultisigSettings(keys, "3")); err != nil || !restart {
+		t.Fatalf("expected restart for new threshold: restart = %t, err = %v", restart, err)
+	}
+	if _, err := reconfigure(WalletTypeMultisig, tMultisigSettings(keys[:1], "1")); err == nil {
+		t.Fatal("no error for invalid settings")
+	}
+}
+
+func TestMultisigSend(t *testing.T) {
+	keys := tCosigners(t, 3, tChainParams)
+	ms, err := newMultisigScript(tMultisigSettings(keys, "2"), tChainParams)
+	if err != nil {
+		t.Fatalf("newMultisigScript error: %v", err)
+	}
+
+	spvw, dcrw := tNewSpvWallet()
+	spvw.chainParams = tChainParams
+	spvw.setAccounts(false)
+	msw := &multisigSPVWallet{spvWallet: spvw, ms: ms}
+	emitC := make(chan asset.WalletNotification, 16)
+	ew, err := unconnectedWallet(&asset.WalletConfig{
+		Type:    WalletTypeMultisig,
+		DataDir: t.TempDir(),
+		Emit:    asset.NewWalletEmitter(emitC, BipID, tLogger),
+	}, &walletConfig{}, tChainParams, tLogger, 0)
+	if err != nil {
+		t.Fatalf("unconnectedWallet error: %v", err)
+	}
+	ew.wallet = msw
+	ew.ctx = tCtx
+	w := &MultisigWallet{ExchangeWallet: ew, ms: msw, pending: make(map[string]*multisigRequest)}
+
+	_, pkScript := ms.addr.PaymentScript()
+	for i, amt := range []dcrutil.Amount{1e8, 2e8} {
+		dcrw.multisigCredits = append(dcrw.multisigCredits, &udb.MultisigCredit{
+			OutPoint: wire.NewOutPoint(&chainhash.Hash{byte(i + 1)}, 0, wire.TxTreeRegular),
+			MSScript: ms.script,
+			M:        2,
+			N:        3,
+			Amount:   amt,
+		})
+	}
+
+	if addr, _ := w.DepositAddress(); addr != ms.addr.String() {
+		t.Fatalf("wrong deposit address %s", addr)
+	}
+	bal, err := w.Balance()
+	if err != nil {
+		t.Fatalf("Balance error: %v", err)
+	}
+	if bal.Available != 3e8 || bal.Locked != 0 {
+		t.Fatalf("wrong balance %+v", bal)
+	}
+
+	recipient, _ := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(make([]byte, 20), tChainParams)
+	if _, err := w.Send(recipient.String(), 4e8, 10); !errors.Is(err, asset.ErrInsufficientBalance) {
+		t.Fatalf("expected insufficient balance, got %v", err)
+	}
+	if _, err := w.Send(recipient.String(), 15e7, 10); !errors.Is(err, asset.ErrAwaitingSignature) {
+		t.Fatalf("expected ErrAwaitingSignature, got %v", err)
+	}
+	reqs := w.PendingPSBTs()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	req := reqs[0]
+	tx, err := msgTxFromHex(req.PSBT)
+	if err != nil {
+		t.Fatalf("error decoding request: %v", err)
+	}
+	// The larger output covers the send, and the change goes back to the
+	// multisig address.
+	if len(tx.TxIn) != 1 || len(tx.TxOut) != 2 || tx.TxOut[0].Value != 15e7 {
+		t.Fatalf("wrong transaction: %d inputs, %d outputs", len(tx.TxIn), len(tx.TxOut))
+	}
+	if string(tx.TxOut[1].PkScript) != string(pkScript) {
+		t.Fatal("change is not paid to the multisig address")
+	}
+	if bal, _ := w.Balance(); bal.Available != 1e8 || bal.Locked != 2e8 {
+		t.Fatalf("wrong balance with a pending send %+v", bal)
+	}
+
+	cosign := func(key *hdkeychain.ExtendedKey, tx *wire.MsgTx) string {
+		t.Helper()
+		branch, _ := key.Child(udb.ExternalBranch)
+		child, _ := branch.Child(0)
+		priv, err := child.SerializedPrivKey()
+		if err != nil {
+			t.Fatalf("SerializedPrivKey error: %v", err)
+		}
+		signed := tx.Copy()
+		for i := range signed.TxIn {
+			sig, err := sign.RawTxInSignature(signed, i, ms.script, txscript.SigHashAll,
+				priv, dcrec.STEcdsaSecp256k1)
+			if err != nil {
+				t.Fatalf("RawTxInSignature error: %v", err)
+			}
+			signed.TxIn[i].SignatureScript, _ = txscript.NewScriptBuilder().
+				AddData(sig).AddData(ms.script).Script()
+		}
+		txHex, err := msgTxToHex(signed)
+		if err != nil {
+			t.Fatalf("msgTxToHex error: %v", err)
+		}
+		return txHex
+	}
+
+	if err := w.SubmitSignedPSBT(req.ID, req.PSBT); err == nil {
+		t.Fatal("no error for an unsigned transaction")
+	}
+	other := tx.Copy()
+	other.TxOut[0].Value--
+	if err := w.SubmitSignedPSBT(req.ID, cosign(keys[0], other)); err == nil {
+		t.Fatal("no error for a different transaction")
+	}
+
+	first := cosign(keys[2], tx)
+	if err := w.SubmitSignedPSBT(req.ID, first); err != nil {
+		t.Fatalf("error submitting first signature: %v", err)
+	}
+	if dcrw.publishedTx != nil {
+		t.Fatal("transaction broadcast with one signature")
+	}
+	if err := w.SubmitSignedPSBT(req.ID, first); err == nil {
+		t.Fatal("no error for a resubmitted signature")
+	}
+	if err := w.SubmitSignedPSBT(req.ID, cosign(keys[0], tx)); err != nil {
+		t.Fatalf("error submitting second signature: %v", err)
+	}
+	if dcrw.publishedTx == nil {
+		t.Fatal("transaction not broadcast with two signatures")
+	}
+	if len(w.PendingPSBTs()) != 0 {
+		t.Fatal("request not removed after broadcast")
+	}
+
+	published := dcrw.publishedTx
+	vm, err := txscript.NewEngine(pkScript, published, 0, txscript.ScriptVerifyCleanStack, 0, nil)
+	if err != nil {
+		t.Fatalf("NewEngine error: %v", err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("signed input does not validate: %v", err)
+	}
+}
+
+func TestMultisigWalletRejectsTrading(t *testing.T) {
+	w := &MultisigWallet{ExchangeWallet: &ExchangeWallet{}}
+	checks := map[string]error{}
+	_, _, _, checks["FundOrder"] = w.FundOrder(&asset.Order{})
+	_, _, _, checks["FundMultiOrder"] = w.FundMultiOrder(&asset.MultiOrder{}, 0)
+	_, checks["MaxOrder"] = w.MaxOrder(&asset.MaxOrderForm{})
+	_, checks["PreSwap"] = w.PreSwap(&asset.PreSwapForm{})
+	_, _, checks["MakeBondTx"] = w.MakeBondTx(0, 0, 0, time.Time{}, nil, nil)
+	checks["PurchaseTickets"] = w.PurchaseTickets(1, 0)
+	checks["SetVotingPreferences"] = w.SetVotingPreferences(nil, nil, nil)
+	for method, err := range checks {
+		if !errors.Is(err, errMultisigTrading) {
+			t.Errorf("%s: expected errMultisigTrading, got %v", method, err)
+		}
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"sync"
 	"time"
 
@@ -150,12 +149,43 @@ func (w *NativeWallet) FundsMixingStats() (*asset.FundsMixingStats, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &asset.FundsMixingStats{
+	unmixedFunds, err := w.spvw.AccountBalance(w.ctx, 0, defaultAccountName)
+	if err != nil {
+		return nil, err
+	}
+	stats := &asset.FundsMixingStats{
 		Enabled:                 w.mixing.Load(),
 		UnmixedBalanceThreshold: smalletCSPPSplitPoint,
 		MixedFunds:              toAtoms(mixedFunds.Total),
 		TradingFunds:            toAtoms(tradingFunds.Total),
-	}, nil
+		UnmixedFunds:            toAtoms(unmixedFunds.Total),
+	}
+	if stats.Enabled {
+		unspents, err := w.spvw.Unspents(w.ctx, defaultAccountName)
+		if err != nil {
+			return nil, err
+		}
+		sinfo, err := w.spvw.StakeInfo(w.ctx)
+		if err != nil {
+			return nil, err
+		}
+		var unmixed []dcrutil.Amount
+		for _, u := range unspents {
+			if u.Confirmations >= mixMinConf {
+				unmixed = append(unmixed, dcrutil.Amount(toAtoms(u.Amount)))
+			}
+		}
+		stats.QueuedDenominations = queuedDenominations(unmixed, sinfo.Sdiff)
+	}
+	ms := &w.spvw.mixStatus
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+	if !ms.lastMix.IsZero() {
+		stats.LastMix = uint64(ms.lastMix.Unix())
+	}
+	stats.MixFailures = ms.failures
+	stats.LastMixError = ms.lastErr
+	return stats, nil
 }
 
 // startFundsMixer starts the funds mixer.  This will error if the wallet does
@@ -231,9 +261,11 @@ func (w *NativeWallet) mixFunds() {
 // runSimnetMixer just sends all funds from the mixed account to the default
 // account, after a short delay.
 func (w *NativeWallet) runSimnetMixer(ctx context.Context) {
-	if err := w.transferAccount(ctx, mixedAccountName, defaultAccountName); err != nil {
+	err := w.transferAccount(ctx, mixedAccountName, defaultAccountName)
+	if err != nil {
 		w.log.Errorf("error transferring funds while disabling mixing: %w", err)
 	}
+	w.spvw.mixStatus.record(err)
 }
 
 // transferAccount sends all funds from the fromAccts to the toAcct.
@@ -271,42 +303,6 @@ func (w *NativeWallet) transferAccount(ctx context.Context, toAcct string, fromA
 	return nil
 }
 
-// birthdayBlockHeight performs a binary search for the last block with a
-// timestamp lower than the provided birthday.
-func (w *NativeWallet) birthdayBlockHeight(ctx context.Context, bday uint64) int32 {
-	tipHeight := w.cachedBestBlock().height
-	var err error
-	firstBlockAfterBday := sort.Search(int(tipHeight), func(blockHeightI int) bool {
-		if err != nil { // if we see any errors, just give up.
-			return false
-		}
-		var blockHash *chainhash.Hash
-		if blockHash, err = w.spvw.GetBlockHash(ctx, int64(blockHeightI)); err != nil {
-			w.log.Errorf("Error getting block hash for height %d: %v", blockHeightI, err)
-			return false
-		}
-		stamp, err := w.spvw.BlockTimestamp(ctx, blockHash)
-		if err != nil {
-			w.log.Errorf("Error getting block header for hash %s: %v", blockHash, err)
-			return false
-		}
-		return uint64(stamp.Unix()) >= bday
-	})
-	if err != nil {
-		w.log.Errorf("Error encountered searching for birthday block: %v", err)
-		firstBlockAfterBday = 1
-	}
-	if firstBlockAfterBday == int(tipHeight) {
-		w.log.Errorf("Birthday %d is from the future", bday)
-		return 0
-	}
-
-	if firstBlockAfterBday == 0 {
-		return 0
-	}
-	return int32(firstBlockAfterBday - 1)
-}
-
 // Rescan initiates a rescan of the wallet from height 0. Rescan only blocks
 // long enough for the first asynchronous update, either an error or after the
 // first 2000 blocks are scanned.
@@ -334,10 +330,14 @@ func (w *NativeWallet) Rescan(ctx context.Context, bday uint64) (err error) {
 		bdayHeight = 0
 	}
 
+	tipHeight := w.cachedBestBlock().height
+	tracker := asset.NewRescanTracker(w.emit, uint64(bdayHeight), uint64(tipHeight))
+
 	setProgress := func(height int32) {
 		w.rescan.Lock()
 		w.rescan.progress = &rescanProgress{scannedThrough: int64(height)}
 		w.rescan.Unlock()
+		tracker.Update(uint64(height), uint64(w.cachedBestBlock().height))
 	}
 
 	c := make(chan wallet.RescanProgress)
@@ -373,6 +373,7 @@ func (w *NativeWallet) Rescan(ctx context.Context, bday uint64) (err error) {
 		// Rescans are quick. Timeouts > a second are probably too high, but
 		// we'll give ample buffer.
 		timeout := time.After(time.Minute)
+		var rescanErr error
 		for {
 			select {
 			case u, open := <-c:
@@ -381,20 +382,27 @@ func (w *NativeWallet) Rescan(ctx context.Context, bday uint64) (err error) {
 						sendErr(nil)
 					} else {
 						// We never saw an update.
-						sendErr(errors.New("rescan finished without a progress update"))
+						rescanErr = errors.New("rescan finished without a progress update")
+						sendErr(rescanErr)
 					}
+					tracker.Done(rescanErr)
 					return
 				}
 				sendErr(u.Err) // Hopefully nil, causing Rescan to return nil.
 				timeout = nil  // Any update cancels timeout.
 				if u.Err == nil {
 					setProgress(u.ScannedThrough)
+				} else {
+					rescanErr = u.Err
 				}
 			case <-timeout:
-				sendErr(errors.New("rescan never sent progress updates"))
+				err := errors.New("rescan never sent progress updates")
+				sendErr(err)
+				tracker.Done(err)
 				return
 			case <-ctx.Done():
 				sendErr(ctx.Err())
+				tracker.Done(ctx.Err())
 				return
 			}
 		}
@@ -0,0 +1,126 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dcr
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const methodRescanWallet = "rescanwallet"
+
+var _ asset.Rescanner = (*ExchangeWallet)(nil)
+
+// blockRescanner is implemented by wallet backends that rescan on request.
+type blockRescanner interface {
+	// rescanFrom rescans from the height, and blocks until it is done.
+	rescanFrom(ctx context.Context, height int32) error
+}
+
+// rescanFrom rescans the blockchain from the height with dcrwallet's
+// rescanwallet RPC, which returns when the rescan is done.
+func (w *rpcWallet) rescanFrom(ctx context.Context, height int32) error {
+	return w.rpcClientRawRequest(ctx, methodRescanWallet, anylist{height}, nil)
+}
+
+// blockTimestamp is the time of the block.
+func (dcr *ExchangeWallet) blockTimestamp(ctx context.Context, blockHash *chainhash.Hash) (time.Time, error) {
+	if spvw, is := dcr.wallet.(*spvWallet); is {
+		return spvw.BlockTimestamp(ctx, blockHash)
+	}
+	hdr, err := dcr.wallet.GetBlockHeader(ctx, blockHash)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return hdr.Timestamp, nil
+}
+
+// birthdayBlockHeight performs a binary search for the last block with a
+// timestamp lower than the provided birthday.
+func (dcr *ExchangeWallet) birthdayBlockHeight(ctx context.Context, bday uint64) int32 {
+	tipHeight := dcr.cachedBestBlock().height
+	var err error
+	firstBlockAfterBday := sort.Search(int(tipHeight), func(blockHeightI int) bool {
+		if err != nil { // if we see any errors, just give up.
+			return false
+		}
+		var blockHash *chainhash.Hash
+		if blockHash, err = dcr.wallet.GetBlockHash(ctx, int64(blockHeightI)); err != nil {
+			dcr.log.Errorf("Error getting block hash for height %d: %v", blockHeightI, err)
+			return false
+		}
+		stamp, err := dcr.blockTimestamp(ctx, blockHash)
+		if err != nil {
+			dcr.log.Errorf("Error getting block header for hash %s: %v", blockHash, err)
+			return false
+		}
+		return uint64(stamp.Unix()) >= bday
+	})
+	if err != nil {
+		dcr.log.Errorf("Error encountered searching for birthday block: %v", err)
+		firstBlockAfterBday = 1
+	}
+	if firstBlockAfterBday == int(tipHeight) {
+		dcr.log.Errorf("Birthday %d is from the future", bday)
+		return 0
+	}
+
+	if firstBlockAfterBday == 0 {
+		return 0
+	}
+	return int32(firstBlockAfterBday - 1)
+}
+
+// Rescan rescans the blockchain from the birthday with an external dcrwallet.
+// The rescan continues asynchronously. The wallet doesn't report progress
+// during the rescan, so only the start and end are emitted. Part of the
+// asset.Rescanner interface.
+func (dcr *ExchangeWallet) Rescan(ctx context.Context, bday uint64) error {
+	rescanner, is := dcr.wallet.(blockRescanner)
+	if !is {
+		return errors.New("wallet does not support rescanning")
+	}
+	dcr.rescan.Lock()
+	rescanInProgress := dcr.rescan.progress != nil
+	if !rescanInProgress {
+		dcr.rescan.progress = &rescanProgress{}
+	}
+	dcr.rescan.Unlock()
+	if rescanInProgress {
+		return errors.New("rescan already in progress")
+	}
+
+	if bday == 0 {
+		bday = defaultWalletBirthdayUnix
+	}
+	startHeight := dcr.birthdayBlockHeight(ctx, bday)
+	tipHeight := dcr.cachedBestBlock().height
+	// SyncStatus reports the wallet as unsynced during the rescan.
+	dcr.rescan.Lock()
+	dcr.rescan.progress = &rescanProgress{scannedThrough: int64(startHeight)}
+	dcr.rescan.Unlock()
+	tracker := asset.NewRescanTracker(dcr.emit, uint64(startHeight), uint64(tipHeight))
+
+	dcr.wg.Add(1)
+	go func() {
+		defer dcr.wg.Done()
+		err := rescanner.rescanFrom(ctx, startHeight)
+		dcr.rescan.Lock()
+		dcr.rescan.progress = nil
+		dcr.rescan.Unlock()
+		if err != nil {
+			dcr.log.Errorf("Rescan error: %v", err)
+		} else {
+			dcr.log.Infof("Completed rescan from block %d", startHeight)
+			dcr.receiveTxLastQuery.Store(0)
+		}
+		tracker.Done(err)
+	}()
+	return nil
+}
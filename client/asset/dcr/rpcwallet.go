@@ -764,6 +764,7 @@ func (w *rpcWallet) GetTransaction(ctx context.Context, txHash *chainhash.Hash)
 		BlockHash:     tx.BlockHash,
 		Details:       tx.Details,
 		MsgTx:         msgTx,
+		Time:          tx.Time,
 	}, nil
 }
 
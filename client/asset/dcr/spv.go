@@ -138,6 +138,12 @@ func (w *extendedWallet) TxDetails(ctx context.Context, txHash *chainhash.Hash)
 	return wallet.UnstableAPI(w.Wallet).TxDetails(ctx, txHash)
 }
 
+// UnspentMultisigCreditsForAddress exposes the
+// (UnstableApi).UnspentMultisigCreditsForAddress method.
+func (w *extendedWallet) UnspentMultisigCreditsForAddress(ctx context.Context, p2shAddr *stdaddr.AddressScriptHashV0) ([]*udb.MultisigCredit, error) {
+	return wallet.UnstableAPI(w.Wallet).UnspentMultisigCreditsForAddress(ctx, p2shAddr)
+}
+
 // MainTipChangedNotifications returns a channel for receiving main tip change
 // notifications, along with a function to close the channel when it is no
 // longer needed.
@@ -167,6 +173,8 @@ type spvWallet struct {
 
 	spvMtx sync.RWMutex
 	spv    spvSyncer // *spv.Syncer
+
+	mixStatus mixStatus
 }
 
 var _ Wallet = (*spvWallet)(nil)
@@ -860,6 +868,7 @@ func (w *spvWallet) GetTransaction(ctx context.Context, txHash *chainhash.Hash)
 
 	ret := WalletTransaction{
 		MsgTx: &txd.MsgTx,
+		Time:  txd.Received.Unix(),
 	}
 
 	if txd.Block.Height != -1 {
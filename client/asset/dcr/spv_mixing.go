@@ -2,7 +2,11 @@ package dcr
 
 import (
 	"context"
+	"sort"
+	"sync"
+	"time"
 
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrwallet/v5/wallet/udb"
 	"github.com/decred/dcrd/dcrutil/v4"
 )
@@ -34,12 +38,77 @@ const (
 	mixedAccountName      = "mixed"
 	mixedAccountBranch    = udb.InternalBranch
 	tradingAccountName    = "dextrading"
+
+	// maxMixCount is the most outputs of one denomination that dcrwallet
+	// will create from a single unmixed output in a mix.
+	maxMixCount = 4
+	// mixMinConf is the number of confirmations an unmixed output needs to
+	// be mixed.
+	mixMinConf = 2
 )
 
+// mixStatus is the outcome of the most recent mixing cycles.
+type mixStatus struct {
+	mtx      sync.Mutex
+	lastMix  time.Time
+	failures uint32
+	lastErr  string
+}
+
+// record records the outcome of a mixing cycle.
+func (s *mixStatus) record(err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if err != nil {
+		s.failures++
+		s.lastErr = err.Error()
+		return
+	}
+	s.lastMix = time.Now()
+	s.failures = 0
+	s.lastErr = ""
+}
+
+// mixDenomination is the denomination and number of mixed outputs that
+// dcrwallet will create from an unmixed output. This follows the choice of
+// denomination in dcrwallet's MixOutput, ignoring the fee. Denominations at
+// least as large as the ticket price are skipped in favor of the more active
+// smaller ones. The count is zero if the output is too small to mix.
+func mixDenomination(amt, sdiff dcrutil.Amount) (dcrutil.Amount, uint32) {
+	for i, sp := range splitPoints {
+		if i != len(splitPoints)-1 && sp >= sdiff {
+			continue
+		}
+		if amt < sp {
+			continue
+		}
+		return sp, uint32(min(amt/sp, maxMixCount))
+	}
+	return 0, 0
+}
+
+// queuedDenominations estimates the mixed outputs that will be created from
+// the unmixed outputs in the next mixing cycle, largest first.
+func queuedDenominations(unmixed []dcrutil.Amount, sdiff dcrutil.Amount) []*asset.MixDenomination {
+	counts := make(map[dcrutil.Amount]uint32)
+	for _, amt := range unmixed {
+		if denom, n := mixDenomination(amt, sdiff); n > 0 {
+			counts[denom] += n
+		}
+	}
+	denoms := make([]*asset.MixDenomination, 0, len(counts))
+	for denom, n := range counts {
+		denoms = append(denoms, &asset.MixDenomination{Value: uint64(denom), Count: n})
+	}
+	sort.Slice(denoms, func(i, j int) bool { return denoms[i].Value > denoms[j].Value })
+	return denoms
+}
+
 func (w *spvWallet) mix(ctx context.Context) {
 	mixedAccount, err := w.AccountNumber(ctx, mixedAccountName)
 	if err != nil {
 		w.log.Errorf("unable to look up mixed account: %v", err)
+		w.mixStatus.record(err)
 		return
 	}
 
@@ -61,4 +130,5 @@ func (w *spvWallet) mix(ctx context.Context) {
 	if err = w.MixAccount(ctx, unmixedAccount, mixedAccount, mixedAccountBranch); err != nil {
 		w.log.Errorf("Error mixing account: %v", err)
 	}
+	w.mixStatus.record(err)
 }
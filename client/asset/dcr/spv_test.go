@@ -84,6 +84,8 @@ type tDcrWallet struct {
 	lockedOutpoint   *wire.OutPoint
 	stakeInfo        wallet.StakeInfoData
 	rescanUpdates    []wallet.RescanProgress
+	multisigCredits  []*udb.MultisigCredit
+	publishedTx      *wire.MsgTx
 }
 
 func (w *tDcrWallet) KnownAddress(ctx context.Context, a stdaddr.Address) (wallet.KnownAddress, error) {
@@ -159,6 +161,7 @@ func (w *tDcrWallet) PublishTransaction(ctx context.Context, tx *wire.MsgTx, n w
 	if w.publishTxErr != nil {
 		return nil, w.publishTxErr
 	}
+	w.publishedTx = tx
 	h := tx.TxHash()
 	return &h, nil
 }
@@ -439,6 +442,10 @@ func (w *tDcrWallet) NewVSPClient(cfg wallet.VSPClientConfig, log slog.Logger, d
 	return nil, nil
 }
 
+func (w *tDcrWallet) UnspentMultisigCreditsForAddress(ctx context.Context, p2shAddr *stdaddr.AddressScriptHashV0) ([]*udb.MultisigCredit, error) {
+	return w.multisigCredits, nil
+}
+
 func tNewSpvWallet() (*spvWallet, *tDcrWallet) {
 	dcrw := &tDcrWallet{
 		blockInfo:      make(map[int32]*wallet.BlockInfo),
@@ -989,10 +996,12 @@ func TestRescan(t *testing.T) {
 	const tipHeight = 20
 
 	spvw, dcrw := tNewSpvWallet()
+	log := dex.StdOutLogger("T", dex.LevelInfo)
 	w := &NativeWallet{
 		ExchangeWallet: &ExchangeWallet{
 			wallet: spvw,
-			log:    dex.StdOutLogger("T", dex.LevelInfo),
+			log:    log,
+			emit:   asset.NewWalletEmitter(make(chan asset.WalletNotification, 128), BipID, log),
 		},
 		spvw: spvw,
 	}
@@ -1035,3 +1044,33 @@ func TestRescan(t *testing.T) {
 	w.rescan.Unlock()
 	ensureErr("rescan already in progress", []wallet.RescanProgress{{}})
 }
+
+func TestQueuedDenominations(t *testing.T) {
+	const sdiff = 1 << 31 // between the 1 << 30 and 1 << 32 split points
+	unmixed := []dcrutil.Amount{
+		1 << 17,       // too small to mix
+		3<<30 + 1<<20, // 3 of 1 << 30
+		1 << 36,       // 4 of 1 << 30, since larger denominations are skipped
+		1 << 20,       // 1 of 1 << 20
+		1<<18 + 1<<17, // 1 of 1 << 18
+	}
+	denoms := queuedDenominations(unmixed, sdiff)
+	expected := []*asset.MixDenomination{
+		{Value: 1 << 30, Count: 7},
+		{Value: 1 << 20, Count: 1},
+		{Value: 1 << 18, Count: 1},
+	}
+	if len(denoms) != len(expected) {
+		t.Fatalf("expected %d denominations, got %d", len(expected), len(denoms))
+	}
+	for i, d := range denoms {
+		if *d != *expected[i] {
+			t.Fatalf("denomination %d: expected %d of %d, got %d of %d", i, expected[i].Count, expected[i].Value, d.Count, d.Value)
+		}
+	}
+
+	// The smallest denomination is used even if the ticket price is lower.
+	if denom, n := mixDenomination(1<<19, 1<<17); denom != 1<<18 || n != 2 {
+		t.Fatalf("expected 2 of %d, got %d of %d", 1<<18, n, denom)
+	}
+}
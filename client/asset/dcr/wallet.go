@@ -185,6 +185,8 @@ type WalletTransaction struct {
 	BlockHash     string
 	Details       []walletjson.GetTransactionDetailsResult
 	MsgTx         *wire.MsgTx
+	// Time is when the wallet first saw the transaction, in unix seconds.
+	Time int64
 }
 
 // tipNotifier can be implemented if the Wallet is able to provide a stream of
@@ -0,0 +1,244 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dcr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/config"
+	"decred.org/dcrwallet/v5/wallet"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/hdkeychain/v3"
+)
+
+const WalletTypeWatchOnly = "watchonly"
+
+var (
+	errWatchOnly = errors.New("watch-only wallet cannot spend funds")
+
+	watchOnlyOpts = []*asset.ConfigOption{
+		{
+			Key:         "xpub",
+			DisplayName: "Extended public key",
+			Description: "The extended public key of the account to watch, " +
+				"e.g. from dcrwallet's getmasterpubkey.",
+			Required: true,
+		},
+		{
+			Key:          "gaplimit",
+			DisplayName:  "Address Gap Limit",
+			Description:  "The gap limit for used address discovery",
+			DefaultValue: wallet.DefaultGapLimit,
+		},
+	}
+)
+
+// watchOnlyConfig is the configuration for a watch-only wallet.
+type watchOnlyConfig struct {
+	XPub string `ini:"xpub"`
+}
+
+// parseXPub checks that the extended key is a public key for the network.
+func parseXPub(xpub string, chainParams *chaincfg.Params) (*hdkeychain.ExtendedKey, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub, chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extended public key: %w", err)
+	}
+	if key.IsPrivate() {
+		return nil, errors.New("extended key is private. provide the public key")
+	}
+	return key, nil
+}
+
+// watchOnlyDir is the directory of the wallet database for the extended
+// public key. Each key has its own database, so that changing the key in the
+// settings doesn't mix the history of two accounts.
+func watchOnlyDir(dataDir, xpub string, chainParams *chaincfg.Params) string {
+	h := sha256.Sum256([]byte(xpub))
+	return filepath.Join(dataDir, chainParams.Name, "watchonly", hex.EncodeToString(h[:8]))
+}
+
+// createWatchOnlyWallet creates the watching-only wallet database for the
+// extended public key.
+func createWatchOnlyWallet(walletDir, xpub string, chainParams *chaincfg.Params, log dex.Logger) (err error) {
+	if err := initLogging(filepath.Dir(filepath.Dir(walletDir))); err != nil {
+		return fmt.Errorf("error initializing dcrwallet logging: %w", err)
+	}
+	if err := checkCreateDir(walletDir); err != nil {
+		return fmt.Errorf("checkCreateDir error: %w", err)
+	}
+	// The directory is specific to the key, and there is no database yet, so
+	// the remnants of a failed attempt can be removed.
+	defer func() {
+		if err != nil {
+			_ = os.RemoveAll(walletDir)
+		}
+	}()
+	dbPath := filepath.Join(walletDir, walletDbName)
+	db, err := wallet.CreateDB(dbDriver, dbPath)
+	if err != nil {
+		return fmt.Errorf("CreateDB error: %w", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Errorf("Error closing database: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err = wallet.CreateWatchOnly(ctx, db, xpub, nil, chainParams); err != nil {
+		return fmt.Errorf("wallet.CreateWatchOnly error: %w", err)
+	}
+	return nil
+}
+
+// watchOnlyWallet is an SPV wallet that only has the extended public key of
+// its account.
+type watchOnlyWallet struct {
+	*spvWallet
+	xpub string
+}
+
+var _ Wallet = (*watchOnlyWallet)(nil)
+
+// openWatchOnlyWallet opens the watch-only SPV wallet for the configured
+// extended public key, creating it on first use.
+func openWatchOnlyWallet(settings map[string]string, dataDir string, gapLimit uint32, chainParams *chaincfg.Params, log dex.Logger) (*watchOnlyWallet, error) {
+	var cfg watchOnlyConfig
+	if err := config.Unmapify(settings, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing watch-only wallet settings: %w", err)
+	}
+	if _, err := parseXPub(cfg.XPub, chainParams); err != nil {
+		return nil, err
+	}
+
+	dir := watchOnlyDir(dataDir, cfg.XPub, chainParams)
+	exists, err := walletExists(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := createWatchOnlyWallet(dir, cfg.XPub, chainParams, log); err != nil {
+			return nil, err
+		}
+	}
+
+	spvw := &spvWallet{
+		dir:         dir,
+		chainParams: chainParams,
+		log:         log.SubLogger("SPV"),
+		blockCache: blockCache{
+			blocks: make(map[chainhash.Hash]*cachedBlock),
+		},
+		tipChan:  make(chan *block, 16),
+		gapLimit: gapLimit,
+	}
+	spvw.setAccounts(false)
+	return &watchOnlyWallet{spvWallet: spvw, xpub: cfg.XPub}, nil
+}
+
+// AccountUnlocked is always false. The account has no private keys, and
+// dcrwallet errors for accounts without their own passphrase.
+func (w *watchOnlyWallet) AccountUnlocked(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+// Reconfigure requires a restart if the wallet type or extended public key
+// changes.
+func (w *watchOnlyWallet) Reconfigure(_ context.Context, cfg *asset.WalletConfig, _ dex.Network, _ string) (bool, error) {
+	if cfg.Type != WalletTypeWatchOnly {
+		return true, nil
+	}
+	var newCfg watchOnlyConfig
+	if err := config.Unmapify(cfg.Settings, &newCfg); err != nil {
+		return false, err
+	}
+	if newCfg.XPub == w.xpub {
+		return false, nil
+	}
+	if _, err := parseXPub(newCfg.XPub, w.chainParams); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WatchOnlyWallet is a Decred wallet created from an extended public key. It
+// can receive, and reports the balance and transaction history of the
+// account, but it has no keys, so it can't trade, post bonds, buy tickets, or
+// send. Every method that would sign a transaction is rejected.
+type WatchOnlyWallet struct {
+	*ExchangeWallet
+}
+
+// Unlock is a no-op. A watch-only wallet has no keys to unlock.
+func (w *WatchOnlyWallet) Unlock([]byte) error {
+	return nil
+}
+
+// Lock is a no-op. A watch-only wallet has no keys to lock.
+func (w *WatchOnlyWallet) Lock() error {
+	return nil
+}
+
+// Locked is always false. A watch-only wallet has no keys to unlock.
+func (w *WatchOnlyWallet) Locked() bool {
+	return false
+}
+
+// FundOrder is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) FundOrder(*asset.Order) (asset.Coins, []dex.Bytes, uint64, error) {
+	return nil, nil, 0, errWatchOnly
+}
+
+// FundMultiOrder is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) FundMultiOrder(*asset.MultiOrder, uint64) ([]asset.Coins, [][]dex.Bytes, uint64, error) {
+	return nil, nil, 0, errWatchOnly
+}
+
+// MakeBondTx is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) MakeBondTx(uint16, uint64, uint64, time.Time, *secp256k1.PrivateKey, []byte) (*asset.Bond, func(), error) {
+	return nil, nil, errWatchOnly
+}
+
+// Send is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) Send(string, uint64, uint64) (asset.Coin, error) {
+	return nil, errWatchOnly
+}
+
+// Withdraw is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) Withdraw(string, uint64, uint64) (asset.Coin, error) {
+	return nil, errWatchOnly
+}
+
+// PurchaseTickets is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) PurchaseTickets(int, uint64) error {
+	return errWatchOnly
+}
+
+// SendTransaction is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) SendTransaction([]byte) ([]byte, error) {
+	return nil, errWatchOnly
+}
+
+// Refund is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) Refund(dex.Bytes, dex.Bytes, uint64) (dex.Bytes, error) {
+	return nil, errWatchOnly
+}
+
+// SetVotingPreferences is not supported by watch-only wallets.
+func (w *WatchOnlyWallet) SetVotingPreferences(map[string]string, map[string]string, map[string]string) error {
+	return errWatchOnly
+}
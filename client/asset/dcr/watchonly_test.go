@@ -0,0 +1,92 @@
+//go:build !harness && !vspd
+
+package dcr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/hdkeychain/v3"
+)
+
+func TestOpenWatchOnlyWallet(t *testing.T) {
+	chainParams := chaincfg.SimNetParams()
+	seed := make([]byte, hdkeychain.RecommendedSeedLen)
+	master, err := hdkeychain.NewMaster(seed, chainParams)
+	if err != nil {
+		t.Fatalf("NewMaster error: %v", err)
+	}
+	xpub := master.Neuter().String()
+
+	dataDir := t.TempDir()
+	open := func(xpub string) (*watchOnlyWallet, error) {
+		return openWatchOnlyWallet(map[string]string{"xpub": xpub}, dataDir, 0, chainParams, tLogger)
+	}
+
+	if _, err := open(""); err == nil {
+		t.Fatal("no error for missing xpub")
+	}
+	if _, err := open(master.String()); err == nil {
+		t.Fatal("no error for private key")
+	}
+
+	w, err := open(xpub)
+	if err != nil {
+		t.Fatalf("error creating watch-only wallet: %v", err)
+	}
+	if exists, err := walletExists(w.dir); err != nil || !exists {
+		t.Fatalf("wallet database not created: exists = %t, err = %v", exists, err)
+	}
+	if w.Accounts().PrimaryAccount != defaultAccountName {
+		t.Fatalf("wrong primary account %q", w.Accounts().PrimaryAccount)
+	}
+	if unlocked, err := w.AccountUnlocked(tCtx, defaultAccountName); err != nil || unlocked {
+		t.Fatalf("expected locked account without error: unlocked = %t, err = %v", unlocked, err)
+	}
+	// Opening again uses the existing database.
+	if _, err := open(xpub); err != nil {
+		t.Fatalf("error reopening watch-only wallet: %v", err)
+	}
+
+	reconfigure := func(walletType, xpub string) (bool, error) {
+		return w.Reconfigure(tCtx, &asset.WalletConfig{
+			Type:     walletType,
+			Settings: map[string]string{"xpub": xpub},
+		}, 0, "")
+	}
+	if restart, err := reconfigure(WalletTypeWatchOnly, xpub); err != nil || restart {
+		t.Fatalf("unexpected restart or error for same key: restart = %t, err = %v", restart, err)
+	}
+	if restart, _ := reconfigure(walletTypeSPV, xpub); !restart {
+		t.Fatal("no restart for new wallet type")
+	}
+	child, _ := master.Child(hdkeychain.HardenedKeyStart)
+	if restart, err := reconfigure(WalletTypeWatchOnly, child.Neuter().String()); err != nil || !restart {
+		t.Fatalf("expected restart for new key: restart = %t, err = %v", restart, err)
+	}
+	if _, err := reconfigure(WalletTypeWatchOnly, "xpub"); err == nil {
+		t.Fatal("no error for invalid key")
+	}
+}
+
+func TestWatchOnlyWalletRejectsSpends(t *testing.T) {
+	w := &WatchOnlyWallet{&ExchangeWallet{}}
+	checks := map[string]error{}
+	_, _, _, checks["FundOrder"] = w.FundOrder(&asset.Order{})
+	_, _, _, checks["FundMultiOrder"] = w.FundMultiOrder(&asset.MultiOrder{}, 0)
+	_, _, checks["MakeBondTx"] = w.MakeBondTx(0, 0, 0, time.Time{}, nil, nil)
+	_, checks["Send"] = w.Send("", 0, 0)
+	_, checks["Withdraw"] = w.Withdraw("", 0, 0)
+	_, checks["SendTransaction"] = w.SendTransaction(nil)
+	_, checks["Refund"] = w.Refund(nil, nil, 0)
+	checks["PurchaseTickets"] = w.PurchaseTickets(1, 0)
+	checks["SetVotingPreferences"] = w.SetVotingPreferences(nil, nil, nil)
+	for method, err := range checks {
+		if !errors.Is(err, errWatchOnly) {
+			t.Errorf("%s: expected errWatchOnly, got %v", method, err)
+		}
+	}
+}
@@ -184,7 +184,13 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 		AssetID:                  BipID,
 	}
 
-	return btc.BTCCloneWallet(cloneCFG)
+	w, err := btc.BTCCloneWallet(cloneCFG)
+	if err != nil {
+		return nil, err
+	}
+	// dogecoind mines by ancestor fee rate, so orders can be accelerated with
+	// child-pays-for-parent transactions.
+	return &btc.ExchangeWalletAccelerator{ExchangeWalletFullNode: w}, nil
 }
 
 // NOTE: btc.(*baseWallet).feeRate calls the local and external fee estimators
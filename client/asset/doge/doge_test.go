@@ -0,0 +1,30 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package doge
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+func TestWalletAccelerates(t *testing.T) {
+	cfg := &asset.WalletConfig{
+		Type: walletTypeRPC,
+		Settings: map[string]string{
+			"rpcuser":     "user",
+			"rpcpassword": "pass",
+		},
+		DataDir: t.TempDir(),
+		Emit:    asset.NewWalletEmitter(make(chan asset.WalletNotification, 1), BipID, dex.StdOutLogger("T", dex.LevelOff)),
+	}
+	w, err := NewWallet(cfg, dex.StdOutLogger("T", dex.LevelOff), dex.Regtest)
+	if err != nil {
+		t.Fatalf("NewWallet error: %v", err)
+	}
+	if _, is := w.(asset.Accelerator); !is {
+		t.Fatalf("doge wallet is not an asset.Accelerator")
+	}
+}
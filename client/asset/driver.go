@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	"decred.org/dcrdex/dex"
@@ -20,6 +21,19 @@ type nettedToken struct {
 	netSupportedAssetVersions map[dex.Network][]uint32
 }
 
+// onNetwork is a copy of the Token with the network's contract address and
+// asset versions, or nil if the token is not available on the network.
+func (nt *nettedToken) onNetwork(net dex.Network) *Token {
+	addr, exists := nt.erc20NetAddrs[net]
+	if !exists {
+		return nil
+	}
+	token := *nt.Token
+	token.ContractAddress = addr
+	token.SupportedAssetVersions = nt.netSupportedAssetVersions[net]
+	return &token
+}
+
 var (
 	driversMtx sync.RWMutex
 	drivers    = make(map[uint32]Driver)
@@ -55,6 +69,46 @@ type Creator interface {
 	Create(*CreateWalletParams) error
 }
 
+// CustomTokenRegistrar is implemented by the Drivers of assets that allow
+// user-defined tokens.
+type CustomTokenRegistrar interface {
+	// RegisterCustomToken validates the CustomToken and returns the token's
+	// asset ID and Token. The Driver must be able to open a wallet for the
+	// token once RegisterCustomToken returns without an error. Registering
+	// the same token again must not be an error.
+	RegisterCustomToken(ct *CustomToken, net dex.Network) (uint32, *Token, error)
+}
+
+// EVMChainIdentifier is implemented by the Drivers of EVM-compatible assets.
+type EVMChainIdentifier interface {
+	// EVMChainID is the chain ID of the asset's blockchain on the network.
+	EVMChainID(net dex.Network) (chainID int64, found bool)
+}
+
+// EVMChainAsset finds the asset ID of the EVM-compatible asset with the chain
+// ID on the network.
+func EVMChainAsset(chainID int64, net dex.Network) (uint32, error) {
+	driversMtx.RLock()
+	defer driversMtx.RUnlock()
+	var assetIDs []uint32
+	for assetID, drv := range drivers {
+		identifier, is := drv.(EVMChainIdentifier)
+		if !is {
+			continue
+		}
+		if id, found := identifier.EVMChainID(net); found && id == chainID {
+			assetIDs = append(assetIDs, assetID)
+		}
+	}
+	switch len(assetIDs) {
+	case 0:
+		return 0, fmt.Errorf("no asset for EVM chain ID %d on %s", chainID, net)
+	case 1:
+		return assetIDs[0], nil
+	}
+	return 0, fmt.Errorf("EVM chain ID %d is used by more than one asset on %s", chainID, net)
+}
+
 func withDriver(assetID uint32, f func(Driver) error) error {
 	driversMtx.RLock()
 	drv, ok := drivers[assetID]
@@ -113,6 +167,49 @@ func RegisterToken(
 	}
 }
 
+// RegisterCustomToken registers a user-defined token at runtime. Unlike
+// RegisterToken, the token is only registered for the specified network, which
+// should be the network passed to SetNetwork. The token's symbol will be its
+// lower-cased Symbol with the parent asset's symbol as a suffix, e.g. abc.eth.
+func RegisterCustomToken(ct *CustomToken, net dex.Network) (uint32, error) {
+	if ct.Symbol == "" || strings.ContainsAny(ct.Symbol, ". ") {
+		return 0, fmt.Errorf("invalid token symbol %q", ct.Symbol)
+	}
+	if ct.Name == "" {
+		return 0, errors.New("no token name")
+	}
+	driversMtx.Lock()
+	defer driversMtx.Unlock()
+	drv, found := drivers[ct.ParentID]
+	if !found {
+		return 0, fmt.Errorf("unknown parent asset %d", ct.ParentID)
+	}
+	registrar, is := drv.(CustomTokenRegistrar)
+	if !is {
+		return 0, fmt.Errorf("%s does not support custom tokens", dex.BipIDSymbol(ct.ParentID))
+	}
+	symbol := strings.ToLower(ct.Symbol) + "." + dex.BipIDSymbol(ct.ParentID)
+	if assetID, found := dex.BipSymbolID(symbol); found {
+		return 0, fmt.Errorf("symbol %s is already used by asset %d", symbol, assetID)
+	}
+	tokenID, token, err := registrar.RegisterCustomToken(ct, net)
+	if err != nil {
+		return 0, err
+	}
+	if _, exists := tokens[tokenID]; exists {
+		return 0, fmt.Errorf("token %d already exists", tokenID)
+	}
+	if err := dex.RegisterCustomSymbol(tokenID, symbol); err != nil {
+		return 0, err
+	}
+	tokens[tokenID] = &nettedToken{
+		Token:                     token,
+		erc20NetAddrs:             map[dex.Network]string{net: token.ContractAddress},
+		netSupportedAssetVersions: map[dex.Network][]uint32{net: token.SupportedAssetVersions},
+	}
+	return tokenID, nil
+}
+
 // WalletExists will be true if the specified wallet exists.
 func WalletExists(assetID uint32, walletType, dataDir string, settings map[string]string, net dex.Network) (exists bool, err error) {
 	return exists, withDriver(assetID, func(drv Driver) error {
@@ -176,6 +273,20 @@ type RegisteredAsset struct {
 
 // Assets returns a list of information about supported assets.
 func Assets() map[uint32]*RegisteredAsset {
+	return registeredAssets(func(nt *nettedToken) *Token { return nt.Token })
+}
+
+// NetworkAssets is like Assets, but only lists the tokens available on the
+// network, with the network's contract addresses and asset versions. Unlike
+// SetNetwork, NetworkAssets does not modify the registry, so it can be used
+// when running multiple networks in one process.
+func NetworkAssets(net dex.Network) map[uint32]*RegisteredAsset {
+	return registeredAssets(func(nt *nettedToken) *Token { return nt.onNetwork(net) })
+}
+
+// registeredAssets lists the drivers and the tokens for which tokenInfo is
+// non-nil.
+func registeredAssets(tokenInfo func(*nettedToken) *Token) map[uint32]*RegisteredAsset {
 	driversMtx.RLock()
 	defer driversMtx.RUnlock()
 	assets := make(map[uint32]*RegisteredAsset, len(drivers))
@@ -187,7 +298,11 @@ func Assets() map[uint32]*RegisteredAsset {
 		}
 	}
 
-	for tokenID, token := range tokens {
+	for tokenID, nt := range tokens {
+		token := tokenInfo(nt)
+		if token == nil {
+			continue
+		}
 		parent, found := assets[token.ParentID]
 		if !found {
 			// should be impossible.
@@ -197,7 +312,7 @@ func Assets() map[uint32]*RegisteredAsset {
 		if parent.Tokens == nil {
 			parent.Tokens = make(map[uint32]*Token, 1)
 		}
-		parent.Tokens[tokenID] = token.Token
+		parent.Tokens[tokenID] = token
 	}
 	return assets
 }
@@ -205,6 +320,16 @@ func Assets() map[uint32]*RegisteredAsset {
 // Asset gets the RegisteredAsset for the specified asset ID. Asset is for
 // base chain assets, not tokens.
 func Asset(assetID uint32) *RegisteredAsset {
+	return registeredAsset(assetID, func(nt *nettedToken) *Token { return nt.Token })
+}
+
+// NetworkAsset is like Asset, but only includes the tokens available on the
+// network. See NetworkAssets.
+func NetworkAsset(net dex.Network, assetID uint32) *RegisteredAsset {
+	return registeredAsset(assetID, func(nt *nettedToken) *Token { return nt.onNetwork(net) })
+}
+
+func registeredAsset(assetID uint32, tokenInfo func(*nettedToken) *Token) *RegisteredAsset {
 	driversMtx.RLock()
 	defer driversMtx.RUnlock()
 	drv := drivers[assetID]
@@ -216,12 +341,15 @@ func Asset(assetID uint32) *RegisteredAsset {
 		Symbol: dex.BipIDSymbol(assetID),
 		Info:   drv.Info(),
 	}
-	for tokenID, token := range tokens {
-		if token.ParentID == assetID {
+	for tokenID, nt := range tokens {
+		if nt.ParentID != assetID {
+			continue
+		}
+		if token := tokenInfo(nt); token != nil {
 			if ra.Tokens == nil {
 				ra.Tokens = make(map[uint32]*Token, 1)
 			}
-			ra.Tokens[tokenID] = token.Token
+			ra.Tokens[tokenID] = token
 		}
 	}
 	return ra
@@ -239,6 +367,18 @@ func TokenInfo(assetID uint32) *Token {
 	return nt.Token
 }
 
+// NetworkTokenInfo is like TokenInfo, but returns nil if the token is not
+// available on the network. See NetworkAssets.
+func NetworkTokenInfo(net dex.Network, assetID uint32) *Token {
+	driversMtx.RLock()
+	defer driversMtx.RUnlock()
+	nt := tokens[assetID]
+	if nt == nil {
+		return nil
+	}
+	return nt.onNetwork(net)
+}
+
 // Info returns the WalletInfo for the specified asset, if supported. Info only
 // returns WalletInfo for base chain assets, not tokens.
 func Info(assetID uint32) (*WalletInfo, error) {
@@ -267,7 +407,9 @@ func UnitInfo(assetID uint32) (dex.UnitInfo, error) {
 }
 
 // SetNetwork will filter registered assets for those available on the specified
-// network. SetNetwork need only be called once during initialization.
+// network. SetNetwork need only be called once during initialization. When
+// running multiple networks in one process, SetNetwork must not be called, and
+// the Network* functions are used instead.
 func SetNetwork(net dex.Network) {
 	for assetID, nt := range tokens {
 		addr, exists := nt.erc20NetAddrs[net]
@@ -0,0 +1,80 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+// TokenAllowances returns the allowance for each version of the token's swap
+// contract.
+// Part of the asset.TokenAllowanceManager interface.
+func (w *TokenWallet) TokenAllowances() ([]*asset.TokenAllowance, error) {
+	vers := make([]uint32, 0, len(w.versionedContracts))
+	for ver := range w.versionedContracts {
+		vers = append(vers, ver)
+	}
+	sort.Slice(vers, func(i, j int) bool { return vers[i] < vers[j] })
+
+	allowances := make([]*asset.TokenAllowance, 0, len(vers))
+	for _, ver := range vers {
+		contract := w.versionedContracts[ver]
+		allowance, err := w.tokenAllowance(ver)
+		if err != nil {
+			return nil, fmt.Errorf("error getting allowance for swap contract version %d: %w", ver, err)
+		}
+		w.approvalsMtx.RLock()
+		_, pending := w.pendingApprovals[contract]
+		w.approvalsMtx.RUnlock()
+		a := &asset.TokenAllowance{
+			Version:  ver,
+			Contract: contract.String(),
+			Pending:  pending,
+		}
+		if allowance.Cmp(unlimitedAllowanceReplenishThreshold) >= 0 {
+			a.Unlimited = true
+		} else if atoms := new(big.Int).Div(allowance, w.evmify(1)); !atoms.IsUint64() {
+			a.Amount = math.MaxUint64
+		} else {
+			a.Amount = w.atomize(allowance)
+		}
+		allowances = append(allowances, a)
+	}
+	return allowances, nil
+}
+
+// SetTokenAllowance sends a transaction setting the allowance of a version of
+// the token's swap contract to amount atomic units. A zero amount revokes the
+// approval. The token must be approved again with ApproveToken before trading
+// with a tightened allowance.
+// Part of the asset.TokenAllowanceManager interface.
+func (w *TokenWallet) SetTokenAllowance(assetVer uint32, amount uint64, onConfirm func()) (string, error) {
+	contract, found := w.versionedContracts[assetVer]
+	if !found {
+		return "", fmt.Errorf("no contract address found for asset %d contract version %d", w.assetID, assetVer)
+	}
+
+	w.approvalsMtx.RLock()
+	_, pending := w.pendingApprovals[contract]
+	w.approvalsMtx.RUnlock()
+	if pending {
+		return "", asset.ErrApprovalPending
+	}
+
+	allowance := w.evmify(amount)
+	current, err := w.tokenAllowance(assetVer)
+	if err != nil {
+		return "", fmt.Errorf("error getting current allowance: %w", err)
+	}
+	if current.Cmp(allowance) == 0 {
+		return "", fmt.Errorf("allowance is already %d", amount)
+	}
+
+	return w.sendAllowance(assetVer, contract, allowance, onConfirm)
+}
@@ -0,0 +1,76 @@
+//go:build !harness && !rpclive
+
+package eth
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestTokenAllowances(t *testing.T) {
+	w, _, node, shutdown := tassetWallet(usdcEthID)
+	defer shutdown()
+	tw := w.(*TokenWallet)
+	contract := common.Address{0x01}
+	tw.versionedContracts = map[uint32]common.Address{1: contract, 0: {0x02}}
+
+	check := func(amt uint64, unlimited, pending bool) {
+		t.Helper()
+		allowances, err := tw.TokenAllowances()
+		if err != nil {
+			t.Fatalf("TokenAllowances error: %v", err)
+		}
+		if len(allowances) != 2 || allowances[0].Version != 0 || allowances[1].Version != 1 {
+			t.Fatalf("wrong allowances %+v", allowances)
+		}
+		a := allowances[1]
+		if a.Amount != amt || a.Unlimited != unlimited || a.Pending != pending || a.Contract != contract.String() {
+			t.Fatalf("wrong allowance %+v", a)
+		}
+	}
+
+	node.tokenContractor.allow = unlimitedAllowance
+	check(0, true, false)
+	node.tokenContractor.allow = tw.evmify(1e6)
+	check(1e6, false, false)
+	// Too big for a uint64, but not unlimited.
+	node.tokenContractor.allow = new(big.Int).Mul(tw.evmify(1), new(big.Int).Lsh(big.NewInt(1), 65))
+	check(math.MaxUint64, false, false)
+
+	node.tokenContractor.allowErr = errors.New("test error")
+	if _, err := tw.TokenAllowances(); err == nil {
+		t.Fatalf("no error for allowance error")
+	}
+	node.tokenContractor.allowErr = nil
+
+	// Setting the same allowance is an error.
+	node.tokenContractor.allow = tw.evmify(1e6)
+	if _, err := tw.SetTokenAllowance(1, 1e6, nil); err == nil {
+		t.Fatalf("no error for unchanged allowance")
+	}
+	if _, err := tw.SetTokenAllowance(5, 0, nil); err == nil {
+		t.Fatalf("no error for unknown contract version")
+	}
+
+	node.tokenContractor.approveTx = types.NewTx(&types.DynamicFeeTx{})
+	node.tokenContractor.approveEstimate = tokenGasesV1.Approve
+	node.bal = dexeth.GweiToWei(1e9)
+	if _, err := tw.SetTokenAllowance(1, 0, nil); err != nil {
+		t.Fatalf("SetTokenAllowance error: %v", err)
+	}
+	if !node.tokenContractor.approved {
+		t.Fatalf("approval not sent")
+	}
+	check(1e6, false, true)
+
+	if _, err := tw.SetTokenAllowance(1, 5, nil); !errors.Is(err, asset.ErrApprovalPending) {
+		t.Fatalf("wrong error for pending approval: %v", err)
+	}
+}
@@ -0,0 +1,344 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// bondWallet adds the asset.Bonder methods to an ETH wallet. It is only
+// created for networks with a bond contract. See newBondWallet.
+type bondWallet struct {
+	w *ETHWallet
+}
+
+// newBondWallet creates a bondWallet for the ETHWallet, or returns nil if bonds
+// are not supported. Bonds require a deployed bond contract.
+func newBondWallet(w *ETHWallet) *bondWallet {
+	if w.bondContractAddr == (common.Address{}) {
+		return nil
+	}
+	return &bondWallet{w: w}
+}
+
+// ETHBondWallet is an ETHWallet that supports fidelity bonds.
+type ETHBondWallet struct {
+	*ETHWallet
+	*bondWallet
+}
+
+// ETHBridgeBondWallet is an ETHBridgeWallet that supports fidelity bonds.
+type ETHBridgeBondWallet struct {
+	*ETHBridgeWallet
+	*bondWallet
+}
+
+var _ asset.Bonder = (*ETHBondWallet)(nil)
+var _ asset.Bonder = (*ETHBridgeBondWallet)(nil)
+
+// bondState gets the state of a bond from the bond contract.
+func (w *baseWallet) bondState(ctx context.Context, bondID [32]byte) (*dexeth.BondState, error) {
+	data, err := dexeth.PackBondsData(bondID)
+	if err != nil {
+		return nil, fmt.Errorf("error packing bonds call: %w", err)
+	}
+	res, err := w.node.contractBackend().CallContract(ctx, ethereum.CallMsg{To: &w.bondContractAddr, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dexeth.UnpackBondsResult(res)
+}
+
+// checkBondVersion errors if the bond version is not supported.
+func checkBondVersion(ver uint16) error {
+	if ver != dexeth.BondVersion {
+		return fmt.Errorf("only version %d bonds supported", dexeth.BondVersion)
+	}
+	return nil
+}
+
+// BondsFeeBuffer suggests how much extra may be required for the transaction
+// fees part of bond reserves when bond rotation is enabled. The feeRate is
+// in gwei/gas.
+func (b *bondWallet) BondsFeeBuffer(feeRate uint64) uint64 {
+	// Normally we can plan on just 2 parallel "tracks" (single bond overlap
+	// when bonds are expired and waiting to refund) but that may increase
+	// temporarily if target tier is adjusted up.
+	const parallelTracks uint64 = 4
+	return parallelTracks * (dexeth.CreateBondGas + dexeth.RefundBondGas) * feeRate
+}
+
+// SetBondReserves sets the bond reserve amount for the wallet.
+func (b *bondWallet) SetBondReserves(reserves uint64) {
+	b.w.bondReserves.Store(reserves)
+}
+
+// MakeBondTx creates a transaction that calls the bond contract's createBond
+// method with the provided amount, lock time, and account ID. The bond ID is
+// derived from the bond key's public key, and is returned as the Data field
+// of the Bond. The bond is refunded by the address that created it, so there
+// is no backup RedeemTx.
+//
+// The signed transaction uses the next nonce and is queued with the wallet's
+// pending transactions, but is not broadcast until SendTransaction. The
+// returned abandon function releases the nonce if the transaction was never
+// broadcast. The provided fee rate is ignored in favor of the current network
+// fee rates.
+func (b *bondWallet) MakeBondTx(ver uint16, amt, _ uint64, lockTime time.Time, bondKey *secp256k1.PrivateKey, acctID []byte) (*asset.Bond, func(), error) {
+	w := b.w
+	if err := checkBondVersion(ver); err != nil {
+		return nil, nil, err
+	}
+	if until := time.Until(lockTime); until >= 365*12*time.Hour /* ~6 months */ {
+		return nil, nil, fmt.Errorf("that lock time is nuts: %v", lockTime)
+	} else if until < 0 {
+		return nil, nil, fmt.Errorf("that lock time is already passed: %v", lockTime)
+	}
+	if len(acctID) != 32 {
+		return nil, nil, fmt.Errorf("invalid account ID length %d", len(acctID))
+	}
+	if amt == 0 {
+		return nil, nil, errors.New("zero bond amount")
+	}
+
+	var acct [32]byte
+	copy(acct[:], acctID)
+	bondID := dexeth.BondID(bondKey.PubKey().SerializeCompressed())
+	lockTimeSec := uint64(lockTime.Unix())
+	data, err := dexeth.PackCreateBondData(acct, bondID, lockTimeSec)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error packing createBond data: %w", err)
+	}
+
+	maxFeeRate, tipRate, err := w.recommendedMaxFeeRate(w.ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	fees := dexeth.CreateBondGas * dexeth.WeiToGweiCeil(maxFeeRate)
+	// Bond reserves are meant for this, so they are not deducted here.
+	bal, err := w.balance()
+	if err != nil {
+		return nil, nil, err
+	}
+	if bal.Available < amt+fees {
+		return nil, nil, fmt.Errorf("%w: %s available, %s needed for bond and fees", asset.ErrInsufficientBalance,
+			w.amtString(bal.Available), w.amtString(amt+fees))
+	}
+
+	var bondTx *types.Transaction
+	err = w.withNonce(w.ctx, func(nonce *big.Int) (*genTxResult, error) {
+		txOpts, err := w.node.txOpts(w.ctx, amt, dexeth.CreateBondGas, maxFeeRate, tipRate, nonce)
+		if err != nil {
+			return nil, err
+		}
+		bondTx, err = w.node.signTransaction(w.ctx, txOpts, w.bondContractAddr, data)
+		if err != nil {
+			return nil, err
+		}
+		// withNonce holds the nonceMtx.
+		w.unbroadcastBonds[bondTx.Hash()] = true
+		return &genTxResult{
+			tx:     bondTx,
+			txType: asset.CreateBond,
+			amt:    amt,
+			bondInfo: &asset.BondTxInfo{
+				AccountID: acctID,
+				LockTime:  lockTimeSec,
+				BondID:    bondID[:],
+			},
+		}, nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating bond transaction: %w", err)
+	}
+
+	signedTx, err := bondTx.MarshalBinary()
+	if err != nil {
+		b.abandonBondTx(bondTx.Hash())
+		return nil, nil, fmt.Errorf("error serializing bond transaction: %w", err)
+	}
+	unsignedTx, err := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   bondTx.ChainId(),
+		Nonce:     bondTx.Nonce(),
+		GasTipCap: bondTx.GasTipCap(),
+		GasFeeCap: bondTx.GasFeeCap(),
+		Gas:       bondTx.Gas(),
+		To:        bondTx.To(),
+		Value:     bondTx.Value(),
+		Data:      bondTx.Data(),
+	}).MarshalBinary()
+	if err != nil {
+		b.abandonBondTx(bondTx.Hash())
+		return nil, nil, fmt.Errorf("error serializing unsigned bond transaction: %w", err)
+	}
+
+	bond := &asset.Bond{
+		Version:    ver,
+		AssetID:    w.assetID,
+		Amount:     amt,
+		CoinID:     bondTx.Hash().Bytes(),
+		Data:       bondID[:],
+		SignedTx:   signedTx,
+		UnsignedTx: unsignedTx,
+	}
+	abandon := func() {
+		b.abandonBondTx(bondTx.Hash())
+	}
+	return bond, abandon, nil
+}
+
+// abandonBondTx removes a bond tx created by MakeBondTx from the pending txs
+// if it was never broadcast, releasing its nonce.
+func (b *bondWallet) abandonBondTx(txHash common.Hash) {
+	w := b.w
+	w.nonceMtx.Lock()
+	defer w.nonceMtx.Unlock()
+	if !w.unbroadcastBonds[txHash] {
+		w.log.Warnf("Not abandoning bond tx %s, which may have been broadcast", txHash)
+		return
+	}
+	delete(w.unbroadcastBonds, txHash)
+	for i, pendingTx := range w.pendingTxs {
+		if pendingTx.txHash != txHash {
+			continue
+		}
+		w.log.Infof("Abandoning unbroadcast bond transaction %s", txHash)
+		pendingTx.AssumedLost = true
+		w.tryStoreDBTx(pendingTx)
+		w.pendingTxs = append(w.pendingTxs[:i], w.pendingTxs[i+1:]...)
+		// Nothing was sent with this nonce, so it can be reused.
+		if next := new(big.Int).Add(pendingTx.Nonce, big.NewInt(1)); w.pendingNonceAt.Cmp(next) == 0 {
+			w.pendingNonceAt.Set(pendingTx.Nonce)
+		}
+		w.emitTransactionNote(pendingTx.WalletTransaction, false)
+		return
+	}
+}
+
+// RefundBond refunds the bond with the ID in script, which is the Bond.Data
+// returned from MakeBondTx. The bond can only be refunded by the address that
+// created it, after its lock time. The private key is used to verify the bond
+// ID. It is a CoinNotFoundError if the bond is not in the contract or has
+// already been refunded.
+func (b *bondWallet) RefundBond(ctx context.Context, ver uint16, coinID, script []byte, _ uint64, privKey *secp256k1.PrivateKey) (asset.Coin, error) {
+	w := b.w
+	if err := checkBondVersion(ver); err != nil {
+		return nil, err
+	}
+	if len(script) != 32 {
+		return nil, fmt.Errorf("invalid bond ID length %d", len(script))
+	}
+	var bondID [32]byte
+	copy(bondID[:], script)
+	if dexeth.BondID(privKey.PubKey().SerializeCompressed()) != bondID {
+		return nil, asset.ErrIncorrectBondKey
+	}
+
+	bond, err := w.bondState(ctx, bondID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving bond %x: %w", bondID, err)
+	}
+	if bond.Owner == (common.Address{}) || bond.Value.Sign() == 0 {
+		return nil, asset.CoinNotFoundError
+	}
+	if bond.Owner != w.addr {
+		return nil, fmt.Errorf("bond %x was created by %s, not this wallet", bondID, bond.Owner)
+	}
+	if time.Now().Unix() < int64(bond.LockTime) {
+		return nil, fmt.Errorf("bond %x is locked until %v", bondID, time.Unix(int64(bond.LockTime), 0))
+	}
+
+	data, err := dexeth.PackRefundBondData(bondID)
+	if err != nil {
+		return nil, fmt.Errorf("error packing refundBond data: %w", err)
+	}
+	maxFeeRate, tipRate, err := w.recommendedMaxFeeRate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	amt := w.atomize(bond.Value)
+	var tx *types.Transaction
+	err = w.withNonce(ctx, func(nonce *big.Int) (*genTxResult, error) {
+		txOpts, err := w.node.txOpts(ctx, 0, dexeth.RefundBondGas, maxFeeRate, tipRate, nonce)
+		if err != nil {
+			return nil, err
+		}
+		tx, err = w.node.sendTransaction(ctx, txOpts, w.bondContractAddr, data)
+		if err != nil {
+			return nil, err
+		}
+		return &genTxResult{
+			tx:     tx,
+			txType: asset.RedeemBond,
+			amt:    amt,
+			bondInfo: &asset.BondTxInfo{
+				AccountID: bond.AcctID[:],
+				LockTime:  bond.LockTime,
+				BondID:    bondID[:],
+			},
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error sending bond refund transaction: %w", err)
+	}
+	return &coin{id: tx.Hash(), value: amt}, nil
+}
+
+// FindBond finds the bond created by the transaction with coinID, and
+// returns the values used to create it. The bond must not have been
+// refunded.
+func (b *bondWallet) FindBond(ctx context.Context, coinID []byte, _ time.Time) (*asset.BondDetails, error) {
+	w := b.w
+	if err := checkBondVersion(dexeth.BondVersion); err != nil {
+		return nil, err
+	}
+	txHash, err := dexeth.DecodeCoinID(coinID)
+	if err != nil {
+		return nil, err
+	}
+	tx, _, err := w.node.getTransaction(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("error finding bond transaction %s: %w", txHash, err)
+	}
+	if to := tx.To(); to == nil || *to != w.bondContractAddr {
+		return nil, fmt.Errorf("transaction %s is not a bond transaction", txHash)
+	}
+	_, bondID, lockTime, err := dexeth.ParseCreateBondData(tx.Data())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing bond transaction %s: %w", txHash, err)
+	}
+	bond, err := w.bondState(ctx, bondID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving bond %x: %w", bondID, err)
+	}
+	if bond.Owner == (common.Address{}) || bond.Value.Sign() == 0 {
+		return nil, fmt.Errorf("bond %x not found or already refunded", bondID)
+	}
+
+	return &asset.BondDetails{
+		Bond: &asset.Bond{
+			Version: dexeth.BondVersion,
+			AssetID: w.assetID,
+			Amount:  w.atomize(bond.Value),
+			CoinID:  coinID,
+			Data:    bondID[:],
+		},
+		LockTime: time.Unix(int64(lockTime), 0),
+		CheckPrivKey: func(priv *secp256k1.PrivateKey) bool {
+			return dexeth.BondID(priv.PubKey().SerializeCompressed()) == bondID
+		},
+	}, nil
+}
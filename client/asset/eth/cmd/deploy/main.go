@@ -41,9 +41,11 @@ import (
 	"strings"
 
 	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/arbitrum"
 	"decred.org/dcrdex/client/asset/eth"
 	"decred.org/dcrdex/client/asset/polygon"
 	"decred.org/dcrdex/dex"
+	dexarb "decred.org/dcrdex/dex/networks/arbitrum"
 	dexeth "decred.org/dcrdex/dex/networks/eth"
 	dexpolygon "decred.org/dcrdex/dex/networks/polygon"
 	"github.com/ethereum/go-ethereum/common"
@@ -158,6 +160,14 @@ func mainErr() error {
 		if err != nil {
 			return fmt.Errorf("error finding chain config: %v", err)
 		}
+	case "arbitrum":
+		bui = &dexarb.UnitInfo
+		chainCfg, err = arbitrum.ChainConfig(net)
+		if err != nil {
+			return fmt.Errorf("error finding chain config: %v", err)
+		}
+	default:
+		return fmt.Errorf("chain %s not known", chain)
 	}
 
 	switch {
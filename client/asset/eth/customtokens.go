@@ -0,0 +1,144 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// customTokens are user-defined tokens for all EVM chains, registered with
+	// RegisterCustomToken.
+	customTokensMtx sync.RWMutex
+	customTokens    = make(map[uint32]*dexeth.Token)
+)
+
+// customTokenSwapVersion is the only swap contract version for user-defined
+// tokens. The version 0 contracts are deployed for each listed token.
+const customTokenSwapVersion = 1
+
+var _ asset.EVMChainIdentifier = (*Driver)(nil)
+
+// EVMChainID is the Ethereum chain ID on the network. Part of the
+// asset.EVMChainIdentifier interface.
+func (d *Driver) EVMChainID(net dex.Network) (int64, bool) {
+	chainID, found := dexeth.ChainIDs[net]
+	return chainID, found
+}
+
+var _ asset.CustomTokenRegistrar = (*Driver)(nil)
+
+// RegisterCustomToken prepares a user-defined Ethereum ERC20 token.
+// Part of the asset.CustomTokenRegistrar interface.
+func (d *Driver) RegisterCustomToken(ct *asset.CustomToken, net dex.Network) (uint32, *asset.Token, error) {
+	return RegisterCustomToken(ct, net, dexeth.Tokens, "Ethereum token")
+}
+
+// RegisterCustomToken prepares a user-defined ERC20 token for any EVM chain.
+// builtIn are the chain's listed tokens, which can't be registered again as
+// custom tokens. tab is the WalletDefinition.Tab for the token.
+func RegisterCustomToken(ct *asset.CustomToken, net dex.Network, builtIn map[uint32]*dexeth.Token, tab string) (uint32, *asset.Token, error) {
+	if !common.IsHexAddress(ct.Address) {
+		return 0, nil, fmt.Errorf("invalid token contract address %q", ct.Address)
+	}
+	addr := common.HexToAddress(ct.Address)
+	if addr == (common.Address{}) {
+		return 0, nil, errors.New("zero token contract address")
+	}
+	for tokenID, token := range builtIn {
+		if netToken := token.NetTokens[net]; netToken != nil && netToken.Address == addr {
+			return 0, nil, fmt.Errorf("token contract %s is already listed as %s", addr, dex.BipIDSymbol(tokenID))
+		}
+	}
+	for _, ver := range ct.SwapContractVersions {
+		if ver != customTokenSwapVersion {
+			return 0, nil, fmt.Errorf("user-defined tokens only support version %d of the swap contract, not %d", customTokenSwapVersion, ver)
+		}
+	}
+	tokenID := dexeth.CustomTokenID(ct.ParentID, addr)
+	token := dexeth.NewCustomToken(ct.ParentID, ct.Name, ct.Symbol, ct.Decimals, net, addr)
+	if ct.UnitInfo != nil {
+		ui, err := customTokenUnitInfo(ct.UnitInfo, token.UnitInfo)
+		if err != nil {
+			return 0, nil, err
+		}
+		token.UnitInfo = *ui
+	}
+
+	customTokensMtx.Lock()
+	customTokens[tokenID] = token
+	customTokensMtx.Unlock()
+
+	return tokenID, &asset.Token{
+		Token: token.Token,
+		Definition: &asset.WalletDefinition{
+			Type:        walletTypeToken,
+			Tab:         tab,
+			Description: fmt.Sprintf("The user-defined %s ERC20 token.", token.UnitInfo.Conventional.Unit),
+		},
+		ContractAddress:        addr.String(),
+		SupportedAssetVersions: []uint32{customTokenSwapVersion},
+	}, nil
+}
+
+// customTokenUnitInfo checks the user's unit info against the unit info
+// derived from the token's decimals, and fills in any missing fields.
+func customTokenUnitInfo(userUI *dex.UnitInfo, derived dex.UnitInfo) (*dex.UnitInfo, error) {
+	ui := *userUI
+	if ui.Conventional.ConversionFactor == 0 {
+		ui.Conventional.ConversionFactor = derived.Conventional.ConversionFactor
+	} else if ui.Conventional.ConversionFactor != derived.Conventional.ConversionFactor {
+		return nil, fmt.Errorf("conversion factor %d does not match the token's decimals. expected %d",
+			ui.Conventional.ConversionFactor, derived.Conventional.ConversionFactor)
+	}
+	if ui.Conventional.Unit == "" {
+		ui.Conventional.Unit = derived.Conventional.Unit
+	}
+	if ui.AtomicUnit == "" {
+		ui.AtomicUnit = derived.AtomicUnit
+	}
+	for _, d := range ui.Alternatives {
+		if d.Unit == "" || d.ConversionFactor == 0 {
+			return nil, fmt.Errorf("invalid denomination %+v", d)
+		}
+	}
+	ui.FeeRateDenom = derived.FeeRateDenom
+	return &ui, nil
+}
+
+// token gets the listed or user-defined token on the wallet's chain.
+func (w *baseWallet) token(assetID uint32) *dexeth.Token {
+	if token := w.tokens[assetID]; token != nil {
+		return token
+	}
+	customTokensMtx.RLock()
+	defer customTokensMtx.RUnlock()
+	if token := customTokens[assetID]; token != nil && token.ParentID == w.baseChainID {
+		return token
+	}
+	return nil
+}
+
+// allTokens gets all listed and user-defined tokens on the wallet's chain.
+func (w *baseWallet) allTokens() map[uint32]*dexeth.Token {
+	customTokensMtx.RLock()
+	defer customTokensMtx.RUnlock()
+	tokens := make(map[uint32]*dexeth.Token, len(w.tokens)+len(customTokens))
+	for tokenID, token := range w.tokens {
+		tokens[tokenID] = token
+	}
+	for tokenID, token := range customTokens {
+		if token.ParentID == w.baseChainID {
+			tokens[tokenID] = token
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,107 @@
+//go:build !harness && !rpclive
+
+package eth
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRegisterCustomToken(t *testing.T) {
+	_, eth, _, shutdown := tassetWallet(BipID)
+	defer shutdown()
+
+	ct := &asset.CustomToken{
+		ParentID: BipID,
+		Address:  "0x0000000000000000000000000000000000000abc",
+		Symbol:   "abc",
+		Name:     "ABC",
+		Decimals: 18,
+	}
+	tokenID, token, err := (&Driver{}).RegisterCustomToken(ct, dex.Simnet)
+	if err != nil {
+		t.Fatalf("RegisterCustomToken error: %v", err)
+	}
+	if !dexeth.IsCustomTokenID(tokenID) || tokenID != dexeth.CustomTokenID(BipID, common.HexToAddress(ct.Address)) {
+		t.Fatalf("wrong token ID %d", tokenID)
+	}
+	if token.UnitInfo.Conventional.ConversionFactor != 1e9 || token.UnitInfo.Conventional.Unit != "ABC" {
+		t.Fatalf("wrong unit info %+v", token.UnitInfo)
+	}
+	ethToken := eth.token(tokenID)
+	if ethToken == nil {
+		t.Fatalf("custom token not found")
+	}
+	if v := ethToken.AtomicToEVM(1); v.Cmp(dexeth.GweiToWei(1)) != 0 {
+		t.Fatalf("wrong EVM conversion %s", v)
+	}
+	if _, found := eth.allTokens()[tokenID]; !found {
+		t.Fatalf("custom token not in all tokens")
+	}
+	if err := eth.CreateTokenWallet(tokenID, nil); err != nil {
+		t.Fatalf("CreateTokenWallet error: %v", err)
+	}
+
+	// Not a token on another chain.
+	eth.baseChainID++
+	if eth.token(tokenID) != nil {
+		t.Fatalf("custom token found for wrong chain")
+	}
+	eth.baseChainID--
+
+	// Bad address.
+	badCT := *ct
+	badCT.Address = "0xabc"
+	if _, _, err := (&Driver{}).RegisterCustomToken(&badCT, dex.Simnet); err == nil {
+		t.Fatalf("no error for bad address")
+	}
+	// Listed token.
+	badCT.Address = dexeth.Tokens[usdcEthID].NetTokens[dex.Mainnet].Address.String()
+	if _, _, err := (&Driver{}).RegisterCustomToken(&badCT, dex.Mainnet); err == nil {
+		t.Fatalf("no error for listed token")
+	}
+}
+
+func TestRegisterCustomTokenOptions(t *testing.T) {
+	ct := &asset.CustomToken{
+		ParentID: BipID,
+		Address:  "0x0000000000000000000000000000000000000def",
+		Symbol:   "def",
+		Name:     "DEF",
+		Decimals: 6,
+		UnitInfo: &dex.UnitInfo{
+			AtomicUnit: "micro",
+			Alternatives: []dex.Denomination{{
+				Unit:             "mDEF",
+				ConversionFactor: 1e3,
+			}},
+		},
+		SwapContractVersions: []uint32{1},
+	}
+	_, token, err := (&Driver{}).RegisterCustomToken(ct, dex.Simnet)
+	if err != nil {
+		t.Fatalf("RegisterCustomToken error: %v", err)
+	}
+	ui := token.UnitInfo
+	if ui.AtomicUnit != "micro" || ui.Conventional.Unit != "DEF" || ui.Conventional.ConversionFactor != 1e6 ||
+		len(ui.Alternatives) != 1 || ui.FeeRateDenom != "gas" {
+		t.Fatalf("wrong unit info %+v", ui)
+	}
+
+	// Conversion factor that doesn't match the decimals.
+	badCT := *ct
+	badCT.UnitInfo = &dex.UnitInfo{Conventional: dex.Denomination{ConversionFactor: 1e8}}
+	if _, _, err := (&Driver{}).RegisterCustomToken(&badCT, dex.Simnet); err == nil {
+		t.Fatalf("no error for bad conversion factor")
+	}
+	// Unsupported swap contract version.
+	badCT = *ct
+	badCT.SwapContractVersions = []uint32{0, 1}
+	if _, _, err := (&Driver{}).RegisterCustomToken(&badCT, dex.Simnet); err == nil {
+		t.Fatalf("no error for version 0 swap contract")
+	}
+}
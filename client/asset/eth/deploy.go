@@ -266,7 +266,9 @@ func (contractDeployer) deployContract(
 	feeRate := dexeth.WeiToGweiCeil(maxFeeRate)
 	log.Infof("Estimated fees: %s gwei", ui.ConventionalString(feeRate*gas))
 
-	gas *= 5 / 4 // Add 20% buffer
+	// Add a 25% buffer. On rollups, the estimate includes the L1 data fee,
+	// which changes with the L1 fee rate.
+	gas = gas * 5 / 4
 	feesWithBuffer := feeRate * gas
 
 	gweiBal := dexeth.WeiToGwei(baseChainBal)
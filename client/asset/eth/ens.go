@@ -0,0 +1,100 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	_ asset.NameResolver = (*ETHWallet)(nil)
+	_ asset.NameResolver = (*TokenWallet)(nil)
+
+	// ensResolverSelector is the selector of the ENS registry's
+	// resolver(bytes32) method.
+	ensResolverSelector = crypto.Keccak256([]byte("resolver(bytes32)"))[:4]
+	// ensAddrSelector is the selector of the ENS resolver's addr(bytes32)
+	// method.
+	ensAddrSelector = crypto.Keccak256([]byte("addr(bytes32)"))[:4]
+)
+
+// normalizeENSName lower-cases the name, and checks that it has at least two
+// non-empty labels. Full ENSIP-15 normalization is not performed.
+func normalizeENSName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("%q is not an ENS name", name)
+	}
+	for _, label := range labels {
+		if label == "" {
+			return "", fmt.Errorf("%q is not an ENS name", name)
+		}
+	}
+	return name, nil
+}
+
+// ensNameHash is the ENS namehash of the name, as specified in EIP-137.
+func ensNameHash(name string) common.Hash {
+	var node common.Hash
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		labelHash := crypto.Keccak256([]byte(labels[i]))
+		node = crypto.Keccak256Hash(node[:], labelHash)
+	}
+	return node
+}
+
+// ensCall calls a method of an ENS contract that takes a namehash and returns
+// an address.
+func (w *baseWallet) ensCall(ctx context.Context, contract common.Address, selector []byte, node common.Hash) (common.Address, error) {
+	data := append(append(make([]byte, 0, len(selector)+len(node)), selector...), node[:]...)
+	res, err := w.node.contractBackend().CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(res) != 32 {
+		return common.Address{}, fmt.Errorf("unexpected ENS response length %d", len(res))
+	}
+	return common.BytesToAddress(res[12:]), nil
+}
+
+// ResolveName resolves an ENS name to an address using the name's resolver.
+// Part of the asset.NameResolver interface.
+func (w *baseWallet) ResolveName(name string) (string, error) {
+	if w.ensRegistryAddress == (common.Address{}) {
+		return "", errors.New("ENS names are not supported on this network")
+	}
+	name, err := normalizeENSName(name)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(w.ctx, 10*time.Second)
+	defer cancel()
+	node := ensNameHash(name)
+	resolver, err := w.ensCall(ctx, w.ensRegistryAddress, ensResolverSelector, node)
+	if err != nil {
+		return "", fmt.Errorf("error getting resolver for %s: %w", name, err)
+	}
+	if resolver == (common.Address{}) {
+		return "", fmt.Errorf("ENS name %s is not registered", name)
+	}
+	addr, err := w.ensCall(ctx, resolver, ensAddrSelector, node)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", name, err)
+	}
+	if addr == (common.Address{}) {
+		return "", fmt.Errorf("ENS name %s does not resolve to an address", name)
+	}
+	return addr.Hex(), nil
+}
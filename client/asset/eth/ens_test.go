@@ -0,0 +1,98 @@
+//go:build !harness && !rpclive
+
+package eth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// tENSBackend answers ENS registry and resolver calls.
+type tENSBackend struct {
+	bind.ContractBackend
+	registry  common.Address
+	resolvers map[common.Hash]common.Address // namehash => resolver
+	addrs     map[common.Hash]common.Address // namehash => address
+	err       error
+}
+
+func (b *tENSBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	selector, node := call.Data[:4], common.BytesToHash(call.Data[4:])
+	var addr common.Address
+	switch {
+	case *call.To == b.registry && bytes.Equal(selector, ensResolverSelector):
+		addr = b.resolvers[node]
+	case bytes.Equal(selector, ensAddrSelector):
+		addr = b.addrs[node]
+	default:
+		return nil, errors.New("unknown call")
+	}
+	return common.LeftPadBytes(addr[:], 32), nil
+}
+
+func TestENSNameHash(t *testing.T) {
+	// https://eips.ethereum.org/EIPS/eip-137#namehash-algorithm
+	for name, exp := range map[string]string{
+		"eth":     "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae",
+		"foo.eth": "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f",
+	} {
+		if h := ensNameHash(name); h != common.HexToHash(exp) {
+			t.Fatalf("wrong namehash for %s: %s", name, h)
+		}
+	}
+}
+
+func TestResolveName(t *testing.T) {
+	node := newTestNode(BipID)
+	registry := common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+	resolver := common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63")
+	addr := common.HexToAddress("0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+	nameHash := ensNameHash("vitalik.eth")
+	backend := &tENSBackend{
+		registry:  registry,
+		resolvers: map[common.Hash]common.Address{nameHash: resolver},
+		addrs:     map[common.Hash]common.Address{nameHash: addr},
+	}
+	node.simBackend = backend
+	w := &baseWallet{node: node, ctx: context.Background()}
+
+	if _, err := w.ResolveName("vitalik.eth"); err == nil {
+		t.Fatalf("no error without an ENS registry")
+	}
+
+	w.ensRegistryAddress = registry
+	resolved, err := w.ResolveName(" Vitalik.ETH ")
+	if err != nil {
+		t.Fatalf("ResolveName error: %v", err)
+	}
+	if resolved != addr.Hex() {
+		t.Fatalf("wrong address %s", resolved)
+	}
+
+	for _, name := range []string{"vitalik", "vitalik..eth", "nobody.eth", addr.Hex()} {
+		if _, err := w.ResolveName(name); err == nil {
+			t.Fatalf("no error for %s", name)
+		}
+	}
+
+	// Registered, but no address set.
+	delete(backend.addrs, nameHash)
+	if _, err := w.ResolveName("vitalik.eth"); err == nil {
+		t.Fatalf("no error for a name without an address")
+	}
+
+	backend.err = errors.New("test error")
+	if _, err := w.ResolveName("vitalik.eth"); err == nil {
+		t.Fatalf("no error for a failed call")
+	}
+}
@@ -339,6 +339,8 @@ type ethFetcher interface {
 	shutdown()
 	sendSignedTransaction(ctx context.Context, tx *types.Transaction, filts ...acceptabilityFilter) error
 	sendTransaction(ctx context.Context, txOpts *bind.TransactOpts, to common.Address, data []byte, filts ...acceptabilityFilter) (*types.Transaction, error)
+	// signTransaction authors and signs a transaction without broadcasting it.
+	signTransaction(ctx context.Context, txOpts *bind.TransactOpts, to common.Address, data []byte) (*types.Transaction, error)
 	signData(data []byte) (sig, pubKey []byte, err error)
 	syncProgress(context.Context) (progress *ethereum.SyncProgress, tipTime uint64, err error)
 	transactionConfirmations(context.Context, common.Hash) (uint32, error)
@@ -384,8 +386,14 @@ var _ asset.DynamicSwapper = (*ETHWallet)(nil)
 var _ asset.DynamicSwapper = (*TokenWallet)(nil)
 var _ asset.Authenticator = (*ETHWallet)(nil)
 var _ asset.TokenApprover = (*TokenWallet)(nil)
+var _ asset.TokenAllowanceManager = (*TokenWallet)(nil)
 var _ asset.WalletHistorian = (*ETHWallet)(nil)
 var _ asset.WalletHistorian = (*TokenWallet)(nil)
+var _ asset.GasFeeSetter = (*ETHWallet)(nil)
+var _ asset.GasFeeSetter = (*TokenWallet)(nil)
+var _ asset.ProviderHealthReporter = (*ETHWallet)(nil)
+var _ asset.ProviderHealthReporter = (*TokenWallet)(nil)
+var _ asset.NonceRepairer = (*ETHWallet)(nil)
 
 type baseWallet struct {
 	// The asset subsystem starts with Connect(ctx). This ctx will be initialized
@@ -403,12 +411,19 @@ type baseWallet struct {
 	multiBalanceAddress  common.Address
 	multiBalanceContract *multibal.MultiBalanceV0
 
+	ensRegistryAddress common.Address
+
+	// bondContractAddr is the address of the fidelity bond contract. Bonds
+	// are not supported if it is empty.
+	bondContractAddr common.Address
+
 	baseChainID  uint32
 	chainCfg     *params.ChainConfig
 	chainID      int64
 	compat       *CompatibilityData
 	tokens       map[uint32]*dexeth.Token
 	maxTxFeeGwei uint64
+	l1Gas        L1GasEstimator
 
 	startingBlocks atomic.Uint64
 
@@ -428,6 +443,9 @@ type baseWallet struct {
 	confirmedNonceAt    *big.Int
 	pendingNonceAt      *big.Int
 	recoveryRequestSent bool
+	// unbroadcastBonds are bond txs that were created by MakeBondTx and are
+	// in pendingTxs, but have not been broadcast with SendTransaction.
+	unbroadcastBonds map[common.Hash]bool
 
 	balances struct {
 		sync.Mutex
@@ -467,6 +485,9 @@ type assetWallet struct {
 		refundReserves     uint64
 	}
 
+	// bondReserves is only set for a base chain wallet that is a Bonder.
+	bondReserves atomic.Uint64
+
 	findRedemptionMtx  sync.RWMutex
 	findRedemptionReqs map[string]*findRedemptionRequest
 
@@ -711,6 +732,8 @@ func newWallet(assetCFG *asset.WalletConfig, logger dex.Logger, net dex.Network)
 		Logger:             logger,
 		BaseChainContracts: contracts,
 		MultiBalAddress:    dexeth.MultiBalanceAddresses[net],
+		ENSRegistryAddress: dexeth.ENSRegistryAddresses[net],
+		BondContract:       dexeth.BondContractAddresses[net],
 		WalletInfo:         WalletInfo,
 		Net:                net,
 		DefaultProviders:   defaultProviders,
@@ -720,9 +743,27 @@ func newWallet(assetCFG *asset.WalletConfig, logger dex.Logger, net dex.Network)
 		return nil, err
 	}
 
+	// Bonds are only supported if the bond contract is deployed on this
+	// network.
+	bonder := newBondWallet(evmWallet)
+
 	if _, supported := PolygonBridgeSupportedAsset(BipID, net); supported {
-		return &ETHBridgeWallet{
+		bridgeWallet := &ETHBridgeWallet{
 			ETHWallet: evmWallet,
+		}
+		if bonder != nil {
+			return &ETHBridgeBondWallet{
+				ETHBridgeWallet: bridgeWallet,
+				bondWallet:      bonder,
+			}, nil
+		}
+		return bridgeWallet, nil
+	}
+
+	if bonder != nil {
+		return &ETHBondWallet{
+			ETHWallet:  evmWallet,
+			bondWallet: bonder,
 		}, nil
 	}
 
@@ -742,11 +783,16 @@ type EVMWalletConfig struct {
 	BaseChainContracts map[uint32]common.Address
 	DefaultProviders   []string
 	MultiBalAddress    common.Address // If empty, separate calls for N tokens + 1
+	ENSRegistryAddress common.Address // If empty, ENS names are not resolved
+	BondContract       common.Address // If empty, bonds are not supported
 	WalletInfo         asset.WalletInfo
 	Net                dex.Network
 	// MaxTxFeeGwei is the absolute maximum fees we will allow for a single tx.
 	// It should be set to a relatively large value.
 	MaxTxFeeGwei uint64
+	// L1GasEstimator should be set for rollups that charge for posting
+	// transaction data to L1 in L2 gas.
+	L1GasEstimator L1GasEstimator
 }
 
 func NewEVMWallet(cfg *EVMWalletConfig) (w *ETHWallet, err error) {
@@ -789,7 +835,11 @@ func NewEVMWallet(cfg *EVMWalletConfig) (w *ETHWallet, err error) {
 		gasFeeLimitV:        gasFeeLimit,
 		wallets:             make(map[uint32]*assetWallet),
 		multiBalanceAddress: cfg.MultiBalAddress,
+		ensRegistryAddress:  cfg.ENSRegistryAddress,
+		bondContractAddr:    cfg.BondContract,
+		unbroadcastBonds:    make(map[common.Hash]bool),
 		maxTxFeeGwei:        cfg.MaxTxFeeGwei,
+		l1Gas:               cfg.L1GasEstimator,
 	}
 
 	var maxSwapGas, maxRedeemGas uint64
@@ -883,6 +933,7 @@ func (w *ETHWallet) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 			return nil, err
 		}
 		rpcCl.finalizeConfs = w.finalizeConfs
+		rpcCl.l1Gas = w.l1Gas
 		cl = rpcCl
 	default:
 		return nil, fmt.Errorf("unknown wallet type %q", w.walletType)
@@ -1191,6 +1242,7 @@ type genTxResult struct {
 	bridgeCounterpartAssetID *uint32
 	bridgeCounterpartTxID    *string
 	bridgeCompletionTime     *uint64
+	bondInfo                 *asset.BondTxInfo
 }
 
 // transactionGenerator is an action that uses a nonce and returns a tx, it's
@@ -1322,7 +1374,7 @@ func parseTokenWalletConfig(settings map[string]string) (cfg *tokenWalletConfig,
 // to do, except check that the token exists.
 func (w *baseWallet) CreateTokenWallet(tokenID uint32, _ map[string]string) error {
 	// Just check that the token exists for now.
-	if w.tokens[tokenID] == nil {
+	if w.token(tokenID) == nil {
 		return fmt.Errorf("token not found for asset ID %d", tokenID)
 	}
 	return nil
@@ -1501,8 +1553,8 @@ var _ asset.Bridger = (*TokenBridgeWallet)(nil)
 
 // OpenTokenWallet creates a new TokenWallet.
 func (w *ETHWallet) OpenTokenWallet(tokenCfg *asset.TokenConfig) (asset.Wallet, error) {
-	token, found := w.tokens[tokenCfg.AssetID]
-	if !found {
+	token := w.token(tokenCfg.AssetID)
+	if token == nil {
 		return nil, fmt.Errorf("token %d not found", tokenCfg.AssetID)
 	}
 
@@ -1633,9 +1685,13 @@ func (w *assetWallet) lockFunds(amt uint64, t fundReserveType) error {
 		return err
 	}
 
-	if balance.Available < amt {
+	avail := balance.Available
+	if reserves := w.bondReserves.Load(); reserves > 0 {
+		avail -= min(reserves, avail)
+	}
+	if avail < amt {
 		return fmt.Errorf("attempting to lock more %s for %s than is currently available. %d > %d %s",
-			dex.BipIDSymbol(w.assetID), t, amt, balance.Available, w.ui.AtomicUnit)
+			dex.BipIDSymbol(w.assetID), t, amt, avail, w.ui.AtomicUnit)
 	}
 
 	w.lockedFunds.mtx.Lock()
@@ -1671,7 +1727,24 @@ func (w *assetWallet) amountLocked() uint64 {
 
 // Balance returns the available and locked funds (token or eth).
 func (w *assetWallet) Balance() (*asset.Balance, error) {
-	return w.balance()
+	bal, err := w.balance()
+	if err != nil {
+		return nil, err
+	}
+
+	reserves := w.bondReserves.Load()
+	if reserves > bal.Available {
+		w.log.Warnf("Available balance is below configured reserves: %s < %s",
+			w.amtString(bal.Available), w.amtString(reserves))
+		bal.ReservesDeficit = reserves - bal.Available
+		reserves = bal.Available
+	}
+
+	bal.BondReserves = reserves
+	bal.Available -= reserves
+	bal.Locked += reserves
+
+	return bal, nil
 }
 
 // balance returns the total available funds in the account.
@@ -1785,8 +1858,14 @@ func (w *assetWallet) preSwap(req *asset.PreSwapForm, feeWallet *assetWallet) (*
 		return nil, err
 	}
 
+	opts, err := w.swapOrderOptions(req.MaxFeeRate)
+	if err != nil {
+		return nil, err
+	}
+
 	return &asset.PreSwap{
 		Estimate: est,
+		Options:  opts,
 	}, nil
 }
 
@@ -1858,11 +1937,17 @@ func (w *assetWallet) PreRedeem(req *asset.PreRedeemForm) (*asset.PreRedeem, err
 		return nil, err
 	}
 
+	opts, err := w.redeemOrderOptions(req.FeeSuggestion)
+	if err != nil {
+		return nil, err
+	}
+
 	return &asset.PreRedeem{
 		Estimate: &asset.RedeemEstimate{
 			RealisticBestCase:  nRedeem * req.FeeSuggestion,
 			RealisticWorstCase: oneRedeem * req.Lots * req.FeeSuggestion,
 		},
+		Options: opts,
 	}, nil
 }
 
@@ -2449,9 +2534,9 @@ func (w *ETHWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, uint6
 	}
 
 	maxFeeRate := dexeth.GweiToWei(swaps.FeeRate)
-	_, tipRate, err := w.currentNetworkFees(w.ctx)
+	tipRate, err := w.swapTipRate(swaps.Options, maxFeeRate)
 	if err != nil {
-		return fail("Swap: failed to get network tip cap: %w", err)
+		return fail("Swap: %w", err)
 	}
 
 	tx, err := w.initiate(w.ctx, w.assetID, swaps.Contracts, gasLimit, maxFeeRate, tipRate, contractVer)
@@ -2557,9 +2642,9 @@ func (w *TokenWallet) Swap(swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, uin
 	}
 
 	maxFeeRate := dexeth.GweiToWei(swaps.FeeRate)
-	_, tipRate, err := w.currentNetworkFees(w.ctx)
+	tipRate, err := w.swapTipRate(swaps.Options, maxFeeRate)
 	if err != nil {
-		return fail("Swap: failed to get network tip cap: %w", err)
+		return fail("Swap: %w", err)
 	}
 
 	tx, err := w.initiate(w.ctx, w.assetID, swaps.Contracts, gasLimit, maxFeeRate, tipRate, contractVer)
@@ -2720,15 +2805,33 @@ func (w *assetWallet) Redeem(form *asset.RedeemForm, feeWallet *assetWallet, non
 	}
 	*/
 
+	opts := new(redeemOptions)
+	if err := config.Unmapify(form.Options, opts); err != nil {
+		return fail(fmt.Errorf("error parsing redeem options: %w", err))
+	}
+
 	// If the base fee is higher than the FeeSuggestion we attempt to increase
 	// the gasFeeCap to 2*baseFee. If we don't have enough funds, we use the
-	// funds we have available.
+	// funds we have available. A max fee rate selected by the user is used
+	// as is, as long as the wallet can pay for it.
 	baseFee, tipRate, err := w.currentNetworkFees(w.ctx)
 	if err != nil {
 		return fail(fmt.Errorf("Error getting net fee state: %w", err))
 	}
 	baseFeeGwei := dexeth.WeiToGweiCeil(baseFee)
-	if baseFeeGwei > form.FeeSuggestion {
+	if opts.MaxFeeRate > 0 {
+		if opts.MaxFeeRate < baseFeeGwei {
+			return fail(fmt.Errorf("redeem max fee rate %d gwei is lower than the current base fee rate %d gwei",
+				opts.MaxFeeRate, baseFeeGwei))
+		}
+		if opts.MaxFeeRate > gasFeeCap {
+			if additionalFundsNeeded := (opts.MaxFeeRate - gasFeeCap) * gasLimit; bal.Available < additionalFundsNeeded {
+				return fail(fmt.Errorf("insufficient balance for redeem max fee rate %d gwei. %d < %d",
+					opts.MaxFeeRate, bal.Available, additionalFundsNeeded))
+			}
+		}
+		gasFeeCap = opts.MaxFeeRate
+	} else if baseFeeGwei > form.FeeSuggestion {
 		additionalFundsNeeded := (2 * baseFeeGwei * gasLimit) - originalFundsReserved
 		if bal.Available > additionalFundsNeeded {
 			gasFeeCap = 2 * baseFeeGwei
@@ -2737,6 +2840,12 @@ func (w *assetWallet) Redeem(form *asset.RedeemForm, feeWallet *assetWallet, non
 		}
 		w.log.Warnf("base fee %d > server max fee rate %d. using %d as gas fee cap for redemption", baseFeeGwei, form.FeeSuggestion, gasFeeCap)
 	}
+	if opts.TipRate > 0 {
+		if opts.TipRate > gasFeeCap {
+			return fail(fmt.Errorf("redeem tip rate %d gwei is higher than the max fee rate %d gwei", opts.TipRate, gasFeeCap))
+		}
+		tipRate = dexeth.GweiToWei(opts.TipRate)
+	}
 
 	tx, err := w.redeem(w.ctx, form.Redemptions, gasFeeCap, tipRate, gasLimit, contractVer)
 	if err != nil {
@@ -2875,6 +2984,13 @@ func (w *TokenWallet) swapContractApprovalStatus(assetVer uint32) (asset.Approva
 // already been done or is pending. The onConfirm callback is called
 // when the approval transaction is confirmed.
 func (w *TokenWallet) ApproveToken(assetVer uint32, onConfirm func()) (string, error) {
+	return w.ApproveTokenWithGasFees(assetVer, nil, onConfirm)
+}
+
+// ApproveTokenWithGasFees is like ApproveToken, but the approval transaction
+// pays the fee rates. Zero or nil rates are chosen by the wallet.
+// Part of the asset.GasFeeSetter interface.
+func (w *TokenWallet) ApproveTokenWithGasFees(assetVer uint32, rates *asset.GasFeeRates, onConfirm func()) (string, error) {
 	contract, found := w.versionedContracts[assetVer]
 	if !found {
 		return "", fmt.Errorf("no contract address found for asset %d contract version %d", w.assetID, assetVer)
@@ -2891,7 +3007,7 @@ func (w *TokenWallet) ApproveToken(assetVer uint32, onConfirm func()) (string, e
 		return "", asset.ErrApprovalPending
 	}
 
-	maxFeeRate, tipRate, err := w.recommendedMaxFeeRate(w.ctx)
+	maxFeeRate, tipRate, err := w.gasFeeRates(w.ctx, rates)
 	if err != nil {
 		return "", fmt.Errorf("error calculating approval fee rate: %w", err)
 	}
@@ -2946,12 +3062,18 @@ func (w *TokenWallet) UnapproveToken(assetVer uint32, onConfirm func()) (string,
 		return "", asset.ErrApprovalPending
 	}
 
+	return w.sendAllowance(assetVer, contract, big.NewInt(0), onConfirm)
+}
+
+// sendAllowance sends an approval transaction setting the allowance of the
+// swap contract, paying the recommended fee rates.
+func (w *TokenWallet) sendAllowance(assetVer uint32, contract common.Address, allowance *big.Int, onConfirm func()) (string, error) {
 	maxFeeRate, tipRate, err := w.recommendedMaxFeeRate(w.ctx)
 	if err != nil {
 		return "", fmt.Errorf("error calculating approval fee rate: %w", err)
 	}
 	feeRateGwei := dexeth.WeiToGweiCeil(maxFeeRate)
-	approvalGas, err := w.approvalGas(big.NewInt(0), assetVer)
+	approvalGas, err := w.approvalGas(allowance, assetVer)
 	if err != nil {
 		return "", fmt.Errorf("error calculating approval gas: %w", err)
 	}
@@ -2961,13 +3083,13 @@ func (w *TokenWallet) UnapproveToken(assetVer uint32, onConfirm func()) (string,
 		return "", fmt.Errorf("error getting eth balance: %w", err)
 	}
 	if ethBal.Available < approvalGas*feeRateGwei {
-		return "", fmt.Errorf("insufficient eth balance for unapproval. required: %d, available: %d",
+		return "", fmt.Errorf("insufficient eth balance for approval. required: %d, available: %d",
 			approvalGas*feeRateGwei, ethBal.Available)
 	}
 
-	tx, err := w.approveToken(w.ctx, big.NewInt(0), approvalGas, maxFeeRate, tipRate, assetVer)
+	tx, err := w.approveToken(w.ctx, allowance, approvalGas, maxFeeRate, tipRate, assetVer)
 	if err != nil {
-		return "", fmt.Errorf("error unapproving token: %w", err)
+		return "", fmt.Errorf("error setting token allowance: %w", err)
 	}
 
 	w.approvalsMtx.Lock()
@@ -3798,6 +3920,11 @@ func (w *assetWallet) findSecret(locator []byte, contractVer uint32) ([]byte, st
 
 // Refund refunds a contract. This can only be used after the time lock has
 // expired.
+//
+// Each refund is sent in its own transaction. Refunds are not batched because
+// the swap contracts only refund one swap per call and require the caller to
+// be the transaction origin, so refunds can't be combined through a multicall
+// contract either. See dex/networks/eth/contracts/README.md.
 func (w *assetWallet) Refund(_, contract dex.Bytes, feeRate uint64) (dex.Bytes, error) {
 	contractVer, locator, err := dexeth.DecodeContractData(contract)
 	if err != nil {
@@ -3884,6 +4011,9 @@ func (eth *baseWallet) SendTransaction(rawTx []byte) ([]byte, error) {
 	if err := eth.node.sendSignedTransaction(eth.ctx, tx); err != nil {
 		return nil, err
 	}
+	eth.nonceMtx.Lock()
+	delete(eth.unbroadcastBonds, tx.Hash())
+	eth.nonceMtx.Unlock()
 	return tx.Hash().Bytes(), nil
 }
 
@@ -3915,12 +4045,13 @@ func isValidSend(addr string, value uint64, subtract bool) error {
 }
 
 // canSend ensures that the wallet has enough to cover send value and returns
-// the fee rate and max fee required for the send tx. If isPreEstimate is false,
-// wallet balance must be enough to cover total spend.
-func (w *ETHWallet) canSend(value uint64, verifyBalance, isPreEstimate bool) (maxFee uint64, maxFeeRate, tipRate *big.Int, err error) {
-	maxFeeRate, tipRate, err = w.recommendedMaxFeeRate(w.ctx)
+// the fee rate and max fee required for the send tx. If rates is nil, the
+// recommended fee rates are used. If isPreEstimate is false, wallet balance
+// must be enough to cover total spend.
+func (w *ETHWallet) canSend(value uint64, rates *asset.GasFeeRates, verifyBalance, isPreEstimate bool) (maxFee uint64, maxFeeRate, tipRate *big.Int, err error) {
+	maxFeeRate, tipRate, err = w.gasFeeRates(w.ctx, rates)
 	if err != nil {
-		return 0, nil, nil, fmt.Errorf("error getting max fee rate: %w", err)
+		return 0, nil, nil, err
 	}
 	maxFeeRateGwei := dexeth.WeiToGweiCeil(maxFeeRate)
 
@@ -3948,11 +4079,12 @@ func (w *ETHWallet) canSend(value uint64, verifyBalance, isPreEstimate bool) (ma
 }
 
 // canSend ensures that the wallet has enough to cover send value and returns
-// the fee rate and max fee required for the send tx.
-func (w *TokenWallet) canSend(value uint64, verifyBalance, isPreEstimate bool) (maxFee uint64, maxFeeRate, tipRate *big.Int, err error) {
-	maxFeeRate, tipRate, err = w.recommendedMaxFeeRate(w.ctx)
+// the fee rate and max fee required for the send tx. If rates is nil, the
+// recommended fee rates are used.
+func (w *TokenWallet) canSend(value uint64, rates *asset.GasFeeRates, verifyBalance, isPreEstimate bool) (maxFee uint64, maxFeeRate, tipRate *big.Int, err error) {
+	maxFeeRate, tipRate, err = w.gasFeeRates(w.ctx, rates)
 	if err != nil {
-		return 0, nil, nil, fmt.Errorf("error getting max fee rate: %w", err)
+		return 0, nil, nil, err
 	}
 	maxFeeRateGwei := dexeth.WeiToGweiCeil(maxFeeRate)
 
@@ -3998,7 +4130,7 @@ func (w *ETHWallet) EstimateSendTxFee(addr string, value, _ uint64, _, maxWithdr
 	if err := isValidSend(addr, value, maxWithdraw); err != nil && addr != "" { // fee estimate for a send tx.
 		return 0, false, err
 	}
-	maxFee, _, _, err := w.canSend(value, addr != "", true)
+	maxFee, _, _, err := w.canSend(value, nil, addr != "", true)
 	if err != nil {
 		return 0, false, err
 	}
@@ -4018,7 +4150,7 @@ func (w *TokenWallet) EstimateSendTxFee(addr string, value, _ uint64, _, maxWith
 	if err := isValidSend(addr, value, maxWithdraw); err != nil && addr != "" { // fee estimate for a send tx.
 		return 0, false, err
 	}
-	maxFee, _, _, err := w.canSend(value, addr != "", true)
+	maxFee, _, _, err := w.canSend(value, nil, addr != "", true)
 	if err != nil {
 		return 0, false, err
 	}
@@ -4105,11 +4237,18 @@ func (w *assetWallet) SwapConfirmations(ctx context.Context, coinID dex.Bytes, c
 // Send sends the exact value to the specified address. The provided fee rate is
 // ignored since all sends will use an internally derived fee rate.
 func (w *ETHWallet) Send(addr string, value, _ uint64) (asset.Coin, error) {
+	return w.SendWithGasFees(addr, value, nil)
+}
+
+// SendWithGasFees sends the exact value to the specified address, paying the
+// fee rates. Zero or nil rates are chosen by the wallet.
+// Part of the asset.GasFeeSetter interface.
+func (w *ETHWallet) SendWithGasFees(addr string, value uint64, rates *asset.GasFeeRates) (asset.Coin, error) {
 	if err := isValidSend(addr, value, false); err != nil {
 		return nil, err
 	}
 
-	_ /* maxFee */, maxFeeRate, tipRate, err := w.canSend(value, true, false)
+	_ /* maxFee */, maxFeeRate, tipRate, err := w.canSend(value, rates, true, false)
 	if err != nil {
 		return nil, err
 	}
@@ -4132,11 +4271,19 @@ func (w *ETHWallet) Send(addr string, value, _ uint64) (asset.Coin, error) {
 // parent wallet. The provided fee rate is ignored since all sends will use an
 // internally derived fee rate.
 func (w *TokenWallet) Send(addr string, value, _ uint64) (asset.Coin, error) {
+	return w.SendWithGasFees(addr, value, nil)
+}
+
+// SendWithGasFees sends the exact value to the specified address, paying the
+// fee rates from the parent wallet. Zero or nil rates are chosen by the
+// wallet.
+// Part of the asset.GasFeeSetter interface.
+func (w *TokenWallet) SendWithGasFees(addr string, value uint64, rates *asset.GasFeeRates) (asset.Coin, error) {
 	if err := isValidSend(addr, value, false); err != nil {
 		return nil, err
 	}
 
-	_ /* maxFee */, maxFeeRate, tipRate, err := w.canSend(value, true, false)
+	_ /* maxFee */, maxFeeRate, tipRate, err := w.canSend(value, rates, true, false)
 	if err != nil {
 		return nil, err
 	}
@@ -4781,7 +4928,7 @@ func (w *assetWallet) getConfirmedBalance() (*big.Int, error) {
 		0: w.baseChainID,
 	}
 	i := 1
-	for assetID, tkn := range w.tokens {
+	for assetID, tkn := range w.allTokens() {
 		netToken := tkn.NetTokens[w.net]
 		if netToken == nil || netToken.Address == (common.Address{}) {
 			continue
@@ -5118,8 +5265,8 @@ func (w *assetWallet) estimateRefundGas(ctx context.Context, locator []byte, con
 
 // loadContractors prepares the token contractors and add them to the map.
 func (w *assetWallet) loadContractors(parent *assetWallet) error {
-	token, found := w.tokens[w.assetID]
-	if !found {
+	token := w.token(w.assetID)
+	if token == nil {
 		return fmt.Errorf("token %d not found", w.assetID)
 	}
 	netToken, found := token.NetTokens[w.net]
@@ -5606,6 +5753,7 @@ func (w *baseWallet) checkPendingTxs() {
 		if pendingTx.Confirmed || pendingTx.BlockNumber > 0 ||
 			pendingTx.actionRequested || // Waiting on action
 			pendingTx.indexed || // Provider knows about it
+			w.unbroadcastBonds[pendingTx.txHash] || // Waiting on SendTransaction
 			time.Since(pendingTx.lastBroadcast) < rebroadcastPeriod {
 
 			continue
@@ -5713,52 +5861,60 @@ func (w *assetWallet) userActionBumpFees(actionB []byte) error {
 			return nil
 		}
 
-		nonce := new(big.Int).SetUint64(tx.Nonce())
 		maxFeeRate, tipCap, err := w.recommendedMaxFeeRate(w.ctx)
 		if err != nil {
 			return fmt.Errorf("error getting new fee rate: %w", err)
 		}
-		txOpts, err := w.node.txOpts(w.ctx, 0 /* set below */, tx.Gas(), maxFeeRate, tipCap, nonce)
-		if err != nil {
-			return fmt.Errorf("error preparing tx opts: %w", err)
-		}
-		txOpts.Value = tx.Value()
-		addr := tx.To()
-		if addr == nil {
-			return errors.New("pending tx has no recipient?")
-		}
+		_, err = w.bumpPendingTx(tx, pendingTx, idx, maxFeeRate, tipCap)
+		return err
+	})
+}
 
-		newTx, err := w.node.sendTransaction(w.ctx, txOpts, *addr, tx.Data())
-		if err != nil {
-			return fmt.Errorf("error sending bumped-fee transaction: %w", err)
-		}
+// bumpPendingTx sends the same transaction as the pending transaction at idx,
+// with new fee rates, and replaces it in pendingTxs. The nonceMtx must be
+// held.
+func (w *assetWallet) bumpPendingTx(tx *types.Transaction, pendingTx *extendedWalletTx, idx int, maxFeeRate, tipRate *big.Int) (*extendedWalletTx, error) {
+	nonce := new(big.Int).SetUint64(tx.Nonce())
+	txOpts, err := w.node.txOpts(w.ctx, 0 /* set below */, tx.Gas(), maxFeeRate, tipRate, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing tx opts: %w", err)
+	}
+	txOpts.Value = tx.Value()
+	addr := tx.To()
+	if addr == nil {
+		return nil, errors.New("pending tx has no recipient?")
+	}
 
-		var bridgeDestAssetID *uint32
-		var bridgeDestTxID *string
-		if pendingTx.BridgeCounterpartTx != nil {
-			bridgeDestAssetID = &pendingTx.BridgeCounterpartTx.AssetID
-			bridgeDestTxID = &pendingTx.BridgeCounterpartTx.ID
-		}
+	newTx, err := w.node.sendTransaction(w.ctx, txOpts, *addr, tx.Data())
+	if err != nil {
+		return nil, fmt.Errorf("error sending bumped-fee transaction: %w", err)
+	}
 
-		res := &genTxResult{
-			tx:                       newTx,
-			txType:                   pendingTx.Type,
-			amt:                      pendingTx.Amount,
-			bridgeCounterpartAssetID: bridgeDestAssetID,
-			bridgeCounterpartTxID:    bridgeDestTxID,
-			recipient:                pendingTx.Recipient,
-		}
-		newPendingTx := w.extendedTx(res)
+	var bridgeDestAssetID *uint32
+	var bridgeDestTxID *string
+	if pendingTx.BridgeCounterpartTx != nil {
+		bridgeDestAssetID = &pendingTx.BridgeCounterpartTx.AssetID
+		bridgeDestTxID = &pendingTx.BridgeCounterpartTx.ID
+	}
 
-		pendingTx.NonceReplacement = newPendingTx.ID
-		pendingTx.FeeReplacement = true
+	res := &genTxResult{
+		tx:                       newTx,
+		txType:                   pendingTx.Type,
+		amt:                      pendingTx.Amount,
+		bridgeCounterpartAssetID: bridgeDestAssetID,
+		bridgeCounterpartTxID:    bridgeDestTxID,
+		recipient:                pendingTx.Recipient,
+	}
+	newPendingTx := w.extendedTx(res)
 
-		w.tryStoreDBTx(pendingTx)
-		w.tryStoreDBTx(newPendingTx)
+	pendingTx.NonceReplacement = newPendingTx.ID
+	pendingTx.FeeReplacement = true
 
-		w.pendingTxs[idx] = newPendingTx
-		return nil
-	})
+	w.tryStoreDBTx(pendingTx)
+	w.tryStoreDBTx(newPendingTx)
+
+	w.pendingTxs[idx] = newPendingTx
+	return newPendingTx, nil
 }
 
 // tryStoreDBTx attempts to store the DB tx and logs errors internally. This
@@ -5866,21 +6022,29 @@ func (w *assetWallet) userActionRecoverNonces(actionB []byte) error {
 		// they reboot.
 		return nil
 	}
+	_, err := w.fillMissingNonces()
+	return err
+}
+
+// fillMissingNonces sends zero-value txs to ourselves to fill any gaps in
+// our nonce sequence, and returns their IDs.
+func (w *assetWallet) fillMissingNonces() ([]string, error) {
 	maxFeeRate, tipRate, err := w.recommendedMaxFeeRate(w.ctx)
 	if err != nil {
-		return fmt.Errorf("error getting max fee rate for nonce resolution: %v", err)
+		return nil, fmt.Errorf("error getting max fee rate for nonce resolution: %v", err)
 	}
 	w.nonceMtx.Lock()
 	defer w.nonceMtx.Unlock()
 	missingNonces := findMissingNonces(w.confirmedNonceAt, w.pendingNonceAt, w.pendingTxs)
 	if len(missingNonces) == 0 {
-		return nil
+		return nil, nil
 	}
+	txIDs := make([]string, 0, len(missingNonces))
 	for i, n := range missingNonces {
 		nonce := new(big.Int).SetUint64(n)
 		txOpts, err := w.node.txOpts(w.ctx, 0, defaultSendGasLimit, maxFeeRate, tipRate, nonce)
 		if err != nil {
-			return fmt.Errorf("error getting tx opts for nonce resolution: %v", err)
+			return txIDs, fmt.Errorf("error getting tx opts for nonce resolution: %v", err)
 		}
 		var skip bool
 		tx, err := w.node.sendTransaction(w.ctx, txOpts, w.addr, nil, func(err error) (discard, propagate, fail bool) {
@@ -5891,7 +6055,7 @@ func (w *assetWallet) userActionRecoverNonces(actionB []byte) error {
 			return false, false, true
 		})
 		if err != nil {
-			return fmt.Errorf("error sending tx %d for nonce resolution: %v", nonce, err)
+			return txIDs, fmt.Errorf("error sending tx %d for nonce resolution: %v", nonce, err)
 		}
 		if skip {
 			w.log.Warnf("skipping storing underpriced replacement tx for nonce %d", nonce)
@@ -5908,17 +6072,18 @@ func (w *assetWallet) userActionRecoverNonces(actionB []byte) error {
 			sort.Slice(w.pendingTxs, func(i, j int) bool {
 				return w.pendingTxs[i].Nonce.Cmp(w.pendingTxs[j].Nonce) < 0
 			})
+			txIDs = append(txIDs, pendingTx.ID)
 		}
 		if i < len(missingNonces)-1 {
 			select {
 			case <-time.After(time.Second * 1):
 			case <-w.ctx.Done():
-				return nil
+				return txIDs, nil
 			}
 		}
 	}
 	w.emit.ActionResolved(w.missingNoncesActionID())
-	return nil
+	return txIDs, nil
 }
 
 // requestAction sends a ActionRequired notification up the chain of command.
@@ -6013,6 +6178,7 @@ func (w *baseWallet) extendAndStoreTx(genTxResult *genTxResult, tokenAssetID *ui
 			Fees:      dexeth.WeiToGweiCeil(transactionFeeLimit(genTxResult.tx)), // updated later
 			TokenID:   tokenAssetID,
 			Recipient: genTxResult.recipient,
+			BondInfo:  genTxResult.bondInfo,
 			AdditionalData: map[string]string{
 				txHistoryNonceKey: strconv.FormatUint(nonce, 10),
 			},
@@ -266,6 +266,9 @@ func (n *testNode) sendTransaction(ctx context.Context, txOpts *bind.TransactOpt
 	n.sentTxs++
 	return n.sendTxTx, n.sendTxErr
 }
+func (n *testNode) signTransaction(ctx context.Context, txOpts *bind.TransactOpts, to common.Address, data []byte) (*types.Transaction, error) {
+	return n.sendTxTx, n.sendTxErr
+}
 func (n *testNode) sendSignedTransaction(ctx context.Context, tx *types.Transaction, filts ...acceptabilityFilter) error {
 	n.lastSignedTx = tx
 	return nil
@@ -5686,3 +5689,202 @@ func parseRecoveryID(c asset.Coin) []byte {
 func randomHash() common.Hash {
 	return common.BytesToHash(encode.RandomBytes(20))
 }
+
+func TestGasFeeRates(t *testing.T) {
+	_, eth, _, shutdown := tassetWallet(BipID)
+	defer shutdown()
+
+	// base fee rate 100 gwei, tip rate 2 gwei
+	tests := []struct {
+		name             string
+		rates            *asset.GasFeeRates
+		wantMax, wantTip uint64
+		wantErr          bool
+	}{{
+		name:    "recommended",
+		wantMax: 202,
+		wantTip: 2,
+	}, {
+		name:    "zero rates",
+		rates:   &asset.GasFeeRates{},
+		wantMax: 202,
+		wantTip: 2,
+	}, {
+		name:    "max fee rate",
+		rates:   &asset.GasFeeRates{MaxFeeRate: 150},
+		wantMax: 150,
+		wantTip: 2,
+	}, {
+		name:    "tip rate",
+		rates:   &asset.GasFeeRates{TipRate: 10},
+		wantMax: 210,
+		wantTip: 10,
+	}, {
+		name:    "both",
+		rates:   &asset.GasFeeRates{MaxFeeRate: 120, TipRate: 20},
+		wantMax: 120,
+		wantTip: 20,
+	}, {
+		name:    "max fee rate below base fee rate",
+		rates:   &asset.GasFeeRates{MaxFeeRate: 99},
+		wantErr: true,
+	}, {
+		name:    "tip rate above max fee rate",
+		rates:   &asset.GasFeeRates{MaxFeeRate: 120, TipRate: 121},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		maxFeeRate, tipRate, err := eth.gasFeeRates(eth.ctx, tt.rates)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got := dexeth.WeiToGwei(maxFeeRate); got != tt.wantMax {
+			t.Fatalf("%s: wanted max fee rate %d, got %d", tt.name, tt.wantMax, got)
+		}
+		if got := dexeth.WeiToGwei(tipRate); got != tt.wantTip {
+			t.Fatalf("%s: wanted tip rate %d, got %d", tt.name, tt.wantTip, got)
+		}
+	}
+}
+
+func TestPreviewGasFees(t *testing.T) {
+	w, _, _, shutdown := tassetWallet(BipID)
+	defer shutdown()
+	setter := w.(asset.GasFeeSetter)
+
+	p, err := setter.PreviewGasFees(asset.Send, 0, &asset.GasFeeRates{MaxFeeRate: 150, TipRate: 5})
+	if err != nil {
+		t.Fatalf("PreviewGasFees error: %v", err)
+	}
+	if p.GasLimit != defaultSendGasLimit {
+		t.Fatalf("wrong gas limit %d", p.GasLimit)
+	}
+	if p.MaxFees != 150*defaultSendGasLimit {
+		t.Fatalf("wrong max fees %d", p.MaxFees)
+	}
+	if p.EstimatedFees != 105*defaultSendGasLimit {
+		t.Fatalf("wrong estimated fees %d", p.EstimatedFees)
+	}
+
+	// The estimate is limited by the max fee rate.
+	p, err = setter.PreviewGasFees(asset.Redeem, 0, &asset.GasFeeRates{MaxFeeRate: 101, TipRate: 5})
+	if err != nil {
+		t.Fatalf("PreviewGasFees error: %v", err)
+	}
+	if p.GasLimit != ethGasesV0.Redeem || p.EstimatedFees != 101*ethGasesV0.Redeem {
+		t.Fatalf("wrong redeem preview %+v", p)
+	}
+
+	if _, err := setter.PreviewGasFees(asset.ApproveToken, 0, nil); err == nil {
+		t.Fatalf("no error for approval preview on base chain wallet")
+	}
+
+	tw, _, _, shutdown := tassetWallet(usdcEthID)
+	defer shutdown()
+	p, err = tw.(asset.GasFeeSetter).PreviewGasFees(asset.Send, 0, nil)
+	if err != nil {
+		t.Fatalf("token PreviewGasFees error: %v", err)
+	}
+	if p.GasLimit != tokenGasesV1.Transfer || p.MaxFees != 202*tokenGasesV1.Transfer {
+		t.Fatalf("wrong token send preview %+v", p)
+	}
+}
+
+func TestSwapTipRate(t *testing.T) {
+	_, eth, _, shutdown := tassetWallet(BipID)
+	defer shutdown()
+
+	maxFeeRate := dexeth.GweiToWei(200)
+	tipRate, err := eth.swapTipRate(nil, maxFeeRate)
+	if err != nil {
+		t.Fatalf("swapTipRate error: %v", err)
+	}
+	if dexeth.WeiToGwei(tipRate) != 2 {
+		t.Fatalf("expected network tip rate, got %s", tipRate)
+	}
+	tipRate, err = eth.swapTipRate(map[string]string{swapTipRateKey: "30"}, maxFeeRate)
+	if err != nil {
+		t.Fatalf("swapTipRate error: %v", err)
+	}
+	if dexeth.WeiToGwei(tipRate) != 30 {
+		t.Fatalf("expected selected tip rate, got %s", tipRate)
+	}
+	if _, err := eth.swapTipRate(map[string]string{swapTipRateKey: "201"}, maxFeeRate); err == nil {
+		t.Fatalf("no error for tip rate higher than max fee rate")
+	}
+}
+
+func TestAddL1Gas(t *testing.T) {
+	to := common.Address{0x01}
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		Nonce:     2,
+		To:        &to,
+		Gas:       21_000,
+		GasFeeCap: big.NewInt(3),
+		GasTipCap: big.NewInt(1),
+		Value:     big.NewInt(4),
+		Data:      []byte{0x05},
+	})
+	m := &multiRPCClient{}
+	if newTx, err := m.addL1Gas(context.Background(), tx); err != nil || newTx != tx {
+		t.Fatalf("transaction changed without an L1 gas estimator")
+	}
+
+	var estErr error
+	m.l1Gas = func(_ context.Context, _ bind.ContractCaller, txTo *common.Address, data []byte) (uint64, error) {
+		if *txTo != to || !bytes.Equal(data, tx.Data()) {
+			t.Fatalf("wrong L1 gas estimate args")
+		}
+		return 10_000, estErr
+	}
+	newTx, err := m.addL1Gas(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("addL1Gas error: %v", err)
+	}
+	if newTx.Gas() != 21_000+15_000 {
+		t.Fatalf("wrong gas limit %d", newTx.Gas())
+	}
+	if newTx.Nonce() != tx.Nonce() || newTx.Value().Cmp(tx.Value()) != 0 || newTx.GasFeeCap().Cmp(tx.GasFeeCap()) != 0 {
+		t.Fatalf("transaction fields changed")
+	}
+
+	estErr = errors.New("test error")
+	if _, err := m.addL1Gas(context.Background(), tx); err == nil {
+		t.Fatalf("no error for failed estimate")
+	}
+}
+
+func TestNewBondWallet(t *testing.T) {
+	bondContractAddr := common.BytesToAddress(encode.RandomBytes(20))
+	tests := []struct {
+		name         string
+		contractAddr common.Address
+		walletType   string
+		wantBonder   bool
+	}{{
+		name:         "ok",
+		contractAddr: bondContractAddr,
+		walletType:   walletTypeRPC,
+		wantBonder:   true,
+	}, {
+		name:       "no bond contract",
+		walletType: walletTypeRPC,
+	}}
+	for _, test := range tests {
+		w := &ETHWallet{assetWallet: &assetWallet{baseWallet: &baseWallet{
+			bondContractAddr: test.contractAddr,
+			walletType:       test.walletType,
+		}}}
+		if bonder := newBondWallet(w); (bonder != nil) != test.wantBonder {
+			t.Fatalf("%s: wanted bonder = %t, got %t", test.name, test.wantBonder, bonder != nil)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex/config"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+)
+
+const (
+	swapTipRateKey      = "swaptiprate"
+	redeemMaxFeeRateKey = "redeemmaxfeerate"
+	redeemTipRateKey    = "redeemtiprate"
+)
+
+// swapOptions are the order options that apply to swaps. Tagged to be used
+// with config.Unmapify to decode asset.Swaps.Options. The max fee rate of a
+// swap can't be changed, since the fees are reserved when the order is
+// funded.
+type swapOptions struct {
+	TipRate uint64 `ini:"swaptiprate"` // gwei/gas
+}
+
+// redeemOptions are the order options that apply to redemptions. Tagged to be
+// used with config.Unmapify to decode asset.RedeemForm.Options.
+type redeemOptions struct {
+	MaxFeeRate uint64 `ini:"redeemmaxfeerate"` // gwei/gas
+	TipRate    uint64 `ini:"redeemtiprate"`    // gwei/gas
+}
+
+// gasFeeRates returns the max fee rate and tip rate for a transaction, in wei.
+// Zero or nil rates are replaced with the recommended rates. If only the tip
+// rate is set, the max fee rate leaves the usual room for the base fee to
+// double. The max fee rate can't be lower than the current base fee rate,
+// since the transaction wouldn't be mined.
+func (w *baseWallet) gasFeeRates(ctx context.Context, rates *asset.GasFeeRates) (maxFeeRate, tipRate *big.Int, err error) {
+	maxFeeRate, tipRate, err = w.recommendedMaxFeeRate(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting max fee rate: %w", err)
+	}
+	if rates == nil || (rates.MaxFeeRate == 0 && rates.TipRate == 0) {
+		return maxFeeRate, tipRate, nil
+	}
+	baseRate, _, err := w.currentNetworkFees(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rates.TipRate > 0 {
+		tipRate = dexeth.GweiToWei(rates.TipRate)
+	}
+	if rates.MaxFeeRate > 0 {
+		maxFeeRate = dexeth.GweiToWei(rates.MaxFeeRate)
+		if maxFeeRate.Cmp(baseRate) < 0 {
+			return nil, nil, fmt.Errorf("max fee rate %d gwei is lower than the current base fee rate %d gwei",
+				rates.MaxFeeRate, dexeth.WeiToGweiCeil(baseRate))
+		}
+	} else {
+		maxFeeRate = new(big.Int).Add(tipRate, new(big.Int).Mul(baseRate, big.NewInt(2)))
+	}
+	if tipRate.Cmp(maxFeeRate) > 0 {
+		return nil, nil, fmt.Errorf("tip rate %d gwei is higher than the max fee rate %d gwei",
+			dexeth.WeiToGweiCeil(tipRate), dexeth.WeiToGweiCeil(maxFeeRate))
+	}
+	return maxFeeRate, tipRate, nil
+}
+
+// previewGasFees is the cost of a transaction with the gas limit that pays
+// the fee rates.
+func (w *assetWallet) previewGasFees(gasLimit uint64, rates *asset.GasFeeRates) (*asset.GasFeePreview, error) {
+	maxFeeRate, tipRate, err := w.gasFeeRates(w.ctx, rates)
+	if err != nil {
+		return nil, err
+	}
+	baseRate, _, err := w.currentNetworkFees(w.ctx)
+	if err != nil {
+		return nil, err
+	}
+	maxFeeRateGwei := dexeth.WeiToGweiCeil(maxFeeRate)
+	tipRateGwei := dexeth.WeiToGweiCeil(tipRate)
+	baseRateGwei := dexeth.WeiToGweiCeil(baseRate)
+	return &asset.GasFeePreview{
+		MaxFeeRate:    maxFeeRateGwei,
+		TipRate:       tipRateGwei,
+		BaseFeeRate:   baseRateGwei,
+		GasLimit:      gasLimit,
+		MaxFees:       gasLimit * maxFeeRateGwei,
+		EstimatedFees: gasLimit * min(maxFeeRateGwei, baseRateGwei+tipRateGwei),
+	}, nil
+}
+
+// orderGasLimit is the gas limit of a single swap or redemption.
+func (w *assetWallet) orderGasLimit(txType asset.TransactionType, assetVer uint32) (uint64, error) {
+	g := w.gases(contractVersion(assetVer))
+	if g == nil {
+		return 0, fmt.Errorf("no gases known for %d contract version %d", w.assetID, contractVersion(assetVer))
+	}
+	if txType == asset.Swap {
+		return g.Swap, nil
+	}
+	return g.Redeem, nil
+}
+
+// PreviewGasFees validates the fee rates for a transaction and returns the
+// cost. Swap and Redeem previews are for a single match.
+// Part of the asset.GasFeeSetter interface.
+func (w *ETHWallet) PreviewGasFees(txType asset.TransactionType, assetVer uint32, rates *asset.GasFeeRates) (*asset.GasFeePreview, error) {
+	var gasLimit uint64
+	switch txType {
+	case asset.Send:
+		gasLimit = defaultSendGasLimit
+	case asset.Swap, asset.Redeem:
+		var err error
+		if gasLimit, err = w.orderGasLimit(txType, assetVer); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("fee rates can't be set for transaction type %d", txType)
+	}
+	return w.previewGasFees(gasLimit, rates)
+}
+
+// ApproveTokenWithGasFees is not supported by the base chain wallet.
+// Part of the asset.GasFeeSetter interface.
+func (w *ETHWallet) ApproveTokenWithGasFees(uint32, *asset.GasFeeRates, func()) (string, error) {
+	return "", errors.New("only token wallets send approval transactions")
+}
+
+// PreviewGasFees validates the fee rates for a transaction and returns the
+// cost, which is paid by the parent wallet. Swap and Redeem previews are for
+// a single match.
+// Part of the asset.GasFeeSetter interface.
+func (w *TokenWallet) PreviewGasFees(txType asset.TransactionType, assetVer uint32, rates *asset.GasFeeRates) (*asset.GasFeePreview, error) {
+	var gasLimit uint64
+	var err error
+	switch txType {
+	case asset.Send:
+		g := w.gases(dexeth.ContractVersionERC20)
+		if g == nil {
+			return nil, fmt.Errorf("gas table not found")
+		}
+		gasLimit = g.Transfer
+	case asset.ApproveToken:
+		if gasLimit, err = w.approvalGas(unlimitedAllowance, assetVer); err != nil {
+			return nil, fmt.Errorf("error calculating approval gas: %w", err)
+		}
+	case asset.Swap, asset.Redeem:
+		if gasLimit, err = w.orderGasLimit(txType, assetVer); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("fee rates can't be set for transaction type %d", txType)
+	}
+	return w.previewGasFees(gasLimit, rates)
+}
+
+// swapTipRate is the tip rate for a swap paying up to the max fee rate. The
+// tip rate can be set with the swaptiprate order option, but it is limited to
+// the max fee rate.
+func (w *assetWallet) swapTipRate(options map[string]string, maxFeeRate *big.Int) (*big.Int, error) {
+	opts := new(swapOptions)
+	if err := config.Unmapify(options, opts); err != nil {
+		return nil, fmt.Errorf("error parsing swap options: %w", err)
+	}
+	if opts.TipRate > 0 {
+		tipRate := dexeth.GweiToWei(opts.TipRate)
+		if tipRate.Cmp(maxFeeRate) > 0 {
+			return nil, fmt.Errorf("swap tip rate %d gwei is higher than the max fee rate %d gwei",
+				opts.TipRate, dexeth.WeiToGweiCeil(maxFeeRate))
+		}
+		return tipRate, nil
+	}
+	_, tipRate, err := w.currentNetworkFees(w.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network tip cap: %w", err)
+	}
+	return tipRate, nil
+}
+
+// swapOrderOptions are the order options for the tip rate of swaps, which
+// ranges from the current network tip rate to the max fee rate.
+func (w *assetWallet) swapOrderOptions(maxFeeRate uint64) ([]*asset.OrderOption, error) {
+	_, tipRate, err := w.currentNetworkFees(w.ctx)
+	if err != nil {
+		return nil, err
+	}
+	tipRateGwei := dexeth.WeiToGweiCeil(tipRate)
+	if tipRateGwei >= maxFeeRate {
+		return nil, nil
+	}
+	return []*asset.OrderOption{gasRateOption(swapTipRateKey, "Swap Priority Tip",
+		"Raise the priority tip paid to validators for faster confirmation of your swap transactions. "+
+			"The max fee rate of swaps is set by the server.", tipRateGwei, maxFeeRate)}, nil
+}
+
+// redeemOrderOptions are the order options for the max fee rate and tip rate
+// of redemptions.
+func (w *assetWallet) redeemOrderOptions(feeSuggestion uint64) ([]*asset.OrderOption, error) {
+	baseRate, tipRate, err := w.currentNetworkFees(w.ctx)
+	if err != nil {
+		return nil, err
+	}
+	maxFeeRate := max(feeSuggestion, 2*dexeth.WeiToGweiCeil(baseRate))
+	tipRateGwei := dexeth.WeiToGweiCeil(tipRate)
+	opts := []*asset.OrderOption{gasRateOption(redeemMaxFeeRateKey, "Redemption Max Fee Rate",
+		"Raise the max fee rate of your redemption transactions, so they can still be mined "+
+			"if the base fee rate rises. The difference is paid from your available balance.",
+		maxFeeRate, maxFeeRate*2)}
+	if tipRateGwei < maxFeeRate {
+		opts = append(opts, gasRateOption(redeemTipRateKey, "Redemption Priority Tip",
+			"Raise the priority tip paid to validators for faster confirmation of your redemption transactions.",
+			tipRateGwei, maxFeeRate))
+	}
+	return opts, nil
+}
+
+// gasRateOption is an order option for a fee rate in gwei/gas.
+func gasRateOption(key, name, desc string, start, end uint64) *asset.OrderOption {
+	return &asset.OrderOption{
+		ConfigOption: asset.ConfigOption{
+			Key:          key,
+			DisplayName:  name,
+			Description:  desc,
+			DefaultValue: start,
+		},
+		XYRange: &asset.XYRange{
+			Start: asset.XYRangePoint{
+				Label: "Current",
+				X:     float64(start),
+				Y:     float64(start),
+			},
+			End: asset.XYRangePoint{
+				Label: "Max",
+				X:     float64(end),
+				Y:     float64(end),
+			},
+			XUnit:  "gwei/gas",
+			YUnit:  "gwei/gas",
+			RoundX: true,
+			RoundY: true,
+		},
+	}
+}
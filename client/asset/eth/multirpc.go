@@ -118,6 +118,8 @@ type provider struct {
 		failCount    int
 		wsHeaderSeen atomic.Bool
 	}
+
+	health providerHealth
 }
 
 // String returns the provider host name.
@@ -172,8 +174,12 @@ func (p *provider) setFailed() {
 	p.tip.Unlock()
 }
 
-// failed will be true if setFailed has been called in the last failQuarantine.
+// failed will be true if setFailed has been called in the last failQuarantine,
+// or if the provider is blacklisted for misbehaving.
 func (p *provider) failed() bool {
+	if p.health.blacklisted() {
+		return true
+	}
 	p.tip.Lock()
 	defer p.tip.Unlock()
 	return p.tip.failCount > brickedFailCount || time.Since(p.tip.failStamp) < failQuarantine
@@ -403,6 +409,9 @@ type multiRPCClient struct {
 		cache     map[common.Hash]*receiptRecord
 		lastClean time.Time
 	}
+
+	// l1Gas is set for rollups that charge for L1 data in L2 gas.
+	l1Gas L1GasEstimator
 }
 
 var _ ethFetcher = (*multiRPCClient)(nil)
@@ -1042,9 +1051,12 @@ func (m *multiRPCClient) withOne(ctx context.Context, providers []*provider, f f
 	}
 	for _, p := range readyProviders {
 		ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+		start := time.Now()
 		err := f(ctx, p)
+		latency := time.Since(start)
 		cancel()
 		if err == nil {
+			m.recordHealth(p, latency, false)
 			return nil
 		}
 		if superError == nil {
@@ -1055,15 +1067,18 @@ func (m *multiRPCClient) withOne(ctx context.Context, providers []*provider, f f
 		for _, f := range acceptabilityFilters { // use case for more than one? is it just variadic to allow 0?
 			discard, propagate, fail := f(err)
 			if discard {
+				m.recordHealth(p, latency, false)
 				return nil
 			}
 			if propagate {
+				m.recordHealth(p, latency, false)
 				return err
 			}
 			if fail {
 				p.setFailed()
 			}
 		}
+		m.recordHealth(p, latency, true)
 	}
 	if superError == nil {
 		return errors.New("all providers in a failed state")
@@ -1093,9 +1108,12 @@ func (m *multiRPCClient) withAll(
 		}
 
 		ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+		start := time.Now()
 		err := f(ctx, p)
+		latency := time.Since(start)
 		cancel()
 		if err == nil {
+			m.recordHealth(p, latency, false)
 			atLeastOne = true // return nil err unless a later "propagated" error says to
 			continue
 		}
@@ -1111,9 +1129,11 @@ func (m *multiRPCClient) withAll(
 				p.setFailed()
 			}
 			if propagate {
+				m.recordHealth(p, latency, false)
 				return err
 			}
 		}
+		m.recordHealth(p, latency, !discarded)
 		if discarded {
 			atLeastOne = true
 		} else {
@@ -1139,12 +1159,11 @@ func (m *multiRPCClient) withAll(
 	return nil
 }
 
-// withAny runs the provider function against known providers in random order
-// until one succeeds or all have failed.
+// withAny runs the provider function against known providers in order of
+// their health until one succeeds or all have failed. Healthy providers with
+// the same score are tried in random order.
 func (m *multiRPCClient) withAny(ctx context.Context, f func(context.Context, *provider) error, acceptabilityFilters ...acceptabilityFilter) error {
-	providers := m.providerList()
-	shuffleProviders(providers)
-	return m.withOne(ctx, providers, f, acceptabilityFilters...)
+	return m.withOne(ctx, m.healthSortedProviders(), f, acceptabilityFilters...)
 }
 
 // withFreshest runs the provider function against known providers in order of
@@ -1318,7 +1337,16 @@ func (m *multiRPCClient) sendSignedTransaction(ctx context.Context, tx *types.Tr
 }
 
 func (m *multiRPCClient) sendTransaction(ctx context.Context, txOpts *bind.TransactOpts, to common.Address, data []byte, filts ...acceptabilityFilter) (*types.Transaction, error) {
-	tx, err := m.creds.ks.SignTx(*m.creds.acct, types.NewTx(&types.DynamicFeeTx{
+	tx, err := m.signTransaction(ctx, txOpts, to, data)
+	if err != nil {
+		return nil, err
+	}
+	return tx, m.sendSignedTransaction(ctx, tx, filts...)
+}
+
+// signTransaction authors and signs a transaction without broadcasting it.
+func (m *multiRPCClient) signTransaction(ctx context.Context, txOpts *bind.TransactOpts, to common.Address, data []byte) (*types.Transaction, error) {
+	tx, err := m.addL1Gas(ctx, types.NewTx(&types.DynamicFeeTx{
 		To:        &to,
 		ChainID:   m.chainID,
 		Nonce:     txOpts.Nonce.Uint64(),
@@ -1327,13 +1355,53 @@ func (m *multiRPCClient) sendTransaction(ctx context.Context, txOpts *bind.Trans
 		GasTipCap: txOpts.GasTipCap,
 		Value:     txOpts.Value,
 		Data:      data,
-	}), m.chainID)
+	}))
+	if err != nil {
+		return nil, err
+	}
 
+	tx, err = m.signTx(tx)
 	if err != nil {
-		return nil, fmt.Errorf("signing error: %v", err)
+		return nil, fmt.Errorf("signing error: %w", err)
 	}
+	return tx, nil
+}
 
-	return tx, m.sendSignedTransaction(ctx, tx, filts...)
+// L1GasEstimator estimates the gas that a rollup charges for posting a
+// transaction's data to L1, in addition to the gas used for execution on L2. to
+// is nil for a contract deployment.
+type L1GasEstimator func(ctx context.Context, cb bind.ContractCaller, to *common.Address, data []byte) (uint64, error)
+
+// l1GasPadding is the percent added to the estimated L1 gas. The L1 fee rate
+// can change before the transaction is mined, and unused gas is not charged.
+const l1GasPadding = 50
+
+// addL1Gas increases the transaction's gas limit by the padded L1 gas estimate
+// if this is a rollup. The gas limits used elsewhere in the wallet only cover
+// execution.
+func (m *multiRPCClient) addL1Gas(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	if m.l1Gas == nil {
+		return tx, nil
+	}
+	l1Gas, err := m.l1Gas(ctx, m, tx.To(), tx.Data())
+	if err != nil {
+		return nil, fmt.Errorf("error estimating L1 gas: %w", err)
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tx.GasTipCap(),
+		GasFeeCap: tx.GasFeeCap(),
+		Gas:       tx.Gas() + l1Gas*(100+l1GasPadding)/100,
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	}), nil
+}
+
+// signTx signs the transaction with the account's key.
+func (m *multiRPCClient) signTx(tx *types.Transaction) (*types.Transaction, error) {
+	return m.creds.wallet.SignTx(*m.creds.acct, tx, m.chainID)
 }
 
 func (m *multiRPCClient) signData(data []byte) (sig, pubKey []byte, err error) {
@@ -1419,7 +1487,11 @@ func (m *multiRPCClient) txOpts(ctx context.Context, val, maxGas uint64, maxFeeR
 	txOpts.Nonce = nonce
 
 	txOpts.Signer = func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
-		return m.creds.wallet.SignTx(*m.creds.acct, tx, m.chainID)
+		tx, err := m.addL1Gas(ctx, tx)
+		if err != nil {
+			return nil, err
+		}
+		return m.signTx(tx)
 	}
 
 	return txOpts, nil
@@ -1582,6 +1654,10 @@ func newCompatibilityTests(cb bind.ContractBackend, compat *CompatibilityData, l
 		{
 			name: "HeaderByHash",
 			f: func(ctx context.Context, p *provider) error {
+				if compat.BlockHash == (common.Hash{}) {
+					log.Debug("#### Skipping HeaderByHash. No block hash provided")
+					return nil
+				}
 				_, err := p.ec.HeaderByHash(ctx, compat.BlockHash)
 				return err
 			},
@@ -1589,6 +1665,10 @@ func newCompatibilityTests(cb bind.ContractBackend, compat *CompatibilityData, l
 		{
 			name: "TransactionReceipt",
 			f: func(ctx context.Context, p *provider) error {
+				if compat.TxHash == (common.Hash{}) {
+					log.Debug("#### Skipping TransactionReceipt. No tx hash provided")
+					return nil
+				}
 				_, err := p.ec.TransactionReceipt(ctx, compat.TxHash)
 				return err
 			},
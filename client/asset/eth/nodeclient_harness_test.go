@@ -756,6 +756,7 @@ func TestGas(t *testing.T) {
 	// t.Run("testInitiateGas", func(t *testing.T) { testInitiateGas(t, BipID) })
 	t.Run("testRedeemGas", func(t *testing.T) { testRedeemGas(t, BipID) })
 	t.Run("testRefundGas", func(t *testing.T) { testRefundGas(t, BipID) })
+	t.Run("testBondGas", testBondGas)
 }
 
 func TestTokenContract(t *testing.T) {
@@ -1815,6 +1816,63 @@ func testRefundGas(t *testing.T, assetID uint32) {
 	fmt.Printf("Gas used for refund: %v \n", gas)
 }
 
+// testBondGas creates and refunds a bond, and checks that the gas used is within
+// the limits used by the wallet. The bond contract is only deployed by the
+// harness if its bytecode was generated with build-bond.sh.
+func testBondGas(t *testing.T) {
+	bondContractAddr, found := dexeth.BondContractAddresses[dex.Simnet]
+	if !found {
+		t.Skip("no bond contract deployed")
+	}
+
+	var acctID [32]byte
+	copy(acctID[:], encode.RandomBytes(32))
+	bondID := dexeth.BondID(encode.RandomBytes(33))
+	lockTime := time.Now().Add(5 * secPerBlock)
+
+	checkGas := func(name string, data []byte, val, expGas uint64) {
+		t.Helper()
+		txOpts, err := ethClient.txOpts(ctx, val, expGas, nil, nil, nil)
+		if err != nil {
+			t.Fatalf("txOpts error: %v", err)
+		}
+		tx, err := ethClient.sendTransaction(ctx, txOpts, bondContractAddr, data)
+		if err != nil {
+			t.Fatalf("error sending %s transaction: %v", name, err)
+		}
+		if err := waitForMined(); err != nil {
+			t.Fatalf("unexpected error while waiting to mine: %v", err)
+		}
+		receipt, err := waitForReceipt(ethClient, tx)
+		if err != nil {
+			t.Fatalf("error getting %s receipt: %v", name, err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("%s transaction failed", name)
+		}
+		if receipt.GasUsed > expGas || receipt.GasUsed < expGas/2 {
+			t.Fatalf("expected %s gas to be near %d, but got %d", name, expGas, receipt.GasUsed)
+		}
+		fmt.Printf("Gas used for %s: %v \n", name, receipt.GasUsed)
+	}
+
+	data, err := dexeth.PackCreateBondData(acctID, bondID, uint64(lockTime.Unix()))
+	if err != nil {
+		t.Fatalf("PackCreateBondData error: %v", err)
+	}
+	checkGas(dexeth.CreateBondMethodName, data, 1, dexeth.CreateBondGas)
+
+	time.Sleep(time.Until(lockTime))
+	if err := waitForMined(); err != nil {
+		t.Fatalf("unexpected error while waiting to mine: %v", err)
+	}
+
+	if data, err = dexeth.PackRefundBondData(bondID); err != nil {
+		t.Fatalf("PackRefundBondData error: %v", err)
+	}
+	checkGas(dexeth.RefundBondMethodName, data, 0, dexeth.RefundBondGas)
+}
+
 func testRefund(t *testing.T, assetID uint32) {
 	if assetID != BipID {
 		prepareTokenClients(t)
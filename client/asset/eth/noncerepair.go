@@ -0,0 +1,190 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// stuckTxAge is how long a transaction can go unmined before it is
+	// reported as stuck.
+	stuckTxAge = 10 * time.Minute
+	// replacementFeeBump is the minimum increase of both fee rates, in
+	// percent, for a replacement transaction to be accepted by geth's
+	// transaction pool.
+	replacementFeeBump = 10
+)
+
+// stuckTxReason says why the pending transaction is unlikely to be mined
+// without replacement, or is empty if it isn't stuck.
+func stuckTxReason(pendingTx *extendedWalletTx, tx *types.Transaction, baseRate, confirmedNonce *big.Int, missingNonces []uint64) string {
+	if pendingTx.Nonce.Cmp(confirmedNonce) < 0 {
+		return "nonce was used by another transaction"
+	}
+	nonce := pendingTx.Nonce.Uint64()
+	for _, n := range missingNonces {
+		if n < nonce {
+			return fmt.Sprintf("waiting for missing nonce %d", n)
+		}
+	}
+	if tx.GasFeeCap().Cmp(baseRate) < 0 {
+		return "max fee rate is below the base fee rate"
+	}
+	if age := pendingTx.age(); age >= stuckTxAge {
+		return fmt.Sprintf("unconfirmed for %s", age.Round(time.Minute))
+	}
+	return ""
+}
+
+// NonceStatus detects missing nonces and stuck transactions.
+// Part of the asset.NonceRepairer interface.
+func (w *ETHWallet) NonceStatus() (*asset.NonceStatus, error) {
+	baseRate, _, err := w.currentNetworkFees(w.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting base fee rate: %w", err)
+	}
+	w.nonceMtx.RLock()
+	defer w.nonceMtx.RUnlock()
+	if w.confirmedNonceAt == nil || w.pendingNonceAt == nil {
+		return nil, errors.New("nonces not known yet")
+	}
+	missingNonces := findMissingNonces(w.confirmedNonceAt, w.pendingNonceAt, w.pendingTxs)
+	status := &asset.NonceStatus{
+		ConfirmedNonce: w.confirmedNonceAt.Uint64(),
+		PendingNonce:   w.pendingNonceAt.Uint64(),
+		MissingNonces:  missingNonces,
+		PendingTxs:     make([]*asset.NonceTx, 0, len(w.pendingTxs)),
+		BaseFeeRate:    dexeth.WeiToGweiCeil(baseRate),
+	}
+	for _, pendingTx := range w.pendingTxs {
+		if pendingTx.Confirmed || pendingTx.BlockNumber > 0 {
+			continue
+		}
+		tx, err := pendingTx.tx()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding transaction %s: %w", pendingTx.ID, err)
+		}
+		reason := stuckTxReason(pendingTx, tx, baseRate, w.confirmedNonceAt, missingNonces)
+		status.PendingTxs = append(status.PendingTxs, &asset.NonceTx{
+			ID:         pendingTx.ID,
+			Nonce:      pendingTx.Nonce.Uint64(),
+			Type:       pendingTx.Type,
+			TokenID:    pendingTx.TokenID,
+			Age:        uint64(pendingTx.age().Seconds()),
+			MaxFeeRate: dexeth.WeiToGweiCeil(tx.GasFeeCap()),
+			TipRate:    dexeth.WeiToGweiCeil(tx.GasTipCap()),
+			Stuck:      reason != "",
+			Reason:     reason,
+		})
+	}
+	return status, nil
+}
+
+// replacementFeeRates raises the fee rates enough for a replacement of the
+// transaction to be accepted.
+func replacementFeeRates(tx *types.Transaction, maxFeeRate, tipRate *big.Int) (*big.Int, *big.Int) {
+	minBumped := func(r *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(r, big.NewInt(100+replacementFeeBump))
+		bumped.Add(bumped, big.NewInt(99))
+		return bumped.Div(bumped, big.NewInt(100))
+	}
+	if minMax := minBumped(tx.GasFeeCap()); maxFeeRate.Cmp(minMax) < 0 {
+		maxFeeRate = minMax
+	}
+	if minTip := minBumped(tx.GasTipCap()); tipRate.Cmp(minTip) < 0 {
+		tipRate = minTip
+	}
+	if tipRate.Cmp(maxFeeRate) > 0 {
+		maxFeeRate = tipRate
+	}
+	return maxFeeRate, tipRate
+}
+
+// ReplaceTx replaces an unconfirmed transaction with the same transaction
+// paying higher fees.
+// Part of the asset.NonceRepairer interface.
+func (w *ETHWallet) ReplaceTx(txID string, rates *asset.GasFeeRates) (string, error) {
+	maxFeeRate, tipRate, err := w.gasFeeRates(w.ctx, rates)
+	if err != nil {
+		return "", err
+	}
+	var newTx *extendedWalletTx
+	if err := w.amendPendingTx(txID, func(_ common.Hash, tx *types.Transaction, pendingTx *extendedWalletTx, idx int) error {
+		if pendingTx.Confirmed || pendingTx.BlockNumber > 0 {
+			return fmt.Errorf("transaction %s is already mined", txID)
+		}
+		maxFeeRate, tipRate := replacementFeeRates(tx, maxFeeRate, tipRate)
+		newTx, err = w.bumpPendingTx(tx, pendingTx, idx, maxFeeRate, tipRate)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	if newTx == nil {
+		return "", fmt.Errorf("transaction %s is not pending", txID)
+	}
+	w.log.Infof("Replaced transaction %s with %s", txID, newTx.ID)
+	w.emitTransactionNote(newTx.WalletTransaction, true)
+	return newTx.ID, nil
+}
+
+// CancelTx replaces an unconfirmed transaction with a zero-value send to
+// ourselves. Cancelling a swap or redemption will cause the match to fail.
+// Part of the asset.NonceRepairer interface.
+func (w *ETHWallet) CancelTx(txID string, rates *asset.GasFeeRates) (string, error) {
+	maxFeeRate, tipRate, err := w.gasFeeRates(w.ctx, rates)
+	if err != nil {
+		return "", err
+	}
+	var newTx *extendedWalletTx
+	if err := w.amendPendingTx(txID, func(_ common.Hash, tx *types.Transaction, pendingTx *extendedWalletTx, idx int) error {
+		if pendingTx.Confirmed || pendingTx.BlockNumber > 0 {
+			return fmt.Errorf("transaction %s is already mined", txID)
+		}
+		maxFeeRate, tipRate := replacementFeeRates(tx, maxFeeRate, tipRate)
+		nonce := new(big.Int).SetUint64(tx.Nonce())
+		txOpts, err := w.node.txOpts(w.ctx, 0, defaultSendGasLimit, maxFeeRate, tipRate, nonce)
+		if err != nil {
+			return fmt.Errorf("error preparing tx opts: %w", err)
+		}
+		cancelTx, err := w.node.sendTransaction(w.ctx, txOpts, w.addr, nil)
+		if err != nil {
+			return fmt.Errorf("error sending cancellation transaction: %w", err)
+		}
+		recipient := w.addr.Hex()
+		newTx = w.extendedTx(&genTxResult{
+			tx:        cancelTx,
+			txType:    asset.SelfSend,
+			recipient: &recipient,
+		})
+		pendingTx.NonceReplacement = newTx.ID
+		w.tryStoreDBTx(pendingTx)
+		w.tryStoreDBTx(newTx)
+		w.pendingTxs[idx] = newTx
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	if newTx == nil {
+		return "", fmt.Errorf("transaction %s is not pending", txID)
+	}
+	w.log.Infof("Cancelled transaction %s with %s", txID, newTx.ID)
+	w.emitTransactionNote(newTx.WalletTransaction, true)
+	return newTx.ID, nil
+}
+
+// FillNonceGaps sends zero-value transactions to ourselves for the missing
+// nonces.
+// Part of the asset.NonceRepairer interface.
+func (w *ETHWallet) FillNonceGaps() ([]string, error) {
+	return w.fillMissingNonces()
+}
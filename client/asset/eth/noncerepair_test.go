@@ -0,0 +1,140 @@
+//go:build !harness && !rpclive
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestReplacementFeeRates(t *testing.T) {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasFeeCap: big.NewInt(100),
+		GasTipCap: big.NewInt(15),
+	})
+	for _, tt := range []struct {
+		name                string
+		maxFeeRate, tipRate int64
+		wantMaxFee, wantTip int64
+	}{
+		{"low rates bumped", 50, 1, 110, 17},
+		{"high rates kept", 200, 30, 200, 30},
+		{"max raised to tip", 110, 120, 120, 120},
+	} {
+		maxFeeRate, tipRate := replacementFeeRates(tx, big.NewInt(tt.maxFeeRate), big.NewInt(tt.tipRate))
+		if maxFeeRate.Int64() != tt.wantMaxFee || tipRate.Int64() != tt.wantTip {
+			t.Fatalf("%s: wanted %d/%d, got %s/%s", tt.name, tt.wantMaxFee, tt.wantTip, maxFeeRate, tipRate)
+		}
+	}
+}
+
+func TestNonceStatus(t *testing.T) {
+	w, eth, node, shutdown := tassetWallet(BipID)
+	defer shutdown()
+	repairer := w.(asset.NonceRepairer)
+
+	newPendingTx := func(nonce uint64) *extendedWalletTx {
+		return eth.extendedTx(&genTxResult{
+			tx:     node.newTransaction(nonce, dexeth.GweiToWei(1)),
+			txType: asset.Send,
+			amt:    1,
+		})
+	}
+	// tx2 is below the base fee, and tx4 is blocked by the missing nonce 3.
+	tx2, tx4 := newPendingTx(2), newPendingTx(4)
+	eth.pendingTxs = []*extendedWalletTx{tx2, tx4}
+	eth.confirmedNonceAt = big.NewInt(2)
+	eth.pendingNonceAt = big.NewInt(5)
+
+	status, err := repairer.NonceStatus()
+	if err != nil {
+		t.Fatalf("NonceStatus error: %v", err)
+	}
+	if len(status.MissingNonces) != 1 || status.MissingNonces[0] != 3 {
+		t.Fatalf("wrong missing nonces %v", status.MissingNonces)
+	}
+	if len(status.PendingTxs) != 2 {
+		t.Fatalf("expected 2 pending txs, got %d", len(status.PendingTxs))
+	}
+	if !status.PendingTxs[0].Stuck || status.PendingTxs[0].Reason != "max fee rate is below the base fee rate" {
+		t.Fatalf("wrong status for tx with low fees: %+v", status.PendingTxs[0])
+	}
+	if !status.PendingTxs[1].Stuck || status.PendingTxs[1].Reason != "waiting for missing nonce 3" {
+		t.Fatalf("wrong status for tx after gap: %+v", status.PendingTxs[1])
+	}
+
+	// A tx that pays enough is only stuck once it's old.
+	tx := types.NewTx(&types.DynamicFeeTx{GasFeeCap: dexeth.GweiToWei(500)})
+	if reason := stuckTxReason(tx2, tx, node.baseFee, eth.confirmedNonceAt, nil); reason != "" {
+		t.Fatalf("new tx reported as stuck: %s", reason)
+	}
+	tx2.SubmissionTime = uint64(time.Now().Add(-stuckTxAge).Unix())
+	if reason := stuckTxReason(tx2, tx, node.baseFee, eth.confirmedNonceAt, nil); reason == "" {
+		t.Fatalf("old tx not reported as stuck")
+	}
+}
+
+func TestReplaceAndCancelTx(t *testing.T) {
+	w, eth, node, shutdown := tassetWallet(BipID)
+	defer shutdown()
+	repairer := w.(asset.NonceRepairer)
+
+	newPendingTx := func() *extendedWalletTx {
+		return eth.extendedTx(&genTxResult{
+			tx:     node.newTransaction(1, dexeth.GweiToWei(1)),
+			txType: asset.Send,
+			amt:    1,
+		})
+	}
+
+	pendingTx := newPendingTx()
+	eth.pendingTxs = []*extendedWalletTx{pendingTx}
+	node.sendTxTx = node.newTransaction(1, dexeth.GweiToWei(1))
+	newTxID, err := repairer.ReplaceTx(pendingTx.ID, nil)
+	if err != nil {
+		t.Fatalf("ReplaceTx error: %v", err)
+	}
+	if newTxID != node.sendTxTx.Hash().String() || eth.pendingTxs[0].ID != newTxID {
+		t.Fatalf("tx not replaced")
+	}
+	if pendingTx.NonceReplacement != newTxID || !pendingTx.FeeReplacement {
+		t.Fatalf("replaced tx not updated")
+	}
+
+	// Unknown tx.
+	if _, err := repairer.ReplaceTx(pendingTx.ID, nil); err == nil {
+		t.Fatalf("no error for replaced tx")
+	}
+
+	// Bad rates.
+	pendingTx = newPendingTx()
+	eth.pendingTxs = []*extendedWalletTx{pendingTx}
+	if _, err := repairer.CancelTx(pendingTx.ID, &asset.GasFeeRates{MaxFeeRate: 1}); err == nil {
+		t.Fatalf("no error for max fee rate below base fee rate")
+	}
+
+	node.sendTxTx = node.newTransaction(1, new(big.Int))
+	newTxID, err = repairer.CancelTx(pendingTx.ID, nil)
+	if err != nil {
+		t.Fatalf("CancelTx error: %v", err)
+	}
+	if eth.pendingTxs[0].ID != newTxID || eth.pendingTxs[0].Type != asset.SelfSend {
+		t.Fatalf("tx not cancelled")
+	}
+	if pendingTx.NonceReplacement != newTxID || pendingTx.FeeReplacement {
+		t.Fatalf("cancelled tx not updated")
+	}
+
+	// Mined txs can't be replaced.
+	pendingTx = newPendingTx()
+	pendingTx.BlockNumber = 10
+	eth.pendingTxs = []*extendedWalletTx{pendingTx}
+	if _, err := repairer.CancelTx(pendingTx.ID, nil); err == nil {
+		t.Fatalf("no error for mined tx")
+	}
+}
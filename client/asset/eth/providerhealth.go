@@ -0,0 +1,200 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+const (
+	// healthSampleWeight is the weight of the newest request in the moving
+	// averages of a provider's latency and error rate.
+	healthSampleWeight = 0.2
+	// minHealthSamples is the number of requests needed before a provider can
+	// be blacklisted for its error rate.
+	minHealthSamples = 10
+	// maxProviderErrorRate is the error rate above which a provider is
+	// blacklisted.
+	maxProviderErrorRate = 0.5
+	// maxProviderHeadLag is the number of blocks a provider's best header can
+	// be behind the best header of all providers before it is blacklisted.
+	maxProviderHeadLag = 5
+	// headLagPenalty is the latency added to a provider's score for each
+	// block its best header is behind.
+	headLagPenalty = time.Second
+)
+
+// providerBlacklistDuration is how long a misbehaving provider is not used.
+// After this time, the provider's health is tracked from scratch.
+var providerBlacklistDuration = 10 * time.Minute
+
+// providerHealth tracks the latency, error rate, and head lag of a provider.
+type providerHealth struct {
+	sync.Mutex
+	samples          uint64
+	latency          float64 // moving average, ms
+	errRate          float64 // moving average, 0 to 1
+	headLag          uint64
+	blacklistedUntil time.Time
+}
+
+// record adds a request to the moving averages. The provider is blacklisted
+// if its error rate is too high, in which case true is returned.
+func (h *providerHealth) record(latency time.Duration, failed bool) (blacklisted bool) {
+	h.Lock()
+	defer h.Unlock()
+	ms := float64(latency.Milliseconds())
+	var fail float64
+	if failed {
+		fail = 1
+	}
+	if h.samples == 0 {
+		h.latency, h.errRate = ms, fail
+	} else {
+		h.latency += healthSampleWeight * (ms - h.latency)
+		h.errRate += healthSampleWeight * (fail - h.errRate)
+	}
+	h.samples++
+	if h.samples >= minHealthSamples && h.errRate > maxProviderErrorRate && !h.blacklistedLocked() {
+		h.blacklistLocked()
+		return true
+	}
+	return false
+}
+
+// setHeadLag sets the number of blocks the provider is behind. The provider
+// is blacklisted if it is too far behind, in which case true is returned.
+func (h *providerHealth) setHeadLag(lag uint64) (blacklisted bool) {
+	h.Lock()
+	defer h.Unlock()
+	h.headLag = lag
+	if lag > maxProviderHeadLag && !h.blacklistedLocked() {
+		h.blacklistLocked()
+		return true
+	}
+	return false
+}
+
+// blacklistLocked blacklists the provider and resets the moving averages, so
+// that the provider is judged on its new requests when the blacklisting
+// expires. The mutex must be held.
+func (h *providerHealth) blacklistLocked() {
+	h.blacklistedUntil = time.Now().Add(providerBlacklistDuration)
+	h.samples, h.latency, h.errRate = 0, 0, 0
+}
+
+func (h *providerHealth) blacklistedLocked() bool {
+	return time.Now().Before(h.blacklistedUntil)
+}
+
+// blacklisted is true if the provider has been misbehaving recently.
+func (h *providerHealth) blacklisted() bool {
+	h.Lock()
+	defer h.Unlock()
+	return h.blacklistedLocked()
+}
+
+// score is used to order providers. Lower is better. Providers without
+// requests have a zero score, so that they are tried.
+func (h *providerHealth) score() float64 {
+	h.Lock()
+	defer h.Unlock()
+	return h.latency*(1+4*h.errRate) + float64(h.headLag*uint64(headLagPenalty.Milliseconds()))
+}
+
+func (h *providerHealth) report(host string) *asset.ProviderHealth {
+	h.Lock()
+	defer h.Unlock()
+	return &asset.ProviderHealth{
+		Host:        host,
+		LatencyMS:   uint64(h.latency),
+		ErrorRate:   h.errRate,
+		HeadLag:     h.headLag,
+		Blacklisted: h.blacklistedLocked(),
+	}
+}
+
+// recordHealth records a request to the provider.
+func (m *multiRPCClient) recordHealth(p *provider, latency time.Duration, failed bool) {
+	if p.health.record(latency, failed) {
+		m.log.Warnf("Blacklisting provider %s for %s because of a high error rate", p.host, providerBlacklistDuration)
+	}
+}
+
+// updateHeadLags sets each provider's head lag from the best headers of the
+// providers.
+func (m *multiRPCClient) updateHeadLags(providers []*provider) {
+	heights := make([]uint64, len(providers))
+	var best uint64
+	for i, p := range providers {
+		p.tip.RLock()
+		if p.tip.header != nil {
+			heights[i] = p.tip.header.Number.Uint64()
+		}
+		p.tip.RUnlock()
+		best = max(best, heights[i])
+	}
+	for i, p := range providers {
+		if heights[i] == 0 {
+			continue // no header yet
+		}
+		if lag := best - heights[i]; p.health.setHeadLag(lag) {
+			m.log.Warnf("Blacklisting provider %s for %s because it is %d blocks behind",
+				p.host, providerBlacklistDuration, lag)
+		}
+	}
+}
+
+// healthSortedProviders returns the providers in order of their health
+// scores, with blacklisted providers last. Providers with the same score are
+// in random order.
+func (m *multiRPCClient) healthSortedProviders() []*provider {
+	providers := m.providerList()
+	m.updateHeadLags(providers)
+	shuffleProviders(providers)
+	type scoredProvider struct {
+		p           *provider
+		score       float64
+		blacklisted bool
+	}
+	sps := make([]*scoredProvider, len(providers))
+	for i, p := range providers {
+		sps[i] = &scoredProvider{p: p, score: p.health.score(), blacklisted: p.health.blacklisted()}
+	}
+	sort.SliceStable(sps, func(i, j int) bool {
+		if sps[i].blacklisted != sps[j].blacklisted {
+			return !sps[i].blacklisted
+		}
+		return sps[i].score < sps[j].score
+	})
+	for i, sp := range sps {
+		providers[i] = sp.p
+	}
+	return providers
+}
+
+// providerHealth returns the health of the providers, in order of
+// preference.
+func (m *multiRPCClient) providerHealth() []*asset.ProviderHealth {
+	providers := m.healthSortedProviders()
+	healths := make([]*asset.ProviderHealth, len(providers))
+	for i, p := range providers {
+		healths[i] = p.health.report(p.host)
+	}
+	return healths
+}
+
+// ProviderHealth returns the health of the wallet's RPC providers, in order
+// of preference.
+// Part of the asset.ProviderHealthReporter interface.
+func (w *baseWallet) ProviderHealth() []*asset.ProviderHealth {
+	if m, is := w.node.(*multiRPCClient); is {
+		return m.providerHealth()
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+//go:build !harness && !rpclive
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestProviderHealthBlacklist(t *testing.T) {
+	var h providerHealth
+	for i := 0; i < minHealthSamples-1; i++ {
+		if h.record(time.Millisecond, true) {
+			t.Fatalf("blacklisted with %d samples", i+1)
+		}
+	}
+	if !h.record(time.Millisecond, true) {
+		t.Fatalf("not blacklisted for errors")
+	}
+	if !h.blacklisted() {
+		t.Fatalf("blacklisted not reported")
+	}
+	// Stats are reset.
+	if r := h.report("a"); r.ErrorRate != 0 || !r.Blacklisted {
+		t.Fatalf("wrong report after blacklisting: %+v", r)
+	}
+
+	// Mostly successful requests are ok.
+	h = providerHealth{}
+	for i := 0; i < minHealthSamples*2; i++ {
+		if h.record(time.Millisecond, i%3 == 0) {
+			t.Fatalf("blacklisted for a low error rate")
+		}
+	}
+
+	if h.setHeadLag(maxProviderHeadLag) {
+		t.Fatalf("blacklisted at max head lag")
+	}
+	if !h.setHeadLag(maxProviderHeadLag + 1) {
+		t.Fatalf("not blacklisted for head lag")
+	}
+
+	// Blacklisting expires.
+	h.blacklistedUntil = time.Now().Add(-time.Second)
+	if h.blacklisted() {
+		t.Fatalf("blacklisting didn't expire")
+	}
+}
+
+func TestHealthSortedProviders(t *testing.T) {
+	newProvider := func(host string, height int64, latency time.Duration) *provider {
+		p := &provider{host: host}
+		p.tip.header = &types.Header{Number: big.NewInt(height)}
+		p.health.record(latency, false)
+		return p
+	}
+	slow := newProvider("slow", 100, 2*time.Second)
+	fast := newProvider("fast", 100, time.Millisecond)
+	medium := newProvider("medium", 99, 10*time.Millisecond)
+	behind := newProvider("behind", 90, time.Millisecond)
+	m := &multiRPCClient{
+		log:       tLogger,
+		providers: []*provider{slow, behind, medium, fast},
+	}
+
+	providers := m.healthSortedProviders()
+	for i, exp := range []*provider{fast, medium, slow, behind} {
+		if providers[i] != exp {
+			t.Fatalf("wrong provider at position %d. expected %s, got %s", i, exp, providers[i])
+		}
+	}
+	if !behind.failed() {
+		t.Fatalf("lagging provider not failed")
+	}
+
+	healths := m.providerHealth()
+	if healths[0].Host != "fast" || healths[1].HeadLag != 1 || !healths[3].Blacklisted {
+		t.Fatalf("wrong provider health")
+	}
+}
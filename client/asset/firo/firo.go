@@ -22,6 +22,7 @@ import (
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 )
 
 const (
@@ -64,6 +65,7 @@ var (
 			// DefaultValue is false
 		},
 	}...)
+	rpcConfigOpts = append(configOpts, sparkConfigOpts...)
 	// WalletInfo defines some general information about a Firo wallet.
 	WalletInfo = &asset.WalletInfo{
 		Name:              "Firo",
@@ -75,7 +77,7 @@ var (
 				Tab:               "Firo Core (external)",
 				Description:       "Connect to firod",
 				DefaultConfigPath: dexbtc.SystemConfigPath("firo"),
-				ConfigOpts:        configOpts,
+				ConfigOpts:        rpcConfigOpts,
 				MultiFundingOpts:  btc.MultiFundingOpts,
 			},
 			{
@@ -104,6 +106,13 @@ func (d *Driver) Open(cfg *asset.WalletConfig, logger dex.Logger, network dex.Ne
 // DecodeCoinID creates a human-readable representation of a coin ID
 // for Firo.
 func (d *Driver) DecodeCoinID(coinID []byte) (string, error) {
+	// Spark transactions don't have transparent outputs, so the coin ID is
+	// just the tx hash.
+	if len(coinID) == chainhash.HashSize {
+		var txHash chainhash.Hash
+		copy(txHash[:], coinID)
+		return txHash.String(), nil
+	}
 	// Firo and Bitcoin have the same tx hash and output format.
 	return (&btc.Driver{}).DecodeCoinID(coinID)
 }
@@ -176,14 +185,7 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 
 	switch cfg.Type {
 	case walletTypeRPC:
-		var exw *btc.ExchangeWalletFullNode
-		// override PrivKeyFunc - we need our own Firo dumpprivkey fn
-		cloneCFG.PrivKeyFunc = func(addr string) (*btcec.PrivateKey, error) {
-			return privKeyForAddress(exw, addr)
-		}
-		var err error
-		exw, err = btc.BTCCloneWallet(cloneCFG)
-		return exw, err
+		return newFullNodeWallet(cloneCFG)
 	case walletTypeElectrum:
 		// override Ports - no default ports
 		cloneCFG.Ports = dexbtc.NetPorts{}
@@ -0,0 +1,315 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package firo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/btc"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/config"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	methodGetSparkBalance        = "getsparkbalance"
+	methodGetSparkDefaultAddress = "getsparkdefaultaddress"
+	methodMintSpark              = "mintspark"
+	methodSpendSpark             = "spendspark"
+)
+
+var sparkConfigOpts = []*asset.ConfigOption{
+	{
+		Key:         "sparkchange",
+		DisplayName: "Anonymize change",
+		Description: "After a send from the transparent balance, mint the " +
+			"value of the change into the wallet's Spark balance. Spark funds " +
+			"can be sent, but can't be used to fund orders.",
+		IsBoolean: true,
+	},
+}
+
+// sparkConfig is the Spark-related wallet configuration.
+type sparkConfig struct {
+	AnonymizeChange bool `ini:"sparkchange"`
+}
+
+// sparkHRP returns the human-readable part of Spark addresses for the
+// network.
+func sparkHRP(net dex.Network) string {
+	switch net {
+	case dex.Mainnet:
+		return "sm"
+	case dex.Testnet:
+		return "st"
+	default:
+		return "sr"
+	}
+}
+
+// isSparkAddress checks whether the address is a Spark address for the
+// network. Spark addresses are longer than the bech32 length limit.
+func isSparkAddress(addr string, net dex.Network) bool {
+	hrp, _, err := bech32.DecodeNoLimit(addr)
+	return err == nil && hrp == sparkHRP(net)
+}
+
+func toSatoshi(v float64) uint64 {
+	amt, _ := btcutil.NewAmount(v) // zero if invalid
+	return uint64(amt)
+}
+
+// sparkCoin is the coin returned for Spark transactions, which don't have
+// transparent outputs.
+type sparkCoin struct {
+	txHash *chainhash.Hash
+	v      uint64
+}
+
+var _ asset.Coin = (*sparkCoin)(nil)
+
+// ID is the transaction hash.
+func (c *sparkCoin) ID() dex.Bytes {
+	return c.txHash[:]
+}
+
+// String is a string representation of the coin.
+func (c *sparkCoin) String() string {
+	return c.txHash.String()
+}
+
+// Value is the amount sent.
+func (c *sparkCoin) Value() uint64 {
+	return c.v
+}
+
+// TxID is the ID of the transaction.
+func (c *sparkCoin) TxID() string {
+	return c.txHash.String()
+}
+
+// ExchangeWalletFullNode is a firod wallet with Spark support. The Spark
+// balance is reported as locked, since it can't fund orders, but it can be
+// sent to both Spark and transparent addresses.
+type ExchangeWalletFullNode struct {
+	*btc.ExchangeWalletFullNode
+	net dex.Network
+	log dex.Logger
+	cfg atomic.Pointer[sparkConfig]
+}
+
+// newFullNodeWallet creates the firod wallet.
+func newFullNodeWallet(cloneCFG *btc.BTCCloneCFG) (*ExchangeWalletFullNode, error) {
+	sparkCfg := new(sparkConfig)
+	if err := config.Unmapify(cloneCFG.WalletCFG.Settings, sparkCfg); err != nil {
+		return nil, fmt.Errorf("error parsing Spark settings: %w", err)
+	}
+	w := &ExchangeWalletFullNode{
+		net: cloneCFG.Network,
+		log: cloneCFG.Logger,
+	}
+	w.cfg.Store(sparkCfg)
+	// override PrivKeyFunc - we need our own Firo dumpprivkey fn
+	cloneCFG.PrivKeyFunc = func(addr string) (*btcec.PrivateKey, error) {
+		return privKeyForAddress(w, addr)
+	}
+	cloneCFG.BalanceFunc = w.balance
+	var err error
+	w.ExchangeWalletFullNode, err = btc.BTCCloneWallet(cloneCFG)
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Reconfigure updates the Spark settings and reconfigures the wallet.
+func (w *ExchangeWalletFullNode) Reconfigure(ctx context.Context, cfg *asset.WalletConfig, currentAddress string) (bool, error) {
+	sparkCfg := new(sparkConfig)
+	if err := config.Unmapify(cfg.Settings, sparkCfg); err != nil {
+		return false, fmt.Errorf("error parsing Spark settings: %w", err)
+	}
+	restart, err := w.ExchangeWalletFullNode.Reconfigure(ctx, cfg, currentAddress)
+	if err != nil {
+		return false, err
+	}
+	w.cfg.Store(sparkCfg)
+	return restart, nil
+}
+
+type sparkBalance struct {
+	Available   uint64 `json:"availableBalance"`
+	Unconfirmed uint64 `json:"unconfirmedBalance"`
+	Full        uint64 `json:"fullBalance"`
+}
+
+func (w *ExchangeWalletFullNode) sparkBalance() (*sparkBalance, error) {
+	var bal sparkBalance
+	return &bal, w.CallRPC(methodGetSparkBalance, nil, &bal)
+}
+
+// balance is the BalanceFunc for firod wallets. The Spark balance is added to
+// the locked balance.
+func (w *ExchangeWalletFullNode) balance(_ context.Context, locked uint64) (*asset.Balance, error) {
+	var walletInfo btc.GetWalletInfoResult
+	if err := w.CallRPC("getwalletinfo", nil, &walletInfo); err != nil {
+		return nil, err
+	}
+	spark, err := w.sparkBalance()
+	if err != nil {
+		return nil, fmt.Errorf("error getting Spark balance: %w", err)
+	}
+	var available uint64
+	if bal := toSatoshi(walletInfo.Balance + walletInfo.UnconfirmedBalance); bal > locked {
+		available = bal - locked
+	}
+	return &asset.Balance{
+		Available: available,
+		Immature:  toSatoshi(walletInfo.ImmatureBalance),
+		Locked:    locked + spark.Full,
+		Other: map[asset.BalanceCategory]asset.CustomBalance{
+			asset.BalanceCategorySpark: {
+				Amount: spark.Full,
+				Locked: true,
+			},
+		},
+	}, nil
+}
+
+type sparkRecipient struct {
+	Amount      float64 `json:"amount"`
+	Memo        string  `json:"memo"`
+	SubtractFee bool    `json:"subtractFee"`
+}
+
+// sendSpark sends to the Spark address from the Spark balance if it's large
+// enough, otherwise the transparent balance is minted to the address.
+func (w *ExchangeWalletFullNode) sendSpark(addr string, value uint64, subtract bool) (*chainhash.Hash, error) {
+	bal, err := w.sparkBalance()
+	if err != nil {
+		return nil, fmt.Errorf("error getting Spark balance: %w", err)
+	}
+	recips := map[string]*sparkRecipient{
+		addr: {
+			Amount:      btcutil.Amount(value).ToBTC(),
+			SubtractFee: subtract,
+		},
+	}
+	var txID string
+	if bal.Available >= value {
+		if err := w.CallRPC(methodSpendSpark, []any{recips}, &txID); err != nil {
+			return nil, fmt.Errorf("spendspark error: %w", err)
+		}
+	} else {
+		var txIDs []string
+		if err := w.CallRPC(methodMintSpark, []any{recips}, &txIDs); err != nil {
+			return nil, fmt.Errorf("mintspark error: %w", err)
+		}
+		if len(txIDs) != 1 {
+			return nil, fmt.Errorf("expected 1 Spark mint transaction, got %d", len(txIDs))
+		}
+		txID = txIDs[0]
+	}
+	return chainhash.NewHashFromStr(txID)
+}
+
+// Send sends the exact value to the specified address. Sends to Spark
+// addresses are made from the Spark balance when possible.
+func (w *ExchangeWalletFullNode) Send(addr string, value, feeRate uint64) (asset.Coin, error) {
+	if isSparkAddress(addr, w.net) {
+		txHash, err := w.sendSpark(addr, value, false)
+		if err != nil {
+			return nil, err
+		}
+		return &sparkCoin{txHash: txHash, v: value}, nil
+	}
+	coin, err := w.ExchangeWalletFullNode.Send(addr, value, feeRate)
+	if err != nil {
+		return nil, err
+	}
+	if w.cfg.Load().AnonymizeChange {
+		w.anonymizeChange(coin.(*btc.Output))
+	}
+	return coin, nil
+}
+
+// Withdraw sends the value to the specified address, subtracting the fees
+// from the value.
+func (w *ExchangeWalletFullNode) Withdraw(addr string, value, feeRate uint64) (asset.Coin, error) {
+	if isSparkAddress(addr, w.net) {
+		txHash, err := w.sendSpark(addr, value, true)
+		if err != nil {
+			return nil, err
+		}
+		return &sparkCoin{txHash: txHash, v: value}, nil
+	}
+	return w.ExchangeWalletFullNode.Withdraw(addr, value, feeRate)
+}
+
+// anonymizeChange mints the value of the change output of a send transaction
+// into the wallet's Spark balance. firod selects the inputs for the mint, so
+// an equal value of other confirmed funds may be used while the change is
+// unconfirmed. Errors are logged, since the send has already succeeded.
+func (w *ExchangeWalletFullNode) anonymizeChange(sent *btc.Output) {
+	var tx btc.GetTransactionResult
+	if err := w.CallRPC("gettransaction", []any{sent.Pt.TxHash.String()}, &tx); err != nil {
+		w.log.Errorf("Error getting send transaction %s to anonymize change: %v", sent.Pt.TxHash, err)
+		return
+	}
+	msgTx := wire.NewMsgTx(wire.TxVersion)
+	if err := msgTx.Deserialize(bytes.NewReader(tx.Bytes)); err != nil {
+		w.log.Errorf("Error decoding send transaction %s: %v", sent.Pt.TxHash, err)
+		return
+	}
+	var change uint64
+	for vout, txOut := range msgTx.TxOut {
+		if uint32(vout) != sent.Pt.Vout {
+			change += uint64(txOut.Value)
+		}
+	}
+	if change == 0 {
+		return
+	}
+	var addrs []string
+	if err := w.CallRPC(methodGetSparkDefaultAddress, nil, &addrs); err != nil || len(addrs) == 0 {
+		w.log.Errorf("Error getting Spark address: addrs = %v, err = %v", addrs, err)
+		return
+	}
+	recips := map[string]*sparkRecipient{
+		addrs[0]: {
+			Amount:      btcutil.Amount(change).ToBTC(),
+			SubtractFee: true,
+		},
+	}
+	var txIDs []string
+	if err := w.CallRPC(methodMintSpark, []any{recips}, &txIDs); err != nil {
+		w.log.Warnf("Unable to mint change from send %s to Spark: %v", sent.Pt.TxHash, err)
+		return
+	}
+	w.log.Infof("Minted %s change from send %s to Spark in %v", btcutil.Amount(change), sent.Pt.TxHash, txIDs)
+}
+
+// ValidateAddress checks that the address is a valid transparent, exchange,
+// or Spark address.
+func (w *ExchangeWalletFullNode) ValidateAddress(addr string) bool {
+	return isSparkAddress(addr, w.net) || w.ExchangeWalletFullNode.ValidateAddress(addr)
+}
+
+// EstimateSendTxFee returns a tx fee estimate for sending or withdrawing the
+// provided amount. For Spark addresses, the estimate is for a transparent send
+// of the same value, since firod doesn't estimate Spark transaction fees.
+func (w *ExchangeWalletFullNode) EstimateSendTxFee(addr string, sendAmount, feeRate uint64, subtract, maxWithdraw bool) (uint64, bool, error) {
+	if !isSparkAddress(addr, w.net) {
+		return w.ExchangeWalletFullNode.EstimateSendTxFee(addr, sendAmount, feeRate, subtract, maxWithdraw)
+	}
+	fee, _, err := w.ExchangeWalletFullNode.EstimateSendTxFee("", sendAmount, feeRate, subtract, maxWithdraw)
+	return fee, err == nil, err
+}
@@ -0,0 +1,37 @@
+package firo
+
+import (
+	"bytes"
+	"testing"
+
+	"decred.org/dcrdex/dex"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+func TestIsSparkAddress(t *testing.T) {
+	// Spark addresses encode more data than the bech32 length limit allows.
+	data := bytes.Repeat([]byte{0x0f}, 230)
+	mainnetAddr, _ := bech32.EncodeM("sm", data)
+	testnetAddr, _ := bech32.EncodeM("st", data)
+
+	if !isSparkAddress(mainnetAddr, dex.Mainnet) {
+		t.Fatalf("mainnet Spark address not recognized")
+	}
+	if !isSparkAddress(testnetAddr, dex.Testnet) {
+		t.Fatalf("testnet Spark address not recognized")
+	}
+	if isSparkAddress(testnetAddr, dex.Mainnet) {
+		t.Fatalf("testnet Spark address accepted for mainnet")
+	}
+	if isSparkAddress(exxAddress, dex.Mainnet) {
+		t.Fatalf("EXX address recognized as a Spark address")
+	}
+	// Corrupt the checksum.
+	bad := mainnetAddr[:len(mainnetAddr)-1] + "q"
+	if bad == mainnetAddr {
+		bad = mainnetAddr[:len(mainnetAddr)-1] + "p"
+	}
+	if isSparkAddress(bad, dex.Mainnet) {
+		t.Fatalf("Spark address with bad checksum accepted")
+	}
+}
@@ -1,6 +1,7 @@
 package importall
 
 import (
-	_ "decred.org/dcrdex/client/asset/eth"     // register eth asset
-	_ "decred.org/dcrdex/client/asset/polygon" // register polygon network
+	_ "decred.org/dcrdex/client/asset/arbitrum" // register arbitrum network
+	_ "decred.org/dcrdex/client/asset/eth"      // register eth asset
+	_ "decred.org/dcrdex/client/asset/polygon"  // register polygon network
 )
@@ -240,6 +240,10 @@ const (
 	// ErrNotEnoughConfirms is returned when a transaction is confirmed,
 	// but does not have enough confirmations to be trusted.
 	ErrNotEnoughConfirms = dex.ErrorKind("transaction does not have enough confirmations")
+	// ErrAwaitingSignature is returned when a transaction cannot be
+	// broadcast until it has been signed with an external signer. The caller
+	// should retry after the signed transaction has been submitted.
+	ErrAwaitingSignature = dex.ErrorKind("awaiting external signature")
 	// ErrWalletTypeDisabled indicates that a wallet type is no longer
 	// available.
 	ErrWalletTypeDisabled = dex.ErrorKind("wallet type has been disabled")
@@ -304,6 +308,30 @@ type Token struct {
 	SupportedAssetVersions []uint32          `json:"supportedAssetVersions"`
 }
 
+// CustomToken is a user-defined token, e.g. an ERC20 token that is not in the
+// built-in token list.
+type CustomToken struct {
+	// ParentID is the asset ID of the token's parent asset.
+	ParentID uint32 `json:"parentID"`
+	// Address is the token contract address.
+	Address string `json:"address"`
+	// Symbol is the token's ticker symbol, without the parent asset suffix.
+	Symbol string `json:"symbol"`
+	// Name is the display name of the token.
+	Name string `json:"name"`
+	// Decimals is the number of decimal places of the token's contract
+	// units.
+	Decimals uint8 `json:"decimals"`
+	// UnitInfo optionally overrides the display units of the token, e.g. to
+	// name the atomic unit or add alternative denominations. The
+	// conventional conversion factor must agree with Decimals.
+	UnitInfo *dex.UnitInfo `json:"unitInfo,omitempty"`
+	// SwapContractVersions are the versions of the swap contract that the
+	// token can be traded with. If not set, the versions that the parent
+	// asset supports for user-defined tokens are used.
+	SwapContractVersions []uint32 `json:"swapContractVersions,omitempty"`
+}
+
 // WalletInfo is auxiliary information about an ExchangeWallet.
 type WalletInfo struct {
 	// Name is the display name for the currency, e.g. "Decred"
@@ -584,6 +612,15 @@ type TxFeeEstimator interface {
 	EstimateSendTxFee(address string, value, feeRate uint64, subtract, maxWithdraw bool) (fee uint64, isValidAddress bool, err error)
 }
 
+// NameResolver is a wallet that can resolve human-readable names, such as ENS
+// names, to addresses.
+type NameResolver interface {
+	// ResolveName resolves the name to an address. An error is returned if
+	// the name is not a name that the wallet can resolve, or if it does not
+	// resolve to an address.
+	ResolveName(name string) (string, error)
+}
+
 // Broadcaster is a wallet that can send a raw transaction on the asset network.
 type Broadcaster interface {
 	// SendTransaction broadcasts a raw transaction, returning its coin ID.
@@ -669,12 +706,27 @@ type Bonder interface {
 
 // Rescanner is a wallet implementation with rescan functionality.
 type Rescanner interface {
-	// Rescan performs a rescan and block until it is done. If no birthday is
-	// provided, internal wallets may use a birthday concurrent with the
-	// earliest date at which a wallet was possible, which is asset-dependent.
+	// Rescan starts a rescan from the birthday. If no birthday is provided,
+	// wallets may use a birthday concurrent with the earliest date at which a
+	// wallet was possible, which is asset-dependent. The rescan may continue
+	// asynchronously. Progress is reported with RescanProgressNotes, the last
+	// of which is Done.
 	Rescan(ctx context.Context, bday /* unix time seconds */ uint64) error
 }
 
+// RescanProgress is the progress of a wallet rescan.
+type RescanProgress struct {
+	StartHeight    uint64 `json:"startHeight"`
+	ScannedThrough uint64 `json:"scannedThrough"`
+	TargetHeight   uint64 `json:"targetHeight"`
+	// ETA is the estimated number of seconds until the rescan is done, or
+	// zero if it can't be estimated yet.
+	ETA  uint64 `json:"eta"`
+	Done bool   `json:"done"`
+	// Err is set if the rescan failed.
+	Err string `json:"err,omitempty"`
+}
+
 // Recoverer is a wallet implementation with recover functionality.
 type Recoverer interface {
 	// GetRecoveryCfg returns information that will help the wallet get back to
@@ -734,6 +786,146 @@ type Bridger interface {
 	BridgeHistory(n int, refID *string, past bool) ([]*WalletTransaction, error)
 }
 
+// LightningInvoice is an invoice to receive funds over the Lightning Network.
+type LightningInvoice struct {
+	// Invoice is the BOLT 11 payment request.
+	Invoice string `json:"invoice"`
+	// PaymentHash is the hex-encoded payment hash, used to look up the
+	// status of the invoice.
+	PaymentHash string `json:"paymentHash"`
+	Amount      uint64 `json:"amount"`
+}
+
+// LightningPayment is the result of paying a Lightning invoice.
+type LightningPayment struct {
+	PaymentHash string `json:"paymentHash"`
+	Amount      uint64 `json:"amount"`
+	Fees        uint64 `json:"fees"`
+}
+
+// LightningTransferer is a wallet that can send and receive funds over the
+// Lightning Network, e.g. for fast transfers to and from an exchange.
+type LightningTransferer interface {
+	// LightningBalance returns the amount that can be sent over the
+	// Lightning Network.
+	LightningBalance(ctx context.Context) (uint64, error)
+	// LightningInvoice creates an invoice to receive amt.
+	LightningInvoice(ctx context.Context, amt uint64, memo string) (*LightningInvoice, error)
+	// LightningInvoiceStatus checks whether an invoice created with
+	// LightningInvoice has been paid.
+	LightningInvoiceStatus(ctx context.Context, paymentHash string) (settled bool, amtPaid uint64, err error)
+	// PayLightningInvoice pays an invoice, paying at most maxFee in routing
+	// fees.
+	PayLightningInvoice(ctx context.Context, invoice string, maxFee uint64) (*LightningPayment, error)
+}
+
+// ActionIDSignPSBT is the ActionID of an ActionRequiredNote emitted when a
+// transaction must be signed with an external signer. The payload is a
+// *PSBTRequest.
+const ActionIDSignPSBT = "signPSBT"
+
+// PSBTRequest is a transaction that needs to be signed with an external
+// signer, encoded as a partially signed bitcoin transaction (BIP 174).
+type PSBTRequest struct {
+	// ID identifies the request.
+	ID string `json:"id"`
+	// PSBT is the base64-encoded PSBT. Decred has no PSBT format, so the
+	// requests of a Decred multisig wallet have the hex-encoded transaction,
+	// with the signatures collected so far.
+	PSBT string `json:"psbt"`
+	// Stamp is the time the request was created, in unix milliseconds.
+	Stamp uint64 `json:"stamp"`
+}
+
+// ExternalSigner is a wallet for which transactions are signed on an
+// external device. Methods that must broadcast a transaction return
+// ErrAwaitingSignature until the signed PSBT has been submitted.
+type ExternalSigner interface {
+	// PendingPSBTs are the transactions waiting to be signed.
+	PendingPSBTs() []*PSBTRequest
+	// SubmitSignedPSBT submits the signed, base64-encoded PSBT for the
+	// request with the specified ID.
+	SubmitSignedPSBT(id, signedPSBT string) error
+}
+
+// MWEBPegger is a Litecoin wallet that can move funds into and out of the
+// MimbleWimble Extension Block (MWEB). MWEB outputs cannot fund swaps, so
+// funds received through MWEB must be pegged out before they can be traded.
+type MWEBPegger interface {
+	// PegIn sends amt from the wallet's regular outputs to one of its MWEB
+	// addresses.
+	PegIn(amt uint64) (txID string, err error)
+	// PegOut sends amt from the wallet's MWEB outputs to one of its regular
+	// addresses. The fee is subtracted from amt.
+	PegOut(amt uint64) (txID string, err error)
+}
+
+// WalletUTXO is an unspent output controlled by the wallet.
+type WalletUTXO struct {
+	TxID          string `json:"txid"`
+	Vout          uint32 `json:"vout"`
+	Address       string `json:"address"`
+	Value         uint64 `json:"value"`
+	Confirmations uint32 `json:"confs"`
+	// Token is true if the output carries a token, e.g. a Bitcoin Cash
+	// CashToken. Token outputs are never used to fund orders or pay fees.
+	Token bool `json:"token,omitempty"`
+}
+
+// UTXOLister is a wallet that can list its unspent outputs.
+type UTXOLister interface {
+	// ListUTXOs lists the wallet's unlocked unspent outputs, including any
+	// that can't be spent by the DEX.
+	ListUTXOs() ([]*WalletUTXO, error)
+}
+
+// FeeBumper is a wallet that can replace an unconfirmed send with a version
+// that pays a higher fee rate, e.g. with BIP 125 replace-by-fee.
+type FeeBumper interface {
+	// BumpFee replaces the unconfirmed transaction with one paying the
+	// specified fee rate, and returns the ID of the replacement. The original
+	// transaction must have been sent by the wallet with replacement
+	// enabled.
+	BumpFee(txID string, feeRate uint64) (string, error)
+}
+
+// GasFeeRates are user-chosen EIP-1559 fee rates for a transaction, in gwei
+// per gas. A zero rate is chosen by the wallet.
+type GasFeeRates struct {
+	MaxFeeRate uint64 `json:"maxFeeRate"`
+	TipRate    uint64 `json:"tipRate"`
+}
+
+// GasFeePreview is the cost of a transaction paying the fee rates. Rates are
+// in gwei per gas, and fees are in the atomic units of the fee asset.
+type GasFeePreview struct {
+	MaxFeeRate  uint64 `json:"maxFeeRate"`
+	TipRate     uint64 `json:"tipRate"`
+	BaseFeeRate uint64 `json:"baseFeeRate"`
+	GasLimit    uint64 `json:"gasLimit"`
+	// MaxFees is the most the transaction can cost, GasLimit * MaxFeeRate.
+	MaxFees uint64 `json:"maxFees"`
+	// EstimatedFees is the cost at the current base fee rate.
+	EstimatedFees uint64 `json:"estimatedFees"`
+}
+
+// GasFeeSetter is a wallet for an EIP-1559 chain that can send transactions
+// with user-chosen fee rates instead of the rates recommended by the wallet.
+type GasFeeSetter interface {
+	// PreviewGasFees validates the fee rates for a transaction of the type,
+	// which must be Send, ApproveToken, Swap, or Redeem, and returns the
+	// cost. The assetVer is the version of the swap contract for Swap, Redeem,
+	// and ApproveToken transactions.
+	PreviewGasFees(txType TransactionType, assetVer uint32, rates *GasFeeRates) (*GasFeePreview, error)
+	// SendWithGasFees sends the exact value to the address, paying the fee
+	// rates.
+	SendWithGasFees(addr string, value uint64, rates *GasFeeRates) (Coin, error)
+	// ApproveTokenWithGasFees is like TokenApprover.ApproveToken, but pays
+	// the fee rates. An error is returned if the wallet is not a token
+	// wallet.
+	ApproveTokenWithGasFees(assetVer uint32, rates *GasFeeRates, onConfirm func()) (string, error)
+}
+
 // Sweeper is a wallet that can clear the entire balance of the wallet/account
 // to an address. Similar to Withdraw, but no input value is required.
 type Sweeper interface {
@@ -800,6 +992,24 @@ type FundsMixingStats struct {
 	MixedFunds uint64 `json:"mixedFunds"`
 	// TradingFunds is the total amout of funds in the trading account.
 	TradingFunds uint64 `json:"tradingFunds"`
+	// UnmixedFunds is the total amount of funds in the unmixed account.
+	UnmixedFunds uint64 `json:"unmixedFunds"`
+	// QueuedDenominations are the standard denominations that the unmixed
+	// funds are expected to be mixed into in the next mixing cycle.
+	QueuedDenominations []*MixDenomination `json:"queuedDenominations"`
+	// LastMix is the time of the last successful mixing cycle, in unix
+	// seconds. Zero if there hasn't been one since the wallet was started.
+	LastMix uint64 `json:"lastMix"`
+	// MixFailures is the number of consecutive failed mixing cycles.
+	MixFailures uint32 `json:"mixFailures"`
+	// LastMixError is the error from the last mixing cycle, if it failed.
+	LastMixError string `json:"lastMixError,omitempty"`
+}
+
+// MixDenomination is a number of mixed outputs of the same value.
+type MixDenomination struct {
+	Value uint64 `json:"value"`
+	Count uint32 `json:"count"`
 }
 
 // FundsMixer defines methods for mixing funds in a wallet.
@@ -1016,6 +1226,120 @@ type TokenApprover interface {
 	ApprovalFee(assetVer uint32, approval bool) (uint64, error)
 }
 
+// TokenAllowance is the amount of a token that a version of the swap contract
+// is allowed to spend on behalf of the wallet.
+type TokenAllowance struct {
+	Version  uint32 `json:"version"`
+	Contract string `json:"contract"`
+	// Amount is the allowance in atomic units. Amount is not set if the
+	// allowance is Unlimited.
+	Amount    uint64 `json:"amount"`
+	Unlimited bool   `json:"unlimited"`
+	// Pending is true if an approval transaction for the contract has not
+	// been confirmed yet.
+	Pending bool `json:"pending"`
+}
+
+// TokenAllowanceManager is implemented by token wallets that can list and
+// adjust the allowances granted to the swap contracts.
+type TokenAllowanceManager interface {
+	// TokenAllowances returns the allowance for each version of the swap
+	// contract.
+	TokenAllowances() ([]*TokenAllowance, error)
+	// SetTokenAllowance sends a transaction setting the allowance of a
+	// version of the swap contract to amount atomic units. A zero amount
+	// revokes the approval. The token must be approved again with
+	// ApproveToken before trading with a tightened allowance.
+	SetTokenAllowance(assetVer uint32, amount uint64, onConfirm func()) (string, error)
+}
+
+// KeySweeper is implemented by wallets that can move the funds controlled by
+// an external private key, e.g. a paper wallet, into the wallet. The key's
+// encoding is asset-specific, e.g. WIF for Bitcoin.
+type KeySweeper interface {
+	// PrivateKeyFunds finds the unspent funds controlled by the private key.
+	PrivateKeyFunds(privKey string) (uint64, error)
+	// SweepPrivateKey sends all of the funds controlled by the private key to
+	// the wallet in a single transaction paying feeRate. The ID of the
+	// transaction and the amount received by the wallet are returned.
+	SweepPrivateKey(privKey string, feeRate uint64) (txID string, received uint64, err error)
+}
+
+// ProviderHealth is the health of an RPC provider used by a wallet.
+type ProviderHealth struct {
+	Host string `json:"host"`
+	// LatencyMS is the recent average response time.
+	LatencyMS uint64 `json:"latencyMS"`
+	// ErrorRate is the recent fraction of failed requests, 0 to 1.
+	ErrorRate float64 `json:"errorRate"`
+	// HeadLag is how many blocks the provider's best header is behind the
+	// best header of all providers.
+	HeadLag uint64 `json:"headLag"`
+	// Blacklisted is true if the provider is not being used because it has
+	// been misbehaving.
+	Blacklisted bool `json:"blacklisted"`
+}
+
+// ProviderHealthReporter is a wallet that uses RPC providers and tracks their
+// health.
+type ProviderHealthReporter interface {
+	// ProviderHealth returns the health of the wallet's RPC providers, in
+	// order of preference.
+	ProviderHealth() []*ProviderHealth
+}
+
+// NonceTx is an unconfirmed transaction of an account-based wallet.
+type NonceTx struct {
+	ID    string          `json:"id"`
+	Nonce uint64          `json:"nonce"`
+	Type  TransactionType `json:"type"`
+	// TokenID is set for token transactions.
+	TokenID *uint32 `json:"tokenID,omitempty"`
+	// Age is the time since the transaction was broadcast, in seconds.
+	Age uint64 `json:"age"`
+	// MaxFeeRate and TipRate are in gwei/gas.
+	MaxFeeRate uint64 `json:"maxFeeRate"`
+	TipRate    uint64 `json:"tipRate"`
+	// Stuck is true if the transaction is unlikely to be mined without
+	// replacement, and Reason says why.
+	Stuck  bool   `json:"stuck"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// NonceStatus is the state of an account's transaction sequence.
+type NonceStatus struct {
+	// ConfirmedNonce is the nonce of the next transaction to be mined.
+	ConfirmedNonce uint64 `json:"confirmedNonce"`
+	// PendingNonce is the nonce of the next transaction to be sent.
+	PendingNonce uint64 `json:"pendingNonce"`
+	// MissingNonces are nonces with no known transaction, which block all
+	// of the transactions with higher nonces.
+	MissingNonces []uint64 `json:"missingNonces"`
+	// PendingTxs are the unconfirmed transactions, in nonce order.
+	PendingTxs []*NonceTx `json:"pendingTxs"`
+	// BaseFeeRate is the current base fee rate, in gwei/gas.
+	BaseFeeRate uint64 `json:"baseFeeRate"`
+}
+
+// NonceRepairer is an account-based wallet that can repair its transaction
+// sequence, since a single stuck transaction blocks every transaction after
+// it.
+type NonceRepairer interface {
+	// NonceStatus detects missing nonces and stuck transactions.
+	NonceStatus() (*NonceStatus, error)
+	// ReplaceTx replaces an unconfirmed transaction with the same transaction
+	// paying higher fees, and returns the ID of the replacement. Nil or zero
+	// rates are chosen by the wallet, but the fees are always raised enough
+	// for the network to accept the replacement.
+	ReplaceTx(txID string, rates *GasFeeRates) (string, error)
+	// CancelTx replaces an unconfirmed transaction with a zero-value send to
+	// the wallet's own address, and returns the ID of the replacement.
+	CancelTx(txID string, rates *GasFeeRates) (string, error)
+	// FillNonceGaps sends zero-value transactions to the wallet's own
+	// address for the missing nonces, and returns their IDs.
+	FillNonceGaps() ([]string, error)
+}
+
 // TicketTransaction represents a ticket transaction.
 type TicketTransaction struct {
 	Hash        string `json:"hash"`
@@ -1333,9 +1657,18 @@ type BalanceCategory string
 // these balance categories should change, the customWalletBalanceCategory
 // function in the wallet.js file above should be updated with the new value.
 const (
-	BalanceCategoryShielded = "Shielded"
-	BalanceCategoryUnmixed  = "Unmixed"
-	BalanceCategoryStaked   = "Staked"
+	BalanceCategoryShielded   = "Shielded"
+	BalanceCategoryUnmixed    = "Unmixed"
+	BalanceCategoryStaked     = "Staked"
+	BalanceCategoryMWEB       = "MWEB"
+	BalanceCategorySapling    = "Sapling"
+	BalanceCategoryCashTokens = "CashTokens"
+	BalanceCategorySpark      = "Spark"
+	// BalanceCategoryPendingDeposit is incoming funds that the wallet has
+	// seen in the mempool, but that are not yet mined. The Amount is also
+	// included in Balance.Immature or, if the wallet can spend unconfirmed
+	// funds, Balance.Available.
+	BalanceCategoryPendingDeposit = "PendingDeposit"
 )
 
 // Coin is some amount of spendable asset. Coin provides the information needed
@@ -1607,6 +1940,20 @@ type TransactionNote struct {
 	New         bool               `json:"new"`
 }
 
+// RescanProgressNote is sent periodically during a rescan, and when it ends.
+type RescanProgressNote struct {
+	baseWalletNotification
+	Progress *RescanProgress `json:"progress"`
+}
+
+// PendingDepositNote is sent when the wallet sees an incoming transaction
+// with no confirmations, so that funds on the way can be shown before they are
+// mined.
+type PendingDepositNote struct {
+	baseWalletNotification
+	Transaction *WalletTransaction `json:"transaction"`
+}
+
 // CustomWalletNote is any other information the wallet wishes to convey to
 // the user.
 type CustomWalletNote struct {
@@ -1712,6 +2059,28 @@ func (e *WalletEmitter) TransactionHistorySyncedNote() {
 	})
 }
 
+// RescanProgress sends a RescanProgressNote.
+func (e *WalletEmitter) RescanProgress(p *RescanProgress) {
+	e.emit(&RescanProgressNote{
+		baseWalletNotification: baseWalletNotification{
+			AssetID: e.assetID,
+			Route:   "rescanProgress",
+		},
+		Progress: p,
+	})
+}
+
+// PendingDeposit sends a PendingDepositNote.
+func (e *WalletEmitter) PendingDeposit(tx *WalletTransaction) {
+	e.emit(&PendingDepositNote{
+		baseWalletNotification: baseWalletNotification{
+			AssetID: e.assetID,
+			Route:   "pendingDeposit",
+		},
+		Transaction: tx,
+	})
+}
+
 // ActionRequired is a route that will end up as a special dialogue seeking
 // user input via (ActionTaker).TakeAction.
 func (e *WalletEmitter) ActionRequired(uniqueID, actionID string, payload any) {
@@ -65,10 +65,12 @@ var (
 		Type:             walletTypeSPV,
 		Tab:              "Native",
 		Description:      "Use the built-in SPV wallet",
-		ConfigOpts:       btc.CommonConfigOpts("LTC", true),
+		ConfigOpts:       append(btc.CommonConfigOpts("LTC", true), btc.SPVPeerConfigOpts...),
 		Seeded:           true,
 		MultiFundingOpts: btc.MultiFundingOpts,
 	}
+	// Litecoin Core supports descriptor wallets since v0.21.
+	watchOnlyWalletDefinition = btc.WatchOnlyWalletDefinition("Litecoin", "LTC", "9332", "litecoin")
 	// WalletInfo defines some general information about a Litecoin wallet.
 	WalletInfo = &asset.WalletInfo{
 		Name:              "Litecoin",
@@ -78,6 +80,7 @@ var (
 			spvWalletDefinition,
 			rpcWalletDefinition,
 			electrumWalletDefinition,
+			watchOnlyWalletDefinition,
 		},
 	}
 )
@@ -229,8 +232,10 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 
 	switch cfg.Type {
 	case walletTypeRPC, walletTypeLegacy:
-		return btc.BTCCloneWallet(cloneCFG)
+		return newFullNodeWallet(cloneCFG)
 	case walletTypeSPV:
+		// The *btc.ExchangeWalletSPV accelerates orders itself, signing with
+		// the clone's wallet.
 		return btc.OpenSPVWallet(cloneCFG, openSPVWallet)
 	case walletTypeElectrum:
 		cloneCFG.Ports = dexbtc.NetPorts{} // no default ports
@@ -240,6 +245,8 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, network dex.Network)
 		}
 		cloneCFG.MinElectrumVersion = *ver
 		return btc.ElectrumWallet(cloneCFG)
+	case btc.WalletTypeWatchOnly:
+		return btc.WatchOnlyWallet(cloneCFG)
 	default:
 		makeCustomWallet, ok := customWalletConstructors[cfg.Type]
 		if !ok {
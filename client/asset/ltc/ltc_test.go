@@ -0,0 +1,60 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package ltc
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/btc"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	dexltc "decred.org/dcrdex/dex/networks/ltc"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+)
+
+func TestWalletAccelerates(t *testing.T) {
+	for _, walletType := range []string{walletTypeRPC, walletTypeSPV} {
+		cfg := &asset.WalletConfig{
+			Type: walletType,
+			Settings: map[string]string{
+				"rpcuser":     "user",
+				"rpcpassword": "pass",
+			},
+			DataDir: t.TempDir(),
+			Emit:    asset.NewWalletEmitter(make(chan asset.WalletNotification, 1), BipID, dex.StdOutLogger("T", dex.LevelOff)),
+		}
+		w, err := NewWallet(cfg, dex.StdOutLogger("T", dex.LevelOff), dex.Regtest)
+		if err != nil {
+			t.Fatalf("NewWallet error for %s wallet: %v", walletType, err)
+		}
+		if _, is := w.(asset.Accelerator); !is {
+			t.Fatalf("ltc %s wallet is not an asset.Accelerator", walletType)
+		}
+	}
+}
+
+func TestWatchOnlyWallet(t *testing.T) {
+	master, _ := hdkeychain.NewMaster(encode.RandomBytes(32), dexltc.RegressionNetParams)
+	acct, _ := master.Derive(hdkeychain.HardenedKeyStart + 84)
+	xpub, _ := acct.Neuter()
+	cfg := &asset.WalletConfig{
+		Type: btc.WalletTypeWatchOnly,
+		Settings: map[string]string{
+			"rpcuser":     "user",
+			"rpcpassword": "pass",
+			"walletname":  "watch",
+			"xpub":        xpub.String(),
+		},
+		DataDir: t.TempDir(),
+		Emit:    asset.NewWalletEmitter(make(chan asset.WalletNotification, 1), BipID, dex.StdOutLogger("T", dex.LevelOff)),
+	}
+	w, err := NewWallet(cfg, dex.StdOutLogger("T", dex.LevelOff), dex.Regtest)
+	if err != nil {
+		t.Fatalf("NewWallet error: %v", err)
+	}
+	if _, is := w.(asset.ExternalSigner); !is {
+		t.Fatalf("watch-only wallet is not an asset.ExternalSigner")
+	}
+}
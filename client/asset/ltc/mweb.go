@@ -0,0 +1,181 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package ltc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/btc"
+	"decred.org/dcrdex/dex"
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// mwebHRP returns the human-readable part of MWEB addresses for the network.
+func mwebHRP(net dex.Network) string {
+	if net == dex.Mainnet {
+		return "ltcmweb"
+	}
+	return "tmweb"
+}
+
+// isMWEBUnspent checks whether the litecoind listunspent result is an MWEB
+// output. MWEB outputs can only be spent by litecoind itself.
+func isMWEBUnspent(hrp string, u *btc.ListUnspentResult) bool {
+	return strings.HasPrefix(u.Address, hrp+"1")
+}
+
+func toSatoshi(v float64) uint64 {
+	amt, _ := btcutil.NewAmount(v) // zero if invalid
+	return uint64(amt)
+}
+
+// ExchangeWalletFullNode is a litecoind wallet. Outputs in the MimbleWimble
+// Extension Block are not used to fund orders, and are reported as a locked
+// MWEB balance. Funds can be pegged into and out of MWEB, and swaps can be
+// accelerated.
+type ExchangeWalletFullNode struct {
+	*btc.ExchangeWalletAccelerator
+	hrp string
+	log dex.Logger
+
+	// pegMtx prevents concurrent peg-outs, which temporarily lock the
+	// wallet's regular outputs.
+	pegMtx sync.Mutex
+}
+
+var _ asset.MWEBPegger = (*ExchangeWalletFullNode)(nil)
+var _ asset.Accelerator = (*ExchangeWalletFullNode)(nil)
+
+// newFullNodeWallet creates the litecoind wallet.
+func newFullNodeWallet(cloneCFG *btc.BTCCloneCFG) (*ExchangeWalletFullNode, error) {
+	w := &ExchangeWalletFullNode{
+		hrp: mwebHRP(cloneCFG.Network),
+		log: cloneCFG.Logger,
+	}
+	cloneCFG.ExcludeUnspent = func(u *btc.ListUnspentResult) bool {
+		return isMWEBUnspent(w.hrp, u)
+	}
+	cloneCFG.BalanceFunc = w.balance
+	fn, err := btc.BTCCloneWallet(cloneCFG)
+	if err != nil {
+		return nil, err
+	}
+	w.ExchangeWalletAccelerator = &btc.ExchangeWalletAccelerator{ExchangeWalletFullNode: fn}
+	return w, nil
+}
+
+// listUnspent lists all of the wallet's unlocked outputs, including MWEB
+// outputs.
+func (w *ExchangeWalletFullNode) listUnspent() ([]*btc.ListUnspentResult, error) {
+	var unspents []*btc.ListUnspentResult
+	if err := w.CallRPC("listunspent", []any{0}, &unspents); err != nil {
+		return nil, err
+	}
+	return unspents, nil
+}
+
+// balance is the BalanceFunc for litecoind wallets. litecoind includes MWEB
+// outputs in the trusted balance, but they can't be spent by the DEX, so they
+// are moved to the locked balance.
+func (w *ExchangeWalletFullNode) balance(_ context.Context, locked uint64) (*asset.Balance, error) {
+	var balances btc.GetBalancesResult
+	if err := w.CallRPC("getbalances", nil, &balances); err != nil {
+		return nil, err
+	}
+	unspents, err := w.listUnspent()
+	if err != nil {
+		return nil, err
+	}
+	var mweb uint64
+	for _, u := range unspents {
+		if isMWEBUnspent(w.hrp, u) && u.Safe() {
+			mweb += toSatoshi(u.Amount)
+		}
+	}
+	var available uint64
+	if trusted := toSatoshi(balances.Mine.Trusted); trusted > locked+mweb {
+		available = trusted - locked - mweb
+	}
+	return &asset.Balance{
+		Available: available,
+		Immature:  toSatoshi(balances.Mine.Immature + balances.Mine.Untrusted),
+		Locked:    locked + mweb,
+		Other: map[asset.BalanceCategory]asset.CustomBalance{
+			asset.BalanceCategoryMWEB: {
+				Amount: mweb,
+				Locked: true,
+			},
+		},
+	}, nil
+}
+
+func (w *ExchangeWalletFullNode) sendToAddress(addr string, amt uint64, subtract bool) (string, error) {
+	var txID string
+	// args: address amount comment comment_to subtractfeefromamount
+	err := w.CallRPC("sendtoaddress", []any{addr, btcutil.Amount(amt).ToBTC(), "", "", subtract}, &txID)
+	if err != nil {
+		return "", err
+	}
+	return txID, nil
+}
+
+// PegIn sends amt from the wallet's regular outputs to a new MWEB address.
+// Part of the asset.MWEBPegger interface.
+func (w *ExchangeWalletFullNode) PegIn(amt uint64) (string, error) {
+	var addr string
+	if err := w.CallRPC("getnewaddress", []any{"", "mweb"}, &addr); err != nil {
+		return "", fmt.Errorf("error getting MWEB address: %w", err)
+	}
+	txID, err := w.sendToAddress(addr, amt, false)
+	if err != nil {
+		return "", fmt.Errorf("error sending to MWEB: %w", err)
+	}
+	return txID, nil
+}
+
+// PegOut sends amt from the wallet's MWEB outputs to a new regular address.
+// litecoind chooses the inputs, so the wallet's regular outputs are locked
+// while the transaction is created. Orders can't be funded during that time.
+// Part of the asset.MWEBPegger interface.
+func (w *ExchangeWalletFullNode) PegOut(amt uint64) (string, error) {
+	w.pegMtx.Lock()
+	defer w.pegMtx.Unlock()
+
+	unspents, err := w.listUnspent()
+	if err != nil {
+		return "", err
+	}
+	var regular []*btc.RPCOutpoint
+	for _, u := range unspents {
+		if !isMWEBUnspent(w.hrp, u) {
+			regular = append(regular, &btc.RPCOutpoint{TxID: u.TxID, Vout: u.Vout})
+		}
+	}
+	if len(regular) > 0 {
+		var success bool
+		if err := w.CallRPC("lockunspent", []any{false, regular}, &success); err != nil || !success {
+			return "", fmt.Errorf("error locking regular outputs: success = %t, err = %v", success, err)
+		}
+		defer func() {
+			var success bool
+			if err := w.CallRPC("lockunspent", []any{true, regular}, &success); err != nil || !success {
+				w.log.Errorf("Error unlocking regular outputs after MWEB peg-out: success = %t, err = %v", success, err)
+			}
+		}()
+	}
+
+	var addr string
+	if err := w.CallRPC("getnewaddress", []any{"", "bech32"}, &addr); err != nil {
+		return "", fmt.Errorf("error getting address: %w", err)
+	}
+	txID, err := w.sendToAddress(addr, amt, true)
+	if err != nil {
+		return "", fmt.Errorf("error sending from MWEB: %w", err)
+	}
+	return txID, nil
+}
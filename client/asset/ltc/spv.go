@@ -78,6 +78,7 @@ var (
 type ltcSPVWallet struct {
 	// This section is populated in openSPVWallet.
 	dir         string
+	cfg         *btc.WalletConfig
 	chainParams *ltcchaincfg.Params
 	btcParams   *chaincfg.Params
 	log         dex.Logger
@@ -107,6 +108,7 @@ func openSPVWallet(dir string, cfg *btc.WalletConfig, btcParams *chaincfg.Params
 	}
 	w := &ltcSPVWallet{
 		dir:         dir,
+		cfg:         cfg,
 		chainParams: ltcParams,
 		btcParams:   btcParams,
 		log:         log,
@@ -207,12 +209,25 @@ func (w *ltcSPVWallet) Start() (btc.SPVService, error) {
 	}
 	errCloser.Add(w.neutrinoDB.Close)
 
+	dnsSeeds := make([]string, 0, len(w.chainParams.DNSSeeds))
+	for _, seed := range w.chainParams.DNSSeeds {
+		dnsSeeds = append(dnsSeeds, seed.Host)
+	}
+	peerSettings, err := btc.NewSPVPeerSettings(w.cfg, w.chainParams.DefaultPort, dnsSeeds)
+	if err != nil {
+		return nil, err
+	}
+
 	w.log.Debug("Starting neutrino chain service...")
 	w.cl, err = neutrino.NewChainService(neutrino.Config{
 		DataDir:       w.dir,
 		Database:      w.neutrinoDB,
 		ChainParams:   *w.chainParams,
 		PersistToDisk: true, // keep cfilter headers on disk for efficient rescanning
+		AddPeers:      peerSettings.AddPeers,
+		ConnectPeers:  peerSettings.ConnectPeers,
+		Dialer:        peerSettings.Dialer,
+		NameResolver:  peerSettings.NameResolver,
 		// WARNING: PublishTransaction currently uses the entire duration
 		// because if an external bug, but even if the resolved, a typical
 		// inv/getdata round trip is ~4 seconds, so we set this so neutrino does
@@ -233,7 +248,10 @@ func (w *ltcSPVWallet) Start() (btc.SPVService, error) {
 	case ltcwire.TestNet, ltcwire.SimNet: // plain "wire.TestNet" is regnet!
 		defaultPeers = []string{"127.0.0.1:20585"}
 	}
-	peerManager := btc.NewSPVPeerManager(&spvService{w.cl}, defaultPeers, w.dir, w.log, w.chainParams.DefaultPort)
+	if w.cfg.OnlyTrustedPeers {
+		defaultPeers = nil
+	}
+	peerManager := btc.NewSPVPeerManager(&spvService{w.cl}, defaultPeers, w.dir, w.log, w.chainParams.DefaultPort, peerSettings.NameResolver)
 	w.peerManager = peerManager
 
 	if err = w.chainClient.Start(); err != nil { // lazily starts connmgr
@@ -194,3 +194,20 @@ func (d *Driver) Create(cfg *asset.CreateWalletParams) error {
 	}
 	return eth.CreateEVMWallet(dexpolygon.ChainIDs[cfg.Net], cfg, &compat, false)
 }
+
+var _ asset.EVMChainIdentifier = (*Driver)(nil)
+
+// EVMChainID is the Polygon chain ID on the network. Part of the
+// asset.EVMChainIdentifier interface.
+func (d *Driver) EVMChainID(net dex.Network) (int64, bool) {
+	chainID, found := dexpolygon.ChainIDs[net]
+	return chainID, found
+}
+
+var _ asset.CustomTokenRegistrar = (*Driver)(nil)
+
+// RegisterCustomToken prepares a user-defined Polygon ERC20 token.
+// Part of the asset.CustomTokenRegistrar interface.
+func (d *Driver) RegisterCustomToken(ct *asset.CustomToken, net dex.Network) (uint32, *asset.Token, error) {
+	return eth.RegisterCustomToken(ct, net, dexpolygon.Tokens, "Polygon token")
+}
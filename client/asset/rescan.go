@@ -0,0 +1,95 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package asset
+
+import (
+	"sync"
+	"time"
+)
+
+// rescanNoteInterval is the minimum time between RescanProgressNotes.
+const rescanNoteInterval = time.Second
+
+// RescanTracker emits RescanProgressNotes for a rescan, with an ETA based on
+// the scan rate since the first update. Rescanners use a RescanTracker so that
+// progress is reported the same way for every asset.
+type RescanTracker struct {
+	emit *WalletEmitter
+
+	mtx      sync.Mutex
+	progress RescanProgress
+	// refHeight and refTime are from the first update, since the start
+	// height may be approximate, e.g. before the birthday block is found.
+	refHeight uint64
+	refTime   time.Time
+	lastNote  time.Time
+}
+
+// NewRescanTracker creates a RescanTracker for a rescan from startHeight, and
+// emits the first RescanProgressNote.
+func NewRescanTracker(emit *WalletEmitter, startHeight, targetHeight uint64) *RescanTracker {
+	t := &RescanTracker{
+		emit: emit,
+		progress: RescanProgress{
+			StartHeight:    startHeight,
+			ScannedThrough: startHeight,
+			TargetHeight:   targetHeight,
+		},
+	}
+	t.send(time.Now())
+	return t
+}
+
+// Update records the scanned height and the current target height. A
+// RescanProgressNote is emitted if one hasn't been emitted recently.
+func (t *RescanTracker) Update(scannedThrough, targetHeight uint64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.progress.Done {
+		return
+	}
+	now := time.Now()
+	if t.refTime.IsZero() {
+		t.refHeight, t.refTime = scannedThrough, now
+	}
+	t.progress.ScannedThrough = scannedThrough
+	if targetHeight > 0 {
+		t.progress.TargetHeight = targetHeight
+	}
+	t.progress.ETA = 0
+	if scannedThrough > t.refHeight && t.progress.TargetHeight > scannedThrough {
+		scanned := scannedThrough - t.refHeight
+		remaining := t.progress.TargetHeight - scannedThrough
+		t.progress.ETA = uint64(now.Sub(t.refTime).Seconds() * float64(remaining) / float64(scanned))
+	}
+	if now.Sub(t.lastNote) >= rescanNoteInterval {
+		t.send(now)
+	}
+}
+
+// Done emits the final RescanProgressNote, with the error if the rescan
+// failed.
+func (t *RescanTracker) Done(err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.progress.Done {
+		return
+	}
+	t.progress.Done = true
+	t.progress.ETA = 0
+	if err != nil {
+		t.progress.Err = err.Error()
+	} else if t.progress.TargetHeight > t.progress.ScannedThrough {
+		t.progress.ScannedThrough = t.progress.TargetHeight
+	}
+	t.send(time.Now())
+}
+
+// send emits a copy of the progress. The mutex must be held, except in the
+// constructor.
+func (t *RescanTracker) send(now time.Time) {
+	t.lastNote = now
+	p := t.progress
+	t.emit.RescanProgress(&p)
+}
@@ -19,6 +19,7 @@ const (
 	methodZGetOperationResult   = "z_getoperationresult"
 	methodZGetNotesCount        = "z_getnotescount"
 	methodZListUnspent          = "z_listunspent"
+	methodZListReceivedByAddr   = "z_listreceivedbyaddress"
 )
 
 type zListAccountsResult struct {
@@ -123,6 +124,18 @@ func zValidateAddress(c rpcCaller, addr string) (res *zValidateAddressResult, er
 	return res, c.CallRPC(methodZValidateAddress, []any{addr}, &res)
 }
 
+type zReceivedByAddressResult struct {
+	TxID   string  `json:"txid"`
+	Pool   string  `json:"pool"`
+	Amount float64 `json:"amount"`
+}
+
+// z_listreceivedbyaddress "address" ( minconf )
+func zListReceivedByAddress(c rpcCaller, addr string) (res []*zReceivedByAddressResult, err error) {
+	const minConf = 0
+	return res, c.CallRPC(methodZListReceivedByAddr, []any{addr, minConf}, &res)
+}
+
 type zSendManyRecipient struct {
 	Address string  `json:"address"`
 	Amount  float64 `json:"amount"`
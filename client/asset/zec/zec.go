@@ -66,6 +66,9 @@ const (
 	// previous blocks, only found 1 with a tx with > 6 nActionsOrchard. Most
 	// are 2.
 	nActionsOrchardEstimate = 6
+	// minAutoShieldFeeMultiple is how many times larger than the fees
+	// transparent funds must be for auto-shielding.
+	minAutoShieldFeeMultiple = 10
 
 	blockTicker     = time.Second
 	peerCountTicker = 5 * time.Second
@@ -125,6 +128,14 @@ var (
 				"Used only for standing-type orders, e.g. limit orders without immediate time-in-force.",
 			IsBoolean: true,
 		},
+		{
+			Key:         "autoshield",
+			DisplayName: "Auto-shield transparent funds",
+			Description: "Move confirmed transparent change that is not being used for an order, swap or bond " +
+				"into the Orchard shielded pool after each new block. Shielded funds are moved back to a " +
+				"transparent address when they are needed to fund an order.",
+			IsBoolean: true,
+		},
 	}
 	// WalletInfo defines some general information about a Zcash wallet.
 	WalletInfo = &asset.WalletInfo{
@@ -186,6 +197,7 @@ func (d *Driver) MinLotSize(maxFeeRate uint64) uint64 {
 // WalletConfig are wallet-level configuration settings.
 type WalletConfig struct {
 	UseSplitTx       bool   `ini:"txsplit"`
+	AutoShield       bool   `ini:"autoshield"`
 	RedeemConfTarget uint64 `ini:"redeemconftarget"`
 	ActivelyUsed     bool   `ini:"special_activelyUsed"` // injected by core
 }
@@ -268,10 +280,11 @@ func NewWallet(cfg *asset.WalletConfig, logger dex.Logger, net dex.Network) (ass
 		decodeAddr: func(addr string, net *chaincfg.Params) (btcutil.Address, error) {
 			return dexzec.DecodeAddress(addr, addrParams, btcParams)
 		},
-		ar:         ar,
-		node:       cl,
-		walletDir:  cfg.DataDir,
-		pendingTxs: make(map[chainhash.Hash]*btc.ExtendedWalletTx),
+		ar:          ar,
+		node:        cl,
+		walletDir:   cfg.DataDir,
+		pendingTxs:  make(map[chainhash.Hash]*btc.ExtendedWalletTx),
+		changeAddrs: make(map[string]bool),
 	}
 	zw.walletCfg.Store(&walletCfg)
 	zw.prepareCoinManager()
@@ -331,6 +344,13 @@ type zecWallet struct {
 
 	txHistoryDB      atomic.Value // *btc.BadgerTxDB
 	syncingTxHistory atomic.Bool
+
+	shielding atomic.Bool
+
+	// changeAddrs are the transparent addresses that received change from
+	// transactions built by the wallet. Only change is auto-shielded.
+	changeAddrsMtx sync.Mutex
+	changeAddrs    map[string]bool
 }
 
 var _ asset.FeeRater = (*zecWallet)(nil)
@@ -679,6 +699,10 @@ func (w *zecWallet) reportNewTip(ctx context.Context, newTip *btc.BlockVector) {
 	w.rf.ReportNewTip(ctx, prevTip, newTip)
 
 	w.syncTxHistory(uint64(newTip.Height))
+
+	if w.walletCfg.Load().(*WalletConfig).AutoShield {
+		go w.autoShield(ctx)
+	}
 }
 
 type swapOptions struct {
@@ -1337,6 +1361,13 @@ func (w *zecWallet) signTxAndAddChange(baseTx *dexzec.Tx, addr btcutil.Address,
 	var change *btc.Output
 	if changeAdded {
 		change = btc.NewOutput(&txHash, uint32(changeIdx), uint64(changeOutput.Value))
+		if addrStr, err := dexzec.EncodeAddress(addr, w.addrParams); err != nil {
+			w.log.Errorf("Error encoding change address %s: %v", addr, err)
+		} else {
+			w.changeAddrsMtx.Lock()
+			w.changeAddrs[addrStr] = true
+			w.changeAddrsMtx.Unlock()
+		}
 	}
 
 	return msgTx, change, nil
@@ -1655,6 +1686,69 @@ type depositAddressJSON struct {
 	Sapling     string `json:"sapling,omitempty"`
 }
 
+// unwrapDepositAddress returns the unified address from an address encoded by
+// DepositAddress. Other addresses are returned unchanged.
+func unwrapDepositAddress(addrStr string) (string, error) {
+	if !strings.HasPrefix(addrStr, depositAddrPrefix) {
+		return addrStr, nil
+	}
+	var addrs depositAddressJSON
+	if err := json.Unmarshal([]byte(addrStr[len(depositAddrPrefix):]), &addrs); err != nil {
+		return "", fmt.Errorf("error decoding unified address info: %w", err)
+	}
+	return addrs.Unified, nil
+}
+
+// preferredReceiverType is the type of the unified address receiver that
+// zcashd will pay. zcashd pays the most private receiver, so Orchard is
+// preferred over Sapling, and any shielded receiver over a transparent one.
+func preferredReceiverType(r *unifiedReceivers) string {
+	switch {
+	case r.Orchard != "":
+		return orchardAddressType
+	case r.Sapling != "":
+		return saplingAddressType
+	default:
+		return transparentAddressType
+	}
+}
+
+// zecAddress is a validated send destination.
+type zecAddress struct {
+	addr string
+	// receiverType is the type of address that will be paid. For unified
+	// addresses, this is the type of the preferred receiver.
+	receiverType string
+}
+
+// parseAddress validates the address, which may be a unified address encoded
+// by DepositAddress, and determines the receiver type.
+func (w *zecWallet) parseAddress(addrStr string) (*zecAddress, error) {
+	addrStr, err := unwrapDepositAddress(addrStr)
+	if err != nil {
+		return nil, err
+	}
+	res, err := zValidateAddress(w, addrStr)
+	if err != nil {
+		return nil, fmt.Errorf("error validating address: %w", err)
+	}
+	if !res.IsValid {
+		return nil, fmt.Errorf("invalid address %q", addrStr)
+	}
+	addr := &zecAddress{
+		addr:         addrStr,
+		receiverType: res.AddressType,
+	}
+	if res.AddressType == unifiedAddressType {
+		r, err := zGetUnifiedReceivers(w, addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("error getting unified receivers: %w", err)
+		}
+		addr.receiverType = preferredReceiverType(r)
+	}
+	return addr, nil
+}
+
 func (w *zecWallet) DepositAddress() (string, error) {
 	addrRes, err := zGetAddressForAccount(w, shieldedAcctNumber, []string{transparentAddressType, orchardAddressType})
 	if err != nil {
@@ -1681,11 +1775,47 @@ func (w *zecWallet) NewAddress() (string, error) {
 	return w.DepositAddress()
 }
 
-// AddressUsed checks if a wallet address has been used.
+// AddressUsed checks if a wallet address has been used. Unified addresses,
+// including those encoded by DepositAddress, are used if any of their
+// receivers have received funds.
 func (w *zecWallet) AddressUsed(addrStr string) (bool, error) {
-	// TODO: Resolve with new unified address encoding in https://github.com/decred/dcrdex/pull/2675
-	recv, err := getReceivedByAddress(w, addrStr)
-	return recv != 0, err
+	addrStr, err := unwrapDepositAddress(addrStr)
+	if err != nil {
+		return false, err
+	}
+	res, err := zValidateAddress(w, addrStr)
+	if err != nil {
+		return false, fmt.Errorf("error validating address: %w", err)
+	}
+	if !res.IsValid {
+		return false, fmt.Errorf("invalid address %q", addrStr)
+	}
+	tAddr := addrStr
+	switch res.AddressType {
+	case transparentAddressType:
+	case unifiedAddressType:
+		r, err := zGetUnifiedReceivers(w, addrStr)
+		if err != nil {
+			return false, fmt.Errorf("error getting unified receivers: %w", err)
+		}
+		tAddr = r.Transparent
+	default:
+		tAddr = ""
+	}
+	if tAddr != "" {
+		recv, err := getReceivedByAddress(w, tAddr)
+		if err != nil || recv != 0 {
+			return recv != 0, err
+		}
+		if res.AddressType == transparentAddressType {
+			return false, nil
+		}
+	}
+	received, err := zListReceivedByAddress(w, addrStr)
+	if err != nil {
+		return false, fmt.Errorf("error listing shielded receipts: %w", err)
+	}
+	return len(received) > 0, nil
 }
 
 func (w *zecWallet) FindRedemption(ctx context.Context, coinID, contract dex.Bytes) (redemptionCoin, secret dex.Bytes, err error) {
@@ -1889,12 +2019,9 @@ func (w *zecWallet) MaxFundingFees(numTrades uint32, feeRate uint64, settings ma
 }
 
 func (w *zecWallet) OwnsDepositAddress(addrStr string) (bool, error) {
-	if strings.HasPrefix(addrStr, depositAddrPrefix) {
-		var addrs depositAddressJSON
-		if err := json.Unmarshal([]byte(addrStr[len(depositAddrPrefix):]), &addrs); err != nil {
-			return false, fmt.Errorf("error decoding unified address info: %w", err)
-		}
-		addrStr = addrs.Unified
+	addrStr, err := unwrapDepositAddress(addrStr)
+	if err != nil {
+		return false, err
 	}
 	res, err := zValidateAddress(w, addrStr)
 	if err != nil {
@@ -2108,13 +2235,13 @@ func (w *zecWallet) EstimateSendTxFee(
 	fees uint64, isValidAddress bool, err error,
 ) {
 
-	res, err := zValidateAddress(w, addrStr)
-	isValidAddress = err == nil && res.IsValid
+	addr, err := w.parseAddress(addrStr)
+	isValidAddress = err == nil
 
 	if maxWithdraw {
 		addrType := transparentAddressType
 		if isValidAddress {
-			addrType = res.AddressType
+			addrType = addr.receiverType
 		}
 		fees, err = w.maxWithdrawFees(addrType)
 		return
@@ -2161,7 +2288,7 @@ func (w *zecWallet) maxWithdrawFees(addrType string) (uint64, error) {
 	nActionsOrchard := uint64(noteCounts.Orchard)
 	var txOutsSize, nOutputsSapling uint64
 	switch addrType {
-	case unifiedAddressType, orchardAddressType:
+	case orchardAddressType:
 		nActionsOrchard++
 	case saplingAddressType:
 		nOutputsSapling = 1
@@ -2561,13 +2688,14 @@ func (w *zecWallet) SyncStatus() (*asset.SyncStatus, error) {
 	return ss, nil
 }
 
+// ValidateAddress checks that the address is valid. Unified addresses encoded
+// by DepositAddress are accepted.
 func (w *zecWallet) ValidateAddress(addr string) bool {
-	res, err := zValidateAddress(w, addr)
-	if err != nil {
-		w.log.Errorf("z_validateaddress error for address %q: %w", addr, err)
+	if _, err := w.parseAddress(addr); err != nil {
+		w.log.Debugf("Invalid address %q: %v", addr, err)
 		return false
 	}
-	return res.IsValid
+	return true
 }
 
 func (w *zecWallet) ValidateSecret(secret, secretHash []byte) bool {
@@ -2657,6 +2785,14 @@ func (w *zecWallet) Balance() (*asset.Balance, error) {
 	bal.Other[asset.BalanceCategoryShielded] = asset.CustomBalance{
 		Amount: bals.orchard.avail, // + bals.orchard.maturing,
 	}
+	// Sapling funds can be sent, but can't fund orders.
+	if bals.sapling > 0 {
+		bal.Other[asset.BalanceCategorySapling] = asset.CustomBalance{
+			Amount: bals.sapling,
+			Locked: true,
+		}
+		bal.Locked += bals.sapling
+	}
 
 	reserves := w.reserves.Load()
 	if reserves > bal.Available {
@@ -2772,13 +2908,9 @@ func (w *zecWallet) sendOneShielded(ctx context.Context, toAddr string, amt uint
 }
 
 func (w *zecWallet) sendShielded(ctx context.Context, toAddr string, amt uint64) (*chainhash.Hash, error) {
-	res, err := zValidateAddress(w, toAddr)
+	addr, err := w.parseAddress(toAddr)
 	if err != nil {
-		return nil, fmt.Errorf("error validating address: %w", err)
-	}
-
-	if !res.IsValid {
-		return nil, fmt.Errorf("invalid address %q", toAddr)
+		return nil, err
 	}
 
 	// TODO: We're using NoPrivacy for everything except orchard-to-orchard
@@ -2788,21 +2920,15 @@ func (w *zecWallet) sendShielded(ctx context.Context, toAddr string, amt uint64)
 	// potential for change outputs. In the end, it changes nothing, and zcashd
 	// will optimize privacy the best it can.
 	priv := NoPrivacy
-	if res.AddressType == unifiedAddressType {
-		r, err := zGetUnifiedReceivers(w, toAddr)
-		if err != nil {
-			return nil, fmt.Errorf("error getting unified receivers: %w", err)
-		}
-		if r.Orchard != "" {
-			if _, _, _, isFunded, err := w.fundOrchard(amt); err != nil {
-				return nil, fmt.Errorf("error checking orchard funding: %w", err)
-			} else if isFunded {
-				priv = FullPrivacy
-			}
+	if addr.receiverType == orchardAddressType {
+		if _, _, _, isFunded, err := w.fundOrchard(amt); err != nil {
+			return nil, fmt.Errorf("error checking orchard funding: %w", err)
+		} else if isFunded {
+			priv = FullPrivacy
 		}
 	}
 
-	txHash, err := w.sendOneShielded(ctx, toAddr, amt, priv)
+	txHash, err := w.sendOneShielded(ctx, addr.addr, amt, priv)
 	if err != nil {
 		return nil, err
 	}
@@ -2810,6 +2936,138 @@ func (w *zecWallet) sendShielded(ctx context.Context, toAddr string, amt uint64)
 	return txHash, nil
 }
 
+// autoShield moves confirmed change into the Orchard pool. zcashd picks the
+// inputs of a z_sendmany itself, so each shielding transaction is sent from a
+// single change address rather than from any transparent address, and only
+// from addresses that hold no locked outputs, i.e. outputs funding orders or
+// bonds. Change addresses are only known for transactions built since the
+// wallet was started. Small amounts aren't shielded, since the fees would be a
+// large fraction of the value.
+func (w *zecWallet) autoShield(ctx context.Context) {
+	if !w.shielding.CompareAndSwap(false, true) {
+		return // still shielding from the last block
+	}
+	defer w.shielding.Store(false)
+
+	w.changeAddrsMtx.Lock()
+	changeAddrs := make(map[string]bool, len(w.changeAddrs))
+	for addr := range w.changeAddrs {
+		changeAddrs[addr] = true
+	}
+	w.changeAddrsMtx.Unlock()
+	if len(changeAddrs) == 0 {
+		return
+	}
+
+	utxos, _, _, err := w.cm.SpendableUTXOs(1)
+	if err != nil {
+		w.log.Errorf("Error listing unspent outputs for auto-shielding: %v", err)
+		return
+	}
+	addrUTXOs := make(map[string][]*btc.CompositeUTXO)
+	for _, utxo := range utxos {
+		if changeAddrs[utxo.Address] {
+			addrUTXOs[utxo.Address] = append(addrUTXOs[utxo.Address], utxo)
+		}
+	}
+	if len(addrUTXOs) == 0 {
+		return
+	}
+	lockedAddrs, err := w.lockedAddresses()
+	if err != nil {
+		w.log.Errorf("Error listing locked outputs for auto-shielding: %v", err)
+		return
+	}
+	for addr, utxos := range addrUTXOs {
+		if lockedAddrs[addr] {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		w.shieldChange(ctx, addr, utxos)
+	}
+}
+
+// lockedAddresses are the addresses of the wallet's locked unspent outputs.
+func (w *zecWallet) lockedAddresses() (map[string]bool, error) {
+	var locked []*btc.RPCOutpoint
+	if err := w.CallRPC("listlockunspent", nil, &locked); err != nil {
+		return nil, err
+	}
+	addrs := make(map[string]bool, len(locked))
+	for _, pt := range locked {
+		var txOut *btcjson.GetTxOutResult
+		if err := w.CallRPC("gettxout", []any{pt.TxID, pt.Vout, true}, &txOut); err != nil {
+			return nil, fmt.Errorf("gettxout(%v:%d): %w", pt.TxID, pt.Vout, err)
+		}
+		if txOut == nil {
+			continue // spent
+		}
+		if txOut.ScriptPubKey.Address != "" {
+			addrs[txOut.ScriptPubKey.Address] = true
+		}
+		for _, addr := range txOut.ScriptPubKey.Addresses {
+			addrs[addr] = true
+		}
+	}
+	return addrs, nil
+}
+
+// shieldChange sends the confirmed funds of a change address to the Orchard
+// pool. The outputs are locked in the coin manager while shielding, so that
+// they aren't used to fund an order at the same time.
+func (w *zecWallet) shieldChange(ctx context.Context, fromAddr string, utxos []*btc.CompositeUTXO) {
+	var sum uint64
+	lockUTXOs := make([]*btc.UTxO, len(utxos))
+	pts := make([]btc.OutPoint, len(utxos))
+	for i, utxo := range utxos {
+		sum += utxo.Amount
+		lockUTXOs[i] = utxo.UTxO
+		pts[i] = btc.NewOutPoint(utxo.TxHash, utxo.Vout)
+	}
+	n := uint64(len(utxos))
+	txInsSize := n*dexbtc.RedeemP2PKHInputSize + uint64(wire.VarIntSerializeSize(n))
+	const orchardOutputs = 1
+	fees := dexzec.TxFeesZIP317(txInsSize, 1, 0, 0, 0, orchardOutputs)
+	if sum < fees*minAutoShieldFeeMultiple {
+		return
+	}
+
+	toAddr, err := w.lastShieldedAddress()
+	if err != nil {
+		w.log.Errorf("Error getting shielded address for auto-shielding: %v", err)
+		return
+	}
+
+	w.cm.LockUTXOs(lockUTXOs)
+	defer w.cm.UnlockOutPoints(pts)
+
+	amt := sum - fees
+	operationID, err := zSendMany(w, fromAddr, singleSendManyRecipient(toAddr, amt), AllowRevealedSenders)
+	if err != nil {
+		w.log.Errorf("Error auto-shielding %s from %s: %v", btcutil.Amount(amt), fromAddr, err)
+		return
+	}
+	txHash, err := w.awaitSendManyOperation(ctx, w, operationID)
+	if err != nil {
+		w.log.Errorf("Error auto-shielding %s from %s: %v", btcutil.Amount(amt), fromAddr, err)
+		return
+	}
+	w.log.Infof("Shielded %s of change from %d outputs in transaction %s", btcutil.Amount(amt), n, txHash)
+
+	w.changeAddrsMtx.Lock()
+	delete(w.changeAddrs, fromAddr)
+	w.changeAddrsMtx.Unlock()
+
+	w.addTxToHistory(&asset.WalletTransaction{
+		Type:   asset.SelfSend,
+		ID:     txHash.String(),
+		Amount: amt,
+		Fees:   fees,
+	}, txHash, true)
+}
+
 func zecTx(tx *wire.MsgTx) *dexzec.Tx {
 	return dexzec.NewTxFromMsgTx(tx, dexzec.MaxExpiryHeight)
 }
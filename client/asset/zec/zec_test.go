@@ -1141,6 +1141,61 @@ func TestSend(t *testing.T) {
 	}
 }
 
+func TestAddressUsed(t *testing.T) {
+	w, cl, shutdown := tNewWallet()
+	defer shutdown()
+	defer cl.checkEmptiness(t)
+
+	depositAddr := depositAddrPrefix + `{"unified":"` + tUnifiedAddr + `","transparent":"` + tAddr + `"}`
+
+	// Transparent receiver used.
+	cl.queueResponse(methodZValidateAddress, &zValidateAddressResult{IsValid: true, AddressType: unifiedAddressType})
+	cl.queueResponse(methodZListUnifiedReceivers, &unifiedReceivers{Transparent: tAddr})
+	cl.queueResponse("getreceivedbyaddress", 1e8)
+	if used, err := w.AddressUsed(depositAddr); err != nil || !used {
+		t.Fatalf("expected used, got used = %t, err = %v", used, err)
+	}
+
+	// Only the shielded receivers are checked after the transparent receiver.
+	cl.queueResponse(methodZValidateAddress, &zValidateAddressResult{IsValid: true, AddressType: unifiedAddressType})
+	cl.queueResponse(methodZListUnifiedReceivers, &unifiedReceivers{Transparent: tAddr})
+	cl.queueResponse("getreceivedbyaddress", 0)
+	cl.queueResponse(methodZListReceivedByAddr, []*zReceivedByAddressResult{{TxID: tTxID, Pool: "orchard", Amount: 1}})
+	if used, err := w.AddressUsed(depositAddr); err != nil || !used {
+		t.Fatalf("expected used, got used = %t, err = %v", used, err)
+	}
+
+	cl.queueResponse(methodZValidateAddress, &zValidateAddressResult{IsValid: true, AddressType: unifiedAddressType})
+	cl.queueResponse(methodZListUnifiedReceivers, &unifiedReceivers{Transparent: tAddr})
+	cl.queueResponse("getreceivedbyaddress", 0)
+	cl.queueResponse(methodZListReceivedByAddr, []*zReceivedByAddressResult{})
+	if used, err := w.AddressUsed(tUnifiedAddr); err != nil || used {
+		t.Fatalf("expected unused, got used = %t, err = %v", used, err)
+	}
+
+	// Transparent address.
+	cl.queueResponse(methodZValidateAddress, &zValidateAddressResult{IsValid: true, AddressType: transparentAddressType})
+	cl.queueResponse("getreceivedbyaddress", 0)
+	if used, err := w.AddressUsed(tAddr); err != nil || used {
+		t.Fatalf("expected unused, got used = %t, err = %v", used, err)
+	}
+}
+
+func TestPreferredReceiverType(t *testing.T) {
+	for _, tt := range []struct {
+		r    *unifiedReceivers
+		want string
+	}{
+		{&unifiedReceivers{Transparent: "t", Orchard: "o", Sapling: "s"}, orchardAddressType},
+		{&unifiedReceivers{Transparent: "t", Sapling: "s"}, saplingAddressType},
+		{&unifiedReceivers{Transparent: "t"}, transparentAddressType},
+	} {
+		if got := preferredReceiverType(tt.r); got != tt.want {
+			t.Fatalf("wanted %s, got %s", tt.want, got)
+		}
+	}
+}
+
 func TestSwapConfirmations(t *testing.T) {
 	w, cl, shutdown := tNewWallet()
 	defer shutdown()
@@ -1528,3 +1583,91 @@ func TestFundMultiOrder(t *testing.T) {
 		t.Fatalf("error for simple path: %v", err)
 	}
 }
+
+func TestAutoShield(t *testing.T) {
+	w, cl, shutdown := tNewWallet()
+	defer shutdown()
+	defer cl.checkEmptiness(t)
+
+	newAddr := func() string {
+		pkh, _ := btcutil.NewAddressPubKeyHash(encode.RandomBytes(20), w.btcParams)
+		addr, _ := dexzec.EncodeAddress(pkh, w.addrParams)
+		return addr
+	}
+	changeAddr, lockedChangeAddr, otherAddr := newAddr(), newAddr(), newAddr()
+
+	// Change added by the wallet is recorded.
+	changeBtcAddr, _ := dexzec.DecodeAddress(changeAddr, w.addrParams, w.btcParams)
+	baseTx := dexzec.NewTxFromMsgTx(wire.NewMsgTx(dexzec.VersionNU5), dexzec.MaxExpiryHeight)
+	baseTx.AddTxIn(dummyInput())
+	baseTx.AddTxOut(wire.NewTxOut(1e6, tP2PKH))
+	if _, change, err := w.signTxAndAddChange(baseTx, changeBtcAddr, 1e8, 1e6, 1e4); err != nil || change == nil {
+		t.Fatalf("signTxAndAddChange: change = %v, err = %v", change, err)
+	}
+	w.changeAddrs[lockedChangeAddr] = true
+	if !w.changeAddrs[changeAddr] {
+		t.Fatalf("change address not recorded")
+	}
+
+	const changeVal, lockedVal, otherVal = 1e7, 2e7, 3e7
+	unspent := func(addr string, vout uint32, val uint64) *btc.ListUnspentResult {
+		return &btc.ListUnspentResult{
+			TxID:          tTxID,
+			Address:       addr,
+			Amount:        float64(val) / 1e8,
+			Confirmations: 1,
+			Vout:          vout,
+			ScriptPubKey:  tP2PKH,
+			Spendable:     true,
+			Solvable:      true,
+			SafePtr:       boolPtr(true),
+		}
+	}
+	cl.queueResponse("listunspent", []*btc.ListUnspentResult{
+		unspent(changeAddr, 0, changeVal),
+		unspent(lockedChangeAddr, 1, lockedVal),
+		unspent(otherAddr, 2, otherVal),
+	})
+	// The other change address also has an output funding an order.
+	cl.queueResponse("listlockunspent", []*btc.RPCOutpoint{{TxID: tTxID, Vout: 3}})
+	txOut := &btcjson.GetTxOutResult{}
+	txOut.ScriptPubKey.Addresses = []string{lockedChangeAddr}
+	cl.queueResponse("gettxout", txOut)
+
+	w.lastAddress.Store(tUnifiedAddr)
+	var fromAddr string
+	var recips []*zSendManyRecipient
+	cl.queueResponse(methodZSendMany, func(args []json.RawMessage) (json.RawMessage, error) {
+		if err := json.Unmarshal(args[0], &fromAddr); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(args[1], &recips); err != nil {
+			return nil, err
+		}
+		return json.Marshal("opid-123456")
+	})
+	cl.queueResponse(methodZGetOperationResult, []*operationStatus{{
+		Status: "success",
+		Result: &opResult{TxID: tTxID},
+	}})
+
+	w.autoShield(tCtx)
+
+	if fromAddr != changeAddr {
+		t.Fatalf("shielded from %q, wanted the change address %q", fromAddr, changeAddr)
+	}
+	txInsSize := dexbtc.RedeemP2PKHInputSize + uint64(wire.VarIntSerializeSize(1))
+	fees := dexzec.TxFeesZIP317(txInsSize, 1, 0, 0, 0, 1)
+	if len(recips) != 1 || recips[0].Address != tUnifiedAddr || toZats(recips[0].Amount) != changeVal-fees {
+		t.Fatalf("wrong recipients %+v", recips)
+	}
+	if w.changeAddrs[changeAddr] {
+		t.Fatalf("shielded change address not removed")
+	}
+	if !w.changeAddrs[lockedChangeAddr] {
+		t.Fatalf("change address with locked outputs removed")
+	}
+	if w.cm.LockedOutput(btc.NewOutPoint(tTxHash, 0)) != nil {
+		t.Fatalf("shielded output still reserved")
+	}
+}
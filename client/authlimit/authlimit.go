@@ -0,0 +1,146 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package authlimit limits password guessing against the client's web and RPC
+// servers. Failed attempts are tracked by source IP. After a few failures,
+// each further attempt must wait for a delay that doubles with every failure,
+// and after too many failures the source is locked out for a while. All
+// attempts are logged with the source IP.
+package authlimit
+
+import (
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+const (
+	// FreeAttempts is the number of failed attempts permitted before delays
+	// are imposed.
+	FreeAttempts = 3
+	// BaseDelay is the delay imposed after the first failure beyond
+	// FreeAttempts. It doubles with each additional failure, up to MaxDelay.
+	BaseDelay = time.Second
+	// MaxDelay is the longest delay imposed before a lockout.
+	MaxDelay = 30 * time.Second
+	// LockoutAttempts is the number of failed attempts that triggers a
+	// lockout. Failures after a lockout trigger another lockout until the
+	// failures are forgotten.
+	LockoutAttempts = 10
+	// LockoutDuration is how long a source is locked out.
+	LockoutDuration = 15 * time.Minute
+	// ForgetAfter is how long after its last failure a source's failures are
+	// forgotten.
+	ForgetAfter = time.Hour
+)
+
+type record struct {
+	failures    int
+	lastFailure time.Time
+	// blockedUntil is the earliest time of the next allowed attempt.
+	blockedUntil time.Time
+}
+
+// Limiter tracks failed authentication attempts by source IP.
+type Limiter struct {
+	log  dex.Logger
+	now  func() time.Time
+	mtx  sync.Mutex
+	recs map[dex.IPKey]*record
+}
+
+// New is the constructor for a Limiter. The logger is used for the audit log
+// of authentication attempts.
+func New(log dex.Logger) *Limiter {
+	return &Limiter{
+		log:  log,
+		now:  time.Now,
+		recs: make(map[dex.IPKey]*record),
+	}
+}
+
+// delay is the delay imposed after the nth failure.
+func delay(n int) time.Duration {
+	switch {
+	case n >= LockoutAttempts:
+		return LockoutDuration
+	case n <= FreeAttempts:
+		return 0
+	}
+	d := BaseDelay << (n - FreeAttempts - 1)
+	if d > MaxDelay {
+		return MaxDelay
+	}
+	return d
+}
+
+// Allow checks whether an authentication attempt from the remote address is
+// allowed now. If not, the time until it will be is returned, and the rejected
+// attempt is logged.
+func (l *Limiter) Allow(remoteAddr string) (bool, time.Duration) {
+	ip := dex.NewIPKey(remoteAddr)
+	now := l.now()
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	rec := l.recs[ip]
+	if rec == nil {
+		return true, 0
+	}
+	if wait := rec.blockedUntil.Sub(now); wait > 0 {
+		l.log.Warnf("Rejected authentication attempt from %s, which has %d recent failures. Retry in %v.",
+			ip, rec.failures, wait.Round(time.Second))
+		return false, wait
+	}
+	return true, 0
+}
+
+// Failure records a failed authentication attempt from the remote address.
+func (l *Limiter) Failure(remoteAddr string) {
+	ip := dex.NewIPKey(remoteAddr)
+	now := l.now()
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.prune(now)
+	rec := l.recs[ip]
+	if rec == nil {
+		rec = new(record)
+		l.recs[ip] = rec
+	}
+	rec.failures++
+	rec.lastFailure = now
+	d := delay(rec.failures)
+	rec.blockedUntil = now.Add(d)
+	switch {
+	case rec.failures >= LockoutAttempts:
+		l.log.Errorf("Failed authentication attempt %d from %s. Locked out for %v.", rec.failures, ip, d)
+	case d > 0:
+		l.log.Warnf("Failed authentication attempt %d from %s. Next attempt allowed in %v.", rec.failures, ip, d)
+	default:
+		l.log.Warnf("Failed authentication attempt %d from %s.", rec.failures, ip)
+	}
+}
+
+// Success records a successful authentication from the remote address,
+// forgetting its failures.
+func (l *Limiter) Success(remoteAddr string) {
+	ip := dex.NewIPKey(remoteAddr)
+	l.mtx.Lock()
+	rec := l.recs[ip]
+	delete(l.recs, ip)
+	l.mtx.Unlock()
+	if rec != nil {
+		l.log.Infof("Successful authentication from %s after %d failed attempts.", ip, rec.failures)
+		return
+	}
+	l.log.Debugf("Successful authentication from %s.", ip)
+}
+
+// prune forgets sources with no recent failures. The mtx must be locked.
+func (l *Limiter) prune(now time.Time) {
+	for ip, rec := range l.recs {
+		if now.Sub(rec.lastFailure) >= ForgetAfter && !now.Before(rec.blockedUntil) {
+			delete(l.recs, ip)
+		}
+	}
+}
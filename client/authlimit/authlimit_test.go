@@ -0,0 +1,71 @@
+package authlimit
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+func TestLimiter(t *testing.T) {
+	l := New(dex.StdOutLogger("TEST", dex.LevelTrace))
+	now := time.Unix(1700000000, 0)
+	l.now = func() time.Time { return now }
+
+	const addr, otherAddr = "192.0.2.1:5758", "192.0.2.2:5758"
+
+	checkAllowed := func(addr string, expAllowed bool, expWait time.Duration) {
+		t.Helper()
+		allowed, wait := l.Allow(addr)
+		if allowed != expAllowed || wait != expWait {
+			t.Fatalf("expected allowed = %t, wait = %v, got %t, %v", expAllowed, expWait, allowed, wait)
+		}
+	}
+
+	// Free attempts.
+	for i := 0; i < FreeAttempts; i++ {
+		checkAllowed(addr, true, 0)
+		l.Failure(addr)
+	}
+	checkAllowed(addr, true, 0)
+
+	// Progressive delays.
+	expDelay := BaseDelay
+	for i := FreeAttempts + 1; i < LockoutAttempts; i++ {
+		l.Failure(addr)
+		checkAllowed(addr, false, expDelay)
+		// Other sources and other ports of the same source.
+		checkAllowed(otherAddr, true, 0)
+		checkAllowed("192.0.2.1:1", false, expDelay)
+		now = now.Add(expDelay)
+		checkAllowed(addr, true, 0)
+		if expDelay *= 2; expDelay > MaxDelay {
+			expDelay = MaxDelay
+		}
+	}
+
+	// Lockout.
+	l.Failure(addr)
+	checkAllowed(addr, false, LockoutDuration)
+	now = now.Add(LockoutDuration)
+	checkAllowed(addr, true, 0)
+	l.Failure(addr)
+	checkAllowed(addr, false, LockoutDuration)
+
+	// Success forgets the failures.
+	now = now.Add(LockoutDuration)
+	l.Success(addr)
+	l.Failure(addr)
+	checkAllowed(addr, true, 0)
+
+	// Failures are forgotten after a while.
+	for i := 1; i < FreeAttempts; i++ {
+		l.Failure(addr)
+	}
+	now = now.Add(ForgetAfter)
+	l.Failure(addr)
+	checkAllowed(addr, true, 0)
+	if n := l.recs[dex.NewIPKey(addr)].failures; n != 1 {
+		t.Fatalf("expected 1 failure after forgetting, got %d", n)
+	}
+}
@@ -115,6 +115,12 @@ func mainCore() error {
 			time.Now().Local().Format("15:04:05 MST"))
 	}
 
+	if cfg.DeepLink != "" {
+		if _, err := webserver.ParseDeepLink(cfg.DeepLink); err != nil {
+			return err
+		}
+	}
+
 	syncDir := filepath.Join(cfg.AppData, cfg.Net.String())
 
 	// The --kill flag is a backup measure to end a background process (that
@@ -124,7 +130,7 @@ func mainCore() error {
 		return nil
 	}
 
-	startServer, err := synchronize(syncDir)
+	startServer, err := synchronize(syncDir, cfg.DeepLink)
 	if err != nil || !startServer {
 		// If we didn't start the server but there is no error, and it means
 		// that we've successfully sent an open window request to an already
@@ -242,22 +248,28 @@ func mainCore() error {
 
 	// No errors running webserver, so we can be certain we won any race between
 	// starting instances. Start the sync server now.
-	openC := make(chan struct{}) // no buffer. Is ignored if window is currently open
+	openC := make(chan string) // no buffer. Is ignored if window is currently open
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 		runServer(appCtx, syncDir, openC, killChan, cfg.Net)
 	}()
 
-	openWindow := func() {
+	// openWindow opens a window, showing the page for the deep link if one is
+	// given.
+	openWindow := func(link string) {
+		url := "http://" + webSrv.Addr()
+		if link != "" {
+			url += webserver.DeepLinkPath(link)
+		}
 		wg.Add(1)
 		go func() {
-			runWebviewSubprocess(appCtx, "http://"+webSrv.Addr())
+			runWebviewSubprocess(appCtx, url)
 			wg.Done()
 		}()
 	}
 
-	openWindow()
+	openWindow(cfg.DeepLink)
 
 	wg.Add(1)
 	go func() {
@@ -298,9 +310,9 @@ func mainCore() error {
 					case <-time.After(time.Minute):
 						// Try to log out again.
 						continue logout
-					case <-openC:
+					case link := <-openC:
 						// re-open the window
-						openWindow()
+						openWindow(link)
 						continue windowloop
 					case <-appCtx.Done():
 						break windowloop
@@ -308,8 +320,8 @@ func mainCore() error {
 				}
 			case <-appCtx.Done():
 				break windowloop
-			case <-openC:
-				openWindow()
+			case link := <-openC:
+				openWindow(link)
 			}
 		}
 	}()
@@ -458,7 +470,7 @@ func runWebviewSubprocess(ctx context.Context, url string) {
 	}
 }
 
-func systrayOnReady(ctx context.Context, logDirectory string, openC chan<- struct{},
+func systrayOnReady(ctx context.Context, logDirectory string, openC chan<- string,
 	killC chan<- os.Signal, activeState <-chan bool) {
 	systray.SetIcon(FavIcon)
 	systray.SetTitle("Bison Wallet")
@@ -486,7 +498,7 @@ func systrayOnReady(ctx context.Context, logDirectory string, openC chan<- struc
 	go func() {
 		for range mOpen.ClickedCh {
 			select {
-			case openC <- struct{}{}:
+			case openC <- "":
 				log.Debug("Received window reopen request from system tray")
 			default:
 				log.Infof("Ignored a window open request from the system tray")
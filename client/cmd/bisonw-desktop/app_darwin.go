@@ -114,6 +114,12 @@ var (
 
 	// Initialized when bisonw-desktop has been started.
 	appURL *url.URL
+	// startLink is the deep link to open in the first window. It is set by
+	// the --deeplink flag, or by a dex:// link that launched bisonw-desktop.
+	// finishedLaunching is set when the first window is created. Both are
+	// only accessed on the main thread after the app starts.
+	startLink         string
+	finishedLaunching bool
 
 	// completionHandler handles Objective-C callback functions for some
 	// delegate methods.
@@ -184,6 +190,13 @@ func mainCore() error {
 		return errors.New(`--kill flag is not supported. Use the "Quit" or "Force Quit" button to kill any running process.`)
 	}
 
+	if cfg.DeepLink != "" {
+		if _, err := webserver.ParseDeepLink(cfg.DeepLink); err != nil {
+			return err
+		}
+		startLink = cfg.DeepLink
+	}
+
 	// Filter registered assets.
 	asset.SetNetwork(cfg.Net)
 
@@ -417,17 +430,22 @@ func hasOpenWindows() bool {
 	return nOpenWindows.Load() > 0
 }
 
-// createNewWebView creates a new webview with the specified URL. The actual
-// window will be created when the webview is loaded (i.e the
-// "webView:didFinishNavigation:" method have been executed).
-func createNewWebView() {
+// createNewWebView creates a new webview showing the page for the deep link,
+// or the default page if link is empty. The actual window will be created when
+// the webview is loaded (i.e the "webView:didFinishNavigation:" method have
+// been executed).
+func createNewWebView(link string) {
 	if int(nOpenWindows.Load()) >= maxOpenWindows {
 		log.Debugf("Ignoring open new window request, max number of (%d) open windows exceeded", maxOpenWindows)
 		return
 	}
 
 	// Create a new webview and loads the appURL.
-	req := mdCore.NSURLRequest_Init(mdCore.URL(appURL.String()))
+	u := appURL.String()
+	if link != "" {
+		u += webserver.DeepLinkPath(link)
+	}
+	req := mdCore.NSURLRequest_Init(mdCore.URL(u))
 	webView := webkit.WKWebView_Init(mdCore.Rect(0, 0, float64(width), float64(height)), webviewConfig)
 	webView.Object.Class().AddMethod(selIsNewWebview, func(_ objc.Object) objc.Object { return mdCore.True })
 	webView.LoadRequest(req)
@@ -467,6 +485,10 @@ func initCocoaDefaultDelegateClassWrapper(logDir string) *cocoaDefaultDelegateCl
 	// "applicationDockMenu:" method returns the app's dock menu. See:
 	// https://developer.apple.com/documentation/appkit/nsapplicationdelegate/1428564-applicationdockmenu?language=objc
 	ad.AddMethod("applicationDockMenu:", ad.handleApplicationDockMenu)
+	// MacOS will execute this method when a dex:// link is opened, launching
+	// bisonw-desktop first if it is not running. See:
+	// https://developer.apple.com/documentation/appkit/nsapplicationdelegate/2887193-application?language=objc
+	ad.AddMethod("application:openURLs:", ad.handleApplicationOpenURLs)
 	// WebView will execute this method when the page has loaded. We can then
 	// create a new window to avoid a temporary blank window. See:
 	// https://developer.apple.com/documentation/webkit/wknavigationdelegate/1455629-webview?language=objc
@@ -501,7 +523,7 @@ func initCocoaDefaultDelegateClassWrapper(logDir string) *cocoaDefaultDelegateCl
 		windows := cocoa.NSApp().OrderedWindows()
 		len := windows.Count()
 		if len < uint64(maxOpenWindows) {
-			createNewWebView()
+			createNewWebView("")
 		} else {
 			// Show the last window if maxOpenWindows has been exceeded.
 			winObj := windows.ObjectAtIndex(len - 1)
@@ -550,14 +572,15 @@ func (ad *cocoaDefaultDelegateClassWrapper) handleApplicationDidFinishLaunching(
 		cocoa.NSApp().TryToPerform_with_(objc.Sel("unhide:"), nil)
 	})
 
-	createNewWebView()
+	finishedLaunching = true
+	createNewWebView(startLink)
 }
 
 func (ad *cocoaDefaultDelegateClassWrapper) handleApplicationShouldHandleReopenHasVisibleWindows(_ objc.Object) bool {
 	if !hasOpenWindows() {
 		// bisonw-desktop is already running but there are no windows open so
 		// we should create a new window.
-		createNewWebView()
+		createNewWebView("")
 		return false
 	}
 
@@ -567,6 +590,24 @@ func (ad *cocoaDefaultDelegateClassWrapper) handleApplicationShouldHandleReopenH
 	return true
 }
 
+// handleApplicationOpenURLs opens a window for each dex:// link. A link that
+// launched bisonw-desktop is opened in the first window.
+func (ad *cocoaDefaultDelegateClassWrapper) handleApplicationOpenURLs(_ objc.Object /* delegate */, _ objc.Object /* app */, urls objc.Object) {
+	links := mdCore.NSArray_fromRef(urls)
+	for i := uint64(0); i < links.Count(); i++ {
+		link := links.ObjectAtIndex(i).String()
+		if _, err := webserver.ParseDeepLink(link); err != nil {
+			log.Errorf("Ignoring link: %v", err)
+			continue
+		}
+		if !finishedLaunching && startLink == "" {
+			startLink = link
+			continue
+		}
+		createNewWebView(link)
+	}
+}
+
 func (ad *cocoaDefaultDelegateClassWrapper) handleApplicationDockMenu(_ objc.Object) objc.Object {
 	menu := cocoa.NSMenu_New()
 	newWindowMenuItem := cocoa.NSMenuItem_Init("New Window", objc.Sel(selNewWindow), "n")
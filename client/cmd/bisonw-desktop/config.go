@@ -17,6 +17,7 @@ type Config struct {
 	Kill      bool   `long:"kill" description:"Send a kill signal to a running instance and exit. This is not be supported on darwin"`
 	LogStdout bool   `long:"stdout" description:"Log to stdout (in addition to the log file)"`
 	Webview   string `long:"webview" description:"Opens a webview window pointing to the provided URL but not supported on darwin. Does nothing else. Precludes applicability of any other settings."`
+	DeepLink  string `long:"deeplink" description:"A dex:// link to open, e.g. dex://dex.decred.org:7232/market/dcr_btc. If Bison Wallet is already running, the link is opened in the running instance."`
 }
 
 func configure() (*Config, error) {
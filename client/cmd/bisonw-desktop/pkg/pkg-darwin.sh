@@ -78,6 +78,10 @@ function prepare() {
 		  <key>NSSupportsAutomaticGraphicsSwitching</key><true/>
 		  <key>CFBundlePackageType</key><string>APPL</string>
 		  <key>NSUserNotificationAlertStyle</key><string>alert</string>
+		  <key>CFBundleURLTypes</key><array><dict>
+		    <key>CFBundleURLName</key><string>com.decred.dcrdex</string>
+		    <key>CFBundleURLSchemes</key><array><string>dex</string></array>
+		  </dict></array>
 		  </dict></plist>" > "${CONTENTS_DIR}/Info.plist"
 }
 
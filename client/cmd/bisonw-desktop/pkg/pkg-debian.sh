@@ -92,11 +92,12 @@ cat > "${DOT_DESKTOP_BUILDPATH}" <<EOF
 Version=1.5
 Name=Bison Wallet
 Comment=Multi-wallet backed by Decred DEX
-Exec=${BIN_FILENAME}
+Exec=${BIN_FILENAME} --deeplink=%u
 Icon=${APP}
 Terminal=false
 Type=Application
 Categories=Office;Finance;
+MimeType=x-scheme-handler/dex;
 EOF
 chmod 644 "${DOT_DESKTOP_BUILDPATH}"
 
@@ -10,7 +10,8 @@ running webserver will create a sync server and publish the address at specific
 file location. On startup, before getting too far, bisonw-desktop looks for a
 file, reads the address, and attempts to make contact. If contact is made, the
 requesting bisonw-desktop will exit without error. The receiving bisonw-desktop
-will reopen the window if it is closed.
+will reopen the window if it is closed. A dex:// deep link given to the
+requesting bisonw-desktop is forwarded and opened in a new window.
 
 The sync server also enables killing a bisonw-desktop process that is running
 in the background because of active orders.
@@ -27,12 +28,14 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"decred.org/dcrdex/client/webserver"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
 )
@@ -86,9 +89,10 @@ func sendKillSignal(syncDir string) {
 
 // synchronize attempts to determine the state of any other bisonw-desktop
 // instances that are running. If none are found, startServer will be true.
-// If another instance is found, close will be true, and this instance of
-// bisonw-desktop should exit immediately.
-func synchronize(syncDir string) (startServer bool, err error) {
+// If another instance is found, startServer will be false, and this instance of
+// bisonw-desktop should exit immediately. The other instance opens a window,
+// showing the page for the deep link if one is given.
+func synchronize(syncDir, link string) (startServer bool, err error) {
 	addr, _, err := getSyncAddress(syncDir)
 	if err != nil {
 		return false, err
@@ -97,7 +101,11 @@ func synchronize(syncDir string) (startServer bool, err error) {
 		// Just start the server then.
 		return true, nil
 	}
-	resp, err := http.Get("http://" + addr)
+	openURL := "http://" + addr
+	if link != "" {
+		openURL += "/?link=" + url.QueryEscape(link)
+	}
+	resp, err := http.Get(openURL)
 	if err == nil {
 		if resp.StatusCode == http.StatusOK {
 			// Other instance will open the window.
@@ -113,7 +121,7 @@ func synchronize(syncDir string) (startServer bool, err error) {
 
 // runServer runs an instance of the sync server. Received commands are
 // communicated via unbuffered channels. Blocking channels are ignored.
-func runServer(ctx context.Context, syncDir string, openC chan<- struct{}, killC chan<- os.Signal, netw dex.Network) {
+func runServer(ctx context.Context, syncDir string, openC chan<- string, killC chan<- os.Signal, netw dex.Network) {
 	l, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
 		log.Errorf("ListenTCP error: %v", err)
@@ -153,7 +161,7 @@ func runServer(ctx context.Context, syncDir string, openC chan<- struct{}, killC
 }
 
 type syncServer struct {
-	openC   chan<- struct{}
+	openC   chan<- string
 	killC   chan<- os.Signal
 	killKey string
 }
@@ -161,9 +169,16 @@ type syncServer struct {
 func (s *syncServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch r.URL.Path {
 	case "/":
+		link := r.URL.Query().Get("link")
+		if link != "" {
+			if _, err := webserver.ParseDeepLink(link); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
 		w.WriteHeader(http.StatusOK)
 		select {
-		case s.openC <- struct{}{}:
+		case s.openC <- link:
 			log.Debug("Received window open request")
 		default:
 			log.Info("Ignored a window reopen request from another instance")
@@ -0,0 +1,164 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+)
+
+// shutdownRetryInterval is how often logout is retried while waiting for
+// active orders to settle.
+const shutdownRetryInterval = 15 * time.Second
+
+// sdNotify sends a state notification to the systemd service manager, e.g.
+// "READY=1". It is a no-op if the process was not started by systemd with
+// Type=notify.
+func sdNotify(state string) error {
+	sockPath := os.Getenv("NOTIFY_SOCKET")
+	if sockPath == "" {
+		return nil
+	}
+	// A leading @ indicates an abstract socket.
+	if strings.HasPrefix(sockPath, "@") {
+		sockPath = "\x00" + sockPath[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	if err != nil {
+		return fmt.Errorf("error connecting to systemd notify socket: %w", err)
+	}
+	defer conn.Close()
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// sdWatchdogInterval is the interval at which systemd expects watchdog
+// notifications, or zero if the watchdog is not enabled for this process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseUint(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec == 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// runWatchdog sends watchdog notifications to systemd at half the required
+// interval while healthy returns true, so that systemd restarts a hung
+// process.
+func runWatchdog(ctx context.Context, healthy func() bool) {
+	interval := sdWatchdogInterval()
+	if interval == 0 {
+		return
+	}
+	log.Infof("Sending systemd watchdog notifications every %s", interval/2)
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !healthy() {
+				log.Warnf("Skipping systemd watchdog notification. Client is not healthy.")
+				continue
+			}
+			if err := sdNotify("WATCHDOG=1"); err != nil {
+				log.Errorf("Error sending systemd watchdog notification: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// writePIDFile writes the process ID to the file, replacing a stale file from
+// an unclean exit. A second instance fails before this when opening the
+// database. The returned function removes the file.
+func writePIDFile(path string) (func(), error) {
+	path = dex.CleanAndExpandPath(path)
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("error writing pid file: %w", err)
+	}
+	return func() {
+		if err := os.Remove(path); err != nil {
+			log.Errorf("Error removing pid file: %v", err)
+		}
+	}, nil
+}
+
+// tryLogout attempts to log out of each Core, which fails if there are active
+// orders. A Logout that hangs or fails for any other reason does not prevent
+// shutdown.
+func tryLogout(cores []*core.Core) (ok bool) {
+	ok = true
+	for _, clientCore := range cores {
+		if !tryLogoutCore(clientCore) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+func tryLogoutCore(clientCore *core.Core) bool {
+	res := make(chan bool, 1)
+	go func() {
+		err := clientCore.Logout()
+		if err != nil && !errors.Is(err, core.ActiveOrdersLogoutErr) {
+			log.Errorf("Unexpected logout error: %v", err)
+		}
+		res <- !errors.Is(err, core.ActiveOrdersLogoutErr)
+	}()
+
+	select {
+	case <-time.After(10 * time.Second):
+		log.Errorf("Timeout waiting for Logout. Allowing shutdown, but you likely have active orders!")
+		return true // cancel all the contexts, hopefully breaking whatever deadlock
+	case ok := <-res:
+		return ok
+	}
+}
+
+// drainShutdown is the shutdown for SIGTERM, when there is nobody to prompt.
+// With active orders, it keeps the client running until the orders settle,
+// the wait expires, or another signal is received on forceChan.
+func drainShutdown(cores []*core.Core, wait time.Duration, forceChan <-chan os.Signal) {
+	log.Infof("Attempting to logout...")
+	sdNotify("STOPPING=1")
+	if tryLogout(cores) {
+		return
+	}
+	log.Warnf("Waiting up to %s for active orders to settle before shutting down. "+
+		"Signal again to shut down now, which may result in failed swaps and account penalization.", wait)
+	deadline := time.After(wait)
+	ticker := time.NewTicker(shutdownRetryInterval)
+	defer ticker.Stop()
+	for {
+		// Keep systemd from killing the process while waiting.
+		sdNotify(fmt.Sprintf("STATUS=Waiting for active orders to settle\nEXTEND_TIMEOUT_USEC=%d",
+			(2 * shutdownRetryInterval).Microseconds()))
+		select {
+		case <-ticker.C:
+			if tryLogout(cores) {
+				log.Infof("Active orders settled.")
+				return
+			}
+		case <-deadline:
+			log.Errorf("Shutting down with active orders after waiting %s!", wait)
+			return
+		case sig := <-forceChan:
+			log.Errorf("Received %s. Shutting down with active orders!", sig)
+			return
+		}
+	}
+}
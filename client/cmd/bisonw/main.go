@@ -15,12 +15,14 @@ import (
 	"runtime/pprof"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"decred.org/dcrdex/client/app"
 	"decred.org/dcrdex/client/asset"
 	_ "decred.org/dcrdex/client/asset/importall"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/grpcserver"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/client/rpcserver"
 	"decred.org/dcrdex/client/webserver"
@@ -36,15 +38,28 @@ var (
 	log            dex.Logger
 )
 
+// network is the client for one of the networks run by the process.
+type network struct {
+	cfg  *app.Config
+	core *core.Core
+	mm   *mm.MarketMaker
+}
+
 func runCore(cfg *app.Config) error {
 	defer cancel() // for the earliest returns
 
-	asset.SetNetwork(cfg.Net)
+	netCfgs := append([]*app.Config{cfg}, cfg.ExtraNetworks()...)
+	multiNet := len(netCfgs) > 1
+	// The token registry is global, so it is only trimmed to the network
+	// when running one network.
+	if !multiNet {
+		asset.SetNetwork(cfg.Net)
+	}
 
-	// If explicitly running without web server then you must run the rpc
-	// server.
-	if cfg.NoWeb && !cfg.RPCOn {
-		return fmt.Errorf("cannot run without web server unless --rpc is specified")
+	// If explicitly running without web server then you must run the rpc or
+	// grpc server.
+	if cfg.NoWeb && !cfg.RPCOn && !cfg.GRPCOn {
+		return fmt.Errorf("cannot run without web server unless --rpc or --grpc is specified")
 	}
 
 	if cfg.CPUProfile != "" {
@@ -70,9 +85,19 @@ func runCore(cfg *app.Config) error {
 		log.Infof("Logging with UTC time stamps. Current local time is %v",
 			time.Now().Local().Format("15:04:05 MST"))
 	}
-	log.Infof("bisonw starting for network: %s", cfg.Net)
-	log.Infof("Swap locktimes config: maker %s, taker %s",
-		dex.LockTimeMaker(cfg.Net), dex.LockTimeTaker(cfg.Net))
+	for _, netCfg := range netCfgs {
+		log.Infof("bisonw starting for network: %s", netCfg.Net)
+		log.Infof("Swap locktimes config: maker %s, taker %s",
+			dex.LockTimeMaker(netCfg.Net), dex.LockTimeTaker(netCfg.Net))
+	}
+
+	if cfg.PIDFile != "" {
+		removePIDFile, err := writePIDFile(cfg.PIDFile)
+		if err != nil {
+			return err
+		}
+		defer removePIDFile()
+	}
 
 	defer func() {
 		if pv := recover(); pv != nil {
@@ -81,99 +106,185 @@ func runCore(cfg *app.Config) error {
 		}
 	}()
 
-	// Prepare the Core.
-	clientCore, err := core.New(cfg.Core(logMaker.Logger("CORE")))
-	if err != nil {
-		return fmt.Errorf("error creating client core: %w", err)
+	// netLogger names a logger for a network's subsystem. The web, RPC and
+	// gRPC server packages share a logger between networks, so their names
+	// are not changed.
+	netLogger := func(netCfg *app.Config, name string) dex.Logger {
+		if multiNet {
+			name += "-" + strings.ToUpper(netCfg.Net.String())
+		}
+		return logMaker.Logger(name)
+	}
+
+	var provisioning *app.Provisioning
+	if cfg.Provision != "" {
+		var err error
+		if provisioning, err = app.LoadProvisioning(cfg.Provision); err != nil {
+			return err
+		}
 	}
 
-	marketMaker, err := mm.NewMarketMaker(clientCore, cfg.MMConfig.EventLogDBPath, cfg.MMConfig.BotConfigPath, logMaker.Logger("MM"))
-	if err != nil {
-		return fmt.Errorf("error creating market maker: %w", err)
+	// Prepare the Cores.
+	nets := make([]*network, 0, len(netCfgs))
+	cores := make([]*core.Core, 0, len(netCfgs))
+	for _, netCfg := range netCfgs {
+		clientCore, err := core.New(netCfg.Core(netLogger(netCfg, "CORE")))
+		if err != nil {
+			return fmt.Errorf("error creating %s client core: %w", netCfg.Net, err)
+		}
+
+		marketMaker, err := mm.NewMarketMaker(clientCore, netCfg.MMConfig.EventLogDBPath, netCfg.MMConfig.BotConfigPath, netLogger(netCfg, "MM"))
+		if err != nil {
+			return fmt.Errorf("error creating %s market maker: %w", netCfg.Net, err)
+		}
+		nets = append(nets, &network{cfg: netCfg, core: clientCore, mm: marketMaker})
+		cores = append(cores, clientCore)
 	}
 
 	// Catch interrupt signal (e.g. ctrl+c), prompting to shutdown if the user
-	// is logged in, and there are active orders or matches.
+	// is logged in, and there are active orders or matches. SIGTERM, e.g. from
+	// systemd, waits for active orders to settle instead of prompting.
 	killChan := make(chan os.Signal, 1)
-	signal.Notify(killChan, os.Interrupt)
+	signal.Notify(killChan, os.Interrupt, syscall.SIGTERM)
 	go func() {
-		for range killChan {
-			if promptShutdown(clientCore) {
-				log.Infof("Shutting down...")
-				cancel()
-				return
+		for sig := range killChan {
+			if sig == syscall.SIGTERM {
+				drainShutdown(cores, cfg.ShutdownWait, killChan)
+			} else if !promptShutdown(cores) {
+				continue
 			}
+			log.Infof("Shutting down...")
+			cancel()
+			return
 		}
 	}()
 
 	var wg sync.WaitGroup
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		clientCore.Run(appCtx)
-		cancel() // in the event that Run returns prematurely prior to context cancellation
-	}()
+	for _, n := range nets {
+		clientCore := n.core
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clientCore.Run(appCtx)
+			cancel() // in the event that Run returns prematurely prior to context cancellation
+		}()
+	}
 
-	<-clientCore.Ready()
+	for _, n := range nets {
+		<-n.core.Ready()
+	}
 
-	var mmCM *dex.ConnectionMaster
+	var mmCMs []*dex.ConnectionMaster
 	defer func() {
 		log.Info("Exiting bisonw main.")
 		cancel()  // no-op with clean rpc/web server setup
 		wg.Wait() // no-op with clean setup and shutdown
-		if mmCM != nil {
+		for _, mmCM := range mmCMs {
 			mmCM.Wait()
 		}
 	}()
 
-	if marketMaker != nil {
-		mmCM = dex.NewConnectionMaster(marketMaker)
-		if err := mmCM.ConnectOnce(appCtx); err != nil {
-			return fmt.Errorf("Error connecting market maker")
+	for _, n := range nets {
+		if n.mm != nil {
+			mmCM := dex.NewConnectionMaster(n.mm)
+			if err := mmCM.ConnectOnce(appCtx); err != nil {
+				return fmt.Errorf("Error connecting %s market maker", n.cfg.Net)
+			}
+			mmCMs = append(mmCMs, mmCM)
 		}
 	}
 
-	if cfg.RPCOn {
-		rpcSrv, err := rpcserver.New(cfg.RPC(clientCore, marketMaker, logMaker.Logger("RPC")))
-		if err != nil {
-			return fmt.Errorf("failed to create rpc server: %w", err)
-		}
-
+	if provisioning != nil {
+		primary := nets[0]
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			cm := dex.NewConnectionMaster(rpcSrv)
-			err := cm.Connect(appCtx)
-			if err != nil {
-				log.Errorf("Error starting rpc server: %v", err)
+			err := provisioning.Run(appCtx, primary.core, primary.mm, netLogger(primary.cfg, "PROV"))
+			if err != nil && !errors.Is(err, context.Canceled) {
+				log.Errorf("Provisioning failed: %v", err)
 				cancel()
-				return
 			}
-			cm.Wait()
 		}()
 	}
 
-	if !cfg.NoWeb {
-		webSrv, err := webserver.New(cfg.Web(clientCore, marketMaker, logMaker.Logger("WEB"), utc))
-		if err != nil {
-			return fmt.Errorf("failed creating web server: %w", err)
-		}
+	// started is done when the servers are listening.
+	var started sync.WaitGroup
 
+	// startServer runs a server until the app context is canceled. If the
+	// server fails to start, the app is shut down.
+	startServer := func(srv dex.Connector, name string, onStart func()) {
 		wg.Add(1)
+		started.Add(1)
 		go func() {
 			defer wg.Done()
-			cm := dex.NewConnectionMaster(webSrv)
+			cm := dex.NewConnectionMaster(srv)
 			err := cm.Connect(appCtx)
+			started.Done()
 			if err != nil {
-				log.Errorf("Error starting web server: %v", err)
+				log.Errorf("Error starting %s: %v", name, err)
 				cancel()
 				return
 			}
-			webserverReady <- webSrv.Addr()
+			if onStart != nil {
+				onStart()
+			}
 			cm.Wait()
 		}()
-	} else {
-		close(webserverReady)
+	}
+
+	for i, n := range nets {
+		netCfg, clientCore, marketMaker := n.cfg, n.core, n.mm
+		if netCfg.RPCOn {
+			rpcSrv, err := rpcserver.New(netCfg.RPC(clientCore, marketMaker, logMaker.Logger("RPC")))
+			if err != nil {
+				return fmt.Errorf("failed to create %s rpc server: %w", netCfg.Net, err)
+			}
+			startServer(rpcSrv, netCfg.Net.String()+" rpc server", nil)
+		}
+
+		if netCfg.GRPCOn {
+			grpcSrv, err := grpcserver.New(netCfg.GRPC(clientCore, marketMaker, logMaker.Logger("GRPC")))
+			if err != nil {
+				return fmt.Errorf("failed to create %s grpc server: %w", netCfg.Net, err)
+			}
+			startServer(grpcSrv, netCfg.Net.String()+" grpc server", nil)
+		}
+
+		if !netCfg.NoWeb {
+			webSrv, err := webserver.New(netCfg.Web(clientCore, marketMaker, logMaker.Logger("WEB"), utc))
+			if err != nil {
+				return fmt.Errorf("failed creating %s web server: %w", netCfg.Net, err)
+			}
+			var onStart func()
+			// The primary network's web server is the one opened by the
+			// system tray, and it links to the others.
+			if i == 0 {
+				onStart = func() { webserverReady <- webSrv.Addr() }
+			}
+			startServer(webSrv, netCfg.Net.String()+" web server", onStart)
+		} else if i == 0 {
+			close(webserverReady)
+		}
+	}
+
+	// Tell systemd that startup is complete, and start its watchdog.
+	started.Wait()
+	if appCtx.Err() == nil {
+		if err := sdNotify("READY=1\nSTATUS=Running"); err != nil {
+			log.Errorf("Error sending systemd readiness notification: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runWatchdog(appCtx, func() bool {
+				for _, clientCore := range cores {
+					if clientCore.CheckDB() != nil {
+						return false
+					}
+				}
+				return true
+			})
+		}()
 	}
 
 	// Wait for everything to stop.
@@ -183,33 +294,12 @@ func runCore(cfg *app.Config) error {
 }
 
 // promptShutdown checks if there are active orders and asks confirmation to
-// shutdown if there are. The return value indicates if it is safe to stop Core
-// or if the user has confirmed they want to shutdown with active orders.
-func promptShutdown(clientCore *core.Core) bool {
+// shutdown if there are. The return value indicates if it is safe to stop the
+// Cores or if the user has confirmed they want to shutdown with active orders.
+func promptShutdown(cores []*core.Core) bool {
 	log.Infof("Attempting to logout...")
-	// Do not allow Logout hanging to prevent shutdown.
-	res := make(chan bool, 1)
-	go func() {
-		// Only block logout if err is ActiveOrdersLogoutErr.
-		var ok bool
-		err := clientCore.Logout()
-		if err == nil {
-			ok = true
-		} else if !errors.Is(err, core.ActiveOrdersLogoutErr) {
-			log.Errorf("Unexpected logout error: %v", err)
-			ok = true
-		} // else not ok => prompt
-		res <- ok
-	}()
-
-	select {
-	case <-time.After(10 * time.Second):
-		log.Errorf("Timeout waiting for Logout. Allowing shutdown, but you likely have active orders!")
-		return true // cancel all the contexts, hopefully breaking whatever deadlock
-	case ok := <-res:
-		if ok {
-			return true
-		}
+	if tryLogout(cores) {
+		return true
 	}
 
 	fmt.Print("You have active orders. Shutting down now may result in failed swaps and account penalization.\n" +
@@ -23,6 +23,7 @@ const (
 	defaultSimnetHost     = "127.0.0.3"
 	defaultConfigFilename = "dexcctl.conf"
 	defaultRPCCertFile    = "rpc.cert"
+	defaultHistoryFile    = "history"
 )
 
 var (
@@ -40,6 +41,8 @@ type config struct {
 	RPCPass      string   `short:"P" long:"rpcpass" default-mask:"-" description:"RPC password"`
 	RPCAddr      string   `short:"a" long:"rpcaddr" description:"RPC server to connect to"`
 	RPCCert      string   `short:"c" long:"rpccert" description:"RPC server certificate chain for validation"`
+	ClientCert   string   `long:"clientcert" description:"Client certificate for RPC servers requiring client certificate authentication"`
+	ClientKey    string   `long:"clientkey" description:"Client certificate key"`
 	PrintJSON    bool     `short:"j" long:"json" description:"Print json messages sent and received"`
 	Proxy        string   `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser    string   `long:"proxyuser" description:"Username for proxy server"`
@@ -47,6 +50,8 @@ type config struct {
 	PasswordArgs []string `short:"p" long:"passarg" description:"Password arguments to bypass stdin prompts."`
 	Testnet      bool     `long:"testnet" description:"use testnet"`
 	Simnet       bool     `long:"simnet" description:"use simnet"`
+	Interactive  bool     `short:"i" long:"interactive" description:"Start an interactive shell with command history and tab completion"`
+	HistoryFile  string   `long:"historyfile" description:"File where the interactive shell's command history is saved"`
 }
 
 // configure parses command line options and a config file if present. Returns
@@ -64,7 +69,9 @@ func configure() (*config, []string, bool, error) {
 		var flagErr *flags.Error
 		if errors.As(err, &flagErr) && flagErr.Type == flags.ErrHelp {
 			// This line is printed below the help message.
-			fmt.Printf("%v\nThe special parameter `-` indicates that a parameter should be read from the\nnext unread line from standard input.\n", err)
+			fmt.Printf("%v\nThe special parameter `-` indicates that a parameter should be read from the\nnext unread line from standard input.\n"+
+				"\nThe watch command prints notifications as JSON lines until interrupted. Give\n"+
+				"note types to print only those, e.g. `watch actionrequired order`.\n", err)
 			return nil, nil, stop, nil
 		}
 		return nil, nil, false, err
@@ -112,6 +119,17 @@ func configure() (*config, []string, bool, error) {
 		cfg.RPCCert = dex.CleanAndExpandPath(cfg.RPCCert)
 	}
 
+	if (cfg.ClientCert == "") != (cfg.ClientKey == "") {
+		return nil, nil, false, fmt.Errorf("clientcert and clientkey must be specified together")
+	}
+	cfg.ClientCert = dex.CleanAndExpandPath(cfg.ClientCert)
+	cfg.ClientKey = dex.CleanAndExpandPath(cfg.ClientKey)
+
+	if cfg.HistoryFile == "" {
+		cfg.HistoryFile = filepath.Join(appDir, defaultHistoryFile)
+	}
+	cfg.HistoryFile = dex.CleanAndExpandPath(cfg.HistoryFile)
+
 	if cfg.Simnet && cfg.Testnet {
 		return nil, nil, false, fmt.Errorf("simnet and testnet cannot both be specified")
 	}
@@ -5,11 +5,22 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/gorilla/websocket"
 )
 
 func TestConfigure(t *testing.T) {
@@ -160,3 +171,197 @@ func TestReadTextFile(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "", want: nil},
+		{line: "  version  ", want: []string{"version"}},
+		{line: `orderhistory '{"n": 5, "hosts": ["dex.com"]}'`, want: []string{"orderhistory", `{"n": 5, "hosts": ["dex.com"]}`}},
+		{line: `send 42 "a b" c\ d ""`, want: []string{"send", "42", "a b", "c d", ""}},
+		{line: `x "a\"b" 'c\d'`, want: []string{"x", `a"b`, `c\d`}},
+		{line: `x "ab`, wantErr: true},
+		{line: `x ab\`, wantErr: true},
+	}
+	for _, test := range tests {
+		args, err := splitArgs(test.line)
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%q: no error", test.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.line, err)
+		}
+		if !reflect.DeepEqual(args, test.want) {
+			t.Fatalf("%q: wanted %q, got %q", test.line, test.want, args)
+		}
+	}
+}
+
+func TestCompleter(t *testing.T) {
+	c := newCompleter("help (cmd) (includePasswords)\nnewwallet assetID walletType (\"path\")\nnotifications n\norderbook host base quote")
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "wallet.conf"), nil, 0600)
+
+	tests := []struct {
+		line      string
+		pos       int // -1 for the end of the line
+		wantLine  string
+		wantHints []string
+	}{
+		{line: "ord", pos: -1, wantLine: "orderbook "},
+		{line: "n", pos: -1, wantLine: "n", wantHints: []string{"newwallet", "notifications"}},
+		{line: "no", pos: -1, wantLine: "notifications "},
+		{line: "e", pos: -1, wantLine: "exit "},
+		{line: "ord dex.com", pos: 3, wantLine: "orderbook  dex.com"},
+		{line: "help ne", pos: -1, wantLine: "help newwallet "},
+		{line: "orderbook dex.com ", pos: -1, wantLine: "orderbook dex.com ", wantHints: []string{"orderbook host base quote"}},
+		{line: "newwallet 42 rpc " + filepath.Join(dir, "wal"), pos: -1, wantLine: "newwallet 42 rpc " + filepath.Join(dir, "wallet.conf") + " "},
+		{line: "bogus ", pos: -1, wantLine: "bogus "},
+	}
+	for _, test := range tests {
+		pos := test.pos
+		if pos < 0 {
+			pos = len(test.line)
+		}
+		line, newPos, hints := c.complete(test.line, pos)
+		if line != test.wantLine || !reflect.DeepEqual(hints, test.wantHints) {
+			t.Fatalf("%q: got %q, %q", test.line, line, hints)
+		}
+		if test.pos < 0 && test.wantHints == nil && newPos != len(line) {
+			t.Fatalf("%q: wrong cursor position %d", test.line, newPos)
+		}
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bwctl", "history")
+	h, err := loadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxHistory+10; i++ {
+		h.Add("cmd" + strconv.Itoa(i))
+	}
+	// Repeats and empty lines are not recorded.
+	h.Add("cmd" + strconv.Itoa(maxHistory+9))
+	h.Add("")
+	h.close()
+
+	h, err = loadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.close()
+	if h.Len() != maxHistory || h.At(0) != "cmd"+strconv.Itoa(maxHistory+9) || h.At(maxHistory-1) != "cmd10" {
+		t.Fatalf("wrong history, len %d, newest %q", h.Len(), h.At(0))
+	}
+	// The file was trimmed.
+	b, _ := os.ReadFile(path)
+	if n := len(strings.Split(strings.TrimSpace(string(b)), "\n")); n != maxHistory {
+		t.Fatalf("history file has %d lines", n)
+	}
+}
+
+// tLineWriter sends each write on a channel.
+type tLineWriter chan string
+
+func (w tLineWriter) Write(b []byte) (int, error) {
+	w <- string(b)
+	return len(b), nil
+}
+
+func TestWatch(t *testing.T) {
+	defer func(d time.Duration) { watchReconnectInterval = d }(watchReconnectInterval)
+	watchReconnectInterval = time.Millisecond
+
+	subs := make(chan *watchSubscription, 2)
+	var conns int
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "bob" || pass != "pass" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var req msgjson.Message
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		sub := new(watchSubscription)
+		req.Unmarshal(sub)
+		subs <- sub
+		conns++
+		resp, _ := msgjson.NewResponse(req.ID, &watchSubscribed{Stream: "abc", Seq: 5, Complete: true}, nil)
+		conn.WriteJSON(resp)
+		// Drop the first connection after one notification.
+		seq := uint64(5 + conns)
+		note, _ := msgjson.NewNotification("subnote", &watchNote{
+			Seq:  seq,
+			Note: json.RawMessage(`{ "type": "actionrequired", "seq": ` + strconv.FormatUint(seq, 10) + ` }`),
+		})
+		conn.WriteJSON(note)
+		if conns > 1 {
+			conn.ReadMessage() // until the client disconnects
+		}
+	}))
+	defer srv.Close()
+
+	certPath := filepath.Join(t.TempDir(), "rpc.cert")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(certPath, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{
+		RPCUser: "bob",
+		RPCPass: "pass",
+		RPCAddr: srv.Listener.Addr().String(),
+		RPCCert: certPath,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lines := make(tLineWriter, 2)
+	errC := make(chan error, 1)
+	go func() { errC <- runWatch(ctx, cfg, []string{"actionrequired"}, lines) }()
+
+	for _, want := range []string{`{"type":"actionrequired","seq":6}` + "\n", `{"type":"actionrequired","seq":7}` + "\n"} {
+		select {
+		case line := <-lines:
+			if line != want {
+				t.Fatalf("wrong line %q, wanted %q", line, want)
+			}
+		case err := <-errC:
+			t.Fatalf("runWatch returned early: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for notification")
+		}
+	}
+	first, second := <-subs, <-subs
+	if !reflect.DeepEqual(first, &watchSubscription{Types: []string{"actionrequired"}}) {
+		t.Fatalf("wrong first subscription %+v", first)
+	}
+	// The reconnected subscription resumes after the last notification.
+	if second.Stream != "abc" || second.Since != 6 {
+		t.Fatalf("wrong resumed subscription %+v", second)
+	}
+	cancel()
+	if err := <-errC; err != nil {
+		t.Fatalf("runWatch error: %v", err)
+	}
+
+	// Bad credentials fail without retrying.
+	cfg.RPCPass = "wrong"
+	if err := runWatch(context.Background(), cfg, nil, lines); err == nil {
+		t.Fatal("no error for bad credentials")
+	}
+}
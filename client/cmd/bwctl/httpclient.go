@@ -39,25 +39,13 @@ func newHTTPClient(cfg *config, urlStr string) (*http.Client, error) {
 		}
 	}
 
-	// Configure TLS.
-	pem, err := os.ReadFile(cfg.RPCCert)
-	if err != nil {
-		return nil, err
-	}
-
 	uri, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing URL: %v", err)
 	}
-
-	pool := x509.NewCertPool()
-	if ok := pool.AppendCertsFromPEM(pem); !ok {
-		return nil, fmt.Errorf("invalid certificate file: %v",
-			cfg.RPCCert)
-	}
-	tlsConfig := &tls.Config{
-		RootCAs:    pool,
-		ServerName: uri.Hostname(),
+	tlsConfig, err := newTLSConfig(cfg, uri.Hostname())
+	if err != nil {
+		return nil, err
 	}
 
 	// Create and return the new HTTP client potentially configured with a
@@ -71,6 +59,33 @@ func newHTTPClient(cfg *config, urlStr string) (*http.Client, error) {
 	return &client, nil
 }
 
+// newTLSConfig creates the TLS configuration for connecting to the RPC server
+// at host, with the RPC server certificate and the client certificate from the
+// config.
+func newTLSConfig(cfg *config, host string) (*tls.Config, error) {
+	pem, err := os.ReadFile(cfg.RPCCert)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(pem); !ok {
+		return nil, fmt.Errorf("invalid certificate file: %v",
+			cfg.RPCCert)
+	}
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		ServerName: host,
+	}
+	if cfg.ClientCert != "" {
+		keypair, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{keypair}
+	}
+	return tlsConfig, nil
+}
+
 // sendPostRequest sends the marshalled JSON-RPC command using HTTP-POST mode
 // to the server described in the passed config struct.  It also attempts to
 // unmarshal the response as a msgjson.Message response and returns either the
@@ -63,25 +63,34 @@ var promptPasswords = map[string][]string{
 	"purchasetickets":   {"App password:"},
 	"startmmbot":        {"App password:"},
 	"withdrawbchspv":    {"App password"},
+	"exportaccount":     {"App password:"},
+	"importaccount":     {"App password:"},
+	"recoverykeys":      {"App seed:"},
+	"reconfigurewallet": {"App password:", "New wallet password (empty to keep current):"},
 }
 
 // optionalTextFiles is a map of routes to arg index for routes that should read
 // the text content of a file, where the file path _may_ be found in the route's
 // cmd args at the specified index.
 var optionalTextFiles = map[string]int{
-	"discoveracct": 1,
-	"bondassets":   1,
-	"postbond":     4,
-	"getdexconfig": 1,
-	"register":     3,
-	"newwallet":    2,
+	"discoveracct":      1,
+	"bondassets":        1,
+	"postbond":          4,
+	"getdexconfig":      1,
+	"register":          3,
+	"newwallet":         2,
+	"reconfigurewallet": 2,
+	"importaccount":     0,
 }
 
+// passwordPrompter prompts for a password.
+type passwordPrompter func(ctx context.Context, prompt string) ([]byte, error)
+
 // promptPWs prompts for passwords on stdin and returns an error if prompting
 // fails or a password is empty. Returns passwords as a slice of []byte. If
 // cmdPWs is provided, the passwords will be drawn from cmdPWs instead of stdin
 // prompts.
-func promptPWs(ctx context.Context, cmd string, cmdPWs []string) ([]encode.PassBytes, error) {
+func promptPWs(ctx context.Context, cmd string, cmdPWs []string, promptPW passwordPrompter) ([]encode.PassBytes, error) {
 	prompts, exists := promptPasswords[cmd]
 	if !exists {
 		return nil, nil
@@ -101,7 +110,7 @@ func promptPWs(ctx context.Context, cmd string, cmdPWs []string) ([]encode.PassB
 
 	// Prompt for passwords one at a time.
 	for i, prompt := range prompts {
-		pws[i], err = admin.PasswordPrompt(ctx, prompt)
+		pws[i], err = promptPW(ctx, prompt)
 		if err != nil {
 			return nil, err
 		}
@@ -140,10 +149,27 @@ func run(ctx context.Context) error {
 		return nil
 	}
 
+	if cfg.Interactive {
+		if len(args) > 0 {
+			return fmt.Errorf("a command cannot be specified in interactive mode")
+		}
+		return runInteractive(ctx, cfg)
+	}
+
 	if len(args) < 1 {
 		return fmt.Errorf("no command specified\n%s", listCmdMessage)
 	}
 
+	if args[0] == watchCommand {
+		return runWatch(ctx, cfg, args[1:], os.Stdout)
+	}
+
+	return runCommand(ctx, cfg, args, admin.PasswordPrompt)
+}
+
+// runCommand sends the command in args to the RPC server and prints the
+// result.
+func runCommand(ctx context.Context, cfg *config, args []string, promptPW passwordPrompter) error {
 	// Convert remaining command line args to a slice of interface values
 	// to be passed along as parameters to new command creation function.
 	//
@@ -168,7 +194,7 @@ func run(ctx context.Context) error {
 	}
 
 	// Prompt for passwords.
-	pws, err := promptPWs(ctx, args[0], cfg.PasswordArgs)
+	pws, err := promptPWs(ctx, args[0], cfg.PasswordArgs, promptPW)
 	if err != nil {
 		return err
 	}
@@ -210,11 +236,8 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("unable to unmarshal response payload: %v", err)
 	}
 
-	if resp.Error != nil {
-		return errors.New(resp.Error.Message)
-	}
-
-	// Choose how to display the result based on its type.
+	// Choose how to display the result based on its type. A result may
+	// accompany an error, e.g. the status of a degraded client.
 	strResult := string(resp.Result)
 	if strings.HasPrefix(strResult, "{") || strings.HasPrefix(strResult, "[") {
 		var dst bytes.Buffer
@@ -228,10 +251,14 @@ func run(ctx context.Context) error {
 			return fmt.Errorf("failed to unmarshal result: %v", err)
 		}
 		fmt.Println(str)
-	} else if strResult != "null" {
+	} else if strResult != "null" && strResult != "" {
 		fmt.Println(strResult)
 	}
 
+	if resp.Error != nil {
+		return errors.New(resp.Error.Message)
+	}
+
 	// If this is a version check command, go the extra mile and check for
 	// compatibility.
 	if args[0] == "version" {
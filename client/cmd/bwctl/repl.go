@@ -0,0 +1,355 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"decred.org/dcrdex/client/rpcserver"
+	"decred.org/dcrdex/server/admin"
+	"golang.org/x/term"
+)
+
+const (
+	replPrompt = "bwctl> "
+	// maxHistory is the number of commands kept in the history file.
+	maxHistory = 1000
+)
+
+// replCommands are handled by the interactive shell instead of the RPC server.
+var replCommands = []string{"exit", "quit"}
+
+// runInteractive runs the interactive shell. The RPC credentials and the app
+// password are only entered once per session.
+func runInteractive(ctx context.Context, cfg *config) error {
+	stdin := int(os.Stdin.Fd())
+	if !term.IsTerminal(stdin) {
+		return errors.New("interactive mode requires a terminal")
+	}
+	if len(cfg.PasswordArgs) > 0 {
+		return errors.New("password arguments cannot be used in interactive mode")
+	}
+
+	if cfg.RPCUser != "" && cfg.RPCPass == "" {
+		pass, err := admin.PasswordPrompt(ctx, "RPC password:")
+		if err != nil {
+			return err
+		}
+		cfg.RPCPass = string(pass)
+	}
+
+	hist, err := loadHistory(cfg.HistoryFile)
+	if err != nil {
+		return err
+	}
+	defer hist.close()
+
+	comp := newCompleter(rpcserver.ListCommands(false))
+	termState, err := term.MakeRaw(stdin)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(stdin, termState)
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, replPrompt)
+	t.History = hist
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, hints := comp.complete(line, pos)
+		if len(hints) > 0 {
+			fmt.Fprintln(t, strings.Join(hints, "  "))
+		}
+		return newLine, newPos, true
+	}
+
+	sess := &replSession{}
+	fmt.Fprintln(t, "Type a command, or exit to quit. Tab completes routes and arguments.")
+	for ctx.Err() == nil {
+		line, err := t.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		args, err := splitArgs(line)
+		if err != nil {
+			fmt.Fprintln(t, err)
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return nil
+		}
+		if args[0] == watchCommand {
+			fmt.Fprintln(t, "watch cannot be used in interactive mode")
+			continue
+		}
+
+		// Commands run with the terminal in its initial state, so that their
+		// output and password prompts behave as in a single command.
+		if err := term.Restore(stdin, termState); err != nil {
+			return err
+		}
+		if err := runCommand(ctx, cfg, args, sess.promptPassword); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			// The app password may have been wrong.
+			sess.appPass = nil
+		}
+		if _, err := term.MakeRaw(stdin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replSession holds the credentials entered during an interactive session.
+type replSession struct {
+	appPass []byte
+}
+
+// promptPassword prompts for a password. The app password is remembered for
+// the rest of the session.
+func (s *replSession) promptPassword(ctx context.Context, prompt string) ([]byte, error) {
+	isAppPass := strings.HasPrefix(prompt, "App password")
+	if isAppPass && s.appPass != nil {
+		return s.appPass, nil
+	}
+	pass, err := admin.PasswordPrompt(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+	if isAppPass {
+		s.appPass = pass
+	}
+	return pass, nil
+}
+
+// splitArgs splits a command line into arguments. Arguments may be quoted with
+// single or double quotes, e.g. for JSON arguments, and a backslash escapes
+// the next character outside of single quotes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var inArg, escaped bool
+	var quote rune
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				arg.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
+
+// completer completes routes and their arguments.
+type completer struct {
+	routes []string
+	// usages are the routes' short usage strings.
+	usages map[string]string
+}
+
+// newCompleter creates a completer for the routes listed by
+// rpcserver.ListCommands.
+func newCompleter(cmdList string) *completer {
+	c := &completer{usages: make(map[string]string)}
+	for _, line := range strings.Split(cmdList, "\n") {
+		route, _, _ := strings.Cut(line, " ")
+		if route == "" {
+			continue
+		}
+		c.routes = append(c.routes, route)
+		c.usages[route] = line
+	}
+	c.routes = append(c.routes, replCommands...)
+	sort.Strings(c.routes)
+	return c
+}
+
+// complete completes the word before pos. The returned hints are the
+// candidates or the route's usage, to be shown when the word cannot be
+// completed.
+func (c *completer) complete(line string, pos int) (string, int, []string) {
+	prefix := line[:pos]
+	start := strings.LastIndexAny(prefix, " \t") + 1
+	word := prefix[start:]
+	prevArgs := strings.Fields(prefix[:start])
+
+	var candidates []string
+	switch {
+	case len(prevArgs) == 0:
+		candidates = withPrefix(c.routes, word)
+	case prevArgs[0] == "help" && len(prevArgs) == 1:
+		candidates = withPrefix(c.routes, word)
+	default:
+		route := prevArgs[0]
+		if fileIdx, found := optionalTextFiles[route]; found && fileIdx == len(prevArgs)-1 {
+			candidates = completePath(word)
+			break
+		}
+		if usage, found := c.usages[route]; found {
+			return line, pos, []string{usage}
+		}
+		return line, pos, nil
+	}
+
+	switch len(candidates) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		completion := candidates[0]
+		if !strings.HasSuffix(completion, string(filepath.Separator)) {
+			completion += " "
+		}
+		return prefix[:start] + completion + line[pos:], start + len(completion), nil
+	}
+	if common := commonPrefix(candidates); len(common) > len(word) {
+		return prefix[:start] + common + line[pos:], start + len(common), nil
+	}
+	return line, pos, candidates
+}
+
+// withPrefix returns the strings starting with prefix.
+func withPrefix(strs []string, prefix string) []string {
+	var matches []string
+	for _, s := range strs {
+		if strings.HasPrefix(s, prefix) {
+			matches = append(matches, s)
+		}
+	}
+	return matches
+}
+
+// completePath returns the file paths starting with word. Directories end in
+// a separator.
+func completePath(word string) []string {
+	matches, _ := filepath.Glob(word + "*")
+	for i, m := range matches {
+		if fi, err := os.Stat(m); err == nil && fi.IsDir() {
+			matches[i] = m + string(filepath.Separator)
+		}
+	}
+	return matches
+}
+
+// commonPrefix is the longest common prefix of the strings.
+func commonPrefix(strs []string) string {
+	common := strs[0]
+	for _, s := range strs[1:] {
+		for !strings.HasPrefix(s, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	return common
+}
+
+// history is the interactive shell's command history, which is saved to a
+// file.
+type history struct {
+	entries []string
+	f       *os.File
+}
+
+// loadHistory loads the history file, creating it if necessary.
+func loadHistory(path string) (*history, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("error creating history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening history file: %w", err)
+	}
+	h := &history{f: f}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error reading history file: %w", err)
+	}
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+		// Rewrite the file so it does not grow without bound.
+		if err := f.Truncate(0); err == nil {
+			f.WriteString(strings.Join(h.entries, "\n") + "\n")
+		}
+	}
+	return h, nil
+}
+
+// Add adds a command to the history. Part of the term.History interface.
+func (h *history) Add(entry string) {
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[1:]
+	}
+	if h.f != nil {
+		h.f.WriteString(entry + "\n")
+	}
+}
+
+// Len is the number of commands in the history. Part of the term.History
+// interface.
+func (h *history) Len() int {
+	return len(h.entries)
+}
+
+// At returns a command from the history, with 0 being the most recent. Part of
+// the term.History interface.
+func (h *history) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
+
+var _ term.History = (*history)(nil)
+
+func (h *history) close() {
+	h.f.Close()
+}
@@ -0,0 +1,189 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/decred/go-socks/socks"
+	"github.com/gorilla/websocket"
+)
+
+// watchCommand is handled by bwctl instead of the RPC server. It prints
+// notifications from the RPC server's websocket feed.
+const watchCommand = "watch"
+
+// watchReconnectInterval is how long to wait before reconnecting after the
+// connection to the RPC server is lost.
+var watchReconnectInterval = 5 * time.Second
+
+// watchSubscription is the payload of the RPC server's websocket subscribe
+// request. Stream and Since resume the subscription after reconnecting.
+type watchSubscription struct {
+	Types  []string `json:"types"`
+	Stream string   `json:"stream,omitempty"`
+	Since  uint64   `json:"since,omitempty"`
+}
+
+// watchSubscribed is the result of a subscribe request.
+type watchSubscribed struct {
+	Stream   string `json:"stream"`
+	Seq      uint64 `json:"seq"`
+	Complete bool   `json:"complete"`
+}
+
+// watchNote is the payload of a subnote notification.
+type watchNote struct {
+	Seq  uint64          `json:"seq"`
+	Note json.RawMessage `json:"note"`
+}
+
+// runWatch prints notifications of the note types, or all notifications if no
+// types are given, to w as JSON lines until the context is canceled. If the
+// connection is lost, it reconnects and resumes with the notifications missed
+// in the meantime.
+func runWatch(ctx context.Context, cfg *config, types []string, w io.Writer) error {
+	sub := &watchSubscription{Types: types}
+	for connected := false; ; {
+		err := watchConn(ctx, cfg, sub, w, func() { connected = true })
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Fail right away for bad settings or credentials.
+		if !connected {
+			return err
+		}
+		var rpcErr *msgjson.Error
+		if errors.As(err, &rpcErr) {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Connection lost: %v. Reconnecting in %s.\n", err, watchReconnectInterval)
+		select {
+		case <-time.After(watchReconnectInterval):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// watchConn subscribes to the notifications on one websocket connection and
+// prints them until the connection is lost. sub is updated with the last
+// notification received.
+func watchConn(ctx context.Context, cfg *config, sub *watchSubscription, w io.Writer, onConnect func()) error {
+	conn, err := dialWS(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	onConnect()
+	// Unblock ReadMessage when the context is canceled.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	const subID = 1
+	req, err := msgjson.NewRequest(subID, "subscribe", sub)
+	if err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(req); err != nil {
+		return fmt.Errorf("error sending subscribe request: %w", err)
+	}
+
+	for {
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		msg, err := msgjson.DecodeMessage(b)
+		if err != nil {
+			return fmt.Errorf("error decoding message: %w", err)
+		}
+		switch {
+		case msg.Type == msgjson.Response && msg.ID == subID:
+			resp, err := msg.Response()
+			if err != nil {
+				return fmt.Errorf("error decoding subscribe response: %w", err)
+			}
+			if resp.Error != nil {
+				return resp.Error
+			}
+			var res watchSubscribed
+			if err := json.Unmarshal(resp.Result, &res); err != nil {
+				return fmt.Errorf("error decoding subscribe result: %w", err)
+			}
+			if sub.Stream != "" && !res.Complete {
+				fmt.Fprintln(os.Stderr, "Some notifications were missed while disconnected.")
+			}
+			if sub.Stream != res.Stream {
+				sub.Stream, sub.Since = res.Stream, res.Seq
+			}
+		case msg.Type == msgjson.Notification && msg.Route == "subnote":
+			var note watchNote
+			if err := msg.Unmarshal(&note); err != nil {
+				return fmt.Errorf("error decoding notification: %w", err)
+			}
+			var line bytes.Buffer
+			if err := json.Compact(&line, note.Note); err != nil {
+				return fmt.Errorf("error formatting notification: %w", err)
+			}
+			line.WriteByte('\n')
+			if _, err := w.Write(line.Bytes()); err != nil {
+				return err
+			}
+			sub.Since = note.Seq
+		}
+	}
+}
+
+// dialWS connects to the RPC server's websocket endpoint.
+func dialWS(ctx context.Context, cfg *config) (*websocket.Conn, error) {
+	host, _, err := net.SplitHostPort(cfg.RPCAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPC address %q: %w", cfg.RPCAddr, err)
+	}
+	tlsConfig, err := newTLSConfig(cfg, host)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &websocket.Dialer{
+		TLSClientConfig:  tlsConfig,
+		HandshakeTimeout: 10 * time.Second,
+	}
+	if cfg.Proxy != "" {
+		proxy := &socks.Proxy{
+			Addr:     cfg.Proxy,
+			Username: cfg.ProxyUser,
+			Password: cfg.ProxyPass,
+		}
+		dialer.NetDialContext = proxy.DialContext
+	}
+	hdr := make(http.Header)
+	hdr.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(cfg.RPCUser+":"+cfg.RPCPass)))
+	conn, resp, err := dialer.DialContext(ctx, "wss://"+cfg.RPCAddr+"/ws", hdr)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("error connecting to the RPC server: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("error connecting to the RPC server: %w", err)
+	}
+	return conn, nil
+}
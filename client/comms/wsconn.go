@@ -10,9 +10,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -21,6 +23,7 @@ import (
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
+	dexws "decred.org/dcrdex/dex/ws"
 	"github.com/gorilla/websocket"
 )
 
@@ -40,6 +43,19 @@ const (
 	// DefaultResponseTimeout is the default timeout for responses after a
 	// request is successfully sent.
 	DefaultResponseTimeout = time.Minute
+
+	// quicHandshakeTimeout is how long to wait for the websocket handshake
+	// over QUIC before falling back to TCP.
+	quicHandshakeTimeout = 10 * time.Second
+
+	// compressionThreshold is the size of the smallest message that is
+	// compressed when compression is negotiated. Smaller messages are not
+	// worth the CPU time.
+	compressionThreshold = 512
+
+	// maxQueuedRequests is the most requests that QueueRequest will hold
+	// while the connection is down.
+	maxQueuedRequests = 64
 )
 
 // ConnectionStatus represents the current status of the websocket connection.
@@ -67,6 +83,10 @@ func (cs ConnectionStatus) String() string {
 
 // invalidCertRegexp is a regexp that helps check for non-typed x509 errors
 // caused by or related to an invalid cert.
+// proxyFromEnvironment is the proxy function of the websocket dialer. It is a
+// variable for testing, since http.ProxyFromEnvironment caches the environment.
+var proxyFromEnvironment = http.ProxyFromEnvironment
+
 var invalidCertRegexp = regexp.MustCompile(".*(unknown authority|not standards compliant|not trusted)")
 
 // isErrorInvalidCert checks if the provided error is one of the different
@@ -87,6 +107,10 @@ var ErrInvalidCert = fmt.Errorf("invalid certificate")
 // cert was provided.
 var ErrCertRequired = fmt.Errorf("certificate required")
 
+// ErrRequestQueueFull is the error returned by QueueRequest when the
+// connection is down and the maximum number of requests are already queued.
+var ErrRequestQueueFull = fmt.Errorf("request queue full")
+
 // WsConn is an interface for a websocket client.
 type WsConn interface {
 	NextID() uint64
@@ -96,6 +120,7 @@ type WsConn interface {
 	Request(msg *msgjson.Message, respHandler func(*msgjson.Message)) error
 	RequestRaw(msgID uint64, rawMsg []byte, respHandler func(*msgjson.Message)) error
 	RequestWithTimeout(msg *msgjson.Message, respHandler func(*msgjson.Message), expireTime time.Duration, expire func()) error
+	QueueRequest(msg *msgjson.Message, respHandler func(*msgjson.Message), expireTime time.Duration, expire func()) error
 	Connect(ctx context.Context) (*sync.WaitGroup, error)
 	MessageSource() <-chan *msgjson.Message
 	UpdateURL(string)
@@ -109,6 +134,18 @@ type responseHandler struct {
 	abort      func() // only to be run at most once, and not if f ran
 }
 
+// queuedRequest is a request that could not be sent because the connection
+// was down. It is sent when the connection is reestablished, unless it expires
+// first.
+type queuedRequest struct {
+	id         uint64
+	rawMsg     []byte
+	f          func(*msgjson.Message)
+	expire     func()
+	deadline   time.Time
+	expiration *time.Timer
+}
+
 // WsCfg is the configuration struct for initializing a WsConn.
 type WsCfg struct {
 	// URL is the websocket endpoint URL.
@@ -149,6 +186,23 @@ type WsCfg struct {
 
 	// EchoPingData will echo any data from pings as the pong data.
 	EchoPingData bool
+
+	// EnableCompression negotiates permessage-deflate compression with the
+	// server, if the server supports it. Compression reduces the bandwidth of
+	// large messages, such as order books, at the cost of CPU time.
+	EnableCompression bool
+
+	// EnableQUIC connects over QUIC to a server that advertises it, falling
+	// back to TCP if the QUIC connection fails. QUIC is advertised when the
+	// connection is made, so it is used for reconnects. QUIC is not used with
+	// a NetDialContext, which can't be assumed to support UDP, or if a proxy
+	// is configured in the environment.
+	EnableQUIC bool
+
+	// EnableCBOR requests the msgjson.CBORSubprotocol, which allows the server
+	// to send high-volume messages, such as order books and candles,
+	// CBOR-encoded. Requests are always JSON-encoded.
+	EnableCBOR bool
 }
 
 // wsConn represents a client websocket connection.
@@ -163,6 +217,9 @@ type wsConn struct {
 	tlsCfg *tls.Config
 	readCh chan *msgjson.Message
 	urlV   atomic.Value // string
+	// quicAddr is the QUIC address last advertised by the server, if
+	// EnableQUIC.
+	quicAddr atomic.Value // string
 
 	wsMtx sync.Mutex
 	ws    *websocket.Conn
@@ -172,6 +229,9 @@ type wsConn struct {
 	reqMtx       sync.RWMutex
 	respHandlers map[uint64]*responseHandler
 
+	queueMtx sync.Mutex
+	queue    []*queuedRequest
+
 	reconnectCh chan struct{} // trigger for immediate reconnect
 }
 
@@ -226,6 +286,38 @@ func (conn *wsConn) url() string {
 	return conn.urlV.Load().(string)
 }
 
+// advertisedQUICAddr is the QUIC address to connect to, or an empty string if
+// the connection should be made over TCP.
+func (conn *wsConn) advertisedQUICAddr() string {
+	if !conn.cfg.EnableQUIC || conn.cfg.NetDialContext != nil || !strings.HasPrefix(conn.url(), "wss:") {
+		return ""
+	}
+	if conn.proxied() {
+		return ""
+	}
+	quicAddr, _ := conn.quicAddr.Load().(string)
+	return quicAddr
+}
+
+// proxied is true if the environment configures a proxy for the connection. A
+// QUIC connection would bypass the proxy, revealing the client's address to
+// the server, so QUIC is not used if there is a proxy.
+func (conn *wsConn) proxied() bool {
+	for _, k := range []string{"ALL_PROXY", "all_proxy"} {
+		if os.Getenv(k) != "" {
+			return true
+		}
+	}
+	u, err := url.Parse(conn.url())
+	if err != nil {
+		return true
+	}
+	// The websocket dialer requests the proxy for the equivalent http URL.
+	u.Scheme = "https"
+	proxyURL, err := proxyFromEnvironment(&http.Request{URL: u})
+	return err != nil || proxyURL != nil
+}
+
 // IsDown indicates if the connection is known to be down.
 func (conn *wsConn) IsDown() bool {
 	return atomic.LoadUint32(&conn.connectionStatus) != uint32(Connected)
@@ -244,16 +336,41 @@ func (conn *wsConn) setConnectionStatus(status ConnectionStatus) {
 // connect attempts to establish a websocket connection.
 func (conn *wsConn) connect(ctx context.Context) error {
 	dialer := &websocket.Dialer{
-		HandshakeTimeout: DefaultResponseTimeout,
-		TLSClientConfig:  conn.tlsCfg,
+		HandshakeTimeout:  DefaultResponseTimeout,
+		TLSClientConfig:   conn.tlsCfg,
+		EnableCompression: conn.cfg.EnableCompression,
+	}
+	if conn.cfg.EnableCBOR {
+		dialer.Subprotocols = []string{msgjson.CBORSubprotocol}
 	}
 	if conn.cfg.NetDialContext != nil {
 		dialer.NetDialContext = conn.cfg.NetDialContext
 	} else {
-		dialer.Proxy = http.ProxyFromEnvironment
+		dialer.Proxy = proxyFromEnvironment
+	}
+
+	var ws *websocket.Conn
+	var resp *http.Response
+	var err error
+	if quicAddr := conn.advertisedQUICAddr(); quicAddr != "" {
+		quicDialer := *dialer
+		quicDialer.Proxy = nil
+		quicDialer.HandshakeTimeout = quicHandshakeTimeout
+		quicDialer.NetDialTLSContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dexws.DialQUIC(ctx, quicAddr, conn.tlsCfg)
+		}
+		ws, resp, err = quicDialer.DialContext(ctx, conn.url(), conn.cfg.ConnectHeaders)
+		if err != nil {
+			// Don't try QUIC again unless the server advertises it again.
+			conn.quicAddr.Store("")
+			conn.log.Infof("QUIC connection to %s failed. Falling back to TCP: %v", quicAddr, err)
+		} else {
+			conn.log.Debugf("Connected to %s over QUIC", quicAddr)
+		}
+	}
+	if ws == nil {
+		ws, resp, err = dialer.DialContext(ctx, conn.url(), conn.cfg.ConnectHeaders)
 	}
-
-	ws, _, err := dialer.DialContext(ctx, conn.url(), conn.cfg.ConnectHeaders)
 	if err != nil {
 		if isErrorInvalidCert(err) {
 			conn.setConnectionStatus(InvalidCert)
@@ -266,6 +383,12 @@ func (conn *wsConn) connect(ctx context.Context) error {
 		return err
 	}
 
+	if conn.cfg.EnableQUIC && resp != nil {
+		if u, err := url.Parse(conn.url()); err == nil {
+			conn.quicAddr.Store(dexws.QUICAltSvcAddr(u.Hostname(), resp.Header))
+		}
+	}
+
 	// Set the initial read deadline for the first ping. Subsequent read
 	// deadlines are set in the ping handler.
 	err = ws.SetReadDeadline(time.Now().Add(conn.cfg.PingWait))
@@ -312,7 +435,7 @@ func (conn *wsConn) connect(ctx context.Context) error {
 	conn.wsMtx.Unlock()
 
 	conn.setConnectionStatus(Connected)
-	conn.wg.Add(1)
+	conn.wg.Add(2)
 	go func() {
 		defer conn.wg.Done()
 		if conn.cfg.RawHandler != nil {
@@ -321,6 +444,10 @@ func (conn *wsConn) connect(ctx context.Context) error {
 			conn.read(ctx)
 		}
 	}()
+	go func() {
+		defer conn.wg.Done()
+		conn.sendQueued()
+	}()
 
 	return nil
 }
@@ -406,12 +533,42 @@ func (conn *wsConn) readRaw(ctx context.Context) {
 	}
 }
 
+// errCBORDecode wraps the error decoding a CBOR-encoded message.
+var errCBORDecode = errors.New("cbor decode error")
+
+// readMessage reads the next message from the websocket connection. Messages
+// are JSON-encoded in text frames, or CBOR-encoded in binary frames if the
+// server selected the msgjson.CBORSubprotocol.
+func readMessage(ws *websocket.Conn) (*msgjson.Message, error) {
+	msgType, r, err := ws.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	if msgType == websocket.BinaryMessage && ws.Subprotocol() == msgjson.CBORSubprotocol {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := msgjson.DecodeCBORMessage(b)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errCBORDecode, err)
+		}
+		return msg, nil
+	}
+	// Same as (*websocket.Conn).ReadJSON.
+	msg := new(msgjson.Message)
+	err = json.NewDecoder(r).Decode(msg)
+	if errors.Is(err, io.EOF) {
+		// A message with no data is an unexpected EOF.
+		err = io.ErrUnexpectedEOF
+	}
+	return msg, err
+}
+
 // read fetches and parses incoming messages for processing. This should be
 // run as a goroutine. Increment the wg before calling read.
 func (conn *wsConn) read(ctx context.Context) {
 	for {
-		msg := new(msgjson.Message)
-
 		// Lock since conn.ws may be set by connect.
 		conn.wsMtx.Lock()
 		ws := conn.ws
@@ -419,7 +576,7 @@ func (conn *wsConn) read(ctx context.Context) {
 
 		// The read itself does not require locking since only this goroutine
 		// uses read functions that are not safe for concurrent use.
-		err := ws.ReadJSON(msg)
+		msg, err := readMessage(ws)
 		// Drop the read error on context cancellation.
 		if ctx.Err() != nil {
 			return
@@ -431,6 +588,10 @@ func (conn *wsConn) read(ctx context.Context) {
 				conn.log.Errorf("json decode error: %v", mErr)
 				continue
 			}
+			if errors.Is(err, errCBORDecode) {
+				conn.log.Error(err)
+				continue
+			}
 			conn.handleReadError(err)
 			return
 		}
@@ -439,8 +600,7 @@ func (conn *wsConn) read(ctx context.Context) {
 		if msg.Type == msgjson.Response {
 			handler := conn.respHandler(msg.ID)
 			if handler == nil {
-				b, _ := json.Marshal(msg)
-				conn.log.Errorf("No handler found for response: %v", string(b))
+				conn.log.Errorf("No handler found for response: %v", msg)
 				continue
 			}
 			// Run handlers in a goroutine so that other messages can be
@@ -607,6 +767,8 @@ func (conn *wsConn) SendRaw(b []byte) error {
 		return err
 	}
 
+	// This is a no-op if compression was not negotiated.
+	conn.ws.EnableWriteCompression(len(b) >= compressionThreshold)
 	err = conn.ws.WriteMessage(websocket.TextMessage, b)
 	if err != nil {
 		conn.log.Errorf("Send: WriteMessage error: %v", err)
@@ -684,6 +846,110 @@ func (conn *wsConn) RequestRawWithTimeout(msgID uint64, rawMsg []byte, f func(*m
 	return err
 }
 
+// QueueRequest is like RequestWithTimeout, but is intended for idempotent
+// requests that are not time-critical. If the connection is down, or the
+// request can't be sent, the request is queued and sent when the connection
+// is reestablished instead of returning an error. expireTime includes any time
+// spent in the queue, and expire is called if the request is still queued when
+// it expires. An error is returned only if the request is invalid or the queue
+// is full, in which case neither function will run.
+func (conn *wsConn) QueueRequest(msg *msgjson.Message, f func(*msgjson.Message), expireTime time.Duration, expire func()) error {
+	if msg.Type != msgjson.Request {
+		return fmt.Errorf("Message is not a request: %v", msg.Type)
+	}
+	rawMsg, err := json.Marshal(msg)
+	if err != nil {
+		conn.log.Errorf("Failed to marshal message: %v", err)
+		return err
+	}
+	if !conn.IsDown() {
+		err = conn.RequestRawWithTimeout(msg.ID, rawMsg, f, expireTime, expire)
+		if err == nil {
+			return nil
+		}
+		conn.log.Debugf("Queueing '%s' request (ID %d) that could not be sent: %v", msg.Route, msg.ID, err)
+	}
+	return conn.enqueue(&queuedRequest{
+		id:       msg.ID,
+		rawMsg:   rawMsg,
+		f:        f,
+		expire:   expire,
+		deadline: time.Now().Add(expireTime),
+	})
+}
+
+// enqueue adds the request to the queue and starts its expiration timer.
+func (conn *wsConn) enqueue(req *queuedRequest) error {
+	conn.queueMtx.Lock()
+	defer conn.queueMtx.Unlock()
+	if len(conn.queue) >= maxQueuedRequests {
+		return ErrRequestQueueFull
+	}
+	req.expiration = time.AfterFunc(time.Until(req.deadline), func() {
+		if conn.dequeue(req.id) {
+			req.expire()
+		}
+	})
+	conn.queue = append(conn.queue, req)
+	return nil
+}
+
+// dequeue removes the request with the provided ID from the queue, returning
+// true if it was found.
+func (conn *wsConn) dequeue(id uint64) bool {
+	conn.queueMtx.Lock()
+	defer conn.queueMtx.Unlock()
+	for i, req := range conn.queue {
+		if req.id == id {
+			conn.queue = append(conn.queue[:i], conn.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// sendQueued sends the requests that were queued while the connection was
+// down. Requests that can't be sent are queued again.
+func (conn *wsConn) sendQueued() {
+	conn.queueMtx.Lock()
+	var reqs []*queuedRequest
+	remaining := conn.queue[:0]
+	for _, req := range conn.queue {
+		// If the timer has already fired, leave the request for the timer
+		// func to dequeue and expire.
+		if req.expiration.Stop() {
+			reqs = append(reqs, req)
+		} else {
+			remaining = append(remaining, req)
+		}
+	}
+	conn.queue = remaining
+	conn.queueMtx.Unlock()
+
+	if len(reqs) > 0 {
+		conn.log.Debugf("Sending %d queued requests", len(reqs))
+	}
+	for i, req := range reqs {
+		timeout := time.Until(req.deadline)
+		if timeout <= 0 {
+			req.expire()
+			continue
+		}
+		err := conn.RequestRawWithTimeout(req.id, req.rawMsg, req.f, timeout, req.expire)
+		if err == nil {
+			continue
+		}
+		// The connection is probably down again.
+		conn.log.Debugf("Error sending queued request (ID %d): %v", req.id, err)
+		for _, req := range reqs[i:] {
+			if err := conn.enqueue(req); err != nil {
+				req.expire()
+			}
+		}
+		return
+	}
+}
+
 func (conn *wsConn) expire(id uint64) bool {
 	conn.reqMtx.Lock()
 	defer conn.reqMtx.Unlock()
@@ -4,13 +4,18 @@ import (
 	"bytes"
 	"context"
 	"crypto/elliptic"
+	"crypto/tls"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -18,6 +23,7 @@ import (
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
+	dexws "decred.org/dcrdex/dex/ws"
 	"github.com/decred/dcrd/certgen"
 	"github.com/gorilla/websocket"
 )
@@ -486,3 +492,343 @@ func TestWsConn(t *testing.T) {
 		t.Error("read source should have been closed")
 	}
 }
+
+func TestWsConnCompression(t *testing.T) {
+	type received struct {
+		negotiated bool
+		msg        []byte
+	}
+	recv := make(chan *received, 1)
+	upgrader := websocket.Upgrader{EnableCompression: true}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		negotiated := strings.Contains(r.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("unable to upgrade http connection: %v", err)
+			return
+		}
+		defer c.Close()
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			t.Errorf("read error: %v", err)
+		}
+		recv <- &received{negotiated, msg}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	large := bytes.Repeat([]byte("a"), compressionThreshold)
+	for _, compress := range []bool{true, false} {
+		cl, err := NewWsConn(&WsCfg{
+			URL:                  "ws" + strings.TrimPrefix(srv.URL, "http"),
+			PingWait:             time.Minute,
+			Logger:               tLogger,
+			DisableAutoReconnect: true,
+			EnableCompression:    compress,
+		})
+		if err != nil {
+			t.Fatalf("NewWsConn error: %v", err)
+		}
+		cm := dex.NewConnectionMaster(cl)
+		if err := cm.ConnectOnce(ctx); err != nil {
+			t.Fatalf("connect error: %v", err)
+		}
+		if err := cl.SendRaw(large); err != nil {
+			t.Fatalf("SendRaw error: %v", err)
+		}
+		r := <-recv
+		if r.negotiated != compress {
+			t.Fatalf("compression negotiated = %t, expected %t", r.negotiated, compress)
+		}
+		if !bytes.Equal(r.msg, large) {
+			t.Fatalf("wrong message received")
+		}
+		cm.Disconnect()
+	}
+}
+
+func TestWsConnCBOR(t *testing.T) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{msgjson.CBORSubprotocol}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("unable to upgrade http connection: %v", err)
+			return
+		}
+		defer c.Close()
+		note := &msgjson.UnbookOrderNote{Seq: 5, MarketID: "dcr_btc"}
+		var msgType int
+		var b []byte
+		if c.Subprotocol() == msgjson.CBORSubprotocol {
+			msg, _ := msgjson.NewNotificationCBOR(msgjson.UnbookOrderRoute, note)
+			msgType = websocket.BinaryMessage
+			b, err = msg.EncodeCBOR()
+		} else {
+			msg, _ := msgjson.NewNotification(msgjson.UnbookOrderRoute, note)
+			msgType = websocket.TextMessage
+			b, err = json.Marshal(msg)
+		}
+		if err != nil {
+			t.Errorf("encode error: %v", err)
+			return
+		}
+		if err := c.WriteMessage(msgType, b); err != nil {
+			t.Errorf("write error: %v", err)
+		}
+		c.ReadMessage() // wait for the client to disconnect
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, enable := range []bool{true, false} {
+		cl, err := NewWsConn(&WsCfg{
+			URL:                  "ws" + strings.TrimPrefix(srv.URL, "http"),
+			PingWait:             time.Minute,
+			Logger:               tLogger,
+			DisableAutoReconnect: true,
+			EnableCBOR:           enable,
+		})
+		if err != nil {
+			t.Fatalf("NewWsConn error: %v", err)
+		}
+		cm := dex.NewConnectionMaster(cl)
+		if err := cm.ConnectOnce(ctx); err != nil {
+			t.Fatalf("connect error: %v", err)
+		}
+		var msg *msgjson.Message
+		select {
+		case msg = <-cl.MessageSource():
+		case <-time.After(time.Second):
+			t.Fatalf("no message received")
+		}
+		if msg.IsCBOR() != enable {
+			t.Fatalf("message CBOR = %t, expected %t", msg.IsCBOR(), enable)
+		}
+		note := new(msgjson.UnbookOrderNote)
+		if err := msg.Unmarshal(note); err != nil {
+			t.Fatalf("unmarshal error: %v", err)
+		}
+		if msg.Route != msgjson.UnbookOrderRoute || note.Seq != 5 || note.MarketID != "dcr_btc" {
+			t.Fatalf("wrong message received: %s", msg)
+		}
+		cm.Disconnect()
+	}
+}
+
+func TestWsConnQUIC(t *testing.T) {
+	certB, keyB, err := certgen.NewTLSCertPair(elliptic.P256(), "dcrdex test cert", time.Now().Add(time.Hour), nil)
+	if err != nil {
+		t.Fatalf("error generating cert: %v", err)
+	}
+	keypair, err := tls.X509KeyPair(certB, keyB)
+	if err != nil {
+		t.Fatalf("error loading cert: %v", err)
+	}
+	tlsCfg := &tls.Config{Certificates: []tls.Certificate{keypair}}
+	tcpListener, err := tls.Listen("tcp", "127.0.0.1:0", tlsCfg)
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	quicListener, err := dexws.ListenQUIC("udp", tcpListener.Addr().String(), tlsCfg)
+	if err != nil {
+		t.Fatalf("ListenQUIC error: %v", err)
+	}
+	_, port, _ := net.SplitHostPort(quicListener.Addr().String())
+
+	// The server reports the network of each connection.
+	networks := make(chan string, 1)
+	upgrader := websocket.Upgrader{}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, http.Header{"Alt-Svc": {dexws.QUICAltSvc(port)}})
+		if err != nil {
+			t.Errorf("unable to upgrade http connection: %v", err)
+			return
+		}
+		networks <- r.Context().Value(http.LocalAddrContextKey).(net.Addr).Network()
+		for {
+			if _, _, err := c.ReadMessage(); err != nil {
+				c.Close()
+				return
+			}
+		}
+	})}
+	var wg sync.WaitGroup
+	for _, l := range []net.Listener{tcpListener, quicListener} {
+		wg.Add(1)
+		go func(l net.Listener) {
+			defer wg.Done()
+			srv.Serve(l)
+		}(l)
+	}
+	defer wg.Wait()
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cl, err := NewWsConn(&WsCfg{
+		URL:                  "wss://" + tcpListener.Addr().String() + "/ws",
+		PingWait:             time.Minute,
+		Cert:                 certB,
+		Logger:               tLogger,
+		DisableAutoReconnect: true,
+		EnableQUIC:           true,
+	})
+	if err != nil {
+		t.Fatalf("NewWsConn error: %v", err)
+	}
+	conn := cl.(*wsConn)
+	checkNetwork := func(exp string) {
+		t.Helper()
+		select {
+		case network := <-networks:
+			if network != exp {
+				t.Fatalf("connected over %s, expected %s", network, exp)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("no connection")
+		}
+	}
+
+	// The first connection is over TCP, and QUIC is advertised.
+	if _, err := conn.Connect(ctx); err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+	checkNetwork("tcp")
+	if conn.advertisedQUICAddr() == "" {
+		t.Fatalf("QUIC address not advertised")
+	}
+
+	// Reconnects are over QUIC.
+	if err := conn.connect(ctx); err != nil {
+		t.Fatalf("QUIC connect error: %v", err)
+	}
+	checkNetwork("udp")
+
+	// QUIC would bypass a proxy, so it is not used with one.
+	proxyURL, _ := url.Parse("http://127.0.0.1:8080")
+	proxyFromEnvironment = func(*http.Request) (*url.URL, error) { return proxyURL, nil }
+	proxied := conn.advertisedQUICAddr() != ""
+	proxyFromEnvironment = http.ProxyFromEnvironment
+	if proxied {
+		t.Fatalf("QUIC used with a proxy")
+	}
+
+	// Without QUIC, reconnects fall back to TCP.
+	quicListener.Close()
+	if err := conn.connect(ctx); err != nil {
+		t.Fatalf("fallback connect error: %v", err)
+	}
+	checkNetwork("tcp")
+}
+
+func TestQueueRequest(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("unable to upgrade http connection: %v", err)
+			return
+		}
+		defer c.Close()
+		for {
+			_, b, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			req, err := msgjson.DecodeMessage(b)
+			if err != nil {
+				t.Errorf("error decoding request: %v", err)
+				return
+			}
+			resp, _ := msgjson.NewResponse(req.ID, req.Route, nil)
+			if err := c.WriteJSON(resp); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	cl, err := NewWsConn(&WsCfg{
+		URL:                  "ws" + strings.TrimPrefix(srv.URL, "http"),
+		PingWait:             time.Minute,
+		Logger:               tLogger,
+		DisableAutoReconnect: true,
+	})
+	if err != nil {
+		t.Fatalf("NewWsConn error: %v", err)
+	}
+	conn := cl.(*wsConn)
+
+	// Requests made while the connection is down are queued.
+	responses := make(chan string, maxQueuedRequests)
+	expired := make(chan string, maxQueuedRequests)
+	queue := func(route string, timeout time.Duration) error {
+		return conn.QueueRequest(makeRequest(conn.NextID(), route, nil), func(*msgjson.Message) {
+			responses <- route
+		}, timeout, func() {
+			expired <- route
+		})
+	}
+	if err := queue("expires", 50*time.Millisecond); err != nil {
+		t.Fatalf("QueueRequest error: %v", err)
+	}
+	for i := 1; i < maxQueuedRequests; i++ {
+		if err := queue("replayed", time.Minute); err != nil {
+			t.Fatalf("QueueRequest error: %v", err)
+		}
+	}
+
+	// The queue is bounded.
+	if err := queue("rejected", time.Minute); !errors.Is(err, ErrRequestQueueFull) {
+		t.Fatalf("expected ErrRequestQueueFull, got %v", err)
+	}
+
+	// Queued requests expire.
+	select {
+	case route := <-expired:
+		if route != "expires" {
+			t.Fatalf("wrong request expired: %s", route)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("queued request did not expire")
+	}
+
+	// Queued requests are sent when the connection is made.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cm := dex.NewConnectionMaster(cl)
+	if err := cm.ConnectOnce(ctx); err != nil {
+		t.Fatalf("connect error: %v", err)
+	}
+	defer cm.Disconnect()
+	for i := 1; i < maxQueuedRequests; i++ {
+		select {
+		case route := <-responses:
+			if route != "replayed" {
+				t.Fatalf("wrong response route: %s", route)
+			}
+		case route := <-expired:
+			t.Fatalf("request %s expired", route)
+		case <-time.After(time.Second * 5):
+			t.Fatalf("no response to queued request %d", i)
+		}
+	}
+
+	// Requests are sent immediately while connected.
+	if err := queue("sent", time.Minute); err != nil {
+		t.Fatalf("QueueRequest error: %v", err)
+	}
+	select {
+	case route := <-responses:
+		if route != "sent" {
+			t.Fatalf("wrong response route: %s", route)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("no response to request")
+	}
+	if len(conn.queue) != 0 {
+		t.Fatalf("%d requests still queued", len(conn.queue))
+	}
+}
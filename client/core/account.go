@@ -10,6 +10,8 @@ import (
 	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/keygen"
+	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/server/account"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
@@ -285,6 +287,33 @@ func (c *Core) AccountImport(pw []byte, acct *Account, bonds []*db.Bond) error {
 	return nil
 }
 
+// ReputationBreakdown requests a breakdown of the account's score from the
+// server. The breakdown lists the recent swap outcomes, preimage misses, and
+// cancellation rate that make up the score, so that tier changes can be
+// explained. The account must be logged in.
+func (c *Core) ReputationBreakdown(host string) (*msgjson.ReputationResult, error) {
+	dc, connected, err := c.dex(host)
+	if err != nil {
+		return nil, err
+	}
+	if !connected || !dc.acct.authed() {
+		return nil, fmt.Errorf("not logged in to %s", dc.acct.host)
+	}
+	if !dc.capabilities().Has(msgjson.CapReputation) {
+		return nil, fmt.Errorf("%s does not provide reputation breakdowns", dc.acct.host)
+	}
+	res := new(msgjson.ReputationResult)
+	if err := sendRequest(dc.WsConn, msgjson.ReputationRoute, nil, res, DefaultResponseTimeout); err != nil {
+		return nil, fmt.Errorf("error requesting reputation breakdown from %s: %w", dc.acct.host, err)
+	}
+	if res.Reputation != nil {
+		dc.acct.authMtx.Lock()
+		dc.updateReputation(res.Reputation)
+		dc.acct.authMtx.Unlock()
+	}
+	return res, nil
+}
+
 // UpdateCert attempts to connect to a server using a new TLS certificate. If
 // the connection is successful, then the cert in the database is updated.
 // Updating cert for already connected dex will return an error.
@@ -314,6 +343,7 @@ func (c *Core) UpdateCert(host string, cert []byte) error {
 		dc.booksMtx.Lock()
 		for m, b := range dc.books {
 			b.closeFeeds()
+			b.stopRecording()
 			if b.closeTimer != nil {
 				b.closeTimer.Stop()
 			}
@@ -402,3 +432,96 @@ func (c *Core) UpdateDEXHost(oldHost, newHost string, appPW []byte, certI any) (
 	updatedHost = true
 	return c.exchangeInfo(newDc), nil
 }
+
+// maxRecoveryKeys is the most keys of each kind that RecoveryKeys derives.
+const maxRecoveryKeys = 1000
+
+// RecoveryKeys derives the DEX account keys and bond keys from the app seed,
+// so that funds locked in bonds can be recovered with external tools if the
+// client database is lost. The seed does not need to be the seed of this app.
+// The account keys are derived from the DEX's public key, which is retrieved
+// from the DEX if there is no connection to it. The derived keys are not
+// stored.
+func (c *Core) RecoveryKeys(form *RecoveryKeysForm) (*RecoveryKeys, error) {
+	if form.AcctKeys > maxRecoveryKeys || form.BondKeys > maxRecoveryKeys {
+		return nil, fmt.Errorf("cannot derive more than %d keys of a kind", maxRecoveryKeys)
+	}
+	seed, _, err := decodeSeedString(form.Seed)
+	if err != nil {
+		return nil, err
+	}
+	defer encode.ClearBytes(seed)
+
+	keys := &RecoveryKeys{
+		Accounts: make([]*RecoveryAccountKey, 0, len(form.Hosts)*int(form.AcctKeys)),
+		Bonds:    make([]*RecoveryBondKey, 0, len(form.BondAssets)*int(form.BondKeys)),
+	}
+	for _, addr := range form.Hosts {
+		host, err := addrHost(addr)
+		if err != nil {
+			return nil, newError(addressParseErr, "error parsing address: %w", err)
+		}
+		dexPubKey, err := c.dexPubKey(host)
+		if err != nil {
+			return nil, err
+		}
+		dexPkB := dexPubKey.SerializeCompressed()
+		for keyIndex := uint32(0); keyIndex < form.AcctKeys; keyIndex++ {
+			priv, err := keygen.AccountKey(seed, dexPkB, keyIndex)
+			if err != nil {
+				return nil, fmt.Errorf("error deriving %s account key %d: %w", host, keyIndex, err)
+			}
+			keys.Accounts = append(keys.Accounts, &RecoveryAccountKey{
+				Host:      host,
+				DEXPubKey: dexPkB,
+				KeyIndex:  keyIndex,
+				AccountID: account.NewID(priv.PubKey().SerializeCompressed()).String(),
+				PrivKey:   priv.Serialize(),
+			})
+		}
+	}
+
+	if len(form.BondAssets) == 0 || form.BondKeys == 0 {
+		return keys, nil
+	}
+	bondXPriv, err := keygen.BondXPriv(seed)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving bond extended key: %w", err)
+	}
+	defer bondXPriv.Zero()
+	for _, assetID := range form.BondAssets {
+		for bondIndex := uint32(0); bondIndex < form.BondKeys; bondIndex++ {
+			priv, err := keygen.BondKey(bondXPriv, assetID, bondIndex)
+			if err != nil {
+				return nil, fmt.Errorf("error deriving %s bond key %d: %w", unbip(assetID), bondIndex, err)
+			}
+			keys.Bonds = append(keys.Bonds, &RecoveryBondKey{
+				AssetID:   assetID,
+				Symbol:    unbip(assetID),
+				BondIndex: bondIndex,
+				PubKey:    priv.PubKey().SerializeCompressed(),
+				PrivKey:   priv.Serialize(),
+			})
+		}
+	}
+	return keys, nil
+}
+
+// dexPubKey gets the public key of the DEX at host, connecting to the DEX if
+// there is no connection to it.
+func (c *Core) dexPubKey(host string) (*secp256k1.PublicKey, error) {
+	c.connMtx.RLock()
+	dc, found := c.conns[host]
+	c.connMtx.RUnlock()
+	if !found {
+		var err error
+		if dc, err = c.tempDexConnection(host, nil); err != nil {
+			return nil, err
+		}
+		defer dc.connMaster.Disconnect()
+	}
+	if dc.acct.dexPubKey == nil {
+		return nil, fmt.Errorf("no public key for the DEX at %s", host)
+	}
+	return dc.acct.dexPubKey, nil
+}
@@ -509,3 +509,65 @@ func TestAccountImportAccountCreateAccountError(t *testing.T) {
 		t.Fatalf("expected db error, actual error: '%v'", err)
 	}
 }
+
+func TestRecoveryKeys(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	seed := encode.RandomBytes(legacySeedLength)
+	form := &RecoveryKeysForm{
+		Seed:       hex.EncodeToString(seed),
+		Hosts:      []string{tDexHost},
+		AcctKeys:   2,
+		BondAssets: []uint32{tUTXOAssetA.ID, tUTXOAssetB.ID},
+		BondKeys:   3,
+	}
+	keys, err := tCore.RecoveryKeys(form)
+	if err != nil {
+		t.Fatalf("RecoveryKeys error: %v", err)
+	}
+	if len(keys.Accounts) != 2 || len(keys.Bonds) != 6 {
+		t.Fatalf("expected 2 account keys and 6 bond keys, got %d and %d", len(keys.Accounts), len(keys.Bonds))
+	}
+
+	// The account keys are the keys the account would be set up with.
+	for i, k := range keys.Accounts {
+		acct := newDEXAccount(&db.AccountInfo{Host: tDexHost, DEXPubKey: rig.acct.dexPubKey}, false)
+		creds := &db.PrimaryCredentials{EncSeed: bytes.Clone(seed)}
+		if err := acct.setupCryptoV2(creds, rig.crypter, uint32(i)); err != nil {
+			t.Fatalf("setupCryptoV2 error: %v", err)
+		}
+		if k.KeyIndex != uint32(i) || k.Host != tDexHost {
+			t.Fatalf("wrong account key %d: %+v", i, k)
+		}
+		if !bytes.Equal(k.PrivKey, acct.privKey.Serialize()) || k.AccountID != acct.id.String() {
+			t.Fatalf("account key %d does not match the account", i)
+		}
+	}
+
+	// The bond keys are the keys bonds would be posted with.
+	bondXPriv, err := deriveBondXPriv(seed)
+	if err != nil {
+		t.Fatalf("deriveBondXPriv error: %v", err)
+	}
+	for _, k := range keys.Bonds {
+		priv, err := deriveBondKey(bondXPriv, k.AssetID, k.BondIndex)
+		if err != nil {
+			t.Fatalf("deriveBondKey error: %v", err)
+		}
+		if !bytes.Equal(k.PrivKey, priv.Serialize()) {
+			t.Fatalf("%s bond key %d does not match", k.Symbol, k.BondIndex)
+		}
+	}
+
+	form.BondKeys = maxRecoveryKeys + 1
+	if _, err := tCore.RecoveryKeys(form); err == nil {
+		t.Fatalf("no error for too many keys")
+	}
+	form.BondKeys = 1
+	form.Seed = "not a seed"
+	if _, err := tCore.RecoveryKeys(form); err == nil {
+		t.Fatalf("no error for an invalid seed")
+	}
+}
@@ -1083,24 +1083,11 @@ func (c *Core) RedeemPrepaidBond(appPW []byte, code []byte, host string, certI a
 }
 
 func deriveBondKey(bondXPriv *hdkeychain.ExtendedKey, assetID, bondIndex uint32) (*secp256k1.PrivateKey, error) {
-	kids := []uint32{
-		assetID + hdkeychain.HardenedKeyStart,
-		bondIndex,
-	}
-	extKey, err := keygen.GenDeepChildFromXPriv(bondXPriv, kids)
-	if err != nil {
-		return nil, fmt.Errorf("GenDeepChild error: %w", err)
-	}
-	privB, err := extKey.SerializedPrivKey()
-	if err != nil {
-		return nil, fmt.Errorf("SerializedPrivKey error: %w", err)
-	}
-	priv := secp256k1.PrivKeyFromBytes(privB)
-	return priv, nil
+	return keygen.BondKey(bondXPriv, assetID, bondIndex)
 }
 
 func deriveBondXPriv(seed []byte) (*hdkeychain.ExtendedKey, error) {
-	return keygen.GenDeepChild(seed, []uint32{hdKeyPurposeBonds})
+	return keygen.BondXPriv(seed)
 }
 
 func (c *Core) bondKeyIdx(assetID, idx uint32) (*secp256k1.PrivateKey, error) {
@@ -6,6 +6,9 @@ package core
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,6 +32,10 @@ var (
 	outdatedClientErr = errors.New("outdated client")
 )
 
+// bookSnapshotInterval is how often a recorded book is snapshotted, so that a
+// replay only needs to apply the updates since the last snapshot.
+const bookSnapshotInterval = 10 * time.Minute
+
 // BookFeed manages a channel for receiving order book updates. It is imperative
 // that the feeder (BookFeed).Close() when no longer using the feed.
 type BookFeed interface {
@@ -57,7 +64,9 @@ func (f *bookFeed) Close() {
 }
 
 // Candles subscribes to the candlestick duration and sends the initial set
-// of sticks over the update channel.
+// of sticks over the update channel. Durations that the server does not provide
+// are aggregated from a server-provided duration that divides them evenly, e.g.
+// "4h" or "3d".
 func (f *bookFeed) Candles(durStr string) error {
 	return f.bookie.candles(durStr, f.id)
 }
@@ -101,9 +110,16 @@ func (c *candleCache) addCandle(msgCandle *msgjson.Candle) (recent msgjson.Candl
 // supplied close() callback.
 type bookie struct {
 	*orderbook.OrderBook
-	dc           *dexConnection
-	candleCaches map[string]*candleCache
-	log          dex.Logger
+	dc  *dexConnection
+	log dex.Logger
+
+	// binSizes are the server-provided candle durations.
+	binSizes []string
+	// candleCaches are keyed by candle duration. Besides the server-provided
+	// durations, candleCaches has an entry for every aggregated duration that
+	// has been requested.
+	candleCachesMtx sync.RWMutex
+	candleCaches    map[string]*candleCache
 
 	feedsMtx sync.RWMutex
 	feeds    map[uint32]*bookFeed
@@ -113,6 +129,13 @@ type bookie struct {
 
 	base, quote           uint32
 	baseUnits, quoteUnits dex.UnitInfo
+
+	// resyncing is set while the book is being rebuilt after failing
+	// checksum validation.
+	resyncing atomic.Bool
+
+	// recorder is set if the book is being recorded.
+	recorder *orderbook.Recorder
 }
 
 func defaultUnitInfo(symbol string) dex.UnitInfo {
@@ -163,6 +186,7 @@ func newBookie(dc *dexConnection, base, quote uint32, binSizes []string, logger
 	return &bookie{
 		OrderBook:    orderbook.NewOrderBook(logger.SubLogger("book")),
 		dc:           dc,
+		binSizes:     binSizes,
 		candleCaches: candleCaches,
 		log:          logger,
 		feeds:        make(map[uint32]*bookFeed, 1),
@@ -195,19 +219,20 @@ func (b *bookie) logEpochReport(note *msgjson.EpochReportNote) error {
 		},
 	})
 
+	b.candleCachesMtx.RLock()
+	defer b.candleCachesMtx.RUnlock()
 	for durStr, cache := range b.candleCaches {
 		c, ok := cache.addCandle(&note.Candle)
 		if !ok {
 			continue
 		}
-		dur, _ := time.ParseDuration(durStr)
 		b.send(&BookUpdate{
 			Action:   CandleUpdateAction,
 			Host:     b.dc.acct.host,
 			MarketID: marketID,
 			Payload: CandleUpdate{
 				Dur:          durStr,
-				DurMilliSecs: uint64(dur.Milliseconds()),
+				DurMilliSecs: cache.BinSize,
 				// Providing a copy of msgjson.Candle data here since it will be used concurrently.
 				Candle: &c,
 			},
@@ -256,40 +281,48 @@ func (b *bookie) closeFeeds() {
 
 }
 
-// candles fetches the candle set from the server and activates the candle
-// cache.
+// candles activates the candle cache for the duration and sends its candles
+// to the feed.
 func (b *bookie) candles(durStr string, feedID uint32) error {
+	b.candleCachesMtx.RLock()
 	cache := b.candleCaches[durStr]
-	if cache == nil {
-		return fmt.Errorf("no candles for %s-%s %q", unbip(b.base), unbip(b.quote), durStr)
-	}
+	b.candleCachesMtx.RUnlock()
 	var err error
-	defer func() {
-		if err != nil {
-			return
-		}
-		b.feedsMtx.RLock()
-		defer b.feedsMtx.RUnlock()
-		f, ok := b.feeds[feedID]
-		if !ok {
-			// Feed must have been closed in another thread.
-			return
-		}
-		dur, _ := time.ParseDuration(durStr)
-		cache.candleMtx.RLock()
-		cdls := cache.CandlesCopy()
-		cache.candleMtx.RUnlock()
-		f.c <- &BookUpdate{
-			Action:   FreshCandlesAction,
-			Host:     b.dc.acct.host,
-			MarketID: marketName(b.base, b.quote),
-			Payload: &CandlesPayload{
-				Dur:          durStr,
-				DurMilliSecs: uint64(dur.Milliseconds()),
-				Candles:      cdls,
-			},
-		}
-	}()
+	if cache != nil {
+		err = b.activateCandles(durStr, cache)
+	} else {
+		cache, err = b.aggregatedCandles(durStr)
+	}
+	if err != nil {
+		return err
+	}
+
+	b.feedsMtx.RLock()
+	defer b.feedsMtx.RUnlock()
+	f, ok := b.feeds[feedID]
+	if !ok {
+		// Feed must have been closed in another thread.
+		return nil
+	}
+	cache.candleMtx.RLock()
+	cdls := cache.CandlesCopy()
+	cache.candleMtx.RUnlock()
+	f.c <- &BookUpdate{
+		Action:   FreshCandlesAction,
+		Host:     b.dc.acct.host,
+		MarketID: marketName(b.base, b.quote),
+		Payload: &CandlesPayload{
+			Dur:          durStr,
+			DurMilliSecs: cache.BinSize,
+			Candles:      cdls,
+		},
+	}
+	return nil
+}
+
+// activateCandles fetches the candle set for a server-provided duration from
+// the server and activates the candle cache, if it is not already active.
+func (b *bookie) activateCandles(durStr string, cache *candleCache) error {
 	if atomic.LoadUint32(&cache.on) == 1 {
 		return nil
 	}
@@ -301,7 +334,7 @@ func (b *bookie) candles(durStr string, feedID uint32) error {
 		NumCandles: candles.CacheSize,
 	}
 	wireCandles := new(msgjson.WireCandles)
-	err = sendRequest(b.dc.WsConn, msgjson.CandlesRoute, payload, wireCandles, DefaultResponseTimeout)
+	err := sendRequest(b.dc.WsConn, msgjson.CandlesRoute, payload, wireCandles, DefaultResponseTimeout)
 	if err != nil {
 		return err
 	}
@@ -310,6 +343,50 @@ func (b *bookie) candles(durStr string, feedID uint32) error {
 	return nil
 }
 
+// aggregatedCandles creates an active candle cache for a duration that the
+// server does not provide, from the candles of a server-provided duration that
+// divides it evenly. The new cache is updated with epoch reports like the
+// others.
+func (b *bookie) aggregatedCandles(durStr string) (*candleCache, error) {
+	dur, err := candles.ParseBinSize(durStr)
+	if err != nil {
+		return nil, fmt.Errorf("no candles for %s-%s %q: %w", unbip(b.base), unbip(b.quote), durStr, err)
+	}
+	srcDur, err := candles.SourceBinSize(b.binSizes, dur)
+	if err != nil {
+		return nil, fmt.Errorf("no candles for %s-%s %q: %w", unbip(b.base), unbip(b.quote), durStr, err)
+	}
+	b.candleCachesMtx.RLock()
+	src := b.candleCaches[srcDur]
+	b.candleCachesMtx.RUnlock()
+	if src == nil { // binSizes with unparseable durations
+		return nil, fmt.Errorf("no candles for %s-%s %q", unbip(b.base), unbip(b.quote), srcDur)
+	}
+	if err := b.activateCandles(srcDur, src); err != nil {
+		return nil, err
+	}
+
+	// Epoch reports are blocked while the candles are copied and the new
+	// cache is added, so the new cache can't miss a candle.
+	b.candleCachesMtx.Lock()
+	defer b.candleCachesMtx.Unlock()
+	if cache := b.candleCaches[durStr]; cache != nil {
+		return cache, nil // created concurrently
+	}
+	src.candleMtx.RLock()
+	cdls := candles.Aggregate(src.CandlesCopy(), uint64(dur.Milliseconds()))
+	src.candleMtx.RUnlock()
+	cache := &candleCache{
+		Cache: candles.NewCache(candles.CacheSize, uint64(dur.Milliseconds())),
+		on:    1,
+	}
+	for i := range cdls {
+		cache.Add(&cdls[i])
+	}
+	b.candleCaches[durStr] = cache
+	return cache, nil
+}
+
 // closeFeed closes the specified feed, and if no more feeds are open, sets a
 // close timer to disconnect from the market feed.
 func (b *bookie) closeFeed(feedID uint32) {
@@ -399,6 +476,70 @@ func (dc *dexConnection) midGap(base, quote uint32) (midGap uint64, err error) {
 	return booky.MidGap()
 }
 
+// bookRecordPath is the path of the file that a market's book is recorded to.
+func bookRecordPath(dir, host, mktID string) string {
+	// Ports are separated with a character that is not allowed in Windows
+	// file names.
+	return filepath.Join(dir, strings.ReplaceAll(host, ":", "_"), mktID+".jsonl")
+}
+
+// startRecording starts recording the book to the file at path. Errors are
+// logged, since trading does not depend on the recording.
+func (b *bookie) startRecording(path string) {
+	rec, err := orderbook.NewRecorder(path, bookSnapshotInterval)
+	if err != nil {
+		b.log.Errorf("Unable to record order book: %v", err)
+		return
+	}
+	b.recorder = rec
+	b.SetRecorder(rec)
+}
+
+// stopRecording stops recording the book, if it is being recorded.
+func (b *bookie) stopRecording() {
+	if b.recorder == nil {
+		return
+	}
+	b.SetRecorder(nil)
+	if err := b.recorder.Close(); err != nil {
+		b.log.Errorf("Error closing order book recording: %v", err)
+	}
+	b.recorder = nil
+}
+
+// RecordedBook reconstructs a market's order book as it was at the specified
+// time from the recording made when Config.BookRecordDir is set. The book
+// will not include epoch orders or recent matches.
+func (c *Core) RecordedBook(host string, base, quote uint32, stamp time.Time) (*OrderBook, error) {
+	if c.cfg.BookRecordDir == "" {
+		return nil, fmt.Errorf("order books are not being recorded")
+	}
+	host, err := addrHost(host)
+	if err != nil {
+		return nil, newError(addressParseErr, "error parsing address: %w", err)
+	}
+	c.connMtx.RLock()
+	dc, found := c.conns[host]
+	c.connMtx.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("no DEX %s", host)
+	}
+	mktID := marketName(base, quote)
+	path := bookRecordPath(c.cfg.BookRecordDir, host, mktID)
+	ob, err := orderbook.ReplayBookFile(path, stamp, dc.log.SubLogger(mktID))
+	if err != nil {
+		return nil, fmt.Errorf("error replaying %s order book: %w", mktID, err)
+	}
+	// The bookie translates the orders with the market's unit info.
+	booky := newBookie(dc, base, quote, nil, dc.log.SubLogger(mktID))
+	booky.OrderBook = ob
+	buys, sells, _ := ob.Orders()
+	return &OrderBook{
+		Buys:  booky.translateBookSide(buys),
+		Sells: booky.translateBookSide(sells),
+	}, nil
+}
+
 // syncBook subscribes to the order book and returns the book and a BookFeed to
 // receive order book updates. The BookFeed must be Close()d when it is no
 // longer in use. Use stopBook to unsubscribed and clean up the feed.
@@ -424,8 +565,12 @@ func (dc *dexConnection) syncBook(base, quote uint32) (*orderbook.OrderBook, Boo
 		}
 
 		booky = newBookie(dc, base, quote, cfg.BinSizes, dc.log.SubLogger(mktID))
+		if dc.bookRecordDir != "" {
+			booky.startRecording(bookRecordPath(dc.bookRecordDir, dc.acct.host, mktID))
+		}
 		err = booky.Sync(obRes)
 		if err != nil {
+			booky.stopRecording()
 			return nil, nil, err
 		}
 		dc.books[mktID] = booky
@@ -497,6 +642,7 @@ func (dc *dexConnection) stopBook(base, quote uint32) {
 			return
 		}
 		// No BookFeeds, delete the bookie.
+		booky.stopRecording()
 		delete(dc.books, mkt)
 	}
 
@@ -803,12 +949,8 @@ func handleTradeResumptionMsg(c *Core, dc *dexConnection, msg *msgjson.Message)
 	dc.epoch[rs.MarketID] = rs.StartEpoch
 	dc.epochMtx.Unlock()
 
-	// TODO: Server config change without restart is not implemented on the
-	// server, but it would involve either getting the config response or adding
-	// the entire market config to the TradeResumption payload.
-	//
-	// Fetch the updated DEX configuration.
-	// dc.refreshServerConfig()
+	// Any changes to the market's configuration are delivered in a
+	// config_update notification sent prior to the resumption.
 
 	subject, detail := c.formatDetails(TopicMarketResumed, rs.MarketID, dc.acct.host, rs.StartEpoch)
 	c.notify(newServerNotifyNote(TopicMarketResumed, subject, detail, db.Success))
@@ -822,6 +964,13 @@ func (dc *dexConnection) apiVersion() int32 {
 	return atomic.LoadInt32(&dc.apiVer)
 }
 
+// capabilities are the capabilities negotiated with the server when the
+// account was authenticated. Servers that predate capability negotiation, and
+// connections that are not authenticated, have no capabilities.
+func (dc *dexConnection) capabilities() msgjson.Capabilities {
+	return msgjson.Capabilities(dc.caps.Load())
+}
+
 // refreshServerConfig fetches and replaces server configuration data. It also
 // initially checks that a server's API version is one of serverAPIVers.
 func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
@@ -831,7 +980,15 @@ func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to fetch server config: %w", err)
 	}
+	if err = dc.applyServerConfig(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
 
+// applyServerConfig checks the server's API version and replaces the
+// dexConnection's configuration data and market maps with those in cfg.
+func (dc *dexConnection) applyServerConfig(cfg *msgjson.ConfigResult) error {
 	apiVer := int32(cfg.APIVersion)
 	dc.log.Infof("Server %v supports API version %v.", dc.acct.host, cfg.APIVersion)
 	atomic.StoreInt32(&dc.apiVer, apiVer)
@@ -848,7 +1005,7 @@ func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
 		if apiVer > supportedAPIVers[len(supportedAPIVers)-1] {
 			err = fmt.Errorf("%v: %w", err, outdatedClientErr)
 		}
-		return nil, err
+		return err
 	}
 
 	bTimeout := time.Millisecond * time.Duration(cfg.BroadcastTimeout)
@@ -866,7 +1023,7 @@ func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
 
 	assets, epochs, err := generateDEXMaps(dc.acct.host, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("inconsistent 'config' response: %w", err)
+		return fmt.Errorf("inconsistent 'config' response: %w", err)
 	}
 
 	// Update dc.{epoch,assets}
@@ -882,7 +1039,7 @@ func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
 	if dc.acct.dexPubKey == nil && len(cfg.DEXPubKey) > 0 {
 		dc.acct.dexPubKey, err = secp256k1.ParsePubKey(cfg.DEXPubKey)
 		if err != nil {
-			return nil, fmt.Errorf("error decoding secp256k1 PublicKey from bytes: %w", err)
+			return fmt.Errorf("error decoding secp256k1 PublicKey from bytes: %w", err)
 		}
 	}
 
@@ -891,7 +1048,85 @@ func (dc *dexConnection) refreshServerConfig() (*msgjson.ConfigResult, error) {
 	dc.resolvedEpoch = utils.CopyMap(epochs)
 	dc.epochMtx.Unlock()
 
-	return cfg, nil
+	return nil
+}
+
+// handleConfigUpdateMsg is called when a config_update notification is
+// received. The server sends the complete configuration when a market is added,
+// modified, or delisted without a restart.
+func handleConfigUpdateMsg(c *Core, dc *dexConnection, msg *msgjson.Message) error {
+	cfg := new(msgjson.ConfigResult)
+	err := msg.Unmarshal(cfg)
+	if err != nil {
+		return fmt.Errorf("config update unmarshal error: %w", err)
+	}
+
+	// Note the market changes and maintenance windows already scheduled, so
+	// only new ones are announced.
+	scheduled := make(map[string]uint64)
+	maintenance := make(map[string]uint64)
+	dc.cfgMtx.RLock()
+	if dc.cfg != nil {
+		for _, mkt := range dc.cfg.Markets {
+			if mkt.ScheduledChange != nil {
+				scheduled[mkt.Name] = mkt.ScheduledChange.FinalEpoch
+			}
+			if mkt.Maintenance != nil {
+				maintenance[mkt.Name] = mkt.Maintenance.FinalEpoch
+			}
+		}
+	}
+	dc.cfgMtx.RUnlock()
+
+	if err = dc.applyServerConfig(cfg); err != nil {
+		if errors.Is(err, outdatedClientErr) {
+			sendOutdatedClientNotification(c, dc)
+		}
+		return fmt.Errorf("unable to apply config update from %s: %w", dc.acct.host, err)
+	}
+	c.updateSelfGoverned(dc)
+
+	for _, mkt := range cfg.Markets {
+		if mt := mkt.Maintenance; mt != nil && maintenance[mkt.Name] != mt.FinalEpoch {
+			subject, detail := c.formatDetails(TopicMaintenanceScheduled, mkt.Name, dc.acct.host,
+				time.UnixMilli(int64(mt.SuspendTime)), time.UnixMilli(int64(mt.ResumeTime)), mt.Reason)
+			c.notify(newServerNotifyNote(TopicMaintenanceScheduled, subject, detail, db.WarningLevel))
+		}
+		change := mkt.ScheduledChange
+		if change == nil || scheduled[mkt.Name] == change.FinalEpoch {
+			continue
+		}
+		changeTime := time.UnixMilli(int64((change.FinalEpoch + 1) * mkt.EpochLen))
+		subject, detail := c.formatDetails(TopicMarketChangeScheduled, mkt.Name, dc.acct.host, changeTime, dc.describeMarketChange(mkt))
+		c.notify(newServerNotifyNote(TopicMarketChangeScheduled, subject, detail, db.WarningLevel))
+	}
+
+	c.notify(newServerConfigUpdateNote(dc.acct.host))
+	return nil
+}
+
+// describeMarketChange describes the changed parameters of a market's scheduled
+// change, e.g. "lot size 2 DCR, rate step 1000".
+func (dc *dexConnection) describeMarketChange(mkt *msgjson.Market) string {
+	change := mkt.ScheduledChange
+	var parts []string
+	if change.LotSize != 0 {
+		lotSize := strconv.FormatUint(change.LotSize, 10)
+		if base := dc.assetConfig(mkt.Base); base != nil {
+			lotSize = base.UnitInfo.FormatAtoms(change.LotSize)
+		}
+		parts = append(parts, "lot size "+lotSize)
+	}
+	if change.RateStep != 0 {
+		parts = append(parts, fmt.Sprintf("rate step %d", change.RateStep))
+	}
+	if change.EpochLen != 0 {
+		parts = append(parts, fmt.Sprintf("epoch duration %v", time.Duration(change.EpochLen)*time.Millisecond))
+	}
+	if change.ParcelSize != 0 {
+		parts = append(parts, fmt.Sprintf("parcel size %d lots", change.ParcelSize))
+	}
+	return strings.Join(parts, ", ")
 }
 
 // subPriceFeed subscribes to the price_feed notification feed and primes the
@@ -1014,10 +1249,64 @@ func handleEpochReportMsg(c *Core, dc *dexConnection, msg *msgjson.Message) erro
 	if err != nil {
 		return fmt.Errorf("error logging epoch report: %w", err)
 	}
+	if len(note.BookChecksum) > 0 {
+		if err := book.VerifyChecksum(note.BookSeq, note.BookChecksum); err != nil {
+			go c.resyncBook(dc, book, err)
+		}
+	}
 	c.checkEpochResolution(dc.acct.host, note.MarketID)
 	return nil
 }
 
+// resyncBook resubscribes to the bookie's market and rebuilds the book from a
+// fresh snapshot after the book has failed validation against the server's
+// checksum.
+func (c *Core) resyncBook(dc *dexConnection, booky *bookie, reason error) {
+	if !booky.resyncing.CompareAndSwap(false, true) {
+		return // already resyncing
+	}
+	defer booky.resyncing.Store(false)
+
+	mktID := marketName(booky.base, booky.quote)
+	c.log.Warnf("Resyncing %s order book at %s: %v", mktID, dc.acct.host, reason)
+	subject, details := c.formatDetails(TopicBookResync, mktID, dc.acct.host)
+	c.notify(newServerNotifyNote(TopicBookResync, subject, details, db.WarningLevel))
+
+	if err := dc.refreshBook(booky); err != nil {
+		c.log.Errorf("Failed to resync %s order book at %s: %v", mktID, dc.acct.host, err)
+	}
+}
+
+// refreshBook resubscribes to the bookie's market, resets the book with the
+// new snapshot, and sends a FreshBookAction to the bookie's feeds.
+func (dc *dexConnection) refreshBook(booky *bookie) error {
+	snap, err := dc.subscribe(booky.base, booky.quote)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to 'orderbook': %w", err)
+	}
+
+	// Create a fresh OrderBook for the bookie.
+	err = booky.Reset(snap)
+
+	// Send a FreshBookAction to the subscribers.
+	mktID := marketName(booky.base, booky.quote)
+	booky.send(&BookUpdate{
+		Action:   FreshBookAction,
+		Host:     dc.acct.host,
+		MarketID: mktID,
+		Payload: &MarketOrderBook{
+			Base:  booky.base,
+			Quote: booky.quote,
+			Book:  booky.book(),
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to sync order book snapshot: %w", err)
+	}
+	return nil
+}
+
 // handleEpochOrderMsg is called when an epoch_order notification is
 // received.
 func handleEpochOrderMsg(_ *Core, dc *dexConnection, msg *msgjson.Message) error {
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -33,9 +34,11 @@ import (
 	"decred.org/dcrdex/client/db/bolt"
 	"decred.org/dcrdex/client/mnemonic"
 	"decred.org/dcrdex/client/orderbook"
+	"decred.org/dcrdex/client/tor"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/calc"
 	"decred.org/dcrdex/dex/config"
+	"decred.org/dcrdex/dex/dexnet"
 	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/encrypt"
 	"decred.org/dcrdex/dex/msgjson"
@@ -48,6 +51,7 @@ import (
 	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"github.com/decred/dcrd/hdkeychain/v3"
 	"github.com/decred/go-socks/socks"
+	"github.com/tyler-smith/go-bip39"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
@@ -109,6 +113,9 @@ var (
 	// updating the API. Long-running operations may start and end with
 	// differing versions.
 	supportedAPIVers = []int32{serverdex.V1APIVersion}
+	// clientCapabilities are the optional protocol features that this client
+	// supports. The capabilities used with a server are negotiated on connect.
+	clientCapabilities = msgjson.CapBookChecksum | msgjson.CapReputation
 	// ActiveOrdersLogoutErr is returned from logout when there are active
 	// orders.
 	ActiveOrdersLogoutErr = errors.New("cannot log out with active orders")
@@ -155,6 +162,8 @@ type dexConnection struct {
 	ticker     *dexTicker
 	// apiVer is an atomic. An uninitiated connection should be set to -1.
 	apiVer int32
+	// caps are the capabilities negotiated with the server on connect.
+	caps atomic.Uint64
 
 	assetsMtx sync.RWMutex
 	assets    map[uint32]*dex.Asset
@@ -164,6 +173,8 @@ type dexConnection struct {
 
 	booksMtx sync.RWMutex
 	books    map[string]*bookie
+	// bookRecordDir is the Config.BookRecordDir.
+	bookRecordDir string
 
 	// tradeMtx is used to synchronize access to the trades map.
 	tradeMtx sync.RWMutex
@@ -368,6 +379,8 @@ func coreMarketFromMsgMarket(dc *dexConnection, msgMkt *msgjson.Market) *Market
 		MarketBuyBuffer: msgMkt.MarketBuyBuffer,
 		AtomToConv:      float64(bconv) / float64(qconv),
 		MinimumRate:     dc.minimumMarketRate(quote, msgMkt.LotSize),
+		ScheduledChange: msgMkt.ScheduledChange,
+		Maintenance:     msgMkt.Maintenance,
 	}
 
 	trades, inFlight := dc.marketTrades(mkt.marketName())
@@ -1324,15 +1337,21 @@ func (dc *dexConnection) marketEpoch(mktID string, stamp time.Time) uint64 {
 	return uint64(stamp.UnixMilli()) / epochLen
 }
 
-// fetchFeeRate gets an asset's fee rate estimate from the server.
-func (dc *dexConnection) fetchFeeRate(assetID uint32) (rate uint64) {
+// fetchFeeRate gets an asset's fee rate estimate from the server. If queue is
+// true, a request that can't be sent because the connection is down is queued
+// and sent on reconnect, so fetchFeeRate may block until the request expires.
+func (dc *dexConnection) fetchFeeRate(assetID uint32, queue bool) (rate uint64) {
 	msg, err := msgjson.NewRequest(dc.NextID(), msgjson.FeeRateRoute, assetID)
 	if err != nil {
 		dc.log.Errorf("Error fetching fee rate for %s: %v", unbip(assetID), err)
 		return
 	}
+	request := dc.RequestWithTimeout
+	if queue {
+		request = dc.QueueRequest
+	}
 	errChan := make(chan error, 1)
-	err = dc.RequestWithTimeout(msg, func(msg *msgjson.Message) {
+	err = request(msg, func(msg *msgjson.Message) {
 		errChan <- msg.UnmarshalResult(&rate)
 	}, DefaultResponseTimeout, func() {
 		errChan <- fmt.Errorf("timed out waiting for fee_rate response")
@@ -1456,6 +1475,28 @@ type Config struct {
 	// on shutdown. This is useful if the consumer is using the BackupDB method,
 	// or simply creating manual backups of the DB file after shutdown.
 	NoAutoDBBackup bool // zero value is legacy behavior
+	// NoWSCompression disables the negotiation of websocket compression with
+	// DEX servers.
+	NoWSCompression bool
+	// EnableQUIC enables connecting over QUIC to DEX servers that support
+	// it. QUIC is not used if a proxy is configured.
+	EnableQUIC bool
+	// NoCBOR disables CBOR encoding of high-volume messages, such as order
+	// books, from DEX servers that support it.
+	NoCBOR bool
+	// DBBackupInterval is how often to back up the database while running.
+	// Zero disables scheduled backups.
+	DBBackupInterval time.Duration
+	// DBBackupDir is the directory of the scheduled backups. The default is
+	// the "backup/scheduled" folder next to the database.
+	DBBackupDir string
+	// DBBackupRetention is the number of scheduled backups to keep. The
+	// default is 7.
+	DBBackupRetention int
+	// BookRecordDir is a directory to record the order books of subscribed
+	// markets to, so that a book can be replayed as it was at a past moment.
+	// See RecordedBook. Books are not recorded if empty.
+	BookRecordDir string
 	// UnlockCoinsOnLogin indicates that on wallet connect during login, or on
 	// creation of a new wallet, all coins with the wallet should be unlocked.
 	UnlockCoinsOnLogin bool
@@ -1463,6 +1504,10 @@ type Config struct {
 	// for running core in extension mode, which gives the caller options for
 	// e.g. limiting the ability to configure wallets.
 	ExtensionModeFile string
+	// TokenRegistryFile is the path to a JSON file of token definitions, a
+	// TokenRegistry, that are registered on startup in addition to the
+	// built-in tokens.
+	TokenRegistryFile string
 
 	TheOneHost string
 }
@@ -1490,6 +1535,8 @@ type Core struct {
 	intl          atomic.Value // *locale
 
 	extensionModeConfig *ExtensionModeConfig
+	// tokenRegistry is from the Config.TokenRegistryFile.
+	tokenRegistry *TokenRegistry
 
 	// construction or init sets credentials
 	credMtx     sync.RWMutex
@@ -1528,6 +1575,9 @@ type Core struct {
 	fiatRateSources map[string]*commonRateSource
 
 	reFiat chan struct{}
+	// lastFiatRecord is when the fiat rates were last recorded to the rate
+	// history. Only used by refreshFiatRates, which is not run concurrently.
+	lastFiatRecord time.Time
 
 	pendingWalletsMtx sync.RWMutex
 	pendingWallets    map[uint32]bool
@@ -1538,6 +1588,30 @@ type Core struct {
 
 	requestedActionMtx sync.RWMutex
 	requestedActions   map[string]*asset.ActionRequiredNote
+
+	explorerMtx sync.RWMutex
+	explorers   *db.ExplorerSettings
+
+	// torActive are the tor settings loaded at startup, which are in effect
+	// until restart. tor is nil if tor is disabled or unavailable.
+	torActive *db.TorSettings
+	tor       *tor.Client
+	// torMtx guards torSettings, the settings saved since startup.
+	torMtx      sync.RWMutex
+	torSettings *db.TorSettings
+	// httpClients are the clients for HTTP requests to the outbound
+	// services, by db.ProxyService* name.
+	httpClients map[string]*http.Client
+
+	// proxyActive are the proxy settings loaded at startup, which are in
+	// effect until restart.
+	proxyActive *db.ProxySettings
+	// proxyMtx guards proxySettings, the settings saved since startup.
+	proxyMtx      sync.RWMutex
+	proxySettings *db.ProxySettings
+
+	// pruneNow signals the record pruning loop to prune right away.
+	pruneNow chan struct{}
 }
 
 // New is the constructor for a new Core.
@@ -1547,6 +1621,10 @@ func New(cfg *Config) (*Core, error) {
 	}
 	dbOpts := bolt.Opts{
 		BackupOnShutdown: !cfg.NoAutoDBBackup,
+		BackupInterval:   cfg.DBBackupInterval,
+		BackupDir:        cfg.DBBackupDir,
+		BackupRetention:  cfg.DBBackupRetention,
+		DecodeCoinID:     asset.DecodeCoinID,
 	}
 	boltDB, err := bolt.NewDB(cfg.DBPath, cfg.Logger.SubLogger("DB"), dbOpts)
 	if err != nil {
@@ -1634,6 +1712,29 @@ func New(cfg *Config) (*Core, error) {
 		return nil, err
 	}
 
+	explorers, err := boltDB.ExplorerSettings()
+	if err != nil {
+		cfg.Logger.Errorf("Error loading block explorer settings: %v", err)
+		explorers = new(db.ExplorerSettings)
+	}
+
+	torSettings, err := boltDB.TorSettings()
+	if err != nil {
+		cfg.Logger.Errorf("Error loading tor settings: %v", err)
+		// Tor may have been enabled, so fail closed.
+		torSettings = &db.TorSettings{Enabled: true}
+	}
+	torClient, err := newTorClient(torSettings, cfg.DBPath, cfg.Logger.SubLogger("TOR"))
+	if err != nil {
+		cfg.Logger.Errorf("Error initializing tor: %v", err)
+	}
+
+	proxySettings, err := boltDB.ProxySettings()
+	if err != nil {
+		cfg.Logger.Errorf("Error loading proxy settings: %v", err)
+		proxySettings = new(db.ProxySettings)
+	}
+
 	var xCfg *ExtensionModeConfig
 	if cfg.ExtensionModeFile != "" {
 		b, err := os.ReadFile(cfg.ExtensionModeFile)
@@ -1643,6 +1744,25 @@ func New(cfg *Config) (*Core, error) {
 		if err := json.Unmarshal(b, &xCfg); err != nil {
 			return nil, fmt.Errorf("error unmarshalling extension mode file: %w", err)
 		}
+		if xCfg != nil && xCfg.Branding != nil {
+			dir := filepath.Dir(cfg.ExtensionModeFile)
+			for _, path := range []*string{&xCfg.Branding.Logo, &xCfg.Branding.Icon} {
+				if *path != "" && !filepath.IsAbs(*path) {
+					*path = filepath.Join(dir, *path)
+				}
+			}
+		}
+	}
+
+	var tokenRegistry *TokenRegistry
+	if cfg.TokenRegistryFile != "" {
+		b, err := os.ReadFile(cfg.TokenRegistryFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading token registry file at %q: %w", cfg.TokenRegistryFile, err)
+		}
+		if err := json.Unmarshal(b, &tokenRegistry); err != nil {
+			return nil, fmt.Errorf("error unmarshalling token registry file: %w", err)
+		}
 	}
 
 	c := &Core{
@@ -1668,7 +1788,15 @@ func New(cfg *Config) (*Core, error) {
 		noteChans:     make(map[uint64]chan Notification),
 
 		extensionModeConfig: xCfg,
+		tokenRegistry:       tokenRegistry,
 		seedGenerationTime:  seedGenerationTime,
+		explorers:           explorers,
+		torActive:           torSettings,
+		tor:                 torClient,
+		torSettings:         torSettings,
+		proxyActive:         proxySettings,
+		proxySettings:       proxySettings,
+		pruneNow:            make(chan struct{}, 1),
 
 		fiatRateSources: make(map[string]*commonRateSource),
 		reFiat:          make(chan struct{}, 1),
@@ -1678,6 +1806,11 @@ func New(cfg *Config) (*Core, error) {
 		requestedActions: make(map[string]*asset.ActionRequiredNote),
 	}
 
+	c.httpClients = map[string]*http.Client{
+		db.ProxyServiceOracles:   c.newHTTPClient(db.ProxyServiceOracles),
+		db.ProxyServiceFiatRates: c.newHTTPClient(db.ProxyServiceFiatRates),
+	}
+
 	c.intl.Store(&locale{
 		lang:    lang,
 		m:       translations,
@@ -1696,6 +1829,7 @@ func (c *Core) Run(ctx context.Context) {
 	// Store the context as a field, since we will need to spawn new DEX threads
 	// when new accounts are registered.
 	c.ctx = ctx
+	c.startTor(ctx)
 	if err := c.initialize(); err != nil { // connectDEX gets ctx for the wsConn
 		c.log.Critical(err)
 		close(c.ready) // unblock <-Ready()
@@ -1734,7 +1868,7 @@ fetchers:
 		}
 		c.fiatRateSources[token] = newCommonRateSource(rateFetcher)
 	}
-	c.fetchFiatExchangeRates(ctx)
+	c.fetchFiatExchangeRates(dexnet.ContextWithClient(ctx, c.httpClients[db.ProxyServiceFiatRates]))
 
 	// Start a goroutine to keep the FeeState updated.
 	c.wg.Add(1)
@@ -1762,6 +1896,13 @@ fetchers:
 		c.watchBonds(ctx)
 	}()
 
+	// Prune old records according to the retention settings.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.pruneRecordsLoop(ctx)
+	}()
+
 	// Handle wallet notifications.
 	c.wg.Add(1)
 	go func() {
@@ -1778,7 +1919,11 @@ fetchers:
 
 	c.wg.Wait() // block here until all goroutines except DB complete
 
-	if err := c.db.SavePokes(c.pokes()); err != nil {
+	// The pokes can't be saved to an encrypted database that was never
+	// unlocked.
+	if c.db.Locked() {
+		c.log.Debugf("Not saving pokes to the locked database.")
+	} else if err := c.db.SavePokes(c.pokes()); err != nil {
 		c.log.Errorf("Error saving pokes: %v", err)
 	}
 
@@ -1884,6 +2029,12 @@ func (c *Core) BackupDB(dst string, overwrite, compact bool) error {
 	return c.db.BackupTo(dst, overwrite, compact)
 }
 
+// RestoreDB validates the database backup at src, and restores it when the
+// client is restarted. The replaced database is kept in the backup folder.
+func (c *Core) RestoreDB(src string) error {
+	return c.db.Restore(dex.CleanAndExpandPath(src))
+}
+
 const defaultDEXPort = "7232"
 
 // addrHost returns the host or url:port pair for an address.
@@ -2473,7 +2624,7 @@ func (c *Core) setWalletCreationComplete(tokenID uint32) {
 
 // assetMap returns a map of asset information for supported assets.
 func (c *Core) assetMap() map[uint32]*SupportedAsset {
-	supported := asset.Assets()
+	supported := asset.NetworkAssets(c.net)
 	assets := make(map[uint32]*SupportedAsset, len(supported))
 	c.walletMtx.RLock()
 	defer c.walletMtx.RUnlock()
@@ -2517,7 +2668,7 @@ func (c *Core) asset(assetID uint32) *SupportedAsset {
 	if w != nil {
 		wallet = w.state()
 	}
-	regAsset := asset.Asset(assetID)
+	regAsset := asset.NetworkAsset(c.net, assetID)
 	if regAsset != nil {
 		return &SupportedAsset{
 			ID:       assetID,
@@ -2529,7 +2680,7 @@ func (c *Core) asset(assetID uint32) *SupportedAsset {
 		}
 	}
 
-	token := asset.TokenInfo(assetID)
+	token := asset.NetworkTokenInfo(c.net, assetID)
 	if token == nil {
 		return nil
 	}
@@ -2556,9 +2707,21 @@ func (c *Core) User() *User {
 		Net:                c.net,
 		ExtensionConfig:    c.extensionModeConfig,
 		Actions:            c.requestedActionsList(),
+		Explorers:          c.activeExplorers(),
 	}
 }
 
+// PendingActions are the wallet and trade actions awaiting a user response.
+func (c *Core) PendingActions() []*asset.ActionRequiredNote {
+	return c.requestedActionsList()
+}
+
+// CheckDB checks that the database can be read.
+func (c *Core) CheckDB() error {
+	_, err := c.db.ListAccounts()
+	return err
+}
+
 func (c *Core) requestedActionsList() []*asset.ActionRequiredNote {
 	c.requestedActionMtx.RLock()
 	defer c.requestedActionMtx.RUnlock()
@@ -2772,14 +2935,29 @@ func (c *Core) createWallet(crypter encrypt.Crypter, walletPW []byte, assetID ui
 		}
 	}
 
+	var encSeed []byte
 	if walletDef.Seeded {
 		if len(walletPW) > 0 {
 			return nil, errors.New("external password incompatible with seeded wallet")
 		}
-		walletPW, err = c.createSeededWallet(assetID, crypter, form)
+		var importedSeed []byte
+		if form.Mnemonic != "" {
+			importedSeed, err = mnemonicSeed(form.Mnemonic)
+			if err != nil {
+				return nil, err
+			}
+			defer encode.ClearBytes(importedSeed)
+			encSeed, err = crypter.Encrypt(importedSeed)
+			if err != nil {
+				return nil, fmt.Errorf("wallet seed encryption error: %w", err)
+			}
+		}
+		walletPW, err = c.createSeededWallet(assetID, crypter, form, importedSeed)
 		if err != nil {
 			return nil, err
 		}
+	} else if form.Mnemonic != "" {
+		return nil, fmt.Errorf("a seed phrase can only be imported for a native wallet, not %q", walletDef.Type)
 	}
 
 	var encPW []byte
@@ -2791,14 +2969,26 @@ func (c *Core) createWallet(crypter encrypt.Crypter, walletPW []byte, assetID ui
 	}
 
 	return &db.Wallet{
-		Type:        walletDef.Type,
-		AssetID:     assetID,
-		Settings:    form.Config,
-		EncryptedPW: encPW,
+		Type:          walletDef.Type,
+		AssetID:       assetID,
+		Settings:      form.Config,
+		EncryptedPW:   encPW,
+		EncryptedSeed: encSeed,
 		// Balance and Address are set after connect.
 	}, nil
 }
 
+// mnemonicSeed validates the BIP-39 seed phrase and returns the seed, with no
+// passphrase.
+func mnemonicSeed(phrase string) ([]byte, error) {
+	phrase = strings.Join(strings.Fields(strings.ToLower(phrase)), " ")
+	seed, err := bip39.NewSeedWithErrorChecking(phrase, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed phrase: %w", err)
+	}
+	return seed, nil
+}
+
 func (c *Core) createTokenWallet(tokenID uint32, token *asset.Token, form *WalletForm) (*db.Wallet, error) {
 	wallet, found := c.wallet(token.ParentID)
 	if !found {
@@ -2830,10 +3020,90 @@ func (c *Core) createTokenWallet(tokenID uint32, token *asset.Token, form *Walle
 	}, nil
 }
 
+// AddCustomToken registers a user-defined token, e.g. an ERC20 token that is
+// not in the built-in token list, and creates a wallet for it. The parent
+// asset's wallet must already exist. The token is stored and registered again
+// on startup. The token can be traded on any DEX that lists it under the
+// returned asset ID.
+func (c *Core) AddCustomToken(appPW []byte, ct *asset.CustomToken) (uint32, error) {
+	if _, found := c.wallet(ct.ParentID); !found {
+		return 0, fmt.Errorf("no %s wallet", unbip(ct.ParentID))
+	}
+	tokenID, err := asset.RegisterCustomToken(ct, c.net)
+	if err != nil {
+		return 0, fmt.Errorf("error registering token: %w", err)
+	}
+	if err := c.db.SaveCustomToken(tokenID, ct); err != nil {
+		return 0, fmt.Errorf("error storing token: %w", err)
+	}
+	c.log.Infof("Registered custom token %s (%d) with contract address %s", unbip(tokenID), tokenID, ct.Address)
+	token := asset.TokenInfo(tokenID)
+	if err := c.CreateWallet(appPW, nil, &WalletForm{
+		AssetID: tokenID,
+		Type:    token.Definition.Type,
+	}); err != nil {
+		return 0, fmt.Errorf("error creating %s wallet: %w", unbip(tokenID), err)
+	}
+	return tokenID, nil
+}
+
+// registerCustomTokens registers the user-defined tokens stored with
+// AddCustomToken.
+func (c *Core) registerCustomTokens() {
+	tokens, err := c.db.CustomTokens()
+	if err != nil {
+		c.log.Errorf("Error loading custom tokens: %v", err)
+		return
+	}
+	for tokenID, ct := range tokens {
+		if asset.TokenInfo(tokenID) != nil {
+			continue // already registered
+		}
+		regID, err := asset.RegisterCustomToken(ct, c.net)
+		if err != nil {
+			c.log.Errorf("Error registering custom token %s at %s: %v", ct.Symbol, ct.Address, err)
+			continue
+		}
+		if regID != tokenID {
+			c.log.Errorf("Custom token %s registered with asset ID %d, but stored with asset ID %d", ct.Symbol, regID, tokenID)
+		}
+	}
+}
+
+// registerTokenRegistry registers the tokens of the token registry file. A
+// token that can't be registered is skipped.
+func (c *Core) registerTokenRegistry() {
+	if c.tokenRegistry == nil {
+		return
+	}
+	for _, rt := range c.tokenRegistry.Tokens {
+		parentID, err := asset.EVMChainAsset(rt.EVMChainID, c.net)
+		if err != nil {
+			c.log.Errorf("Error registering token %s from the token registry: %v", rt.Symbol, err)
+			continue
+		}
+		tokenID, err := asset.RegisterCustomToken(&asset.CustomToken{
+			ParentID:             parentID,
+			Address:              rt.Address,
+			Symbol:               rt.Symbol,
+			Name:                 rt.Name,
+			Decimals:             rt.Decimals,
+			UnitInfo:             rt.UnitInfo,
+			SwapContractVersions: rt.SwapContractVersions,
+		}, c.net)
+		if err != nil {
+			c.log.Errorf("Error registering token %s at %s from the token registry: %v", rt.Symbol, rt.Address, err)
+			continue
+		}
+		c.log.Infof("Registered token %s (%d) with contract address %s from the token registry", unbip(tokenID), tokenID, rt.Address)
+	}
+}
+
 // createSeededWallet initializes a seeded wallet with an asset-specific seed
-// and password derived deterministically from the app seed. The password is
-// returned for encrypting and storing.
-func (c *Core) createSeededWallet(assetID uint32, crypter encrypt.Crypter, form *WalletForm) ([]byte, error) {
+// and password derived deterministically from the app seed. If importedSeed is
+// non-nil, the wallet is created from it instead of the derived seed. The
+// password is returned for encrypting and storing.
+func (c *Core) createSeededWallet(assetID uint32, crypter encrypt.Crypter, form *WalletForm, importedSeed []byte) ([]byte, error) {
 	seed, pw, err := c.assetSeedAndPass(assetID, crypter)
 	if err != nil {
 		return nil, err
@@ -2841,7 +3111,11 @@ func (c *Core) createSeededWallet(assetID uint32, crypter encrypt.Crypter, form
 	defer encode.ClearBytes(seed)
 
 	var bday uint64
-	if creds := c.creds(); !creds.Birthday.IsZero() {
+	if importedSeed != nil {
+		// The birthday of an imported seed is unknown, so the wallet's default
+		// is used.
+		seed = importedSeed
+	} else if creds := c.creds(); !creds.Birthday.IsZero() {
 		bday = uint64(creds.Birthday.Unix())
 	}
 
@@ -2882,6 +3156,22 @@ func (c *Core) assetSeedAndPass(assetID uint32, crypter encrypt.Crypter) (seed,
 	return seed, pass, nil
 }
 
+// walletSeedAndPass is like assetSeedAndPass, but the seed is the wallet's
+// imported seed, if it has one.
+func (c *Core) walletSeedAndPass(assetID uint32, encSeed []byte, crypter encrypt.Crypter) (seed, pass []byte, err error) {
+	seed, pass, err = c.assetSeedAndPass(assetID, crypter)
+	if err != nil || len(encSeed) == 0 {
+		return seed, pass, err
+	}
+	encode.ClearBytes(seed)
+	seed, err = crypter.Decrypt(encSeed)
+	if err != nil {
+		encode.ClearBytes(pass)
+		return nil, nil, fmt.Errorf("wallet seed decryption error: %w", err)
+	}
+	return seed, pass, nil
+}
+
 // AssetSeedAndPass derives the wallet seed and password that would be used to
 // create a native wallet for a particular asset and application seed. Depending
 // on external wallet software and their key derivation paths, this seed may be
@@ -2942,6 +3232,7 @@ func (c *Core) loadWallet(dbWallet *db.Wallet) (*xcWallet, error) {
 			BondLocked:     bondLockedAmt,
 		},
 		encPass:      dbWallet.EncryptedPW,
+		encSeed:      dbWallet.EncryptedSeed,
 		address:      dbWallet.Address,
 		peerCount:    -1, // no count yet
 		dbID:         dbWallet.ID(),
@@ -3210,8 +3501,11 @@ func (c *Core) startWalletSyncMonitor(wallet *xcWallet) {
 // this will check for active orders involving this asset before initiating a
 // rescan. WARNING: It is ill-advised to initiate a wallet rescan with active
 // orders unless as a last ditch effort to get the wallet to recognize a
-// transaction needed to complete a swap.
-func (c *Core) RescanWallet(assetID uint32, force bool) error {
+// transaction needed to complete a swap. The rescan starts from the birthday
+// bday, in unix seconds. If bday is zero, seeded wallets use the app's
+// birthday, and other wallets use their default. Wallets emit the progress of
+// the rescan with asset.RescanProgressNotes.
+func (c *Core) RescanWallet(assetID uint32, force bool, bday uint64) error {
 	if !force && c.walletIsActive(assetID) {
 		return newError(activeOrdersErr, "active orders or registration fee payments for %v", unbip(assetID))
 	}
@@ -3227,8 +3521,7 @@ func (c *Core) RescanWallet(assetID uint32, force bool) error {
 		return newError(assetSupportErr, "asset.WalletDef error: %w", err)
 	}
 
-	var bday uint64 // unix time seconds
-	if walletDef.Seeded {
+	if bday == 0 && walletDef.Seeded {
 		creds := c.creds()
 		if !creds.Birthday.IsZero() {
 			bday = uint64(creds.Birthday.Unix())
@@ -3313,7 +3606,7 @@ func (c *Core) RecoverWallet(assetID uint32, appPW []byte, force bool) error {
 		return fmt.Errorf("error retrieving DB wallet: %w", err)
 	}
 
-	seed, pw, err := c.assetSeedAndPass(assetID, crypter)
+	seed, pw, err := c.walletSeedAndPass(assetID, dbWallet.EncryptedSeed, crypter)
 	if err != nil {
 		return err
 	}
@@ -3532,11 +3825,26 @@ func (c *Core) ResetAppPass(newPass []byte, seedStr string) (err error) {
 	}
 
 	innerKey := seedInnerKey(seed)
-	_, err = c.reCrypter(innerKey[:], creds.InnerKeyParams)
+	innerCrypter, err := c.reCrypter(innerKey[:], creds.InnerKeyParams)
 	if err != nil {
 		c.log.Errorf("Error reseting password with seed: %v", err)
 		return errors.New("incorrect seed")
 	}
+	defer innerCrypter.Close()
+
+	// A user who forgot their password never logged in, so an encrypted
+	// database is still locked. The inner crypter unlocks it, and the
+	// accounts and wallets that were not loaded at startup are loaded as they
+	// would be on login.
+	wasLocked := c.db.Locked()
+	if err := c.db.Unlock(innerCrypter); err != nil {
+		return fmt.Errorf("error unlocking database: %w", err)
+	}
+	if wasLocked {
+		if _, err := c.loadFromDB(); err != nil {
+			return err
+		}
+	}
 
 	return c.changeAppPass(newPass, innerKey[:], creds)
 }
@@ -3585,6 +3893,10 @@ func (c *Core) ReconfigureWallet(appPW, newWalletPW []byte, form *WalletForm) er
 		EncryptedPW: oldWallet.encPW(),
 		Address:     oldDepositAddr,
 	}
+	// An imported seed is kept for any seeded wallet type.
+	if walletDef.Seeded {
+		dbWallet.EncryptedSeed = oldWallet.encSeed
+	}
 
 	storeWithBalance := func(w *xcWallet, dbWallet *db.Wallet) error {
 		balances, err := c.walletBalance(w)
@@ -3695,7 +4007,15 @@ func (c *Core) ReconfigureWallet(appPW, newWalletPW []byte, form *WalletForm) er
 				return newError(authErr, "error retrieving wallet password: %w", err)
 			}
 		} else {
-			pw, err = c.createSeededWallet(assetID, crypter, form)
+			var importedSeed []byte
+			if len(oldWallet.encSeed) > 0 {
+				importedSeed, err = crypter.Decrypt(oldWallet.encSeed)
+				if err != nil {
+					return newError(authErr, "error decrypting wallet seed: %w", err)
+				}
+				defer encode.ClearBytes(importedSeed)
+			}
+			pw, err = c.createSeededWallet(assetID, crypter, form, importedSeed)
 			if err != nil {
 				return newError(createWalletErr, "error creating new %q-type %s wallet: %w", form.Type, unbip(assetID), err)
 			}
@@ -4414,16 +4734,22 @@ func (c *Core) InitializeClient(pw []byte, restorationSeed *string) (string, err
 		return "", fmt.Errorf("already initialized, login instead")
 	}
 
-	_, creds, mnemonicSeed, err := c.generateCredentials(pw, restorationSeed)
+	crypter, creds, mnemonicSeed, err := c.generateCredentials(pw, restorationSeed)
 	if err != nil {
 		return "", err
 	}
+	defer crypter.Close()
 
 	err = c.db.SetPrimaryCredentials(creds)
 	if err != nil {
 		return "", fmt.Errorf("SetPrimaryCredentials error: %w", err)
 	}
 
+	// Encrypt the new database.
+	if err := c.db.Unlock(crypter); err != nil {
+		return "", fmt.Errorf("error encrypting database: %w", err)
+	}
+
 	freshSeed := restorationSeed == nil
 	if freshSeed {
 		now := uint64(time.Now().Unix())
@@ -4619,6 +4945,19 @@ func (c *Core) Login(pw []byte) error {
 			if err != nil {
 				return false, fmt.Errorf("GenDeepChild error: %w", err)
 			}
+			// Unlock the database, encrypting it on the first login after
+			// upgrading. The accounts and wallets of an encrypted database
+			// were not loaded at startup.
+			wasLocked := c.db.Locked()
+			if err := c.db.Unlock(crypter); err != nil {
+				return false, fmt.Errorf("error unlocking database: %w", err)
+			}
+			if wasLocked {
+				c.notify(newLoginNote("Loading accounts and wallets..."))
+				if _, err := c.loadFromDB(); err != nil {
+					return false, err
+				}
+			}
 			c.loggedIn = true
 			return true, nil
 		}
@@ -4639,6 +4978,7 @@ func (c *Core) Login(pw []byte) error {
 		c.resolveActiveTrades(crypter)
 		c.notify(newLoginNote("Connecting to DEX servers..."))
 		c.initializeDEXConnections(crypter)
+		c.signalPrune()
 
 	}
 
@@ -4950,6 +5290,8 @@ func (c *Core) Orders(filter *OrderFilter) ([]*Order, error) {
 		Assets:   filter.Assets,
 		Market:   mkt,
 		Statuses: filter.Statuses,
+		Since:    filter.Since,
+		Until:    filter.Until,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("UserOrders error: %w", err)
@@ -5012,6 +5354,17 @@ func (c *Core) Order(oidB dex.Bytes) (*Order, error) {
 	return c.coreOrderFromMetaOrder(mOrd)
 }
 
+// MarketStats returns the daily trading totals of the markets, sorted by day.
+// The totals are kept up to date as matches complete, so the stats don't
+// require a scan of the order history.
+func (c *Core) MarketStats(filter *db.MarketStatsFilter) ([]*db.MarketStats, error) {
+	stats, err := c.db.MarketStats(filter)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving market stats: %w", err)
+	}
+	return stats, nil
+}
+
 // marketWallets gets the 2 *dex.Assets and 2 *xcWallet associated with a
 // market. The wallets will be connected, but not necessarily unlocked.
 func (c *Core) marketWallets(host string, base, quote uint32) (ba, qa *dex.Asset, bw, qw *xcWallet, err error) {
@@ -5338,7 +5691,7 @@ func (c *Core) feeSuggestionAny(assetID uint32, preferredConns ...*dexConnection
 			continue
 		}
 
-		feeSuggestion := dc.fetchFeeRate(assetID)
+		feeSuggestion := dc.fetchFeeRate(assetID, false)
 		if feeSuggestion > 0 {
 			return feeSuggestion
 		}
@@ -5355,66 +5708,455 @@ func (c *Core) feeSuggestion(dc *dexConnection, assetID uint32) (feeSuggestion u
 	if feeSuggestion > 0 {
 		return
 	}
-	return dc.fetchFeeRate(assetID)
+	return dc.fetchFeeRate(assetID, false)
+}
+
+// Send initiates either send or withdraw from an exchange wallet. if subtract
+// is true, fees are subtracted from the value else fees are taken from the
+// exchange wallet.
+func (c *Core) Send(pw []byte, assetID uint32, value uint64, address string, subtract bool) (asset.Coin, error) {
+	var crypter encrypt.Crypter
+	// Empty password can be provided if wallet is already unlocked. Webserver
+	// and RPCServer should not allow empty password, but this is used for
+	// bots.
+	if len(pw) > 0 {
+		var err error
+		crypter, err = c.encryptionKey(pw)
+		if err != nil {
+			return nil, fmt.Errorf("Trade password error: %w", err)
+		}
+		defer crypter.Close()
+	}
+
+	if value == 0 {
+		return nil, fmt.Errorf("cannot send/withdraw zero %s", unbip(assetID))
+	}
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	err := c.connectAndUnlock(crypter, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = wallet.checkPeersAndSyncStatus(); err != nil {
+		return nil, err
+	}
+
+	address, err = c.resolveAddress(wallet, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var coin asset.Coin
+	feeSuggestion := c.feeSuggestionAny(assetID)
+	if !subtract {
+		coin, err = wallet.Wallet.Send(address, value, feeSuggestion)
+	} else {
+		if withdrawer, isWithdrawer := wallet.Wallet.(asset.Withdrawer); isWithdrawer {
+			coin, err = withdrawer.Withdraw(address, value, feeSuggestion)
+		} else {
+			return nil, fmt.Errorf("wallet does not support subtracting network fee from withdraw amount")
+		}
+	}
+	if err != nil {
+		subject, details := c.formatDetails(TopicSendError, unbip(assetID), err)
+		c.notify(newSendNote(TopicSendError, subject, details, db.ErrorLevel))
+		return nil, err
+	}
+
+	sentValue := wallet.Info().UnitInfo.ConventionalString(coin.Value())
+	subject, details := c.formatDetails(TopicSendSuccess, sentValue, unbip(assetID), address, coin)
+	c.notify(newSendNote(TopicSendSuccess, subject, details, db.Success))
+
+	c.updateAssetBalance(assetID)
+
+	return coin, nil
+}
+
+// PreviewGasFees returns the cost of a transaction of the type from the
+// asset's wallet paying the specified EIP-1559 fee rates. Zero rates are
+// chosen by the wallet. For swaps, redemptions, and token approvals, assetVer
+// is the version of the asset's swap contract.
+func (c *Core) PreviewGasFees(assetID uint32, txType asset.TransactionType, assetVer uint32, rates *asset.GasFeeRates) (*asset.GasFeePreview, error) {
+	w, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, err
+	}
+	setter, is := w.Wallet.(asset.GasFeeSetter)
+	if !is {
+		return nil, fmt.Errorf("%s wallet does not support setting gas fee rates", unbip(assetID))
+	}
+	return setter.PreviewGasFees(txType, assetVer, rates)
+}
+
+// SendWithGasFees sends the exact value from the asset's wallet to the
+// address, paying the specified EIP-1559 fee rates. Zero rates are chosen by
+// the wallet.
+func (c *Core) SendWithGasFees(pw []byte, assetID uint32, value uint64, address string, rates *asset.GasFeeRates) (asset.Coin, error) {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return nil, fmt.Errorf("Trade password error: %w", err)
+	}
+	defer crypter.Close()
+
+	if value == 0 {
+		return nil, fmt.Errorf("cannot send zero %s", unbip(assetID))
+	}
+	if rates == nil {
+		return nil, fmt.Errorf("no fee rates provided")
+	}
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	setter, is := wallet.Wallet.(asset.GasFeeSetter)
+	if !is {
+		return nil, fmt.Errorf("%s wallet does not support setting gas fee rates", unbip(assetID))
+	}
+	if err := c.connectAndUnlock(crypter, wallet); err != nil {
+		return nil, err
+	}
+	if err = wallet.checkPeersAndSyncStatus(); err != nil {
+		return nil, err
+	}
+	if address, err = c.resolveAddress(wallet, address); err != nil {
+		return nil, err
+	}
+
+	coin, err := setter.SendWithGasFees(address, value, rates)
+	if err != nil {
+		subject, details := c.formatDetails(TopicSendError, unbip(assetID), err)
+		c.notify(newSendNote(TopicSendError, subject, details, db.ErrorLevel))
+		return nil, err
+	}
+
+	sentValue := wallet.Info().UnitInfo.ConventionalString(coin.Value())
+	subject, details := c.formatDetails(TopicSendSuccess, sentValue, unbip(assetID), address, coin)
+	c.notify(newSendNote(TopicSendSuccess, subject, details, db.Success))
+
+	c.updateAssetBalance(assetID)
+
+	return coin, nil
+}
+
+// BumpFee replaces an unconfirmed send from the asset's wallet with one paying
+// a higher fee rate. The wallet must have been configured to signal
+// replaceability when the transaction was sent. The ID of the replacement
+// transaction is returned.
+func (c *Core) BumpFee(assetID uint32, txID string, feeRate uint64) (string, error) {
+	w, err := c.connectedWallet(assetID)
+	if err != nil {
+		return "", err
+	}
+	bumper, is := w.Wallet.(asset.FeeBumper)
+	if !is {
+		return "", fmt.Errorf("%s wallet does not support fee bumping", unbip(assetID))
+	}
+	if feeRate == 0 {
+		return "", fmt.Errorf("no fee rate provided")
+	}
+	newTxID, err := bumper.BumpFee(txID, feeRate)
+	if err != nil {
+		return "", err
+	}
+	c.updateAssetBalance(assetID)
+	return newTxID, nil
+}
+
+// nonceRepairer returns the connected wallet for the asset as an
+// asset.NonceRepairer. Tokens share the nonces of their parent asset's
+// wallet, which is returned with its asset ID.
+func (c *Core) nonceRepairer(assetID uint32) (asset.NonceRepairer, uint32, error) {
+	if token := asset.TokenInfo(assetID); token != nil {
+		assetID = token.ParentID
+	}
+	w, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, 0, err
+	}
+	repairer, is := w.Wallet.(asset.NonceRepairer)
+	if !is {
+		return nil, 0, fmt.Errorf("%s wallet does not support nonce repair", unbip(assetID))
+	}
+	return repairer, assetID, nil
+}
+
+// NonceStatus reports missing nonces and stuck transactions for the asset's
+// account-based wallet.
+func (c *Core) NonceStatus(assetID uint32) (*asset.NonceStatus, error) {
+	repairer, _, err := c.nonceRepairer(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return repairer.NonceStatus()
+}
+
+// ReplaceTx replaces an unconfirmed transaction from the asset's
+// account-based wallet with the same transaction paying higher fees. Nil
+// rates are chosen by the wallet. The ID of the replacement is returned.
+func (c *Core) ReplaceTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error) {
+	repairer, parentID, err := c.nonceRepairer(assetID)
+	if err != nil {
+		return "", err
+	}
+	newTxID, err := repairer.ReplaceTx(txID, rates)
+	if err != nil {
+		return "", err
+	}
+	c.updateAssetBalance(parentID)
+	return newTxID, nil
+}
+
+// CancelTx replaces an unconfirmed transaction from the asset's
+// account-based wallet with a zero-value send to the wallet's own address.
+// Nil rates are chosen by the wallet. The ID of the replacement is returned.
+func (c *Core) CancelTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error) {
+	repairer, parentID, err := c.nonceRepairer(assetID)
+	if err != nil {
+		return "", err
+	}
+	newTxID, err := repairer.CancelTx(txID, rates)
+	if err != nil {
+		return "", err
+	}
+	c.updateAssetBalance(parentID)
+	if assetID != parentID {
+		c.updateAssetBalance(assetID)
+	}
+	return newTxID, nil
+}
+
+// FillNonceGaps sends zero-value transactions from the asset's account-based
+// wallet to itself for any missing nonces. The IDs of the transactions are
+// returned.
+func (c *Core) FillNonceGaps(assetID uint32) ([]string, error) {
+	repairer, parentID, err := c.nonceRepairer(assetID)
+	if err != nil {
+		return nil, err
+	}
+	txIDs, err := repairer.FillNonceGaps()
+	if err != nil {
+		return txIDs, err
+	}
+	c.updateAssetBalance(parentID)
+	return txIDs, nil
+}
+
+// keySweeper returns the connected wallet for the asset as an
+// asset.KeySweeper.
+func (c *Core) keySweeper(assetID uint32) (*xcWallet, asset.KeySweeper, error) {
+	w, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sweeper, is := w.Wallet.(asset.KeySweeper)
+	if !is {
+		return nil, nil, fmt.Errorf("%s wallet cannot sweep private keys", unbip(assetID))
+	}
+	return w, sweeper, nil
+}
+
+// PrivateKeyFunds finds the funds controlled by an external private key,
+// which can be moved into the asset's wallet with SweepPrivateKey.
+func (c *Core) PrivateKeyFunds(assetID uint32, privKey string) (uint64, error) {
+	_, sweeper, err := c.keySweeper(assetID)
+	if err != nil {
+		return 0, err
+	}
+	return sweeper.PrivateKeyFunds(privKey)
+}
+
+// SweepPrivateKey sends all of the funds controlled by an external private
+// key, e.g. from a paper wallet, to the asset's wallet in one transaction. The
+// ID of the transaction and the amount received are returned.
+func (c *Core) SweepPrivateKey(pw []byte, assetID uint32, privKey string) (string, uint64, error) {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return "", 0, fmt.Errorf("password error: %w", err)
+	}
+	defer crypter.Close()
+
+	wallet, sweeper, err := c.keySweeper(assetID)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := wallet.Unlock(crypter); err != nil {
+		return "", 0, err
+	}
+	if err := wallet.checkPeersAndSyncStatus(); err != nil {
+		return "", 0, err
+	}
+
+	txID, received, err := sweeper.SweepPrivateKey(privKey, c.feeSuggestionAny(assetID))
+	if err != nil {
+		return "", 0, err
+	}
+	c.updateAssetBalance(assetID)
+	return txID, received, nil
+}
+
+// PegMWEB moves amt into the MimbleWimble Extension Block of the asset's
+// wallet if pegIn is true, or out of it otherwise. The ID of the transaction
+// is returned.
+func (c *Core) PegMWEB(pw []byte, assetID uint32, amt uint64, pegIn bool) (string, error) {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return "", fmt.Errorf("Trade password error: %w", err)
+	}
+	defer crypter.Close()
+
+	if amt == 0 {
+		return "", fmt.Errorf("cannot peg zero %s", unbip(assetID))
+	}
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return "", newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	pegger, is := wallet.Wallet.(asset.MWEBPegger)
+	if !is {
+		return "", fmt.Errorf("%s wallet does not support MWEB", unbip(assetID))
+	}
+	if err = c.connectAndUnlock(crypter, wallet); err != nil {
+		return "", err
+	}
+	if err = wallet.checkPeersAndSyncStatus(); err != nil {
+		return "", err
+	}
+
+	var txID string
+	if pegIn {
+		txID, err = pegger.PegIn(amt)
+	} else {
+		txID, err = pegger.PegOut(amt)
+	}
+	if err != nil {
+		return "", err
+	}
+	c.updateAssetBalance(assetID)
+	return txID, nil
+}
+
+// WalletUTXOs lists the unspent outputs of the asset's wallet. Outputs that
+// carry tokens are flagged.
+func (c *Core) WalletUTXOs(assetID uint32) ([]*asset.WalletUTXO, error) {
+	w, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, err
+	}
+	lister, is := w.Wallet.(asset.UTXOLister)
+	if !is {
+		return nil, fmt.Errorf("%s wallet does not support listing outputs", unbip(assetID))
+	}
+	return lister.ListUTXOs()
+}
+
+// externalSigner returns the connected wallet for the asset as an
+// asset.ExternalSigner.
+func (c *Core) externalSigner(assetID uint32) (asset.ExternalSigner, error) {
+	w, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, err
+	}
+	signer, is := w.Wallet.(asset.ExternalSigner)
+	if !is {
+		return nil, fmt.Errorf("%s wallet does not support external signing", unbip(assetID))
+	}
+	return signer, nil
+}
+
+// PendingPSBTs returns the transactions that are waiting to be signed with
+// the external signer of the asset's wallet.
+func (c *Core) PendingPSBTs(assetID uint32) ([]*asset.PSBTRequest, error) {
+	signer, err := c.externalSigner(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return signer.PendingPSBTs(), nil
+}
+
+// SubmitSignedPSBT submits a base64-encoded PSBT that was signed with the
+// external signer. The transaction is broadcast the next time the wallet
+// attempts to send it, e.g. on the next tick for swaps and redemptions.
+func (c *Core) SubmitSignedPSBT(assetID uint32, id, signedPSBT string) error {
+	signer, err := c.externalSigner(assetID)
+	if err != nil {
+		return err
+	}
+	return signer.SubmitSignedPSBT(id, signedPSBT)
+}
+
+// lightningWallet returns the connected wallet for the asset as an
+// asset.LightningTransferer.
+func (c *Core) lightningWallet(assetID uint32) (asset.LightningTransferer, error) {
+	w, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, err
+	}
+	ln, is := w.Wallet.(asset.LightningTransferer)
+	if !is {
+		return nil, fmt.Errorf("%s wallet does not support lightning transfers", unbip(assetID))
+	}
+	return ln, nil
 }
 
-// Send initiates either send or withdraw from an exchange wallet. if subtract
-// is true, fees are subtracted from the value else fees are taken from the
-// exchange wallet.
-func (c *Core) Send(pw []byte, assetID uint32, value uint64, address string, subtract bool) (asset.Coin, error) {
-	var crypter encrypt.Crypter
-	// Empty password can be provided if wallet is already unlocked. Webserver
-	// and RPCServer should not allow empty password, but this is used for
-	// bots.
-	if len(pw) > 0 {
-		var err error
-		crypter, err = c.encryptionKey(pw)
-		if err != nil {
-			return nil, fmt.Errorf("Trade password error: %w", err)
-		}
-		defer crypter.Close()
+// LightningBalance returns the amount that the asset's wallet can send over
+// the Lightning Network.
+func (c *Core) LightningBalance(assetID uint32) (uint64, error) {
+	ln, err := c.lightningWallet(assetID)
+	if err != nil {
+		return 0, err
 	}
+	return ln.LightningBalance(c.ctx)
+}
 
-	if value == 0 {
-		return nil, fmt.Errorf("cannot send/withdraw zero %s", unbip(assetID))
-	}
-	wallet, found := c.wallet(assetID)
-	if !found {
-		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+// LightningInvoice creates an invoice to receive funds over the Lightning
+// Network.
+func (c *Core) LightningInvoice(assetID uint32, amt uint64, memo string) (*asset.LightningInvoice, error) {
+	if amt == 0 {
+		return nil, fmt.Errorf("cannot create an invoice for zero %s", unbip(assetID))
 	}
-	err := c.connectAndUnlock(crypter, wallet)
+	ln, err := c.lightningWallet(assetID)
 	if err != nil {
 		return nil, err
 	}
+	return ln.LightningInvoice(c.ctx, amt, memo)
+}
 
-	if err = wallet.checkPeersAndSyncStatus(); err != nil {
-		return nil, err
+// LightningInvoiceStatus checks whether an invoice created with
+// LightningInvoice has been paid.
+func (c *Core) LightningInvoiceStatus(assetID uint32, paymentHash string) (settled bool, amtPaid uint64, err error) {
+	ln, err := c.lightningWallet(assetID)
+	if err != nil {
+		return false, 0, err
 	}
+	return ln.LightningInvoiceStatus(c.ctx, paymentHash)
+}
 
-	var coin asset.Coin
-	feeSuggestion := c.feeSuggestionAny(assetID)
-	if !subtract {
-		coin, err = wallet.Wallet.Send(address, value, feeSuggestion)
-	} else {
-		if withdrawer, isWithdrawer := wallet.Wallet.(asset.Withdrawer); isWithdrawer {
-			coin, err = withdrawer.Withdraw(address, value, feeSuggestion)
-		} else {
-			return nil, fmt.Errorf("wallet does not support subtracting network fee from withdraw amount")
+// PayLightningInvoice pays an invoice over the Lightning Network, paying at
+// most maxFee in routing fees. Like Send, an empty password can be used if
+// the wallet is already unlocked.
+func (c *Core) PayLightningInvoice(pw []byte, assetID uint32, invoice string, maxFee uint64) (*asset.LightningPayment, error) {
+	if len(pw) > 0 {
+		crypter, err := c.encryptionKey(pw)
+		if err != nil {
+			return nil, fmt.Errorf("password error: %w", err)
 		}
+		crypter.Close()
+	}
+	ln, err := c.lightningWallet(assetID)
+	if err != nil {
+		return nil, err
 	}
+	payment, err := ln.PayLightningInvoice(c.ctx, invoice, maxFee)
 	if err != nil {
 		subject, details := c.formatDetails(TopicSendError, unbip(assetID), err)
 		c.notify(newSendNote(TopicSendError, subject, details, db.ErrorLevel))
 		return nil, err
 	}
-
-	sentValue := wallet.Info().UnitInfo.ConventionalString(coin.Value())
-	subject, details := c.formatDetails(TopicSendSuccess, sentValue, unbip(assetID), address, coin)
-	c.notify(newSendNote(TopicSendSuccess, subject, details, db.Success))
-
 	c.updateAssetBalance(assetID)
-
-	return coin, nil
+	return payment, nil
 }
 
 // ValidateAddress checks that the provided address is valid.
@@ -5426,12 +6168,65 @@ func (c *Core) ValidateAddress(address string, assetID uint32) (bool, error) {
 	if !found {
 		return false, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
 	}
-	return wallet.Wallet.ValidateAddress(address), nil
+	resolved, err := c.resolveAddress(wallet, address)
+	if err != nil {
+		c.log.Debugf("Invalid %s address %q: %v", unbip(assetID), address, err)
+		return false, nil
+	}
+	return wallet.Wallet.ValidateAddress(resolved), nil
+}
+
+// ResolveAddress returns the address that a name, such as an ENS name,
+// resolves to, if the asset's wallet can resolve names. Addresses are returned
+// unchanged.
+func (c *Core) ResolveAddress(address string, assetID uint32) (string, error) {
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return "", newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	return c.resolveAddress(wallet, address)
+}
+
+// resolveAddress resolves the address with the wallet if it is not a valid
+// address, and the wallet is an asset.NameResolver. Otherwise, the address is
+// returned unchanged.
+func (c *Core) resolveAddress(wallet *xcWallet, address string) (string, error) {
+	if wallet.Wallet.ValidateAddress(address) {
+		return address, nil
+	}
+	resolver, is := wallet.Wallet.(asset.NameResolver)
+	if !is {
+		return address, nil
+	}
+	if !wallet.connected() {
+		return "", errWalletNotConnected
+	}
+	resolved, err := resolver.ResolveName(address)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %q: %w", address, err)
+	}
+	c.log.Infof("Resolved %s to %s address %s", address, unbip(wallet.AssetID), resolved)
+	return resolved, nil
 }
 
 // ApproveToken calls a wallet's ApproveToken method. It approves the version
 // of the token used by the dex at the specified address.
 func (c *Core) ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConfirm func()) (string, error) {
+	return c.approveToken(appPW, assetID, dexAddr, nil, onConfirm)
+}
+
+// ApproveTokenWithGasFees is like ApproveToken, but the approval transaction
+// pays the specified EIP-1559 fee rates. Zero rates are chosen by the wallet.
+func (c *Core) ApproveTokenWithGasFees(appPW []byte, assetID uint32, dexAddr string, rates *asset.GasFeeRates, onConfirm func()) (string, error) {
+	if rates == nil {
+		return "", fmt.Errorf("no fee rates provided")
+	}
+	return c.approveToken(appPW, assetID, dexAddr, rates, onConfirm)
+}
+
+// approveToken sends an approval transaction for the version of the token used
+// by the DEX. If rates is nil, the wallet chooses the fee rates.
+func (c *Core) approveToken(appPW []byte, assetID uint32, dexAddr string, rates *asset.GasFeeRates, onConfirm func()) (string, error) {
 	crypter, err := c.encryptionKey(appPW)
 	if err != nil {
 		return "", err
@@ -5470,7 +6265,12 @@ func (c *Core) ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConf
 		go c.notify(newTokenApprovalNote(wallet.state()))
 	}
 
-	txID, err := wallet.ApproveToken(asset.Version, walletOnConfirm)
+	var txID string
+	if rates == nil {
+		txID, err = wallet.ApproveToken(asset.Version, walletOnConfirm)
+	} else {
+		txID, err = wallet.ApproveTokenWithGasFees(asset.Version, rates, walletOnConfirm)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -5515,6 +6315,64 @@ func (c *Core) UnapproveToken(appPW []byte, assetID uint32, version uint32) (str
 	return txID, nil
 }
 
+// TokenAllowances returns the swap contract allowances of every connected
+// token wallet that can report them, keyed by asset ID.
+func (c *Core) TokenAllowances() (map[uint32][]*asset.TokenAllowance, error) {
+	allowances := make(map[uint32][]*asset.TokenAllowance)
+	for _, w := range c.xcWallets() {
+		manager, is := w.Wallet.(asset.TokenAllowanceManager)
+		if !is || !w.connected() {
+			continue
+		}
+		as, err := manager.TokenAllowances()
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s allowances: %w", unbip(w.AssetID), err)
+		}
+		allowances[w.AssetID] = as
+	}
+	return allowances, nil
+}
+
+// SetTokenAllowance sets the allowance of a version of the token's swap
+// contract to amount atomic units. A zero amount revokes the approval.
+func (c *Core) SetTokenAllowance(appPW []byte, assetID, version uint32, amount uint64) (string, error) {
+	crypter, err := c.encryptionKey(appPW)
+	if err != nil {
+		return "", err
+	}
+
+	wallet, err := c.connectedWallet(assetID)
+	if err != nil {
+		return "", err
+	}
+	manager, is := wallet.Wallet.(asset.TokenAllowanceManager)
+	if !is {
+		return "", fmt.Errorf("%s wallet cannot set token allowances", unbip(assetID))
+	}
+
+	err = wallet.Unlock(crypter)
+	if err != nil {
+		return "", err
+	}
+
+	err = wallet.checkPeersAndSyncStatus()
+	if err != nil {
+		return "", err
+	}
+
+	onConfirm := func() {
+		go c.notify(newTokenApprovalNote(wallet.state()))
+	}
+
+	txID, err := manager.SetTokenAllowance(version, amount, onConfirm)
+	if err != nil {
+		return "", err
+	}
+
+	c.notify(newTokenApprovalNote(wallet.state()))
+	return txID, nil
+}
+
 // ApproveTokenFee returns the fee for a token approval/unapproval.
 func (c *Core) ApproveTokenFee(assetID uint32, version uint32, approval bool) (uint64, error) {
 	wallet, err := c.connectedWallet(assetID)
@@ -5662,6 +6520,11 @@ func (c *Core) EstimateSendTxFee(address string, assetID uint32, amount uint64,
 		return 0, false, fmt.Errorf("wallet does not support fee estimation")
 	}
 
+	address, err = c.resolveAddress(wallet, address)
+	if err != nil {
+		return 0, false, err
+	}
+
 	return estimator.EstimateSendTxFee(address, amount, c.feeSuggestionAny(assetID), subtract, maxWithdraw)
 }
 
@@ -6889,9 +7752,10 @@ func (c *Core) authDEX(dc *dexConnection) error {
 	// Prepare and sign the message for the 'connect' route.
 	acctID := dc.acct.ID()
 	payload := &msgjson.Connect{
-		AccountID:  acctID[:],
-		APIVersion: 0,
-		Time:       uint64(time.Now().UnixMilli()),
+		AccountID:    acctID[:],
+		APIVersion:   uint16(supportedAPIVers[len(supportedAPIVers)-1]),
+		Time:         uint64(time.Now().UnixMilli()),
+		Capabilities: clientCapabilities,
 	}
 	sigMsg := payload.Serialize()
 	sig, err := dc.acct.sign(sigMsg)
@@ -6942,6 +7806,10 @@ func (c *Core) authDEX(dc *dexConnection) error {
 		return newError(signatureErr, "DEX signature validation error: %w", err)
 	}
 
+	dc.caps.Store(uint64(result.Capabilities))
+	c.log.Debugf("Negotiated API version %d and capabilities %#x with %s", result.APIVersion,
+		result.Capabilities, dc.acct.host)
+
 	// Check active and pending bonds, comparing against result.ActiveBonds. For
 	// pendingBonds, rebroadcast and start waiter to postBond. For
 	// (locally-confirmed) bonds that are not in connectResp.Bonds, postBond.
@@ -7270,19 +8138,56 @@ func pluralize(n int) string {
 }
 
 // initialize pulls the known DEXes from the database and attempts to connect
-// and retrieve the DEX configuration.
+// and retrieve the DEX configuration. If the database is encrypted, this is
+// deferred until login.
 func (c *Core) initialize() error {
+	// Custom tokens must be registered before their wallets are loaded and
+	// before DEX configs that may list them are processed. The registry
+	// tokens are registered first, so that a token that was also added with
+	// AddCustomToken isn't registered twice.
+	c.registerTokenRegistry()
+	c.pokesCache = newPokesCache(pokesCapacity)
+
+	if c.db.Locked() {
+		c.log.Infof("The database is encrypted. DEX accounts and wallets will be loaded at login.")
+		return nil
+	}
+
+	accts, err := c.loadFromDB()
+	if err != nil {
+		return err
+	}
+
+	// Check DB for active orders on any DEX.
+	for _, acct := range accts {
+		host, _ := addrHost(acct.Host)
+		activeOrders, _ := c.dbOrders(host) // non-nil error will load 0 orders, and any subsequent db error will cause a shutdown on dex auth or sooner
+		if n := len(activeOrders); n > 0 {
+			c.log.Warnf("\n\n\t ****  IMPORTANT: You have %d active order%s on %s. LOGIN immediately!  **** \n",
+				n, pluralize(n), host)
+		}
+	}
+
+	return nil
+}
+
+// loadFromDB registers the custom tokens, connects to the DEXes of the
+// accounts, loads the wallets and restores the pokes from the database. The
+// accounts are returned.
+func (c *Core) loadFromDB() ([]*db.AccountInfo, error) {
+	c.registerCustomTokens()
+
 	accts, err := c.db.Accounts()
 	if err != nil {
-		return fmt.Errorf("failed to retrieve accounts from database: %w", err)
+		return nil, fmt.Errorf("failed to retrieve accounts from database: %w", err)
 	}
 
 	pokes, err := c.db.LoadPokes()
-	c.pokesCache = newPokesCache(pokesCapacity)
 	if err != nil {
 		c.log.Errorf("Error loading pokes from db: %v", err)
 	} else {
-		c.pokesCache.init(pokes)
+		// Keep any pokes from before the database was unlocked.
+		c.pokesCache.init(append(pokes, c.pokes()...))
 	}
 
 	// Start connecting to DEX servers.
@@ -7315,7 +8220,7 @@ func (c *Core) initialize() error {
 	c.log.Infof("Connected to %d of %d DEX servers", liveConns, len(accts))
 
 	for _, dbWallet := range dbWallets {
-		if asset.Asset(dbWallet.AssetID) == nil && asset.TokenInfo(dbWallet.AssetID) == nil {
+		if asset.Asset(dbWallet.AssetID) == nil && asset.NetworkTokenInfo(c.net, dbWallet.AssetID) == nil {
 			c.log.Infof("Wallet for asset %s no longer supported", dex.BipIDSymbol(dbWallet.AssetID))
 			continue
 		}
@@ -7330,17 +8235,7 @@ func (c *Core) initialize() error {
 		c.updateWallet(assetID, wallet)
 	}
 
-	// Check DB for active orders on any DEX.
-	for _, acct := range accts {
-		host, _ := addrHost(acct.Host)
-		activeOrders, _ := c.dbOrders(host) // non-nil error will load 0 orders, and any subsequent db error will cause a shutdown on dex auth or sooner
-		if n := len(activeOrders); n > 0 {
-			c.log.Warnf("\n\n\t ****  IMPORTANT: You have %d active order%s on %s. LOGIN immediately!  **** \n",
-				n, pluralize(n), host)
-		}
-	}
-
-	return nil
+	return accts, nil
 }
 
 // connectAccount makes a connection to the DEX for the given account. If a
@@ -8278,6 +9173,7 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 		notify:            c.notify,
 		ticker:            newDexTicker(defaultTickInterval), // updated when server config obtained
 		books:             make(map[string]*bookie),
+		bookRecordDir:     c.cfg.BookRecordDir,
 		trades:            make(map[order.OrderID]*trackedTrade),
 		cancels:           make(map[order.OrderID]order.OrderID),
 		inFlightOrders:    make(map[uint64]*InFlightOrder),
@@ -8290,14 +9186,29 @@ func (c *Core) newDEXConnection(acctInfo *db.AccountInfo, flag connectDEXFlag) (
 	}
 
 	wsCfg := comms.WsCfg{
-		URL:      wsURL.String(),
-		PingWait: 20 * time.Second, // larger than server's pingPeriod (server/comms/server.go)
-		Cert:     acctInfo.Cert,
-		Logger:   c.log.SubLogger(wsURL.String()),
+		URL:               wsURL.String(),
+		PingWait:          20 * time.Second, // larger than server's pingPeriod (server/comms/server.go)
+		Cert:              acctInfo.Cert,
+		Logger:            c.log.SubLogger(wsURL.String()),
+		EnableCompression: !c.cfg.NoWSCompression,
+		EnableQUIC:        c.cfg.EnableQUIC,
+		EnableCBOR:        !c.cfg.NoCBOR,
 	}
 
 	isOnionHost := isOnionHost(wsURL.Host)
-	if isOnionHost || c.cfg.TorProxy != "" {
+	if proxy := c.hostProxy(host); proxy != nil {
+		if isOnionHost {
+			wsURL.Scheme = "ws"
+			wsCfg.URL = wsURL.String()
+		}
+		wsCfg.NetDialContext = proxyDialer(proxy, isOnionHost)
+	} else if c.torEnabled() {
+		if isOnionHost {
+			wsURL.Scheme = "ws"
+			wsCfg.URL = wsURL.String()
+		}
+		wsCfg.NetDialContext = c.torDialContext
+	} else if isOnionHost || c.cfg.TorProxy != "" {
 		proxyAddr := c.cfg.TorProxy
 		if isOnionHost {
 			if c.cfg.Onion == "" {
@@ -8468,30 +9379,7 @@ func (c *Core) handleReconnect(host string) {
 		}
 	}
 
-	// Update the orders' selfGoverned flag according to the configured markets.
-	for _, trade := range dc.trackedTrades() {
-		// If the server's market is gone, we're on our own, otherwise we are
-		// now free to swap for this order.
-		auto := mkts[trade.mktID] == nil
-		if !auto { // market exists, now check asset config and version
-			baseCfg := dc.assetConfig(trade.Base())
-			auto = baseCfg == nil || !trade.wallets.baseWallet.supportsVer(baseCfg.Version)
-		}
-		if !auto {
-			quoteCfg := dc.assetConfig(trade.Quote())
-			auto = quoteCfg == nil || !trade.wallets.quoteWallet.supportsVer(quoteCfg.Version)
-		}
-
-		if trade.setSelfGoverned(auto) {
-			if auto {
-				c.log.Warnf("DEX %v is MISSING/INCOMPATIBLE market %v for trade %v!", host, trade.mktID, trade.ID())
-			} else {
-				c.log.Infof("DEX %v with market %v restored for trade %v", host, trade.mktID, trade.ID())
-			}
-		}
-		// We could refresh the asset configs in the walletSet, but we'll stick
-		// to what we have recorded in OrderMetaData at time of order placement.
-	}
+	c.updateSelfGoverned(dc)
 
 	go dc.subPriceFeed()
 
@@ -8549,29 +9437,9 @@ func (c *Core) handleReconnect(host string) {
 
 		// Resubscribe since our old subscription was probably lost by the
 		// server when the connection dropped.
-		snap, err := dc.subscribe(mkt.base, mkt.quote)
-		if err != nil {
-			c.log.Errorf("handleReconnect: Failed to Subscribe to market %q 'orderbook': %v", mkt.name, err)
-			return
+		if err := dc.refreshBook(booky); err != nil {
+			c.log.Errorf("handleReconnect: market %q: %v", mkt.name, err)
 		}
-
-		// Create a fresh OrderBook for the bookie.
-		err = booky.Reset(snap)
-		if err != nil {
-			c.log.Errorf("handleReconnect: Failed to Sync market %q order book snapshot: %v", mkt.name, err)
-		}
-
-		// Send a FreshBookAction to the subscribers.
-		booky.send(&BookUpdate{
-			Action:   FreshBookAction,
-			Host:     dc.acct.host,
-			MarketID: mkt.name,
-			Payload: &MarketOrderBook{
-				Base:  mkt.base,
-				Quote: mkt.quote,
-				Book:  booky.book(),
-			},
-		})
 	}
 
 	// For each market, resubscribe to any market books.
@@ -8580,6 +9448,35 @@ func (c *Core) handleReconnect(host string) {
 	}
 }
 
+// updateSelfGoverned updates the tracked trades' selfGoverned flag according to
+// the server's configured markets and assets.
+func (c *Core) updateSelfGoverned(dc *dexConnection) {
+	host := dc.acct.host
+	for _, trade := range dc.trackedTrades() {
+		// If the server's market is gone, we're on our own, otherwise we are
+		// now free to swap for this order.
+		auto := dc.marketConfig(trade.mktID) == nil
+		if !auto { // market exists, now check asset config and version
+			baseCfg := dc.assetConfig(trade.Base())
+			auto = baseCfg == nil || !trade.wallets.baseWallet.supportsVer(baseCfg.Version)
+		}
+		if !auto {
+			quoteCfg := dc.assetConfig(trade.Quote())
+			auto = quoteCfg == nil || !trade.wallets.quoteWallet.supportsVer(quoteCfg.Version)
+		}
+
+		if trade.setSelfGoverned(auto) {
+			if auto {
+				c.log.Warnf("DEX %v is MISSING/INCOMPATIBLE market %v for trade %v!", host, trade.mktID, trade.ID())
+			} else {
+				c.log.Infof("DEX %v with market %v restored for trade %v", host, trade.mktID, trade.ID())
+			}
+		}
+		// We could refresh the asset configs in the walletSet, but we'll stick
+		// to what we have recorded in OrderMetaData at time of order placement.
+	}
+}
+
 func (dc *dexConnection) broadcastingConnect() bool {
 	return atomic.LoadUint32(&dc.reportingConnects) == 1
 }
@@ -8899,6 +9796,7 @@ var noteHandlers = map[string]routeHandler{
 	msgjson.TierChangeRoute:      handleTierChangeMsg,
 	msgjson.ScoreChangeRoute:     handleScoreChangeMsg,
 	msgjson.BondExpiredRoute:     handleBondExpiredMsg,
+	msgjson.ConfigUpdateRoute:    handleConfigUpdateMsg,
 }
 
 // listen monitors the DEX websocket connection for server requests and
@@ -9926,17 +10824,17 @@ func (c *Core) WalletRestorationInfo(pw []byte, assetID uint32) ([]*asset.Wallet
 	}
 	defer crypter.Close()
 
-	seed, _, err := c.assetSeedAndPass(assetID, crypter)
-	if err != nil {
-		return nil, fmt.Errorf("assetSeedAndPass error: %w", err)
-	}
-	defer encode.ClearBytes(seed)
-
 	wallet, found := c.wallet(assetID)
 	if !found {
 		return nil, fmt.Errorf("no wallet configured for asset %d", assetID)
 	}
 
+	seed, _, err := c.walletSeedAndPass(assetID, wallet.encSeed, crypter)
+	if err != nil {
+		return nil, fmt.Errorf("walletSeedAndPass error: %w", err)
+	}
+	defer encode.ClearBytes(seed)
+
 	restorer, ok := wallet.Wallet.(asset.WalletRestorer)
 	if !ok {
 		return nil, fmt.Errorf("wallet for asset %d doesn't support exporting functionality", assetID)
@@ -10235,6 +11133,21 @@ func (c *Core) DeleteArchivedRecordsWithBackup(olderThan *time.Time, saveMatches
 // records and file paths to save deleted records as comma separated values. If
 // a nil *time.Time is provided, current time is used.
 func (c *Core) DeleteArchivedRecords(olderThan *time.Time, matchesFile, ordersFile string) (int, error) {
+	nMatchesDeleted, err := c.deleteArchivedMatches(olderThan, 0, matchesFile)
+	if err != nil {
+		return 0, err
+	}
+	nOrdersDeleted, err := c.deleteArchivedOrders(olderThan, 0, ordersFile)
+	if err != nil {
+		return 0, err
+	}
+	return nOrdersDeleted + nMatchesDeleted, nil
+}
+
+// deleteArchivedMatches deletes archived matches older than olderThan, keeping
+// the newest keepNewest matches if it is non-zero. The deleted matches are
+// saved to matchesFile if it is not empty.
+func (c *Core) deleteArchivedMatches(olderThan *time.Time, keepNewest int, matchesFile string) (int, error) {
 	var (
 		err             error
 		perMtchFn       func(*db.MetaMatch, bool) error
@@ -10259,12 +11172,19 @@ func (c *Core) DeleteArchivedRecords(olderThan *time.Time, matchesFile, ordersFi
 
 	// Delete matches while saving to csv if available until the database
 	// says that's all or context is canceled.
-	nMatchesDeleted, err = c.db.DeleteInactiveMatches(c.ctx, olderThan, perMtchFn)
+	nMatchesDeleted, err = c.db.DeleteInactiveMatches(c.ctx, olderThan, keepNewest, perMtchFn)
 	if err != nil {
 		return 0, fmt.Errorf("unable to delete matches: %v", err)
 	}
+	return nMatchesDeleted, nil
+}
 
+// deleteArchivedOrders deletes archived orders older than olderThan, keeping
+// the newest keepNewest orders if it is non-zero. The deleted orders are saved
+// to ordersFile if it is not empty.
+func (c *Core) deleteArchivedOrders(olderThan *time.Time, keepNewest int, ordersFile string) (int, error) {
 	var (
+		err            error
 		perOrdFn       func(*db.MetaOrder) error
 		nOrdersDeleted int
 	)
@@ -10288,11 +11208,11 @@ func (c *Core) DeleteArchivedRecords(olderThan *time.Time, matchesFile, ordersFi
 
 	// Delete orders while saving to csv if available until the database
 	// says that's all or context is canceled.
-	nOrdersDeleted, err = c.db.DeleteInactiveOrders(c.ctx, olderThan, perOrdFn)
+	nOrdersDeleted, err = c.db.DeleteInactiveOrders(c.ctx, olderThan, keepNewest, perOrdFn)
 	if err != nil {
 		return 0, fmt.Errorf("unable to delete orders: %v", err)
 	}
-	return nOrdersDeleted + nMatchesDeleted, nil
+	return nOrdersDeleted, nil
 }
 
 // AccelerateOrder will use the Child-Pays-For-Parent technique to accelerate
@@ -10531,10 +11451,67 @@ func (c *Core) refreshFiatRates(ctx context.Context) {
 
 	fiatRatesMap := c.fiatConversions()
 	if len(fiatRatesMap) != 0 {
+		c.recordFiatRates(fiatRatesMap)
 		c.notify(newFiatRatesUpdate(fiatRatesMap))
 	}
 }
 
+// recordFiatRates records the fiat rates to the rate history, if it has been
+// at least fiatRateHistoryInterval since they were last recorded.
+func (c *Core) recordFiatRates(rates map[uint32]float64) {
+	now := time.Now()
+	if now.Sub(c.lastFiatRecord) < fiatRateHistoryInterval {
+		return
+	}
+	if err := c.db.SaveFiatRates(uint64(now.UnixMilli()), rates); err != nil {
+		c.log.Errorf("Error recording fiat rate history: %v", err)
+		return
+	}
+	c.lastFiatRecord = now
+}
+
+// FiatRateHistory returns the fiat rates of the asset recorded in the time
+// range, in milliseconds, oldest first. Since is inclusive, and until is
+// exclusive. Zero until means no limit. The rates are recorded hourly while
+// a fiat rate source is enabled.
+func (c *Core) FiatRateHistory(assetID uint32, since, until uint64) ([]*db.FiatRate, error) {
+	rates, err := c.db.FiatRateHistory(assetID, since, until)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving fiat rate history: %w", err)
+	}
+	return rates, nil
+}
+
+// FiatRateAt returns the fiat rate of the asset at the time stamp, in
+// milliseconds, for valuing past transactions at the rate of the time. The
+// rate is the recorded rate nearest to the time, or the current rate if it
+// is nearer. An error wrapping db.ErrNoFiatRate is returned if there is no
+// rate within fiatRateHistoryMaxGap of the time.
+func (c *Core) FiatRateAt(assetID uint32, stamp uint64) (*db.FiatRate, error) {
+	rate, err := c.db.FiatRateAt(assetID, stamp)
+	if err != nil && !errors.Is(err, db.ErrNoFiatRate) {
+		return nil, fmt.Errorf("error retrieving fiat rate: %w", err)
+	}
+	now := uint64(time.Now().UnixMilli())
+	if rate == nil || absDiff(now, stamp) < absDiff(rate.Stamp, stamp) {
+		if current, found := c.fiatConversions()[assetID]; found {
+			rate = &db.FiatRate{Stamp: now, Rate: current}
+		}
+	}
+	if rate == nil || absDiff(rate.Stamp, stamp) > uint64(fiatRateHistoryMaxGap.Milliseconds()) {
+		return nil, fmt.Errorf("%w for %s near %s", db.ErrNoFiatRate, dex.BipIDSymbol(assetID), time.UnixMilli(int64(stamp)))
+	}
+	return rate, nil
+}
+
+// absDiff is the absolute difference of two time stamps.
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
 // FiatRateSources returns a list of fiat rate sources and their individual
 // status.
 func (c *Core) FiatRateSources() map[string]bool {
@@ -10557,7 +11534,7 @@ func (c *Core) FiatConversionRates() map[uint32]float64 {
 // wallet.
 func (c *Core) fiatConversions() map[uint32]float64 {
 	assetIDs := make(map[uint32]struct{})
-	supportedAssets := asset.Assets()
+	supportedAssets := asset.NetworkAssets(c.net)
 	for assetID, asset := range supportedAssets {
 		assetIDs[assetID] = struct{}{}
 		for tokenID := range asset.Tokens {
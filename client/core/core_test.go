@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
 	"os"
 	"reflect"
 	"sort"
@@ -38,6 +39,7 @@ import (
 	serverdex "decred.org/dcrdex/server/dex"
 	"github.com/decred/dcrd/crypto/blake256"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/tyler-smith/go-bip39"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 )
@@ -336,6 +338,9 @@ func (conn *TWebsocket) RequestWithTimeout(msg *msgjson.Message, f func(*msgjson
 	}
 	return fmt.Errorf("no handler for route %q", msg.Route)
 }
+func (conn *TWebsocket) QueueRequest(msg *msgjson.Message, f func(*msgjson.Message), expireTime time.Duration, expire func()) error {
+	return conn.RequestWithTimeout(msg, f, expireTime, expire)
+}
 func (conn *TWebsocket) MessageSource() <-chan *msgjson.Message { return conn.msgs } // use when Core.listen is running
 func (conn *TWebsocket) IsDown() bool {
 	return false
@@ -381,11 +386,20 @@ type TDB struct {
 	setCredsErr              error
 	legacyKeyErr             error
 	recryptErr               error
+	fiatRateStamps           []uint64
+	fiatRateAt               *db.FiatRate
 	deleteInactiveOrdersErr  error
 	archivedOrders           int
 	deleteInactiveMatchesErr error
 	archivedMatches          int
 	updateAccountInfoErr     error
+	customTokens             map[uint32]*asset.CustomToken
+	explorerSettings         *db.ExplorerSettings
+	torSettings              *db.TorSettings
+	proxySettings            *db.ProxySettings
+	locked                   bool
+	unlockErr                error
+	accountsLoaded           bool
 }
 
 func (tdb *TDB) Run(context.Context) {}
@@ -395,6 +409,7 @@ func (tdb *TDB) ListAccounts() ([]string, error) {
 }
 
 func (tdb *TDB) Accounts() ([]*db.AccountInfo, error) {
+	tdb.accountsLoaded = true
 	return []*db.AccountInfo{}, nil
 }
 
@@ -543,11 +558,11 @@ func (tdb *TDB) SetPrimaryCredentials(creds *db.PrimaryCredentials) error {
 	return nil
 }
 
-func (tdb *TDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time, perBatchFn func(ords *db.MetaOrder) error) (int, error) {
+func (tdb *TDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time, keepNewest int, perBatchFn func(ords *db.MetaOrder) error) (int, error) {
 	return tdb.archivedOrders, tdb.deleteInactiveOrdersErr
 }
 
-func (tdb *TDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Time, perBatchFn func(mtchs *db.MetaMatch, isSell bool) error) (int, error) {
+func (tdb *TDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Time, keepNewest int, perBatchFn func(mtchs *db.MetaMatch, isSell bool) error) (int, error) {
 	return tdb.archivedMatches, tdb.deleteInactiveMatchesErr
 }
 
@@ -576,10 +591,62 @@ func (tdb *TDB) Recrypt(creds *db.PrimaryCredentials, oldCrypter, newCrypter enc
 	return nil, nil, nil
 }
 
+func (tdb *TDB) Unlock(crypter encrypt.Crypter) error {
+	if tdb.unlockErr != nil {
+		return tdb.unlockErr
+	}
+	tdb.locked = false
+	return nil
+}
+
+func (tdb *TDB) Locked() bool {
+	return tdb.locked
+}
+
 func (tdb *TDB) Backup() error {
 	return nil
 }
 
+func (tdb *TDB) Restore(src string) error {
+	return nil
+}
+
+func (tdb *TDB) Search(*db.SearchFilter) ([]*db.SearchResult, error) {
+	return nil, nil
+}
+
+func (tdb *TDB) MarketStats(*db.MarketStatsFilter) ([]*db.MarketStats, error) {
+	return nil, nil
+}
+
+func (tdb *TDB) SaveFiatRates(stamp uint64, rates map[uint32]float64) error {
+	tdb.fiatRateStamps = append(tdb.fiatRateStamps, stamp)
+	return nil
+}
+
+func (tdb *TDB) FiatRateHistory(assetID uint32, since, until uint64) ([]*db.FiatRate, error) {
+	return nil, nil
+}
+
+func (tdb *TDB) FiatRateAt(assetID uint32, stamp uint64) (*db.FiatRate, error) {
+	if tdb.fiatRateAt == nil {
+		return nil, db.ErrNoFiatRate
+	}
+	return tdb.fiatRateAt, nil
+}
+
+func (tdb *TDB) DeleteNotifications(olderThan *time.Time, keepNewest int) (int, error) {
+	return 0, nil
+}
+
+func (tdb *TDB) SaveRetentionSettings(*db.RetentionSettings) error {
+	return nil
+}
+
+func (tdb *TDB) RetentionSettings() (*db.RetentionSettings, error) {
+	return &db.RetentionSettings{}, nil
+}
+
 func (tdb *TDB) AckNotification(id []byte) error { return nil }
 
 func (tdb *TDB) SetLanguage(lang string) error {
@@ -589,6 +656,54 @@ func (tdb *TDB) Language() (string, error) {
 	return "en-US", nil
 }
 
+func (tdb *TDB) SaveCustomToken(tokenID uint32, ct *asset.CustomToken) error {
+	if tdb.customTokens == nil {
+		tdb.customTokens = make(map[uint32]*asset.CustomToken)
+	}
+	tdb.customTokens[tokenID] = ct
+	return nil
+}
+
+func (tdb *TDB) CustomTokens() (map[uint32]*asset.CustomToken, error) {
+	return tdb.customTokens, nil
+}
+
+func (tdb *TDB) SaveExplorerSettings(settings *db.ExplorerSettings) error {
+	tdb.explorerSettings = settings
+	return nil
+}
+
+func (tdb *TDB) ExplorerSettings() (*db.ExplorerSettings, error) {
+	if tdb.explorerSettings == nil {
+		return new(db.ExplorerSettings), nil
+	}
+	return tdb.explorerSettings, nil
+}
+
+func (tdb *TDB) SaveTorSettings(settings *db.TorSettings) error {
+	tdb.torSettings = settings
+	return nil
+}
+
+func (tdb *TDB) TorSettings() (*db.TorSettings, error) {
+	if tdb.torSettings == nil {
+		return new(db.TorSettings), nil
+	}
+	return tdb.torSettings, nil
+}
+
+func (tdb *TDB) SaveProxySettings(settings *db.ProxySettings) error {
+	tdb.proxySettings = settings
+	return nil
+}
+
+func (tdb *TDB) ProxySettings() (*db.ProxySettings, error) {
+	if tdb.proxySettings == nil {
+		return new(db.ProxySettings), nil
+	}
+	return tdb.proxySettings, nil
+}
+
 type tCoin struct {
 	id []byte
 
@@ -640,6 +755,7 @@ func (r *tReceipt) SignedRefund() dex.Bytes {
 type TXCWallet struct {
 	swapSize            uint64
 	sendFeeSuggestion   uint64
+	sendAddr            string
 	sendCoin            *tCoin
 	sendErr             error
 	addrErr             error
@@ -936,6 +1052,7 @@ func (w *TXCWallet) ConfirmTime(id dex.Bytes, nConfs uint32) (time.Time, error)
 
 func (w *TXCWallet) Send(address string, value, feeSuggestion uint64) (asset.Coin, error) {
 	w.sendFeeSuggestion = feeSuggestion
+	w.sendAddr = address
 	w.sendCoin.val = value
 	return w.sendCoin, w.sendErr
 }
@@ -1471,6 +1588,8 @@ func (rig *testRig) queueConnect(rpcErr *msgjson.Error, matches []*msgjson.Match
 			Score:               10,
 			Reputation:          &account.Reputation{BondedTier: 1},
 		}
+		result.APIVersion, result.Capabilities = msgjson.NegotiateAPI(serverdex.APIVersion, serverdex.Capabilities,
+			connect.APIVersion, connect.Capabilities)
 		if len(suspended) > 0 && suspended[0] {
 			result.Reputation.Penalties = 1
 		}
@@ -1580,16 +1699,18 @@ func TestBookFeed(t *testing.T) {
 	tCore := rig.core
 	dc := rig.dc
 
-	checkAction := func(feed BookFeed, action string) {
+	checkAction := func(feed BookFeed, action string) *BookUpdate {
 		t.Helper()
 		select {
 		case u := <-feed.Next():
 			if u.Action != action {
 				t.Fatalf("expected action = %s, got %s", action, u.Action)
 			}
+			return u
 		default:
 			t.Fatalf("no %s received", action)
 		}
+		return nil
 	}
 
 	// Ensure handleOrderBookMsg creates an order book as expected.
@@ -1831,6 +1952,28 @@ func TestBookFeed(t *testing.T) {
 	checkAction(feed2, EpochMatchSummary)
 	checkAction(feed2, CandleUpdateAction)
 	checkAction(feed2, CandleUpdateAction)
+
+	// A duration that the server doesn't provide is aggregated from an active
+	// server cache without another request.
+	if err := feed2.Candles("3d"); err != nil {
+		t.Fatalf("3d Candles error: %v", err)
+	}
+	u := checkAction(feed2, FreshCandlesAction)
+	if payload := u.Payload.(*CandlesPayload); payload.DurMilliSecs != uint64((72 * time.Hour).Milliseconds()) {
+		t.Fatalf("wrong aggregated candle duration %d", payload.DurMilliSecs)
+	}
+	if err := handleEpochReportMsg(tCore, dc, epochReport); err != nil {
+		t.Fatalf("handleEpochReportMsg error: %v", err)
+	}
+	checkAction(feed2, EpochMatchSummary)
+	for i := 0; i < 3; i++ {
+		checkAction(feed2, CandleUpdateAction)
+	}
+
+	// Durations must be a multiple of a server-provided duration.
+	if err := feed2.Candles("7m"); err == nil {
+		t.Fatalf("no error for 7m candles")
+	}
 }
 
 type tDriver struct {
@@ -1856,16 +1999,332 @@ type tCreator struct {
 	doesntExist bool
 	existsErr   error
 	createErr   error
+	seed        []byte
 }
 
 func (ctr *tCreator) Exists(walletType, dataDir string, settings map[string]string, net dex.Network) (bool, error) {
 	return !ctr.doesntExist, ctr.existsErr
 }
 
-func (ctr *tCreator) Create(*asset.CreateWalletParams) error {
+func (ctr *tCreator) Create(params *asset.CreateWalletParams) error {
+	ctr.seed = append([]byte(nil), params.Seed...)
 	return ctr.createErr
 }
 
+type tTokenRegistrar struct {
+	*tDriver
+}
+
+func (drv *tTokenRegistrar) RegisterCustomToken(ct *asset.CustomToken, net dex.Network) (uint32, *asset.Token, error) {
+	return 0x80000000 | ct.ParentID, &asset.Token{
+		Token: &dex.Token{
+			ParentID: ct.ParentID,
+			Name:     ct.Name,
+			UnitInfo: tWalletInfo.UnitInfo,
+		},
+		Definition:             &asset.WalletDefinition{Type: "token"},
+		ContractAddress:        ct.Address,
+		SupportedAssetVersions: []uint32{1},
+	}, nil
+}
+
+func TestCustomTokens(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	const parentID = 966
+	asset.Register(parentID, &tTokenRegistrar{&tDriver{winfo: tWalletInfo}})
+
+	ct := &asset.CustomToken{
+		ParentID: parentID,
+		Address:  "0x01",
+		Symbol:   "abc",
+		Name:     "ABC",
+		Decimals: 6,
+	}
+	const tokenID = 0x80000000 | parentID
+
+	// No parent wallet.
+	if _, err := tCore.AddCustomToken(tPW, ct); err == nil {
+		t.Fatalf("no error for missing parent wallet")
+	}
+	if asset.TokenInfo(tokenID) != nil {
+		t.Fatalf("token registered without parent wallet")
+	}
+
+	// Stored tokens are registered on startup.
+	rig.db.customTokens = map[uint32]*asset.CustomToken{tokenID: ct}
+	tCore.registerCustomTokens()
+	token := asset.TokenInfo(tokenID)
+	if token == nil || token.ContractAddress != ct.Address {
+		t.Fatalf("stored token not registered")
+	}
+	if sym := dex.BipIDSymbol(tokenID); sym != "abc.polygon" {
+		t.Fatalf("wrong symbol %q", sym)
+	}
+	// Registering again is skipped.
+	tCore.registerCustomTokens()
+}
+
+type tEVMTokenRegistrar struct {
+	*tTokenRegistrar
+	chainID int64
+}
+
+func (drv *tEVMTokenRegistrar) EVMChainID(net dex.Network) (int64, bool) {
+	return drv.chainID, true
+}
+
+func TestTokenRegistry(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	const parentID, chainID = 889, 88
+	asset.Register(parentID, &tEVMTokenRegistrar{&tTokenRegistrar{&tDriver{winfo: tWalletInfo}}, chainID})
+
+	tCore.tokenRegistry = &TokenRegistry{Tokens: []*RegistryToken{{
+		// Unknown chain is skipped.
+		EVMChainID: chainID + 1,
+		Address:    "0x02",
+		Symbol:     "def",
+		Name:       "DEF",
+	}, {
+		EVMChainID: chainID,
+		Address:    "0x01",
+		Symbol:     "abc",
+		Name:       "ABC",
+		Decimals:   6,
+	}}}
+	tCore.registerTokenRegistry()
+	const tokenID = 0x80000000 | parentID
+	token := asset.TokenInfo(tokenID)
+	if token == nil || token.ContractAddress != "0x01" {
+		t.Fatalf("registry token not registered")
+	}
+	if sym := dex.BipIDSymbol(tokenID); sym != "abc.tomo" {
+		t.Fatalf("wrong symbol %q", sym)
+	}
+	// The stored custom tokens skip the registry's tokens.
+	rig.db.customTokens = map[uint32]*asset.CustomToken{tokenID: {
+		ParentID: parentID,
+		Address:  "0x01",
+		Symbol:   "abc",
+		Name:     "ABC",
+		Decimals: 6,
+	}}
+	tCore.registerCustomTokens()
+}
+
+func TestBlockExplorers(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	tCore.net = dex.Mainnet
+
+	// Built-in clearnet explorer by default.
+	if e := tCore.BlockExplorer(tUTXOAssetA.ID); e == nil || e.Name != "dcrdata" {
+		t.Fatalf("wrong default dcr explorer %+v", e)
+	}
+
+	custom := &db.BlockExplorer{
+		Name:       " my dcrdata ",
+		TxURL:      "http://dcrdatasomethingsomething.onion/tx/{txid}",
+		AddressURL: "http://dcrdatasomethingsomething.onion/address/{address}",
+	}
+	settings := &db.ExplorerSettings{
+		PreferOnion: true,
+		Assets: map[uint32]*db.AssetExplorers{
+			tUTXOAssetA.ID: {Custom: []*db.BlockExplorer{custom}},
+		},
+	}
+	if err := tCore.UpdateBlockExplorers(settings); err != nil {
+		t.Fatalf("UpdateBlockExplorers error: %v", err)
+	}
+	if rig.db.explorerSettings == nil {
+		t.Fatalf("settings not stored")
+	}
+	// The custom onion explorer is preferred.
+	e := tCore.BlockExplorer(tUTXOAssetA.ID)
+	if e == nil || e.Name != "my dcrdata" || !e.Onion {
+		t.Fatalf("onion explorer not used %+v", e)
+	}
+	if user := tCore.User(); user.Explorers[tUTXOAssetA.ID] == nil || user.Explorers[tUTXOAssetA.ID].Name != "my dcrdata" {
+		t.Fatalf("wrong explorer for user")
+	}
+	// The selected explorer is used regardless.
+	settings.Assets[tUTXOAssetA.ID].Selected = "dcrdata"
+	if err := tCore.UpdateBlockExplorers(settings); err != nil {
+		t.Fatalf("UpdateBlockExplorers error: %v", err)
+	}
+	if e := tCore.BlockExplorer(tUTXOAssetA.ID); e == nil || e.Name != "dcrdata" {
+		t.Fatalf("selected explorer not used %+v", e)
+	}
+	var found bool
+	for _, ae := range tCore.BlockExplorers().Assets {
+		if ae.AssetID == tUTXOAssetA.ID {
+			found = true
+			if len(ae.Builtin) != 1 || len(ae.Custom) != 1 || ae.Selected != "dcrdata" {
+				t.Fatalf("wrong dcr explorers %+v", ae)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("no dcr explorers listed")
+	}
+
+	badSettings := func(name string, ae *db.AssetExplorers) {
+		t.Helper()
+		err := tCore.UpdateBlockExplorers(&db.ExplorerSettings{
+			Assets: map[uint32]*db.AssetExplorers{tUTXOAssetA.ID: ae},
+		})
+		if err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+	badSettings("unknown selection", &db.AssetExplorers{Selected: "nope"})
+	badSettings("duplicate name", &db.AssetExplorers{Custom: []*db.BlockExplorer{{Name: "dcrdata", TxURL: "https://a.org/{txid}"}}})
+	badSettings("no name", &db.AssetExplorers{Custom: []*db.BlockExplorer{{TxURL: "https://a.org/{txid}"}}})
+	badSettings("no txid", &db.AssetExplorers{Custom: []*db.BlockExplorer{{Name: "a", TxURL: "https://a.org/tx"}}})
+	badSettings("bad scheme", &db.AssetExplorers{Custom: []*db.BlockExplorer{{Name: "a", TxURL: "javascript:alert({txid})"}}})
+	badSettings("no vout", &db.AssetExplorers{Custom: []*db.BlockExplorer{{Name: "a", TxURL: "https://a.org/{txid}", OutputURL: "https://a.org/{txid}"}}})
+	if e := tCore.BlockExplorer(tUTXOAssetA.ID); e == nil || e.Name != "dcrdata" {
+		t.Fatalf("invalid settings changed the explorer")
+	}
+}
+
+func TestTorSettings(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	if status := tCore.TorSettings(); status.Settings.Enabled || status.Running || status.RestartRequired {
+		t.Fatalf("wrong default status %+v", status)
+	}
+
+	if err := tCore.UpdateTorSettings(&db.TorSettings{Enabled: true, SOCKSAddr: "127.0.0.1"}); err == nil {
+		t.Fatalf("no error for SOCKS address without a port")
+	}
+	if err := tCore.UpdateTorSettings(&db.TorSettings{Enabled: true, TorPath: "/not/a/tor"}); err == nil {
+		t.Fatalf("no error for missing tor executable")
+	}
+	settings := &db.TorSettings{Enabled: true, SOCKSAddr: "127.0.0.1:9050"}
+	if err := tCore.UpdateTorSettings(settings); err != nil {
+		t.Fatalf("UpdateTorSettings error: %v", err)
+	}
+	if rig.db.torSettings == nil || *rig.db.torSettings != *settings {
+		t.Fatalf("settings not stored")
+	}
+	// Changes take effect on restart.
+	status := tCore.TorSettings()
+	if !status.Settings.Enabled || !status.RestartRequired || status.Running {
+		t.Fatalf("wrong status after update %+v", status)
+	}
+	if tCore.newHTTPClient(db.ProxyServiceOracles) != http.DefaultClient {
+		t.Fatalf("tor client used before restart")
+	}
+
+	// With tor enabled at startup but unavailable, DEX connections are
+	// configured to dial through tor, and the dials fail.
+	tCore.torActive = &db.TorSettings{Enabled: true}
+	tCore.httpClients = map[string]*http.Client{db.ProxyServiceOracles: tCore.newHTTPClient(db.ProxyServiceOracles)}
+	var wsCfg *comms.WsCfg
+	ogConstructor := tCore.wsConstructor
+	tCore.wsConstructor = func(cfg *comms.WsCfg) (comms.WsConn, error) {
+		wsCfg = cfg
+		return ogConstructor(cfg)
+	}
+	if _, err := tCore.newDEXConnection(&db.AccountInfo{Host: "somedex.com", Cert: []byte{0x1}}, 0); err != nil {
+		t.Fatalf("newDEXConnection error: %v", err)
+	}
+	if wsCfg == nil || wsCfg.NetDialContext == nil {
+		t.Fatalf("tor dialer not used")
+	}
+	if _, err := wsCfg.NetDialContext(tCtx, "tcp", "somedex.com:7232"); err == nil {
+		t.Fatalf("no error dialing with tor unavailable")
+	}
+	if _, err := tCore.HTTPClient().Get("http://127.0.0.1:1"); err == nil || !strings.Contains(err.Error(), "tor") {
+		t.Fatalf("expected tor error for HTTP request, got %v", err)
+	}
+}
+
+func TestProxySettings(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	if status := tCore.ProxySettings(); len(status.Settings.Hosts) != 0 || status.RestartRequired {
+		t.Fatalf("wrong default status %+v", status)
+	}
+
+	for _, settings := range []*db.ProxySettings{
+		{Hosts: map[string]*db.ProxyConfig{"somedex.com": {SOCKSAddr: "127.0.0.1"}}},
+		{Hosts: map[string]*db.ProxyConfig{"somedex.com": nil}},
+		{Services: map[string]*db.ProxyConfig{"weather": {}}},
+	} {
+		if err := tCore.UpdateProxySettings(settings); err == nil {
+			t.Fatalf("no error for invalid settings %+v", settings)
+		}
+	}
+	settings := &db.ProxySettings{
+		Hosts: map[string]*db.ProxyConfig{
+			"somedex.com":  {SOCKSAddr: "127.0.0.1:1"},
+			"otherdex.com": {},
+		},
+		Services: map[string]*db.ProxyConfig{db.ProxyServiceFiatRates: {SOCKSAddr: "127.0.0.1:1"}},
+	}
+	if err := tCore.UpdateProxySettings(settings); err != nil {
+		t.Fatalf("UpdateProxySettings error: %v", err)
+	}
+	// Hosts are stored with their default port.
+	if rig.db.proxySettings == nil || rig.db.proxySettings.Hosts["somedex.com:7232"] == nil {
+		t.Fatalf("settings not stored")
+	}
+	// Changes take effect on restart.
+	if status := tCore.ProxySettings(); !status.RestartRequired {
+		t.Fatalf("restart not required after update")
+	}
+	if tCore.newHTTPClient(db.ProxyServiceFiatRates) != http.DefaultClient {
+		t.Fatalf("proxy used before restart")
+	}
+
+	// After restart, the DEX host and service proxies are used, even with tor
+	// enabled.
+	tCore.proxyActive = rig.db.proxySettings
+	tCore.torActive = &db.TorSettings{Enabled: true}
+	if status := tCore.ProxySettings(); status.RestartRequired {
+		t.Fatalf("restart required after restart")
+	}
+	var wsCfg *comms.WsCfg
+	ogConstructor := tCore.wsConstructor
+	tCore.wsConstructor = func(cfg *comms.WsCfg) (comms.WsConn, error) {
+		wsCfg = cfg
+		return ogConstructor(cfg)
+	}
+	if _, err := tCore.newDEXConnection(&db.AccountInfo{Host: "somedex.com", Cert: []byte{0x1}}, 0); err != nil {
+		t.Fatalf("newDEXConnection error: %v", err)
+	}
+	// Nothing listens on the proxy's port.
+	if _, err := wsCfg.NetDialContext(tCtx, "tcp", "somedex.com:7232"); err == nil || strings.Contains(err.Error(), "tor") {
+		t.Fatalf("expected proxy error, got %v", err)
+	}
+	client := tCore.newHTTPClient(db.ProxyServiceFiatRates)
+	if _, err := client.Get("http://127.0.0.1:2"); err == nil || strings.Contains(err.Error(), "tor") {
+		t.Fatalf("expected proxy error for HTTP request, got %v", err)
+	}
+	// The oracles have no proxy, so they use tor.
+	client = tCore.newHTTPClient(db.ProxyServiceOracles)
+	if _, err := client.Get("http://127.0.0.1:2"); err == nil || !strings.Contains(err.Error(), "tor") {
+		t.Fatalf("expected tor error for HTTP request, got %v", err)
+	}
+	// A direct connection is refused for a .onion host.
+	dial := proxyDialer(&db.ProxyConfig{}, true)
+	if _, err := dial(tCtx, "tcp", "abc.onion:7232"); err == nil {
+		t.Fatalf("no error for a direct connection to a .onion host")
+	}
+}
+
 func TestCreateWallet(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -1954,6 +2413,53 @@ func TestCreateWallet(t *testing.T) {
 	}
 }
 
+func TestCreateWalletFromMnemonic(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	const assetID uint32 = 54322
+	wallet, _ := newTWallet(assetID)
+	winfo := *tWalletInfo
+	winfo.AvailableWallets = []*asset.WalletDefinition{
+		{Type: "seeded", Seeded: true},
+		{Type: "rpc"},
+	}
+	creator := &tCreator{tDriver: &tDriver{wallet: wallet.Wallet, winfo: &winfo}}
+	asset.Register(assetID, creator)
+
+	// BIP-39 test vector.
+	const phrase = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	expSeed := bip39.NewSeed(phrase, "")
+
+	form := &WalletForm{
+		AssetID:  assetID,
+		Type:     "seeded",
+		Mnemonic: "abandon abandon abandon",
+	}
+	if err := tCore.CreateWallet(tPW, nil, form); err == nil {
+		t.Fatalf("no error for invalid seed phrase")
+	}
+
+	// Only seeded wallets can be created from a seed phrase.
+	form.Type, form.Mnemonic = "rpc", phrase
+	if err := tCore.CreateWallet(tPW, nil, form); err == nil {
+		t.Fatalf("no error for importing a seed to an unseeded wallet")
+	}
+
+	// Case and whitespace are normalized.
+	form.Type, form.Mnemonic = "seeded", "  "+strings.ToUpper(phrase)+"\n"
+	if err := tCore.CreateWallet(tPW, nil, form); err != nil {
+		t.Fatalf("CreateWallet error: %v", err)
+	}
+	if !bytes.Equal(creator.seed, expSeed) {
+		t.Fatalf("wallet not created from the imported seed")
+	}
+	if w, found := tCore.wallet(assetID); !found || len(w.encSeed) == 0 {
+		t.Fatalf("imported seed not stored with the wallet")
+	}
+}
+
 // TODO: TestGetDEXConfig
 /*
 func TestGetFee(t *testing.T) {
@@ -2351,6 +2857,9 @@ func TestLogin(t *testing.T) {
 	if err != nil || !rig.acct.authed() {
 		t.Fatalf("initial Login error: %v", err)
 	}
+	if !rig.dc.capabilities().Has(msgjson.CapBookChecksum) {
+		t.Fatalf("capabilities not negotiated")
+	}
 
 	// No encryption key.
 	unauth(rig.acct)
@@ -7284,6 +7793,28 @@ func TestResetAppPass(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
+
+	// An encrypted database is locked when the user forgot their password.
+	// The seed unlocks it, and the accounts are loaded.
+	rig.db.locked = true
+	rig.db.unlockErr = tErr
+	if err = tCore.ResetAppPass(tPW, seed); err == nil {
+		t.Fatalf("no error for database unlock error")
+	}
+	if !rig.db.locked {
+		t.Fatalf("database unlocked after unlock error")
+	}
+	rig.db.unlockErr = nil
+	rig.db.accountsLoaded = false
+	if err = tCore.ResetAppPass(tPW, seed); err != nil {
+		t.Fatalf("error resetting password on a locked database: %v", err)
+	}
+	if rig.db.locked {
+		t.Fatalf("database not unlocked")
+	}
+	if !rig.db.accountsLoaded {
+		t.Fatalf("accounts not loaded after unlocking the database")
+	}
 }
 
 func TestReconfigureWallet(t *testing.T) {
@@ -9826,6 +10357,116 @@ func TestRefreshServerConfig(t *testing.T) {
 	}
 }
 
+func TestHandleConfigUpdateMsg(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+
+	feed := rig.core.NotificationFeed()
+
+	// Modify one market and delist the other.
+	cfg := *rig.dc.cfg
+	mkt := *cfg.Markets[0]
+	mkt.LotSize *= 2
+	mkt.EpochLen *= 2
+	cfg.Markets = []*msgjson.Market{&mkt}
+
+	note, _ := msgjson.NewNotification(msgjson.ConfigUpdateRoute, &cfg)
+	if err := handleConfigUpdateMsg(rig.core, rig.dc, note); err != nil {
+		t.Fatalf("handleConfigUpdateMsg error: %v", err)
+	}
+	mktCfg := rig.dc.marketConfig(tDcrBtcMktName)
+	if mktCfg == nil || mktCfg.LotSize != dcrBtcLotSize*2 {
+		t.Fatalf("market config not updated")
+	}
+	if rig.dc.marketEpochDuration(tDcrBtcMktName) != mkt.EpochLen {
+		t.Fatalf("market epoch duration not updated")
+	}
+	if rig.dc.marketConfig(tBtcEthMktName) != nil {
+		t.Fatalf("delisted market still configured")
+	}
+
+out:
+	for {
+		select {
+		case n := <-feed.C:
+			if n.Type() == NoteTypeServerNotify && n.Topic() == TopicServerConfigUpdate {
+				break out
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no server config update notification")
+		}
+	}
+
+	// A scheduled change is announced.
+	mkt.ScheduledChange = &msgjson.MarketChange{
+		FinalEpoch: 1000,
+		LotSize:    dcrBtcLotSize * 4,
+	}
+	note, _ = msgjson.NewNotification(msgjson.ConfigUpdateRoute, &cfg)
+	if err := handleConfigUpdateMsg(rig.core, rig.dc, note); err != nil {
+		t.Fatalf("handleConfigUpdateMsg error: %v", err)
+	}
+	coreMkt := rig.dc.coreMarket(tDcrBtcMktName)
+	if coreMkt == nil || coreMkt.ScheduledChange == nil || coreMkt.ScheduledChange.LotSize != dcrBtcLotSize*4 {
+		t.Fatalf("scheduled change not set")
+	}
+scheduled:
+	for {
+		select {
+		case n := <-feed.C:
+			if n.Topic() == TopicMarketChangeScheduled {
+				if !strings.Contains(n.Details(), "lot size") {
+					t.Fatalf("lot size not described: %q", n.Details())
+				}
+				break scheduled
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no market change scheduled notification")
+		}
+	}
+
+	// A maintenance window is announced with the reason.
+	const reason = "wallet upgrade"
+	mkt.Maintenance = &msgjson.Maintenance{
+		FinalEpoch:  2000,
+		SuspendTime: 2001 * mkt.EpochLen,
+		ResumeTime:  2100 * mkt.EpochLen,
+		Reason:      reason,
+	}
+	note, _ = msgjson.NewNotification(msgjson.ConfigUpdateRoute, &cfg)
+	if err := handleConfigUpdateMsg(rig.core, rig.dc, note); err != nil {
+		t.Fatalf("handleConfigUpdateMsg error: %v", err)
+	}
+	coreMkt = rig.dc.coreMarket(tDcrBtcMktName)
+	if coreMkt == nil || coreMkt.Maintenance == nil || coreMkt.Maintenance.Reason != reason {
+		t.Fatalf("maintenance not set")
+	}
+maintenance:
+	for {
+		select {
+		case n := <-feed.C:
+			if n.Topic() == TopicMarketChangeScheduled {
+				t.Fatalf("market change announced again")
+			}
+			if n.Topic() == TopicMaintenanceScheduled {
+				if !strings.Contains(n.Details(), reason) {
+					t.Fatalf("reason not in details: %q", n.Details())
+				}
+				break maintenance
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no maintenance scheduled notification")
+		}
+	}
+
+	// Inconsistent config.
+	mkt.Base = ^uint32(0)
+	note, _ = msgjson.NewNotification(msgjson.ConfigUpdateRoute, &cfg)
+	if err := handleConfigUpdateMsg(rig.core, rig.dc, note); err == nil {
+		t.Fatalf("no error for inconsistent config")
+	}
+}
+
 func TestCredentialHandling(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -10322,6 +10963,57 @@ func TestFiatConversions(t *testing.T) {
 	}
 }
 
+func TestFiatRateHistory(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	now := uint64(time.Now().UnixMilli())
+	hour := uint64(time.Hour.Milliseconds())
+
+	// No recorded or current rates.
+	if _, err := tCore.FiatRateAt(tUTXOAssetA.ID, now); !errors.Is(err, db.ErrNoFiatRate) {
+		t.Fatalf("expected ErrNoFiatRate, got %v", err)
+	}
+
+	// A recorded rate is used within the max gap.
+	rig.db.fiatRateAt = &db.FiatRate{Stamp: now - 48*hour, Rate: 40}
+	r, err := tCore.FiatRateAt(tUTXOAssetA.ID, now-40*hour)
+	if err != nil {
+		t.Fatalf("FiatRateAt error: %v", err)
+	}
+	if r.Rate != 40 {
+		t.Fatalf("expected recorded rate 40, got %f", r.Rate)
+	}
+	if _, err := tCore.FiatRateAt(tUTXOAssetA.ID, now-20*hour); !errors.Is(err, db.ErrNoFiatRate) {
+		t.Fatalf("expected ErrNoFiatRate beyond the max gap, got %v", err)
+	}
+
+	// Refreshing the rates records them once per fiatRateHistoryInterval.
+	for token := range fiatRateFetchers {
+		tCore.fiatRateSources[token] = newCommonRateSource(tFetcher)
+	}
+	tCore.refreshFiatRates(tCtx)
+	tCore.refreshFiatRates(tCtx)
+	if len(rig.db.fiatRateStamps) != 1 {
+		t.Fatalf("expected 1 fiat rate recording, got %d", len(rig.db.fiatRateStamps))
+	}
+
+	// The current rate is used if it is nearer than the recorded rate.
+	if r, err = tCore.FiatRateAt(tUTXOAssetA.ID, now-20*hour); err != nil {
+		t.Fatalf("FiatRateAt error: %v", err)
+	}
+	if r.Rate != 45 {
+		t.Fatalf("expected current rate 45, got %f", r.Rate)
+	}
+	if r, err = tCore.FiatRateAt(tUTXOAssetA.ID, now-46*hour); err != nil {
+		t.Fatalf("FiatRateAt error: %v", err)
+	}
+	if r.Rate != 40 {
+		t.Fatalf("expected recorded rate 40, got %f", r.Rate)
+	}
+}
+
 func TestValidateAddress(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -10367,6 +11059,77 @@ func TestValidateAddress(t *testing.T) {
 	}
 }
 
+type tNameResolver struct {
+	*TXCWallet
+	names map[string]string
+}
+
+func (w *tNameResolver) ValidateAddress(address string) bool {
+	for _, addr := range w.names {
+		if addr == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *tNameResolver) ResolveName(name string) (string, error) {
+	if addr, found := w.names[name]; found {
+		return addr, nil
+	}
+	return "", errors.New("unknown name")
+}
+
+func TestResolveAddress(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	wallet, tWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = wallet
+	tWallet.sendCoin = &tCoin{id: encode.RandomBytes(36)}
+	const name, addr = "name.eth", "0xaddr"
+	wallet.Wallet = &tNameResolver{
+		TXCWallet: tWallet,
+		names:     map[string]string{name: addr},
+	}
+
+	for _, test := range []struct {
+		addr, resolved string
+		valid          bool
+	}{
+		{name, addr, true},
+		{addr, addr, true},
+		{"unknown.eth", "", false},
+	} {
+		if valid, _ := tCore.ValidateAddress(test.addr, tUTXOAssetA.ID); valid != test.valid {
+			t.Fatalf("%s: wanted valid = %t", test.addr, test.valid)
+		}
+		resolved, err := tCore.ResolveAddress(test.addr, tUTXOAssetA.ID)
+		if (err == nil) != test.valid || resolved != test.resolved {
+			t.Fatalf("%s: wrong resolution %q, %v", test.addr, resolved, err)
+		}
+	}
+
+	// Names are resolved before sending.
+	if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, name, false); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	if tWallet.sendAddr != addr {
+		t.Fatalf("sent to %q, not the resolved address", tWallet.sendAddr)
+	}
+	tWallet.sendAddr = ""
+	if _, err := tCore.Send(tPW, tUTXOAssetA.ID, 1e8, "unknown.eth", false); err == nil || tWallet.sendAddr != "" {
+		t.Fatalf("no error sending to an unknown name")
+	}
+
+	// A disconnected wallet can't resolve names.
+	wallet.hookedUp = false
+	if _, err := tCore.ResolveAddress(name, tUTXOAssetA.ID); !errors.Is(err, errWalletNotConnected) {
+		t.Fatalf("wrong error for disconnected wallet: %v", err)
+	}
+}
+
 func TestEstimateSendTxFee(t *testing.T) {
 	rig := newTestRig()
 	defer rig.shutdown()
@@ -11290,3 +12053,51 @@ func TestTakeAction(t *testing.T) {
 	}
 
 }
+
+func TestReputationBreakdown(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	rig.acct.authMtx.Lock()
+	rig.acct.isAuthed = true
+	rig.acct.authMtx.Unlock()
+
+	// Server without the capability.
+	rig.dc.caps.Store(uint64(msgjson.CapBookChecksum))
+	if _, err := rig.core.ReputationBreakdown(tDexHost); err == nil {
+		t.Fatalf("no error for server without reputation capability")
+	}
+
+	rig.dc.caps.Store(uint64(msgjson.CapBookChecksum | msgjson.CapReputation))
+	res := &msgjson.ReputationResult{
+		Reputation: &account.Reputation{BondedTier: 2, Penalties: 1, Score: -25},
+		Matches: []*msgjson.ScoredMatch{{
+			MatchID: encode.RandomBytes(32),
+			Outcome: "no swap as taker",
+			Score:   -11,
+		}},
+	}
+	rig.ws.queueResponse(msgjson.ReputationRoute, func(msg *msgjson.Message, f msgFunc) error {
+		resp, _ := msgjson.NewResponse(msg.ID, res, nil)
+		f(resp)
+		return nil
+	})
+	reRes, err := rig.core.ReputationBreakdown(tDexHost)
+	if err != nil {
+		t.Fatalf("ReputationBreakdown error: %v", err)
+	}
+	if len(reRes.Matches) != 1 || reRes.Matches[0].Score != -11 {
+		t.Fatalf("wrong matches returned: %+v", reRes.Matches)
+	}
+	rig.acct.authMtx.RLock()
+	rep := rig.acct.rep
+	rig.acct.authMtx.RUnlock()
+	if rep != *res.Reputation {
+		t.Fatalf("account reputation not updated. wanted %+v, got %+v", *res.Reputation, rep)
+	}
+
+	// Not logged in.
+	rig.acct.unAuth()
+	if _, err := rig.core.ReputationBreakdown(tDexHost); err == nil {
+		t.Fatalf("no error for logged out account")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+
+	"decred.org/dcrdex/client/asset"
 )
 
 type testErr string
@@ -84,3 +86,54 @@ func TestUnwrapErr(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitAwaitingSignature(t *testing.T) {
+	awaitingErr := fmt.Errorf("swap: %w", asset.ErrAwaitingSignature)
+	tests := []struct {
+		name         string
+		in           error
+		wantAwaiting bool
+		wantErr      error
+	}{{
+		name: "nil",
+	}, {
+		name:         "awaiting only",
+		in:           newErrorSet("").addErr(awaitingErr).ifAny(),
+		wantAwaiting: true,
+	}, {
+		name:    "other only",
+		in:      newErrorSet("").addErr(err0).ifAny(),
+		wantErr: err0,
+	}, {
+		name:         "awaiting and other",
+		in:           newErrorSet("").addErr(awaitingErr).addErr(err0).addErr(awaitingErr).ifAny(),
+		wantAwaiting: true,
+		wantErr:      err0,
+	}, {
+		name:         "not a set",
+		in:           awaitingErr,
+		wantAwaiting: true,
+	}, {
+		name:    "plain error",
+		in:      err2,
+		wantErr: err2,
+	}}
+	for _, tt := range tests {
+		awaiting, err := splitAwaitingSignature(tt.in)
+		if awaiting != tt.wantAwaiting {
+			t.Fatalf("%s: wanted awaiting = %t, got %t", tt.name, tt.wantAwaiting, awaiting)
+		}
+		if tt.wantErr == nil {
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("%s: wanted error %v, got %v", tt.name, tt.wantErr, err)
+		}
+		if errors.Is(err, asset.ErrAwaitingSignature) {
+			t.Fatalf("%s: awaiting signature error not removed: %v", tt.name, err)
+		}
+	}
+}
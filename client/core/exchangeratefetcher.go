@@ -22,6 +22,12 @@ const (
 	// fiatRateDataExpiry : Any data older than fiatRateDataExpiry will be discarded.
 	fiatRateDataExpiry = 60 * time.Minute
 	fiatRequestTimeout = time.Second * 5
+	// fiatRateHistoryInterval is the minimum time between recordings of the
+	// fiat rates to the rate history.
+	fiatRateHistoryInterval = time.Hour
+	// fiatRateHistoryMaxGap is the longest time between a requested time and
+	// the nearest recorded rate for the rate to be used for that time.
+	fiatRateHistoryMaxGap = 24 * time.Hour
 
 	// Tokens. Used to identify fiat rate source, source name must not contain a
 	// comma.
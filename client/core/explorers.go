@@ -0,0 +1,324 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+)
+
+const (
+	explorerTxID    = "{txid}"
+	explorerVout    = "{vout}"
+	explorerAddress = "{address}"
+)
+
+// utxoExplorer is an explorer with only a transaction link.
+func utxoExplorer(name, txURL string) *db.BlockExplorer {
+	return &db.BlockExplorer{Name: name, TxURL: txURL}
+}
+
+// evmExplorer is an Etherscan-style explorer at the root URL.
+func evmExplorer(name, root string) *db.BlockExplorer {
+	return &db.BlockExplorer{
+		Name:       name,
+		TxURL:      root + "/tx/" + explorerTxID,
+		AddressURL: root + "/address/" + explorerAddress,
+	}
+}
+
+// dcrdataExplorer is a dcrdata explorer at the root URL.
+func dcrdataExplorer(name, root string) *db.BlockExplorer {
+	return &db.BlockExplorer{
+		Name:       name,
+		TxURL:      root + "/tx/" + explorerTxID,
+		OutputURL:  root + "/tx/" + explorerTxID + "/out/" + explorerVout,
+		AddressURL: root + "/address/" + explorerAddress,
+	}
+}
+
+// mempoolExplorer is a mempool.space explorer at the root URL.
+func mempoolExplorer(name, root string, onion bool) *db.BlockExplorer {
+	return &db.BlockExplorer{
+		Name:       name,
+		TxURL:      root + "/tx/" + explorerTxID,
+		AddressURL: root + "/address/" + explorerAddress,
+		Onion:      onion,
+	}
+}
+
+// builtinExplorers are the block explorers for each base chain asset and
+// network that the user doesn't need to add. Unless the user selects another,
+// the first clearnet explorer is used, or the first onion explorer if the user
+// prefers onion explorers.
+var builtinExplorers = map[uint32]map[dex.Network][]*db.BlockExplorer{
+	42: { // dcr
+		dex.Mainnet: {dcrdataExplorer("dcrdata", "https://explorer.dcrdata.org")},
+		dex.Testnet: {dcrdataExplorer("dcrdata", "https://testnet.dcrdata.org")},
+		dex.Simnet:  {dcrdataExplorer("dcrdata", "http://127.0.0.1:17779")},
+	},
+	0: { // btc
+		dex.Mainnet: {
+			mempoolExplorer("mempool.space", "https://mempool.space", false),
+			mempoolExplorer("mempool.space (onion)", "http://mempoolhqx4isw62xs7abwphsq7ldayuidyx2v2oethdhhj6mlo2r6ad.onion", true),
+		},
+		dex.Testnet: {
+			mempoolExplorer("mempool.space", "https://mempool.space/testnet", false),
+			mempoolExplorer("mempool.space (onion)", "http://mempoolhqx4isw62xs7abwphsq7ldayuidyx2v2oethdhhj6mlo2r6ad.onion/testnet", true),
+		},
+		dex.Simnet: {mempoolExplorer("mempool.space", "https://mempool.space", false)},
+	},
+	2: { // ltc
+		dex.Mainnet: {utxoExplorer("bitaps", "https://ltc.bitaps.com/{txid}")},
+		dex.Testnet: {utxoExplorer("SoChain", "https://sochain.com/tx/LTCTEST/{txid}")},
+		dex.Simnet:  {utxoExplorer("bitaps", "https://ltc.bitaps.com/{txid}")},
+	},
+	20: { // dgb
+		dex.Mainnet: {utxoExplorer("DigiExplorer", "https://digiexplorer.info/tx/{txid}")},
+		dex.Testnet: {utxoExplorer("DigiByte Servers", "https://testnetexplorer.digibyteservers.io/tx/{txid}")},
+		dex.Simnet:  {utxoExplorer("DigiExplorer", "https://digiexplorer.info/tx/{txid}")},
+	},
+	3: { // doge
+		dex.Mainnet: {utxoExplorer("Dogeblocks", "https://dogeblocks.com/tx/{txid}")},
+		dex.Testnet: {utxoExplorer("BlockExplorer.One", "https://blockexplorer.one/dogecoin/testnet/tx/{txid}")},
+		dex.Simnet:  {utxoExplorer("Dogeblocks", "https://dogeblocks.com/tx/{txid}")},
+	},
+	5: { // dash
+		dex.Mainnet: {utxoExplorer("BlockExplorer.One", "https://blockexplorer.one/dash/mainnet/tx/{txid}")},
+		dex.Testnet: {utxoExplorer("BlockExplorer.One", "https://blockexplorer.one/dash/testnet/tx/{txid}")},
+		dex.Simnet:  {utxoExplorer("BlockExplorer.One", "https://blockexplorer.one/dash/mainnet/tx/{txid}")},
+	},
+	133: { // zec
+		dex.Mainnet: {utxoExplorer("Zcash Block Explorer", "https://zcashblockexplorer.com/transactions/{txid}")},
+		dex.Testnet: {utxoExplorer("BlockExplorer.One", "https://blockexplorer.one/zcash/testnet/tx/{txid}")},
+		dex.Simnet:  {utxoExplorer("Zcash Block Explorer", "https://zcashblockexplorer.com/transactions/{txid}")},
+	},
+	147: { // zcl
+		dex.Mainnet: {utxoExplorer("Zelcore", "https://explorer.zcl.zelcore.io/tx/{txid}")},
+		dex.Simnet:  {utxoExplorer("Zelcore", "https://explorer.zcl.zelcore.io/tx/{txid}")},
+	},
+	136: { // firo
+		dex.Mainnet: {utxoExplorer("Firo Explorer", "https://explorer.firo.org/tx/{txid}")},
+		dex.Testnet: {utxoExplorer("Firo Explorer", "https://testexplorer.firo.org/tx/{txid}")},
+		dex.Simnet:  {utxoExplorer("Firo Explorer", "https://explorer.firo.org/tx/{txid}")},
+	},
+	145: { // bch
+		dex.Mainnet: {utxoExplorer("loping.net", "https://bch.loping.net/tx/{txid}")},
+		dex.Testnet: {utxoExplorer("loping.net", "https://tbch4.loping.net/tx/{txid}")},
+		dex.Simnet:  {utxoExplorer("loping.net", "https://bch.loping.net/tx/{txid}")},
+	},
+	128: { // xmr
+		dex.Mainnet: {utxoExplorer("xmrchain.net", "https://xmrchain.net/tx/{txid}")},
+	},
+	60: { // eth
+		dex.Mainnet: {evmExplorer("Etherscan", "https://etherscan.io")},
+		dex.Testnet: {evmExplorer("Etherscan", "https://sepolia.etherscan.io")},
+		dex.Simnet:  {evmExplorer("Etherscan", "https://etherscan.io")},
+	},
+	966: { // polygon
+		dex.Mainnet: {evmExplorer("PolygonScan", "https://polygonscan.com")},
+		dex.Testnet: {evmExplorer("PolygonScan", "https://amoy.polygonscan.com")},
+		dex.Simnet:  {evmExplorer("PolygonScan", "https://polygonscan.com")},
+	},
+	9001: { // arb
+		dex.Mainnet: {evmExplorer("Arbiscan", "https://arbiscan.io")},
+		dex.Testnet: {evmExplorer("Arbiscan", "https://sepolia.arbiscan.io")},
+		dex.Simnet:  {evmExplorer("Arbiscan", "https://arbiscan.io")},
+	},
+}
+
+// validateExplorerURL checks that the link template is an http(s) URL with
+// the required placeholders, and reports whether it is an onion URL.
+func validateExplorerURL(tmpl string, placeholders ...string) (onion bool, err error) {
+	for _, p := range placeholders {
+		if !strings.Contains(tmpl, p) {
+			return false, fmt.Errorf("%q does not contain %s", tmpl, p)
+		}
+	}
+	u, err := url.Parse(strings.NewReplacer(explorerTxID, "txid", explorerVout, "0", explorerAddress, "address").Replace(tmpl))
+	if err != nil {
+		return false, fmt.Errorf("invalid URL %q: %w", tmpl, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false, fmt.Errorf("%q is not an http or https URL", tmpl)
+	}
+	if u.Hostname() == "" {
+		return false, fmt.Errorf("%q has no host", tmpl)
+	}
+	return strings.HasSuffix(u.Hostname(), ".onion"), nil
+}
+
+// validateExplorer validates a user-defined explorer, and sets Onion from its
+// transaction link.
+func validateExplorer(e *db.BlockExplorer) error {
+	e.Name = strings.TrimSpace(e.Name)
+	if e.Name == "" {
+		return errors.New("no explorer name")
+	}
+	var err error
+	if e.Onion, err = validateExplorerURL(e.TxURL, explorerTxID); err != nil {
+		return fmt.Errorf("%s transaction link: %w", e.Name, err)
+	}
+	if e.OutputURL != "" {
+		if _, err := validateExplorerURL(e.OutputURL, explorerTxID, explorerVout); err != nil {
+			return fmt.Errorf("%s output link: %w", e.Name, err)
+		}
+	}
+	if e.AddressURL != "" {
+		if _, err := validateExplorerURL(e.AddressURL, explorerAddress); err != nil {
+			return fmt.Errorf("%s address link: %w", e.Name, err)
+		}
+	}
+	return nil
+}
+
+// explorerBaseChainID is the asset ID of the asset's parent if the asset is a
+// token, otherwise the asset ID. Tokens use their parent chain's explorers.
+func explorerBaseChainID(assetID uint32) uint32 {
+	if token := asset.TokenInfo(assetID); token != nil {
+		return token.ParentID
+	}
+	return assetID
+}
+
+// explorerSettings is the user's block explorer settings. The settings must
+// not be modified.
+func (c *Core) explorerSettings() *db.ExplorerSettings {
+	c.explorerMtx.RLock()
+	defer c.explorerMtx.RUnlock()
+	if c.explorers == nil {
+		return new(db.ExplorerSettings)
+	}
+	return c.explorers
+}
+
+// assetExplorers lists the built-in and custom explorers for the base chain
+// asset, and chooses the active one.
+func (c *Core) assetExplorers(assetID uint32, settings *db.ExplorerSettings) *AssetExplorers {
+	ae := &AssetExplorers{
+		AssetID: assetID,
+		Symbol:  unbip(assetID),
+		Builtin: builtinExplorers[assetID][c.net],
+	}
+	if userSettings := settings.Assets[assetID]; userSettings != nil {
+		ae.Custom = userSettings.Custom
+		ae.Selected = userSettings.Selected
+	}
+	all := append(append([]*db.BlockExplorer{}, ae.Builtin...), ae.Custom...)
+	for _, e := range all {
+		if e.Name == ae.Selected {
+			ae.Active = e
+			return ae
+		}
+	}
+	for _, e := range all {
+		if e.Onion == settings.PreferOnion {
+			ae.Active = e
+			return ae
+		}
+	}
+	if len(all) > 0 {
+		ae.Active = all[0]
+	}
+	return ae
+}
+
+// BlockExplorers lists the block explorers available for every supported base
+// chain asset, and which are in use.
+func (c *Core) BlockExplorers() *BlockExplorerSettings {
+	settings := c.explorerSettings()
+	supported := asset.NetworkAssets(c.net)
+	assets := make([]*AssetExplorers, 0, len(supported))
+	for assetID := range supported {
+		assets = append(assets, c.assetExplorers(assetID, settings))
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return assets[i].Symbol < assets[j].Symbol
+	})
+	return &BlockExplorerSettings{
+		PreferOnion: settings.PreferOnion,
+		Assets:      assets,
+	}
+}
+
+// activeExplorers are the block explorers in use for every base chain asset
+// that has one.
+func (c *Core) activeExplorers() map[uint32]*db.BlockExplorer {
+	settings := c.explorerSettings()
+	explorers := make(map[uint32]*db.BlockExplorer)
+	for assetID := range asset.NetworkAssets(c.net) {
+		if e := c.assetExplorers(assetID, settings).Active; e != nil {
+			explorers[assetID] = e
+		}
+	}
+	return explorers
+}
+
+// BlockExplorer is the block explorer in use for the asset, or nil if there
+// is none. Tokens use their parent chain's explorer.
+func (c *Core) BlockExplorer(assetID uint32) *db.BlockExplorer {
+	return c.assetExplorers(explorerBaseChainID(assetID), c.explorerSettings()).Active
+}
+
+// UpdateBlockExplorers validates and stores the user's block explorer
+// settings, replacing the current settings. Custom explorers can be added for
+// any supported base chain asset, and must be named differently than the
+// asset's other explorers.
+func (c *Core) UpdateBlockExplorers(settings *db.ExplorerSettings) error {
+	if settings == nil {
+		return errors.New("no settings")
+	}
+	newSettings := &db.ExplorerSettings{
+		PreferOnion: settings.PreferOnion,
+		Assets:      make(map[uint32]*db.AssetExplorers, len(settings.Assets)),
+	}
+	for assetID, userSettings := range settings.Assets {
+		if userSettings == nil || (userSettings.Selected == "" && len(userSettings.Custom) == 0) {
+			continue
+		}
+		if asset.Asset(assetID) == nil {
+			return fmt.Errorf("asset %d is not a supported base chain asset", assetID)
+		}
+		names := make(map[string]bool)
+		for _, e := range builtinExplorers[assetID][c.net] {
+			names[e.Name] = true
+		}
+		custom := make([]*db.BlockExplorer, 0, len(userSettings.Custom))
+		for _, e := range userSettings.Custom {
+			if e == nil {
+				continue
+			}
+			ec := *e
+			if err := validateExplorer(&ec); err != nil {
+				return fmt.Errorf("invalid %s explorer: %w", unbip(assetID), err)
+			}
+			if names[ec.Name] {
+				return fmt.Errorf("duplicate %s explorer name %q", unbip(assetID), ec.Name)
+			}
+			names[ec.Name] = true
+			custom = append(custom, &ec)
+		}
+		if userSettings.Selected != "" && !names[userSettings.Selected] {
+			return fmt.Errorf("unknown %s explorer %q", unbip(assetID), userSettings.Selected)
+		}
+		newSettings.Assets[assetID] = &db.AssetExplorers{
+			Selected: userSettings.Selected,
+			Custom:   custom,
+		}
+	}
+	c.explorerMtx.Lock()
+	defer c.explorerMtx.Unlock()
+	if err := c.db.SaveExplorerSettings(newSettings); err != nil {
+		return fmt.Errorf("error storing explorer settings: %w", err)
+	}
+	c.explorers = newSettings
+	return nil
+}
@@ -177,6 +177,18 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Matches Refunded"},
 		template: intl.Translation{T: "Refunded %s %s on order %s", Notes: "args: [qty, ticker, token]"},
 	},
+	TopicSwapAwaitingSignature: {
+		subject:  intl.Translation{T: "Swap awaiting signature"},
+		template: intl.Translation{T: "Sign the %s swap transaction for order %s with your external signer. The match will be revoked if it is not broadcast in %s", Notes: "args: [ticker, token, time remaining]"},
+	},
+	TopicRedeemAwaitingSignature: {
+		subject:  intl.Translation{T: "Redemption awaiting signature"},
+		template: intl.Translation{T: "Sign the %s redeem transaction for order %s with your external signer. The redemption should be broadcast in %s", Notes: "args: [ticker, token, time remaining]"},
+	},
+	TopicRefundAwaitingSignature: {
+		subject:  intl.Translation{T: "Refund awaiting signature"},
+		template: intl.Translation{T: "Sign the %s refund transaction for order %s with your external signer", Notes: "args: [ticker, token]"},
+	},
 	TopicMatchRevoked: {
 		subject:  intl.Translation{T: "Match revoked"},
 		template: intl.Translation{T: "Match %s has been revoked", Notes: "args: [match ID token]"},
@@ -258,6 +270,18 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Market resumed"},
 		template: intl.Translation{T: "Market %s at %s has resumed trading at epoch %d", Notes: "args: [market name, host, epoch]"},
 	},
+	TopicMarketChangeScheduled: {
+		subject:  intl.Translation{T: "Market change scheduled"},
+		template: intl.Translation{T: "Market %s at %s is scheduled to change at %v: %s", Notes: "args: [market name, host, time, description of changes]"},
+	},
+	TopicMaintenanceScheduled: {
+		subject:  intl.Translation{T: "Market maintenance scheduled"},
+		template: intl.Translation{T: "Market %s at %s is scheduled for maintenance from %v until %v: %s", Notes: "args: [market name, host, start time, end time, reason]"},
+	},
+	TopicBookResync: {
+		subject:  intl.Translation{T: "Order book resync"},
+		template: intl.Translation{T: "The %s order book at %s did not match the server's book and is being rebuilt.", Notes: "args: [market name, host]"},
+	},
 	TopicUpgradeNeeded: {
 		subject:  intl.Translation{T: "Upgrade needed"},
 		template: intl.Translation{T: "You may need to update your client to trade at %s.", Notes: "args: [host]"},
@@ -361,49 +361,52 @@ type OrderNote struct {
 }
 
 const (
-	TopicOrderLoadFailure     Topic = "OrderLoadFailure"
-	TopicOrderResumeFailure   Topic = "OrderResumeFailure"
-	TopicBuyOrderPlaced       Topic = "BuyOrderPlaced"
-	TopicSellOrderPlaced      Topic = "SellOrderPlaced"
-	TopicYoloPlaced           Topic = "YoloPlaced"
-	TopicMissingMatches       Topic = "MissingMatches"
-	TopicWalletMissing        Topic = "WalletMissing"
-	TopicMatchErrorCoin       Topic = "MatchErrorCoin"
-	TopicMatchErrorContract   Topic = "MatchErrorContract"
-	TopicMatchRecoveryError   Topic = "MatchRecoveryError"
-	TopicOrderCoinError       Topic = "OrderCoinError"
-	TopicOrderCoinFetchError  Topic = "OrderCoinFetchError"
-	TopicPreimageSent         Topic = "PreimageSent"
-	TopicCancelPreimageSent   Topic = "CancelPreimageSent"
-	TopicMissedCancel         Topic = "MissedCancel"
-	TopicOrderBooked          Topic = "OrderBooked"
-	TopicNoMatch              Topic = "NoMatch"
-	TopicBuyOrderCanceled     Topic = "BuyOrderCanceled"
-	TopicSellOrderCanceled    Topic = "SellOrderCanceled"
-	TopicCancel               Topic = "Cancel"
-	TopicBuyMatchesMade       Topic = "BuyMatchesMade"
-	TopicSellMatchesMade      Topic = "SellMatchesMade"
-	TopicSwapSendError        Topic = "SwapSendError"
-	TopicInitError            Topic = "InitError"
-	TopicReportRedeemError    Topic = "ReportRedeemError"
-	TopicSwapsInitiated       Topic = "SwapsInitiated"
-	TopicRedemptionError      Topic = "RedemptionError"
-	TopicMatchComplete        Topic = "MatchComplete"
-	TopicRefundFailure        Topic = "RefundFailure"
-	TopicMatchesRefunded      Topic = "MatchesRefunded"
-	TopicMatchRevoked         Topic = "MatchRevoked"
-	TopicOrderRevoked         Topic = "OrderRevoked"
-	TopicOrderAutoRevoked     Topic = "OrderAutoRevoked"
-	TopicMatchRecovered       Topic = "MatchRecovered"
-	TopicCancellingOrder      Topic = "CancellingOrder"
-	TopicOrderStatusUpdate    Topic = "OrderStatusUpdate"
-	TopicMatchResolutionError Topic = "MatchResolutionError"
-	TopicFailedCancel         Topic = "FailedCancel"
-	TopicOrderLoaded          Topic = "OrderLoaded"
-	TopicOrderRetired         Topic = "OrderRetired"
-	TopicAsyncOrderFailure    Topic = "AsyncOrderFailure"
-	TopicAsyncOrderSubmitted  Topic = "AsyncOrderSubmitted"
-	TopicOrderQuantityTooHigh Topic = "OrderQuantityTooHigh"
+	TopicOrderLoadFailure        Topic = "OrderLoadFailure"
+	TopicOrderResumeFailure      Topic = "OrderResumeFailure"
+	TopicBuyOrderPlaced          Topic = "BuyOrderPlaced"
+	TopicSellOrderPlaced         Topic = "SellOrderPlaced"
+	TopicYoloPlaced              Topic = "YoloPlaced"
+	TopicMissingMatches          Topic = "MissingMatches"
+	TopicWalletMissing           Topic = "WalletMissing"
+	TopicMatchErrorCoin          Topic = "MatchErrorCoin"
+	TopicMatchErrorContract      Topic = "MatchErrorContract"
+	TopicMatchRecoveryError      Topic = "MatchRecoveryError"
+	TopicOrderCoinError          Topic = "OrderCoinError"
+	TopicOrderCoinFetchError     Topic = "OrderCoinFetchError"
+	TopicPreimageSent            Topic = "PreimageSent"
+	TopicCancelPreimageSent      Topic = "CancelPreimageSent"
+	TopicMissedCancel            Topic = "MissedCancel"
+	TopicOrderBooked             Topic = "OrderBooked"
+	TopicNoMatch                 Topic = "NoMatch"
+	TopicBuyOrderCanceled        Topic = "BuyOrderCanceled"
+	TopicSellOrderCanceled       Topic = "SellOrderCanceled"
+	TopicCancel                  Topic = "Cancel"
+	TopicBuyMatchesMade          Topic = "BuyMatchesMade"
+	TopicSellMatchesMade         Topic = "SellMatchesMade"
+	TopicSwapSendError           Topic = "SwapSendError"
+	TopicInitError               Topic = "InitError"
+	TopicReportRedeemError       Topic = "ReportRedeemError"
+	TopicSwapsInitiated          Topic = "SwapsInitiated"
+	TopicRedemptionError         Topic = "RedemptionError"
+	TopicMatchComplete           Topic = "MatchComplete"
+	TopicRefundFailure           Topic = "RefundFailure"
+	TopicMatchesRefunded         Topic = "MatchesRefunded"
+	TopicSwapAwaitingSignature   Topic = "SwapAwaitingSignature"
+	TopicRedeemAwaitingSignature Topic = "RedeemAwaitingSignature"
+	TopicRefundAwaitingSignature Topic = "RefundAwaitingSignature"
+	TopicMatchRevoked            Topic = "MatchRevoked"
+	TopicOrderRevoked            Topic = "OrderRevoked"
+	TopicOrderAutoRevoked        Topic = "OrderAutoRevoked"
+	TopicMatchRecovered          Topic = "MatchRecovered"
+	TopicCancellingOrder         Topic = "CancellingOrder"
+	TopicOrderStatusUpdate       Topic = "OrderStatusUpdate"
+	TopicMatchResolutionError    Topic = "MatchResolutionError"
+	TopicFailedCancel            Topic = "FailedCancel"
+	TopicOrderLoaded             Topic = "OrderLoaded"
+	TopicOrderRetired            Topic = "OrderRetired"
+	TopicAsyncOrderFailure       Topic = "AsyncOrderFailure"
+	TopicAsyncOrderSubmitted     Topic = "AsyncOrderSubmitted"
+	TopicOrderQuantityTooHigh    Topic = "OrderQuantityTooHigh"
 )
 
 func newOrderNote(topic Topic, subject, details string, severity db.Severity, corder *Order) *OrderNote {
@@ -661,8 +664,11 @@ const (
 	TopicMarketSuspendedWithPurge Topic = "MarketSuspendedWithPurge"
 	TopicMarketResumeScheduled    Topic = "MarketResumeScheduled"
 	TopicMarketResumed            Topic = "MarketResumed"
+	TopicMarketChangeScheduled    Topic = "MarketChangeScheduled"
+	TopicMaintenanceScheduled     Topic = "MaintenanceScheduled"
 	TopicPenalized                Topic = "Penalized"
 	TopicDEXNotification          Topic = "DEXNotification"
+	TopicBookResync               Topic = "BookResync"
 )
 
 func newServerNotifyNote(topic Topic, subject, details string, severity db.Severity) *ServerNotifyNote {
@@ -0,0 +1,132 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+
+	"decred.org/dcrdex/client/db"
+	"github.com/decred/go-socks/socks"
+)
+
+// dialFunc is a function for dialing network connections.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// proxyDialer is the dialer for a proxy configuration. An empty SOCKS address
+// dials directly, which is refused for .onion hosts.
+func proxyDialer(cfg *db.ProxyConfig, onion bool) dialFunc {
+	if cfg.SOCKSAddr != "" {
+		return (&socks.Proxy{Addr: cfg.SOCKSAddr}).DialContext
+	}
+	if onion {
+		return func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("a proxy must be configured for .onion addresses")
+		}
+	}
+	return (&net.Dialer{}).DialContext
+}
+
+// hostProxy is the proxy configuration loaded at startup for a DEX host, or
+// nil if the host has none.
+func (c *Core) hostProxy(host string) *db.ProxyConfig {
+	if c.proxyActive == nil {
+		return nil
+	}
+	return c.proxyActive.Hosts[host]
+}
+
+// serviceProxy is the proxy configuration loaded at startup for an outbound
+// service, or nil if the service has none.
+func (c *Core) serviceProxy(service string) *db.ProxyConfig {
+	if c.proxyActive == nil {
+		return nil
+	}
+	return c.proxyActive.Services[service]
+}
+
+// validateProxySettings checks the proxy settings, and normalizes the DEX
+// hosts.
+func validateProxySettings(settings *db.ProxySettings) (*db.ProxySettings, error) {
+	validateConfig := func(name string, cfg *db.ProxyConfig) error {
+		if cfg == nil {
+			return fmt.Errorf("no proxy configuration for %s", name)
+		}
+		if cfg.SOCKSAddr == "" {
+			return nil
+		}
+		if _, _, err := net.SplitHostPort(cfg.SOCKSAddr); err != nil {
+			return fmt.Errorf("invalid SOCKS address %q for %s: %w", cfg.SOCKSAddr, name, err)
+		}
+		return nil
+	}
+	validated := new(db.ProxySettings)
+	if len(settings.Hosts) > 0 {
+		validated.Hosts = make(map[string]*db.ProxyConfig, len(settings.Hosts))
+	}
+	for addr, cfg := range settings.Hosts {
+		host, err := addrHost(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DEX host %q: %w", addr, err)
+		}
+		if err := validateConfig(host, cfg); err != nil {
+			return nil, err
+		}
+		validated.Hosts[host] = &db.ProxyConfig{SOCKSAddr: cfg.SOCKSAddr}
+	}
+	if len(settings.Services) > 0 {
+		validated.Services = make(map[string]*db.ProxyConfig, len(settings.Services))
+	}
+	for service, cfg := range settings.Services {
+		switch service {
+		case db.ProxyServiceOracles, db.ProxyServiceFiatRates:
+		default:
+			return nil, fmt.Errorf("unknown service %q", service)
+		}
+		if err := validateConfig(service, cfg); err != nil {
+			return nil, err
+		}
+		validated.Services[service] = &db.ProxyConfig{SOCKSAddr: cfg.SOCKSAddr}
+	}
+	return validated, nil
+}
+
+// ProxySettings returns the user's proxy settings for DEX hosts and outbound
+// services.
+func (c *Core) ProxySettings() *ProxyStatus {
+	settings := new(db.ProxySettings)
+	c.proxyMtx.RLock()
+	if c.proxySettings != nil {
+		settings = c.proxySettings
+	}
+	c.proxyMtx.RUnlock()
+	active := c.proxyActive
+	if active == nil {
+		active = new(db.ProxySettings)
+	}
+	return &ProxyStatus{
+		Settings:        settings,
+		RestartRequired: !reflect.DeepEqual(settings, active),
+	}
+}
+
+// UpdateProxySettings validates and stores the user's proxy settings, which
+// replace any previous settings. The new settings take effect when the client
+// is restarted.
+func (c *Core) UpdateProxySettings(settings *db.ProxySettings) error {
+	validated, err := validateProxySettings(settings)
+	if err != nil {
+		return err
+	}
+	if err := c.db.SaveProxySettings(validated); err != nil {
+		return fmt.Errorf("error saving proxy settings: %w", err)
+	}
+	c.proxyMtx.Lock()
+	c.proxySettings = validated
+	c.proxyMtx.Unlock()
+	return nil
+}
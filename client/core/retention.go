@@ -0,0 +1,122 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+)
+
+// pruneInterval is how often records are pruned according to the retention
+// settings.
+const pruneInterval = 6 * time.Hour
+
+// RetentionSettings returns the user's record retention settings.
+func (c *Core) RetentionSettings() (*db.RetentionSettings, error) {
+	return c.db.RetentionSettings()
+}
+
+// UpdateRetentionSettings validates and stores the user's record retention
+// settings. Records are pruned with the new settings right away.
+func (c *Core) UpdateRetentionSettings(settings *db.RetentionSettings) error {
+	if settings.MaxOrders < 0 || settings.MaxMatches < 0 || settings.MaxNotifications < 0 {
+		return fmt.Errorf("negative record count")
+	}
+	if err := c.db.SaveRetentionSettings(settings); err != nil {
+		return fmt.Errorf("error saving retention settings: %w", err)
+	}
+	c.signalPrune()
+	return nil
+}
+
+// signalPrune prunes records in the pruning loop right away.
+func (c *Core) signalPrune() {
+	select {
+	case c.pruneNow <- struct{}{}:
+	default:
+	}
+}
+
+// pruneRecordsLoop prunes records every pruneInterval, and when signaled by
+// signalPrune, until the context is canceled. A locked database is pruned
+// after login.
+func (c *Core) pruneRecordsLoop(ctx context.Context) {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	c.signalPrune()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.pruneNow:
+		case <-ctx.Done():
+			return
+		}
+		if c.db.Locked() {
+			continue
+		}
+		if err := c.pruneRecords(); err != nil {
+			c.log.Errorf("Error pruning records: %v", err)
+		}
+	}
+}
+
+// pruneRecords deletes the archived orders and matches, and the notifications,
+// in excess of the retention settings. If the settings say so, the deleted
+// orders and matches are saved to CSV files in the archived records folder.
+func (c *Core) pruneRecords() error {
+	settings, err := c.db.RetentionSettings()
+	if err != nil {
+		return err
+	}
+	if !settings.Enabled() {
+		return nil
+	}
+
+	// Without an age limit, only the count limits apply.
+	var olderThan *time.Time
+	if settings.MaxAgeDays > 0 {
+		t := time.Now().Add(-time.Duration(settings.MaxAgeDays) * 24 * time.Hour)
+		olderThan = &t
+	}
+	var matchesFile, ordersFile string
+	if settings.Archive {
+		stamp := time.Now().Unix()
+		matchesFile = filepath.Join(c.archivedRecordsDataDirectory(), fmt.Sprintf("archived-matches-%d", stamp))
+		ordersFile = filepath.Join(c.archivedRecordsDataDirectory(), fmt.Sprintf("archived-orders-%d", stamp))
+	}
+
+	// Matches first, since saving a match to file needs its order.
+	if olderThan != nil || settings.MaxMatches > 0 {
+		n, err := c.deleteArchivedMatches(olderThan, settings.MaxMatches, matchesFile)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			c.log.Infof("Pruned %d archived matches", n)
+		}
+	}
+	if olderThan != nil || settings.MaxOrders > 0 {
+		n, err := c.deleteArchivedOrders(olderThan, settings.MaxOrders, ordersFile)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			c.log.Infof("Pruned %d archived orders", n)
+		}
+	}
+	if olderThan != nil || settings.MaxNotifications > 0 {
+		n, err := c.db.DeleteNotifications(olderThan, settings.MaxNotifications)
+		if err != nil {
+			return fmt.Errorf("error deleting notifications: %w", err)
+		}
+		if n > 0 {
+			c.log.Infof("Pruned %d notifications", n)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/client/db"
+)
+
+// defaultSearchResults is the number of search results returned if the
+// SearchFilter does not set N.
+const defaultSearchResults = 50
+
+// Search finds the notifications, orders and matches with all of the search
+// terms, newest first. The terms are the words of a notification, and the
+// IDs, coin IDs and addresses of an order or match.
+func (c *Core) Search(filter *SearchFilter) ([]*SearchResult, error) {
+	n := filter.N
+	if n <= 0 {
+		n = defaultSearchResults
+	}
+	dbResults, err := c.db.Search(&db.SearchFilter{
+		Query: filter.Query,
+		Since: filter.Since,
+		Until: filter.Until,
+		N:     n,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search error: %w", err)
+	}
+	results := make([]*SearchResult, 0, len(dbResults))
+	for _, r := range dbResults {
+		switch {
+		case r.Notification != nil:
+			results = append(results, &SearchResult{
+				Type:         "notification",
+				Stamp:        r.Notification.TimeStamp,
+				Notification: r.Notification,
+			})
+		case r.Order != nil:
+			oid := r.Order.Order.ID()
+			ord, err := c.Order(oid[:])
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, &SearchResult{
+				Type:  "order",
+				Stamp: ord.SubmitTime,
+				Order: ord,
+			})
+		case r.Match != nil:
+			ord, err := c.Order(r.Match.OrderID[:])
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, &SearchResult{
+				Type:    "match",
+				Stamp:   r.Match.MetaData.Stamp,
+				Order:   ord,
+				MatchID: r.Match.MatchID[:],
+			})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,140 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/tor"
+	"decred.org/dcrdex/dex"
+)
+
+// newTorClient creates the tor client for the tor settings loaded at startup.
+// It returns nil if tor is disabled.
+func newTorClient(settings *db.TorSettings, dbPath string, log dex.Logger) (*tor.Client, error) {
+	if !settings.Enabled {
+		return nil, nil
+	}
+	return tor.NewClient(&tor.ClientConfig{
+		DataDir:   filepath.Join(filepath.Dir(dbPath), "torclient"),
+		SOCKSAddr: settings.SOCKSAddr,
+		TorPath:   settings.TorPath,
+		Logger:    log,
+	})
+}
+
+// torEnabled is true if tor was enabled at startup.
+func (c *Core) torEnabled() bool {
+	return c.torActive != nil && c.torActive.Enabled
+}
+
+// startTor starts the tor client if tor is enabled. If tor fails to start,
+// connections that would have used it fail rather than being made without
+// tor.
+func (c *Core) startTor(ctx context.Context) {
+	if !c.torEnabled() {
+		return
+	}
+	if c.tor == nil {
+		c.log.Errorf("Tor is enabled but unavailable. DEX and fiat rate connections will fail. " +
+			"Fix or disable tor in the settings and restart.")
+		return
+	}
+	cm := dex.NewConnectionMaster(c.tor)
+	if err := cm.ConnectOnce(ctx); err != nil {
+		c.log.Errorf("Error starting tor. DEX and fiat rate connections will fail until tor is available: %v", err)
+		return
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		cm.Wait()
+	}()
+}
+
+// torDialContext connects through tor, with a separate circuit for each
+// host.
+func (c *Core) torDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if c.tor == nil {
+		return nil, errors.New("tor is enabled but unavailable")
+	}
+	return c.tor.DialContext(ctx, network, addr)
+}
+
+// newHTTPClient creates the client for HTTP requests to an outbound service,
+// such as the fiat rate or price oracle APIs. A proxy configured for the
+// service is used instead of tor.
+func (c *Core) newHTTPClient(service string) *http.Client {
+	var dial dialFunc
+	if proxy := c.serviceProxy(service); proxy != nil {
+		dial = proxyDialer(proxy, false)
+	} else if c.torEnabled() {
+		dial = c.torDialContext
+	} else {
+		return http.DefaultClient
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dial
+	return &http.Client{Transport: transport}
+}
+
+// HTTPClient is the client for HTTP requests to price oracle APIs. If tor is
+// enabled, or the oracles have a proxy, requests are made through it.
+func (c *Core) HTTPClient() *http.Client {
+	return c.httpClients[db.ProxyServiceOracles]
+}
+
+// TorSettings returns the user's tor settings and the state of the tor
+// client.
+func (c *Core) TorSettings() *TorStatus {
+	var settings, active db.TorSettings
+	c.torMtx.RLock()
+	if c.torSettings != nil {
+		settings = *c.torSettings
+	}
+	c.torMtx.RUnlock()
+	if c.torActive != nil {
+		active = *c.torActive
+	}
+	status := &TorStatus{
+		Settings:        &settings,
+		RestartRequired: settings != active,
+	}
+	if c.tor != nil {
+		status.SOCKSAddr = c.tor.SOCKSAddr()
+		status.Running = status.SOCKSAddr != ""
+	}
+	return status
+}
+
+// UpdateTorSettings validates and stores the user's tor settings. The new
+// settings take effect when the client is restarted.
+func (c *Core) UpdateTorSettings(settings *db.TorSettings) error {
+	if settings.SOCKSAddr != "" {
+		if _, _, err := net.SplitHostPort(settings.SOCKSAddr); err != nil {
+			return fmt.Errorf("invalid tor SOCKS address %q: %w", settings.SOCKSAddr, err)
+		}
+	}
+	if settings.TorPath != "" {
+		if _, err := exec.LookPath(settings.TorPath); err != nil {
+			return fmt.Errorf("invalid tor executable: %w", err)
+		}
+	}
+	if err := c.db.SaveTorSettings(settings); err != nil {
+		return fmt.Errorf("error saving tor settings: %w", err)
+	}
+	saved := *settings
+	c.torMtx.Lock()
+	c.torSettings = &saved
+	c.torMtx.Unlock()
+	return nil
+}
@@ -404,9 +404,10 @@ func (t *trackedTrade) cacheRedemptionFeeSuggestion() {
 		return
 	}
 
-	// Fetch it from the server. Last resort!
+	// Fetch it from the server. Last resort! Nothing is waiting on the result,
+	// so queue the request if the server is unreachable.
 	go func() {
-		feeSuggestion = t.dc.fetchFeeRate(redeemAsset)
+		feeSuggestion = t.dc.fetchFeeRate(redeemAsset, true)
 		if feeSuggestion > 0 {
 			t.redeemFeeSuggestion.Lock()
 			set(feeSuggestion)
@@ -2067,14 +2068,18 @@ func (c *Core) tick(t *trackedTrade) (assetMap, error) {
 		if !t.Trade().Sell {
 			qty = quoteSent
 		}
-		err = c.swapMatches(t, swaps)
+		awaiting, err := splitAwaitingSignature(c.swapMatches(t, swaps))
 		corder := t.coreOrderInternal() // after swapMatches modifies matches
 		ui := t.wallets.fromWallet.Info().UnitInfo
+		if awaiting {
+			// The user was notified by swapMatchGroup.
+			c.log.Infof("Swaps for order %s are waiting for an external signature", t.ID())
+		}
 		if err != nil {
 			errs.addErr(err)
 			subject, details := c.formatDetails(TopicSwapSendError, ui.ConventionalString(qty), ui.Conventional.Unit, makeOrderToken(t.token()))
 			t.notify(newOrderNote(TopicSwapSendError, subject, details, db.ErrorLevel, corder))
-		} else {
+		} else if !awaiting {
 			subject, details := c.formatDetails(TopicSwapsInitiated, ui.ConventionalString(qty), ui.Conventional.Unit, makeOrderToken(t.token()))
 			t.notify(newOrderNote(TopicSwapsInitiated, subject, details, db.Poke, corder))
 		}
@@ -2092,15 +2097,19 @@ func (c *Core) tick(t *trackedTrade) (assetMap, error) {
 		if t.Trade().Sell {
 			qty = quoteReceived
 		}
-		err = c.redeemMatches(t, redeems)
+		awaiting, err := splitAwaitingSignature(c.redeemMatches(t, redeems))
 		corder := t.coreOrderInternal()
 		ui := t.wallets.toWallet.Info().UnitInfo
+		if awaiting {
+			// The user was notified by redeemMatchGroup.
+			c.log.Infof("Redemptions for order %s are waiting for an external signature", t.ID())
+		}
 		if err != nil {
 			errs.addErr(err)
 			subject, details := c.formatDetails(TopicRedemptionError,
 				ui.ConventionalString(qty), ui.Conventional.Unit, makeOrderToken(t.token()))
 			t.notify(newOrderNote(TopicRedemptionError, subject, details, db.ErrorLevel, corder))
-		} else {
+		} else if !awaiting {
 			subject, details := c.formatDetails(TopicMatchComplete,
 				ui.ConventionalString(qty), ui.Conventional.Unit, makeOrderToken(t.token()))
 			t.notify(newOrderNote(TopicMatchComplete, subject, details, db.Poke, corder))
@@ -2116,14 +2125,20 @@ func (c *Core) tick(t *trackedTrade) (assetMap, error) {
 			c.log.Infof("Unexpected unlock needed for the %s wallet while sending a refund", t.wallets.fromWallet.Symbol)
 		}
 		refunded, err := c.refundMatches(t, refunds)
+		awaiting, err := splitAwaitingSignature(err)
 		corder := t.coreOrderInternal()
 		ui := t.wallets.fromWallet.Info().UnitInfo
+		if awaiting {
+			subject, details := c.formatDetails(TopicRefundAwaitingSignature,
+				t.wallets.fromWallet.Symbol, makeOrderToken(t.token()))
+			t.notify(newOrderNote(TopicRefundAwaitingSignature, subject, details, db.WarningLevel, corder))
+		}
 		if err != nil {
 			errs.addErr(err)
 			subject, details := c.formatDetails(TopicRefundFailure,
 				ui.ConventionalString(refunded), ui.Conventional.Unit, makeOrderToken(t.token()))
 			t.notify(newOrderNote(TopicRefundFailure, subject, details, db.ErrorLevel, corder))
-		} else {
+		} else if !awaiting || refunded > 0 {
 			subject, details := c.formatDetails(TopicMatchesRefunded,
 				ui.ConventionalString(refunded), ui.Conventional.Unit, makeOrderToken(t.token()))
 			t.notify(newOrderNote(TopicMatchesRefunded, subject, details, db.WarningLevel, corder))
@@ -2321,6 +2336,21 @@ func (c *Core) swapMatches(t *trackedTrade, matches []*matchTracker) (err error)
 	return errs.ifAny()
 }
 
+// notifyAwaitingSignature notifies the user that a swap or redeem transaction
+// must be signed with an external signer before the deadline. If the
+// deadline is unknown, the broadcast timeout is used.
+func (c *Core) notifyAwaitingSignature(t *trackedTrade, topic Topic, symbol string, deadline time.Time) {
+	remaining := t.broadcastTimeout()
+	if !deadline.IsZero() {
+		remaining = time.Until(deadline)
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	subject, details := c.formatDetails(topic, symbol, makeOrderToken(t.token()), remaining.Round(time.Second))
+	t.notify(newOrderNote(topic, subject, details, db.WarningLevel, t.coreOrderInternal()))
+}
+
 // bestSwapGroupRate gets the most appropriate fee rate for a group of swaps.
 func (t *trackedTrade) bestSwapGroupFeeRate(matches []*matchTracker) uint64 {
 	var highestFeeRate uint64
@@ -2445,6 +2475,33 @@ func (c *Core) swapMatchGroup(t *trackedTrade, matches []*matchTracker, highestF
 		Options:      t.options,
 	}
 	receipts, change, fees, err := fromWallet.Swap(swaps)
+	if errors.Is(err, asset.ErrAwaitingSignature) {
+		// The swap is broadcast on a later tick, once it has been signed.
+		// This isn't a failed swap unless the broadcast timeout passes.
+		bTimeout := t.broadcastTimeout()
+		var deadline time.Time
+		for _, match := range matches {
+			lastActionTime := match.matchTime()
+			if match.Side == order.Taker {
+				auditStamp := match.MetaData.Proof.Auth.AuditStamp
+				if auditStamp == 0 {
+					continue
+				}
+				lastActionTime = time.UnixMilli(int64(auditStamp))
+			}
+			matchDeadline := lastActionTime.Add(bTimeout)
+			if bTimeout > 0 && time.Now().After(matchDeadline) {
+				match.swapErr = err
+				continue
+			}
+			if deadline.IsZero() || matchDeadline.Before(deadline) {
+				deadline = matchDeadline
+			}
+		}
+		c.notifyAwaitingSignature(t, TopicSwapAwaitingSignature, fromWallet.Symbol, deadline)
+		errs.addErr(err)
+		return
+	}
 	if err != nil {
 		bTimeout, tickInterval := t.broadcastTimeout(), t.dc.ticker.Dur() // bTimeout / tickCheckInterval
 		for _, match := range matches {
@@ -2753,6 +2810,28 @@ func (c *Core) redeemMatchGroup(t *trackedTrade, matches []*matchTracker, errs *
 		FeeSuggestion: t.redeemFee(), // fallback - wallet will try to get a rate internally for configured redeem conf target
 		Options:       t.options,
 	})
+	if errors.Is(err, asset.ErrAwaitingSignature) {
+		// The redemption is broadcast on a later tick, once it has been
+		// signed. Keep the matches grouped so the same transaction is built.
+		bTimeout := t.broadcastTimeout()
+		var deadline time.Time
+		for _, match := range matches {
+			lastActionStamp := match.MetaData.Proof.Auth.AuditStamp
+			if match.Side == order.Taker {
+				lastActionStamp = match.MetaData.Proof.Auth.RedemptionStamp
+			}
+			if lastActionStamp == 0 {
+				continue
+			}
+			matchDeadline := time.UnixMilli(int64(lastActionStamp)).Add(bTimeout)
+			if deadline.IsZero() || matchDeadline.Before(deadline) {
+				deadline = matchDeadline
+			}
+		}
+		c.notifyAwaitingSignature(t, TopicRedeemAwaitingSignature, redeemWallet.Symbol, deadline)
+		errs.addErr(err)
+		return
+	}
 	// If an error was encountered, fail all of the matches. A failed match will
 	// not run again on during ticks.
 	if err != nil {
@@ -3291,6 +3370,11 @@ func (c *Core) refundMatches(t *trackedTrade, matches []*matchTracker) (uint64,
 		}
 
 		refundCoin, err := refundWallet.Refund(swapCoinID, contractToRefund, feeRate)
+		if errors.Is(err, asset.ErrAwaitingSignature) {
+			// Try again on the next tick, once it has been signed.
+			errs.addErr(err)
+			continue
+		}
 		if err != nil {
 			// CRITICAL - Refund must indicate if the swap is spent (i.e.
 			// redeemed already) so that as taker we will start the
@@ -4,8 +4,8 @@
 package core
 
 import (
-	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -28,17 +28,6 @@ import (
 	"github.com/decred/dcrd/hdkeychain/v3"
 )
 
-const (
-	// hdKeyPurposeAccts is the BIP-43 purpose field for DEX account keys.
-	hdKeyPurposeAccts uint32 = hdkeychain.HardenedKeyStart + 0x646578 // ASCII "dex"
-	// hdKeyPurposeBonds is the BIP-43 purpose field for bond keys. These keys
-	// are separate from DEX accounts. The following path that is independent of
-	// a dex account will simplify discovery and key recovery if we devise a
-	// scheme to locate them on-chain:
-	//  m / hdKeyPurposeBonds / assetID' / bondIndex
-	hdKeyPurposeBonds uint32 = hdkeychain.HardenedKeyStart + 0x626f6e64 // ASCII "bond"
-)
-
 // errorSet is a slice of orders with a prefix prepended to the Error output.
 type errorSet struct {
 	prefix string
@@ -80,6 +69,34 @@ func (set *errorSet) Error() string {
 	return set.prefix + "{" + strings.Join(errStrings, ", ") + "}"
 }
 
+// Unwrap returns the errors in the set, for use with errors.Is and errors.As.
+func (set *errorSet) Unwrap() []error {
+	return set.errs
+}
+
+// splitAwaitingSignature separates asset.ErrAwaitingSignature from the other
+// errors returned by swapMatches, redeemMatches or refundMatches. awaiting is
+// true if any transaction is waiting for an external signature, and err is
+// whatever else went wrong, or nil if nothing did.
+func splitAwaitingSignature(in error) (awaiting bool, err error) {
+	set, is := in.(*errorSet)
+	if !is {
+		if errors.Is(in, asset.ErrAwaitingSignature) {
+			return true, nil
+		}
+		return false, in
+	}
+	rest := &errorSet{prefix: set.prefix}
+	for _, err := range set.errs {
+		if errors.Is(err, asset.ErrAwaitingSignature) {
+			awaiting = true
+			continue
+		}
+		rest.addErr(err)
+	}
+	return awaiting, rest.ifAny()
+}
+
 // WalletForm is information necessary to create a new exchange wallet.
 // The ConfigText, if provided, will be parsed for wallet connection settings.
 type WalletForm struct {
@@ -94,6 +111,11 @@ type WalletForm struct {
 	// wallet is fully synced, sending NoteTypeCreateWallet notifications to
 	// update with progress.
 	ParentForm *WalletForm
+	// Mnemonic is an optional BIP-39 seed phrase for a seeded wallet. If
+	// provided, the wallet is created from this seed instead of one derived
+	// from the app seed, so that existing keys and history can be used. The
+	// app seed backup does not restore such a wallet.
+	Mnemonic string
 }
 
 // WalletBalance is an exchange wallet's balance which includes various locked
@@ -138,6 +160,9 @@ type WalletState struct {
 	Disabled     bool                            `json:"disabled"`
 	Approved     map[uint32]asset.ApprovalStatus `json:"approved"`
 	FeeState     *FeeState                       `json:"feeState"`
+	// Providers is the health of the wallet's RPC providers, if the wallet
+	// uses them.
+	Providers []*asset.ProviderHealth `json:"providers,omitempty"`
 }
 
 // FeeState is information about the current network transaction fees and
@@ -176,6 +201,47 @@ type ExtensionModeConfig struct {
 		// DisablePrivacy disables mixing configuration and control.
 		DisablePrivacy bool `json:"disablePrivacy"`
 	} `json:"restrictedWallets"`
+	// Branding customizes the appearance of the web UI.
+	Branding *Branding `json:"branding,omitempty"`
+}
+
+// Branding is a white-label bundle for the web UI.
+type Branding struct {
+	// AppName replaces Bison Wallet in page titles.
+	AppName string `json:"appName"`
+	// Logo and Icon are paths to image files that replace the full logo and
+	// the square icon. Relative paths are relative to the directory of the
+	// extension mode file. Icon defaults to Logo.
+	Logo string `json:"logo"`
+	Icon string `json:"icon"`
+	// LightColors and DarkColors override the CSS custom properties of the
+	// light and dark themes, keyed by the property name without the leading
+	// dashes, e.g. "body-bg".
+	LightColors map[string]string `json:"lightColors"`
+	DarkColors  map[string]string `json:"darkColors"`
+	// HiddenPages are pages that are removed from the UI. Valid pages are
+	// "markets", "mm", and "orders".
+	HiddenPages []string `json:"hiddenPages"`
+}
+
+// TokenRegistry is the content of a token registry file. The tokens are
+// registered on startup as user-defined tokens, so that new tokens can be used
+// without a new release.
+type TokenRegistry struct {
+	Tokens []*RegistryToken `json:"tokens"`
+}
+
+// RegistryToken is a token definition in a TokenRegistry. The token's parent
+// asset is identified by the chain ID of its EVM-compatible blockchain.
+type RegistryToken struct {
+	EVMChainID int64  `json:"evmChainID"`
+	Address    string `json:"address"`
+	Symbol     string `json:"symbol"`
+	Name       string `json:"name"`
+	Decimals   uint8  `json:"decimals"`
+	// UnitInfo and SwapContractVersions are optional. See asset.CustomToken.
+	UnitInfo             *dex.UnitInfo `json:"unitInfo,omitempty"`
+	SwapContractVersions []uint32      `json:"swapContractVersions,omitempty"`
 }
 
 // User is information about the user's wallets and DEX accounts.
@@ -188,6 +254,48 @@ type User struct {
 	Net                dex.Network                 `json:"net"`
 	ExtensionConfig    *ExtensionModeConfig        `json:"extensionModeConfig,omitempty"`
 	Actions            []*asset.ActionRequiredNote `json:"actions,omitempty"`
+	// Explorers are the block explorers in use, keyed by base chain asset ID.
+	Explorers map[uint32]*db.BlockExplorer `json:"explorers"`
+}
+
+// AssetExplorers are the block explorers available for a base chain asset.
+type AssetExplorers struct {
+	AssetID  uint32              `json:"assetID"`
+	Symbol   string              `json:"symbol"`
+	Builtin  []*db.BlockExplorer `json:"builtin"`
+	Custom   []*db.BlockExplorer `json:"custom"`
+	Selected string              `json:"selected"`
+	// Active is the explorer in use. It is the Selected explorer if there is
+	// one, and is nil if there are no explorers.
+	Active *db.BlockExplorer `json:"active"`
+}
+
+// BlockExplorerSettings are the block explorers available for every
+// supported base chain asset.
+type BlockExplorerSettings struct {
+	PreferOnion bool              `json:"preferOnion"`
+	Assets      []*AssetExplorers `json:"assets"`
+}
+
+// TorStatus is the user's tor settings and the state of the tor client.
+type TorStatus struct {
+	Settings *db.TorSettings `json:"settings"`
+	// Running is true if tor is enabled and its SOCKS proxy can be used.
+	Running bool `json:"running"`
+	// SOCKSAddr is the address of the SOCKS proxy in use.
+	SOCKSAddr string `json:"socksAddr,omitempty"`
+	// RestartRequired is true if the settings have changed since the client
+	// was started. Changes take effect on restart.
+	RestartRequired bool `json:"restartRequired"`
+}
+
+// ProxyStatus is the user's proxy settings for DEX hosts and outbound
+// services.
+type ProxyStatus struct {
+	Settings *db.ProxySettings `json:"settings"`
+	// RestartRequired is true if the settings have changed since the client
+	// was started. Changes take effect on restart.
+	RestartRequired bool `json:"restartRequired"`
 }
 
 // SupportedAsset is data about an asset and possibly the wallet associated
@@ -561,6 +669,12 @@ type Market struct {
 	// MinimumRate is the minimum rate allowed for the market, which is the
 	// minimum rate at which 1 lot converts to something greater than dust.
 	MinimumRate uint64 `json:"minimumRate"`
+	// ScheduledChange is a change to the market's configuration that the
+	// server has scheduled, or nil if there is none.
+	ScheduledChange *msgjson.MarketChange `json:"scheduledChange,omitempty"`
+	// Maintenance is the server's scheduled maintenance window for the market,
+	// or nil if there is none. The market will be suspended during the window.
+	Maintenance *msgjson.Maintenance `json:"maintenance,omitempty"`
 }
 
 // BaseContractLocked is the amount of base asset locked in un-redeemed
@@ -876,54 +990,22 @@ func (a *dexAccount) setupCryptoV2(creds *db.PrimaryCredentials, crypter encrypt
 	}
 	defer encode.ClearBytes(seed)
 
-	dexPkB := a.dexPubKey.SerializeCompressed()
-	// And because I'm neurotic.
-	if len(dexPkB) != 33 {
-		return fmt.Errorf("invalid dex pubkey length %d", len(dexPkB))
-	}
-
 	// Deterministically generate the DEX private key using a chain of extended
 	// keys. We could surmise a hundred different algorithms to derive the DEX
 	// key, and there's nothing particularly special about doing it this way,
 	// but it works.
-
-	// Prepare the chain of child indices.
-	kids := make([]uint32, 0, 11) // 1 x purpose, 1 x version (incl. oddness), 8 x 4-byte uint32s, 1 x acct key index.
-	// Hardened "purpose" key.
-	kids = append(kids, hdKeyPurposeAccts)
-	// Second child is the format/oddness byte.
-	kids = append(kids, uint32(dexPkB[0]))
-	byteSeq := dexPkB[1:]
-	// Generate uint32's from the 4-byte chunks of the pubkey.
-	for i := 0; i < 8; i++ {
-		kids = append(kids, binary.LittleEndian.Uint32(byteSeq[i*4:i*4+4]))
-	}
-	// Last child is the account key index.
-	kids = append(kids, keyIndex)
-
-	// Harden children by modding i first, technically doubling the
-	// collision rate, but OK.
-	for i := 0; i < len(kids); i++ {
-		kids[i] = kids[i]%hdkeychain.HardenedKeyStart + hdkeychain.HardenedKeyStart
-	}
-
-	extKey, err := keygen.GenDeepChild(seed, kids)
+	priv, err := keygen.AccountKey(seed, a.dexPubKey.SerializeCompressed(), keyIndex)
 	if err != nil {
-		return fmt.Errorf("GenDeepChild error: %w", err)
-	}
-
-	privB, err := extKey.SerializedPrivKey()
-	if err != nil {
-		return fmt.Errorf("SerializedPrivKey error: %w", err)
+		return err
 	}
+	privB := priv.Serialize()
 
 	encKey, err := crypter.Encrypt(privB)
 	if err != nil {
 		return fmt.Errorf("key Encrypt error: %w", err)
 	}
 
-	pkBytes := extKey.SerializedPubKey()
-	priv := secp256k1.PrivKeyFromBytes(privB)
+	pkBytes := priv.PubKey().SerializeCompressed()
 
 	a.keyMtx.Lock()
 	a.encKey = encKey
@@ -1131,6 +1213,36 @@ type OrderFilter struct {
 		Base  uint32 `json:"baseID"`
 		Quote uint32 `json:"quoteID"`
 	} `json:"market"`
+	// Since and Until limit results to orders last updated in the time range,
+	// in milliseconds. Since is inclusive, and Until is exclusive. Zero means
+	// no limit.
+	Since uint64 `json:"since"`
+	Until uint64 `json:"until"`
+}
+
+// SearchFilter is a query of the record search with (*Core).Search.
+type SearchFilter struct {
+	Query string `json:"query"`
+	// Since and Until limit results to records created in the time range, in
+	// milliseconds. Since is inclusive, and Until is exclusive. Zero means no
+	// limit.
+	Since uint64 `json:"since"`
+	Until uint64 `json:"until"`
+	N     int    `json:"n"`
+}
+
+// SearchResult is a notification, order or match found by (*Core).Search.
+type SearchResult struct {
+	// Type is "notification", "order" or "match".
+	Type string `json:"type"`
+	// Stamp is the time of the record in milliseconds.
+	Stamp        uint64           `json:"stamp"`
+	Notification *db.Notification `json:"notification,omitempty"`
+	// Order is the order of an order or match result.
+	Order *Order `json:"order,omitempty"`
+	// MatchID is the ID of a match result. The match is in the Order's
+	// Matches.
+	MatchID dex.Bytes `json:"matchID,omitempty"`
 }
 
 // Account holds data returned from AccountExport.
@@ -1142,6 +1254,47 @@ type Account struct {
 	Cert      string `json:"cert"`
 }
 
+// RecoveryKeysForm specifies the keys to derive with RecoveryKeys.
+type RecoveryKeysForm struct {
+	// Seed is the app seed, as a mnemonic or a hex-encoded legacy seed.
+	Seed string
+	// Hosts are the DEX hosts to derive account keys for.
+	Hosts []string
+	// AcctKeys is the number of account keys to derive for each host,
+	// starting at key index 0.
+	AcctKeys uint32
+	// BondAssets are the IDs of the assets to derive bond keys for.
+	BondAssets []uint32
+	// BondKeys is the number of bond keys to derive for each asset, starting
+	// at bond index 0.
+	BondKeys uint32
+}
+
+// RecoveryAccountKey is a DEX account key derived by RecoveryKeys.
+type RecoveryAccountKey struct {
+	Host      string    `json:"host"`
+	DEXPubKey dex.Bytes `json:"dexPubKey"`
+	KeyIndex  uint32    `json:"keyIndex"`
+	AccountID string    `json:"accountID"`
+	PrivKey   dex.Bytes `json:"privKey"`
+}
+
+// RecoveryBondKey is a bond key derived by RecoveryKeys.
+type RecoveryBondKey struct {
+	AssetID   uint32    `json:"assetID"`
+	Symbol    string    `json:"symbol"`
+	BondIndex uint32    `json:"bondIndex"`
+	PubKey    dex.Bytes `json:"pubKey"`
+	PrivKey   dex.Bytes `json:"privKey"`
+}
+
+// RecoveryKeys are the account and bond keys derived from the app seed by
+// RecoveryKeys.
+type RecoveryKeys struct {
+	Accounts []*RecoveryAccountKey `json:"accounts"`
+	Bonds    []*RecoveryBondKey    `json:"bonds"`
+}
+
 // assetMap tracks a series of assets and provides methods for registering an
 // asset and merging with another assetMap.
 type assetMap map[uint32]struct{}
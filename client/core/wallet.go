@@ -52,10 +52,13 @@ type xcWallet struct {
 	supportedVersions []uint32
 	dbID              []byte
 	walletType        string
-	traits            asset.WalletTrait
-	parent            *xcWallet
-	feeState          atomic.Value // *FeeState
-	connectMtx        sync.Mutex
+	// encSeed is the encrypted imported seed of a seeded wallet, or nil if
+	// the wallet seed is derived from the app seed.
+	encSeed    []byte
+	traits     asset.WalletTrait
+	parent     *xcWallet
+	feeState   atomic.Value // *FeeState
+	connectMtx sync.Mutex
 
 	mtx        sync.RWMutex
 	encPass    []byte // empty means wallet not password protected
@@ -275,8 +278,12 @@ func (w *xcWallet) state() *WalletState {
 	}
 
 	var tokenApprovals map[uint32]asset.ApprovalStatus
+	var providers []*asset.ProviderHealth
 	if w.connector.On() {
 		tokenApprovals = w.ApprovalStatus()
+		if reporter, is := w.Wallet.(asset.ProviderHealthReporter); is {
+			providers = reporter.ProviderHealth()
+		}
 	}
 
 	var feeState *FeeState
@@ -302,6 +309,7 @@ func (w *xcWallet) state() *WalletState {
 		Disabled:     w.disabled,
 		Approved:     tokenApprovals,
 		FeeState:     feeState,
+		Providers:    providers,
 	}
 	w.mtx.RUnlock()
 
@@ -637,6 +645,16 @@ func (w *xcWallet) ApproveToken(assetVersion uint32, onConfirm func()) (string,
 	return approver.ApproveToken(assetVersion, onConfirm)
 }
 
+// ApproveTokenWithGasFees sends an approval transaction paying the fee rates
+// if the wallet is a GasFeeSetter.
+func (w *xcWallet) ApproveTokenWithGasFees(assetVersion uint32, rates *asset.GasFeeRates, onConfirm func()) (string, error) {
+	setter, ok := w.Wallet.(asset.GasFeeSetter)
+	if !ok {
+		return "", fmt.Errorf("%s wallet is not a GasFeeSetter", unbip(w.AssetID))
+	}
+	return setter.ApproveTokenWithGasFees(assetVersion, rates, onConfirm)
+}
+
 // ApproveToken sends an approval transaction if the wallet is a TokenApprover.
 func (w *xcWallet) UnapproveToken(assetVersion uint32, onConfirm func()) (string, error) {
 	approver, ok := w.Wallet.(asset.TokenApprover)
@@ -0,0 +1,195 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// defaultBackupRetention is the number of scheduled backups kept if
+	// Opts.BackupRetention is not set.
+	defaultBackupRetention = 7
+	// scheduledBackupDir is the default directory of the scheduled backups,
+	// in the backup directory.
+	scheduledBackupDir = "scheduled"
+	// backupTimeFormat is the time stamp in the file names of scheduled
+	// backups. The names sort by time.
+	backupTimeFormat = "20060102-150405.000"
+	// restoreSuffix is appended to the database path for a backup that
+	// replaces the database the next time it is opened.
+	restoreSuffix = ".restore"
+	// preRestoreSuffix is appended to the database file name for the copy of
+	// the database that was replaced by a restored backup.
+	preRestoreSuffix = ".pre-restore.bak"
+)
+
+// scheduledBackupDirectory is the directory of the scheduled backups.
+func (db *BoltDB) scheduledBackupDirectory() string {
+	if db.opts.BackupDir != "" {
+		return db.opts.BackupDir
+	}
+	return filepath.Join(filepath.Dir(db.Path()), backupDir, scheduledBackupDir)
+}
+
+// runScheduledBackups makes a backup every Opts.BackupInterval until the
+// context is canceled.
+func (db *BoltDB) runScheduledBackups(ctx context.Context) {
+	ticker := time.NewTicker(db.opts.BackupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := db.scheduledBackup(); err != nil {
+				db.log.Errorf("Scheduled database backup failed: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// scheduledBackup makes a compacted, time-stamped backup, and deletes the
+// oldest scheduled backups in excess of Opts.BackupRetention.
+func (db *BoltDB) scheduledBackup() error {
+	dir := db.scheduledBackupDirectory()
+	file := filepath.Base(db.Path())
+	dst := filepath.Join(dir, fmt.Sprintf("%s.%s.bak", file, time.Now().UTC().Format(backupTimeFormat)))
+	if err := db.BackupTo(dst, false, true); err != nil {
+		return err
+	}
+	db.log.Debugf("Backed up database to %s", dst)
+
+	retention := db.opts.BackupRetention
+	if retention <= 0 {
+		retention = defaultBackupRetention
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error listing backups: %w", err)
+	}
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, file+".") && strings.HasSuffix(name, ".bak") {
+			backups = append(backups, name)
+		}
+	}
+	if len(backups) <= retention {
+		return nil
+	}
+	sort.Strings(backups)
+	for _, name := range backups[:len(backups)-retention] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("error deleting old backup: %w", err)
+		}
+		db.log.Debugf("Deleted old database backup %s", name)
+	}
+	return nil
+}
+
+// ValidateBackup checks that the file is a consistent database that this
+// software can open.
+func ValidateBackup(path string) error {
+	bdb, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 3 * time.Second, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("error opening backup: %w", err)
+	}
+	defer bdb.Close()
+	return bdb.View(func(tx *bbolt.Tx) error {
+		// Read all of the errors, so that the checking goroutine finishes.
+		var checkErr error
+		for err := range tx.Check() {
+			if checkErr == nil {
+				checkErr = err
+			}
+		}
+		if checkErr != nil {
+			return fmt.Errorf("backup is corrupted: %w", checkErr)
+		}
+		version, err := getVersionTx(tx)
+		if err != nil {
+			return err
+		}
+		if version > DBVersion {
+			return fmt.Errorf("backup version %d is newer than the supported version %d", version, DBVersion)
+		}
+		if tx.Bucket(credentialsBucket) == nil {
+			return errors.New("backup has no credentials")
+		}
+		return nil
+	})
+}
+
+// Restore validates the backup, and stages it to replace the database the next
+// time the database is opened. The replaced database is kept in the backup
+// directory.
+func (db *BoltDB) Restore(src string) error {
+	src = filepath.Clean(src)
+	if src == filepath.Clean(db.Path()) {
+		return errors.New("backup is the active DB")
+	}
+	if err := ValidateBackup(src); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	dst := db.Path() + restoreSuffix
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return fmt.Errorf("error copying backup: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	db.log.Infof("Backup %s will be restored when the database is next opened.", src)
+	return nil
+}
+
+// swapInRestore replaces the database with a backup staged with Restore.
+func swapInRestore(dbPath string, log dex.Logger) error {
+	restorePath := dbPath + restoreSuffix
+	if _, err := os.Stat(restorePath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err := ValidateBackup(restorePath); err != nil {
+		return fmt.Errorf("staged database restore is invalid: %w", err)
+	}
+	if _, err := os.Stat(dbPath); err == nil {
+		dir, file := filepath.Split(dbPath)
+		if err := os.MkdirAll(filepath.Join(dir, backupDir), 0700); err != nil {
+			return fmt.Errorf("unable to create backup directory: %w", err)
+		}
+		oldPath := filepath.Join(dir, backupDir, file+preRestoreSuffix)
+		if err := os.Rename(dbPath, oldPath); err != nil {
+			return fmt.Errorf("error moving the database aside for restore: %w", err)
+		}
+		log.Infof("Moved the replaced database to %s", oldPath)
+	}
+	if err := os.Rename(restorePath, dbPath); err != nil {
+		return fmt.Errorf("error restoring database: %w", err)
+	}
+	log.Infof("Restored the database from backup.")
+	return nil
+}
@@ -0,0 +1,114 @@
+package bolt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dbtest "decred.org/dcrdex/client/db/test"
+)
+
+func TestScheduledBackups(t *testing.T) {
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "backups")
+	dbi, err := NewDB(filepath.Join(dir, "db.db"), tLogger, Opts{
+		BackupInterval:  10 * time.Millisecond,
+		BackupDir:       backupDir,
+		BackupRetention: 2,
+	})
+	if err != nil {
+		t.Fatalf("error creating db: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		dbi.Run(ctx)
+	}()
+
+	// Wait for more backups than are retained.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		t.Fatalf("error listing backups: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".bak") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) != 2 {
+		t.Fatalf("expected 2 backups, found %v", names)
+	}
+	for _, name := range names {
+		if err := ValidateBackup(filepath.Join(backupDir, name)); err != nil {
+			t.Fatalf("invalid backup %s: %v", name, err)
+		}
+	}
+}
+
+func TestRestore(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	dbPath := boltdb.Path()
+
+	if err := boltdb.SetPrimaryCredentials(dbtest.RandomPrimaryCredentials()); err != nil {
+		t.Fatalf("SetPrimaryCredentials error: %v", err)
+	}
+	acct1 := dbtest.RandomAccountInfo()
+	if err := boltdb.CreateAccount(acct1); err != nil {
+		t.Fatalf("CreateAccount error: %v", err)
+	}
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+	if err := boltdb.BackupTo(backupPath, false, true); err != nil {
+		t.Fatalf("BackupTo error: %v", err)
+	}
+	acct2 := dbtest.RandomAccountInfo()
+	if err := boltdb.CreateAccount(acct2); err != nil {
+		t.Fatalf("CreateAccount error: %v", err)
+	}
+
+	// Not a database.
+	badPath := filepath.Join(t.TempDir(), "bad.db")
+	if err := os.WriteFile(badPath, randBytes(5000), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := boltdb.Restore(badPath); err == nil {
+		t.Fatalf("no error restoring an invalid backup")
+	}
+	if err := boltdb.Restore(dbPath); err == nil {
+		t.Fatalf("no error restoring the active database")
+	}
+
+	if err := boltdb.Restore(backupPath); err != nil {
+		t.Fatalf("Restore error: %v", err)
+	}
+	shutdown()
+
+	dbi, err := NewDB(dbPath, tLogger)
+	if err != nil {
+		t.Fatalf("error reopening database: %v", err)
+	}
+	boltdb = dbi.(*BoltDB)
+	defer boltdb.Close()
+	hosts, err := boltdb.ListAccounts()
+	if err != nil {
+		t.Fatalf("ListAccounts error: %v", err)
+	}
+	if len(hosts) != 1 || hosts[0] != acct1.Host {
+		t.Fatalf("wrong accounts after restore: %v", hosts)
+	}
+	// The replaced database is kept.
+	oldPath := filepath.Join(filepath.Dir(dbPath), backupDir, filepath.Base(dbPath)+preRestoreSuffix)
+	if err := ValidateBackup(oldPath); err != nil {
+		t.Fatalf("replaced database not kept: %v", err)
+	}
+}
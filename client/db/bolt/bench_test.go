@@ -0,0 +1,131 @@
+package bolt
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	dbtest "decred.org/dcrdex/client/db/test"
+	"decred.org/dcrdex/dex/order"
+)
+
+// benchOrders is the number of orders in the benchmark databases, which is
+// typical of a client that has been running market makers for a while.
+const benchOrders = 5000
+
+// newBenchDB creates a database with benchOrders orders, a tenth of which are
+// active, and benchOrders notifications.
+func newBenchDB(b *testing.B) (*BoltDB, func()) {
+	b.Helper()
+	boltdb, shutdown := newTestDB(b)
+	const host = "dex.example.com"
+	for i := 0; i < benchOrders; i++ {
+		status := order.OrderStatusExecuted
+		if i%10 == 0 {
+			status = order.OrderStatusBooked
+		}
+		mord := &db.MetaOrder{
+			MetaData: &db.OrderMetaData{
+				Status: status,
+				Host:   host,
+				Proof:  db.OrderProof{DEXSig: randBytes(73)},
+			},
+			Order: randOrderForMarket(42, 0),
+		}
+		if err := boltdb.UpdateOrder(mord); err != nil {
+			b.Fatalf("UpdateOrder error: %v", err)
+		}
+	}
+	// Notifications are saved in batches, so save them concurrently.
+	const notifiers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < notifiers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < benchOrders/notifiers; j++ {
+				if err := boltdb.SaveNotification(dbtest.RandomNotification(uint64(time.Now().UnixMilli()))); err != nil {
+					b.Errorf("SaveNotification error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return boltdb, shutdown
+}
+
+func BenchmarkActiveOrders(b *testing.B) {
+	boltdb, shutdown := newBenchDB(b)
+	defer shutdown()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := boltdb.ActiveOrders(); err != nil {
+			b.Fatalf("ActiveOrders error: %v", err)
+		}
+	}
+}
+
+func BenchmarkOrders(b *testing.B) {
+	boltdb, shutdown := newBenchDB(b)
+	defer shutdown()
+	filter := &db.OrderFilter{N: 50}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := boltdb.Orders(filter); err != nil {
+			b.Fatalf("Orders error: %v", err)
+		}
+	}
+}
+
+func BenchmarkAccountOrders(b *testing.B) {
+	boltdb, shutdown := newBenchDB(b)
+	defer shutdown()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := boltdb.AccountOrders("dex.example.com", 0, 1); err != nil {
+			b.Fatalf("AccountOrders error: %v", err)
+		}
+	}
+}
+
+func BenchmarkNotificationsN(b *testing.B) {
+	boltdb, shutdown := newBenchDB(b)
+	defer shutdown()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := boltdb.NotificationsN(100); err != nil {
+			b.Fatalf("NotificationsN error: %v", err)
+		}
+	}
+}
+
+// BenchmarkUpdateBalance updates balances from concurrent goroutines, as the
+// wallets do, while the orders are being read.
+func BenchmarkUpdateBalance(b *testing.B) {
+	boltdb, shutdown := newBenchDB(b)
+	defer shutdown()
+	w := dbtest.RandomWallet()
+	if err := boltdb.UpdateWallet(w); err != nil {
+		b.Fatalf("UpdateWallet error: %v", err)
+	}
+	bal := &db.Balance{Balance: *dbtest.RandomBalance(), Stamp: time.Now()}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			if i++; i%10 == 0 {
+				if _, err := boltdb.ActiveOrders(); err != nil {
+					b.Errorf("ActiveOrders error: %v", err)
+					return
+				}
+				continue
+			}
+			if err := boltdb.UpdateBalance(w.ID(), bal); err != nil {
+				b.Errorf("UpdateBalance error: %v", err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,468 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+	"go.etcd.io/bbolt"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// The database is encrypted with a random database key, which is stored in the
+// credentials bucket encrypted with the app's inner key. The values in the
+// buckets below are encrypted with the database key. The app settings and the
+// credentials are not encrypted, since they are needed before login. Bucket
+// keys are not encrypted either. They are order, match and notification IDs,
+// and asset IDs, except for the DEX hosts of the accounts and market stats
+// buckets and the terms of the search index, which are replaced with a keyed
+// hash.
+var encryptedBuckets = [][]byte{
+	accountsBucket, bondIndexesBucket,
+	activeOrdersBucket, archivedOrdersBucket,
+	activeMatchesBucket, archivedMatchesBucket,
+	walletsBucket, notesBucket, botProgramsBucket,
+	pokesBucket, customTokensBucket, searchIndexBucket, marketStatsBucket,
+}
+
+var encDBKeyKey = []byte("encDBKey")
+
+// dbKeySize is the size of the database key, which is the value encryption key
+// followed by the account key hashing key.
+const dbKeySize = chacha20poly1305.KeySize + 32
+
+// valueCrypter encrypts and decrypts the values in the encrypted buckets.
+type valueCrypter struct {
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// newValueCrypter is the constructor for a valueCrypter.
+func newValueCrypter(dbKey []byte) (*valueCrypter, error) {
+	if len(dbKey) != dbKeySize {
+		return nil, fmt.Errorf("wrong database key size %d", len(dbKey))
+	}
+	aead, err := chacha20poly1305.NewX(dbKey[:chacha20poly1305.KeySize])
+	if err != nil {
+		return nil, err
+	}
+	return &valueCrypter{
+		aead:    aead,
+		hashKey: bytes.Clone(dbKey[chacha20poly1305.KeySize:]),
+	}, nil
+}
+
+// seal encrypts the value. The nonce is prepended to the ciphertext.
+func (vc *valueCrypter) seal(v []byte) ([]byte, error) {
+	nonce := make([]byte, vc.aead.NonceSize(), vc.aead.NonceSize()+len(v)+vc.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return vc.aead.Seal(nonce, nonce, v, nil), nil
+}
+
+// open decrypts a value encrypted with seal.
+func (vc *valueCrypter) open(b []byte) ([]byte, error) {
+	if len(b) < vc.aead.NonceSize()+vc.aead.Overhead() {
+		return nil, errors.New("encrypted value too short")
+	}
+	nonce, cipherText := b[:vc.aead.NonceSize()], b[vc.aead.NonceSize():]
+	v, err := vc.aead.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		// An empty value is not a missing value.
+		v = []byte{}
+	}
+	return v, nil
+}
+
+// hashKey is the keyed hash that replaces a bucket key.
+func (vc *valueCrypter) hash(k []byte) []byte {
+	mac := hmac.New(sha256.New, vc.hashKey)
+	mac.Write(k)
+	return mac.Sum(nil)
+}
+
+// bucket is a *bbolt.Bucket with encrypted values if the database is encrypted.
+// The methods are those of *bbolt.Bucket.
+type bucket struct {
+	b   *bbolt.Bucket
+	vc  *valueCrypter // nil if the database is not encrypted
+	log dex.Logger
+	// decryptErr records the first decryption error of the transaction, which
+	// fails the transaction. nil outside of db.View, db.Update and db.Batch.
+	decryptErr *error
+}
+
+// wrap wraps a nested bucket. It returns nil for a nil *bbolt.Bucket.
+func (b *bucket) wrap(nested *bbolt.Bucket) *bucket {
+	if nested == nil {
+		return nil
+	}
+	return &bucket{b: nested, vc: b.vc, log: b.log, decryptErr: b.decryptErr}
+}
+
+// plainBucket wraps a bucket with values that are not encrypted.
+func plainBucket(b *bbolt.Bucket) *bucket {
+	if b == nil {
+		return nil
+	}
+	return &bucket{b: b, log: dex.Disabled}
+}
+
+// Bucket retrieves a nested bucket, or nil if it does not exist.
+func (b *bucket) Bucket(k []byte) *bucket {
+	return b.wrap(b.b.Bucket(k))
+}
+
+// CreateBucket creates a new nested bucket.
+func (b *bucket) CreateBucket(k []byte) (*bucket, error) {
+	nested, err := b.b.CreateBucket(k)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrap(nested), nil
+}
+
+// CreateBucketIfNotExists creates a new nested bucket if it does not exist.
+func (b *bucket) CreateBucketIfNotExists(k []byte) (*bucket, error) {
+	nested, err := b.b.CreateBucketIfNotExists(k)
+	if err != nil {
+		return nil, err
+	}
+	return b.wrap(nested), nil
+}
+
+// DeleteBucket deletes a nested bucket.
+func (b *bucket) DeleteBucket(k []byte) error {
+	return b.b.DeleteBucket(k)
+}
+
+// Delete deletes a value.
+func (b *bucket) Delete(k []byte) error {
+	return b.b.Delete(k)
+}
+
+// Put stores the value, encrypted if the database is encrypted.
+func (b *bucket) Put(k, v []byte) error {
+	if b.vc == nil {
+		return b.b.Put(k, v)
+	}
+	encV, err := b.vc.seal(v)
+	if err != nil {
+		return fmt.Errorf("error encrypting value: %w", err)
+	}
+	return b.b.Put(k, encV)
+}
+
+// Get retrieves a value. If a value cannot be decrypted, nil is returned, and
+// the transaction fails with the decryption error.
+func (b *bucket) Get(k []byte) []byte {
+	return b.open(k, b.b.Get(k))
+}
+
+// open decrypts a value retrieved from the bucket.
+func (b *bucket) open(k, v []byte) []byte {
+	if b.vc == nil || v == nil {
+		return v
+	}
+	plainV, err := b.vc.open(v)
+	if err != nil {
+		b.log.Errorf("Error decrypting value for key %x: %v", k, err)
+		if b.decryptErr != nil && *b.decryptErr == nil {
+			*b.decryptErr = fmt.Errorf("error decrypting value for key %x: %w", k, err)
+		}
+		return nil
+	}
+	return plainV
+}
+
+// ForEach calls fn with every key and value in the bucket. The value is nil
+// for nested buckets.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	return b.b.ForEach(func(k, v []byte) error {
+		return fn(k, b.open(k, v))
+	})
+}
+
+// Cursor creates a cursor for the bucket.
+func (b *bucket) Cursor() *cursor {
+	return &cursor{c: b.b.Cursor(), b: b}
+}
+
+// cursor is a *bbolt.Cursor that decrypts values.
+type cursor struct {
+	c *bbolt.Cursor
+	b *bucket
+}
+
+// First moves the cursor to the first item in the bucket.
+func (c *cursor) First() (k, v []byte) {
+	k, v = c.c.First()
+	return k, c.b.open(k, v)
+}
+
+// Seek moves the cursor to the key, or to the next key if the key does not
+// exist.
+func (c *cursor) Seek(seek []byte) (k, v []byte) {
+	k, v = c.c.Seek(seek)
+	return k, c.b.open(k, v)
+}
+
+// Next moves the cursor to the next item in the bucket.
+func (c *cursor) Next() (k, v []byte) {
+	k, v = c.c.Next()
+	return k, c.b.open(k, v)
+}
+
+// acctKey is the key of an account's bucket in the accounts bucket.
+func acctKey(accts *bucket, host string) []byte {
+	if accts.vc == nil {
+		return []byte(host)
+	}
+	return accts.vc.hash([]byte(host))
+}
+
+// bucket opens a top-level bucket. It is an error to open an encrypted bucket
+// in a locked database. Use in a db.View or db.Update transaction.
+func (db *BoltDB) bucket(tx *bbolt.Tx, name []byte) (*bucket, error) {
+	bkt := tx.Bucket(name)
+	if bkt == nil {
+		return nil, fmt.Errorf("failed to open %s bucket", string(name))
+	}
+	if db.encrypted && db.vc == nil {
+		return nil, dexdb.ErrLocked
+	}
+	b := &bucket{b: bkt, vc: db.vc, log: db.log}
+	if decryptErr, found := db.decryptErrs.Load(tx); found {
+		b.decryptErr = decryptErr.(*error)
+	}
+	return b, nil
+}
+
+// checkDecryption wraps a transaction function so that the transaction fails if
+// a value cannot be decrypted, even if fn ignores the missing value.
+func (db *BoltDB) checkDecryption(fn func(*bbolt.Tx) error) func(*bbolt.Tx) error {
+	return func(tx *bbolt.Tx) error {
+		decryptErr := new(error)
+		db.decryptErrs.Store(tx, decryptErr)
+		defer db.decryptErrs.Delete(tx)
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return *decryptErr
+	}
+}
+
+// View runs a read-only transaction. The database cannot be encrypted or
+// unlocked during the transaction. The transaction fails if a value cannot be
+// decrypted.
+func (db *BoltDB) View(fn func(*bbolt.Tx) error) error {
+	db.cryptMtx.RLock()
+	defer db.cryptMtx.RUnlock()
+	return db.DB.View(db.checkDecryption(fn))
+}
+
+// Update runs a read-write transaction. The database cannot be encrypted or
+// unlocked during the transaction. The transaction is rolled back if a value
+// cannot be decrypted.
+func (db *BoltDB) Update(fn func(*bbolt.Tx) error) error {
+	db.cryptMtx.RLock()
+	defer db.cryptMtx.RUnlock()
+	return db.DB.Update(db.checkDecryption(fn))
+}
+
+// Batch runs a read-write transaction that may be combined with the
+// transactions of other concurrent callers, which reduces contention for
+// frequent small writes. fn may be run more than once, so it must be
+// idempotent. The database cannot be encrypted or unlocked during the
+// transaction.
+func (db *BoltDB) Batch(fn func(*bbolt.Tx) error) error {
+	db.cryptMtx.RLock()
+	defer db.cryptMtx.RUnlock()
+	return db.DB.Batch(db.checkDecryption(fn))
+}
+
+// Locked is true if the database is encrypted and has not been unlocked.
+func (db *BoltDB) Locked() bool {
+	db.cryptMtx.RLock()
+	defer db.cryptMtx.RUnlock()
+	return db.encrypted && db.vc == nil
+}
+
+// Unlock decrypts the database key with the app's inner crypter, giving access
+// to the encrypted buckets. If the database is not encrypted yet, it is
+// encrypted with a new database key.
+func (db *BoltDB) Unlock(crypter encrypt.Crypter) error {
+	db.cryptMtx.Lock()
+	defer db.cryptMtx.Unlock()
+	if db.vc != nil {
+		return nil
+	}
+	if !db.encrypted {
+		return db.encrypt(crypter)
+	}
+	var encKey []byte
+	if err := db.DB.View(func(tx *bbolt.Tx) error {
+		encKey = getCopy(tx.Bucket(credentialsBucket), encDBKeyKey)
+		return nil
+	}); err != nil {
+		return err
+	}
+	dbKey, err := crypter.Decrypt(encKey)
+	if err != nil {
+		return fmt.Errorf("error decrypting database key: %w", err)
+	}
+	defer encode.ClearBytes(dbKey)
+	db.vc, err = newValueCrypter(dbKey)
+	return err
+}
+
+// encrypt encrypts the values in the encrypted buckets with a new database
+// key, and stores the key encrypted with the crypter. Everything is done in
+// one transaction, so that the database is never partly encrypted. The
+// replaced pages of the database file still hold the unencrypted values, so
+// the database is compacted on shutdown. The caller must hold the cryptMtx.
+func (db *BoltDB) encrypt(crypter encrypt.Crypter) error {
+	dbKey := encode.RandomBytes(dbKeySize)
+	defer encode.ClearBytes(dbKey)
+	vc, err := newValueCrypter(dbKey)
+	if err != nil {
+		return err
+	}
+	encKey, err := crypter.Encrypt(dbKey)
+	if err != nil {
+		return fmt.Errorf("error encrypting database key: %w", err)
+	}
+
+	err = db.DB.Update(func(tx *bbolt.Tx) error {
+		// The search terms and the hosts of the market stats are hashed with
+		// the database key, so they are rebuilt.
+		if err := resetSearchIndex(tx); err != nil {
+			return err
+		}
+		if err := resetMarketStats(tx); err != nil {
+			return err
+		}
+		for _, name := range encryptedBuckets {
+			bkt := tx.Bucket(name)
+			if bkt == nil {
+				continue
+			}
+			var err error
+			if bytes.Equal(name, accountsBucket) {
+				err = encryptAccounts(bkt, vc)
+			} else {
+				err = encryptValues(bkt, vc)
+			}
+			if err != nil {
+				return fmt.Errorf("error encrypting %s bucket: %w", string(name), err)
+			}
+		}
+		creds := tx.Bucket(credentialsBucket)
+		if creds == nil {
+			return errors.New("no credentials bucket")
+		}
+		return creds.Put(encDBKeyKey, encKey)
+	})
+	if err != nil {
+		return err
+	}
+	db.vc, db.encrypted, db.compactOnShutdown = vc, true, true
+	db.log.Infof("Encrypted the database. Any older backups of the database, such as " +
+		"the .bak files in the data directory, are not encrypted and can be deleted.")
+	return nil
+}
+
+// encryptValues encrypts the values in the bucket and its nested buckets in
+// place.
+func encryptValues(bkt *bbolt.Bucket, vc *valueCrypter) error {
+	// The bucket can't be modified while iterating.
+	var keys, vals, nested [][]byte
+	if err := bkt.ForEach(func(k, v []byte) error {
+		if v == nil {
+			nested = append(nested, bytes.Clone(k))
+			return nil
+		}
+		keys = append(keys, bytes.Clone(k))
+		vals = append(vals, bytes.Clone(v))
+		return nil
+	}); err != nil {
+		return err
+	}
+	for i, k := range keys {
+		encV, err := vc.seal(vals[i])
+		if err != nil {
+			return err
+		}
+		if err := bkt.Put(k, encV); err != nil {
+			return err
+		}
+	}
+	for _, k := range nested {
+		if err := encryptValues(bkt.Bucket(k), vc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encryptAccounts moves each account bucket to the hash of the DEX host, and
+// encrypts the values.
+func encryptAccounts(accts *bbolt.Bucket, vc *valueCrypter) error {
+	var hosts [][]byte
+	if err := accts.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return fmt.Errorf("account %s is not a bucket", string(k))
+		}
+		hosts = append(hosts, bytes.Clone(k))
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, host := range hosts {
+		dst, err := accts.CreateBucket(vc.hash(host))
+		if err != nil {
+			return fmt.Errorf("error creating account bucket for %s: %w", string(host), err)
+		}
+		if err := copyEncrypted(accts.Bucket(host), dst, vc); err != nil {
+			return err
+		}
+		if err := accts.DeleteBucket(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyEncrypted copies the values and nested buckets of src to dst, encrypting
+// the values.
+func copyEncrypted(src, dst *bbolt.Bucket, vc *valueCrypter) error {
+	return src.ForEach(func(k, v []byte) error {
+		if v == nil {
+			nested, err := dst.CreateBucket(k)
+			if err != nil {
+				return err
+			}
+			return copyEncrypted(src.Bucket(k), nested, vc)
+		}
+		encV, err := vc.seal(v)
+		if err != nil {
+			return err
+		}
+		return dst.Put(k, encV)
+	})
+}
@@ -15,8 +15,10 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/db"
 	dexdb "decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex"
@@ -30,10 +32,10 @@ import (
 // getCopy returns a copy of the value for the given key and provided bucket. If
 // the key is not found or if an empty slice was loaded, nil is returned. Thus,
 // use bkt.Get(key) == nil directly to test for existence of the key. This
-// function should be used instead of bbolt.(*Bucket).Get when the read value
-// needs to be kept after the transaction, at which time the buffer from Get is
-// no longer safe to use.
-func getCopy(bkt *bbolt.Bucket, key []byte) []byte {
+// function should be used instead of Get when the read value needs to be kept
+// after the transaction, at which time the buffer from Get is no longer safe to
+// use.
+func getCopy(bkt interface{ Get([]byte) []byte }, key []byte) []byte {
 	b := bkt.Get(key)
 	if len(b) == 0 {
 		return nil
@@ -52,6 +54,10 @@ var (
 	uint64Bytes = encode.Uint64Bytes
 )
 
+// batchDelay is the longest a batched write waits for other writes to join
+// its transaction.
+const batchDelay = 2 * time.Millisecond
+
 // Bolt works on []byte keys and values. These are some commonly used key and
 // value encodings.
 var (
@@ -68,7 +74,11 @@ var (
 	walletsBucket         = []byte("wallets")
 	notesBucket           = []byte("notes")
 	pokesBucket           = []byte("pokes")
+	customTokensBucket    = []byte("customTokens")
 	credentialsBucket     = []byte("credentials")
+	searchIndexBucket     = []byte("searchIndex")
+	marketStatsBucket     = []byte("marketStats")
+	fiatRatesBucket       = []byte("fiatRates")
 
 	// value keys
 	versionKey            = []byte("version")
@@ -125,6 +135,13 @@ var (
 	walletDisabledKey     = []byte("walletDisabled")
 	programKey            = []byte("program")
 	langKey               = []byte("lang")
+	explorersKey          = []byte("explorers")
+	torKey                = []byte("tor")
+	proxyKey              = []byte("proxy")
+	retentionKey          = []byte("retention")
+	searchIndexedKey      = []byte("searchIndexed")
+	marketStatsBuiltKey   = []byte("marketStatsBuilt")
+	statsCountedKey       = []byte("statsCounted")
 
 	// values
 	byteTrue   = encode.ByteTrue
@@ -138,6 +155,19 @@ var (
 // Opts is a set of options for the DB.
 type Opts struct {
 	BackupOnShutdown bool // default is true
+	// BackupInterval is how often a backup is made while the database is
+	// running. Zero disables scheduled backups.
+	BackupInterval time.Duration
+	// BackupDir is the directory of the scheduled backups. The default is the
+	// "scheduled" directory in the backup directory.
+	BackupDir string
+	// BackupRetention is the number of scheduled backups kept. The default is
+	// 7.
+	BackupRetention int
+	// DecodeCoinID converts a coin ID to its string form, such as a
+	// transaction ID and output index, for the search index. Coin IDs are
+	// always indexed as hex too.
+	DecodeCoinID func(assetID uint32, coinID []byte) (string, error)
 }
 
 var defaultOpts = Opts{
@@ -150,6 +180,18 @@ type BoltDB struct {
 	*bbolt.DB
 	opts Opts
 	log  dex.Logger
+
+	// cryptMtx is held for reading by View and Update, so that the database
+	// is not encrypted or unlocked during a transaction.
+	cryptMtx  sync.RWMutex
+	encrypted bool
+	vc        *valueCrypter // nil until unlocked
+	// compactOnShutdown is set when the database is encrypted, to remove the
+	// unencrypted values from the free pages of the database file.
+	compactOnShutdown bool
+	// decryptErrs maps the *bbolt.Tx of a running transaction to the *error
+	// that records a failure to decrypt a value. See checkDecryption.
+	decryptErrs sync.Map
 }
 
 // Check that BoltDB satisfies the db.DB interface.
@@ -157,6 +199,10 @@ var _ dexdb.DB = (*BoltDB)(nil)
 
 // NewDB is a constructor for a *BoltDB.
 func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
+	if err := swapInRestore(dbPath, logger); err != nil {
+		return nil, err
+	}
+
 	_, err := os.Stat(dbPath)
 	isNew := os.IsNotExist(err)
 
@@ -167,6 +213,9 @@ func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
 		}
 		return nil, err
 	}
+	// Notifications are saved in batches, but the notifier waits for the
+	// write, so don't wait long for other writes to join the batch.
+	db.MaxBatchDelay = batchDelay
 
 	// Release the file lock on exit.
 	bdb := &BoltDB{
@@ -183,7 +232,8 @@ func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
 		activeOrdersBucket, archivedOrdersBucket,
 		activeMatchesBucket, archivedMatchesBucket,
 		walletsBucket, notesBucket, credentialsBucket,
-		botProgramsBucket, pokesBucket,
+		botProgramsBucket, pokesBucket, customTokensBucket,
+		searchIndexBucket, marketStatsBucket, fiatRatesBucket,
 	}); err != nil {
 		return nil, err
 	}
@@ -218,6 +268,15 @@ func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
 		return nil, err
 	}
 
+	// An encrypted database is locked until Unlock is called at login.
+	err = bdb.DB.View(func(tx *bbolt.Tx) error {
+		bdb.encrypted = tx.Bucket(credentialsBucket).Get(encDBKeyKey) != nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	bdb.log.Infof("Started database (version = %d, file = %s)", DBVersion, dbPath)
 
 	return bdb, nil
@@ -234,12 +293,22 @@ func (db *BoltDB) fileSize(path string) int64 {
 
 // Run waits for context cancellation and closes the database.
 func (db *BoltDB) Run(ctx context.Context) {
+	if db.opts.BackupInterval > 0 {
+		db.runScheduledBackups(ctx) // until shutdown
+	}
 	<-ctx.Done() // wait for shutdown to backup and compact
 
-	// Create a backup in the backups folder.
+	db.cryptMtx.RLock()
+	compactOnShutdown := db.compactOnShutdown
+	db.cryptMtx.RUnlock()
+
+	// Create a backup in the backups folder. If the database was just
+	// encrypted, the backup is compacted so that it has none of the free pages
+	// with unencrypted values.
 	if db.opts.BackupOnShutdown {
 		db.log.Infof("Backing up database...")
-		if err := db.Backup(); err != nil {
+		dir, file := filepath.Split(db.Path())
+		if err := db.BackupTo(filepath.Join(dir, backupDir, file), true, compactOnShutdown); err != nil {
 			db.log.Errorf("Unable to backup database: %v", err)
 		}
 	}
@@ -263,7 +332,7 @@ func (db *BoltDB) Run(ctx context.Context) {
 		dbSize, freeBytes, 100*pctFree)
 	// Only compact if free space is at least the byte threshold AND that fee
 	// space accounts for a significant percent of the file.
-	if freeBytes < byteThresh || pctFree < pctThresh {
+	if !compactOnShutdown && (freeBytes < byteThresh || pctFree < pctThresh) {
 		db.Close()
 		return
 	}
@@ -311,14 +380,14 @@ func (db *BoltDB) Recrypt(creds *dexdb.PrimaryCredentials, oldCrypter, newCrypte
 
 	return walletUpdates, acctUpdates, db.Update(func(tx *bbolt.Tx) error {
 		// Updates accounts and wallets.
-		wallets := tx.Bucket(walletsBucket)
-		if wallets == nil {
-			return fmt.Errorf("no wallets bucket")
+		wallets, err := db.bucket(tx, walletsBucket)
+		if err != nil {
+			return err
 		}
 
-		accounts := tx.Bucket(accountsBucket)
-		if accounts == nil {
-			return fmt.Errorf("no accounts bucket")
+		accounts, err := db.bucket(tx, accountsBucket)
+		if err != nil {
+			return err
 		}
 
 		if err := wallets.ForEach(func(wid, _ []byte) error {
@@ -339,6 +408,17 @@ func (db *BoltDB) Recrypt(creds *dexdb.PrimaryCredentials, oldCrypter, newCrypte
 			if err != nil {
 				return fmt.Errorf("Encrypt error: %w", err)
 			}
+			if len(w.EncryptedSeed) > 0 {
+				seed, err := oldCrypter.Decrypt(w.EncryptedSeed)
+				if err != nil {
+					return fmt.Errorf("seed Decrypt error: %w", err)
+				}
+				w.EncryptedSeed, err = newCrypter.Encrypt(seed)
+				encode.ClearBytes(seed)
+				if err != nil {
+					return fmt.Errorf("seed Encrypt error: %w", err)
+				}
+			}
 			err = wBkt.Put(walletKey, w.Encode())
 			if err != nil {
 				return err
@@ -392,6 +472,22 @@ func (db *BoltDB) Recrypt(creds *dexdb.PrimaryCredentials, oldCrypter, newCrypte
 			return fmt.Errorf("accounts update error: %w", err)
 		}
 
+		// Re-encrypt the database key.
+		if encKey := tx.Bucket(credentialsBucket).Get(encDBKeyKey); encKey != nil {
+			dbKey, err := oldCrypter.Decrypt(encKey)
+			if err != nil {
+				return fmt.Errorf("error decrypting database key: %w", err)
+			}
+			encKey, err = newCrypter.Encrypt(dbKey)
+			encode.ClearBytes(dbKey)
+			if err != nil {
+				return fmt.Errorf("error encrypting database key: %w", err)
+			}
+			if err := tx.Bucket(credentialsBucket).Put(encDBKeyKey, encKey); err != nil {
+				return err
+			}
+		}
+
 		// Store the new credentials.
 		return db.setCreds(tx, creds)
 	})
@@ -523,22 +619,29 @@ func (db *BoltDB) SeedGenerationTime() (uint64, error) {
 // account per DEX, so the account itself is identified by the DEX URL.
 func (db *BoltDB) ListAccounts() ([]string, error) {
 	var urls []string
-	return urls, db.acctsView(func(accts *bbolt.Bucket) error {
+	return urls, db.acctsView(func(accts *bucket) error {
 		c := accts.Cursor()
 		for acct, _ := c.First(); acct != nil; acct, _ = c.Next() {
 			acctBkt := accts.Bucket(acct)
 			if acctBkt == nil {
 				return fmt.Errorf("account bucket %s value not a nested bucket", string(acct))
 			}
-			if bEqual(acctBkt.Get(activeKey), byteTrue) {
-				urls = append(urls, string(acct))
+			if !bEqual(acctBkt.Get(activeKey), byteTrue) {
+				continue
+			}
+			// The bucket key is a hash of the host if the database is
+			// encrypted.
+			acctInfo, err := dexdb.DecodeAccountInfo(acctBkt.Get(accountKey))
+			if err != nil {
+				return fmt.Errorf("error decoding account: %w", err)
 			}
+			urls = append(urls, acctInfo.Host)
 		}
 		return nil
 	})
 }
 
-func loadAccountInfo(acct *bbolt.Bucket, log dex.Logger) (*db.AccountInfo, error) {
+func loadAccountInfo(acct *bucket, log dex.Logger) (*db.AccountInfo, error) {
 	acctB := getCopy(acct, accountKey)
 	if acctB == nil {
 		return nil, fmt.Errorf("empty account")
@@ -579,7 +682,7 @@ func loadAccountInfo(acct *bbolt.Bucket, log dex.Logger) (*db.AccountInfo, error
 // allow bonds filter based on lockTime.
 func (db *BoltDB) Accounts() ([]*dexdb.AccountInfo, error) {
 	var accounts []*dexdb.AccountInfo
-	return accounts, db.acctsView(func(accts *bbolt.Bucket) error {
+	return accounts, db.acctsView(func(accts *bucket) error {
 		c := accts.Cursor()
 		for acctKey, _ := c.First(); acctKey != nil; acctKey, _ = c.Next() {
 			acct := accts.Bucket(acctKey)
@@ -599,9 +702,8 @@ func (db *BoltDB) Accounts() ([]*dexdb.AccountInfo, error) {
 // Account gets the AccountInfo associated with the specified DEX address.
 func (db *BoltDB) Account(url string) (*dexdb.AccountInfo, error) {
 	var acctInfo *dexdb.AccountInfo
-	acctKey := []byte(url)
-	return acctInfo, db.acctsView(func(accts *bbolt.Bucket) (err error) {
-		acct := accts.Bucket(acctKey)
+	return acctInfo, db.acctsView(func(accts *bucket) (err error) {
+		acct := accts.Bucket(acctKey(accts, url))
 		if acct == nil {
 			return dexdb.ErrAcctNotFound
 		}
@@ -619,8 +721,8 @@ func (db *BoltDB) CreateAccount(ai *dexdb.AccountInfo) error {
 	if ai.DEXPubKey == nil {
 		return fmt.Errorf("nil DEXPubKey not allowed")
 	}
-	return db.acctsUpdate(func(accts *bbolt.Bucket) error {
-		acct, err := accts.CreateBucket([]byte(ai.Host))
+	return db.acctsUpdate(func(accts *bucket) error {
+		acct, err := accts.CreateBucket(acctKey(accts, ai.Host))
 		if err != nil {
 			return fmt.Errorf("failed to create account bucket: %w", err)
 		}
@@ -661,9 +763,9 @@ func (db *BoltDB) CreateAccount(ai *dexdb.AccountInfo) error {
 func (db *BoltDB) NextBondKeyIndex(assetID uint32) (uint32, error) {
 	var bondIndex uint32
 	return bondIndex, db.Update(func(tx *bbolt.Tx) error {
-		bkt := tx.Bucket(bondIndexesBucket)
-		if bkt == nil {
-			return errors.New("no bond indexes bucket")
+		bkt, err := db.bucket(tx, bondIndexesBucket)
+		if err != nil {
+			return err
 		}
 
 		thisBondIdxKey := uint32Bytes(assetID)
@@ -679,8 +781,8 @@ func (db *BoltDB) NextBondKeyIndex(assetID uint32) (uint32, error) {
 // the same Host as the parameter. If no account exists with this host,
 // an error is returned.
 func (db *BoltDB) UpdateAccountInfo(ai *dexdb.AccountInfo) error {
-	return db.acctsUpdate(func(accts *bbolt.Bucket) error {
-		acct := accts.Bucket([]byte(ai.Host))
+	return db.acctsUpdate(func(accts *bucket) error {
+		acct := accts.Bucket(acctKey(accts, ai.Host))
 		if acct == nil {
 			return fmt.Errorf("account not found for %s", ai.Host)
 		}
@@ -715,8 +817,8 @@ func (db *BoltDB) UpdateAccountInfo(ai *dexdb.AccountInfo) error {
 // ToggleAccountStatus enables or disables the account associated with the given
 // host.
 func (db *BoltDB) ToggleAccountStatus(host string, disable bool) error {
-	return db.acctsUpdate(func(accts *bbolt.Bucket) error {
-		acct := accts.Bucket([]byte(host))
+	return db.acctsUpdate(func(accts *bucket) error {
+		acct := accts.Bucket(acctKey(accts, host))
 		if acct == nil {
 			return fmt.Errorf("account not found for %s", host)
 		}
@@ -752,7 +854,7 @@ func (db *BoltDB) acctsUpdate(f bucketFunc) error {
 	return db.withBucket(accountsBucket, db.Update, f)
 }
 
-func (db *BoltDB) storeBond(bondBkt *bbolt.Bucket, bond *db.Bond) error {
+func (db *BoltDB) storeBond(bondBkt *bucket, bond *db.Bond) error {
 	err := bondBkt.Put(bondKey, bond.Encode())
 	if err != nil {
 		return fmt.Errorf("bondKey put error: %w", err)
@@ -787,9 +889,8 @@ func (db *BoltDB) storeBond(bondBkt *bbolt.Bucket, bond *db.Bond) error {
 // AddBond saves a new Bond or updates an existing bond for an existing DEX
 // account.
 func (db *BoltDB) AddBond(host string, bond *db.Bond) error {
-	acctKey := []byte(host)
-	return db.acctsUpdate(func(accts *bbolt.Bucket) error {
-		acct := accts.Bucket(acctKey)
+	return db.acctsUpdate(func(accts *bucket) error {
+		acct := accts.Bucket(acctKey(accts, host))
 		if acct == nil {
 			return fmt.Errorf("account not found for %s", host)
 		}
@@ -810,9 +911,8 @@ func (db *BoltDB) AddBond(host string, bond *db.Bond) error {
 }
 
 func (db *BoltDB) setBondFlag(host string, assetID uint32, bondCoinID []byte, flagKey []byte) error {
-	acctKey := []byte(host)
-	return db.acctsUpdate(func(accts *bbolt.Bucket) error {
-		acct := accts.Bucket(acctKey)
+	return db.acctsUpdate(func(accts *bucket) error {
+		acct := accts.Bucket(acctKey(accts, host))
 		if acct == nil {
 			return fmt.Errorf("account not found for %s", host)
 		}
@@ -855,7 +955,7 @@ func (db *BoltDB) UpdateOrder(m *dexdb.MetaOrder) error {
 	if len(md.Proof.DEXSig) == 0 {
 		return fmt.Errorf("cannot save order without DEX signature")
 	}
-	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
+	return db.ordersUpdate(func(ob, archivedOB *bucket) error {
 		oid := ord.ID()
 
 		// Create or move an order bucket based on order status. Active
@@ -902,14 +1002,20 @@ func (db *BoltDB) UpdateOrder(m *dexdb.MetaOrder) error {
 			return err
 		}
 
-		return updateOrderMetaData(oBkt, md)
+		if err := db.updateFeeStats(ob.b.Tx(), oBkt, md); err != nil {
+			return err
+		}
+		if err := updateOrderMetaData(oBkt, md); err != nil {
+			return err
+		}
+		return db.indexOrder(ob.b.Tx(), m)
 	})
 }
 
 // ActiveOrders retrieves all orders which appear to be in an active state,
 // which is either in the epoch queue or in the order book.
 func (db *BoltDB) ActiveOrders() ([]*dexdb.MetaOrder, error) {
-	return db.filteredOrders(func(_ *bbolt.Bucket) bool {
+	return db.filteredOrders(func(_ *bucket) bool {
 		return true
 	}, false)
 }
@@ -920,12 +1026,12 @@ func (db *BoltDB) ActiveOrders() ([]*dexdb.MetaOrder, error) {
 func (db *BoltDB) AccountOrders(dex string, n int, since uint64) ([]*dexdb.MetaOrder, error) {
 	dexB := []byte(dex)
 	if n == 0 && since == 0 {
-		return db.filteredOrders(func(oBkt *bbolt.Bucket) bool {
+		return db.filteredOrders(func(oBkt *bucket) bool {
 			return bEqual(dexB, oBkt.Get(dexKey))
 		}, true)
 	}
 	sinceB := uint64Bytes(since)
-	return db.newestOrders(n, func(_ []byte, oBkt *bbolt.Bucket) bool {
+	return db.newestOrders(n, func(_ []byte, oBkt *bucket) bool {
 		timeB := oBkt.Get(updateTimeKey)
 		return bEqual(dexB, oBkt.Get(dexKey)) && bytes.Compare(timeB, sinceB) >= 0
 	}, true)
@@ -947,14 +1053,14 @@ func (db *BoltDB) MarketOrders(dex string, base, quote uint32, n int, since uint
 // ActiveDEXOrders retrieves all orders for the specified DEX.
 func (db *BoltDB) ActiveDEXOrders(dex string) ([]*dexdb.MetaOrder, error) {
 	dexB := []byte(dex)
-	return db.filteredOrders(func(oBkt *bbolt.Bucket) bool {
+	return db.filteredOrders(func(oBkt *bucket) bool {
 		return bEqual(dexB, oBkt.Get(dexKey))
 	}, false)
 }
 
 // marketOrdersAll retrieves all orders for the specified DEX and market.
 func (db *BoltDB) marketOrdersAll(dexB, baseB, quoteB []byte) ([]*dexdb.MetaOrder, error) {
-	return db.filteredOrders(func(oBkt *bbolt.Bucket) bool {
+	return db.filteredOrders(func(oBkt *bucket) bool {
 		return bEqual(dexB, oBkt.Get(dexKey)) && bEqual(baseB, oBkt.Get(baseKey)) &&
 			bEqual(quoteB, oBkt.Get(quoteKey))
 	}, true)
@@ -966,7 +1072,7 @@ func (db *BoltDB) marketOrdersAll(dexB, baseB, quoteB []byte) ([]*dexdb.MetaOrde
 // use marketOrdersAll instead.
 func (db *BoltDB) marketOrdersSince(dexB, baseB, quoteB []byte, n int, since uint64) ([]*dexdb.MetaOrder, error) {
 	sinceB := uint64Bytes(since)
-	return db.newestOrders(n, func(_ []byte, oBkt *bbolt.Bucket) bool {
+	return db.newestOrders(n, func(_ []byte, oBkt *bucket) bool {
 		timeB := oBkt.Get(updateTimeKey)
 		return bEqual(dexB, oBkt.Get(dexKey)) && bEqual(baseB, oBkt.Get(baseKey)) &&
 			bEqual(quoteB, oBkt.Get(quoteKey)) && bytes.Compare(timeB, sinceB) >= 0
@@ -977,10 +1083,10 @@ func (db *BoltDB) marketOrdersSince(dexB, baseB, quoteB []byte, n int, since uin
 // function. Each order's bucket is provided to the filter, and a boolean true
 // return value indicates the order should is eligible to be decoded and
 // returned.
-func (db *BoltDB) newestOrders(n int, filter func([]byte, *bbolt.Bucket) bool, includeArchived bool) ([]*dexdb.MetaOrder, error) {
+func (db *BoltDB) newestOrders(n int, filter func([]byte, *bucket) bool, includeArchived bool) ([]*dexdb.MetaOrder, error) {
 	orders := make([]*dexdb.MetaOrder, 0, n)
-	return orders, db.ordersView(func(ob, archivedOB *bbolt.Bucket) error {
-		buckets := []*bbolt.Bucket{ob}
+	return orders, db.ordersView(func(ob, archivedOB *bucket) error {
+		buckets := []*bucket{ob}
 		if includeArchived {
 			buckets = append(buckets, archivedOB)
 		}
@@ -999,10 +1105,10 @@ func (db *BoltDB) newestOrders(n int, filter func([]byte, *bbolt.Bucket) bool, i
 // filteredOrders gets all orders that pass the provided filter function. Each
 // order's bucket is provided to the filter, and a boolean true return value
 // indicates the order should be decoded and returned.
-func (db *BoltDB) filteredOrders(filter func(*bbolt.Bucket) bool, includeArchived bool) ([]*dexdb.MetaOrder, error) {
+func (db *BoltDB) filteredOrders(filter func(*bucket) bool, includeArchived bool) ([]*dexdb.MetaOrder, error) {
 	var orders []*dexdb.MetaOrder
-	return orders, db.ordersView(func(ob, archivedOB *bbolt.Bucket) error {
-		buckets := []*bbolt.Bucket{ob}
+	return orders, db.ordersView(func(ob, archivedOB *bucket) error {
+		buckets := []*bucket{ob}
 		if includeArchived {
 			buckets = append(buckets, archivedOB)
 		}
@@ -1032,7 +1138,7 @@ func (db *BoltDB) filteredOrders(filter func(*bbolt.Bucket) bool, includeArchive
 // Order fetches a MetaOrder by order ID.
 func (db *BoltDB) Order(oid order.OrderID) (mord *dexdb.MetaOrder, err error) {
 	oidB := oid[:]
-	err = db.ordersView(func(ob, archivedOB *bbolt.Bucket) error {
+	err = db.ordersView(func(ob, archivedOB *bucket) error {
 		oBkt := ob.Bucket(oidB)
 		// If the order is not in the active bucket, check the archived
 		// orders bucket.
@@ -1052,11 +1158,11 @@ func (db *BoltDB) Order(oid order.OrderID) (mord *dexdb.MetaOrder, err error) {
 // filterSet is a set of bucket filtering functions. Each function takes a
 // bucket key and the associated bucket, and should return true if the bucket
 // passes the filter.
-type filterSet []func(oidB []byte, oBkt *bbolt.Bucket) bool
+type filterSet []func(oidB []byte, oBkt *bucket) bool
 
 // check runs the bucket through all filters, and will return false if the
 // bucket fails to pass any one filter.
-func (fs filterSet) check(oidB []byte, oBkt *bbolt.Bucket) bool {
+func (fs filterSet) check(oidB []byte, oBkt *bucket) bool {
 	for _, f := range fs {
 		if !f(oidB, oBkt) {
 			return false
@@ -1070,7 +1176,7 @@ func (fs filterSet) check(oidB []byte, oBkt *bbolt.Bucket) bool {
 func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrder, err error) {
 	// Default filter is just to exclude cancel orders.
 	filters := filterSet{
-		func(oidB []byte, oBkt *bbolt.Bucket) bool {
+		func(oidB []byte, oBkt *bucket) bool {
 			oTypeB := oBkt.Get(typeKey)
 			if len(oTypeB) != 1 {
 				db.log.Error("encountered order type encoded with wrong number of bytes = %d for order %x", len(oTypeB), oidB)
@@ -1086,7 +1192,7 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 		for _, host := range orderFilter.Hosts {
 			hosts[host] = true
 		}
-		filters = append(filters, func(_ []byte, oBkt *bbolt.Bucket) bool {
+		filters = append(filters, func(_ []byte, oBkt *bucket) bool {
 			return hosts[string(oBkt.Get(dexKey))]
 		})
 	}
@@ -1096,14 +1202,14 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 		for _, assetID := range orderFilter.Assets {
 			assetIDs[assetID] = true
 		}
-		filters = append(filters, func(_ []byte, oBkt *bbolt.Bucket) bool {
+		filters = append(filters, func(_ []byte, oBkt *bucket) bool {
 			return assetIDs[intCoder.Uint32(oBkt.Get(baseKey))] || assetIDs[intCoder.Uint32(oBkt.Get(quoteKey))]
 		})
 	}
 
 	includeArchived := true
 	if len(orderFilter.Statuses) > 0 {
-		filters = append(filters, func(_ []byte, oBkt *bbolt.Bucket) bool {
+		filters = append(filters, func(_ []byte, oBkt *bucket) bool {
 			status := order.OrderStatus(intCoder.Uint16(oBkt.Get(statusKey)))
 			return slices.Contains(orderFilter.Statuses, status)
 		})
@@ -1117,16 +1223,27 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 	}
 
 	if orderFilter.Market != nil {
-		filters = append(filters, func(_ []byte, oBkt *bbolt.Bucket) bool {
+		filters = append(filters, func(_ []byte, oBkt *bucket) bool {
 			baseID, quoteID := intCoder.Uint32(oBkt.Get(baseKey)), intCoder.Uint32(oBkt.Get(quoteKey))
 			return orderFilter.Market.Base == baseID && orderFilter.Market.Quote == quoteID
 		})
 	}
 
+	if orderFilter.Since > 0 || orderFilter.Until > 0 {
+		filters = append(filters, func(_ []byte, oBkt *bucket) bool {
+			stampB := oBkt.Get(updateTimeKey)
+			if len(stampB) != 8 {
+				return false
+			}
+			stamp := intCoder.Uint64(stampB)
+			return stamp >= orderFilter.Since && (orderFilter.Until == 0 || stamp < orderFilter.Until)
+		})
+	}
+
 	if !orderFilter.Offset.IsZero() {
 		offsetOID := orderFilter.Offset
 		var stampB []byte
-		err := db.ordersView(func(ob, archivedOB *bbolt.Bucket) error {
+		err := db.ordersView(func(ob, archivedOB *bucket) error {
 			offsetBucket := ob.Bucket(offsetOID[:])
 			// If the order is not in the active bucket, check the
 			// archived orders bucket.
@@ -1143,7 +1260,7 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 			return nil, err
 		}
 
-		filters = append(filters, func(oidB []byte, oBkt *bbolt.Bucket) bool {
+		filters = append(filters, func(oidB []byte, oBkt *bucket) bool {
 			comp := bytes.Compare(oBkt.Get(updateTimeKey), stampB)
 			return comp < 0 || (comp == 0 && bytes.Compare(offsetOID[:], oidB) < 0)
 		})
@@ -1152,8 +1269,8 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 	return db.newestOrders(orderFilter.N, filters.check, includeArchived)
 }
 
-// decodeOrderBucket decodes the order's *bbolt.Bucket into a *MetaOrder.
-func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error) {
+// decodeOrderBucket decodes the order's *bucket into a *MetaOrder.
+func decodeOrderBucket(oid []byte, oBkt *bucket) (*dexdb.MetaOrder, error) {
 	orderB := getCopy(oBkt, orderKey)
 	if orderB == nil {
 		return nil, fmt.Errorf("nil order bytes for order %x", oid)
@@ -1278,7 +1395,7 @@ func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error)
 // found it moves the order to the archived bucket and returns that order
 // bucket. If status is less than or equal to booked, it expects the order
 // to already be in active orders and returns that bucket.
-func updateOrderBucket(ob, archivedOB *bbolt.Bucket, oid order.OrderID, status order.OrderStatus) (*bbolt.Bucket, error) {
+func updateOrderBucket(ob, archivedOB *bucket, oid order.OrderID, status order.OrderStatus) (*bucket, error) {
 	if status == order.OrderStatusUnknown {
 		return nil, fmt.Errorf("cannot set order %s status to unknown", oid)
 	}
@@ -1319,17 +1436,31 @@ func updateOrderBucket(ob, archivedOB *bbolt.Bucket, oid order.OrderID, status o
 
 // UpdateOrderMetaData updates the order metadata, not including the Host.
 func (db *BoltDB) UpdateOrderMetaData(oid order.OrderID, md *dexdb.OrderMetaData) error {
-	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
+	return db.ordersUpdate(func(ob, archivedOB *bucket) error {
 		oBkt, err := updateOrderBucket(ob, archivedOB, oid, md.Status)
 		if err != nil {
 			return fmt.Errorf("UpdateOrderMetaData: %w", err)
 		}
 
-		return updateOrderMetaData(oBkt, md)
+		if err := db.updateFeeStats(ob.b.Tx(), oBkt, md); err != nil {
+			return err
+		}
+		if err := updateOrderMetaData(oBkt, md); err != nil {
+			return err
+		}
+		// Index the change coin.
+		if !searchIndexed(ob.b.Tx()) {
+			return nil
+		}
+		mord, err := decodeOrderBucket(oid[:], oBkt)
+		if err != nil {
+			return err
+		}
+		return db.indexOrder(ob.b.Tx(), mord)
 	})
 }
 
-func updateOrderMetaData(bkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
+func updateOrderMetaData(bkt *bucket, md *dexdb.OrderMetaData) error {
 	var linkedB []byte
 	if !md.LinkedOrder.IsZero() {
 		linkedB = md.LinkedOrder[:]
@@ -1361,7 +1492,7 @@ func updateOrderMetaData(bkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
 
 // UpdateOrderStatus sets the order status for an order.
 func (db *BoltDB) UpdateOrderStatus(oid order.OrderID, status order.OrderStatus) error {
-	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
+	return db.ordersUpdate(func(ob, archivedOB *bucket) error {
 		oBkt, err := updateOrderBucket(ob, archivedOB, oid, status)
 		if err != nil {
 			return fmt.Errorf("UpdateOrderStatus: %w", err)
@@ -1372,7 +1503,7 @@ func (db *BoltDB) UpdateOrderStatus(oid order.OrderID, status order.OrderStatus)
 
 // LinkOrder sets the linked order.
 func (db *BoltDB) LinkOrder(oid, linkedID order.OrderID) error {
-	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
+	return db.ordersUpdate(func(ob, archivedOB *bucket) error {
 		oBkt := ob.Bucket(oid[:])
 		// If the order is not in the active bucket, check the archived
 		// orders bucket.
@@ -1394,15 +1525,15 @@ func (db *BoltDB) LinkOrder(oid, linkedID order.OrderID) error {
 // Orders are spread over two buckets to make searching active orders faster.
 // Any reads of the order buckets should be done in the same transaction, as
 // orders may move from active to archived at any time.
-func (db *BoltDB) ordersView(f func(ob, archivedOB *bbolt.Bucket) error) error {
+func (db *BoltDB) ordersView(f func(ob, archivedOB *bucket) error) error {
 	return db.View(func(tx *bbolt.Tx) error {
-		ob := tx.Bucket(activeOrdersBucket)
-		if ob == nil {
-			return fmt.Errorf("failed to open %s bucket", string(activeOrdersBucket))
+		ob, err := db.bucket(tx, activeOrdersBucket)
+		if err != nil {
+			return err
 		}
-		archivedOB := tx.Bucket(archivedOrdersBucket)
-		if archivedOB == nil {
-			return fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
+		archivedOB, err := db.bucket(tx, archivedOrdersBucket)
+		if err != nil {
+			return err
 		}
 		return f(ob, archivedOB)
 	})
@@ -1412,15 +1543,15 @@ func (db *BoltDB) ordersView(f func(ob, archivedOB *bbolt.Bucket) error) error {
 // Orders are spread over two buckets to make searching active orders faster.
 // Any writes of the order buckets should be done in the same transaction to
 // ensure that reads can be kept concurrent.
-func (db *BoltDB) ordersUpdate(f func(ob, archivedOB *bbolt.Bucket) error) error {
+func (db *BoltDB) ordersUpdate(f func(ob, archivedOB *bucket) error) error {
 	return db.Update(func(tx *bbolt.Tx) error {
-		ob := tx.Bucket(activeOrdersBucket)
-		if ob == nil {
-			return fmt.Errorf("failed to open %s bucket", string(activeOrdersBucket))
+		ob, err := db.bucket(tx, activeOrdersBucket)
+		if err != nil {
+			return err
 		}
-		archivedOB := tx.Bucket(archivedOrdersBucket)
-		if archivedOB == nil {
-			return fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
+		archivedOB, err := db.bucket(tx, archivedOrdersBucket)
+		if err != nil {
+			return err
 		}
 		return f(ob, archivedOB)
 	})
@@ -1430,7 +1561,7 @@ func (db *BoltDB) ordersUpdate(f func(ob, archivedOB *bbolt.Bucket) error) error
 // bucket. It will be created if it is not in either matches bucket. If an
 // inactive match is found in the active bucket, it is moved to the archived
 // matches bucket.
-func matchBucket(mb, archivedMB *bbolt.Bucket, metaID []byte, active bool) (*bbolt.Bucket, error) {
+func matchBucket(mb, archivedMB *bucket, metaID []byte, active bool) (*bucket, error) {
 	if active {
 		// Active match would be in active bucket if it was previously inserted.
 		return mb.CreateBucketIfNotExists(metaID) // might exist already
@@ -1471,7 +1602,7 @@ func (db *BoltDB) UpdateMatch(m *dexdb.MetaMatch) error {
 	if md.DEX == "" {
 		return fmt.Errorf("empty DEX not allowed")
 	}
-	return db.matchesUpdate(func(mb, archivedMB *bbolt.Bucket) error {
+	return db.matchesUpdate(func(mb, archivedMB *bucket) error {
 		metaID := m.MatchOrderUniqueID()
 		active := dexdb.MatchIsActive(m.UserMatch, &m.MetaData.Proof)
 		mBkt, err := matchBucket(mb, archivedMB, metaID, active)
@@ -1479,7 +1610,7 @@ func (db *BoltDB) UpdateMatch(m *dexdb.MetaMatch) error {
 			return err
 		}
 
-		return newBucketPutter(mBkt).
+		err = newBucketPutter(mBkt).
 			put(baseKey, uint32Bytes(md.Base)).
 			put(quoteKey, uint32Bytes(md.Quote)).
 			put(statusKey, []byte{byte(match.Status)}).
@@ -1491,6 +1622,13 @@ func (db *BoltDB) UpdateMatch(m *dexdb.MetaMatch) error {
 			put(matchKey, order.EncodeMatch(match)).
 			put(stampKey, uint64Bytes(md.Stamp)).
 			err()
+		if err != nil {
+			return err
+		}
+		if err := db.updateMatchStats(mb.b.Tx(), mBkt, m); err != nil {
+			return err
+		}
+		return db.indexMatch(mb.b.Tx(), m)
 	})
 }
 
@@ -1498,7 +1636,7 @@ func (db *BoltDB) UpdateMatch(m *dexdb.MetaMatch) error {
 // any match that is still active.
 func (db *BoltDB) ActiveMatches() ([]*dexdb.MetaMatch, error) {
 	return db.filteredMatches(
-		func(mBkt *bbolt.Bucket) bool {
+		func(mBkt *bucket) bool {
 			return true // all matches in the bucket
 		},
 		true,  // don't bother with cancel matches that are never active
@@ -1512,7 +1650,7 @@ func (db *BoltDB) DEXOrdersWithActiveMatches(dex string) ([]order.OrderID, error
 	dexB := []byte(dex)
 	// For each match for this DEX, pick the active ones.
 	idMap := make(map[order.OrderID]bool)
-	err := db.matchesView(func(ob, _ *bbolt.Bucket) error { // only the active matches bucket is used
+	err := db.matchesView(func(ob, _ *bucket) error { // only the active matches bucket is used
 		return ob.ForEach(func(k, _ []byte) error {
 			mBkt := ob.Bucket(k)
 			if mBkt == nil {
@@ -1543,7 +1681,7 @@ func (db *BoltDB) DEXOrdersWithActiveMatches(dex string) ([]order.OrderID, error
 // MatchesForOrder retrieves the matches for the specified order ID.
 func (db *BoltDB) MatchesForOrder(oid order.OrderID, excludeCancels bool) ([]*dexdb.MetaMatch, error) {
 	oidB := oid[:]
-	return db.filteredMatches(func(mBkt *bbolt.Bucket) bool {
+	return db.filteredMatches(func(mBkt *bucket) bool {
 		oid := mBkt.Get(orderIDKey)
 		return bytes.Equal(oid, oidB)
 	}, excludeCancels, true) // include archived matches
@@ -1554,10 +1692,10 @@ func (db *BoltDB) MatchesForOrder(oid order.OrderID, excludeCancels bool) ([]*de
 // indicates the match should be decoded and returned. Matches with cancel
 // orders may be excluded, a separate option so the filter function does not
 // need to load and decode the matchKey value.
-func (db *BoltDB) filteredMatches(filter func(*bbolt.Bucket) bool, excludeCancels, includeArchived bool) ([]*dexdb.MetaMatch, error) {
+func (db *BoltDB) filteredMatches(filter func(*bucket) bool, excludeCancels, includeArchived bool) ([]*dexdb.MetaMatch, error) {
 	var matches []*dexdb.MetaMatch
-	return matches, db.matchesView(func(mb, archivedMB *bbolt.Bucket) error {
-		buckets := []*bbolt.Bucket{mb}
+	return matches, db.matchesView(func(mb, archivedMB *bucket) error {
+		buckets := []*bucket{mb}
 		if includeArchived {
 			buckets = append(buckets, archivedMB)
 		}
@@ -1587,7 +1725,7 @@ func (db *BoltDB) filteredMatches(filter func(*bbolt.Bucket) bool, excludeCancel
 	})
 }
 
-func loadMatchBucket(mBkt *bbolt.Bucket, excludeCancels bool) (*dexdb.MetaMatch, error) {
+func loadMatchBucket(mBkt *bucket, excludeCancels bool) (*dexdb.MetaMatch, error) {
 	var proof *dexdb.MatchProof
 	matchB := getCopy(mBkt, matchKey)
 	if matchB == nil {
@@ -1632,30 +1770,30 @@ func loadMatchBucket(mBkt *bbolt.Bucket, excludeCancels bool) (*dexdb.MetaMatch,
 }
 
 // matchesView is a convenience function for reading from the match bucket.
-func (db *BoltDB) matchesView(f func(mb, archivedMB *bbolt.Bucket) error) error {
+func (db *BoltDB) matchesView(f func(mb, archivedMB *bucket) error) error {
 	return db.View(func(tx *bbolt.Tx) error {
-		mb := tx.Bucket(activeMatchesBucket)
-		if mb == nil {
-			return fmt.Errorf("failed to open %s bucket", string(activeMatchesBucket))
+		mb, err := db.bucket(tx, activeMatchesBucket)
+		if err != nil {
+			return err
 		}
-		archivedMB := tx.Bucket(archivedMatchesBucket)
-		if archivedMB == nil {
-			return fmt.Errorf("failed to open %s bucket", string(archivedMatchesBucket))
+		archivedMB, err := db.bucket(tx, archivedMatchesBucket)
+		if err != nil {
+			return err
 		}
 		return f(mb, archivedMB)
 	})
 }
 
 // matchesUpdate is a convenience function for updating the match bucket.
-func (db *BoltDB) matchesUpdate(f func(mb, archivedMB *bbolt.Bucket) error) error {
+func (db *BoltDB) matchesUpdate(f func(mb, archivedMB *bucket) error) error {
 	return db.Update(func(tx *bbolt.Tx) error {
-		mb := tx.Bucket(activeMatchesBucket)
-		if mb == nil {
-			return fmt.Errorf("failed to open %s bucket", string(activeMatchesBucket))
+		mb, err := db.bucket(tx, activeMatchesBucket)
+		if err != nil {
+			return err
 		}
-		archivedMB := tx.Bucket(archivedMatchesBucket)
-		if archivedMB == nil {
-			return fmt.Errorf("failed to open %s bucket", string(archivedMatchesBucket))
+		archivedMB, err := db.bucket(tx, archivedMatchesBucket)
+		if err != nil {
+			return err
 		}
 		return f(mb, archivedMB)
 	})
@@ -1666,7 +1804,7 @@ func (db *BoltDB) UpdateWallet(wallet *dexdb.Wallet) error {
 	if wallet.Balance == nil {
 		return fmt.Errorf("cannot UpdateWallet with nil Balance field")
 	}
-	return db.walletsUpdate(func(master *bbolt.Bucket) error {
+	return db.walletsUpdate(func(master *bucket) error {
 		wBkt, err := master.CreateBucketIfNotExists(wallet.ID())
 		if err != nil {
 			return err
@@ -1681,7 +1819,7 @@ func (db *BoltDB) UpdateWallet(wallet *dexdb.Wallet) error {
 
 // SetWalletPassword set the encrypted password field for the wallet.
 func (db *BoltDB) SetWalletPassword(wid []byte, newEncPW []byte) error {
-	return db.walletsUpdate(func(master *bbolt.Bucket) error {
+	return db.walletsUpdate(func(master *bucket) error {
 		wBkt := master.Bucket(wid)
 		if wBkt == nil {
 			return fmt.Errorf("wallet with ID is %x not known", wid)
@@ -1703,9 +1841,10 @@ func (db *BoltDB) SetWalletPassword(wid []byte, newEncPW []byte) error {
 	})
 }
 
-// UpdateBalance updates balance in the wallet bucket.
+// UpdateBalance updates balance in the wallet bucket. Balance updates are
+// frequent, so they are batched with other concurrent writes.
 func (db *BoltDB) UpdateBalance(wid []byte, bal *dexdb.Balance) error {
-	return db.walletsUpdate(func(master *bbolt.Bucket) error {
+	return db.withBucket(walletsBucket, db.Batch, func(master *bucket) error {
 		wBkt := master.Bucket(wid)
 		if wBkt == nil {
 			return fmt.Errorf("wallet %x bucket is not a bucket", wid)
@@ -1716,7 +1855,7 @@ func (db *BoltDB) UpdateBalance(wid []byte, bal *dexdb.Balance) error {
 
 // UpdateWalletStatus updates a wallet's status.
 func (db *BoltDB) UpdateWalletStatus(wid []byte, disable bool) error {
-	return db.walletsUpdate(func(master *bbolt.Bucket) error {
+	return db.walletsUpdate(func(master *bucket) error {
 		wBkt := master.Bucket(wid)
 		if wBkt == nil {
 			return fmt.Errorf("wallet %x bucket is not a bucket", wid)
@@ -1731,7 +1870,7 @@ func (db *BoltDB) UpdateWalletStatus(wid []byte, disable bool) error {
 // Wallets loads all wallets from the database.
 func (db *BoltDB) Wallets() ([]*dexdb.Wallet, error) {
 	var wallets []*dexdb.Wallet
-	return wallets, db.walletsView(func(master *bbolt.Bucket) error {
+	return wallets, db.walletsView(func(master *bucket) error {
 		c := master.Cursor()
 		// key, _ := c.First()
 		for wid, _ := c.First(); wid != nil; wid, _ = c.Next() {
@@ -1747,13 +1886,13 @@ func (db *BoltDB) Wallets() ([]*dexdb.Wallet, error) {
 
 // Wallet loads a single wallet from the database.
 func (db *BoltDB) Wallet(wid []byte) (wallet *dexdb.Wallet, err error) {
-	return wallet, db.walletsView(func(master *bbolt.Bucket) error {
+	return wallet, db.walletsView(func(master *bucket) error {
 		wallet, err = makeWallet(master.Bucket(wid))
 		return err
 	})
 }
 
-func makeWallet(wBkt *bbolt.Bucket) (*dexdb.Wallet, error) {
+func makeWallet(wBkt *bucket) (*dexdb.Wallet, error) {
 	if wBkt == nil {
 		return nil, fmt.Errorf("wallets bucket value not a nested bucket")
 	}
@@ -1798,7 +1937,7 @@ func (db *BoltDB) SaveNotification(note *dexdb.Notification) error {
 	if note.Severeness < dexdb.Success {
 		return fmt.Errorf("storage of notification with severity %s is forbidden", note.Severeness)
 	}
-	return db.notesUpdate(func(master *bbolt.Bucket) error {
+	return db.notesBatch(func(master *bucket) error {
 		noteB := note.Encode()
 		k := note.ID()
 		noteBkt, err := master.CreateBucketIfNotExists(k)
@@ -1813,13 +1952,16 @@ func (db *BoltDB) SaveNotification(note *dexdb.Notification) error {
 		if err != nil {
 			return err
 		}
-		return noteBkt.Put(noteKey, noteB)
+		if err := noteBkt.Put(noteKey, noteB); err != nil {
+			return err
+		}
+		return db.indexNote(master.b.Tx(), k, note)
 	})
 }
 
 // AckNotification sets the acknowledgement for a notification.
 func (db *BoltDB) AckNotification(id []byte) error {
-	return db.notesUpdate(func(master *bbolt.Bucket) error {
+	return db.notesBatch(func(master *bucket) error {
 		noteBkt := master.Bucket(id)
 		if noteBkt == nil {
 			return fmt.Errorf("notification not found")
@@ -1828,11 +1970,14 @@ func (db *BoltDB) AckNotification(id []byte) error {
 	})
 }
 
-// NotificationsN reads out the N most recent notifications.
+// NotificationsN reads out the N most recent notifications. The notifications
+// are read in a read-only transaction. A write transaction is only needed if
+// there are stored notes with bad keys to acknowledge.
 func (db *BoltDB) NotificationsN(n int) ([]*dexdb.Notification, error) {
 	notes := make([]*dexdb.Notification, 0, n)
-	return notes, db.notesUpdate(func(master *bbolt.Bucket) error {
-		trios := newestBuckets([]*bbolt.Bucket{master}, n, stampKey, nil)
+	var badKeys [][]byte
+	err := db.notesView(func(master *bucket) error {
+		trios := newestBuckets([]*bucket{master}, n, stampKey, nil)
 		for _, trio := range trios {
 			note, err := dexdb.DecodeNotification(getCopy(trio.b, noteKey))
 			if err != nil {
@@ -1847,7 +1992,7 @@ func (db *BoltDB) NotificationsN(n int) ([]*dexdb.Notification, error) {
 				if !note.Ack {
 					db.log.Tracef("Ignoring stored note with bad key: %x != %x \"%s\"",
 						[]byte(note.Id), trio.k, note.String())
-					_ = trio.b.Put(ackKey, byteTrue)
+					badKeys = append(badKeys, trio.k)
 				}
 				continue
 			}
@@ -1855,6 +2000,53 @@ func (db *BoltDB) NotificationsN(n int) ([]*dexdb.Notification, error) {
 		}
 		return nil
 	})
+	if err != nil || len(badKeys) == 0 {
+		return notes, err
+	}
+	return notes, db.notesBatch(func(master *bucket) error {
+		for _, k := range badKeys {
+			if noteBkt := master.Bucket(k); noteBkt != nil {
+				if err := noteBkt.Put(ackKey, byteTrue); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteNotifications deletes notifications older than the supplied time,
+// except for the newest keepNewest notifications if keepNewest is non-zero. If
+// no time is supplied, the current time is used.
+func (db *BoltDB) DeleteNotifications(olderThan *time.Time, keepNewest int) (int, error) {
+	olderThanB := uint64Bytes(timeNow())
+	if olderThan != nil && !olderThan.IsZero() {
+		olderThanB = uint64Bytes(uint64(olderThan.UnixMilli()))
+	}
+	var nDeleted int
+	return nDeleted, db.notesUpdate(func(master *bucket) error {
+		var keys, stamps [][]byte
+		if err := master.ForEach(func(k, _ []byte) error {
+			if noteBkt := master.Bucket(k); noteBkt != nil {
+				keys = append(keys, bytes.Clone(k))
+				stamps = append(stamps, getCopy(noteBkt, stampKey))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		cutoff := keepNewestCutoff(olderThanB, slices.Clone(stamps), keepNewest)
+		for i, k := range keys {
+			if bytes.Compare(stamps[i], cutoff) > 0 {
+				continue
+			}
+			if err := master.DeleteBucket(k); err != nil {
+				return fmt.Errorf("failed to delete notification bucket: %w", err)
+			}
+			nDeleted++
+		}
+		return nil
+	})
 }
 
 // notesView is a convenience function to read from the notifications bucket.
@@ -1867,6 +2059,12 @@ func (db *BoltDB) notesUpdate(f bucketFunc) error {
 	return db.withBucket(notesBucket, db.Update, f)
 }
 
+// notesBatch is a convenience function for batched updates of the
+// notifications bucket. f must be idempotent.
+func (db *BoltDB) notesBatch(f bucketFunc) error {
+	return db.withBucket(notesBucket, db.Batch, f)
+}
+
 // SavePokes saves a slice of notifications, overwriting any previously saved
 // slice.
 func (db *BoltDB) SavePokes(pokes []*dexdb.Notification) error {
@@ -1875,7 +2073,7 @@ func (db *BoltDB) SavePokes(pokes []*dexdb.Notification) error {
 	if err != nil {
 		return fmt.Errorf("JSON marshal error: %w", err)
 	}
-	return db.withBucket(pokesBucket, db.Update, func(bkt *bbolt.Bucket) error {
+	return db.withBucket(pokesBucket, db.Update, func(bkt *bucket) error {
 		return bkt.Put(pokesKey, b)
 	})
 }
@@ -1883,7 +2081,7 @@ func (db *BoltDB) SavePokes(pokes []*dexdb.Notification) error {
 // LoadPokes loads the slice of notifications last saved with SavePokes. The
 // loaded pokes are deleted from the database.
 func (db *BoltDB) LoadPokes() (pokes []*dexdb.Notification, _ error) {
-	return pokes, db.withBucket(pokesBucket, db.Update, func(bkt *bbolt.Bucket) error {
+	return pokes, db.withBucket(pokesBucket, db.Update, func(bkt *bucket) error {
 		b := bkt.Get(pokesKey)
 		if len(b) == 0 { // None saved
 			return nil
@@ -1898,19 +2096,20 @@ func (db *BoltDB) LoadPokes() (pokes []*dexdb.Notification, _ error) {
 // newest buckets gets the nested buckets with the highest timestamp from the
 // specified master buckets. The nested bucket should have an encoded uint64 at
 // the timeKey. An optional filter function can be used to reject buckets.
-func newestBuckets(buckets []*bbolt.Bucket, n int, timeKey []byte, filter func([]byte, *bbolt.Bucket) bool) []*keyTimeTrio {
+func newestBuckets(buckets []*bucket, n int, timeKey []byte, filter func([]byte, *bucket) bool) []*keyTimeTrio {
 	idx := newTimeIndexNewest(n)
 	for _, master := range buckets {
 		master.ForEach(func(k, _ []byte) error {
 			bkt := master.Bucket(k)
-			stamp := intCoder.Uint64(bkt.Get(timeKey))
+			// Filter first, since reading an encrypted time stamp is not
+			// free.
 			if filter == nil || filter(k, bkt) {
-				idx.add(stamp, k, bkt)
+				idx.add(intCoder.Uint64(bkt.Get(timeKey)), k, bkt)
 			}
 			return nil
 		})
 	}
-	return idx.trios
+	return idx.sorted()
 }
 
 // makeTopLevelBuckets creates a top-level bucket for each of the provided keys,
@@ -1933,9 +2132,9 @@ func (db *BoltDB) makeTopLevelBuckets(buckets [][]byte) error {
 // bucketFunc will be called with the requested bucket as its only argument.
 func (db *BoltDB) withBucket(bkt []byte, viewer txFunc, f bucketFunc) error {
 	return viewer(func(tx *bbolt.Tx) error {
-		bucket := tx.Bucket(bkt)
-		if bucket == nil {
-			return fmt.Errorf("failed to open %s bucket", string(bkt))
+		bucket, err := db.bucket(tx, bkt)
+		if err != nil {
+			return err
 		}
 		return f(bucket)
 	})
@@ -2030,15 +2229,16 @@ func (db *BoltDB) Backup() error {
 	return db.BackupTo(filepath.Join(dir, backupDir, file), true, false)
 }
 
-// bucketPutter enables chained calls to (*bbolt.Bucket).Put with error
+// bucketPutter enables chained calls to (*bucket).Put with error
 // deferment.
 type bucketPutter struct {
-	bucket *bbolt.Bucket
+	bucket interface{ Put(k, v []byte) error }
 	putErr error
 }
 
-// newBucketPutter is a constructor for a bucketPutter.
-func newBucketPutter(bkt *bbolt.Bucket) *bucketPutter {
+// newBucketPutter is a constructor for a bucketPutter. The bucket is a
+// *bbolt.Bucket or a *bucket.
+func newBucketPutter(bkt interface{ Put(k, v []byte) error }) *bucketPutter {
 	return &bucketPutter{bucket: bkt}
 }
 
@@ -2062,7 +2262,7 @@ func (bp *bucketPutter) err() error {
 type keyTimeTrio struct {
 	k []byte
 	t uint64
-	b *bbolt.Bucket
+	b *bucket
 }
 
 // timeIndexNewest is a struct used to build an index of sorted keyTimeTrios.
@@ -2081,42 +2281,56 @@ func newTimeIndexNewest(n int) *timeIndexNewest {
 	}
 }
 
+// newer is true if trio a sorts before trio b, which is when a is newer, or
+// when a has the lexicographically larger key if the times are equal.
+func (a *keyTimeTrio) newer(b *keyTimeTrio) bool {
+	return a.t > b.t || (a.t == b.t && bytes.Compare(a.k, b.k) == 1)
+}
+
 // Conditionally add a time-key trio to the index. The trio will only be added
-// if the timeIndexNewest is under capacity and the time t is larger than the
-// oldest trio's time.
-func (idx *timeIndexNewest) add(t uint64, k []byte, b *bbolt.Bucket) {
+// if the timeIndexNewest is under capacity or the trio is newer than the
+// oldest trio. An index with a capacity is kept sorted. An unlimited index is
+// sorted once by sorted, instead of on every add.
+func (idx *timeIndexNewest) add(t uint64, k []byte, b *bucket) {
+	trio := &keyTimeTrio{
+		// Need to make a copy, and []byte(k) upsets the linter.
+		k: append([]byte(nil), k...),
+		t: t,
+		b: b,
+	}
+	if idx.cap == 0 {
+		idx.trios = append(idx.trios, trio)
+		return
+	}
 	count := len(idx.trios)
-	if idx.cap == 0 || count < idx.cap {
-		idx.trios = append(idx.trios, &keyTimeTrio{
-			// Need to make a copy, and []byte(k) upsets the linter.
-			k: append([]byte(nil), k...),
-			t: t,
-			b: b,
-		})
-	} else {
-		// non-zero length, at capacity.
-		if t <= idx.trios[count-1].t {
-			// Too old. Discard.
-			return
-		}
-		idx.trios[count-1] = &keyTimeTrio{
-			k: append([]byte(nil), k...),
-			t: t,
-			b: b,
-		}
-	}
-	sort.Slice(idx.trios, func(i, j int) bool {
-		// newest (highest t) first, or by lexicographically by key if the times
-		// are equal.
-		t1, t2 := idx.trios[i].t, idx.trios[j].t
-		return t1 > t2 || (t1 == t2 && bytes.Compare(idx.trios[i].k, idx.trios[j].k) == 1)
+	if count == idx.cap && !trio.newer(idx.trios[count-1]) {
+		// Too old. Discard.
+		return
+	}
+	i := sort.Search(count, func(i int) bool {
+		return trio.newer(idx.trios[i])
 	})
+	if count < idx.cap {
+		idx.trios = append(idx.trios, nil)
+	}
+	copy(idx.trios[i+1:], idx.trios[i:])
+	idx.trios[i] = trio
+}
+
+// sorted returns the trios, newest first.
+func (idx *timeIndexNewest) sorted() []*keyTimeTrio {
+	if idx.cap == 0 {
+		sort.Slice(idx.trios, func(i, j int) bool {
+			return idx.trios[i].newer(idx.trios[j])
+		})
+	}
+	return idx.trios
 }
 
 // DeleteInactiveOrders deletes orders that are no longer needed for normal
 // operations. Optionally accepts a time to delete orders with a later time
 // stamp. Accepts an optional function to perform on deleted orders.
-func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time,
+func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time, keepNewest int,
 	perOrderFn func(ords *dexdb.MetaOrder) error) (int, error) {
 	const batchSize = 1000
 	var olderThanB []byte
@@ -2130,7 +2344,10 @@ func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time
 	if err := db.View(func(tx *bbolt.Tx) error {
 		// Some archived orders may still be needed for active matches.
 		// Put those order id's in a map to prevent deletion.
-		amb := tx.Bucket(activeMatchesBucket)
+		amb, err := db.bucket(tx, activeMatchesBucket)
+		if err != nil {
+			return err
+		}
 		if err := amb.ForEach(func(k, _ []byte) error {
 			mBkt := amb.Bucket(k)
 			if mBkt == nil {
@@ -2150,20 +2367,26 @@ func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time
 	}
 
 	// Get the keys of every archived order.
-	var keys [][]byte
+	var keys, stamps [][]byte
 	if err := db.View(func(tx *bbolt.Tx) error {
-		archivedOB := tx.Bucket(archivedOrdersBucket)
-		if archivedOB == nil {
-			return fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
+		archivedOB, err := db.bucket(tx, archivedOrdersBucket)
+		if err != nil {
+			return err
 		}
 		archivedOB.ForEach(func(k, _ []byte) error {
 			keys = append(keys, bytes.Clone(k))
+			if keepNewest > 0 {
+				if oBkt := archivedOB.Bucket(k); oBkt != nil {
+					stamps = append(stamps, getCopy(oBkt, updateTimeKey))
+				}
+			}
 			return nil
 		})
 		return nil
 	}); err != nil {
 		return 0, fmt.Errorf("unable to get archived order keys: %v", err)
 	}
+	olderThanB = keepNewestCutoff(olderThanB, stamps, keepNewest)
 
 	nDeletedOrders := 0
 	start := time.Now()
@@ -2182,9 +2405,9 @@ func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time
 
 		nDeletedBatch := 0
 		err := db.Update(func(tx *bbolt.Tx) error {
-			archivedOB := tx.Bucket(archivedOrdersBucket)
-			if archivedOB == nil {
-				return fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
+			archivedOB, err := db.bucket(tx, archivedOrdersBucket)
+			if err != nil {
+				return err
 			}
 			for j := i; j < end; j++ {
 				key := keys[j]
@@ -2242,19 +2465,40 @@ func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time
 	return nDeletedOrders, nil
 }
 
+// keepNewestCutoff lowers the olderThanB time stamp of a deletion so that the
+// records with the newest keepNewest time stamps are kept.
+func keepNewestCutoff(olderThanB []byte, stamps [][]byte, keepNewest int) []byte {
+	if keepNewest <= 0 || len(stamps) <= keepNewest {
+		return olderThanB
+	}
+	// Newest first. Records without a time stamp sort last.
+	sort.Slice(stamps, func(i, j int) bool {
+		return bytes.Compare(stamps[i], stamps[j]) > 0
+	})
+	oldestKept := stamps[keepNewest-1]
+	if len(oldestKept) != 8 || intCoder.Uint64(oldestKept) == 0 {
+		return olderThanB
+	}
+	cutoff := uint64Bytes(intCoder.Uint64(oldestKept) - 1)
+	if bytes.Compare(cutoff, olderThanB) < 0 {
+		return cutoff
+	}
+	return olderThanB
+}
+
 // orderSide Returns whether the order was for buying or selling the asset.
-func orderSide(tx *bbolt.Tx, oid order.OrderID) (sell bool, err error) {
+func (db *BoltDB) orderSide(tx *bbolt.Tx, oid order.OrderID) (sell bool, err error) {
 	oidB := oid[:]
-	ob := tx.Bucket(activeOrdersBucket)
-	if ob == nil {
-		return false, fmt.Errorf("failed to open %s bucket", string(activeOrdersBucket))
+	ob, err := db.bucket(tx, activeOrdersBucket)
+	if err != nil {
+		return false, err
 	}
 	oBkt := ob.Bucket(oidB)
 	// If the order is not in the active bucket, check the archived bucket.
 	if oBkt == nil {
-		archivedOB := tx.Bucket(archivedOrdersBucket)
-		if archivedOB == nil {
-			return false, fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
+		archivedOB, err := db.bucket(tx, archivedOrdersBucket)
+		if err != nil {
+			return false, err
 		}
 		oBkt = archivedOB.Bucket(oidB)
 	}
@@ -2280,7 +2524,7 @@ func orderSide(tx *bbolt.Tx, oid order.OrderID) (sell bool, err error) {
 // DeleteInactiveMatches deletes matches that are no longer needed for normal
 // operations. Optionally accepts a time to delete matches with a later time
 // stamp. Accepts an optional function to perform on deleted matches.
-func (db *BoltDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Time,
+func (db *BoltDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Time, keepNewest int,
 	perMatchFn func(mtch *dexdb.MetaMatch, isSell bool) error) (int, error) {
 	const batchSize = 1000
 	var olderThanB []byte
@@ -2295,7 +2539,10 @@ func (db *BoltDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Tim
 		// Some archived matches still have active orders. Put those
 		// order id's in a map to prevent deletion just in case they
 		// are needed again.
-		aob := tx.Bucket(activeOrdersBucket)
+		aob, err := db.bucket(tx, activeOrdersBucket)
+		if err != nil {
+			return err
+		}
 		if err := aob.ForEach(func(k, _ []byte) error {
 			var oid order.OrderID
 			copy(oid[:], k)
@@ -2310,20 +2557,26 @@ func (db *BoltDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Tim
 	}
 
 	// Get the keys of every archived match.
-	var keys [][]byte
+	var keys, stamps [][]byte
 	if err := db.View(func(tx *bbolt.Tx) error {
-		archivedMB := tx.Bucket(archivedMatchesBucket)
-		if archivedMB == nil {
-			return fmt.Errorf("failed to open %s bucket", string(archivedMatchesBucket))
+		archivedMB, err := db.bucket(tx, archivedMatchesBucket)
+		if err != nil {
+			return err
 		}
 		archivedMB.ForEach(func(k, _ []byte) error {
 			keys = append(keys, bytes.Clone(k))
+			if keepNewest > 0 {
+				if mBkt := archivedMB.Bucket(k); mBkt != nil {
+					stamps = append(stamps, getCopy(mBkt, stampKey))
+				}
+			}
 			return nil
 		})
 		return nil
 	}); err != nil {
 		return 0, fmt.Errorf("unable to get archived match keys: %v", err)
 	}
+	olderThanB = keepNewestCutoff(olderThanB, stamps, keepNewest)
 
 	nDeletedMatches := 0
 	start := time.Now()
@@ -2342,9 +2595,9 @@ func (db *BoltDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Tim
 
 		nDeletedBatch := 0
 		if err := db.Update(func(tx *bbolt.Tx) error {
-			archivedMB := tx.Bucket(archivedMatchesBucket)
-			if archivedMB == nil {
-				return fmt.Errorf("failed to open %s bucket", string(archivedMatchesBucket))
+			archivedMB, err := db.bucket(tx, archivedMatchesBucket)
+			if err != nil {
+				return err
 			}
 			for j := i; j < end; j++ {
 				key := keys[j]
@@ -2374,7 +2627,7 @@ func (db *BoltDB) DeleteInactiveMatches(ctx context.Context, olderThan *time.Tim
 					return fmt.Errorf("failed to delete match bucket: %v", err)
 				}
 				if perMatchFn != nil {
-					isSell, err := orderSide(tx, m.OrderID)
+					isSell, err := db.orderSide(tx, m.OrderID)
 					if err != nil {
 						return fmt.Errorf("problem getting order side for order %v: %v", m.OrderID, err)
 					}
@@ -2459,11 +2712,176 @@ func (db *BoltDB) Language() (lang string, _ error) {
 	})
 }
 
+// SaveCustomToken stores a user-defined token.
+func (db *BoltDB) SaveCustomToken(tokenID uint32, ct *asset.CustomToken) error {
+	b, err := json.Marshal(ct)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.withBucket(customTokensBucket, db.Update, func(bkt *bucket) error {
+		return bkt.Put(encode.Uint32Bytes(tokenID), b)
+	})
+}
+
+// CustomTokens retrieves all tokens stored with SaveCustomToken.
+func (db *BoltDB) CustomTokens() (map[uint32]*asset.CustomToken, error) {
+	tokens := make(map[uint32]*asset.CustomToken)
+	return tokens, db.withBucket(customTokensBucket, db.View, func(bkt *bucket) error {
+		return bkt.ForEach(func(k, v []byte) error {
+			if len(k) != 4 {
+				return fmt.Errorf("invalid custom token key %x", k)
+			}
+			var ct asset.CustomToken
+			if err := json.Unmarshal(v, &ct); err != nil {
+				return fmt.Errorf("error decoding custom token %x: %w", k, err)
+			}
+			tokens[intCoder.Uint32(k)] = &ct
+			return nil
+		})
+	})
+}
+
+// SaveExplorerSettings stores the block explorer settings.
+func (db *BoltDB) SaveExplorerSettings(settings *dexdb.ExplorerSettings) error {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return fmt.Errorf("app bucket not found")
+		}
+		return bkt.Put(explorersKey, b)
+	})
+}
+
+// ExplorerSettings retrieves the settings stored with SaveExplorerSettings.
+func (db *BoltDB) ExplorerSettings() (*dexdb.ExplorerSettings, error) {
+	settings := new(dexdb.ExplorerSettings)
+	return settings, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return nil
+		}
+		b := bkt.Get(explorersKey)
+		if len(b) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(b, settings); err != nil {
+			return fmt.Errorf("error decoding explorer settings: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveTorSettings stores the tor settings.
+func (db *BoltDB) SaveTorSettings(settings *dexdb.TorSettings) error {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return fmt.Errorf("app bucket not found")
+		}
+		return bkt.Put(torKey, b)
+	})
+}
+
+// TorSettings retrieves the settings stored with SaveTorSettings.
+func (db *BoltDB) TorSettings() (*dexdb.TorSettings, error) {
+	settings := new(dexdb.TorSettings)
+	return settings, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return nil
+		}
+		b := bkt.Get(torKey)
+		if len(b) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(b, settings); err != nil {
+			return fmt.Errorf("error decoding tor settings: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveProxySettings stores the proxy settings.
+func (db *BoltDB) SaveProxySettings(settings *dexdb.ProxySettings) error {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return fmt.Errorf("app bucket not found")
+		}
+		return bkt.Put(proxyKey, b)
+	})
+}
+
+// ProxySettings retrieves the settings stored with SaveProxySettings.
+func (db *BoltDB) ProxySettings() (*dexdb.ProxySettings, error) {
+	settings := new(dexdb.ProxySettings)
+	return settings, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return nil
+		}
+		b := bkt.Get(proxyKey)
+		if len(b) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(b, settings); err != nil {
+			return fmt.Errorf("error decoding proxy settings: %w", err)
+		}
+		return nil
+	})
+}
+
+// SaveRetentionSettings stores the record retention settings.
+func (db *BoltDB) SaveRetentionSettings(settings *dexdb.RetentionSettings) error {
+	b, err := json.Marshal(settings)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.Update(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return fmt.Errorf("app bucket not found")
+		}
+		return bkt.Put(retentionKey, b)
+	})
+}
+
+// RetentionSettings retrieves the settings stored with SaveRetentionSettings.
+func (db *BoltDB) RetentionSettings() (*dexdb.RetentionSettings, error) {
+	settings := new(dexdb.RetentionSettings)
+	return settings, db.View(func(dbTx *bbolt.Tx) error {
+		bkt := dbTx.Bucket(appBucket)
+		if bkt == nil {
+			return nil
+		}
+		b := bkt.Get(retentionKey)
+		if len(b) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(b, settings); err != nil {
+			return fmt.Errorf("error decoding retention settings: %w", err)
+		}
+		return nil
+	})
+}
+
 // timeNow is the current unix timestamp in milliseconds.
 func timeNow() uint64 {
 	return uint64(time.Now().UnixMilli())
 }
 
 // A couple of common bbolt functions.
-type bucketFunc func(*bbolt.Bucket) error
+type bucketFunc func(*bucket) error
 type txFunc func(func(*bbolt.Tx) error) error
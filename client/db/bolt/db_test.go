@@ -8,13 +8,16 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/db"
 	dbtest "decred.org/dcrdex/client/db/test"
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encrypt"
 	"decred.org/dcrdex/dex/order"
 	ordertest "decred.org/dcrdex/dex/order/test"
 	"go.etcd.io/bbolt"
@@ -24,7 +27,7 @@ var (
 	tLogger = dex.StdOutLogger("db_TEST", dex.LevelTrace)
 )
 
-func newTestDB(t *testing.T) (*BoltDB, func()) {
+func newTestDB(t testing.TB) (*BoltDB, func()) {
 	t.Helper()
 	dbPath := filepath.Join(t.TempDir(), "db.db")
 	dbi, err := NewDB(dbPath, tLogger)
@@ -672,7 +675,7 @@ func TestOrderFilters(t *testing.T) {
 		}
 
 		// Set the update time.
-		boltdb.ordersUpdate(func(aob, eob *bbolt.Bucket) error {
+		boltdb.ordersUpdate(func(aob, eob *bucket) error {
 			oBkt := aob.Bucket(oid[:])
 			if oBkt == nil {
 				oBkt = eob.Bucket(oid[:])
@@ -770,6 +773,23 @@ func TestOrderFilters(t *testing.T) {
 			},
 			expected: []int{4, 3},
 		},
+		{
+			name: "since",
+			filter: &db.OrderFilter{
+				N:     orderCount,
+				Since: uint64(start + 3),
+			},
+			expected: []int{4, 5, 3},
+		},
+		{
+			name: "since & until",
+			filter: &db.OrderFilter{
+				N:     orderCount,
+				Since: uint64(start + 1),
+				Until: uint64(start + 4),
+			},
+			expected: []int{3, 2, 1},
+		},
 	}
 
 	for _, test := range tests {
@@ -1303,7 +1323,7 @@ func TestDeleteInactiveMatches(t *testing.T) {
 			return nil
 		}
 	)
-	nMatchesDeleted, err := boltdb.DeleteInactiveMatches(ctx, &olderThan, perMatchFn)
+	nMatchesDeleted, err := boltdb.DeleteInactiveMatches(ctx, &olderThan, 0, perMatchFn)
 	if err != nil {
 		t.Fatalf("unable to delete inactive matches: %v", err)
 	}
@@ -1472,7 +1492,7 @@ func TestDeleteInactiveOrders(t *testing.T) {
 			return nil
 		}
 	)
-	nOrdersDeleted, err := boltdb.DeleteInactiveOrders(ctx, &olderThan, perOrderFn)
+	nOrdersDeleted, err := boltdb.DeleteInactiveOrders(ctx, &olderThan, 0, perOrderFn)
 	if err != nil {
 		t.Fatalf("unable to delete inactive matches: %v", err)
 	}
@@ -1563,7 +1583,7 @@ func TestOrderSide(t *testing.T) {
 
 	if err := boltdb.View(func(tx *bbolt.Tx) error {
 		for _, ord := range orders {
-			side, err := orderSide(tx, ord.Order.ID())
+			side, err := boltdb.orderSide(tx, ord.Order.ID())
 			if err != nil {
 				return err
 			}
@@ -1613,3 +1633,357 @@ func TestPokes(t *testing.T) {
 		t.Fatal("Result from second LoadPokes wasn't empty")
 	}
 }
+
+func TestCustomTokens(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	tokens, err := boltdb.CustomTokens()
+	if err != nil {
+		t.Fatalf("CustomTokens error: %v", err)
+	}
+	if len(tokens) != 0 {
+		t.Fatalf("expected no tokens, got %d", len(tokens))
+	}
+
+	ct := &asset.CustomToken{
+		ParentID: 60,
+		Address:  "0x0000000000000000000000000000000000000001",
+		Symbol:   "abc",
+		Name:     "ABC",
+		Decimals: 18,
+	}
+	const tokenID = 0x80000001
+	if err := boltdb.SaveCustomToken(tokenID, ct); err != nil {
+		t.Fatalf("SaveCustomToken error: %v", err)
+	}
+	tokens, err = boltdb.CustomTokens()
+	if err != nil {
+		t.Fatalf("CustomTokens error: %v", err)
+	}
+	if len(tokens) != 1 || !reflect.DeepEqual(tokens[tokenID], ct) {
+		t.Fatalf("wrong tokens %+v", tokens)
+	}
+}
+
+func TestExplorerSettings(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	settings, err := boltdb.ExplorerSettings()
+	if err != nil {
+		t.Fatalf("ExplorerSettings error: %v", err)
+	}
+	if settings.PreferOnion || len(settings.Assets) != 0 {
+		t.Fatalf("expected empty settings, got %+v", settings)
+	}
+
+	settings = &db.ExplorerSettings{
+		PreferOnion: true,
+		Assets: map[uint32]*db.AssetExplorers{
+			42: {
+				Selected: "mine",
+				Custom: []*db.BlockExplorer{{
+					Name:  "mine",
+					TxURL: "http://127.0.0.1:7777/tx/{txid}",
+				}},
+			},
+		},
+	}
+	if err := boltdb.SaveExplorerSettings(settings); err != nil {
+		t.Fatalf("SaveExplorerSettings error: %v", err)
+	}
+	reloaded, err := boltdb.ExplorerSettings()
+	if err != nil {
+		t.Fatalf("ExplorerSettings error: %v", err)
+	}
+	if !reloaded.PreferOnion || reloaded.Assets[42] == nil || reloaded.Assets[42].Selected != "mine" ||
+		len(reloaded.Assets[42].Custom) != 1 || *reloaded.Assets[42].Custom[0] != *settings.Assets[42].Custom[0] {
+		t.Fatalf("wrong settings %+v", reloaded)
+	}
+}
+
+func TestTorSettings(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	settings, err := boltdb.TorSettings()
+	if err != nil {
+		t.Fatalf("TorSettings error: %v", err)
+	}
+	if *settings != (db.TorSettings{}) {
+		t.Fatalf("expected empty settings, got %+v", settings)
+	}
+
+	settings = &db.TorSettings{Enabled: true, SOCKSAddr: "127.0.0.1:9050"}
+	if err := boltdb.SaveTorSettings(settings); err != nil {
+		t.Fatalf("SaveTorSettings error: %v", err)
+	}
+	reloaded, err := boltdb.TorSettings()
+	if err != nil {
+		t.Fatalf("TorSettings error: %v", err)
+	}
+	if *reloaded != *settings {
+		t.Fatalf("wrong settings %+v", reloaded)
+	}
+}
+
+func TestProxySettings(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	settings, err := boltdb.ProxySettings()
+	if err != nil {
+		t.Fatalf("ProxySettings error: %v", err)
+	}
+	if len(settings.Hosts) != 0 || len(settings.Services) != 0 {
+		t.Fatalf("expected empty settings, got %+v", settings)
+	}
+
+	settings = &db.ProxySettings{
+		Hosts: map[string]*db.ProxyConfig{
+			"dex.example.com:7232":   {SOCKSAddr: "127.0.0.1:1080"},
+			"other.example.com:7232": {},
+		},
+		Services: map[string]*db.ProxyConfig{
+			db.ProxyServiceFiatRates: {SOCKSAddr: "127.0.0.1:9050"},
+		},
+	}
+	if err := boltdb.SaveProxySettings(settings); err != nil {
+		t.Fatalf("SaveProxySettings error: %v", err)
+	}
+	reloaded, err := boltdb.ProxySettings()
+	if err != nil {
+		t.Fatalf("ProxySettings error: %v", err)
+	}
+	if !reflect.DeepEqual(reloaded, settings) {
+		t.Fatalf("wrong settings %+v", reloaded)
+	}
+}
+
+func TestEncryption(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	dbPath := boltdb.Path()
+
+	if err := boltdb.SetPrimaryCredentials(dbtest.RandomPrimaryCredentials()); err != nil {
+		t.Fatalf("SetPrimaryCredentials error: %v", err)
+	}
+	acct := dbtest.RandomAccountInfo()
+	if err := boltdb.CreateAccount(acct); err != nil {
+		t.Fatalf("CreateAccount error: %v", err)
+	}
+	w := dbtest.RandomWallet()
+	if err := boltdb.UpdateWallet(w); err != nil {
+		t.Fatalf("UpdateWallet error: %v", err)
+	}
+	ord, _ := ordertest.RandomLimitOrder()
+	mord := &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status: order.OrderStatusBooked,
+			Host:   acct.Host,
+			Proof:  db.OrderProof{DEXSig: randBytes(73)},
+		},
+		Order: ord,
+	}
+	if err := boltdb.UpdateOrder(mord); err != nil {
+		t.Fatalf("UpdateOrder error: %v", err)
+	}
+
+	if boltdb.Locked() {
+		t.Fatalf("unencrypted database is locked")
+	}
+	crypter := encrypt.NewCrypter([]byte("abc"))
+	if err := boltdb.Unlock(crypter); err != nil {
+		t.Fatalf("error encrypting database: %v", err)
+	}
+
+	// The values are encrypted and the account is keyed by a hash of the host.
+	if err := boltdb.View(func(tx *bbolt.Tx) error {
+		accts := tx.Bucket(accountsBucket)
+		if accts.Bucket([]byte(acct.Host)) != nil {
+			return errors.New("account found by host")
+		}
+		acctBkt := accts.Bucket(boltdb.vc.hash([]byte(acct.Host)))
+		if acctBkt == nil {
+			return errors.New("account not found by host hash")
+		}
+		if bytes.Equal(acctBkt.Get(accountKey), acct.Encode()) {
+			return errors.New("account not encrypted")
+		}
+		wBkt := tx.Bucket(walletsBucket).Bucket(w.ID())
+		if wBkt == nil {
+			return errors.New("wallet not found")
+		}
+		if bytes.Equal(wBkt.Get(walletKey), w.Encode()) {
+			return errors.New("wallet not encrypted")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkData := func() {
+		t.Helper()
+		hosts, err := boltdb.ListAccounts()
+		if err != nil {
+			t.Fatalf("ListAccounts error: %v", err)
+		}
+		if len(hosts) != 1 || hosts[0] != acct.Host {
+			t.Fatalf("wrong hosts %v", hosts)
+		}
+		reAcct, err := boltdb.Account(acct.Host)
+		if err != nil {
+			t.Fatalf("Account error: %v", err)
+		}
+		dbtest.MustCompareAccountInfo(t, reAcct, acct)
+		reW, err := boltdb.Wallet(w.ID())
+		if err != nil {
+			t.Fatalf("Wallet error: %v", err)
+		}
+		dbtest.MustCompareWallets(t, reW, w)
+		reOrd, err := boltdb.Order(ord.ID())
+		if err != nil {
+			t.Fatalf("Order error: %v", err)
+		}
+		ordertest.MustCompareOrders(t, reOrd.Order, ord)
+	}
+	checkData()
+
+	// Reopened, the database is locked until unlocked with the same crypter.
+	shutdown()
+	dbi, err := NewDB(dbPath, tLogger)
+	if err != nil {
+		t.Fatalf("error reopening database: %v", err)
+	}
+	boltdb = dbi.(*BoltDB)
+	defer boltdb.Close()
+	if !boltdb.Locked() {
+		t.Fatalf("encrypted database not locked")
+	}
+	if _, err := boltdb.Accounts(); !errors.Is(err, db.ErrLocked) {
+		t.Fatalf("expected ErrLocked, got %v", err)
+	}
+	if err := boltdb.Unlock(encrypt.NewCrypter([]byte("abc"))); err == nil {
+		t.Fatalf("no error unlocking with the wrong crypter")
+	}
+	if err := boltdb.Unlock(crypter); err != nil {
+		t.Fatalf("Unlock error: %v", err)
+	}
+	checkData()
+}
+
+func TestDecryptionError(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	if err := boltdb.SetPrimaryCredentials(dbtest.RandomPrimaryCredentials()); err != nil {
+		t.Fatalf("SetPrimaryCredentials error: %v", err)
+	}
+	w := dbtest.RandomWallet()
+	if err := boltdb.UpdateWallet(w); err != nil {
+		t.Fatalf("UpdateWallet error: %v", err)
+	}
+	if err := boltdb.Unlock(encrypt.NewCrypter([]byte("abc"))); err != nil {
+		t.Fatalf("error encrypting database: %v", err)
+	}
+
+	// Corrupt the wallet's balance, which is optional, so that makeWallet
+	// would not notice the missing value.
+	if err := boltdb.DB.Update(func(tx *bbolt.Tx) error {
+		wBkt := tx.Bucket(walletsBucket).Bucket(w.ID())
+		encBal := bytes.Clone(wBkt.Get(balanceKey))
+		encBal[len(encBal)-1] ^= 0xff
+		return wBkt.Put(balanceKey, encBal)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := boltdb.Wallet(w.ID()); err == nil {
+		t.Fatal("no error for a wallet that can't be decrypted")
+	}
+	if _, err := boltdb.Wallets(); err == nil {
+		t.Fatal("no error listing a wallet that can't be decrypted")
+	}
+}
+
+func TestDeleteNotifications(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	// Ten notifications, one per second.
+	start := time.Now().Add(-time.Hour)
+	notes := make([]*db.Notification, 10)
+	for i := range notes {
+		notes[i] = dbtest.RandomNotification(1)
+		notes[i].TimeStamp = uint64(start.Add(time.Duration(i) * time.Second).UnixMilli())
+		if err := boltdb.SaveNotification(notes[i]); err != nil {
+			t.Fatalf("SaveNotification error: %v", err)
+		}
+	}
+	remaining := func() []*db.Notification {
+		t.Helper()
+		notes, err := boltdb.NotificationsN(100)
+		if err != nil {
+			t.Fatalf("NotificationsN error: %v", err)
+		}
+		return notes
+	}
+
+	// Older than the 3rd.
+	olderThan := start.Add(2500 * time.Millisecond)
+	n, err := boltdb.DeleteNotifications(&olderThan, 0)
+	if err != nil {
+		t.Fatalf("DeleteNotifications error: %v", err)
+	}
+	if n != 3 || len(remaining()) != 7 {
+		t.Fatalf("expected 3 deleted, got %d", n)
+	}
+
+	// Keep the newest 5, including newer than olderThan.
+	n, err = boltdb.DeleteNotifications(nil, 5)
+	if err != nil {
+		t.Fatalf("DeleteNotifications error: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 deleted, got %d", n)
+	}
+	left := remaining()
+	if len(left) != 5 || !bytes.Equal(left[0].ID(), notes[9].ID()) || !bytes.Equal(left[4].ID(), notes[5].ID()) {
+		t.Fatalf("wrong notifications kept")
+	}
+
+	// The age limit applies with more than keepNewest left.
+	olderThan = start.Add(7500 * time.Millisecond)
+	n, err = boltdb.DeleteNotifications(&olderThan, 2)
+	if err != nil {
+		t.Fatalf("DeleteNotifications error: %v", err)
+	}
+	if n != 3 || len(remaining()) != 2 {
+		t.Fatalf("expected 3 deleted, got %d", n)
+	}
+}
+
+func TestRetentionSettings(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	settings, err := boltdb.RetentionSettings()
+	if err != nil {
+		t.Fatalf("RetentionSettings error: %v", err)
+	}
+	if settings.Enabled() {
+		t.Fatalf("expected no limits, got %+v", settings)
+	}
+
+	settings = &db.RetentionSettings{MaxAgeDays: 90, MaxNotifications: 100, Archive: true}
+	if err := boltdb.SaveRetentionSettings(settings); err != nil {
+		t.Fatalf("SaveRetentionSettings error: %v", err)
+	}
+	reloaded, err := boltdb.RetentionSettings()
+	if err != nil {
+		t.Fatalf("RetentionSettings error: %v", err)
+	}
+	if *reloaded != *settings {
+		t.Fatalf("wrong settings %+v", reloaded)
+	}
+}
@@ -0,0 +1,135 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"bytes"
+	"fmt"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"go.etcd.io/bbolt"
+)
+
+// The fiat rates bucket has the sampled fiat exchange rates of the assets. The
+// key is the asset ID followed by the time stamp, so the rates of an asset are
+// sorted by time. The rates are public information and are recorded whether or
+// not the app is logged in, so the bucket is not encrypted.
+
+// fiatRateKey is the key of an asset's fiat rate at the time stamp.
+func fiatRateKey(assetID uint32, stamp uint64) []byte {
+	return append(uint32Bytes(assetID), uint64Bytes(stamp)...)
+}
+
+// parseFiatRateKey parses the asset ID and time stamp from a fiat rate key.
+func parseFiatRateKey(k []byte) (assetID uint32, stamp uint64, ok bool) {
+	if len(k) != 12 {
+		return 0, 0, false
+	}
+	return intCoder.Uint32(k[:4]), intCoder.Uint64(k[4:]), true
+}
+
+// SaveFiatRates records the fiat exchange rates of the assets at the time
+// stamp, in milliseconds.
+func (db *BoltDB) SaveFiatRates(stamp uint64, rates map[uint32]float64) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(fiatRatesBucket)
+		if bkt == nil {
+			return fmt.Errorf("failed to open %s bucket", string(fiatRatesBucket))
+		}
+		for assetID, rate := range rates {
+			r := &dexdb.FiatRate{Stamp: stamp, Rate: rate}
+			if err := bkt.Put(fiatRateKey(assetID, stamp), r.Encode()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FiatRateHistory returns the fiat rates recorded for the asset in the time
+// range, oldest first. Since is inclusive, and until is exclusive. Zero until
+// means no limit.
+func (db *BoltDB) FiatRateHistory(assetID uint32, since, until uint64) ([]*dexdb.FiatRate, error) {
+	var rates []*dexdb.FiatRate
+	err := db.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(fiatRatesBucket)
+		if bkt == nil {
+			return fmt.Errorf("failed to open %s bucket", string(fiatRatesBucket))
+		}
+		prefix := uint32Bytes(assetID)
+		c := bkt.Cursor()
+		for k, v := c.Seek(fiatRateKey(assetID, since)); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			_, stamp, ok := parseFiatRateKey(k)
+			if !ok {
+				continue
+			}
+			if until > 0 && stamp >= until {
+				break
+			}
+			r, err := dexdb.DecodeFiatRate(stamp, v)
+			if err != nil {
+				return fmt.Errorf("error decoding fiat rate: %w", err)
+			}
+			rates = append(rates, r)
+		}
+		return nil
+	})
+	return rates, err
+}
+
+// FiatRateAt returns the fiat rate recorded for the asset nearest to the time
+// stamp. dexdb.ErrNoFiatRate is returned if there are no recorded rates for
+// the asset.
+func (db *BoltDB) FiatRateAt(assetID uint32, stamp uint64) (*dexdb.FiatRate, error) {
+	var rate *dexdb.FiatRate
+	err := db.View(func(tx *bbolt.Tx) error {
+		bkt := tx.Bucket(fiatRatesBucket)
+		if bkt == nil {
+			return fmt.Errorf("failed to open %s bucket", string(fiatRatesBucket))
+		}
+		// The nearest rate is either the first rate at or after the stamp, or
+		// the one before it.
+		c := bkt.Cursor()
+		consider := func(k, v []byte) error {
+			kAssetID, rStamp, ok := parseFiatRateKey(k)
+			if !ok || kAssetID != assetID {
+				return nil
+			}
+			if rate != nil && absDiff(rStamp, stamp) >= absDiff(rate.Stamp, stamp) {
+				return nil
+			}
+			r, err := dexdb.DecodeFiatRate(rStamp, v)
+			if err != nil {
+				return fmt.Errorf("error decoding fiat rate: %w", err)
+			}
+			rate = r
+			return nil
+		}
+		k, v := c.Seek(fiatRateKey(assetID, stamp))
+		if err := consider(k, v); err != nil {
+			return err
+		}
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		if err := consider(k, v); err != nil {
+			return err
+		}
+		if rate == nil {
+			return dexdb.ErrNoFiatRate
+		}
+		return nil
+	})
+	return rate, err
+}
+
+// absDiff is the absolute difference of two time stamps.
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
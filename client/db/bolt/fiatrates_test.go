@@ -0,0 +1,87 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"errors"
+	"testing"
+
+	"decred.org/dcrdex/client/db"
+)
+
+func TestFiatRates(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	if _, err := boltdb.FiatRateAt(42, 1000); !errors.Is(err, db.ErrNoFiatRate) {
+		t.Fatalf("expected ErrNoFiatRate, got %v", err)
+	}
+
+	// Assets 0 and 60 are on either side of 42 in the key space, so the queries
+	// must not cross assets.
+	for i, stamp := range []uint64{1000, 2000, 3000} {
+		rates := map[uint32]float64{
+			0:  50_000 + float64(i),
+			42: 20 + float64(i),
+			60: 3_000 + float64(i),
+		}
+		if err := boltdb.SaveFiatRates(stamp, rates); err != nil {
+			t.Fatalf("SaveFiatRates error: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		since, until uint64
+		expStamps    []uint64
+	}{
+		{"all", 0, 0, []uint64{1000, 2000, 3000}},
+		{"since", 2000, 0, []uint64{2000, 3000}},
+		{"until exclusive", 0, 3000, []uint64{1000, 2000}},
+		{"range", 1500, 2500, []uint64{2000}},
+		{"none", 3001, 0, nil},
+	}
+	for _, test := range tests {
+		rates, err := boltdb.FiatRateHistory(42, test.since, test.until)
+		if err != nil {
+			t.Fatalf("%s: FiatRateHistory error: %v", test.name, err)
+		}
+		if len(rates) != len(test.expStamps) {
+			t.Fatalf("%s: expected %d rates, got %d", test.name, len(test.expStamps), len(rates))
+		}
+		for i, r := range rates {
+			if r.Stamp != test.expStamps[i] {
+				t.Fatalf("%s: expected stamp %d, got %d", test.name, test.expStamps[i], r.Stamp)
+			}
+			if exp := 20 + float64(r.Stamp/1000-1); r.Rate != exp {
+				t.Fatalf("%s: expected rate %f, got %f", test.name, exp, r.Rate)
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		stamp    uint64
+		expStamp uint64
+	}{
+		{0, 1000},
+		{1000, 1000},
+		{1400, 1000},
+		{1600, 2000},
+		{2900, 3000},
+		{10_000, 3000},
+	} {
+		for _, assetID := range []uint32{0, 42, 60} {
+			r, err := boltdb.FiatRateAt(assetID, test.stamp)
+			if err != nil {
+				t.Fatalf("FiatRateAt(%d, %d) error: %v", assetID, test.stamp, err)
+			}
+			if r.Stamp != test.expStamp {
+				t.Fatalf("FiatRateAt(%d, %d): expected stamp %d, got %d", assetID, test.stamp, test.expStamp, r.Stamp)
+			}
+		}
+	}
+	if _, err := boltdb.FiatRateAt(2, 1000); !errors.Is(err, db.ErrNoFiatRate) {
+		t.Fatalf("expected ErrNoFiatRate for unrecorded asset, got %v", err)
+	}
+}
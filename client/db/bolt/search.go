@@ -0,0 +1,416 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"go.etcd.io/bbolt"
+)
+
+// The search index is a bucket with a key for every search term of every
+// indexed record. The key is the hash of the term followed by a record
+// reference, which is a record type byte and the record's key in its bucket.
+// The value is the record's time stamp. Records that are deleted are removed
+// from the index when they are found by a search. The index is built from the
+// existing records by the first search.
+const (
+	refNote  byte = 'n'
+	refOrder byte = 'o'
+	refMatch byte = 'm'
+	// minTermLen is the length of the shortest search term. Shorter words
+	// are not indexed.
+	minTermLen = 3
+)
+
+// searchTerms splits the texts into the lowercase words and numbers that are
+// indexed.
+func searchTerms(texts ...string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		for _, term := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len(term) < minTermLen || seen[term] {
+				continue
+			}
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// termKey is the prefix of the search index keys of a term.
+func termKey(idx *bucket, term string) []byte {
+	k := []byte("search:" + term)
+	if idx.vc == nil {
+		h := sha256.Sum256(k)
+		return h[:]
+	}
+	return idx.vc.hash(k)
+}
+
+// coinTerms are the search terms of a coin ID, which is indexed as hex and as
+// its string form for each of the assets.
+func (db *BoltDB) coinTerms(coinID []byte, assetIDs ...uint32) []string {
+	if len(coinID) == 0 {
+		return nil
+	}
+	texts := []string{hex.EncodeToString(coinID)}
+	if db.opts.DecodeCoinID != nil {
+		for _, assetID := range assetIDs {
+			if s, err := db.opts.DecodeCoinID(assetID, coinID); err == nil {
+				texts = append(texts, s)
+			}
+		}
+	}
+	return searchTerms(texts...)
+}
+
+// orderTerms are the search terms of a trade: the order ID, the DEX host, the
+// redeem address, and the funding and change coins. Cancel orders are not
+// indexed.
+func (db *BoltDB) orderTerms(mord *dexdb.MetaOrder) []string {
+	trade := mord.Order.Trade()
+	if trade == nil {
+		return nil
+	}
+	fromID := mord.Order.Quote()
+	if trade.Sell {
+		fromID = mord.Order.Base()
+	}
+	terms := searchTerms(mord.Order.ID().String(), mord.MetaData.Host, trade.Address)
+	for _, coinID := range trade.Coins {
+		terms = append(terms, db.coinTerms(coinID, fromID)...)
+	}
+	return append(terms, db.coinTerms(mord.MetaData.ChangeCoin, fromID)...)
+}
+
+// matchTerms are the search terms of a match: the match ID, the counterparty's
+// address, and the swap, redeem and refund coins. Cancel matches are not
+// indexed.
+func (db *BoltDB) matchTerms(m *dexdb.MetaMatch) []string {
+	if m.Address == "" {
+		return nil
+	}
+	md := m.MetaData
+	terms := searchTerms(m.MatchID.String(), m.Address)
+	for _, coinID := range [][]byte{md.Proof.MakerSwap, md.Proof.TakerSwap,
+		md.Proof.MakerRedeem, md.Proof.TakerRedeem, md.Proof.RefundCoin} {
+		// The asset of each coin depends on the side of the match.
+		terms = append(terms, db.coinTerms(coinID, md.Base, md.Quote)...)
+	}
+	return terms
+}
+
+// putTerms adds a record's search terms to the index.
+func putTerms(idx *bucket, ref []byte, stamp uint64, terms []string) error {
+	stampB := uint64Bytes(stamp)
+	for _, term := range terms {
+		if err := idx.Put(append(termKey(idx, term), ref...), stampB); err != nil {
+			return fmt.Errorf("error indexing search term: %w", err)
+		}
+	}
+	return nil
+}
+
+// searchIndexed is true if the search index has been built.
+func searchIndexed(tx *bbolt.Tx) bool {
+	return tx.Bucket(appBucket).Get(searchIndexedKey) != nil
+}
+
+// indexRecord adds a record's search terms to the index. Nothing is done if
+// the index has not been built yet.
+func (db *BoltDB) indexRecord(tx *bbolt.Tx, ref []byte, stamp uint64, terms []string) error {
+	if len(terms) == 0 || !searchIndexed(tx) {
+		return nil
+	}
+	idx, err := db.bucket(tx, searchIndexBucket)
+	if err != nil {
+		return err
+	}
+	return putTerms(idx, ref, stamp, terms)
+}
+
+// indexNote adds a notification to the search index.
+func (db *BoltDB) indexNote(tx *bbolt.Tx, id []byte, note *dexdb.Notification) error {
+	return db.indexRecord(tx, append([]byte{refNote}, id...), note.TimeStamp, searchTerms(note.SubjectText, note.DetailText))
+}
+
+// indexOrder adds an order to the search index.
+func (db *BoltDB) indexOrder(tx *bbolt.Tx, mord *dexdb.MetaOrder) error {
+	oid := mord.Order.ID()
+	return db.indexRecord(tx, append([]byte{refOrder}, oid[:]...), uint64(mord.Order.Time()), db.orderTerms(mord))
+}
+
+// indexMatch adds a match to the search index.
+func (db *BoltDB) indexMatch(tx *bbolt.Tx, m *dexdb.MetaMatch) error {
+	return db.indexRecord(tx, append([]byte{refMatch}, m.MatchOrderUniqueID()...), m.MetaData.Stamp, db.matchTerms(m))
+}
+
+// resetSearchIndex empties the search index, which is built again by the next
+// search.
+func resetSearchIndex(tx *bbolt.Tx) error {
+	if err := tx.DeleteBucket(searchIndexBucket); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+		return err
+	}
+	if _, err := tx.CreateBucket(searchIndexBucket); err != nil {
+		return err
+	}
+	return tx.Bucket(appBucket).Delete(searchIndexedKey)
+}
+
+// buildSearchIndex indexes all of the notifications, orders and matches.
+// Records that cannot be decoded are skipped.
+func (db *BoltDB) buildSearchIndex(tx *bbolt.Tx) error {
+	idx, err := db.bucket(tx, searchIndexBucket)
+	if err != nil {
+		return err
+	}
+	buckets := make(map[string]*bucket)
+	for _, name := range [][]byte{notesBucket, activeOrdersBucket, archivedOrdersBucket,
+		activeMatchesBucket, archivedMatchesBucket} {
+		if buckets[string(name)], err = db.bucket(tx, name); err != nil {
+			return err
+		}
+	}
+	var nIndexed int
+	index := func(ref []byte, stamp uint64, terms []string) error {
+		nIndexed++
+		return putTerms(idx, ref, stamp, terms)
+	}
+
+	notes := buckets[string(notesBucket)]
+	if err := notes.ForEach(func(k, _ []byte) error {
+		noteBkt := notes.Bucket(k)
+		if noteBkt == nil {
+			return nil
+		}
+		note, err := dexdb.DecodeNotification(getCopy(noteBkt, noteKey))
+		if err != nil {
+			db.log.Warnf("Not indexing notification %x: %v", k, err)
+			return nil
+		}
+		return index(append([]byte{refNote}, k...), note.TimeStamp, searchTerms(note.SubjectText, note.DetailText))
+	}); err != nil {
+		return err
+	}
+
+	for _, name := range [][]byte{activeOrdersBucket, archivedOrdersBucket} {
+		master := buckets[string(name)]
+		if err := master.ForEach(func(k, _ []byte) error {
+			oBkt := master.Bucket(k)
+			if oBkt == nil {
+				return nil
+			}
+			mord, err := decodeOrderBucket(k, oBkt)
+			if err != nil {
+				db.log.Warnf("Not indexing order %x: %v", k, err)
+				return nil
+			}
+			return index(append([]byte{refOrder}, k...), uint64(mord.Order.Time()), db.orderTerms(mord))
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range [][]byte{activeMatchesBucket, archivedMatchesBucket} {
+		master := buckets[string(name)]
+		if err := master.ForEach(func(k, _ []byte) error {
+			mBkt := master.Bucket(k)
+			if mBkt == nil {
+				return nil
+			}
+			m, err := loadMatchBucket(mBkt, false)
+			if err != nil {
+				db.log.Warnf("Not indexing match %x: %v", k, err)
+				return nil
+			}
+			return index(append([]byte{refMatch}, k...), m.MetaData.Stamp, db.matchTerms(m))
+		}); err != nil {
+			return err
+		}
+	}
+
+	db.log.Infof("Built the search index of %d records", nIndexed)
+	return tx.Bucket(appBucket).Put(searchIndexedKey, byteTrue)
+}
+
+// Search finds the notifications, orders and matches with all of the search
+// terms, newest first. The search index is built by the first search.
+func (db *BoltDB) Search(filter *dexdb.SearchFilter) ([]*dexdb.SearchResult, error) {
+	terms := searchTerms(filter.Query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("no search terms of at least %d characters", minTermLen)
+	}
+	// The index is read in a read-only transaction. A write transaction is
+	// only needed to build the index, and to remove deleted records from it.
+	var results []*dexdb.SearchResult
+	var dangling [][]byte
+	var indexed bool
+	err := db.View(func(tx *bbolt.Tx) (err error) {
+		if indexed = searchIndexed(tx); indexed {
+			results, dangling, err = db.searchIndex(tx, filter, terms)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !indexed {
+		err = db.Update(func(tx *bbolt.Tx) (err error) {
+			if !searchIndexed(tx) {
+				if err := db.buildSearchIndex(tx); err != nil {
+					return fmt.Errorf("error building search index: %w", err)
+				}
+			}
+			results, dangling, err = db.searchIndex(tx, filter, terms)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(dangling) == 0 {
+		return results, nil
+	}
+	return results, db.Update(func(tx *bbolt.Tx) error {
+		idx, err := db.bucket(tx, searchIndexBucket)
+		if err != nil {
+			return err
+		}
+		for _, ref := range dangling {
+			for _, term := range terms {
+				if err := idx.Delete(append(termKey(idx, term), ref...)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// searchIndex finds the records with all of the terms, newest first. The
+// references of records that were deleted are returned as dangling.
+func (db *BoltDB) searchIndex(tx *bbolt.Tx, filter *dexdb.SearchFilter, terms []string) (results []*dexdb.SearchResult, dangling [][]byte, _ error) {
+	idx, err := db.bucket(tx, searchIndexBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Find the records with the first term, and then drop the ones
+	// without the other terms.
+	stamps := make(map[string]uint64)
+	prefix := termKey(idx, terms[0])
+	c := idx.Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		if len(v) != 8 {
+			continue
+		}
+		stamp := intCoder.Uint64(v)
+		if (filter.Since > 0 && stamp < filter.Since) || (filter.Until > 0 && stamp >= filter.Until) {
+			continue
+		}
+		stamps[string(k[len(prefix):])] = stamp
+	}
+	for _, term := range terms[1:] {
+		k := termKey(idx, term)
+		for ref := range stamps {
+			if idx.Get(append(k, ref...)) == nil {
+				delete(stamps, ref)
+			}
+		}
+	}
+
+	refs := make([]string, 0, len(stamps))
+	for ref := range stamps {
+		refs = append(refs, ref)
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return stamps[refs[i]] > stamps[refs[j]]
+	})
+	for _, ref := range refs {
+		if filter.N > 0 && len(results) >= filter.N {
+			break
+		}
+		res, err := db.searchResult(tx, []byte(ref))
+		if err != nil {
+			return nil, nil, err
+		}
+		if res != nil {
+			results = append(results, res)
+			continue
+		}
+		// The record was deleted.
+		dangling = append(dangling, []byte(ref))
+	}
+	return results, dangling, nil
+}
+
+// searchResult loads the record of a search index reference. The result is
+// nil if the record does not exist.
+func (db *BoltDB) searchResult(tx *bbolt.Tx, ref []byte) (*dexdb.SearchResult, error) {
+	if len(ref) == 0 {
+		return nil, nil
+	}
+	// findBucket finds the record's bucket in either of the master buckets.
+	findBucket := func(k []byte, names ...[]byte) (*bucket, error) {
+		for _, name := range names {
+			master, err := db.bucket(tx, name)
+			if err != nil {
+				return nil, err
+			}
+			if bkt := master.Bucket(k); bkt != nil {
+				return bkt, nil
+			}
+		}
+		return nil, nil
+	}
+	k := ref[1:]
+	switch ref[0] {
+	case refNote:
+		noteBkt, err := findBucket(k, notesBucket)
+		if noteBkt == nil {
+			return nil, err
+		}
+		note, err := dexdb.DecodeNotification(getCopy(noteBkt, noteKey))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding notification %x: %w", k, err)
+		}
+		note.Ack = bEqual(noteBkt.Get(ackKey), byteTrue)
+		note.Id = k
+		return &dexdb.SearchResult{Notification: note}, nil
+	case refOrder:
+		oBkt, err := findBucket(k, activeOrdersBucket, archivedOrdersBucket)
+		if oBkt == nil {
+			return nil, err
+		}
+		mord, err := decodeOrderBucket(k, oBkt)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding order %x: %w", k, err)
+		}
+		return &dexdb.SearchResult{Order: mord}, nil
+	case refMatch:
+		mBkt, err := findBucket(k, activeMatchesBucket, archivedMatchesBucket)
+		if mBkt == nil {
+			return nil, err
+		}
+		m, err := loadMatchBucket(mBkt, false)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding match %x: %w", k, err)
+		}
+		return &dexdb.SearchResult{Match: m}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,120 @@
+package bolt
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"decred.org/dcrdex/client/db"
+	dbtest "decred.org/dcrdex/client/db/test"
+	"decred.org/dcrdex/dex/encrypt"
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+)
+
+func TestSearch(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	if err := boltdb.SetPrimaryCredentials(dbtest.RandomPrimaryCredentials()); err != nil {
+		t.Fatalf("SetPrimaryCredentials error: %v", err)
+	}
+
+	saveNote := func(subject, details string, stamp uint64) *db.Notification {
+		t.Helper()
+		note := db.NewNotification("order", db.Topic("test"), subject, details, db.Success)
+		note.TimeStamp = stamp
+		if err := boltdb.SaveNotification(&note); err != nil {
+			t.Fatalf("SaveNotification error: %v", err)
+		}
+		return &note
+	}
+	search := func(filter *db.SearchFilter, expN int) []*db.SearchResult {
+		t.Helper()
+		results, err := boltdb.Search(filter)
+		if err != nil {
+			t.Fatalf("Search error: %v", err)
+		}
+		if len(results) != expN {
+			t.Fatalf("expected %d results for %q, got %d", expN, filter.Query, len(results))
+		}
+		return results
+	}
+
+	// Records saved before the index is built are indexed by the first
+	// search.
+	saveNote("Swap sent", "Sent the swap for match abc123 in March", 1000)
+	ord, _ := ordertest.RandomLimitOrder()
+	mord := &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status:     order.OrderStatusExecuted,
+			Host:       "dex.example.com",
+			Proof:      db.OrderProof{DEXSig: randBytes(73)},
+			ChangeCoin: randBytes(36),
+		},
+		Order: ord,
+	}
+	if err := boltdb.UpdateOrder(mord); err != nil {
+		t.Fatalf("UpdateOrder error: %v", err)
+	}
+
+	if _, err := boltdb.Search(&db.SearchFilter{Query: "a b"}); err == nil {
+		t.Fatalf("no error for short search terms")
+	}
+	results := search(&db.SearchFilter{Query: "MARCH swap"}, 1)
+	if results[0].Notification == nil || results[0].Notification.TimeStamp != 1000 {
+		t.Fatalf("wrong notification found")
+	}
+	search(&db.SearchFilter{Query: "march refund"}, 0)
+	results = search(&db.SearchFilter{Query: ord.ID().String()}, 1)
+	if results[0].Order == nil || results[0].Order.Order.ID() != ord.ID() {
+		t.Fatalf("wrong order found")
+	}
+	search(&db.SearchFilter{Query: hex.EncodeToString(mord.MetaData.ChangeCoin)}, 1)
+	search(&db.SearchFilter{Query: ord.Address}, 1)
+	search(&db.SearchFilter{Query: "example"}, 1)
+
+	// Records saved after the index is built are indexed when they are saved.
+	saveNote("Swap sent", "Sent another swap", 2000)
+	saveNote("Swap sent", "And another swap", 3000)
+	results = search(&db.SearchFilter{Query: "swap"}, 3)
+	if results[0].Notification.TimeStamp != 3000 || results[2].Notification.TimeStamp != 1000 {
+		t.Fatalf("results not sorted newest first")
+	}
+	search(&db.SearchFilter{Query: "swap", N: 2}, 2)
+	search(&db.SearchFilter{Query: "swap", Since: 2000}, 2)
+	search(&db.SearchFilter{Query: "swap", Since: 1500, Until: 2500}, 1)
+
+	match := &db.MetaMatch{
+		MetaData: &db.MatchMetaData{
+			Proof: *dbtest.RandomMatchProof(0),
+			DEX:   "dex.example.com",
+			Base:  ord.Base(),
+			Quote: ord.Quote(),
+			Stamp: 4000,
+		},
+		UserMatch: ordertest.RandomUserMatch(),
+	}
+	match.Address = "someaddress"
+	if err := boltdb.UpdateMatch(match); err != nil {
+		t.Fatalf("UpdateMatch error: %v", err)
+	}
+	results = search(&db.SearchFilter{Query: hex.EncodeToString(match.MetaData.Proof.MakerSwap)}, 1)
+	if results[0].Match == nil || results[0].Match.MatchID != match.MatchID {
+		t.Fatalf("wrong match found")
+	}
+	search(&db.SearchFilter{Query: "someaddress"}, 1)
+
+	// Deleted records are not found.
+	if _, err := boltdb.DeleteNotifications(nil, 1); err != nil {
+		t.Fatalf("DeleteNotifications error: %v", err)
+	}
+	search(&db.SearchFilter{Query: "swap"}, 1)
+
+	// Encrypting the database rebuilds the index with hashed terms.
+	if err := boltdb.Unlock(encrypt.NewCrypter([]byte("abc"))); err != nil {
+		t.Fatalf("error encrypting database: %v", err)
+	}
+	search(&db.SearchFilter{Query: "swap"}, 1)
+	search(&db.SearchFilter{Query: "someaddress"}, 1)
+	search(&db.SearchFilter{Query: ord.ID().String()}, 1)
+}
@@ -0,0 +1,331 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+	"go.etcd.io/bbolt"
+)
+
+// The market stats bucket has the daily totals of each market. The key is the
+// hash of the DEX host, the base and quote asset IDs, and the day. The stats
+// are built from the existing orders and matches the first time they are
+// requested, and are updated as matches complete and fees are recorded after
+// that. A match is flagged when it is counted, so that it is only counted
+// once. Fees are counted as the difference from the previously recorded
+// fees of the order.
+
+// msPerDay is the number of milliseconds in a day.
+const msPerDay = 24 * 60 * 60 * 1000
+
+// stampDay is the UTC day of a time stamp in milliseconds.
+func stampDay(stamp uint64) uint32 {
+	return uint32(stamp / msPerDay)
+}
+
+// marketStatsKey is the key of a market's stats for a day.
+func marketStatsKey(stats *bucket, host string, base, quote, day uint32) []byte {
+	k := []byte("stats:" + host)
+	var hostKey []byte
+	if stats.vc == nil {
+		h := sha256.Sum256(k)
+		hostKey = h[:]
+	} else {
+		hostKey = stats.vc.hash(k)
+	}
+	k = make([]byte, 0, len(hostKey)+12)
+	k = append(k, hostKey...)
+	k = append(k, uint32Bytes(base)...)
+	k = append(k, uint32Bytes(quote)...)
+	return append(k, uint32Bytes(day)...)
+}
+
+// marketStatsBuilt is true if the market stats have been built.
+func marketStatsBuilt(tx *bbolt.Tx) bool {
+	return tx.Bucket(appBucket).Get(marketStatsBuiltKey) != nil
+}
+
+// addMarketStats adds to a market's stats for a day.
+func addMarketStats(stats *bucket, host string, base, quote, day uint32, add func(*dexdb.MarketStats)) error {
+	k := marketStatsKey(stats, host, base, quote, day)
+	s := &dexdb.MarketStats{Host: host, Base: base, Quote: quote, Day: day}
+	if b := stats.Get(k); b != nil {
+		var err error
+		if s, err = dexdb.DecodeMarketStats(b); err != nil {
+			return fmt.Errorf("error decoding market stats: %w", err)
+		}
+	}
+	add(s)
+	return stats.Put(k, s.Encode())
+}
+
+// matchCompleted is true if the match is a trade match that completed without
+// a refund.
+func matchCompleted(m *dexdb.MetaMatch) bool {
+	if m.Address == "" || len(m.MetaData.Proof.Auth.InitSig) == 0 || len(m.MetaData.Proof.RefundCoin) > 0 {
+		return false
+	}
+	return m.Status == order.MatchComplete || m.Status == order.MatchConfirmed
+}
+
+// countMatch adds a completed match to the stats, and flags the match as
+// counted.
+func countMatch(stats, mBkt *bucket, m *dexdb.MetaMatch, sell bool) error {
+	md := m.MetaData
+	quoteQty := calc.BaseToQuote(m.Rate, m.Quantity)
+	err := addMarketStats(stats, md.DEX, md.Base, md.Quote, stampDay(md.Stamp), func(s *dexdb.MarketStats) {
+		s.Matches++
+		if sell {
+			s.BaseSold += m.Quantity
+			s.QuoteBought += quoteQty
+		} else {
+			s.BaseBought += m.Quantity
+			s.QuoteSold += quoteQty
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return mBkt.Put(statsCountedKey, byteTrue)
+}
+
+// updateMatchStats counts a match in the stats when it completes. The match's
+// order must be in the database for the side of the match.
+func (db *BoltDB) updateMatchStats(tx *bbolt.Tx, mBkt *bucket, m *dexdb.MetaMatch) error {
+	if !marketStatsBuilt(tx) || !matchCompleted(m) || mBkt.Get(statsCountedKey) != nil {
+		return nil
+	}
+	sell, err := db.orderSide(tx, m.OrderID)
+	if err != nil {
+		db.log.Errorf("Not counting match %s in market stats: %v", m.MatchID, err)
+		return nil
+	}
+	stats, err := db.bucket(tx, marketStatsBucket)
+	if err != nil {
+		return err
+	}
+	return countMatch(stats, mBkt, m, sell)
+}
+
+// orderFees are the fees recorded for an order, by the asset that pays them.
+func orderFees(sell bool, swapFees, redeemFees, fundingFees uint64) (baseFees, quoteFees uint64) {
+	fromFees, toFees := swapFees+fundingFees, redeemFees
+	if sell {
+		return fromFees, toFees
+	}
+	return toFees, fromFees
+}
+
+// updateFeeStats adds the fees of an order that were paid since the order's
+// fees were last recorded. The order bucket must have the order, and the
+// previous fees if any.
+func (db *BoltDB) updateFeeStats(tx *bbolt.Tx, oBkt *bucket, md *dexdb.OrderMetaData) error {
+	if !marketStatsBuilt(tx) {
+		return nil
+	}
+	ord, err := order.DecodeOrder(getCopy(oBkt, orderKey))
+	if err != nil {
+		return fmt.Errorf("error decoding order: %w", err)
+	}
+	trade := ord.Trade()
+	if trade == nil {
+		return nil
+	}
+	prevFees := func(k []byte) uint64 {
+		if b := oBkt.Get(k); len(b) == 8 {
+			return intCoder.Uint64(b)
+		}
+		return 0
+	}
+	newFees := func(fees uint64, k []byte) uint64 {
+		if prev := prevFees(k); fees > prev {
+			return fees - prev
+		}
+		return 0
+	}
+	baseFees, quoteFees := orderFees(trade.Sell, newFees(md.SwapFeesPaid, swapFeesKey),
+		newFees(md.RedemptionFeesPaid, redemptionFeesKey), newFees(md.FundingFeesPaid, fundingFeesKey))
+	if baseFees == 0 && quoteFees == 0 {
+		return nil
+	}
+	stats, err := db.bucket(tx, marketStatsBucket)
+	if err != nil {
+		return err
+	}
+	return addMarketStats(stats, md.Host, ord.Base(), ord.Quote(), stampDay(timeNow()), func(s *dexdb.MarketStats) {
+		s.BaseFees += baseFees
+		s.QuoteFees += quoteFees
+	})
+}
+
+// resetMarketStats empties the market stats, which are built again when they
+// are next requested.
+func resetMarketStats(tx *bbolt.Tx) error {
+	if err := tx.DeleteBucket(marketStatsBucket); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+		return err
+	}
+	if _, err := tx.CreateBucket(marketStatsBucket); err != nil {
+		return err
+	}
+	return tx.Bucket(appBucket).Delete(marketStatsBuiltKey)
+}
+
+// buildMarketStats counts the completed matches, and the fees of the orders on
+// the days they were placed. Records that cannot be decoded are skipped.
+func (db *BoltDB) buildMarketStats(tx *bbolt.Tx) error {
+	stats, err := db.bucket(tx, marketStatsBucket)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range [][]byte{activeMatchesBucket, archivedMatchesBucket} {
+		master, err := db.bucket(tx, name)
+		if err != nil {
+			return err
+		}
+		// The matches are flagged, so they can't be counted while iterating.
+		var keys [][]byte
+		if err := master.ForEach(func(k, _ []byte) error {
+			keys = append(keys, bytes.Clone(k))
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range keys {
+			mBkt := master.Bucket(k)
+			if mBkt == nil {
+				continue
+			}
+			m, err := loadMatchBucket(mBkt, true)
+			if err != nil || m == nil || !matchCompleted(m) {
+				continue
+			}
+			sell, err := db.orderSide(tx, m.OrderID)
+			if err != nil {
+				db.log.Warnf("Not counting match %s in market stats: %v", m.MatchID, err)
+				continue
+			}
+			if err := countMatch(stats, mBkt, m, sell); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, name := range [][]byte{activeOrdersBucket, archivedOrdersBucket} {
+		master, err := db.bucket(tx, name)
+		if err != nil {
+			return err
+		}
+		if err := master.ForEach(func(k, _ []byte) error {
+			oBkt := master.Bucket(k)
+			if oBkt == nil {
+				return nil
+			}
+			mord, err := decodeOrderBucket(k, oBkt)
+			if err != nil {
+				db.log.Warnf("Not counting order %x in market stats: %v", k, err)
+				return nil
+			}
+			trade := mord.Order.Trade()
+			if trade == nil {
+				return nil
+			}
+			md := mord.MetaData
+			baseFees, quoteFees := orderFees(trade.Sell, md.SwapFeesPaid, md.RedemptionFeesPaid, md.FundingFeesPaid)
+			if baseFees == 0 && quoteFees == 0 {
+				return nil
+			}
+			return addMarketStats(stats, md.Host, mord.Order.Base(), mord.Order.Quote(), stampDay(uint64(mord.Order.Time())),
+				func(s *dexdb.MarketStats) {
+					s.BaseFees += baseFees
+					s.QuoteFees += quoteFees
+				})
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Bucket(appBucket).Put(marketStatsBuiltKey, byteTrue)
+}
+
+// MarketStats returns the daily trading totals of the markets, sorted by day.
+// The stats are built from the existing orders and matches by the first
+// request.
+func (db *BoltDB) MarketStats(filter *dexdb.MarketStatsFilter) ([]*dexdb.MarketStats, error) {
+	var sinceDay, untilDay uint32
+	if filter.Since > 0 {
+		sinceDay = stampDay(filter.Since)
+	}
+	if filter.Until > 0 {
+		// Until is exclusive, so a day that starts at Until is excluded.
+		untilDay = stampDay(filter.Until + msPerDay - 1)
+	}
+	var allStats []*dexdb.MarketStats
+	readStats := func(tx *bbolt.Tx) error {
+		stats, err := db.bucket(tx, marketStatsBucket)
+		if err != nil {
+			return err
+		}
+		return stats.ForEach(func(k, v []byte) error {
+			s, err := dexdb.DecodeMarketStats(v)
+			if err != nil {
+				return fmt.Errorf("error decoding market stats: %w", err)
+			}
+			if (filter.Host != "" && s.Host != filter.Host) ||
+				(filter.Market != nil && (s.Base != filter.Market.Base || s.Quote != filter.Market.Quote)) ||
+				s.Day < sinceDay || (untilDay > 0 && s.Day >= untilDay) {
+				return nil
+			}
+			allStats = append(allStats, s)
+			return nil
+		})
+	}
+	// The stats are read in a read-only transaction, unless they have to be
+	// built first.
+	var built bool
+	err := db.View(func(tx *bbolt.Tx) error {
+		if built = marketStatsBuilt(tx); !built {
+			return nil
+		}
+		return readStats(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !built {
+		err = db.Update(func(tx *bbolt.Tx) error {
+			if !marketStatsBuilt(tx) {
+				if err := db.buildMarketStats(tx); err != nil {
+					return fmt.Errorf("error building market stats: %w", err)
+				}
+			}
+			return readStats(tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(allStats, func(i, j int) bool {
+		si, sj := allStats[i], allStats[j]
+		if si.Day != sj.Day {
+			return si.Day < sj.Day
+		}
+		if si.Host != sj.Host {
+			return si.Host < sj.Host
+		}
+		if si.Base != sj.Base {
+			return si.Base < sj.Base
+		}
+		return si.Quote < sj.Quote
+	})
+	return allStats, nil
+}
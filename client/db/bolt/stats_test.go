@@ -0,0 +1,125 @@
+package bolt
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/db"
+	dbtest "decred.org/dcrdex/client/db/test"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+)
+
+func TestMarketStats(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	const host = "dex.example.com"
+	ord, _ := ordertest.RandomLimitOrder()
+	ord.T.Sell = true
+	mord := &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status:             order.OrderStatusExecuted,
+			Host:               host,
+			Proof:              db.OrderProof{DEXSig: randBytes(73)},
+			SwapFeesPaid:       100,
+			RedemptionFeesPaid: 10,
+		},
+		Order: ord,
+	}
+	if err := boltdb.UpdateOrder(mord); err != nil {
+		t.Fatalf("UpdateOrder error: %v", err)
+	}
+	orderDay := stampDay(uint64(ord.Time()))
+
+	newMatch := func(status order.MatchStatus) *db.MetaMatch {
+		proof := dbtest.RandomMatchProof(0)
+		proof.RefundCoin = nil
+		proof.ServerRevoked, proof.SelfRevoked = false, false
+		m := &db.MetaMatch{
+			MetaData: &db.MatchMetaData{
+				Proof: *proof,
+				DEX:   host,
+				Base:  ord.Base(),
+				Quote: ord.Quote(),
+				Stamp: uint64(ord.Time()),
+			},
+			UserMatch: ordertest.RandomUserMatch(),
+		}
+		m.OrderID = ord.ID()
+		m.Status = status
+		m.Quantity = 1e8
+		m.Rate = 2e8
+		return m
+	}
+	updateMatch := func(m *db.MetaMatch) {
+		t.Helper()
+		if err := boltdb.UpdateMatch(m); err != nil {
+			t.Fatalf("UpdateMatch error: %v", err)
+		}
+	}
+	getStats := func(filter *db.MarketStatsFilter) []*db.MarketStats {
+		t.Helper()
+		stats, err := boltdb.MarketStats(filter)
+		if err != nil {
+			t.Fatalf("MarketStats error: %v", err)
+		}
+		return stats
+	}
+
+	// Matches and fees recorded before the stats are built are counted by the
+	// first request. Refunded matches are not counted.
+	updateMatch(newMatch(order.MatchConfirmed))
+	refunded := newMatch(order.MakerSwapCast)
+	refunded.MetaData.Proof.RefundCoin = randBytes(36)
+	updateMatch(refunded)
+	stats := getStats(&db.MarketStatsFilter{})
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 day of stats, got %d", len(stats))
+	}
+	s := stats[0]
+	quoteQty := calc.BaseToQuote(2e8, 1e8)
+	if s.Host != host || s.Day != orderDay || s.Matches != 1 || s.BaseSold != 1e8 ||
+		s.QuoteBought != quoteQty || s.BaseBought != 0 || s.BaseFees != 100 || s.QuoteFees != 10 {
+		t.Fatalf("wrong stats %+v", s)
+	}
+
+	// A match is counted once, when it completes.
+	m := newMatch(order.MakerSwapCast)
+	updateMatch(m)
+	if getStats(&db.MarketStatsFilter{})[0].Matches != 1 {
+		t.Fatalf("active match counted")
+	}
+	m.Status = order.MatchComplete
+	updateMatch(m)
+	m.Status = order.MatchConfirmed
+	updateMatch(m)
+	if s := getStats(&db.MarketStatsFilter{})[0]; s.Matches != 2 || s.BaseSold != 2e8 {
+		t.Fatalf("completed match not counted once: %+v", s)
+	}
+
+	// Fees are counted on the day they are recorded.
+	mord.MetaData.SwapFeesPaid = 150
+	if err := boltdb.UpdateOrderMetaData(ord.ID(), mord.MetaData); err != nil {
+		t.Fatalf("UpdateOrderMetaData error: %v", err)
+	}
+	today := stampDay(timeNow())
+	stats = getStats(&db.MarketStatsFilter{Since: uint64(today) * msPerDay})
+	if today == orderDay {
+		if len(stats) != 1 || stats[0].BaseFees != 150 {
+			t.Fatalf("fees not counted: %+v", stats)
+		}
+	} else if len(stats) != 1 || stats[0].BaseFees != 50 || stats[0].Matches != 0 {
+		t.Fatalf("fees not counted on the day they were recorded: %+v", stats)
+	}
+
+	if len(getStats(&db.MarketStatsFilter{Host: "other.dex"})) != 0 {
+		t.Fatalf("stats for the wrong host")
+	}
+	if len(getStats(&db.MarketStatsFilter{Market: &db.OrderFilterMarket{Base: ord.Base(), Quote: ord.Quote() + 1}})) != 0 {
+		t.Fatalf("stats for the wrong market")
+	}
+	if len(getStats(&db.MarketStatsFilter{Until: uint64(orderDay) * msPerDay})) != 0 {
+		t.Fatalf("stats before the until day")
+	}
+}
@@ -42,6 +42,8 @@ var upgrades = [...]upgradefunc{
 	v5Upgrade,
 	// v5 => v6 splits matches into separate active and archived buckets.
 	v6Upgrade,
+	// v6 => v7 allows encrypted values. The database is encrypted at login.
+	v7Upgrade,
 }
 
 // DBVersion is the latest version of the database that is understood. Databases
@@ -366,6 +368,13 @@ func v6Upgrade(dbtx *bbolt.Tx) error {
 	})
 }
 
+// v7Upgrade changes nothing. The version prevents older software, which can't
+// decrypt the values, from opening the database after it is encrypted at
+// login.
+func v7Upgrade(dbtx *bbolt.Tx) error {
+	return nil
+}
+
 func ensureVersion(tx *bbolt.Tx, ver uint32) error {
 	dbVersion, err := getVersionTx(tx)
 	if err != nil {
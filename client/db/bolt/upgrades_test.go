@@ -258,7 +258,7 @@ func verifyV6Upgrade(t *testing.T, db *bbolt.DB) {
 	}
 
 	bdb := &BoltDB{DB: db}
-	err := bdb.matchesView(func(mb, amb *bbolt.Bucket) error {
+	err := bdb.matchesView(func(mb, amb *bucket) error {
 		// active matches
 		err := mb.ForEach(func(k, _ []byte) error {
 			matchID := hex.EncodeToString(k)
@@ -7,6 +7,7 @@ import (
 	"context"
 	"time"
 
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encrypt"
 	"decred.org/dcrdex/dex/order"
@@ -24,6 +25,13 @@ type DB interface {
 	// stores the new *PrimaryCredentials.
 	Recrypt(creds *PrimaryCredentials, oldCrypter, newCrypter encrypt.Crypter) (
 		walletUpdates map[uint32][]byte, acctUpdates map[string][]byte, err error)
+	// Unlock gives access to the encrypted data with the app's inner crypter.
+	// If the database is not encrypted yet, it is encrypted.
+	Unlock(crypter encrypt.Crypter) error
+	// Locked is true if the database is encrypted and has not been unlocked.
+	// Methods that access encrypted data return ErrLocked while the database
+	// is locked.
+	Locked() bool
 	// ListAccounts returns a list of DEX URLs. The DB is designed to have a
 	// single account per DEX, so the account is uniquely identified by the DEX
 	// host.
@@ -118,10 +126,30 @@ type DB interface {
 	// BackupTo makes a backup of the database at the specified location,
 	// optionally overwriting any existing file and compacting the database.
 	BackupTo(dst string, overwrite, compact bool) error
+	// Restore validates the backup at src, and stages it to replace the
+	// database the next time the database is opened.
+	Restore(src string) error
 	// SaveNotification saves the notification.
 	SaveNotification(*Notification) error
 	// NotificationsN reads out the N most recent notifications.
 	NotificationsN(int) ([]*Notification, error)
+	// Search finds the notifications, orders and matches with the search
+	// terms, newest first.
+	Search(*SearchFilter) ([]*SearchResult, error)
+	// MarketStats returns the daily trading totals of the markets, sorted by
+	// day.
+	MarketStats(*MarketStatsFilter) ([]*MarketStats, error)
+	// SaveFiatRates records the fiat exchange rates of the assets at the time
+	// stamp, in milliseconds.
+	SaveFiatRates(stamp uint64, rates map[uint32]float64) error
+	// FiatRateHistory returns the fiat rates recorded for the asset in the
+	// time range, oldest first. Since is inclusive, and until is exclusive.
+	// Zero until means no limit.
+	FiatRateHistory(assetID uint32, since, until uint64) ([]*FiatRate, error)
+	// FiatRateAt returns the fiat rate recorded for the asset nearest to the
+	// time stamp. ErrNoFiatRate is returned if there are no recorded rates for
+	// the asset.
+	FiatRateAt(assetID uint32, stamp uint64) (*FiatRate, error)
 	// AckNotification sets the acknowledgement for a notification.
 	AckNotification(id []byte) error
 	// SavePokes saves a slice of notifications, overwriting any previously
@@ -132,15 +160,21 @@ type DB interface {
 	LoadPokes() ([]*Notification, error)
 	// DeleteInactiveOrders deletes inactive orders from the database that are
 	// older than the supplied time and returns the total number of orders
-	// deleted. If no time is supplied, the current time is used. Accepts an
-	// optional function to perform on deleted orders.
-	DeleteInactiveOrders(ctx context.Context, olderThan *time.Time, perOrderFn func(ord *MetaOrder) error) (int, error)
+	// deleted. If no time is supplied, the current time is used. If keepNewest
+	// is non-zero, the newest keepNewest archived orders are not deleted.
+	// Accepts an optional function to perform on deleted orders.
+	DeleteInactiveOrders(ctx context.Context, olderThan *time.Time, keepNewest int, perOrderFn func(ord *MetaOrder) error) (int, error)
 	// DeleteInactiveMatches deletes inactive matches from the database that are
 	// older than the supplied time and return total number of matches deleted.
-	// If no time is supplied, the current time is used. Accepts an optional
-	// function to perform on deleted matches that includes if it was a sell
-	// order.
-	DeleteInactiveMatches(ctx context.Context, olderThan *time.Time, perMatchFn func(match *MetaMatch, isSell bool) error) (int, error)
+	// If no time is supplied, the current time is used. If keepNewest is
+	// non-zero, the newest keepNewest archived matches are not deleted.
+	// Accepts an optional function to perform on deleted matches that includes
+	// if it was a sell order.
+	DeleteInactiveMatches(ctx context.Context, olderThan *time.Time, keepNewest int, perMatchFn func(match *MetaMatch, isSell bool) error) (int, error)
+	// DeleteNotifications deletes notifications older than the supplied time,
+	// except for the newest keepNewest notifications if keepNewest is non-zero,
+	// and returns the number of notifications deleted.
+	DeleteNotifications(olderThan *time.Time, keepNewest int) (int, error)
 	// SetSeedGenerationTime stores the time when the app seed was generated.
 	SetSeedGenerationTime(time uint64) error
 	// SeedGenerationTime fetches the time when the app seed was generated.
@@ -155,4 +189,30 @@ type DB interface {
 	SetLanguage(lang string) error
 	// Language gets the language stored with SetLanguage.
 	Language() (string, error)
+	// SaveCustomToken stores a user-defined token, overwriting any stored
+	// token with the same asset ID.
+	SaveCustomToken(tokenID uint32, ct *asset.CustomToken) error
+	// CustomTokens retrieves all tokens stored with SaveCustomToken.
+	CustomTokens() (map[uint32]*asset.CustomToken, error)
+	// SaveExplorerSettings stores the user's block explorer settings.
+	SaveExplorerSettings(*ExplorerSettings) error
+	// ExplorerSettings retrieves the settings stored with
+	// SaveExplorerSettings. If none have been stored, empty settings are
+	// returned.
+	ExplorerSettings() (*ExplorerSettings, error)
+	// SaveTorSettings stores the user's tor settings.
+	SaveTorSettings(*TorSettings) error
+	// TorSettings retrieves the settings stored with SaveTorSettings. If none
+	// have been stored, tor is disabled.
+	TorSettings() (*TorSettings, error)
+	// SaveProxySettings stores the user's proxy settings.
+	SaveProxySettings(*ProxySettings) error
+	// ProxySettings retrieves the settings stored with SaveProxySettings. If
+	// none have been stored, empty settings are returned.
+	ProxySettings() (*ProxySettings, error)
+	// SaveRetentionSettings stores the user's record retention settings.
+	SaveRetentionSettings(*RetentionSettings) error
+	// RetentionSettings retrieves the settings stored with
+	// SaveRetentionSettings. If none have been stored, nothing is pruned.
+	RetentionSettings() (*RetentionSettings, error)
 }
@@ -75,6 +75,10 @@ func RandomPrimaryCredentials() *db.PrimaryCredentials {
 
 // RandomWallet creates a random wallet.
 func RandomWallet() *db.Wallet {
+	var encSeed []byte
+	if rand.Intn(2) == 0 {
+		encSeed = randBytes(80)
+	}
 	return &db.Wallet{
 		AssetID: rand.Uint32(),
 		Settings: map[string]string{
@@ -87,7 +91,8 @@ func RandomWallet() *db.Wallet {
 			Balance: *RandomBalance(),
 			Stamp:   time.Unix(rand.Int63()/(1<<31), 0),
 		},
-		Address: ordertest.RandomAddress(),
+		Address:       ordertest.RandomAddress(),
+		EncryptedSeed: encSeed,
 	}
 }
 
@@ -319,6 +324,9 @@ func MustCompareWallets(t testKiller, w1, w2 *db.Wallet) {
 	if !bytes.Equal(w1.EncryptedPW, w2.EncryptedPW) {
 		t.Fatalf("EncryptedPW mismatch. %x != %x", w1.EncryptedPW, w2.EncryptedPW)
 	}
+	if !bytes.Equal(w1.EncryptedSeed, w2.EncryptedSeed) {
+		t.Fatalf("EncryptedSeed mismatch. %x != %x", w1.EncryptedSeed, w2.EncryptedSeed)
+	}
 }
 
 // MustCompareBalances ensures the two BalanceSet are identical, calling the
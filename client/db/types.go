@@ -45,6 +45,8 @@ const (
 	ErrNoCredentials = dex.ErrorKind("no credentials have been stored")
 	ErrAcctNotFound  = dex.ErrorKind("account not found")
 	ErrNoSeedGenTime = dex.ErrorKind("seed generation time has not been stored")
+	ErrLocked        = dex.ErrorKind("database is locked")
+	ErrNoFiatRate    = dex.ErrorKind("no fiat rate recorded")
 )
 
 // String satisfies fmt.Stringer for Severity.
@@ -106,7 +108,7 @@ type Bond struct {
 	Data       dex.Bytes `json:"data"` // e.g. redeem script
 	Amount     uint64    `json:"amt"`
 	LockTime   uint64    `json:"lockTime"`
-	KeyIndex   uint32    `json:"keyIndex"` // child key index for HD path: m / hdKeyPurposeBonds / assetID' / bondIndex
+	KeyIndex   uint32    `json:"keyIndex"` // child key index for HD path: m / keygen.BondKeyPurpose / assetID' / bondIndex
 	RefundTx   dex.Bytes `json:"refundTx"` // pays to wallet that created it - only a backup for emergency!
 
 	Confirmed bool `json:"confirmed"` // if reached required confs according to server, not in serialization
@@ -866,18 +868,22 @@ type Wallet struct {
 	Settings    map[string]string
 	Balance     *Balance
 	EncryptedPW []byte
-	Address     string
-	Disabled    bool
+	// EncryptedSeed is the encrypted seed of a seeded wallet that was created
+	// from an imported seed instead of one derived from the app seed.
+	EncryptedSeed []byte
+	Address       string
+	Disabled      bool
 }
 
 // Encode encodes the Wallet to a versioned blob.
 func (w *Wallet) Encode() []byte {
-	return versionedBytes(1).
+	return versionedBytes(2).
 		AddData(uint32Bytes(w.AssetID)).
 		AddData(config.Data(w.Settings)).
 		AddData(w.EncryptedPW).
 		AddData([]byte(w.Address)).
-		AddData([]byte(w.Type))
+		AddData([]byte(w.Type)).
+		AddData(w.EncryptedSeed)
 }
 
 // DecodeWallet decodes the versioned blob to a *Wallet. The Balance is NOT set;
@@ -892,6 +898,8 @@ func DecodeWallet(b []byte) (*Wallet, error) {
 		return decodeWallet_v0(pushes)
 	case 1:
 		return decodeWallet_v1(pushes)
+	case 2:
+		return decodeWallet_v2(pushes)
 	}
 	return nil, fmt.Errorf("unknown DecodeWallet version %d", ver)
 }
@@ -903,21 +911,32 @@ func decodeWallet_v0(pushes [][]byte) (*Wallet, error) {
 }
 
 func decodeWallet_v1(pushes [][]byte) (*Wallet, error) {
-	if len(pushes) != 5 {
-		return nil, fmt.Errorf("decodeWallet_v1: expected 5 pushes, got %d", len(pushes))
+	// Add a push for the encrypted seed.
+	pushes = append(pushes, nil)
+	return decodeWallet_v2(pushes)
+}
+
+func decodeWallet_v2(pushes [][]byte) (*Wallet, error) {
+	if len(pushes) != 6 {
+		return nil, fmt.Errorf("decodeWallet_v2: expected 6 pushes, got %d", len(pushes))
 	}
 	idB, settingsB, keyB := pushes[0], pushes[1], pushes[2]
-	addressB, typeB := pushes[3], pushes[4]
+	addressB, typeB, seedB := pushes[3], pushes[4], pushes[5]
 	settings, err := config.Parse(settingsB)
 	if err != nil {
 		return nil, fmt.Errorf("unable to decode wallet settings")
 	}
+	var encSeed []byte
+	if len(seedB) > 0 {
+		encSeed = seedB
+	}
 	return &Wallet{
-		AssetID:     intCoder.Uint32(idB),
-		Type:        string(typeB),
-		Settings:    settings,
-		EncryptedPW: keyB,
-		Address:     string(addressB),
+		AssetID:       intCoder.Uint32(idB),
+		Type:          string(typeB),
+		Settings:      settings,
+		EncryptedPW:   keyB,
+		EncryptedSeed: encSeed,
+		Address:       string(addressB),
 	}, nil
 }
 
@@ -1218,6 +1237,249 @@ type OrderFilter struct {
 	// Statuses is a list of acceptable statuses. A zero-length Statuses means
 	// all statuses are accepted.
 	Statuses []order.OrderStatus
+	// Since and Until limit results to orders last updated in the time range,
+	// in milliseconds. Since is inclusive, and Until is exclusive. Zero means
+	// no limit.
+	Since, Until uint64
+}
+
+// FiatRate is a recorded fiat exchange rate of an asset.
+type FiatRate struct {
+	// Stamp is the time the rate was recorded, in milliseconds.
+	Stamp uint64 `json:"stamp"`
+	// Rate is the value of one unit of the asset in the fiat currency.
+	Rate float64 `json:"rate"`
+}
+
+// Encode serializes the FiatRate's rate. The stamp is part of the key.
+func (r *FiatRate) Encode() []byte {
+	return versionedBytes(0).AddData(uint64Bytes(math.Float64bits(r.Rate)))
+}
+
+// DecodeFiatRate decodes the versioned blob to a *FiatRate with the stamp.
+func DecodeFiatRate(stamp uint64, b []byte) (*FiatRate, error) {
+	ver, pushes, err := encode.DecodeBlob(b, 1)
+	if err != nil {
+		return nil, err
+	}
+	if ver != 0 {
+		return nil, fmt.Errorf("unknown FiatRate version %d", ver)
+	}
+	if len(pushes) != 1 || len(pushes[0]) != 8 {
+		return nil, fmt.Errorf("invalid FiatRate encoding")
+	}
+	return &FiatRate{
+		Stamp: stamp,
+		Rate:  math.Float64frombits(intCoder.Uint64(pushes[0])),
+	}, nil
+}
+
+// MarketStats are the totals of a day of trading on a market. The totals are
+// updated as matches complete and fees are paid.
+type MarketStats struct {
+	Host  string `json:"host"`
+	Base  uint32 `json:"baseID"`
+	Quote uint32 `json:"quoteID"`
+	// Day is the UTC day, in days since the Unix epoch. Matches are counted
+	// on the day they were made, and fees on the day they were recorded.
+	Day uint32 `json:"day"`
+	// Matches is the number of completed trade matches. Refunded matches are
+	// not counted.
+	Matches     uint32 `json:"matches"`
+	BaseBought  uint64 `json:"baseBought"`
+	BaseSold    uint64 `json:"baseSold"`
+	QuoteBought uint64 `json:"quoteBought"`
+	QuoteSold   uint64 `json:"quoteSold"`
+	// BaseFees and QuoteFees are the swap, redemption and funding fees paid
+	// for the base and quote asset transactions, in the units of the asset
+	// that pays the fees, which is the parent asset for a token.
+	BaseFees  uint64 `json:"baseFees"`
+	QuoteFees uint64 `json:"quoteFees"`
+}
+
+// Encode serializes the MarketStats.
+func (s *MarketStats) Encode() []byte {
+	return versionedBytes(0).
+		AddData([]byte(s.Host)).
+		AddData(uint32Bytes(s.Base)).
+		AddData(uint32Bytes(s.Quote)).
+		AddData(uint32Bytes(s.Day)).
+		AddData(uint32Bytes(s.Matches)).
+		AddData(uint64Bytes(s.BaseBought)).
+		AddData(uint64Bytes(s.BaseSold)).
+		AddData(uint64Bytes(s.QuoteBought)).
+		AddData(uint64Bytes(s.QuoteSold)).
+		AddData(uint64Bytes(s.BaseFees)).
+		AddData(uint64Bytes(s.QuoteFees))
+}
+
+// DecodeMarketStats decodes the versioned blob to a *MarketStats.
+func DecodeMarketStats(b []byte) (*MarketStats, error) {
+	ver, pushes, err := encode.DecodeBlob(b, 11)
+	if err != nil {
+		return nil, err
+	}
+	switch ver {
+	case 0:
+		return decodeMarketStats_v0(pushes)
+	}
+	return nil, fmt.Errorf("unknown MarketStats version %d", ver)
+}
+
+func decodeMarketStats_v0(pushes [][]byte) (*MarketStats, error) {
+	if len(pushes) != 11 {
+		return nil, fmt.Errorf("decodeMarketStats_v0: expected 11 pushes, got %d", len(pushes))
+	}
+	for i := 1; i < 5; i++ {
+		if len(pushes[i]) != 4 {
+			return nil, fmt.Errorf("decodeMarketStats_v0: push %d length %d, wanted 4", i, len(pushes[i]))
+		}
+	}
+	for i := 5; i < 11; i++ {
+		if len(pushes[i]) != 8 {
+			return nil, fmt.Errorf("decodeMarketStats_v0: push %d length %d, wanted 8", i, len(pushes[i]))
+		}
+	}
+	return &MarketStats{
+		Host:        string(pushes[0]),
+		Base:        intCoder.Uint32(pushes[1]),
+		Quote:       intCoder.Uint32(pushes[2]),
+		Day:         intCoder.Uint32(pushes[3]),
+		Matches:     intCoder.Uint32(pushes[4]),
+		BaseBought:  intCoder.Uint64(pushes[5]),
+		BaseSold:    intCoder.Uint64(pushes[6]),
+		QuoteBought: intCoder.Uint64(pushes[7]),
+		QuoteSold:   intCoder.Uint64(pushes[8]),
+		BaseFees:    intCoder.Uint64(pushes[9]),
+		QuoteFees:   intCoder.Uint64(pushes[10]),
+	}, nil
+}
+
+// MarketStatsFilter limits the results of (DB).MarketStats.
+type MarketStatsFilter struct {
+	// Host limits results to a DEX host if not empty.
+	Host string
+	// Market limits results to a specific market.
+	Market *OrderFilterMarket
+	// Since and Until limit results to the days in the time range, in
+	// milliseconds. Since is inclusive, and Until is exclusive. Zero means no
+	// limit.
+	Since, Until uint64
+}
+
+// SearchFilter is a query of the search index with (DB).Search.
+type SearchFilter struct {
+	// Query is the search terms. A record matches if it has all of the
+	// terms. The terms are the words of a notification, and the IDs, coin IDs
+	// and addresses of an order or match. Terms are matched whole and are not
+	// case sensitive.
+	Query string
+	// Since and Until limit results to records created in the time range, in
+	// milliseconds. Since is inclusive, and Until is exclusive. Zero means no
+	// limit.
+	Since, Until uint64
+	// N is the maximum number of results. Zero means no limit.
+	N int
+}
+
+// SearchResult is a record found with (DB).Search. Only one of the fields is
+// set.
+type SearchResult struct {
+	Notification *Notification
+	Order        *MetaOrder
+	Match        *MetaMatch
+}
+
+// BlockExplorer is a block explorer's link templates. In the templates,
+// {txid}, {vout}, and {address} are replaced with a transaction ID, output
+// index, and address.
+type BlockExplorer struct {
+	Name  string `json:"name"`
+	TxURL string `json:"txURL"`
+	// OutputURL is optional, and is used for coin IDs with an output index.
+	OutputURL string `json:"outputURL,omitempty"`
+	// AddressURL is optional.
+	AddressURL string `json:"addressURL,omitempty"`
+	// Onion is true if the explorer is a Tor onion service.
+	Onion bool `json:"onion,omitempty"`
+}
+
+// AssetExplorers are the user's block explorer settings for a base chain
+// asset.
+type AssetExplorers struct {
+	// Selected is the name of the chosen explorer, built-in or custom. If
+	// empty, a built-in explorer is chosen.
+	Selected string `json:"selected,omitempty"`
+	// Custom are explorers added by the user.
+	Custom []*BlockExplorer `json:"custom,omitempty"`
+}
+
+// ExplorerSettings are the user's block explorer settings.
+type ExplorerSettings struct {
+	// PreferOnion chooses onion explorers over clearnet explorers for assets
+	// with no selected explorer.
+	PreferOnion bool                       `json:"preferOnion"`
+	Assets      map[uint32]*AssetExplorers `json:"assets"`
+}
+
+// TorSettings are the user's settings for routing DEX, fiat rate, and price
+// oracle traffic through tor.
+type TorSettings struct {
+	Enabled bool `json:"enabled"`
+	// SOCKSAddr is the address of an external tor node's SOCKS proxy. If
+	// empty, a tor node is started and managed by the client.
+	SOCKSAddr string `json:"socksAddr,omitempty"`
+	// TorPath is an optional tor executable for the managed tor node.
+	TorPath string `json:"torPath,omitempty"`
+}
+
+// Outbound services that can have their own proxy in ProxySettings.
+const (
+	// ProxyServiceOracles is the price oracles used by the market makers.
+	ProxyServiceOracles = "oracles"
+	// ProxyServiceFiatRates is the fiat exchange rate sources.
+	ProxyServiceFiatRates = "fiatrates"
+)
+
+// ProxyConfig is how to connect to a DEX host or outbound service.
+type ProxyConfig struct {
+	// SOCKSAddr is the address of a SOCKS5 proxy. If empty, connections are
+	// made directly, without tor or any other proxy.
+	SOCKSAddr string `json:"socksAddr,omitempty"`
+}
+
+// ProxySettings are the user's proxy settings for individual DEX hosts and
+// outbound services. A host or service with a ProxyConfig uses it instead of
+// tor or the proxy configured at startup.
+type ProxySettings struct {
+	// Hosts are the proxies of DEX hosts, by host.
+	Hosts map[string]*ProxyConfig `json:"hosts,omitempty"`
+	// Services are the proxies of the outbound services, by ProxyService*
+	// name.
+	Services map[string]*ProxyConfig `json:"services,omitempty"`
+}
+
+// RetentionSettings are the user's settings for pruning old records from the
+// database. A zero value is no limit. Only archived orders and matches are
+// pruned, and never those of an order with active matches or a match of an
+// active order. Accounts and their bonds are never pruned.
+type RetentionSettings struct {
+	// MaxAgeDays is the age in days after which archived orders and matches,
+	// and notifications, are pruned.
+	MaxAgeDays uint32 `json:"maxAgeDays"`
+	// MaxOrders is the number of archived orders to keep.
+	MaxOrders int `json:"maxOrders"`
+	// MaxMatches is the number of archived matches to keep.
+	MaxMatches int `json:"maxMatches"`
+	// MaxNotifications is the number of notifications to keep.
+	MaxNotifications int `json:"maxNotifications"`
+	// Archive saves the pruned orders and matches to CSV files.
+	Archive bool `json:"archive"`
+}
+
+// Enabled is true if any limit is set.
+func (s *RetentionSettings) Enabled() bool {
+	return s.MaxAgeDays > 0 || s.MaxOrders > 0 || s.MaxMatches > 0 || s.MaxNotifications > 0
 }
 
 // noteKeySize must be <= 32.
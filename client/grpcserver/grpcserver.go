@@ -0,0 +1,273 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package grpcserver provides a gRPC interface to the client core. The service
+// is defined in pb/bisonw.proto.
+package grpcserver
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/authlimit"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/grpcserver/pb"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/dex"
+	"github.com/decred/dcrd/certgen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative pb/bisonw.proto
+
+const (
+	// grpcSemver is the gRPC server's semantic API version. Move major up one
+	// for breaking changes. Move minor for backwards compatible features.
+	// Move patch for bug fixes.
+	grpcSemverMajor uint32 = 0
+	grpcSemverMinor uint32 = 1
+	grpcSemverPatch uint32 = 0
+)
+
+var (
+	// Check that core.Core satisfies clientCore.
+	_ clientCore = (*core.Core)(nil)
+	// Check that mm.MarketMaker satisfies mmCore.
+	_   mmCore = (*mm.MarketMaker)(nil)
+	log dex.Logger
+)
+
+// clientCore is satisfied by core.Core.
+type clientCore interface {
+	Login(appPass []byte) error
+	Logout() error
+	Wallets() (walletsStates []*core.WalletState)
+	CreateWallet(appPass, walletPass []byte, form *core.WalletForm) error
+	OpenWallet(assetID uint32, appPass []byte) error
+	CloseWallet(assetID uint32) error
+	ReconfigureWallet(appPW, newWalletPW []byte, form *core.WalletForm) error
+	Send(appPass []byte, assetID uint32, value uint64, addr string, subtract bool) (asset.Coin, error)
+	Exchanges() (exchanges map[string]*core.Exchange)
+	Book(host string, base, quote uint32) (orderBook *core.OrderBook, err error)
+	Trade(appPass []byte, form *core.TradeForm) (order *core.Order, err error)
+	MultiTrade(pw []byte, form *core.MultiTradeForm) []*core.MultiTradeResult
+	Cancel(orderID dex.Bytes) error
+	Orders(filter *core.OrderFilter) ([]*core.Order, error)
+	NotificationFeed() *core.NoteFeed
+}
+
+// mmCore is satisfied by mm.MarketMaker.
+type mmCore interface {
+	Status() *mm.Status
+	StartBot(startCfg *mm.StartConfig, alternateConfigPath *string, appPW []byte, overrideLotSizeChange bool) (err error)
+	StopBot(mkt *mm.MarketWithHost) error
+}
+
+// SetLogger sets the logger for the grpcserver package.
+func SetLogger(logger dex.Logger) {
+	log = logger
+}
+
+// Config holds variables needed to create a new gRPC Server.
+type Config struct {
+	Core clientCore
+	// MarketMaker is optional. The market making methods return an
+	// Unavailable error without it.
+	MarketMaker                 mmCore
+	Addr, User, Pass, Cert, Key string
+	// Version is the Bison Wallet version.
+	Version   string
+	CertHosts []string
+}
+
+// Server is a gRPC server enabling a protobuf interface to Bison Wallet.
+type Server struct {
+	pb.UnimplementedBisonwServer
+
+	core      clientCore
+	mm        mmCore
+	addr      string
+	srv       *grpc.Server
+	authSHA   [32]byte
+	limiter   *authlimit.Limiter
+	bwVersion string
+}
+
+// genCertPair generates a key/cert pair to the paths provided.
+func genCertPair(certFile, keyFile string, hosts []string) error {
+	log.Infof("Generating TLS certificates...")
+
+	org := "dcrdex autogenerated cert"
+	validUntil := time.Now().Add(10 * 365 * 24 * time.Hour)
+	cert, key, err := certgen.NewTLSCertPair(elliptic.P521(), org,
+		validUntil, hosts)
+	if err != nil {
+		return err
+	}
+
+	// Write cert and key files.
+	if err = os.WriteFile(certFile, cert, 0644); err != nil {
+		return err
+	}
+	if err = os.WriteFile(keyFile, key, 0600); err != nil {
+		os.Remove(certFile)
+		return err
+	}
+
+	log.Infof("Done generating TLS certificates")
+	return nil
+}
+
+// New is the constructor for a gRPC Server.
+func New(cfg *Config) (*Server, error) {
+	if cfg.Pass == "" {
+		return nil, fmt.Errorf("missing gRPC password")
+	}
+
+	// Find or create the key pair.
+	keyExists := dex.FileExists(cfg.Key)
+	certExists := dex.FileExists(cfg.Cert)
+	if certExists == !keyExists {
+		return nil, fmt.Errorf("missing cert pair file")
+	}
+	if !keyExists && !certExists {
+		err := genCertPair(cfg.Cert, cfg.Key, cfg.CertHosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	keypair, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{keypair},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	s := newServer(cfg, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	return s, nil
+}
+
+// newServer creates the Server and registers the service with the provided
+// server options.
+func newServer(cfg *Config, opts ...grpc.ServerOption) *Server {
+	s := &Server{
+		core:      cfg.Core,
+		mm:        cfg.MarketMaker,
+		addr:      cfg.Addr,
+		limiter:   authlimit.New(log.SubLogger("AUTH")),
+		bwVersion: cfg.Version,
+	}
+
+	// Create authSHA to verify requests against.
+	login := cfg.User + ":" + cfg.Pass
+	auth := "Basic " +
+		base64.StdEncoding.EncodeToString([]byte(login))
+	s.authSHA = sha256.Sum256([]byte(auth))
+
+	opts = append(opts,
+		grpc.UnaryInterceptor(s.authUnary),
+		grpc.StreamInterceptor(s.authStream),
+	)
+	s.srv = grpc.NewServer(opts...)
+	pb.RegisterBisonwServer(s.srv, s)
+	return s
+}
+
+// Connect starts the gRPC server. Satisfies the dex.Connector interface.
+func (s *Server) Connect(ctx context.Context) (*sync.WaitGroup, error) {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return nil, fmt.Errorf("can't listen on %s. grpc server quitting: %w", s.addr, err)
+	}
+	// Update the listening address in case a :0 was provided.
+	s.addr = listener.Addr().String()
+	return s.serve(ctx, listener), nil
+}
+
+// serve serves gRPC requests on the listener until the context is canceled.
+func (s *Server) serve(ctx context.Context, listener net.Listener) *sync.WaitGroup {
+	var wg sync.WaitGroup
+
+	// Stop the server on context cancellation. Streams end with their
+	// contexts.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		s.srv.Stop()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := s.srv.Serve(listener); err != nil {
+			log.Warnf("unexpected (grpc.Server).Serve error: %v", err)
+		}
+		log.Infof("gRPC server off")
+	}()
+	log.Infof("gRPC server listening on %s", s.addr)
+	return &wg
+}
+
+// authorize checks the request's credentials. The authorization metadata is
+// the same as the HTTP basic authentication header used by the RPC server.
+func (s *Server) authorize(ctx context.Context) error {
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	auth := md.Get("authorization")
+	if len(auth) == 0 {
+		log.Warnf("authentication failure from ip: %s", remoteAddr)
+		return status.Error(codes.Unauthenticated, "missing credentials")
+	}
+	// Wrong credentials are limited by source IP. Requests without
+	// credentials are not attempts.
+	if ok, wait := s.limiter.Allow(remoteAddr); !ok {
+		return status.Errorf(codes.ResourceExhausted, "too many failed attempts, retry in %v", wait.Round(time.Second))
+	}
+	authSHA := sha256.Sum256([]byte(auth[0]))
+	if subtle.ConstantTimeCompare(s.authSHA[:], authSHA[:]) != 1 {
+		s.limiter.Failure(remoteAddr)
+		log.Warnf("authentication failure from ip: %s", remoteAddr)
+		return status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+	s.limiter.Success(remoteAddr)
+	log.Debugf("authenticated user with ip: %s", remoteAddr)
+	return nil
+}
+
+// authUnary is the unary interceptor checking credentials.
+func (s *Server) authUnary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// authStream is the stream interceptor checking credentials.
+func (s *Server) authStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authorize(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
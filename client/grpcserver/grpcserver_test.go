@@ -0,0 +1,316 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package grpcserver
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/grpcserver/pb"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func init() {
+	log = dex.StdOutLogger("TEST", dex.LevelTrace)
+}
+
+const (
+	tUser = "user"
+	tPass = "pass"
+)
+
+var tErr = errors.New("test error")
+
+type TCore struct {
+	loginErr    error
+	wallets     []*core.WalletState
+	tradeForm   *core.TradeForm
+	order       *core.Order
+	tradeErr    error
+	orderFilter *core.OrderFilter
+	orders      []*core.Order
+	cancelErr   error
+	noteFeed    chan core.Notification
+}
+
+func (c *TCore) Login([]byte) error { return c.loginErr }
+func (c *TCore) Logout() error      { return nil }
+func (c *TCore) Wallets() []*core.WalletState {
+	return c.wallets
+}
+func (c *TCore) CreateWallet(appPass, walletPass []byte, form *core.WalletForm) error {
+	return nil
+}
+func (c *TCore) OpenWallet(assetID uint32, appPass []byte) error { return nil }
+func (c *TCore) CloseWallet(assetID uint32) error                { return nil }
+func (c *TCore) ReconfigureWallet(appPW, newWalletPW []byte, form *core.WalletForm) error {
+	return nil
+}
+func (c *TCore) Send(appPass []byte, assetID uint32, value uint64, addr string, subtract bool) (asset.Coin, error) {
+	return nil, nil
+}
+func (c *TCore) Exchanges() map[string]*core.Exchange { return nil }
+func (c *TCore) Book(host string, base, quote uint32) (*core.OrderBook, error) {
+	return &core.OrderBook{}, nil
+}
+func (c *TCore) Trade(appPass []byte, form *core.TradeForm) (*core.Order, error) {
+	c.tradeForm = form
+	return c.order, c.tradeErr
+}
+func (c *TCore) MultiTrade(pw []byte, form *core.MultiTradeForm) []*core.MultiTradeResult {
+	return nil
+}
+func (c *TCore) Cancel(orderID dex.Bytes) error { return c.cancelErr }
+func (c *TCore) Orders(filter *core.OrderFilter) ([]*core.Order, error) {
+	c.orderFilter = filter
+	return c.orders, nil
+}
+func (c *TCore) NotificationFeed() *core.NoteFeed {
+	return &core.NoteFeed{C: c.noteFeed}
+}
+
+type TMarketMaker struct {
+	status   *mm.Status
+	startCfg *mm.StartConfig
+	cfgPath  *string
+}
+
+func (m *TMarketMaker) Status() *mm.Status { return m.status }
+func (m *TMarketMaker) StartBot(startCfg *mm.StartConfig, alternateConfigPath *string, appPW []byte, overrideLotSizeChange bool) error {
+	m.startCfg = startCfg
+	m.cfgPath = alternateConfigPath
+	return nil
+}
+func (m *TMarketMaker) StopBot(mkt *mm.MarketWithHost) error { return nil }
+
+// newTServer starts a Server on an in-memory listener and returns a client
+// connected to it.
+func newTServer(t *testing.T, cfg *Config) pb.BisonwClient {
+	t.Helper()
+	cfg.User, cfg.Pass = tUser, tPass
+	s := newServer(cfg)
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	wg := s.serve(ctx, listener)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("error creating client: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		wg.Wait()
+	})
+	return pb.NewBisonwClient(conn)
+}
+
+func authCtx(user, pass string) context.Context {
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", auth)
+}
+
+func checkCode(t *testing.T, err error, wantCode codes.Code) {
+	t.Helper()
+	if code := status.Code(err); code != wantCode {
+		t.Fatalf("wanted code %v, got %v (%v)", wantCode, code, err)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	client := newTServer(t, &Config{Core: new(TCore)})
+
+	_, err := client.Version(context.Background(), &pb.VersionRequest{})
+	checkCode(t, err, codes.Unauthenticated)
+
+	_, err = client.Version(authCtx(tUser, "wrong"), &pb.VersionRequest{})
+	checkCode(t, err, codes.Unauthenticated)
+
+	// Streams are authenticated too.
+	stream, err := client.Notifications(context.Background(), &pb.NotificationsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	checkCode(t, err, codes.Unauthenticated)
+
+	res, err := client.Version(authCtx(tUser, tPass), &pb.VersionRequest{})
+	if err != nil {
+		t.Fatalf("Version error: %v", err)
+	}
+	if res.ApiMajor != grpcSemverMajor || res.ApiMinor != grpcSemverMinor {
+		t.Fatalf("wrong version %+v", res)
+	}
+
+	// Repeated failures are rate limited.
+	for range 10 {
+		_, err = client.Version(authCtx(tUser, "wrong"), &pb.VersionRequest{})
+	}
+	checkCode(t, err, codes.ResourceExhausted)
+}
+
+func TestTrade(t *testing.T) {
+	tCore := &TCore{
+		order: &core.Order{
+			ID:          make(dex.Bytes, order.OrderIDSize),
+			Host:        "dex.com",
+			Type:        order.LimitOrderType,
+			Status:      order.OrderStatusBooked,
+			TimeInForce: order.StandingTiF,
+			Qty:         1e8,
+			Rate:        2e6,
+		},
+	}
+	client := newTServer(t, &Config{Core: tCore})
+	ctx := authCtx(tUser, tPass)
+
+	req := &pb.TradeRequest{
+		AppPass: []byte("abc"),
+		Host:    "dex.com",
+		IsLimit: true,
+		BaseId:  42,
+		QuoteId: 0,
+		Qty:     1e8,
+		Rate:    2e6,
+	}
+	res, err := client.Trade(ctx, req)
+	if err != nil {
+		t.Fatalf("Trade error: %v", err)
+	}
+	if tCore.tradeForm.Base != 42 || tCore.tradeForm.Qty != 1e8 || !tCore.tradeForm.IsLimit {
+		t.Fatalf("wrong trade form %+v", tCore.tradeForm)
+	}
+	if res.Order.Status != "booked" || res.Order.Tif != "standing" || res.Order.Type != "limit" {
+		t.Fatalf("wrong order %+v", res.Order)
+	}
+
+	tCore.tradeErr = tErr
+	_, err = client.Trade(ctx, req)
+	checkCode(t, err, codes.Internal)
+
+	req.Host = ""
+	_, err = client.Trade(ctx, req)
+	checkCode(t, err, codes.InvalidArgument)
+}
+
+func TestOrders(t *testing.T) {
+	tCore := &TCore{orders: []*core.Order{{Type: order.MarketOrderType, Status: order.OrderStatusExecuted}}}
+	client := newTServer(t, &Config{Core: tCore})
+	ctx := authCtx(tUser, tPass)
+
+	res, err := client.Orders(ctx, &pb.OrdersRequest{N: 5, Statuses: []string{"booked", "epoch"}})
+	if err != nil {
+		t.Fatalf("Orders error: %v", err)
+	}
+	if len(res.Orders) != 1 || res.Orders[0].Status != "executed" || res.Orders[0].Tif != "" {
+		t.Fatalf("wrong orders %+v", res.Orders)
+	}
+	f := tCore.orderFilter
+	if f.N != 5 || len(f.Statuses) != 2 || f.Statuses[0] != order.OrderStatusBooked || f.Statuses[1] != order.OrderStatusEpoch {
+		t.Fatalf("wrong order filter %+v", f)
+	}
+
+	_, err = client.Orders(ctx, &pb.OrdersRequest{Statuses: []string{"bogus"}})
+	checkCode(t, err, codes.InvalidArgument)
+
+	_, err = client.Cancel(ctx, &pb.CancelRequest{OrderId: []byte{1}})
+	checkCode(t, err, codes.InvalidArgument)
+}
+
+func TestMarketMaking(t *testing.T) {
+	client := newTServer(t, &Config{Core: new(TCore)})
+	ctx := authCtx(tUser, tPass)
+	_, err := client.MMStatus(ctx, &pb.MMStatusRequest{})
+	checkCode(t, err, codes.Unavailable)
+
+	tMM := &TMarketMaker{
+		status: &mm.Status{Bots: []*mm.BotStatus{{
+			Config:  &mm.BotConfig{Host: "dex.com", BaseID: 42},
+			Running: true,
+		}}},
+	}
+	client = newTServer(t, &Config{Core: new(TCore), MarketMaker: tMM})
+	res, err := client.MMStatus(ctx, &pb.MMStatusRequest{})
+	if err != nil {
+		t.Fatalf("MMStatus error: %v", err)
+	}
+	if len(res.Bots) != 1 || res.Bots[0].Host != "dex.com" || res.Bots[0].BaseId != 42 || !res.Bots[0].Running || len(res.Bots[0].StatusJson) == 0 {
+		t.Fatalf("wrong status %+v", res.Bots)
+	}
+
+	_, err = client.StartBot(ctx, &pb.StartBotRequest{Host: "dex.com", BaseId: 42})
+	if err != nil {
+		t.Fatalf("StartBot error: %v", err)
+	}
+	if tMM.startCfg.Host != "dex.com" || tMM.startCfg.BaseID != 42 || tMM.cfgPath != nil {
+		t.Fatalf("wrong start config %+v, %v", tMM.startCfg, tMM.cfgPath)
+	}
+	_, err = client.StartBot(ctx, &pb.StartBotRequest{Host: "dex.com", ConfigPath: "mm.json"})
+	if err != nil {
+		t.Fatalf("StartBot error: %v", err)
+	}
+	if tMM.cfgPath == nil || *tMM.cfgPath != "mm.json" {
+		t.Fatalf("wrong config path %v", tMM.cfgPath)
+	}
+}
+
+func TestNotifications(t *testing.T) {
+	tCore := &TCore{noteFeed: make(chan core.Notification, 2)}
+	client := newTServer(t, &Config{Core: tCore})
+	ctx, cancel := context.WithCancel(authCtx(tUser, tPass))
+	defer cancel()
+
+	stream, err := client.Notifications(ctx, &pb.NotificationsRequest{Hosts: []string{"dex.com"}})
+	if err != nil {
+		t.Fatalf("Notifications error: %v", err)
+	}
+	tCore.noteFeed <- &core.BalanceNote{
+		Notification: db.NewNotification(core.NoteTypeBalance, core.TopicBalanceUpdated, "", "", db.Data),
+	}
+	tCore.noteFeed <- &core.ConnEventNote{
+		Notification: db.NewNotification(core.NoteTypeConnEvent, core.TopicDEXConnected, "subject", "", db.Data),
+		Host:         "dex.com",
+	}
+
+	type recv struct {
+		note *pb.Notification
+		err  error
+	}
+	recvC := make(chan recv, 1)
+	go func() {
+		note, err := stream.Recv()
+		recvC <- recv{note, err}
+	}()
+	select {
+	case r := <-recvC:
+		if r.err != nil {
+			t.Fatalf("Recv error: %v", r.err)
+		}
+		// The balance note does not match the filter.
+		if r.note.Type != core.NoteTypeConnEvent || r.note.Topic != string(core.TopicDEXConnected) ||
+			r.note.Subject != "subject" || len(r.note.NoteJson) == 0 {
+			t.Fatalf("wrong notification %+v", r.note)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification received")
+	}
+}
@@ -0,0 +1,486 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/grpcserver/pb"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/client/websocket"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/order"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// orderStatuses are the order statuses by name.
+var orderStatuses = map[string]order.OrderStatus{
+	order.OrderStatusEpoch.String():    order.OrderStatusEpoch,
+	order.OrderStatusBooked.String():   order.OrderStatusBooked,
+	order.OrderStatusExecuted.String(): order.OrderStatusExecuted,
+	order.OrderStatusCanceled.String(): order.OrderStatusCanceled,
+	order.OrderStatusRevoked.String():  order.OrderStatusRevoked,
+}
+
+// failed creates the error returned when a core method fails.
+func failed(what string, err error) error {
+	return status.Errorf(codes.Internal, "unable to %s: %v", what, err)
+}
+
+// Version returns the Bison Wallet and API versions.
+func (s *Server) Version(context.Context, *pb.VersionRequest) (*pb.VersionResponse, error) {
+	return &pb.VersionResponse{
+		Version:  s.bwVersion,
+		ApiMajor: grpcSemverMajor,
+		ApiMinor: grpcSemverMinor,
+		ApiPatch: grpcSemverPatch,
+	}, nil
+}
+
+// Login unlocks the app and connects to the DEX servers.
+func (s *Server) Login(_ context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	if err := s.core.Login(req.AppPass); err != nil {
+		return nil, failed("log in", err)
+	}
+	return &pb.LoginResponse{}, nil
+}
+
+// Logout locks the app.
+func (s *Server) Logout(context.Context, *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	if err := s.core.Logout(); err != nil {
+		return nil, failed("log out", err)
+	}
+	return &pb.LogoutResponse{}, nil
+}
+
+// Wallets returns the state of every wallet.
+func (s *Server) Wallets(context.Context, *pb.WalletsRequest) (*pb.WalletsResponse, error) {
+	states := s.core.Wallets()
+	wallets := make([]*pb.WalletState, 0, len(states))
+	for _, w := range states {
+		wallets = append(wallets, walletState(w))
+	}
+	return &pb.WalletsResponse{Wallets: wallets}, nil
+}
+
+// NewWallet creates and connects a wallet.
+func (s *Server) NewWallet(_ context.Context, req *pb.NewWalletRequest) (*pb.NewWalletResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	defer encode.ClearBytes(req.WalletPass)
+	err := s.core.CreateWallet(req.AppPass, req.WalletPass, &core.WalletForm{
+		AssetID: req.AssetId,
+		Config:  req.Config,
+		Type:    req.WalletType,
+	})
+	if err != nil {
+		return nil, failed("create wallet", err)
+	}
+	return &pb.NewWalletResponse{}, nil
+}
+
+// OpenWallet unlocks a wallet.
+func (s *Server) OpenWallet(_ context.Context, req *pb.OpenWalletRequest) (*pb.OpenWalletResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	if err := s.core.OpenWallet(req.AssetId, req.AppPass); err != nil {
+		return nil, failed("open wallet", err)
+	}
+	return &pb.OpenWalletResponse{}, nil
+}
+
+// CloseWallet locks a wallet.
+func (s *Server) CloseWallet(_ context.Context, req *pb.CloseWalletRequest) (*pb.CloseWalletResponse, error) {
+	if err := s.core.CloseWallet(req.AssetId); err != nil {
+		return nil, failed("close wallet", err)
+	}
+	return &pb.CloseWalletResponse{}, nil
+}
+
+// ReconfigureWallet changes the type or settings of a wallet, and optionally
+// its password.
+func (s *Server) ReconfigureWallet(_ context.Context, req *pb.ReconfigureWalletRequest) (*pb.ReconfigureWalletResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	defer encode.ClearBytes(req.NewWalletPass)
+	var newWalletPass []byte
+	if len(req.NewWalletPass) > 0 {
+		newWalletPass = req.NewWalletPass
+	}
+	err := s.core.ReconfigureWallet(req.AppPass, newWalletPass, &core.WalletForm{
+		AssetID: req.AssetId,
+		Config:  req.Config,
+		Type:    req.WalletType,
+	})
+	if err != nil {
+		return nil, failed("reconfigure wallet", err)
+	}
+	return &pb.ReconfigureWalletResponse{}, nil
+}
+
+// Send sends funds to an address.
+func (s *Server) Send(_ context.Context, req *pb.SendRequest) (*pb.SendResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	if req.Value == 0 {
+		return nil, status.Error(codes.InvalidArgument, "value must be greater than zero")
+	}
+	if req.Address == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing address")
+	}
+	coin, err := s.core.Send(req.AppPass, req.AssetId, req.Value, req.Address, req.Subtract)
+	if err != nil {
+		return nil, failed("send", err)
+	}
+	return &pb.SendResponse{CoinId: coin.String()}, nil
+}
+
+// Exchanges returns the DEX servers and their markets.
+func (s *Server) Exchanges(context.Context, *pb.ExchangesRequest) (*pb.ExchangesResponse, error) {
+	xcs := s.core.Exchanges()
+	hosts := make([]string, 0, len(xcs))
+	for host := range xcs {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	exchanges := make([]*pb.Exchange, 0, len(xcs))
+	for _, host := range hosts {
+		xc := xcs[host]
+		mktNames := make([]string, 0, len(xc.Markets))
+		for name := range xc.Markets {
+			mktNames = append(mktNames, name)
+		}
+		sort.Strings(mktNames)
+		mkts := make([]*pb.Market, 0, len(mktNames))
+		for _, name := range mktNames {
+			mkt := xc.Markets[name]
+			mkts = append(mkts, &pb.Market{
+				Name:        mkt.Name,
+				BaseId:      mkt.BaseID,
+				BaseSymbol:  mkt.BaseSymbol,
+				QuoteId:     mkt.QuoteID,
+				QuoteSymbol: mkt.QuoteSymbol,
+				LotSize:     mkt.LotSize,
+				ParcelSize:  mkt.ParcelSize,
+				RateStep:    mkt.RateStep,
+				EpochLen:    mkt.EpochLen,
+				MinimumRate: mkt.MinimumRate,
+			})
+		}
+		exchanges = append(exchanges, &pb.Exchange{
+			Host:             xc.Host,
+			AcctId:           xc.AcctID,
+			ConnectionStatus: uint32(xc.ConnectionStatus),
+			ViewOnly:         xc.ViewOnly,
+			Disabled:         xc.Disabled,
+			Markets:          mkts,
+		})
+	}
+	return &pb.ExchangesResponse{Exchanges: exchanges}, nil
+}
+
+// OrderBook returns a market's order book.
+func (s *Server) OrderBook(_ context.Context, req *pb.OrderBookRequest) (*pb.OrderBookResponse, error) {
+	if req.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing host")
+	}
+	book, err := s.core.Book(req.Host, req.BaseId, req.QuoteId)
+	if err != nil {
+		return nil, failed("retrieve order book", err)
+	}
+	return &pb.OrderBookResponse{
+		Sells: bookOrders(book.Sells, req.NOrders),
+		Buys:  bookOrders(book.Buys, req.NOrders),
+		Epoch: bookOrders(book.Epoch, 0),
+	}, nil
+}
+
+// Trade places an order.
+func (s *Server) Trade(_ context.Context, req *pb.TradeRequest) (*pb.TradeResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	if req.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing host")
+	}
+	ord, err := s.core.Trade(req.AppPass, &core.TradeForm{
+		Host:    req.Host,
+		IsLimit: req.IsLimit,
+		Sell:    req.Sell,
+		Base:    req.BaseId,
+		Quote:   req.QuoteId,
+		Qty:     req.Qty,
+		Rate:    req.Rate,
+		TifNow:  req.TifNow,
+		Options: req.Options,
+	})
+	if err != nil {
+		return nil, failed("place order", err)
+	}
+	return &pb.TradeResponse{Order: orderMsg(ord)}, nil
+}
+
+// MultiTrade places several standing limit orders on the same side of a
+// market.
+func (s *Server) MultiTrade(_ context.Context, req *pb.MultiTradeRequest) (*pb.MultiTradeResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	if req.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing host")
+	}
+	if len(req.Placements) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no placements")
+	}
+	placements := make([]*core.QtyRate, 0, len(req.Placements))
+	for _, p := range req.Placements {
+		placements = append(placements, &core.QtyRate{Qty: p.Qty, Rate: p.Rate})
+	}
+	results := s.core.MultiTrade(req.AppPass, &core.MultiTradeForm{
+		Host:       req.Host,
+		Sell:       req.Sell,
+		Base:       req.BaseId,
+		Quote:      req.QuoteId,
+		Placements: placements,
+		Options:    req.Options,
+		MaxLock:    req.MaxLock,
+	})
+	res := &pb.MultiTradeResponse{Results: make([]*pb.MultiTradeResult, 0, len(results))}
+	for _, r := range results {
+		result := new(pb.MultiTradeResult)
+		if r.Error != nil {
+			result.Error = r.Error.Error()
+		} else {
+			result.Order = orderMsg(r.Order)
+		}
+		res.Results = append(res.Results, result)
+	}
+	return res, nil
+}
+
+// Cancel cancels an order.
+func (s *Server) Cancel(_ context.Context, req *pb.CancelRequest) (*pb.CancelResponse, error) {
+	if len(req.OrderId) != order.OrderIDSize {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid order ID length %d", len(req.OrderId))
+	}
+	if err := s.core.Cancel(req.OrderId); err != nil {
+		return nil, failed("cancel order", err)
+	}
+	return &pb.CancelResponse{}, nil
+}
+
+// Orders returns the user's orders, newest first.
+func (s *Server) Orders(_ context.Context, req *pb.OrdersRequest) (*pb.OrdersResponse, error) {
+	filter := &core.OrderFilter{
+		N:      int(req.N),
+		Hosts:  req.Hosts,
+		Assets: req.Assets,
+	}
+	for _, name := range req.Statuses {
+		st, found := orderStatuses[name]
+		if !found {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown order status %q", name)
+		}
+		filter.Statuses = append(filter.Statuses, st)
+	}
+	ords, err := s.core.Orders(filter)
+	if err != nil {
+		return nil, failed("retrieve orders", err)
+	}
+	res := &pb.OrdersResponse{Orders: make([]*pb.Order, 0, len(ords))}
+	for _, ord := range ords {
+		res.Orders = append(res.Orders, orderMsg(ord))
+	}
+	return res, nil
+}
+
+// MMStatus returns the state of the market making bots.
+func (s *Server) MMStatus(context.Context, *pb.MMStatusRequest) (*pb.MMStatusResponse, error) {
+	if s.mm == nil {
+		return nil, status.Error(codes.Unavailable, "market making is not available")
+	}
+	st := s.mm.Status()
+	res := &pb.MMStatusResponse{Bots: make([]*pb.BotStatus, 0, len(st.Bots))}
+	for _, bot := range st.Bots {
+		b, err := json.Marshal(bot)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error encoding bot status: %v", err)
+		}
+		botStatus := &pb.BotStatus{
+			Running:    bot.Running,
+			StatusJson: b,
+		}
+		if bot.Config != nil {
+			botStatus.Host = bot.Config.Host
+			botStatus.BaseId = bot.Config.BaseID
+			botStatus.QuoteId = bot.Config.QuoteID
+		}
+		res.Bots = append(res.Bots, botStatus)
+	}
+	return res, nil
+}
+
+// StartBot starts a market making bot.
+func (s *Server) StartBot(_ context.Context, req *pb.StartBotRequest) (*pb.StartBotResponse, error) {
+	defer encode.ClearBytes(req.AppPass)
+	if s.mm == nil {
+		return nil, status.Error(codes.Unavailable, "market making is not available")
+	}
+	if req.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing host")
+	}
+	var cfgPath *string
+	if req.ConfigPath != "" {
+		cfgPath = &req.ConfigPath
+	}
+	mkt := mm.MarketWithHost{Host: req.Host, BaseID: req.BaseId, QuoteID: req.QuoteId}
+	if err := s.mm.StartBot(&mm.StartConfig{MarketWithHost: mkt}, cfgPath, req.AppPass, true); err != nil {
+		return nil, failed("start market making", err)
+	}
+	return &pb.StartBotResponse{}, nil
+}
+
+// StopBot stops a market making bot.
+func (s *Server) StopBot(_ context.Context, req *pb.StopBotRequest) (*pb.StopBotResponse, error) {
+	if s.mm == nil {
+		return nil, status.Error(codes.Unavailable, "market making is not available")
+	}
+	if req.Host == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing host")
+	}
+	if err := s.mm.StopBot(&mm.MarketWithHost{Host: req.Host, BaseID: req.BaseId, QuoteID: req.QuoteId}); err != nil {
+		return nil, failed("stop market making", err)
+	}
+	return &pb.StopBotResponse{}, nil
+}
+
+// Notifications streams the notifications matching the filter until the call
+// is canceled or the server stops.
+func (s *Server) Notifications(req *pb.NotificationsRequest, stream pb.Bisonw_NotificationsServer) error {
+	filter := &websocket.NoteFilter{
+		Types: req.Types,
+		Hosts: req.Hosts,
+	}
+	for _, t := range req.Topics {
+		filter.Topics = append(filter.Topics, core.Topic(t))
+	}
+	for _, m := range req.Markets {
+		filter.Markets = append(filter.Markets, &websocket.NoteMarket{
+			Host:    m.Host,
+			BaseID:  m.BaseId,
+			QuoteID: m.QuoteId,
+		})
+	}
+
+	feed := s.core.NotificationFeed()
+	defer feed.ReturnFeed()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case n := <-feed.C:
+			if !filter.Match(n) {
+				continue
+			}
+			note, err := notification(n)
+			if err != nil {
+				log.Errorf("error encoding %s notification: %v", n.Type(), err)
+				continue
+			}
+			if err := stream.Send(note); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func walletState(w *core.WalletState) *pb.WalletState {
+	ws := &pb.WalletState{
+		AssetId:      w.AssetID,
+		Symbol:       w.Symbol,
+		WalletType:   w.WalletType,
+		Open:         w.Open,
+		Running:      w.Running,
+		Disabled:     w.Disabled,
+		Encrypted:    w.Encrypted,
+		Address:      w.Address,
+		Synced:       w.Synced,
+		SyncProgress: w.SyncProgress,
+		PeerCount:    w.PeerCount,
+	}
+	if bal := w.Balance; bal != nil {
+		ws.Balance = &pb.WalletBalance{
+			OrderLocked:    bal.OrderLocked,
+			ContractLocked: bal.ContractLocked,
+			BondLocked:     bal.BondLocked,
+		}
+		if bal.Balance != nil {
+			ws.Balance.Available = bal.Available
+			ws.Balance.Immature = bal.Immature
+			ws.Balance.Locked = bal.Locked
+		}
+	}
+	return ws
+}
+
+// bookOrders converts the book orders. n limits the number of orders if not
+// zero.
+func bookOrders(ords []*core.MiniOrder, n uint32) []*pb.BookOrder {
+	if n > 0 && int(n) < len(ords) {
+		ords = ords[:n]
+	}
+	res := make([]*pb.BookOrder, 0, len(ords))
+	for _, o := range ords {
+		res = append(res, &pb.BookOrder{
+			Qty:   o.QtyAtomic,
+			Rate:  o.MsgRate,
+			Sell:  o.Sell,
+			Token: o.Token,
+			Epoch: o.Epoch,
+		})
+	}
+	return res
+}
+
+func orderMsg(o *core.Order) *pb.Order {
+	ord := &pb.Order{
+		Id:         o.ID,
+		Host:       o.Host,
+		Market:     o.MarketID,
+		BaseId:     o.BaseID,
+		QuoteId:    o.QuoteID,
+		Type:       o.Type.String(),
+		Sell:       o.Sell,
+		Qty:        o.Qty,
+		Rate:       o.Rate,
+		Filled:     o.Filled,
+		Status:     o.Status.String(),
+		Cancelling: o.Cancelling,
+		Canceled:   o.Canceled,
+		Stamp:      o.Stamp,
+		SubmitTime: o.SubmitTime,
+		Epoch:      o.Epoch,
+	}
+	if o.Type == order.LimitOrderType {
+		ord.Tif = o.TimeInForce.String()
+	}
+	return ord
+}
+
+func notification(n core.Notification) (*pb.Notification, error) {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding notification: %w", err)
+	}
+	return &pb.Notification{
+		Id:       n.ID(),
+		Type:     n.Type(),
+		Topic:    string(n.Topic()),
+		Subject:  n.Subject(),
+		Details:  n.Details(),
+		Severity: uint32(n.Severity()),
+		Stamp:    n.Time(),
+		NoteJson: b,
+	}, nil
+}
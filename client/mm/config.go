@@ -12,12 +12,17 @@ import (
 type MarketMakingConfig struct {
 	BotConfigs []*BotConfig `json:"botConfigs"`
 	CexConfigs []*CEXConfig `json:"cexConfigs"`
+	// SummaryInterval is how often performance summaries are generated for
+	// each bot. Valid values are "daily" and "weekly". If empty, no
+	// summaries are generated.
+	SummaryInterval string `json:"summaryInterval,omitempty"`
 }
 
 func (cfg *MarketMakingConfig) Copy() *MarketMakingConfig {
 	c := &MarketMakingConfig{
-		BotConfigs: make([]*BotConfig, len(cfg.BotConfigs)),
-		CexConfigs: make([]*CEXConfig, len(cfg.CexConfigs)),
+		BotConfigs:      make([]*BotConfig, len(cfg.BotConfigs)),
+		CexConfigs:      make([]*CEXConfig, len(cfg.CexConfigs)),
+		SummaryInterval: cfg.SummaryInterval,
 	}
 	copy(c.BotConfigs, cfg.BotConfigs)
 	copy(c.CexConfigs, cfg.CexConfigs)
@@ -50,12 +55,17 @@ type CEXConfig struct {
 type AutoRebalanceConfig struct {
 	MinBaseTransfer  uint64 `json:"minBaseTransfer"`
 	MinQuoteTransfer uint64 `json:"minQuoteTransfer"`
+	// Lightning enables deposits and withdrawals over the Lightning Network
+	// for assets whose wallet and CEX both support it. Deposits fall back to
+	// on-chain transfers if the wallet's channels can't send the amount.
+	Lightning bool `json:"lightning,omitempty"`
 }
 
 func (a *AutoRebalanceConfig) copy() *AutoRebalanceConfig {
 	return &AutoRebalanceConfig{
 		MinBaseTransfer:  a.MinBaseTransfer,
 		MinQuoteTransfer: a.MinQuoteTransfer,
+		Lightning:        a.Lightning,
 	}
 }
 
@@ -151,6 +161,14 @@ type BotConfig struct {
 	// when they are starting the bot.
 	LotSize uint64 `json:"lotSize"`
 
+	// Throttle limits the rate at which the bot submits orders and
+	// cancellations. If nil, the bot is not throttled.
+	Throttle *ThrottleConfig `json:"throttle,omitempty"`
+
+	// GasReserve configures management of the parent asset balance used to
+	// pay fees when trading tokens. If nil, the reserve is not monitored.
+	GasReserve *GasReserveConfig `json:"gasReserve,omitempty"`
+
 	// Only one of the following configs should be set
 	BasicMMConfig        *BasicMarketMakingConfig `json:"basicMarketMakingConfig,omitempty"`
 	SimpleArbConfig      *SimpleArbConfig         `json:"simpleArbConfig,omitempty"`
@@ -170,6 +188,8 @@ func (c *BotConfig) copy() *BotConfig {
 	if c.RPCConfig != nil {
 		b.RPCConfig = c.RPCConfig.copy()
 	}
+	b.Throttle = c.Throttle.copy()
+	b.GasReserve = c.GasReserve.copy()
 	if c.BasicMMConfig != nil {
 		b.BasicMMConfig = c.BasicMMConfig.copy()
 	}
@@ -194,6 +214,11 @@ func (c *BotConfig) updateLotSize(oldLotSize, newLotSize uint64) {
 }
 
 func (c *BotConfig) validate() error {
+	if c.GasReserve != nil {
+		if err := c.GasReserve.validate(c.CEXName); err != nil {
+			return err
+		}
+	}
 	if c.BasicMMConfig != nil {
 		return c.BasicMMConfig.validate()
 	} else if c.SimpleArbConfig != nil {
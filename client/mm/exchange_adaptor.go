@@ -166,6 +166,10 @@ type pendingWithdrawal struct {
 	// It will not be the same as the amount received in the dex wallet.
 	amtWithdrawn uint64
 
+	// lightning is true for a withdrawal over the Lightning Network, in which
+	// case txID is the payment hash of the wallet's invoice.
+	lightning bool
+
 	txMtx sync.RWMutex
 	txID  string
 	tx    *asset.WalletTransaction
@@ -317,6 +321,11 @@ type market struct {
 	qui         dex.UnitInfo
 	quoteFeeID  uint32
 	quoteFeeUI  dex.UnitInfo
+
+	// changeEpoch and maintenanceEpoch are the final epochs before the last
+	// scheduled market change and maintenance window announced by the server.
+	changeEpoch      atomic.Uint64
+	maintenanceEpoch atomic.Uint64
 }
 
 func parseMarket(host string, mkt *core.Market) (*market, error) {
@@ -476,6 +485,9 @@ type unifiedExchangeAdaptor struct {
 
 	epochReport atomic.Value // *EpochReport
 
+	// throttle enforces the bot's ThrottleConfig, if any.
+	throttle actionThrottle
+
 	cexProblemsMtx sync.RWMutex
 	cexProblems    *CEXProblems
 }
@@ -888,6 +900,21 @@ func (u *unifiedExchangeAdaptor) groupedBookedOrders(sells bool) (orders map[uin
 	return
 }
 
+// orderBooked returns whether the order with the given ID is a pending dex
+// order that is still on the books.
+func (u *unifiedExchangeAdaptor) orderBooked(oidB dex.Bytes) bool {
+	var oid order.OrderID
+	copy(oid[:], oidB)
+
+	u.balancesMtx.RLock()
+	pendingOrder, found := u.pendingDEXOrders[oid]
+	u.balancesMtx.RUnlock()
+	if !found {
+		return false
+	}
+	return pendingOrder.currentState().order.Status <= order.OrderStatusBooked
+}
+
 // rateCausesSelfMatchFunc returns a function that can be called to determine
 // whether a rate would cause a self match. The sell parameter indicates whether
 // the returned function will support sell or buy orders.
@@ -1350,16 +1377,49 @@ func (u *unifiedExchangeAdaptor) multiTrade(
 		}
 	}
 
-	if len(orderInfos) > 0 {
-		or.UsedDEXBals[fromFeeID] += fundingFees
-	}
-
-	for _, cancel := range cancels {
+	// Cancels are submitted before new orders so that they get priority if
+	// the bot is being throttled. Throttled cancels are queued and retried
+	// first during the next epoch.
+	throttleCfg := u.botCfg().Throttle
+	now := time.Now()
+	cancels = u.throttle.prioritizeCancels(cancels, u.orderBooked)
+	for i, cancel := range cancels {
+		if !u.throttle.allow(throttleCfg, currEpoch, true, now) {
+			u.log.Debugf("multiTrade: throttled %d cancels", len(cancels)-i)
+			u.throttle.queueCancels(cancels[i:])
+			break
+		}
 		if err := u.Cancel(cancel); err != nil {
 			u.log.Errorf("multiTrade: error canceling order %s: %v", cancel, err)
 		}
 	}
 
+	for i := range orderInfos {
+		if u.throttle.allow(throttleCfg, currEpoch, false, now) {
+			continue
+		}
+		u.log.Debugf("multiTrade: throttled %d orders", len(orderInfos)-i)
+		for _, throttled := range orderInfos[i:] {
+			placement := or.Placements[throttled.placementIndex]
+			for assetID, v := range placement.UsedDEX {
+				or.RemainingDEXBals[assetID] += v
+				or.UsedDEXBals[assetID] -= v
+			}
+			or.RemainingCEXBal += placement.UsedCEX
+			or.UsedCEXBal -= placement.UsedCEX
+			placement.OrderedLots = 0
+			placement.UsedDEX = make(map[uint32]uint64)
+			placement.UsedCEX = 0
+			placement.Error = &BotProblems{Throttled: true}
+		}
+		orderInfos = orderInfos[:i]
+		break
+	}
+
+	if len(orderInfos) > 0 {
+		or.UsedDEXBals[fromFeeID] += fundingFees
+	}
+
 	if len(orderInfos) > 0 {
 		results := u.placeMultiTrade(orderInfos, sell)
 		ordered := make(map[order.OrderID]*dexOrderInfo, len(placements))
@@ -1389,6 +1449,10 @@ func (u *unifiedExchangeAdaptor) DEXTrade(rate, qty uint64, sell bool) (*core.Or
 		return nil, fmt.Errorf("insufficient balance")
 	}
 
+	if !u.throttle.allow(u.botCfg().Throttle, 0, false, time.Now()) {
+		return nil, fmt.Errorf("order throttled")
+	}
+
 	placements := []*dexOrderInfo{{
 		placement: &core.QtyRate{
 			Qty:  qty,
@@ -1693,7 +1757,7 @@ func (u *unifiedExchangeAdaptor) pendingDepositComplete(deposit *pendingDeposit)
 
 	if assetID == u.baseID {
 		u.pendingBaseRebalance.Store(false)
-	} else {
+	} else if assetID == u.quoteID {
 		u.pendingQuoteRebalance.Store(false)
 	}
 
@@ -1749,6 +1813,10 @@ func (u *unifiedExchangeAdaptor) confirmDeposit(ctx context.Context, txID string
 			updated = true
 			cexConfirmed = true
 			deposit.cexConfirmed = true
+			if deposit.tx != nil && amtCredited < deposit.tx.Amount {
+				u.log.Warnf("CEX credited %s for deposit %s of %s",
+					u.fmtQty(deposit.assetID, amtCredited), txID, u.fmtQty(deposit.assetID, deposit.tx.Amount))
+			}
 		}
 		deposit.mtx.Unlock()
 	}
@@ -1789,6 +1857,14 @@ func (u *unifiedExchangeAdaptor) deposit(ctx context.Context, assetID uint32, am
 		return fmt.Errorf("bot has insufficient balance to deposit %d. required: %v, have: %v", assetID, amount, balance.Available)
 	}
 
+	if ln := u.lightningCEX(assetID); ln != nil {
+		err := u.depositLightning(ctx, ln, assetID, amount)
+		if !errors.Is(err, errLightningUnavailable) {
+			return err
+		}
+		u.log.Infof("Depositing %s on-chain: %v", u.fmtQty(assetID, amount), err)
+	}
+
 	addr, err := u.CEX.GetDepositAddress(ctx, assetID)
 	if err != nil {
 		return err
@@ -1800,7 +1876,7 @@ func (u *unifiedExchangeAdaptor) deposit(ctx context.Context, assetID uint32, am
 
 	if assetID == u.baseID {
 		u.pendingBaseRebalance.Store(true)
-	} else {
+	} else if assetID == u.quoteID {
 		u.pendingQuoteRebalance.Store(true)
 	}
 
@@ -1865,7 +1941,7 @@ func (u *unifiedExchangeAdaptor) pendingWithdrawalComplete(id string, tx *asset.
 
 	if withdrawal.assetID == u.baseID {
 		u.pendingBaseRebalance.Store(false)
-	} else {
+	} else if withdrawal.assetID == u.quoteID {
 		u.pendingQuoteRebalance.Store(false)
 	}
 
@@ -1898,6 +1974,10 @@ func (u *unifiedExchangeAdaptor) confirmWithdrawal(ctx context.Context, id strin
 	txID := withdrawal.txID
 	withdrawal.txMtx.RUnlock()
 
+	if withdrawal.lightning {
+		return u.confirmLightningWithdrawal(id, withdrawal, txID)
+	}
+
 	if txID == "" {
 		var err error
 		_, txID, err = u.CEX.ConfirmWithdrawal(ctx, id, withdrawal.assetID)
@@ -1947,6 +2027,10 @@ func (u *unifiedExchangeAdaptor) withdraw(ctx context.Context, assetID uint32, a
 		return fmt.Errorf("bot has insufficient balance to withdraw %s. required: %v, have: %v", symbol, amount, balance.Available)
 	}
 
+	if ln := u.lightningCEX(assetID); ln != nil {
+		return u.withdrawLightning(ctx, ln, assetID, amount)
+	}
+
 	addr, err := u.clientCore.NewDepositAddress(assetID)
 	if err != nil {
 		return err
@@ -1975,7 +2059,7 @@ func (u *unifiedExchangeAdaptor) withdraw(ctx context.Context, assetID uint32, a
 	u.log.Infof("Withdrew %s", u.fmtQty(assetID, amount))
 	if assetID == u.baseID {
 		u.pendingBaseRebalance.Store(true)
-	} else {
+	} else if assetID == u.quoteID {
 		u.pendingQuoteRebalance.Store(true)
 	}
 	withdrawal := &pendingWithdrawal{
@@ -2790,6 +2874,24 @@ func (u *unifiedExchangeAdaptor) handleServerConfigUpdate() {
 		return
 	}
 
+	if mt := coreMkt.Maintenance; mt != nil && u.maintenanceEpoch.Swap(mt.FinalEpoch) != mt.FinalEpoch {
+		u.log.Warnf("Server has scheduled maintenance of the market from %v until %v: %s. "+
+			"Orders will not be matched during maintenance.",
+			time.UnixMilli(int64(mt.SuspendTime)), time.UnixMilli(int64(mt.ResumeTime)), mt.Reason)
+	}
+
+	if change := coreMkt.ScheduledChange; change != nil && u.changeEpoch.Swap(change.FinalEpoch) != change.FinalEpoch {
+		if change.LotSize != 0 && change.LotSize != u.lotSize.Load() {
+			u.log.Infof("Server has scheduled a lot size change from %s to %s after epoch %d. "+
+				"Placements will be adjusted when the change takes effect.",
+				u.fmtBase(u.lotSize.Load()), u.fmtBase(change.LotSize), change.FinalEpoch)
+		}
+		if change.RateStep != 0 && change.RateStep != u.rateStep.Load() {
+			u.log.Infof("Server has scheduled a rate step change from %s to %s after epoch %d.",
+				u.fmtRate(u.rateStep.Load()), u.fmtRate(change.RateStep), change.FinalEpoch)
+		}
+	}
+
 	if coreMkt.LotSize == u.lotSize.Load() && coreMkt.RateStep == u.rateStep.Load() {
 		return
 	}
@@ -3788,6 +3890,7 @@ func (u *unifiedExchangeAdaptor) updateCEXProblems(typ cexProblemType, assetID u
 func (u *unifiedExchangeAdaptor) checkBotHealth(epochNum uint64) (healthy bool) {
 	var err error
 	var baseAssetNotSynced, baseAssetNoPeers, quoteAssetNotSynced, quoteAssetNoPeers, accountSuspended bool
+	var lowGasReserve []uint32
 
 	defer func() {
 		if healthy {
@@ -3809,6 +3912,12 @@ func (u *unifiedExchangeAdaptor) checkBotHealth(epochNum uint64) (healthy bool)
 			AccountSuspended: accountSuspended,
 			UnknownError:     unknownErr,
 		}
+		if len(lowGasReserve) > 0 {
+			problems.LowGasReserve = make(map[uint32]bool, len(lowGasReserve))
+			for _, assetID := range lowGasReserve {
+				problems.LowGasReserve[assetID] = true
+			}
+		}
 		u.updateEpochReport(&EpochReport{
 			PreOrderProblems: problems,
 			EpochNum:         epochNum,
@@ -3840,7 +3949,9 @@ func (u *unifiedExchangeAdaptor) checkBotHealth(epochNum uint64) (healthy bool)
 	}
 	accountSuspended = exchange.Auth.EffectiveTier <= 0
 
-	return !(baseAssetNotSynced || baseAssetNoPeers || quoteAssetNotSynced || quoteAssetNoPeers || accountSuspended)
+	lowGasReserve = u.checkGasReserve()
+
+	return !(baseAssetNotSynced || baseAssetNoPeers || quoteAssetNotSynced || quoteAssetNoPeers || accountSuspended || len(lowGasReserve) > 0)
 }
 
 type exchangeAdaptorCfg struct {
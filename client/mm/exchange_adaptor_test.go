@@ -26,6 +26,10 @@ import (
 type tEventLogDB struct {
 	storedEventsMtx sync.Mutex
 	storedEvents    []*MarketMakingEvent
+
+	storedRuns []*MarketMakingRun
+	overviews  map[int64]*MarketMakingRunOverview
+	runEvts    map[int64][]*MarketMakingEvent
 }
 
 var _ eventLogDB = (*tEventLogDB)(nil)
@@ -76,13 +80,13 @@ func (db *tEventLogDB) latestStoredEvent() *MarketMakingEvent {
 	return db.storedEvents[len(db.storedEvents)-1]
 }
 func (db *tEventLogDB) runs(n uint64, refStartTime *uint64, refMkt *MarketWithHost) ([]*MarketMakingRun, error) {
-	return nil, nil
+	return db.storedRuns, nil
 }
 func (db *tEventLogDB) runOverview(startTime int64, mkt *MarketWithHost) (*MarketMakingRunOverview, error) {
-	return nil, nil
+	return db.overviews[startTime], nil
 }
 func (db *tEventLogDB) runEvents(startTime int64, mkt *MarketWithHost, n uint64, refID *uint64, pendingOnly bool, filters *RunLogFilters) ([]*MarketMakingEvent, error) {
-	return nil, nil
+	return db.runEvts[startTime], nil
 }
 
 func tFees(swap, redeem, refund, funding uint64) *OrderFees {
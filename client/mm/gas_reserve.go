@@ -0,0 +1,104 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"fmt"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+)
+
+// GasReserveConfig configures management of the parent asset balance (e.g.
+// ETH or POL) used to pay the fees for token swaps. If the bot's DEX balance
+// of the parent asset falls below Floor, no new orders will be placed until it
+// is replenished.
+type GasReserveConfig struct {
+	// Floor is the minimum DEX balance of the parent asset, in atoms.
+	Floor uint64 `json:"floor"`
+	// TopUpAmount is the amount of the parent asset, in atoms, to withdraw
+	// from the CEX when the balance falls below the floor. If zero, the
+	// reserve is not topped up automatically. Topping up requires the bot
+	// to have a CEX balance of the parent asset.
+	TopUpAmount uint64 `json:"topUpAmount"`
+}
+
+func (c *GasReserveConfig) copy() *GasReserveConfig {
+	if c == nil {
+		return nil
+	}
+	cp := *c
+	return &cp
+}
+
+func (c *GasReserveConfig) validate(cexName string) error {
+	if c.TopUpAmount > 0 && cexName == "" {
+		return fmt.Errorf("gas reserve top up requires a CEX")
+	}
+	return nil
+}
+
+// gasReserveAssets returns the parent assets of any tokens traded by the bot.
+func (u *unifiedExchangeAdaptor) gasReserveAssets() []uint32 {
+	assets := make([]uint32, 0, 2)
+	for _, assetID := range []uint32{u.baseID, u.quoteID} {
+		token := asset.TokenInfo(assetID)
+		if token == nil {
+			continue
+		}
+		var found bool
+		for _, parentID := range assets {
+			found = found || parentID == token.ParentID
+		}
+		if !found {
+			assets = append(assets, token.ParentID)
+		}
+	}
+	return assets
+}
+
+// pendingWithdrawalOf returns whether there is a pending withdrawal of the
+// asset.
+func (u *unifiedExchangeAdaptor) pendingWithdrawalOf(assetID uint32) bool {
+	u.balancesMtx.RLock()
+	defer u.balancesMtx.RUnlock()
+	for _, w := range u.pendingWithdrawals {
+		if w.assetID == assetID {
+			return true
+		}
+	}
+	return false
+}
+
+// checkGasReserve returns the parent assets for which the bot's DEX balance
+// is below the configured gas reserve floor. If the bot is configured to top
+// up the reserve, a withdrawal from the CEX is initiated for each of them,
+// unless one is already pending.
+func (u *unifiedExchangeAdaptor) checkGasReserve() (low []uint32) {
+	cfg := u.botCfg().GasReserve
+	if cfg == nil || cfg.Floor == 0 {
+		return nil
+	}
+
+	for _, assetID := range u.gasReserveAssets() {
+		bal := u.DEXBalance(assetID)
+		if bal.Available+bal.Pending >= cfg.Floor {
+			continue
+		}
+		low = append(low, assetID)
+		u.log.Warnf("%s gas reserve of %s is below the floor of %s",
+			dex.BipIDSymbol(assetID), u.fmtQty(assetID, bal.Available), u.fmtQty(assetID, cfg.Floor))
+
+		if cfg.TopUpAmount == 0 || u.CEX == nil || u.pendingWithdrawalOf(assetID) {
+			continue
+		}
+		err := u.withdraw(u.ctx, assetID, cfg.TopUpAmount)
+		u.updateCEXProblems(cexWithdrawProblem, assetID, err)
+		if err != nil {
+			u.log.Errorf("Error topping up %s gas reserve: %v", dex.BipIDSymbol(assetID), err)
+		}
+	}
+
+	return low
+}
@@ -0,0 +1,79 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"decred.org/dcrdex/dex/encode"
+)
+
+func TestCheckGasReserve(t *testing.T) {
+	const usdcID, ethID, btcID = 60001, 60, 0
+
+	tests := []struct {
+		name        string
+		cfg         *GasReserveConfig
+		ethBalance  uint64
+		cexBalance  uint64
+		expLow      bool
+		expWithdraw bool
+	}{
+		{
+			name:       "no config",
+			ethBalance: 0,
+		},
+		{
+			name:       "above floor",
+			cfg:        &GasReserveConfig{Floor: 1e8},
+			ethBalance: 2e8,
+		},
+		{
+			name:       "below floor, no top up",
+			cfg:        &GasReserveConfig{Floor: 1e8},
+			ethBalance: 5e7,
+			expLow:     true,
+		},
+		{
+			name:        "below floor, top up",
+			cfg:         &GasReserveConfig{Floor: 1e8, TopUpAmount: 2e8},
+			ethBalance:  5e7,
+			cexBalance:  5e8,
+			expLow:      true,
+			expWithdraw: true,
+		},
+	}
+
+	for _, test := range tests {
+		tCore := newTCore()
+		tCEX := newTCEX()
+		tCEX.withdrawalID = hex.EncodeToString(encode.RandomBytes(32))
+
+		adaptor := mustParseAdaptor(&exchangeAdaptorCfg{
+			core:            tCore,
+			cex:             tCEX,
+			baseDexBalances: map[uint32]uint64{usdcID: 1e9, ethID: test.ethBalance, btcID: 1e8},
+			baseCexBalances: map[uint32]uint64{ethID: test.cexBalance},
+			mwh:             &MarketWithHost{Host: "host1", BaseID: usdcID, QuoteID: btcID},
+			eventLogDB:      newTEventLogDB(),
+		})
+		adaptor.botCfgV.Store(&BotConfig{GasReserve: test.cfg, CEXName: "Binance"})
+		ctx, cancel := context.WithCancel(context.Background())
+		adaptor.ctx = ctx
+
+		low := adaptor.checkGasReserve()
+		if (len(low) > 0) != test.expLow {
+			t.Fatalf("%s: expected low = %t, got %v", test.name, test.expLow, low)
+		}
+		if test.expLow && low[0] != ethID {
+			t.Fatalf("%s: expected eth to be low, got %v", test.name, low)
+		}
+		if adaptor.pendingWithdrawalOf(ethID) != test.expWithdraw {
+			t.Fatalf("%s: expected withdrawal = %t", test.name, test.expWithdraw)
+		}
+		cancel()
+	}
+}
@@ -520,6 +520,7 @@ type binance struct {
 }
 
 var _ CEX = (*binance)(nil)
+var _ LightningTransferer = (*binance)(nil)
 
 // TODO: Investigate stablecoin auto-conversion.
 // https://developers.binance.com/docs/wallet/endpoints/switch-busd-stable-coins-convertion
@@ -1016,6 +1017,77 @@ func (bnc *binance) Withdraw(ctx context.Context, assetID uint32, qty uint64, ad
 	return withdrawResp.ID, nil
 }
 
+// lightningNetwork is the network of BTC deposits and withdrawals over the
+// Lightning Network.
+const lightningNetwork = "LIGHTNING"
+
+// lightningQty formats qty for a Lightning deposit or withdrawal. Only BTC can
+// be transferred over the Lightning Network.
+func lightningQty(assetID uint32, qty uint64) (string, string, error) {
+	assetCfg, err := bncAssetCfg(assetID)
+	if err != nil {
+		return "", "", fmt.Errorf("error getting asset cfg for %d: %w", assetID, err)
+	}
+	if assetCfg.coin != "BTC" {
+		return "", "", fmt.Errorf("%s cannot be transferred over the Lightning Network", assetCfg.coin)
+	}
+	prec := int(math.Round(math.Log10(float64(assetCfg.conversionFactor))))
+	convQty := float64(qty) / float64(assetCfg.conversionFactor)
+	return assetCfg.coin, strconv.FormatFloat(convQty, 'f', prec, 64), nil
+}
+
+// GetLightningDepositInvoice returns a Lightning invoice for a deposit of qty.
+// Part of the LightningTransferer interface.
+func (bnc *binance) GetLightningDepositInvoice(ctx context.Context, assetID uint32, qty uint64) (string, error) {
+	coin, qtyStr, err := lightningQty(assetID, qty)
+	if err != nil {
+		return "", err
+	}
+
+	v := make(url.Values)
+	v.Add("coin", coin)
+	v.Add("network", lightningNetwork)
+	v.Add("amount", qtyStr)
+
+	resp := struct {
+		Address string `json:"address"`
+	}{}
+	err = bnc.getAPI(ctx, "/sapi/v1/capital/deposit/address", v, true, true, &resp)
+	if err != nil {
+		return "", err
+	}
+	if resp.Address == "" {
+		return "", errors.New("no invoice returned")
+	}
+
+	return resp.Address, nil
+}
+
+// WithdrawLightning withdraws qty by paying the invoice. Part of the
+// LightningTransferer interface.
+func (bnc *binance) WithdrawLightning(ctx context.Context, assetID uint32, qty uint64, invoice string) (string, error) {
+	coin, qtyStr, err := lightningQty(assetID, qty)
+	if err != nil {
+		return "", err
+	}
+
+	v := make(url.Values)
+	v.Add("coin", coin)
+	v.Add("network", lightningNetwork)
+	v.Add("address", invoice)
+	v.Add("amount", qtyStr)
+
+	withdrawResp := struct {
+		ID string `json:"id"`
+	}{}
+	err = bnc.postAPI(ctx, "/sapi/v1/capital/withdraw/apply", nil, v, true, true, &withdrawResp)
+	if err != nil {
+		return "", err
+	}
+
+	return withdrawResp.ID, nil
+}
+
 // GetDepositAddress returns a deposit address for an asset.
 func (bnc *binance) GetDepositAddress(ctx context.Context, assetID uint32) (string, error) {
 	assetCfg, err := bncAssetCfg(assetID)
@@ -136,6 +136,17 @@ type CEX interface {
 	Book(baseID, quoteID uint32) (buys, sells []*core.MiniOrder, _ error)
 }
 
+// LightningTransferer is a CEX that accepts deposits and sends withdrawals
+// over the Lightning Network.
+type LightningTransferer interface {
+	// GetLightningDepositInvoice returns a Lightning invoice for a deposit of
+	// amt.
+	GetLightningDepositInvoice(ctx context.Context, assetID uint32, amt uint64) (string, error)
+	// WithdrawLightning withdraws amt by paying the invoice, which must be for
+	// amt. The returned withdrawal ID can be passed to ConfirmWithdrawal.
+	WithdrawLightning(ctx context.Context, assetID uint32, amt uint64, invoice string) (string, error)
+}
+
 const (
 	Binance   = "Binance"
 	BinanceUS = "BinanceUS"
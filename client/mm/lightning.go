@@ -0,0 +1,182 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/mm/libxc"
+)
+
+const (
+	// lightningMaxFeeRatio is the maximum routing fee of a Lightning deposit,
+	// as a fraction of the amount deposited.
+	lightningMaxFeeRatio = 0.005
+	// lightningConfirmInterval is how often the wallet is checked for the
+	// payment of a Lightning withdrawal.
+	lightningConfirmInterval = 10 * time.Second
+)
+
+// errLightningUnavailable is returned by depositLightning if the deposit can't
+// be sent over the Lightning Network and should be sent on-chain instead.
+var errLightningUnavailable = errors.New("lightning transfer unavailable")
+
+// lightningCEX returns the CEX as a libxc.LightningTransferer if transfers of
+// the asset should go over the Lightning Network, or nil otherwise. Lightning
+// transfers must be enabled in the bot's rebalance settings, and both the
+// wallet and the CEX must support them.
+func (u *unifiedExchangeAdaptor) lightningCEX(assetID uint32) libxc.LightningTransferer {
+	if u.autoRebalanceCfg == nil || !u.autoRebalanceCfg.Lightning {
+		return nil
+	}
+	ln, is := u.CEX.(libxc.LightningTransferer)
+	if !is {
+		return nil
+	}
+	if _, err := u.clientCore.LightningBalance(assetID); err != nil {
+		return nil
+	}
+	return ln
+}
+
+// depositLightning deposits funds to the CEX by paying a Lightning invoice
+// from the CEX. A settled payment means that the CEX has received the funds,
+// so the deposit is complete as soon as the payment is. errLightningUnavailable
+// is returned if the wallet's channels can't send the amount.
+func (u *unifiedExchangeAdaptor) depositLightning(ctx context.Context, ln libxc.LightningTransferer, assetID uint32, amount uint64) error {
+	maxFee := uint64(float64(amount) * lightningMaxFeeRatio)
+	lnBal, err := u.clientCore.LightningBalance(assetID)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errLightningUnavailable, err)
+	}
+	if lnBal < amount+maxFee {
+		return fmt.Errorf("%w: channel balance %s is less than %s", errLightningUnavailable,
+			u.fmtQty(assetID, lnBal), u.fmtQty(assetID, amount+maxFee))
+	}
+
+	invoice, err := ln.GetLightningDepositInvoice(ctx, assetID, amount)
+	if err != nil {
+		return fmt.Errorf("error getting Lightning deposit invoice: %w", err)
+	}
+	payment, err := u.clientCore.PayLightningInvoice([]byte{}, assetID, invoice, maxFee)
+	if err != nil {
+		return fmt.Errorf("error paying Lightning deposit invoice: %w", err)
+	}
+
+	u.log.Infof("Deposited %s over the Lightning Network. Payment hash = %s", u.fmtQty(assetID, payment.Amount), payment.PaymentHash)
+
+	ui, _ := asset.UnitInfo(assetID)
+	tx := &asset.WalletTransaction{
+		Type:      asset.Send,
+		ID:        payment.PaymentHash,
+		Amount:    payment.Amount,
+		Fees:      payment.Fees,
+		Confirmed: true,
+	}
+	deposit := &pendingDeposit{
+		eventLogID:      u.eventLogID.Add(1),
+		timestamp:       time.Now().Unix(),
+		tx:              tx,
+		assetID:         assetID,
+		feeConfirmed:    true,
+		cexConfirmed:    true,
+		amtCredited:     payment.Amount,
+		amtConventional: float64(payment.Amount) / float64(ui.Conventional.ConversionFactor),
+	}
+	u.updateDepositEvent(deposit)
+
+	u.balancesMtx.Lock()
+	u.pendingDeposits[tx.ID] = deposit
+	u.balancesMtx.Unlock()
+
+	u.pendingDepositComplete(deposit)
+	return nil
+}
+
+// withdrawLightning withdraws funds from the CEX by having it pay an invoice
+// from the wallet. The withdrawal is complete when the invoice is settled.
+func (u *unifiedExchangeAdaptor) withdrawLightning(ctx context.Context, ln libxc.LightningTransferer, assetID uint32, amount uint64) error {
+	invoice, err := u.clientCore.LightningInvoice(assetID, amount, "market maker withdrawal")
+	if err != nil {
+		return fmt.Errorf("error creating Lightning invoice: %w", err)
+	}
+
+	u.balancesMtx.Lock()
+	withdrawalID, err := ln.WithdrawLightning(ctx, assetID, amount, invoice.Invoice)
+	if err != nil {
+		u.balancesMtx.Unlock()
+		return err
+	}
+
+	u.log.Infof("Withdrew %s over the Lightning Network. Payment hash = %s", u.fmtQty(assetID, amount), invoice.PaymentHash)
+	if assetID == u.baseID {
+		u.pendingBaseRebalance.Store(true)
+	} else if assetID == u.quoteID {
+		u.pendingQuoteRebalance.Store(true)
+	}
+	withdrawal := &pendingWithdrawal{
+		eventLogID:   u.eventLogID.Add(1),
+		timestamp:    time.Now().Unix(),
+		assetID:      assetID,
+		amtWithdrawn: amount,
+		withdrawalID: withdrawalID,
+		lightning:    true,
+		txID:         invoice.PaymentHash,
+	}
+	u.pendingWithdrawals[withdrawalID] = withdrawal
+	u.balancesMtx.Unlock()
+
+	u.updateWithdrawalEvent(withdrawal, nil)
+	u.sendStatsUpdate()
+
+	u.wg.Add(1)
+	go func() {
+		defer u.wg.Done()
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+				if u.confirmWithdrawal(ctx, withdrawalID) {
+					return
+				}
+				timer = time.NewTimer(lightningConfirmInterval)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// confirmLightningWithdrawal completes a Lightning withdrawal if the wallet's
+// invoice has been paid.
+func (u *unifiedExchangeAdaptor) confirmLightningWithdrawal(id string, withdrawal *pendingWithdrawal, paymentHash string) bool {
+	settled, amtPaid, err := u.clientCore.LightningInvoiceStatus(withdrawal.assetID, paymentHash)
+	if err != nil {
+		u.log.Errorf("Error checking Lightning withdrawal invoice: %v", err)
+		return false
+	}
+	if !settled {
+		return false
+	}
+
+	tx := &asset.WalletTransaction{
+		Type:      asset.Receive,
+		ID:        paymentHash,
+		Amount:    amtPaid,
+		Confirmed: true,
+	}
+	withdrawal.txMtx.Lock()
+	withdrawal.tx = tx
+	withdrawal.txMtx.Unlock()
+
+	u.pendingWithdrawalComplete(id, tx)
+	return true
+}
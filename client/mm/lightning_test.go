@@ -0,0 +1,143 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex/encode"
+)
+
+func TestLightningDeposit(t *testing.T) {
+	const btcID, dcrID = 0, 42
+	const depositAmt, lnFees = 1e6, 100
+
+	tests := []struct {
+		name         string
+		lightning    bool
+		lnBalance    uint64
+		expLightning bool
+	}{
+		{
+			name:         "lightning",
+			lightning:    true,
+			lnBalance:    2e6,
+			expLightning: true,
+		},
+		{
+			name:      "insufficient channel balance",
+			lightning: true,
+			lnBalance: depositAmt,
+		},
+		{
+			name:      "lightning disabled",
+			lnBalance: 2e6,
+		},
+	}
+
+	for _, test := range tests {
+		tCore := newTCore()
+		tCore.lnBalance = test.lnBalance
+		tCore.lnPayment = &asset.LightningPayment{
+			PaymentHash: hex.EncodeToString(encode.RandomBytes(32)),
+			Amount:      depositAmt,
+			Fees:        lnFees,
+		}
+		coin := &tCoin{coinID: encode.RandomBytes(32)}
+		tCore.sendCoin = coin
+		tCore.walletTxs[coin.TxID()] = &asset.WalletTransaction{ID: coin.TxID(), Amount: depositAmt}
+		tCEX := newTCEX()
+		tCEX.lnDepositInvoice = "lnbcrt10m1invoice"
+
+		adaptor := mustParseAdaptor(&exchangeAdaptorCfg{
+			core:                tCore,
+			cex:                 tCEX,
+			baseDexBalances:     map[uint32]uint64{btcID: 5e6, dcrID: 5e6},
+			baseCexBalances:     map[uint32]uint64{btcID: 5e6, dcrID: 5e6},
+			mwh:                 &MarketWithHost{Host: "host1", BaseID: dcrID, QuoteID: btcID},
+			autoRebalanceConfig: &AutoRebalanceConfig{Lightning: test.lightning},
+			eventLogDB:          newTEventLogDB(),
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		if err := adaptor.deposit(ctx, btcID, depositAmt); err != nil {
+			t.Fatalf("%s: deposit error: %v", test.name, err)
+		}
+		cancel()
+
+		if !test.expLightning {
+			if len(tCore.lnPaidInvoices) != 0 || len(tCore.sends) != 1 {
+				t.Fatalf("%s: expected an on-chain deposit", test.name)
+			}
+			continue
+		}
+		if len(tCore.sends) != 0 || len(tCore.lnPaidInvoices) != 1 || tCore.lnPaidInvoices[0] != tCEX.lnDepositInvoice {
+			t.Fatalf("%s: expected the CEX invoice to be paid", test.name)
+		}
+		if bal := adaptor.DEXBalance(btcID); bal.Available != 5e6-depositAmt-lnFees || bal.Pending != 0 {
+			t.Fatalf("%s: wrong DEX balance %+v", test.name, bal)
+		}
+		if bal := adaptor.CEXBalance(btcID); bal.Available != 5e6+depositAmt || bal.Pending != 0 {
+			t.Fatalf("%s: wrong CEX balance %+v", test.name, bal)
+		}
+	}
+}
+
+func TestLightningWithdrawal(t *testing.T) {
+	const btcID, dcrID = 0, 42
+	const withdrawAmt = 1e6
+
+	tCore := newTCore()
+	tCore.lnBalance = 1e6
+	tCore.lnInvoice = &asset.LightningInvoice{
+		Invoice:     "lnbcrt10m1invoice",
+		PaymentHash: hex.EncodeToString(encode.RandomBytes(32)),
+		Amount:      withdrawAmt,
+	}
+	tCEX := newTCEX()
+	tCEX.withdrawalID = hex.EncodeToString(encode.RandomBytes(32))
+
+	adaptor := mustParseAdaptor(&exchangeAdaptorCfg{
+		core:                tCore,
+		cex:                 tCEX,
+		baseDexBalances:     map[uint32]uint64{btcID: 5e6, dcrID: 5e6},
+		baseCexBalances:     map[uint32]uint64{btcID: 5e6, dcrID: 5e6},
+		mwh:                 &MarketWithHost{Host: "host1", BaseID: dcrID, QuoteID: btcID},
+		autoRebalanceConfig: &AutoRebalanceConfig{Lightning: true},
+		eventLogDB:          newTEventLogDB(),
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := adaptor.withdraw(ctx, btcID, withdrawAmt); err != nil {
+		t.Fatalf("withdraw error: %v", err)
+	}
+	if len(tCEX.lnWithdrawals) != 1 || tCEX.lnWithdrawals[0] != tCore.lnInvoice.Invoice {
+		t.Fatalf("expected a Lightning withdrawal to the wallet's invoice")
+	}
+	if len(tCEX.withdrawals) != 0 {
+		t.Fatalf("unexpected on-chain withdrawal")
+	}
+
+	if adaptor.confirmWithdrawal(ctx, tCEX.withdrawalID) {
+		t.Fatalf("withdrawal confirmed before the invoice was paid")
+	}
+	if bal := adaptor.DEXBalance(btcID); bal.Pending != withdrawAmt {
+		t.Fatalf("wrong pending DEX balance %+v", bal)
+	}
+
+	tCore.lnSettled.Store(true)
+	if !adaptor.confirmWithdrawal(ctx, tCEX.withdrawalID) {
+		t.Fatalf("withdrawal not confirmed after the invoice was paid")
+	}
+	if bal := adaptor.DEXBalance(btcID); bal.Available != 5e6+withdrawAmt || bal.Pending != 0 {
+		t.Fatalf("wrong DEX balance %+v", bal)
+	}
+	if bal := adaptor.CEXBalance(btcID); bal.Available != 5e6-withdrawAmt {
+		t.Fatalf("wrong CEX balance %+v", bal)
+	}
+}
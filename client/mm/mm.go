@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"sync"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"decred.org/dcrdex/client/mm/libxc"
 	"decred.org/dcrdex/client/orderbook"
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/dexnet"
 	"decred.org/dcrdex/dex/order"
 )
 
@@ -44,7 +46,13 @@ type clientCore interface {
 	WalletTransaction(uint32, string) (*asset.WalletTransaction, error)
 	TradingLimits(host string) (userParcels, parcelLimit uint32, err error)
 	WalletState(assetID uint32) *core.WalletState
+	WalletSettings(assetID uint32) (map[string]string, error)
+	LightningBalance(assetID uint32) (uint64, error)
+	LightningInvoice(assetID uint32, amt uint64, memo string) (*asset.LightningInvoice, error)
+	LightningInvoiceStatus(assetID uint32, paymentHash string) (settled bool, amtPaid uint64, err error)
+	PayLightningInvoice(pw []byte, assetID uint32, invoice string, maxFee uint64) (*asset.LightningPayment, error)
 	Exchange(host string) (*core.Exchange, error)
+	HTTPClient() *http.Client
 }
 
 var _ clientCore = (*core.Core)(nil)
@@ -161,6 +169,10 @@ type MarketMaker struct {
 
 	cexMtx sync.RWMutex
 	cexes  map[string]*centralizedExchange
+
+	// summaryIntervalUpdated wakes the summary scheduler when the summary
+	// interval is changed.
+	summaryIntervalUpdated chan struct{}
 }
 
 // NewMarketMaker creates a new MarketMaker.
@@ -182,6 +194,8 @@ func NewMarketMaker(c clientCore, eventLogDBPath, cfgPath string, log dex.Logger
 		eventLogDBPath: eventLogDBPath,
 		runningBots:    make(map[MarketWithHost]*runningBot),
 		cexes:          make(map[string]*centralizedExchange),
+
+		summaryIntervalUpdated: make(chan struct{}, 1),
 	}, nil
 }
 
@@ -263,6 +277,12 @@ type BotProblems struct {
 	CEXOrderbookUnsynced bool `json:"cexOrderbookUnsynced"`
 	// CausesSelfMatch is true if the order would cause a self match.
 	CausesSelfMatch bool `json:"causesSelfMatch"`
+	// LowGasReserve is true for parent assets of traded tokens whose balance
+	// is below the bot's gas reserve floor.
+	LowGasReserve map[uint32]bool `json:"lowGasReserve"`
+	// Throttled is true if the order was not placed because the bot had
+	// reached a limit in its ThrottleConfig.
+	Throttled bool `json:"throttled"`
 	// UnknownError is set if an error occurred that was not one of the above.
 	UnknownError string `json:"unknownError"`
 }
@@ -496,6 +516,23 @@ func (m *MarketMaker) loginAndUnlockWallets(pw []byte, cfg *BotConfig) error {
 	return nil
 }
 
+// checkRedeemAddresses returns an error if a wallet of the bot's market
+// redeems to an external address. The bot's balances would be credited with
+// redemptions that never reach the wallet.
+func (m *MarketMaker) checkRedeemAddresses(cfg *BotConfig) error {
+	for _, assetID := range []uint32{cfg.BaseID, cfg.QuoteID} {
+		settings, err := m.core.WalletSettings(assetID)
+		if err != nil {
+			return fmt.Errorf("error getting %s wallet settings: %w", dex.BipIDSymbol(assetID), err)
+		}
+		if settings["redeemaddress"] != "" {
+			return fmt.Errorf("the %s wallet redeems to an external address, which is not supported for market making",
+				dex.BipIDSymbol(assetID))
+		}
+	}
+	return nil
+}
+
 func (m *MarketMaker) connectCEX(ctx context.Context, c *centralizedExchange) error {
 	var cm *dex.ConnectionMaster
 	c.mtx.Lock()
@@ -659,10 +696,18 @@ func (m *MarketMaker) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	}
 	m.eventLogDB = eventLogDB
 
-	m.oracle = newPriceOracle(m.ctx, m.log.SubLogger("oracle"))
+	// Oracle requests go through tor if it's enabled in Core.
+	oracleCtx := dexnet.ContextWithClient(m.ctx, m.core.HTTPClient())
+	m.oracle = newPriceOracle(oracleCtx, m.log.SubLogger("oracle"))
 
 	var wg sync.WaitGroup
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.runSummaryScheduler(ctx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -857,6 +902,10 @@ func (m *MarketMaker) StartBot(startCfg *StartConfig, alternateConfigPath *strin
 
 func (m *MarketMaker) startBot(startCfg *StartConfig, botCfg *BotConfig, cexCfg *CEXConfig, appPW []byte) (err error) {
 	mwh := &startCfg.MarketWithHost
+	if err := m.checkRedeemAddresses(botCfg); err != nil {
+		return err
+	}
+
 	if err := m.balancesSufficient(startCfg.Alloc, mwh, cexCfg); err != nil {
 		return err
 	}
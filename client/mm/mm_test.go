@@ -5,8 +5,10 @@ import (
 	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"net/http"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -93,6 +95,13 @@ type tCore struct {
 	parcelLimit       uint32
 	exchange          *core.Exchange
 	walletStates      map[uint32]*core.WalletState
+	walletSettings    map[uint32]map[string]string
+	lnBalance         uint64
+	lnBalanceErr      error
+	lnInvoice         *asset.LightningInvoice
+	lnSettled         atomic.Bool
+	lnPayment         *asset.LightningPayment
+	lnPaidInvoices    []string
 }
 
 func newTCore() *tCore {
@@ -190,6 +199,10 @@ func (c *tCore) Network() dex.Network {
 	return dex.Simnet
 }
 
+func (c *tCore) HTTPClient() *http.Client {
+	return http.DefaultClient
+}
+
 func (c *tCore) FiatConversionRates() map[uint32]float64 {
 	return c.fiatRates
 }
@@ -227,6 +240,27 @@ func (c *tCore) WalletState(assetID uint32) *core.WalletState {
 	return c.walletStates[assetID]
 }
 
+func (c *tCore) WalletSettings(assetID uint32) (map[string]string, error) {
+	return c.walletSettings[assetID], nil
+}
+
+func (c *tCore) LightningBalance(assetID uint32) (uint64, error) {
+	return c.lnBalance, c.lnBalanceErr
+}
+func (c *tCore) LightningInvoice(assetID uint32, amt uint64, memo string) (*asset.LightningInvoice, error) {
+	return c.lnInvoice, nil
+}
+func (c *tCore) LightningInvoiceStatus(assetID uint32, paymentHash string) (bool, uint64, error) {
+	if !c.lnSettled.Load() {
+		return false, 0, nil
+	}
+	return true, c.lnInvoice.Amount, nil
+}
+func (c *tCore) PayLightningInvoice(pw []byte, assetID uint32, invoice string, maxFee uint64) (*asset.LightningPayment, error) {
+	c.lnPaidInvoices = append(c.lnPaidInvoices, invoice)
+	return c.lnPayment, nil
+}
+
 func (c *tCore) setWalletsAndExchange(m *core.Market) {
 	c.walletStates[m.BaseID] = &core.WalletState{
 		PeerCount: 1,
@@ -432,6 +466,8 @@ type tCEX struct {
 	confirmDepositMtx    sync.Mutex
 	confirmedDeposit     *uint64
 	tradeStatus          *libxc.Trade
+	lnDepositInvoice     string
+	lnWithdrawals        []string
 }
 
 func newTCEX() *tCEX {
@@ -445,6 +481,15 @@ func newTCEX() *tCEX {
 }
 
 var _ libxc.CEX = (*tCEX)(nil)
+var _ libxc.LightningTransferer = (*tCEX)(nil)
+
+func (c *tCEX) GetLightningDepositInvoice(ctx context.Context, assetID uint32, amt uint64) (string, error) {
+	return c.lnDepositInvoice, nil
+}
+func (c *tCEX) WithdrawLightning(ctx context.Context, assetID uint32, amt uint64, invoice string) (string, error) {
+	c.lnWithdrawals = append(c.lnWithdrawals, invoice)
+	return c.withdrawalID, nil
+}
 
 func (c *tCEX) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	return &sync.WaitGroup{}, nil
@@ -812,3 +857,20 @@ func TestAvailableBalances(t *testing.T) {
 	checkAvailableBalances(btcUsdc, map[uint32]uint64{0: 3e5, 60: 7e5, 60001: 4e5}, map[uint32]uint64{0: 5e5, 60001: 4e5})
 	checkAvailableBalances(dcrUsdc, map[uint32]uint64{42: 9e5, 60: 7e5, 60001: 4e5}, map[uint32]uint64{42: 7e5, 60001: 6e5})
 }
+
+func TestCheckRedeemAddresses(t *testing.T) {
+	tCore := newTCore()
+	mm := &MarketMaker{core: tCore}
+	cfg := &BotConfig{BaseID: 42, QuoteID: 0}
+
+	if err := mm.checkRedeemAddresses(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tCore.walletSettings = map[uint32]map[string]string{
+		0: {"redeemaddress": "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"},
+	}
+	if err := mm.checkRedeemAddresses(cfg); err == nil {
+		t.Fatalf("no error for a wallet with a redeem address")
+	}
+}
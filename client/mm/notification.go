@@ -13,6 +13,7 @@ const (
 	NoteTypeCEXNotification = "cexnote"
 	NoteTypeEpochReport     = "epochreport"
 	NoteTypeCEXProblems     = "cexproblems"
+	NoteTypeBotSummary      = "botsummary"
 )
 
 type runStatsNote struct {
@@ -35,6 +36,11 @@ func newRunStatsNote(host string, baseID, quoteID uint32, stats *RunStats) *runS
 	}
 }
 
+// BotMarket is the market of the bot that the notification is for.
+func (n *runStatsNote) BotMarket() (host string, baseID, quoteID uint32) {
+	return n.Host, n.BaseID, n.QuoteID
+}
+
 type runEventNote struct {
 	db.Notification
 
@@ -56,6 +62,11 @@ func newRunEventNote(host string, baseID, quoteID uint32, startTime int64, event
 	}
 }
 
+// BotMarket is the market of the bot that the notification is for.
+func (n *runEventNote) BotMarket() (host string, baseID, quoteID uint32) {
+	return n.Host, n.BaseID, n.QuoteID
+}
+
 type cexNotification struct {
 	db.Notification
 	CEXName string      `json:"cexName"`
@@ -92,6 +103,11 @@ func newEpochReportNote(host string, baseID, quoteID uint32, report *EpochReport
 	}
 }
 
+// BotMarket is the market of the bot that the notification is for.
+func (n *botProblemsNotification) BotMarket() (host string, baseID, quoteID uint32) {
+	return n.Host, n.BaseID, n.QuoteID
+}
+
 type cexProblemsNotification struct {
 	db.Notification
 	Host     string       `json:"host"`
@@ -109,3 +125,20 @@ func newCexProblemsNote(host string, baseID, quoteID uint32, problems *CEXProble
 		Problems:     problems,
 	}
 }
+
+// BotMarket is the market of the bot that the notification is for.
+func (n *cexProblemsNotification) BotMarket() (host string, baseID, quoteID uint32) {
+	return n.Host, n.BaseID, n.QuoteID
+}
+
+type botSummaryNotification struct {
+	db.Notification
+	Summary *PerformanceSummary `json:"summary"`
+}
+
+func newPerformanceSummaryNote(summary *PerformanceSummary) *botSummaryNotification {
+	return &botSummaryNotification{
+		Notification: db.NewNotification(NoteTypeBotSummary, "", "", "", db.Data),
+		Summary:      summary,
+	}
+}
@@ -0,0 +1,175 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultMaxWithdrawalFeeRatio is the default portion of a withdrawal that a
+// CEX may deduct as a fee before the withdrawal is flagged as a discrepancy.
+const DefaultMaxWithdrawalFeeRatio = 0.05
+
+// TransferRecord is an entry in the transfer ledger of a market making run.
+// It tracks a deposit to or a withdrawal from a CEX, comparing the amount that
+// was sent with the amount that was received.
+type TransferRecord struct {
+	EventID   uint64 `json:"eventID"`
+	Timestamp int64  `json:"timestamp"`
+	Deposit   bool   `json:"deposit"`
+	AssetID   uint32 `json:"assetID"`
+	TxID      string `json:"txID,omitempty"`
+	// Sent is the amount sent by the wallet for deposits, or the amount
+	// debited from the CEX balance for withdrawals.
+	Sent uint64 `json:"sent"`
+	// Received is the amount credited to the CEX balance for deposits, or the
+	// amount received by the wallet for withdrawals.
+	Received uint64 `json:"received"`
+	// Fees are the on-chain fees paid by the wallet for deposits, or the
+	// amount deducted by the CEX for withdrawals.
+	Fees uint64 `json:"fees"`
+	// Pending is true until the CEX has credited a deposit, or the wallet
+	// has received a withdrawal.
+	Pending bool `json:"pending"`
+	// Confirmed is true if the on-chain transaction has been confirmed.
+	Confirmed bool `json:"confirmed"`
+	// Shortfall is the amount missing from a completed transfer beyond the
+	// expected fees.
+	Shortfall uint64 `json:"shortfall"`
+	// Discrepancy describes why the transfer was flagged. It is empty if
+	// the transfer reconciled.
+	Discrepancy string `json:"discrepancy,omitempty"`
+}
+
+// ReconciliationReport is the result of reconciling all of the transfers made
+// during a market making run.
+type ReconciliationReport struct {
+	Transfers []*TransferRecord `json:"transfers"`
+	// Pending is the number of transfers that have not completed.
+	Pending int `json:"pending"`
+	// Flagged is the number of transfers with a discrepancy.
+	Flagged int `json:"flagged"`
+	// Shortfalls is the total shortfall of flagged transfers per asset.
+	Shortfalls map[uint32]uint64 `json:"shortfalls"`
+}
+
+func depositRecord(e *MarketMakingEvent) *TransferRecord {
+	d := e.DepositEvent
+	r := &TransferRecord{
+		EventID:   e.ID,
+		Timestamp: e.TimeStamp,
+		Deposit:   true,
+		AssetID:   d.AssetID,
+		Received:  d.CEXCredit,
+		Pending:   e.Pending,
+	}
+	if d.Transaction == nil {
+		if !e.Pending {
+			r.Discrepancy = "deposit completed without a transaction"
+		}
+		return r
+	}
+	tx := d.Transaction
+	r.TxID = tx.ID
+	r.Sent = tx.Amount
+	r.Fees = tx.Fees
+	r.Confirmed = tx.Confirmed
+	switch {
+	case tx.Rejected:
+		r.Discrepancy = "deposit transaction rejected"
+	case e.Pending:
+	case r.Received < r.Sent:
+		r.Shortfall = r.Sent - r.Received
+		r.Discrepancy = "CEX credited less than the amount deposited"
+	}
+	return r
+}
+
+func withdrawalRecord(e *MarketMakingEvent, maxFeeRatio float64) *TransferRecord {
+	w := e.WithdrawalEvent
+	r := &TransferRecord{
+		EventID:   e.ID,
+		Timestamp: e.TimeStamp,
+		AssetID:   w.AssetID,
+		Sent:      w.CEXDebit,
+		Pending:   e.Pending,
+	}
+	if w.Transaction == nil {
+		if !e.Pending {
+			r.Discrepancy = "withdrawal completed without a transaction"
+			r.Shortfall = r.Sent
+		}
+		return r
+	}
+	tx := w.Transaction
+	r.TxID = tx.ID
+	r.Received = tx.Amount
+	r.Confirmed = tx.Confirmed
+	if r.Sent > r.Received {
+		r.Fees = r.Sent - r.Received
+	}
+	if e.Pending {
+		return r
+	}
+	if maxFee := uint64(float64(r.Sent) * maxFeeRatio); r.Fees > maxFee {
+		r.Shortfall = r.Fees - maxFee
+		r.Discrepancy = fmt.Sprintf("withdrawal fee exceeds %.2f%% of the amount withdrawn", maxFeeRatio*100)
+	}
+	return r
+}
+
+// reconcileTransfers builds a reconciliation report from the deposit and
+// withdrawal events of a run.
+func reconcileTransfers(events []*MarketMakingEvent, maxWithdrawalFeeRatio float64) *ReconciliationReport {
+	report := &ReconciliationReport{
+		Transfers:  make([]*TransferRecord, 0),
+		Shortfalls: make(map[uint32]uint64),
+	}
+	for _, e := range events {
+		var r *TransferRecord
+		switch {
+		case e.DepositEvent != nil:
+			r = depositRecord(e)
+		case e.WithdrawalEvent != nil:
+			r = withdrawalRecord(e, maxWithdrawalFeeRatio)
+		default:
+			continue
+		}
+		if r.Pending {
+			report.Pending++
+		}
+		if r.Discrepancy != "" {
+			report.Flagged++
+			report.Shortfalls[r.AssetID] += r.Shortfall
+		}
+		report.Transfers = append(report.Transfers, r)
+	}
+	sort.Slice(report.Transfers, func(i, j int) bool {
+		return report.Transfers[i].EventID < report.Transfers[j].EventID
+	})
+	return report
+}
+
+// ReconcileTransfers returns the transfer ledger of a market making run,
+// flagging deposits that were not fully credited by the CEX and withdrawals
+// for which the CEX deducted more than maxWithdrawalFeeRatio of the amount
+// withdrawn. If maxWithdrawalFeeRatio is zero, DefaultMaxWithdrawalFeeRatio
+// is used.
+func (m *MarketMaker) ReconcileTransfers(startTime int64, mkt *MarketWithHost, maxWithdrawalFeeRatio float64) (*ReconciliationReport, error) {
+	if maxWithdrawalFeeRatio < 0 || maxWithdrawalFeeRatio >= 1 {
+		return nil, fmt.Errorf("invalid max withdrawal fee ratio %f", maxWithdrawalFeeRatio)
+	}
+	if maxWithdrawalFeeRatio == 0 {
+		maxWithdrawalFeeRatio = DefaultMaxWithdrawalFeeRatio
+	}
+	events, err := m.eventLogDB.runEvents(startTime, mkt, 0, nil, false, &RunLogFilters{
+		Deposits:    true,
+		Withdrawals: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reconcileTransfers(events, maxWithdrawalFeeRatio), nil
+}
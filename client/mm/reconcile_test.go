@@ -0,0 +1,83 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+func TestReconcileTransfers(t *testing.T) {
+	const assetID = 42
+	events := []*MarketMakingEvent{
+		{ // fully credited deposit
+			ID: 1,
+			DepositEvent: &DepositEvent{
+				AssetID:     assetID,
+				CEXCredit:   1e8,
+				Transaction: &asset.WalletTransaction{ID: "a", Amount: 1e8, Fees: 1000, Confirmed: true},
+			},
+		},
+		{ // short deposit
+			ID: 2,
+			DepositEvent: &DepositEvent{
+				AssetID:     assetID,
+				CEXCredit:   9e7,
+				Transaction: &asset.WalletTransaction{ID: "b", Amount: 1e8, Fees: 1000, Confirmed: true},
+			},
+		},
+		{ // pending deposit
+			ID:      3,
+			Pending: true,
+			DepositEvent: &DepositEvent{
+				AssetID:     assetID,
+				Transaction: &asset.WalletTransaction{ID: "c", Amount: 1e8},
+			},
+		},
+		{ // withdrawal with a reasonable fee
+			ID: 4,
+			WithdrawalEvent: &WithdrawalEvent{
+				AssetID:     assetID,
+				CEXDebit:    1e8,
+				Transaction: &asset.WalletTransaction{ID: "d", Amount: 1e8 - 1e6},
+			},
+		},
+		{ // withdrawal with an excessive fee
+			ID: 5,
+			WithdrawalEvent: &WithdrawalEvent{
+				AssetID:     assetID,
+				CEXDebit:    1e8,
+				Transaction: &asset.WalletTransaction{ID: "e", Amount: 8e7},
+			},
+		},
+		{ // not a transfer
+			ID:            6,
+			CEXOrderEvent: &CEXOrderEvent{},
+		},
+	}
+
+	report := reconcileTransfers(events, DefaultMaxWithdrawalFeeRatio)
+	if len(report.Transfers) != 5 {
+		t.Fatalf("expected 5 transfers, got %d", len(report.Transfers))
+	}
+	if report.Pending != 1 {
+		t.Fatalf("expected 1 pending transfer, got %d", report.Pending)
+	}
+	if report.Flagged != 2 {
+		t.Fatalf("expected 2 flagged transfers, got %d", report.Flagged)
+	}
+	if report.Transfers[1].Shortfall != 1e7 {
+		t.Fatalf("wrong deposit shortfall %d", report.Transfers[1].Shortfall)
+	}
+	if report.Transfers[3].Discrepancy != "" || report.Transfers[3].Fees != 1e6 {
+		t.Fatalf("withdrawal with reasonable fee flagged or wrong fee: %+v", report.Transfers[3])
+	}
+	if report.Transfers[4].Shortfall != 2e7-5e6 {
+		t.Fatalf("wrong withdrawal shortfall %d", report.Transfers[4].Shortfall)
+	}
+	if report.Shortfalls[assetID] != 1e7+2e7-5e6 {
+		t.Fatalf("wrong total shortfall %d", report.Shortfalls[assetID])
+	}
+}
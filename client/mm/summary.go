@@ -0,0 +1,379 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+const (
+	// SummaryIntervalDaily generates performance summaries at midnight UTC
+	// each day.
+	SummaryIntervalDaily = "daily"
+	// SummaryIntervalWeekly generates performance summaries at midnight UTC
+	// each Monday.
+	SummaryIntervalWeekly = "weekly"
+)
+
+// summaryPeriodStart returns the start of the summary period that contains t.
+// An empty interval returns the zero time.
+func summaryPeriodStart(t time.Time, interval string) (time.Time, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case SummaryIntervalDaily:
+		return day, nil
+	case SummaryIntervalWeekly:
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysSinceMonday), nil
+	case "":
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown summary interval %q", interval)
+	}
+}
+
+// nextSummaryPeriodStart returns the start of the summary period following the
+// one that contains t.
+func nextSummaryPeriodStart(t time.Time, interval string) (time.Time, error) {
+	start, err := summaryPeriodStart(t, interval)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if interval == SummaryIntervalWeekly {
+		return start.AddDate(0, 0, 7), nil
+	}
+	return start.AddDate(0, 0, 1), nil
+}
+
+// PerformanceSummary is an aggregate of a bot's activity on a market over a
+// reporting period. All amounts are in atomic units of the asset.
+type PerformanceSummary struct {
+	Host        string `json:"host"`
+	BaseID      uint32 `json:"baseID"`
+	QuoteID     uint32 `json:"quoteID"`
+	PeriodStart int64  `json:"periodStart"`
+	PeriodEnd   int64  `json:"periodEnd"`
+	// Runs is the number of runs that overlapped with the period.
+	Runs int `json:"runs"`
+	// Uptime is the number of seconds during the period that a bot was
+	// running on the market.
+	Uptime int64 `json:"uptime"`
+	// BaseVolume and QuoteVolume are the amounts traded on the DEX.
+	BaseVolume  uint64 `json:"baseVolume"`
+	QuoteVolume uint64 `json:"quoteVolume"`
+	// CEXBaseVolume and CEXQuoteVolume are the amounts traded on the CEX.
+	CEXBaseVolume  uint64 `json:"cexBaseVolume"`
+	CEXQuoteVolume uint64 `json:"cexQuoteVolume"`
+	DEXFills       uint32 `json:"dexFills"`
+	CEXFills       uint32 `json:"cexFills"`
+	// Fees are the on-chain and transfer fees paid, keyed by asset ID.
+	Fees    map[uint32]uint64 `json:"fees"`
+	FeesUSD float64           `json:"feesUSD"`
+	// ProfitLoss is the net change in settled balances due to the bot's
+	// actions, keyed by asset ID.
+	ProfitLoss    map[uint32]int64 `json:"profitLoss"`
+	ProfitLossUSD float64          `json:"profitLossUSD"`
+	// Incidents is the number of refunds, rejected transactions and
+	// transfers initiated during the period that are still pending.
+	Incidents uint32 `json:"incidents"`
+}
+
+func newPerformanceSummary(mkt *MarketWithHost, periodStart, periodEnd int64) *PerformanceSummary {
+	return &PerformanceSummary{
+		Host:        mkt.Host,
+		BaseID:      mkt.BaseID,
+		QuoteID:     mkt.QuoteID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Fees:        make(map[uint32]uint64),
+		ProfitLoss:  make(map[uint32]int64),
+	}
+}
+
+// addRun adds the events of a run to the summary. runEnd should be the current
+// time if the run has not ended. Events outside of the summary period are
+// ignored.
+func (s *PerformanceSummary) addRun(runStart, runEnd int64, events []*MarketMakingEvent, fiatRates map[uint32]float64) {
+	if runEnd <= s.PeriodStart || runStart >= s.PeriodEnd {
+		return
+	}
+	s.Runs++
+	s.Uptime += min(runEnd, s.PeriodEnd) - max(runStart, s.PeriodStart)
+
+	addFee := func(assetID uint32, fee uint64) {
+		if fee == 0 {
+			return
+		}
+		s.Fees[assetID] += fee
+		s.FeesUSD += NewAmount(assetID, int64(fee), fiatRates[assetID]).USD
+	}
+
+	for _, e := range events {
+		if e.TimeStamp < s.PeriodStart || e.TimeStamp >= s.PeriodEnd {
+			continue
+		}
+
+		if e.BalanceEffects != nil {
+			for assetID, v := range e.BalanceEffects.Settled {
+				s.ProfitLoss[assetID] += v
+				s.ProfitLossUSD += NewAmount(assetID, v, fiatRates[assetID]).USD
+			}
+		}
+
+		switch {
+		case e.DEXOrderEvent != nil:
+			o := e.DEXOrderEvent
+			fromAsset, toAsset := s.QuoteID, s.BaseID
+			if o.Sell {
+				fromAsset, toAsset = s.BaseID, s.QuoteID
+			}
+			for _, tx := range o.Transactions {
+				if tx.Rejected {
+					s.Incidents++
+				}
+				switch tx.Type {
+				case asset.Swap:
+					s.DEXFills++
+					if o.Sell {
+						s.BaseVolume += tx.Amount
+					} else {
+						s.QuoteVolume += tx.Amount
+					}
+					addFee(feeAssetID(fromAsset), tx.Fees)
+				case asset.Redeem:
+					if o.Sell {
+						s.QuoteVolume += tx.Amount
+					} else {
+						s.BaseVolume += tx.Amount
+					}
+					addFee(feeAssetID(toAsset), tx.Fees)
+				case asset.Refund:
+					s.Incidents++
+					addFee(feeAssetID(fromAsset), tx.Fees)
+				default:
+					addFee(feeAssetID(fromAsset), tx.Fees)
+				}
+			}
+		case e.CEXOrderEvent != nil:
+			o := e.CEXOrderEvent
+			if o.BaseFilled > 0 {
+				s.CEXFills++
+			}
+			s.CEXBaseVolume += o.BaseFilled
+			s.CEXQuoteVolume += o.QuoteFilled
+		case e.DepositEvent != nil:
+			d := e.DepositEvent
+			if e.Pending {
+				s.Incidents++
+			}
+			if d.Transaction != nil {
+				if d.Transaction.Rejected {
+					s.Incidents++
+				}
+				addFee(feeAssetID(d.AssetID), d.Transaction.Fees)
+			}
+		case e.WithdrawalEvent != nil:
+			w := e.WithdrawalEvent
+			if e.Pending {
+				s.Incidents++
+			}
+			if w.Transaction != nil && w.CEXDebit > w.Transaction.Amount {
+				addFee(w.AssetID, w.CEXDebit-w.Transaction.Amount)
+			}
+		}
+	}
+}
+
+// PerformanceSummaries returns a summary for each market that had a bot
+// running between start and end, which are unix timestamps in seconds.
+func (m *MarketMaker) PerformanceSummaries(start, end int64) ([]*PerformanceSummary, error) {
+	if end <= start {
+		return nil, fmt.Errorf("end time %d is not after start time %d", end, start)
+	}
+
+	runs, err := m.eventLogDB.runs(0, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	var currentRates map[uint32]float64
+	summaries := make(map[MarketWithHost]*PerformanceSummary)
+	for _, run := range runs {
+		if run.StartTime >= end {
+			continue
+		}
+		overview, err := m.eventLogDB.runOverview(run.StartTime, run.Market)
+		if err != nil {
+			return nil, fmt.Errorf("error getting overview for run %d on %s: %w", run.StartTime, run.Market, err)
+		}
+		runEnd := now
+		if overview.EndTime != nil {
+			runEnd = *overview.EndTime
+		}
+		if runEnd <= start {
+			continue
+		}
+		events, err := m.eventLogDB.runEvents(run.StartTime, run.Market, 0, nil, false, noFilters)
+		if err != nil {
+			return nil, fmt.Errorf("error getting events for run %d on %s: %w", run.StartTime, run.Market, err)
+		}
+		s, found := summaries[*run.Market]
+		if !found {
+			s = newPerformanceSummary(run.Market, start, end)
+			summaries[*run.Market] = s
+		}
+		// Runs that are still going are valued at the current rates. Ended
+		// runs are valued at the rates recorded in their final state.
+		var fiatRates map[uint32]float64
+		if overview.EndTime != nil && overview.FinalState != nil {
+			fiatRates = overview.FinalState.FiatRates
+		}
+		if len(fiatRates) == 0 {
+			if currentRates == nil {
+				currentRates = m.core.FiatConversionRates()
+			}
+			fiatRates = currentRates
+		}
+		s.addRun(run.StartTime, runEnd, events, fiatRates)
+	}
+
+	sorted := make([]*PerformanceSummary, 0, len(summaries))
+	for _, s := range summaries {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.BaseID != b.BaseID {
+			return a.BaseID < b.BaseID
+		}
+		return a.QuoteID < b.QuoteID
+	})
+	return sorted, nil
+}
+
+var performanceSummaryCSVHeader = []string{
+	"host", "base", "quote", "periodStart", "periodEnd", "runs", "uptime",
+	"baseVolume", "quoteVolume", "cexBaseVolume", "cexQuoteVolume",
+	"dexFills", "cexFills", "feesUSD", "profitLossUSD", "incidents",
+}
+
+// WritePerformanceSummariesCSV writes the summaries to w in CSV format. Asset
+// amounts are written in atomic units.
+func WritePerformanceSummariesCSV(w io.Writer, summaries []*PerformanceSummary) error {
+	u64 := func(v uint64) string { return strconv.FormatUint(v, 10) }
+	i64 := func(v int64) string { return strconv.FormatInt(v, 10) }
+	f64 := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(performanceSummaryCSVHeader); err != nil {
+		return err
+	}
+	for _, s := range summaries {
+		if err := cw.Write([]string{
+			s.Host,
+			u64(uint64(s.BaseID)),
+			u64(uint64(s.QuoteID)),
+			i64(s.PeriodStart),
+			i64(s.PeriodEnd),
+			strconv.Itoa(s.Runs),
+			i64(s.Uptime),
+			u64(s.BaseVolume),
+			u64(s.QuoteVolume),
+			u64(s.CEXBaseVolume),
+			u64(s.CEXQuoteVolume),
+			u64(uint64(s.DEXFills)),
+			u64(uint64(s.CEXFills)),
+			f64(s.FeesUSD),
+			f64(s.ProfitLossUSD),
+			u64(uint64(s.Incidents)),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// summaryInterval returns the configured performance summary interval.
+func (m *MarketMaker) summaryInterval() string {
+	return m.defaultConfig().SummaryInterval
+}
+
+// UpdateSummaryInterval sets the interval at which performance summary
+// notifications are sent. Valid intervals are SummaryIntervalDaily and
+// SummaryIntervalWeekly. An empty interval disables the notifications.
+func (m *MarketMaker) UpdateSummaryInterval(interval string) error {
+	if _, err := summaryPeriodStart(time.Now(), interval); err != nil {
+		return err
+	}
+
+	cfg := m.defaultConfig()
+	cfg.SummaryInterval = interval
+	if err := m.writeConfigFile(cfg); err != nil {
+		return err
+	}
+
+	select {
+	case m.summaryIntervalUpdated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// runSummaryScheduler sends a performance summary notification for each
+// active market at the end of every summary period.
+func (m *MarketMaker) runSummaryScheduler(ctx context.Context) {
+	const recheckInterval = time.Hour
+	for {
+		interval := m.summaryInterval()
+		wait := recheckInterval
+		var periodEnd time.Time
+		if interval != "" {
+			var err error
+			periodEnd, err = nextSummaryPeriodStart(time.Now(), interval)
+			if err != nil {
+				m.log.Errorf("Performance summaries disabled: %v", err)
+				interval = ""
+			} else {
+				wait = time.Until(periodEnd)
+			}
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-m.summaryIntervalUpdated:
+			continue
+		case <-ctx.Done():
+			return
+		}
+
+		// The configuration may have been updated while we were waiting.
+		if interval == "" || interval != m.summaryInterval() {
+			continue
+		}
+
+		periodStart, _ := summaryPeriodStart(periodEnd.Add(-time.Second), interval)
+		summaries, err := m.PerformanceSummaries(periodStart.Unix(), periodEnd.Unix())
+		if err != nil {
+			m.log.Errorf("Error generating performance summaries: %v", err)
+			continue
+		}
+		for _, s := range summaries {
+			m.core.Broadcast(newPerformanceSummaryNote(s))
+		}
+	}
+}
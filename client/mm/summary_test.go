@@ -0,0 +1,256 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+)
+
+func TestSummaryPeriodStart(t *testing.T) {
+	// Wednesday
+	tm := time.Date(2024, 5, 15, 13, 45, 0, 0, time.UTC)
+
+	daily, err := summaryPeriodStart(tm, SummaryIntervalDaily)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC); !daily.Equal(exp) {
+		t.Fatalf("wrong daily period start. expected %s, got %s", exp, daily)
+	}
+
+	weekly, err := summaryPeriodStart(tm, SummaryIntervalWeekly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC); !weekly.Equal(exp) {
+		t.Fatalf("wrong weekly period start. expected %s, got %s", exp, weekly)
+	}
+
+	next, err := nextSummaryPeriodStart(tm, SummaryIntervalWeekly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC); !next.Equal(exp) {
+		t.Fatalf("wrong next weekly period start. expected %s, got %s", exp, next)
+	}
+
+	if _, err := summaryPeriodStart(tm, "monthly"); err == nil {
+		t.Fatalf("expected error for unknown interval")
+	}
+}
+
+func TestPerformanceSummary(t *testing.T) {
+	const baseID, quoteID = 42, 0
+	mkt := &MarketWithHost{Host: "dex.com", BaseID: baseID, QuoteID: quoteID}
+	const periodStart, periodEnd = 1000, 2000
+
+	events := []*MarketMakingEvent{
+		{ // before the period
+			TimeStamp: 900,
+			DEXOrderEvent: &DEXOrderEvent{
+				Sell: true,
+				Transactions: []*asset.WalletTransaction{
+					{Type: asset.Swap, Amount: 1e8, Fees: 1000},
+				},
+			},
+		},
+		{
+			TimeStamp: 1100,
+			DEXOrderEvent: &DEXOrderEvent{
+				Sell: true,
+				Transactions: []*asset.WalletTransaction{
+					{Type: asset.Swap, Amount: 2e8, Fees: 2000},
+					{Type: asset.Redeem, Amount: 3e6, Fees: 300},
+				},
+			},
+			BalanceEffects: &BalanceEffects{
+				Settled: map[uint32]int64{baseID: -2e8 - 2000, quoteID: 3e6 - 300},
+			},
+		},
+		{
+			TimeStamp: 1200,
+			DEXOrderEvent: &DEXOrderEvent{
+				Transactions: []*asset.WalletTransaction{
+					{Type: asset.Swap, Amount: 4e6, Fees: 400},
+					{Type: asset.Refund, Amount: 4e6, Fees: 500},
+				},
+			},
+		},
+		{
+			TimeStamp: 1300,
+			CEXOrderEvent: &CEXOrderEvent{
+				BaseFilled:  5e8,
+				QuoteFilled: 6e6,
+			},
+		},
+		{
+			TimeStamp: 1400,
+			Pending:   true,
+			WithdrawalEvent: &WithdrawalEvent{
+				AssetID:     baseID,
+				CEXDebit:    1e8,
+				Transaction: &asset.WalletTransaction{Amount: 1e8 - 5000},
+			},
+		},
+	}
+
+	s := newPerformanceSummary(mkt, periodStart, periodEnd)
+	// This run does not overlap with the period.
+	s.addRun(100, 500, nil, nil)
+	s.addRun(800, 1500, events, map[uint32]float64{baseID: 10, quoteID: 50000})
+
+	if s.Runs != 1 {
+		t.Fatalf("expected 1 run, got %d", s.Runs)
+	}
+	if s.Uptime != 500 {
+		t.Fatalf("expected uptime 500, got %d", s.Uptime)
+	}
+	if s.BaseVolume != 2e8 {
+		t.Fatalf("expected base volume 2e8, got %d", s.BaseVolume)
+	}
+	if s.QuoteVolume != 7e6 {
+		t.Fatalf("expected quote volume 7e6, got %d", s.QuoteVolume)
+	}
+	if s.DEXFills != 2 || s.CEXFills != 1 {
+		t.Fatalf("expected 2 dex fills and 1 cex fill, got %d and %d", s.DEXFills, s.CEXFills)
+	}
+	if s.CEXBaseVolume != 5e8 || s.CEXQuoteVolume != 6e6 {
+		t.Fatalf("wrong cex volume %d / %d", s.CEXBaseVolume, s.CEXQuoteVolume)
+	}
+	if s.Fees[baseID] != 2000+5000 {
+		t.Fatalf("expected base fees 7000, got %d", s.Fees[baseID])
+	}
+	if s.Fees[quoteID] != 300+400+500 {
+		t.Fatalf("expected quote fees 1200, got %d", s.Fees[quoteID])
+	}
+	if s.ProfitLoss[baseID] != -2e8-2000 || s.ProfitLoss[quoteID] != 3e6-300 {
+		t.Fatalf("wrong profit loss %v", s.ProfitLoss)
+	}
+	// One refund and one pending withdrawal.
+	if s.Incidents != 2 {
+		t.Fatalf("expected 2 incidents, got %d", s.Incidents)
+	}
+
+	var buf bytes.Buffer
+	if err := WritePerformanceSummariesCSV(&buf, []*PerformanceSummary{s}); err != nil {
+		t.Fatalf("error writing csv: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading csv: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 csv records, got %d", len(records))
+	}
+	if len(records[1]) != len(performanceSummaryCSVHeader) {
+		t.Fatalf("expected %d columns, got %d", len(performanceSummaryCSVHeader), len(records[1]))
+	}
+}
+
+func TestPerformanceSummariesFiatRates(t *testing.T) {
+	const baseID, quoteID = 42, 0
+	mkt := &MarketWithHost{Host: "dex.com", BaseID: baseID, QuoteID: quoteID}
+	now := time.Now().Unix()
+	const endedStart, runningStart = 1000, 2000
+	endTime := int64(1500)
+
+	settled := func(ts int64) []*MarketMakingEvent {
+		return []*MarketMakingEvent{{
+			TimeStamp:      ts,
+			BalanceEffects: &BalanceEffects{Settled: map[uint32]int64{baseID: 1e8}},
+		}}
+	}
+
+	db := newTEventLogDB()
+	db.storedRuns = []*MarketMakingRun{
+		{StartTime: endedStart, Market: mkt},
+		{StartTime: runningStart, Market: mkt},
+	}
+	db.overviews = map[int64]*MarketMakingRunOverview{
+		endedStart: {
+			EndTime:    &endTime,
+			FinalState: &BalanceState{FiatRates: map[uint32]float64{baseID: 10}},
+		},
+		// A running bot has no end time, and its stored state may not have
+		// any rates yet.
+		runningStart: {FinalState: &BalanceState{}},
+	}
+	db.runEvts = map[int64][]*MarketMakingEvent{
+		endedStart:   settled(1100),
+		runningStart: settled(2100),
+	}
+
+	tc := newTCore()
+	tc.fiatRates = map[uint32]float64{baseID: 20}
+	m := &MarketMaker{core: tc, eventLogDB: db, log: tLogger}
+
+	summaries, err := m.PerformanceSummaries(0, now+1)
+	if err != nil {
+		t.Fatalf("PerformanceSummaries error: %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 summary, got %d", len(summaries))
+	}
+	s := summaries[0]
+	if s.Runs != 2 {
+		t.Fatalf("expected 2 runs, got %d", s.Runs)
+	}
+	// 1 DCR at the ended run's rate of 10 plus 1 DCR at the current rate of
+	// 20.
+	if s.ProfitLossUSD != 30 {
+		t.Fatalf("expected profit/loss of 30 USD, got %f", s.ProfitLossUSD)
+	}
+}
+
+func TestUpdateSummaryInterval(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "mm.conf")
+	m, err := NewMarketMaker(newTCore(), "", cfgPath, tLogger)
+	if err != nil {
+		t.Fatalf("NewMarketMaker error: %v", err)
+	}
+
+	if err := m.UpdateSummaryInterval("monthly"); err == nil {
+		t.Fatalf("no error for unknown interval")
+	}
+
+	if err := m.UpdateSummaryInterval(SummaryIntervalWeekly); err != nil {
+		t.Fatalf("UpdateSummaryInterval error: %v", err)
+	}
+	if m.summaryInterval() != SummaryIntervalWeekly {
+		t.Fatalf("interval not updated")
+	}
+	b, err := os.ReadFile(cfgPath)
+	if err != nil {
+		t.Fatalf("error reading config file: %v", err)
+	}
+	var cfg MarketMakingConfig
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		t.Fatalf("error decoding config file: %v", err)
+	}
+	if cfg.SummaryInterval != SummaryIntervalWeekly {
+		t.Fatalf("interval not saved, got %q", cfg.SummaryInterval)
+	}
+
+	// The scheduler should be woken.
+	select {
+	case <-m.summaryIntervalUpdated:
+	default:
+		t.Fatalf("scheduler not signaled")
+	}
+
+	if err := m.UpdateSummaryInterval(""); err != nil {
+		t.Fatalf("error disabling summaries: %v", err)
+	}
+	if m.summaryInterval() != "" {
+		t.Fatalf("summaries not disabled")
+	}
+}
@@ -0,0 +1,140 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+// ThrottleConfig limits the rate at which a bot submits orders and
+// cancellations to the DEX, so that aggressive configurations do not trip
+// server-side limits. A zero value for any limit means there is no limit.
+// Cancellations are always given priority over new orders.
+type ThrottleConfig struct {
+	// MaxOrdersPerEpoch is the maximum number of orders that will be placed
+	// in a single epoch.
+	MaxOrdersPerEpoch uint32 `json:"maxOrdersPerEpoch"`
+	// MaxCancelsPerEpoch is the maximum number of cancellations that will be
+	// submitted in a single epoch. Cancellations that exceed the limit are
+	// queued and submitted in following epochs.
+	MaxCancelsPerEpoch uint32 `json:"maxCancelsPerEpoch"`
+	// MaxActionsPerMinute is the maximum number of orders and cancellations
+	// combined that will be submitted in any 60 second window.
+	MaxActionsPerMinute uint32 `json:"maxActionsPerMinute"`
+}
+
+func (c *ThrottleConfig) copy() *ThrottleConfig {
+	if c == nil {
+		return nil
+	}
+	cp := *c
+	return &cp
+}
+
+// actionThrottle tracks the orders and cancellations submitted by a bot and
+// enforces the limits in a ThrottleConfig. The zero value is ready to use.
+type actionThrottle struct {
+	mtx          sync.Mutex
+	epoch        uint64
+	epochOrders  uint32
+	epochCancels uint32
+	// recent are the times of the actions taken in the last minute, oldest
+	// first.
+	recent []time.Time
+	// queuedCancels are the IDs of orders that should have been cancelled,
+	// but were not due to the throttle, oldest first.
+	queuedCancels []dex.Bytes
+}
+
+// allow returns whether an order, or a cancellation if cancel is true, may be
+// submitted in the epoch. If so, the action is counted against the limits.
+// An epoch of zero indicates that the epoch is not known, and only the per
+// minute limit is applied.
+func (t *actionThrottle) allow(cfg *ThrottleConfig, epoch uint64, cancel bool, now time.Time) bool {
+	if cfg == nil {
+		return true
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if epoch > t.epoch {
+		t.epoch = epoch
+		t.epochOrders, t.epochCancels = 0, 0
+	}
+
+	cutoff := now.Add(-time.Minute)
+	var expired int
+	for expired < len(t.recent) && !t.recent[expired].After(cutoff) {
+		expired++
+	}
+	t.recent = t.recent[expired:]
+
+	if cfg.MaxActionsPerMinute > 0 && uint32(len(t.recent)) >= cfg.MaxActionsPerMinute {
+		return false
+	}
+	if epoch > 0 {
+		if cancel {
+			if cfg.MaxCancelsPerEpoch > 0 && t.epochCancels >= cfg.MaxCancelsPerEpoch {
+				return false
+			}
+			t.epochCancels++
+		} else {
+			if cfg.MaxOrdersPerEpoch > 0 && t.epochOrders >= cfg.MaxOrdersPerEpoch {
+				return false
+			}
+			t.epochOrders++
+		}
+	}
+	t.recent = append(t.recent, now)
+	return true
+}
+
+// queueCancels adds order IDs to the cancel queue, ignoring any that are
+// already queued.
+func (t *actionThrottle) queueCancels(oids []dex.Bytes) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for _, oid := range oids {
+		if !containsOrderID(t.queuedCancels, oid) {
+			t.queuedCancels = append(t.queuedCancels, oid)
+		}
+	}
+}
+
+// prioritizeCancels empties the cancel queue and returns the queued
+// cancellations followed by the new ones, without duplicates. Queued
+// cancellations for which stillBooked returns false are dropped.
+func (t *actionThrottle) prioritizeCancels(newCancels []dex.Bytes, stillBooked func(dex.Bytes) bool) []dex.Bytes {
+	t.mtx.Lock()
+	queued := t.queuedCancels
+	t.queuedCancels = nil
+	t.mtx.Unlock()
+
+	cancels := make([]dex.Bytes, 0, len(queued)+len(newCancels))
+	for _, oid := range queued {
+		if stillBooked(oid) {
+			cancels = append(cancels, oid)
+		}
+	}
+	for _, oid := range newCancels {
+		if !containsOrderID(cancels, oid) {
+			cancels = append(cancels, oid)
+		}
+	}
+	return cancels
+}
+
+func containsOrderID(oids []dex.Bytes, oid dex.Bytes) bool {
+	for _, id := range oids {
+		if bytes.Equal(id, oid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mm
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+)
+
+func TestActionThrottle(t *testing.T) {
+	var throttle actionThrottle
+	now := time.Now()
+
+	// No config means no limits.
+	for i := 0; i < 100; i++ {
+		if !throttle.allow(nil, 1, false, now) {
+			t.Fatalf("throttled without a config")
+		}
+	}
+
+	cfg := &ThrottleConfig{
+		MaxOrdersPerEpoch:   2,
+		MaxCancelsPerEpoch:  1,
+		MaxActionsPerMinute: 4,
+	}
+
+	if !throttle.allow(cfg, 2, true, now) {
+		t.Fatalf("first cancel throttled")
+	}
+	if throttle.allow(cfg, 2, true, now) {
+		t.Fatalf("second cancel not throttled")
+	}
+	if !throttle.allow(cfg, 2, false, now) || !throttle.allow(cfg, 2, false, now) {
+		t.Fatalf("orders throttled")
+	}
+	if throttle.allow(cfg, 2, false, now) {
+		t.Fatalf("third order not throttled")
+	}
+
+	// New epoch resets the epoch limits, but the per minute limit is reached
+	// after one more action.
+	if !throttle.allow(cfg, 3, false, now) {
+		t.Fatalf("order in new epoch throttled")
+	}
+	if throttle.allow(cfg, 3, true, now) {
+		t.Fatalf("per minute limit not enforced")
+	}
+
+	// A minute later, actions are allowed again.
+	if !throttle.allow(cfg, 4, true, now.Add(time.Minute+time.Second)) {
+		t.Fatalf("cancel throttled after a minute")
+	}
+}
+
+func TestPrioritizeCancels(t *testing.T) {
+	var throttle actionThrottle
+	a, b, c := dex.Bytes{1}, dex.Bytes{2}, dex.Bytes{3}
+
+	throttle.queueCancels([]dex.Bytes{a, b})
+	throttle.queueCancels([]dex.Bytes{a})
+
+	booked := func(oid dex.Bytes) bool { return oid[0] != b[0] }
+	cancels := throttle.prioritizeCancels([]dex.Bytes{c, a}, booked)
+	if len(cancels) != 2 || cancels[0][0] != a[0] || cancels[1][0] != c[0] {
+		t.Fatalf("wrong cancels %v", cancels)
+	}
+
+	if cancels = throttle.prioritizeCancels(nil, booked); len(cancels) != 0 {
+		t.Fatalf("queue not emptied")
+	}
+}
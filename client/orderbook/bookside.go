@@ -27,6 +27,37 @@ type Fill struct {
 	Quantity uint64
 }
 
+// Depth is the liquidity available on one side of the book, starting from
+// the best order and continuing until a limit is reached.
+type Depth struct {
+	// VWAP is the volume-weighted average rate of the Lots.
+	VWAP uint64
+	// Extrema is the rate of the worst order needed to fill the Lots.
+	Extrema uint64
+	// Lots is the number of lots available, up to the limit.
+	Lots uint64
+	// Quantity is the quantity of the Lots, in units of the base asset.
+	Quantity uint64
+	// QuoteQty is the quantity of the Lots, in units of the quote asset.
+	QuoteQty uint64
+	// Filled is true if the limit was reached before the book was
+	// exhausted.
+	Filled bool
+}
+
+// DepthLevel is the aggregated quantity of the booked orders in a price bin.
+type DepthLevel struct {
+	// Rate is the bin's edge nearest the best rate. The bin covers rates from
+	// Rate up to, but not including, one bin width farther from the best
+	// rate.
+	Rate uint64 `json:"rate"`
+	// Quantity is the total quantity of the bin's orders, in units of the
+	// base asset.
+	Quantity uint64 `json:"qty"`
+	// Orders is the number of orders in the bin.
+	Orders int `json:"orders"`
+}
+
 // bookSide represents a side of the order book.
 type bookSide struct {
 	bins      map[uint64][]*Order
@@ -224,6 +255,98 @@ func (d *bookSide) bestFill(quantity uint64, convert bool, lotSize uint64) ([]*F
 	return best, remainingQty == 0
 }
 
+// depth is the Depth of the book side up to maxLots lots or maxQuote units of
+// the quote asset, whichever is reached first. A zero limit is ignored. Only
+// whole lots are counted.
+func (d *bookSide) depth(lotSize, maxLots, maxQuote uint64) *Depth {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	dep := new(Depth)
+	var weightedSum uint64
+	d.iterateOrders(func(ord *Order) bool {
+		lots := ord.Quantity / lotSize
+		if maxLots > 0 && lots >= maxLots-dep.Lots {
+			lots = maxLots - dep.Lots
+			dep.Filled = true
+		}
+		if maxQuote > 0 {
+			if affordable := calc.QuoteToBase(ord.Rate, maxQuote-dep.QuoteQty) / lotSize; affordable < lots {
+				lots = affordable
+				dep.Filled = true
+			}
+		}
+		if lots > 0 {
+			qty := lots * lotSize
+			dep.Lots += lots
+			dep.Quantity += qty
+			dep.QuoteQty += calc.BaseToQuote(ord.Rate, qty)
+			dep.Extrema = ord.Rate
+			weightedSum += lots * ord.Rate
+		}
+		if maxQuote > 0 && dep.QuoteQty >= maxQuote {
+			dep.Filled = true
+		}
+		return !dep.Filled
+	})
+	if dep.Lots > 0 {
+		dep.VWAP = weightedSum / dep.Lots
+	}
+	return dep
+}
+
+// aggregate bins the book side into levels of the specified width, starting
+// at the best rate. Empty levels are included. Fewer levels are returned if
+// they would extend below a zero rate.
+func (d *bookSide) aggregate(levels int, width uint64) []*DepthLevel {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+
+	out := make([]*DepthLevel, 0, levels)
+	var best uint64
+	d.iterateOrders(func(ord *Order) bool {
+		if len(out) == 0 {
+			best = ord.Rate
+		}
+		var dist uint64
+		if d.orderPref == ascending {
+			dist = ord.Rate - best
+		} else {
+			dist = best - ord.Rate
+		}
+		i := int(dist / width)
+		if i >= levels {
+			return false
+		}
+		for len(out) <= i {
+			n := uint64(len(out))
+			rate := best + n*width
+			if d.orderPref == descending {
+				rate = best - n*width
+			}
+			out = append(out, &DepthLevel{Rate: rate})
+		}
+		out[i].Quantity += ord.Quantity
+		out[i].Orders++
+		return true
+	})
+	if len(out) == 0 {
+		return out
+	}
+	// Fill in the empty levels past the worst order.
+	for n := uint64(len(out)); len(out) < levels; n++ {
+		if d.orderPref == ascending {
+			out = append(out, &DepthLevel{Rate: best + n*width})
+			continue
+		}
+		if n*width > best {
+			break
+		}
+		out = append(out, &DepthLevel{Rate: best - n*width})
+	}
+	return out
+}
+
 func (d *bookSide) idxCalculator() func(i int) int {
 	if d.orderPref == ascending {
 		return func(i int) int { return i }
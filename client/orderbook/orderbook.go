@@ -6,8 +6,10 @@ package orderbook
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
@@ -18,6 +20,10 @@ import (
 // ErrEmptyOrderbook is returned from MidGap when the order book is empty.
 const ErrEmptyOrderbook = dex.ErrorKind("cannot calculate mid-gap from empty order book")
 
+// ErrBookMismatch is returned by VerifyChecksum when the book does not match
+// the server's.
+const ErrBookMismatch = dex.ErrorKind("order book does not match server")
+
 // Order represents an ask or bid.
 type Order struct {
 	OrderID  order.OrderID
@@ -102,6 +108,8 @@ type OrderBook struct {
 
 	matchSummaryMtx sync.Mutex
 	matchesSummary  []*MatchSummary
+
+	recorder atomic.Pointer[Recorder]
 }
 
 // NewOrderBook creates a new order book.
@@ -320,6 +328,10 @@ func (ob *OrderBook) Reset(snapshot *msgjson.OrderBook) error {
 
 	ob.setSynced(true)
 
+	if r := ob.recorder.Load(); r != nil {
+		ob.recordSnapshot(r, time.Now())
+	}
+
 	return nil
 }
 
@@ -376,7 +388,9 @@ func (ob *OrderBook) book(note *msgjson.BookOrderNote, cached bool) error {
 
 // Book adds a new order to the order book.
 func (ob *OrderBook) Book(note *msgjson.BookOrderNote) error {
-	return ob.book(note, false)
+	return ob.recorded(msgjson.BookOrderRoute, note, func() error {
+		return ob.book(note, false)
+	})
 }
 
 // updateRemaining is the workhorse of the exported UpdateRemaining function. It
@@ -420,7 +434,9 @@ func (ob *OrderBook) updateRemaining(note *msgjson.UpdateRemainingNote, cached b
 
 // UpdateRemaining updates the remaining quantity of a booked order.
 func (ob *OrderBook) UpdateRemaining(note *msgjson.UpdateRemainingNote) error {
-	return ob.updateRemaining(note, false)
+	return ob.recorded(msgjson.UpdateRemainingRoute, note, func() error {
+		return ob.updateRemaining(note, false)
+	})
 }
 
 // LogEpochReport is currently a no-op, and will update market history charts in
@@ -432,6 +448,38 @@ func (ob *OrderBook) LogEpochReport(note *msgjson.EpochReportNote) error {
 	return nil
 }
 
+// VerifyChecksum checks that the book matches the server's book, which had the
+// order.BookChecksum csum after the update with sequence number seq. If the
+// book is not synced, or was reset to a snapshot taken after seq, nil is
+// returned since the books can't be compared. If the book has not received
+// every update through seq, or the checksum does not match, an error wrapping
+// ErrBookMismatch is returned.
+func (ob *OrderBook) VerifyChecksum(seq uint64, csum []byte) error {
+	if !ob.isSynced() {
+		return nil
+	}
+	// The caller is expected to process book notes and the epoch report
+	// sequentially, so the book is not modified while it is checked.
+	ob.seqMtx.Lock()
+	defer ob.seqMtx.Unlock()
+	if ob.seq > seq {
+		return nil
+	}
+	if ob.seq < seq {
+		return fmt.Errorf("%w: at sequence %d, server at %d", ErrBookMismatch, ob.seq, seq)
+	}
+	var localCsum order.BookChecksum
+	for _, side := range []*bookSide{ob.buys, ob.sells} {
+		for _, o := range side.Orders() {
+			localCsum.Add(o.OrderID, o.Quantity)
+		}
+	}
+	if !bytes.Equal(localCsum[:], csum) {
+		return fmt.Errorf("%w: checksum mismatch at sequence %d", ErrBookMismatch, seq)
+	}
+	return nil
+}
+
 // unbook is the workhorse of the exported Unbook function. It allows unbooking
 // cached and uncached order notes.
 func (ob *OrderBook) unbook(note *msgjson.UnbookOrderNote, cached bool) error {
@@ -473,7 +521,23 @@ func (ob *OrderBook) unbook(note *msgjson.UnbookOrderNote, cached bool) error {
 
 // Unbook removes an order from the order book.
 func (ob *OrderBook) Unbook(note *msgjson.UnbookOrderNote) error {
-	return ob.unbook(note, false)
+	return ob.recorded(msgjson.UnbookOrderRoute, note, func() error {
+		return ob.unbook(note, false)
+	})
+}
+
+// recorded runs the update and records the note if the book has a Recorder.
+// Notes that are cached while the book is unsynced are not recorded, since
+// they are included in the snapshot recorded when the book is synced.
+func (ob *OrderBook) recorded(route string, note any, update func() error) error {
+	synced := ob.isSynced()
+	if err := update(); err != nil {
+		return err
+	}
+	if synced {
+		ob.recordUpdate(route, note)
+	}
+	return nil
 }
 
 // BestNOrders returns the best n orders from the provided side.
@@ -530,30 +594,81 @@ func (ob *OrderBook) OrderIsBooked(oid order.OrderID, sell bool) bool {
 // VWAP calculates the volume weighted average price for the specified number
 // of lots.
 func (ob *OrderBook) VWAP(lots, lotSize uint64, sell bool) (avg, extrema uint64, filled bool, err error) {
-	orders, _, err := ob.BestNOrders(int(lots), sell)
+	if lots == 0 {
+		return 0, 0, false, nil
+	}
+	dep, err := ob.DepthLots(lots, lotSize, sell)
 	if err != nil {
 		return 0, 0, false, err
 	}
 
-	remainingLots := lots
-	var weightedSum uint64
-	for _, order := range orders {
-		extrema = order.Rate
-		lotsInOrder := order.Quantity / lotSize
-		if lotsInOrder >= remainingLots {
-			weightedSum += remainingLots * extrema
-			filled = true
-			break
-		}
-		remainingLots -= lotsInOrder
-		weightedSum += lotsInOrder * extrema
+	if !dep.Filled {
+		return 0, 0, false, nil
 	}
 
-	if !filled {
-		return 0, 0, false, nil
+	return dep.VWAP, dep.Extrema, true, nil
+}
+
+// DepthLots returns the volume weighted average price and the quantity
+// available within the specified number of lots from the best order on the
+// sell or buy side of the book. If the book does not have that many lots,
+// the Depth covers the whole side and Filled is false.
+func (ob *OrderBook) DepthLots(lots, lotSize uint64, sell bool) (*Depth, error) {
+	if lots == 0 || lotSize == 0 {
+		return nil, fmt.Errorf("zero lots or lot size")
+	}
+	return ob.depth(lotSize, lots, 0, sell)
+}
+
+// DepthQuote returns the volume weighted average price and the quantity
+// available, in whole lots, that can be bought or sold for up to quoteQty
+// units of the quote asset, starting from the best order on the sell or buy
+// side of the book. If the book is exhausted first, Filled is false.
+func (ob *OrderBook) DepthQuote(quoteQty, lotSize uint64, sell bool) (*Depth, error) {
+	if quoteQty == 0 || lotSize == 0 {
+		return nil, fmt.Errorf("zero quote quantity or lot size")
+	}
+	return ob.depth(lotSize, 0, quoteQty, sell)
+}
+
+// AggregatedDepth returns the depth of the sell or buy side of the book binned
+// into the number of price levels, starting at the best rate. binWidth is the
+// width of each level as a fraction of the best rate, e.g. 10 levels with a
+// binWidth of 0.001 are ten 0.1% levels. Levels without orders are included,
+// so that the levels are evenly spaced. An empty book side has no levels.
+func (ob *OrderBook) AggregatedDepth(sell bool, levels int, binWidth float64) ([]*DepthLevel, error) {
+	if levels <= 0 {
+		return nil, fmt.Errorf("invalid number of levels %d", levels)
 	}
+	if !(binWidth > 0 && binWidth < 1) {
+		return nil, fmt.Errorf("invalid bin width %f", binWidth)
+	}
+	if !ob.isSynced() {
+		return nil, fmt.Errorf("order book is unsynced")
+	}
+	side := ob.buys
+	if sell {
+		side = ob.sells
+	}
+	best, _ := side.BestNOrders(1)
+	if len(best) == 0 {
+		return []*DepthLevel{}, nil
+	}
+	width := uint64(math.Round(float64(best[0].Rate) * binWidth))
+	if width == 0 {
+		width = 1
+	}
+	return side.aggregate(levels, width), nil
+}
 
-	return weightedSum / lots, extrema, true, nil
+func (ob *OrderBook) depth(lotSize, maxLots, maxQuote uint64, sell bool) (*Depth, error) {
+	if !ob.isSynced() {
+		return nil, fmt.Errorf("order book is unsynced")
+	}
+	if sell {
+		return ob.sells.depth(lotSize, maxLots, maxQuote), nil
+	}
+	return ob.buys.depth(lotSize, maxLots, maxQuote), nil
 }
 
 // Orders is the full order book, as slices of sorted buys and sells, and
@@ -3,6 +3,7 @@ package orderbook
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"testing"
 
 	"decred.org/dcrdex/dex/msgjson"
@@ -1051,6 +1052,229 @@ func TestVWAP(t *testing.T) {
 	}
 }
 
+func TestDepth(t *testing.T) {
+	const lotSize = 10
+	orders := []*Order{
+		// buys
+		makeOrder([32]byte{'b'}, msgjson.BuyOrderNum, 20, 150e6, 2),
+		makeOrder([32]byte{'c'}, msgjson.BuyOrderNum, 10, 100e6, 2),
+
+		// sells
+		makeOrder([32]byte{'d'}, msgjson.SellOrderNum, 20, 200e6, 2),
+		makeOrder([32]byte{'e'}, msgjson.SellOrderNum, 20, 300e6, 2),
+		makeOrder([32]byte{'f'}, msgjson.SellOrderNum, 10, 400e6, 2),
+	}
+
+	ob := makeOrderBook(1, "ob", orders, make([]*cachedOrderNote, 0), true)
+
+	tests := []struct {
+		name     string
+		sell     bool
+		lots     uint64
+		quoteQty uint64
+		exp      *Depth
+	}{{
+		name: "lots within first order",
+		sell: true,
+		lots: 1,
+		exp:  &Depth{VWAP: 200e6, Extrema: 200e6, Lots: 1, Quantity: 10, QuoteQty: 20, Filled: true},
+	}, {
+		name: "lots across orders",
+		sell: true,
+		lots: 3,
+		exp:  &Depth{VWAP: 233333333, Extrema: 300e6, Lots: 3, Quantity: 30, QuoteQty: 70, Filled: true},
+	}, {
+		name: "lots exhaust book",
+		sell: true,
+		lots: 6,
+		exp:  &Depth{VWAP: 280e6, Extrema: 400e6, Lots: 5, Quantity: 50, QuoteQty: 140},
+	}, {
+		name:     "quote at order boundary",
+		sell:     true,
+		quoteQty: 100,
+		exp:      &Depth{VWAP: 250e6, Extrema: 300e6, Lots: 4, Quantity: 40, QuoteQty: 100, Filled: true},
+	}, {
+		name:     "quote within order",
+		sell:     true,
+		quoteQty: 75,
+		exp:      &Depth{VWAP: 233333333, Extrema: 300e6, Lots: 3, Quantity: 30, QuoteQty: 70, Filled: true},
+	}, {
+		name:     "quote less than a lot",
+		sell:     true,
+		quoteQty: 10,
+		exp:      &Depth{Filled: true},
+	}, {
+		name:     "quote exhausts book",
+		sell:     true,
+		quoteQty: 1000,
+		exp:      &Depth{VWAP: 280e6, Extrema: 400e6, Lots: 5, Quantity: 50, QuoteQty: 140},
+	}, {
+		name: "buy lots",
+		lots: 2,
+		exp:  &Depth{VWAP: 150e6, Extrema: 150e6, Lots: 2, Quantity: 20, QuoteQty: 30, Filled: true},
+	}, {
+		name:     "buy quote",
+		quoteQty: 40,
+		exp:      &Depth{VWAP: 133333333, Extrema: 100e6, Lots: 3, Quantity: 30, QuoteQty: 40, Filled: true},
+	}}
+
+	for _, tt := range tests {
+		var dep *Depth
+		var err error
+		if tt.lots > 0 {
+			dep, err = ob.DepthLots(tt.lots, lotSize, tt.sell)
+		} else {
+			dep, err = ob.DepthQuote(tt.quoteQty, lotSize, tt.sell)
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if *dep != *tt.exp {
+			t.Fatalf("%s: expected %+v, got %+v", tt.name, tt.exp, dep)
+		}
+	}
+
+	if _, err := ob.DepthLots(0, lotSize, true); err == nil {
+		t.Fatalf("no error for zero lots")
+	}
+	if _, err := ob.DepthQuote(0, lotSize, true); err == nil {
+		t.Fatalf("no error for zero quote quantity")
+	}
+}
+
+func TestAggregatedDepth(t *testing.T) {
+	orders := []*Order{
+		// buys
+		makeOrder([32]byte{'a'}, msgjson.BuyOrderNum, 10, 99e6, 2),
+		makeOrder([32]byte{'b'}, msgjson.BuyOrderNum, 20, 98_950_000, 2),
+		makeOrder([32]byte{'c'}, msgjson.BuyOrderNum, 30, 98_800_000, 2),
+
+		// sells
+		makeOrder([32]byte{'d'}, msgjson.SellOrderNum, 10, 100e6, 2),
+		makeOrder([32]byte{'e'}, msgjson.SellOrderNum, 20, 100_050_000, 2),
+		makeOrder([32]byte{'f'}, msgjson.SellOrderNum, 30, 100_250_000, 2),
+		makeOrder([32]byte{'g'}, msgjson.SellOrderNum, 40, 101e6, 2),
+	}
+	ob := makeOrderBook(1, "ob", orders, make([]*cachedOrderNote, 0), true)
+
+	tests := []struct {
+		name     string
+		sell     bool
+		levels   int
+		binWidth float64
+		exp      []DepthLevel
+	}{{
+		name:     "sells",
+		sell:     true,
+		levels:   5,
+		binWidth: 0.001,
+		exp: []DepthLevel{
+			{Rate: 100e6, Quantity: 30, Orders: 2},
+			{Rate: 100_100_000},
+			{Rate: 100_200_000, Quantity: 30, Orders: 1},
+			{Rate: 100_300_000},
+			{Rate: 100_400_000},
+		},
+	}, {
+		name:     "buys",
+		levels:   3,
+		binWidth: 0.001,
+		exp: []DepthLevel{
+			{Rate: 99e6, Quantity: 30, Orders: 2},
+			{Rate: 98_901_000},
+			{Rate: 98_802_000, Quantity: 30, Orders: 1},
+		},
+	}, {
+		name:     "one wide level",
+		sell:     true,
+		levels:   1,
+		binWidth: 0.1,
+		exp: []DepthLevel{
+			{Rate: 100e6, Quantity: 100, Orders: 4},
+		},
+	}}
+	for _, tt := range tests {
+		levels, err := ob.AggregatedDepth(tt.sell, tt.levels, tt.binWidth)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(levels) != len(tt.exp) {
+			t.Fatalf("%s: expected %d levels, got %d", tt.name, len(tt.exp), len(levels))
+		}
+		for i, lvl := range levels {
+			if *lvl != tt.exp[i] {
+				t.Fatalf("%s: level %d: expected %+v, got %+v", tt.name, i, tt.exp[i], *lvl)
+			}
+		}
+	}
+
+	// Levels don't extend below a zero rate.
+	ob = makeOrderBook(1, "ob", []*Order{makeOrder([32]byte{'a'}, msgjson.BuyOrderNum, 10, 3, 2)},
+		make([]*cachedOrderNote, 0), true)
+	levels, err := ob.AggregatedDepth(false, 5, 0.5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(levels) != 2 || levels[0].Rate != 3 || levels[1].Rate != 1 {
+		t.Fatalf("wrong levels for low rate book")
+	}
+
+	// Empty side.
+	if levels, err := ob.AggregatedDepth(true, 5, 0.5); err != nil || len(levels) != 0 {
+		t.Fatalf("expected no levels for empty side, got %d, err = %v", len(levels), err)
+	}
+
+	if _, err := ob.AggregatedDepth(true, 0, 0.001); err == nil {
+		t.Fatalf("no error for zero levels")
+	}
+	if _, err := ob.AggregatedDepth(true, 5, 0); err == nil {
+		t.Fatalf("no error for zero bin width")
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	orders := []*Order{
+		makeOrder([32]byte{'b'}, msgjson.BuyOrderNum, 20, 150e6, 2),
+		makeOrder([32]byte{'c'}, msgjson.BuyOrderNum, 10, 100e6, 2),
+		makeOrder([32]byte{'d'}, msgjson.SellOrderNum, 20, 200e6, 2),
+	}
+	ob := makeOrderBook(5, "ob", orders, make([]*cachedOrderNote, 0), true)
+
+	// The server adds the orders in a different order.
+	var csum order.BookChecksum
+	for i := len(orders) - 1; i >= 0; i-- {
+		csum.Add(orders[i].OrderID, orders[i].Quantity)
+	}
+	if err := ob.VerifyChecksum(5, csum[:]); err != nil {
+		t.Fatalf("unexpected error for matching book: %v", err)
+	}
+
+	// The book was reset to a later snapshot.
+	if err := ob.VerifyChecksum(4, nil); err != nil {
+		t.Fatalf("unexpected error for older checksum: %v", err)
+	}
+
+	// Missed updates.
+	if err := ob.VerifyChecksum(6, csum[:]); !errors.Is(err, ErrBookMismatch) {
+		t.Fatalf("expected ErrBookMismatch for missed update, got %v", err)
+	}
+
+	// The server's book has a different remaining quantity.
+	var badCsum order.BookChecksum
+	badCsum.Add(orders[0].OrderID, orders[0].Quantity)
+	badCsum.Add(orders[1].OrderID, orders[1].Quantity-1)
+	badCsum.Add(orders[2].OrderID, orders[2].Quantity)
+	if err := ob.VerifyChecksum(5, badCsum[:]); !errors.Is(err, ErrBookMismatch) {
+		t.Fatalf("expected ErrBookMismatch for wrong quantity, got %v", err)
+	}
+
+	// Unsynced books are not checked.
+	ob.setSynced(false)
+	if err := ob.VerifyChecksum(5, badCsum[:]); err != nil {
+		t.Fatalf("unexpected error for unsynced book: %v", err)
+	}
+}
+
 func TestValidateMatchProof(t *testing.T) {
 	mid := "mkt"
 	ob := NewOrderBook(tLogger)
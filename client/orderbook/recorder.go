@@ -0,0 +1,245 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package orderbook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+)
+
+// SnapshotRoute is the Route of a Record holding a snapshot of the book, as a
+// *msgjson.OrderBook.
+const SnapshotRoute = "snapshot"
+
+// Record is an entry in a book recording. Updates have the route of the note
+// that was applied to the book, i.e. msgjson.BookOrderRoute,
+// msgjson.UnbookOrderRoute, or msgjson.UpdateRemainingRoute, and the note as
+// the Payload.
+type Record struct {
+	// Stamp is the time the record was made, in unix milliseconds.
+	Stamp   uint64          `json:"stamp"`
+	Route   string          `json:"route"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Recorder persists an order book's snapshots and updates to a file as lines
+// of JSON-encoded Records, from which the book can be reconstructed at any
+// recorded time with ReplayBook. A snapshot is recorded when the book is
+// synced, and then every snapshot interval so that a replay does not need to
+// start from the beginning of the recording.
+type Recorder struct {
+	mtx          sync.Mutex
+	f            *os.File
+	enc          *json.Encoder
+	snapInterval time.Duration
+	lastSnap     time.Time
+}
+
+// NewRecorder creates a Recorder that appends to the file at path, creating
+// the file and its directory if necessary.
+func NewRecorder(path string, snapInterval time.Duration) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("error creating book recording directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening book recording: %w", err)
+	}
+	return &Recorder{
+		f:            f,
+		enc:          json.NewEncoder(f),
+		snapInterval: snapInterval,
+	}, nil
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.f.Close()
+}
+
+// snapshotDue is true if it has been at least the snapshot interval since the
+// last snapshot was recorded.
+func (r *Recorder) snapshotDue(now time.Time) bool {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return now.Sub(r.lastSnap) >= r.snapInterval
+}
+
+// record writes a Record with the encoded payload.
+func (r *Recorder) record(stamp time.Time, route string, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if route == SnapshotRoute {
+		r.lastSnap = stamp
+	}
+	return r.enc.Encode(&Record{
+		Stamp:   uint64(stamp.UnixMilli()),
+		Route:   route,
+		Payload: b,
+	})
+}
+
+// SetRecorder sets the Recorder that the book's updates are recorded to. If
+// the book is synced, a snapshot is recorded immediately, otherwise the first
+// snapshot is recorded when the book is synced. Use a nil Recorder to stop
+// recording. The caller is responsible for closing the Recorder.
+func (ob *OrderBook) SetRecorder(r *Recorder) {
+	ob.recorder.Store(r)
+	if r != nil && ob.isSynced() {
+		ob.recordSnapshot(r, time.Now())
+	}
+}
+
+// recordUpdate records an update note that was applied to a synced book, and a
+// snapshot of the book if one is due.
+func (ob *OrderBook) recordUpdate(route string, note any) {
+	r := ob.recorder.Load()
+	if r == nil {
+		return
+	}
+	now := time.Now()
+	if err := r.record(now, route, note); err != nil {
+		ob.log.Errorf("Error recording %s note: %v", route, err)
+		return
+	}
+	if r.snapshotDue(now) {
+		ob.recordSnapshot(r, now)
+	}
+}
+
+// recordSnapshot records a snapshot of the synced book.
+func (ob *OrderBook) recordSnapshot(r *Recorder, now time.Time) {
+	if err := r.record(now, SnapshotRoute, ob.snapshot()); err != nil {
+		ob.log.Errorf("Error recording book snapshot: %v", err)
+	}
+}
+
+// snapshot creates a *msgjson.OrderBook from the book.
+func (ob *OrderBook) snapshot() *msgjson.OrderBook {
+	ob.seqMtx.Lock()
+	seq := ob.seq
+	ob.seqMtx.Unlock()
+
+	snap := &msgjson.OrderBook{
+		Seq:          seq,
+		MarketID:     ob.marketID,
+		Epoch:        ob.CurrentEpoch(),
+		BaseFeeRate:  ob.BaseFeeRate(),
+		QuoteFeeRate: ob.QuoteFeeRate(),
+	}
+	for _, side := range []*bookSide{ob.buys, ob.sells} {
+		for _, o := range side.Orders() {
+			oid := o.OrderID
+			snap.Orders = append(snap.Orders, &msgjson.BookOrderNote{
+				OrderNote: msgjson.OrderNote{
+					MarketID: ob.marketID,
+					OrderID:  oid[:],
+				},
+				TradeNote: msgjson.TradeNote{
+					Side:     o.Side,
+					Quantity: o.Quantity,
+					Rate:     o.Rate,
+					Time:     o.Time,
+				},
+			})
+		}
+	}
+	return snap
+}
+
+// ReplayBook reconstructs the order book recorded by a Recorder as it was at
+// the specified time. The book is restored from the last snapshot recorded at
+// or before the time, and the updates recorded after that snapshot, up to the
+// time, are applied.
+func ReplayBook(r io.Reader, stamp time.Time, logger dex.Logger) (*OrderBook, error) {
+	endStamp := uint64(stamp.UnixMilli())
+	var ob *OrderBook
+	dec := json.NewDecoder(r)
+	for {
+		rec := new(Record)
+		if err := dec.Decode(rec); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break // a truncated record was being written when recording stopped
+			}
+			return nil, fmt.Errorf("error decoding book record: %w", err)
+		}
+		if rec.Stamp > endStamp {
+			break
+		}
+		if rec.Route == SnapshotRoute {
+			snap := new(msgjson.OrderBook)
+			if err := json.Unmarshal(rec.Payload, snap); err != nil {
+				return nil, fmt.Errorf("error decoding book snapshot: %w", err)
+			}
+			ob = NewOrderBook(logger)
+			if err := ob.Sync(snap); err != nil {
+				return nil, fmt.Errorf("error syncing book snapshot: %w", err)
+			}
+			continue
+		}
+		if ob == nil {
+			continue // updates from before the first snapshot
+		}
+		if err := ob.replay(rec); err != nil {
+			return nil, err
+		}
+	}
+	if ob == nil {
+		return nil, fmt.Errorf("no book snapshot recorded before %s", stamp)
+	}
+	return ob, nil
+}
+
+// ReplayBookFile is ReplayBook for a recording file.
+func ReplayBookFile(path string, stamp time.Time, logger dex.Logger) (*OrderBook, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReplayBook(f, stamp, logger)
+}
+
+// replay applies a recorded update to the book.
+func (ob *OrderBook) replay(rec *Record) error {
+	var err error
+	switch rec.Route {
+	case msgjson.BookOrderRoute:
+		note := new(msgjson.BookOrderNote)
+		if err = json.Unmarshal(rec.Payload, note); err == nil {
+			err = ob.Book(note)
+		}
+	case msgjson.UnbookOrderRoute:
+		note := new(msgjson.UnbookOrderNote)
+		if err = json.Unmarshal(rec.Payload, note); err == nil {
+			err = ob.Unbook(note)
+		}
+	case msgjson.UpdateRemainingRoute:
+		note := new(msgjson.UpdateRemainingNote)
+		if err = json.Unmarshal(rec.Payload, note); err == nil {
+			err = ob.UpdateRemaining(note)
+		}
+	default:
+		return fmt.Errorf("unknown book record route %q", rec.Route)
+	}
+	if err != nil {
+		return fmt.Errorf("error replaying %s record: %w", rec.Route, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package orderbook
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
+)
+
+func TestRecorder(t *testing.T) {
+	const mkt = "dcr_btc"
+	for _, snapInterval := range []time.Duration{time.Hour, 0} {
+		path := filepath.Join(t.TempDir(), "book.jsonl")
+		rec, err := NewRecorder(path, snapInterval)
+		if err != nil {
+			t.Fatalf("NewRecorder error: %v", err)
+		}
+
+		// Wait between updates so they have different millisecond stamps.
+		stamps := make([]time.Time, 0, 5)
+		mark := func() {
+			time.Sleep(2 * time.Millisecond)
+			stamps = append(stamps, time.Now())
+			time.Sleep(2 * time.Millisecond)
+		}
+
+		mark() // 0: before recording
+
+		ob := NewOrderBook(tLogger)
+		ob.SetRecorder(rec)
+		err = ob.Sync(makeOrderBookMsg(1, mkt, []*msgjson.BookOrderNote{
+			makeBookOrderNote(1, mkt, [32]byte{'a'}, msgjson.BuyOrderNum, 10, 10, 1),
+			makeBookOrderNote(1, mkt, [32]byte{'b'}, msgjson.SellOrderNum, 10, 20, 1),
+		}))
+		if err != nil {
+			t.Fatalf("Sync error: %v", err)
+		}
+		mark() // 1: synced
+
+		if err := ob.Book(makeBookOrderNote(2, mkt, [32]byte{'d'}, msgjson.BuyOrderNum, 5, 5, 2)); err != nil {
+			t.Fatalf("Book error: %v", err)
+		}
+		mark() // 2: booked
+
+		err = ob.UpdateRemaining(&msgjson.UpdateRemainingNote{
+			OrderNote: msgjson.OrderNote{Seq: 3, MarketID: mkt, OrderID: []byte{'a', 31: 0}},
+			Remaining: 4,
+		})
+		if err != nil {
+			t.Fatalf("UpdateRemaining error: %v", err)
+		}
+		mark() // 3: updated
+
+		if err := ob.Unbook(makeUnbookOrderNote(4, mkt, [32]byte{'b'})); err != nil {
+			t.Fatalf("Unbook error: %v", err)
+		}
+		mark() // 4: unbooked
+
+		ob.SetRecorder(nil)
+		if err := rec.Close(); err != nil {
+			t.Fatalf("Close error: %v", err)
+		}
+
+		if _, err := ReplayBookFile(path, stamps[0], tLogger); err == nil {
+			t.Fatalf("no error replaying before the first snapshot")
+		}
+
+		expBooks := []map[order.OrderID]uint64{
+			1: {{'a'}: 10, {'b'}: 10},
+			2: {{'a'}: 10, {'b'}: 10, {'d'}: 5},
+			3: {{'a'}: 4, {'b'}: 10, {'d'}: 5},
+			4: {{'a'}: 4, {'d'}: 5},
+		}
+		for i := 1; i < len(stamps); i++ {
+			replayed, err := ReplayBookFile(path, stamps[i], tLogger)
+			if err != nil {
+				t.Fatalf("ReplayBookFile error for stamp %d: %v", i, err)
+			}
+			buys, sells, _ := replayed.Orders()
+			book := make(map[order.OrderID]uint64)
+			for _, o := range append(buys, sells...) {
+				book[o.OrderID] = o.Quantity
+			}
+			exp := expBooks[i]
+			if len(book) != len(exp) {
+				t.Fatalf("snapshot interval %s, stamp %d: expected %d orders, got %d", snapInterval, i, len(exp), len(book))
+			}
+			for oid, qty := range exp {
+				if book[oid] != qty {
+					t.Fatalf("snapshot interval %s, stamp %d: expected quantity %d for order %s, got %d",
+						snapInterval, i, qty, oid, book[oid])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,227 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Authentication modes. With client certificate authentication, clients must
+// present a certificate from the allow-list during the TLS handshake, and the
+// routes they may use are limited to the certificate's scopes.
+const (
+	// AuthModePassword requires the RPC user and password. This is the
+	// default.
+	AuthModePassword = "password"
+	// AuthModeCert requires a client certificate from the allow-list.
+	AuthModeCert = "cert"
+	// AuthModeCertAndPassword requires both a client certificate from the
+	// allow-list and the RPC user and password.
+	AuthModeCertAndPassword = "both"
+)
+
+// Permission scopes of client certificates.
+const (
+	// scopeRead allows the routes that only return information.
+	scopeRead = "read"
+	// scopeTrade allows placing and canceling orders.
+	scopeTrade = "trade"
+	// scopeWallet allows managing wallets and spending funds.
+	scopeWallet = "wallet"
+	// scopeMM allows controlling the market making bots.
+	scopeMM = "mm"
+	// scopeAdmin allows app and account management, e.g. login and seed
+	// export.
+	scopeAdmin = "admin"
+	// scopeAll allows every route.
+	scopeAll = "all"
+)
+
+// routeScopes are the scopes required for each route.
+var routeScopes = map[string]string{
+	bondAssetsRoute:            scopeRead,
+	bridgeHistoryRoute:         scopeRead,
+	checkBridgeApprovalRoute:   scopeRead,
+	exchangesRoute:             scopeRead,
+	getDEXConfRoute:            scopeRead,
+	helpRoute:                  scopeRead,
+	mixingStatsRoute:           scopeRead,
+	mmAvailableBalancesRoute:   scopeRead,
+	mmStatusRoute:              scopeRead,
+	myOrdersRoute:              scopeRead,
+	orderHistoryRoute:          scopeRead,
+	walletHistoryRoute:         scopeRead,
+	statusRoute:                scopeRead,
+	notificationsRoute:         scopeRead,
+	orderBookRoute:             scopeRead,
+	pendingBridgesRoute:        scopeRead,
+	stakeStatusRoute:           scopeRead,
+	txHistoryRoute:             scopeRead,
+	versionRoute:               scopeRead,
+	votingPreferencesRoute:     scopeRead,
+	walletPeersRoute:           scopeRead,
+	walletTxRoute:              scopeRead,
+	walletsRoute:               scopeRead,
+	cancelRoute:                scopeTrade,
+	multiTradeRoute:            scopeTrade,
+	tradeRoute:                 scopeTrade,
+	addWalletPeerRoute:         scopeWallet,
+	approveBridgeContractRoute: scopeWallet,
+	bridgeRoute:                scopeWallet,
+	closeWalletRoute:           scopeWallet,
+	newWalletRoute:             scopeWallet,
+	openWalletRoute:            scopeWallet,
+	purchaseTicketsRoute:       scopeWallet,
+	reconfigureWalletRoute:     scopeWallet,
+	removeWalletPeerRoute:      scopeWallet,
+	rescanWalletRoute:          scopeWallet,
+	sendRoute:                  scopeWallet,
+	setVSPRoute:                scopeWallet,
+	setVotingPreferencesRoute:  scopeWallet,
+	takeActionRoute:            scopeWallet,
+	toggleWalletStatusRoute:    scopeWallet,
+	withdrawBchSpvRoute:        scopeWallet,
+	withdrawRoute:              scopeWallet,
+	removeBotConfigRoute:       scopeMM,
+	startBotRoute:              scopeMM,
+	stopBotRoute:               scopeMM,
+	updateBotConfigRoute:       scopeMM,
+	updateRunningBotCfgRoute:   scopeMM,
+	updateRunningBotInvRoute:   scopeMM,
+	appSeedRoute:               scopeAdmin,
+	bondOptionsRoute:           scopeAdmin,
+	deleteArchivedRecordsRoute: scopeAdmin,
+	discoverAcctRoute:          scopeAdmin,
+	exportAccountRoute:         scopeAdmin,
+	importAccountRoute:         scopeAdmin,
+	initRoute:                  scopeAdmin,
+	loginRoute:                 scopeAdmin,
+	logoutRoute:                scopeAdmin,
+	postBondRoute:              scopeAdmin,
+	recoveryKeysRoute:          scopeAdmin,
+}
+
+// ClientCert is an entry in the client certificate allow-list file, which is
+// a JSON array of entries.
+type ClientCert struct {
+	// Name identifies the client in the logs.
+	Name string `json:"name"`
+	// Cert is the path of the client's PEM-encoded certificate. Either Cert
+	// or SHA256 must be set.
+	Cert string `json:"cert"`
+	// SHA256 is the hex-encoded SHA-256 fingerprint of the client's
+	// DER-encoded certificate.
+	SHA256 string `json:"sha256"`
+	// Scopes are the permission scopes of the client: read, trade, wallet,
+	// mm, admin or all.
+	Scopes []string `json:"scopes"`
+}
+
+// clientPerms are the permissions of an authenticated client.
+type clientPerms struct {
+	name   string
+	scopes map[string]bool
+}
+
+// allowed checks whether the client may use the route.
+func (p *clientPerms) allowed(route string) bool {
+	if p == nil || p.scopes[scopeAll] {
+		return true
+	}
+	return p.scopes[routeScopes[route]]
+}
+
+type ctxKey int
+
+const permsCtxKey ctxKey = iota
+
+// clientPermsFromContext gets the permissions of the client. Password
+// authenticated clients have no permissions in the context, and are not
+// limited.
+func clientPermsFromContext(ctx context.Context) *clientPerms {
+	perms, _ := ctx.Value(permsCtxKey).(*clientPerms)
+	return perms
+}
+
+// certFingerprint is the hex-encoded SHA-256 hash of the DER-encoded
+// certificate.
+func certFingerprint(der []byte) string {
+	h := sha256.Sum256(der)
+	return hex.EncodeToString(h[:])
+}
+
+// loadClientCerts loads the client certificate allow-list file. The returned
+// map is keyed by certificate fingerprint.
+func loadClientCerts(path string) (map[string]*clientPerms, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client certificates file: %w", err)
+	}
+	var entries []*ClientCert
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing client certificates file: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no client certificates in %s", path)
+	}
+	certs := make(map[string]*clientPerms, len(entries))
+	for i, entry := range entries {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("client certificate %d has no name", i)
+		}
+		var fp string
+		switch {
+		case entry.Cert != "" && entry.SHA256 != "":
+			return nil, fmt.Errorf("client certificate %q has both a cert and a sha256 fingerprint", entry.Name)
+		case entry.Cert != "":
+			pemB, err := os.ReadFile(entry.Cert)
+			if err != nil {
+				return nil, fmt.Errorf("error reading client certificate %q: %w", entry.Name, err)
+			}
+			block, _ := pem.Decode(pemB)
+			if block == nil || block.Type != "CERTIFICATE" {
+				return nil, fmt.Errorf("no PEM certificate found for client certificate %q", entry.Name)
+			}
+			if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+				return nil, fmt.Errorf("error parsing client certificate %q: %w", entry.Name, err)
+			}
+			fp = certFingerprint(block.Bytes)
+		case entry.SHA256 != "":
+			fp = strings.ToLower(strings.ReplaceAll(entry.SHA256, ":", ""))
+			if b, err := hex.DecodeString(fp); err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("invalid sha256 fingerprint for client certificate %q", entry.Name)
+			}
+		default:
+			return nil, fmt.Errorf("client certificate %q has no cert or sha256 fingerprint", entry.Name)
+		}
+		if _, found := certs[fp]; found {
+			return nil, fmt.Errorf("duplicate client certificate %q", entry.Name)
+		}
+		if len(entry.Scopes) == 0 {
+			return nil, fmt.Errorf("client certificate %q has no scopes", entry.Name)
+		}
+		perms := &clientPerms{
+			name:   entry.Name,
+			scopes: make(map[string]bool, len(entry.Scopes)),
+		}
+		for _, scope := range entry.Scopes {
+			switch scope {
+			case scopeRead, scopeTrade, scopeWallet, scopeMM, scopeAdmin, scopeAll:
+			default:
+				return nil, fmt.Errorf("unknown scope %q for client certificate %q", scope, entry.Name)
+			}
+			perms.scopes[scope] = true
+		}
+		certs[fp] = perms
+	}
+	return certs, nil
+}
@@ -0,0 +1,238 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+//go:build !live
+
+package rpcserver
+
+import (
+	"crypto/elliptic"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/decred/dcrd/certgen"
+)
+
+func TestRouteScopes(t *testing.T) {
+	for route := range routes {
+		if _, found := routeScopes[route]; !found {
+			t.Fatalf("no scope for route %s", route)
+		}
+	}
+	for route := range routeScopes {
+		if _, found := routes[route]; !found {
+			t.Fatalf("scope for unknown route %s", route)
+		}
+	}
+}
+
+// newClientCert generates a client certificate, writes it to the directory,
+// and returns its path.
+func newClientCert(t *testing.T, dir, name string) (string, *x509.Certificate) {
+	t.Helper()
+	certPEM, _, err := certgen.NewTLSCertPair(elliptic.P256(), name, time.Now().Add(time.Hour), nil)
+	if err != nil {
+		t.Fatalf("error generating cert: %v", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("error parsing cert: %v", err)
+	}
+	path := filepath.Join(dir, name+".cert")
+	if err := os.WriteFile(path, certPEM, 0644); err != nil {
+		t.Fatalf("error writing cert: %v", err)
+	}
+	return path, cert
+}
+
+func writeClientCerts(t *testing.T, dir string, entries any) string {
+	t.Helper()
+	b, _ := json.Marshal(entries)
+	path := filepath.Join(dir, "clients.json")
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("error writing client certs: %v", err)
+	}
+	return path
+}
+
+func TestLoadClientCerts(t *testing.T) {
+	dir := t.TempDir()
+	certPath, cert := newClientCert(t, dir, "monitor")
+	fp := certFingerprint(cert.Raw)
+
+	tests := []struct {
+		name    string
+		entries []*ClientCert
+		wantErr bool
+	}{{
+		name: "ok",
+		entries: []*ClientCert{
+			{Name: "monitor", Cert: certPath, Scopes: []string{scopeRead}},
+			{Name: "admin", SHA256: "AB:" + strings.Repeat("ab", 31), Scopes: []string{scopeAll}},
+		},
+	}, {
+		name:    "no entries",
+		entries: []*ClientCert{},
+		wantErr: true,
+	}, {
+		name:    "no name",
+		entries: []*ClientCert{{Cert: certPath, Scopes: []string{scopeRead}}},
+		wantErr: true,
+	}, {
+		name:    "no cert",
+		entries: []*ClientCert{{Name: "monitor", Scopes: []string{scopeRead}}},
+		wantErr: true,
+	}, {
+		name:    "cert and fingerprint",
+		entries: []*ClientCert{{Name: "monitor", Cert: certPath, SHA256: fp, Scopes: []string{scopeRead}}},
+		wantErr: true,
+	}, {
+		name:    "bad fingerprint",
+		entries: []*ClientCert{{Name: "monitor", SHA256: "abcd", Scopes: []string{scopeRead}}},
+		wantErr: true,
+	}, {
+		name: "duplicate",
+		entries: []*ClientCert{
+			{Name: "monitor", Cert: certPath, Scopes: []string{scopeRead}},
+			{Name: "monitor2", SHA256: fp, Scopes: []string{scopeRead}},
+		},
+		wantErr: true,
+	}, {
+		name:    "no scopes",
+		entries: []*ClientCert{{Name: "monitor", Cert: certPath}},
+		wantErr: true,
+	}, {
+		name:    "unknown scope",
+		entries: []*ClientCert{{Name: "monitor", Cert: certPath, Scopes: []string{"root"}}},
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		certs, err := loadClientCerts(writeClientCerts(t, dir, test.entries))
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s: no error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if perms := certs[fp]; perms == nil || perms.name != "monitor" || !perms.scopes[scopeRead] {
+			t.Fatalf("%s: wrong permissions %+v", test.name, perms)
+		}
+	}
+}
+
+func TestClientCertAuth(t *testing.T) {
+	dir := t.TempDir()
+	monitorPath, monitorCert := newClientCert(t, dir, "monitor")
+	_, unknownCert := newClientCert(t, dir, "unknown")
+	clientCerts := writeClientCerts(t, dir, []*ClientCert{
+		{Name: "monitor", Cert: monitorPath, Scopes: []string{scopeRead}},
+	})
+
+	newServer := func(mode string) *RPCServer {
+		t.Helper()
+		s, err := New(&Config{
+			Core:        &TCore{},
+			Addr:        "127.0.0.1:0",
+			User:        "user",
+			Pass:        "abc",
+			Cert:        filepath.Join(dir, "rpc.cert"),
+			Key:         filepath.Join(dir, "rpc.key"),
+			AuthMode:    mode,
+			ClientCerts: clientCerts,
+		})
+		if err != nil {
+			t.Fatalf("error creating %s server: %v", mode, err)
+		}
+		if s.tlsConfig.ClientAuth != tls.RequireAnyClientCert {
+			t.Fatalf("client certificates not required for %s mode", mode)
+		}
+		return s
+	}
+
+	var gotPerms *clientPerms
+	try := func(s *RPCServer, cert *x509.Certificate, pass string, wantCode int) {
+		t.Helper()
+		am := s.authMiddleware(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				gotPerms = clientPermsFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+		r, _ := http.NewRequest("GET", "", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		if cert != nil {
+			r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		}
+		if pass != "" {
+			r.SetBasicAuth("user", pass)
+		}
+		w := &tResponseWriter{}
+		gotPerms = nil
+		am.ServeHTTP(w, r)
+		if w.code != wantCode {
+			t.Fatalf("expected HTTP status %d, got %d", wantCode, w.code)
+		}
+	}
+
+	s := newServer(AuthModeCert)
+	try(s, nil, "abc", http.StatusUnauthorized)
+	try(s, unknownCert, "abc", http.StatusForbidden)
+	try(s, monitorCert, "", http.StatusOK)
+	if gotPerms == nil || gotPerms.name != "monitor" {
+		t.Fatalf("wrong permissions %+v", gotPerms)
+	}
+
+	s = newServer(AuthModeCertAndPassword)
+	try(s, monitorCert, "", http.StatusUnauthorized)
+	try(s, monitorCert, "wrong", http.StatusUnauthorized)
+	try(s, unknownCert, "abc", http.StatusForbidden)
+	try(s, monitorCert, "abc", http.StatusOK)
+	if gotPerms == nil || gotPerms.name != "monitor" {
+		t.Fatalf("wrong permissions %+v", gotPerms)
+	}
+
+	// The read scope allows version, but not trade.
+	checkRoute := func(route string, wantErrCode int) {
+		t.Helper()
+		req, _ := msgjson.NewRequest(1, route, nil)
+		payload := s.handleRequest(req, gotPerms)
+		if wantErrCode == -1 {
+			if payload.Error != nil {
+				t.Fatalf("%s: unexpected error %v", route, payload.Error)
+			}
+			return
+		}
+		if payload.Error == nil || payload.Error.Code != wantErrCode {
+			t.Fatalf("%s: wanted error code %d, got %v", route, wantErrCode, payload.Error)
+		}
+	}
+	checkRoute(versionRoute, -1)
+	checkRoute(tradeRoute, msgjson.RPCPermissionDenied)
+	// Password authenticated clients are not limited.
+	gotPerms = nil
+	checkRoute(tradeRoute, msgjson.RPCArgumentsError)
+
+	// The cert modes require the allow-list, and the password is still
+	// required in the both mode.
+	if _, err := New(&Config{Pass: "abc", AuthMode: AuthModeCert}); err == nil {
+		t.Fatal("no error for missing client certificates file")
+	}
+	if _, err := New(&Config{Pass: "abc", AuthMode: "bogus"}); err == nil {
+		t.Fatal("no error for unknown auth mode")
+	}
+	if _, err := New(&Config{AuthMode: AuthModeCertAndPassword, ClientCerts: clientCerts}); err == nil {
+		t.Fatal("no error for missing password")
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"time"
 
 	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
@@ -62,6 +64,8 @@ const (
 	setVSPRoute                = "setvsp"
 	purchaseTicketsRoute       = "purchasetickets"
 	setVotingPreferencesRoute  = "setvotingprefs"
+	votingPreferencesRoute     = "votingprefs"
+	mixingStatsRoute           = "mixingstats"
 	txHistoryRoute             = "txhistory"
 	walletTxRoute              = "wallettx"
 	withdrawBchSpvRoute        = "withdrawbchspv"
@@ -70,18 +74,31 @@ const (
 	approveBridgeContractRoute = "approvebridgecontract"
 	pendingBridgesRoute        = "pendingbridges"
 	bridgeHistoryRoute         = "bridgehistory"
+	orderHistoryRoute          = "orderhistory"
+	walletHistoryRoute         = "wallethistory"
+	statusRoute                = "status"
+	exportAccountRoute         = "exportaccount"
+	importAccountRoute         = "importaccount"
+	reconfigureWalletRoute     = "reconfigurewallet"
+	takeActionRoute            = "takeaction"
+	updateBotConfigRoute       = "updatebotconfig"
+	removeBotConfigRoute       = "removebotconfig"
+	recoveryKeysRoute          = "recoverykeys"
 )
 
 const (
-	initializedStr    = "app initialized"
-	walletCreatedStr  = "%s wallet created and unlocked"
-	walletLockedStr   = "%s wallet locked"
-	walletUnlockedStr = "%s wallet unlocked"
-	canceledOrderStr  = "canceled order %s"
-	logoutStr         = "goodbye"
-	walletStatusStr   = "%s wallet has been %s"
-	setVotePrefsStr   = "vote preferences set"
-	setVSPStr         = "vsp set to %s"
+	initializedStr        = "app initialized"
+	walletCreatedStr      = "%s wallet created and unlocked"
+	walletLockedStr       = "%s wallet locked"
+	walletUnlockedStr     = "%s wallet unlocked"
+	canceledOrderStr      = "canceled order %s"
+	logoutStr             = "goodbye"
+	walletStatusStr       = "%s wallet has been %s"
+	walletReconfiguredStr = "%s wallet reconfigured"
+	accountImportedStr    = "account imported"
+	actionTakenStr        = "action taken"
+	setVotePrefsStr       = "vote preferences set"
+	setVSPStr             = "vsp set to %s"
 )
 
 // createResponse creates a msgjson response payload.
@@ -144,6 +161,8 @@ var routes = map[string]func(s *RPCServer, params *RawParams) *msgjson.ResponseP
 	setVSPRoute:                handleSetVSP,
 	purchaseTicketsRoute:       handlePurchaseTickets,
 	setVotingPreferencesRoute:  handleSetVotingPreferences,
+	votingPreferencesRoute:     handleVotingPreferences,
+	mixingStatsRoute:           handleMixingStats,
 	txHistoryRoute:             handleTxHistory,
 	walletTxRoute:              handleWalletTx,
 	withdrawBchSpvRoute:        handleWithdrawBchSpv,
@@ -152,6 +171,16 @@ var routes = map[string]func(s *RPCServer, params *RawParams) *msgjson.ResponseP
 	approveBridgeContractRoute: handleApproveBridge,
 	pendingBridgesRoute:        handlePendingBridges,
 	bridgeHistoryRoute:         handleBridgeHistory,
+	orderHistoryRoute:          handleOrderHistory,
+	walletHistoryRoute:         handleWalletHistory,
+	statusRoute:                handleStatus,
+	exportAccountRoute:         handleExportAccount,
+	importAccountRoute:         handleImportAccount,
+	reconfigureWalletRoute:     handleReconfigureWallet,
+	takeActionRoute:            handleTakeAction,
+	updateBotConfigRoute:       handleUpdateBotConfig,
+	removeBotConfigRoute:       handleRemoveBotConfig,
+	recoveryKeysRoute:          handleRecoveryKeys,
 }
 
 // handleHelp handles requests for help. Returns general help for all commands
@@ -321,6 +350,28 @@ func handleToggleWalletStatus(s *RPCServer, params *RawParams) *msgjson.Response
 	return createResponse(toggleWalletStatusRoute, &res, nil)
 }
 
+// handleReconfigureWallet handles requests for reconfigurewallet.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleReconfigureWallet(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseReconfigureWalletArgs(params)
+	if err != nil {
+		return usage(reconfigureWalletRoute, err)
+	}
+	defer form.appPass.Clear()
+	defer form.newWalletPass.Clear()
+	err = s.core.ReconfigureWallet(form.appPass, form.newWalletPass, &core.WalletForm{
+		AssetID: form.assetID,
+		Config:  form.config,
+		Type:    form.walletType,
+	})
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCReconfigureWalletError, "unable to reconfigure %s wallet: %v",
+			dex.BipIDSymbol(form.assetID), err)
+		return createResponse(reconfigureWalletRoute, nil, resErr)
+	}
+	return createResponse(reconfigureWalletRoute, fmt.Sprintf(walletReconfiguredStr, dex.BipIDSymbol(form.assetID)), nil)
+}
+
 // handleWallets handles requests for wallets. Returns a list of wallet details.
 func handleWallets(s *RPCServer, _ *RawParams) *msgjson.ResponsePayload {
 	walletsStates := s.core.Wallets()
@@ -609,11 +660,11 @@ func send(s *RPCServer, params *RawParams, route string) *msgjson.ResponsePayloa
 // state should be consulted for status. *msgjson.ResponsePayload.Error is empty
 // if successful.
 func handleRescanWallet(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
-	assetID, force, err := parseRescanWalletArgs(params)
+	form, err := parseRescanWalletArgs(params)
 	if err != nil {
 		return usage(rescanWalletRoute, err)
 	}
-	err = s.core.RescanWallet(assetID, force)
+	err = s.core.RescanWallet(form.assetID, form.force, form.birthday)
 	if err != nil {
 		resErr := msgjson.NewError(msgjson.RPCWalletRescanError, "unable to rescan wallet: %v", err)
 		return createResponse(rescanWalletRoute, nil, resErr)
@@ -787,6 +838,56 @@ func handleAppSeed(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	return createResponse(appSeedRoute, seed, nil)
 }
 
+// handleExportAccount handles requests for exportaccount.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleExportAccount(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAccountExportArgs(params)
+	if err != nil {
+		return usage(exportAccountRoute, err)
+	}
+	defer form.appPass.Clear()
+	acct, bonds, err := s.core.AccountExport(form.appPass, form.host)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAccountExportError, "unable to export account: %v", err)
+		return createResponse(exportAccountRoute, nil, resErr)
+	}
+	if bonds == nil {
+		bonds = make([]*db.Bond, 0) // marshal to [], not null
+	}
+	return createResponse(exportAccountRoute, &accountExport{Account: acct, Bonds: bonds}, nil)
+}
+
+// handleRecoveryKeys handles requests for recoverykeys.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleRecoveryKeys(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseRecoveryKeysArgs(params)
+	if err != nil {
+		return usage(recoveryKeysRoute, err)
+	}
+	keys, err := s.core.RecoveryKeys(form)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCRecoveryKeysError, "unable to derive recovery keys: %v", err)
+		return createResponse(recoveryKeysRoute, nil, resErr)
+	}
+	return createResponse(recoveryKeysRoute, keys, nil)
+}
+
+// handleImportAccount handles requests for importaccount.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleImportAccount(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseAccountImportArgs(params)
+	if err != nil {
+		return usage(importAccountRoute, err)
+	}
+	defer form.appPass.Clear()
+	err = s.core.AccountImport(form.appPass, form.export.Account, form.export.Bonds)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAccountImportError, "unable to import account: %v", err)
+		return createResponse(importAccountRoute, nil, resErr)
+	}
+	return createResponse(importAccountRoute, accountImportedStr, nil)
+}
+
 // handleDeleteArchivedRecords handles requests for deleting archived records.
 // *msgjson.ResponsePayload.Error is empty if successful.
 func handleDeleteArchivedRecords(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
@@ -866,6 +967,21 @@ func handleNotifications(s *RPCServer, params *RawParams) *msgjson.ResponsePaylo
 	return createResponse(notificationsRoute, notes, nil)
 }
 
+// handleTakeAction handles requests for takeaction, which resolves an action
+// requested by a wallet or core in an actionrequired notification.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleTakeAction(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseTakeActionArgs(params)
+	if err != nil {
+		return usage(takeActionRoute, err)
+	}
+	if err = s.core.TakeAction(form.assetID, form.actionID, form.action); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCTakeActionError, "unable to take action: %v", err)
+		return createResponse(takeActionRoute, nil, resErr)
+	}
+	return createResponse(takeActionRoute, actionTakenStr, nil)
+}
+
 func handleMMAvailableBalances(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	form, err := parseMMAvailableBalancesArgs(params)
 	if err != nil {
@@ -925,39 +1041,79 @@ func handleUpdateRunningBotCfg(s *RPCServer, params *RawParams) *msgjson.Respons
 		return usage(updateRunningBotCfgRoute, err)
 	}
 
-	data, err := os.ReadFile(form.cfgFilePath)
+	botCfg, err := readBotConfig(form.cfgFilePath, form.mkt)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCUpdateRunningBotCfgError, "%v", err)
+		return createResponse(updateRunningBotCfgRoute, nil, resErr)
+	}
+
+	err = s.mm.UpdateRunningBotCfg(botCfg, form.balances, false)
 	if err != nil {
-		resErr := msgjson.NewError(msgjson.RPCUpdateRunningBotCfgError, "unable to read config file: %v", err)
+		resErr := msgjson.NewError(msgjson.RPCUpdateRunningBotCfgError, "unable to update running bot: %v", err)
 		return createResponse(updateRunningBotCfgRoute, nil, resErr)
 	}
 
+	return createResponse(updateRunningBotCfgRoute, "updated running bot", nil)
+}
+
+// readBotConfig reads the config for the market's bot from the market maker
+// config file.
+func readBotConfig(cfgFilePath string, mkt *mm.MarketWithHost) (*mm.BotConfig, error) {
+	data, err := os.ReadFile(cfgFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %v", err)
+	}
+
 	cfg := &mm.MarketMakingConfig{}
 	err = json.Unmarshal(data, cfg)
 	if err != nil {
-		resErr := msgjson.NewError(msgjson.RPCUpdateRunningBotCfgError, "unable to unmarshal config: %v", err)
-		return createResponse(updateRunningBotCfgRoute, nil, resErr)
+		return nil, fmt.Errorf("unable to unmarshal config: %v", err)
 	}
 
-	var botCfg *mm.BotConfig
 	for _, bot := range cfg.BotConfigs {
-		if bot.Host == form.mkt.Host && bot.BaseID == form.mkt.BaseID && bot.QuoteID == form.mkt.QuoteID {
-			botCfg = bot
-			break
+		if bot.Host == mkt.Host && bot.BaseID == mkt.BaseID && bot.QuoteID == mkt.QuoteID {
+			return bot, nil
 		}
 	}
+	return nil, fmt.Errorf("bot config not found for market %s", mkt.String())
+}
 
-	if botCfg == nil {
-		resErr := msgjson.NewError(msgjson.RPCUpdateRunningBotCfgError, "bot config not found for market %s", form.mkt.String())
-		return createResponse(updateRunningBotCfgRoute, nil, resErr)
+// handleUpdateBotConfig saves the config for a bot that is not running, read
+// from a market maker config file, to the default market maker config.
+func handleUpdateBotConfig(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseUpdateBotConfigArgs(params)
+	if err != nil {
+		return usage(updateBotConfigRoute, err)
 	}
 
-	err = s.mm.UpdateRunningBotCfg(botCfg, form.balances, false)
+	botCfg, err := readBotConfig(form.cfgFilePath, form.mkt)
 	if err != nil {
-		resErr := msgjson.NewError(msgjson.RPCUpdateRunningBotCfgError, "unable to update running bot: %v", err)
-		return createResponse(updateRunningBotCfgRoute, nil, resErr)
+		resErr := msgjson.NewError(msgjson.RPCUpdateBotConfigError, "%v", err)
+		return createResponse(updateBotConfigRoute, nil, resErr)
 	}
 
-	return createResponse(updateRunningBotCfgRoute, "updated running bot", nil)
+	if err = s.mm.UpdateBotConfig(botCfg); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCUpdateBotConfigError, "unable to update bot config: %v", err)
+		return createResponse(updateBotConfigRoute, nil, resErr)
+	}
+
+	return createResponse(updateBotConfigRoute, "updated bot config", nil)
+}
+
+// handleRemoveBotConfig removes a bot's config from the default market maker
+// config.
+func handleRemoveBotConfig(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	mkt, err := parseRemoveBotConfigArgs(params)
+	if err != nil {
+		return usage(removeBotConfigRoute, err)
+	}
+
+	if err = s.mm.RemoveBotConfig(mkt.Host, mkt.BaseID, mkt.QuoteID); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCRemoveBotConfigError, "unable to remove bot config: %v", err)
+		return createResponse(removeBotConfigRoute, nil, resErr)
+	}
+
+	return createResponse(removeBotConfigRoute, "removed bot config", nil)
 }
 
 func handleUpdateRunningBotInventory(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
@@ -1024,6 +1180,37 @@ func handleStakeStatus(s *RPCServer, params *RawParams) *msgjson.ResponsePayload
 	return createResponse(stakeStatusRoute, &stakeStatus, nil)
 }
 
+// handleVotingPreferences handles requests for votingprefs.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleVotingPreferences(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	assetID, err := parseStakeStatusArgs(params)
+	if err != nil {
+		return usage(votingPreferencesRoute, err)
+	}
+	stakeStatus, err := s.core.StakeStatus(assetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCStakeStatusError, "unable to get voting preferences: %v", err)
+		return createResponse(votingPreferencesRoute, nil, resErr)
+	}
+
+	return createResponse(votingPreferencesRoute, &stakeStatus.Stances, nil)
+}
+
+// handleMixingStats handles requests for mixingstats.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleMixingStats(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	assetID, err := parseMixingStatsArgs(params)
+	if err != nil {
+		return usage(mixingStatsRoute, err)
+	}
+	stats, err := s.core.FundsMixingStats(assetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCMixingStatsError, "unable to get mixing status: %v", err)
+		return createResponse(mixingStatsRoute, nil, resErr)
+	}
+	return createResponse(mixingStatsRoute, stats, nil)
+}
+
 func handleSetVotingPreferences(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
 	form, err := parseSetVotingPreferencesArgs(params)
 	if err != nil {
@@ -1207,6 +1394,246 @@ func handleBridgeHistory(s *RPCServer, params *RawParams) *msgjson.ResponsePaylo
 	return createResponse(bridgeHistoryRoute, bridges, nil)
 }
 
+// handleOrderHistory handles requests for orderhistory. It returns a page of
+// the user's orders, newest first, with the offset for the next page.
+func handleOrderHistory(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	filter, err := parseOrderHistoryArgs(params)
+	if err != nil {
+		return usage(orderHistoryRoute, err)
+	}
+
+	ords, err := s.core.Orders(filter)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCOrderHistoryError, "unable to get order history: %v", err)
+		return createResponse(orderHistoryRoute, nil, resErr)
+	}
+
+	res := &orderHistoryResponse{Orders: make([]*myOrder, 0, len(ords))}
+	for _, ord := range ords {
+		res.Orders = append(res.Orders, parseCoreOrder(ord, ord.BaseID, ord.QuoteID))
+	}
+	// A full page may be followed by another.
+	if len(ords) == filter.N {
+		res.Next = ords[len(ords)-1].ID
+	}
+	return createResponse(orderHistoryRoute, res, nil)
+}
+
+// walletHistoryScanSize is the number of transactions requested from the
+// wallet at a time while filtering a wallethistory page.
+const walletHistoryScanSize = 100
+
+// handleWalletHistory handles requests for wallethistory. It returns a page of
+// the wallet's transactions, newest first, with the refID for the next page.
+// Swaps, redemptions and refunds are annotated with the matches they settled.
+func handleWalletHistory(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	form, err := parseWalletHistoryArgs(params)
+	if err != nil {
+		return usage(walletHistoryRoute, err)
+	}
+
+	res := &walletHistoryResponse{Txs: make([]*walletHistoryTx, 0, form.n)}
+	refID := form.refID
+scan:
+	for {
+		txs, err := s.core.TxHistory(form.assetID, walletHistoryScanSize, refID, true)
+		if err != nil {
+			resErr := msgjson.NewError(msgjson.RPCTxHistoryError, "unable to get tx history: %v", err)
+			return createResponse(walletHistoryRoute, nil, resErr)
+		}
+		for _, tx := range txs {
+			// Unconfirmed transactions have no timestamp, and are newer
+			// than any confirmed transaction.
+			if form.until != 0 && (tx.Timestamp == 0 || tx.Timestamp >= form.until) {
+				continue
+			}
+			if form.since != 0 && tx.Timestamp != 0 && tx.Timestamp < form.since {
+				break scan
+			}
+			if form.types != nil && !form.types[tx.Type] {
+				continue
+			}
+			res.Txs = append(res.Txs, &walletHistoryTx{
+				WalletTransaction: tx,
+				TypeName:          txTypeName(tx.Type),
+			})
+			if len(res.Txs) == form.n {
+				res.Next = tx.ID
+				break scan
+			}
+		}
+		if len(txs) < walletHistoryScanSize {
+			break
+		}
+		refID = &txs[len(txs)-1].ID
+	}
+
+	if err := s.annotateWalletHistory(form.assetID, res.Txs); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCTxHistoryError, "unable to get orders: %v", err)
+		return createResponse(walletHistoryRoute, nil, resErr)
+	}
+	return createResponse(walletHistoryRoute, res, nil)
+}
+
+// annotateWalletHistory adds the matches settled by the swap, redeem and refund
+// transactions.
+func (s *RPCServer) annotateWalletHistory(assetID uint32, txs []*walletHistoryTx) error {
+	byID := make(map[string]*walletHistoryTx)
+	var since uint64
+	for _, tx := range txs {
+		switch tx.Type {
+		case asset.Swap, asset.Redeem, asset.Refund, asset.SwapOrSend:
+		default:
+			continue
+		}
+		byID[strings.ToLower(tx.ID)] = tx
+		// Orders are updated after their matches' transactions.
+		if tx.Timestamp != 0 && (since == 0 || tx.Timestamp < since) {
+			since = tx.Timestamp
+		}
+	}
+	if len(byID) == 0 {
+		return nil
+	}
+	ords, err := s.core.Orders(&core.OrderFilter{
+		Assets: []uint32{assetID},
+		Since:  since * 1000,
+	})
+	if err != nil {
+		return err
+	}
+	for _, ord := range ords {
+		for _, match := range ord.Matches {
+			for _, c := range []struct {
+				coin *core.Coin
+				role string
+			}{{match.Swap, "swap"}, {match.Redeem, "redeem"}, {match.Refund, "refund"}} {
+				if c.coin == nil || c.coin.AssetID != assetID {
+					continue
+				}
+				// UTXO coin IDs are the tx hash and output index.
+				txID, _, _ := strings.Cut(c.coin.StringID, ":")
+				tx := byID[strings.ToLower(txID)]
+				if tx == nil {
+					continue
+				}
+				tx.DEX = append(tx.DEX, &txDEXContext{
+					Host:    ord.Host,
+					Market:  ord.MarketID,
+					OrderID: ord.ID,
+					MatchID: match.MatchID,
+					Sell:    ord.Sell,
+					Rate:    match.Rate,
+					Qty:     match.Qty,
+					Role:    c.role,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// txTypeName is the name of the transaction type.
+func txTypeName(txType asset.TransactionType) string {
+	for name, t := range txTypes {
+		if t == txType {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// handleStatus handles requests for status. The status is returned with an
+// RPCDegradedStatus error if there are any problems, so that monitoring probes
+// need only check for an error.
+func handleStatus(s *RPCServer, params *RawParams) *msgjson.ResponsePayload {
+	if err := checkNArgs(params, []int{0}, []int{0}); err != nil {
+		return usage(statusRoute, err)
+	}
+
+	res := &healthStatus{
+		DB:             &dbHealth{OK: true},
+		Wallets:        make([]*walletHealth, 0),
+		Exchanges:      make([]*exchangeHealth, 0),
+		PendingActions: make([]*pendingAction, 0),
+	}
+	problem := func(format string, args ...any) {
+		res.Problems = append(res.Problems, fmt.Sprintf(format, args...))
+	}
+
+	if err := s.core.CheckDB(); err != nil {
+		res.DB = &dbHealth{Error: err.Error()}
+		problem("database error: %v", err)
+	}
+
+	for _, w := range s.core.Wallets() {
+		wh := &walletHealth{
+			AssetID:   w.AssetID,
+			Symbol:    w.Symbol,
+			Disabled:  w.Disabled,
+			Running:   w.Running,
+			Open:      w.Open,
+			Synced:    w.Synced,
+			PeerCount: w.PeerCount,
+		}
+		if w.SyncStatus != nil {
+			wh.SyncProgress = w.SyncStatus.BlockProgress()
+		}
+		res.Wallets = append(res.Wallets, wh)
+		switch {
+		case w.Disabled:
+		case !w.Running:
+			problem("%s wallet is not running", w.Symbol)
+		case !w.Synced:
+			problem("%s wallet is syncing (%.1f%%)", w.Symbol, wh.SyncProgress*100)
+		case w.PeerCount == 0:
+			problem("%s wallet has no peers", w.Symbol)
+		case !w.Open:
+			problem("%s wallet is locked", w.Symbol)
+		}
+	}
+
+	exchanges := s.core.Exchanges()
+	hosts := make([]string, 0, len(exchanges))
+	for host := range exchanges {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		xc := exchanges[host]
+		res.Exchanges = append(res.Exchanges, &exchangeHealth{
+			Host:     host,
+			Disabled: xc.Disabled,
+			Status:   xc.ConnectionStatus.String(),
+		})
+		if !xc.Disabled && xc.ConnectionStatus != comms.Connected {
+			problem("%s is %s", host, xc.ConnectionStatus)
+		}
+	}
+
+	for _, a := range s.core.PendingActions() {
+		res.PendingActions = append(res.PendingActions, &pendingAction{
+			AssetID:  a.AssetID,
+			ActionID: a.ActionID,
+			UniqueID: a.UniqueID,
+		})
+	}
+	if n := len(res.PendingActions); n > 0 {
+		problem("%d action(s) pending", n)
+	}
+
+	if s.mm != nil {
+		res.ActiveBots = len(s.mm.RunningBotsStatus().Bots)
+	}
+
+	res.Healthy = len(res.Problems) == 0
+	if !res.Healthy {
+		resErr := msgjson.NewError(msgjson.RPCDegradedStatus, "degraded: %s", strings.Join(res.Problems, "; "))
+		return createResponse(statusRoute, res, resErr)
+	}
+	return createResponse(statusRoute, res, nil)
+}
+
 // format concatenates thing and tail. If thing is empty, returns an empty
 // string.
 func format(thing, tail string) string {
@@ -1423,6 +1850,24 @@ var helpMsgs = map[string]helpMsg{
   disable (bool): The wallet's status. e.g To disable a wallet set to "true", to enable set to "false".`,
 		returns: `Returns:
     string: The message "` + fmt.Sprintf(walletStatusStr, "[coin symbol]", "[wallet status]") + `".`,
+	},
+	reconfigureWalletRoute: {
+		pwArgsShort: `"appPass" ("newWalletPass")`,
+		argsShort:   `assetID walletType ("path" "settings")`,
+		cmdSummary:  `Change the type or settings of an existing wallet, and optionally its password.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.
+    newWalletPass (string): Optional. The wallet's new password. Leave empty to keep the current password.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index. e.g. 42 for DCR.
+      See https://github.com/satoshilabs/slips/blob/master/slip-0044.md
+    walletType (string): The wallet type.
+    path (string): Optional. The path to a configuration file.
+    settings (string): A JSON-encoded string->string mapping of additional
+       configuration settings. These settings take precedence over any settings
+       parsed from file.`,
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(walletReconfiguredStr, "[coin symbol]") + `"`,
 	},
 	walletsRoute: {
 		cmdSummary: `List all wallets.`,
@@ -1617,10 +2062,10 @@ var helpMsgs = map[string]helpMsg{
     string: The message "` + fmt.Sprintf(canceledOrderStr, "[order ID]") + `"`,
 	},
 	rescanWalletRoute: {
-		argsShort: `assetID (force)`,
+		argsShort: `assetID (force) (birthday)`,
 		cmdSummary: `Initiate a rescan of an asset's wallet. This is only supported for certain
-wallet types. Wallet resynchronization may be asynchronous, and the wallet
-state should be consulted for progress.
+wallet types. Wallet resynchronization may be asynchronous. Progress is
+reported with rescanProgress wallet notifications.
 
 WARNING: It is ill-advised to initiate a wallet rescan with active orders
 unless as a last ditch effort to get the wallet to recognize a transaction
@@ -1632,7 +2077,9 @@ needed to complete a swap.`,
       which wallet to withdraw from. e.g. 42 for DCR. See
       https://github.com/satoshilabs/slips/blob/master/slip-0044.md
     force (bool): Force a wallet rescan even if their are active orders. The
-      default is false.`,
+      default is false.
+    birthday (int): The unix time to rescan from. The default is the
+      wallet's birthday.`,
 	},
 	withdrawRoute: {
 		pwArgsShort: `"appPass"`,
@@ -1779,6 +2226,95 @@ needed to complete a swap.`,
     appPass (string): The Bison Wallet password.`,
 		returns: `Returns:
     string: The application's seed as hex.`,
+	},
+	exportAccountRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `host`,
+		cmdSummary: `Export a DEX account and its bonds. The result can be restored with
+  importaccount. Keep it safe, since it includes the account's private key.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    host (string): The DEX address.`,
+		returns: `Returns:
+  obj: The account and its bonds.
+  {
+    "account" (obj): {
+      "host" (string): The DEX address.
+      "accountID" (string): The account ID.
+      "privKey" (string): The account private key as hex.
+      "DEXPubKey" (string): The DEX's public key as hex.
+      "cert" (string): The DEX's TLS certificate.
+    },
+    "bonds" (array): The account's bonds.
+  }`,
+	},
+	recoveryKeysRoute: {
+		pwArgsShort: `"seed"`,
+		argsShort:   `"hosts" (acctKeys) ("bondAssets") (bondKeys)`,
+		cmdSummary: `Derive DEX account keys and bond keys from an app seed, so that
+  funds locked in bonds can be recovered with external tools if the client
+  database is lost. The DEX public key needed to derive the account keys is
+  retrieved from each DEX. The keys are not stored. Keep them safe.`,
+		pwArgsLong: `Password Args:
+    seed (string): The app seed, as a mnemonic or hex.`,
+		argsLong: `Args:
+    hosts (string): Comma-separated DEX addresses to derive account keys for.
+      May be empty.
+    acctKeys (int): Optional. The number of account keys to derive for each
+      DEX, from key index 0. The account key index is 0 unless the account
+      was replaced. Default is 1.
+    bondAssets (string): Optional. Comma-separated IDs of the assets to derive
+      bond keys for.
+    bondKeys (int): Optional. The number of bond keys to derive for each
+      asset, from bond index 0. Bond indexes are assigned sequentially for
+      each asset, regardless of the DEX. Default is 20.`,
+		returns: `Returns:
+  obj: The derived keys.
+  {
+    "accounts" (array): [
+      {
+        "host" (string): The DEX address.
+        "dexPubKey" (string): The DEX's public key as hex.
+        "keyIndex" (int): The account key index.
+        "accountID" (string): The account ID.
+        "privKey" (string): The account private key as hex.
+      },...
+    ],
+    "bonds" (array): [
+      {
+        "assetID" (int): The asset ID.
+        "symbol" (string): The asset's ticker symbol.
+        "bondIndex" (int): The bond key index.
+        "pubKey" (string): The bond public key as hex.
+        "privKey" (string): The bond private key as hex.
+      },...
+    ]
+  }`,
+	},
+	importAccountRoute: {
+		pwArgsShort: `"appPass"`,
+		argsShort:   `"account"`,
+		cmdSummary:  `Import a DEX account exported with exportaccount.`,
+		pwArgsLong: `Password Args:
+    appPass (string): The Bison Wallet password.`,
+		argsLong: `Args:
+    account (string): The JSON-encoded exportaccount result. bwctl reads it
+      from the file at the given path.`,
+		returns: `Returns:
+    string: The message "` + accountImportedStr + `"`,
+	},
+	takeActionRoute: {
+		argsShort: `assetID actionID ("action")`,
+		cmdSummary: `Resolve an action requested in an actionrequired notification, e.g. a
+  transaction that is stuck or a redemption that was rejected.`,
+		argsLong: `Args:
+    assetID (int): The asset ID from the notification.
+    actionID (string): The action ID from the notification.
+    action (string): Optional. The JSON-encoded action, which depends on the
+      actionID. e.g. '{"orderID":"...","coinID":"...","bump":true}'.`,
+		returns: `Returns:
+    string: The message "` + actionTakenStr + `"`,
 	},
 	walletPeersRoute: {
 		cmdSummary: `Show the peers a wallet is connected to.`,
@@ -1842,6 +2378,24 @@ needed to complete a swap.`,
 	mmStatusRoute: {
 		cmdSummary: `Get market making status.`,
 	},
+	updateBotConfigRoute: {
+		cmdSummary: `Save the config of a bot that is not running to the default market
+  maker config, from which the bot can then be started.`,
+		argsShort: `(cfgPath) (host) (baseID) (quoteID)`,
+		argsLong: `Args:
+		cfgPath (string): The path to a market maker config file with the bot's config.
+		host (string): The DEX address.
+		baseID (int): The base asset's BIP-44 registered coin index.
+		quoteID (int): The quote asset's BIP-44 registered coin index.`,
+	},
+	removeBotConfigRoute: {
+		cmdSummary: `Remove a bot's config from the default market maker config.`,
+		argsShort:  `(host) (baseID) (quoteID)`,
+		argsLong: `Args:
+		host (string): The DEX address.
+		baseID (int): The base asset's BIP-44 registered coin index.
+		quoteID (int): The quote asset's BIP-44 registered coin index.`,
+	},
 	updateRunningBotCfgRoute: {
 		cmdSummary: `Update the config and optionally the inventory of a running bot`,
 		argsShort:  `(cfgPath) (host) (baseID) (quoteID) (dexInventory) (cexInventory)`,
@@ -1943,7 +2497,7 @@ an spv wallet and enables options to view and set the vsp.
 	},
 	setVotingPreferencesRoute: {
 		argsShort:  `assetID (choicesMap) (tSpendPolicyMap) (treasuryPolicyMap)`,
-		cmdSummary: `Cancel an order.`,
+		cmdSummary: `Set the consensus vote choices, tSpend policies, and treasury policies for all live and future tickets.`,
 		argsLong: `Args:
   assetID (int): The asset's BIP-44 registered coin index.
   choicesMap ({"agendaid": "choiceid", ...}): A map of choices IDs to choice policies.
@@ -1951,6 +2505,66 @@ an spv wallet and enables options to view and set the vsp.
   treasuryPolicyMap ({"key": "policy", ...}): A map of treasury spender public keys to tSpend policies.`,
 		returns: `Returns:
   string: The message "` + setVotePrefsStr + `"`,
+	},
+	votingPreferencesRoute: {
+		cmdSummary: `Get the consensus vote choices, tSpend policies, and treasury policies used when tickets vote.`,
+		argsShort:  `assetID`,
+		argsLong: `Args:
+  assetID (int): The asset's BIP-44 registered coin index.`,
+		returns: `Returns:
+  obj: The voting policies.
+  {
+    agendas (array): An array of consensus vote choices.
+    [
+      {
+        id (string): The agenda ID,
+        description (string): A description of the agenda being voted on.
+        currentChoice (string): Your current choice.
+        choices ([{id: "string", description: "string"}, ...]): A description of the available choices.
+      },
+    ],...
+    tspends (array): An array of TSpend policies.
+    [
+      {
+        hash (string): The TSpend txid.,
+        value (int): The total value send in the tspend.,
+        currentValue (string): The policy.
+      },
+    ],...
+    treasuryKeys (array): An array of treasury policies.
+    [
+      {
+        key (string): The pubkey of the tspend creator.
+        policy (string): The policy.
+      },
+    ],...
+  }`,
+	},
+	mixingStatsRoute: {
+		cmdSummary: `Get the status of a wallet's funds mixer.`,
+		argsShort:  `assetID`,
+		argsLong: `Args:
+  assetID (int): The asset's BIP-44 registered coin index.`,
+		returns: `Returns:
+  obj: The mixing status.
+  {
+    enabled (bool): Whether the wallet is configured for funds mixing.
+    unmixedBalanceThreshold (int): The minimum amount of unmixed funds in atoms that can be mixed.
+    mixedFunds (int): The balance of the mixed account in atoms.
+    tradingFunds (int): The balance of the trading account in atoms.
+    unmixedFunds (int): The balance of the unmixed account in atoms.
+    queuedDenominations (array): The mixed outputs expected from the next mix.
+    [
+      {
+        value (int): The output value in atoms.
+        count (int): The number of outputs.
+      },
+    ],...
+    lastMix (int): The unix time of the last successful mix, or zero if there
+      hasn't been one since the wallet was started.
+    mixFailures (int): The number of consecutive failed mixes.
+    lastMixError (string): The error from the last mix, if it failed.
+  }`,
 	},
 	txHistoryRoute: {
 		argsShort:  `assetID (n) (refTxID) (past)`,
@@ -2015,4 +2629,105 @@ an spv wallet and enables options to view and set the vsp.
 		past (bool): If true, the transactions before the reference tx will be returned. If false, the
 		transactions after the reference tx will be returned.`,
 	},
+	orderHistoryRoute: {
+		argsShort: `("filter")`,
+		cmdSummary: `Fetch a page of the user's order history, newest first. Unlike
+    myorders, archived orders are included.`,
+		argsLong: `Args:
+    filter (string): Optional. A JSON object filtering the orders. Every field
+      is optional.
+      {
+        "n" (int): The number of orders in the page. Default is 20, maximum is 500.
+        "offset" (string): The "next" order ID of the previous page.
+        "hosts" (array): The DEX addresses.
+        "assets" (array): BIP-44 coin indexes of either asset of the market.
+        "market" (object): The market, {"baseID" (int), "quoteID" (int)}.
+        "statuses" (array): The order statuses. "epoch", "booked", "executed",
+          "canceled", or "revoked".
+        "since" (int): Orders last updated at or after this time, in
+          milliseconds since 00:00:00 Jan 1 1970.
+        "until" (int): Orders last updated before this time, in milliseconds.
+      }`,
+		returns: `Returns:
+  obj: The orders.
+  {
+    "orders" (array): The orders, as returned by myorders.
+    "next" (string): The offset for the next page. Omitted if this is the last page.
+  }`,
+	},
+	walletHistoryRoute: {
+		argsShort: `assetID ("filter")`,
+		cmdSummary: `Fetch a page of a wallet's transaction history, newest first. Swaps,
+    redemptions and refunds are annotated with the matches they settled.`,
+		argsLong: `Args:
+    assetID (int): The asset's BIP-44 registered coin index.
+    filter (string): Optional. A JSON object filtering the transactions. Every
+      field is optional.
+      {
+        "n" (int): The number of transactions in the page. Default is 50,
+          maximum is 500.
+        "refID" (string): The "next" transaction ID of the previous page.
+        "types" (array): The transaction types, e.g. "send", "receive", "swap",
+          "redeem", "refund", "createBond" or "redeemBond".
+        "since" (int): Transactions mined at or after this time, in seconds
+          since 00:00:00 Jan 1 1970.
+        "until" (int): Transactions mined before this time, in seconds.
+          Unconfirmed transactions are excluded.
+      }`,
+		returns: `Returns:
+  obj: The transactions.
+  {
+    "txs" (array): The transactions, as returned by txhistory, with the
+      "typeName" of the type and, for trade transactions, "dex" (array):
+      [
+        {
+          "host" (string): The DEX address.
+          "market" (string): The market.
+          "orderID" (string): The order ID.
+          "matchID" (string): The match ID.
+          "sell" (bool): Whether the order is a sell.
+          "rate" (int): The match rate.
+          "qty" (int): The match quantity.
+          "role" (string): "swap", "redeem" or "refund".
+        },...
+      ]
+    "next" (string): The refID for the next page. Omitted if this is the last page.
+  }`,
+	},
+	statusRoute: {
+		cmdSummary: `Check the health of the client. If there are any problems, an
+    error listing them is returned with the status, so bwctl exits with a
+    nonzero status. Problems are a database error, an enabled wallet that is
+    not running, syncing, without peers or locked, an enabled DEX that is not
+    connected, and pending actions.`,
+		returns: `Returns:
+  obj: The status.
+  {
+    "healthy" (bool): Whether there are no problems.
+    "problems" (array): The problems, if any.
+    "db" (obj): {"ok" (bool), "error" (string)}.
+    "wallets" (array): [
+      {
+        "assetID" (int): The asset's BIP-44 registered coin index.
+        "symbol" (string): The asset's symbol.
+        "disabled" (bool): Whether the wallet is disabled.
+        "running" (bool): Whether the wallet is running.
+        "open" (bool): Whether the wallet is unlocked.
+        "synced" (bool): Whether the wallet is synced.
+        "syncProgress" (float): The sync progress, 0 to 1.
+        "peerCount" (int): The number of peers.
+      },...
+    ]
+    "exchanges" (array): [
+      {
+        "host" (string): The DEX address.
+        "disabled" (bool): Whether the account is disabled.
+        "status" (string): "connected", "disconnected" or "invalid certificate".
+      },...
+    ]
+    "pendingActions" (array): The actions awaiting a response with takeaction,
+      [{"assetID" (int), "actionID" (string), "uniqueID" (string)},...]
+    "activeBots" (int): The number of running market making bots.
+  }`,
+	},
 }
@@ -4,6 +4,7 @@
 package rpcserver
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -13,11 +14,13 @@ import (
 	"testing"
 
 	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/websocket"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
 	"github.com/davecgh/go-spew/spew"
 )
 
@@ -1343,6 +1346,84 @@ func TestHandleStakeStatus(t *testing.T) {
 	}
 }
 
+func TestHandleVotingPreferences(t *testing.T) {
+	params := &RawParams{
+		Args: []string{
+			"42",
+		},
+	}
+	tests := []struct {
+		name           string
+		params         *RawParams
+		stakeStatusErr error
+		wantErrCode    int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:           "core.StakeStatus error",
+		params:         params,
+		stakeStatusErr: errors.New("error"),
+		wantErrCode:    msgjson.RPCStakeStatusError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			stakeStatus:    &asset.TicketStakingStatus{},
+			stakeStatusErr: test.stakeStatusErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleVotingPreferences(r, test.params)
+		res := new(asset.Stances)
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestHandleMixingStats(t *testing.T) {
+	params := &RawParams{
+		Args: []string{
+			"42",
+		},
+	}
+	tests := []struct {
+		name           string
+		params         *RawParams
+		mixingStatsErr error
+		wantErrCode    int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:           "core.FundsMixingStats error",
+		params:         params,
+		mixingStatsErr: errors.New("error"),
+		wantErrCode:    msgjson.RPCMixingStatsError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			mixingStats:    &asset.FundsMixingStats{Enabled: true},
+			mixingStatsErr: test.mixingStatsErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleMixingStats(r, test.params)
+		res := new(asset.FundsMixingStats)
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 func TestHandleSetVotingPreferences(t *testing.T) {
 	params := &RawParams{
 		Args: []string{
@@ -1382,3 +1463,624 @@ func TestHandleSetVotingPreferences(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleRecoveryKeys(t *testing.T) {
+	seed := encode.PassBytes("seed")
+	tests := []struct {
+		name            string
+		params          *RawParams
+		recoveryKeysErr error
+		wantErrCode     int
+	}{{
+		name: "ok",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{seed},
+			Args:   []string{"dex:1234,dex2:1234", "2", "42,0", "5"},
+		},
+		wantErrCode: -1,
+	}, {
+		name: "ok bonds only",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{seed},
+			Args:   []string{"", "", "42"},
+		},
+		wantErrCode: -1,
+	}, {
+		name: "core.RecoveryKeys error",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{seed},
+			Args:   []string{"dex:1234"},
+		},
+		recoveryKeysErr: errors.New("error"),
+		wantErrCode:     msgjson.RPCRecoveryKeysError,
+	}, {
+		name: "no hosts or bond assets",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{seed},
+			Args:   []string{""},
+		},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name: "bad acctKeys",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{seed},
+			Args:   []string{"dex:1234", "abc"},
+		},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name: "bad bond asset",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{seed},
+			Args:   []string{"", "1", "btc"},
+		},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			recoveryKeys:    &core.RecoveryKeys{},
+			recoveryKeysErr: test.recoveryKeysErr,
+		}
+		r := &RPCServer{core: tc}
+		payload := handleRecoveryKeys(r, test.params)
+		res := new(core.RecoveryKeys)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestParseRecoveryKeysArgs(t *testing.T) {
+	form, err := parseRecoveryKeysArgs(&RawParams{
+		PWArgs: []encode.PassBytes{encode.PassBytes("seed")},
+		Args:   []string{" dex:1234, ,dex2:1234", "", "42, 0"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if form.Seed != "seed" {
+		t.Fatalf("wrong seed %q", form.Seed)
+	}
+	if len(form.Hosts) != 2 || form.Hosts[0] != "dex:1234" || form.Hosts[1] != "dex2:1234" {
+		t.Fatalf("wrong hosts %v", form.Hosts)
+	}
+	if len(form.BondAssets) != 2 || form.BondAssets[0] != 42 || form.BondAssets[1] != 0 {
+		t.Fatalf("wrong bond assets %v", form.BondAssets)
+	}
+	if form.AcctKeys != defaultRecoveryAcctKeys || form.BondKeys != defaultRecoveryBondKeys {
+		t.Fatalf("wrong defaults %d, %d", form.AcctKeys, form.BondKeys)
+	}
+}
+
+func TestHandleExportAccount(t *testing.T) {
+	params := &RawParams{
+		PWArgs: []encode.PassBytes{encode.PassBytes("abc")},
+		Args:   []string{"dex:1234"},
+	}
+	tests := []struct {
+		name             string
+		params           *RawParams
+		accountExportErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:             "core.AccountExport error",
+		params:           params,
+		accountExportErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCAccountExportError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{accountExportErr: test.accountExportErr}
+		r := &RPCServer{core: tc}
+		payload := handleExportAccount(r, test.params)
+		res := new(accountExport)
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 {
+			if res.Account == nil || res.Account.Host != "dex:1234" {
+				t.Fatalf("%s: wrong account %+v", test.name, res.Account)
+			}
+			if res.Bonds == nil {
+				t.Fatalf("%s: nil bonds", test.name)
+			}
+		}
+	}
+}
+
+func TestHandleImportAccount(t *testing.T) {
+	pw := encode.PassBytes("abc")
+	params := &RawParams{
+		PWArgs: []encode.PassBytes{pw},
+		Args:   []string{`{"account":{"host":"dex:1234"},"bonds":[]}`},
+	}
+	tests := []struct {
+		name             string
+		params           *RawParams
+		accountImportErr error
+		wantErrCode      int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:             "core.AccountImport error",
+		params:           params,
+		accountImportErr: errors.New("error"),
+		wantErrCode:      msgjson.RPCAccountImportError,
+	}, {
+		name: "no account",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{pw},
+			Args:   []string{`{"bonds":[]}`},
+		},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name: "bad JSON",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{pw},
+			Args:   []string{`{`},
+		},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{accountImportErr: test.accountImportErr}
+		r := &RPCServer{core: tc}
+		payload := handleImportAccount(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && tc.importedAccount.Host != "dex:1234" {
+			t.Fatalf("%s: wrong account imported", test.name)
+		}
+	}
+}
+
+func TestHandleReconfigureWallet(t *testing.T) {
+	appPass := encode.PassBytes("abc")
+	tests := []struct {
+		name                 string
+		params               *RawParams
+		reconfigureWalletErr error
+		wantNewPW            bool
+		wantErrCode          int
+	}{{
+		name: "ok",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{appPass},
+			Args:   []string{"42", "rpc", "username=tacotime", `{"password":"abc"}`},
+		},
+		wantErrCode: -1,
+	}, {
+		name: "ok with empty new password",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{appPass, {}},
+			Args:   []string{"42", "rpc"},
+		},
+		wantErrCode: -1,
+	}, {
+		name: "ok with new password",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{appPass, encode.PassBytes("def")},
+			Args:   []string{"42", "rpc"},
+		},
+		wantNewPW:   true,
+		wantErrCode: -1,
+	}, {
+		name: "core.ReconfigureWallet error",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{appPass},
+			Args:   []string{"42", "rpc"},
+		},
+		reconfigureWalletErr: errors.New("error"),
+		wantErrCode:          msgjson.RPCReconfigureWalletError,
+	}, {
+		name: "bad params",
+		params: &RawParams{
+			PWArgs: []encode.PassBytes{appPass},
+			Args:   []string{"asdf", "rpc"},
+		},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{reconfigureWalletErr: test.reconfigureWalletErr}
+		r := &RPCServer{core: tc}
+		payload := handleReconfigureWallet(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		if (tc.reconfigureWalletPW != nil) != test.wantNewPW {
+			t.Fatalf("%s: wanted new password = %t", test.name, test.wantNewPW)
+		}
+		form := tc.reconfigureWalletForm
+		if form.AssetID != 42 || form.Type != "rpc" {
+			t.Fatalf("%s: wrong wallet form %+v", test.name, form)
+		}
+		if len(test.params.Args) > 3 && (form.Config["username"] != "tacotime" || form.Config["password"] != "abc") {
+			t.Fatalf("%s: wrong wallet config %v", test.name, form.Config)
+		}
+	}
+}
+
+func TestHandleTakeAction(t *testing.T) {
+	params := &RawParams{
+		Args: []string{"60", "tooCheap", `{"txID":"abc","bump":true}`},
+	}
+	tests := []struct {
+		name          string
+		params        *RawParams
+		takeActionErr error
+		wantErrCode   int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name: "ok without action",
+		params: &RawParams{
+			Args: []string{"60", "tooCheap"},
+		},
+		wantErrCode: -1,
+	}, {
+		name:          "core.TakeAction error",
+		params:        params,
+		takeActionErr: errors.New("error"),
+		wantErrCode:   msgjson.RPCTakeActionError,
+	}, {
+		name: "bad action",
+		params: &RawParams{
+			Args: []string{"60", "tooCheap", "{"},
+		},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad params",
+		params:      &RawParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{takeActionErr: test.takeActionErr}
+		r := &RPCServer{core: tc}
+		payload := handleTakeAction(r, test.params)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && tc.actionID != "tooCheap" {
+			t.Fatalf("%s: wrong action ID %q", test.name, tc.actionID)
+		}
+	}
+}
+
+func TestHandleOrderHistory(t *testing.T) {
+	oid := dex.Bytes(encode.RandomBytes(order.OrderIDSize))
+	ords := []*core.Order{{
+		ID:     oid,
+		Host:   "dex.com",
+		BaseID: 42,
+		Type:   order.LimitOrderType,
+		Status: order.OrderStatusExecuted,
+	}}
+	tests := []struct {
+		name        string
+		params      *RawParams
+		ordersErr   error
+		wantFilter  *core.OrderFilter
+		wantNext    bool
+		wantErrCode int
+	}{{
+		name:        "ok no filter",
+		params:      &RawParams{},
+		wantFilter:  &core.OrderFilter{N: defaultOrderHistoryN},
+		wantErrCode: -1,
+	}, {
+		name: "ok full page",
+		params: &RawParams{Args: []string{fmt.Sprintf(`{"n":1,"offset":"%s","hosts":["dex.com"],"market":{"baseID":42,"quoteID":0},`+
+			`"statuses":["executed","canceled"],"since":1000,"until":2000}`, oid.String())}},
+		wantFilter: &core.OrderFilter{
+			N:        1,
+			Offset:   oid,
+			Hosts:    []string{"dex.com"},
+			Statuses: []order.OrderStatus{order.OrderStatusExecuted, order.OrderStatusCanceled},
+			Since:    1000,
+			Until:    2000,
+		},
+		wantNext:    true,
+		wantErrCode: -1,
+	}, {
+		name:        "core.Orders error",
+		params:      &RawParams{},
+		ordersErr:   errors.New("error"),
+		wantErrCode: msgjson.RPCOrderHistoryError,
+	}, {
+		name:        "bad JSON",
+		params:      &RawParams{Args: []string{"{"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "n too large",
+		params:      &RawParams{Args: []string{`{"n":501}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad offset",
+		params:      &RawParams{Args: []string{`{"offset":"abcd"}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "unknown status",
+		params:      &RawParams{Args: []string{`{"statuses":["open"]}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad time range",
+		params:      &RawParams{Args: []string{`{"since":2000,"until":1000}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{orders: ords, ordersErr: test.ordersErr}
+		r := &RPCServer{core: tc}
+		payload := handleOrderHistory(r, test.params)
+		res := new(orderHistoryResponse)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		f := tc.orderFilter
+		if f.N != test.wantFilter.N || !bytes.Equal(f.Offset, test.wantFilter.Offset) ||
+			fmt.Sprint(f.Hosts) != fmt.Sprint(test.wantFilter.Hosts) ||
+			fmt.Sprint(f.Statuses) != fmt.Sprint(test.wantFilter.Statuses) ||
+			f.Since != test.wantFilter.Since || f.Until != test.wantFilter.Until {
+			t.Fatalf("%s: wrong filter %+v", test.name, f)
+		}
+		if len(res.Orders) != 1 || res.Orders[0].ID != oid.String() || res.Orders[0].Status != "executed" {
+			t.Fatalf("%s: wrong orders %+v", test.name, res.Orders)
+		}
+		if test.wantNext != (len(res.Next) > 0) || (test.wantNext && !bytes.Equal(res.Next, oid)) {
+			t.Fatalf("%s: wrong next %x", test.name, res.Next)
+		}
+	}
+}
+
+func TestHandleWalletHistory(t *testing.T) {
+	// The first transaction is unconfirmed. Every third is a swap.
+	txs := make([]*asset.WalletTransaction, 150)
+	for i := range txs {
+		tx := &asset.WalletTransaction{ID: fmt.Sprintf("tx%03d", i), Type: asset.Receive}
+		if i > 0 {
+			tx.Timestamp = 10000 - uint64(i)
+		}
+		if i > 0 && i%3 == 0 {
+			tx.Type = asset.Swap
+		}
+		txs[i] = tx
+	}
+	oid := encode.RandomBytes(order.OrderIDSize)
+	mid := encode.RandomBytes(order.MatchIDSize)
+	ords := []*core.Order{{
+		ID:       oid,
+		Host:     "dex.com",
+		MarketID: "dcr_btc",
+		Sell:     true,
+		Matches: []*core.Match{{
+			MatchID: mid,
+			Rate:    2e6,
+			Qty:     1e8,
+			Swap:    &core.Coin{AssetID: 42, StringID: "TX003:1"},
+			// The redeem is on the other chain.
+			Redeem: &core.Coin{AssetID: 0, StringID: "tx006:0"},
+		}},
+	}}
+	tests := []struct {
+		name        string
+		params      *RawParams
+		txHistErr   error
+		ordersErr   error
+		wantIDs     []string
+		wantNext    string
+		wantErrCode int
+	}{{
+		name:        "ok no filter",
+		params:      &RawParams{Args: []string{"42"}},
+		wantNext:    "tx049",
+		wantErrCode: -1,
+	}, {
+		name:        "ok time range",
+		params:      &RawParams{Args: []string{"42", `{"since":9980,"until":9990}`}},
+		wantIDs:     []string{"tx011", "tx012", "tx013", "tx014", "tx015", "tx016", "tx017", "tx018", "tx019", "tx020"},
+		wantErrCode: -1,
+	}, {
+		name:        "ok refID",
+		params:      &RawParams{Args: []string{"42", `{"n":1,"refID":"tx049"}`}},
+		wantIDs:     []string{"tx050"},
+		wantNext:    "tx050",
+		wantErrCode: -1,
+	}, {
+		name:        "core.TxHistory error",
+		params:      &RawParams{Args: []string{"42"}},
+		txHistErr:   errors.New("error"),
+		wantErrCode: msgjson.RPCTxHistoryError,
+	}, {
+		name:        "core.Orders error",
+		params:      &RawParams{Args: []string{"42"}},
+		ordersErr:   errors.New("error"),
+		wantErrCode: msgjson.RPCTxHistoryError,
+	}, {
+		name:        "bad asset ID",
+		params:      &RawParams{Args: []string{"dcr"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "n too large",
+		params:      &RawParams{Args: []string{"42", `{"n":501}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "unknown type",
+		params:      &RawParams{Args: []string{"42", `{"types":["trade"]}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad time range",
+		params:      &RawParams{Args: []string{"42", `{"since":2000,"until":1000}`}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{txs: txs, txHistoryErr: test.txHistErr, orders: ords, ordersErr: test.ordersErr}
+		r := &RPCServer{core: tc}
+		payload := handleWalletHistory(r, test.params)
+		res := new(walletHistoryResponse)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		if res.Next != test.wantNext {
+			t.Fatalf("%s: wrong next %q", test.name, res.Next)
+		}
+		if test.wantIDs != nil {
+			ids := make([]string, 0, len(res.Txs))
+			for _, tx := range res.Txs {
+				ids = append(ids, tx.ID)
+			}
+			if !reflect.DeepEqual(ids, test.wantIDs) {
+				t.Fatalf("%s: wrong txs %v", test.name, ids)
+			}
+		}
+		for _, tx := range res.Txs {
+			wantType := "receive"
+			if tx.Type == asset.Swap {
+				wantType = "swap"
+			}
+			if tx.TypeName != wantType {
+				t.Fatalf("%s: wrong type name %q for %s", test.name, tx.TypeName, tx.ID)
+			}
+			if tx.ID != "tx003" {
+				if len(tx.DEX) != 0 {
+					t.Fatalf("%s: unexpected annotation for %s", test.name, tx.ID)
+				}
+				continue
+			}
+			if len(tx.DEX) != 1 || tx.DEX[0].Host != "dex.com" || tx.DEX[0].Role != "swap" ||
+				!bytes.Equal(tx.DEX[0].OrderID, oid) || !bytes.Equal(tx.DEX[0].MatchID, mid) {
+				t.Fatalf("%s: wrong annotation %+v", test.name, tx.DEX)
+			}
+		}
+	}
+	// The swaps span more than one scan.
+	payload := handleWalletHistory(&RPCServer{core: &TCore{txs: txs}}, &RawParams{Args: []string{"42", `{"n":60,"types":["swap"]}`}})
+	res := new(walletHistoryResponse)
+	if err := verifyResponse(payload, res, -1); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Txs) != 49 || res.Txs[48].ID != "tx147" {
+		t.Fatalf("wrong swaps, got %d", len(res.Txs))
+	}
+}
+
+func TestHandleStatus(t *testing.T) {
+	healthyWallets := func() []*core.WalletState {
+		return []*core.WalletState{
+			{AssetID: 42, Symbol: "dcr", Running: true, Open: true, Synced: true, PeerCount: 8,
+				SyncStatus: &asset.SyncStatus{Synced: true, TargetHeight: 100, Blocks: 100}},
+			// Disabled wallets are not a problem.
+			{AssetID: 0, Symbol: "btc", Disabled: true},
+		}
+	}
+	healthyExchanges := func() map[string]*core.Exchange {
+		return map[string]*core.Exchange{
+			"dex.com":  {Host: "dex.com", ConnectionStatus: comms.Connected},
+			"dex2.com": {Host: "dex2.com", Disabled: true},
+		}
+	}
+	tests := []struct {
+		name         string
+		params       *RawParams
+		modify       func(tc *TCore)
+		wantProblems int
+		wantErrCode  int
+	}{{
+		name:        "healthy",
+		params:      &RawParams{},
+		wantErrCode: -1,
+	}, {
+		name:   "db error",
+		params: &RawParams{},
+		modify: func(tc *TCore) {
+			tc.checkDBErr = errors.New("error")
+		},
+		wantProblems: 1,
+		wantErrCode:  msgjson.RPCDegradedStatus,
+	}, {
+		name:   "wallet syncing and locked",
+		params: &RawParams{},
+		modify: func(tc *TCore) {
+			tc.wallets[0].Synced = false
+			tc.wallets = append(tc.wallets, &core.WalletState{AssetID: 60, Symbol: "eth", Running: true, Synced: true, PeerCount: 1})
+		},
+		wantProblems: 2,
+		wantErrCode:  msgjson.RPCDegradedStatus,
+	}, {
+		name:   "wallet not running",
+		params: &RawParams{},
+		modify: func(tc *TCore) {
+			tc.wallets[0].Running = false
+		},
+		wantProblems: 1,
+		wantErrCode:  msgjson.RPCDegradedStatus,
+	}, {
+		name:   "no peers",
+		params: &RawParams{},
+		modify: func(tc *TCore) {
+			tc.wallets[0].PeerCount = 0
+		},
+		wantProblems: 1,
+		wantErrCode:  msgjson.RPCDegradedStatus,
+	}, {
+		name:   "dex disconnected and pending action",
+		params: &RawParams{},
+		modify: func(tc *TCore) {
+			tc.exchanges["dex.com"].ConnectionStatus = comms.Disconnected
+			tc.pendingActions = []*asset.ActionRequiredNote{{UniqueID: "abc", ActionID: "redeemRejected"}}
+		},
+		wantProblems: 2,
+		wantErrCode:  msgjson.RPCDegradedStatus,
+	}, {
+		name:        "args",
+		params:      &RawParams{Args: []string{"dcr"}},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{wallets: healthyWallets(), exchanges: healthyExchanges()}
+		if test.modify != nil {
+			test.modify(tc)
+		}
+		r := &RPCServer{core: tc}
+		payload := handleStatus(r, test.params)
+		// The status accompanies the degraded status error.
+		res := new(healthStatus)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == msgjson.RPCArgumentsError {
+			continue
+		}
+		if res.Healthy != (test.wantProblems == 0) || len(res.Problems) != test.wantProblems {
+			t.Fatalf("%s: wrong problems %v", test.name, res.Problems)
+		}
+		if len(res.Wallets) != len(tc.wallets) || len(res.Exchanges) != 2 || res.Exchanges[0].Host != "dex.com" ||
+			len(res.PendingActions) != len(tc.pendingActions) || res.DB.OK != (tc.checkDBErr == nil) {
+			t.Fatalf("%s: wrong status %+v", test.name, res)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package rpcserver
 
 import (
+	"bytes"
 	"context"
 	"crypto/elliptic"
 	"crypto/sha256"
@@ -15,12 +16,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/authlimit"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
@@ -44,6 +48,9 @@ const (
 	// rpcTimeoutSeconds is the number of seconds a connection to the RPC server
 	// is allowed to stay open without authenticating before it is closed.
 	rpcTimeoutSeconds = 10
+
+	// maxBatchSize is the maximum number of requests in a batch.
+	maxBatchSize = 100
 )
 
 var (
@@ -75,7 +82,7 @@ type clientCore interface {
 	Trade(appPass []byte, form *core.TradeForm) (order *core.Order, err error)
 	Wallets() (walletsStates []*core.WalletState)
 	WalletState(assetID uint32) *core.WalletState
-	RescanWallet(assetID uint32, force bool) error
+	RescanWallet(assetID uint32, force bool, bday uint64) error
 	Send(appPass []byte, assetID uint32, value uint64, addr string, subtract bool) (asset.Coin, error)
 	ExportSeed(pw []byte) (string, error)
 	DeleteArchivedRecords(olderThan *time.Time, matchesFileStr, ordersFileStr string) (int, error)
@@ -99,6 +106,16 @@ type clientCore interface {
 	PurchaseTickets(assetID uint32, pw []byte, n int) error
 	SetVotingPreferences(assetID uint32, choices, tSpendPolicy, treasuryPolicy map[string]string) error
 	GenerateBCHRecoveryTransaction(appPW []byte, recipient string) ([]byte, error)
+	FundsMixingStats(assetID uint32) (*asset.FundsMixingStats, error)
+	AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error)
+	RecoveryKeys(form *core.RecoveryKeysForm) (*core.RecoveryKeys, error)
+	AccountImport(pw []byte, account *core.Account, bonds []*db.Bond) error
+	ReconfigureWallet(appPW, newWalletPW []byte, form *core.WalletForm) error
+	TakeAction(assetID uint32, actionID string, actionB json.RawMessage) error
+	NotificationFeed() *core.NoteFeed
+	Orders(filter *core.OrderFilter) ([]*core.Order, error)
+	PendingActions() []*asset.ActionRequiredNote
+	CheckDB() error
 }
 
 // RPCServer is a single-client http and websocket server enabling a JSON
@@ -112,9 +129,14 @@ type RPCServer struct {
 	tlsConfig *tls.Config
 	srv       *http.Server
 	authSHA   [32]byte
-	wg        sync.WaitGroup
-	bwVersion *SemVersion
-	ctx       context.Context
+	authMode  string
+	// clientCerts are the allowed client certificates by fingerprint, for
+	// the cert auth modes.
+	clientCerts map[string]*clientPerms
+	limiter     *authlimit.Limiter
+	wg          sync.WaitGroup
+	bwVersion   *SemVersion
+	ctx         context.Context
 }
 
 // genCertPair generates a key/cert pair to the paths provided.
@@ -180,6 +202,10 @@ func (s *RPCServer) handleJSON(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "error reading request body", http.StatusBadRequest)
 		return
 	}
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		s.handleBatch(w, body, clientPermsFromContext(r.Context()))
+		return
+	}
 	req := new(msgjson.Message)
 	err = json.Unmarshal(body, req)
 	if err != nil {
@@ -190,7 +216,49 @@ func (s *RPCServer) handleJSON(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Responses not accepted", http.StatusMethodNotAllowed)
 		return
 	}
-	s.parseHTTPRequest(w, req)
+	s.parseHTTPRequest(w, req, clientPermsFromContext(r.Context()))
+}
+
+// handleBatch handles a JSON array of requests. The requests are handled in
+// order, and the response is an array with the response to each request.
+// Problems with an item are reported in its response rather than failing the
+// batch.
+func (s *RPCServer) handleBatch(w http.ResponseWriter, body []byte, perms *clientPerms) {
+	var reqs []*msgjson.Message
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		http.Error(w, "JSON decode error", http.StatusUnprocessableEntity)
+		return
+	}
+	if len(reqs) == 0 {
+		http.Error(w, "empty batch", http.StatusBadRequest)
+		return
+	}
+	if len(reqs) > maxBatchSize {
+		http.Error(w, fmt.Sprintf("batch exceeds %d requests", maxBatchSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	resps := make([]*msgjson.Message, 0, len(reqs))
+	for _, req := range reqs {
+		var id uint64
+		if req != nil {
+			id = req.ID
+		}
+		payload := new(msgjson.ResponsePayload)
+		if req == nil || req.Type != msgjson.Request {
+			payload.Error = msgjson.NewError(msgjson.RPCParseError, "batch items must be requests")
+		} else {
+			payload = s.handleRequest(req, perms)
+		}
+		resp, err := msgjson.NewResponse(id, payload.Result, payload.Error)
+		if err != nil {
+			msg := fmt.Sprintf("error encoding response: %v", err)
+			http.Error(w, msg, http.StatusInternalServerError)
+			log.Errorf("handleBatch: NewResponse failed: %s", msg)
+			return
+		}
+		resps = append(resps, resp)
+	}
+	writeJSON(w, resps)
 }
 
 // Config holds variables needed to create a new RPC Server.
@@ -200,6 +268,12 @@ type Config struct {
 	Addr, User, Pass, Cert, Key string
 	BWVersion                   *SemVersion
 	CertHosts                   []string
+	// AuthMode is AuthModePassword, AuthModeCert or AuthModeCertAndPassword.
+	// The default is AuthModePassword.
+	AuthMode string
+	// ClientCerts is the path of the client certificate allow-list file,
+	// required for the cert auth modes.
+	ClientCerts string
 }
 
 // SetLogger sets the logger for the RPCServer package.
@@ -209,8 +283,26 @@ func SetLogger(logger dex.Logger) {
 
 // New is the constructor for an RPCServer.
 func New(cfg *Config) (*RPCServer, error) {
+	authMode := cfg.AuthMode
+	if authMode == "" {
+		authMode = AuthModePassword
+	}
+	var clientCerts map[string]*clientPerms
+	switch authMode {
+	case AuthModePassword:
+	case AuthModeCert, AuthModeCertAndPassword:
+		if cfg.ClientCerts == "" {
+			return nil, fmt.Errorf("missing client certificates file for auth mode %q", authMode)
+		}
+		var err error
+		if clientCerts, err = loadClientCerts(cfg.ClientCerts); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q", authMode)
+	}
 
-	if cfg.Pass == "" {
+	if cfg.Pass == "" && authMode != AuthModeCert {
 		return nil, fmt.Errorf("missing RPC password")
 	}
 
@@ -236,6 +328,11 @@ func New(cfg *Config) (*RPCServer, error) {
 		Certificates: []tls.Certificate{keypair},
 		MinVersion:   tls.VersionTLS12,
 	}
+	if clientCerts != nil {
+		// Client certificates are checked against the allow-list by
+		// fingerprint, so they may be self-signed.
+		tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	}
 
 	// Create an HTTP router.
 	mux := chi.NewRouter()
@@ -247,14 +344,17 @@ func New(cfg *Config) (*RPCServer, error) {
 
 	// Make the server.
 	s := &RPCServer{
-		core:      cfg.Core,
-		mm:        cfg.MarketMaker,
-		mux:       mux,
-		srv:       httpServer,
-		addr:      cfg.Addr,
-		tlsConfig: tlsConfig,
-		bwVersion: cfg.BWVersion,
-		wsServer:  websocket.New(cfg.Core, log.SubLogger("WS")),
+		core:        cfg.Core,
+		mm:          cfg.MarketMaker,
+		mux:         mux,
+		srv:         httpServer,
+		addr:        cfg.Addr,
+		tlsConfig:   tlsConfig,
+		bwVersion:   cfg.BWVersion,
+		wsServer:    websocket.New(cfg.Core, log.SubLogger("WS")),
+		limiter:     authlimit.New(log.SubLogger("AUTH")),
+		authMode:    authMode,
+		clientCerts: clientCerts,
 	}
 
 	// Create authSHA to verify requests against.
@@ -265,7 +365,8 @@ func New(cfg *Config) (*RPCServer, error) {
 
 	// Middleware
 	mux.Use(middleware.Recoverer)
-	mux.Use(middleware.RealIP)
+	// Don't use middleware.RealIP. Failed authentication attempts are limited
+	// by source IP, which must not be set by client-supplied headers.
 	mux.Use(s.authMiddleware)
 
 	// The WebSocket handler is mounted on /ws in Connect.
@@ -300,8 +401,19 @@ func (s *RPCServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 		}
 	}()
 
+	// Send notifications to the websocket clients' subscriptions.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.readNotifications(ctx)
+	}()
+
 	// Configure the websocket handler before starting the server.
 	s.mux.Get("/ws", func(w http.ResponseWriter, r *http.Request) {
+		if !clientPermsFromContext(r.Context()).allowed(helpRoute) {
+			http.Error(w, "the read scope is required for websocket connections", http.StatusForbidden)
+			return
+		}
 		s.wsServer.HandleConnect(ctx, w, r)
 	})
 
@@ -320,8 +432,26 @@ func (s *RPCServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	return &s.wg, nil
 }
 
+// readNotifications reads from the Core notification channel and sends the
+// notifications to the websocket clients with a matching subscription.
+func (s *RPCServer) readNotifications(ctx context.Context) {
+	ch := s.core.NotificationFeed()
+	defer ch.ReturnFeed()
+
+	for {
+		select {
+		case n := <-ch.C:
+			s.wsServer.NotifySubscribers(n)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 // handleRequest sends the request to the correct handler function if able.
-func (s *RPCServer) handleRequest(req *msgjson.Message) *msgjson.ResponsePayload {
+// The route must be allowed by the permissions of certificate authenticated
+// clients.
+func (s *RPCServer) handleRequest(req *msgjson.Message, perms *clientPerms) *msgjson.ResponsePayload {
 	payload := new(msgjson.ResponsePayload)
 	if req.Route == "" {
 		log.Debugf("route not specified")
@@ -337,6 +467,12 @@ func (s *RPCServer) handleRequest(req *msgjson.Message) *msgjson.ResponsePayload
 		return payload
 	}
 
+	if !perms.allowed(req.Route) {
+		log.Warnf("client %q is not allowed to use route %s", perms.name, req.Route)
+		payload.Error = msgjson.NewError(msgjson.RPCPermissionDenied, "the %s scope is required for %s", routeScopes[req.Route], req.Route)
+		return payload
+	}
+
 	params := new(RawParams)
 	err := req.Unmarshal(params) // NOT &params to prevent setting it to nil for []byte("null") Payload
 	if err != nil {
@@ -350,8 +486,8 @@ func (s *RPCServer) handleRequest(req *msgjson.Message) *msgjson.ResponsePayload
 
 // parseHTTPRequest parses the msgjson message in the request body, creates a
 // response message, and writes it to the http.ResponseWriter.
-func (s *RPCServer) parseHTTPRequest(w http.ResponseWriter, req *msgjson.Message) {
-	payload := s.handleRequest(req)
+func (s *RPCServer) parseHTTPRequest(w http.ResponseWriter, req *msgjson.Message, perms *clientPerms) {
+	payload := s.handleRequest(req, perms)
 	resp, err := msgjson.NewResponse(req.ID, payload.Result, payload.Error)
 	if err != nil {
 		msg := fmt.Sprintf("error encoding response: %v", err)
@@ -362,9 +498,30 @@ func (s *RPCServer) parseHTTPRequest(w http.ResponseWriter, req *msgjson.Message
 	writeJSON(w, resp)
 }
 
-// authMiddleware checks incoming requests for authentication.
+// authMiddleware checks incoming requests for authentication. In the cert auth
+// modes, the client certificate must be in the allow-list, and its permissions
+// are added to the request context.
 func (s *RPCServer) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.clientCerts != nil {
+			if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+				log.Warnf("missing client certificate from ip: %s", r.RemoteAddr)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			perms := s.clientCerts[certFingerprint(r.TLS.PeerCertificates[0].Raw)]
+			if perms == nil {
+				log.Warnf("unknown client certificate from ip: %s", r.RemoteAddr)
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), permsCtxKey, perms))
+			if s.authMode == AuthModeCert {
+				log.Debugf("authenticated client %q with ip: %s", perms.name, r.RemoteAddr)
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
 		fail := func() {
 			log.Warnf("authentication failure from ip: %s", r.RemoteAddr)
 			w.Header().Add("WWW-Authenticate", `Basic realm="dex RPC"`)
@@ -375,11 +532,20 @@ func (s *RPCServer) authMiddleware(next http.Handler) http.Handler {
 			fail()
 			return
 		}
+		// Wrong credentials are limited by source IP. Requests without
+		// credentials are not attempts.
+		if ok, wait := s.limiter.Allow(r.RemoteAddr); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
 		authSHA := sha256.Sum256([]byte(auth[0]))
 		if subtle.ConstantTimeCompare(s.authSHA[:], authSHA[:]) != 1 {
+			s.limiter.Failure(r.RemoteAddr)
 			fail()
 			return
 		}
+		s.limiter.Success(r.RemoteAddr)
 		log.Debugf("authenticated user with ip: %s", r.RemoteAddr)
 		next.ServeHTTP(w, r)
 	})
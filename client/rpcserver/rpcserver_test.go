@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/authlimit"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mnemonic"
@@ -36,6 +37,8 @@ var (
 
 type TCore struct {
 	dexExchange              *core.Exchange
+	recoveryKeys             *core.RecoveryKeys
+	recoveryKeysErr          error
 	getDEXConfigErr          error
 	balanceErr               error
 	syncErr                  error
@@ -56,6 +59,9 @@ type TCore struct {
 	order                    *core.Order
 	tradeErr                 error
 	cancelErr                error
+	orderFilter              *core.OrderFilter
+	orders                   []*core.Order
+	ordersErr                error
 	coin                     asset.Coin
 	sendErr                  error
 	logoutErr                error
@@ -71,6 +77,20 @@ type TCore struct {
 	stakeStatus              *asset.TicketStakingStatus
 	stakeStatusErr           error
 	setVotingPrefErr         error
+	mixingStats              *asset.FundsMixingStats
+	mixingStatsErr           error
+	accountExportErr         error
+	accountImportErr         error
+	importedAccount          *core.Account
+	reconfigureWalletErr     error
+	reconfigureWalletForm    *core.WalletForm
+	reconfigureWalletPW      []byte
+	takeActionErr            error
+	actionID                 string
+	txs                      []*asset.WalletTransaction
+	txHistoryErr             error
+	pendingActions           []*asset.ActionRequiredNote
+	checkDBErr               error
 }
 
 func (c *TCore) Balance(uint32) (uint64, error) {
@@ -126,7 +146,7 @@ func (c *TCore) ToggleWalletStatus(assetID uint32, disable bool) error {
 	}
 	return c.walletStatusErr
 }
-func (c *TCore) RescanWallet(assetID uint32, force bool) error {
+func (c *TCore) RescanWallet(assetID uint32, force bool, bday uint64) error {
 	return c.rescanWalletErr
 }
 func (c *TCore) GetDEXConfig(dexAddr string, certI any) (*core.Exchange, error) {
@@ -189,11 +209,32 @@ func (c *TCore) PurchaseTickets(assetID uint32, pw []byte, n int) error {
 func (c *TCore) StakeStatus(assetID uint32) (*asset.TicketStakingStatus, error) {
 	return c.stakeStatus, c.stakeStatusErr
 }
+func (c *TCore) FundsMixingStats(assetID uint32) (*asset.FundsMixingStats, error) {
+	return c.mixingStats, c.mixingStatsErr
+}
 func (c *TCore) SetVotingPreferences(assetID uint32, choices, tSpendPolicy, treasuryPolicy map[string]string) error {
 	return c.setVotingPrefErr
 }
 func (c *TCore) TxHistory(assetID uint32, n int, refID *string, past bool) ([]*asset.WalletTransaction, error) {
-	return nil, nil
+	txs := c.txs
+	if refID != nil {
+		for i, tx := range txs {
+			if tx.ID == *refID {
+				txs = txs[i+1:]
+				break
+			}
+		}
+	}
+	if n > 0 && len(txs) > n {
+		txs = txs[:n]
+	}
+	return txs, c.txHistoryErr
+}
+func (c *TCore) PendingActions() []*asset.ActionRequiredNote {
+	return c.pendingActions
+}
+func (c *TCore) CheckDB() error {
+	return c.checkDBErr
 }
 func (c *TCore) WalletTransaction(assetID uint32, txID string) (*asset.WalletTransaction, error) {
 	return nil, nil
@@ -219,6 +260,37 @@ func (c *TCore) BridgeHistory(fromAssetID uint32, n int, refID *string, past boo
 func (c *TCore) PendingBridges(fromAssetID uint32) ([]*asset.WalletTransaction, error) {
 	return nil, nil
 }
+func (c *TCore) RecoveryKeys(form *core.RecoveryKeysForm) (*core.RecoveryKeys, error) {
+	return c.recoveryKeys, c.recoveryKeysErr
+}
+func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
+	if c.accountExportErr != nil {
+		return nil, nil, c.accountExportErr
+	}
+	return &core.Account{Host: host}, nil, nil
+}
+func (c *TCore) AccountImport(pw []byte, account *core.Account, bonds []*db.Bond) error {
+	c.importedAccount = account
+	return c.accountImportErr
+}
+func (c *TCore) ReconfigureWallet(appPW, newWalletPW []byte, form *core.WalletForm) error {
+	c.reconfigureWalletForm = form
+	c.reconfigureWalletPW = newWalletPW
+	return c.reconfigureWalletErr
+}
+func (c *TCore) Orders(filter *core.OrderFilter) ([]*core.Order, error) {
+	c.orderFilter = filter
+	return c.orders, c.ordersErr
+}
+func (c *TCore) NotificationFeed() *core.NoteFeed {
+	return &core.NoteFeed{
+		C: make(chan core.Notification, 1),
+	}
+}
+func (c *TCore) TakeAction(assetID uint32, actionID string, actionB json.RawMessage) error {
+	c.actionID = actionID
+	return c.takeActionErr
+}
 
 type tBookFeed struct{}
 
@@ -416,6 +488,70 @@ func TestParseHTTPRequest(t *testing.T) {
 	ensureMsgErr("bad params", msgjson.RPCParseError)
 }
 
+func TestHandleBatch(t *testing.T) {
+	s, shutdown := newTServer(t, false, "", "abc")
+	defer shutdown()
+
+	post := func(body []byte) *tResponseWriter {
+		t.Helper()
+		r, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+		w := &tResponseWriter{}
+		s.handleJSON(w, r)
+		return w
+	}
+
+	if w := post([]byte(" []")); w.code != http.StatusBadRequest {
+		t.Fatalf("empty batch: wanted code %d, got %d", http.StatusBadRequest, w.code)
+	}
+	if w := post([]byte("[{")); w.code != http.StatusUnprocessableEntity {
+		t.Fatalf("bad JSON: wanted code %d, got %d", http.StatusUnprocessableEntity, w.code)
+	}
+	tooMany := make([]*msgjson.Message, maxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i], _ = msgjson.NewRequest(uint64(i), versionRoute, nil)
+	}
+	b, _ := json.Marshal(tooMany)
+	if w := post(b); w.code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("too many: wanted code %d, got %d", http.StatusRequestEntityTooLarge, w.code)
+	}
+
+	good, _ := msgjson.NewRequest(1, versionRoute, nil)
+	badRoute, _ := msgjson.NewRequest(2, "123", nil)
+	notReq, _ := msgjson.NewResponse(3, nil, nil)
+	badArgs, _ := msgjson.NewRequest(4, versionRoute, "something")
+	b, _ = json.Marshal([]*msgjson.Message{good, badRoute, notReq, badArgs})
+	w := post(b)
+	if w.code != 200 {
+		t.Fatalf("batch: HTTP error %d", w.code)
+	}
+	var resps []*msgjson.Message
+	if err := json.Unmarshal(w.b, &resps); err != nil {
+		t.Fatalf("unable to unmarshal responses: %v", err)
+	}
+	wantErrCodes := []int{-1, msgjson.RPCUnknownRoute, msgjson.RPCParseError, msgjson.RPCParseError}
+	if len(resps) != len(wantErrCodes) {
+		t.Fatalf("wanted %d responses, got %d", len(wantErrCodes), len(resps))
+	}
+	for i, resp := range resps {
+		if resp.ID != uint64(i+1) {
+			t.Fatalf("response %d: wrong ID %d", i, resp.ID)
+		}
+		payload := new(msgjson.ResponsePayload)
+		if err := json.Unmarshal(resp.Payload, payload); err != nil {
+			t.Fatalf("unable to unmarshal payload: %v", err)
+		}
+		if wantErrCodes[i] == -1 {
+			if payload.Error != nil {
+				t.Fatalf("response %d: unexpected error %v", i, payload.Error)
+			}
+			continue
+		}
+		if payload.Error == nil || payload.Error.Code != wantErrCodes[i] {
+			t.Fatalf("response %d: wanted error code %d, got %v", i, wantErrCodes[i], payload.Error)
+		}
+	}
+}
+
 func TestNew(t *testing.T) {
 	authTests := []struct {
 		name, user, pass, wantAuth string
@@ -541,3 +677,32 @@ func TestAuthMiddleware(t *testing.T) {
 		wantAuthError(test.name, test.wantErr)
 	}
 }
+
+func TestAuthRateLimit(t *testing.T) {
+	s, shutdown := newTServer(t, false, "user", "abc")
+	defer shutdown()
+	am := s.authMiddleware(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	try := func(remoteAddr, pass string, wantCode int) {
+		t.Helper()
+		r, _ := http.NewRequest("GET", "", nil)
+		r.RemoteAddr = remoteAddr
+		r.SetBasicAuth("user", pass)
+		w := &tResponseWriter{}
+		am.ServeHTTP(w, r)
+		if w.code != wantCode {
+			t.Fatalf("expected HTTP status %d, got %d", wantCode, w.code)
+		}
+	}
+
+	for i := 0; i < authlimit.FreeAttempts+1; i++ {
+		try("192.0.2.1:1234", "wrong", http.StatusUnauthorized)
+	}
+	// The correct password is rejected too while limited, but not from
+	// another address.
+	try("192.0.2.1:1234", "abc", http.StatusTooManyRequests)
+	try("192.0.2.2:1234", "abc", http.StatusOK)
+}
@@ -10,9 +10,12 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 	"time"
 
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/config"
@@ -23,6 +26,54 @@ import (
 // An orderID is a 256 bit number encoded as a hex string.
 const orderIdLen = 2 * order.OrderIDSize // 2 * 32
 
+const (
+	// defaultOrderHistoryN is the default number of orders in an orderhistory
+	// page.
+	defaultOrderHistoryN = 20
+	// maxOrderHistoryN is the maximum number of orders in an orderhistory
+	// page.
+	maxOrderHistoryN = 500
+	// defaultWalletHistoryN is the default number of transactions in a
+	// wallethistory page.
+	defaultWalletHistoryN = 50
+	// maxWalletHistoryN is the maximum number of transactions in a
+	// wallethistory page.
+	maxWalletHistoryN = 500
+)
+
+// orderStatuses are the order statuses by name.
+var orderStatuses = map[string]order.OrderStatus{
+	order.OrderStatusEpoch.String():    order.OrderStatusEpoch,
+	order.OrderStatusBooked.String():   order.OrderStatusBooked,
+	order.OrderStatusExecuted.String(): order.OrderStatusExecuted,
+	order.OrderStatusCanceled.String(): order.OrderStatusCanceled,
+	order.OrderStatusRevoked.String():  order.OrderStatusRevoked,
+}
+
+// txTypes are the wallet transaction types by name.
+var txTypes = map[string]asset.TransactionType{
+	"unknown":             asset.Unknown,
+	"send":                asset.Send,
+	"receive":             asset.Receive,
+	"swap":                asset.Swap,
+	"redeem":              asset.Redeem,
+	"refund":              asset.Refund,
+	"split":               asset.Split,
+	"createBond":          asset.CreateBond,
+	"redeemBond":          asset.RedeemBond,
+	"approveToken":        asset.ApproveToken,
+	"acceleration":        asset.Acceleration,
+	"selfSend":            asset.SelfSend,
+	"revokeTokenApproval": asset.RevokeTokenApproval,
+	"ticketPurchase":      asset.TicketPurchase,
+	"ticketVote":          asset.TicketVote,
+	"ticketRevocation":    asset.TicketRevocation,
+	"swapOrSend":          asset.SwapOrSend,
+	"mix":                 asset.Mix,
+	"initiateBridge":      asset.InitiateBridge,
+	"completeBridge":      asset.CompleteBridge,
+}
+
 var (
 	// errArgs is wrapped when arguments to the known command cannot be parsed.
 	errArgs = errors.New("unable to parse arguments")
@@ -169,6 +220,13 @@ type sendOrWithdrawForm struct {
 	address string
 }
 
+// rescanWalletForm is information necessary to rescan a wallet.
+type rescanWalletForm struct {
+	assetID  uint32
+	force    bool
+	birthday uint64
+}
+
 // orderBookForm is information necessary to fetch an order book.
 type orderBookForm struct {
 	host    string
@@ -177,6 +235,117 @@ type orderBookForm struct {
 	nOrders uint64
 }
 
+// orderHistoryFilter is the orderhistory filter argument.
+type orderHistoryFilter struct {
+	N      int       `json:"n"`
+	Offset dex.Bytes `json:"offset"`
+	Hosts  []string  `json:"hosts"`
+	Assets []uint32  `json:"assets"`
+	Market *struct {
+		BaseID  uint32 `json:"baseID"`
+		QuoteID uint32 `json:"quoteID"`
+	} `json:"market"`
+	Statuses []string `json:"statuses"`
+	Since    uint64   `json:"since"`
+	Until    uint64   `json:"until"`
+}
+
+// orderHistoryResponse is the response to the orderhistory route.
+type orderHistoryResponse struct {
+	Orders []*myOrder `json:"orders"`
+	// Next is the offset of the next page, if there may be one.
+	Next dex.Bytes `json:"next,omitempty"`
+}
+
+// walletHistoryFilter is the wallethistory filter argument.
+type walletHistoryFilter struct {
+	N     int      `json:"n"`
+	RefID string   `json:"refID"`
+	Types []string `json:"types"`
+	Since uint64   `json:"since"`
+	Until uint64   `json:"until"`
+}
+
+// walletHistoryForm is the information necessary to fetch a page of wallet
+// transactions.
+type walletHistoryForm struct {
+	assetID uint32
+	n       int
+	refID   *string
+	// types is nil if transactions of all types are requested.
+	types map[asset.TransactionType]bool
+	since uint64
+	until uint64
+}
+
+// txDEXContext relates a wallet transaction to a match.
+type txDEXContext struct {
+	Host    string    `json:"host"`
+	Market  string    `json:"market"`
+	OrderID dex.Bytes `json:"orderID"`
+	MatchID dex.Bytes `json:"matchID"`
+	Sell    bool      `json:"sell"`
+	Rate    uint64    `json:"rate"`
+	Qty     uint64    `json:"qty"`
+	// Role is "swap", "redeem" or "refund".
+	Role string `json:"role"`
+}
+
+// walletHistoryTx is a wallet transaction with the matches it settled, if any.
+type walletHistoryTx struct {
+	*asset.WalletTransaction
+	TypeName string          `json:"typeName"`
+	DEX      []*txDEXContext `json:"dex,omitempty"`
+}
+
+// walletHistoryResponse is the response to the wallethistory route.
+type walletHistoryResponse struct {
+	Txs []*walletHistoryTx `json:"txs"`
+	// Next is the refID of the next page, if there may be one.
+	Next string `json:"next,omitempty"`
+}
+
+// healthStatus is the response to the status route.
+type healthStatus struct {
+	// Healthy is false if there are any problems.
+	Healthy        bool              `json:"healthy"`
+	Problems       []string          `json:"problems,omitempty"`
+	DB             *dbHealth         `json:"db"`
+	Wallets        []*walletHealth   `json:"wallets"`
+	Exchanges      []*exchangeHealth `json:"exchanges"`
+	PendingActions []*pendingAction  `json:"pendingActions"`
+	ActiveBots     int               `json:"activeBots"`
+}
+
+type dbHealth struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type walletHealth struct {
+	AssetID      uint32  `json:"assetID"`
+	Symbol       string  `json:"symbol"`
+	Disabled     bool    `json:"disabled"`
+	Running      bool    `json:"running"`
+	Open         bool    `json:"open"`
+	Synced       bool    `json:"synced"`
+	SyncProgress float32 `json:"syncProgress"`
+	PeerCount    uint32  `json:"peerCount"`
+}
+
+type exchangeHealth struct {
+	Host     string `json:"host"`
+	Disabled bool   `json:"disabled"`
+	// Status is "connected", "disconnected" or "invalid certificate".
+	Status string `json:"status"`
+}
+
+type pendingAction struct {
+	AssetID  uint32 `json:"assetID"`
+	ActionID string `json:"actionID"`
+	UniqueID string `json:"uniqueID"`
+}
+
 // myOrdersForm is information necessary to fetch the user's orders.
 type myOrdersForm struct {
 	host  string
@@ -240,6 +409,46 @@ type txHistoryForm struct {
 	past    bool
 }
 
+// accountExportForm is information necessary to export an account.
+type accountExportForm struct {
+	appPass encode.PassBytes
+	host    string
+}
+
+// accountExport is the exportaccount response and the importaccount argument.
+type accountExport struct {
+	Account *core.Account `json:"account"`
+	Bonds   []*db.Bond    `json:"bonds"`
+}
+
+// accountImportForm is information necessary to import an account.
+type accountImportForm struct {
+	appPass encode.PassBytes
+	export  *accountExport
+}
+
+// reconfigureWalletForm is information necessary to reconfigure a wallet.
+type reconfigureWalletForm struct {
+	appPass encode.PassBytes
+	// newWalletPass is nil if the wallet password should not be changed.
+	newWalletPass encode.PassBytes
+	assetID       uint32
+	walletType    string
+	config        map[string]string
+}
+
+// takeActionForm is information necessary to resolve a requested action.
+type takeActionForm struct {
+	assetID  uint32
+	actionID string
+	action   json.RawMessage
+}
+
+type updateBotConfigForm struct {
+	cfgFilePath string
+	mkt         *mm.MarketWithHost
+}
+
 // checkNArgs checks that args and pwArgs are the correct length.
 func checkNArgs(params *RawParams, nPWArgs, nArgs []int) error {
 	// For want, one integer indicates an exact match, two are the min and max.
@@ -365,32 +574,68 @@ func parseNewWalletArgs(params *RawParams) (*newWalletForm, error) {
 		return nil, err
 	}
 
+	cfg, err := parseWalletConfig(params.Args[2:])
+	if err != nil {
+		return nil, err
+	}
 	req := &newWalletForm{
 		appPass:    params.PWArgs[0],
 		walletType: params.Args[1],
 		walletPass: params.PWArgs[1],
 		assetID:    uint32(assetID),
-		config:     make(map[string]string),
+		config:     cfg,
 	}
-	if len(params.Args) > 2 {
-		req.config, err = config.Parse([]byte(params.Args[2]))
+	return req, nil
+}
+
+// parseWalletConfig parses the optional wallet config file text and JSON
+// override arguments of the newwallet and reconfigurewallet routes.
+func parseWalletConfig(args []string) (map[string]string, error) {
+	cfg := make(map[string]string)
+	if len(args) > 0 {
+		var err error
+		cfg, err = config.Parse([]byte(args[0]))
 		if err != nil {
 			return nil, fmt.Errorf("config parse error: %v", err)
 		}
 	}
-	if len(params.Args) > 3 {
-		cfg := make(map[string]string)
-		err := json.Unmarshal([]byte(params.Args[3]), &cfg)
+	if len(args) > 1 {
+		overrides := make(map[string]string)
+		err := json.Unmarshal([]byte(args[1]), &overrides)
 		if err != nil {
 			return nil, fmt.Errorf("JSON parse error: %v", err)
 		}
-		for key, val := range cfg {
-			if fileVal, found := req.config[key]; found {
+		for key, val := range overrides {
+			if fileVal, found := cfg[key]; found {
 				log.Infof("Overriding config file setting %s=%s with %s", key, fileVal, val)
 			}
-			req.config[key] = val
+			cfg[key] = val
 		}
 	}
+	return cfg, nil
+}
+
+func parseReconfigureWalletArgs(params *RawParams) (*reconfigureWalletForm, error) {
+	if err := checkNArgs(params, []int{1, 2}, []int{2, 4}); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := parseWalletConfig(params.Args[2:])
+	if err != nil {
+		return nil, err
+	}
+	req := &reconfigureWalletForm{
+		appPass:    params.PWArgs[0],
+		assetID:    uint32(assetID),
+		walletType: params.Args[1],
+		config:     cfg,
+	}
+	if len(params.PWArgs) > 1 && len(params.PWArgs[1]) > 0 {
+		req.newWalletPass = params.PWArgs[1]
+	}
 	return req, nil
 }
 
@@ -712,22 +957,29 @@ func parseBchWithdrawArgs(params *RawParams) (appPW encode.PassBytes, recipient
 	return params.PWArgs[0], params.Args[0], nil
 }
 
-func parseRescanWalletArgs(params *RawParams) (uint32, bool, error) {
-	if err := checkNArgs(params, []int{0}, []int{1, 2}); err != nil {
-		return 0, false, err
+func parseRescanWalletArgs(params *RawParams) (*rescanWalletForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{1, 3}); err != nil {
+		return nil, err
 	}
 	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
 	if err != nil {
-		return 0, false, err
+		return nil, err
 	}
-	var force bool // do not rescan with active orders by default
+	form := &rescanWalletForm{assetID: uint32(assetID)}
+	// do not rescan with active orders by default
 	if len(params.Args) > 1 {
-		force, err = checkBoolArg(params.Args[1], "force")
+		form.force, err = checkBoolArg(params.Args[1], "force")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(params.Args) > 2 {
+		form.birthday, err = checkUIntArg(params.Args[2], "birthday", 64)
 		if err != nil {
-			return 0, false, err
+			return nil, err
 		}
 	}
-	return uint32(assetID), force, nil
+	return form, nil
 }
 
 func parseOrderBookArgs(params *RawParams) (*orderBookForm, error) {
@@ -797,6 +1049,197 @@ func parseAppSeedArgs(params *RawParams) (encode.PassBytes, error) {
 	return params.PWArgs[0], nil
 }
 
+func parseAccountExportArgs(params *RawParams) (*accountExportForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{1}); err != nil {
+		return nil, err
+	}
+	return &accountExportForm{
+		appPass: params.PWArgs[0],
+		host:    params.Args[0],
+	}, nil
+}
+
+const (
+	defaultRecoveryAcctKeys = 1
+	defaultRecoveryBondKeys = 20
+)
+
+func parseRecoveryKeysArgs(params *RawParams) (*core.RecoveryKeysForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{1, 4}); err != nil {
+		return nil, err
+	}
+	form := &core.RecoveryKeysForm{
+		Seed:     string(params.PWArgs[0]),
+		AcctKeys: defaultRecoveryAcctKeys,
+		BondKeys: defaultRecoveryBondKeys,
+	}
+	for _, host := range strings.Split(params.Args[0], ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			form.Hosts = append(form.Hosts, host)
+		}
+	}
+	if len(params.Args) > 1 && params.Args[1] != "" {
+		n, err := checkUIntArg(params.Args[1], "acctKeys", 32)
+		if err != nil {
+			return nil, err
+		}
+		form.AcctKeys = uint32(n)
+	}
+	if len(params.Args) > 2 {
+		for _, s := range strings.Split(params.Args[2], ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			assetID, err := checkUIntArg(s, "bondAssets", 32)
+			if err != nil {
+				return nil, err
+			}
+			form.BondAssets = append(form.BondAssets, uint32(assetID))
+		}
+	}
+	if len(params.Args) > 3 && params.Args[3] != "" {
+		n, err := checkUIntArg(params.Args[3], "bondKeys", 32)
+		if err != nil {
+			return nil, err
+		}
+		form.BondKeys = uint32(n)
+	}
+	if len(form.Hosts) == 0 && len(form.BondAssets) == 0 {
+		return nil, fmt.Errorf("%w: no hosts or bond assets", errArgs)
+	}
+	return form, nil
+}
+
+func parseOrderHistoryArgs(params *RawParams) (*core.OrderFilter, error) {
+	if err := checkNArgs(params, []int{0}, []int{0, 1}); err != nil {
+		return nil, err
+	}
+	f := new(orderHistoryFilter)
+	if len(params.Args) > 0 && params.Args[0] != "" {
+		if err := json.Unmarshal([]byte(params.Args[0]), f); err != nil {
+			return nil, fmt.Errorf("%w: cannot parse filter: %v", errArgs, err)
+		}
+	}
+	switch {
+	case f.N == 0:
+		f.N = defaultOrderHistoryN
+	case f.N < 0 || f.N > maxOrderHistoryN:
+		return nil, fmt.Errorf("%w: n must be between 1 and %d", errArgs, maxOrderHistoryN)
+	}
+	if len(f.Offset) != 0 && len(f.Offset) != order.OrderIDSize {
+		return nil, fmt.Errorf("%w: invalid offset order ID", errArgs)
+	}
+	if f.Until != 0 && f.Until <= f.Since {
+		return nil, fmt.Errorf("%w: until must be after since", errArgs)
+	}
+	filter := &core.OrderFilter{
+		N:      f.N,
+		Offset: f.Offset,
+		Hosts:  f.Hosts,
+		Assets: f.Assets,
+		Since:  f.Since,
+		Until:  f.Until,
+	}
+	if f.Market != nil {
+		filter.Market = &struct {
+			Base  uint32 `json:"baseID"`
+			Quote uint32 `json:"quoteID"`
+		}{f.Market.BaseID, f.Market.QuoteID}
+	}
+	for _, name := range f.Statuses {
+		status, found := orderStatuses[name]
+		if !found {
+			return nil, fmt.Errorf("%w: unknown order status %q", errArgs, name)
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	return filter, nil
+}
+
+func parseWalletHistoryArgs(params *RawParams) (*walletHistoryForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{1, 2}); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	f := new(walletHistoryFilter)
+	if len(params.Args) > 1 && params.Args[1] != "" {
+		if err := json.Unmarshal([]byte(params.Args[1]), f); err != nil {
+			return nil, fmt.Errorf("%w: cannot parse filter: %v", errArgs, err)
+		}
+	}
+	switch {
+	case f.N == 0:
+		f.N = defaultWalletHistoryN
+	case f.N < 0 || f.N > maxWalletHistoryN:
+		return nil, fmt.Errorf("%w: n must be between 1 and %d", errArgs, maxWalletHistoryN)
+	}
+	if f.Until != 0 && f.Until <= f.Since {
+		return nil, fmt.Errorf("%w: until must be after since", errArgs)
+	}
+	form := &walletHistoryForm{
+		assetID: uint32(assetID),
+		n:       f.N,
+		since:   f.Since,
+		until:   f.Until,
+	}
+	if f.RefID != "" {
+		form.refID = &f.RefID
+	}
+	if len(f.Types) > 0 {
+		form.types = make(map[asset.TransactionType]bool, len(f.Types))
+		for _, name := range f.Types {
+			txType, found := txTypes[name]
+			if !found {
+				return nil, fmt.Errorf("%w: unknown transaction type %q", errArgs, name)
+			}
+			form.types[txType] = true
+		}
+	}
+	return form, nil
+}
+
+func parseAccountImportArgs(params *RawParams) (*accountImportForm, error) {
+	if err := checkNArgs(params, []int{1}, []int{1}); err != nil {
+		return nil, err
+	}
+	export := new(accountExport)
+	if err := json.Unmarshal([]byte(params.Args[0]), export); err != nil {
+		return nil, fmt.Errorf("%w: cannot parse account: %v", errArgs, err)
+	}
+	if export.Account == nil {
+		return nil, fmt.Errorf("%w: account missing", errArgs)
+	}
+	return &accountImportForm{
+		appPass: params.PWArgs[0],
+		export:  export,
+	}, nil
+}
+
+func parseTakeActionArgs(params *RawParams) (*takeActionForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{2, 3}); err != nil {
+		return nil, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return nil, err
+	}
+	form := &takeActionForm{
+		assetID:  uint32(assetID),
+		actionID: params.Args[1],
+		action:   json.RawMessage("{}"),
+	}
+	if len(params.Args) > 2 {
+		if !json.Valid([]byte(params.Args[2])) {
+			return nil, fmt.Errorf("%w: action must be JSON", errArgs)
+		}
+		form.action = json.RawMessage(params.Args[2])
+	}
+	return form, nil
+}
+
 func parseDeleteArchivedRecordsArgs(params *RawParams) (form *deleteRecordsForm, err error) {
 	if err = checkNArgs(params, []int{0}, []int{0, 3}); err != nil {
 		return nil, err
@@ -946,6 +1389,27 @@ func parseStopBotArgs(params *RawParams) (*mm.MarketWithHost, error) {
 	return parseMktWithHost(params.Args[0], params.Args[1], params.Args[2])
 }
 
+func parseUpdateBotConfigArgs(params *RawParams) (*updateBotConfigForm, error) {
+	if err := checkNArgs(params, []int{0}, []int{4}); err != nil {
+		return nil, err
+	}
+	mkt, err := parseMktWithHost(params.Args[1], params.Args[2], params.Args[3])
+	if err != nil {
+		return nil, err
+	}
+	return &updateBotConfigForm{
+		cfgFilePath: params.Args[0],
+		mkt:         mkt,
+	}, nil
+}
+
+func parseRemoveBotConfigArgs(params *RawParams) (*mm.MarketWithHost, error) {
+	if err := checkNArgs(params, []int{0}, []int{3}); err != nil {
+		return nil, err
+	}
+	return parseMktWithHost(params.Args[0], params.Args[1], params.Args[2])
+}
+
 func parseUpdateRunningBotArgs(params *RawParams) (*updateRunningBotForm, error) {
 	if err := checkNArgs(params, []int{0}, []int{4, 6}); err != nil {
 		return nil, err
@@ -1061,6 +1525,17 @@ func parseStakeStatusArgs(params *RawParams) (uint32, error) {
 	return uint32(assetID), nil
 }
 
+func parseMixingStatsArgs(params *RawParams) (uint32, error) {
+	if err := checkNArgs(params, []int{0}, []int{1}); err != nil {
+		return 0, err
+	}
+	assetID, err := checkUIntArg(params.Args[0], "assetID", 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid assetID: %v", err)
+	}
+	return uint32(assetID), nil
+}
+
 func parseSetVotingPreferencesArgs(params *RawParams) (*setVotingPreferencesForm, error) {
 	err := checkNArgs(params, []int{0}, []int{1, 4})
 	if err != nil {
@@ -531,6 +531,47 @@ func TestParseSendOrWithdrawArgs(t *testing.T) {
 	}
 }
 
+func TestParseRescanWalletArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantForce    bool
+		wantBirthday uint64
+		wantErr      error
+	}{{
+		name: "ok",
+		args: []string{"42"},
+	}, {
+		name:         "ok with birthday",
+		args:         []string{"42", "true", "1700000000"},
+		wantForce:    true,
+		wantBirthday: 1700000000,
+	}, {
+		name:    "birthday is not int",
+		args:    []string{"42", "false", "yesterday"},
+		wantErr: errArgs,
+	}, {
+		name:    "too many args",
+		args:    []string{"42", "false", "1700000000", "1"},
+		wantErr: errArgs,
+	}}
+	for _, test := range tests {
+		res, err := parseRescanWalletArgs(&RawParams{Args: test.args})
+		if test.wantErr != nil {
+			if errors.Is(err, test.wantErr) {
+				continue
+			}
+			t.Fatalf("expected error for test %v", test.name)
+		}
+		if err != nil {
+			t.Fatalf("unexpected error %v for test %s", err, test.name)
+		}
+		if res.assetID != 42 || res.force != test.wantForce || res.birthday != test.wantBirthday {
+			t.Fatalf("wrong form %+v for test %s", res, test.name)
+		}
+	}
+}
+
 func TestParseOrderBookArgs(t *testing.T) {
 	paramsWithArgs := func(base, quote, nOrders string) *RawParams {
 		args := []string{
@@ -0,0 +1,193 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package tor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"github.com/decred/go-socks/socks"
+)
+
+// isolationPassword is the SOCKS password sent with every connection. Only
+// the username, which is the destination host, varies.
+const isolationPassword = "bisonw"
+
+// clientTorrcTemplate configures a tor client with no hidden service.
+// IsolateSOCKSAuth is the default, but stream isolation relies on it.
+const clientTorrcTemplate = `#Bison Wallet tor client configuration
+SOCKSPort 127.0.0.1:%s IsolateSOCKSAuth
+DataDirectory %s
+`
+
+// ClientConfig is the configuration for a tor Client.
+type ClientConfig struct {
+	// DataDir is the directory for a managed tor node's data and logs.
+	DataDir string
+	// SOCKSAddr is the address of the SOCKS proxy of an external tor node.
+	// If set, a tor node is not started.
+	SOCKSAddr string
+	// TorPath is the tor executable to run. If empty, the embedded tor binary
+	// is used, or else tor from the PATH.
+	TorPath string
+	Logger  dex.Logger
+}
+
+// Client routes connections through tor, with a separate circuit for each
+// destination host so that traffic to different hosts cannot be linked by
+// a shared exit node. Client starts and manages its own tor node unless an
+// external tor SOCKS proxy is configured.
+type Client struct {
+	log     dex.Logger
+	dataDir string
+	exePath string // empty for an external tor node
+	logPath string
+
+	mtx       sync.RWMutex
+	socksAddr string
+}
+
+// NewClient is the constructor for a Client.
+func NewClient(cfg *ClientConfig) (*Client, error) {
+	c := &Client{log: cfg.Logger}
+	if cfg.SOCKSAddr != "" {
+		if _, _, err := net.SplitHostPort(cfg.SOCKSAddr); err != nil {
+			return nil, fmt.Errorf("invalid tor SOCKS address %q: %w", cfg.SOCKSAddr, err)
+		}
+		c.socksAddr = cfg.SOCKSAddr
+		return c, nil
+	}
+
+	if cfg.DataDir == "" {
+		return nil, errors.New("no tor data directory specified")
+	}
+	exePath, logPath, err := prepareDataDir(cfg.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.TorPath != "" {
+		exePath = cfg.TorPath
+	}
+	if exePath == "" {
+		if exePath, err = exec.LookPath("tor"); err != nil {
+			return nil, errors.New("tor was not packaged with this build and no tor executable was found")
+		}
+	}
+	c.dataDir, c.exePath, c.logPath = cfg.DataDir, exePath, logPath
+	return c, nil
+}
+
+// Connect starts the tor node, or checks that the external tor node is
+// reachable. Satisfies the dex.Connector interface.
+func (c *Client) Connect(ctx context.Context) (*sync.WaitGroup, error) {
+	var wg sync.WaitGroup
+	if c.exePath == "" {
+		if err := waitForSOCKS(ctx, c.SOCKSAddr(), 0); err != nil {
+			return nil, fmt.Errorf("tor SOCKS proxy not reachable: %w", err)
+		}
+		return &wg, nil
+	}
+
+	ports, err := findOpenPort(1)
+	if err != nil {
+		return nil, fmt.Errorf("error finding open port: %w", err)
+	}
+	socksAddr := "127.0.0.1:" + ports[0]
+
+	torrcPath := filepath.Join(c.dataDir, "torrc")
+	torrcData := []byte(fmt.Sprintf(clientTorrcTemplate, ports[0], c.dataDir))
+	if err := os.WriteFile(torrcPath, torrcData, 0600); err != nil {
+		return nil, fmt.Errorf("error writing torrc file: %w", err)
+	}
+
+	// Stop tor if it does not start.
+	ctx, cancel := context.WithCancel(ctx)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer cancel()
+		runTor(ctx, c.log, c.exePath, torrcPath, c.logPath)
+		c.mtx.Lock()
+		c.socksAddr = ""
+		c.mtx.Unlock()
+	}()
+
+	// tor opens the SOCKS port before it is bootstrapped, and holds requests
+	// until a circuit can be built.
+	if err := waitForSOCKS(ctx, socksAddr, time.Second*30); err != nil {
+		cancel()
+		wg.Wait()
+		return nil, fmt.Errorf("tor did not start: %w. See %s", err, c.logPath)
+	}
+	c.mtx.Lock()
+	c.socksAddr = socksAddr
+	c.mtx.Unlock()
+	c.log.Infof("Tor SOCKS proxy listening on %s", socksAddr)
+	return &wg, nil
+}
+
+// SOCKSAddr is the address of the tor SOCKS proxy, or an empty string if the
+// managed tor node is not running.
+func (c *Client) SOCKSAddr() string {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.socksAddr
+}
+
+// DialContext connects to the address through tor. Connections to the same
+// host share a circuit, and connections to different hosts do not. DialContext
+// fails if tor is not running, so that connections are never made without
+// tor.
+func (c *Client) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	socksAddr := c.SOCKSAddr()
+	if socksAddr == "" {
+		return nil, errors.New("tor is not running")
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	proxy := &socks.Proxy{
+		Addr:     socksAddr,
+		Username: host,
+		Password: isolationPassword,
+	}
+	conn, err := proxy.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	// The proxy sets the context's deadline on the connection, which would
+	// break long-lived and reused connections.
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// waitForSOCKS waits until a connection can be made to the SOCKS proxy. With
+// a zero timeout, only one attempt is made.
+func waitForSOCKS(ctx context.Context, addr string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := (&net.Dialer{Timeout: time.Second}).DialContext(ctx, "tcp", addr)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		select {
+		case <-time.After(time.Millisecond * 100):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package tor
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"decred.org/dcrdex/dex"
+)
+
+// tSOCKSServer is a SOCKS5 server that records the username and destination
+// of each request, and echoes data back instead of connecting.
+type tSOCKSServer struct {
+	ln net.Listener
+
+	mtx sync.Mutex
+	// users maps destination hosts to the usernames sent with them.
+	users map[string][]string
+}
+
+func newTSOCKSServer(t *testing.T) *tSOCKSServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	s := &tSOCKSServer{ln: ln, users: make(map[string][]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *tSOCKSServer) handle(conn net.Conn) {
+	defer conn.Close()
+	b := make([]byte, 512)
+	// Greeting: version, number of methods, methods.
+	if _, err := io.ReadFull(conn, b[:2]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, b[:b[1]]); err != nil {
+		return
+	}
+	conn.Write([]byte{5, 2}) // username/password
+	// Auth: version, username length, username, password length, password.
+	if _, err := io.ReadFull(conn, b[:2]); err != nil {
+		return
+	}
+	user := make([]byte, b[1])
+	if _, err := io.ReadFull(conn, user); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, b[:1]); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, b[:b[0]]); err != nil {
+		return
+	}
+	conn.Write([]byte{1, 0})
+	// Request: version, command, reserved, address type (domain), length,
+	// host, port.
+	if _, err := io.ReadFull(conn, b[:5]); err != nil {
+		return
+	}
+	host := make([]byte, b[4])
+	if _, err := io.ReadFull(conn, host); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, b[:2]); err != nil {
+		return
+	}
+	s.mtx.Lock()
+	s.users[string(host)] = append(s.users[string(host)], string(user))
+	s.mtx.Unlock()
+	conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 80})
+	io.Copy(conn, conn)
+}
+
+func TestClientIsolation(t *testing.T) {
+	srv := newTSOCKSServer(t)
+	log := dex.StdOutLogger("T", dex.LevelOff)
+	c, err := NewClient(&ClientConfig{SOCKSAddr: srv.ln.Addr().String(), Logger: log})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := c.Connect(ctx); err != nil {
+		t.Fatalf("Connect error: %v", err)
+	}
+
+	for _, addr := range []string{"dex.example.com:7232", "dex.example.com:443", "rates.example.com:443"} {
+		conn, err := c.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			t.Fatalf("DialContext error: %v", err)
+		}
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			t.Fatalf("Write error: %v", err)
+		}
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(conn, b); err != nil || string(b) != "ping" {
+			t.Fatalf("echo failed: %q, %v", b, err)
+		}
+		conn.Close()
+	}
+
+	srv.mtx.Lock()
+	defer srv.mtx.Unlock()
+	dexUsers, rateUsers := srv.users["dex.example.com"], srv.users["rates.example.com"]
+	if len(dexUsers) != 2 || len(rateUsers) != 1 {
+		t.Fatalf("wrong requests: %v", srv.users)
+	}
+	if dexUsers[0] != dexUsers[1] {
+		t.Fatalf("connections to the same host used different credentials")
+	}
+	if dexUsers[0] == rateUsers[0] {
+		t.Fatalf("connections to different hosts used the same credentials")
+	}
+}
+
+func TestClientNotRunning(t *testing.T) {
+	log := dex.StdOutLogger("T", dex.LevelOff)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	c, err := NewClient(&ClientConfig{SOCKSAddr: addr, Logger: log})
+	if err != nil {
+		t.Fatalf("NewClient error: %v", err)
+	}
+	if _, err := c.Connect(context.Background()); err == nil {
+		t.Fatalf("no error connecting to an unreachable proxy")
+	}
+
+	// A managed node that was never started must not allow connections.
+	c = &Client{log: log, exePath: "tor"}
+	if _, err := c.DialContext(context.Background(), "tcp", "dex.example.com:7232"); err == nil {
+		t.Fatalf("no error dialing without tor")
+	}
+
+	if _, err := NewClient(&ClientConfig{SOCKSAddr: "127.0.0.1", Logger: log}); err == nil {
+		t.Fatalf("no error for address without a port")
+	}
+}
@@ -40,20 +40,15 @@ func New(dataDir string, log dex.Logger) (_ *HiddenService, err error) {
 		return nil, errors.New("tor not packaged")
 	}
 
-	logDir := filepath.Join(dataDir, "logs")
-	if err := os.MkdirAll(logDir, 0700); err != nil {
-		return nil, fmt.Errorf("error creating tor directory: %w", err)
-	}
-
-	exePath := filepath.Join(dataDir, "tor")
-	if err := os.WriteFile(exePath, torBinary, 0700); err != nil {
-		return nil, fmt.Errorf("error writing tor binary: %w", err)
+	exePath, logPath, err := prepareDataDir(dataDir)
+	if err != nil {
+		return nil, err
 	}
 
 	return &HiddenService{
 		log:     log,
 		exePath: exePath,
-		logPath: filepath.Join(logDir, "tor.Log"),
+		logPath: logPath,
 		dataDir: dataDir,
 	}, nil
 }
@@ -89,51 +84,7 @@ func (s *HiddenService) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-
-		const maxLogRolls = 8
-		const logFileMaxSizeKB = 16 * 1024 // 16 MB
-		logRotator, err := rotator.New(s.logPath, logFileMaxSizeKB, false, maxLogRolls)
-		if err != nil {
-			s.log.Errorf("error intializing log files: %w", err)
-			return
-		}
-		defer logRotator.Close()
-
-		cmd := exec.CommandContext(ctx, s.exePath, "-f", torrcPath)
-		cmd.Stdout = logRotator
-		cmd.Stderr = logRotator
-		cmd.Cancel = func() error {
-			if cmd.Process == nil { // probably not possible?
-				return nil
-			}
-			if err := cmd.Process.Signal(syscall.SIGINT); err != nil {
-				cmd.Process.Kill()
-				return err
-			}
-			return nil
-		}
-		if err := cmd.Start(); err != nil {
-			s.log.Errorf("tor node exited with an error: %v", err)
-			return
-		}
-
-		<-ctx.Done()
-
-		errC := make(chan error)
-		go func() {
-			_, err := cmd.Process.Wait()
-			errC <- err
-		}()
-
-		select {
-		case err := <-errC:
-			if err != nil {
-				s.log.Errorf("Error attempting clean shutdown: %v", err)
-			}
-		case <-time.After(time.Second * 5):
-			cmd.Process.Kill()
-			s.log.Error("Timed out waiting for clean shutdown")
-		}
+		runTor(ctx, s.log, s.exePath, torrcPath, s.logPath)
 	}()
 
 	hostnamePath := filepath.Join(hiddenServiceDir, "hostname")
@@ -171,6 +122,74 @@ func (s *HiddenService) OnionAddress() string {
 	return "http://" + s.onionAddr
 }
 
+// prepareDataDir creates the data and log directories and writes the
+// embedded tor binary to the data directory. The paths to the executable and
+// the log file are returned.
+func prepareDataDir(dataDir string) (exePath, logPath string, err error) {
+	logDir := filepath.Join(dataDir, "logs")
+	if err := os.MkdirAll(logDir, 0700); err != nil {
+		return "", "", fmt.Errorf("error creating tor directory: %w", err)
+	}
+	logPath = filepath.Join(logDir, "tor.Log")
+	if len(torBinary) == 0 {
+		return "", logPath, nil
+	}
+	exePath = filepath.Join(dataDir, "tor")
+	if err := os.WriteFile(exePath, torBinary, 0700); err != nil {
+		return "", "", fmt.Errorf("error writing tor binary: %w", err)
+	}
+	return exePath, logPath, nil
+}
+
+// runTor runs tor with the torrc file until the context is canceled, writing
+// tor's output to the log file.
+func runTor(ctx context.Context, log dex.Logger, exePath, torrcPath, logPath string) {
+	const maxLogRolls = 8
+	const logFileMaxSizeKB = 16 * 1024 // 16 MB
+	logRotator, err := rotator.New(logPath, logFileMaxSizeKB, false, maxLogRolls)
+	if err != nil {
+		log.Errorf("error intializing log files: %w", err)
+		return
+	}
+	defer logRotator.Close()
+
+	cmd := exec.CommandContext(ctx, exePath, "-f", torrcPath)
+	cmd.Stdout = logRotator
+	cmd.Stderr = logRotator
+	cmd.Cancel = func() error {
+		if cmd.Process == nil { // probably not possible?
+			return nil
+		}
+		if err := cmd.Process.Signal(syscall.SIGINT); err != nil {
+			cmd.Process.Kill()
+			return err
+		}
+		return nil
+	}
+	if err := cmd.Start(); err != nil {
+		log.Errorf("tor node exited with an error: %v", err)
+		return
+	}
+
+	<-ctx.Done()
+
+	errC := make(chan error)
+	go func() {
+		_, err := cmd.Process.Wait()
+		errC <- err
+	}()
+
+	select {
+	case err := <-errC:
+		if err != nil {
+			log.Errorf("Error attempting clean shutdown: %v", err)
+		}
+	case <-time.After(time.Second * 5):
+		cmd.Process.Kill()
+		log.Error("Timed out waiting for clean shutdown")
+	}
+}
+
 func findOpenPort(n int) ([]string, error) {
 	ports := make([]string, n)
 	for i := 0; i < n; i++ {
@@ -0,0 +1,111 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ACMEConfig configures certificates obtained and renewed automatically from
+// an ACME certificate authority such as Let's Encrypt. The domains must
+// resolve to this machine. Challenges are answered with TLS-ALPN-01 on the web
+// server's own listener, which must then be reachable on port 443, and with
+// HTTP-01 if HTTPAddr is set, which must then be reachable on port 80.
+type ACMEConfig struct {
+	Domains []string
+	// Email is an optional contact address for the ACME account, used by the
+	// CA to notify about problems with the certificates.
+	Email string
+	// DirectoryURL is the ACME directory of the CA. The default is Let's
+	// Encrypt's production directory.
+	DirectoryURL string
+	// HTTPAddr is an optional address on which to answer HTTP-01 challenges,
+	// e.g. ":80". Other requests to this address are redirected to HTTPS.
+	HTTPAddr string
+}
+
+// acmeCacheDir is the data subdirectory where the ACME account key and
+// certificates are stored.
+const acmeCacheDir = "acme"
+
+// newACMEManager creates the autocert.Manager for the ACMEConfig.
+func newACMEManager(cfg *ACMEConfig, dataDir string) (*autocert.Manager, error) {
+	domains := make([]string, 0, len(cfg.Domains))
+	for _, d := range cfg.Domains {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	if len(domains) == 0 {
+		return nil, errors.New("no ACME domains specified")
+	}
+	for _, d := range domains {
+		if net.ParseIP(strings.Trim(d, "[]")) != nil {
+			return nil, fmt.Errorf("ACME certificates require a domain name, not an IP address (%s)", d)
+		}
+	}
+	if dataDir == "" {
+		return nil, errors.New("ACME enabled but no data directory was specified")
+	}
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(filepath.Join(dataDir, acmeCacheDir)),
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Email:      cfg.Email,
+	}
+	if cfg.DirectoryURL != "" {
+		m.Client = &acme.Client{DirectoryURL: cfg.DirectoryURL}
+	}
+	return m, nil
+}
+
+// acmeTLSConfig is the TLS config for serving certificates from the
+// autocert.Manager.
+func acmeTLSConfig(m *autocert.Manager) *tls.Config {
+	tlsCfg := m.TLSConfig()
+	tlsCfg.MinVersion = tls.VersionTLS12
+	return tlsCfg
+}
+
+// serveACMEChallenges answers HTTP-01 challenges on addr until the context is
+// canceled.
+func (s *WebServer) serveACMEChallenges(ctx context.Context, wg *sync.WaitGroup, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("can't listen on %s for ACME challenges: %w", addr, err)
+	}
+	srv := &http.Server{
+		Handler:      s.acme.HTTPHandler(nil),
+		ReadTimeout:  httpConnTimeoutSeconds * time.Second,
+		WriteTimeout: httpConnTimeoutSeconds * time.Second,
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		if err := srv.Shutdown(context.Background()); err != nil {
+			log.Errorf("Problem shutting down ACME challenge server: %v", err)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		log.Infof("Answering ACME HTTP-01 challenges on %s", listener.Addr())
+		if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			log.Warnf("unexpected (http.Server).Serve error for ACME challenges: %v", err)
+		}
+	}()
+	return nil
+}
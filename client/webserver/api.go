@@ -22,6 +22,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/config"
 	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/msgjson"
 )
 
 var zero = encode.ClearBytes
@@ -98,9 +99,17 @@ func (s *WebServer) apiValidateAddress(w http.ResponseWriter, r *http.Request) {
 	}
 	resp := struct {
 		OK bool `json:"ok"`
+		// Resolved is the address that a name resolves to.
+		Resolved string `json:"resolved,omitempty"`
 	}{
 		OK: valid,
 	}
+	if valid {
+		resolved, err := s.core.ResolveAddress(form.Addr, *form.AssetID)
+		if err == nil && resolved != form.Addr {
+			resp.Resolved = resolved
+		}
+	}
 	writeJSON(w, resp)
 }
 
@@ -219,6 +228,8 @@ func (s *WebServer) apiApproveToken(w http.ResponseWriter, r *http.Request) {
 		AssetID  uint32           `json:"assetID"`
 		DexAddr  string           `json:"dexAddr"`
 		Password encode.PassBytes `json:"pass"`
+		// GasFees are optional EIP-1559 fee rates for the approval.
+		GasFees *asset.GasFeeRates `json:"gasFees,omitempty"`
 	}
 	if !readPost(w, r, &form) {
 		return
@@ -230,7 +241,12 @@ func (s *WebServer) apiApproveToken(w http.ResponseWriter, r *http.Request) {
 	}
 	defer zero(pass)
 
-	txID, err := s.core.ApproveToken(pass, form.AssetID, form.DexAddr, func() {})
+	var txID string
+	if form.GasFees != nil {
+		txID, err = s.core.ApproveTokenWithGasFees(pass, form.AssetID, form.DexAddr, form.GasFees, func() {})
+	} else {
+		txID, err = s.core.ApproveToken(pass, form.AssetID, form.DexAddr, func() {})
+	}
 	if err != nil {
 		s.writeAPIError(w, err)
 		return
@@ -276,6 +292,54 @@ func (s *WebServer) apiUnapproveToken(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// apiTokenAllowances handles the 'tokenallowances' API request.
+func (s *WebServer) apiTokenAllowances(w http.ResponseWriter, r *http.Request) {
+	allowances, err := s.core.TokenAllowances()
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error getting token allowances: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK         bool                               `json:"ok"`
+		Allowances map[uint32][]*asset.TokenAllowance `json:"allowances"`
+	}{
+		OK:         true,
+		Allowances: allowances,
+	})
+}
+
+// apiSetTokenAllowance handles the 'settokenallowance' API request.
+func (s *WebServer) apiSetTokenAllowance(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		AssetID  uint32           `json:"assetID"`
+		Version  uint32           `json:"version"`
+		Amount   uint64           `json:"amount"`
+		Password encode.PassBytes `json:"pass"`
+	}
+	if !readPost(w, r, &form) {
+		return
+	}
+	pass, err := s.resolvePass(form.Password, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	defer zero(pass)
+
+	txID, err := s.core.SetTokenAllowance(pass, form.AssetID, form.Version, form.Amount)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool   `json:"ok"`
+		TxID string `json:"txID"`
+	}{
+		OK:   true,
+		TxID: txID,
+	})
+}
+
 // apiGetDEXInfo is the handler for the '/getdexinfo' API request.
 func (s *WebServer) apiGetDEXInfo(w http.ResponseWriter, r *http.Request) {
 	form := new(registrationForm)
@@ -445,6 +509,36 @@ func (s *WebServer) apiRedeemPrepaidBond(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, resp)
 }
 
+// apiAddCustomToken is the handler for the '/addcustomtoken' API request.
+func (s *WebServer) apiAddCustomToken(w http.ResponseWriter, r *http.Request) {
+	form := new(addCustomTokenForm)
+	defer form.AppPW.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	if form.Token == nil {
+		s.writeAPIError(w, errors.New("no token provided"))
+		return
+	}
+	appPW, err := s.resolvePass(form.AppPW, r)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	tokenID, err := s.core.AddCustomToken(appPW, form.Token)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error adding custom token: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK      bool   `json:"ok"`
+		TokenID uint32 `json:"tokenID"`
+	}{
+		OK:      true,
+		TokenID: tokenID,
+	})
+}
+
 // apiNewWallet is the handler for the '/newwallet' API request.
 func (s *WebServer) apiNewWallet(w http.ResponseWriter, r *http.Request) {
 	form := new(newWalletForm)
@@ -467,9 +561,10 @@ func (s *WebServer) apiNewWallet(w http.ResponseWriter, r *http.Request) {
 	var parentForm *core.WalletForm
 	if f := form.ParentForm; f != nil {
 		parentForm = &core.WalletForm{
-			AssetID: f.AssetID,
-			Config:  f.Config,
-			Type:    f.WalletType,
+			AssetID:  f.AssetID,
+			Config:   f.Config,
+			Type:     f.WalletType,
+			Mnemonic: f.Mnemonic,
 		}
 	}
 	// Wallet does not exist yet. Try to create it.
@@ -478,6 +573,7 @@ func (s *WebServer) apiNewWallet(w http.ResponseWriter, r *http.Request) {
 		Type:       form.WalletType,
 		Config:     form.Config,
 		ParentForm: parentForm,
+		Mnemonic:   form.Mnemonic,
 	})
 	if err != nil {
 		s.writeAPIError(w, fmt.Errorf("error creating %s wallet: %w", unbip(form.AssetID), err))
@@ -525,6 +621,9 @@ func (s *WebServer) apiRescanWallet(w http.ResponseWriter, r *http.Request) {
 	var form struct {
 		AssetID uint32 `json:"assetID"`
 		Force   bool   `json:"force"`
+		// Birthday is the unix time to rescan from, or zero for the
+		// wallet's default.
+		Birthday uint64 `json:"birthday"`
 	}
 	if !readPost(w, r, &form) {
 		return
@@ -534,7 +633,7 @@ func (s *WebServer) apiRescanWallet(w http.ResponseWriter, r *http.Request) {
 		s.writeAPIError(w, fmt.Errorf("No wallet for %d -> %s", form.AssetID, unbip(form.AssetID)))
 		return
 	}
-	err := s.core.RescanWallet(form.AssetID, form.Force)
+	err := s.core.RescanWallet(form.AssetID, form.Force, form.Birthday)
 	if err != nil {
 		s.writeAPIError(w, fmt.Errorf("error rescanning %s wallet: %w", unbip(form.AssetID), err))
 		return
@@ -798,6 +897,29 @@ func (s *WebServer) apiAccountImport(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, simpleAck())
 }
 
+// apiReputationBreakdown is the handler for the '/reputationbreakdown' API
+// request.
+func (s *WebServer) apiReputationBreakdown(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		Host string `json:"host"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	breakdown, err := s.core.ReputationBreakdown(form.Host)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error getting reputation breakdown: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK        bool                      `json:"ok"`
+		Breakdown *msgjson.ReputationResult `json:"breakdown"`
+	}{
+		OK:        true,
+		Breakdown: breakdown,
+	})
+}
+
 func (s *WebServer) apiUpdateCert(w http.ResponseWriter, r *http.Request) {
 	form := &struct {
 		Host string `json:"host"`
@@ -945,6 +1067,10 @@ func (s *WebServer) apiInit(w http.ResponseWriter, r *http.Request) {
 	if !readPost(w, r, &init) {
 		return
 	}
+	if s.isGuestPass(init.Pass) {
+		s.writeAPIError(w, errAppPassIsGuestPass)
+		return
+	}
 	var seed *string
 	if len(init.Seed) > 0 {
 		seed = &init.Seed
@@ -1073,6 +1199,10 @@ func (s *WebServer) apiNotes(w http.ResponseWriter, r *http.Request) {
 
 // apiLogout handles the 'logout' API request.
 func (s *WebServer) apiLogout(w http.ResponseWriter, r *http.Request) {
+	if s.guestLogout(w, r) {
+		writeJSON(w, simpleAck())
+		return
+	}
 	err := s.core.Logout()
 	if err != nil {
 		s.writeAPIError(w, fmt.Errorf("logout error: %w", err))
@@ -1083,8 +1213,8 @@ func (s *WebServer) apiLogout(w http.ResponseWriter, r *http.Request) {
 	// to force any other sessions to login again.
 	s.deauth()
 
-	clearCookie(authCK, w)
-	clearCookie(pwKeyCK, w)
+	s.clearCookie(authCK, w)
+	s.clearCookie(pwKeyCK, w)
 
 	response := struct {
 		OK bool `json:"ok"`
@@ -1342,6 +1472,11 @@ func (s *WebServer) apiChangeAppPass(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.isGuestPass(form.NewAppPW) {
+		s.writeAPIError(w, errAppPassIsGuestPass)
+		return
+	}
+
 	// Update application password.
 	err := s.core.ChangeAppPass(form.AppPW, form.NewAppPW)
 	if err != nil {
@@ -1355,14 +1490,14 @@ func (s *WebServer) apiChangeAppPass(w http.ResponseWriter, r *http.Request) {
 	// the new password (if it was previously cached) for this session.
 	s.deauth()
 	authToken := s.authorize()
-	setCookie(authCK, authToken, w)
+	s.setCookie(authCK, authToken, w)
 	if passwordIsCached {
 		key, err := s.cacheAppPassword(form.NewAppPW, authToken)
 		if err != nil {
 			log.Errorf("unable to cache password: %w", err)
-			clearCookie(pwKeyCK, w)
+			s.clearCookie(pwKeyCK, w)
 		} else {
-			setCookie(pwKeyCK, hex.EncodeToString(key), w)
+			s.setCookie(pwKeyCK, hex.EncodeToString(key), w)
 			zero(key)
 		}
 	}
@@ -1381,6 +1516,11 @@ func (s *WebServer) apiResetAppPassword(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if s.isGuestPass(form.NewPass) {
+		s.writeAPIError(w, errAppPassIsGuestPass)
+		return
+	}
+
 	err := s.core.ResetAppPass(form.NewPass, form.Seed)
 	if err != nil {
 		s.writeAPIError(w, err)
@@ -1443,7 +1583,17 @@ func (s *WebServer) apiSend(w http.ResponseWriter, r *http.Request) {
 		s.writeAPIError(w, fmt.Errorf("empty password"))
 		return
 	}
-	coin, err := s.core.Send(form.Pass, form.AssetID, form.Value, form.Address, form.Subtract)
+	var coin asset.Coin
+	var err error
+	if form.GasFees != nil {
+		if form.Subtract {
+			s.writeAPIError(w, errors.New("fees can't be subtracted from a send with gas fee rates"))
+			return
+		}
+		coin, err = s.core.SendWithGasFees(form.Pass, form.AssetID, form.Value, form.Address, form.GasFees)
+	} else {
+		coin, err = s.core.Send(form.Pass, form.AssetID, form.Value, form.Address, form.Subtract)
+	}
 	if err != nil {
 		s.writeAPIError(w, fmt.Errorf("send/withdraw error: %w", err))
 		return
@@ -1458,6 +1608,379 @@ func (s *WebServer) apiSend(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// apiPreviewGasFees handles the 'previewgasfees' API request. The txType is
+// one of send, approve, swap, or redeem.
+func (s *WebServer) apiPreviewGasFees(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32             `json:"assetID"`
+		TxType  string             `json:"txType"`
+		Version uint32             `json:"version"`
+		GasFees *asset.GasFeeRates `json:"gasFees"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	var txType asset.TransactionType
+	switch form.TxType {
+	case "send":
+		txType = asset.Send
+	case "approve":
+		txType = asset.ApproveToken
+	case "swap":
+		txType = asset.Swap
+	case "redeem":
+		txType = asset.Redeem
+	default:
+		s.writeAPIError(w, fmt.Errorf("unknown transaction type %q", form.TxType))
+		return
+	}
+	preview, err := s.core.PreviewGasFees(form.AssetID, txType, form.Version, form.GasFees)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error previewing gas fees: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK      bool                 `json:"ok"`
+		Preview *asset.GasFeePreview `json:"preview"`
+	}{
+		OK:      true,
+		Preview: preview,
+	})
+}
+
+// apiBumpFee handles the 'bumpfee' API request.
+func (s *WebServer) apiBumpFee(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+		TxID    string `json:"txID"`
+		FeeRate uint64 `json:"feeRate"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	txID, err := s.core.BumpFee(form.AssetID, form.TxID, form.FeeRate)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error bumping fee: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool   `json:"ok"`
+		TxID string `json:"txID"`
+	}{
+		OK:   true,
+		TxID: txID,
+	})
+}
+
+// apiNonceStatus handles the 'noncestatus' API request.
+func (s *WebServer) apiNonceStatus(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	status, err := s.core.NonceStatus(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error getting nonce status: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK     bool               `json:"ok"`
+		Status *asset.NonceStatus `json:"status"`
+	}{
+		OK:     true,
+		Status: status,
+	})
+}
+
+// apiReplaceTx handles the 'replacetx' API request.
+func (s *WebServer) apiReplaceTx(w http.ResponseWriter, r *http.Request) {
+	form := new(replaceTxForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	txID, err := s.core.ReplaceTx(form.AssetID, form.TxID, form.GasFees)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error replacing transaction: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool   `json:"ok"`
+		TxID string `json:"txID"`
+	}{
+		OK:   true,
+		TxID: txID,
+	})
+}
+
+// apiCancelTx handles the 'canceltx' API request.
+func (s *WebServer) apiCancelTx(w http.ResponseWriter, r *http.Request) {
+	form := new(replaceTxForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	txID, err := s.core.CancelTx(form.AssetID, form.TxID, form.GasFees)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error cancelling transaction: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool   `json:"ok"`
+		TxID string `json:"txID"`
+	}{
+		OK:   true,
+		TxID: txID,
+	})
+}
+
+// apiFillNonceGaps handles the 'fillnoncegaps' API request.
+func (s *WebServer) apiFillNonceGaps(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	txIDs, err := s.core.FillNonceGaps(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error filling nonce gaps: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool     `json:"ok"`
+		TxIDs []string `json:"txIDs"`
+	}{
+		OK:    true,
+		TxIDs: txIDs,
+	})
+}
+
+// apiPegMWEB handles the 'pegmweb' API request.
+func (s *WebServer) apiPegMWEB(w http.ResponseWriter, r *http.Request) {
+	form := new(pegMWEBForm)
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	if len(form.Pass) == 0 {
+		s.writeAPIError(w, fmt.Errorf("empty password"))
+		return
+	}
+	txID, err := s.core.PegMWEB(form.Pass, form.AssetID, form.Value, form.PegIn)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error pegging MWEB funds: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool   `json:"ok"`
+		TxID string `json:"txID"`
+	}{
+		OK:   true,
+		TxID: txID,
+	})
+}
+
+// apiLightningBalance handles the 'lightningbalance' API request.
+func (s *WebServer) apiLightningBalance(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		AssetID uint32 `json:"assetID"`
+	}
+	if !readPost(w, r, &form) {
+		return
+	}
+	bal, err := s.core.LightningBalance(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error getting lightning balance: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK      bool   `json:"ok"`
+		Balance uint64 `json:"balance"`
+	}{
+		OK:      true,
+		Balance: bal,
+	})
+}
+
+// apiLightningInvoice handles the 'lightninginvoice' API request.
+func (s *WebServer) apiLightningInvoice(w http.ResponseWriter, r *http.Request) {
+	form := new(lightningInvoiceForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	inv, err := s.core.LightningInvoice(form.AssetID, form.Value, form.Memo)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error creating lightning invoice: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK      bool                    `json:"ok"`
+		Invoice *asset.LightningInvoice `json:"invoice"`
+	}{
+		OK:      true,
+		Invoice: inv,
+	})
+}
+
+// apiLightningInvoiceStatus handles the 'lightninginvoicestatus' API request.
+func (s *WebServer) apiLightningInvoiceStatus(w http.ResponseWriter, r *http.Request) {
+	var form struct {
+		AssetID     uint32 `json:"assetID"`
+		PaymentHash string `json:"paymentHash"`
+	}
+	if !readPost(w, r, &form) {
+		return
+	}
+	settled, amtPaid, err := s.core.LightningInvoiceStatus(form.AssetID, form.PaymentHash)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error checking lightning invoice: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK      bool   `json:"ok"`
+		Settled bool   `json:"settled"`
+		AmtPaid uint64 `json:"amtPaid"`
+	}{
+		OK:      true,
+		Settled: settled,
+		AmtPaid: amtPaid,
+	})
+}
+
+// apiPayLightningInvoice handles the 'paylightninginvoice' API request.
+func (s *WebServer) apiPayLightningInvoice(w http.ResponseWriter, r *http.Request) {
+	form := new(payLightningInvoiceForm)
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	if len(form.Pass) == 0 {
+		s.writeAPIError(w, fmt.Errorf("empty password"))
+		return
+	}
+	payment, err := s.core.PayLightningInvoice(form.Pass, form.AssetID, form.Invoice, form.MaxFee)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error paying lightning invoice: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK      bool                    `json:"ok"`
+		Payment *asset.LightningPayment `json:"payment"`
+	}{
+		OK:      true,
+		Payment: payment,
+	})
+}
+
+// apiPrivateKeyFunds handles the 'privatekeyfunds' API request.
+func (s *WebServer) apiPrivateKeyFunds(w http.ResponseWriter, r *http.Request) {
+	form := new(sweepKeyForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	funds, err := s.core.PrivateKeyFunds(form.AssetID, form.PrivKey)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error finding private key funds: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool   `json:"ok"`
+		Funds uint64 `json:"funds"`
+	}{
+		OK:    true,
+		Funds: funds,
+	})
+}
+
+// apiSweepPrivateKey handles the 'sweepprivatekey' API request.
+func (s *WebServer) apiSweepPrivateKey(w http.ResponseWriter, r *http.Request) {
+	form := new(sweepKeyForm)
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	if len(form.Pass) == 0 {
+		s.writeAPIError(w, fmt.Errorf("empty password"))
+		return
+	}
+	txID, received, err := s.core.SweepPrivateKey(form.Pass, form.AssetID, form.PrivKey)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error sweeping private key: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK       bool   `json:"ok"`
+		TxID     string `json:"txID"`
+		Received uint64 `json:"received"`
+	}{
+		OK:       true,
+		TxID:     txID,
+		Received: received,
+	})
+}
+
+// apiWalletUTXOs handles the 'walletutxos' API request.
+func (s *WebServer) apiWalletUTXOs(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	utxos, err := s.core.WalletUTXOs(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error listing wallet outputs: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool                `json:"ok"`
+		UTXOs []*asset.WalletUTXO `json:"utxos"`
+	}{
+		OK:    true,
+		UTXOs: utxos,
+	})
+}
+
+// apiPendingPSBTs handles the 'pendingpsbts' API request.
+func (s *WebServer) apiPendingPSBTs(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	reqs, err := s.core.PendingPSBTs(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error getting pending psbts: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool                 `json:"ok"`
+		PSBTs []*asset.PSBTRequest `json:"psbts"`
+	}{
+		OK:    true,
+		PSBTs: reqs,
+	})
+}
+
+// apiSubmitPSBT handles the 'submitpsbt' API request.
+func (s *WebServer) apiSubmitPSBT(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+		ID      string `json:"id"`
+		PSBT    string `json:"psbt"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.SubmitSignedPSBT(form.AssetID, form.ID, form.PSBT); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error submitting psbt: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiMaxBuy handles the 'maxbuy' API request.
 func (s *WebServer) apiMaxBuy(w http.ResponseWriter, r *http.Request) {
 	form := &struct {
@@ -1541,30 +2064,63 @@ func (s *WebServer) actuallyLogin(w http.ResponseWriter, r *http.Request, login
 	if err != nil {
 		return fmt.Errorf("password error: %w", err)
 	}
-	err = s.core.Login(pass)
-	if err != nil {
+	if s.isGuestPass(pass) {
+		// The app password is checked first, so that a guest password that
+		// is also the app password can't lock the user out of a full
+		// session. Not being the app password is not a failed login.
+		if err := s.core.Login(pass); err != nil {
+			return s.guestLogin(w, r)
+		}
+		log.Warnf("The guest password is the app password. Logging in with full access. Set a different guest password.")
+	} else if err = s.checkLoginPass(r, pass); err != nil {
 		return fmt.Errorf("login error: %w", err)
 	}
 
-	if !s.isAuthed(r) {
+	// A guest session is upgraded with a new token.
+	if s.guestLogout(w, r) || !s.isAuthed(r) {
 		authToken := s.authorize()
-		setCookie(authCK, authToken, w)
+		s.setCookie(authCK, authToken, w)
 		key, err := s.cacheAppPassword(pass, authToken)
 		if err != nil {
 			return fmt.Errorf("login error: %w", err)
 
 		}
-		setCookie(pwKeyCK, hex.EncodeToString(key), w)
+		s.setCookie(pwKeyCK, hex.EncodeToString(key), w)
 		zero(key)
 	}
 
 	return nil
 }
 
+// loginRateLimitError is returned for a login attempt from a source with too
+// many recent failed attempts.
+type loginRateLimitError struct {
+	wait time.Duration
+}
+
+func (err *loginRateLimitError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %v", err.wait.Round(time.Second))
+}
+
+// checkLoginPass logs in with the app password, or just checks it if already
+// logged in. Failed attempts are limited by the request's source IP.
+func (s *WebServer) checkLoginPass(r *http.Request, pass []byte) error {
+	if ok, wait := s.loginLimiter.Allow(r.RemoteAddr); !ok {
+		return &loginRateLimitError{wait: wait}
+	}
+	if err := s.core.Login(pass); err != nil {
+		s.loginLimiter.Failure(r.RemoteAddr)
+		return err
+	}
+	s.loginLimiter.Success(r.RemoteAddr)
+	log.Infof("Login from %s", r.RemoteAddr)
+	return nil
+}
+
 // apiUser handles the 'user' API request.
 func (s *WebServer) apiUser(w http.ResponseWriter, r *http.Request) {
 	var u *core.User
-	if s.isAuthed(r) {
+	if s.isAuthedOrGuest(r) {
 		u = s.core.User()
 	}
 
@@ -1578,6 +2134,7 @@ func (s *WebServer) apiUser(w http.ResponseWriter, r *http.Request) {
 		Lang     string     `json:"lang"`
 		Langs    []string   `json:"langs"`
 		Inited   bool       `json:"inited"`
+		Guest    bool       `json:"guest"`
 		OK       bool       `json:"ok"`
 		OnionUrl string     `json:"onionUrl"`
 		MMStatus *mm.Status `json:"mmStatus"`
@@ -1586,6 +2143,7 @@ func (s *WebServer) apiUser(w http.ResponseWriter, r *http.Request) {
 		Lang:     s.lang.Load().(string),
 		Langs:    s.langs,
 		Inited:   s.core.IsInitialized(),
+		Guest:    s.isGuest(r),
 		OK:       true,
 		OnionUrl: s.onion,
 		MMStatus: mmStatus,
@@ -1610,6 +2168,55 @@ func (s *WebServer) apiToggleRateSource(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, simpleAck())
 }
 
+// apiBlockExplorers handles the '/blockexplorers' API request.
+func (s *WebServer) apiBlockExplorers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK        bool                        `json:"ok"`
+		Explorers *core.BlockExplorerSettings `json:"explorers"`
+	}{
+		OK:        true,
+		Explorers: s.core.BlockExplorers(),
+	})
+}
+
+// apiUpdateBlockExplorers handles the '/updateblockexplorers' API request.
+func (s *WebServer) apiUpdateBlockExplorers(w http.ResponseWriter, r *http.Request) {
+	settings := new(db.ExplorerSettings)
+	if !readPost(w, r, settings) {
+		return
+	}
+	if err := s.core.UpdateBlockExplorers(settings); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error updating block explorers: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiTorSettings handles the '/torsettings' API request.
+func (s *WebServer) apiTorSettings(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK  bool            `json:"ok"`
+		Tor *core.TorStatus `json:"tor"`
+	}{
+		OK:  true,
+		Tor: s.core.TorSettings(),
+	})
+}
+
+// apiUpdateTorSettings handles the '/updatetorsettings' API request. The new
+// settings take effect on restart.
+func (s *WebServer) apiUpdateTorSettings(w http.ResponseWriter, r *http.Request) {
+	settings := new(db.TorSettings)
+	if !readPost(w, r, settings) {
+		return
+	}
+	if err := s.core.UpdateTorSettings(settings); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error updating tor settings: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
 // apiDeleteArchiveRecords handles the '/deletearchivedrecords' API request.
 func (s *WebServer) apiDeleteArchivedRecords(w http.ResponseWriter, r *http.Request) {
 	form := new(deleteRecordsForm)
@@ -1737,6 +2344,94 @@ func (s *WebServer) apiRunLogs(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// apiPerformanceSummaries returns the performance summaries of the bots that
+// ran during the requested period. If the requested format is "csv", the
+// summaries are sent as a CSV file attachment.
+func (s *WebServer) apiPerformanceSummaries(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Start  int64  `json:"start"`
+		End    int64  `json:"end"`
+		Format string `json:"format"`
+	}
+	if !readPost(w, r, &req) {
+		return
+	}
+
+	summaries, err := s.mm.PerformanceSummaries(req.Start, req.End)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error getting performance summaries: %w", err))
+		return
+	}
+
+	switch req.Format {
+	case "", "json":
+		writeJSON(w, &struct {
+			OK        bool                     `json:"ok"`
+			Summaries []*mm.PerformanceSummary `json:"summaries"`
+		}{
+			OK:        true,
+			Summaries: summaries,
+		})
+	case "csv":
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=mmsummary_%d_%d.csv", req.Start, req.End))
+		w.Header().Set("Content-Type", "text/csv")
+		w.WriteHeader(http.StatusOK)
+		if err := mm.WritePerformanceSummariesCSV(w, summaries); err != nil {
+			log.Errorf("error writing performance summaries: %v", err)
+		}
+	default:
+		s.writeAPIError(w, fmt.Errorf("unknown format %q", req.Format))
+	}
+}
+
+// apiUpdateSummaryInterval sets the interval at which performance summary
+// notifications are sent. An empty interval disables them.
+func (s *WebServer) apiUpdateSummaryInterval(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Interval string `json:"interval"`
+	}
+	if !readPost(w, r, &req) {
+		return
+	}
+
+	if err := s.mm.UpdateSummaryInterval(req.Interval); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error updating summary interval: %w", err))
+		return
+	}
+
+	writeJSON(w, simpleAck())
+}
+
+func (s *WebServer) apiReconcileTransfers(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		StartTime             int64              `json:"startTime"`
+		Market                *mm.MarketWithHost `json:"market"`
+		MaxWithdrawalFeeRatio float64            `json:"maxWithdrawalFeeRatio"`
+	}
+	if !readPost(w, r, &req) {
+		return
+	}
+
+	if req.Market == nil {
+		s.writeAPIError(w, errors.New("market missing"))
+		return
+	}
+
+	report, err := s.mm.ReconcileTransfers(req.StartTime, req.Market, req.MaxWithdrawalFeeRatio)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error reconciling transfers: %w", err))
+		return
+	}
+
+	writeJSON(w, &struct {
+		OK     bool                     `json:"ok"`
+		Report *mm.ReconciliationReport `json:"report"`
+	}{
+		OK:     true,
+		Report: report,
+	})
+}
+
 func (s *WebServer) apiCEXBook(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Host    string `json:"host"`
@@ -2163,23 +2858,25 @@ func (s *WebServer) writeAPIError(w http.ResponseWriter, err error) {
 }
 
 // setCookie sets the value of a cookie in the http response.
-func setCookie(name, value string, w http.ResponseWriter) {
+func (s *WebServer) setCookie(name, value string, w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     name,
 		Path:     "/",
 		Value:    value,
-		SameSite: http.SameSiteStrictMode,
+		SameSite: s.cookieSameSite(),
+		Secure:   s.crossSiteCookies,
 	})
 }
 
 // clearCookie removes a cookie in the http response.
-func clearCookie(name string, w http.ResponseWriter) {
+func (s *WebServer) clearCookie(name string, w http.ResponseWriter) {
 	http.SetCookie(w, &http.Cookie{
 		Name:     name,
 		Path:     "/",
 		Value:    "",
 		Expires:  time.Unix(0, 0),
-		SameSite: http.SameSiteStrictMode,
+		SameSite: s.cookieSameSite(),
+		Secure:   s.crossSiteCookies,
 	})
 }
 
@@ -2189,6 +2886,9 @@ func (s *WebServer) resolvePass(appPW []byte, r *http.Request) ([]byte, error) {
 	if len(appPW) > 0 {
 		return appPW, nil
 	}
+	if auth := extractAPIKeyAuth(r); auth != nil {
+		return auth.pass()
+	}
 	cachedPass, err := s.getCachedPasswordUsingRequest(r)
 	if err != nil {
 		if errors.Is(err, errNoCachedPW) {
@@ -0,0 +1,374 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+	"github.com/go-chi/chi/v5"
+)
+
+// apiKeyScope is a permission granted to an API key.
+type apiKeyScope string
+
+const (
+	// scopeRead permits reading wallets, markets, orders, bonds, and bots.
+	scopeRead apiKeyScope = "read"
+	// scopeTrade permits placing and canceling orders.
+	scopeTrade apiKeyScope = "trade"
+	// scopeWalletSend permits sending funds, generating addresses, and
+	// posting bonds.
+	scopeWalletSend apiKeyScope = "wallet-send"
+	// scopeMMAdmin permits starting and stopping market making bots.
+	scopeMMAdmin apiKeyScope = "mm-admin"
+)
+
+var apiKeyScopes = map[apiKeyScope]bool{
+	scopeRead:       true,
+	scopeTrade:      true,
+	scopeWalletSend: true,
+	scopeMMAdmin:    true,
+}
+
+// passScopes are the scopes with requests that need the app password.
+var passScopes = map[apiKeyScope]bool{
+	scopeTrade:      true,
+	scopeWalletSend: true,
+	scopeMMAdmin:    true,
+}
+
+const apiKeysFilename = "apikeys.json"
+
+// apiKey is a token for programmatic access to the v1 API. Only a hash of the
+// token is stored. If the key has a scope that needs the app password, the
+// password is stored encrypted with the token, so that requests authorized by
+// the key don't need the password. Keys issued before the app password is
+// changed can't be used for those requests.
+type apiKey struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Scopes []apiKeyScope `json:"scopes"`
+	// Created and Expiry are Unix times in seconds. An Expiry of zero means
+	// the key does not expire.
+	Created           int64     `json:"created"`
+	Expiry            int64     `json:"expiry,omitempty"`
+	TokenHash         dex.Bytes `json:"tokenHash"`
+	EncryptedPass     dex.Bytes `json:"encPass,omitempty"`
+	SerializedCrypter dex.Bytes `json:"crypter,omitempty"`
+}
+
+func (k *apiKey) expired() bool {
+	return k.Expiry != 0 && time.Now().Unix() >= k.Expiry
+}
+
+func (k *apiKey) hasScope(scope apiKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// needsPass is true if the key has a scope with requests that need the app
+// password.
+func (k *apiKey) needsPass() bool {
+	for _, s := range k.Scopes {
+		if passScopes[s] {
+			return true
+		}
+	}
+	return false
+}
+
+// v1APIKey is an API key as listed by the v1 API.
+type v1APIKey struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	Scopes  []apiKeyScope `json:"scopes"`
+	Created int64         `json:"created"`
+	Expiry  int64         `json:"expiry,omitempty"`
+	Expired bool          `json:"expired"`
+}
+
+func newV1APIKey(k *apiKey) *v1APIKey {
+	return &v1APIKey{
+		ID:      k.ID,
+		Name:    k.Name,
+		Scopes:  k.Scopes,
+		Created: k.Created,
+		Expiry:  k.Expiry,
+		Expired: k.expired(),
+	}
+}
+
+// apiKeyAuth is the API key that authorized a request, and its token.
+type apiKeyAuth struct {
+	key   *apiKey
+	token string
+}
+
+// pass decrypts the app password stored with the key.
+func (a *apiKeyAuth) pass() ([]byte, error) {
+	if len(a.key.EncryptedPass) == 0 {
+		return nil, errors.New("no password stored with the API key")
+	}
+	crypter, err := encrypt.Deserialize([]byte(a.token), a.key.SerializedCrypter)
+	if err != nil {
+		return nil, fmt.Errorf("error deserializing crypter: %w", err)
+	}
+	defer crypter.Close()
+	pw, err := crypter.Decrypt(a.key.EncryptedPass)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting password: %w", err)
+	}
+	return pw, nil
+}
+
+// extractAPIKeyAuth gets the apiKeyAuth set by rejectUnauthedV1, which is nil
+// if the request was authorized by a login session.
+func extractAPIKeyAuth(r *http.Request) *apiKeyAuth {
+	auth, _ := r.Context().Value(ctxAPIKey).(*apiKeyAuth)
+	return auth
+}
+
+func apiKeyTokenHash(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+// getBearerToken gets the token from the request's Authorization header.
+func getBearerToken(r *http.Request) string {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// loadAPIKeys loads the API keys saved in the data directory.
+func (s *WebServer) loadAPIKeys() error {
+	if s.dataDir == "" {
+		return nil
+	}
+	b, err := os.ReadFile(filepath.Join(s.dataDir, apiKeysFilename))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	var keys []*apiKey
+	if err := json.Unmarshal(b, &keys); err != nil {
+		return fmt.Errorf("error parsing API keys: %w", err)
+	}
+	for _, k := range keys {
+		s.apiKeys[k.TokenHash.String()] = k
+	}
+	return nil
+}
+
+// saveAPIKeys saves the API keys to the data directory. The apiKeysMtx must
+// be locked.
+func (s *WebServer) saveAPIKeys() error {
+	if s.dataDir == "" {
+		return nil
+	}
+	keys := make([]*apiKey, 0, len(s.apiKeys))
+	for _, k := range s.apiKeys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created < keys[j].Created })
+	b, err := json.MarshalIndent(keys, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dataDir, 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dataDir, apiKeysFilename), b, 0600)
+}
+
+// apiKeyForToken gets the unexpired API key with the token.
+func (s *WebServer) apiKeyForToken(token string) *apiKey {
+	s.apiKeysMtx.RLock()
+	k := s.apiKeys[apiKeyTokenHash(token)]
+	s.apiKeysMtx.RUnlock()
+	if k == nil || k.expired() {
+		return nil
+	}
+	return k
+}
+
+// newAPIKey issues a new API key, returning the key and its token.
+func (s *WebServer) newAPIKey(name string, scopes []apiKeyScope, expiry int64, appPW []byte) (*apiKey, string, error) {
+	if len(scopes) == 0 {
+		return nil, "", errors.New("no scopes")
+	}
+	for _, scope := range scopes {
+		if !apiKeyScopes[scope] {
+			return nil, "", fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	now := time.Now().Unix()
+	if expiry != 0 && expiry <= now {
+		return nil, "", errors.New("expiry is in the past")
+	}
+
+	token := hex.EncodeToString(encode.RandomBytes(32))
+	tokenHash := apiKeyTokenHash(token)
+	k := &apiKey{
+		ID:      hex.EncodeToString(encode.RandomBytes(8)),
+		Name:    name,
+		Scopes:  scopes,
+		Created: now,
+		Expiry:  expiry,
+	}
+	k.TokenHash, _ = hex.DecodeString(tokenHash)
+	// The password is only stored if a scope needs it.
+	if k.needsPass() {
+		crypter := encrypt.NewCrypter([]byte(token))
+		defer crypter.Close()
+		encPW, err := crypter.Encrypt(appPW)
+		if err != nil {
+			return nil, "", fmt.Errorf("error encrypting password: %w", err)
+		}
+		k.EncryptedPass = encPW
+		k.SerializedCrypter = crypter.Serialize()
+	}
+
+	s.apiKeysMtx.Lock()
+	defer s.apiKeysMtx.Unlock()
+	s.apiKeys[tokenHash] = k
+	if err := s.saveAPIKeys(); err != nil {
+		delete(s.apiKeys, tokenHash)
+		return nil, "", fmt.Errorf("error saving API keys: %w", err)
+	}
+	return k, token, nil
+}
+
+// revokeAPIKey deletes the API key with the ID. found is false if there is no
+// such key.
+func (s *WebServer) revokeAPIKey(id string) (found bool, err error) {
+	s.apiKeysMtx.Lock()
+	defer s.apiKeysMtx.Unlock()
+	for tokenHash, k := range s.apiKeys {
+		if k.ID != id {
+			continue
+		}
+		delete(s.apiKeys, tokenHash)
+		if err := s.saveAPIKeys(); err != nil {
+			s.apiKeys[tokenHash] = k
+			return true, fmt.Errorf("error saving API keys: %w", err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// requireScopeV1 rejects requests authorized by an API key without the scope.
+// Login sessions have every scope.
+func requireScopeV1(scope apiKeyScope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if auth := extractAPIKeyAuth(r); auth != nil && !auth.key.hasScope(scope) {
+				writeV1Error(w, fmt.Errorf("API key does not have the %s scope", scope), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireSessionV1 rejects requests authorized by an API key.
+func requireSessionV1(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if extractAPIKeyAuth(r) != nil {
+			writeV1Error(w, errors.New("this request requires a login session"), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// withAPIKeyAuth embeds the apiKeyAuth into the request context.
+func withAPIKeyAuth(r *http.Request, auth *apiKeyAuth) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), ctxAPIKey, auth))
+}
+
+// apiV1APIKeys lists the API keys.
+func (s *WebServer) apiV1APIKeys(w http.ResponseWriter, r *http.Request) {
+	s.apiKeysMtx.RLock()
+	keys := make([]*v1APIKey, 0, len(s.apiKeys))
+	for _, k := range s.apiKeys {
+		keys = append(keys, newV1APIKey(k))
+	}
+	s.apiKeysMtx.RUnlock()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created < keys[j].Created })
+	writeJSON(w, keys)
+}
+
+// apiV1NewAPIKey issues an API key. The token is only ever returned in this
+// response.
+func (s *WebServer) apiV1NewAPIKey(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		Name   string           `json:"name"`
+		Scopes []apiKeyScope    `json:"scopes"`
+		Expiry int64            `json:"expiry"`
+		Pass   encode.PassBytes `json:"pass"`
+	}{}
+	defer form.Pass.Clear()
+	if !readV1Body(w, r, form) {
+		return
+	}
+	if len(form.Pass) == 0 {
+		writeV1Error(w, errors.New("empty password"), http.StatusBadRequest)
+		return
+	}
+	if err := s.checkLoginPass(r, form.Pass); err != nil {
+		writeV1LoginError(w, fmt.Errorf("password error: %w", err))
+		return
+	}
+	k, token, err := s.newAPIKey(form.Name, form.Scopes, form.Expiry, form.Pass)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	writeJSONWithStatus(w, &struct {
+		*v1APIKey
+		Token string `json:"token"`
+	}{
+		v1APIKey: newV1APIKey(k),
+		Token:    token,
+	}, http.StatusCreated)
+}
+
+// apiV1RevokeAPIKey revokes an API key.
+func (s *WebServer) apiV1RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	found, err := s.revokeAPIKey(id)
+	if err != nil {
+		writeV1Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		writeV1Error(w, fmt.Errorf("no API key %q", id), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,1251 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/client/websocket"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/order"
+	"github.com/go-chi/chi/v5"
+)
+
+// The v1 REST API is a documented, versioned alternative to the internal API
+// used by the frontend. Resources are addressed by path, request methods have
+// their usual meanings, and failures are reported with an HTTP status code and
+// a v1Error body. The JSON schemas of the v1 types below are described in
+// openapi_v1.json, and must not change in backwards-incompatible ways.
+
+// defaultV1OrdersN is the number of orders listed if the request does not
+// specify n.
+const defaultV1OrdersN = 50
+
+// openAPIV1 is the OpenAPI description of the v1 REST API.
+//
+//go:embed openapi_v1.json
+var openAPIV1 []byte
+
+// v1OrderStatuses maps the order status names used by the v1 API to their
+// order.OrderStatus.
+var v1OrderStatuses = map[string]order.OrderStatus{
+	order.OrderStatusEpoch.String():    order.OrderStatusEpoch,
+	order.OrderStatusBooked.String():   order.OrderStatusBooked,
+	order.OrderStatusExecuted.String(): order.OrderStatusExecuted,
+	order.OrderStatusCanceled.String(): order.OrderStatusCanceled,
+	order.OrderStatusRevoked.String():  order.OrderStatusRevoked,
+}
+
+// v1Error is the body of a failed v1 API request.
+type v1Error struct {
+	Error string `json:"error"`
+	// Code is the core error code, if there is one.
+	Code *int `json:"code,omitempty"`
+}
+
+// v1Balance is a wallet balance. All amounts are in atomic units.
+type v1Balance struct {
+	Available      uint64 `json:"available"`
+	Immature       uint64 `json:"immature"`
+	Locked         uint64 `json:"locked"`
+	OrderLocked    uint64 `json:"orderLocked"`
+	ContractLocked uint64 `json:"contractLocked"`
+	BondLocked     uint64 `json:"bondLocked"`
+}
+
+// v1Wallet is a wallet.
+type v1Wallet struct {
+	AssetID      uint32     `json:"assetID"`
+	Symbol       string     `json:"symbol"`
+	Type         string     `json:"type"`
+	Open         bool       `json:"open"`
+	Running      bool       `json:"running"`
+	Disabled     bool       `json:"disabled"`
+	Synced       bool       `json:"synced"`
+	SyncProgress float32    `json:"syncProgress"`
+	PeerCount    uint32     `json:"peerCount"`
+	Address      string     `json:"address"`
+	Balance      *v1Balance `json:"balance"`
+}
+
+// v1Market is a market on a DEX server.
+type v1Market struct {
+	Host        string `json:"host"`
+	Name        string `json:"name"`
+	BaseID      uint32 `json:"baseID"`
+	BaseSymbol  string `json:"baseSymbol"`
+	QuoteID     uint32 `json:"quoteID"`
+	QuoteSymbol string `json:"quoteSymbol"`
+	LotSize     uint64 `json:"lotSize"`
+	ParcelSize  uint32 `json:"parcelSize"`
+	RateStep    uint64 `json:"rateStep"`
+	EpochLen    uint64 `json:"epochLen"`
+	MinimumRate uint64 `json:"minimumRate"`
+}
+
+// v1BookOrder is an order in an order book.
+type v1BookOrder struct {
+	Qty   uint64 `json:"qty"`
+	Rate  uint64 `json:"rate"`
+	Sell  bool   `json:"sell"`
+	Epoch uint64 `json:"epoch,omitempty"`
+}
+
+// v1Book is a market's order book.
+type v1Book struct {
+	Sells []*v1BookOrder `json:"sells"`
+	Buys  []*v1BookOrder `json:"buys"`
+	Epoch []*v1BookOrder `json:"epoch"`
+}
+
+// v1Match is a match of an order.
+type v1Match struct {
+	MatchID     string `json:"matchID"`
+	Status      string `json:"status"`
+	Side        string `json:"side"`
+	Active      bool   `json:"active"`
+	Revoked     bool   `json:"revoked"`
+	Rate        uint64 `json:"rate"`
+	Qty         uint64 `json:"qty"`
+	Stamp       uint64 `json:"stamp"`
+	IsCancel    bool   `json:"isCancel"`
+	Swap        string `json:"swap,omitempty"`
+	CounterSwap string `json:"counterSwap,omitempty"`
+	Redeem      string `json:"redeem,omitempty"`
+	Refund      string `json:"refund,omitempty"`
+}
+
+// v1Order is an order.
+type v1Order struct {
+	ID          string     `json:"id"`
+	Host        string     `json:"host"`
+	Market      string     `json:"market"`
+	BaseID      uint32     `json:"baseID"`
+	QuoteID     uint32     `json:"quoteID"`
+	Type        string     `json:"type"`
+	Sell        bool       `json:"sell"`
+	Qty         uint64     `json:"qty"`
+	Rate        uint64     `json:"rate,omitempty"`
+	TimeInForce string     `json:"tif,omitempty"`
+	Status      string     `json:"status"`
+	Filled      uint64     `json:"filled"`
+	Stamp       uint64     `json:"stamp"`
+	Epoch       uint64     `json:"epoch"`
+	Cancelling  bool       `json:"cancelling"`
+	Canceled    bool       `json:"canceled"`
+	Matches     []*v1Match `json:"matches"`
+}
+
+// v1TradeForm is the body of a request to place an order.
+type v1TradeForm struct {
+	Host    string            `json:"host"`
+	BaseID  uint32            `json:"baseID"`
+	QuoteID uint32            `json:"quoteID"`
+	Sell    bool              `json:"sell"`
+	Type    string            `json:"type"`
+	Qty     uint64            `json:"qty"`
+	Rate    uint64            `json:"rate"`
+	TifNow  bool              `json:"tifNow"`
+	Options map[string]string `json:"options"`
+	Pass    encode.PassBytes  `json:"pass"`
+}
+
+// v1SearchResult is a notification, order or match found by a search.
+type v1SearchResult struct {
+	Type         string           `json:"type"`
+	Stamp        uint64           `json:"stamp"`
+	Notification *db.Notification `json:"notification,omitempty"`
+	Order        *v1Order         `json:"order,omitempty"`
+	MatchID      string           `json:"matchID,omitempty"`
+}
+
+// v1PendingBond is a bond that is waiting for confirmations.
+type v1PendingBond struct {
+	CoinID  string `json:"coinID"`
+	AssetID uint32 `json:"assetID"`
+	Confs   uint32 `json:"confs"`
+}
+
+// v1Bonds is the bonding status of the account on a DEX server.
+type v1Bonds struct {
+	Host            string           `json:"host"`
+	BondAssetID     uint32           `json:"bondAssetID"`
+	TargetTier      uint64           `json:"targetTier"`
+	EffectiveTier   int64            `json:"effectiveTier"`
+	LiveStrength    int64            `json:"liveStrength"`
+	PendingStrength int64            `json:"pendingStrength"`
+	WeakStrength    int64            `json:"weakStrength"`
+	MaxBondedAmt    uint64           `json:"maxBondedAmt"`
+	PenaltyComps    uint16           `json:"penaltyComps"`
+	Score           int32            `json:"score"`
+	PendingBonds    []*v1PendingBond `json:"pendingBonds"`
+}
+
+// v1BotMarket identifies the market of a market making bot.
+type v1BotMarket struct {
+	Host    string `json:"host"`
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+}
+
+// v1Bot is a configured market making bot.
+type v1Bot struct {
+	v1BotMarket
+	Running bool `json:"running"`
+	// The rest are only set while the bot is running.
+	StartTime        int64   `json:"startTime,omitempty"`
+	CompletedMatches uint32  `json:"completedMatches,omitempty"`
+	TradedUSD        float64 `json:"tradedUSD,omitempty"`
+	ProfitUSD        float64 `json:"profitUSD,omitempty"`
+}
+
+// registerAPIV1 adds the v1 REST API routes to the router, which is mounted
+// on /api/v1.
+func (s *WebServer) registerAPIV1(r chi.Router) {
+	r.Get("/openapi.json", s.apiV1OpenAPI)
+
+	r.Group(func(apiInit chi.Router) {
+		apiInit.Use(s.rejectUninitedV1)
+		apiInit.Post("/login", s.apiV1Login)
+
+		// Read-only routes that guest sessions can use. Guests are denied
+		// everything else.
+		apiInit.Group(func(apiGuest chi.Router) {
+			apiGuest.Use(s.rejectUnauthedAllowGuestV1)
+
+			apiGuest.Group(func(session chi.Router) {
+				session.Use(requireSessionV1)
+				session.Post("/logout", s.apiV1Logout)
+				session.Get("/csrf", s.apiV1CSRFToken)
+			})
+
+			apiGuest.Group(func(read chi.Router) {
+				read.Use(requireScopeV1(scopeRead))
+				read.Get("/wallets", s.apiV1Wallets)
+				read.Get("/wallets/{assetID}", s.apiV1Wallet)
+				read.Get("/markets", s.apiV1Markets)
+				read.Get("/tickers", s.apiV1Tickers)
+				read.Get("/markets/{host}/{baseID}/{quoteID}/book", s.apiV1Book)
+				read.Get("/markets/{host}/{baseID}/{quoteID}/depth", s.apiV1Depth)
+				read.Get("/markets/{host}/{baseID}/{quoteID}/candles", s.apiV1Candles)
+				read.Get("/orders", s.apiV1Orders)
+				read.Get("/orders/{oid}", s.apiV1Order)
+				read.Get("/export/orders", s.apiV1ExportOrders)
+				read.Get("/export/matches", s.apiV1ExportMatches)
+				read.Get("/export/transactions/{assetID}", s.apiV1ExportTransactions)
+				read.Get("/search", s.apiV1Search)
+				read.Get("/stats/markets", s.apiV1MarketStats)
+				read.Get("/fiatrates/{assetID}", s.apiV1FiatRateHistory)
+				read.Get("/fiatrates/{assetID}/at/{stamp}", s.apiV1FiatRateAt)
+				read.Get("/bonds", s.apiV1Bonds)
+				read.Get("/mm/bots", s.apiV1Bots)
+				read.Get("/mm/events", s.apiV1MMEvents)
+				read.Get("/settings/explorers", s.apiV1Explorers)
+				read.Get("/settings/tor", s.apiV1Tor)
+				read.Get("/settings/proxies", s.apiV1Proxies)
+				read.Get("/settings/retention", s.apiV1Retention)
+			})
+		})
+
+		apiInit.Group(func(apiAuth chi.Router) {
+			apiAuth.Use(s.rejectUnauthedV1)
+
+			// API keys and settings can't be managed with an API key.
+			apiAuth.Group(func(keys chi.Router) {
+				keys.Use(requireSessionV1)
+				keys.Get("/keys", s.apiV1APIKeys)
+				keys.Post("/keys", s.apiV1NewAPIKey)
+				keys.Delete("/keys/{id}", s.apiV1RevokeAPIKey)
+				keys.Put("/settings/explorers", s.apiV1UpdateExplorers)
+				keys.Put("/settings/tor", s.apiV1UpdateTor)
+				keys.Put("/settings/proxies", s.apiV1UpdateProxies)
+				keys.Put("/settings/retention", s.apiV1UpdateRetention)
+			})
+
+			apiAuth.Group(func(trade chi.Router) {
+				trade.Use(requireScopeV1(scopeTrade))
+				trade.Post("/orders", s.apiV1Trade)
+				trade.Delete("/orders/{oid}", s.apiV1Cancel)
+			})
+
+			apiAuth.Group(func(send chi.Router) {
+				send.Use(requireScopeV1(scopeWalletSend))
+				send.Post("/wallets/{assetID}/address", s.apiV1NewAddress)
+				send.Post("/wallets/{assetID}/send", s.apiV1Send)
+				send.Post("/bonds", s.apiV1PostBond)
+			})
+
+			apiAuth.Group(func(mmAdmin chi.Router) {
+				mmAdmin.Use(requireScopeV1(scopeMMAdmin))
+				mmAdmin.Post("/mm/bots/start", s.apiV1StartBot)
+				mmAdmin.Post("/mm/bots/stop", s.apiV1StopBot)
+			})
+		})
+	})
+}
+
+// writeV1Error logs the error and writes a v1Error with the HTTP status code.
+func writeV1Error(w http.ResponseWriter, err error, status int) {
+	var cErr *core.Error
+	var code *int
+	if errors.As(err, &cErr) {
+		code = cErr.Code()
+	}
+	log.Errorf("v1 API error: %v", err)
+	writeJSONWithStatus(w, &v1Error{
+		Error: core.UnwrapErr(err).Error(),
+		Code:  code,
+	}, status)
+}
+
+// writeV1LoginError writes a failed login error, which is 429 Too Many
+// Requests with a Retry-After header if the source is rate limited.
+func writeV1LoginError(w http.ResponseWriter, err error) {
+	var rlErr *loginRateLimitError
+	if errors.As(err, &rlErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rlErr.wait.Seconds()))))
+		writeV1Error(w, err, http.StatusTooManyRequests)
+		return
+	}
+	writeV1Error(w, err, http.StatusUnauthorized)
+}
+
+// readV1Body unmarshals the request body into thing, responding with an error
+// if the body is invalid.
+func readV1Body(w http.ResponseWriter, r *http.Request, thing any) bool {
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error reading request body: %w", err), http.StatusBadRequest)
+		return false
+	}
+	if err = json.Unmarshal(body, thing); err != nil {
+		writeV1Error(w, fmt.Errorf("invalid JSON request body: %w", err), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// rejectUninitedV1 is like rejectUninited, but responds with a v1Error.
+func (s *WebServer) rejectUninitedV1(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.core.IsInitialized() {
+			writeV1Error(w, errors.New("app not initialized"), http.StatusPreconditionRequired)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rejectUnauthedV1 is like rejectUnauthed, but responds with a v1Error, and
+// also accepts an API key as a bearer token. Use extractAPIKeyAuth to access
+// the API key in downstream handlers.
+func (s *WebServer) rejectUnauthedV1(next http.Handler) http.Handler {
+	return s.checkAuthV1(next, false)
+}
+
+// rejectUnauthedAllowGuestV1 is like rejectUnauthedV1, but also allows guest
+// sessions. It must only be used for read-only routes.
+func (s *WebServer) rejectUnauthedAllowGuestV1(next http.Handler) http.Handler {
+	return s.checkAuthV1(next, true)
+}
+
+// checkAuthV1 authorizes requests by API key or session cookie. Guest sessions
+// are only authorized if allowGuest is true.
+func (s *WebServer) checkAuthV1(next http.Handler, allowGuest bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := getBearerToken(r); token != "" {
+			k := s.apiKeyForToken(token)
+			if k == nil {
+				writeV1Error(w, errors.New("invalid or expired API key"), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, withAPIKeyAuth(r, &apiKeyAuth{key: k, token: token}))
+			return
+		}
+		if s.isAuthed(r) || (allowGuest && s.isGuest(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if s.isGuest(r) {
+			writeV1Error(w, errGuest, http.StatusForbidden)
+			return
+		}
+		writeV1Error(w, errors.New("not authorized - login first"), http.StatusUnauthorized)
+	})
+}
+
+// v1AssetIDParam parses the {assetID} URL parameter.
+func v1AssetIDParam(r *http.Request, name string) (uint32, error) {
+	v, err := strconv.ParseUint(chi.URLParam(r, name), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return uint32(v), nil
+}
+
+// v1OrderIDParam parses the {oid} URL parameter.
+func v1OrderIDParam(r *http.Request) (dex.Bytes, error) {
+	oid, err := hex.DecodeString(chi.URLParam(r, "oid"))
+	if err != nil || len(oid) != order.OrderIDSize {
+		return nil, errors.New("invalid order ID")
+	}
+	return oid, nil
+}
+
+// apiV1OpenAPI serves the OpenAPI description of the v1 API.
+func (s *WebServer) apiV1OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if _, err := w.Write(openAPIV1); err != nil {
+		log.Errorf("Write error: %v", err)
+	}
+}
+
+// apiV1Login logs in, setting the session cookies that authorize the rest of
+// the v1 API.
+func (s *WebServer) apiV1Login(w http.ResponseWriter, r *http.Request) {
+	login := new(loginForm)
+	defer login.Pass.Clear()
+	if !readV1Body(w, r, login) {
+		return
+	}
+	if err := s.actuallyLogin(w, r, login); err != nil {
+		writeV1LoginError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiV1Logout logs out and invalidates all sessions.
+func (s *WebServer) apiV1Logout(w http.ResponseWriter, r *http.Request) {
+	if s.guestLogout(w, r) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err := s.core.Logout(); err != nil {
+		writeV1Error(w, fmt.Errorf("logout error: %w", err), http.StatusInternalServerError)
+		return
+	}
+	s.deauth()
+	s.clearCookie(authCK, w)
+	s.clearCookie(pwKeyCK, w)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newV1Wallet(st *core.WalletState) *v1Wallet {
+	wallet := &v1Wallet{
+		AssetID:      st.AssetID,
+		Symbol:       st.Symbol,
+		Type:         st.WalletType,
+		Open:         st.Open,
+		Running:      st.Running,
+		Disabled:     st.Disabled,
+		Synced:       st.Synced,
+		SyncProgress: st.SyncProgress,
+		PeerCount:    st.PeerCount,
+		Address:      st.Address,
+	}
+	if bal := st.Balance; bal != nil && bal.Balance != nil {
+		wallet.Balance = &v1Balance{
+			Available:      bal.Available,
+			Immature:       bal.Immature,
+			Locked:         bal.Locked,
+			OrderLocked:    bal.OrderLocked,
+			ContractLocked: bal.ContractLocked,
+			BondLocked:     bal.BondLocked,
+		}
+	}
+	return wallet
+}
+
+// apiV1Wallets lists the wallets.
+func (s *WebServer) apiV1Wallets(w http.ResponseWriter, r *http.Request) {
+	states := s.core.Wallets()
+	wallets := make([]*v1Wallet, 0, len(states))
+	for _, st := range states {
+		wallets = append(wallets, newV1Wallet(st))
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].AssetID < wallets[j].AssetID })
+	writeJSON(w, wallets)
+}
+
+// v1WalletState gets the state of the {assetID} wallet, responding with an
+// error if there is no such wallet.
+func (s *WebServer) v1WalletState(w http.ResponseWriter, r *http.Request) *core.WalletState {
+	assetID, err := v1AssetIDParam(r, "assetID")
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return nil
+	}
+	st := s.core.WalletState(assetID)
+	if st == nil {
+		writeV1Error(w, fmt.Errorf("no %s wallet", unbip(assetID)), http.StatusNotFound)
+		return nil
+	}
+	return st
+}
+
+// apiV1Wallet gets a wallet.
+func (s *WebServer) apiV1Wallet(w http.ResponseWriter, r *http.Request) {
+	if st := s.v1WalletState(w, r); st != nil {
+		writeJSON(w, newV1Wallet(st))
+	}
+}
+
+// apiV1NewAddress generates a new deposit address.
+func (s *WebServer) apiV1NewAddress(w http.ResponseWriter, r *http.Request) {
+	st := s.v1WalletState(w, r)
+	if st == nil {
+		return
+	}
+	addr, err := s.core.NewDepositAddress(st.AssetID)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error generating address: %w", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, &struct {
+		Address string `json:"address"`
+	}{
+		Address: addr,
+	})
+}
+
+// apiV1Send sends funds from a wallet. The app password is always required,
+// unless the request is authorized by an API key.
+func (s *WebServer) apiV1Send(w http.ResponseWriter, r *http.Request) {
+	st := s.v1WalletState(w, r)
+	if st == nil {
+		return
+	}
+	form := &struct {
+		Address  string           `json:"address"`
+		Value    uint64           `json:"value"`
+		Subtract bool             `json:"subtract"`
+		Pass     encode.PassBytes `json:"pass"`
+	}{}
+	defer form.Pass.Clear()
+	if !readV1Body(w, r, form) {
+		return
+	}
+	pass := []byte(form.Pass)
+	if auth := extractAPIKeyAuth(r); auth != nil && len(pass) == 0 {
+		var err error
+		if pass, err = auth.pass(); err != nil {
+			writeV1Error(w, fmt.Errorf("password error: %w", err), http.StatusUnauthorized)
+			return
+		}
+		defer zero(pass)
+	}
+	if len(pass) == 0 {
+		writeV1Error(w, errors.New("empty password"), http.StatusBadRequest)
+		return
+	}
+	coin, err := s.core.Send(pass, st.AssetID, form.Value, form.Address, form.Subtract)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("send error: %w", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, &struct {
+		Coin string `json:"coin"`
+		TxID string `json:"txID"`
+	}{
+		Coin: coin.String(),
+		TxID: coin.TxID(),
+	})
+}
+
+// apiV1Markets lists the markets of all known DEX servers.
+func (s *WebServer) apiV1Markets(w http.ResponseWriter, r *http.Request) {
+	markets := make([]*v1Market, 0)
+	for host, xc := range s.core.Exchanges() {
+		for _, mkt := range xc.Markets {
+			markets = append(markets, &v1Market{
+				Host:        host,
+				Name:        mkt.Name,
+				BaseID:      mkt.BaseID,
+				BaseSymbol:  mkt.BaseSymbol,
+				QuoteID:     mkt.QuoteID,
+				QuoteSymbol: mkt.QuoteSymbol,
+				LotSize:     mkt.LotSize,
+				ParcelSize:  mkt.ParcelSize,
+				RateStep:    mkt.RateStep,
+				EpochLen:    mkt.EpochLen,
+				MinimumRate: mkt.MinimumRate,
+			})
+		}
+	}
+	sort.Slice(markets, func(i, j int) bool {
+		if markets[i].Host != markets[j].Host {
+			return markets[i].Host < markets[j].Host
+		}
+		return markets[i].Name < markets[j].Name
+	})
+	writeJSON(w, markets)
+}
+
+func newV1BookOrders(ords []*core.MiniOrder) []*v1BookOrder {
+	bookOrds := make([]*v1BookOrder, 0, len(ords))
+	for _, ord := range ords {
+		bookOrds = append(bookOrds, &v1BookOrder{
+			Qty:   ord.QtyAtomic,
+			Rate:  ord.MsgRate,
+			Sell:  ord.Sell,
+			Epoch: ord.Epoch,
+		})
+	}
+	return bookOrds
+}
+
+// apiV1Book gets a market's order book.
+func (s *WebServer) apiV1Book(w http.ResponseWriter, r *http.Request) {
+	host, baseID, quoteID, err := v1MarketParams(r)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	book, err := s.core.Book(host, baseID, quoteID)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error getting order book: %w", err), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, &v1Book{
+		Sells: newV1BookOrders(book.Sells),
+		Buys:  newV1BookOrders(book.Buys),
+		Epoch: newV1BookOrders(book.Epoch),
+	})
+}
+
+func newV1Order(ord *core.Order) *v1Order {
+	o := &v1Order{
+		ID:         ord.ID.String(),
+		Host:       ord.Host,
+		Market:     ord.MarketID,
+		BaseID:     ord.BaseID,
+		QuoteID:    ord.QuoteID,
+		Type:       ord.Type.String(),
+		Sell:       ord.Sell,
+		Qty:        ord.Qty,
+		Status:     ord.Status.String(),
+		Filled:     ord.Filled,
+		Stamp:      ord.Stamp,
+		Epoch:      ord.Epoch,
+		Cancelling: ord.Cancelling,
+		Canceled:   ord.Canceled,
+		Matches:    make([]*v1Match, 0, len(ord.Matches)),
+	}
+	if ord.Type == order.LimitOrderType {
+		o.Rate = ord.Rate
+		o.TimeInForce = ord.TimeInForce.String()
+	}
+	coinID := func(c *core.Coin) string {
+		if c == nil {
+			return ""
+		}
+		return c.StringID
+	}
+	for _, m := range ord.Matches {
+		o.Matches = append(o.Matches, &v1Match{
+			MatchID:     m.MatchID.String(),
+			Status:      m.Status.String(),
+			Side:        m.Side.String(),
+			Active:      m.Active,
+			Revoked:     m.Revoked,
+			Rate:        m.Rate,
+			Qty:         m.Qty,
+			Stamp:       m.Stamp,
+			IsCancel:    m.IsCancel,
+			Swap:        coinID(m.Swap),
+			CounterSwap: coinID(m.CounterSwap),
+			Redeem:      coinID(m.Redeem),
+			Refund:      coinID(m.Refund),
+		})
+	}
+	return o
+}
+
+// parseV1OrderFilter parses the order filter query parameters. The parameters
+// host and status may be repeated. base and quote must be specified together.
+func parseV1OrderFilter(q url.Values) (*core.OrderFilter, error) {
+	filter := &core.OrderFilter{
+		N:     defaultV1OrdersN,
+		Hosts: q["host"],
+	}
+	if nStr := q.Get("n"); nStr != "" {
+		n, err := strconv.Atoi(nStr)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid n %q", nStr)
+		}
+		filter.N = n
+	}
+	if offset := q.Get("offset"); offset != "" {
+		oid, err := hex.DecodeString(offset)
+		if err != nil || len(oid) != order.OrderIDSize {
+			return nil, fmt.Errorf("invalid offset %q", offset)
+		}
+		filter.Offset = oid
+	}
+	for _, name := range q["status"] {
+		status, found := v1OrderStatuses[name]
+		if !found {
+			return nil, fmt.Errorf("unknown order status %q", name)
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	baseStr, quoteStr := q.Get("base"), q.Get("quote")
+	if baseStr != "" || quoteStr != "" {
+		base, err := strconv.ParseUint(baseStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base %q", baseStr)
+		}
+		quote, err := strconv.ParseUint(quoteStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quote %q", quoteStr)
+		}
+		filter.Market = &struct {
+			Base  uint32 `json:"baseID"`
+			Quote uint32 `json:"quoteID"`
+		}{
+			Base:  uint32(base),
+			Quote: uint32(quote),
+		}
+	}
+	return filter, nil
+}
+
+// apiV1Orders lists orders, newest first. See parseV1OrderFilter for the query
+// parameters.
+func (s *WebServer) apiV1Orders(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseV1OrderFilter(r.URL.Query())
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	ords, err := s.core.Orders(filter)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error listing orders: %w", err), http.StatusInternalServerError)
+		return
+	}
+	v1Ords := make([]*v1Order, 0, len(ords))
+	for _, ord := range ords {
+		v1Ords = append(v1Ords, newV1Order(ord))
+	}
+	writeJSON(w, v1Ords)
+}
+
+// apiV1Order gets an order.
+func (s *WebServer) apiV1Order(w http.ResponseWriter, r *http.Request) {
+	oid, err := v1OrderIDParam(r)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	ord, err := s.core.Order(oid)
+	if err != nil || ord == nil {
+		writeV1Error(w, fmt.Errorf("order %s not found", oid), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, newV1Order(ord))
+}
+
+// apiV1Trade places an order.
+func (s *WebServer) apiV1Trade(w http.ResponseWriter, r *http.Request) {
+	form := new(v1TradeForm)
+	defer form.Pass.Clear()
+	if !readV1Body(w, r, form) {
+		return
+	}
+	var isLimit bool
+	switch form.Type {
+	case order.LimitOrderType.String():
+		isLimit = true
+	case order.MarketOrderType.String():
+	default:
+		writeV1Error(w, fmt.Errorf("invalid order type %q", form.Type), http.StatusBadRequest)
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("password error: %w", err), http.StatusUnauthorized)
+		return
+	}
+	defer zero(pass)
+	ord, err := s.core.Trade(pass, &core.TradeForm{
+		Host:    form.Host,
+		IsLimit: isLimit,
+		Sell:    form.Sell,
+		Base:    form.BaseID,
+		Quote:   form.QuoteID,
+		Qty:     form.Qty,
+		Rate:    form.Rate,
+		TifNow:  form.TifNow,
+		Options: form.Options,
+	})
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error placing order: %w", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSONWithStatus(w, newV1Order(ord), http.StatusCreated)
+}
+
+// apiV1Cancel requests cancellation of an order.
+func (s *WebServer) apiV1Cancel(w http.ResponseWriter, r *http.Request) {
+	oid, err := v1OrderIDParam(r)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	if err := s.core.Cancel(oid); err != nil {
+		writeV1Error(w, fmt.Errorf("error cancelling order %s: %w", oid, err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiV1Search finds the notifications, orders and matches with all of the
+// terms of the q query parameter, newest first. The since, until and n query
+// parameters limit the results.
+func (s *WebServer) apiV1Search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := &core.SearchFilter{Query: q.Get("q")}
+	if filter.Query == "" {
+		writeV1Error(w, errors.New("no search query"), http.StatusBadRequest)
+		return
+	}
+	var err error
+	if filter.Since, filter.Until, err = parseV1TimeRange(q); err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	if nStr := q.Get("n"); nStr != "" {
+		if filter.N, err = strconv.Atoi(nStr); err != nil || filter.N <= 0 {
+			writeV1Error(w, fmt.Errorf("invalid n %q", nStr), http.StatusBadRequest)
+			return
+		}
+	}
+	results, err := s.core.Search(filter)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	v1Results := make([]*v1SearchResult, 0, len(results))
+	for _, res := range results {
+		v1Res := &v1SearchResult{
+			Type:         res.Type,
+			Stamp:        res.Stamp,
+			Notification: res.Notification,
+			MatchID:      res.MatchID.String(),
+		}
+		if res.Order != nil {
+			v1Res.Order = newV1Order(res.Order)
+		}
+		v1Results = append(v1Results, v1Res)
+	}
+	writeJSON(w, v1Results)
+}
+
+// apiV1MarketStats lists the daily trading totals of the markets, sorted by
+// day. The host, base, quote, since and until query parameters limit the
+// results.
+func (s *WebServer) apiV1MarketStats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := &db.MarketStatsFilter{Host: q.Get("host")}
+	var err error
+	if filter.Since, filter.Until, err = parseV1TimeRange(q); err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	baseStr, quoteStr := q.Get("base"), q.Get("quote")
+	if baseStr != "" || quoteStr != "" {
+		base, err := strconv.ParseUint(baseStr, 10, 32)
+		if err != nil {
+			writeV1Error(w, fmt.Errorf("invalid base %q", baseStr), http.StatusBadRequest)
+			return
+		}
+		quote, err := strconv.ParseUint(quoteStr, 10, 32)
+		if err != nil {
+			writeV1Error(w, fmt.Errorf("invalid quote %q", quoteStr), http.StatusBadRequest)
+			return
+		}
+		filter.Market = &db.OrderFilterMarket{Base: uint32(base), Quote: uint32(quote)}
+	}
+	stats, err := s.core.MarketStats(filter)
+	if err != nil {
+		writeV1Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	if stats == nil {
+		stats = []*db.MarketStats{}
+	}
+	writeJSON(w, stats)
+}
+
+// apiV1FiatRateHistory lists the recorded fiat rates of an asset, oldest
+// first. The since and until query parameters limit the results.
+func (s *WebServer) apiV1FiatRateHistory(w http.ResponseWriter, r *http.Request) {
+	assetID, err := v1AssetIDParam(r, "assetID")
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	since, until, err := parseV1TimeRange(r.URL.Query())
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	if until == math.MaxUint64 {
+		until = 0
+	}
+	rates, err := s.core.FiatRateHistory(assetID, since, until)
+	if err != nil {
+		writeV1Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	if rates == nil {
+		rates = []*db.FiatRate{}
+	}
+	writeJSON(w, rates)
+}
+
+// apiV1FiatRateAt gets the fiat rate of an asset at a time, in milliseconds,
+// for valuing past transactions.
+func (s *WebServer) apiV1FiatRateAt(w http.ResponseWriter, r *http.Request) {
+	assetID, err := v1AssetIDParam(r, "assetID")
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	stampStr := chi.URLParam(r, "stamp")
+	stamp, err := strconv.ParseUint(stampStr, 10, 64)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("invalid stamp %q", stampStr), http.StatusBadRequest)
+		return
+	}
+	rate, err := s.core.FiatRateAt(assetID, stamp)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, db.ErrNoFiatRate) {
+			status = http.StatusNotFound
+		}
+		writeV1Error(w, err, status)
+		return
+	}
+	writeJSON(w, rate)
+}
+
+// apiV1Bonds lists the bonding status of the account on every DEX server.
+func (s *WebServer) apiV1Bonds(w http.ResponseWriter, r *http.Request) {
+	bonds := make([]*v1Bonds, 0)
+	for host, xc := range s.core.Exchanges() {
+		if xc.ViewOnly {
+			continue
+		}
+		auth := &xc.Auth
+		b := &v1Bonds{
+			Host:            host,
+			BondAssetID:     auth.BondAssetID,
+			TargetTier:      auth.TargetTier,
+			EffectiveTier:   auth.EffectiveTier,
+			LiveStrength:    auth.LiveStrength,
+			PendingStrength: auth.PendingStrength,
+			WeakStrength:    auth.WeakStrength,
+			MaxBondedAmt:    auth.MaxBondedAmt,
+			PenaltyComps:    auth.PenaltyComps,
+			Score:           auth.Rep.Score,
+			PendingBonds:    make([]*v1PendingBond, 0, len(auth.PendingBonds)),
+		}
+		for _, pb := range auth.PendingBonds {
+			b.PendingBonds = append(b.PendingBonds, &v1PendingBond{
+				CoinID:  pb.CoinID,
+				AssetID: pb.AssetID,
+				Confs:   pb.Confs,
+			})
+		}
+		bonds = append(bonds, b)
+	}
+	sort.Slice(bonds, func(i, j int) bool { return bonds[i].Host < bonds[j].Host })
+	writeJSON(w, bonds)
+}
+
+// apiV1PostBond posts a bond to a DEX server with which an account exists.
+func (s *WebServer) apiV1PostBond(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		Host     string           `json:"host"`
+		AssetID  uint32           `json:"assetID"`
+		Amount   uint64           `json:"amount"`
+		LockTime uint64           `json:"lockTime"`
+		Pass     encode.PassBytes `json:"pass"`
+	}{}
+	defer form.Pass.Clear()
+	if !readV1Body(w, r, form) {
+		return
+	}
+	if s.core.WalletState(form.AssetID) == nil {
+		writeV1Error(w, fmt.Errorf("no %s wallet", unbip(form.AssetID)), http.StatusNotFound)
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("password error: %w", err), http.StatusUnauthorized)
+		return
+	}
+	defer zero(pass)
+	res, err := s.core.PostBond(&core.PostBondForm{
+		Addr:     form.Host,
+		AppPass:  pass,
+		Bond:     form.Amount,
+		Asset:    &form.AssetID,
+		LockTime: form.LockTime,
+	})
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error posting bond: %w", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSONWithStatus(w, res, http.StatusCreated)
+}
+
+// v1MarketMaker responds with an error and returns false if market making is
+// not available.
+func (s *WebServer) v1MarketMaker(w http.ResponseWriter) bool {
+	if s.mm == nil {
+		writeV1Error(w, errors.New("market making is not available"), http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// apiV1Bots lists the configured market making bots.
+func (s *WebServer) apiV1Bots(w http.ResponseWriter, r *http.Request) {
+	if !s.v1MarketMaker(w) {
+		return
+	}
+	status := s.mm.Status()
+	bots := make([]*v1Bot, 0, len(status.Bots))
+	for _, bs := range status.Bots {
+		if bs.Config == nil {
+			continue
+		}
+		bot := &v1Bot{
+			v1BotMarket: v1BotMarket{
+				Host:    bs.Config.Host,
+				BaseID:  bs.Config.BaseID,
+				QuoteID: bs.Config.QuoteID,
+			},
+			Running: bs.Running,
+		}
+		if stats := bs.RunStats; stats != nil {
+			bot.StartTime = stats.StartTime
+			bot.CompletedMatches = stats.CompletedMatches
+			bot.TradedUSD = stats.TradedUSD
+			if stats.ProfitLoss != nil {
+				bot.ProfitUSD = stats.ProfitLoss.Profit
+			}
+		}
+		bots = append(bots, bot)
+	}
+	writeJSON(w, bots)
+}
+
+// parseV1MMEventFilter parses the bot and type query parameters of an MM event
+// stream request. Bots are formatted as host-baseID-quoteID.
+func parseV1MMEventFilter(q url.Values) (*websocket.MMEventFilter, error) {
+	filter := &websocket.MMEventFilter{Types: q["type"]}
+	for _, bot := range q["bot"] {
+		// The host may contain dashes, so the IDs are parsed from the end.
+		parts := strings.Split(bot, "-")
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("invalid bot %q", bot)
+		}
+		baseID, err := strconv.ParseUint(parts[len(parts)-2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base asset ID in bot %q", bot)
+		}
+		quoteID, err := strconv.ParseUint(parts[len(parts)-1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quote asset ID in bot %q", bot)
+		}
+		filter.Bots = append(filter.Bots, &websocket.BotMarket{
+			Host:    strings.Join(parts[:len(parts)-2], "-"),
+			BaseID:  uint32(baseID),
+			QuoteID: uint32(quoteID),
+		})
+	}
+	return filter, nil
+}
+
+// apiV1MMEvents upgrades the connection to a websocket that streams market
+// making notifications, e.g. epoch reports and order and transfer events, for
+// the bots and notification types in the query.
+func (s *WebServer) apiV1MMEvents(w http.ResponseWriter, r *http.Request) {
+	if !s.v1MarketMaker(w) {
+		return
+	}
+	filter, err := parseV1MMEventFilter(r.URL.Query())
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	// The websocket upgrader only accepts same-origin connections.
+	if origin := r.Header.Get("Origin"); origin != "" && s.allowedOrigins[strings.ToLower(origin)] {
+		r.Header.Del("Origin")
+	}
+	s.wsServer.HandleMMEventsConnect(s.ctx, w, r, filter)
+}
+
+// apiV1StartBot starts a configured market making bot.
+func (s *WebServer) apiV1StartBot(w http.ResponseWriter, r *http.Request) {
+	if !s.v1MarketMaker(w) {
+		return
+	}
+	form := &struct {
+		v1BotMarket
+		Alloc         *mm.BotBalanceAllocation `json:"alloc"`
+		AutoRebalance *mm.AutoRebalanceConfig  `json:"autoRebalance"`
+		Pass          encode.PassBytes         `json:"pass"`
+	}{}
+	defer form.Pass.Clear()
+	if !readV1Body(w, r, form) {
+		return
+	}
+	if form.Alloc == nil {
+		writeV1Error(w, errors.New("missing allocation"), http.StatusBadRequest)
+		return
+	}
+	pass, err := s.resolvePass(form.Pass, r)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("password error: %w", err), http.StatusUnauthorized)
+		return
+	}
+	defer zero(pass)
+	cfg := &mm.StartConfig{
+		MarketWithHost: mm.MarketWithHost{
+			Host:    form.Host,
+			BaseID:  form.BaseID,
+			QuoteID: form.QuoteID,
+		},
+		Alloc:         form.Alloc,
+		AutoRebalance: form.AutoRebalance,
+	}
+	if err := s.mm.StartBot(cfg, nil, pass, true); err != nil {
+		writeV1Error(w, fmt.Errorf("error starting bot: %w", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiV1StopBot stops a running market making bot.
+func (s *WebServer) apiV1StopBot(w http.ResponseWriter, r *http.Request) {
+	if !s.v1MarketMaker(w) {
+		return
+	}
+	form := new(v1BotMarket)
+	if !readV1Body(w, r, form) {
+		return
+	}
+	mkt := &mm.MarketWithHost{
+		Host:    form.Host,
+		BaseID:  form.BaseID,
+		QuoteID: form.QuoteID,
+	}
+	if err := s.mm.StopBot(mkt); err != nil {
+		writeV1Error(w, fmt.Errorf("error stopping bot %s: %w", mkt, err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiV1Explorers lists the block explorers available for each asset.
+func (s *WebServer) apiV1Explorers(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.core.BlockExplorers())
+}
+
+// apiV1UpdateExplorers replaces the user's block explorer settings.
+func (s *WebServer) apiV1UpdateExplorers(w http.ResponseWriter, r *http.Request) {
+	settings := new(db.ExplorerSettings)
+	if !readV1Body(w, r, settings) {
+		return
+	}
+	if err := s.core.UpdateBlockExplorers(settings); err != nil {
+		writeV1Error(w, fmt.Errorf("error updating block explorers: %w", err), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, s.core.BlockExplorers())
+}
+
+// apiV1Tor returns the user's tor settings and whether tor is running.
+func (s *WebServer) apiV1Tor(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.core.TorSettings())
+}
+
+// apiV1UpdateTor replaces the user's tor settings, which take effect on
+// restart.
+func (s *WebServer) apiV1UpdateTor(w http.ResponseWriter, r *http.Request) {
+	settings := new(db.TorSettings)
+	if !readV1Body(w, r, settings) {
+		return
+	}
+	if err := s.core.UpdateTorSettings(settings); err != nil {
+		writeV1Error(w, fmt.Errorf("error updating tor settings: %w", err), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, s.core.TorSettings())
+}
+
+// apiV1Proxies returns the user's proxy settings for DEX hosts and outbound
+// services.
+func (s *WebServer) apiV1Proxies(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.core.ProxySettings())
+}
+
+// apiV1UpdateProxies replaces the user's proxy settings, which take effect on
+// restart.
+func (s *WebServer) apiV1UpdateProxies(w http.ResponseWriter, r *http.Request) {
+	settings := new(db.ProxySettings)
+	if !readV1Body(w, r, settings) {
+		return
+	}
+	if err := s.core.UpdateProxySettings(settings); err != nil {
+		writeV1Error(w, fmt.Errorf("error updating proxy settings: %w", err), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, s.core.ProxySettings())
+}
+
+// apiV1Retention returns the user's record retention settings.
+func (s *WebServer) apiV1Retention(w http.ResponseWriter, r *http.Request) {
+	settings, err := s.core.RetentionSettings()
+	if err != nil {
+		writeV1Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, settings)
+}
+
+// apiV1UpdateRetention replaces the user's record retention settings. Records
+// are pruned with the new settings right away.
+func (s *WebServer) apiV1UpdateRetention(w http.ResponseWriter, r *http.Request) {
+	settings := new(db.RetentionSettings)
+	if !readV1Body(w, r, settings) {
+		return
+	}
+	if err := s.core.UpdateRetentionSettings(settings); err != nil {
+		writeV1Error(w, fmt.Errorf("error updating retention settings: %w", err), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, settings)
+}
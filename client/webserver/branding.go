@@ -0,0 +1,196 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/client/core"
+)
+
+const (
+	brandingCSSRoute  = "/branding/style.css"
+	brandingLogoRoute = "/branding/logo"
+	brandingIconRoute = "/branding/icon"
+	// maxBrandingImageSize is the largest logo or icon file accepted.
+	maxBrandingImageSize = 1 << 20
+)
+
+// brandablePages are the pages that can be hidden, and the routes that are
+// removed for each.
+var brandablePages = map[string][]string{
+	"markets": {marketsRoute},
+	"mm":      {marketMakerRoute, mmSettingsRoute, mmArchivesRoute, mmLogsRoute},
+	"orders":  {ordersRoute, "/order/"},
+}
+
+var (
+	cssPropertyRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	cssColorRegexp    = regexp.MustCompile(`^[a-zA-Z0-9#%(),. -]+$`)
+)
+
+// brandingImage is a logo or icon.
+type brandingImage struct {
+	b           []byte
+	contentType string
+}
+
+// branding is the parsed core.Branding served to the frontend.
+type branding struct {
+	appName string
+	css     []byte
+	logo    *brandingImage
+	icon    *brandingImage
+	hidden  map[string]bool
+}
+
+func loadBrandingImage(path string) (*brandingImage, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() > maxBrandingImageSize {
+		return nil, fmt.Errorf("%s is larger than %d bytes", path, maxBrandingImageSize)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	contentType := http.DetectContentType(b)
+	if strings.HasSuffix(strings.ToLower(path), ".svg") {
+		// DetectContentType doesn't recognize SVG.
+		contentType = "image/svg+xml"
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("%s is not an image", path)
+	}
+	return &brandingImage{b: b, contentType: contentType}, nil
+}
+
+// writeCSSProperties writes a rule setting the custom properties.
+func writeCSSProperties(buf *bytes.Buffer, selector string, props map[string]string) error {
+	if len(props) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(props))
+	for name, v := range props {
+		if !cssPropertyRegexp.MatchString(name) {
+			return fmt.Errorf("invalid color property name %q", name)
+		}
+		if !cssColorRegexp.MatchString(v) {
+			return fmt.Errorf("invalid value %q for color %q", v, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(buf, "%s {\n", selector)
+	for _, name := range names {
+		fmt.Fprintf(buf, "  --%s: %s;\n", name, props[name])
+	}
+	buf.WriteString("}\n\n")
+	return nil
+}
+
+// newBranding validates the branding configuration, reads the images, and
+// generates the style sheet.
+func newBranding(cfg *core.Branding) (*branding, error) {
+	b := &branding{
+		appName: strings.TrimSpace(cfg.AppName),
+		hidden:  make(map[string]bool, len(cfg.HiddenPages)),
+	}
+	var err error
+	if cfg.Logo != "" {
+		if b.logo, err = loadBrandingImage(cfg.Logo); err != nil {
+			return nil, fmt.Errorf("error loading logo: %w", err)
+		}
+	}
+	b.icon = b.logo
+	if cfg.Icon != "" {
+		if b.icon, err = loadBrandingImage(cfg.Icon); err != nil {
+			return nil, fmt.Errorf("error loading icon: %w", err)
+		}
+	}
+
+	var css bytes.Buffer
+	if err := writeCSSProperties(&css, "body.branded:not(.dark)", cfg.LightColors); err != nil {
+		return nil, err
+	}
+	if err := writeCSSProperties(&css, "body.branded.dark", cfg.DarkColors); err != nil {
+		return nil, err
+	}
+	if b.logo != nil {
+		fmt.Fprintf(&css, "body.branded img.logo-full,\nbody.branded.dark img.logo-full {\n  content: url(%q);\n  object-fit: contain;\n}\n\n", brandingLogoRoute)
+	}
+	if b.icon != nil {
+		fmt.Fprintf(&css, "body.branded img.logo-square,\nbody.branded.dark img.logo-square {\n  content: url(%q);\n  object-fit: contain;\n}\n\n", brandingIconRoute)
+	}
+	pages := make([]string, 0, len(cfg.HiddenPages))
+	for _, page := range cfg.HiddenPages {
+		if _, ok := brandablePages[page]; !ok {
+			return nil, fmt.Errorf("unknown page %q cannot be hidden", page)
+		}
+		b.hidden[page] = true
+		pages = append(pages, page)
+	}
+	if len(pages) > 0 {
+		sort.Strings(pages)
+		var selectors []string
+		for _, page := range pages {
+			for _, route := range brandablePages[page] {
+				selectors = append(selectors, fmt.Sprintf("body.branded a[href^=%q]", route))
+			}
+		}
+		fmt.Fprintf(&css, "%s {\n  display: none !important;\n}\n", strings.Join(selectors, ",\n"))
+	}
+	b.css = css.Bytes()
+	return b, nil
+}
+
+// title replaces the app name in a page title.
+func (b *branding) title(title string) string {
+	if b == nil || b.appName == "" {
+		return title
+	}
+	return strings.Replace(title, "Bison Wallet", b.appName, 1)
+}
+
+// hidePage responds with 404 Not Found if the page is hidden.
+func (s *WebServer) hidePage(page string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.branding != nil && s.branding.hidden[page] {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handleBrandingCSS serves the branding style sheet.
+func (s *WebServer) handleBrandingCSS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/css")
+	w.Header().Set("Content-Length", strconv.Itoa(len(s.branding.css)))
+	w.Write(s.branding.css)
+}
+
+// serveBrandingImage is a handler for the image, if there is one.
+func serveBrandingImage(img *brandingImage) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if img == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", img.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(img.b)))
+		w.Write(img.b)
+	}
+}
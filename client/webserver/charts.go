@@ -0,0 +1,348 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultDepthBins    = 50
+	maxDepthBins        = 500
+	defaultDepthPercent = 10.0
+	defaultCandlesN     = 500
+	maxCandlesN         = 1000
+	// candlesTimeout is how long to wait for candles from the server.
+	candlesTimeout = 30 * time.Second
+)
+
+// v1DepthBin is a price bin of one side of a depth chart.
+type v1DepthBin struct {
+	// Rate is the edge of the bin farthest from the mid-market rate.
+	Rate uint64 `json:"rate"`
+	// Qty is the quantity of the orders in the bin.
+	Qty uint64 `json:"qty"`
+	// Depth is the cumulative quantity of the bin and all bins closer to the
+	// mid-market rate.
+	Depth uint64 `json:"depth"`
+}
+
+// v1Depth is a binned depth chart.
+type v1Depth struct {
+	MidRate  uint64        `json:"midRate"`
+	BinWidth uint64        `json:"binWidth"`
+	Buys     []*v1DepthBin `json:"buys"`
+	Sells    []*v1DepthBin `json:"sells"`
+}
+
+// v1Candle is a candlestick.
+type v1Candle struct {
+	StartStamp  uint64 `json:"startStamp"`
+	EndStamp    uint64 `json:"endStamp"`
+	MatchVolume uint64 `json:"matchVolume"`
+	QuoteVolume uint64 `json:"quoteVolume"`
+	HighRate    uint64 `json:"highRate"`
+	LowRate     uint64 `json:"lowRate"`
+	StartRate   uint64 `json:"startRate"`
+	EndRate     uint64 `json:"endRate"`
+}
+
+// v1Candles is a series of candles.
+type v1Candles struct {
+	Dur     string      `json:"dur"`
+	DurMs   uint64      `json:"ms"`
+	Candles []*v1Candle `json:"candles"`
+}
+
+// v1MarketParams parses the {host}, {baseID}, and {quoteID} URL parameters.
+func v1MarketParams(r *http.Request) (host string, baseID, quoteID uint32, err error) {
+	if baseID, err = v1AssetIDParam(r, "baseID"); err != nil {
+		return
+	}
+	if quoteID, err = v1AssetIDParam(r, "quoteID"); err != nil {
+		return
+	}
+	return chi.URLParam(r, "host"), baseID, quoteID, nil
+}
+
+// binDepth bins the booked orders of a market into a depth chart with the
+// number of bins on each side, spanning pct percent of the mid-market rate.
+func binDepth(book *core.OrderBook, bins int, pct float64) *v1Depth {
+	bestBuy, bestSell := bestRates(book)
+	var mid uint64
+	switch {
+	case bestBuy > 0 && bestSell > 0:
+		mid = (bestBuy + bestSell) / 2
+	case bestBuy > 0:
+		mid = bestBuy
+	default:
+		mid = bestSell
+	}
+	depth := &v1Depth{
+		MidRate: mid,
+		Buys:    make([]*v1DepthBin, 0, bins),
+		Sells:   make([]*v1DepthBin, 0, bins),
+	}
+	if mid == 0 {
+		return depth
+	}
+	width := uint64(math.Round(float64(mid) * pct / 100 / float64(bins)))
+	if width == 0 {
+		width = 1
+	}
+	depth.BinWidth = width
+
+	side := func(ords []*core.MiniOrder, sell bool) []*v1DepthBin {
+		qtys := make([]uint64, bins)
+		for _, o := range ords {
+			var dist uint64
+			if sell {
+				if o.MsgRate > mid {
+					dist = o.MsgRate - mid
+				}
+			} else if o.MsgRate < mid {
+				dist = mid - o.MsgRate
+			}
+			i := dist / width
+			if dist > 0 && dist%width == 0 {
+				i-- // bins include their far edge
+			}
+			if i >= uint64(bins) {
+				continue
+			}
+			qtys[i] += o.QtyAtomic
+		}
+		out := make([]*v1DepthBin, 0, bins)
+		var cum uint64
+		for i, qty := range qtys {
+			cum += qty
+			edge := uint64(i+1) * width
+			rate := mid + edge
+			if !sell {
+				rate = 0
+				if edge < mid {
+					rate = mid - edge
+				}
+			}
+			out = append(out, &v1DepthBin{Rate: rate, Qty: qty, Depth: cum})
+			if rate == 0 {
+				break
+			}
+		}
+		return out
+	}
+	depth.Buys = side(book.Buys, false)
+	depth.Sells = side(book.Sells, true)
+	return depth
+}
+
+// candleBinSize picks the largest of the available candle durations that
+// divides the requested duration evenly.
+func candleBinSize(dur time.Duration, durStrs []string) (string, error) {
+	var best string
+	var bestDur time.Duration
+	for _, s := range durStrs {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			continue
+		}
+		if dur%d == 0 && d > bestDur {
+			best, bestDur = s, d
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("duration %s is not a multiple of the server's candle durations %v", dur, durStrs)
+	}
+	return best, nil
+}
+
+// aggregateCandles combines the candles, which must be sorted, into candles of
+// the duration. The start and end stamps of aggregated candles are aligned to
+// the duration, except that the end stamp of the last candle is not later than
+// the end stamp of the candles it combines.
+func aggregateCandles(cs []msgjson.Candle, durMs uint64) []*v1Candle {
+	out := make([]*v1Candle, 0, len(cs))
+	var c *v1Candle
+	for i := range cs {
+		in := &cs[i]
+		start := in.StartStamp - in.StartStamp%durMs
+		if c == nil || c.StartStamp != start {
+			c = &v1Candle{
+				StartStamp: start,
+				EndStamp:   start + durMs,
+				StartRate:  in.StartRate,
+				HighRate:   in.HighRate,
+				LowRate:    in.LowRate,
+			}
+			out = append(out, c)
+		}
+		c.MatchVolume += in.MatchVolume
+		c.QuoteVolume += in.QuoteVolume
+		c.EndRate = in.EndRate
+		if in.HighRate > c.HighRate {
+			c.HighRate = in.HighRate
+		}
+		if in.LowRate > 0 && (c.LowRate == 0 || in.LowRate < c.LowRate) {
+			c.LowRate = in.LowRate
+		}
+	}
+	if len(out) > 0 {
+		out[len(out)-1].EndStamp = min(out[len(out)-1].EndStamp, cs[len(cs)-1].EndStamp)
+	}
+	return out
+}
+
+// fetchCandles gets the server's candles of the bin size for the market.
+func (s *WebServer) fetchCandles(r *http.Request, host string, baseID, quoteID uint32, binSize string) ([]msgjson.Candle, error) {
+	_, feed, err := s.core.SyncBook(host, baseID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer feed.Close()
+	if err := feed.Candles(binSize); err != nil {
+		return nil, err
+	}
+	timeout := time.NewTimer(candlesTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case u, ok := <-feed.Next():
+			if !ok {
+				return nil, errors.New("market feed closed")
+			}
+			if u.Action != core.FreshCandlesAction {
+				continue
+			}
+			if p, ok := u.Payload.(*core.CandlesPayload); ok && p.Dur == binSize {
+				return p.Candles, nil
+			}
+		case <-timeout.C:
+			return nil, errors.New("timed out waiting for candles")
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		}
+	}
+}
+
+// apiV1Depth gets a binned depth chart of a market's order book. The query
+// parameter bins is the number of bins on each side, and range is the
+// percentage of the mid-market rate that the bins on each side span.
+func (s *WebServer) apiV1Depth(w http.ResponseWriter, r *http.Request) {
+	host, baseID, quoteID, err := v1MarketParams(r)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	bins := defaultDepthBins
+	if binsStr := q.Get("bins"); binsStr != "" {
+		bins, err = strconv.Atoi(binsStr)
+		if err != nil || bins <= 0 || bins > maxDepthBins {
+			writeV1Error(w, fmt.Errorf("invalid bins %q, must be 1 to %d", binsStr, maxDepthBins), http.StatusBadRequest)
+			return
+		}
+	}
+	pct := defaultDepthPercent
+	if pctStr := q.Get("range"); pctStr != "" {
+		pct, err = strconv.ParseFloat(pctStr, 64)
+		if err != nil || !(pct > 0 && pct <= 100) {
+			writeV1Error(w, fmt.Errorf("invalid range %q, must be a percentage greater than 0 and at most 100", pctStr), http.StatusBadRequest)
+			return
+		}
+	}
+	book, err := s.core.Book(host, baseID, quoteID)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error getting order book: %w", err), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, binDepth(book, bins, pct))
+}
+
+// apiV1Candles gets a market's candles. The query parameter dur is the candle
+// duration, which must be a multiple of one of the server's candle durations.
+// start and end are the range of candle start times, in Unix milliseconds. At
+// most n of the most recent candles in the range are returned. The range is
+// limited by the number of candles the server provides.
+func (s *WebServer) apiV1Candles(w http.ResponseWriter, r *http.Request) {
+	host, baseID, quoteID, err := v1MarketParams(r)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	durStr := q.Get("dur")
+	dur, err := time.ParseDuration(durStr)
+	if err != nil || dur < time.Millisecond {
+		writeV1Error(w, fmt.Errorf("invalid dur %q", durStr), http.StatusBadRequest)
+		return
+	}
+	n := defaultCandlesN
+	if nStr := q.Get("n"); nStr != "" {
+		n, err = strconv.Atoi(nStr)
+		if err != nil || n <= 0 || n > maxCandlesN {
+			writeV1Error(w, fmt.Errorf("invalid n %q, must be 1 to %d", nStr, maxCandlesN), http.StatusBadRequest)
+			return
+		}
+	}
+	var start, end uint64 = 0, math.MaxUint64
+	for _, p := range []struct {
+		name string
+		v    *uint64
+	}{{"start", &start}, {"end", &end}} {
+		if vStr := q.Get(p.name); vStr != "" {
+			if *p.v, err = strconv.ParseUint(vStr, 10, 64); err != nil {
+				writeV1Error(w, fmt.Errorf("invalid %s %q", p.name, vStr), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	if end < start {
+		writeV1Error(w, errors.New("end is before start"), http.StatusBadRequest)
+		return
+	}
+
+	xc, err := s.core.Exchange(host)
+	if err != nil || xc == nil {
+		writeV1Error(w, fmt.Errorf("unknown DEX %q", host), http.StatusNotFound)
+		return
+	}
+	binSize, err := candleBinSize(dur, xc.CandleDurs)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	cs, err := s.fetchCandles(r, host, baseID, quoteID, binSize)
+	if err != nil {
+		writeV1Error(w, fmt.Errorf("error getting candles: %w", err), http.StatusBadGateway)
+		return
+	}
+	sort.Slice(cs, func(i, j int) bool { return cs[i].StartStamp < cs[j].StartStamp })
+
+	durMs := uint64(dur.Milliseconds())
+	agg := aggregateCandles(cs, durMs)
+	candles := make([]*v1Candle, 0, len(agg))
+	for _, c := range agg {
+		if c.StartStamp >= start && c.StartStamp <= end {
+			candles = append(candles, c)
+		}
+	}
+	if len(candles) > n {
+		candles = candles[len(candles)-n:]
+	}
+	writeJSON(w, &v1Candles{
+		Dur:     durStr,
+		DurMs:   durMs,
+		Candles: candles,
+	})
+}
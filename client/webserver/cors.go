@@ -0,0 +1,149 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// csrfHeader is the request header that carries the CSRF token.
+const csrfHeader = "X-CSRF-Token"
+
+// parseAllowedOrigins validates and normalizes the origins allowed to make
+// cross-origin requests, e.g. https://ui.example.com:8080.
+func parseAllowedOrigins(origins []string) (map[string]bool, error) {
+	allowed := make(map[string]bool, len(origins))
+	for _, o := range origins {
+		o = strings.TrimSuffix(strings.TrimSpace(o), "/")
+		if o == "" {
+			continue
+		}
+		if o == "*" {
+			return nil, errors.New("wildcard origin not allowed, list each origin")
+		}
+		u, err := url.Parse(o)
+		if err != nil {
+			return nil, fmt.Errorf("invalid origin %q: %w", o, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" ||
+			u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+			return nil, fmt.Errorf("invalid origin %q, must be scheme://host[:port]", o)
+		}
+		allowed[strings.ToLower(u.Scheme+"://"+u.Host)] = true
+	}
+	return allowed, nil
+}
+
+// isSameOrigin checks whether the Origin header of the request is this
+// server's origin.
+func isSameOrigin(r *http.Request, origin string) bool {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return strings.EqualFold(origin, scheme+"://"+r.Host)
+}
+
+// csrfToken is the CSRF token for the session identified by the auth token.
+func (s *WebServer) csrfToken(authToken string) string {
+	mac := hmac.New(sha256.New, s.csrfKey)
+	mac.Write([]byte(authToken))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validCSRFToken checks the request's CSRF token against its session.
+func (s *WebServer) validCSRFToken(r *http.Request) bool {
+	token := r.Header.Get(csrfHeader)
+	if token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.csrfToken(getAuthToken(r)))) == 1
+}
+
+// cookieSameSite is the SameSite attribute of the session cookies. Cookies
+// must be sent with requests from the allowed cross-site origins if there are
+// any, which requires HTTPS.
+func (s *WebServer) cookieSameSite() http.SameSite {
+	if s.crossSiteCookies {
+		return http.SameSiteNoneMode
+	}
+	return http.SameSiteStrictMode
+}
+
+// corsMiddleware applies the cross-origin policy to API requests. It is only
+// used if there are allowed origins. Requests from the allowed origins get
+// CORS headers, and preflight requests are answered. Unsafe requests from
+// other origins are rejected, and unsafe requests from an allowed origin that
+// are authorized by a session cookie must have the session's CSRF token.
+// Requests with an API key are not subject to CSRF since the key is not sent
+// by the browser automatically.
+func (s *WebServer) corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" || !strings.HasPrefix(r.URL.Path, "/api/") || isSameOrigin(r, origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		allowed := s.allowedOrigins[strings.ToLower(origin)]
+		w.Header().Add("Vary", "Origin")
+		if allowed {
+			h := w.Header()
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Allow-Credentials", "true")
+			h.Set("Access-Control-Expose-Headers", "Retry-After")
+		}
+
+		// Preflight.
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if !allowed {
+				log.Warnf("Rejected preflight request from origin %s, ip %s", origin, r.RemoteAddr)
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			h := w.Header()
+			h.Set("Access-Control-Allow-Methods", "GET, POST, DELETE")
+			h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+csrfHeader)
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			// Safe methods. The browser won't reveal the response to
+			// disallowed origins.
+		default:
+			if !allowed {
+				log.Warnf("Rejected %s %s from origin %s, ip %s", r.Method, r.URL.Path, origin, r.RemoteAddr)
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+			if getBearerToken(r) == "" && s.isAuthedOrGuest(r) && !s.validCSRFToken(r) {
+				log.Warnf("Rejected %s %s from origin %s, ip %s, with a missing or invalid CSRF token",
+					r.Method, r.URL.Path, origin, r.RemoteAddr)
+				http.Error(w, "missing or invalid CSRF token", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiV1CSRFToken gets the CSRF token for the session, which must be sent in
+// the X-CSRF-Token header of unsafe cross-origin requests.
+func (s *WebServer) apiV1CSRFToken(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		Token string `json:"token"`
+	}{
+		Token: s.csrfToken(getAuthToken(r)),
+	})
+}
@@ -0,0 +1,99 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+)
+
+const (
+	// DeepLinkScheme is the URI scheme of deep links into the web UI.
+	DeepLinkScheme = "dex"
+	// deepLinkRoute is the page that opens a deep link given with the uri
+	// query parameter.
+	deepLinkRoute = "/deeplink"
+)
+
+// DeepLinkPath is the web UI path that opens the deep link.
+func DeepLinkPath(link string) string {
+	return deepLinkRoute + "?uri=" + url.QueryEscape(link)
+}
+
+// ParseDeepLink resolves a deep link to the path of a web UI page. The links
+// are
+//
+//	dex://wallets
+//	dex://orders
+//	dex://order/<order ID>
+//	dex://mm
+//	dex://settings
+//	dex://<host>/settings
+//	dex://<host>/market/<base>_<quote>, e.g. dex://dex.decred.org:7232/market/dcr_btc
+func ParseDeepLink(link string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid deep link: %w", err)
+	}
+	if u.Scheme != DeepLinkScheme {
+		return "", fmt.Errorf("not a %s:// link", DeepLinkScheme)
+	}
+	var parts []string
+	if p := strings.Trim(u.Path, "/"); p != "" {
+		parts = strings.Split(p, "/")
+	}
+
+	switch u.Host {
+	case "":
+		return "", fmt.Errorf("no page in deep link %q", link)
+	case "wallets", "orders", "mm", "settings":
+		if len(parts) == 0 {
+			return "/" + u.Host, nil
+		}
+	case "order":
+		if len(parts) == 1 {
+			oid, err := hex.DecodeString(parts[0])
+			if err != nil || len(oid) != order.OrderIDSize {
+				return "", fmt.Errorf("invalid order ID %q", parts[0])
+			}
+			return "/order/" + parts[0], nil
+		}
+	default: // a DEX host
+		switch {
+		case len(parts) == 1 && parts[0] == "settings":
+			return "/dexsettings/" + url.PathEscape(u.Host), nil
+		case len(parts) == 2 && parts[0] == "market":
+			baseSymbol, quoteSymbol, found := strings.Cut(parts[1], "_")
+			baseID, baseFound := dex.BipSymbolID(strings.ToLower(baseSymbol))
+			quoteID, quoteFound := dex.BipSymbolID(strings.ToLower(quoteSymbol))
+			if !found || !baseFound || !quoteFound {
+				return "", fmt.Errorf("unknown market %q", parts[1])
+			}
+			q := make(url.Values)
+			q.Set("host", u.Host)
+			q.Set("baseID", strconv.FormatUint(uint64(baseID), 10))
+			q.Set("quoteID", strconv.FormatUint(uint64(quoteID), 10))
+			return marketsRoute + "?" + q.Encode(), nil
+		}
+	}
+	return "", fmt.Errorf("unknown deep link %q", link)
+}
+
+// handleDeepLink is the handler for the '/deeplink' page request. It
+// redirects to the page for the deep link in the uri query parameter.
+func (s *WebServer) handleDeepLink(w http.ResponseWriter, r *http.Request) {
+	path, err := ParseDeepLink(r.URL.Query().Get("uri"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	http.Redirect(w, r, path, http.StatusSeeOther)
+}
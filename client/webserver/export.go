@@ -0,0 +1,422 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+)
+
+// exportPageSize is the number of records retrieved at a time for CSV exports.
+const exportPageSize = 500
+
+var txTypeNames = map[asset.TransactionType]string{
+	asset.Unknown:             "Unknown",
+	asset.Send:                "Send",
+	asset.Receive:             "Receive",
+	asset.Swap:                "Swap",
+	asset.Redeem:              "Redeem",
+	asset.Refund:              "Refund",
+	asset.Split:               "Split",
+	asset.CreateBond:          "Create Bond",
+	asset.RedeemBond:          "Redeem Bond",
+	asset.ApproveToken:        "Approve Token",
+	asset.Acceleration:        "Acceleration",
+	asset.SelfSend:            "Self Send",
+	asset.RevokeTokenApproval: "Revoke Token Approval",
+	asset.TicketPurchase:      "Ticket Purchase",
+	asset.TicketVote:          "Ticket Vote",
+	asset.TicketRevocation:    "Ticket Revocation",
+	asset.SwapOrSend:          "Swap or Send",
+	asset.Mix:                 "Mix",
+	asset.InitiateBridge:      "Initiate Bridge",
+	asset.CompleteBridge:      "Complete Bridge",
+}
+
+// csvExport writes CSV records to an http.ResponseWriter. The header is
+// written before the first record, so that errors before then can still be
+// reported with an error status.
+type csvExport struct {
+	w        http.ResponseWriter
+	cw       *csv.Writer
+	filename string
+	header   []string
+	started  bool
+}
+
+func newCSVExport(w http.ResponseWriter, r *http.Request, filename string, header []string) *csvExport {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = strings.Contains(r.UserAgent(), "Windows")
+	return &csvExport{w: w, cw: cw, filename: filename, header: header}
+}
+
+func (e *csvExport) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	e.w.Header().Set("Content-Disposition", "attachment; filename="+e.filename)
+	e.w.Header().Set("Content-Type", "text/csv")
+	e.w.WriteHeader(http.StatusOK)
+	return e.cw.Write(e.header)
+}
+
+// write writes a record.
+func (e *csvExport) write(record []string) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.cw.Write(record)
+}
+
+// flush flushes the written records to the response.
+func (e *csvExport) flush() error {
+	e.cw.Flush()
+	if err := e.cw.Error(); err != nil {
+		return err
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// finish writes the header if there were no records, and flushes.
+func (e *csvExport) finish() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+// fail responds with the error if nothing has been written yet. Otherwise the
+// error is just logged, and the truncated CSV will be missing its last line
+// ending.
+func (e *csvExport) fail(err error, status int) {
+	if !e.started {
+		writeV1Error(e.w, err, status)
+		return
+	}
+	log.Errorf("error writing %s: %v", e.filename, err)
+}
+
+// parseV1TimeRange parses the since and until query parameters, which are Unix
+// times in milliseconds.
+func parseV1TimeRange(q url.Values) (since, until uint64, err error) {
+	since, until = 0, math.MaxUint64
+	if s := q.Get("since"); s != "" {
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid since %q", s)
+		}
+	}
+	if s := q.Get("until"); s != "" {
+		if until, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("invalid until %q", s)
+		}
+	}
+	if until < since {
+		return 0, 0, fmt.Errorf("until is before since")
+	}
+	return since, until, nil
+}
+
+// forEachOrder calls f for each order passing the filter, newest first, that
+// was placed in the time range. The filter's N and Offset are used for paging.
+func (s *WebServer) forEachOrder(filter *core.OrderFilter, since, until uint64, f func(*core.Order) error) error {
+	filter.N = exportPageSize
+	filter.Offset = nil
+	for {
+		ords, err := s.core.Orders(filter)
+		if err != nil {
+			return fmt.Errorf("error listing orders: %w", err)
+		}
+		for _, ord := range ords {
+			if ord.Stamp > until {
+				continue
+			}
+			if ord.Stamp < since {
+				return nil
+			}
+			if err := f(ord); err != nil {
+				return err
+			}
+		}
+		if len(ords) < exportPageSize {
+			return nil
+		}
+		filter.Offset = ords[len(ords)-1].ID
+	}
+}
+
+func csvTimestamp(stampMs uint64) string {
+	return time.UnixMilli(int64(stampMs)).Local().Format(time.RFC3339Nano)
+}
+
+// apiV1ExportOrders streams orders as CSV, newest first. The query parameters
+// are those of apiV1Orders except n and offset, and since and until, which are
+// the range of order times in Unix milliseconds.
+func (s *WebServer) apiV1ExportOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := parseV1OrderFilter(q)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	since, until, err := parseV1TimeRange(q)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	exp := newCSVExport(w, r, "orders.csv", []string{
+		"Host",
+		"Order ID",
+		"Base",
+		"Quote",
+		"Base Quantity",
+		"Order Rate",
+		"Actual Rate",
+		"Base Fees",
+		"Base Fees Asset",
+		"Quote Fees",
+		"Quote Fees Asset",
+		"Type",
+		"Side",
+		"Time in Force",
+		"Status",
+		"Target Order ID",
+		"Filled (%)",
+		"Settled (%)",
+		"Time",
+	})
+	err = s.forEachOrder(filter, since, until, func(ord *core.Order) error {
+		ordReader := s.orderReader(ord)
+		var targetOrderID string
+		if len(ord.TargetOrderID) > 0 {
+			targetOrderID = ord.TargetOrderID.String()
+		}
+		return exp.write([]string{
+			ord.Host,                      // Host
+			ord.ID.String(),               // Order ID
+			ord.BaseSymbol,                // Base
+			ord.QuoteSymbol,               // Quote
+			ordReader.BaseQtyString(),     // Base Quantity
+			ordReader.SimpleRateString(),  // Order Rate
+			ordReader.AverageRateString(), // Actual Rate
+			ordReader.BaseAssetFees(),     // Base Fees
+			ordReader.BaseFeeSymbol(),     // Base Fees Asset
+			ordReader.QuoteAssetFees(),    // Quote Fees
+			ordReader.QuoteFeeSymbol(),    // Quote Fees Asset
+			ordReader.Type.String(),       // Type
+			ordReader.SideString(),        // Side
+			ord.TimeInForce.String(),      // Time in Force
+			ordReader.StatusString(),      // Status
+			targetOrderID,                 // Target Order ID
+			ordReader.FilledPercent(),     // Filled
+			ordReader.SettledPercent(),    // Settled
+			csvTimestamp(ord.Stamp),       // Time
+		})
+	})
+	if err == nil {
+		err = exp.finish()
+	}
+	if err != nil {
+		exp.fail(err, http.StatusInternalServerError)
+	}
+}
+
+// apiV1ExportMatches streams the trade matches of orders as CSV, grouped by
+// order, newest order first. Matches of cancel orders are not included. The
+// query parameters are those of apiV1ExportOrders, and filter the orders.
+func (s *WebServer) apiV1ExportMatches(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter, err := parseV1OrderFilter(q)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	since, until, err := parseV1TimeRange(q)
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	exp := newCSVExport(w, r, "matches.csv", []string{
+		"Host",
+		"Base",
+		"Quote",
+		"Match ID",
+		"Order ID",
+		"Quantity",
+		"Rate",
+		"Swap Fee Rate",
+		"Status",
+		"Side",
+		"Order Side",
+		"Swap Coin ID",
+		"Counter Swap Coin ID",
+		"Redeem Coin ID",
+		"Counter Redeem Coin ID",
+		"Refund Coin ID",
+		"Time",
+	})
+	coinID := func(c *core.Coin) string {
+		if c == nil {
+			return ""
+		}
+		return c.StringID
+	}
+	err = s.forEachOrder(filter, since, until, func(ord *core.Order) error {
+		side := "buy"
+		if ord.Sell {
+			side = "sell"
+		}
+		for _, m := range ord.Matches {
+			if m.IsCancel {
+				continue
+			}
+			if err := exp.write([]string{
+				ord.Host,                          // Host
+				ord.BaseSymbol,                    // Base
+				ord.QuoteSymbol,                   // Quote
+				m.MatchID.String(),                // Match ID
+				ord.ID.String(),                   // Order ID
+				strconv.FormatUint(m.Qty, 10),     // Quantity
+				strconv.FormatUint(m.Rate, 10),    // Rate
+				strconv.FormatUint(m.FeeRate, 10), // Swap Fee Rate
+				m.Status.String(),                 // Status
+				m.Side.String(),                   // Side
+				side,                              // Order Side
+				coinID(m.Swap),                    // Swap Coin ID
+				coinID(m.CounterSwap),             // Counter Swap Coin ID
+				coinID(m.Redeem),                  // Redeem Coin ID
+				coinID(m.CounterRedeem),           // Counter Redeem Coin ID
+				coinID(m.Refund),                  // Refund Coin ID
+				csvTimestamp(m.Stamp),             // Time
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = exp.finish()
+	}
+	if err != nil {
+		exp.fail(err, http.StatusInternalServerError)
+	}
+}
+
+// apiV1ExportTransactions streams a wallet's transaction history as CSV,
+// newest first. since and until are the range of transaction times in Unix
+// milliseconds. Unconfirmed transactions are included regardless of the range.
+func (s *WebServer) apiV1ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	assetID, err := v1AssetIDParam(r, "assetID")
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	since, until, err := parseV1TimeRange(r.URL.Query())
+	if err != nil {
+		writeV1Error(w, err, http.StatusBadRequest)
+		return
+	}
+	if s.core.WalletState(assetID) == nil {
+		writeV1Error(w, fmt.Errorf("no %s wallet", dex.BipIDSymbol(assetID)), http.StatusNotFound)
+		return
+	}
+	unitInfo := func(assetID uint32) *dex.UnitInfo {
+		ui, err := asset.UnitInfo(assetID)
+		if err != nil {
+			return nil
+		}
+		return &ui
+	}
+	format := func(ui *dex.UnitInfo, v uint64) string {
+		if ui == nil {
+			return strconv.FormatUint(v, 10)
+		}
+		return ui.ConventionalString(v)
+	}
+	feeUnits := unitInfo(assetID)
+	feeAsset := assetID
+	if token := asset.TokenInfo(assetID); token != nil {
+		feeAsset = token.ParentID
+		feeUnits = unitInfo(token.ParentID)
+	}
+
+	exp := newCSVExport(w, r, dex.BipIDSymbol(assetID)+"-transactions.csv", []string{
+		"Transaction ID",
+		"Type",
+		"Asset",
+		"Amount",
+		"Fees",
+		"Fees Asset",
+		"Recipient",
+		"Block",
+		"Confirmed",
+		"Rejected",
+		"Time",
+	})
+	var refID *string
+	for {
+		txs, err := s.core.TxHistory(assetID, exportPageSize, refID, true)
+		if err != nil {
+			exp.fail(fmt.Errorf("error retrieving transaction history: %w", err), http.StatusInternalServerError)
+			return
+		}
+		for _, tx := range txs {
+			stampMs := tx.Timestamp * 1000
+			if tx.BlockNumber > 0 && (stampMs < since || stampMs > until) {
+				continue
+			}
+			amtAsset := assetID
+			if tx.TokenID != nil {
+				amtAsset = *tx.TokenID
+			}
+			var recipient, stamp string
+			if tx.Recipient != nil {
+				recipient = *tx.Recipient
+			}
+			if tx.Timestamp > 0 {
+				stamp = csvTimestamp(stampMs)
+			}
+			if err := exp.write([]string{
+				tx.ID,                                  // Transaction ID
+				txTypeNames[tx.Type],                   // Type
+				dex.BipIDSymbol(amtAsset),              // Asset
+				format(unitInfo(amtAsset), tx.Amount),  // Amount
+				format(feeUnits, tx.Fees),              // Fees
+				dex.BipIDSymbol(feeAsset),              // Fees Asset
+				recipient,                              // Recipient
+				strconv.FormatUint(tx.BlockNumber, 10), // Block
+				strconv.FormatBool(tx.Confirmed),       // Confirmed
+				strconv.FormatBool(tx.Rejected),        // Rejected
+				stamp,                                  // Time
+			}); err != nil {
+				exp.fail(err, http.StatusInternalServerError)
+				return
+			}
+		}
+		if err := exp.flush(); err != nil {
+			exp.fail(err, http.StatusInternalServerError)
+			return
+		}
+		if len(txs) < exportPageSize {
+			break
+		}
+		refID = &txs[len(txs)-1].ID
+	}
+	if err := exp.finish(); err != nil {
+		exp.fail(err, http.StatusInternalServerError)
+	}
+}
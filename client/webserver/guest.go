@@ -0,0 +1,125 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// errGuest is the error for requests that guest sessions can't make.
+	errGuest = errors.New("not available in read-only guest mode")
+	// errAppPassIsGuestPass is the error for setting the app password to
+	// the guest password.
+	errAppPassIsGuestPass = errors.New("the app password must not be the guest password")
+)
+
+// guestPassHash hashes the guest password. nil is returned if there is no
+// guest password.
+func guestPassHash(pass string) []byte {
+	if pass == "" {
+		return nil
+	}
+	h := sha256.Sum256([]byte(pass))
+	return h[:]
+}
+
+// isGuestPass checks whether the password is the guest password.
+func (s *WebServer) isGuestPass(pass []byte) bool {
+	if s.guestPassHash == nil || len(pass) == 0 {
+		return false
+	}
+	h := sha256.Sum256(pass)
+	return subtle.ConstantTimeCompare(h[:], s.guestPassHash) == 1
+}
+
+// authorizeGuest creates, stores, and returns a new auth token for a
+// read-only guest session.
+func (s *WebServer) authorizeGuest() string {
+	token := newAuthToken()
+	s.authMtx.Lock()
+	s.guestTokens[token] = true
+	s.authMtx.Unlock()
+	return token
+}
+
+// deauthGuest invalidates a guest session's auth token. Unlike deauth, other
+// sessions are not affected.
+func (s *WebServer) deauthGuest(token string) {
+	s.authMtx.Lock()
+	delete(s.guestTokens, token)
+	s.authMtx.Unlock()
+}
+
+// isGuest checks if the incoming request is from a guest session.
+func (s *WebServer) isGuest(r *http.Request) bool {
+	authToken := getAuthToken(r)
+	if authToken == "" {
+		return false
+	}
+	s.authMtx.RLock()
+	defer s.authMtx.RUnlock()
+	return s.guestTokens[authToken]
+}
+
+// guestLogin starts a guest session for a request with the guest password.
+// Core is not logged in, so wallets are only unlocked if the app password has
+// been used already.
+func (s *WebServer) guestLogin(w http.ResponseWriter, r *http.Request) error {
+	if ok, wait := s.loginLimiter.Allow(r.RemoteAddr); !ok {
+		return fmt.Errorf("login error: %w", &loginRateLimitError{wait: wait})
+	}
+	s.loginLimiter.Success(r.RemoteAddr)
+	log.Infof("Guest login from %s", r.RemoteAddr)
+	s.setCookie(authCK, s.authorizeGuest(), w)
+	s.clearCookie(pwKeyCK, w)
+	return nil
+}
+
+// guestLogout ends the request's guest session, if it is one. Other sessions
+// stay logged in.
+func (s *WebServer) guestLogout(w http.ResponseWriter, r *http.Request) bool {
+	if !s.isGuest(r) {
+		return false
+	}
+	s.deauthGuest(getAuthToken(r))
+	s.clearCookie(authCK, w)
+	s.clearCookie(pwKeyCK, w)
+	return true
+}
+
+// isAuthedOrGuest checks if the incoming request is from an authorized
+// user/device or from a read-only guest session.
+func (s *WebServer) isAuthedOrGuest(r *http.Request) bool {
+	return s.isAuthed(r) || s.isGuest(r)
+}
+
+// requireLoginAllowGuest is like requireLogin, but also allows guest sessions.
+// Guests are denied by requireLogin, so this must only be used for pages that
+// are safe for read-only guests.
+func (s *WebServer) requireLoginAllowGuest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAuthedOrGuest(r) {
+			http.Redirect(w, r, loginRoute, http.StatusSeeOther)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rejectUnauthedAllowGuest is like rejectUnauthed, but also allows guest
+// sessions. It must only be used for read-only endpoints.
+func (s *WebServer) rejectUnauthedAllowGuest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.isAuthedOrGuest(r) {
+			http.Error(w, "not authorized - login first", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -59,15 +59,21 @@ type CommonArguments struct {
 	Title          string
 	UseDEXBranding bool
 	Version        string
+	// Branded is true if there is a branding style sheet, and BrandIcon is
+	// true if there is a branding icon.
+	Branded   bool
+	BrandIcon bool
 }
 
 // Create the CommonArguments for the request.
 func (s *WebServer) commonArgs(r *http.Request, title string) *CommonArguments {
 	return &CommonArguments{
 		UserInfo:       extractUserInfo(r),
-		Title:          title,
+		Title:          s.branding.title(title),
 		UseDEXBranding: s.useDEXBranding,
 		Version:        s.appVersion,
+		Branded:        s.branding != nil,
+		BrandIcon:      s.branding != nil && s.branding.icon != nil,
 	}
 }
 
@@ -77,6 +83,22 @@ func (s *WebServer) handleHome(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, walletsRoute, http.StatusSeeOther)
 }
 
+// handleNetworkRedirect redirects requests for /<net>/... to the web server
+// for that network, e.g. /testnet/wallets to the testnet server's /wallets.
+func (s *WebServer) handleNetworkRedirect(w http.ResponseWriter, r *http.Request) {
+	net, path, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	netURL, found := s.networkURLs[net]
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	target := strings.TrimSuffix(netURL, "/") + "/" + path
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
 // handleLogin is the handler for the '/login' page request.
 func (s *WebServer) handleLogin(w http.ResponseWriter, r *http.Request) {
 	cArgs := s.commonArgs(r, "Login | Bison Wallet")
@@ -72,6 +72,7 @@ const (
 	candlesLoadingID                 = "CANDLES_LOADING"
 	depthLoadingID                   = "DEPTH_LOADING"
 	invalidAddrressMsgID             = "INVALID_ADDRESS_MSG"
+	resolvedAddressID                = "RESOLVED_ADDRESS"
 	txFeeSupportedID                 = "TXFEE_UNSUPPORTED"
 	txFeeErrorMsgID                  = "TXFEE_ERR_MSG"
 	activeOrdersLogoutErrorID        = "ACTIVE_ORDERS_LOGOUT_ERR_MSG"
@@ -129,6 +130,8 @@ const (
 	bondReservesID                   = "BOND_RESERVES"
 	bondReservesMsgID                = "BOND_RESERVES_MSG"
 	shieldedID                       = "SHIELDED"
+	pendingDepositID                 = "PENDING_DEPOSIT"
+	pendingDepositMsgID              = "PENDING_DEPOSIT_MSG"
 	shieldedMsgID                    = "SHIELDED_MSG"
 	orderID                          = "ORDER"
 	lockedOrderBalMsgID              = "LOCKED_ORDER_BAL_MSG"
@@ -221,6 +224,15 @@ const (
 	idCausesSelfMatch                = "CAUSES_SELF_MATCH"
 	idCexNotConnected                = "CEX_NOT_CONNECTED"
 	idDeleteBot                      = "DELETE_BOT"
+	idMixFailures                    = "MIX_FAILURES"
+	idProviderHealth                 = "PROVIDER_HEALTH"
+	idProviderBlacklisted            = "PROVIDER_BLACKLISTED"
+	idExplorerAutomatic              = "EXPLORER_AUTOMATIC"
+	idTorRunning                     = "TOR_RUNNING"
+	idTorNotRunning                  = "TOR_NOT_RUNNING"
+	idTorRestartRequired             = "TOR_RESTART_REQUIRED"
+	idScoreWindow                    = "SCORE_WINDOW"
+	idPenaltyThreshold               = "PENALTY_THRESHOLD"
 )
 
 var enUS = map[string]*intl.Translation{
@@ -294,6 +306,7 @@ var enUS = map[string]*intl.Translation{
 	candlesLoadingID:                 {T: "waiting for candlesticks"},
 	depthLoadingID:                   {T: "retrieving depth data"},
 	invalidAddrressMsgID:             {T: "invalid address: {{ address }}"},
+	resolvedAddressID:                {T: "Resolves to {{ address }}"},
 	txFeeSupportedID:                 {T: "fee estimation is not supported for this wallet type"},
 	txFeeErrorMsgID:                  {T: "fee estimation failed: {{ err }}"},
 	activeOrdersLogoutErrorID:        {T: "cannot logout with active orders"},
@@ -352,6 +365,8 @@ var enUS = map[string]*intl.Translation{
 	bondReservesID:                   {T: "Bond Reserves"},
 	bondReservesMsgID:                {T: "Funds reserved to cover the expenses associated with bond maintenance"},
 	shieldedID:                       {T: "Shielded"},
+	pendingDepositID:                 {T: "Incoming"},
+	pendingDepositMsgID:              {T: "Funds sent to this wallet that are not yet mined into a block."},
 	shieldedMsgID:                    {T: "Total funds kept shielded"},
 	orderID:                          {T: "Order"},
 	lockedOrderBalMsgID:              {T: "Funds locked in unmatched orders"},
@@ -441,6 +456,15 @@ var enUS = map[string]*intl.Translation{
 	idCausesSelfMatch:                {T: "This order would cause a self-match"},
 	idCexNotConnected:                {T: "{{ cexName }} not connected"},
 	idDeleteBot:                      {T: "Are you sure you want to delete this bot for the {{ baseTicker }}-{{ quoteTicker }} market on {{ host }}?"},
+	idMixFailures:                    {T: "{{ n }} failed mixing attempts. Last error: {{ error }}"},
+	idProviderHealth:                 {T: "{{ errors }}% of recent requests failed. {{ lag }} blocks behind the best provider."},
+	idProviderBlacklisted:            {T: "Not in use because it is misbehaving."},
+	idExplorerAutomatic:              {T: "Automatic"},
+	idTorRunning:                     {T: "Connected to Tor at {{ addr }}"},
+	idTorNotRunning:                  {T: "Tor is enabled but not running. Connections will fail."},
+	idTorRestartRequired:             {T: "Restart Bison Wallet to apply the new settings."},
+	idScoreWindow:                    {T: "Only your last {{ matches }} swaps and {{ orders }} orders are scored. Older outcomes no longer count toward your score."},
+	idPenaltyThreshold:               {T: "Each {{ threshold }} points of negative score results in a penalty, lowering your tier by one."},
 }
 
 var ptBR = map[string]*intl.Translation{
@@ -45,6 +45,7 @@ import (
 	dexbtc "decred.org/dcrdex/dex/networks/btc"
 	"decred.org/dcrdex/dex/order"
 	ordertest "decred.org/dcrdex/dex/order/test"
+	"decred.org/dcrdex/server/account"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -616,6 +617,9 @@ func (c *TCore) BondsFeeBuffer(assetID uint32) (uint64, error) {
 func (c *TCore) ValidateAddress(address string, assetID uint32) (bool, error) {
 	return len(address) > 10, nil
 }
+func (c *TCore) ResolveAddress(address string, assetID uint32) (string, error) {
+	return address, nil
+}
 func (c *TCore) EstimateSendTxFee(addr string, assetID uint32, value uint64, subtract, maxWithdraw bool) (fee uint64, isValidAddress bool, err error) {
 	return uint64(float64(value) * 0.01), len(addr) > 10, nil
 }
@@ -628,6 +632,22 @@ func (c *TCore) Notifications(n int) (notes, pokes []*db.Notification, _ error)
 
 var orderAssets = []string{"dcr", "btc", "ltc", "doge", "mona", "vtc", "usdc.eth"}
 
+func (c *TCore) Search(*core.SearchFilter) ([]*core.SearchResult, error) {
+	return nil, nil
+}
+
+func (c *TCore) MarketStats(*db.MarketStatsFilter) ([]*db.MarketStats, error) {
+	return nil, nil
+}
+
+func (c *TCore) FiatRateHistory(assetID uint32, since, until uint64) ([]*db.FiatRate, error) {
+	return nil, nil
+}
+
+func (c *TCore) FiatRateAt(assetID uint32, stamp uint64) (*db.FiatRate, error) {
+	return nil, db.ErrNoFiatRate
+}
+
 func (c *TCore) Orders(filter *core.OrderFilter) ([]*core.Order, error) {
 	var spacing uint64 = 60 * 60 * 1000 / 2 // half an hour
 	t := uint64(time.Now().UnixMilli())
@@ -769,6 +789,56 @@ func (c *TCore) PreOrder(*core.TradeForm) (*core.OrderEstimate, error) {
 func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
 	return nil, nil, nil
 }
+func (c *TCore) ReputationBreakdown(host string) (*msgjson.ReputationResult, error) {
+	const matchLimit, orderLimit = 60, 40
+	res := &msgjson.ReputationResult{
+		PenaltyThreshold: 20,
+		MatchLimit:       matchLimit,
+		OrderLimit:       orderLimit,
+		PreimageOrders:   orderLimit,
+		CancelRate: &msgjson.CancelRateScore{
+			Orders:     orderLimit,
+			Cancels:    10,
+			GraceLimit: 2,
+			Threshold:  0.95,
+		},
+	}
+	outcomes := []string{"swap success", "no swap as maker", "no swap as taker", "no redeem as maker", "no redeem as taker"}
+	scores := []int32{1, -4, -11, -7, -1}
+	var score int32
+	stamp := uint64(time.Now().UnixMilli())
+	for i := 0; i < matchLimit; i++ {
+		stamp -= uint64(rand.Intn(3600_000))
+		outcome := 0
+		if rand.Float32() < 0.15 {
+			outcome = 1 + rand.Intn(len(outcomes)-1)
+		}
+		score += scores[outcome]
+		res.Matches = append(res.Matches, &msgjson.ScoredMatch{
+			MatchID: encode.RandomBytes(32),
+			BaseID:  42,
+			QuoteID: 0,
+			Stamp:   stamp,
+			Value:   uint64(rand.Intn(100)) * 1e8,
+			Outcome: outcomes[outcome],
+			Score:   scores[outcome],
+		})
+	}
+	res.PreimageMisses = []*msgjson.ScoredOrder{{
+		OrderID: encode.RandomBytes(32),
+		Stamp:   stamp,
+		Score:   -2,
+	}}
+	score -= 2
+	res.Reputation = &account.Reputation{
+		BondedTier: 1,
+		Score:      score,
+	}
+	if score < 0 {
+		res.Reputation.Penalties = uint16(-score / res.PenaltyThreshold)
+	}
+	return res, nil
+}
 func (c *TCore) AccountImport(pw []byte, account *core.Account, bond []*db.Bond) error {
 	return nil
 }
@@ -919,6 +989,10 @@ func (c *TCore) Order(dex.Bytes) (*core.Order, error) {
 	return makeCoreOrder(), nil
 }
 
+func (c *TCore) Book(dexAddr string, base, quote uint32) (*core.OrderBook, error) {
+	return &core.OrderBook{}, nil
+}
+
 func (c *TCore) SyncBook(dexAddr string, base, quote uint32) (*orderbook.OrderBook, core.BookFeed, error) {
 	mktID, _ := dex.MarketName(base, quote)
 	c.mtx.Lock()
@@ -1420,6 +1494,9 @@ func (c *TCore) CreateWallet(appPW, walletPW []byte, form *core.WalletForm) erro
 	}()
 	return nil
 }
+func (c *TCore) AddCustomToken(appPW []byte, ct *asset.CustomToken) (uint32, error) {
+	return 0, nil
+}
 
 func (c *TCore) createWallet(form *core.WalletForm, synced bool) (done chan struct{}) {
 	done = make(chan struct{})
@@ -1533,7 +1610,7 @@ func (c *TCore) createWallet(form *core.WalletForm, synced bool) (done chan stru
 	return
 }
 
-func (c *TCore) RescanWallet(assetID uint32, force bool) error {
+func (c *TCore) RescanWallet(assetID uint32, force bool, bday uint64) error {
 	return nil
 }
 
@@ -1717,6 +1794,57 @@ func (c *TCore) SupportedAssets() map[uint32]*core.SupportedAsset {
 func (c *TCore) Send(pw []byte, assetID uint32, value uint64, address string, subtract bool) (asset.Coin, error) {
 	return &tCoin{id: []byte{0xde, 0xc7, 0xed}}, nil
 }
+func (c *TCore) BumpFee(assetID uint32, txID string, feeRate uint64) (string, error) {
+	return "", nil
+}
+func (c *TCore) SendWithGasFees(pw []byte, assetID uint32, value uint64, address string, rates *asset.GasFeeRates) (asset.Coin, error) {
+	return &tCoin{id: []byte{0xde, 0xc7, 0xed}}, nil
+}
+func (c *TCore) PreviewGasFees(assetID uint32, txType asset.TransactionType, assetVer uint32, rates *asset.GasFeeRates) (*asset.GasFeePreview, error) {
+	return &asset.GasFeePreview{}, nil
+}
+func (c *TCore) LightningBalance(assetID uint32) (uint64, error) {
+	return 0, nil
+}
+func (c *TCore) LightningInvoice(assetID uint32, amt uint64, memo string) (*asset.LightningInvoice, error) {
+	return &asset.LightningInvoice{Amount: amt}, nil
+}
+func (c *TCore) LightningInvoiceStatus(assetID uint32, paymentHash string) (bool, uint64, error) {
+	return false, 0, nil
+}
+func (c *TCore) PayLightningInvoice(pw []byte, assetID uint32, invoice string, maxFee uint64) (*asset.LightningPayment, error) {
+	return &asset.LightningPayment{}, nil
+}
+func (c *TCore) PegMWEB(pw []byte, assetID uint32, amt uint64, pegIn bool) (string, error) {
+	return "", nil
+}
+func (c *TCore) WalletUTXOs(assetID uint32) ([]*asset.WalletUTXO, error) {
+	return nil, nil
+}
+func (c *TCore) PendingPSBTs(assetID uint32) ([]*asset.PSBTRequest, error) {
+	return nil, nil
+}
+func (c *TCore) SubmitSignedPSBT(assetID uint32, id, signedPSBT string) error {
+	return nil
+}
+func (c *TCore) NonceStatus(assetID uint32) (*asset.NonceStatus, error) {
+	return nil, nil
+}
+func (c *TCore) ReplaceTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error) {
+	return "", nil
+}
+func (c *TCore) CancelTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error) {
+	return "", nil
+}
+func (c *TCore) FillNonceGaps(assetID uint32) ([]string, error) {
+	return nil, nil
+}
+func (c *TCore) PrivateKeyFunds(assetID uint32, privKey string) (uint64, error) {
+	return 0, nil
+}
+func (c *TCore) SweepPrivateKey(pw []byte, assetID uint32, privKey string) (string, uint64, error) {
+	return "", 0, nil
+}
 func (c *TCore) Trade(pw []byte, form *core.TradeForm) (*core.Order, error) {
 	return c.trade(form), nil
 }
@@ -1929,6 +2057,30 @@ func (c *TCore) ToggleRateSourceStatus(src string, disable bool) error {
 func (c *TCore) FiatRateSources() map[string]bool {
 	return c.fiatSources
 }
+func (c *TCore) BlockExplorers() *core.BlockExplorerSettings {
+	return &core.BlockExplorerSettings{}
+}
+func (c *TCore) UpdateBlockExplorers(settings *db.ExplorerSettings) error {
+	return nil
+}
+func (c *TCore) TorSettings() *core.TorStatus {
+	return &core.TorStatus{Settings: new(db.TorSettings)}
+}
+func (c *TCore) UpdateTorSettings(settings *db.TorSettings) error {
+	return nil
+}
+func (c *TCore) ProxySettings() *core.ProxyStatus {
+	return &core.ProxyStatus{Settings: new(db.ProxySettings)}
+}
+func (c *TCore) UpdateProxySettings(settings *db.ProxySettings) error {
+	return nil
+}
+func (c *TCore) RetentionSettings() (*db.RetentionSettings, error) {
+	return new(db.RetentionSettings), nil
+}
+func (c *TCore) UpdateRetentionSettings(settings *db.RetentionSettings) error {
+	return nil
+}
 func (c *TCore) DeleteArchivedRecordsWithBackup(olderThan *time.Time, saveMatchesToFile, saveOrdersToFile bool) (string, int, error) {
 	return "/path/to/records", 10, nil
 }
@@ -1947,6 +2099,15 @@ func (c *TCore) ApproveToken(appPW []byte, assetID uint32, dexAddr string, onCon
 func (c *TCore) UnapproveToken(appPW []byte, assetID uint32, version uint32) (string, error) {
 	return "", nil
 }
+func (c *TCore) TokenAllowances() (map[uint32][]*asset.TokenAllowance, error) {
+	return nil, nil
+}
+func (c *TCore) SetTokenAllowance(appPW []byte, assetID, version uint32, amount uint64) (string, error) {
+	return "", nil
+}
+func (c *TCore) ApproveTokenWithGasFees(appPW []byte, assetID uint32, dexAddr string, rates *asset.GasFeeRates, onConfirm func()) (string, error) {
+	return "", nil
+}
 func (c *TCore) ApproveTokenFee(assetID uint32, version uint32, approval bool) (uint64, error) {
 	return 0, nil
 }
@@ -2434,6 +2595,18 @@ func randomBotConfig(mkt *mm.MarketWithHost) *mm.BotConfig {
 	return cfg
 }
 
+func (m *TMarketMaker) PerformanceSummaries(start, end int64) ([]*mm.PerformanceSummary, error) {
+	return nil, nil
+}
+
+func (m *TMarketMaker) UpdateSummaryInterval(interval string) error {
+	return nil
+}
+
+func (m *TMarketMaker) ReconcileTransfers(startTime int64, mkt *mm.MarketWithHost, maxWithdrawalFeeRatio float64) (*mm.ReconciliationReport, error) {
+	return &mm.ReconciliationReport{Shortfalls: make(map[uint32]uint64)}, nil
+}
+
 func (m *TMarketMaker) RunOverview(startTime int64, mkt *mm.MarketWithHost) (*mm.MarketMakingRunOverview, error) {
 	endTime := time.Unix(startTime, 0).Add(time.Hour * 5).Unix()
 	run := &mm.MarketMakingRunOverview{
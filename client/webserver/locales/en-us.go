@@ -8,6 +8,7 @@ var EnUS = map[string]*intl.Translation{
 	"Wallets":                        {T: "Wallets"}, // unused
 	"Notifications":                  {T: "Notifications"},
 	"Recent Activity":                {T: "Recent Activity"},
+	"Read-only":                      {T: "Read-only"},
 	"Sign Out":                       {T: "Sign Out"},
 	"Order History":                  {T: "Order History"},
 	"load from file":                 {T: "load from file"},
@@ -296,11 +297,22 @@ var EnUS = map[string]*intl.Translation{
 	"export_wallet_msg":           {T: "Below are the seeds needed to restore your wallet in some popular external wallets. DO NOT make transactions with your external wallet while you have active trades running on the DEX."},
 	"clipboard_warning":           {T: "Copy/Pasting a wallet seed is a potential security risk. Do this at your own risk."},
 	"fiat_exchange_rate_sources":  {T: "Fiat Exchange Rate Sources"},
+	"Block Explorers":             {T: "Block Explorers"},
+	"block_explorers_msg":         {T: "The block explorers used for transaction and address links. In custom explorer links, {txid}, {vout}, and {address} are replaced with the transaction ID, output index, and address."},
+	"prefer_onion_explorers":      {T: "Prefer onion explorers"},
+	"Explorer name":               {T: "Explorer name"},
+	"explorer_tx_link":            {T: "Transaction link, e.g. https://example.com/tx/{txid}"},
+	"explorer_address_link":       {T: "Address link (optional)"},
+	"tor_settings_msg":            {T: "Route DEX, fiat rate, and price oracle connections through Tor, with a separate circuit for each host. Bison Wallet runs its own Tor unless the SOCKS address of an existing Tor is provided. Changes take effect on restart."},
+	"tor_enabled":                 {T: "Use Tor"},
+	"tor_socks_addr":              {T: "Existing Tor SOCKS address (optional), e.g. 127.0.0.1:9050"},
+	"tor_path":                    {T: "Tor executable (optional)"},
 	"Synchronizing":               {T: "Synchronizing"},
 	"wallet_wait_synced":          {T: "wallet will be created after sync"},
 	"Create a Wallet":             {T: "Create a Wallet"},
 	"Wallet Type":                 {T: "Wallet Type"},
 	"Peer Count":                  {T: "Peer Count"},
+	"RPC Providers":               {T: "RPC Providers"},
 	"Sync Progress":               {T: "Sync Progress"},
 	"Settings":                    {T: "Settings"},
 	"asset_name Markets":          {T: "<span data-asset-name=1></span> Markets"},
@@ -542,6 +554,10 @@ var EnUS = map[string]*intl.Translation{
 	"Privacy off":                 {T: "Privacy off"},
 	"staking_disabled":            {T: `Ticket purchasing disabled by <span id="extensionModeAppName"></span>.`},
 	"loading privacy status":      {T: "loading privacy status"},
+	"Unmixed":                     {T: "Unmixed"},
+	"Mixed":                       {T: "Mixed"},
+	"Next mix":                    {T: "Next mix"},
+	"Last mix":                    {T: "Last mix"},
 	"mixing_pw_prompt":            {T: "Enter your password to unlock your wallet turn on privacy"},
 	"cspp_addr":                   {T: "CSPP Server Address"},
 	"cspp_how":                    {T: "StakeShuffle creates outputs that cannot be definitively linked to previous on-chain activity. While output amounts are still visible in this form of privacy, the destruction of traceability means your on-chain history can no longer be inferred from publicly available data."},
@@ -689,4 +705,12 @@ var EnUS = map[string]*intl.Translation{
 	"delete_bot":                  {T: "Delete Bot"},
 	"export_logs":                 {T: "Export Logs"},
 	"address has been used":       {T: "address has been used"},
+	"restore_from_seed":           {T: "Restore from a seed phrase"},
+	"Score Breakdown":             {T: "Score Breakdown"},
+	"Swap Outcomes":               {T: "Swap Outcomes"},
+	"Preimage Misses":             {T: "Preimage Misses"},
+	"Cancellation Rate":           {T: "Cancellation Rate"},
+	"Outcome":                     {T: "Outcome"},
+	"no_scored_swaps":             {T: "No swaps have been scored yet."},
+	"import_seed_note":            {T: "Enter the BIP-39 seed phrase of an existing wallet to keep its keys and history. This wallet will not be restored from your Bison Wallet app seed, so keep a separate backup of this seed phrase."},
 }
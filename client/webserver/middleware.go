@@ -20,6 +20,7 @@ type ctxID int
 const (
 	ctxOID ctxID = iota
 	ctxHost
+	ctxAPIKey
 )
 
 // securityMiddleware adds security headers to the server responses.
@@ -42,7 +43,8 @@ func (s *WebServer) securityMiddleware(next http.Handler) http.Handler {
 func (s *WebServer) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := context.WithValue(r.Context(), ctxKeyUserInfo, &userInfo{
-			Authed:           s.isAuthed(r),
+			Authed:           s.isAuthedOrGuest(r),
+			Guest:            s.isGuest(r),
 			PasswordIsCached: s.isPasswordCached(r),
 		})
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -57,7 +59,7 @@ func (s *WebServer) tokenAuthMiddleware(next http.Handler) http.Handler {
 		queries := r.URL.Query()
 		authToken := queries.Get(authCK)
 		if authToken != "" {
-			setCookie(authCK, authToken, w)
+			s.setCookie(authCK, authToken, w)
 		}
 		next.ServeHTTP(w, r)
 	})
@@ -123,11 +125,15 @@ func (s *WebServer) rejectUninited(next http.Handler) http.Handler {
 
 // requireLogin ensures that the user is authenticated (has logged in) before
 // allowing the incoming request to proceed. Redirects to login page if user is
-// not logged in. This check should typically be performed after checking that
-// the app is initialized.
+// not logged in. Guest sessions are rejected. This check should typically be
+// performed after checking that the app is initialized.
 func (s *WebServer) requireLogin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !s.isAuthed(r) {
+			if s.isGuest(r) {
+				http.Error(w, errGuest.Error(), http.StatusForbidden)
+				return
+			}
 			http.Redirect(w, r, loginRoute, http.StatusSeeOther)
 			return
 		}
@@ -140,6 +146,10 @@ func (s *WebServer) requireLogin(next http.Handler) http.Handler {
 func (s *WebServer) rejectUnauthed(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !s.isAuthed(r) {
+			if s.isGuest(r) {
+				http.Error(w, errGuest.Error(), http.StatusForbidden)
+				return
+			}
 			http.Error(w, "not authorized - login first", http.StatusUnauthorized)
 			return
 		}
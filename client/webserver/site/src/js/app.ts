@@ -90,6 +90,7 @@ interface UserResponse extends APIResponse {
   lang: string
   langs: string[]
   inited: boolean
+  guest: boolean
   onionUrl: string
   mmStatus: MarketMakingStatus
 }
@@ -160,6 +161,7 @@ export default class Application {
   mmStatus: MarketMakingStatus
   inited: boolean
   authed: boolean
+  guest: boolean
   user: User
   seedGenTime: number
   commitHash: string
@@ -275,7 +277,16 @@ export default class Application {
     this.attachHeader()
     this.attachActions()
     this.attachCommon(this.header)
-    this.attach({})
+    // A deep link to a market selects it with the query.
+    let params = {}
+    if (handler === 'markets' && url.searchParams.has('host')) {
+      params = {
+        host: url.searchParams.get('host'),
+        baseID: url.searchParams.get('baseID'),
+        quoteID: url.searchParams.get('quoteID')
+      }
+    }
+    this.attach(params)
 
     // If we are authed, populate notes, otherwise get we'll them from the login
     // response.
@@ -308,6 +319,8 @@ export default class Application {
     if (!this.checkResponse(resp)) return
     this.inited = resp.inited
     this.authed = Boolean(resp.user)
+    this.guest = resp.guest
+    document.body.classList.toggle('guest', this.guest)
     this.onionUrl = resp.onionUrl
     this.lang = resp.lang
     this.langs = resp.langs
@@ -902,6 +915,7 @@ export default class Application {
       // would already be hidden/displayed as appropriate.
       return
     }
+    Doc.setVis(authed && this.guest, page.guestBadge)
     if (!authed) {
       page.profileBox.classList.remove('authed')
       Doc.hide(page.noteBell, page.walletsMenuEntry, page.marketsMenuEntry)
@@ -10,7 +10,8 @@ import {
   ConnectionStatus,
   Exchange,
   WalletState,
-  PrepaidBondID
+  PrepaidBondID,
+  ReputationBreakdown
 } from './registry'
 
 interface Animator {
@@ -101,6 +102,8 @@ export default class DexSettingsPage extends BasePage {
     this.reputationMeter = new ReputationMeter(page.repMeter)
     this.reputationMeter.setHost(host)
 
+    Doc.cleanTemplates(page.sbMatchTmpl)
+    Doc.bind(page.scoreBreakdownBtn, 'click', () => this.showScoreBreakdown())
     Doc.bind(page.exportDexBtn, 'click', () => this.exportAccount())
 
     this.accountDisabled = body.dataset.disabled === 'true'
@@ -292,6 +295,52 @@ export default class DexSettingsPage extends BasePage {
     })
   }
 
+  // showScoreBreakdown fetches the itemized score from the server and shows it
+  // in the score breakdown form.
+  async showScoreBreakdown () {
+    const { page, host } = this
+    Doc.hide(page.errMsg)
+    const loaded = app().loading(this.body)
+    const res = await postJSON('/api/reputationbreakdown', { host })
+    loaded()
+    if (!app().checkResponse(res)) {
+      page.errMsg.textContent = res.msg
+      Doc.show(page.errMsg)
+      return
+    }
+    const bd: ReputationBreakdown = res.breakdown
+    const sumScores = (items: { score: number }[]) => items.reduce((sum, item) => sum + item.score, 0)
+    const fmtScore = (score: number) => score > 0 ? `+${score}` : String(score)
+    page.sbScore.textContent = String(bd.reputation.score)
+    page.sbPenalties.textContent = String(bd.reputation.penalties)
+    page.sbMatchScore.textContent = fmtScore(sumScores(bd.matches))
+    page.sbPreimageScore.textContent = `${fmtScore(sumScores(bd.preimageMisses))} (${bd.preimageMisses.length} / ${bd.preimageOrders})`
+    Doc.setVis(bd.cancelRate, page.sbCancelBox)
+    if (bd.cancelRate) {
+      const { cancels, orders, score } = bd.cancelRate
+      page.sbCancelRate.textContent = `(${cancels} / ${orders})`
+      page.sbCancelScore.textContent = fmtScore(score)
+    }
+    page.sbPenaltyThreshold.textContent = intl.prep(intl.ID_PENALTY_THRESHOLD, { threshold: String(bd.penaltyThreshold) })
+    page.sbWindow.textContent = intl.prep(intl.ID_SCORE_WINDOW, { matches: String(bd.matchLimit), orders: String(bd.orderLimit) })
+
+    Doc.empty(page.sbMatches)
+    Doc.setVis(bd.matches.length === 0, page.sbNoMatches)
+    Doc.setVis(bd.matches.length > 0, page.sbMatchTable)
+    const symbol = (assetID: number) => app().assets[assetID]?.symbol.toUpperCase() ?? String(assetID)
+    for (const m of bd.matches) {
+      const tr = page.sbMatchTmpl.cloneNode(true) as PageElement
+      const tmpl = Doc.parseTemplate(tr)
+      tmpl.stamp.textContent = new Date(m.stamp).toLocaleString()
+      tmpl.market.textContent = `${symbol(m.baseID)}-${symbol(m.quoteID)}`
+      tmpl.outcome.textContent = m.outcome
+      tmpl.score.textContent = fmtScore(m.score)
+      tmpl.score.classList.toggle('text-danger', m.score < 0)
+      page.sbMatches.appendChild(tr)
+    }
+    this.showForm(page.scoreBreakdownForm)
+  }
+
   // exportAccount exports and downloads the account info.
   async exportAccount () {
     const { page, host } = this
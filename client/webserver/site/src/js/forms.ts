@@ -32,7 +32,7 @@ import {
   WalletTransaction
 } from './registry'
 import { XYRangeHandler } from './opts'
-import { CoinExplorers } from './coinexplorers'
+import { coinExplorerURL } from './coinexplorers'
 import { MM, setCexElements } from './mmutil'
 
 interface ConfigOptionInput extends HTMLInputElement {
@@ -61,6 +61,7 @@ interface WalletConfig {
   assetID: number
   config: Record<string, string>
   walletType: string
+  mnemonic?: string
 }
 
 interface FormsConfig {
@@ -156,6 +157,11 @@ export class NewWalletForm {
 
     bind(form, page.submitAdd, () => this.submit())
     bind(form, page.oneBttn, () => this.submit())
+    bind(form, page.importSeedToggle, () => {
+      Doc.hide(page.importSeedToggle)
+      Doc.show(page.importSeedInputBox)
+      page.importSeed.focus()
+    })
 
     app().registerNoteFeeder({
       walletstate: (note: WalletStateNote) => { this.reportWalletState(note.wallet) },
@@ -182,13 +188,20 @@ export class NewWalletForm {
   }
 
   async createWallet (assetID: number, walletType: string, parentForm?: WalletConfig) {
-    const createForm = {
+    const createForm: Record<string, any> = {
       assetID: assetID,
       pass: this.page.newWalletPass.value || '',
       config: this.subform.map(assetID),
       walletType: walletType,
       parentForm: parentForm
     }
+    // An imported seed is for the seeded wallet, which is the parent of a
+    // token.
+    const mnemonic = this.page.importSeed.value?.trim()
+    if (mnemonic) {
+      if (parentForm) parentForm.mnemonic = mnemonic
+      else createForm.mnemonic = mnemonic
+    }
 
     const ani = new Wave(this.form, { backgroundColor: true })
     const res = await postJSON('/api/newwallet', createForm)
@@ -220,6 +233,7 @@ export class NewWalletForm {
       return
     }
     newWalletPass.value = ''
+    page.importSeed.value = ''
     if (parentAsset) await this.runParentSync()
     else this.success(this.current.asset.id)
   }
@@ -407,6 +421,12 @@ export class NewWalletForm {
     } else this.subform.update(asset.id, configOpts, false)
     this.setGuideLink(guideLink)
 
+    // A seeded wallet can be restored from its own seed phrase.
+    page.importSeed.value = ''
+    Doc.hide(page.importSeedInputBox)
+    Doc.show(page.importSeedToggle)
+    Doc.setVis(walletDef.seeded, page.importSeedBox)
+
     // A seeded or token wallet is internal to Bison Wallet and as such does
     // not have an external config file to select.
     if (walletDef.seeded || Boolean(this.current.asset.token)) Doc.hide(this.subform.fileSelector)
@@ -2152,10 +2172,8 @@ export class TokenApprovalForm {
       return
     }
     page.txid.innerText = res.txID
-    const assetExplorer = CoinExplorers[tokenAsset.id]
-    if (assetExplorer && assetExplorer[app().user.net]) {
-      page.txid.href = assetExplorer[app().user.net](res.txID)
-    }
+    const url = coinExplorerURL(tokenAsset.id, res.txID)
+    if (url) page.txid.href = url
     Doc.hide(page.submissionElements, page.balanceBox, page.addressBox)
     Doc.show(page.txMsg)
     if (success) success()
@@ -72,6 +72,7 @@ export const ID_FEE_BALANCE = 'FEE_BALANCE'
 export const ID_CANDLES_LOADING = 'CANDLES_LOADING'
 export const ID_DEPTH_LOADING = 'DEPTH_LOADING'
 export const ID_INVALID_ADDRESS_MSG = 'INVALID_ADDRESS_MSG'
+export const ID_RESOLVED_ADDRESS = 'RESOLVED_ADDRESS'
 export const ID_TXFEE_UNSUPPORTED = 'TXFEE_UNSUPPORTED'
 export const ID_TXFEE_ERR_MSG = 'TXFEE_ERR_MSG'
 export const ID_ACTIVE_ORDERS_LOGOUT_ERR_MSG = 'ACTIVE_ORDERS_LOGOUT_ERR_MSG'
@@ -131,6 +132,8 @@ export const ID_BOND_RESERVES_MSG = 'BOND_RESERVES_MSG'
 export const ID_SHIELDED = 'SHIELDED'
 export const ID_TRANSPARENT = 'TRANSPARENT'
 export const ID_SHIELDED_MSG = 'SHIELDED_MSG'
+export const ID_PENDING_DEPOSIT = 'PENDING_DEPOSIT'
+export const ID_PENDING_DEPOSIT_MSG = 'PENDING_DEPOSIT_MSG'
 export const ID_ORDER = 'ORDER'
 export const ID_LOCKED_ORDER_BAL_MSG = 'LOCKED_ORDER_BAL_MSG'
 export const ID_CREATING_WALLETS = 'CREATING_WALLETS'
@@ -221,6 +224,15 @@ export const ID_CEX_BALANCES = 'CEX_BALANCES'
 export const ID_CAUSES_SELF_MATCH = 'CAUSES_SELF_MATCH'
 export const ID_CEX_NOT_CONNECTED = 'CEX_NOT_CONNECTED'
 export const ID_DELETE_BOT = 'DELETE_BOT'
+export const ID_MIX_FAILURES = 'MIX_FAILURES'
+export const ID_PROVIDER_HEALTH = 'PROVIDER_HEALTH'
+export const ID_PROVIDER_BLACKLISTED = 'PROVIDER_BLACKLISTED'
+export const ID_EXPLORER_AUTOMATIC = 'EXPLORER_AUTOMATIC'
+export const ID_TOR_RUNNING = 'TOR_RUNNING'
+export const ID_TOR_NOT_RUNNING = 'TOR_NOT_RUNNING'
+export const ID_TOR_RESTART_REQUIRED = 'TOR_RESTART_REQUIRED'
+export const ID_SCORE_WINDOW = 'SCORE_WINDOW'
+export const ID_PENALTY_THRESHOLD = 'PENALTY_THRESHOLD'
 
 let locale: Locale
 
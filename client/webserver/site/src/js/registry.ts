@@ -37,6 +37,41 @@ export interface Reputation {
   score: number
 }
 
+export interface ScoredMatch {
+  matchID: string
+  baseID: number
+  quoteID: number
+  stamp: number
+  value: number
+  outcome: string
+  score: number
+}
+
+export interface ScoredOrder {
+  orderID: string
+  stamp: number
+  score: number
+}
+
+export interface CancelRateScore {
+  orders: number
+  cancels: number
+  graceLimit: number
+  threshold: number
+  score: number
+}
+
+export interface ReputationBreakdown {
+  reputation: Reputation
+  penaltyThreshold: number
+  matchLimit: number
+  orderLimit: number
+  matches: ScoredMatch[]
+  preimageOrders: number
+  preimageMisses: ScoredOrder[]
+  cancelRate?: CancelRateScore
+}
+
 export interface ExchangeAuth {
   rep: Reputation
   bondAssetID: number
@@ -101,6 +136,24 @@ export interface Market {
   atomToConv: number
   inflight: InFlightOrder[]
   minimumRate: number
+  scheduledChange?: MarketChange
+  maintenance?: Maintenance
+}
+
+export interface MarketChange {
+  finalepoch: number
+  lotsize?: number
+  ratestep?: number
+  epochlen?: number
+  parcelSize?: number
+}
+
+export interface Maintenance {
+  finalepoch: number
+  suspendtime: number
+  resumetime: number
+  persistbook: boolean
+  reason: string
 }
 
 export interface InFlightOrder extends Order {
@@ -260,6 +313,15 @@ export interface WalletState {
   syncStatus: SyncStatus
   approved: Record<number, ApprovalStatus>
   feeState?: FeeState
+  providers?: ProviderHealth[]
+}
+
+export interface ProviderHealth {
+  host: string
+  latencyMS: number
+  errorRate: number
+  headLag: number
+  blacklisted: boolean
 }
 
 export interface WalletInfo {
@@ -368,6 +430,42 @@ export interface User {
   net: number
   extensionModeConfig: ExtensionModeConfig
   actions: ActionRequiredNote[]
+  explorers: Record<number, BlockExplorer>
+}
+
+export interface BlockExplorer {
+  name: string
+  txURL: string
+  outputURL?: string
+  addressURL?: string
+  onion?: boolean
+}
+
+export interface AssetExplorers {
+  assetID: number
+  symbol: string
+  builtin: BlockExplorer[] | null
+  custom: BlockExplorer[] | null
+  selected: string
+  active: BlockExplorer | null
+}
+
+export interface BlockExplorerSettings {
+  preferOnion: boolean
+  assets: AssetExplorers[]
+}
+
+export interface TorSettings {
+  enabled: boolean
+  socksAddr?: string
+  torPath?: string
+}
+
+export interface TorStatus {
+  settings: TorSettings
+  running: boolean
+  socksAddr?: string
+  restartRequired: boolean
 }
 
 export interface CoreNote {
@@ -760,6 +858,7 @@ export interface OrderPlacement {
 export interface AutoRebalanceConfig {
   minBaseTransfer: number
   minQuoteTransfer: number
+  lightning?: boolean
 }
 
 export interface BasicMarketMakingConfig {
@@ -1212,6 +1311,23 @@ export interface TicketStats {
   queued: number
 }
 
+export interface MixDenomination {
+  value: number
+  count: number
+}
+
+export interface FundsMixingStats {
+  enabled: boolean
+  unmixedBalanceThreshold: number
+  mixedFunds: number
+  tradingFunds: number
+  unmixedFunds: number
+  queuedDenominations: MixDenomination[] | null
+  lastMix: number
+  mixFailures: number
+  lastMixError?: string
+}
+
 export interface TicketStakingStatus {
   ticketPrice: number
   votingSubsidy: number
@@ -1278,6 +1394,7 @@ export interface Application {
   showPopups: boolean
   commitHash: string
   authed: boolean
+  guest: boolean
   onionUrl: string
   start (): Promise<void>
   reconnected (): void
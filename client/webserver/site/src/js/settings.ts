@@ -1,7 +1,7 @@
 import Doc from './doc'
 import BasePage from './basepage'
 import State from './state'
-import { postJSON } from './http'
+import { getJSON, postJSON } from './http'
 import * as forms from './forms'
 import * as intl from './locales'
 import { setCoinHref } from './coinexplorers'
@@ -14,9 +14,13 @@ import {
 } from './notifications'
 import {
   app,
+  AssetExplorers,
+  BlockExplorer,
+  BlockExplorerSettings,
   Exchange,
   PageElement,
-  PrepaidBondID
+  PrepaidBondID,
+  TorStatus
 } from './registry'
 
 const animationLength = 300
@@ -27,6 +31,7 @@ export default class SettingsPage extends BasePage {
   page: Record<string, PageElement>
   forms: PageElement[]
   fiatRateSources: PageElement[]
+  explorers: BlockExplorerSettings
   regAssetForm: forms.FeeAssetSelectionForm
   confirmRegisterForm: forms.ConfirmRegistrationForm
   newWalletForm: forms.NewWalletForm
@@ -81,6 +86,31 @@ export default class SettingsPage extends BasePage {
       })
     })
 
+    Doc.cleanTemplates(page.customExplorerTmpl)
+    Doc.bind(page.explorerAsset, 'change', () => this.showAssetExplorers())
+    Doc.bind(page.explorerSelect, 'change', () => {
+      const ae = this.assetExplorers()
+      if (ae) this.saveExplorers(ae, page.explorerSelect.value || '', ae.custom ?? [])
+    })
+    Doc.bind(page.preferOnionExplorers, 'change', () => this.saveExplorers())
+    Doc.bind(page.addExplorer, 'click', async () => {
+      const ae = this.assetExplorers()
+      if (!ae) return
+      const explorer: BlockExplorer = {
+        name: page.explorerName.value || '',
+        txURL: page.explorerTxURL.value || '',
+        addressURL: page.explorerAddressURL.value || ''
+      }
+      if (!await this.saveExplorers(ae, ae.selected, [...(ae.custom ?? []), explorer])) return
+      page.explorerName.value = ''
+      page.explorerTxURL.value = ''
+      page.explorerAddressURL.value = ''
+    })
+    if (!Doc.isHidden(page.blockExplorers)) this.loadExplorers()
+
+    Doc.bind(page.saveTor, 'click', () => this.saveTor())
+    if (!Doc.isHidden(page.torSettings)) this.loadTor()
+
     // Asset selection
     this.regAssetForm = new forms.FeeAssetSelectionForm(page.regAssetForm, async (assetID: number, tier: number) => {
       if (assetID === PrepaidBondID) {
@@ -265,6 +295,117 @@ export default class SettingsPage extends BasePage {
    * slideSwap animates the replacement of the currently shown form with the
    * newForm and sets this.currentForm.
    */
+  /* loadExplorers fetches and displays the block explorer settings. */
+  async loadExplorers () {
+    const { page } = this
+    const res = await getJSON('/api/blockexplorers')
+    if (!app().checkResponse(res)) return
+    this.explorers = res.explorers
+    page.preferOnionExplorers.checked = this.explorers.preferOnion
+    const assetID = page.explorerAsset.value
+    Doc.empty(page.explorerAsset)
+    for (const { assetID, symbol } of this.explorers.assets) {
+      const opt = document.createElement('option')
+      opt.value = String(assetID)
+      opt.textContent = symbol.toUpperCase()
+      page.explorerAsset.appendChild(opt)
+    }
+    if (assetID) page.explorerAsset.value = assetID
+    this.showAssetExplorers()
+  }
+
+  /* assetExplorers is the block explorer settings for the selected asset. */
+  assetExplorers (): AssetExplorers | undefined {
+    const assetID = parseInt(this.page.explorerAsset.value || '')
+    return this.explorers?.assets.find(ae => ae.assetID === assetID)
+  }
+
+  /* showAssetExplorers displays the selected asset's block explorers. */
+  showAssetExplorers () {
+    const { page } = this
+    Doc.empty(page.explorerSelect, page.customExplorers)
+    const ae = this.assetExplorers()
+    if (!ae) return
+    const addOption = (value: string, text: string) => {
+      const opt = document.createElement('option')
+      opt.value = value
+      opt.textContent = text
+      page.explorerSelect.appendChild(opt)
+    }
+    addOption('', intl.prep(intl.ID_EXPLORER_AUTOMATIC))
+    for (const { name } of [...(ae.builtin ?? []), ...(ae.custom ?? [])]) addOption(name, name)
+    page.explorerSelect.value = ae.selected
+    for (const explorer of ae.custom ?? []) {
+      const row = page.customExplorerTmpl.cloneNode(true) as PageElement
+      const tmpl = Doc.parseTemplate(row)
+      tmpl.name.textContent = explorer.name
+      Doc.bind(tmpl.remove, 'click', () => {
+        const selected = ae.selected === explorer.name ? '' : ae.selected
+        this.saveExplorers(ae, selected, (ae.custom ?? []).filter(e => e !== explorer))
+      })
+      page.customExplorers.appendChild(row)
+    }
+  }
+
+  /*
+   * saveExplorers saves the block explorer settings, with the asset's
+   * selection and custom explorers changed, if an asset is provided.
+   */
+  async saveExplorers (changed?: AssetExplorers, selected?: string, custom?: BlockExplorer[]): Promise<boolean> {
+    const { page } = this
+    Doc.hide(page.explorerErr)
+    const assets: Record<number, { selected: string, custom: BlockExplorer[] }> = {}
+    for (const ae of this.explorers.assets) {
+      if (ae === changed) assets[ae.assetID] = { selected: selected ?? '', custom: custom ?? [] }
+      else assets[ae.assetID] = { selected: ae.selected, custom: ae.custom ?? [] }
+    }
+    const res = await postJSON('/api/updateblockexplorers', {
+      preferOnion: page.preferOnionExplorers.checked,
+      assets
+    })
+    if (!app().checkResponse(res)) {
+      page.explorerErr.textContent = res.msg
+      Doc.show(page.explorerErr)
+      await this.loadExplorers()
+      return false
+    }
+    await this.loadExplorers()
+    // Update the explorers used for links.
+    await app().fetchUser()
+    return true
+  }
+
+  /* loadTor fetches and displays the tor settings and status. */
+  async loadTor () {
+    const { page } = this
+    const res = await getJSON('/api/torsettings')
+    if (!app().checkResponse(res)) return
+    const tor: TorStatus = res.tor
+    page.torEnabled.checked = tor.settings.enabled
+    page.torSOCKSAddr.value = tor.settings.socksAddr ?? ''
+    page.torPath.value = tor.settings.torPath ?? ''
+    if (tor.restartRequired) page.torStatus.textContent = intl.prep(intl.ID_TOR_RESTART_REQUIRED)
+    else if (tor.running) page.torStatus.textContent = intl.prep(intl.ID_TOR_RUNNING, { addr: tor.socksAddr ?? '' })
+    else if (tor.settings.enabled) page.torStatus.textContent = intl.prep(intl.ID_TOR_NOT_RUNNING)
+    else page.torStatus.textContent = ''
+  }
+
+  /* saveTor saves the tor settings, which take effect on restart. */
+  async saveTor () {
+    const { page } = this
+    Doc.hide(page.torErr)
+    const res = await postJSON('/api/updatetorsettings', {
+      enabled: page.torEnabled.checked,
+      socksAddr: page.torSOCKSAddr.value?.trim() ?? '',
+      torPath: page.torPath.value?.trim() ?? ''
+    })
+    if (!app().checkResponse(res)) {
+      page.torErr.textContent = res.msg
+      Doc.show(page.torErr)
+    }
+    await this.loadTor()
+  }
+
   slideSwap (newForm: PageElement) {
     forms.slideSwap(this.currentForm, newForm)
     this.currentForm = newForm
@@ -38,12 +38,14 @@ import {
   VotingServiceProvider,
   Ticket,
   TicketStats,
+  FundsMixingStats,
   TxHistoryResult,
   TransactionNote,
   WalletTransaction,
-  FeeState
+  FeeState,
+  ProviderHealth
 } from './registry'
-import { CoinExplorers } from './coinexplorers'
+import { coinExplorerURL } from './coinexplorers'
 
 interface DecredTicketTipUpdate {
   ticketPrice: number
@@ -53,6 +55,7 @@ interface DecredTicketTipUpdate {
 
 interface TicketPurchaseUpdate extends BaseWalletNote {
   err?: string
+  warn?: string
   remaining:number
   tickets?: Ticket[]
   stats?: TicketStats
@@ -218,8 +221,6 @@ interface reconfigSettings {
   elevateProviders?: boolean
 }
 
-let net = 0
-
 export default class WalletsPage extends BasePage {
   body: HTMLElement
   data?: WalletsPageData
@@ -249,14 +250,17 @@ export default class WalletsPage extends BasePage {
   mixerToggle: AniToggle
   stampers: PageElement[]
   secondTicker: number
+  // resolvedAddrs maps names entered as send addresses, e.g. ENS names, to
+  // the addresses they resolve to.
+  resolvedAddrs: Record<string, string>
 
   constructor (body: HTMLElement, data?: WalletsPageData) {
     super()
     this.body = body
     this.data = data
     const page = this.page = Doc.idDescendants(body)
+    this.resolvedAddrs = {}
     this.stampers = []
-    net = app().user.net
 
     const setStamp = () => {
       for (const span of this.stampers) {
@@ -283,7 +287,7 @@ export default class WalletsPage extends BasePage {
     Doc.cleanTemplates(
       page.iconSelectTmpl, page.balanceDetailRow, page.recentOrderTmpl, page.vspRowTmpl,
       page.ticketHistoryRowTmpl, page.votingChoiceTmpl, page.votingAgendaTmpl, page.tspendTmpl,
-      page.tkeyTmpl, page.txHistoryRowTmpl, page.txHistoryDateRowTmpl
+      page.tkeyTmpl, page.txHistoryRowTmpl, page.txHistoryDateRowTmpl, page.providerHealthTmpl
     )
 
     Doc.bind(page.createWallet, 'click', () => this.showNewWallet(this.selectedAssetID))
@@ -389,11 +393,18 @@ export default class WalletsPage extends BasePage {
     Doc.bind(page.sendAddr, 'input', async () => {
       const asset = app().assets[this.selectedAssetID]
       page.sendAddr.classList.remove('border-danger', 'border-success')
+      Doc.hide(page.sendAddrResolved)
       const addr = page.sendAddr.value || ''
       if (!asset || addr === '') return
       const valid = await this.validateSendAddress(addr, asset.id)
+      if (addr !== page.sendAddr.value) return // changed during request
       if (valid) page.sendAddr.classList.add('border-success')
       else page.sendAddr.classList.add('border-danger')
+      const resolved = this.resolvedAddrs[addr]
+      if (valid && resolved) {
+        page.sendAddrResolved.textContent = intl.prep(intl.ID_RESOLVED_ADDRESS, { address: resolved })
+        Doc.show(page.sendAddrResolved)
+      }
     })
 
     // A link on the wallet reconfiguration form to show/hide the password field.
@@ -512,6 +523,9 @@ export default class WalletsPage extends BasePage {
     page.vTotalSend.textContent = Doc.formatFullPrecision(value, ui)
     Doc.showFiatValue(page.vTotalSendFiat, value, xcRate, ui)
     page.vSendAddr.textContent = page.sendAddr.value || ''
+    const resolved = this.resolvedAddrs[addr]
+    page.vSendAddrResolved.textContent = resolved ? intl.prep(intl.ID_RESOLVED_ADDRESS, { address: resolved }) : ''
+    Doc.setVis(Boolean(resolved), page.vSendAddrResolved)
     const bal = wallet.balance.available - value
     page.balanceAfterSend.textContent = Doc.formatFullPrecision(bal, ui)
     Doc.showFiatValue(page.balanceAfterSendFiat, bal, xcRate, ui)
@@ -552,6 +566,8 @@ export default class WalletsPage extends BasePage {
    */
   async validateSendAddress (addr: string, assetID: number): Promise<boolean> {
     const resp = await postJSON('/api/validateaddress', { addr: addr, assetID: assetID })
+    if (resp.resolved) this.resolvedAddrs[addr] = resp.resolved
+    else delete this.resolvedAddrs[addr]
     return app().checkResponse(resp)
   }
 
@@ -610,10 +626,8 @@ export default class WalletsPage extends BasePage {
       return
     }
 
-    const assetExplorer = CoinExplorers[this.selectedAssetID]
-    if (assetExplorer && assetExplorer[net]) {
-      page.unapproveTokenTxID.href = assetExplorer[net](res.txID)
-    }
+    const url = coinExplorerURL(this.selectedAssetID, res.txID)
+    if (url) page.unapproveTokenTxID.href = url
     page.unapproveTokenTxID.textContent = res.txID
     Doc.hide(page.unapproveTokenSubmissionElements, page.unapproveTokenErr)
     Doc.show(page.unapproveTokenTxMsg)
@@ -1002,7 +1016,7 @@ export default class WalletsPage extends BasePage {
       page.statusOff, page.unlockBttnBox, page.lockBttnBox, page.connectBttnBox,
       page.peerCountBox, page.syncProgressBox, page.statusDisabled, page.tokenInfoBox,
       page.needsProviderBox, page.feeStateBox, page.txSyncBox, page.txProgress,
-      page.txFindingAddrs
+      page.txFindingAddrs, page.providerHealthBox
     )
     this.checkNeedsProvider(assetID)
     if (token) {
@@ -1030,10 +1044,11 @@ export default class WalletsPage extends BasePage {
   updateSyncAndPeers (assetID: number) {
     const { page, selectedAssetID } = this
     if (assetID !== selectedAssetID) return
-    const { peerCount, syncProgress, syncStatus, encrypted, open, running } = app().walletMap[assetID]
+    const { peerCount, syncProgress, syncStatus, encrypted, open, running, providers } = app().walletMap[assetID]
     if (!running) return
     Doc.show(page.sendReceive, page.peerCountBox, page.syncProgressBox)
     page.peerCount.textContent = String(peerCount)
+    this.updateProviderHealth(providers)
     page.syncProgress.textContent = `${(syncProgress * 100).toFixed(1)}%`
     if (open) {
       Doc.show(page.statusReady)
@@ -1051,6 +1066,35 @@ export default class WalletsPage extends BasePage {
     }
   }
 
+  /*
+   * updateProviderHealth lists the wallet's RPC providers in order of
+   * preference, with their response times. Providers that are misbehaving
+   * are marked, and the details are in the tooltip.
+   */
+  updateProviderHealth (providers: ProviderHealth[] | undefined) {
+    const page = this.page
+    Doc.empty(page.providerHealthList)
+    if (!providers || providers.length === 0) {
+      Doc.hide(page.providerHealthBox)
+      return
+    }
+    Doc.show(page.providerHealthBox)
+    for (const p of providers) {
+      const div = page.providerHealthTmpl.cloneNode(true) as PageElement
+      const tmpl = Doc.parseTemplate(div)
+      tmpl.host.textContent = p.host
+      tmpl.latency.textContent = `${p.latencyMS} ms`
+      const healthy = !p.blacklisted && p.errorRate < 0.1
+      tmpl.icon.classList.add(healthy ? 'ico-check' : 'ico-cross', healthy ? 'text-success' : 'text-warning')
+      div.dataset.tooltip = intl.prep(intl.ID_PROVIDER_HEALTH, {
+        errors: (p.errorRate * 100).toFixed(0),
+        lag: String(p.headLag)
+      }) + (p.blacklisted ? ` ${intl.prep(intl.ID_PROVIDER_BLACKLISTED)}` : '')
+      page.providerHealthList.appendChild(div)
+    }
+    app().bindTooltips(page.providerHealthList)
+  }
+
   updateFeeState (feeState: FeeState) {
     const { page, selectedAssetID: assetID } = this
     Doc.hide(page.feeStateBox)
@@ -1235,12 +1279,13 @@ export default class WalletsPage extends BasePage {
   processTicketPurchaseUpdate (walletNote: CustomWalletNote) {
     const { stakeStatus, selectedAssetID, page } = this
     const { assetID } = walletNote
-    const { err, remaining, tickets, stats } = walletNote.payload as TicketPurchaseUpdate
+    const { err, warn, remaining, tickets, stats } = walletNote.payload as TicketPurchaseUpdate
     if (assetID !== selectedAssetID) return
-    if (err) {
+    if (err || warn) {
+      // A warning is sent when the ticket buyer falls back to a backup VSP.
       Doc.show(page.purchaseTicketsErrBox)
-      page.purchaseTicketsErr.textContent = err
-      return
+      page.purchaseTicketsErr.textContent = err || warn || ''
+      if (err) return
     }
     if (tickets) stakeStatus.tickets = tickets.concat(stakeStatus.tickets)
     if (stats) this.updateTicketStats(stats, app().assets[assetID].unitInfo)
@@ -1290,7 +1335,7 @@ export default class WalletsPage extends BasePage {
   displayTicketPage (pageNumber: number, pageOfTickets: Ticket[]) {
     const { page, selectedAssetID: assetID } = this
     const ui = app().unitInfo(assetID)
-    const coinLink = CoinExplorers[assetID][app().user.net]
+    const coinLink = (cid: string) => coinExplorerURL(assetID, cid) || ''
     Doc.empty(page.ticketHistoryRows)
     page.ticketHistoryPage.textContent = String(pageNumber)
     for (const { tx, status } of pageOfTickets) {
@@ -1363,7 +1408,7 @@ export default class WalletsPage extends BasePage {
     const { page, stakeStatus, selectedAssetID: assetID } = this
     const ui = app().unitInfo(assetID)
     Doc.hide(page.votingFormErr)
-    const coinLink = CoinExplorers[assetID][app().user.net]
+    const coinLink = (cid: string) => coinExplorerURL(assetID, cid) || ''
     const upperCase = (s: string) => s.charAt(0).toUpperCase() + s.slice(1)
 
     const setVotes = async (req: any) => {
@@ -1473,10 +1518,29 @@ export default class WalletsPage extends BasePage {
       return
     }
 
-    this.mixing = res.stats.enabled as boolean
+    const stats = res.stats as FundsMixingStats
+    this.mixing = stats.enabled
     if (this.mixing) Doc.show(page.mixerOn)
     else Doc.show(page.mixerOff)
     this.mixerToggle.setState(this.mixing)
+    this.showMixingDetails(stats, app().assets[assetID].unitInfo)
+  }
+
+  showMixingDetails (stats: FundsMixingStats, ui: UnitInfo) {
+    const page = this.page
+    Doc.setVis(stats.enabled, page.mixingDetails)
+    if (!stats.enabled) return
+    page.mixUnmixed.textContent = Doc.formatCoinValue(stats.unmixedFunds, ui)
+    page.mixMixed.textContent = Doc.formatCoinValue(stats.mixedFunds, ui)
+    const queued = stats.queuedDenominations ?? []
+    Doc.setVis(queued.length > 0, page.mixQueuedBox)
+    page.mixQueued.textContent = queued.map((d) => `${d.count} x ${Doc.formatCoinValue(d.value, ui)}`).join(', ')
+    Doc.setVis(stats.lastMix > 0, page.mixLastBox)
+    if (stats.lastMix > 0) page.mixLast.textContent = new Date(stats.lastMix * 1000).toLocaleString()
+    Doc.setVis(stats.mixFailures > 0, page.mixFailures)
+    if (stats.mixFailures > 0) {
+      page.mixFailures.textContent = intl.prep(intl.ID_MIX_FAILURES, { n: String(stats.mixFailures), error: stats.lastMixError ?? '' })
+    }
   }
 
   async updateMixerState (on: boolean) {
@@ -1493,6 +1557,7 @@ export default class WalletsPage extends BasePage {
     Doc.setVis(on, page.mixerOn)
     Doc.setVis(!on, page.mixerOff)
     this.mixerToggle.setState(on)
+    if (!on) Doc.hide(page.mixingDetails)
   }
 
   updateDisplayedAssetBalance (): void {
@@ -1555,10 +1620,17 @@ export default class WalletsPage extends BasePage {
     if (bal.bondlocked > 0) addSubBalance(intl.prep(intl.ID_BONDED), bal.bondlocked, intl.prep(intl.ID_LOCKED_BOND_BAL_MSG))
     if (bal.bondReserves > 0) addSubBalance(intl.prep(intl.ID_BOND_RESERVES), bal.bondReserves, intl.prep(intl.ID_BOND_RESERVES_MSG))
     if (bal?.other?.Staked !== undefined) addSubBalance('Staked', bal.other.Staked.amt)
+    if (bal?.other?.MWEB !== undefined && bal.other.MWEB.amt > 0) addSubBalance('MWEB', bal.other.MWEB.amt)
+    if (bal?.other?.Spark !== undefined && bal.other.Spark.amt > 0) addSubBalance('Spark', bal.other.Spark.amt)
+    if (bal?.other?.CashTokens !== undefined && bal.other.CashTokens.amt > 0) addSubBalance('CashTokens', bal.other.CashTokens.amt)
+    if (bal?.other?.Sapling !== undefined) addSubBalance('Sapling', bal.other.Sapling.amt)
     setRowClasses()
 
     if (bal.immature) addPrimaryBalance(intl.prep(intl.ID_IMMATURE_TITLE), bal.immature, intl.prep(intl.ID_IMMATURE_BAL_MSG))
     if (bal?.other?.Unmixed !== undefined) addSubBalance('Unmixed', bal.other.Unmixed.amt)
+    if (bal?.other?.PendingDeposit !== undefined && bal.other.PendingDeposit.amt > 0) {
+      addSubBalance(intl.prep(intl.ID_PENDING_DEPOSIT), bal.other.PendingDeposit.amt, intl.prep(intl.ID_PENDING_DEPOSIT_MSG))
+    }
     setRowClasses()
 
     // TODO: handle reserves deficit with a notification.
@@ -1737,10 +1809,8 @@ export default class WalletsPage extends BasePage {
     const page = this.page
 
     // Block explorer
-    const assetExplorer = CoinExplorers[this.selectedAssetID]
-    if (assetExplorer && assetExplorer[net]) {
-      page.txViewBlockExplorer.href = assetExplorer[net](tx.id)
-    }
+    const url = coinExplorerURL(this.selectedAssetID, tx.id)
+    if (url) page.txViewBlockExplorer.href = url
 
     // Tx type
     let txType = txTypeString(tx.type)
@@ -2167,9 +2237,10 @@ export default class WalletsPage extends BasePage {
       Doc.show(page.toggleSubtract)
     }
 
-    Doc.hide(page.sendErr, page.maxSendDisplay, page.sendTokenMsgBox)
+    Doc.hide(page.sendErr, page.maxSendDisplay, page.sendTokenMsgBox, page.sendAddrResolved)
     page.sendAddr.classList.remove('border-danger', 'border-success')
     page.sendAddr.value = ''
+    this.resolvedAddrs = {}
     page.sendAmt.value = ''
     const xcRate = app().fiatRatesMap[assetID]
     Doc.showFiatValue(page.sendValue, 0, xcRate, ui)
@@ -2281,9 +2352,11 @@ export default class WalletsPage extends BasePage {
       Doc.showFormError(page.vSendErr, intl.prep(intl.ID_NO_PASS_ERROR_MSG))
       return
     }
+    const addr = page.sendAddr.value || ''
     const open = {
       assetID: assetID,
-      address: page.sendAddr.value,
+      // Send to the resolved address that the user confirmed.
+      address: this.resolvedAddrs[addr] ?? addr,
       subtract: subtract,
       value: Math.round(parseFloatDefault(page.sendAmt.value) * conversionFactor),
       pw: pw
@@ -0,0 +1,131 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package webserver
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex/order"
+)
+
+// tickerBookConcurrency is the most order books that are retrieved at once
+// for the tickers.
+const tickerBookConcurrency = 4
+
+// v1Ticker is a summary of a market.
+type v1Ticker struct {
+	Host        string `json:"host"`
+	Name        string `json:"name"`
+	BaseID      uint32 `json:"baseID"`
+	BaseSymbol  string `json:"baseSymbol"`
+	QuoteID     uint32 `json:"quoteID"`
+	QuoteSymbol string `json:"quoteSymbol"`
+	// The spot price and 24 hour stats are reported by the server, and are
+	// zero if it hasn't reported them.
+	Stamp    uint64  `json:"stamp,omitempty"`
+	Rate     uint64  `json:"rate"`
+	Change24 float64 `json:"change24"`
+	Vol24    uint64  `json:"vol24"`
+	High24   uint64  `json:"high24"`
+	Low24    uint64  `json:"low24"`
+	// BestBuy and BestSell are the best booked rates, and are zero if the side
+	// of the book is empty or the book could not be retrieved.
+	BestBuy  uint64 `json:"bestBuy"`
+	BestSell uint64 `json:"bestSell"`
+	// Spread is BestSell - BestBuy, and is zero if either is zero.
+	Spread uint64 `json:"spread"`
+	// MyOrders is the number of the user's orders that are in the epoch
+	// queue or booked, including orders being submitted.
+	MyOrders int `json:"myOrders"`
+}
+
+// bestRates finds the best buy and sell rates in the order book.
+func bestRates(book *core.OrderBook) (bestBuy, bestSell uint64) {
+	for _, o := range book.Buys {
+		if o.MsgRate > bestBuy {
+			bestBuy = o.MsgRate
+		}
+	}
+	for _, o := range book.Sells {
+		if bestSell == 0 || o.MsgRate < bestSell {
+			bestSell = o.MsgRate
+		}
+	}
+	return
+}
+
+func newV1Ticker(host string, mkt *core.Market) *v1Ticker {
+	t := &v1Ticker{
+		Host:        host,
+		Name:        mkt.Name,
+		BaseID:      mkt.BaseID,
+		BaseSymbol:  mkt.BaseSymbol,
+		QuoteID:     mkt.QuoteID,
+		QuoteSymbol: mkt.QuoteSymbol,
+		MyOrders:    len(mkt.InFlightOrders),
+	}
+	if spot := mkt.SpotPrice; spot != nil {
+		t.Stamp = spot.Stamp
+		t.Rate = spot.Rate
+		t.Change24 = spot.Change24
+		t.Vol24 = spot.Vol24
+		t.High24 = spot.High24
+		t.Low24 = spot.Low24
+	}
+	for _, ord := range mkt.Orders {
+		if ord.Status == order.OrderStatusEpoch || ord.Status == order.OrderStatusBooked {
+			t.MyOrders++
+		}
+	}
+	return t
+}
+
+// apiV1Tickers gets a summary of every market of every connected DEX server.
+// Getting the spread may require subscribing to the order book of markets
+// that aren't synced already, so the query parameter spread=false can be
+// used to skip it.
+func (s *WebServer) apiV1Tickers(w http.ResponseWriter, r *http.Request) {
+	tickers := make([]*v1Ticker, 0)
+	for host, xc := range s.core.Exchanges() {
+		if xc.ConnectionStatus != comms.Connected {
+			continue
+		}
+		for _, mkt := range xc.Markets {
+			tickers = append(tickers, newV1Ticker(host, mkt))
+		}
+	}
+	sort.Slice(tickers, func(i, j int) bool {
+		if tickers[i].Host != tickers[j].Host {
+			return tickers[i].Host < tickers[j].Host
+		}
+		return tickers[i].Name < tickers[j].Name
+	})
+
+	if r.URL.Query().Get("spread") != "false" {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, tickerBookConcurrency)
+		for _, t := range tickers {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(t *v1Ticker) {
+				defer func() { <-sem; wg.Done() }()
+				book, err := s.core.Book(t.Host, t.BaseID, t.QuoteID)
+				if err != nil {
+					log.Debugf("Error getting %s book for ticker: %v", t.Name, err)
+					return
+				}
+				t.BestBuy, t.BestSell = bestRates(book)
+				if t.BestBuy > 0 && t.BestSell > t.BestBuy {
+					t.Spread = t.BestSell - t.BestBuy
+				}
+			}(t)
+		}
+		wg.Wait()
+	}
+	writeJSON(w, tickers)
+}
@@ -4,6 +4,7 @@
 package webserver
 
 import (
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex"
@@ -82,6 +83,9 @@ type walletConfig struct {
 	// These are only used if the Decred wallet does not already exist. In that
 	// case, these parameters will be used to create the wallet.
 	Config map[string]string `json:"config"`
+	// Mnemonic is an optional BIP-39 seed phrase to create a native wallet
+	// from, instead of the app seed.
+	Mnemonic string `json:"mnemonic,omitempty"`
 }
 
 // newWalletForm is information necessary to create a new wallet.
@@ -120,6 +124,49 @@ type sendForm struct {
 	Address  string           `json:"address"`
 	Subtract bool             `json:"subtract"`
 	Pass     encode.PassBytes `json:"pw"`
+	// GasFees are optional EIP-1559 fee rates for the send.
+	GasFees *asset.GasFeeRates `json:"gasFees,omitempty"`
+}
+
+// addCustomTokenForm is information necessary to register a user-defined
+// token and create its wallet.
+type addCustomTokenForm struct {
+	AppPW encode.PassBytes   `json:"appPW"`
+	Token *asset.CustomToken `json:"token"`
+}
+
+// replaceTxForm is the form for the 'replacetx' and 'canceltx' API requests.
+// GasFees is optional.
+type replaceTxForm struct {
+	AssetID uint32             `json:"assetID"`
+	TxID    string             `json:"txID"`
+	GasFees *asset.GasFeeRates `json:"gasFees"`
+}
+
+type pegMWEBForm struct {
+	AssetID uint32           `json:"assetID"`
+	Value   uint64           `json:"value"`
+	PegIn   bool             `json:"pegIn"`
+	Pass    encode.PassBytes `json:"pw"`
+}
+
+type lightningInvoiceForm struct {
+	AssetID uint32 `json:"assetID"`
+	Value   uint64 `json:"value"`
+	Memo    string `json:"memo"`
+}
+
+type payLightningInvoiceForm struct {
+	AssetID uint32           `json:"assetID"`
+	Invoice string           `json:"invoice"`
+	MaxFee  uint64           `json:"maxFee"`
+	Pass    encode.PassBytes `json:"pw"`
+}
+
+type sweepKeyForm struct {
+	AssetID uint32           `json:"assetID"`
+	PrivKey string           `json:"privKey"`
+	Pass    encode.PassBytes `json:"pw"`
 }
 
 type accountExportForm struct {
@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/authlimit"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
@@ -39,9 +40,11 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/encrypt"
+	"decred.org/dcrdex/dex/msgjson"
 	"github.com/decred/dcrd/certgen"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/text/language"
 )
 
@@ -96,6 +99,7 @@ type clientCore interface {
 	Network() dex.Network
 	Exchanges() map[string]*core.Exchange
 	Exchange(host string) (*core.Exchange, error)
+	Book(dex string, base, quote uint32) (*core.OrderBook, error)
 	PostBond(form *core.PostBondForm) (*core.PostBondResult, error)
 	RedeemPrepaidBond(appPW []byte, code []byte, host string, certI any) (tier uint64, err error)
 	UpdateBondOptions(form *core.BondOptionsForm) error
@@ -103,8 +107,9 @@ type clientCore interface {
 	InitializeClient(pw []byte, seed *string) (string, error)
 	AssetBalance(assetID uint32) (*core.WalletBalance, error)
 	CreateWallet(appPW, walletPW []byte, form *core.WalletForm) error
+	AddCustomToken(appPW []byte, ct *asset.CustomToken) (uint32, error)
 	OpenWallet(assetID uint32, pw []byte) error
-	RescanWallet(assetID uint32, force bool) error
+	RescanWallet(assetID uint32, force bool, bday uint64) error
 	RecoverWallet(assetID uint32, appPW []byte, force bool) error
 	CloseWallet(assetID uint32) error
 	ConnectWallet(assetID uint32) error
@@ -124,6 +129,23 @@ type clientCore interface {
 	DiscoverAccount(dexAddr string, pass []byte, certI any) (*core.Exchange, bool, error)
 	SupportedAssets() map[uint32]*core.SupportedAsset
 	Send(pw []byte, assetID uint32, value uint64, address string, subtract bool) (asset.Coin, error)
+	BumpFee(assetID uint32, txID string, feeRate uint64) (string, error)
+	SendWithGasFees(pw []byte, assetID uint32, value uint64, address string, rates *asset.GasFeeRates) (asset.Coin, error)
+	PreviewGasFees(assetID uint32, txType asset.TransactionType, assetVer uint32, rates *asset.GasFeeRates) (*asset.GasFeePreview, error)
+	PegMWEB(pw []byte, assetID uint32, amt uint64, pegIn bool) (string, error)
+	LightningBalance(assetID uint32) (uint64, error)
+	LightningInvoice(assetID uint32, amt uint64, memo string) (*asset.LightningInvoice, error)
+	LightningInvoiceStatus(assetID uint32, paymentHash string) (settled bool, amtPaid uint64, err error)
+	PayLightningInvoice(pw []byte, assetID uint32, invoice string, maxFee uint64) (*asset.LightningPayment, error)
+	WalletUTXOs(assetID uint32) ([]*asset.WalletUTXO, error)
+	PendingPSBTs(assetID uint32) ([]*asset.PSBTRequest, error)
+	SubmitSignedPSBT(assetID uint32, id, signedPSBT string) error
+	NonceStatus(assetID uint32) (*asset.NonceStatus, error)
+	ReplaceTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error)
+	CancelTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error)
+	FillNonceGaps(assetID uint32) ([]string, error)
+	PrivateKeyFunds(assetID uint32, privKey string) (uint64, error)
+	SweepPrivateKey(pw []byte, assetID uint32, privKey string) (string, uint64, error)
 	Trade(pw []byte, form *core.TradeForm) (*core.Order, error)
 	TradeAsync(pw []byte, form *core.TradeForm) (*core.InFlightOrder, error)
 	Cancel(oid dex.Bytes) error
@@ -131,9 +153,14 @@ type clientCore interface {
 	Logout() error
 	Orders(*core.OrderFilter) ([]*core.Order, error)
 	Order(oid dex.Bytes) (*core.Order, error)
+	Search(*core.SearchFilter) ([]*core.SearchResult, error)
+	MarketStats(*db.MarketStatsFilter) ([]*db.MarketStats, error)
+	FiatRateHistory(assetID uint32, since, until uint64) ([]*db.FiatRate, error)
+	FiatRateAt(assetID uint32, stamp uint64) (*db.FiatRate, error)
 	MaxBuy(host string, base, quote uint32, rate uint64) (*core.MaxOrderEstimate, error)
 	MaxSell(host string, base, quote uint32) (*core.MaxOrderEstimate, error)
 	AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error)
+	ReputationBreakdown(host string) (*msgjson.ReputationResult, error)
 	AccountImport(pw []byte, account *core.Account, bonds []*db.Bond) error
 	ToggleAccountStatus(pw []byte, host string, disable bool) error
 	IsInitialized() bool
@@ -149,15 +176,27 @@ type clientCore interface {
 	WalletRestorationInfo(pw []byte, assetID uint32) ([]*asset.WalletRestoration, error)
 	ToggleRateSourceStatus(src string, disable bool) error
 	FiatRateSources() map[string]bool
+	BlockExplorers() *core.BlockExplorerSettings
+	UpdateBlockExplorers(settings *db.ExplorerSettings) error
+	TorSettings() *core.TorStatus
+	UpdateTorSettings(settings *db.TorSettings) error
+	ProxySettings() *core.ProxyStatus
+	UpdateProxySettings(settings *db.ProxySettings) error
+	RetentionSettings() (*db.RetentionSettings, error)
+	UpdateRetentionSettings(settings *db.RetentionSettings) error
 	EstimateSendTxFee(address string, assetID uint32, value uint64, subtract, maxWithdraw bool) (fee uint64, isValidAddress bool, err error)
 	ValidateAddress(address string, assetID uint32) (bool, error)
+	ResolveAddress(address string, assetID uint32) (string, error)
 	DeleteArchivedRecordsWithBackup(olderThan *time.Time, saveMatchesToFile, saveOrdersToFile bool) (string, int, error)
 	WalletPeers(assetID uint32) ([]*asset.WalletPeer, error)
 	AddWalletPeer(assetID uint32, addr string) error
 	RemoveWalletPeer(assetID uint32, addr string) error
 	Notifications(n int) (notes, pokes []*db.Notification, _ error)
 	ApproveToken(appPW []byte, assetID uint32, dexAddr string, onConrim func()) (string, error)
+	ApproveTokenWithGasFees(appPW []byte, assetID uint32, dexAddr string, rates *asset.GasFeeRates, onConfirm func()) (string, error)
 	UnapproveToken(appPW []byte, assetID uint32, version uint32) (string, error)
+	TokenAllowances() (map[uint32][]*asset.TokenAllowance, error)
+	SetTokenAllowance(appPW []byte, assetID, version uint32, amount uint64) (string, error)
 	ApproveTokenFee(assetID uint32, version uint32, approval bool) (uint64, error)
 	StakeStatus(assetID uint32) (*asset.TicketStakingStatus, error)
 	SetVSP(assetID uint32, addr string) error
@@ -188,6 +227,9 @@ type MMCore interface {
 	RunOverview(startTime int64, mkt *mm.MarketWithHost) (*mm.MarketMakingRunOverview, error)
 	RunLogs(startTime int64, mkt *mm.MarketWithHost, n uint64, refID *uint64, filter *mm.RunLogFilters) (events, updatedEvents []*mm.MarketMakingEvent, overview *mm.MarketMakingRunOverview, err error)
 	CEXBook(host string, baseID, quoteID uint32) (buys, sells []*core.MiniOrder, _ error)
+	PerformanceSummaries(start, end int64) ([]*mm.PerformanceSummary, error)
+	UpdateSummaryInterval(interval string) error
+	ReconcileTransfers(startTime int64, mkt *mm.MarketWithHost, maxWithdrawalFeeRatio float64) (*mm.ReconciliationReport, error)
 }
 
 // genCertPair generates a key/cert pair to the paths provided.
@@ -236,6 +278,19 @@ type Config struct {
 	AppVersion    string // user app version for UI
 	CertFile      string
 	KeyFile       string
+	// ACME, if set, is used to obtain and renew certificates from an ACME
+	// certificate authority instead of using CertFile and KeyFile.
+	ACME *ACMEConfig
+	// AllowedOrigins are the origins of alternative frontends that may make
+	// cross-origin API requests, e.g. https://ui.example.com. Unsafe requests
+	// from these origins that are authorized by a session cookie must have
+	// the session's CSRF token.
+	AllowedOrigins []string
+	// GuestPassword, if set, is a password for read-only guest sessions.
+	// Guests can view balances, orders, and market making stats, but can't
+	// use any endpoint that changes anything. It must not be the app
+	// password.
+	GuestPassword string
 	// NoEmbed indicates to serve files from the system disk rather than the
 	// embedded files. Since this is a developer setting, this also implies
 	// reloading of templates on each request. Note that only embedded files
@@ -246,6 +301,11 @@ type Config struct {
 	HttpProf        bool
 	Tor             bool
 	MainLogFilePath string
+
+	// NetworkURLs are the URLs of the web servers for other networks run by
+	// the same process, keyed by network name, e.g. testnet. Requests for
+	// /testnet and /testnet/... are redirected to that network's server.
+	NetworkURLs map[string]string
 }
 
 type valStamp struct {
@@ -271,10 +331,27 @@ type WebServer struct {
 	html     atomic.Value // *templates
 	tor      bool
 	onion    string
+	acme     *autocert.Manager
+	acmeHTTP string // address for HTTP-01 challenges
 
 	authMtx         sync.RWMutex
 	authTokens      map[string]bool
+	guestTokens     map[string]bool
 	cachedPasswords map[string]*cachedPassword // cached passwords keyed by auth token
+	// guestPassHash is the hash of the guest password, or nil if guest
+	// sessions are disabled.
+	guestPassHash []byte
+
+	apiKeysMtx sync.RWMutex
+	apiKeys    map[string]*apiKey // keyed by token hash
+
+	loginLimiter *authlimit.Limiter
+
+	allowedOrigins map[string]bool
+	// crossSiteCookies is true if session cookies are sent with cross-site
+	// requests from the allowed origins.
+	crossSiteCookies bool
+	csrfKey          []byte
 
 	bondBufMtx sync.Mutex
 	bondBuf    map[uint32]valStamp
@@ -282,7 +359,9 @@ type WebServer struct {
 	appVersion string
 
 	useDEXBranding  bool
+	branding        *branding
 	mainLogFilePath string
+	networkURLs     map[string]string
 }
 
 // New is the constructor for a new WebServer. CustomSiteDir in the Config can
@@ -349,7 +428,19 @@ func New(cfg *Config) (*WebServer, error) {
 		WriteTimeout: 2 * time.Minute,                      // request to response time, must be long enough for slow handlers
 	}
 
-	if cfg.CertFile != "" || cfg.KeyFile != "" {
+	var acmeMgr *autocert.Manager
+	var acmeHTTP string
+	if cfg.ACME != nil {
+		var err error
+		if acmeMgr, err = newACMEManager(cfg.ACME, cfg.DataDir); err != nil {
+			return nil, err
+		}
+		log.Infof("Using HTTPS with certificates from ACME provider for %s. "+
+			"Certificates are stored in %s.", strings.Join(cfg.ACME.Domains, ", "),
+			filepath.Join(cfg.DataDir, acmeCacheDir))
+		httpServer.TLSConfig = acmeTLSConfig(acmeMgr)
+		acmeHTTP = cfg.ACME.HTTPAddr
+	} else if cfg.CertFile != "" || cfg.KeyFile != "" {
 		// Find or create the key pair.
 		keyExists := dex.FileExists(cfg.KeyFile)
 		certExists := dex.FileExists(cfg.CertFile)
@@ -381,6 +472,15 @@ func New(cfg *Config) (*WebServer, error) {
 		// httpServer.TLSNextProto = make(map[string]func(*http.Server, *tls.Conn, http.Handler), 0)
 	}
 
+	allowedOrigins, err := parseAllowedOrigins(cfg.AllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+	if len(allowedOrigins) > 0 && httpServer.TLSConfig == nil {
+		log.Warnf("Cross-origin requests are allowed without HTTPS. Session " +
+			"cookies will only be sent from allowed origins on the same site.")
+	}
+
 	lang := cfg.Core.Language()
 
 	langs := make([]string, 0, len(localesMap))
@@ -389,31 +489,52 @@ func New(cfg *Config) (*WebServer, error) {
 	}
 
 	var useDEXBranding bool
+	var brand *branding
 	if xCfg := cfg.Core.ExtensionModeConfig(); xCfg != nil {
 		useDEXBranding = xCfg.UseDEXBranding
+		if xCfg.Branding != nil {
+			if brand, err = newBranding(xCfg.Branding); err != nil {
+				return nil, fmt.Errorf("error loading branding: %w", err)
+			}
+		}
 	}
 
 	// Make the server here so its methods can be registered.
 	s := &WebServer{
-		langs:           langs,
-		core:            cfg.Core,
-		mm:              cfg.MarketMaker,
-		siteDir:         siteDir,
-		mux:             mux,
-		srv:             httpServer,
-		addr:            cfg.Addr,
-		dataDir:         cfg.DataDir,
-		wsServer:        websocket.New(cfg.Core, log.SubLogger("WS")),
-		authTokens:      make(map[string]bool),
-		cachedPasswords: make(map[string]*cachedPassword),
-		tor:             cfg.Tor,
-		bondBuf:         map[uint32]valStamp{},
-		appVersion:      cfg.AppVersion,
-		useDEXBranding:  useDEXBranding,
-		mainLogFilePath: cfg.MainLogFilePath,
+		langs:            langs,
+		core:             cfg.Core,
+		mm:               cfg.MarketMaker,
+		siteDir:          siteDir,
+		mux:              mux,
+		srv:              httpServer,
+		addr:             cfg.Addr,
+		dataDir:          cfg.DataDir,
+		wsServer:         websocket.New(cfg.Core, log.SubLogger("WS")),
+		authTokens:       make(map[string]bool),
+		guestTokens:      make(map[string]bool),
+		guestPassHash:    guestPassHash(cfg.GuestPassword),
+		cachedPasswords:  make(map[string]*cachedPassword),
+		apiKeys:          make(map[string]*apiKey),
+		tor:              cfg.Tor,
+		acme:             acmeMgr,
+		acmeHTTP:         acmeHTTP,
+		loginLimiter:     authlimit.New(log.SubLogger("AUTH")),
+		allowedOrigins:   allowedOrigins,
+		crossSiteCookies: len(allowedOrigins) > 0 && httpServer.TLSConfig != nil,
+		csrfKey:          encode.RandomBytes(32),
+		bondBuf:          map[uint32]valStamp{},
+		appVersion:       cfg.AppVersion,
+		useDEXBranding:   useDEXBranding,
+		branding:         brand,
+		mainLogFilePath:  cfg.MainLogFilePath,
+		networkURLs:      cfg.NetworkURLs,
 	}
 	s.lang.Store(lang)
 
+	if err := s.loadAPIKeys(); err != nil {
+		return nil, fmt.Errorf("error loading API keys: %w", err)
+	}
+
 	if err := s.buildTemplates(lang); err != nil {
 		return nil, fmt.Errorf("error loading localized html templates: %v", err)
 	}
@@ -427,6 +548,9 @@ func New(cfg *Config) (*WebServer, error) {
 	}))
 	mux.Use(s.securityMiddleware)
 	mux.Use(middleware.Recoverer)
+	if len(allowedOrigins) > 0 {
+		mux.Use(s.corsMiddleware)
+	}
 
 	// Compress responses if using tor.
 	if cfg.Tor {
@@ -453,6 +577,12 @@ func New(cfg *Config) (*WebServer, error) {
 
 	// The WebSocket handler is mounted on /ws in Connect.
 
+	// Other networks run by this process have their own servers.
+	for net := range cfg.NetworkURLs {
+		mux.Get("/"+net, s.handleNetworkRedirect)
+		mux.Get("/"+net+"/*", s.handleNetworkRedirect)
+	}
+
 	// Webpages
 	mux.Group(func(web chi.Router) {
 		web.Use(s.tokenAuthMiddleware)
@@ -461,9 +591,11 @@ func New(cfg *Config) (*WebServer, error) {
 		// inject the User object for page template execution.
 		web.Use(s.authMiddleware)
 		web.Get(settingsRoute, s.handleSettings)
+		web.Get(deepLinkRoute, s.handleDeepLink)
 
 		web.Get("/generateqrcode", s.handleGenerateQRCode)
-		web.Get("/generatecompanionappqrcode", s.handleGenerateCompanionAppQRCode)
+		// The companion app QR code has an auth token for a full session.
+		web.With(s.requireLogin).Get("/generatecompanionappqrcode", s.handleGenerateCompanionAppQRCode)
 
 		web.Group(func(notInit chi.Router) {
 			notInit.Use(s.requireNotInit)
@@ -487,23 +619,29 @@ func New(cfg *Config) (*WebServer, error) {
 				// The rest of these handlers require both init and auth.
 				webNoAuth.Group(func(webAuth chi.Router) {
 					webAuth.Use(s.requireLogin)
-					webAuth.Get(homeRoute, s.handleHome)
-					webAuth.Get(walletsRoute, s.handleWallets)
 					webAuth.Get(walletLogRoute, s.handleWalletLogFile)
 				})
+
+				// Read-only pages that guest sessions can view.
+				webNoAuth.Group(func(webGuest chi.Router) {
+					webGuest.Use(s.requireLoginAllowGuest)
+					webGuest.Get(homeRoute, s.handleHome)
+					webGuest.Get(walletsRoute, s.handleWallets)
+				})
 			})
 
-			// Handlers requiring a DEX connection.
+			// Handlers requiring a DEX connection. These pages are
+			// read-only, so guest sessions can view them.
 			webInit.Group(func(webDC chi.Router) {
-				webDC.Use(s.requireDEXConnection, s.requireLogin)
-				webDC.With(orderIDCtx).Get("/order/{oid}", s.handleOrder)
-				webDC.Get(ordersRoute, s.handleOrders)
-				webDC.Get(exportOrderRoute, s.handleExportOrders)
-				webDC.Get(marketsRoute, s.handleMarkets)
-				webDC.Get(mmSettingsRoute, s.handleMMSettings)
-				webDC.Get(mmArchivesRoute, s.handleMMArchives)
-				webDC.Get(mmLogsRoute, s.handleMMLogs)
-				webDC.Get(marketMakerRoute, s.handleMarketMaking)
+				webDC.Use(s.requireDEXConnection, s.requireLoginAllowGuest)
+				webDC.With(s.hidePage("orders"), orderIDCtx).Get("/order/{oid}", s.handleOrder)
+				webDC.With(s.hidePage("orders")).Get(ordersRoute, s.handleOrders)
+				webDC.With(s.hidePage("orders")).Get(exportOrderRoute, s.handleExportOrders)
+				webDC.With(s.hidePage("markets")).Get(marketsRoute, s.handleMarkets)
+				webDC.With(s.hidePage("mm")).Get(mmSettingsRoute, s.handleMMSettings)
+				webDC.With(s.hidePage("mm")).Get(mmArchivesRoute, s.handleMMArchives)
+				webDC.With(s.hidePage("mm")).Get(mmLogsRoute, s.handleMMLogs)
+				webDC.With(s.hidePage("mm")).Get(marketMakerRoute, s.handleMarketMaking)
 				webDC.With(dexHostCtx).Get("/dexsettings/{host}", s.handleDexSettings)
 			})
 
@@ -519,6 +657,7 @@ func New(cfg *Config) (*WebServer, error) {
 		r.Get("/user", s.apiUser)
 		r.Post("/locale", s.apiLocale)
 		r.Post("/setlocale", s.apiSetLocale)
+		r.Route("/v1", s.registerAPIV1)
 
 		r.Group(func(apiInit chi.Router) {
 			apiInit.Use(s.rejectUninited)
@@ -529,14 +668,39 @@ func New(cfg *Config) (*WebServer, error) {
 			apiInit.Post("/bondsfeebuffer", s.apiBondsFeeBuffer)
 		})
 
+		// Read-only endpoints that guest sessions can use. Guests are
+		// denied everything else.
+		r.Group(func(apiGuest chi.Router) {
+			apiGuest.Use(s.rejectUnauthedAllowGuest)
+			apiGuest.Get("/notes", s.apiNotes)
+			apiGuest.Post("/logout", s.apiLogout)
+			apiGuest.Post("/balance", s.apiGetBalance)
+			apiGuest.Post("/orders", s.apiOrders)
+			apiGuest.Post("/order", s.apiOrder)
+			apiGuest.Get("/tokenallowances", s.apiTokenAllowances)
+			apiGuest.Post("/txhistory", s.apiTxHistory)
+			apiGuest.Post("/stakestatus", s.apiStakeStatus)
+			apiGuest.Post("/ticketpage", s.apiTicketPage)
+			apiGuest.Post("/mixingstats", s.apiMixingStats)
+			apiGuest.Get("/marketmakingstatus", s.apiMarketMakingStatus)
+			apiGuest.Post("/marketreport", s.apiMarketReport)
+			apiGuest.Post("/cexbalance", s.apiCEXBalance)
+			apiGuest.Get("/archivedmmruns", s.apiArchivedRuns)
+			apiGuest.Post("/mmrunlogs", s.apiRunLogs)
+			apiGuest.Post("/cexbook", s.apiCEXBook)
+			apiGuest.Post("/mmsummaries", s.apiPerformanceSummaries)
+			apiGuest.Get("/blockexplorers", s.apiBlockExplorers)
+			apiGuest.Get("/torsettings", s.apiTorSettings)
+		})
+
 		r.Group(func(apiAuth chi.Router) {
 			apiAuth.Use(s.rejectUnauthed)
-			apiAuth.Get("/notes", s.apiNotes)
 			apiAuth.Post("/defaultwalletcfg", s.apiDefaultWalletCfg)
 			apiAuth.Post("/postbond", s.apiPostBond)
 			apiAuth.Post("/updatebondoptions", s.apiUpdateBondOptions)
 			apiAuth.Post("/redeemprepaidbond", s.apiRedeemPrepaidBond)
 			apiAuth.Post("/newwallet", s.apiNewWallet)
+			apiAuth.Post("/addcustomtoken", s.apiAddCustomToken)
 			apiAuth.Post("/openwallet", s.apiOpenWallet)
 			apiAuth.Post("/depositaddress", s.apiNewDepositAddress)
 			apiAuth.Post("/addressused", s.apiAddressUsed)
@@ -547,20 +711,33 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/trade", s.apiTrade)
 			apiAuth.Post("/tradeasync", s.apiTradeAsync)
 			apiAuth.Post("/cancel", s.apiCancel)
-			apiAuth.Post("/logout", s.apiLogout)
-			apiAuth.Post("/balance", s.apiGetBalance)
 			apiAuth.Post("/parseconfig", s.apiParseConfig)
 			apiAuth.Post("/reconfigurewallet", s.apiReconfig)
 			apiAuth.Post("/changeapppass", s.apiChangeAppPass)
 			apiAuth.Post("/walletsettings", s.apiWalletSettings)
 			apiAuth.Post("/togglewalletstatus", s.apiToggleWalletStatus)
-			apiAuth.Post("/orders", s.apiOrders)
-			apiAuth.Post("/order", s.apiOrder)
 			apiAuth.Post("/send", s.apiSend)
+			apiAuth.Post("/bumpfee", s.apiBumpFee)
+			apiAuth.Post("/noncestatus", s.apiNonceStatus)
+			apiAuth.Post("/replacetx", s.apiReplaceTx)
+			apiAuth.Post("/canceltx", s.apiCancelTx)
+			apiAuth.Post("/fillnoncegaps", s.apiFillNonceGaps)
+			apiAuth.Post("/previewgasfees", s.apiPreviewGasFees)
+			apiAuth.Post("/pegmweb", s.apiPegMWEB)
+			apiAuth.Post("/lightningbalance", s.apiLightningBalance)
+			apiAuth.Post("/lightninginvoice", s.apiLightningInvoice)
+			apiAuth.Post("/lightninginvoicestatus", s.apiLightningInvoiceStatus)
+			apiAuth.Post("/paylightninginvoice", s.apiPayLightningInvoice)
+			apiAuth.Post("/privatekeyfunds", s.apiPrivateKeyFunds)
+			apiAuth.Post("/sweepprivatekey", s.apiSweepPrivateKey)
+			apiAuth.Post("/walletutxos", s.apiWalletUTXOs)
+			apiAuth.Post("/pendingpsbts", s.apiPendingPSBTs)
+			apiAuth.Post("/submitpsbt", s.apiSubmitPSBT)
 			apiAuth.Post("/maxbuy", s.apiMaxBuy)
 			apiAuth.Post("/maxsell", s.apiMaxSell)
 			apiAuth.Post("/preorder", s.apiPreOrder)
 			apiAuth.Post("/exportaccount", s.apiAccountExport)
+			apiAuth.Post("/reputationbreakdown", s.apiReputationBreakdown)
 			apiAuth.Post("/exportseed", s.apiExportSeed)
 			apiAuth.Post("/importaccount", s.apiAccountImport)
 			apiAuth.Post("/toggleaccountstatus", s.apiToggleAccountStatus)
@@ -571,6 +748,8 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/updatedexhost", s.apiUpdateDEXHost)
 			apiAuth.Post("/restorewalletinfo", s.apiRestoreWalletInfo)
 			apiAuth.Post("/toggleratesource", s.apiToggleRateSource)
+			apiAuth.Post("/updateblockexplorers", s.apiUpdateBlockExplorers)
+			apiAuth.Post("/updatetorsettings", s.apiUpdateTorSettings)
 			apiAuth.Post("/validateaddress", s.apiValidateAddress)
 			apiAuth.Post("/txfee", s.apiEstimateSendTxFee)
 			apiAuth.Post("/deletearchivedrecords", s.apiDeleteArchivedRecords)
@@ -579,20 +758,17 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/removewalletpeer", s.apiRemoveWalletPeer)
 			apiAuth.Post("/approvetoken", s.apiApproveToken)
 			apiAuth.Post("/unapprovetoken", s.apiUnapproveToken)
+			apiAuth.Post("/settokenallowance", s.apiSetTokenAllowance)
 			apiAuth.Post("/approvetokenfee", s.apiApproveTokenFee)
-			apiAuth.Post("/txhistory", s.apiTxHistory)
 			apiAuth.Post("/takeaction", s.apiTakeAction)
 			apiAuth.Post("/redeemgamecode", s.redeemGameCode)
 			apiAuth.Get("/exportapplog", s.apiExportAppLogs)
 
-			apiAuth.Post("/stakestatus", s.apiStakeStatus)
 			apiAuth.Post("/setvsp", s.apiSetVSP)
 			apiAuth.Post("/purchasetickets", s.apiPurchaseTickets)
 			apiAuth.Post("/setvotes", s.apiSetVotingPreferences)
 			apiAuth.Post("/listvsps", s.apiListVSPs)
-			apiAuth.Post("/ticketpage", s.apiTicketPage)
 
-			apiAuth.Post("/mixingstats", s.apiMixingStats)
 			apiAuth.Post("/configuremixer", s.apiConfigureMixer)
 
 			apiAuth.Post("/startmarketmakingbot", s.apiStartMarketMakingBot)
@@ -600,16 +776,17 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/updatebotconfig", s.apiUpdateBotConfig)
 			apiAuth.Post("/updatecexconfig", s.apiUpdateCEXConfig)
 			apiAuth.Post("/removebotconfig", s.apiRemoveBotConfig)
-			apiAuth.Get("/marketmakingstatus", s.apiMarketMakingStatus)
-			apiAuth.Post("/marketreport", s.apiMarketReport)
-			apiAuth.Post("/cexbalance", s.apiCEXBalance)
-			apiAuth.Get("/archivedmmruns", s.apiArchivedRuns)
-			apiAuth.Post("/mmrunlogs", s.apiRunLogs)
-			apiAuth.Post("/cexbook", s.apiCEXBook)
+			apiAuth.Post("/mmsummaryinterval", s.apiUpdateSummaryInterval)
+			apiAuth.Post("/mmreconcile", s.apiReconcileTransfers)
 		})
 	})
 
 	// Files
+	if s.branding != nil {
+		mux.Get(brandingCSSRoute, s.handleBrandingCSS)
+		mux.Get(brandingLogoRoute, serveBrandingImage(s.branding.logo))
+		mux.Get(brandingIconRoute, serveBrandingImage(s.branding.icon))
+	}
 	fileServer(mux, "/js", siteDir, "dist", "text/javascript")
 	fileServer(mux, "/css", siteDir, "dist", "text/css")
 	fileServer(mux, "/img", siteDir, "src/img", "")
@@ -731,6 +908,13 @@ func (s *WebServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	listeners = append(listeners, listener)
 	s.ctx = ctx
 
+	if s.acmeHTTP != "" {
+		if err := s.serveACMEChallenges(ctx, &wg, s.acmeHTTP); err != nil {
+			listener.Close()
+			return nil, err
+		}
+	}
+
 	addr, allowInCSP := prepareAddr(listener.Addr())
 	if allowInCSP {
 		// Work around a webkit (safari) bug with the handling of the
@@ -763,6 +947,10 @@ func (s *WebServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 
 	// Configure the websocket handler before starting the server.
 	s.mux.Get("/ws", func(w http.ResponseWriter, r *http.Request) {
+		// The websocket upgrader only accepts same-origin connections.
+		if origin := r.Header.Get("Origin"); origin != "" && s.allowedOrigins[strings.ToLower(origin)] {
+			r.Header.Del("Origin")
+		}
 		s.wsServer.HandleConnect(ctx, w, r)
 	})
 
@@ -815,13 +1003,19 @@ func prepareAddr(addr net.Addr) (string, bool) {
 // authorize creates, stores, and returns a new auth token to identify the user.
 // deauth should be used to invalidate tokens on logout.
 func (s *WebServer) authorize() string {
+	token := newAuthToken()
+	s.authMtx.Lock()
+	s.authTokens[token] = true
+	s.authMtx.Unlock()
+	return token
+}
+
+// newAuthToken generates a random auth token.
+func newAuthToken() string {
 	b := make([]byte, 32)
 	crand.Read(b)
 	token := hex.EncodeToString(b)
 	zero(b)
-	s.authMtx.Lock()
-	s.authTokens[token] = true
-	s.authMtx.Unlock()
 	return token
 }
 
@@ -830,6 +1024,7 @@ func (s *WebServer) authorize() string {
 func (s *WebServer) deauth() {
 	s.authMtx.Lock()
 	s.authTokens = make(map[string]bool)
+	s.guestTokens = make(map[string]bool)
 	s.cachedPasswords = make(map[string]*cachedPassword)
 	s.authMtx.Unlock()
 }
@@ -870,7 +1065,8 @@ func getPWKey(r *http.Request) ([]byte, error) {
 
 // isAuthed checks if the incoming request is from an authorized user/device.
 // Requires the auth token cookie to be set in the request and for the token
-// to match `WebServer.validAuthToken`.
+// to match `WebServer.validAuthToken`. Guest sessions are not authorized. Use
+// isAuthedOrGuest for read-only pages and endpoints.
 func (s *WebServer) isAuthed(r *http.Request) bool {
 	authToken := getAuthToken(r)
 	if authToken == "" {
@@ -959,6 +1155,10 @@ func (s *WebServer) readNotifications(ctx context.Context) {
 		select {
 		case n := <-ch.C:
 			s.wsServer.Notify(notifyRoute, n)
+			s.wsServer.NotifySubscribers(n)
+			if bn, ok := n.(websocket.BotNote); ok {
+				s.wsServer.NotifyMM(bn)
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -989,6 +1189,7 @@ func readPost(w http.ResponseWriter, r *http.Request, thing any) bool {
 // and cookies.
 type userInfo struct {
 	Authed           bool
+	Guest            bool
 	PasswordIsCached bool
 }
 
@@ -5,6 +5,8 @@ package webserver
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -13,20 +15,28 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/authlimit"
+	"decred.org/dcrdex/client/comms"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mnemonic"
 	"decred.org/dcrdex/client/orderbook"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/acme"
 )
 
 var (
@@ -84,6 +94,7 @@ type TCore struct {
 	estFee           uint64
 	estFeeErr        error
 	validAddr        bool
+	resolvedAddr     string
 	walletDisabled   bool
 	walletStatusErr  error
 	deletedRecords   int
@@ -91,10 +102,23 @@ type TCore struct {
 	tradeErr         error
 	notes            []*db.Notification
 	notesErr         error
+	exchanges        map[string]*core.Exchange
+	explorers        *db.ExplorerSettings
+	explorersErr     error
+	orders           []*core.Order
+	txs              []*asset.WalletTransaction
+	torSettings      *db.TorSettings
+	proxySettings    *db.ProxySettings
+	retention        *db.RetentionSettings
+	searchFilter     *core.SearchFilter
+	searchResults    []*core.SearchResult
+	statsFilter      *db.MarketStatsFilter
+	marketStats      []*db.MarketStats
+	fiatRates        []*db.FiatRate
 }
 
 func (c *TCore) Network() dex.Network                         { return dex.Mainnet }
-func (c *TCore) Exchanges() map[string]*core.Exchange         { return nil }
+func (c *TCore) Exchanges() map[string]*core.Exchange         { return c.exchanges }
 func (c *TCore) Exchange(host string) (*core.Exchange, error) { return nil, nil }
 func (c *TCore) GetDEXConfig(dexAddr string, certI any) (*core.Exchange, error) {
 	return nil, c.getDEXConfigErr // TODO along with test for apiUser / Exchanges() / User()
@@ -123,6 +147,46 @@ func (c *TCore) ToggleRateSourceStatus(src string, disable bool) error {
 func (c *TCore) FiatRateSources() map[string]bool {
 	return nil
 }
+func (c *TCore) BlockExplorers() *core.BlockExplorerSettings {
+	return &core.BlockExplorerSettings{}
+}
+func (c *TCore) UpdateBlockExplorers(settings *db.ExplorerSettings) error {
+	if c.explorersErr != nil {
+		return c.explorersErr
+	}
+	c.explorers = settings
+	return nil
+}
+func (c *TCore) TorSettings() *core.TorStatus {
+	return &core.TorStatus{Settings: c.torSettings}
+}
+func (c *TCore) UpdateTorSettings(settings *db.TorSettings) error {
+	c.torSettings = settings
+	return nil
+}
+func (c *TCore) ProxySettings() *core.ProxyStatus {
+	return &core.ProxyStatus{Settings: c.proxySettings}
+}
+func (c *TCore) UpdateProxySettings(settings *db.ProxySettings) error {
+	if settings.Services[""] != nil {
+		return tErr
+	}
+	c.proxySettings = settings
+	return nil
+}
+func (c *TCore) RetentionSettings() (*db.RetentionSettings, error) {
+	if c.retention == nil {
+		return new(db.RetentionSettings), nil
+	}
+	return c.retention, nil
+}
+func (c *TCore) UpdateRetentionSettings(settings *db.RetentionSettings) error {
+	if settings.MaxOrders < 0 {
+		return tErr
+	}
+	c.retention = settings
+	return nil
+}
 
 func (c *TCore) InitializeClient(pw []byte, seed *string) (string, error) {
 	var mnemonicSeed string
@@ -154,12 +218,15 @@ func (c *TCore) WalletState(assetID uint32) *core.WalletState {
 func (c *TCore) CreateWallet(appPW, walletPW []byte, form *core.WalletForm) error {
 	return c.createWalletErr
 }
-func (c *TCore) RescanWallet(assetID uint32, force bool) error    { return c.rescanWalletErr }
-func (c *TCore) OpenWallet(assetID uint32, pw []byte) error       { return c.openWalletErr }
-func (c *TCore) CloseWallet(assetID uint32) error                 { return c.closeWalletErr }
-func (c *TCore) ConnectWallet(assetID uint32) error               { return nil }
-func (c *TCore) Wallets() []*core.WalletState                     { return nil }
-func (c *TCore) WalletSettings(uint32) (map[string]string, error) { return nil, nil }
+func (c *TCore) AddCustomToken(appPW []byte, ct *asset.CustomToken) (uint32, error) {
+	return 0, nil
+}
+func (c *TCore) RescanWallet(assetID uint32, force bool, bday uint64) error { return c.rescanWalletErr }
+func (c *TCore) OpenWallet(assetID uint32, pw []byte) error                 { return c.openWalletErr }
+func (c *TCore) CloseWallet(assetID uint32) error                           { return c.closeWalletErr }
+func (c *TCore) ConnectWallet(assetID uint32) error                         { return nil }
+func (c *TCore) Wallets() []*core.WalletState                               { return nil }
+func (c *TCore) WalletSettings(uint32) (map[string]string, error)           { return nil, nil }
 func (c *TCore) ReconfigureWallet(aPW, nPW []byte, form *core.WalletForm) error {
 	return nil
 }
@@ -184,9 +251,66 @@ func (c *TCore) SupportedAssets() map[uint32]*core.SupportedAsset {
 func (c *TCore) Send(pw []byte, assetID uint32, value uint64, address string, subtract bool) (asset.Coin, error) {
 	return &tCoin{id: []byte{0xde, 0xc7, 0xed}}, c.sendErr
 }
+func (c *TCore) BumpFee(assetID uint32, txID string, feeRate uint64) (string, error) {
+	return "", nil
+}
+func (c *TCore) SendWithGasFees(pw []byte, assetID uint32, value uint64, address string, rates *asset.GasFeeRates) (asset.Coin, error) {
+	return &tCoin{id: []byte{0xde, 0xc7, 0xed}}, c.sendErr
+}
+func (c *TCore) PreviewGasFees(assetID uint32, txType asset.TransactionType, assetVer uint32, rates *asset.GasFeeRates) (*asset.GasFeePreview, error) {
+	return &asset.GasFeePreview{}, nil
+}
+func (c *TCore) LightningBalance(assetID uint32) (uint64, error) {
+	return 0, nil
+}
+func (c *TCore) LightningInvoice(assetID uint32, amt uint64, memo string) (*asset.LightningInvoice, error) {
+	return &asset.LightningInvoice{Amount: amt}, nil
+}
+func (c *TCore) LightningInvoiceStatus(assetID uint32, paymentHash string) (bool, uint64, error) {
+	return false, 0, nil
+}
+func (c *TCore) PayLightningInvoice(pw []byte, assetID uint32, invoice string, maxFee uint64) (*asset.LightningPayment, error) {
+	return &asset.LightningPayment{}, nil
+}
+func (c *TCore) PegMWEB(pw []byte, assetID uint32, amt uint64, pegIn bool) (string, error) {
+	return "", nil
+}
+func (c *TCore) WalletUTXOs(assetID uint32) ([]*asset.WalletUTXO, error) {
+	return nil, nil
+}
+func (c *TCore) PendingPSBTs(assetID uint32) ([]*asset.PSBTRequest, error) {
+	return nil, nil
+}
+func (c *TCore) SubmitSignedPSBT(assetID uint32, id, signedPSBT string) error {
+	return nil
+}
+func (c *TCore) NonceStatus(assetID uint32) (*asset.NonceStatus, error) {
+	return nil, nil
+}
+func (c *TCore) ReplaceTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error) {
+	return "", nil
+}
+func (c *TCore) CancelTx(assetID uint32, txID string, rates *asset.GasFeeRates) (string, error) {
+	return "", nil
+}
+func (c *TCore) FillNonceGaps(assetID uint32) ([]string, error) {
+	return nil, nil
+}
+func (c *TCore) PrivateKeyFunds(assetID uint32, privKey string) (uint64, error) {
+	return 0, nil
+}
+func (c *TCore) SweepPrivateKey(pw []byte, assetID uint32, privKey string) (string, uint64, error) {
+	return "", 0, nil
+}
 func (c *TCore) ValidateAddress(address string, assetID uint32) (bool, error) {
 	return c.validAddr, nil
 }
+func (c *TCore) ResolveAddress(address string, assetID uint32) (string, error) {
+	if c.resolvedAddr != "" {
+		return c.resolvedAddr, nil
+	}
+	return address, nil
+}
 func (c *TCore) EstimateSendTxFee(addr string, assetID uint32, value uint64, subtract, maxWithdraw bool) (fee uint64, isValidAddress bool, err error) {
 	return c.estFee, true, c.estFeeErr
 }
@@ -230,8 +354,51 @@ func (c *TCore) AckNotes(ids []dex.Bytes) {}
 
 func (c *TCore) Logout() error { return c.logoutErr }
 
-func (c *TCore) Orders(*core.OrderFilter) ([]*core.Order, error) { return nil, nil }
-func (c *TCore) Order(oid dex.Bytes) (*core.Order, error)        { return nil, nil }
+func (c *TCore) Search(filter *core.SearchFilter) ([]*core.SearchResult, error) {
+	c.searchFilter = filter
+	return c.searchResults, nil
+}
+
+func (c *TCore) MarketStats(filter *db.MarketStatsFilter) ([]*db.MarketStats, error) {
+	c.statsFilter = filter
+	return c.marketStats, nil
+}
+
+func (c *TCore) FiatRateHistory(assetID uint32, since, until uint64) ([]*db.FiatRate, error) {
+	var rates []*db.FiatRate
+	for _, r := range c.fiatRates {
+		if r.Stamp >= since && (until == 0 || r.Stamp < until) {
+			rates = append(rates, r)
+		}
+	}
+	return rates, nil
+}
+
+func (c *TCore) FiatRateAt(assetID uint32, stamp uint64) (*db.FiatRate, error) {
+	for _, r := range c.fiatRates {
+		if r.Stamp == stamp {
+			return r, nil
+		}
+	}
+	return nil, db.ErrNoFiatRate
+}
+
+func (c *TCore) Orders(filter *core.OrderFilter) ([]*core.Order, error) {
+	ords := c.orders
+	if filter.Offset != nil {
+		for i, ord := range ords {
+			if bytes.Equal(ord.ID, filter.Offset) {
+				ords = ords[i+1:]
+				break
+			}
+		}
+	}
+	if filter.N > 0 && len(ords) > filter.N {
+		ords = ords[:filter.N]
+	}
+	return ords, nil
+}
+func (c *TCore) Order(oid dex.Bytes) (*core.Order, error) { return nil, nil }
 func (c *TCore) MaxBuy(host string, base, quote uint32, rate uint64) (*core.MaxOrderEstimate, error) {
 	return nil, nil
 }
@@ -244,6 +411,9 @@ func (c *TCore) PreOrder(*core.TradeForm) (*core.OrderEstimate, error) {
 func (c *TCore) AccountExport(pw []byte, host string) (*core.Account, []*db.Bond, error) {
 	return nil, nil, nil
 }
+func (c *TCore) ReputationBreakdown(host string) (*msgjson.ReputationResult, error) {
+	return nil, nil
+}
 func (c *TCore) AccountImport(pw []byte, account *core.Account, bonds []*db.Bond) error {
 	return nil
 }
@@ -297,6 +467,15 @@ func (c *TCore) ApproveToken(appPW []byte, assetID uint32, dexAddr string, onCon
 func (c *TCore) UnapproveToken(appPW []byte, assetID uint32, version uint32) (string, error) {
 	return "", nil
 }
+func (c *TCore) TokenAllowances() (map[uint32][]*asset.TokenAllowance, error) {
+	return nil, nil
+}
+func (c *TCore) SetTokenAllowance(appPW []byte, assetID, version uint32, amount uint64) (string, error) {
+	return "", nil
+}
+func (c *TCore) ApproveTokenWithGasFees(appPW []byte, assetID uint32, dexAddr string, rates *asset.GasFeeRates, onConfirm func()) (string, error) {
+	return "", nil
+}
 func (c *TCore) ApproveTokenFee(assetID uint32, version uint32, approval bool) (uint64, error) {
 	return 0, nil
 }
@@ -325,7 +504,19 @@ func (c *TCore) TicketPage(assetID uint32, scanStart int32, n, skipN int) ([]*as
 }
 
 func (c *TCore) TxHistory(assetID uint32, n int, refID *string, past bool) ([]*asset.WalletTransaction, error) {
-	return nil, nil
+	txs := c.txs
+	if refID != nil {
+		for i, tx := range txs {
+			if tx.ID == *refID {
+				txs = txs[i+1:]
+				break
+			}
+		}
+	}
+	if n > 0 && len(txs) > n {
+		txs = txs[:n]
+	}
+	return txs, nil
 }
 
 func (c *TCore) FundsMixingStats(assetID uint32) (*asset.FundsMixingStats, error) {
@@ -513,6 +704,59 @@ func TestConnectBindError(t *testing.T) {
 	}
 }
 
+func TestACME(t *testing.T) {
+	newServer := func(acmeCfg *ACMEConfig, dataDir string) (*WebServer, error) {
+		return New(&Config{
+			DataDir: dataDir,
+			Core:    &TCore{},
+			Addr:    "127.0.0.1:0",
+			Logger:  tLogger,
+			ACME:    acmeCfg,
+		})
+	}
+
+	dataDir := t.TempDir()
+	s, err := newServer(&ACMEConfig{Domains: []string{"dex.example.com"}, HTTPAddr: "127.0.0.1:0"}, dataDir)
+	if err != nil {
+		t.Fatalf("error creating server: %v", err)
+	}
+	if s.srv.TLSConfig == nil || s.srv.TLSConfig.GetCertificate == nil {
+		t.Fatalf("TLS not configured for ACME")
+	}
+	if !slices.Contains(s.srv.TLSConfig.NextProtos, acme.ALPNProto) {
+		t.Fatalf("TLS-ALPN-01 challenges not enabled")
+	}
+	if err := s.acme.HostPolicy(tCtx, "dex.example.com"); err != nil {
+		t.Fatalf("configured domain rejected: %v", err)
+	}
+	if err := s.acme.HostPolicy(tCtx, "other.example.com"); err == nil {
+		t.Fatalf("other domain not rejected")
+	}
+	if s.acmeHTTP != "127.0.0.1:0" {
+		t.Fatalf("wrong HTTP-01 address %q", s.acmeHTTP)
+	}
+
+	// Non-challenge requests to the HTTP-01 listener are redirected to HTTPS.
+	w := httptest.NewRecorder()
+	s.acme.HTTPHandler(nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://dex.example.com/wallets", nil))
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "https://dex.example.com/wallets" {
+		t.Fatalf("wrong redirect: %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	for name, tt := range map[string]struct {
+		cfg     *ACMEConfig
+		dataDir string
+	}{
+		"no domains": {&ACMEConfig{Domains: []string{" "}}, dataDir},
+		"ip address": {&ACMEConfig{Domains: []string{"203.0.113.1"}}, dataDir},
+		"no datadir": {&ACMEConfig{Domains: []string{"dex.example.com"}}, ""},
+	} {
+		if _, err := newServer(tt.cfg, tt.dataDir); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+}
+
 func TestAPILogin(t *testing.T) {
 	writer := new(TWriter)
 	var body any
@@ -810,7 +1054,7 @@ func TestPasswordCache(t *testing.T) {
 		pwKeyCK: hex.EncodeToString(key1),
 	})
 
-	s.apiLogout(writer, nil)
+	s.apiLogout(writer, httptest.NewRequest("POST", "/api/logout", nil))
 
 	if len(s.cachedPasswords) != 0 {
 		t.Fatal("logout should clear all cached passwords")
@@ -906,6 +1150,10 @@ func TestAPIValidateAddress(t *testing.T) {
 	tCore.validAddr = true
 	ensureResponse(t, s.apiValidateAddress, want, reader, writer, body, nil)
 
+	want = `{"ok":true,"resolved":"0xaddr"}`
+	tCore.resolvedAddr = "0xaddr"
+	ensureResponse(t, s.apiValidateAddress, want, reader, writer, body, nil)
+
 	want = `{"ok":false}`
 	tCore.validAddr = false
 	ensureResponse(t, s.apiValidateAddress, want, reader, writer, body, nil)
@@ -1077,3 +1325,1008 @@ func Test_prepareAddr(t *testing.T) {
 		}
 	}
 }
+
+func TestAPIV1Spec(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPIV1, &spec); err != nil {
+		t.Fatalf("error parsing OpenAPI description: %v", err)
+	}
+
+	s, _, shutdown := newTServer(t, false)
+	defer shutdown()
+	r := chi.NewRouter()
+	s.registerAPIV1(r)
+
+	// Every route is documented, and every documented operation is routed.
+	documented := make(map[string]bool)
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		op := method + " " + route
+		if !documented[op] {
+			return fmt.Errorf("%s is not documented", op)
+		}
+		delete(documented, op)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for op := range documented {
+		t.Fatalf("%s is documented but not routed", op)
+	}
+}
+
+func TestAPIV1(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+
+	var authToken string
+	do := func(method, path string, body any, wantStatus int) []byte {
+		t.Helper()
+		var reqBody io.Reader
+		if body != nil {
+			b, _ := json.Marshal(body)
+			reqBody = bytes.NewReader(b)
+		}
+		req := httptest.NewRequest(method, "/api/v1"+path, reqBody)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if authToken != "" {
+			req.AddCookie(&http.Cookie{Name: authCK, Value: authToken})
+		}
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s: wanted status %d, got %d: %s", method, path, wantStatus, rec.Code, rec.Body.String())
+		}
+		return rec.Body.Bytes()
+	}
+
+	do("GET", "/openapi.json", nil, http.StatusOK)
+
+	// Uninitialized and unauthorized.
+	do("GET", "/wallets", nil, http.StatusPreconditionRequired)
+	tCore.isInited = true
+	var errResp v1Error
+	if err := json.Unmarshal(do("GET", "/wallets", nil, http.StatusUnauthorized), &errResp); err != nil || errResp.Error == "" {
+		t.Fatalf("bad error response: %v", err)
+	}
+	authToken = s.authorize()
+
+	if b := do("GET", "/wallets", nil, http.StatusOK); string(b) != "[]\n" {
+		t.Fatalf("wrong wallets response %s", b)
+	}
+	var wallet v1Wallet
+	if err := json.Unmarshal(do("GET", "/wallets/42", nil, http.StatusOK), &wallet); err != nil || wallet.Symbol != "dcr" {
+		t.Fatalf("wrong wallet: %+v, %v", wallet, err)
+	}
+	do("GET", "/wallets/nope", nil, http.StatusBadRequest)
+	tCore.notHas = true
+	do("GET", "/wallets/42", nil, http.StatusNotFound)
+	tCore.notHas = false
+
+	// Sends always require the password.
+	sendForm := map[string]any{"address": "addr", "value": 1}
+	do("POST", "/wallets/42/send", sendForm, http.StatusBadRequest)
+	sendForm["pass"] = "pass"
+	do("POST", "/wallets/42/send", sendForm, http.StatusOK)
+	tCore.sendErr = tErr
+	do("POST", "/wallets/42/send", sendForm, http.StatusInternalServerError)
+	tCore.sendErr = nil
+
+	tradeForm := &v1TradeForm{
+		Host:    "somedex.com",
+		BaseID:  42,
+		QuoteID: 0,
+		Type:    "limit",
+		Qty:     1e8,
+		Rate:    1e6,
+		Pass:    encode.PassBytes("pass"),
+	}
+	var ord v1Order
+	if err := json.Unmarshal(do("POST", "/orders", tradeForm, http.StatusCreated), &ord); err != nil {
+		t.Fatalf("error decoding order: %v", err)
+	}
+	if ord.Type != "limit" || ord.Qty != 1e8 || ord.Rate != 1e6 {
+		t.Fatalf("wrong order %+v", ord)
+	}
+	tradeForm.Type = "stop"
+	do("POST", "/orders", tradeForm, http.StatusBadRequest)
+
+	oid := strings.Repeat("01", order.OrderIDSize)
+	do("GET", "/orders/"+oid, nil, http.StatusNotFound)
+	do("GET", "/orders/01", nil, http.StatusBadRequest)
+	do("DELETE", "/orders/"+oid, nil, http.StatusNoContent)
+	do("GET", "/orders?status=booked&base=42&quote=0", nil, http.StatusOK)
+	do("GET", "/orders?status=lost", nil, http.StatusBadRequest)
+	do("GET", "/orders?base=42", nil, http.StatusBadRequest)
+
+	tCore.searchResults = []*core.SearchResult{
+		{Type: "match", Stamp: 5, Order: &core.Order{ID: dex.Bytes(encode.RandomBytes(32))}, MatchID: encode.RandomBytes(32)},
+		{Type: "notification", Stamp: 4, Notification: &db.Notification{SubjectText: "Swap sent"}},
+	}
+	var results []*v1SearchResult
+	if err := json.Unmarshal(do("GET", "/search?q=swap+sent&since=1&n=10", nil, http.StatusOK), &results); err != nil {
+		t.Fatalf("error decoding search results: %v", err)
+	}
+	if len(results) != 2 || results[0].Order == nil || results[0].MatchID == "" || results[1].Notification == nil {
+		t.Fatalf("wrong search results %+v", results)
+	}
+	if f := tCore.searchFilter; f.Query != "swap sent" || f.Since != 1 || f.N != 10 {
+		t.Fatalf("wrong search filter %+v", f)
+	}
+	do("GET", "/search", nil, http.StatusBadRequest)
+	do("GET", "/search?q=swap&n=0", nil, http.StatusBadRequest)
+
+	if b := do("GET", "/stats/markets", nil, http.StatusOK); string(b) != "[]\n" {
+		t.Fatalf("wrong empty market stats response %s", b)
+	}
+	tCore.marketStats = []*db.MarketStats{{Host: "somedex.com", Base: 42, Quote: 0, Matches: 3}}
+	var stats []*db.MarketStats
+	if err := json.Unmarshal(do("GET", "/stats/markets?host=somedex.com&base=42&quote=0", nil, http.StatusOK), &stats); err != nil {
+		t.Fatalf("error decoding market stats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].Matches != 3 {
+		t.Fatalf("wrong market stats %+v", stats)
+	}
+	if f := tCore.statsFilter; f.Host != "somedex.com" || f.Market == nil || f.Market.Base != 42 {
+		t.Fatalf("wrong market stats filter %+v", f)
+	}
+	do("GET", "/stats/markets?base=42", nil, http.StatusBadRequest)
+
+	if b := do("GET", "/fiatrates/42", nil, http.StatusOK); string(b) != "[]\n" {
+		t.Fatalf("wrong empty fiat rate history response %s", b)
+	}
+	tCore.fiatRates = []*db.FiatRate{{Stamp: 1000, Rate: 20}, {Stamp: 2000, Rate: 21}}
+	var rates []*db.FiatRate
+	if err := json.Unmarshal(do("GET", "/fiatrates/42?since=1500", nil, http.StatusOK), &rates); err != nil {
+		t.Fatalf("error decoding fiat rate history: %v", err)
+	}
+	if len(rates) != 1 || rates[0].Rate != 21 {
+		t.Fatalf("wrong fiat rate history %+v", rates)
+	}
+	var rate db.FiatRate
+	if err := json.Unmarshal(do("GET", "/fiatrates/42/at/1000", nil, http.StatusOK), &rate); err != nil {
+		t.Fatalf("error decoding fiat rate: %v", err)
+	}
+	if rate.Rate != 20 {
+		t.Fatalf("wrong fiat rate %+v", rate)
+	}
+	do("GET", "/fiatrates/42/at/3000", nil, http.StatusNotFound)
+	do("GET", "/fiatrates/42/at/soon", nil, http.StatusBadRequest)
+	do("GET", "/fiatrates/btc", nil, http.StatusBadRequest)
+
+	do("GET", "/markets/somedex.com/42/0/book", nil, http.StatusOK)
+	do("GET", "/bonds", nil, http.StatusOK)
+
+	// No market maker.
+	do("GET", "/mm/bots", nil, http.StatusServiceUnavailable)
+
+	do("POST", "/logout", nil, http.StatusNoContent)
+	do("GET", "/wallets", nil, http.StatusUnauthorized)
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	tCore.isInited = true
+
+	login := func(remoteAddr string, wantStatus int) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/v1/login", strings.NewReader(`{"pass":"pass"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("wanted status %d, got %d: %s", wantStatus, rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	tCore.loginErr = tErr
+	for i := 0; i < authlimit.FreeAttempts+1; i++ {
+		login("192.0.2.1:1234", http.StatusUnauthorized)
+	}
+	rec := login("192.0.2.1:1234", http.StatusTooManyRequests)
+	if rec.Header().Get("Retry-After") != "1" {
+		t.Fatalf("wrong Retry-After header %q", rec.Header().Get("Retry-After"))
+	}
+
+	// The correct password is rejected too while limited, but not from
+	// another address.
+	tCore.loginErr = nil
+	login("192.0.2.1:1234", http.StatusTooManyRequests)
+	login("192.0.2.2:1234", http.StatusNoContent)
+
+	// Password checks for new API keys are limited as well.
+	req := httptest.NewRequest("POST", "/api/v1/keys", strings.NewReader(`{"scopes":["read"],"pass":"pass"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.AddCookie(&http.Cookie{Name: authCK, Value: s.authorize()})
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("wanted status %d, got %d: %s", http.StatusTooManyRequests, rec.Code, rec.Body.String())
+	}
+}
+
+func TestGuestMode(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	tCore.isInited = true
+	s.guestPassHash = guestPassHash("guestpass")
+
+	var authToken string
+	do := func(method, path, body string, wantStatus int) *httptest.ResponseRecorder {
+		t.Helper()
+		var reqBody io.Reader
+		if body != "" {
+			reqBody = strings.NewReader(body)
+		}
+		req := httptest.NewRequest(method, path, reqBody)
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if authToken != "" {
+			req.AddCookie(&http.Cookie{Name: authCK, Value: authToken})
+		}
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s: wanted status %d, got %d: %s", method, path, wantStatus, rec.Code, rec.Body.String())
+		}
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == authCK {
+				authToken = c.Value
+			}
+		}
+		return rec
+	}
+
+	// The guest password doesn't log in core.
+	tCore.loginErr = tErr
+	do("POST", "/api/v1/login", `{"pass":"guestpass"}`, http.StatusNoContent)
+	if authToken == "" || !s.guestTokens[authToken] {
+		t.Fatalf("no guest session")
+	}
+	var user struct {
+		Guest bool `json:"guest"`
+	}
+	if err := json.Unmarshal(do("GET", "/api/user", "", http.StatusOK).Body.Bytes(), &user); err != nil || !user.Guest {
+		t.Fatalf("user not reported as a guest: %v", err)
+	}
+
+	// Read-only requests are allowed.
+	do("POST", "/api/balance", `{"assetID":42}`, http.StatusOK)
+	do("GET", "/api/v1/wallets", "", http.StatusOK)
+	do("GET", "/api/v1/csrf", "", http.StatusOK)
+
+	// Anything else is not.
+	do("POST", "/api/send", `{"assetID":42,"value":1,"address":"addr","pw":"pass"}`, http.StatusForbidden)
+	do("POST", "/api/exportseed", `{"pass":"pass"}`, http.StatusForbidden)
+	do("POST", "/api/v1/orders", `{}`, http.StatusForbidden)
+	do("POST", "/api/v1/wallets/42/send", `{}`, http.StatusForbidden)
+	do("GET", "/api/v1/keys", "", http.StatusForbidden)
+	do("GET", "/generatecompanionappqrcode", "", http.StatusForbidden)
+	do("POST", "/api/lightningbalance", `{"assetID":0}`, http.StatusForbidden)
+	do("POST", "/api/mmreconcile", `{}`, http.StatusForbidden)
+	do("PUT", "/api/v1/settings/tor", `{}`, http.StatusForbidden)
+
+	// A guest logout doesn't log out core or other sessions.
+	tCore.logoutErr = tErr
+	fullToken := s.authorize()
+	do("POST", "/api/logout", `{}`, http.StatusOK)
+	if len(s.guestTokens) != 0 || !s.authTokens[fullToken] {
+		t.Fatalf("wrong sessions after guest logout")
+	}
+	do("GET", "/api/v1/wallets", "", http.StatusUnauthorized)
+
+	// A guest session is replaced when logging in with the app password.
+	do("POST", "/api/v1/login", `{"pass":"guestpass"}`, http.StatusNoContent)
+	tCore.loginErr = nil
+	do("POST", "/api/v1/login", `{"pass":"pass"}`, http.StatusNoContent)
+	if len(s.guestTokens) != 0 || !s.authTokens[authToken] {
+		t.Fatalf("guest session not upgraded")
+	}
+	do("POST", "/api/v1/orders", `{}`, http.StatusBadRequest)
+
+	// The app password can't be changed to the guest password.
+	if rec := do("POST", "/api/changeapppass", `{"appPW":"pass","newAppPW":"guestpass"}`, http.StatusOK); !strings.Contains(rec.Body.String(), errAppPassIsGuestPass.Error()) {
+		t.Fatalf("app password changed to the guest password")
+	}
+
+	// A guest password that is also the app password logs in fully.
+	do("POST", "/api/logout", `{}`, http.StatusOK)
+	authToken = ""
+	do("POST", "/api/v1/login", `{"pass":"guestpass"}`, http.StatusNoContent)
+	if len(s.guestTokens) != 0 || !s.authTokens[authToken] {
+		t.Fatalf("app password logged in as a guest")
+	}
+
+	// No guest logins without a guest password.
+	s.guestPassHash = nil
+	tCore.loginErr = tErr
+	authToken = ""
+	do("POST", "/api/v1/login", `{"pass":"guestpass"}`, http.StatusUnauthorized)
+}
+
+func TestBranding(t *testing.T) {
+	dir := t.TempDir()
+	logoPath := filepath.Join(dir, "logo.png")
+	// The PNG signature is enough for content type detection.
+	if err := os.WriteFile(logoPath, []byte("\x89PNG\r\n\x1a\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	notImagePath := filepath.Join(dir, "logo.txt")
+	if err := os.WriteFile(notImagePath, []byte("text"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &core.Branding{
+		AppName:     "Example Wallet",
+		Logo:        logoPath,
+		LightColors: map[string]string{"body-bg": "#fff", "text-color": "rgb(0, 0, 0)"},
+		DarkColors:  map[string]string{"body-bg": "#000"},
+		HiddenPages: []string{"mm"},
+	}
+	b, err := newBranding(cfg)
+	if err != nil {
+		t.Fatalf("error loading branding: %v", err)
+	}
+	if b.icon != b.logo || b.logo.contentType != "image/png" {
+		t.Fatalf("wrong images")
+	}
+	css := string(b.css)
+	for _, want := range []string{
+		"body.branded:not(.dark) {\n  --body-bg: #fff;\n  --text-color: rgb(0, 0, 0);\n}",
+		"body.branded.dark {\n  --body-bg: #000;\n}",
+		`content: url("/branding/logo")`,
+		`content: url("/branding/icon")`,
+		`body.branded a[href^="/mmsettings"]`,
+	} {
+		if !strings.Contains(css, want) {
+			t.Fatalf("style sheet missing %q:\n%s", want, css)
+		}
+	}
+	if title := b.title("Wallets | Bison Wallet"); title != "Wallets | Example Wallet" {
+		t.Fatalf("wrong title %q", title)
+	}
+
+	for name, mod := range map[string]func(*core.Branding){
+		"bad color":    func(c *core.Branding) { c.LightColors = map[string]string{"body-bg": "red; } * { display: none"} },
+		"bad property": func(c *core.Branding) { c.DarkColors = map[string]string{"body-bg:": "red"} },
+		"unknown page": func(c *core.Branding) { c.HiddenPages = []string{"wallets"} },
+		"missing logo": func(c *core.Branding) { c.Logo = filepath.Join(dir, "nope.png") },
+		"not an image": func(c *core.Branding) { c.Icon = notImagePath },
+	} {
+		badCfg := *cfg
+		mod(&badCfg)
+		if _, err := newBranding(&badCfg); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+
+	// Hidden pages are not found.
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	tCore.isInited = true
+	tCore.exchanges = map[string]*core.Exchange{"somedex.com": {Host: "somedex.com"}}
+	s.branding = b
+	authToken := s.authorize()
+	for _, path := range []string{"/mm", "/mmsettings", "/mmlogs", "/mmarchives"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.AddCookie(&http.Cookie{Name: authCK, Value: authToken})
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: wanted status %d, got %d", path, http.StatusNotFound, rec.Code)
+		}
+	}
+}
+
+func TestNetworkRedirect(t *testing.T) {
+	s, err := New(&Config{
+		Core:        &TCore{isInited: true},
+		Addr:        "127.0.0.1:0",
+		Logger:      tLogger,
+		NetworkURLs: map[string]string{"testnet": "http://127.0.0.2:5758"},
+	})
+	if err != nil {
+		t.Fatalf("error creating server: %v", err)
+	}
+	for path, want := range map[string]string{
+		"/testnet":                 "http://127.0.0.2:5758/",
+		"/testnet/":                "http://127.0.0.2:5758/",
+		"/testnet/wallets":         "http://127.0.0.2:5758/wallets",
+		"/testnet/order/ab?x=1&y=": "http://127.0.0.2:5758/order/ab?x=1&y=",
+	} {
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusSeeOther {
+			t.Fatalf("%s: wanted status %d, got %d", path, http.StatusSeeOther, rec.Code)
+		}
+		if loc := rec.Header().Get("Location"); loc != want {
+			t.Fatalf("%s: wanted redirect to %s, got %s", path, want, loc)
+		}
+	}
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/simnet/wallets", nil))
+	if rec.Code == http.StatusSeeOther {
+		t.Fatalf("redirected for a network that is not running")
+	}
+}
+
+func TestDeepLink(t *testing.T) {
+	oid := strings.Repeat("ab", 32)
+	for link, want := range map[string]string{
+		"dex://wallets":                            "/wallets",
+		"dex://mm/":                                "/mm",
+		"dex://order/" + oid:                       "/order/" + oid,
+		"dex://dex.decred.org:7232/settings":       "/dexsettings/dex.decred.org:7232",
+		"dex://dex.decred.org:7232/market/dcr_btc": "/markets?baseID=42&host=dex.decred.org%3A7232&quoteID=0",
+	} {
+		path, err := ParseDeepLink(link)
+		if err != nil {
+			t.Fatalf("%s: %v", link, err)
+		}
+		if path != want {
+			t.Fatalf("%s: wanted %s, got %s", link, want, path)
+		}
+	}
+	for _, link := range []string{
+		"",
+		"https://wallets",
+		"dex://",
+		"dex://wallets/btc",
+		"dex://order/abcd",
+		"dex://dex.decred.org:7232",
+		"dex://dex.decred.org:7232/market/dcr",
+		"dex://dex.decred.org:7232/market/dcr_xyz",
+	} {
+		if _, err := ParseDeepLink(link); err == nil {
+			t.Fatalf("no error for %q", link)
+		}
+	}
+
+	s, err := New(&Config{
+		Core:   &TCore{isInited: true},
+		Addr:   "127.0.0.1:0",
+		Logger: tLogger,
+	})
+	if err != nil {
+		t.Fatalf("error creating server: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DeepLinkPath("dex://order/"+oid), nil))
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("wanted status %d, got %d", http.StatusSeeOther, rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/order/"+oid {
+		t.Fatalf("wanted redirect to /order/%s, got %s", oid, loc)
+	}
+	rec = httptest.NewRecorder()
+	s.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DeepLinkPath("dex://nope"), nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("wanted status %d for a bad link, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	for _, origins := range [][]string{{"*"}, {"https://ui.example.com/path"}, {"ftp://ui.example.com"}, {"ui.example.com"}} {
+		if _, err := parseAllowedOrigins(origins); err == nil {
+			t.Fatalf("no error for origins %v", origins)
+		}
+	}
+
+	tCore := &TCore{isInited: true}
+	s, err := New(&Config{
+		Core:           tCore,
+		Addr:           "127.0.0.1:0",
+		Logger:         tLogger,
+		AllowedOrigins: []string{"https://UI.example.com/"},
+	})
+	if err != nil {
+		t.Fatalf("error creating server: %v", err)
+	}
+	const allowedOrigin = "https://ui.example.com"
+	sessionToken := s.authorize()
+
+	do := func(method, path, origin string, session bool, hdrs map[string]string, wantStatus int) *httptest.ResponseRecorder {
+		t.Helper()
+		var body io.Reader
+		if method == http.MethodPost {
+			body = strings.NewReader(`{"pass":"pass"}`)
+		}
+		req := httptest.NewRequest(method, "http://127.0.0.1:5758/api/v1"+path, body)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		if session {
+			req.AddCookie(&http.Cookie{Name: authCK, Value: sessionToken})
+		}
+		for k, v := range hdrs {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s from %q: wanted status %d, got %d: %s", method, path, origin, wantStatus, rec.Code, rec.Body.String())
+		}
+		return rec
+	}
+
+	// Preflight.
+	preflight := map[string]string{"Access-Control-Request-Method": "POST"}
+	rec := do(http.MethodOptions, "/login", allowedOrigin, false, preflight, http.StatusNoContent)
+	if rec.Header().Get("Access-Control-Allow-Origin") != allowedOrigin ||
+		rec.Header().Get("Access-Control-Allow-Credentials") != "true" ||
+		!strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), csrfHeader) {
+		t.Fatalf("wrong preflight headers: %v", rec.Header())
+	}
+	do(http.MethodOptions, "/login", "https://evil.example.com", false, preflight, http.StatusForbidden)
+
+	// Unsafe requests from other origins are rejected.
+	do(http.MethodPost, "/login", "https://evil.example.com", false, nil, http.StatusForbidden)
+	rec = do(http.MethodPost, "/login", allowedOrigin, false, nil, http.StatusNoContent)
+	if rec.Header().Get("Access-Control-Allow-Origin") != allowedOrigin {
+		t.Fatalf("no CORS headers for allowed origin")
+	}
+	// Safe requests are served, but without CORS headers for other origins.
+	rec = do(http.MethodGet, "/csrf", "https://evil.example.com", true, nil, http.StatusOK)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("CORS headers for disallowed origin")
+	}
+
+	// Session requests from an allowed origin need the CSRF token.
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(do(http.MethodGet, "/csrf", allowedOrigin, true, nil, http.StatusOK).Body.Bytes(), &resp); err != nil {
+		t.Fatalf("error decoding CSRF token: %v", err)
+	}
+	do(http.MethodPost, "/logout", allowedOrigin, true, nil, http.StatusForbidden)
+	do(http.MethodPost, "/logout", allowedOrigin, true, map[string]string{csrfHeader: "abcd"}, http.StatusForbidden)
+	do(http.MethodPost, "/logout", allowedOrigin, true, map[string]string{csrfHeader: resp.Token}, http.StatusNoContent)
+
+	// Same-origin requests don't.
+	sessionToken = s.authorize()
+	do(http.MethodPost, "/logout", "http://127.0.0.1:5758", true, nil, http.StatusNoContent)
+
+	// Without HTTPS, session cookies aren't sent cross-site.
+	if s.cookieSameSite() != http.SameSiteStrictMode {
+		t.Fatalf("cross-site cookies without HTTPS")
+	}
+}
+
+func TestBinDepth(t *testing.T) {
+	book := &core.OrderBook{
+		Buys: []*core.MiniOrder{
+			{MsgRate: 990, QtyAtomic: 1},
+			{MsgRate: 980, QtyAtomic: 2}, // far edge of the first bin
+			{MsgRate: 979, QtyAtomic: 3},
+			{MsgRate: 500, QtyAtomic: 4}, // out of range
+		},
+		Sells: []*core.MiniOrder{
+			{MsgRate: 1010, QtyAtomic: 5},
+			{MsgRate: 1045, QtyAtomic: 6},
+		},
+	}
+	depth := binDepth(book, 5, 10)
+	if depth.MidRate != 1000 || depth.BinWidth != 20 {
+		t.Fatalf("wrong mid rate %d or bin width %d", depth.MidRate, depth.BinWidth)
+	}
+	check := func(bins []*v1DepthBin, exp [][3]uint64) {
+		t.Helper()
+		if len(bins) != len(exp) {
+			t.Fatalf("expected %d bins, got %d", len(exp), len(bins))
+		}
+		for i, bin := range bins {
+			if bin.Rate != exp[i][0] || bin.Qty != exp[i][1] || bin.Depth != exp[i][2] {
+				t.Fatalf("bin %d: expected %v, got %+v", i, exp[i], bin)
+			}
+		}
+	}
+	check(depth.Buys, [][3]uint64{{980, 3, 3}, {960, 3, 6}, {940, 0, 6}, {920, 0, 6}, {900, 0, 6}})
+	check(depth.Sells, [][3]uint64{{1020, 5, 5}, {1040, 0, 5}, {1060, 6, 11}, {1080, 0, 11}, {1100, 0, 11}})
+
+	if depth := binDepth(&core.OrderBook{}, 5, 10); depth.MidRate != 0 || len(depth.Buys) != 0 || len(depth.Sells) != 0 {
+		t.Fatalf("wrong depth for empty book")
+	}
+}
+
+func TestAggregateCandles(t *testing.T) {
+	if _, err := candleBinSize(90*time.Minute, []string{"24h", "1h", "5m"}); err != nil {
+		t.Fatalf("candleBinSize error: %v", err)
+	}
+	if binSize, _ := candleBinSize(2*time.Hour, []string{"24h", "1h", "5m"}); binSize != "1h" {
+		t.Fatalf("wrong bin size %q", binSize)
+	}
+	if _, err := candleBinSize(time.Minute, []string{"24h", "1h", "5m"}); err == nil {
+		t.Fatalf("no error for duration shorter than the bin sizes")
+	}
+
+	const hour = uint64(time.Hour / time.Millisecond)
+	cs := []msgjson.Candle{
+		{StartStamp: 0, EndStamp: hour, MatchVolume: 1, QuoteVolume: 10, HighRate: 5, LowRate: 3, StartRate: 4, EndRate: 4},
+		{StartStamp: hour, EndStamp: 2 * hour, MatchVolume: 2, QuoteVolume: 20, HighRate: 8, LowRate: 2, StartRate: 4, EndRate: 7},
+		{StartStamp: 2 * hour, EndStamp: 3 * hour, MatchVolume: 3, QuoteVolume: 30, HighRate: 9, LowRate: 6, StartRate: 7, EndRate: 6},
+		{StartStamp: 3 * hour, EndStamp: 3*hour + 100, MatchVolume: 4, QuoteVolume: 40, HighRate: 7, LowRate: 5, StartRate: 6, EndRate: 5},
+		{StartStamp: 4 * hour, EndStamp: 4*hour + 100, MatchVolume: 5, QuoteVolume: 50, HighRate: 5, LowRate: 5, StartRate: 5, EndRate: 5},
+	}
+	agg := aggregateCandles(cs, 2*hour)
+	exp := []*v1Candle{
+		{StartStamp: 0, EndStamp: 2 * hour, MatchVolume: 3, QuoteVolume: 30, HighRate: 8, LowRate: 2, StartRate: 4, EndRate: 7},
+		{StartStamp: 2 * hour, EndStamp: 4 * hour, MatchVolume: 7, QuoteVolume: 70, HighRate: 9, LowRate: 5, StartRate: 7, EndRate: 5},
+		{StartStamp: 4 * hour, EndStamp: 4*hour + 100, MatchVolume: 5, QuoteVolume: 50, HighRate: 5, LowRate: 5, StartRate: 5, EndRate: 5},
+	}
+	if len(agg) != len(exp) {
+		t.Fatalf("expected %d candles, got %d", len(exp), len(agg))
+	}
+	for i, c := range agg {
+		if *c != *exp[i] {
+			t.Fatalf("candle %d: expected %+v, got %+v", i, exp[i], c)
+		}
+	}
+}
+
+func TestAPIV1Export(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	tCore.isInited = true
+	authToken := s.authorize()
+
+	export := func(path string, wantStatus int) [][]string {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/v1/export"+path, nil)
+		req.AddCookie(&http.Cookie{Name: authCK, Value: authToken})
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("GET %s: wanted status %d, got %d: %s", path, wantStatus, rec.Code, rec.Body.String())
+		}
+		if wantStatus != http.StatusOK {
+			return nil
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "text/csv" {
+			t.Fatalf("GET %s: wrong content type %q", path, ct)
+		}
+		records, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("GET %s: error reading CSV: %v", path, err)
+		}
+		return records
+	}
+
+	const host = "somedex.com"
+	tCore.exchanges = map[string]*core.Exchange{host: {Host: host}}
+	// More orders than a page, newest first.
+	const nOrds = exportPageSize + 10
+	const newest = 1_700_000_000_000
+	for i := 0; i < nOrds; i++ {
+		oid := make(dex.Bytes, order.OrderIDSize)
+		binary.BigEndian.PutUint32(oid, uint32(i))
+		tCore.orders = append(tCore.orders, &core.Order{
+			Host:        host,
+			BaseID:      42,
+			BaseSymbol:  "dcr",
+			QuoteID:     0,
+			QuoteSymbol: "btc",
+			ID:          oid,
+			Type:        order.LimitOrderType,
+			Stamp:       newest - uint64(i)*1000,
+			Qty:         1e8,
+			Rate:        1e6,
+			FeesPaid:    &core.FeeBreakdown{},
+			Matches: []*core.Match{{
+				MatchID: oid,
+				Qty:     1e8,
+				Rate:    1e6,
+				Swap:    &core.Coin{StringID: "swapcoin"},
+			}, {
+				IsCancel: true,
+			}},
+		})
+	}
+
+	records := export("/orders", http.StatusOK)
+	if len(records) != nOrds+1 {
+		t.Fatalf("expected %d orders, got %d records", nOrds, len(records))
+	}
+	if records[0][0] != "Host" || records[1][1] != tCore.orders[0].ID.String() ||
+		records[nOrds][1] != tCore.orders[nOrds-1].ID.String() {
+		t.Fatalf("wrong orders export")
+	}
+	records = export(fmt.Sprintf("/orders?since=%d&until=%d", newest-5000, newest-1000), http.StatusOK)
+	if len(records) != 6 || records[1][1] != tCore.orders[1].ID.String() {
+		t.Fatalf("wrong orders in time range: %v", records)
+	}
+	export("/orders?since=2&until=1", http.StatusBadRequest)
+	export("/orders?status=lost", http.StatusBadRequest)
+
+	records = export(fmt.Sprintf("/matches?since=%d", newest-1000), http.StatusOK)
+	if len(records) != 3 || records[1][11] != "swapcoin" {
+		t.Fatalf("wrong matches: %v", records)
+	}
+
+	tCore.txs = []*asset.WalletTransaction{{
+		Type:        asset.Receive,
+		ID:          "tx1",
+		Amount:      5e8,
+		Timestamp:   newest / 1000,
+		BlockNumber: 100,
+	}, {
+		Type:      asset.Send,
+		ID:        "tx2",
+		Amount:    1e8,
+		Timestamp: 1,
+	}}
+	records = export(fmt.Sprintf("/transactions/42?since=%d", newest), http.StatusOK)
+	if len(records) != 3 || records[1][0] != "tx1" || records[1][1] != "Receive" || records[2][0] != "tx2" {
+		t.Fatalf("wrong transactions: %v", records)
+	}
+	records = export(fmt.Sprintf("/transactions/42?since=%d", newest+1000), http.StatusOK)
+	if len(records) != 2 || records[1][0] != "tx2" {
+		t.Fatalf("wrong transactions with unconfirmed: %v", records)
+	}
+	tCore.notHas = true
+	export("/transactions/42", http.StatusNotFound)
+}
+
+func TestAPIV1Tickers(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	tCore.isInited = true
+	authToken := s.authorize()
+
+	getTickers := func(path string) []*v1Ticker {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/api/v1/tickers"+path, nil)
+		req.AddCookie(&http.Cookie{Name: authCK, Value: authToken})
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: wanted status 200, got %d: %s", path, rec.Code, rec.Body.String())
+		}
+		var tickers []*v1Ticker
+		if err := json.NewDecoder(rec.Body).Decode(&tickers); err != nil {
+			t.Fatalf("GET %s: error decoding tickers: %v", path, err)
+		}
+		return tickers
+	}
+
+	if tickers := getTickers(""); len(tickers) != 0 {
+		t.Fatalf("expected no tickers, got %d", len(tickers))
+	}
+
+	tCore.exchanges = map[string]*core.Exchange{
+		"b.com": {
+			Host:             "b.com",
+			ConnectionStatus: comms.Connected,
+			Markets: map[string]*core.Market{
+				"dcr_btc": {
+					Name:           "dcr_btc",
+					BaseID:         42,
+					BaseSymbol:     "dcr",
+					QuoteSymbol:    "btc",
+					SpotPrice:      &msgjson.Spot{Stamp: 1, Rate: 1e6, Change24: 0.1, Vol24: 5e8},
+					InFlightOrders: []*core.InFlightOrder{{}},
+					Orders: []*core.Order{
+						{Status: order.OrderStatusBooked},
+						{Status: order.OrderStatusEpoch},
+						{Status: order.OrderStatusExecuted},
+					},
+				},
+			},
+		},
+		"a.com": {
+			Host:             "a.com",
+			ConnectionStatus: comms.Connected,
+			Markets: map[string]*core.Market{
+				"eth_btc": {Name: "eth_btc", BaseID: 60, BaseSymbol: "eth", QuoteSymbol: "btc"},
+				"dcr_btc": {Name: "dcr_btc", BaseID: 42, BaseSymbol: "dcr", QuoteSymbol: "btc"},
+			},
+		},
+		"down.com": {
+			Host:             "down.com",
+			ConnectionStatus: comms.Disconnected,
+			Markets: map[string]*core.Market{
+				"dcr_btc": {Name: "dcr_btc", BaseID: 42, BaseSymbol: "dcr", QuoteSymbol: "btc"},
+			},
+		},
+	}
+
+	tickers := getTickers("?spread=false")
+	if len(tickers) != 3 {
+		t.Fatalf("expected 3 tickers, got %d", len(tickers))
+	}
+	if tickers[0].Host != "a.com" || tickers[0].Name != "dcr_btc" || tickers[1].Name != "eth_btc" || tickers[2].Host != "b.com" {
+		t.Fatalf("tickers not sorted")
+	}
+	tk := tickers[2]
+	if tk.Rate != 1e6 || tk.Change24 != 0.1 || tk.Vol24 != 5e8 || tk.Stamp != 1 {
+		t.Fatalf("wrong spot price stats: %+v", tk)
+	}
+	if tk.MyOrders != 3 {
+		t.Fatalf("expected 3 active orders, got %d", tk.MyOrders)
+	}
+	if len(getTickers("")) != 3 {
+		t.Fatalf("wrong number of tickers with spread")
+	}
+}
+
+func TestParseV1MMEventFilter(t *testing.T) {
+	q := url.Values{
+		"bot":  {"dex.example.com:7232-42-0", "my-dex.com-60-0"},
+		"type": {"epochreport", "runevent"},
+	}
+	filter, err := parseV1MMEventFilter(q)
+	if err != nil {
+		t.Fatalf("parseV1MMEventFilter error: %v", err)
+	}
+	if len(filter.Bots) != 2 || len(filter.Types) != 2 {
+		t.Fatalf("wrong filter %+v", filter)
+	}
+	if b := filter.Bots[0]; b.Host != "dex.example.com:7232" || b.BaseID != 42 || b.QuoteID != 0 {
+		t.Fatalf("wrong first bot %+v", b)
+	}
+	if b := filter.Bots[1]; b.Host != "my-dex.com" || b.BaseID != 60 {
+		t.Fatalf("wrong second bot %+v", b)
+	}
+	for _, bot := range []string{"dex.com-42", "dex.com-x-0", "dex.com-42-y"} {
+		if _, err := parseV1MMEventFilter(url.Values{"bot": {bot}}); err == nil {
+			t.Fatalf("no error for bot %q", bot)
+		}
+	}
+}
+
+func TestBestRates(t *testing.T) {
+	book := &core.OrderBook{
+		Buys:  []*core.MiniOrder{{MsgRate: 5}, {MsgRate: 7}, {MsgRate: 6}},
+		Sells: []*core.MiniOrder{{MsgRate: 10}, {MsgRate: 9}, {MsgRate: 11}},
+	}
+	if bestBuy, bestSell := bestRates(book); bestBuy != 7 || bestSell != 9 {
+		t.Fatalf("wrong best rates %d, %d", bestBuy, bestSell)
+	}
+	if bestBuy, bestSell := bestRates(&core.OrderBook{}); bestBuy != 0 || bestSell != 0 {
+		t.Fatalf("wrong best rates for empty book %d, %d", bestBuy, bestSell)
+	}
+}
+
+func TestAPIV1Keys(t *testing.T) {
+	s, tCore, shutdown := newTServer(t, false)
+	defer shutdown()
+	tCore.isInited = true
+	s.dataDir = t.TempDir()
+	sessionToken := s.authorize()
+
+	do := func(method, path, bearer string, body any, wantStatus int) []byte {
+		t.Helper()
+		var reqBody io.Reader
+		if body != nil {
+			b, _ := json.Marshal(body)
+			reqBody = bytes.NewReader(b)
+		}
+		req := httptest.NewRequest(method, "/api/v1"+path, reqBody)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		} else {
+			req.AddCookie(&http.Cookie{Name: authCK, Value: sessionToken})
+		}
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != wantStatus {
+			t.Fatalf("%s %s: wanted status %d, got %d: %s", method, path, wantStatus, rec.Code, rec.Body.String())
+		}
+		return rec.Body.Bytes()
+	}
+
+	newKey := func(scopes ...apiKeyScope) (id, token string) {
+		t.Helper()
+		var resp struct {
+			ID    string `json:"id"`
+			Token string `json:"token"`
+		}
+		form := map[string]any{"name": "bot", "scopes": scopes, "pass": "pass"}
+		if err := json.Unmarshal(do("POST", "/keys", "", form, http.StatusCreated), &resp); err != nil {
+			t.Fatalf("error decoding new key: %v", err)
+		}
+		return resp.ID, resp.Token
+	}
+
+	// Bad requests.
+	do("POST", "/keys", "", map[string]any{"scopes": []string{"read"}}, http.StatusBadRequest)
+	do("POST", "/keys", "", map[string]any{"scopes": []string{}, "pass": "pass"}, http.StatusBadRequest)
+	do("POST", "/keys", "", map[string]any{"scopes": []string{"admin"}, "pass": "pass"}, http.StatusBadRequest)
+	do("POST", "/keys", "", map[string]any{"scopes": []string{"read"}, "pass": "pass", "expiry": 1}, http.StatusBadRequest)
+	tCore.loginErr = tErr
+	do("POST", "/keys", "", map[string]any{"scopes": []string{"read"}, "pass": "pass"}, http.StatusUnauthorized)
+	tCore.loginErr = nil
+
+	readID, readToken := newKey(scopeRead)
+	_, sendToken := newKey(scopeRead, scopeWalletSend)
+	// The password is only stored for scopes that need it.
+	if len(s.apiKeyForToken(readToken).EncryptedPass) != 0 {
+		t.Fatalf("password stored for a read-only key")
+	}
+	if len(s.apiKeyForToken(sendToken).EncryptedPass) == 0 {
+		t.Fatalf("password not stored for a wallet-send key")
+	}
+
+	do("GET", "/wallets", readToken, nil, http.StatusOK)
+	do("GET", "/wallets", "deadbeef", nil, http.StatusUnauthorized)
+	// Missing scopes.
+	do("POST", "/orders", readToken, &v1TradeForm{Type: "limit"}, http.StatusForbidden)
+	do("POST", "/wallets/42/send", readToken, map[string]any{"address": "addr", "value": 1}, http.StatusForbidden)
+	// The stored password is used for sends.
+	do("POST", "/wallets/42/send", sendToken, map[string]any{"address": "addr", "value": 1}, http.StatusOK)
+	// Keys can't be managed with keys.
+	do("GET", "/keys", sendToken, nil, http.StatusForbidden)
+	do("POST", "/logout", sendToken, nil, http.StatusForbidden)
+	// Nor can settings.
+	settings := &db.ExplorerSettings{PreferOnion: true}
+	do("GET", "/settings/explorers", readToken, nil, http.StatusOK)
+	do("PUT", "/settings/explorers", sendToken, settings, http.StatusForbidden)
+	do("PUT", "/settings/explorers", "", settings, http.StatusOK)
+	if tCore.explorers == nil || !tCore.explorers.PreferOnion {
+		t.Fatalf("explorer settings not updated")
+	}
+	tCore.explorersErr = tErr
+	do("PUT", "/settings/explorers", "", settings, http.StatusBadRequest)
+	torSettings := &db.TorSettings{Enabled: true}
+	do("GET", "/settings/tor", readToken, nil, http.StatusOK)
+	do("PUT", "/settings/tor", sendToken, torSettings, http.StatusForbidden)
+	do("PUT", "/settings/tor", "", torSettings, http.StatusOK)
+	if tCore.torSettings == nil || !tCore.torSettings.Enabled {
+		t.Fatalf("tor settings not updated")
+	}
+	proxySettings := &db.ProxySettings{Hosts: map[string]*db.ProxyConfig{"dex.example.com:7232": {SOCKSAddr: "127.0.0.1:1080"}}}
+	do("GET", "/settings/proxies", readToken, nil, http.StatusOK)
+	do("PUT", "/settings/proxies", sendToken, proxySettings, http.StatusForbidden)
+	do("PUT", "/settings/proxies", "", proxySettings, http.StatusOK)
+	if tCore.proxySettings == nil || tCore.proxySettings.Hosts["dex.example.com:7232"] == nil {
+		t.Fatalf("proxy settings not updated")
+	}
+	do("PUT", "/settings/proxies", "", &db.ProxySettings{Services: map[string]*db.ProxyConfig{"": {}}}, http.StatusBadRequest)
+	retention := &db.RetentionSettings{MaxAgeDays: 90, Archive: true}
+	do("GET", "/settings/retention", readToken, nil, http.StatusOK)
+	do("PUT", "/settings/retention", sendToken, retention, http.StatusForbidden)
+	do("PUT", "/settings/retention", "", retention, http.StatusOK)
+	if tCore.retention == nil || tCore.retention.MaxAgeDays != 90 {
+		t.Fatalf("retention settings not updated")
+	}
+	do("PUT", "/settings/retention", "", &db.RetentionSettings{MaxOrders: -1}, http.StatusBadRequest)
+
+	var keys []*v1APIKey
+	if err := json.Unmarshal(do("GET", "/keys", "", nil, http.StatusOK), &keys); err != nil || len(keys) != 2 {
+		t.Fatalf("wrong keys listed: %d, %v", len(keys), err)
+	}
+
+	// Keys are saved.
+	s.apiKeys = make(map[string]*apiKey)
+	if err := s.loadAPIKeys(); err != nil {
+		t.Fatalf("error loading keys: %v", err)
+	}
+	do("GET", "/wallets", readToken, nil, http.StatusOK)
+
+	// Expired.
+	s.apiKeyForToken(readToken).Expiry = time.Now().Unix() - 1
+	do("GET", "/wallets", readToken, nil, http.StatusUnauthorized)
+
+	do("DELETE", "/keys/"+readID, "", nil, http.StatusNoContent)
+	do("DELETE", "/keys/"+readID, "", nil, http.StatusNotFound)
+	do("GET", "/wallets", sendToken, nil, http.StatusOK)
+}
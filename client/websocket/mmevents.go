@@ -0,0 +1,178 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/ws"
+)
+
+const (
+	// mmEventRoute is the route of the notifications sent to market making
+	// event stream clients.
+	mmEventRoute = "mmevent"
+	// mmFilterRoute is the request route for changing the filter of a market
+	// making event stream.
+	mmFilterRoute = "mmfilter"
+)
+
+// BotNote is a market making notification about a bot. The bot notifications
+// from the mm package satisfy BotNote.
+type BotNote interface {
+	core.Notification
+	BotMarket() (host string, baseID, quoteID uint32)
+}
+
+// BotMarket identifies a bot by its market.
+type BotMarket struct {
+	Host    string `json:"host"`
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+}
+
+// MMEventFilter selects the notifications sent to a market making event stream
+// client.
+type MMEventFilter struct {
+	// Bots are the bots to send notifications for. If empty, notifications
+	// are sent for every bot.
+	Bots []*BotMarket `json:"bots"`
+	// Types are the notification types to send, e.g. epochreport or
+	// runevent. If empty, every type is sent.
+	Types []string `json:"types"`
+}
+
+// match checks whether the notification passes the filter.
+func (f *MMEventFilter) match(n BotNote) bool {
+	if len(f.Types) > 0 {
+		var found bool
+		for _, t := range f.Types {
+			if t == n.Type() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Bots) == 0 {
+		return true
+	}
+	host, baseID, quoteID := n.BotMarket()
+	for _, b := range f.Bots {
+		if b.Host == host && b.BaseID == baseID && b.QuoteID == quoteID {
+			return true
+		}
+	}
+	return false
+}
+
+// mmClient is a websocket client for a market making event stream.
+type mmClient struct {
+	*wsClient
+
+	filterMtx sync.RWMutex
+	filter    *MMEventFilter
+}
+
+func (cl *mmClient) setFilter(filter *MMEventFilter) {
+	cl.filterMtx.Lock()
+	cl.filter = filter
+	cl.filterMtx.Unlock()
+}
+
+func (cl *mmClient) match(n BotNote) bool {
+	cl.filterMtx.RLock()
+	defer cl.filterMtx.RUnlock()
+	return cl.filter.match(n)
+}
+
+// handleMessage handles a request from a market making event stream client.
+// The only request is to change the filter.
+func (cl *mmClient) handleMessage(msg *msgjson.Message) *msgjson.Error {
+	if msg.Type != msgjson.Request || msg.Route != mmFilterRoute {
+		return msgjson.NewError(msgjson.UnknownMessageType, "market making event streams only handle %s requests", mmFilterRoute)
+	}
+	filter := new(MMEventFilter)
+	if err := msg.Unmarshal(filter); err != nil {
+		return msgjson.NewError(msgjson.RPCParseError, "error unmarshalling %s payload: %v", mmFilterRoute, err)
+	}
+	cl.setFilter(filter)
+	return nil
+}
+
+// HandleMMEventsConnect is like HandleConnect, but the client is a market
+// making event stream, and only receives the bot notifications passed to
+// NotifyMM that match the filter. The client can change the filter with an
+// mmfilter request.
+func (s *Server) HandleMMEventsConnect(ctx context.Context, w http.ResponseWriter, r *http.Request, filter *MMEventFilter) {
+	wsConn, err := ws.NewCompressedConnection(w, r, pongWait)
+	if err != nil {
+		s.log.Errorf("ws connection error: %v", err)
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.connectMM(ctx, wsConn, r.RemoteAddr, filter)
+	}()
+}
+
+// connectMM is like connect for market making event stream clients.
+func (s *Server) connectMM(ctx context.Context, conn ws.Connection, addr string, filter *MMEventFilter) {
+	s.log.Debugf("New market making event stream client %s", addr)
+	cl := &mmClient{filter: filter}
+	cl.wsClient = newWSClient(addr, conn, func(msg *msgjson.Message) *msgjson.Error {
+		return cl.handleMessage(msg)
+	}, s.log.SubLogger(addr))
+
+	s.clientsMtx.Lock()
+	cm := dex.NewConnectionMaster(cl)
+	if err := cm.ConnectOnce(ctx); err != nil {
+		s.clientsMtx.Unlock()
+		s.log.Errorf("market making event stream client connect: %v", err)
+		return
+	}
+	s.mmClients[cl.cid] = cl
+	s.clientsMtx.Unlock()
+
+	defer func() {
+		s.clientsMtx.Lock()
+		delete(s.mmClients, cl.cid)
+		s.clientsMtx.Unlock()
+	}()
+
+	cm.Wait()
+	s.log.Tracef("Disconnected market making event stream client %s", addr)
+}
+
+// NotifyMM sends the bot notification to the market making event stream
+// clients with a matching filter.
+func (s *Server) NotifyMM(n BotNote) {
+	s.clientsMtx.RLock()
+	defer s.clientsMtx.RUnlock()
+	if len(s.mmClients) == 0 {
+		return
+	}
+	msg, err := msgjson.NewNotification(mmEventRoute, n)
+	if err != nil {
+		s.log.Errorf("%q notification encoding error: %v", mmEventRoute, err)
+		return
+	}
+	for _, cl := range s.mmClients {
+		if !cl.match(n) {
+			continue
+		}
+		if err = cl.Send(msg); err != nil {
+			s.log.Warnf("Failed to send %v notification to client %v at %v: %v",
+				msg.Route, cl.cid, cl.Addr(), err)
+		}
+	}
+}
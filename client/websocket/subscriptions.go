@@ -0,0 +1,348 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package websocket
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/msgjson"
+)
+
+const (
+	// subscribeRoute is the request route for adding a notification
+	// subscription.
+	subscribeRoute = "subscribe"
+	// unsubscribeRoute is the request route for removing a notification
+	// subscription.
+	unsubscribeRoute = "unsubscribe"
+	// subNoteRoute is the route of the notifications sent to clients with a
+	// matching subscription.
+	subNoteRoute = "subnote"
+)
+
+// noteLogSize is the number of sequenced notifications kept for clients
+// resuming their subscriptions after reconnecting.
+var noteLogSize = 1024
+
+// NoteMarket identifies a market on a DEX host.
+type NoteMarket struct {
+	Host    string `json:"host"`
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+}
+
+// NoteFilter selects the notifications sent for a subscription. Each non-empty
+// field must match. Notifications that are not about a host or market, such
+// as balance updates, never match a filter with Hosts or Markets.
+type NoteFilter struct {
+	// Topics are the notification topics, e.g. OrderBooked or
+	// BalanceUpdated.
+	Topics []core.Topic `json:"topics"`
+	// Types are the notification types, e.g. order or balance.
+	Types []string `json:"types"`
+	// Hosts are the DEX hosts.
+	Hosts []string `json:"hosts"`
+	// Markets are the DEX markets.
+	Markets []*NoteMarket `json:"markets"`
+}
+
+// subscribeRequest is the payload of a subscribe request. Stream and Since
+// are set by clients resuming a subscription after reconnecting, and are the
+// stream and the sequence number of the last notification received.
+type subscribeRequest struct {
+	NoteFilter
+	Stream string `json:"stream"`
+	Since  uint64 `json:"since"`
+}
+
+// subscribeResult is the result of a subscribe request.
+type subscribeResult struct {
+	// ID identifies the subscription in subnote notifications and
+	// unsubscribe requests.
+	ID uint32 `json:"id"`
+	// Stream identifies the sequence of notifications. Sequence numbers from
+	// a different stream, e.g. from before a restart, cannot be resumed.
+	Stream string `json:"stream"`
+	// Seq is the sequence number of the last notification.
+	Seq uint64 `json:"seq"`
+	// Complete is false if the subscription was resumed but some
+	// notifications since the requested sequence number are no longer
+	// available.
+	Complete bool `json:"complete"`
+}
+
+// unsubscribeRequest is the payload of an unsubscribe request.
+type unsubscribeRequest struct {
+	ID uint32 `json:"id"`
+}
+
+// SubNote is the payload of a subnote notification.
+type SubNote struct {
+	// Subs are the IDs of the client's matching subscriptions.
+	Subs []uint32          `json:"subs"`
+	Seq  uint64            `json:"seq"`
+	Note core.Notification `json:"note"`
+}
+
+// seqNote is a notification with its sequence number.
+type seqNote struct {
+	seq       uint64
+	note      core.Notification
+	host, mkt string
+}
+
+func newStreamID() string {
+	return hex.EncodeToString(encode.RandomBytes(8))
+}
+
+// noteHostMarket gets the host and market name the notification is about. The
+// market name is empty for notifications that are not about a market.
+func noteHostMarket(n core.Notification) (host, mkt string) {
+	switch nt := n.(type) {
+	case *core.OrderNote:
+		if nt.Order != nil {
+			return nt.Order.Host, nt.Order.MarketID
+		}
+	case *core.MatchNote:
+		return nt.Host, nt.MarketID
+	case *core.EpochNotification:
+		return nt.Host, nt.MarketID
+	case *core.FeePaymentNote:
+		return nt.Dex, ""
+	case *core.BondPostNote:
+		return nt.Dex, ""
+	case *core.ConnEventNote:
+		return nt.Host, ""
+	case *core.SpotPriceNote:
+		return nt.Host, ""
+	case *core.DEXAuthNote:
+		return nt.Host, ""
+	case *core.ServerConfigUpdateNote:
+		return nt.Host, ""
+	case *core.ReputationNote:
+		return nt.Host, ""
+	case BotNote:
+		host, baseID, quoteID := nt.BotMarket()
+		mkt, _ := dex.MarketName(baseID, quoteID)
+		return host, mkt
+	}
+	return "", ""
+}
+
+// match checks whether the notification passes the filter.
+func (f *NoteFilter) match(sn *seqNote) bool {
+	if len(f.Topics) > 0 {
+		var found bool
+		for _, t := range f.Topics {
+			if t == sn.note.Topic() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Types) > 0 {
+		var found bool
+		for _, t := range f.Types {
+			if t == sn.note.Type() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Hosts) > 0 {
+		var found bool
+		for _, h := range f.Hosts {
+			if sn.host != "" && h == sn.host {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Markets) > 0 {
+		if sn.mkt == "" {
+			return false
+		}
+		var found bool
+		for _, m := range f.Markets {
+			if m.Host != sn.host {
+				continue
+			}
+			if mkt, err := dex.MarketName(m.BaseID, m.QuoteID); err == nil && mkt == sn.mkt {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchingSubs returns the IDs of the client's subscriptions matching the
+// notification.
+func (cl *wsClient) matchingSubs(sn *seqNote) []uint32 {
+	cl.subsMtx.RLock()
+	defer cl.subsMtx.RUnlock()
+	var ids []uint32
+	for id, f := range cl.subs {
+		if f.match(sn) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// sendSubNote sends the notification to the client for the subscriptions.
+func (s *Server) sendSubNote(cl *wsClient, subs []uint32, sn *seqNote) {
+	msg, err := msgjson.NewNotification(subNoteRoute, &SubNote{
+		Subs: subs,
+		Seq:  sn.seq,
+		Note: sn.note,
+	})
+	if err != nil {
+		s.log.Errorf("%q notification encoding error: %v", subNoteRoute, err)
+		return
+	}
+	if err = cl.Send(msg); err != nil {
+		s.log.Warnf("Failed to send %v notification to client %v at %v: %v",
+			msg.Route, cl.cid, cl.Addr(), err)
+	}
+}
+
+// NotifySubscribers assigns the notification the next sequence number and
+// sends it to the clients with a matching subscription. The most recent
+// notifications are kept so that clients can resume their subscriptions
+// after reconnecting.
+func (s *Server) NotifySubscribers(n core.Notification) {
+	host, mkt := noteHostMarket(n)
+
+	s.noteMtx.Lock()
+	defer s.noteMtx.Unlock()
+	s.noteSeq++
+	sn := &seqNote{
+		seq:  s.noteSeq,
+		note: n,
+		host: host,
+		mkt:  mkt,
+	}
+	s.noteLog = append(s.noteLog, sn)
+	if len(s.noteLog) > noteLogSize {
+		s.noteLog = s.noteLog[len(s.noteLog)-noteLogSize:]
+	}
+
+	s.clientsMtx.RLock()
+	defer s.clientsMtx.RUnlock()
+	for _, cl := range s.clients {
+		if subs := cl.matchingSubs(sn); len(subs) > 0 {
+			s.sendSubNote(cl, subs, sn)
+		}
+	}
+}
+
+// wsSubscribe is the handler for the 'subscribe' websocket route. It adds a
+// notification subscription and responds with the subscription ID. If the
+// client is resuming, the logged notifications since the requested sequence
+// number that match the filter are sent after the response.
+func wsSubscribe(s *Server, cl *wsClient, msg *msgjson.Message) *msgjson.Error {
+	req := new(subscribeRequest)
+	if err := json.Unmarshal(msg.Payload, req); err != nil {
+		return msgjson.NewError(msgjson.RPCParseError, "error unmarshalling %s payload: %v", subscribeRoute, err)
+	}
+	filter := &req.NoteFilter
+
+	// Hold the note lock until the logged notifications are queued so that
+	// new notifications are sent after them.
+	s.noteMtx.Lock()
+	defer s.noteMtx.Unlock()
+
+	cl.subsMtx.Lock()
+	cl.subID++
+	id := cl.subID
+	cl.subs[id] = filter
+	cl.subsMtx.Unlock()
+
+	res := &subscribeResult{
+		ID:       id,
+		Stream:   s.stream,
+		Seq:      s.noteSeq,
+		Complete: true,
+	}
+	var replay []*seqNote
+	if req.Stream != "" {
+		since := req.Since
+		if req.Stream != s.stream {
+			// Everything in the log is new to the client, and there may
+			// have been notifications before it.
+			since = 0
+			res.Complete = false
+		}
+		for _, sn := range s.noteLog {
+			if sn.seq > since && filter.match(sn) {
+				replay = append(replay, sn)
+			}
+		}
+		if len(s.noteLog) > 0 && s.noteLog[0].seq > since+1 {
+			res.Complete = false
+		}
+	}
+
+	resp, err := msgjson.NewResponse(msg.ID, res, nil)
+	if err != nil {
+		return msgjson.NewError(msgjson.RPCInternal, "error encoding %s response: %v", subscribeRoute, err)
+	}
+	if err = cl.Send(resp); err != nil {
+		s.log.Warnf("Failed to send %s response to client %v at %v: %v", subscribeRoute, cl.cid, cl.Addr(), err)
+		return nil
+	}
+	for _, sn := range replay {
+		s.sendSubNote(cl, []uint32{id}, sn)
+	}
+	return nil
+}
+
+// wsUnsubscribe is the handler for the 'unsubscribe' websocket route. It
+// removes a notification subscription.
+func wsUnsubscribe(s *Server, cl *wsClient, msg *msgjson.Message) *msgjson.Error {
+	req := new(unsubscribeRequest)
+	if err := json.Unmarshal(msg.Payload, req); err != nil {
+		return msgjson.NewError(msgjson.RPCParseError, "error unmarshalling %s payload: %v", unsubscribeRoute, err)
+	}
+	cl.subsMtx.Lock()
+	_, found := cl.subs[req.ID]
+	delete(cl.subs, req.ID)
+	cl.subsMtx.Unlock()
+	if !found {
+		return msgjson.NewError(msgjson.RPCArgumentsError, "unknown subscription %d", req.ID)
+	}
+	resp, err := msgjson.NewResponse(msg.ID, true, nil)
+	if err != nil {
+		return msgjson.NewError(msgjson.RPCInternal, "error encoding %s response: %v", unsubscribeRoute, err)
+	}
+	if err = cl.Send(resp); err != nil {
+		s.log.Warnf("Failed to send %s response to client %v at %v: %v", unsubscribeRoute, cl.cid, cl.Addr(), err)
+	}
+	return nil
+}
+
+// Match checks whether the notification passes the filter.
+func (f *NoteFilter) Match(n core.Notification) bool {
+	host, mkt := noteHostMarket(n)
+	return f.match(&seqNote{note: n, host: host, mkt: mkt})
+}
@@ -45,12 +45,17 @@ type wsClient struct {
 
 	feedMtx sync.RWMutex
 	feed    *bookFeed
+
+	subsMtx sync.RWMutex
+	subID   uint32
+	subs    map[uint32]*NoteFilter
 }
 
 func newWSClient(addr string, conn ws.Connection, hndlr func(msg *msgjson.Message) *msgjson.Error, logger dex.Logger) *wsClient {
 	return &wsClient{
 		WSLink: ws.NewWSLink(addr, conn, pingPeriod, hndlr, logger),
 		cid:    atomic.AddInt32(&cidCounter, 1),
+		subs:   make(map[uint32]*NoteFilter),
 	}
 }
 
@@ -78,14 +83,22 @@ type Server struct {
 
 	clientsMtx sync.RWMutex
 	clients    map[int32]*wsClient
+	mmClients  map[int32]*mmClient
+
+	noteMtx sync.Mutex
+	stream  string
+	noteSeq uint64
+	noteLog []*seqNote
 }
 
 // New returns a new websocket Server.
 func New(core Core, log dex.Logger) *Server {
 	return &Server{
-		core:    core,
-		log:     log,
-		clients: make(map[int32]*wsClient),
+		core:      core,
+		log:       log,
+		clients:   make(map[int32]*wsClient),
+		mmClients: make(map[int32]*mmClient),
+		stream:    newStreamID(),
 	}
 }
 
@@ -96,6 +109,9 @@ func (s *Server) Shutdown() {
 	for _, cl := range s.clients {
 		cl.Disconnect()
 	}
+	for _, cl := range s.mmClients {
+		cl.Disconnect()
+	}
 	s.clientsMtx.Unlock()
 	// Each upgraded connection handler must return. This also waits for running
 	// marketSyncers and response handlers as long as dex/ws.(*WSLink) operates
@@ -110,7 +126,9 @@ func (s *Server) Shutdown() {
 // able to cancel the hijacked connection handler at a later time since this
 // function is not blocking.
 func (s *Server) HandleConnect(ctx context.Context, w http.ResponseWriter, r *http.Request) {
-	wsConn, err := ws.NewConnection(w, r, pongWait)
+	// Book feeds for busy markets can use a lot of bandwidth, so compress if
+	// the browser supports it.
+	wsConn, err := ws.NewCompressedConnection(w, r, pongWait)
 	if err != nil {
 		s.log.Errorf("ws connection error: %v", err)
 		return
@@ -211,10 +229,12 @@ type wsHandler func(*Server, *wsClient, *msgjson.Message) *msgjson.Error
 // wsHandlers is the map used by the server to locate the router handler for a
 // request.
 var wsHandlers = map[string]wsHandler{
-	"loadmarket":  wsLoadMarket,
-	"loadcandles": wsLoadCandles,
-	"unmarket":    wsUnmarket,
-	"acknotes":    wsAckNotes,
+	"loadmarket":     wsLoadMarket,
+	"loadcandles":    wsLoadCandles,
+	"unmarket":       wsUnmarket,
+	"acknotes":       wsAckNotes,
+	subscribeRoute:   wsSubscribe,
+	unsubscribeRoute: wsUnsubscribe,
 }
 
 // marketLoad is sent by websocket clients to subscribe to a market and request
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/orderbook"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
@@ -302,3 +303,284 @@ func TestClientMap(t *testing.T) {
 		t.Fatal("connection not closed on server shutdown")
 	}
 }
+
+type tBotNote struct {
+	db.Notification
+	Host    string `json:"host"`
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+}
+
+func (n *tBotNote) BotMarket() (string, uint32, uint32) {
+	return n.Host, n.BaseID, n.QuoteID
+}
+
+func newTBotNote(noteType, host string, baseID, quoteID uint32) *tBotNote {
+	return &tBotNote{
+		Notification: db.NewNotification(noteType, "", "", "", db.Data),
+		Host:         host,
+		BaseID:       baseID,
+		QuoteID:      quoteID,
+	}
+}
+
+func TestMMEvents(t *testing.T) {
+	srv, _ := newTServer()
+	resp := make(chan []byte, 1)
+	conn := &TConn{
+		respReady: resp,
+		close:     make(chan struct{}, 1),
+	}
+	// msg.ID == 0 gets an error response, which can be discarded.
+	read, _ := json.Marshal(msgjson.Message{ID: 0})
+	conn.addRead(read)
+
+	ctx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		srv.connectMM(ctx, conn, "127.0.0.1", &MMEventFilter{
+			Bots:  []*BotMarket{{Host: "dex.com", BaseID: 42, QuoteID: 0}},
+			Types: []string{"epochreport"},
+		})
+	}()
+	<-resp
+
+	srv.clientsMtx.RLock()
+	if len(srv.clients) != 0 || len(srv.mmClients) != 1 {
+		t.Fatalf("expected 1 MM client and no other clients, found %d and %d", len(srv.mmClients), len(srv.clients))
+	}
+	var cl *mmClient
+	for _, c := range srv.mmClients {
+		cl = c
+	}
+	srv.clientsMtx.RUnlock()
+
+	// Check that the note is sent or not.
+	checkSent := func(n *tBotNote, expSent bool) {
+		t.Helper()
+		srv.NotifyMM(n)
+		// Sends are asynchronous.
+		timeout := 50 * time.Millisecond
+		if expSent {
+			timeout = 2 * time.Second
+		}
+		select {
+		case b := <-resp:
+			if !expSent {
+				t.Fatalf("%s note for %s-%d-%d sent", n.Type(), n.Host, n.BaseID, n.QuoteID)
+			}
+			msg, err := msgjson.DecodeMessage(b)
+			if err != nil || msg.Route != mmEventRoute {
+				t.Fatalf("wrong message %s, err = %v", string(b), err)
+			}
+			var sent tBotNote
+			if err := msg.Unmarshal(&sent); err != nil || sent.Host != n.Host {
+				t.Fatalf("wrong note %+v, err = %v", sent, err)
+			}
+		case <-time.After(timeout):
+			if expSent {
+				t.Fatalf("%s note for %s-%d-%d not sent", n.Type(), n.Host, n.BaseID, n.QuoteID)
+			}
+		}
+	}
+	checkSent(newTBotNote("epochreport", "dex.com", 42, 0), true)
+	checkSent(newTBotNote("runevent", "dex.com", 42, 0), false)
+	checkSent(newTBotNote("epochreport", "dex.com", 60, 0), false)
+	checkSent(newTBotNote("epochreport", "other.com", 42, 0), false)
+
+	// Change the filter to all bots and types.
+	filterMsg, _ := msgjson.NewRequest(1, mmFilterRoute, &MMEventFilter{})
+	if msgErr := cl.handleMessage(filterMsg); msgErr != nil {
+		t.Fatalf("error changing filter: %v", msgErr)
+	}
+	checkSent(newTBotNote("runevent", "other.com", 60, 0), true)
+	// Only mmfilter requests are handled.
+	loadMsg, _ := msgjson.NewRequest(2, "loadmarket", &marketLoad{})
+	if msgErr := cl.handleMessage(loadMsg); msgErr == nil {
+		t.Fatalf("no error for loadmarket request")
+	}
+
+	shutdown()
+	wg.Wait()
+	srv.clientsMtx.RLock()
+	defer srv.clientsMtx.RUnlock()
+	if len(srv.mmClients) != 0 {
+		t.Fatalf("MM client not removed")
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	srv, _ := newTServer()
+	resp := make(chan []byte, 16)
+	conn := &TConn{
+		respReady: resp,
+		close:     make(chan struct{}, 1),
+	}
+	cl := newWSClient("127.0.0.1", conn, func(*msgjson.Message) *msgjson.Error { return nil }, dex.StdOutLogger("ws_TEST", dex.LevelTrace))
+	linkWg, err := cl.Connect(tCtx)
+	if err != nil {
+		t.Fatalf("WSLink Start: %v", err)
+	}
+	defer func() {
+		cl.Disconnect()
+		linkWg.Wait()
+	}()
+	srv.clients[cl.cid] = cl
+
+	// Sends are asynchronous.
+	nextMsg := func() *msgjson.Message {
+		t.Helper()
+		select {
+		case b := <-resp:
+			msg, err := msgjson.DecodeMessage(b)
+			if err != nil {
+				t.Fatalf("error decoding message: %v", err)
+			}
+			return msg
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no message sent")
+		}
+		return nil
+	}
+	checkNoMsg := func() {
+		t.Helper()
+		select {
+		case b := <-resp:
+			t.Fatalf("unexpected message %s", string(b))
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	subscribe := func(req *subscribeRequest) *subscribeResult {
+		t.Helper()
+		msg, _ := msgjson.NewRequest(1, subscribeRoute, req)
+		if msgErr := srv.handleMessage(cl, msg); msgErr != nil {
+			t.Fatalf("subscribe error: %v", msgErr)
+		}
+		respMsg := nextMsg()
+		res := new(subscribeResult)
+		payload, err := respMsg.Response()
+		if err != nil || payload.Error != nil {
+			t.Fatalf("bad subscribe response: %v, %v", err, payload.Error)
+		}
+		if err := json.Unmarshal(payload.Result, res); err != nil {
+			t.Fatalf("error unmarshalling subscribe result: %v", err)
+		}
+		return res
+	}
+	checkSubNote := func(expSeq uint64, expSubs ...uint32) {
+		t.Helper()
+		msg := nextMsg()
+		if msg.Route != subNoteRoute {
+			t.Fatalf("wrong route %q", msg.Route)
+		}
+		var sn struct {
+			Subs []uint32 `json:"subs"`
+			Seq  uint64   `json:"seq"`
+		}
+		if err := msg.Unmarshal(&sn); err != nil {
+			t.Fatalf("error unmarshalling subnote: %v", err)
+		}
+		if sn.Seq != expSeq || fmt.Sprint(sn.Subs) != fmt.Sprint(expSubs) {
+			t.Fatalf("wanted seq %d for subs %v, got seq %d for subs %v", expSeq, expSubs, sn.Seq, sn.Subs)
+		}
+	}
+
+	connNote := func(host string) core.Notification {
+		return &core.ConnEventNote{
+			Notification: db.NewNotification(core.NoteTypeConnEvent, core.TopicDEXConnected, "", "", db.Data),
+			Host:         host,
+		}
+	}
+	orderNote := func(host, mkt string) core.Notification {
+		return &core.OrderNote{
+			Notification: db.NewNotification(core.NoteTypeOrder, core.TopicOrderBooked, "", "", db.Data),
+			Order:        &core.Order{Host: host, MarketID: mkt},
+		}
+	}
+	balNote := &core.BalanceNote{
+		Notification: db.NewNotification(core.NoteTypeBalance, core.TopicBalanceUpdated, "", "", db.Data),
+	}
+
+	res := subscribe(&subscribeRequest{NoteFilter: NoteFilter{Hosts: []string{"dex.com"}}})
+	if res.ID != 1 || res.Seq != 0 || !res.Complete || res.Stream != srv.stream {
+		t.Fatalf("wrong subscribe result %+v", res)
+	}
+	srv.NotifySubscribers(connNote("dex.com"))
+	checkSubNote(1, 1)
+	srv.NotifySubscribers(connNote("other.com"))
+	srv.NotifySubscribers(balNote)
+	checkNoMsg()
+
+	res = subscribe(&subscribeRequest{NoteFilter: NoteFilter{
+		Markets: []*NoteMarket{{Host: "dex.com", BaseID: 42, QuoteID: 0}},
+	}})
+	if res.ID != 2 || res.Seq != 3 {
+		t.Fatalf("wrong subscribe result %+v", res)
+	}
+	srv.NotifySubscribers(orderNote("dex.com", "dcr_btc"))
+	checkSubNote(4, 1, 2)
+	srv.NotifySubscribers(orderNote("dex.com", "eth_btc"))
+	checkSubNote(5, 1)
+	srv.NotifySubscribers(connNote("dex.com")) // not a market note
+	checkSubNote(6, 1)
+
+	// Resume after seq 3. Notes 4 and 5 are orders, and 6 is not.
+	res = subscribe(&subscribeRequest{
+		NoteFilter: NoteFilter{Types: []string{core.NoteTypeOrder}},
+		Stream:     srv.stream,
+		Since:      3,
+	})
+	if res.ID != 3 || res.Seq != 6 || !res.Complete {
+		t.Fatalf("wrong resumed subscribe result %+v", res)
+	}
+	checkSubNote(4, 3)
+	checkSubNote(5, 3)
+	checkNoMsg()
+
+	// Notes from another stream can't be resumed, so the whole log is sent.
+	res = subscribe(&subscribeRequest{
+		NoteFilter: NoteFilter{Topics: []core.Topic{core.TopicBalanceUpdated}},
+		Stream:     "abcd",
+		Since:      5,
+	})
+	if res.Complete {
+		t.Fatalf("resume from another stream was complete")
+	}
+	checkSubNote(3, 4)
+	checkNoMsg()
+
+	// Logged notes are limited.
+	defer func(n int) { noteLogSize = n }(noteLogSize)
+	noteLogSize = 2
+	srv.NotifySubscribers(balNote)
+	checkSubNote(7, 4)
+	res = subscribe(&subscribeRequest{
+		NoteFilter: NoteFilter{Topics: []core.Topic{core.TopicBalanceUpdated}},
+		Stream:     srv.stream,
+		Since:      2,
+	})
+	if res.Complete {
+		t.Fatalf("resume of pruned notes was complete")
+	}
+	checkSubNote(7, 5)
+
+	// Unsubscribe.
+	unsub := func(id uint32) *msgjson.Error {
+		msg, _ := msgjson.NewRequest(2, unsubscribeRoute, &unsubscribeRequest{ID: id})
+		return srv.handleMessage(cl, msg)
+	}
+	for id := uint32(1); id <= 5; id++ {
+		if msgErr := unsub(id); msgErr != nil {
+			t.Fatalf("unsubscribe error: %v", msgErr)
+		}
+		nextMsg()
+	}
+	if msgErr := unsub(1); msgErr == nil {
+		t.Fatalf("no error unsubscribing twice")
+	}
+	srv.NotifySubscribers(connNote("dex.com"))
+	checkNoMsg()
+}
@@ -4,11 +4,20 @@
 package dex
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 )
 
 var symbolBipIDs map[string]uint32
 
+var (
+	// customSymbols are the symbols of user-defined tokens, which are
+	// registered at runtime with RegisterCustomSymbol.
+	customSymbolsMtx sync.RWMutex
+	customSymbols    = make(map[uint32]string)
+)
+
 // BipSymbolID returns the asset ID associated with a given ticker symbol.
 // While there are a number of duplicate ticker symbols in the BIP ID list
 // (cpc, cmt, xrd, dst, one, ask, ...), those are disambiguated in the bipIDs
@@ -22,12 +31,45 @@ func BipSymbolID(symbol string) (uint32, bool) {
 	}
 
 	idx, found := symbolBipIDs[symbol]
-	return idx, found
+	if found {
+		return idx, true
+	}
+	customSymbolsMtx.RLock()
+	defer customSymbolsMtx.RUnlock()
+	for id, sym := range customSymbols {
+		if sym == symbol {
+			return id, true
+		}
+	}
+	return 0, false
 }
 
 // BipIDSymbol returns the BIP ID for a given symbol.
 func BipIDSymbol(id uint32) string {
-	return bipIDs[id]
+	if sym, found := bipIDs[id]; found {
+		return sym
+	}
+	customSymbolsMtx.RLock()
+	defer customSymbolsMtx.RUnlock()
+	return customSymbols[id]
+}
+
+// RegisterCustomSymbol registers the symbol for a user-defined token. Neither
+// the ID nor the symbol can already be in use.
+func RegisterCustomSymbol(id uint32, symbol string) error {
+	if _, found := BipSymbolID(symbol); found {
+		return fmt.Errorf("symbol %q is already in use", symbol)
+	}
+	customSymbolsMtx.Lock()
+	defer customSymbolsMtx.Unlock()
+	if _, found := bipIDs[id]; found {
+		return fmt.Errorf("asset ID %d is already in use", id)
+	}
+	if _, found := customSymbols[id]; found {
+		return fmt.Errorf("asset ID %d is already in use", id)
+	}
+	customSymbols[id] = symbol
+	return nil
 }
 
 // TokenSymbol returns the tokens raw symbol if this is compound symbol that
@@ -578,6 +620,7 @@ var bipIDs = map[uint32]string{
 	8888:  "sbtc",
 	8964:  "nuls",
 	8999:  "btp",
+	9001:  "arbitrum",
 	9797:  "nrg",
 	9888:  "btf",
 	9999:  "god",
@@ -623,6 +666,10 @@ var bipIDs = map[uint32]string{
 	966003: "wbtc.polygon",
 	966004: "usdt.polygon",
 	// END Polygon reserved token range
+	// Arbitrum reserved token range 9001000-9001999
+	9001001: "usdc.arbitrum",
+	9001002: "usdt.arbitrum",
+	// END Arbitrum reserved token range
 	1171337:  "ilt",
 	1313114:  "etho",
 	1313500:  "xero",
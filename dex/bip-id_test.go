@@ -71,3 +71,25 @@ func TestBipSymbolID(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterCustomSymbol(t *testing.T) {
+	const id = 0x80000001
+	if err := RegisterCustomSymbol(id, "dcr"); err == nil {
+		t.Fatalf("no error for existing symbol")
+	}
+	if err := RegisterCustomSymbol(42, "custom.eth"); err == nil {
+		t.Fatalf("no error for existing ID")
+	}
+	if err := RegisterCustomSymbol(id, "custom.eth"); err != nil {
+		t.Fatalf("RegisterCustomSymbol error: %v", err)
+	}
+	if err := RegisterCustomSymbol(id, "custom2.eth"); err == nil {
+		t.Fatalf("no error for registered ID")
+	}
+	if sym := BipIDSymbol(id); sym != "custom.eth" {
+		t.Fatalf("wrong symbol %q", sym)
+	}
+	if assetID, found := BipSymbolID("custom.eth"); !found || assetID != id {
+		t.Fatalf("custom symbol not found")
+	}
+}
@@ -4,6 +4,9 @@
 package candles
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"decred.org/dcrdex/dex/msgjson"
@@ -26,6 +29,60 @@ var (
 	BinSizes = []string{"24h", "1h", "5m"}
 )
 
+// ParseBinSize parses a candle duration. In addition to the units accepted by
+// time.ParseDuration, a "d" suffix specifies a number of days, e.g. "3d".
+func ParseBinSize(s string) (time.Duration, error) {
+	var dur time.Duration
+	if days, found := strings.CutSuffix(s, "d"); found {
+		n, err := strconv.ParseUint(days, 10, 16)
+		if err != nil {
+			return 0, fmt.Errorf("invalid candle duration %q", s)
+		}
+		dur = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if dur, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid candle duration %q: %w", s, err)
+		}
+	}
+	if dur < time.Millisecond {
+		return 0, fmt.Errorf("candle duration %q is too short", s)
+	}
+	return dur, nil
+}
+
+// SourceBinSize picks the bin size from which candles of the duration can be
+// aggregated. This is the largest of the bin sizes that divides the duration
+// evenly, since for a given number of candles it covers the longest history.
+func SourceBinSize(binSizes []string, dur time.Duration) (string, error) {
+	var best string
+	var bestDur time.Duration
+	for _, s := range binSizes {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			continue
+		}
+		if dur%d == 0 && d > bestDur {
+			best, bestDur = s, d
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("duration %s is not a multiple of any of the bin sizes %v", dur, binSizes)
+	}
+	return best, nil
+}
+
+// Aggregate combines the candles, which must be ordered oldest first, into
+// candles of the bin size, which should be a multiple of the bin size of the
+// input candles. Candles are binned by their end stamps, as with a Cache.
+func Aggregate(cs []Candle, binSize uint64) []Candle {
+	c := NewCache(len(cs), binSize)
+	for i := range cs {
+		c.Add(&cs[i])
+	}
+	return c.CandlesCopy()
+}
+
 // Candle is a report about the trading activity of a market over some specified
 // period of time. Candles are managed with a Cache, which takes into account
 // bin sizes and handles candle addition.
@@ -177,6 +234,27 @@ func (c *Cache) Delta(since time.Time) (changePct float64, vol, high, low uint64
 	return (float64(endRate) - float64(startRate)) / float64(startRate), vol, high, low
 }
 
+// QuoteVolume is the quote asset volume since the given time. As with the
+// volume from Delta, a candle that starts before the time contributes the
+// fraction of its volume after the time.
+func (c *Cache) QuoteVolume(since time.Time) (vol uint64) {
+	cutoff := uint64(since.UnixMilli())
+	sz := len(c.Candles)
+	for i := 0; i < sz; i++ {
+		candle := &c.Candles[(c.cursor+sz-i)%sz]
+		if candle.EndStamp <= cutoff {
+			break
+		}
+		if candle.StartStamp <= cutoff {
+			cut := float64(cutoff-candle.StartStamp) / float64(candle.EndStamp-candle.StartStamp)
+			vol += uint64((1 - cut) * float64(candle.QuoteVolume))
+			break
+		}
+		vol += candle.QuoteVolume
+	}
+	return vol
+}
+
 // Last gets the most recent candle in the cache.
 func (c *Cache) Last() *Candle {
 	return &c.Candles[c.cursor]
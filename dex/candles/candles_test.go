@@ -244,6 +244,46 @@ func TestDeltaPartialDays(t *testing.T) {
 	}
 }
 
+func TestQuoteVolume(t *testing.T) {
+	tNow := time.Now().Truncate(time.Millisecond)
+	now := uint64(tNow.UnixMilli())
+
+	c := NewCache(5, fiveMins)
+	if vol := c.QuoteVolume(tNow.Add(-time.Hour)); vol != 0 {
+		t.Fatalf("wrong volume for empty cache. wanted 0, got %d", vol)
+	}
+	c.Add(&Candle{
+		QuoteVolume: 100,
+		StartStamp:  now - 3*fiveMins,
+		EndStamp:    now - 2*fiveMins,
+	})
+	c.Add(&Candle{
+		QuoteVolume: 200,
+		StartStamp:  now - 2*fiveMins,
+		EndStamp:    now - fiveMins,
+	})
+	c.Add(&Candle{
+		QuoteVolume: 50,
+		StartStamp:  now - fiveMins,
+		EndStamp:    now,
+	})
+
+	tests := []struct {
+		since time.Time
+		want  uint64
+	}{
+		{tNow.Add(-time.Hour), 350},
+		{tNow.Add(-time.Minute * 10), 250},
+		{tNow.Add(-time.Minute * 5 / 2), 25}, // half of the last candle
+		{tNow, 0},
+	}
+	for _, tt := range tests {
+		if vol := c.QuoteVolume(tt.since); vol != tt.want {
+			t.Fatalf("wrong volume since %v. wanted %d, got %d", tt.since, tt.want, vol)
+		}
+	}
+}
+
 func TestCandlesCopy(t *testing.T) {
 	smallCap := 10
 	binSize := uint64(60 * 5 * 1000)
@@ -314,3 +354,109 @@ func TestCandlesCopy(t *testing.T) {
 		}
 	}
 }
+
+func TestParseBinSize(t *testing.T) {
+	tests := []struct {
+		s       string
+		exp     time.Duration
+		wantErr bool
+	}{
+		{"5m", 5 * time.Minute, false},
+		{"4h", 4 * time.Hour, false},
+		{"3d", 72 * time.Hour, false},
+		{"1.5d", 0, true},
+		{"d", 0, true},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"1w", 0, true},
+	}
+	for _, tt := range tests {
+		dur, err := ParseBinSize(tt.s)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%q: no error", tt.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.s, err)
+		}
+		if dur != tt.exp {
+			t.Fatalf("%q: expected %s, got %s", tt.s, tt.exp, dur)
+		}
+	}
+}
+
+func TestSourceBinSize(t *testing.T) {
+	for _, tt := range []struct {
+		dur time.Duration
+		exp string
+	}{
+		{4 * time.Hour, "1h"},
+		{72 * time.Hour, "24h"},
+		{15 * time.Minute, "5m"},
+		{7 * time.Minute, ""},
+	} {
+		binSize, err := SourceBinSize(BinSizes, tt.dur)
+		if tt.exp == "" {
+			if err == nil {
+				t.Fatalf("%s: no error", tt.dur)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.dur, err)
+		}
+		if binSize != tt.exp {
+			t.Fatalf("%s: expected %s, got %s", tt.dur, tt.exp, binSize)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	const binSize = fiveMins * 3
+	var cs []Candle
+	// Seven 5 minute candles starting at the beginning of a 15 minute bin.
+	for i := uint64(0); i < 7; i++ {
+		start := binSize*100 + i*fiveMins
+		cs = append(cs, Candle{
+			StartStamp:  start,
+			EndStamp:    start + fiveMins - 1,
+			MatchVolume: 10,
+			QuoteVolume: 20,
+			StartRate:   100 + i,
+			EndRate:     101 + i,
+			LowRate:     90 + i,
+			HighRate:    110 + i,
+		})
+	}
+	agg := Aggregate(cs, binSize)
+	if len(agg) != 3 {
+		t.Fatalf("expected 3 candles, got %d", len(agg))
+	}
+	for i, exp := range []struct {
+		n, first uint64
+	}{{3, 0}, {3, 3}, {1, 6}} {
+		c := &agg[i]
+		first, last := &cs[exp.first], &cs[exp.first+exp.n-1]
+		if c.StartStamp != first.StartStamp || c.EndStamp != last.EndStamp {
+			t.Fatalf("candle %d: wrong stamps %d-%d", i, c.StartStamp, c.EndStamp)
+		}
+		if c.MatchVolume != 10*exp.n || c.QuoteVolume != 20*exp.n {
+			t.Fatalf("candle %d: wrong volumes %d, %d", i, c.MatchVolume, c.QuoteVolume)
+		}
+		if c.StartRate != first.StartRate || c.EndRate != last.EndRate {
+			t.Fatalf("candle %d: wrong start/end rates %d, %d", i, c.StartRate, c.EndRate)
+		}
+		if c.LowRate != first.LowRate || c.HighRate != last.HighRate {
+			t.Fatalf("candle %d: wrong low/high rates %d, %d", i, c.LowRate, c.HighRate)
+		}
+	}
+	// The input is not modified.
+	if cs[0].EndStamp != binSize*100+fiveMins-1 || cs[0].MatchVolume != 10 {
+		t.Fatalf("input candle modified")
+	}
+	if len(Aggregate(nil, binSize)) != 0 {
+		t.Fatalf("candles from nothing")
+	}
+}
@@ -0,0 +1,386 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package indicators computes technical indicators over candle series. The
+// indicators are streaming, i.e. they are updated one candle at a time, and the
+// latest candle can be updated repeatedly as it fills in, as happens with the
+// candles of a candles.Cache that is fed epoch reports. Rates are message-rate
+// encoded, as they are in the candles.
+package indicators
+
+import (
+	"math"
+
+	"decred.org/dcrdex/dex/candles"
+)
+
+// Indicator is a streaming technical indicator.
+type Indicator[V any] interface {
+	// Update adds a candle to the series. A candle with the same start stamp
+	// as the last candle replaces it, otherwise the candle must be newer than
+	// the last candle. The indicator's value after the update is returned,
+	// with ok false if there are not yet enough candles to compute it.
+	Update(c *candles.Candle) (v V, ok bool)
+	// Value is the indicator's value for the series so far.
+	Value() (v V, ok bool)
+}
+
+// Compute updates the indicator with the candles, which must be ordered oldest
+// first, and returns the indicator's values after each candle. The values
+// before index start are not valid. If none of the values are valid, start is
+// len(cs).
+func Compute[V any](ind Indicator[V], cs []candles.Candle) (vs []V, start int) {
+	vs = make([]V, len(cs))
+	start = len(cs)
+	for i := range cs {
+		v, ok := ind.Update(&cs[i])
+		if !ok {
+			continue
+		}
+		if start == len(cs) {
+			start = i
+		}
+		vs[i] = v
+	}
+	return vs, start
+}
+
+// state is an indicator's state after a series of candles. Implementations
+// must have value semantics, because the state from before the last candle
+// is reused when the last candle is updated.
+type state[S any, V any] interface {
+	next(c *candles.Candle) S
+	value() (V, bool)
+}
+
+// stream implements Indicator for a state.
+type stream[S state[S, V], V any] struct {
+	// prev is the state before the last candle, and cur is the state
+	// including the last candle.
+	prev, cur S
+	lastStart uint64
+	started   bool
+}
+
+// Update adds a candle to the series, replacing the last candle if it has the
+// same start stamp. Update is not safe for concurrent use.
+func (s *stream[S, V]) Update(c *candles.Candle) (V, bool) {
+	if !s.started || c.StartStamp != s.lastStart {
+		s.prev = s.cur
+		s.lastStart = c.StartStamp
+		s.started = true
+	}
+	s.cur = s.prev.next(c)
+	return s.cur.value()
+}
+
+// Value is the indicator's value for the series so far.
+func (s *stream[S, V]) Value() (V, bool) {
+	return s.cur.value()
+}
+
+// prices are the rates of a candle.
+type prices struct {
+	high, low, close float64
+}
+
+// lastClose is the close rate of the previous candle.
+type lastClose struct {
+	rate float64
+	ok   bool
+}
+
+// prices gets the rates of the candle. Candles without matches have no rates,
+// and the previous close is used for all of them. ok is false if there are no
+// rates and no previous close.
+func (l lastClose) prices(c *candles.Candle) (p prices, ok bool) {
+	cl := c.EndRate
+	if cl == 0 {
+		cl = c.StartRate
+	}
+	if cl == 0 {
+		return prices{l.rate, l.rate, l.rate}, l.ok
+	}
+	p = prices{float64(c.HighRate), float64(c.LowRate), float64(cl)}
+	if p.high < p.close {
+		p.high = p.close
+	}
+	if p.low == 0 || p.low > p.close {
+		p.low = p.close
+	}
+	return p, true
+}
+
+// window is the close rates of the last period candles.
+type window struct {
+	period int
+	closes []float64
+}
+
+// push returns the window with the rate added. The window's backing array is
+// not modified.
+func (w window) push(rate float64) window {
+	n := len(w.closes)
+	drop := n + 1 - w.period
+	if drop < 0 {
+		drop = 0
+	}
+	w.closes = append(w.closes[drop:n:n], rate)
+	return w
+}
+
+func (w window) full() bool {
+	return len(w.closes) == w.period
+}
+
+func (w window) mean() float64 {
+	var sum float64
+	for _, v := range w.closes {
+		sum += v
+	}
+	return sum / float64(len(w.closes))
+}
+
+// stdDev is the population standard deviation of the window.
+func (w window) stdDev(mean float64) float64 {
+	var sum float64
+	for _, v := range w.closes {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(w.closes)))
+}
+
+type smaState struct {
+	last lastClose
+	win  window
+}
+
+func (s smaState) next(c *candles.Candle) smaState {
+	p, ok := s.last.prices(c)
+	if !ok {
+		return s
+	}
+	s.last = lastClose{p.close, true}
+	s.win = s.win.push(p.close)
+	return s
+}
+
+func (s smaState) value() (float64, bool) {
+	if !s.win.full() {
+		return 0, false
+	}
+	return s.win.mean(), true
+}
+
+// SMA is the simple moving average of the close rate.
+type SMA struct {
+	stream[smaState, float64]
+}
+
+var _ Indicator[float64] = (*SMA)(nil)
+
+// NewSMA is the constructor for an SMA over period candles.
+func NewSMA(period int) *SMA {
+	s := &SMA{}
+	s.cur.win.period = max(period, 1)
+	return s
+}
+
+type emaState struct {
+	last   lastClose
+	period int
+	// n is the number of closes, up to period. The EMA is seeded with the
+	// SMA of the first period closes.
+	n   int
+	ema float64
+}
+
+func (s emaState) next(c *candles.Candle) emaState {
+	p, ok := s.last.prices(c)
+	if !ok {
+		return s
+	}
+	s.last = lastClose{p.close, true}
+	if s.n < s.period {
+		s.n++
+		s.ema += (p.close - s.ema) / float64(s.n) // running mean
+		return s
+	}
+	alpha := 2 / float64(s.period+1)
+	s.ema += alpha * (p.close - s.ema)
+	return s
+}
+
+func (s emaState) value() (float64, bool) {
+	return s.ema, s.n == s.period
+}
+
+// EMA is the exponential moving average of the close rate, with a smoothing
+// factor of 2 / (period + 1).
+type EMA struct {
+	stream[emaState, float64]
+}
+
+var _ Indicator[float64] = (*EMA)(nil)
+
+// NewEMA is the constructor for an EMA over period candles.
+func NewEMA(period int) *EMA {
+	e := &EMA{}
+	e.cur.period = max(period, 1)
+	return e
+}
+
+type rsiState struct {
+	last    lastClose
+	period  int
+	n       int
+	avgGain float64
+	avgLoss float64
+}
+
+func (s rsiState) next(c *candles.Candle) rsiState {
+	p, ok := s.last.prices(c)
+	if !ok {
+		return s
+	}
+	prev := s.last
+	s.last = lastClose{p.close, true}
+	if !prev.ok {
+		return s
+	}
+	var gain, loss float64
+	if change := p.close - prev.rate; change > 0 {
+		gain = change
+	} else {
+		loss = -change
+	}
+	if s.n < s.period {
+		// Seed the averages with the mean of the first period changes.
+		s.n++
+		s.avgGain += (gain - s.avgGain) / float64(s.n)
+		s.avgLoss += (loss - s.avgLoss) / float64(s.n)
+		return s
+	}
+	// Wilder's smoothing.
+	period := float64(s.period)
+	s.avgGain = (s.avgGain*(period-1) + gain) / period
+	s.avgLoss = (s.avgLoss*(period-1) + loss) / period
+	return s
+}
+
+func (s rsiState) value() (float64, bool) {
+	if s.n < s.period {
+		return 0, false
+	}
+	switch {
+	case s.avgGain == 0 && s.avgLoss == 0:
+		return 50, true
+	case s.avgLoss == 0:
+		return 100, true
+	}
+	return 100 - 100/(1+s.avgGain/s.avgLoss), true
+}
+
+// RSI is Wilder's relative strength index of the close rate, in the range 0 to
+// 100.
+type RSI struct {
+	stream[rsiState, float64]
+}
+
+var _ Indicator[float64] = (*RSI)(nil)
+
+// NewRSI is the constructor for an RSI over period candles. The first value is
+// available after period + 1 candles.
+func NewRSI(period int) *RSI {
+	r := &RSI{}
+	r.cur.period = max(period, 1)
+	return r
+}
+
+// Band is a Bollinger band.
+type Band struct {
+	Lower  float64 `json:"lower"`
+	Middle float64 `json:"middle"`
+	Upper  float64 `json:"upper"`
+}
+
+type bollingerState struct {
+	smaState
+	k float64
+}
+
+func (s bollingerState) next(c *candles.Candle) bollingerState {
+	s.smaState = s.smaState.next(c)
+	return s
+}
+
+func (s bollingerState) value() (Band, bool) {
+	mean, ok := s.smaState.value()
+	if !ok {
+		return Band{}, false
+	}
+	d := s.k * s.win.stdDev(mean)
+	return Band{Lower: mean - d, Middle: mean, Upper: mean + d}, true
+}
+
+// Bollinger is the Bollinger band of the close rate, which is the SMA plus or
+// minus a multiple of the standard deviation over the same period.
+type Bollinger struct {
+	stream[bollingerState, Band]
+}
+
+var _ Indicator[Band] = (*Bollinger)(nil)
+
+// NewBollinger is the constructor for a Bollinger band over period candles,
+// with a width of k standard deviations on either side of the SMA.
+func NewBollinger(period int, k float64) *Bollinger {
+	b := &Bollinger{}
+	b.cur.win.period = max(period, 1)
+	b.cur.k = k
+	return b
+}
+
+type atrState struct {
+	last   lastClose
+	period int
+	n      int
+	atr    float64
+}
+
+func (s atrState) next(c *candles.Candle) atrState {
+	p, ok := s.last.prices(c)
+	if !ok {
+		return s
+	}
+	tr := p.high - p.low
+	if s.last.ok {
+		tr = max(tr, math.Abs(p.high-s.last.rate), math.Abs(p.low-s.last.rate))
+	}
+	s.last = lastClose{p.close, true}
+	if s.n < s.period {
+		s.n++
+		s.atr += (tr - s.atr) / float64(s.n)
+		return s
+	}
+	period := float64(s.period)
+	s.atr = (s.atr*(period-1) + tr) / period
+	return s
+}
+
+func (s atrState) value() (float64, bool) {
+	return s.atr, s.n == s.period
+}
+
+// ATR is Wilder's average true range, a measure of volatility in rate units.
+type ATR struct {
+	stream[atrState, float64]
+}
+
+var _ Indicator[float64] = (*ATR)(nil)
+
+// NewATR is the constructor for an ATR over period candles.
+func NewATR(period int) *ATR {
+	a := &ATR{}
+	a.cur.period = max(period, 1)
+	return a
+}
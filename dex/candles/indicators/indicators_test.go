@@ -0,0 +1,144 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package indicators
+
+import (
+	"math"
+	"testing"
+
+	"decred.org/dcrdex/dex/candles"
+)
+
+func makeCandles(closes ...uint64) []candles.Candle {
+	cs := make([]candles.Candle, len(closes))
+	for i, cl := range closes {
+		cs[i] = candles.Candle{
+			StartStamp: uint64(i) * 1000,
+			EndStamp:   uint64(i)*1000 + 999,
+			StartRate:  cl,
+			EndRate:    cl,
+			HighRate:   cl + 1,
+			LowRate:    cl - 1,
+		}
+	}
+	return cs
+}
+
+func checkFloat(t *testing.T, name string, v, exp float64) {
+	t.Helper()
+	if math.Abs(v-exp) > 1e-9 {
+		t.Fatalf("%s: expected %f, got %f", name, exp, v)
+	}
+}
+
+func TestIndicators(t *testing.T) {
+	cs := makeCandles(10, 12, 11, 13, 15, 14)
+
+	smas, start := Compute[float64](NewSMA(3), cs)
+	if start != 2 {
+		t.Fatalf("SMA start %d", start)
+	}
+	for i, exp := range []float64{11, 12, 13, 14} {
+		checkFloat(t, "SMA", smas[i+2], exp)
+	}
+
+	emas, start := Compute[float64](NewEMA(3), cs)
+	if start != 2 {
+		t.Fatalf("EMA start %d", start)
+	}
+	ema := 11.0 // seeded with the SMA
+	for i := 2; i < len(cs); i++ {
+		if i > 2 {
+			ema += 0.5 * (float64(cs[i].EndRate) - ema)
+		}
+		checkFloat(t, "EMA", emas[i], ema)
+	}
+
+	rsis, start := Compute[float64](NewRSI(2), cs)
+	if start != 2 {
+		t.Fatalf("RSI start %d", start)
+	}
+	// Changes +2, -1: avg gain 1, avg loss 0.5
+	checkFloat(t, "RSI", rsis[2], 100-100/(1+1/0.5))
+	// +2: avg gain 1.5, avg loss 0.25
+	checkFloat(t, "RSI", rsis[3], 100-100/(1+1.5/0.25))
+
+	bands, start := Compute[Band](NewBollinger(3, 2), cs)
+	if start != 2 {
+		t.Fatalf("Bollinger start %d", start)
+	}
+	sd := math.Sqrt(2.0 / 3) // 10, 12, 11
+	checkFloat(t, "Bollinger middle", bands[2].Middle, 11)
+	checkFloat(t, "Bollinger lower", bands[2].Lower, 11-2*sd)
+	checkFloat(t, "Bollinger upper", bands[2].Upper, 11+2*sd)
+
+	atrs, start := Compute[float64](NewATR(2), cs)
+	if start != 1 {
+		t.Fatalf("ATR start %d", start)
+	}
+	// True ranges: 2, max(2, |13-10|, |11-10|) = 3, max(2, |12-12|, |10-12|) = 2
+	checkFloat(t, "ATR", atrs[1], 2.5)
+	checkFloat(t, "ATR", atrs[2], (2.5+2)/2)
+
+	// Not enough candles.
+	if _, start := Compute[float64](NewRSI(14), cs); start != len(cs) {
+		t.Fatalf("RSI ready with too few candles")
+	}
+	rsi := NewRSI(14)
+	if _, ok := rsi.Value(); ok {
+		t.Fatalf("RSI ready with no candles")
+	}
+}
+
+func TestStreamingUpdates(t *testing.T) {
+	cs := makeCandles(10, 12, 11, 13, 15, 14, 20, 18)
+	newInds := []func() Indicator[float64]{
+		func() Indicator[float64] { return NewSMA(3) },
+		func() Indicator[float64] { return NewEMA(3) },
+		func() Indicator[float64] { return NewRSI(3) },
+		func() Indicator[float64] { return NewATR(3) },
+	}
+	for i, newInd := range newInds {
+		ind := newInd()
+		for j := range cs {
+			// Update the latest candle a couple of times before its final
+			// form, as epoch reports would.
+			partial := cs[j]
+			partial.EndRate, partial.HighRate = 100, 101
+			ind.Update(&partial)
+			partial.EndRate, partial.LowRate = 1, 0
+			ind.Update(&partial)
+			v, ok := ind.Update(&cs[j])
+
+			exp, start := Compute(newInd(), cs[:j+1])
+			expOK := start <= j
+			if ok != expOK {
+				t.Fatalf("indicator %d, candle %d: expected ok = %t", i, j, expOK)
+			}
+			if ok {
+				checkFloat(t, "streamed", v, exp[j])
+			}
+		}
+	}
+}
+
+func TestNoMatchCandles(t *testing.T) {
+	cs := makeCandles(10, 12, 11)
+	// A candle without matches repeats the last close.
+	cs = append(cs, candles.Candle{StartStamp: 3000, EndStamp: 3999})
+	smas, _ := Compute[float64](NewSMA(2), cs)
+	checkFloat(t, "SMA", smas[3], 11)
+	// Candles without matches before the first close are skipped.
+	shifted := []candles.Candle{{StartStamp: 0, EndStamp: 999}}
+	for _, c := range cs {
+		c.StartStamp += 1000
+		c.EndStamp += 1000
+		shifted = append(shifted, c)
+	}
+	smas, start := Compute[float64](NewSMA(2), shifted)
+	if start != 2 {
+		t.Fatalf("SMA start %d", start)
+	}
+	checkFloat(t, "SMA", smas[2], 11)
+}
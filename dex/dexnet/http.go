@@ -45,6 +45,24 @@ func WithErrorParsing(thing interface{}) *RequestOption {
 	return &RequestOption{errThing: thing}
 }
 
+type clientKey struct{}
+
+// ContextWithClient returns a context that causes Get, Post, and Do to perform
+// requests with the provided client instead of http.DefaultClient, e.g. to
+// route requests through a proxy.
+func ContextWithClient(ctx context.Context, client *http.Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// clientFromContext is the client set with ContextWithClient, or
+// http.DefaultClient.
+func clientFromContext(ctx context.Context) *http.Client {
+	if client, ok := ctx.Value(clientKey{}).(*http.Client); ok && client != nil {
+		return client
+	}
+	return http.DefaultClient
+}
+
 // Post peforms an HTTP POST request. If thing is non-nil, the response will
 // be JSON-unmarshaled into thing.
 func Post(ctx context.Context, uri string, thing interface{}, body []byte, opts ...*RequestOption) error {
@@ -70,6 +88,8 @@ func Get(ctx context.Context, uri string, thing interface{}, opts ...*RequestOpt
 }
 
 // Do does the request and JSON-marshals the result into thing, if non-nil.
+// The request is performed with the client set on the request's context with
+// ContextWithClient, if any.
 func Do(req *http.Request, thing interface{}, opts ...*RequestOption) error {
 	var sizeLimit int64 = defaultResponseSizeLimit
 	var statusFunc func(int)
@@ -88,7 +108,7 @@ func Do(req *http.Request, thing interface{}, opts ...*RequestOption) error {
 			errThing = opt.errThing
 		}
 	}
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := clientFromContext(req.Context()).Do(req)
 	if err != nil {
 		return fmt.Errorf("error performing request: %w", err)
 	}
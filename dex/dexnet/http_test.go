@@ -27,3 +27,38 @@ func TestErrorParsing(t *testing.T) {
 		t.Fatal("unexpected error body")
 	}
 }
+
+type tRoundTripper struct {
+	reqs int
+}
+
+func (rt *tRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.reqs++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestContextWithClient(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"a": 1}`))
+	}))
+	defer ts.Close()
+
+	rt := &tRoundTripper{}
+	ctx := ContextWithClient(context.Background(), &http.Client{Transport: rt})
+	var res struct {
+		A int `json:"a"`
+	}
+	if err := Get(ctx, ts.URL, &res); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if rt.reqs != 1 || res.A != 1 {
+		t.Fatalf("request not performed with the context's client")
+	}
+	// Without a client in the context, the default client is used.
+	if err := Get(context.Background(), ts.URL, &res); err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if rt.reqs != 1 {
+		t.Fatalf("context client used for request without it")
+	}
+}
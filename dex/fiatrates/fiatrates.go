@@ -40,6 +40,9 @@ func parseCoinpapNameSymbol(name, symbol string) (string, string) {
 	case "polygon":
 		symbol = "matic"
 		name = "polygon"
+	case "arbitrum":
+		// The native asset is bridged ETH.
+		symbol, name = "eth", "ethereum"
 	case "weth":
 		name = "weth"
 	case "matic":
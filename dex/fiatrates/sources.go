@@ -262,7 +262,9 @@ func parseTickers(tickerSymbols ...string) string {
 func parseTicker(ticker string) string {
 	if strings.EqualFold(ticker, "polygon") {
 		return "MATIC"
-	} else if strings.EqualFold(ticker, "usdc.eth") || strings.EqualFold(ticker, "usdc.polygon") {
+	} else if strings.EqualFold(ticker, "arbitrum") {
+		return "ETH"
+	} else if strings.EqualFold(ticker, "usdc.eth") || strings.EqualFold(ticker, "usdc.polygon") || strings.EqualFold(ticker, "usdc.arbitrum") {
 		return "USDC"
 	}
 	return upperCaser.String(ticker)
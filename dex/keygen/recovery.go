@@ -0,0 +1,108 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package keygen
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/hdkeychain/v3"
+)
+
+// The DEX client derives its account and bond keys from the app seed, so the
+// keys can be recovered from the seed alone. These functions are the client's
+// derivations, for recovering the keys with external tools.
+
+const (
+	// AccountKeyPurpose is the BIP-43 purpose field for DEX account keys.
+	AccountKeyPurpose uint32 = hdkeychain.HardenedKeyStart + 0x646578 // ASCII "dex"
+	// BondKeyPurpose is the BIP-43 purpose field for bond keys. These keys
+	// are separate from DEX accounts. The following path that is independent
+	// of a dex account will simplify discovery and key recovery if we devise
+	// a scheme to locate them on-chain:
+	//  m / BondKeyPurpose / assetID' / bondIndex
+	BondKeyPurpose uint32 = hdkeychain.HardenedKeyStart + 0x626f6e64 // ASCII "bond"
+)
+
+// AccountKeyPath is the derivation path of the account key for the DEX with
+// the compressed public key dexPubKey. The path is
+//
+//	m / AccountKeyPurpose' / dexPubKey[0]' / 8 x 4-byte chunks of dexPubKey' / keyIndex'
+//
+// The account's key index is 0 unless the account was replaced.
+func AccountKeyPath(dexPubKey []byte, keyIndex uint32) ([]uint32, error) {
+	if len(dexPubKey) != secp256k1.PubKeyBytesLenCompressed {
+		return nil, fmt.Errorf("invalid DEX public key length %d", len(dexPubKey))
+	}
+	if keyIndex >= hdkeychain.HardenedKeyStart {
+		return nil, fmt.Errorf("maximum key generation reached, cannot generate %dth key", keyIndex)
+	}
+	// 1 x purpose, 1 x version (incl. oddness), 8 x 4-byte uint32s, 1 x acct
+	// key index.
+	kids := make([]uint32, 0, 11)
+	kids = append(kids, AccountKeyPurpose)
+	// Second child is the format/oddness byte.
+	kids = append(kids, uint32(dexPubKey[0]))
+	byteSeq := dexPubKey[1:]
+	// Generate uint32's from the 4-byte chunks of the pubkey.
+	for i := 0; i < 8; i++ {
+		kids = append(kids, binary.LittleEndian.Uint32(byteSeq[i*4:i*4+4]))
+	}
+	// Last child is the account key index.
+	kids = append(kids, keyIndex)
+
+	// Harden children by modding i first, technically doubling the
+	// collision rate, but OK.
+	for i := 0; i < len(kids); i++ {
+		kids[i] = kids[i]%hdkeychain.HardenedKeyStart + hdkeychain.HardenedKeyStart
+	}
+	return kids, nil
+}
+
+// AccountKey derives the account private key for the DEX with the compressed
+// public key dexPubKey from the app seed.
+func AccountKey(seed, dexPubKey []byte, keyIndex uint32) (*secp256k1.PrivateKey, error) {
+	kids, err := AccountKeyPath(dexPubKey, keyIndex)
+	if err != nil {
+		return nil, err
+	}
+	extKey, err := GenDeepChild(seed, kids)
+	if err != nil {
+		return nil, fmt.Errorf("GenDeepChild error: %w", err)
+	}
+	defer extKey.Zero()
+	return privKey(extKey)
+}
+
+// BondXPriv derives the extended private key from which the bond keys of all
+// assets are derived with BondKey.
+func BondXPriv(seed []byte) (*hdkeychain.ExtendedKey, error) {
+	return GenDeepChild(seed, []uint32{BondKeyPurpose})
+}
+
+// BondKey derives the bond private key of the asset at the bond index from the
+// extended key from BondXPriv. Bond indexes are assigned sequentially for each
+// asset, starting at 0, regardless of the DEX the bond was posted to.
+func BondKey(bondXPriv *hdkeychain.ExtendedKey, assetID, bondIndex uint32) (*secp256k1.PrivateKey, error) {
+	kids := []uint32{
+		assetID + hdkeychain.HardenedKeyStart,
+		bondIndex,
+	}
+	extKey, err := GenDeepChildFromXPriv(bondXPriv, kids)
+	if err != nil {
+		return nil, fmt.Errorf("GenDeepChild error: %w", err)
+	}
+	defer extKey.Zero()
+	return privKey(extKey)
+}
+
+// privKey is the private key of the extended key.
+func privKey(extKey *hdkeychain.ExtendedKey) (*secp256k1.PrivateKey, error) {
+	privB, err := extKey.SerializedPrivKey()
+	if err != nil {
+		return nil, fmt.Errorf("SerializedPrivKey error: %w", err)
+	}
+	return secp256k1.PrivKeyFromBytes(privB), nil
+}
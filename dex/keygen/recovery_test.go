@@ -0,0 +1,64 @@
+package keygen
+
+import (
+	"bytes"
+	"testing"
+
+	"decred.org/dcrdex/dex/encode"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/hdkeychain/v3"
+)
+
+func TestRecoveryKeys(t *testing.T) {
+	seed := encode.RandomBytes(64)
+	dexPriv, _ := secp256k1.GeneratePrivateKey()
+	dexPubKey := dexPriv.PubKey().SerializeCompressed()
+
+	kids, err := AccountKeyPath(dexPubKey, 1)
+	if err != nil {
+		t.Fatalf("AccountKeyPath error: %v", err)
+	}
+	if len(kids) != 11 || kids[0] != AccountKeyPurpose || kids[10] != hdkeychain.HardenedKeyStart+1 {
+		t.Fatalf("wrong account key path %v", kids)
+	}
+	for _, kid := range kids {
+		if kid < hdkeychain.HardenedKeyStart {
+			t.Fatalf("account key path has non-hardened child %d", kid)
+		}
+	}
+	extKey, err := GenDeepChild(seed, kids)
+	if err != nil {
+		t.Fatalf("GenDeepChild error: %v", err)
+	}
+	expPriv, _ := extKey.SerializedPrivKey()
+	priv, err := AccountKey(seed, dexPubKey, 1)
+	if err != nil {
+		t.Fatalf("AccountKey error: %v", err)
+	}
+	if !bytes.Equal(priv.Serialize(), expPriv) {
+		t.Fatalf("wrong account key")
+	}
+	if _, err := AccountKey(seed, dexPubKey[1:], 0); err == nil {
+		t.Fatalf("no error for invalid DEX public key")
+	}
+	if _, err := AccountKey(seed, dexPubKey, hdkeychain.HardenedKeyStart); err == nil {
+		t.Fatalf("no error for invalid key index")
+	}
+
+	bondXPriv, err := BondXPriv(seed)
+	if err != nil {
+		t.Fatalf("BondXPriv error: %v", err)
+	}
+	extKey, err = GenDeepChild(seed, []uint32{BondKeyPurpose, 42 + hdkeychain.HardenedKeyStart, 7})
+	if err != nil {
+		t.Fatalf("GenDeepChild error: %v", err)
+	}
+	expPriv, _ = extKey.SerializedPrivKey()
+	priv, err = BondKey(bondXPriv, 42, 7)
+	if err != nil {
+		t.Fatalf("BondKey error: %v", err)
+	}
+	if !bytes.Equal(priv.Serialize(), expPriv) {
+		t.Fatalf("wrong bond key")
+	}
+}
@@ -0,0 +1,142 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package msgjson
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"decred.org/dcrdex/dex"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// CBORSubprotocol is the websocket subprotocol requested by a client that can
+// receive CBOR-encoded messages. If the server selects the subprotocol, it may
+// send some messages, typically the high-volume ones such as order books and
+// candles, CBOR-encoded in binary frames instead of JSON-encoded in text
+// frames. Clients always send JSON.
+//
+// CBOR encoding uses the json struct tags of the payload types, but byte
+// fields such as Bytes are encoded as byte strings rather than hex strings,
+// and numbers as binary integers, so CBOR messages are smaller and faster to
+// decode.
+const CBORSubprotocol = "dcrdex-cbor"
+
+// cborMessage is the CBOR encoding of a Message. The Payload is CBOR.
+type cborMessage struct {
+	Type    MessageType     `cbor:"type"`
+	Route   string          `cbor:"route,omitempty"`
+	ID      uint64          `cbor:"id,omitempty"`
+	Payload cbor.RawMessage `cbor:"payload,omitempty"`
+	Sig     dex.Bytes       `cbor:"sig,omitempty"`
+}
+
+// cborResponsePayload is the CBOR encoding of a ResponsePayload.
+type cborResponsePayload struct {
+	Result cbor.RawMessage `cbor:"result,omitempty"`
+	Error  *Error          `cbor:"error,omitempty"`
+}
+
+// NewResponseCBOR is like NewResponse, but the result is CBOR-encoded. The
+// Message must be sent with EncodeCBOR.
+func NewResponseCBOR(id uint64, result any, rpcErr *Error) (*Message, error) {
+	if id == 0 {
+		return nil, fmt.Errorf("id = 0 not allowed for response-type message")
+	}
+	encResult, err := cbor.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	encResp, err := cbor.Marshal(&cborResponsePayload{
+		Result: encResult,
+		Error:  rpcErr,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		Type:    Response,
+		Payload: encResp,
+		ID:      id,
+		cbor:    true,
+	}, nil
+}
+
+// NewNotificationCBOR is like NewNotification, but the payload is
+// CBOR-encoded. The Message must be sent with EncodeCBOR.
+func NewNotificationCBOR(route string, payload any) (*Message, error) {
+	if route == "" {
+		return nil, fmt.Errorf("empty string not allowed for route of notification-type message")
+	}
+	encPayload, err := cbor.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{
+		Type:    Notification,
+		Route:   route,
+		Payload: encPayload,
+		cbor:    true,
+	}, nil
+}
+
+// IsCBOR is true if the Message's Payload is CBOR-encoded, i.e. the Message was
+// created with NewResponseCBOR or NewNotificationCBOR, or decoded with
+// DecodeCBORMessage.
+func (msg *Message) IsCBOR() bool {
+	return msg.cbor
+}
+
+// EncodeCBOR encodes a Message with a CBOR-encoded Payload.
+func (msg *Message) EncodeCBOR() ([]byte, error) {
+	if !msg.cbor {
+		return nil, fmt.Errorf("message payload is not CBOR-encoded")
+	}
+	return cbor.Marshal(&cborMessage{
+		Type:    msg.Type,
+		Route:   msg.Route,
+		ID:      msg.ID,
+		Payload: cbor.RawMessage(msg.Payload),
+		Sig:     msg.Sig,
+	})
+}
+
+// DecodeCBORMessage decodes a *Message encoded with EncodeCBOR. The Payload
+// remains CBOR-encoded, and is decoded by the Message's Unmarshal and
+// UnmarshalResult methods.
+func DecodeCBORMessage(b []byte) (*Message, error) {
+	var m cborMessage
+	if err := cbor.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return &Message{
+		Type:    m.Type,
+		Route:   m.Route,
+		ID:      m.ID,
+		Payload: json.RawMessage(m.Payload),
+		Sig:     m.Sig,
+		cbor:    true,
+	}, nil
+}
+
+// cborResponse decodes the CBOR-encoded payload of a Response-type Message. The
+// ResponsePayload's Result is CBOR-encoded.
+func (msg *Message) cborResponse() (*ResponsePayload, error) {
+	var resp cborResponsePayload
+	if err := cbor.Unmarshal(msg.Payload, &resp); err != nil {
+		return nil, err
+	}
+	return &ResponsePayload{
+		Result: json.RawMessage(resp.Result),
+		Error:  resp.Error,
+	}, nil
+}
+
+// decode decodes a JSON or CBOR payload, depending on the Message's encoding.
+func (msg *Message) decode(b []byte, thing any) error {
+	if msg.cbor {
+		return cbor.Unmarshal(b, thing)
+	}
+	return json.Unmarshal(b, thing)
+}
@@ -1152,3 +1152,71 @@ func randomCoin() *Coin {
 		Redeem:  randomBytes(25),
 	}
 }
+
+func TestCBOR(t *testing.T) {
+	oid := randomBytes(32)
+	note := &BookOrderNote{
+		OrderNote: OrderNote{Seq: 5, MarketID: "dcr_btc", OrderID: oid},
+		TradeNote: TradeNote{Side: SellOrderNum, Quantity: 1e8, Rate: 2e6, Time: 1234},
+	}
+	msg, err := NewNotificationCBOR(BookOrderRoute, note)
+	if err != nil {
+		t.Fatalf("NewNotificationCBOR error: %v", err)
+	}
+	b, err := msg.EncodeCBOR()
+	if err != nil {
+		t.Fatalf("EncodeCBOR error: %v", err)
+	}
+	jsonMsg, _ := NewNotification(BookOrderRoute, note)
+	jsonB, _ := json.Marshal(jsonMsg)
+	if len(b) >= len(jsonB) {
+		t.Fatalf("CBOR encoding is not smaller, %d >= %d bytes", len(b), len(jsonB))
+	}
+	if _, err := jsonMsg.EncodeCBOR(); err == nil {
+		t.Fatalf("no error CBOR-encoding a JSON message")
+	}
+
+	decoded, err := DecodeCBORMessage(b)
+	if err != nil {
+		t.Fatalf("DecodeCBORMessage error: %v", err)
+	}
+	if !decoded.IsCBOR() || decoded.Type != Notification || decoded.Route != BookOrderRoute {
+		t.Fatalf("wrong decoded message %s", decoded)
+	}
+	decodedNote := new(BookOrderNote)
+	if err := decoded.Unmarshal(decodedNote); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !bytes.Equal(decodedNote.OrderID, oid) || decodedNote.Seq != note.Seq ||
+		decodedNote.MarketID != note.MarketID || decodedNote.TradeNote != note.TradeNote {
+		t.Fatalf("wrong decoded note %+v", decodedNote)
+	}
+
+	// Responses
+	candles := &WireCandles{StartStamps: []uint64{1, 2}, EndRates: []uint64{3, 4}}
+	msg, _ = NewResponseCBOR(10, candles, nil)
+	b, _ = msg.EncodeCBOR()
+	decoded, err = DecodeCBORMessage(b)
+	if err != nil {
+		t.Fatalf("DecodeCBORMessage error: %v", err)
+	}
+	decodedCandles := new(WireCandles)
+	if err := decoded.UnmarshalResult(decodedCandles); err != nil {
+		t.Fatalf("UnmarshalResult error: %v", err)
+	}
+	if decoded.ID != 10 || len(decodedCandles.StartStamps) != 2 || decodedCandles.EndRates[1] != 4 {
+		t.Fatalf("wrong decoded candles %+v", decodedCandles)
+	}
+
+	msg, _ = NewResponseCBOR(11, nil, NewError(RPCInternalError, "oops"))
+	b, _ = msg.EncodeCBOR()
+	decoded, _ = DecodeCBORMessage(b)
+	var mErr *Error
+	if err := decoded.UnmarshalResult(decodedCandles); !errors.As(err, &mErr) || mErr.Code != RPCInternalError {
+		t.Fatalf("expected an RPCInternalError, got %v", err)
+	}
+
+	if _, err := DecodeCBORMessage([]byte(jsonB)); err == nil {
+		t.Fatalf("no error decoding JSON as CBOR")
+	}
+}
@@ -99,6 +99,17 @@ const (
 	RPCUpdateRunningBotInvError          // 81
 	RPCMMStatusError                     // 82
 	RPCBridgeError                       // 83
+	RPCMixingStatsError                  // 84
+	RPCAccountExportError                // 85
+	RPCAccountImportError                // 86
+	RPCReconfigureWalletError            // 87
+	RPCTakeActionError                   // 88
+	RPCUpdateBotConfigError              // 89
+	RPCRemoveBotConfigError              // 90
+	RPCPermissionDenied                  // 91
+	RPCOrderHistoryError                 // 92
+	RPCDegradedStatus                    // 93
+	RPCRecoveryKeysError                 // 94
 )
 
 // Routes are destinations for a "payload" of data. The type of data being
@@ -190,6 +201,10 @@ const (
 	// ScoreChangeRoute is a server-originating notificdation sent to a
 	// connected user when their score changes.
 	ScoreChangeRoute = "scorechanged"
+	// ReputationRoute is the client-originating request-type message
+	// requesting a breakdown of the user's score. Only servers with
+	// CapReputation handle it.
+	ReputationRoute = "reputation"
 	// ConfigRoute is the client-originating request-type message requesting the
 	// DEX configuration information.
 	ConfigRoute = "config"
@@ -210,6 +225,10 @@ const (
 	// client of an upcoming trade resumption. This is part of the
 	// subscription-based orderbook notification feed.
 	ResumptionRoute = "resumption"
+	// ConfigUpdateRoute is the DEX-originating notification-type message
+	// delivering the DEX's updated configuration, a ConfigResult, when markets
+	// are added, modified, or delisted while the DEX is running.
+	ConfigUpdateRoute = "config_update"
 	// NotifyRoute is the DEX-originating notification-type message
 	// delivering text messages from the operator.
 	NotifyRoute = "notify"
@@ -231,6 +250,15 @@ const (
 	// CandlesRoute is the HTTP request to get the set of candlesticks
 	// representing market activity history.
 	CandlesRoute = "candles"
+	// TradesRoute is the HTTP or WebSocket request to get a page of a
+	// market's trade history.
+	TradesRoute = "trades"
+	// TickersRoute is the HTTP or WebSocket request to get the 24-hour
+	// statistics and best rates for the DEX's markets.
+	TickersRoute = "tickers"
+	// DepthRoute is the HTTP or WebSocket request to get a market's order book
+	// aggregated by rate.
+	DepthRoute = "depth"
 )
 
 const errNullRespPayload = dex.ErrorKind("null response payload")
@@ -352,6 +380,8 @@ type Message struct {
 	// scheme. The old way was to sign individual payloads. Which is used
 	// depends on the route.
 	Sig dex.Bytes `json:"sig"`
+	// cbor is true if the Payload is CBOR-encoded. See CBORSubprotocol.
+	cbor bool
 }
 
 // DecodeMessage decodes a *Message from JSON-formatted bytes. Note that
@@ -411,11 +441,14 @@ func NewResponse(id uint64, result any, rpcErr *Error) (*Message, error) {
 
 // Response attempts to decode the payload to a *ResponsePayload. Response will
 // return an error if the Type is not Response. It is an error if the Message's
-// Payload is []byte("null").
+// Payload is []byte("null"). If the Message is CBOR-encoded, so is the Result.
 func (msg *Message) Response() (*ResponsePayload, error) {
 	if msg.Type != Response {
 		return nil, fmt.Errorf("invalid type %d for ResponsePayload", msg.Type)
 	}
+	if msg.cbor {
+		return msg.cborResponse()
+	}
 	resp := new(ResponsePayload)
 	err := json.Unmarshal(msg.Payload, &resp)
 	if err != nil {
@@ -447,7 +480,7 @@ func NewNotification(route string, payload any) (*Message, error) {
 // the payload interface must contain a pointer. If it is a pointer to a
 // pointer, it may become nil for a Message.Payload of []byte("null").
 func (msg *Message) Unmarshal(payload any) error {
-	return json.Unmarshal(msg.Payload, payload)
+	return msg.decode(msg.Payload, payload)
 }
 
 // UnmarshalResult is a convenience method for decoding the Result field of a
@@ -460,11 +493,16 @@ func (msg *Message) UnmarshalResult(result any) error {
 	if resp.Error != nil {
 		return fmt.Errorf("rpc error: %w", resp.Error)
 	}
-	return json.Unmarshal(resp.Result, result)
+	return msg.decode(resp.Result, result)
 }
 
-// String prints the message as a JSON-encoded string.
+// String prints the message as a JSON-encoded string. The payload of a
+// CBOR-encoded message is omitted.
 func (msg *Message) String() string {
+	if msg.cbor {
+		return fmt.Sprintf(`{"type":%d,"route":%q,"id":%d,"payload":"<%d bytes CBOR>"}`,
+			msg.Type, msg.Route, msg.ID, len(msg.Payload))
+	}
 	b, err := json.Marshal(msg)
 	if err != nil {
 		return "[Message decode error]"
@@ -932,9 +970,43 @@ type PreimageResponse struct {
 // Connect is the payload for a client-originating ConnectRoute request.
 type Connect struct {
 	Signature
-	AccountID  Bytes  `json:"accountid"`
+	AccountID Bytes `json:"accountid"`
+	// APIVersion is the highest API version that the client supports.
 	APIVersion uint16 `json:"apiver"`
 	Time       uint64 `json:"timestamp"`
+	// Capabilities are the protocol features that the client supports. The
+	// Capabilities are not part of the signed serialization, so that older
+	// servers can verify the signature.
+	Capabilities Capabilities `json:"caps,omitempty"`
+}
+
+// Capabilities are flags for optional protocol features. A client advertises
+// the features it supports in its connect request, and the server responds
+// with the features that both it and the client support. New message types and
+// fields are rolled out behind a flag so that they are only used with
+// counterparts that understand them, and old ones are deprecated by no longer
+// advertising their flag.
+type Capabilities uint64
+
+const (
+	// CapBookChecksum indicates that epoch_report notifications include a
+	// checksum of the order book.
+	CapBookChecksum Capabilities = 1 << iota
+	// CapReputation indicates that the server handles the ReputationRoute
+	// request.
+	CapReputation
+)
+
+// Has is true if all of the specified capabilities are set.
+func (c Capabilities) Has(caps Capabilities) bool {
+	return c&caps == caps
+}
+
+// NegotiateAPI determines the API version and capabilities for a connection
+// from the highest API version and capabilities supported by each side. The
+// lower of the versions is used, with the capabilities supported by both.
+func NegotiateAPI(ver uint16, caps Capabilities, peerVer uint16, peerCaps Capabilities) (uint16, Capabilities) {
+	return min(ver, peerVer), caps & peerCaps
 }
 
 // Serialize serializes the Connect data.
@@ -967,6 +1039,11 @@ type ConnectResult struct {
 	Score               int32               `json:"score"`
 	ActiveBonds         []*Bond             `json:"activeBonds"`
 	Reputation          *account.Reputation `json:"reputation"`
+	// APIVersion and Capabilities are the negotiated API version and
+	// capabilities for the connection. See NegotiateAPI. Older servers do not
+	// set these.
+	APIVersion   uint16       `json:"apiver,omitempty"`
+	Capabilities Capabilities `json:"caps,omitempty"`
 }
 
 // TierChangedNotification is the dex-originating notification sent when the
@@ -1004,6 +1081,58 @@ func (tc *ScoreChangedNotification) Serialize() []byte {
 	return append(b, uint32Bytes(uint32(tc.Reputation.Score))...)
 }
 
+// ReputationResult is the response to a ReputationRoute request. It is a
+// breakdown of the user's score into the recent outcomes that count toward it.
+// Only the most recent MatchLimit match outcomes and OrderLimit orders are
+// scored, so older outcomes stop counting as new ones are added.
+type ReputationResult struct {
+	Reputation *account.Reputation `json:"reputation"`
+	// PenaltyThreshold is the magnitude of negative score that results in one
+	// penalty, a reduction in tier.
+	PenaltyThreshold int32  `json:"penaltyThreshold"`
+	MatchLimit       uint32 `json:"matchLimit"`
+	OrderLimit       uint32 `json:"orderLimit"`
+	// Matches are the scored swap outcomes, newest first.
+	Matches []*ScoredMatch `json:"matches"`
+	// PreimageOrders is the number of orders scored for preimage misses, and
+	// PreimageMisses are the orders with a miss, newest first.
+	PreimageOrders uint32         `json:"preimageOrders"`
+	PreimageMisses []*ScoredOrder `json:"preimageMisses"`
+	// CancelRate is not set if the server does not score cancellation rates.
+	CancelRate *CancelRateScore `json:"cancelRate,omitempty"`
+}
+
+// ScoredMatch is a swap outcome that counts toward a user's score. Value is in
+// atomic units of the base asset.
+type ScoredMatch struct {
+	MatchID Bytes  `json:"matchID"`
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+	Stamp   uint64 `json:"stamp"`
+	Value   uint64 `json:"value"`
+	Outcome string `json:"outcome"`
+	Score   int32  `json:"score"`
+}
+
+// ScoredOrder is an order with a preimage miss that counts toward a user's
+// score.
+type ScoredOrder struct {
+	OrderID Bytes  `json:"orderID"`
+	Stamp   uint64 `json:"stamp"`
+	Score   int32  `json:"score"`
+}
+
+// CancelRateScore is the user's cancellation rate and its effect on their
+// score. The rate is only scored once there are more than GraceLimit orders,
+// and is penalized when the ratio of cancels to orders exceeds Threshold.
+type CancelRateScore struct {
+	Orders     uint32  `json:"orders"`
+	Cancels    uint32  `json:"cancels"`
+	GraceLimit uint32  `json:"graceLimit"`
+	Threshold  float64 `json:"threshold"`
+	Score      int32   `json:"score"`
+}
+
 // PenaltyNote is the payload of a Penalty notification.
 type PenaltyNote struct {
 	Signature
@@ -1225,6 +1354,34 @@ type Market struct {
 	MarketBuyBuffer float64 `json:"buybuffer"`
 	ParcelSize      uint32  `json:"parcelSize"`
 	MarketStatus    `json:"status"`
+	// ScheduledChange is set when a change to the market's configuration is
+	// scheduled. The changes take effect when the market resumes trading
+	// after the FinalEpoch.
+	ScheduledChange *MarketChange `json:"scheduledchange,omitempty"`
+	// Maintenance is set when a maintenance window is scheduled for the
+	// market, and until trading resumes.
+	Maintenance *Maintenance `json:"maintenance,omitempty"`
+}
+
+// Maintenance describes a market's scheduled maintenance window. The market is
+// suspended after FinalEpoch, and trading resumes at the first epoch that
+// starts at or after ResumeTime.
+type Maintenance struct {
+	FinalEpoch  uint64 `json:"finalepoch"`
+	SuspendTime uint64 `json:"suspendtime"` // milliseconds
+	ResumeTime  uint64 `json:"resumetime"`  // milliseconds
+	Persist     bool   `json:"persistbook"`
+	Reason      string `json:"reason"`
+}
+
+// MarketChange describes a scheduled change to a market's configuration. Only
+// the changed parameters are set.
+type MarketChange struct {
+	FinalEpoch uint64 `json:"finalepoch"`
+	LotSize    uint64 `json:"lotsize,omitempty"`
+	RateStep   uint64 `json:"ratestep,omitempty"`
+	EpochLen   uint64 `json:"epochlen,omitempty"`
+	ParcelSize uint32 `json:"parcelSize,omitempty"`
 }
 
 // Running indicates if the market should be running given the known StartEpoch,
@@ -1304,6 +1461,82 @@ type CandlesRequest struct {
 	QuoteID    uint32 `json:"quoteID"`
 	BinSize    string `json:"binSize"`
 	NumCandles int    `json:"numCandles,omitempty"` // default and max defined in apidata.
+	// Before is a time in milliseconds. If set, the stored candles that end at
+	// or before Before are returned, so that history older than that retained
+	// by the server's candle caches may be requested a page at a time.
+	Before uint64 `json:"before,omitempty"`
+}
+
+// TradesRequest is a data API request for a page of a market's trade history,
+// most recent first.
+type TradesRequest struct {
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+	// Before is the ID of a trade, such as a TradesResult's Next. If set, the
+	// page starts with the trade preceding it. Otherwise, the page starts with
+	// the most recent trade.
+	Before Bytes `json:"before,omitempty"`
+	Limit  int   `json:"limit,omitempty"` // default and max defined in apidata.
+}
+
+// MarketTrade is a trade in a market's trade history. Stamp is the end of the
+// epoch in which the orders were matched.
+type MarketTrade struct {
+	ID        Bytes  `json:"id"`
+	Stamp     uint64 `json:"stamp"`
+	Rate      uint64 `json:"rate"`
+	Qty       uint64 `json:"qty"`
+	QuoteQty  uint64 `json:"quoteQty"`
+	TakerSell bool   `json:"takerSell"`
+}
+
+// TradesResult is the response to a TradesRequest. If there may be more
+// trades, Next is the ID to use as the Before of the request for the next page.
+type TradesResult struct {
+	Trades []*MarketTrade `json:"trades"`
+	Next   Bytes          `json:"next,omitempty"`
+}
+
+// Ticker is a market's 24-hour statistics and best rates. A slice of Ticker are
+// sent as the response to the TickersRoute request. Volumes are in atomic units
+// of the base asset, and QuoteVol24 in atomic units of the quote asset.
+type Ticker struct {
+	Stamp      uint64  `json:"stamp"`
+	BaseID     uint32  `json:"baseID"`
+	QuoteID    uint32  `json:"quoteID"`
+	Rate       uint64  `json:"rate"`
+	Bid        uint64  `json:"bid"`
+	Ask        uint64  `json:"ask"`
+	Change24   float64 `json:"change24"`
+	Vol24      uint64  `json:"vol24"`
+	QuoteVol24 uint64  `json:"quoteVol24"`
+	High24     uint64  `json:"high24"`
+	Low24      uint64  `json:"low24"`
+}
+
+// DepthRequest is a data API request for a market's order book aggregated by
+// rate.
+type DepthRequest struct {
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+	Levels  int    `json:"levels,omitempty"` // per side. default and max defined in apidata.
+}
+
+// DepthLevel is the booked quantity of all orders at a rate.
+type DepthLevel struct {
+	Rate   uint64 `json:"rate"`
+	Qty    uint64 `json:"qty"`
+	Orders int    `json:"orders"`
+}
+
+// Depth is the response to a DepthRequest. Bids and Asks are ordered from the
+// best rate.
+type Depth struct {
+	Stamp   uint64        `json:"stamp"`
+	BaseID  uint32        `json:"baseID"`
+	QuoteID uint32        `json:"quoteID"`
+	Bids    []*DepthLevel `json:"bids"`
+	Asks    []*DepthLevel `json:"asks"`
 }
 
 // Candle is a statistical history of a specified period of market activity.
@@ -1375,6 +1608,11 @@ type EpochReportNote struct {
 	// MatchSummary: [rate, quantity]. Quantity is signed. Negative means that
 	// the maker was a sell order.
 	MatchSummary [][2]int64 `json:"matchSummary"`
+	// BookSeq is the sequence number of the last book update sent before the
+	// report, and BookChecksum is the order.BookChecksum of the book after
+	// that update. Clients use these to detect a drifted book.
+	BookSeq      uint64 `json:"bookSeq,omitempty"`
+	BookChecksum Bytes  `json:"bookChecksum,omitempty"`
 	Candle
 }
 
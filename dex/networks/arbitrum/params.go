@@ -0,0 +1,162 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package arbitrum
+
+import (
+	"math/big"
+
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	ArbitrumBipID = 9001
+)
+
+// These are the chain IDs of the Arbitrum networks.
+const (
+	MainnetChainID = 42161  // Arbitrum One
+	TestnetChainID = 421614 // Arbitrum Sepolia
+)
+
+var (
+	// ChainIDs is a map of the network name to it's chain ID. There is no
+	// Arbitrum simnet harness.
+	ChainIDs = map[dex.Network]int64{
+		dex.Mainnet: MainnetChainID,
+		dex.Testnet: TestnetChainID,
+	}
+
+	// Arbitrum's native asset is bridged ETH, so the units are ETH's.
+	UnitInfo = dexeth.UnitInfo
+
+	// Execution on Arbitrum uses the same gas as on Ethereum, so the
+	// Ethereum gas limits are used for the swap contract. Arbitrum also
+	// charges for posting the transaction data to L1, in L2 gas. That
+	// component depends on the L1 fee rate, so it is estimated when each
+	// transaction is sent rather than included here.
+	VersionedGases = map[uint32]*dexeth.Gases{
+		1: dexeth.VersionedGases[1],
+	}
+
+	// ContractAddresses are the version 1 swap contract addresses. The
+	// contract is deployed with client/asset/eth/cmd/deploy --chain arbitrum.
+	// Until it is, the wallet can send and receive, but not trade.
+	ContractAddresses = map[uint32]map[dex.Network]common.Address{
+		1: {},
+	}
+
+	MultiBalanceAddresses = map[dex.Network]common.Address{}
+
+	usdcTokenID, _ = dex.BipSymbolID("usdc.arbitrum")
+	usdtTokenID, _ = dex.BipSymbolID("usdt.arbitrum")
+
+	Tokens = map[uint32]*dexeth.Token{
+		usdcTokenID: TokenUSDC,
+		usdtTokenID: TokenUSDT,
+	}
+
+	// TokenUSDC is Circle's native USDC on Arbitrum, not the bridged USDC.e.
+	TokenUSDC = &dexeth.Token{
+		EVMFactor: new(int64),
+		Token: &dex.Token{
+			ParentID: ArbitrumBipID,
+			Name:     "USDC",
+			UnitInfo: dex.UnitInfo{
+				AtomicUnit: "µUSD",
+				Conventional: dex.Denomination{
+					Unit:             "USDC",
+					ConversionFactor: 1e6,
+				},
+				Alternatives: []dex.Denomination{
+					{
+						Unit:             "cents",
+						ConversionFactor: 1e2,
+					},
+				},
+				FeeRateDenom: "gas",
+			},
+		},
+		NetTokens: map[dex.Network]*dexeth.NetToken{
+			dex.Mainnet: {
+				Address: common.HexToAddress("0xaf88d065e77c8cC2239327C5EDb3A432268e5831"), // https://arbiscan.io/token/0xaf88d065e77c8cC2239327C5EDb3A432268e5831
+				SwapContracts: map[uint32]*dexeth.SwapContract{
+					1: {
+						// Not measured on Arbitrum yet.
+						Gas: dexeth.CustomTokenGases,
+					},
+				},
+			},
+			dex.Testnet: {
+				Address: common.HexToAddress("0x75faf114eafb1BDbe2F0316DF893fd58CE46AA4d"), // https://sepolia.arbiscan.io/token/0x75faf114eafb1BDbe2F0316DF893fd58CE46AA4d
+				SwapContracts: map[uint32]*dexeth.SwapContract{
+					1: {
+						Gas: dexeth.CustomTokenGases,
+					},
+				},
+			},
+		},
+	}
+
+	TokenUSDT = &dexeth.Token{
+		EVMFactor: new(int64),
+		Token: &dex.Token{
+			ParentID: ArbitrumBipID,
+			Name:     "Tether",
+			UnitInfo: dex.UnitInfo{
+				AtomicUnit: "microUSD",
+				Conventional: dex.Denomination{
+					Unit:             "USDT",
+					ConversionFactor: 1e6,
+				},
+				Alternatives: []dex.Denomination{
+					{
+						Unit:             "cents",
+						ConversionFactor: 1e2,
+					},
+				},
+				FeeRateDenom: "gas",
+			},
+		},
+		NetTokens: map[dex.Network]*dexeth.NetToken{
+			dex.Mainnet: {
+				Address: common.HexToAddress("0xFd086bC7CD5C481DCC9C85ebE478A1C0b69FCbb9"), // https://arbiscan.io/token/0xFd086bC7CD5C481DCC9C85ebE478A1C0b69FCbb9
+				SwapContracts: map[uint32]*dexeth.SwapContract{
+					1: {
+						// Not measured on Arbitrum yet.
+						Gas: dexeth.CustomTokenGases,
+					},
+				},
+			},
+		},
+	}
+
+	// MainnetChainConfig is the chain configuration for Arbitrum One. All
+	// forks relevant to transaction signing and fee calculation are active
+	// from genesis.
+	MainnetChainConfig = chainConfig(MainnetChainID)
+	// TestnetChainConfig is the chain configuration for Arbitrum Sepolia.
+	TestnetChainConfig = chainConfig(TestnetChainID)
+)
+
+func chainConfig(chainID int64) *params.ChainConfig {
+	return &params.ChainConfig{
+		ChainID:             big.NewInt(chainID),
+		HomesteadBlock:      big.NewInt(0),
+		DAOForkBlock:        nil,
+		DAOForkSupport:      true,
+		EIP150Block:         big.NewInt(0),
+		EIP155Block:         big.NewInt(0),
+		EIP158Block:         big.NewInt(0),
+		ByzantiumBlock:      big.NewInt(0),
+		ConstantinopleBlock: big.NewInt(0),
+		PetersburgBlock:     big.NewInt(0),
+		IstanbulBlock:       big.NewInt(0),
+		MuirGlacierBlock:    big.NewInt(0),
+		BerlinBlock:         big.NewInt(0),
+		LondonBlock:         big.NewInt(0),
+	}
+}
@@ -0,0 +1,148 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"decred.org/dcrdex/dex"
+	bondv0 "decred.org/dcrdex/dex/networks/eth/contracts/bond"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BondVersion is the version of ETH fidelity bonds. Version 0 bonds are held
+// by the ETHBondV0 contract.
+const BondVersion = 0
+
+const (
+	CreateBondMethodName = "createBond"
+	RefundBondMethodName = "refundBond"
+	BondsMethodName      = "bonds"
+)
+
+// Gas limits for the bond contract methods. createBond writes three fresh
+// storage slots and refundBond clears one and transfers the value. These must
+// be at least the gas measured by TestBondGas in the client's harness tests
+// before the contract is deployed on mainnet.
+const (
+	CreateBondGas = 120_000
+	RefundBondGas = 60_000
+)
+
+// BondContractAddresses are the addresses of the ETHBondV0 contract. ETH bonds
+// are only supported on networks with an entry. The contract is not yet
+// deployed on mainnet or testnet. The simnet address is read from the harness
+// by MaybeReadSimnetAddrs.
+var BondContractAddresses = map[dex.Network]common.Address{}
+
+// BondABI is the parsed ABI of the ETHBondV0 contract.
+var BondABI = initBondABI()
+
+func initBondABI() *abi.ABI {
+	bondABI, err := abi.JSON(strings.NewReader(bondv0.ETHBondABI))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse bond abi: %v", err))
+	}
+	return &bondABI
+}
+
+// BondState is the state of a bond in the bond contract. A bond that was never
+// created has a zero Owner. A refunded bond has a zero Value.
+type BondState struct {
+	AcctID   [32]byte
+	Value    *big.Int
+	Owner    common.Address
+	LockTime uint64
+}
+
+// BondID is the contract's ID for a bond with the provided serialized public
+// key.
+func BondID(pubKey []byte) [32]byte {
+	return crypto.Keccak256Hash(pubKey)
+}
+
+// PackCreateBondData packs the calldata for the createBond method.
+func PackCreateBondData(acctID, bondID [32]byte, lockTime uint64) ([]byte, error) {
+	return BondABI.Pack(CreateBondMethodName, acctID, bondID, lockTime)
+}
+
+// PackRefundBondData packs the calldata for the refundBond method.
+func PackRefundBondData(bondID [32]byte) ([]byte, error) {
+	return BondABI.Pack(RefundBondMethodName, bondID)
+}
+
+// PackBondsData packs the calldata to retrieve a bond's state.
+func PackBondsData(bondID [32]byte) ([]byte, error) {
+	return BondABI.Pack(BondsMethodName, bondID)
+}
+
+// UnpackBondsResult unpacks the result of a call to the bonds method.
+func UnpackBondsResult(res []byte) (*BondState, error) {
+	out, err := BondABI.Unpack(BondsMethodName, res)
+	if err != nil {
+		return nil, err
+	}
+	const numOutputs = 4
+	if len(out) != numOutputs {
+		return nil, fmt.Errorf("expected %d outputs but got %d", numOutputs, len(out))
+	}
+	acctID, ok := out[0].([32]byte)
+	if !ok {
+		return nil, fmt.Errorf("expected account ID of type [32]byte but got %T", out[0])
+	}
+	value, ok := out[1].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type *big.Int but got %T", out[1])
+	}
+	owner, ok := out[2].(common.Address)
+	if !ok {
+		return nil, fmt.Errorf("expected owner of type common.Address but got %T", out[2])
+	}
+	lockTime, ok := out[3].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("expected lock time of type uint64 but got %T", out[3])
+	}
+	return &BondState{
+		AcctID:   acctID,
+		Value:    value,
+		Owner:    owner,
+		LockTime: lockTime,
+	}, nil
+}
+
+// ParseCreateBondData parses the calldata used to call the createBond method
+// of the bond contract.
+func ParseCreateBondData(calldata []byte) (acctID, bondID [32]byte, lockTime uint64, err error) {
+	decoded, err := ParseCallData(calldata, BondABI)
+	if err != nil {
+		return acctID, bondID, 0, fmt.Errorf("unable to parse call data: %v", err)
+	}
+	if decoded.Name != CreateBondMethodName {
+		return acctID, bondID, 0, fmt.Errorf("expected %v function but got %v", CreateBondMethodName, decoded.Name)
+	}
+	args := decoded.inputs
+	const numArgs = 3
+	if len(args) != numArgs {
+		return acctID, bondID, 0, fmt.Errorf("expected %v input args but got %v", numArgs, len(args))
+	}
+	var ok bool
+	if acctID, ok = args[0].value.([32]byte); !ok {
+		return acctID, bondID, 0, fmt.Errorf("expected first arg of type [32]byte but got %T", args[0].value)
+	}
+	if bondID, ok = args[1].value.([32]byte); !ok {
+		return acctID, bondID, 0, fmt.Errorf("expected second arg of type [32]byte but got %T", args[1].value)
+	}
+	if lockTime, ok = args[2].value.(uint64); !ok {
+		return acctID, bondID, 0, fmt.Errorf("expected third arg of type uint64 but got %T", args[2].value)
+	}
+	if lockTime == 0 {
+		return acctID, bondID, 0, errors.New("zero lock time")
+	}
+	return acctID, bondID, lockTime, nil
+}
@@ -0,0 +1,98 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"decred.org/dcrdex/dex/encode"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseCreateBondData(t *testing.T) {
+	var acctID, bondID [32]byte
+	copy(acctID[:], encode.RandomBytes(32))
+	bondID = BondID(encode.RandomBytes(33))
+	const lockTime uint64 = 1_700_000_000
+
+	calldata, err := PackCreateBondData(acctID, bondID, lockTime)
+	if err != nil {
+		t.Fatalf("PackCreateBondData error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		calldata []byte
+		wantErr  bool
+	}{{
+		name:     "ok",
+		calldata: calldata,
+	}, {
+		name:     "short calldata",
+		calldata: calldata[:len(calldata)-2],
+		wantErr:  true,
+	}, {
+		name:     "wrong function",
+		calldata: mustPackRefundBond(t, bondID),
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		parsedAcct, parsedBond, parsedLockTime, err := ParseCreateBondData(test.calldata)
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if parsedAcct != acctID || parsedBond != bondID || parsedLockTime != lockTime {
+			t.Fatalf("%s: wrong parsed values", test.name)
+		}
+	}
+}
+
+func mustPackRefundBond(t *testing.T, bondID [32]byte) []byte {
+	t.Helper()
+	calldata, err := PackRefundBondData(bondID)
+	if err != nil {
+		t.Fatalf("PackRefundBondData error: %v", err)
+	}
+	return calldata
+}
+
+func TestUnpackBondsResult(t *testing.T) {
+	var acctID [32]byte
+	copy(acctID[:], encode.RandomBytes(32))
+	owner := common.HexToAddress("345853e21b1d475582E71cC269124eD5e2dD3422")
+	value := big.NewInt(5e18)
+	const lockTime uint64 = 1_700_000_000
+
+	res, err := BondABI.Methods[BondsMethodName].Outputs.Pack(acctID, value, owner, lockTime)
+	if err != nil {
+		t.Fatalf("error packing outputs: %v", err)
+	}
+	state, err := UnpackBondsResult(res)
+	if err != nil {
+		t.Fatalf("UnpackBondsResult error: %v", err)
+	}
+	if state.AcctID != acctID || state.Value.Cmp(value) != 0 || state.Owner != owner || state.LockTime != lockTime {
+		t.Fatalf("wrong bond state %+v", state)
+	}
+
+	if _, err := UnpackBondsResult(res[:len(res)-32]); err == nil {
+		t.Fatalf("no error for truncated result")
+	}
+
+	// Make sure the method signatures match the contract.
+	for name, sig := range map[string]string{
+		CreateBondMethodName: "createBond(bytes32,bytes32,uint64)",
+		RefundBondMethodName: "refundBond(bytes32)",
+		BondsMethodName:      "bonds(bytes32)",
+	} {
+		if m := BondABI.Methods[name]; m.Sig != sig {
+			t.Fatalf("wrong signature for %s: %s", name, m.Sig)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: BlueOak-1.0.0
+// pragma should be as specific as possible to allow easier validation.
+pragma solidity = 0.8.18;
+
+// ETHBond creates a contract to be deployed on an ethereum network. After
+// deployed, it keeps a map of time-locked fidelity bonds that commit value to a
+// DEX account.
+//
+// A bond is created by sending value along with the DEX account ID, a unique
+// bond ID, and a lock time. The funds belong to the contract until the lock
+// time has passed, after which only the address that created the bond can
+// refund it. A bond ID can only ever be used once, even after the bond is
+// refunded, so a DEX server can rely on the bond ID to locate a bond and to
+// check whether it is still locked.
+//
+// This contract cannot be used by other contracts or by a third party
+// mediating the bond or multisig wallets.
+//
+// This code should be verifiable as resulting in a certain on-chain contract
+// by compiling with the correct version of solidity and comparing the
+// resulting byte code to the data in the original transaction.
+contract ETHBond {
+    // Bond holds the information related to one bond. The order of the struct
+    // fields is important to efficiently pack the struct into as few 256-bit
+    // slots as possible to reduce gas cost. In particular, the 160-bit address
+    // can pack with the 64-bit lock time.
+    struct Bond {
+        bytes32 acctID;
+        uint256 value;
+        address owner;
+        uint64 lockTime;
+    }
+
+    // bonds is a map of bond IDs to bonds. A bond with a zero value and a
+    // non-zero owner has been refunded.
+    mapping(bytes32 => Bond) public bonds;
+
+    // senderIsOrigin ensures that this contract cannot be used by other
+    // contracts, which reduces possible attack vectors.
+    modifier senderIsOrigin() {
+        require(tx.origin == msg.sender, "sender != origin");
+        _;
+    }
+
+    // createBond locks the value of the transaction for the DEX account acctID
+    // until lockTime. bondID must not have been used before.
+    function createBond(bytes32 acctID, bytes32 bondID, uint64 lockTime)
+        public
+        payable
+        senderIsOrigin()
+    {
+        require(msg.value > 0, "zero value");
+        require(lockTime > block.timestamp, "lock time passed");
+        require(bonds[bondID].owner == address(0), "bond exists");
+
+        bonds[bondID] = Bond(acctID, msg.value, msg.sender, lockTime);
+    }
+
+    // refundBond returns the value of an expired bond to its owner.
+    function refundBond(bytes32 bondID) public senderIsOrigin() {
+        Bond storage bond = bonds[bondID];
+        require(bond.owner == msg.sender, "not owner");
+        require(bond.value > 0, "already refunded");
+        require(block.timestamp >= bond.lockTime, "bond locked");
+
+        uint256 value = bond.value;
+        bond.value = 0;
+
+        (bool ok, ) = payable(msg.sender).call{value: value}("");
+        require(ok == true, "transfer failed");
+    }
+}
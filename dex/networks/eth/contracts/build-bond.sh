@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+#
+# 1. Updates bond/contract.go to reflect updated solidity code.
+# 2. Generates the bytecode for the ETHBondV0 contract. This is deployed by the
+#    simnet harness, and can be compared against the bytecode on chain in order
+#    to verify that the expected contract is deployed.
+
+PKG_NAME="bond"
+CONTRACT_NAME="ETHBond"
+SOLIDITY_FILE="./${CONTRACT_NAME}V0.sol"
+if [ ! -f ${SOLIDITY_FILE} ]
+then
+    echo "${SOLIDITY_FILE} does not exist" >&2
+    exit 1
+fi
+
+mkdir temp
+mkdir -p ${PKG_NAME}
+
+solc --abi --bin --bin-runtime --overwrite --optimize ${SOLIDITY_FILE} -o ./temp/
+
+abigen --abi ./temp/${CONTRACT_NAME}.abi --bin ./temp/${CONTRACT_NAME}.bin --pkg ${PKG_NAME} \
+ --type ${CONTRACT_NAME} --out ./${PKG_NAME}/contract.go
+
+BYTECODE=$(<./temp/${CONTRACT_NAME}.bin)
+echo "${BYTECODE}" | xxd -r -p > "${PKG_NAME}/contract.bin"
+
+rm -fr temp
@@ -0,0 +1,86 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"decred.org/dcrdex/dex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CustomTokenIDFlag is set in the asset ID of every user-defined token. IDs
+// with this bit set are not valid BIP-0044 coin types, so they can't collide
+// with a registered asset.
+const CustomTokenIDFlag = 1 << 31
+
+// maxTokenAtomicDecimals is the most decimal places a token's atomic unit
+// will have. The excess is handled with Token.EVMFactor.
+const maxTokenAtomicDecimals = 9
+
+// CustomTokenGases are the gas limits used for user-defined tokens with the
+// version 1 swap contract. The gas used by an arbitrary token contract is not
+// known ahead of time, so these are generous compared to the measured values
+// for the listed tokens.
+var CustomTokenGases = Gases{
+	Swap:      170_000,
+	SwapAdd:   50_000,
+	Redeem:    100_000,
+	RedeemAdd: 20_000,
+	Refund:    100_000,
+	Approve:   100_000,
+	Transfer:  110_000,
+}
+
+// CustomTokenID is the asset ID for a user-defined token. The ID depends only
+// on the parent asset and the token contract address, so that all clients and
+// servers will agree on it.
+func CustomTokenID(parentID uint32, addr common.Address) uint32 {
+	b := make([]byte, 4+common.AddressLength)
+	binary.BigEndian.PutUint32(b, parentID)
+	copy(b[4:], addr[:])
+	return binary.BigEndian.Uint32(crypto.Keccak256(b)[:4]) | CustomTokenIDFlag
+}
+
+// IsCustomTokenID checks whether the asset ID is for a user-defined token.
+func IsCustomTokenID(assetID uint32) bool {
+	return assetID&CustomTokenIDFlag != 0
+}
+
+// NewCustomToken creates the Token for a user-defined ERC20 token on the
+// specified network. The token supports only the version 1 swap contract.
+func NewCustomToken(parentID uint32, name, symbol string, decimals uint8, net dex.Network, addr common.Address) *Token {
+	atomicDecimals, evmFactor := int64(decimals), int64(0)
+	if atomicDecimals > maxTokenAtomicDecimals {
+		atomicDecimals, evmFactor = maxTokenAtomicDecimals, atomicDecimals-maxTokenAtomicDecimals
+	}
+	return &Token{
+		EVMFactor: &evmFactor,
+		Token: &dex.Token{
+			ParentID: parentID,
+			Name:     name,
+			UnitInfo: dex.UnitInfo{
+				AtomicUnit: "atoms",
+				Conventional: dex.Denomination{
+					Unit:             strings.ToUpper(symbol),
+					ConversionFactor: uint64(math.Pow10(int(atomicDecimals))),
+				},
+				FeeRateDenom: "gas",
+			},
+		},
+		NetTokens: map[dex.Network]*NetToken{
+			net: {
+				Address: addr,
+				SwapContracts: map[uint32]*SwapContract{
+					1: {
+						Gas: CustomTokenGases,
+					},
+				},
+			},
+		},
+	}
+}
@@ -87,6 +87,13 @@ var (
 		dex.Mainnet: common.HexToAddress("0x73bc803A2604b2c58B8680c3CE1b14489842EF16"), // tx 0xaf6cb861578c0ded0750397d7e044a7dd86c94aa47211d02188e146a2424dda4
 		dex.Testnet: common.HexToAddress("0x8Bd6F6dBe69588D94953EE289Fd3E1db3e8dB43D"), // tx 0x46a416344927a8d1f33865374e9b9e824249980da8d34f6c3214a1ee036ca5fe
 	}
+
+	// ENSRegistryAddresses are the addresses of the Ethereum Name Service
+	// registry. https://docs.ens.domains/learn/deployments
+	ENSRegistryAddresses = map[dex.Network]common.Address{
+		dex.Mainnet: common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"),
+		dex.Testnet: common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"), // Sepolia
+	}
 )
 
 var v0Gases = &Gases{
@@ -467,10 +467,20 @@ var Tokens = map[uint32]*Token{
 }
 
 // MaybeReadSimnetAddrs attempts to read the info files generated by the eth
-// simnet harness to populate swap contract and token addresses in
-// ContractAddresses and Tokens.
+// simnet harness to populate swap contract, bond contract, and token addresses
+// in ContractAddresses, BondContractAddresses, and Tokens.
 func MaybeReadSimnetAddrs() {
 	MaybeReadSimnetAddrsDir("eth", ContractAddresses, MultiBalanceAddresses, Tokens[usdcTokenID].NetTokens[dex.Simnet], Tokens[usdtTokenID].NetTokens[dex.Simnet])
+
+	// The bond contract is only deployed by the harness if it was built.
+	usr, err := user.Current()
+	if err != nil {
+		return
+	}
+	bondContractAddrFile := filepath.Join(usr.HomeDir, "dextest", "eth", "eth_bond_contract_address.txt")
+	if addr := maybeGetContractAddrFromFile(bondContractAddrFile); addr != (common.Address{}) {
+		BondContractAddresses[dex.Simnet] = addr
+	}
 }
 
 func MaybeReadSimnetAddrsDir(
@@ -808,3 +808,20 @@ func ExtractAddress(ord Order) string {
 	}
 	return trade.Address
 }
+
+// BookChecksum is a checksum of the booked orders on a market, which clients
+// use to verify that their copy of the book matches the server's. It is the
+// XOR of the hashes of each order's ID and remaining quantity, so it does not
+// depend on the order in which orders are added.
+type BookChecksum [hashSize]byte
+
+// Add adds a booked order with the given remaining quantity to the checksum.
+func (c *BookChecksum) Add(oid OrderID, remaining uint64) {
+	b := make([]byte, OrderIDSize+8)
+	copy(b, oid[:])
+	binary.BigEndian.PutUint64(b[OrderIDSize:], remaining)
+	h := blake256.Sum256(b)
+	for i := range c {
+		c[i] ^= h[i]
+	}
+}
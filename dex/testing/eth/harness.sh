@@ -21,6 +21,13 @@ ERC20_SWAP_V0=$(fileToHex "../../networks/erc20/contracts/v0/swap_contract.bin")
 TEST_TOKEN=$(fileToHex "../../networks/erc20/contracts/v0/token_contract.bin")
 MULTIBALANCE_BIN=$(fileToHex "../../networks/eth/contracts/multibalance/contract.bin")
 ETH_SWAP_V1=$(fileToHex "../../networks/eth/contracts/v1/contract.bin")
+# The bond contract bytecode is only present after running
+# dex/networks/eth/contracts/build-bond.sh.
+ETH_BOND_BIN_FILE="../../networks/eth/contracts/bond/contract.bin"
+ETH_BOND_V0=""
+if [ -f "${ETH_BOND_BIN_FILE}" ]; then
+  ETH_BOND_V0=$(fileToHex "${ETH_BOND_BIN_FILE}")
+fi
 
 export NODES_ROOT=~/dextest/eth
 
@@ -138,6 +145,11 @@ TEST_USDT_CONTRACT_HASH=$("${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${
 echo "Deploying MultiBalance contract."
 MULTIBALANCE_CONTRACT_HASH=$("${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${NODES_ROOT}/harness-ctl/deploy.js --exec deploy(\"${MULTIBALANCE_BIN}\")" | sed 's/"//g')
 
+if [ -n "${ETH_BOND_V0}" ]; then
+  echo "Deploying ETHBondV0 contract."
+  ETH_BOND_CONTRACT_HASH=$("${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${NODES_ROOT}/harness-ctl/deploy.js --exec deploy(\"${ETH_BOND_V0}\")" | sed 's/"//g')
+fi
+
 mine_pending_txs() {
   while true
   do
@@ -164,6 +176,14 @@ cat > "${NODES_ROOT}/eth_swap_contract_address_v1.txt" <<EOF
 ${ETH_SWAP_CONTRACT_ADDR_V1}
 EOF
 
+if [ -n "${ETH_BOND_V0}" ]; then
+  ETH_BOND_CONTRACT_ADDR=$("${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${NODES_ROOT}/harness-ctl/contractAddress.js --exec contractAddress(\"${ETH_BOND_CONTRACT_HASH}\")" | sed 's/"//g')
+  echo "ETH bond contract address is ${ETH_BOND_CONTRACT_ADDR}. Saving to ${NODES_ROOT}/eth_bond_contract_address.txt"
+  cat > "${NODES_ROOT}/eth_bond_contract_address.txt" <<EOF
+${ETH_BOND_CONTRACT_ADDR}
+EOF
+fi
+
 TEST_USDC_CONTRACT_ADDR=$("${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${NODES_ROOT}/harness-ctl/contractAddress.js --exec contractAddress(\"${TEST_USDC_CONTRACT_HASH}\")" | sed 's/"//g')
 echo "Test USDC contract address is ${TEST_USDC_CONTRACT_ADDR}. Saving to ${NODES_ROOT}/test_usdc_contract_address.txt"
 cat > "${NODES_ROOT}/test_usdc_contract_address.txt" <<EOF
@@ -0,0 +1,210 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package ws
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/quic-go/quic-go"
+)
+
+// The websocket protocol can be run over a QUIC stream instead of a TCP
+// connection. QUIC's loss recovery does not stall the connection for a lost
+// packet the way TCP does on lossy networks, and its combined transport and
+// TLS handshake makes reconnects faster. A server that accepts QUIC
+// connections advertises it with an Alt-Svc header on its websocket upgrade
+// responses, and the client can use QUIC when it next connects.
+
+const (
+	// QUICProtocol is the ALPN protocol of websocket connections over QUIC,
+	// and the protocol ID in the Alt-Svc header that advertises them.
+	QUICProtocol = "dcrdex-ws"
+	// quicStreamTimeout is how long a server waits for a new QUIC connection
+	// to open its stream.
+	quicStreamTimeout = 10 * time.Second
+	// quicHandshakeTimeout is how long to wait for a QUIC handshake.
+	quicHandshakeTimeout = 3 * time.Second
+	// quicIdleTimeout is how long a QUIC connection can be idle. The peers
+	// ping more often than this.
+	quicIdleTimeout = time.Minute
+)
+
+// quicConfig is the QUIC configuration for clients and servers. UDP may be
+// blocked, so a client gives up on the handshake quickly and falls back to
+// TCP.
+var quicConfig = &quic.Config{
+	HandshakeIdleTimeout: quicHandshakeTimeout,
+	MaxIdleTimeout:       quicIdleTimeout,
+	KeepAlivePeriod:      quicIdleTimeout / 3,
+}
+
+// quicTLSConfig is a copy of the TLS config for QUIC.
+func quicTLSConfig(tlsCfg *tls.Config) *tls.Config {
+	tlsCfg = tlsCfg.Clone()
+	tlsCfg.NextProtos = []string{QUICProtocol}
+	// QUIC requires TLS 1.3.
+	tlsCfg.MinVersion = tls.VersionTLS13
+	return tlsCfg
+}
+
+// quicStreamConn is a net.Conn for the stream of a QUIC connection with a
+// single stream.
+type quicStreamConn struct {
+	*quic.Stream
+	conn *quic.Conn
+}
+
+var _ net.Conn = (*quicStreamConn)(nil)
+
+// LocalAddr returns the local address of the QUIC connection.
+func (c *quicStreamConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the QUIC connection.
+func (c *quicStreamConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// Close closes the QUIC connection.
+func (c *quicStreamConn) Close() error {
+	c.Stream.CancelRead(0)
+	c.Stream.Close()
+	return c.conn.CloseWithError(0, "")
+}
+
+// DialQUIC connects to a websocket server over QUIC. The websocket handshake
+// is done over the returned net.Conn, without another layer of TLS.
+func DialQUIC(ctx context.Context, addr string, tlsCfg *tls.Config) (net.Conn, error) {
+	conn, err := quic.DialAddr(ctx, addr, quicTLSConfig(tlsCfg), quicConfig)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		conn.CloseWithError(0, "")
+		return nil, err
+	}
+	return &quicStreamConn{Stream: stream, conn: conn}, nil
+}
+
+// quicListener is a net.Listener for websocket connections over QUIC. Accept
+// returns the stream of each new QUIC connection.
+type quicListener struct {
+	ln     *quic.Listener
+	ctx    context.Context
+	cancel context.CancelFunc
+	conns  chan net.Conn
+}
+
+// ListenQUIC listens for websocket connections over QUIC on the UDP address.
+// The network is "udp", "udp4", or "udp6". The returned net.Listener can be
+// served by an http.Server like a TLS listener.
+func ListenQUIC(network, addr string, tlsCfg *tls.Config) (net.Listener, error) {
+	udpAddr, err := net.ResolveUDPAddr(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	udpConn, err := net.ListenUDP(network, udpAddr)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := quic.Listen(udpConn, quicTLSConfig(tlsCfg), quicConfig)
+	if err != nil {
+		udpConn.Close()
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &quicListener{
+		ln:     ln,
+		ctx:    ctx,
+		cancel: cancel,
+		conns:  make(chan net.Conn),
+	}
+	go l.acceptConns()
+	return l, nil
+}
+
+// acceptConns accepts QUIC connections until the listener is closed. Each
+// connection waits for its stream in its own goroutine, so that a slow client
+// does not hold up the others.
+func (l *quicListener) acceptConns() {
+	for {
+		conn, err := l.ln.Accept(l.ctx)
+		if err != nil {
+			return
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(l.ctx, quicStreamTimeout)
+			defer cancel()
+			stream, err := conn.AcceptStream(ctx)
+			if err != nil {
+				conn.CloseWithError(0, "")
+				return
+			}
+			select {
+			case l.conns <- &quicStreamConn{Stream: stream, conn: conn}:
+			case <-l.ctx.Done():
+				conn.CloseWithError(0, "")
+			}
+		}()
+	}
+}
+
+// Accept waits for the next QUIC connection's stream.
+func (l *quicListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.ctx.Done():
+		return nil, net.ErrClosed
+	}
+}
+
+// Close stops listening.
+func (l *quicListener) Close() error {
+	l.cancel()
+	return l.ln.Close()
+}
+
+// Addr is the UDP address of the listener.
+func (l *quicListener) Addr() net.Addr {
+	return l.ln.Addr()
+}
+
+// QUICAltSvc is the Alt-Svc header value that advertises websocket
+// connections over QUIC on a port.
+func QUICAltSvc(port string) string {
+	return fmt.Sprintf("%s=%q", QUICProtocol, ":"+port)
+}
+
+// QUICAltSvcAddr is the QUIC address of a host advertised in an upgrade
+// response's headers, or an empty string if QUIC was not advertised.
+func QUICAltSvcAddr(host string, header http.Header) string {
+	for _, v := range header.Values("Alt-Svc") {
+		for _, svc := range strings.Split(v, ",") {
+			proto, authority, found := strings.Cut(strings.TrimSpace(svc), "=")
+			if !found || proto != QUICProtocol {
+				continue
+			}
+			// Drop any parameters.
+			authority, _, _ = strings.Cut(authority, ";")
+			svcHost, port, err := net.SplitHostPort(strings.Trim(authority, `"`))
+			if err != nil || port == "" {
+				continue
+			}
+			if svcHost == "" {
+				svcHost = host
+			}
+			return net.JoinHostPort(svcHost, port)
+		}
+	}
+	return ""
+}
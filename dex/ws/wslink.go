@@ -27,6 +27,10 @@ const (
 	outBufferSize    = 128
 	defaultReadLimit = 8192
 	writeWait        = 5 * time.Second
+	// compressionThreshold is the size of the smallest message that is
+	// compressed on a connection with compression. Smaller messages don't
+	// compress enough to be worth it.
+	compressionThreshold = 512
 	// ErrPeerDisconnected will be returned if Send or Request is called on a
 	// disconnected link.
 	ErrPeerDisconnected = dex.ErrorKind("peer disconnected")
@@ -36,7 +40,12 @@ const (
 
 // websocket.Upgrader is the preferred method of upgrading a request to a
 // websocket connection.
-var upgrader = websocket.Upgrader{}
+var (
+	upgrader = websocket.Upgrader{}
+	// compressingUpgrader negotiates the permessage-deflate extension if the
+	// peer supports it.
+	compressingUpgrader = websocket.Upgrader{EnableCompression: true}
+)
 
 // Connection represents a websocket connection to a remote peer. In practice,
 // it is satisfied by *websocket.Conn. For testing, a stub can be used.
@@ -78,17 +87,24 @@ type WSLink struct {
 	handler func(*msgjson.Message) *msgjson.Error
 	// pingPeriod is how often to ping the peer.
 	pingPeriod time.Duration
+	// cbor is true if the msgjson.CBORSubprotocol was negotiated, so
+	// CBOR-encoded messages can be sent in binary frames.
+	cbor bool
 
 	RawHandler func([]byte)
 }
 
 type sendData struct {
 	data []byte
-	ret  chan<- error
+	// binary is true for a CBOR-encoded message, which is sent in a binary
+	// frame rather than a text frame.
+	binary bool
+	ret    chan<- error
 }
 
 // NewWSLink is a constructor for a new WSLink.
 func NewWSLink(addr string, conn Connection, pingPeriod time.Duration, handler func(*msgjson.Message) *msgjson.Error, logger dex.Logger) *WSLink {
+	sp, _ := conn.(interface{ Subprotocol() string })
 	return &WSLink{
 		addr:       addr,
 		log:        logger,
@@ -96,13 +112,21 @@ func NewWSLink(addr string, conn Connection, pingPeriod time.Duration, handler f
 		outChan:    make(chan *sendData, outBufferSize),
 		pingPeriod: pingPeriod,
 		handler:    handler,
+		cbor:       sp != nil && sp.Subprotocol() == msgjson.CBORSubprotocol,
 	}
 }
 
+// CBOR is true if the peer accepts CBOR-encoded messages. See
+// msgjson.CBORSubprotocol.
+func (c *WSLink) CBOR() bool {
+	return c.cbor
+}
+
 // Send sends the passed Message to the websocket peer. The actual writing of
 // the message on the peer's link occurs asynchronously. As such, a nil error
 // only indicates that the link is believed to be up and the message was
-// successfully marshalled.
+// successfully marshalled. A CBOR-encoded Message can only be sent if the peer
+// accepts CBOR.
 func (c *WSLink) Send(msg *msgjson.Message) error {
 	return c.send(msg, nil)
 }
@@ -114,7 +138,19 @@ func (c *WSLink) SendRaw(b []byte) error {
 	if c.Off() {
 		return ErrPeerDisconnected
 	}
-	return c.sendRaw(b, nil)
+	return c.sendRaw(&sendData{data: b})
+}
+
+// SendRawCBOR is like SendRaw for a CBOR-encoded message, as from
+// (*msgjson.Message).EncodeCBOR. The peer must accept CBOR.
+func (c *WSLink) SendRawCBOR(b []byte) error {
+	if !c.cbor {
+		return fmt.Errorf("peer %s does not accept CBOR", c.addr)
+	}
+	if c.Off() {
+		return ErrPeerDisconnected
+	}
+	return c.sendRaw(&sendData{data: b, binary: true})
 }
 
 // SendNow is like send, but it waits for the message to be written on the
@@ -127,13 +163,13 @@ func (c *WSLink) SendNow(msg *msgjson.Message) error {
 	return <-writeErrChan
 }
 
-// sendRaw sends raw bytes to a peer. Whether or not the peer is connected
-// should be checked before calling.
-func (c *WSLink) sendRaw(b []byte, writeErr chan<- error) error {
+// sendRaw queues raw bytes to send to a peer. Whether or not the peer is
+// connected should be checked before calling.
+func (c *WSLink) sendRaw(sd *sendData) error {
 	// NOTE: Without the stopped chan or access to the Context we are now
 	// racing after the c.Off check in the caller.
 	select {
-	case c.outChan <- &sendData{b, writeErr}:
+	case c.outChan <- sd:
 	case <-c.stopped:
 		return ErrPeerDisconnected
 	}
@@ -145,12 +181,22 @@ func (c *WSLink) send(msg *msgjson.Message, writeErr chan<- error) error {
 	if c.Off() {
 		return ErrPeerDisconnected
 	}
+	if msg != nil && msg.IsCBOR() { // a nil msg is sent as JSON null
+		if !c.cbor {
+			return fmt.Errorf("peer %s does not accept CBOR", c.addr)
+		}
+		b, err := msg.EncodeCBOR()
+		if err != nil {
+			return err
+		}
+		return c.sendRaw(&sendData{data: b, binary: true, ret: writeErr})
+	}
 	b, err := json.Marshal(msg)
 	if err != nil {
 		return err
 	}
 
-	return c.sendRaw(b, writeErr)
+	return c.sendRaw(&sendData{data: b, ret: writeErr})
 }
 
 // SendError sends the msgjson.Error to the peer in a ResponsePayload.
@@ -256,7 +302,7 @@ out:
 			break out
 		}
 		// Block until a message is received or an error occurs.
-		_, msgBytes, err := c.conn.ReadMessage()
+		msgType, msgBytes, err := c.conn.ReadMessage()
 		if err != nil {
 			// Only log the error if it is unexpected (not a disconnect).
 			if websocket.IsCloseError(err, websocket.CloseGoingAway,
@@ -283,7 +329,11 @@ out:
 		// will be accepted by the server, though failure to decode does not force
 		// a disconnect.
 		msg := new(msgjson.Message)
-		err = json.Unmarshal(msgBytes, msg)
+		if msgType == websocket.BinaryMessage && c.cbor {
+			msg, err = msgjson.DecodeCBORMessage(msgBytes)
+		} else {
+			err = json.Unmarshal(msgBytes, msg)
+		}
 		if err != nil {
 			c.SendError(1, msgjson.NewError(msgjson.RPCParseError, "failed to parse message"))
 			continue
@@ -337,7 +387,11 @@ func (c *WSLink) outHandler(ctx context.Context) {
 			return
 		}
 		c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-		err := c.conn.WriteMessage(websocket.TextMessage, sd.data)
+		msgType := websocket.TextMessage
+		if sd.binary {
+			msgType = websocket.BinaryMessage
+		}
+		err := c.conn.WriteMessage(msgType, sd.data)
 		if err != nil {
 			lostCount++
 			relayError(sd.ret, err)
@@ -491,11 +545,61 @@ func (c *WSLink) SetReadLimit(limit int64) {
 	c.conn.SetReadLimit(limit)
 }
 
+// compressingConn is a websocket connection with permessage-deflate. Only
+// messages of at least compressionThreshold bytes are compressed.
+type compressingConn struct {
+	*websocket.Conn
+}
+
+// WriteMessage writes a message, compressing it if it's large enough. Like
+// (*websocket.Conn).WriteMessage, it must not be called concurrently.
+func (c *compressingConn) WriteMessage(messageType int, data []byte) error {
+	c.EnableWriteCompression(len(data) >= compressionThreshold)
+	return c.Conn.WriteMessage(messageType, data)
+}
+
 // NewConnection attempts to to upgrade the http connection to a websocket
 // Connection. If the upgrade fails, a reply will be sent with an appropriate
 // error code.
 func NewConnection(w http.ResponseWriter, r *http.Request, readTimeout time.Duration) (Connection, error) {
-	ws, err := upgrader.Upgrade(w, r, nil)
+	return newConnection(upgrader, w, r, readTimeout, nil)
+}
+
+// NewCompressedConnection is like NewConnection, but negotiates
+// permessage-deflate compression if the peer supports it. Compression trades
+// CPU and memory for bandwidth, which suits e.g. browsers on mobile networks.
+func NewCompressedConnection(w http.ResponseWriter, r *http.Request, readTimeout time.Duration) (Connection, error) {
+	return newConnection(compressingUpgrader, w, r, readTimeout, nil)
+}
+
+// ConnectionConfig is the configuration for NewConnectionWithConfig.
+type ConnectionConfig struct {
+	// Compression negotiates permessage-deflate compression if the peer
+	// supports it, as with NewCompressedConnection.
+	Compression bool
+	// ResponseHeader is added to the upgrade response, e.g. an Alt-Svc header
+	// from QUICAltSvc.
+	ResponseHeader http.Header
+	// CBOR selects the msgjson.CBORSubprotocol if the peer requests it, so
+	// that CBOR-encoded messages can be sent to the peer. See (*WSLink).CBOR.
+	CBOR bool
+}
+
+// NewConnectionWithConfig is like NewConnection, with the options of the
+// ConnectionConfig.
+func NewConnectionWithConfig(w http.ResponseWriter, r *http.Request, readTimeout time.Duration, cfg *ConnectionConfig) (Connection, error) {
+	u := upgrader
+	if cfg.Compression {
+		u = compressingUpgrader
+	}
+	if cfg.CBOR {
+		u.Subprotocols = []string{msgjson.CBORSubprotocol}
+	}
+	return newConnection(u, w, r, readTimeout, cfg.ResponseHeader)
+}
+
+func newConnection(upgrader websocket.Upgrader, w http.ResponseWriter, r *http.Request, readTimeout time.Duration, responseHeader http.Header) (Connection, error) {
+	ws, err := upgrader.Upgrade(w, r, responseHeader)
 	if err != nil {
 		var hsErr websocket.HandshakeError
 		if errors.As(err, &hsErr) {
@@ -527,5 +631,8 @@ func NewConnection(w http.ResponseWriter, r *http.Request, readTimeout time.Dura
 
 	// Do not set an initial read deadline until pinging begins.
 
+	if upgrader.EnableCompression {
+		return &compressingConn{ws}, nil
+	}
 	return ws, nil
 }
@@ -4,6 +4,7 @@
 package ws
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -11,7 +12,10 @@ import (
 	"io"
 	"math"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -277,3 +281,65 @@ func TestSendRaw(t *testing.T) {
 		}
 	}
 }
+
+func TestCompressedConnection(t *testing.T) {
+	conns := make(chan Connection, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := NewCompressedConnection(w, r, time.Minute)
+		if err != nil {
+			t.Errorf("error upgrading connection: %v", err)
+			return
+		}
+		conns <- conn
+	}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	for _, compress := range []bool{true, false} {
+		dialer := &websocket.Dialer{EnableCompression: compress}
+		cl, resp, err := dialer.Dial(url, nil)
+		if err != nil {
+			t.Fatalf("dial error: %v", err)
+		}
+		ext := resp.Header.Get("Sec-WebSocket-Extensions")
+		if negotiated := strings.Contains(ext, "permessage-deflate"); negotiated != compress {
+			t.Fatalf("compression negotiated = %t, expected %t", negotiated, compress)
+		}
+		conn := <-conns
+		for _, msg := range [][]byte{[]byte("small"), bytes.Repeat([]byte("large"), compressionThreshold)} {
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				t.Fatalf("write error: %v", err)
+			}
+			_, b, err := cl.ReadMessage()
+			if err != nil {
+				t.Fatalf("read error: %v", err)
+			}
+			if !bytes.Equal(b, msg) {
+				t.Fatalf("wrong message")
+			}
+		}
+		cl.Close()
+		conn.Close()
+	}
+}
+
+func TestQUICAltSvcAddr(t *testing.T) {
+	tests := []struct {
+		name   string
+		altSvc []string
+		exp    string
+	}{
+		{"none", nil, ""},
+		{"advertised", []string{QUICAltSvc("7232")}, "dex.example.com:7232"},
+		{"other protocols", []string{`h3=":443"; ma=3600, ` + QUICAltSvc("7232") + `; ma=60`}, "dex.example.com:7232"},
+		{"other host", []string{QUICProtocol + `="quic.example.com:7000"`}, "quic.example.com:7000"},
+		{"other protocol only", []string{`h3=":443"`}, ""},
+		{"no port", []string{QUICProtocol + `="quic.example.com"`}, ""},
+	}
+	for _, tt := range tests {
+		header := http.Header{"Alt-Svc": tt.altSvc}
+		if addr := QUICAltSvcAddr("dex.example.com", header); addr != tt.exp {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.exp, addr)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package admin
 
 import (
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -19,9 +20,11 @@ import (
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/auth"
 	dexsrv "decred.org/dcrdex/server/dex"
 	"decred.org/dcrdex/server/market"
 	"github.com/go-chi/chi/v5"
+	qrcode "github.com/skip2/go-qrcode"
 )
 
 const (
@@ -137,6 +140,7 @@ func (s *Server) apiSetFeeScale(w http.ResponseWriter, r *http.Request) {
 // apiMarkets is the handler for the '/markets' API request.
 func (s *Server) apiMarkets(w http.ResponseWriter, r *http.Request) {
 	statuses := s.core.MarketStatuses()
+	pendingChanges := s.core.PendingMarketChanges()
 	mktStatuses := make(map[string]*MarketStatus)
 	for name, status := range statuses {
 		mktStatus := &MarketStatus{
@@ -146,6 +150,7 @@ func (s *Server) apiMarkets(w http.ResponseWriter, r *http.Request) {
 			ActiveEpoch:   status.ActiveEpoch,
 			StartEpoch:    status.StartEpoch,
 			SuspendEpoch:  status.SuspendEpoch,
+			PendingChange: pendingChanges[name],
 		}
 		if status.SuspendEpoch != 0 {
 			persist := status.PersistBook
@@ -179,6 +184,7 @@ func (s *Server) apiMarketInfo(w http.ResponseWriter, r *http.Request) {
 		StartEpoch:    status.StartEpoch,
 		SuspendEpoch:  status.SuspendEpoch,
 		PersistBook:   persist,
+		PendingChange: s.core.PendingMarketChanges()[mkt],
 	}
 	if status.SuspendEpoch != 0 {
 		persist := status.PersistBook
@@ -435,6 +441,214 @@ func (s *Server) apiSuspend(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// parseScheduleTime parses the time in milliseconds from the query of a request
+// to schedule a market change, e.g. the "t" query. If not specified, the zero
+// time.Time is returned to indicate ASAP.
+func parseScheduleTime(r *http.Request, key, action string) (time.Time, error) {
+	tStr := r.URL.Query().Get(key)
+	if tStr == "" {
+		return time.Time{}, nil
+	}
+	tMs, err := strconv.ParseInt(tStr, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s time %q: %v", action, tStr, err)
+	}
+	t := time.UnixMilli(tMs)
+	if time.Until(t) < 0 {
+		return time.Time{}, fmt.Errorf("specified market %s time is in the past: %v", action, t)
+	}
+	return t, nil
+}
+
+// decodeBody decodes the JSON request body, rejecting unknown fields.
+func decodeBody(r *http.Request, thing any) error {
+	defer r.Body.Close()
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(thing); err != nil {
+		return fmt.Errorf("unable to decode request body: %w", err)
+	}
+	return nil
+}
+
+// handler for route '/markets' POST, with the MarketPost body and optional
+// query '?t=UNIXMS' specifying the earliest start time.
+func (s *Server) apiAddMarket(w http.ResponseWriter, r *http.Request) {
+	var mktPost MarketPost
+	if err := decodeBody(r, &mktPost); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	startTime, err := parseScheduleTime(r, "t", "start")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	mktInf, err := dex.NewMarketInfoFromSymbols(mktPost.Base, mktPost.Quote, mktPost.LotSize,
+		mktPost.RateStep, mktPost.EpochDuration, mktPost.ParcelSize, mktPost.MarketBuyBuffer)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if found, _ := s.core.MarketRunning(mktInf.Name); found {
+		http.Error(w, fmt.Sprintf("market %q already exists", mktInf.Name), http.StatusBadRequest)
+		return
+	}
+
+	startEpoch, startTime, err := s.core.AddMarket(mktInf, startTime)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to add market: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, &AddMarketResult{
+		Market:     mktInf.Name,
+		StartEpoch: startEpoch,
+		StartTime:  APITime{startTime},
+	})
+}
+
+// handler for route '/market/{marketName}/modify' POST, with the MarketChange
+// body and optional query '?t=UNIXMS' specifying the earliest suspend time.
+func (s *Server) apiModifyMarket(w http.ResponseWriter, r *http.Request) {
+	// Ensure the market exists and is running.
+	mkt := strings.ToLower(chi.URLParam(r, marketNameKey))
+	found, running := s.core.MarketRunning(mkt)
+	if !found {
+		http.Error(w, fmt.Sprintf("unknown market %q", mkt), http.StatusBadRequest)
+		return
+	}
+	if !running {
+		http.Error(w, fmt.Sprintf("market %q not running", mkt), http.StatusBadRequest)
+		return
+	}
+	var change dexsrv.MarketChange
+	if err := decodeBody(r, &change); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	suspTime, err := parseScheduleTime(r, "t", "suspend")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	suspEpoch, err := s.core.ModifyMarket(mkt, &change, suspTime)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to modify market: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, &SuspendResult{
+		Market:      mkt,
+		FinalEpoch:  suspEpoch.Idx,
+		SuspendTime: APITime{suspEpoch.End},
+	})
+}
+
+// handler for route '/market/{marketName}/delist?t=UNIXMS'
+func (s *Server) apiDelistMarket(w http.ResponseWriter, r *http.Request) {
+	// Ensure the market exists and is running.
+	mkt := strings.ToLower(chi.URLParam(r, marketNameKey))
+	found, running := s.core.MarketRunning(mkt)
+	if !found {
+		http.Error(w, fmt.Sprintf("unknown market %q", mkt), http.StatusBadRequest)
+		return
+	}
+	if !running {
+		http.Error(w, fmt.Sprintf("market %q not running", mkt), http.StatusBadRequest)
+		return
+	}
+	suspTime, err := parseScheduleTime(r, "t", "suspend")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	suspEpoch, err := s.core.DelistMarket(mkt, suspTime)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to delist market: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, &SuspendResult{
+		Market:      mkt,
+		FinalEpoch:  suspEpoch.Idx,
+		SuspendTime: APITime{suspEpoch.End},
+	})
+}
+
+// apiMaintenance is the handler for the
+// '/market/{marketID}/maintenance?start=EPOCH-MS&end=EPOCH-MS&persist=BOOL' API
+// request. The reason for the maintenance is the request body.
+func (s *Server) apiMaintenance(w http.ResponseWriter, r *http.Request) {
+	// Ensure the market exists and is running.
+	mkt := strings.ToLower(chi.URLParam(r, marketNameKey))
+	found, running := s.core.MarketRunning(mkt)
+	if !found {
+		http.Error(w, fmt.Sprintf("unknown market %q", mkt), http.StatusBadRequest)
+		return
+	}
+	if !running {
+		http.Error(w, fmt.Sprintf("market %q not running", mkt), http.StatusBadRequest)
+		return
+	}
+	startTime, err := parseScheduleTime(r, "start", "maintenance start")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	endTime, err := parseScheduleTime(r, "end", "maintenance end")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if endTime.IsZero() {
+		http.Error(w, "maintenance end time not specified", http.StatusBadRequest)
+		return
+	}
+	// Persist the book by default, as with a suspension.
+	persistBook := true
+	if persistBookStr := r.URL.Query().Get("persist"); persistBookStr != "" {
+		persistBook, err = strconv.ParseBool(persistBookStr)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid persist book boolean %q: %v", persistBookStr, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("unable to read request body: %v", err), http.StatusInternalServerError)
+		return
+	}
+	reason := strings.TrimSpace(string(body))
+	if reason == "" {
+		http.Error(w, "no reason for the maintenance", http.StatusBadRequest)
+		return
+	}
+	if len(reason) > maxUInt16 {
+		http.Error(w, fmt.Sprintf("reason cannot be larger than %d bytes", maxUInt16), http.StatusBadRequest)
+		return
+	}
+
+	mt, err := s.core.ScheduleMaintenance(mkt, startTime, endTime, persistBook, reason)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to schedule maintenance: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, &MaintenanceResult{
+		Market:      mkt,
+		FinalEpoch:  int64(mt.FinalEpoch),
+		SuspendTime: APITime{time.UnixMilli(int64(mt.SuspendTime))},
+		ResumeTime:  APITime{time.UnixMilli(int64(mt.ResumeTime))},
+		Persist:     mt.Persist,
+		Reason:      mt.Reason,
+	})
+}
+
 // apiEnableDataAPI is the handler for the `/enabledataapi/{yes}` API request,
 // used to enable or disable the HTTP data API.
 func (s *Server) apiEnableDataAPI(w http.ResponseWriter, r *http.Request) {
@@ -473,45 +687,59 @@ func (s *Server) apiAccountInfo(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, acctInfo)
 }
 
-func (s *Server) prepayBonds(w http.ResponseWriter, r *http.Request) {
-	var n int = 1
+const (
+	// maxPrepaidBonds is the most pre-paid bonds that can be created with the
+	// prepaybonds request.
+	maxPrepaidBonds = 100
+	// maxPrepaidBondBatch is the largest batch of pre-paid bonds that can be
+	// minted with the prepaidbonds/mint request.
+	maxPrepaidBondBatch = 10000
+)
+
+// parsePrepaidBondsQuery parses the n, days, and strength query parameters of
+// a request to create pre-paid bonds.
+func parsePrepaidBondsQuery(r *http.Request, maxN int) (n int, strength uint32, durSecs int64, err error) {
+	n = 1
 	if nStr := r.URL.Query().Get(nKey); nStr != "" {
 		n64, err := strconv.ParseUint(nStr, 10, 16)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("error parsing n: %v", err), http.StatusBadRequest)
-			return
+			return 0, 0, 0, fmt.Errorf("error parsing n: %w", err)
 		}
 		n = int(n64)
 	}
-	if n < 0 || n > 100 {
-		http.Error(w, "requested too many prepaid bonds. max 100", http.StatusBadRequest)
-		return
+	if n < 0 || n > maxN {
+		return 0, 0, 0, fmt.Errorf("requested too many prepaid bonds. max %d", maxN)
 	}
 	daysStr := r.URL.Query().Get(daysKey)
 	if daysStr == "" {
-		http.Error(w, "no days duration specified", http.StatusBadRequest)
-		return
+		return 0, 0, 0, errors.New("no days duration specified")
 	}
 	days, err := strconv.ParseUint(daysStr, 10, 64)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("error parsing days: %v", err), http.StatusBadRequest)
-		return
+		return 0, 0, 0, fmt.Errorf("error parsing days: %w", err)
 	}
 	if days == 0 {
-		http.Error(w, "days parsed to zero", http.StatusBadRequest)
-		return
+		return 0, 0, 0, errors.New("days parsed to zero")
 	}
 	dur := time.Duration(days) * time.Hour * 24
-	var strength uint32 = 1
+	strength = 1
 	if strengthStr := r.URL.Query().Get(strengthKey); strengthStr != "" {
 		n64, err := strconv.ParseUint(strengthStr, 10, 32)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("error parsing strength: %v", err), http.StatusBadRequest)
-			return
+			return 0, 0, 0, fmt.Errorf("error parsing strength: %w", err)
 		}
 		strength = uint32(n64)
 	}
-	coinIDs, err := s.core.CreatePrepaidBonds(n, strength, int64(math.Round(dur.Seconds())))
+	return n, strength, int64(math.Round(dur.Seconds())), nil
+}
+
+func (s *Server) prepayBonds(w http.ResponseWriter, r *http.Request) {
+	n, strength, durSecs, err := parsePrepaidBondsQuery(r, maxPrepaidBonds)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_, coinIDs, err := s.core.CreatePrepaidBonds(n, strength, durSecs)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("error creating bonds: %v", err), http.StatusInternalServerError)
 		return
@@ -523,6 +751,138 @@ func (s *Server) prepayBonds(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, res)
 }
 
+// apiMintPrepaidBonds is the handler for the '/prepaidbonds/mint' API request.
+// It accepts the same query parameters as '/prepaybonds', but allows larger
+// batches and includes the batch ID in the response.
+func (s *Server) apiMintPrepaidBonds(w http.ResponseWriter, r *http.Request) {
+	n, strength, durSecs, err := parsePrepaidBondsQuery(r, maxPrepaidBondBatch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	batch, coinIDs, err := s.core.CreatePrepaidBonds(n, strength, durSecs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error creating bonds: %v", err), http.StatusInternalServerError)
+		return
+	}
+	res := &PrepaidBondBatch{
+		Batch:    batch,
+		Strength: strength,
+		Codes:    make([]dex.Bytes, len(coinIDs)),
+	}
+	for i := range coinIDs {
+		res.Codes[i] = coinIDs[i]
+	}
+	writeJSON(w, res)
+}
+
+// apiPrepaidBonds is the handler for the '/prepaidbonds' API request. The
+// optional batch and status query parameters filter the results, and
+// '?format=csv' exports the bonds as CSV instead of JSON.
+func (s *Server) apiPrepaidBonds(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get(statusKey)
+	switch status {
+	case "", auth.PrepaidBondUnredeemed, auth.PrepaidBondRedeemed, auth.PrepaidBondRevoked, auth.PrepaidBondExpired:
+	default:
+		http.Error(w, fmt.Sprintf("unknown status %q", status), http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get(formatKey)
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, fmt.Sprintf("unknown format %q", format), http.StatusBadRequest)
+		return
+	}
+	batch := r.URL.Query().Get(batchKey)
+	bonds, err := s.core.PrepaidBonds(batch)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error retrieving prepaid bonds: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if status != "" {
+		filtered := make([]*auth.PrepaidBond, 0, len(bonds))
+		for _, b := range bonds {
+			if b.Status == status {
+				filtered = append(filtered, b)
+			}
+		}
+		bonds = filtered
+	}
+	if format != "csv" {
+		writeJSON(w, bonds)
+		return
+	}
+
+	fileName := "prepaid_bonds.csv"
+	if batch != "" {
+		fileName = "prepaid_bonds_" + batch + ".csv"
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	w.WriteHeader(http.StatusOK)
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"code", "batch", "strength", "created", "expiry", "status", "redeemed_by", "redeemed"})
+	for _, b := range bonds {
+		var redeemed string
+		if b.Redeemed != 0 {
+			redeemed = strconv.FormatInt(b.Redeemed, 10)
+		}
+		cw.Write([]string{b.Code.String(), b.Batch, strconv.FormatUint(uint64(b.Strength), 10),
+			strconv.FormatInt(b.Created, 10), strconv.FormatInt(b.Expiry, 10), b.Status,
+			b.RedeemedBy.String(), redeemed})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		log.Errorf("Error writing prepaid bonds CSV: %v", err)
+	}
+}
+
+// apiPrepaidBondQR is the handler for the '/prepaidbonds/qr/{code}' API
+// request. It responds with a PNG image of a QR code encoding the hex pre-paid
+// bond code.
+func (s *Server) apiPrepaidBondQR(w http.ResponseWriter, r *http.Request) {
+	code, err := hex.DecodeString(chi.URLParam(r, codeKey))
+	if err != nil || len(code) == 0 {
+		http.Error(w, "invalid prepaid bond code", http.StatusBadRequest)
+		return
+	}
+	png, err := qrcode.Encode(hex.EncodeToString(code), qrcode.Medium, 256)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error generating qr code: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(len(png)))
+	w.WriteHeader(http.StatusOK)
+	if _, err = w.Write(png); err != nil {
+		log.Errorf("Write error: %v", err)
+	}
+}
+
+// apiRevokePrepaidBonds is the handler for the '/prepaidbonds/revoke' API
+// request. Either the listed codes or, if no codes are listed, all unredeemed
+// bonds in the batch are revoked.
+func (s *Server) apiRevokePrepaidBonds(w http.ResponseWriter, r *http.Request) {
+	var revoke RevokePrepaidBondsPost
+	if err := decodeBody(r, &revoke); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if revoke.Batch == "" && len(revoke.Codes) == 0 {
+		http.Error(w, "no batch or codes specified", http.StatusBadRequest)
+		return
+	}
+	coinIDs := make([][]byte, len(revoke.Codes))
+	for i := range revoke.Codes {
+		coinIDs[i] = revoke.Codes[i]
+	}
+	n, err := s.core.RevokePrepaidBonds(revoke.Batch, coinIDs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error revoking prepaid bonds: %v", err), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, &RevokePrepaidBondsResult{Revoked: n})
+}
+
 // decodeAcctID checks a string as being both hex and the right length and
 // returns its bytes encoded as an account.AccountID.
 func decodeAcctID(acctIDStr string) (account.AccountID, error) {
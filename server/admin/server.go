@@ -49,6 +49,10 @@ const (
 	nKey               = "n"
 	daysKey            = "days"
 	strengthKey        = "strength"
+	batchKey           = "batch"
+	statusKey          = "status"
+	formatKey          = "format"
+	codeKey            = "code"
 )
 
 var (
@@ -70,13 +74,20 @@ type SvrCore interface {
 	MarketStatuses() map[string]*market.Status
 	SuspendMarket(name string, tSusp time.Time, persistBooks bool) (*market.SuspendEpoch, error)
 	ResumeMarket(name string, asSoonAs time.Time) (startEpoch int64, startTime time.Time, err error)
+	AddMarket(mktInf *dex.MarketInfo, asSoonAs time.Time) (startEpoch int64, startTime time.Time, err error)
+	ModifyMarket(name string, change *dexsrv.MarketChange, asSoonAs time.Time) (*market.SuspendEpoch, error)
+	DelistMarket(name string, asSoonAs time.Time) (*market.SuspendEpoch, error)
+	PendingMarketChanges() map[string]string
+	ScheduleMaintenance(name string, start, end time.Time, persist bool, reason string) (*msgjson.Maintenance, error)
 	ForgiveMatchFail(aid account.AccountID, mid order.MatchID) (forgiven, unbanned bool, err error)
 	AccountMatchOutcomesN(user account.AccountID, n int) ([]*auth.MatchOutcome, error)
 	BookOrders(base, quote uint32) (orders []*order.LimitOrder, err error)
 	EpochOrders(base, quote uint32) (orders []order.Order, err error)
 	MarketMatchesStreaming(base, quote uint32, includeInactive bool, N int64, f func(*dexsrv.MatchData) error) (int, error)
 	EnableDataAPI(yes bool)
-	CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error)
+	CreatePrepaidBonds(n int, strength uint32, durSecs int64) (batch string, coinIDs [][]byte, err error)
+	PrepaidBonds(batch string) ([]*auth.PrepaidBond, error)
+	RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error)
 }
 
 // Server is a multi-client https server.
@@ -164,6 +175,7 @@ func NewServer(cfg *SrvConfig) (*Server, error) {
 		})
 		r.Post("/notifyall", s.apiNotifyAll)
 		r.Get("/markets", s.apiMarkets)
+		r.Post("/markets", s.apiAddMarket)
 		r.Route("/market/{"+marketNameKey+"}", func(rm chi.Router) {
 			rm.Get("/", s.apiMarketInfo)
 			rm.Get("/orderbook", s.apiMarketOrderBook)
@@ -171,8 +183,17 @@ func NewServer(cfg *SrvConfig) (*Server, error) {
 			rm.Get("/matches", s.apiMarketMatches)
 			rm.Get("/suspend", s.apiSuspend)
 			rm.Get("/resume", s.apiResume)
+			rm.Post("/modify", s.apiModifyMarket)
+			rm.Get("/delist", s.apiDelistMarket)
+			rm.Post("/maintenance", s.apiMaintenance)
 		})
 		r.Get("/prepaybonds", s.prepayBonds)
+		r.Route("/prepaidbonds", func(rm chi.Router) {
+			rm.Get("/", s.apiPrepaidBonds)
+			rm.Get("/mint", s.apiMintPrepaidBonds)
+			rm.Post("/revoke", s.apiRevokePrepaidBonds)
+			rm.Get("/qr/{"+codeKey+"}", s.apiPrepaidBondQR)
+		})
 	})
 
 	return s, nil
@@ -8,6 +8,7 @@ import (
 	"context"
 	"crypto/elliptic"
 	"crypto/sha256"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -24,6 +25,7 @@ import (
 	"time"
 
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/account"
@@ -68,6 +70,16 @@ type TCore struct {
 	marketMatches    []*dexsrv.MatchData
 	marketMatchesErr error
 	dataEnabled      uint32
+	addedMarket      *dex.MarketInfo
+	marketChange     *dexsrv.MarketChange
+	marketChangeErr  error
+	pendingChanges   map[string]string
+	mintedDurSecs    int64
+	prepaidBonds     []*auth.PrepaidBond
+	prepaidBatch     string
+	revokedBatch     string
+	revokedCodes     [][]byte
+	revokedN         int64
 }
 
 func (c *TCore) ConfigMsg() json.RawMessage { return nil }
@@ -97,6 +109,50 @@ func (c *TCore) SuspendMarket(name string, tSusp time.Time, persistBooks bool) (
 	return tMkt.suspend, nil
 }
 
+func (c *TCore) AddMarket(mktInf *dex.MarketInfo, asSoonAs time.Time) (startEpoch int64, startTime time.Time, err error) {
+	if c.marketChangeErr != nil {
+		return 0, time.Time{}, c.marketChangeErr
+	}
+	c.addedMarket = mktInf
+	if asSoonAs.IsZero() {
+		asSoonAs = time.Now()
+	}
+	startEpoch = 1 + asSoonAs.UnixMilli()/int64(mktInf.EpochDuration)
+	return startEpoch, time.UnixMilli(startEpoch * int64(mktInf.EpochDuration)), nil
+}
+func (c *TCore) ModifyMarket(name string, change *dexsrv.MarketChange, asSoonAs time.Time) (*market.SuspendEpoch, error) {
+	if c.marketChangeErr != nil {
+		return nil, c.marketChangeErr
+	}
+	c.marketChange = change
+	return c.SuspendMarket(name, asSoonAs, change.LotSize == nil && change.RateStep == nil)
+}
+func (c *TCore) DelistMarket(name string, asSoonAs time.Time) (*market.SuspendEpoch, error) {
+	if c.marketChangeErr != nil {
+		return nil, c.marketChangeErr
+	}
+	return c.SuspendMarket(name, asSoonAs, false)
+}
+func (c *TCore) PendingMarketChanges() map[string]string {
+	return c.pendingChanges
+}
+func (c *TCore) ScheduleMaintenance(name string, start, end time.Time, persist bool, reason string) (*msgjson.Maintenance, error) {
+	if c.marketChangeErr != nil {
+		return nil, c.marketChangeErr
+	}
+	suspEpoch, err := c.SuspendMarket(name, start, persist)
+	if err != nil {
+		return nil, err
+	}
+	return &msgjson.Maintenance{
+		FinalEpoch:  uint64(suspEpoch.Idx),
+		SuspendTime: uint64(suspEpoch.End.UnixMilli()),
+		ResumeTime:  uint64(end.UnixMilli()),
+		Persist:     persist,
+		Reason:      reason,
+	}, nil
+}
+
 func (c *TCore) market(name string) *TMarket {
 	if c.markets == nil {
 		return nil
@@ -214,8 +270,21 @@ func (c *TCore) Unban(_ account.AccountID) error {
 func (c *TCore) ForgiveMatchFail(_ account.AccountID, _ order.MatchID) (bool, bool, error) {
 	return false, false, nil // TODO: tests
 }
-func (c *TCore) CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error) {
-	return nil, nil
+func (c *TCore) CreatePrepaidBonds(n int, strength uint32, durSecs int64) (string, [][]byte, error) {
+	c.mintedDurSecs = durSecs
+	coinIDs := make([][]byte, n)
+	for i := range coinIDs {
+		coinIDs[i] = encode.RandomBytes(16)
+	}
+	return "newbatch", coinIDs, nil
+}
+func (c *TCore) PrepaidBonds(batch string) ([]*auth.PrepaidBond, error) {
+	c.prepaidBatch = batch
+	return c.prepaidBonds, nil
+}
+func (c *TCore) RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error) {
+	c.revokedBatch, c.revokedCodes = batch, coinIDs
+	return c.revokedN, nil
 }
 func (c *TCore) AccountMatchOutcomesN(user account.AccountID, n int) ([]*auth.MatchOutcome, error) {
 	return nil, nil
@@ -1037,6 +1106,489 @@ func TestSuspend(t *testing.T) {
 	}
 }
 
+func TestAddMarket(t *testing.T) {
+	core := &TCore{
+		markets: make(map[string]*TMarket),
+	}
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Post("/markets", srv.apiAddMarket)
+
+	const goodBody = `{"base":"DCR","quote":"btc","lotSize":100000000,"rateStep":1000,"epochDuration":20000,"parcelSize":4}`
+	tFuture := time.Now().Add(time.Hour).UnixMilli()
+
+	tests := []struct {
+		name     string
+		query    string
+		body     string
+		existing bool
+		coreErr  error
+		wantCode int
+	}{{
+		name:     "ok",
+		body:     goodBody,
+		wantCode: http.StatusOK,
+	}, {
+		name:     "ok with start time",
+		query:    fmt.Sprintf("?t=%d", tFuture),
+		body:     goodBody,
+		wantCode: http.StatusOK,
+	}, {
+		name:     "start time in the past",
+		query:    "?t=12",
+		body:     goodBody,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "unknown field",
+		body:     `{"base":"dcr","quote":"btc","lotSize":1,"bookSize":1}`,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "unknown asset",
+		body:     `{"base":"zzz","quote":"btc","lotSize":1,"rateStep":1,"parcelSize":1}`,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "market exists",
+		body:     goodBody,
+		existing: true,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "core error",
+		body:     goodBody,
+		coreErr:  errors.New("test error"),
+		wantCode: http.StatusBadRequest,
+	}}
+
+	for _, test := range tests {
+		core.addedMarket = nil
+		core.marketChangeErr = test.coreErr
+		delete(core.markets, "dcr_btc")
+		if test.existing {
+			core.markets["dcr_btc"] = &TMarket{}
+		}
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, "https://localhost/markets"+test.query, strings.NewReader(test.body))
+		r.RemoteAddr = "localhost"
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%s: apiAddMarket returned code %d, expected %d: %s", test.name, w.Code, test.wantCode, w.Body)
+		}
+		if test.wantCode != http.StatusOK {
+			continue
+		}
+		res := new(AddMarketResult)
+		if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+			t.Fatalf("%s: failed to unmarshal result: %v", test.name, err)
+		}
+		if res.Market != "dcr_btc" || core.addedMarket == nil || core.addedMarket.Name != "dcr_btc" {
+			t.Fatalf("%s: wrong market added", test.name)
+		}
+		mktInf := core.addedMarket
+		if mktInf.LotSize != 1e8 || mktInf.RateStep != 1000 || mktInf.EpochDuration != 20000 || mktInf.ParcelSize != 4 {
+			t.Fatalf("%s: wrong market parameters %+v", test.name, mktInf)
+		}
+		if res.StartEpoch == 0 || !res.StartTime.Equal(time.UnixMilli(res.StartEpoch*20000)) {
+			t.Fatalf("%s: wrong start epoch %d or time %v", test.name, res.StartEpoch, res.StartTime)
+		}
+	}
+}
+
+func TestModifyMarket(t *testing.T) {
+	core := &TCore{
+		markets: make(map[string]*TMarket),
+	}
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Post("/market/{"+marketNameKey+"}/modify", srv.apiModifyMarket)
+
+	name := "dcr_btc"
+	tMkt := &TMarket{
+		running: true,
+		suspend: &market.SuspendEpoch{},
+	}
+	core.markets[name] = tMkt
+
+	tests := []struct {
+		name        string
+		mkt         string
+		query       string
+		body        string
+		notRunning  bool
+		coreErr     error
+		wantCode    int
+		wantPersist bool
+	}{{
+		name:        "ok epoch duration",
+		mkt:         name,
+		body:        `{"epochDuration":30000}`,
+		wantCode:    http.StatusOK,
+		wantPersist: true,
+	}, {
+		name:     "ok lot size",
+		mkt:      name,
+		query:    fmt.Sprintf("?t=%d", time.Now().Add(time.Hour).UnixMilli()),
+		body:     `{"lotSize":200000000,"parcelSize":2}`,
+		wantCode: http.StatusOK,
+	}, {
+		name:     "unknown market",
+		mkt:      "dcr_ltc",
+		body:     `{"lotSize":200000000}`,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:       "not running",
+		mkt:        name,
+		body:       `{"lotSize":200000000}`,
+		notRunning: true,
+		wantCode:   http.StatusBadRequest,
+	}, {
+		name:     "bad body",
+		mkt:      name,
+		body:     `{"lotSize":"a lot"}`,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad time",
+		mkt:      name,
+		query:    "?t=QWERT",
+		body:     `{"lotSize":200000000}`,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "core error",
+		mkt:      name,
+		body:     `{"lotSize":200000000}`,
+		coreErr:  errors.New("test error"),
+		wantCode: http.StatusBadRequest,
+	}}
+
+	for _, test := range tests {
+		core.marketChange = nil
+		core.marketChangeErr = test.coreErr
+		tMkt.running = !test.notRunning
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, "https://localhost/market/"+test.mkt+"/modify"+test.query, strings.NewReader(test.body))
+		r.RemoteAddr = "localhost"
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%s: apiModifyMarket returned code %d, expected %d: %s", test.name, w.Code, test.wantCode, w.Body)
+		}
+		if test.wantCode != http.StatusOK {
+			continue
+		}
+		res := new(SuspendResult)
+		if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+			t.Fatalf("%s: failed to unmarshal result: %v", test.name, err)
+		}
+		if res.Market != name || res.FinalEpoch != tMkt.suspend.Idx {
+			t.Fatalf("%s: wrong result %+v", test.name, res)
+		}
+		if core.marketChange == nil {
+			t.Fatalf("%s: no market change", test.name)
+		}
+		if tMkt.persist != test.wantPersist {
+			t.Fatalf("%s: wrong persist %t", test.name, tMkt.persist)
+		}
+	}
+}
+
+func TestDelistMarket(t *testing.T) {
+	core := &TCore{
+		markets: make(map[string]*TMarket),
+	}
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Get("/market/{"+marketNameKey+"}/delist", srv.apiDelistMarket)
+
+	name := "dcr_btc"
+	tMkt := &TMarket{
+		running: true,
+		persist: true,
+		suspend: &market.SuspendEpoch{},
+	}
+	core.markets[name] = tMkt
+
+	tFuture := time.Now().Add(time.Minute).UnixMilli()
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("https://localhost/market/%s/delist?t=%d", name, tFuture), nil)
+	r.RemoteAddr = "localhost"
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("apiDelistMarket returned code %d, expected %d", w.Code, http.StatusOK)
+	}
+	res := new(SuspendResult)
+	if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if res.Market != name || res.FinalEpoch != tFuture {
+		t.Fatalf("wrong result %+v", res)
+	}
+	if tMkt.persist {
+		t.Fatalf("book not purged")
+	}
+
+	// Not running.
+	tMkt.running = false
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodGet, "https://localhost/market/"+name+"/delist", nil)
+	r.RemoteAddr = "localhost"
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("apiDelistMarket returned code %d, expected %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+	core := &TCore{
+		markets: make(map[string]*TMarket),
+	}
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Post("/market/{"+marketNameKey+"}/maintenance", srv.apiMaintenance)
+
+	name := "dcr_btc"
+	tMkt := &TMarket{
+		running: true,
+		suspend: &market.SuspendEpoch{},
+	}
+	core.markets[name] = tMkt
+
+	now := time.Now()
+	tStart := now.Add(time.Hour).UnixMilli()
+	tEnd := now.Add(2 * time.Hour).UnixMilli()
+	const reason = "wallet upgrade"
+
+	tests := []struct {
+		name        string
+		mkt         string
+		query       string
+		body        string
+		notRunning  bool
+		coreErr     error
+		wantCode    int
+		wantPersist bool
+	}{{
+		name:        "ok",
+		mkt:         name,
+		query:       fmt.Sprintf("?start=%d&end=%d", tStart, tEnd),
+		body:        reason + "\n",
+		wantCode:    http.StatusOK,
+		wantPersist: true,
+	}, {
+		name:     "ok no persist, start ASAP",
+		mkt:      name,
+		query:    fmt.Sprintf("?end=%d&persist=false", tEnd),
+		body:     reason,
+		wantCode: http.StatusOK,
+	}, {
+		name:     "unknown market",
+		mkt:      "dcr_ltc",
+		query:    fmt.Sprintf("?end=%d", tEnd),
+		body:     reason,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:       "not running",
+		mkt:        name,
+		query:      fmt.Sprintf("?end=%d", tEnd),
+		body:       reason,
+		notRunning: true,
+		wantCode:   http.StatusBadRequest,
+	}, {
+		name:     "no end",
+		mkt:      name,
+		query:    fmt.Sprintf("?start=%d", tStart),
+		body:     reason,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "end in the past",
+		mkt:      name,
+		query:    "?end=12",
+		body:     reason,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "bad persist",
+		mkt:      name,
+		query:    fmt.Sprintf("?end=%d&persist=maybe", tEnd),
+		body:     reason,
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "no reason",
+		mkt:      name,
+		query:    fmt.Sprintf("?end=%d", tEnd),
+		body:     " \n",
+		wantCode: http.StatusBadRequest,
+	}, {
+		name:     "core error",
+		mkt:      name,
+		query:    fmt.Sprintf("?end=%d", tEnd),
+		body:     reason,
+		coreErr:  errors.New("test error"),
+		wantCode: http.StatusBadRequest,
+	}}
+
+	for _, test := range tests {
+		core.marketChangeErr = test.coreErr
+		tMkt.running = !test.notRunning
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPost, "https://localhost/market/"+test.mkt+"/maintenance"+test.query, strings.NewReader(test.body))
+		r.RemoteAddr = "localhost"
+		mux.ServeHTTP(w, r)
+
+		if w.Code != test.wantCode {
+			t.Fatalf("%s: apiMaintenance returned code %d, expected %d: %s", test.name, w.Code, test.wantCode, w.Body)
+		}
+		if test.wantCode != http.StatusOK {
+			continue
+		}
+		res := new(MaintenanceResult)
+		if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+			t.Fatalf("%s: failed to unmarshal result: %v", test.name, err)
+		}
+		if res.Market != name || res.Reason != reason || res.Persist != test.wantPersist {
+			t.Fatalf("%s: wrong result %+v", test.name, res)
+		}
+		if res.ResumeTime.UnixMilli() != tEnd {
+			t.Fatalf("%s: wrong resume time %v", test.name, res.ResumeTime)
+		}
+		if tMkt.persist != test.wantPersist {
+			t.Fatalf("%s: wrong persist %t", test.name, tMkt.persist)
+		}
+	}
+}
+
+func TestPrepaidBonds(t *testing.T) {
+	core := &TCore{
+		prepaidBonds: []*auth.PrepaidBond{
+			{Code: dex.Bytes{0x01}, Batch: "a", Strength: 1, Status: auth.PrepaidBondUnredeemed},
+			{Code: dex.Bytes{0x02}, Batch: "a", Strength: 1, Status: auth.PrepaidBondRedeemed,
+				RedeemedBy: dex.Bytes{0x0a}, Redeemed: 1700000000},
+			{Code: dex.Bytes{0x03}, Batch: "a", Strength: 1, Status: auth.PrepaidBondRevoked},
+		},
+	}
+	srv := &Server{
+		core: core,
+	}
+
+	mux := chi.NewRouter()
+	mux.Route("/prepaidbonds", func(rm chi.Router) {
+		rm.Get("/", srv.apiPrepaidBonds)
+		rm.Get("/mint", srv.apiMintPrepaidBonds)
+		rm.Post("/revoke", srv.apiRevokePrepaidBonds)
+		rm.Get("/qr/{"+codeKey+"}", srv.apiPrepaidBondQR)
+	})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(method, "https://localhost"+path, strings.NewReader(body))
+		r.RemoteAddr = "localhost"
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	// Mint
+	w := do(http.MethodGet, "/prepaidbonds/mint?n=500&days=30&strength=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("mint returned code %d: %s", w.Code, w.Body)
+	}
+	batch := new(PrepaidBondBatch)
+	if err := json.Unmarshal(w.Body.Bytes(), batch); err != nil {
+		t.Fatalf("failed to unmarshal batch: %v", err)
+	}
+	if batch.Batch != "newbatch" || len(batch.Codes) != 500 || batch.Strength != 2 {
+		t.Fatalf("wrong batch result %+v", batch)
+	}
+	if core.mintedDurSecs != 30*86400 {
+		t.Fatalf("wrong duration %d", core.mintedDurSecs)
+	}
+	for _, path := range []string{"/prepaidbonds/mint?n=10", "/prepaidbonds/mint?n=10001&days=1", "/prepaidbonds/mint?days=0"} {
+		if w = do(http.MethodGet, path, ""); w.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected bad request, got %d", path, w.Code)
+		}
+	}
+
+	// List, with a status filter.
+	w = do(http.MethodGet, "/prepaidbonds?batch=a&status=redeemed", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list returned code %d: %s", w.Code, w.Body)
+	}
+	var bonds []*auth.PrepaidBond
+	if err := json.Unmarshal(w.Body.Bytes(), &bonds); err != nil {
+		t.Fatalf("failed to unmarshal bonds: %v", err)
+	}
+	if len(bonds) != 1 || bonds[0].Code.String() != "02" {
+		t.Fatalf("wrong filtered bonds")
+	}
+	if core.prepaidBatch != "a" {
+		t.Fatalf("wrong batch requested %q", core.prepaidBatch)
+	}
+	if w = do(http.MethodGet, "/prepaidbonds?status=spent", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request for unknown status, got %d", w.Code)
+	}
+
+	// CSV export
+	w = do(http.MethodGet, "/prepaidbonds?batch=a&format=csv", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("csv export returned code %d: %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Fatalf("wrong content type %q", ct)
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading csv: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected header and 3 records, got %d", len(records))
+	}
+	if rec := records[2]; rec[0] != "02" || rec[5] != auth.PrepaidBondRedeemed || rec[6] != "0a" || rec[7] != "1700000000" {
+		t.Fatalf("wrong csv record %v", rec)
+	}
+
+	// QR
+	w = do(http.MethodGet, "/prepaidbonds/qr/0102", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("qr returned code %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	if w = do(http.MethodGet, "/prepaidbonds/qr/xyz", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request for invalid code, got %d", w.Code)
+	}
+
+	// Revoke
+	core.revokedN = 2
+	w = do(http.MethodPost, "/prepaidbonds/revoke", `{"batch":"a"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("revoke returned code %d: %s", w.Code, w.Body)
+	}
+	res := new(RevokePrepaidBondsResult)
+	if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+		t.Fatalf("failed to unmarshal revoke result: %v", err)
+	}
+	if res.Revoked != 2 || core.revokedBatch != "a" || len(core.revokedCodes) != 0 {
+		t.Fatalf("wrong revoke result %+v", res)
+	}
+	w = do(http.MethodPost, "/prepaidbonds/revoke", `{"codes":["01","03"]}`)
+	if w.Code != http.StatusOK || len(core.revokedCodes) != 2 {
+		t.Fatalf("revoke codes returned code %d: %s", w.Code, w.Body)
+	}
+	if w = do(http.MethodPost, "/prepaidbonds/revoke", `{}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected bad request for empty revoke, got %d", w.Code)
+	}
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	pass := "password123"
 	authSHA := sha256.Sum256([]byte(pass))
@@ -34,6 +34,19 @@ type MarketStatus struct {
 	StartEpoch    int64  `json:"startepoch"`
 	SuspendEpoch  int64  `json:"finalepoch,omitempty"`
 	PersistBook   *bool  `json:"persistbook,omitempty"`
+	PendingChange string `json:"pendingchange,omitempty"`
+}
+
+// MarketPost is the expected structure of the market POST data, used to add a
+// market while the DEX is running. The assets are identified by their symbols.
+type MarketPost struct {
+	Base            string  `json:"base"`
+	Quote           string  `json:"quote"`
+	LotSize         uint64  `json:"lotSize"`
+	RateStep        uint64  `json:"rateStep"`
+	EpochDuration   uint64  `json:"epochDuration"`
+	ParcelSize      uint32  `json:"parcelSize"`
+	MarketBuyBuffer float64 `json:"marketBuyBuffer"`
 }
 
 // MatchData describes a match.
@@ -75,6 +88,23 @@ type SuspendResult struct {
 	SuspendTime APITime `json:"supendtime"`
 }
 
+// MaintenanceResult is the result of a market maintenance request.
+type MaintenanceResult struct {
+	Market      string  `json:"market"`
+	FinalEpoch  int64   `json:"finalepoch"`
+	SuspendTime APITime `json:"suspendtime"`
+	ResumeTime  APITime `json:"resumetime"`
+	Persist     bool    `json:"persistbook"`
+	Reason      string  `json:"reason"`
+}
+
+// AddMarketResult is the result of an add market request.
+type AddMarketResult struct {
+	Market     string  `json:"market"`
+	StartEpoch int64   `json:"startepoch"`
+	StartTime  APITime `json:"starttime"`
+}
+
 // ResumeResult is the result of a market resume request.
 type ResumeResult struct {
 	Market     string  `json:"market"`
@@ -106,6 +136,25 @@ func (at *APITime) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// PrepaidBondBatch is the result of minting a batch of pre-paid bonds.
+type PrepaidBondBatch struct {
+	Batch    string      `json:"batch"`
+	Strength uint32      `json:"strength"`
+	Codes    []dex.Bytes `json:"codes"`
+}
+
+// RevokePrepaidBondsPost is the expected structure of the prepaid bond revoke
+// POST data. If Codes is empty, all unredeemed bonds in Batch are revoked.
+type RevokePrepaidBondsPost struct {
+	Batch string      `json:"batch"`
+	Codes []dex.Bytes `json:"codes"`
+}
+
+// RevokePrepaidBondsResult is the result of a prepaid bond revoke request.
+type RevokePrepaidBondsResult struct {
+	Revoked int64 `json:"revoked"`
+}
+
 // ForgiveResult holds the result of a forgive_match.
 type ForgiveResult struct {
 	AccountID   string  `json:"accountid"`
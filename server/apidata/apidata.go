@@ -6,21 +6,49 @@ package apidata
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
 	"decred.org/dcrdex/dex/candles"
 	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/server/db"
 	"decred.org/dcrdex/server/matcher"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultTradesRequest is the number of trades returned when a trades
+	// request does not specify a limit.
+	DefaultTradesRequest = 100
+	// MaxTradesRequest is the maximum number of trades returned for a single
+	// trades request.
+	MaxTradesRequest = 1000
+	// DefaultDepthLevels is the number of rate levels per side returned when a
+	// depth request does not specify the number of levels.
+	DefaultDepthLevels = 50
+	// MaxDepthLevels is the maximum number of rate levels per side returned
+	// for a depth request.
+	MaxDepthLevels = 500
+
+	fiveMins = uint64(time.Minute * 5 / time.Millisecond)
 )
 
 var (
 	// Our internal millisecond representation of the bin sizes.
 	binSizes []uint64
 	started  uint32
+
+	// historyLimiter limits the rate of requests that are served from the
+	// database rather than from memory, i.e. trade history and candles older
+	// than the caches, across all clients. The comms server's per-IP limits
+	// still apply to each request.
+	historyLimiter = rate.NewLimiter(20, 100) // rate per sec, max burst
 )
 
 // DBSource is a source of persistent data. DBSource is used to prime the
@@ -29,6 +57,8 @@ type DBSource interface {
 	LoadEpochStats(base, quote uint32, caches []*candles.Cache) error
 	LastCandleEndStamp(base, quote uint32, candleDur uint64) (uint64, error)
 	InsertCandles(base, quote uint32, dur uint64, cs []*candles.Candle) error
+	Candles(base, quote uint32, candleDur, before uint64, n int) ([]*candles.Candle, error)
+	MarketTrades(base, quote uint32, before *order.MatchID, n int) ([]*db.Trade, error)
 }
 
 // MarketSource is a source of market information. Markets are added after
@@ -52,15 +82,16 @@ type cacheWithStoredTime struct {
 
 // DataAPI is a data API backend.
 type DataAPI struct {
-	db             DBSource
-	epochDurations map[string]uint64
-	bookSource     BookSource
+	db         DBSource
+	bookSource BookSource
 
 	spotsMtx sync.RWMutex
 	spots    map[string]json.RawMessage
 
-	cacheMtx     sync.RWMutex
-	marketCaches map[string]map[uint64]*cacheWithStoredTime
+	cacheMtx       sync.RWMutex
+	marketCaches   map[string]map[uint64]*cacheWithStoredTime
+	epochDurations map[string]uint64
+	marketSources  map[string]MarketSource
 }
 
 // NewDataAPI is the constructor for a new DataAPI.
@@ -70,24 +101,29 @@ func NewDataAPI(dbSrc DBSource, registerHTTP func(route string, handler comms.HT
 		epochDurations: make(map[string]uint64),
 		spots:          make(map[string]json.RawMessage),
 		marketCaches:   make(map[string]map[uint64]*cacheWithStoredTime),
+		marketSources:  make(map[string]MarketSource),
 	}
 
 	if atomic.CompareAndSwapUint32(&started, 0, 1) {
 		registerHTTP(msgjson.SpotsRoute, s.handleSpots)
 		registerHTTP(msgjson.CandlesRoute, s.handleCandles)
 		registerHTTP(msgjson.OrderBookRoute, s.handleOrderBook)
+		registerHTTP(msgjson.TradesRoute, s.handleTrades)
+		registerHTTP(msgjson.TickersRoute, s.handleTickers)
+		registerHTTP(msgjson.DepthRoute, s.handleDepth)
 	}
 	return s
 }
 
-// AddMarketSource should be called before any markets are running.
+// AddMarketSource should be called before the market is running. If the market
+// was added before, as when a market's configuration is modified while the DEX
+// is running, the market's caches are replaced.
 func (s *DataAPI) AddMarketSource(mkt MarketSource) error {
 	mktName, err := dex.MarketName(mkt.Base(), mkt.Quote())
 	if err != nil {
 		return err
 	}
 	epochDur := mkt.EpochDuration()
-	s.epochDurations[mktName] = epochDur
 	binCaches := make(map[uint64]*cacheWithStoredTime, len(binSizes)+1)
 	cacheList := make([]*candles.Cache, 0, len(binSizes)+1)
 	for _, binSize := range append([]uint64{epochDur}, binSizes...) {
@@ -106,6 +142,8 @@ func (s *DataAPI) AddMarketSource(mkt MarketSource) error {
 	}
 	s.cacheMtx.Lock()
 	s.marketCaches[mktName] = binCaches
+	s.epochDurations[mktName] = epochDur
+	s.marketSources[mktName] = mkt
 	s.cacheMtx.Unlock()
 	return nil
 }
@@ -135,7 +173,6 @@ func (s *DataAPI) ReportEpoch(base, quote uint32, epochIdx uint64, stats *matche
 		startStamp := epochIdx * epochDur
 		endStamp := startStamp + epochDur
 		var cache5min *cacheWithStoredTime
-		const fiveMins = uint64(time.Minute * 5 / time.Millisecond)
 		candle := &candles.Candle{
 			StartStamp:  startStamp,
 			EndStamp:    endStamp,
@@ -233,18 +270,235 @@ func (s *DataAPI) handleCandles(thing any) (any, error) {
 	binSize := uint64(binSizeDuration / time.Millisecond)
 
 	s.cacheMtx.RLock()
-	defer s.cacheMtx.RUnlock()
 	marketCaches := s.marketCaches[mkt]
 	if marketCaches == nil {
+		s.cacheMtx.RUnlock()
 		return nil, fmt.Errorf("market %s not known", mkt)
 	}
 
 	cache := marketCaches[binSize]
 	if cache == nil {
+		s.cacheMtx.RUnlock()
 		return nil, fmt.Errorf("no data available for binSize %s", req.BinSize)
 	}
 
-	return cache.WireCandles(req.NumCandles), nil
+	if req.Before == 0 {
+		defer s.cacheMtx.RUnlock()
+		return cache.WireCandles(req.NumCandles), nil
+	}
+
+	// Older candles are loaded from the DB. Epoch candles are not stored.
+	epochDur := s.epochDurations[mkt]
+	s.cacheMtx.RUnlock()
+	if binSize == epochDur {
+		return nil, fmt.Errorf("candle history is not available for binSize %s", req.BinSize)
+	}
+	if !historyLimiter.Allow() {
+		return nil, fmt.Errorf("%w: try again later", comms.ErrTooManyRequests)
+	}
+	cs, err := s.db.Candles(req.BaseID, req.QuoteID, binSize, req.Before, req.NumCandles)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving candles: %w", err)
+	}
+	history := candles.NewCache(len(cs), binSize)
+	for _, c := range cs {
+		history.Add(c)
+	}
+	return history.WireCandles(len(cs)), nil
+}
+
+// handleTrades implements comms.HTTPHandler for the /trades endpoints.
+func (s *DataAPI) handleTrades(thing any) (any, error) {
+	req, ok := thing.(*msgjson.TradesRequest)
+	if !ok {
+		return nil, fmt.Errorf("trades request unparseable")
+	}
+
+	if req.Limit == 0 {
+		req.Limit = DefaultTradesRequest
+	} else if req.Limit < 0 || req.Limit > MaxTradesRequest {
+		return nil, fmt.Errorf("requested limit %d is outside of the allowed range 1 - %d", req.Limit, MaxTradesRequest)
+	}
+
+	var before *order.MatchID
+	if len(req.Before) > 0 {
+		if len(req.Before) != order.MatchIDSize {
+			return nil, fmt.Errorf("invalid trade ID length %d", len(req.Before))
+		}
+		var mid order.MatchID
+		copy(mid[:], req.Before)
+		before = &mid
+	}
+
+	mkt, err := dex.MarketName(req.BaseID, req.QuoteID)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing market for %d - %d", req.BaseID, req.QuoteID)
+	}
+	s.cacheMtx.RLock()
+	_, known := s.marketCaches[mkt]
+	s.cacheMtx.RUnlock()
+	if !known {
+		return nil, fmt.Errorf("market %s not known", mkt)
+	}
+
+	if !historyLimiter.Allow() {
+		return nil, fmt.Errorf("%w: try again later", comms.ErrTooManyRequests)
+	}
+
+	// Request one extra to learn if there is another page.
+	dbTrades, err := s.db.MarketTrades(req.BaseID, req.QuoteID, before, req.Limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving trades: %w", err)
+	}
+
+	res := &msgjson.TradesResult{
+		Trades: make([]*msgjson.MarketTrade, 0, len(dbTrades)),
+	}
+	if len(dbTrades) > req.Limit {
+		dbTrades = dbTrades[:req.Limit]
+		mid := dbTrades[len(dbTrades)-1].MatchID
+		res.Next = mid[:]
+	}
+	for _, t := range dbTrades {
+		res.Trades = append(res.Trades, &msgjson.MarketTrade{
+			ID:        t.MatchID.Bytes(),
+			Stamp:     t.Stamp,
+			Rate:      t.Rate,
+			Qty:       t.Quantity,
+			QuoteQty:  calc.BaseToQuote(t.Rate, t.Quantity),
+			TakerSell: t.TakerSell,
+		})
+	}
+	return res, nil
+}
+
+// handleTickers implements comms.HTTPHandler for the /tickers endpoint.
+func (s *DataAPI) handleTickers(any) (any, error) {
+	// Compute the stats under the cache lock, but get the books after.
+	s.cacheMtx.RLock()
+	now := time.Now()
+	tickers := make([]*msgjson.Ticker, 0, len(s.marketCaches))
+	mkts := make([]string, 0, len(s.marketCaches))
+	for mkt, caches := range s.marketCaches {
+		cache5min := caches[fiveMins]
+		if cache5min == nil {
+			continue
+		}
+		src := s.marketSources[mkt]
+		ticker := &msgjson.Ticker{
+			Stamp:   uint64(now.UnixMilli()),
+			BaseID:  src.Base(),
+			QuoteID: src.Quote(),
+		}
+		if len(cache5min.Candles) > 0 {
+			ticker.Rate = cache5min.Last().EndRate
+		}
+		dayAgo := now.Add(-time.Hour * 24)
+		ticker.Change24, ticker.Vol24, ticker.High24, ticker.Low24 = cache5min.Delta(dayAgo)
+		ticker.QuoteVol24 = cache5min.QuoteVolume(dayAgo)
+		tickers = append(tickers, ticker)
+		mkts = append(mkts, mkt)
+	}
+	s.cacheMtx.RUnlock()
+
+	if s.bookSource != nil {
+		for i, ticker := range tickers {
+			book, err := s.bookSource.Book(mkts[i])
+			if err != nil {
+				continue
+			}
+			ticker.Bid, ticker.Ask = bestRates(book)
+		}
+	}
+
+	sort.Slice(tickers, func(i, j int) bool {
+		if tickers[i].BaseID != tickers[j].BaseID {
+			return tickers[i].BaseID < tickers[j].BaseID
+		}
+		return tickers[i].QuoteID < tickers[j].QuoteID
+	})
+	return tickers, nil
+}
+
+// handleDepth implements comms.HTTPHandler for the /depth endpoints.
+func (s *DataAPI) handleDepth(thing any) (any, error) {
+	req, ok := thing.(*msgjson.DepthRequest)
+	if !ok {
+		return nil, fmt.Errorf("unparseable depth request")
+	}
+
+	if req.Levels == 0 {
+		req.Levels = DefaultDepthLevels
+	} else if req.Levels < 0 || req.Levels > MaxDepthLevels {
+		return nil, fmt.Errorf("requested levels %d is outside of the allowed range 1 - %d", req.Levels, MaxDepthLevels)
+	}
+
+	mkt, err := dex.MarketName(req.BaseID, req.QuoteID)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse requested market")
+	}
+	book, err := s.bookSource.Book(mkt)
+	if err != nil {
+		return nil, err
+	}
+
+	bids := make(map[uint64]*msgjson.DepthLevel)
+	asks := make(map[uint64]*msgjson.DepthLevel)
+	for _, o := range book.Orders {
+		levels := asks
+		if o.Side == msgjson.BuyOrderNum {
+			levels = bids
+		}
+		lvl := levels[o.Rate]
+		if lvl == nil {
+			lvl = &msgjson.DepthLevel{Rate: o.Rate}
+			levels[o.Rate] = lvl
+		}
+		lvl.Qty += o.Quantity
+		lvl.Orders++
+	}
+
+	return &msgjson.Depth{
+		Stamp:   uint64(time.Now().UnixMilli()),
+		BaseID:  req.BaseID,
+		QuoteID: req.QuoteID,
+		Bids:    sortedLevels(bids, true, req.Levels),
+		Asks:    sortedLevels(asks, false, req.Levels),
+	}, nil
+}
+
+// sortedLevels sorts the levels from the best rate, highest first for bids,
+// and returns at most n of them.
+func sortedLevels(levels map[uint64]*msgjson.DepthLevel, bids bool, n int) []*msgjson.DepthLevel {
+	sorted := make([]*msgjson.DepthLevel, 0, len(levels))
+	for _, lvl := range levels {
+		sorted = append(sorted, lvl)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if bids {
+			return sorted[i].Rate > sorted[j].Rate
+		}
+		return sorted[i].Rate < sorted[j].Rate
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// bestRates finds the best bid and ask rates in the book. A zero rate indicates
+// that there are no orders on that side of the book.
+func bestRates(book *msgjson.OrderBook) (bid, ask uint64) {
+	for _, o := range book.Orders {
+		if o.Side == msgjson.BuyOrderNum {
+			if o.Rate > bid {
+				bid = o.Rate
+			}
+		} else if ask == 0 || o.Rate < ask {
+			ask = o.Rate
+		}
+	}
+	return
 }
 
 // handleOrderBook implements comms.HTTPHandler for the /orderbook endpoints.
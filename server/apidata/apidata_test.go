@@ -4,15 +4,20 @@
 package apidata
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"decred.org/dcrdex/dex/candles"
 	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/dex/order"
 	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/server/db"
 	"decred.org/dcrdex/server/matcher"
+	"golang.org/x/time/rate"
 )
 
 var dummyErr = fmt.Errorf("dummy error")
@@ -27,6 +32,12 @@ func (m *TMarketSource) Quote() uint32         { return m.quote }
 
 type TDBSource struct {
 	loadEpochErr error
+	candles      []*candles.Candle
+	candlesErr   error
+	trades       []*db.Trade
+	tradesErr    error
+	tradesBefore *order.MatchID
+	tradesN      int
 }
 
 func (db *TDBSource) LoadEpochStats(base, quote uint32, caches []*candles.Cache) error {
@@ -41,6 +52,18 @@ func (db *TDBSource) InsertCandles(base, quote uint32, dur uint64, cs []*candles
 	return nil
 }
 
+func (db *TDBSource) Candles(base, quote uint32, candleDur, before uint64, n int) ([]*candles.Candle, error) {
+	return db.candles, db.candlesErr
+}
+
+func (db *TDBSource) MarketTrades(base, quote uint32, before *order.MatchID, n int) ([]*db.Trade, error) {
+	db.tradesBefore, db.tradesN = before, n
+	if len(db.trades) > n {
+		return db.trades[:n], db.tradesErr
+	}
+	return db.trades, db.tradesErr
+}
+
 type TBookSource struct {
 	book *msgjson.OrderBook
 }
@@ -195,3 +218,196 @@ func TestOrderBook(t *testing.T) {
 		t.Fatalf("where did this book come from?")
 	}
 }
+
+func TestCandlesHistory(t *testing.T) {
+	rig := newTestRig()
+	if err := rig.api.AddMarketSource(&TMarketSource{42, 0}); err != nil {
+		t.Fatalf("AddMarketSource error: %v", err)
+	}
+	const hour = uint64(time.Hour / time.Millisecond)
+	rig.db.candles = []*candles.Candle{
+		{StartStamp: 0, EndStamp: hour, EndRate: 1},
+		{StartStamp: hour, EndStamp: 2 * hour, EndRate: 2},
+	}
+
+	req := &msgjson.CandlesRequest{
+		BaseID:  42,
+		QuoteID: 0,
+		BinSize: "1h",
+		Before:  2 * hour,
+	}
+	candlesI, err := rig.api.handleCandles(req)
+	if err != nil {
+		t.Fatalf("handleCandles error: %v", err)
+	}
+	wireCandles := candlesI.(*msgjson.WireCandles)
+	if len(wireCandles.EndRates) != 2 || wireCandles.EndRates[0] != 1 || wireCandles.EndRates[1] != 2 {
+		t.Fatalf("wrong candles returned: %+v", wireCandles)
+	}
+
+	// DB error
+	rig.db.candlesErr = dummyErr
+	if _, err = rig.api.handleCandles(req); err == nil {
+		t.Fatalf("no error for DB error")
+	}
+	rig.db.candlesErr = nil
+
+	// Epoch candles are not stored.
+	req.BinSize = "1s"
+	if _, err = rig.api.handleCandles(req); err == nil {
+		t.Fatalf("no error for epoch candle history")
+	}
+}
+
+func TestTrades(t *testing.T) {
+	rig := newTestRig()
+	if err := rig.api.AddMarketSource(&TMarketSource{42, 0}); err != nil {
+		t.Fatalf("AddMarketSource error: %v", err)
+	}
+	defer func(l *rate.Limiter) { historyLimiter = l }(historyLimiter)
+
+	const n = 5
+	for i := 0; i < n; i++ {
+		rig.db.trades = append(rig.db.trades, &db.Trade{
+			MatchID:   order.MatchID{byte(i + 1)},
+			Stamp:     uint64(n - i),
+			Quantity:  1e8,
+			Rate:      2e8,
+			TakerSell: i%2 == 0,
+		})
+	}
+
+	req := &msgjson.TradesRequest{BaseID: 42, QuoteID: 0, Limit: 3}
+	resI, err := rig.api.handleTrades(req)
+	if err != nil {
+		t.Fatalf("handleTrades error: %v", err)
+	}
+	res := resI.(*msgjson.TradesResult)
+	if len(res.Trades) != 3 {
+		t.Fatalf("expected 3 trades, got %d", len(res.Trades))
+	}
+	if rig.db.tradesN != 4 {
+		t.Fatalf("expected DB request for 4 trades, got %d", rig.db.tradesN)
+	}
+	if !bytes.Equal(res.Next, res.Trades[2].ID) {
+		t.Fatalf("wrong next page ID %s", res.Next)
+	}
+	if res.Trades[0].QuoteQty != 2e8 {
+		t.Fatalf("wrong quote quantity %d", res.Trades[0].QuoteQty)
+	}
+
+	// Next page.
+	rig.db.trades = rig.db.trades[3:]
+	req.Before = res.Next
+	resI, err = rig.api.handleTrades(req)
+	if err != nil {
+		t.Fatalf("handleTrades (page 2) error: %v", err)
+	}
+	res = resI.(*msgjson.TradesResult)
+	if len(res.Trades) != 2 || res.Next != nil {
+		t.Fatalf("expected the last 2 trades and no next page, got %d, %s", len(res.Trades), res.Next)
+	}
+	if rig.db.tradesBefore == nil || !bytes.Equal(rig.db.tradesBefore[:], req.Before) {
+		t.Fatalf("before ID not passed to DB")
+	}
+
+	// Bad requests.
+	for _, badReq := range []*msgjson.TradesRequest{
+		{BaseID: 42, QuoteID: 0, Limit: MaxTradesRequest + 1},
+		{BaseID: 42, QuoteID: 0, Limit: -1},
+		{BaseID: 42, QuoteID: 0, Before: []byte{1, 2, 3}},
+		{BaseID: 42, QuoteID: 60},
+	} {
+		if _, err = rig.api.handleTrades(badReq); err == nil {
+			t.Fatalf("no error for bad request %+v", badReq)
+		}
+	}
+
+	// DB error
+	rig.db.tradesErr = dummyErr
+	if _, err = rig.api.handleTrades(req); err == nil {
+		t.Fatalf("no error for DB error")
+	}
+	rig.db.tradesErr = nil
+
+	// Rate limited
+	historyLimiter = rate.NewLimiter(0, 0)
+	if _, err = rig.api.handleTrades(req); !errors.Is(err, comms.ErrTooManyRequests) {
+		t.Fatalf("wrong error for rate-limited request: %v", err)
+	}
+}
+
+func TestTickersAndDepth(t *testing.T) {
+	rig := newTestRig()
+	mktSrc := &TMarketSource{42, 0}
+	if err := rig.api.AddMarketSource(mktSrc); err != nil {
+		t.Fatalf("AddMarketSource error: %v", err)
+	}
+	book := &msgjson.OrderBook{}
+	addOrder := func(side uint8, rate, qty uint64) {
+		book.Orders = append(book.Orders, &msgjson.BookOrderNote{
+			TradeNote: msgjson.TradeNote{Side: side, Rate: rate, Quantity: qty},
+		})
+	}
+	addOrder(msgjson.BuyOrderNum, 9, 1)
+	addOrder(msgjson.BuyOrderNum, 10, 2)
+	addOrder(msgjson.BuyOrderNum, 10, 3)
+	addOrder(msgjson.BuyOrderNum, 8, 4)
+	addOrder(msgjson.SellOrderNum, 12, 5)
+	addOrder(msgjson.SellOrderNum, 11, 6)
+	rig.api.SetBookSource(&TBookSource{book})
+
+	epoch := uint64(time.Now().UnixMilli()) / mktSrc.EpochDuration()
+	stats := &matcher.MatchCycleStats{
+		MatchVolume: 100,
+		QuoteVolume: 200,
+		HighRate:    12,
+		LowRate:     10,
+		StartRate:   10,
+		EndRate:     11,
+	}
+	if _, err := rig.api.ReportEpoch(42, 0, epoch-1, stats); err != nil {
+		t.Fatalf("ReportEpoch error: %v", err)
+	}
+
+	tickersI, err := rig.api.handleTickers(nil)
+	if err != nil {
+		t.Fatalf("handleTickers error: %v", err)
+	}
+	tickers := tickersI.([]*msgjson.Ticker)
+	if len(tickers) != 1 {
+		t.Fatalf("expected 1 ticker, got %d", len(tickers))
+	}
+	ticker := tickers[0]
+	if ticker.BaseID != 42 || ticker.QuoteID != 0 {
+		t.Fatalf("wrong ticker market %d-%d", ticker.BaseID, ticker.QuoteID)
+	}
+	if ticker.Rate != 11 || ticker.Bid != 10 || ticker.Ask != 11 {
+		t.Fatalf("wrong ticker rates. rate = %d, bid = %d, ask = %d", ticker.Rate, ticker.Bid, ticker.Ask)
+	}
+	if ticker.Vol24 != 100 || ticker.QuoteVol24 != 200 {
+		t.Fatalf("wrong ticker volumes. vol = %d, quote vol = %d", ticker.Vol24, ticker.QuoteVol24)
+	}
+
+	depthI, err := rig.api.handleDepth(&msgjson.DepthRequest{BaseID: 42, QuoteID: 0, Levels: 2})
+	if err != nil {
+		t.Fatalf("handleDepth error: %v", err)
+	}
+	depth := depthI.(*msgjson.Depth)
+	if len(depth.Bids) != 2 || len(depth.Asks) != 2 {
+		t.Fatalf("wrong number of levels. %d bids, %d asks", len(depth.Bids), len(depth.Asks))
+	}
+	if lvl := depth.Bids[0]; lvl.Rate != 10 || lvl.Qty != 5 || lvl.Orders != 2 {
+		t.Fatalf("wrong best bid level %+v", lvl)
+	}
+	if depth.Bids[1].Rate != 9 {
+		t.Fatalf("wrong second bid level rate %d", depth.Bids[1].Rate)
+	}
+	if depth.Asks[0].Rate != 11 || depth.Asks[1].Rate != 12 {
+		t.Fatalf("wrong ask level rates %d, %d", depth.Asks[0].Rate, depth.Asks[1].Rate)
+	}
+
+	if _, err = rig.api.handleDepth(&msgjson.DepthRequest{BaseID: 42, QuoteID: 0, Levels: MaxDepthLevels + 1}); err == nil {
+		t.Fatalf("no error for too many levels")
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"decred.org/dcrdex/dex"
 	dexbch "decred.org/dcrdex/dex/networks/bch"
 	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/asset"
 	"decred.org/dcrdex/server/asset/btc"
+	srvdex "decred.org/dcrdex/server/dex"
 	"github.com/btcsuite/btcd/chaincfg"
 )
 
@@ -126,12 +128,14 @@ func NewBackend(cfg *asset.BackendConfig) (asset.Backend, error) {
 	}, nil
 }
 
-// BCHBackend embeds *btc.Backend and re-implements the Contract method to deal
-// with Cash Address translation.
+// BCHBackend embeds *btc.Backend and re-implements the Contract and
+// ParseBondTx methods to deal with Cash Address translation.
 type BCHBackend struct {
 	*btc.Backend
 }
 
+var _ srvdex.Bonder = (*BCHBackend)(nil)
+
 // Contract returns the output from embedded Backend's Contract method, but
 // with the SwapAddress field converted to Cash Address encoding.
 func (bch *BCHBackend) Contract(coinID []byte, redeemScript []byte) (*asset.Contract, error) { // Contract.SwapAddress
@@ -145,3 +149,15 @@ func (bch *BCHBackend) Contract(coinID []byte, redeemScript []byte) (*asset.Cont
 	}
 	return contract, nil
 }
+
+// ParseBondTx returns the output from embedded Backend's ParseBondTx method,
+// but with the bond's P2SH address converted to Cash Address encoding.
+func (bch *BCHBackend) ParseBondTx(ver uint16, rawTx []byte) (bondCoinID []byte, amt int64, bondAddr string,
+	bondPubKeyHash []byte, lockTime int64, acct account.AccountID, err error) {
+	bondCoinID, amt, bondAddr, bondPubKeyHash, lockTime, acct, err = bch.Backend.ParseBondTx(ver, rawTx)
+	if err != nil {
+		return
+	}
+	bondAddr, err = dexbch.RecodeCashAddress(bondAddr, bch.Net())
+	return
+}
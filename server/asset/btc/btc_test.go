@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -24,7 +25,10 @@ import (
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/config"
+	dexbch "decred.org/dcrdex/dex/networks/bch"
 	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	dexltc "decred.org/dcrdex/dex/networks/ltc"
+	"decred.org/dcrdex/server/account"
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/btcjson"
@@ -1540,3 +1544,127 @@ func TestSynced(t *testing.T) {
 	}
 	tNode.rawErr = nil
 }
+
+func TestParseBondTx(t *testing.T) {
+	var acctID [32]byte
+	copy(acctID[:], randomBytes(32))
+	pkh := randomBytes(20)
+	lockTime := uint32(time.Now().Add(time.Hour).Unix())
+	const bondAmt = 1e6
+
+	bondScript, err := dexbtc.MakeBondScript(BondVersion, lockTime, pkh)
+	if err != nil {
+		t.Fatalf("MakeBondScript error: %v", err)
+	}
+
+	// OP_RETURN <2-byte version> <32-byte account ID> <4-byte locktime> <20-byte pubkey hash>
+	pushData := make([]byte, 2+32+4+20)
+	binary.BigEndian.PutUint16(pushData, BondVersion)
+	copy(pushData[2:], acctID[:])
+	binary.BigEndian.PutUint32(pushData[34:], lockTime)
+	copy(pushData[38:], pkh)
+	commitScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).AddData(pushData).Script()
+	if err != nil {
+		t.Fatalf("error building commitment script: %v", err)
+	}
+
+	makeBondTx := func(segwit bool) *wire.MsgTx {
+		var pkScript []byte
+		if segwit {
+			h := sha256.Sum256(bondScript)
+			pkScript, _ = txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(h[:]).Script()
+		} else {
+			pkScript, _ = txscript.NewScriptBuilder().AddOp(txscript.OP_HASH160).
+				AddData(btcutil.Hash160(bondScript)).AddOp(txscript.OP_EQUAL).Script()
+		}
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(randomHash(), 0), nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(bondAmt, pkScript))
+		msgTx.AddTxOut(wire.NewTxOut(0, commitScript))
+		return msgTx
+	}
+
+	tests := []struct {
+		name    string
+		params  *chaincfg.Params
+		segwit  bool
+		txSw    bool
+		modify  func(*wire.MsgTx)
+		wantErr bool
+	}{{
+		name:   "btc segwit",
+		params: &chaincfg.MainNetParams,
+		segwit: true,
+		txSw:   true,
+	}, {
+		name:   "ltc segwit",
+		params: dexltc.MainNetParams,
+		segwit: true,
+		txSw:   true,
+	}, {
+		name:   "bch p2sh",
+		params: dexbch.MainNetParams,
+		txSw:   false,
+	}, {
+		name:    "ltc p2sh bond rejected",
+		params:  dexltc.MainNetParams,
+		segwit:  true,
+		txSw:    false,
+		wantErr: true,
+	}, {
+		name:    "bch p2wsh bond rejected",
+		params:  dexbch.MainNetParams,
+		txSw:    true,
+		wantErr: true,
+	}, {
+		name:    "non-zero tx lock time",
+		params:  dexbch.MainNetParams,
+		modify:  func(tx *wire.MsgTx) { tx.LockTime = 1 },
+		wantErr: true,
+	}, {
+		name:    "non-final input",
+		params:  dexltc.MainNetParams,
+		segwit:  true,
+		txSw:    true,
+		modify:  func(tx *wire.MsgTx) { tx.TxIn[0].Sequence = 0 },
+		wantErr: true,
+	}, {
+		name:   "wrong commitment lock time",
+		params: dexltc.MainNetParams,
+		segwit: true,
+		txSw:   true,
+		modify: func(tx *wire.MsgTx) {
+			badPush := append([]byte(nil), pushData...)
+			binary.BigEndian.PutUint32(badPush[34:], lockTime+1)
+			tx.TxOut[1].PkScript, _ = txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).AddData(badPush).Script()
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		msgTx := makeBondTx(test.txSw)
+		if test.modify != nil {
+			test.modify(msgTx)
+		}
+		amt, bondAddr, bondPKH, lt, acct, err := ParseBondTx(BondVersion, msgTx, test.params, test.segwit)
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("%s: no error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: ParseBondTx error: %v", test.name, err)
+		}
+		if amt != bondAmt || lt != int64(lockTime) || acct != account.AccountID(acctID) || !bytes.Equal(bondPKH, pkh) {
+			t.Fatalf("%s: wrong parsed bond values", test.name)
+		}
+		addr, err := btcutil.DecodeAddress(bondAddr, test.params)
+		if err != nil {
+			t.Fatalf("%s: error decoding bond address %q: %v", test.name, bondAddr, err)
+		}
+		if !addr.IsForNet(test.params) {
+			t.Fatalf("%s: bond address %s is not for %s", test.name, bondAddr, test.params.Name)
+		}
+	}
+}
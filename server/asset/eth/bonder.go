@@ -0,0 +1,158 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/asset"
+	srvdex "decred.org/dcrdex/server/dex"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ETHBondBackend is an ETHBackend that accepts fidelity bonds. The driver only
+// returns an ETHBondBackend if the bond contract is deployed on the network.
+type ETHBondBackend struct {
+	*ETHBackend
+	// bondContractAddr is the address of the fidelity bond contract.
+	bondContractAddr common.Address
+}
+
+var _ srvdex.Bonder = (*ETHBondBackend)(nil)
+
+// BondVer returns the latest supported bond version.
+func (eth *ETHBondBackend) BondVer() uint16 {
+	return dexeth.BondVersion
+}
+
+// ParseBondTx performs basic validation of a serialized fidelity bond
+// transaction, which must call the bond contract's createBond method. The
+// transaction may be unsigned, in which case the returned coin ID will not be
+// the ID of the broadcasted transaction. The returned bondPubKeyHash is the
+// contract's bond ID.
+func (eth *ETHBondBackend) ParseBondTx(ver uint16, rawTx []byte) (bondCoinID []byte, amt int64, bondAddr string,
+	bondPubKeyHash []byte, lockTime int64, acct account.AccountID, err error) {
+	if ver != dexeth.BondVersion {
+		err = fmt.Errorf("unsupported bond version %d", ver)
+		return
+	}
+	tx := new(types.Transaction)
+	if err = tx.UnmarshalBinary(rawTx); err != nil {
+		err = fmt.Errorf("error decoding bond transaction: %w", err)
+		return
+	}
+	var bondID [32]byte
+	amt, bondID, lockTime, acct, err = eth.parseBondTx(tx)
+	if err != nil {
+		return
+	}
+	bondCoinID = tx.Hash().Bytes()
+	bondAddr = eth.bondContractAddr.String()
+	bondPubKeyHash = bondID[:]
+	return
+}
+
+// parseBondTx checks that the transaction creates a bond in the bond contract
+// and returns the bond's value in gwei, bond ID, lock time, and account.
+func (eth *ETHBondBackend) parseBondTx(tx *types.Transaction) (amt int64, bondID [32]byte, lockTime int64, acct account.AccountID, err error) {
+	if eth.bondContractAddr == (common.Address{}) {
+		err = fmt.Errorf("no %s bond contract on %s", eth.baseChainName, eth.net)
+		return
+	}
+	if to := tx.To(); to == nil || *to != eth.bondContractAddr {
+		err = errors.New("bond transaction is not for the bond contract")
+		return
+	}
+	acctID, bondID, lt, err := dexeth.ParseCreateBondData(tx.Data())
+	if err != nil {
+		err = fmt.Errorf("invalid bond transaction data: %w", err)
+		return
+	}
+	if lt > math.MaxInt64 {
+		err = fmt.Errorf("lock time %d out of range", lt)
+		return
+	}
+	// Bond amounts are in gwei, so the value must be a whole number of gwei.
+	gwei := dexeth.WeiToGwei(tx.Value())
+	if gwei == 0 || gwei > math.MaxInt64 || dexeth.GweiToWei(gwei).Cmp(tx.Value()) != 0 {
+		err = fmt.Errorf("invalid bond value %s wei", tx.Value())
+		return
+	}
+	return int64(gwei), bondID, int64(lt), account.AccountID(acctID), nil
+}
+
+// BondCoin locates a bond transaction, validates it against the bond
+// contract's state, and returns the amount, lock time and account ID, and the
+// confirmations of the transaction. It is a CoinNotFoundError if the bond has
+// been refunded.
+func (eth *ETHBondBackend) BondCoin(ctx context.Context, ver uint16, coinID []byte) (amt, lockTime, confs int64, acct account.AccountID, err error) {
+	if ver != dexeth.BondVersion {
+		err = fmt.Errorf("unsupported bond version %d", ver)
+		return
+	}
+	txHash, err := dexeth.DecodeCoinID(coinID)
+	if err != nil {
+		err = fmt.Errorf("error decoding coin ID %x: %w", coinID, err)
+		return
+	}
+	tx, isMempool, err := eth.node.transaction(ctx, txHash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			err = asset.CoinNotFoundError
+		}
+		return
+	}
+	var bondID [32]byte
+	amt, bondID, lockTime, acct, err = eth.parseBondTx(tx)
+	if err != nil || isMempool {
+		// Zero confs while in mempool. The contract state is checked once
+		// the transaction is mined.
+		return
+	}
+
+	receipt, err := eth.node.transactionReceipt(ctx, txHash)
+	if err != nil {
+		err = fmt.Errorf("error retrieving bond transaction receipt: %w", err)
+		return
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		err = fmt.Errorf("bond transaction %s failed", txHash)
+		return
+	}
+
+	bond, err := eth.node.bond(ctx, eth.bondContractAddr, bondID)
+	if err != nil {
+		err = fmt.Errorf("error retrieving bond state: %w", err)
+		return
+	}
+	if bond.Owner == (common.Address{}) {
+		err = fmt.Errorf("bond %x not found in contract", bondID)
+		return
+	}
+	if bond.Value.Sign() == 0 { // refunded, like a spent bond output
+		err = asset.CoinNotFoundError
+		return
+	}
+	if bond.Value.Cmp(tx.Value()) != 0 || bond.AcctID != acct || int64(bond.LockTime) != lockTime {
+		err = fmt.Errorf("bond %x contract state does not match transaction", bondID)
+		return
+	}
+
+	bn, err := eth.node.blockNumber(ctx)
+	if err != nil {
+		err = fmt.Errorf("unable to fetch block number: %w", err)
+		return
+	}
+	if mined := receipt.BlockNumber.Uint64(); bn >= mined {
+		confs = int64(bn - mined + 1)
+	}
+	return
+}
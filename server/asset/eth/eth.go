@@ -200,7 +200,19 @@ func (d *Driver) Setup(cfg *asset.BackendConfig) (asset.Backend, error) {
 		}
 	}
 
-	return NewEVMBackend(cfg, chainID, dexeth.ContractAddresses, registeredTokens)
+	be, err := NewEVMBackend(cfg, chainID, dexeth.ContractAddresses, registeredTokens)
+	if err != nil {
+		return nil, err
+	}
+	// Bonds are only supported if the bond contract is deployed on this
+	// network.
+	if bondContractAddr, found := dexeth.BondContractAddresses[cfg.Net]; found {
+		return &ETHBondBackend{
+			ETHBackend:       be,
+			bondContractAddr: bondContractAddr,
+		}, nil
+	}
+	return be, nil
 }
 
 type TokenDriver struct {
@@ -234,6 +246,8 @@ type ethFetcher interface {
 	vector(ctx context.Context, assetID uint32, locator []byte) (*dexeth.SwapVector, error)
 	statusAndVector(ctx context.Context, assetID uint32, locator []byte) (*dexeth.SwapStatus, *dexeth.SwapVector, error)
 	accountBalance(ctx context.Context, assetID uint32, addr common.Address) (*big.Int, error)
+	// bond gets the state of a bond from the bond contract.
+	bond(ctx context.Context, contractAddr common.Address, bondID [32]byte) (*dexeth.BondState, error)
 }
 
 type baseBackend struct {
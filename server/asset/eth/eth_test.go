@@ -21,6 +21,7 @@ import (
 	"decred.org/dcrdex/dex/calc"
 	"decred.org/dcrdex/dex/encode"
 	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/server/asset"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -163,6 +164,8 @@ type testNode struct {
 	receipt          *types.Receipt
 	acctBal          *big.Int
 	acctBalErr       error
+	bondState        *dexeth.BondState
+	bondErr          error
 }
 
 func (n *testNode) connect(ctx context.Context) error {
@@ -246,6 +249,10 @@ func (n *testNode) accountBalance(ctx context.Context, assetID uint32, addr comm
 	return n.acctBal, n.acctBalErr
 }
 
+func (n *testNode) bond(ctx context.Context, contractAddr common.Address, bondID [32]byte) (*dexeth.BondState, error) {
+	return n.bondState, n.bondErr
+}
+
 func tSwap(bn, locktime int64, value uint64, secret [32]byte, state dexeth.SwapStep, participantAddr *common.Address) *dexeth.SwapState {
 	return &dexeth.SwapState{
 		Secret:      secret,
@@ -1104,3 +1111,131 @@ func TestParseEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestBondCoin(t *testing.T) {
+	be, node := tNewBackend(BipID)
+	bondContractAddr := common.BytesToAddress(encode.RandomBytes(20))
+	eth := &ETHBondBackend{
+		ETHBackend:       &ETHBackend{be},
+		bondContractAddr: bondContractAddr,
+	}
+
+	var acctID, bondID [32]byte
+	copy(acctID[:], encode.RandomBytes(32))
+	copy(bondID[:], encode.RandomBytes(32))
+	const bondAmt = 5e8 // gwei
+	lockTime := uint64(time.Now().Add(time.Hour).Unix())
+
+	data, err := dexeth.PackCreateBondData(acctID, bondID, lockTime)
+	if err != nil {
+		t.Fatalf("PackCreateBondData error: %v", err)
+	}
+	bondTx := tTx(200, 2, bondAmt, &bondContractAddr, data)
+	rawTx, err := bondTx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary error: %v", err)
+	}
+
+	// ParseBondTx
+	coinID, amt, _, bondIDB, lt, acct, err := eth.ParseBondTx(dexeth.BondVersion, rawTx)
+	if err != nil {
+		t.Fatalf("ParseBondTx error: %v", err)
+	}
+	if !bytes.Equal(coinID, bondTx.Hash().Bytes()) || amt != bondAmt || !bytes.Equal(bondIDB, bondID[:]) ||
+		lt != int64(lockTime) || acct != account.AccountID(acctID) {
+		t.Fatalf("wrong parsed bond values")
+	}
+	if _, _, _, _, _, _, err = eth.ParseBondTx(dexeth.BondVersion+1, rawTx); err == nil {
+		t.Fatalf("no error for wrong bond version")
+	}
+	otherAddr := common.BytesToAddress(encode.RandomBytes(20))
+	rawOther, _ := tTx(200, 2, bondAmt, &otherAddr, data).MarshalBinary()
+	if _, _, _, _, _, _, err = eth.ParseBondTx(dexeth.BondVersion, rawOther); err == nil {
+		t.Fatalf("no error for tx to wrong contract")
+	}
+	fractionalTx := types.NewTx(&types.DynamicFeeTx{To: &bondContractAddr, Value: big.NewInt(1), Data: data})
+	rawFractional, _ := fractionalTx.MarshalBinary()
+	if _, _, _, _, _, _, err = eth.ParseBondTx(dexeth.BondVersion, rawFractional); err == nil {
+		t.Fatalf("no error for bond value that is not whole gwei")
+	}
+
+	// BondCoin
+	const minedAt = 10
+	node.tx = bondTx
+	node.blkNum = minedAt + 2
+	node.receipt = &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(minedAt)}
+	node.bondState = &dexeth.BondState{
+		AcctID:   acctID,
+		Value:    dexeth.GweiToWei(bondAmt),
+		Owner:    common.BytesToAddress(encode.RandomBytes(20)),
+		LockTime: lockTime,
+	}
+
+	tests := []struct {
+		name     string
+		modify   func()
+		wantErr  error
+		anyErr   bool
+		expConfs int64
+	}{{
+		name:     "ok",
+		modify:   func() {},
+		expConfs: 3,
+	}, {
+		name:     "mempool",
+		modify:   func() { node.txIsMempool = true },
+		expConfs: 0,
+	}, {
+		name:    "tx not found",
+		modify:  func() { node.txErr = ethereum.NotFound },
+		wantErr: asset.CoinNotFoundError,
+	}, {
+		name: "failed tx",
+		modify: func() {
+			node.receipt = &types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(minedAt)}
+		},
+		anyErr: true,
+	}, {
+		name:    "refunded",
+		modify:  func() { node.bondState.Value = new(big.Int) },
+		wantErr: asset.CoinNotFoundError,
+	}, {
+		name:   "wrong account",
+		modify: func() { node.bondState.AcctID = [32]byte{1} },
+		anyErr: true,
+	}, {
+		name:   "no bond contract",
+		modify: func() { eth.bondContractAddr = common.Address{} },
+		anyErr: true,
+	}}
+
+	for _, test := range tests {
+		origState := *node.bondState
+		origReceipt := node.receipt
+		test.modify()
+		amt, lt, confs, acct, err := eth.BondCoin(tCtx, dexeth.BondVersion, bondTx.Hash().Bytes())
+		node.txIsMempool = false
+		node.txErr = nil
+		node.receipt = origReceipt
+		*node.bondState = origState
+		eth.bondContractAddr = bondContractAddr
+		if test.wantErr != nil || test.anyErr {
+			if err == nil {
+				t.Fatalf("%s: no error", test.name)
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Fatalf("%s: wrong error %v", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if amt != bondAmt || lt != int64(lockTime) || acct != account.AccountID(acctID) {
+			t.Fatalf("%s: wrong bond values", test.name)
+		}
+		if confs != test.expConfs {
+			t.Fatalf("%s: wrong confs. wanted %d, got %d", test.name, test.expConfs, confs)
+		}
+	}
+}
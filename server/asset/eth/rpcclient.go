@@ -622,6 +622,22 @@ func (c *rpcclient) transactionReceipt(ctx context.Context, txHash common.Hash)
 	})
 }
 
+// bond gets the state of a bond from the bond contract.
+func (c *rpcclient) bond(ctx context.Context, contractAddr common.Address, bondID [32]byte) (state *dexeth.BondState, err error) {
+	data, err := dexeth.PackBondsData(bondID)
+	if err != nil {
+		return nil, fmt.Errorf("error packing bonds call: %w", err)
+	}
+	return state, c.withClient(func(ec *ethConn) error {
+		res, err := ec.CallContract(ctx, ethereum.CallMsg{To: &contractAddr, Data: data}, nil)
+		if err != nil {
+			return err
+		}
+		state, err = dexeth.UnpackBondsResult(res)
+		return err
+	})
+}
+
 func isNotFoundError(err error) bool {
 	return strings.Contains(err.Error(), "not found")
 }
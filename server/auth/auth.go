@@ -54,8 +54,10 @@ type Storage interface {
 	AddBond(acct account.AccountID, bond *db.Bond) error
 	DeleteBond(assetID uint32, coinID []byte) error
 	FetchPrepaidBond(bondCoinID []byte) (strength uint32, lockTime int64, err error)
-	DeletePrepaidBond(coinID []byte) error
-	StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error
+	RedeemPrepaidBond(coinID []byte, acct account.AccountID) error
+	StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64, batch string) error
+	PrepaidBonds(batch string) ([]*db.PrepaidBond, error)
+	RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error)
 
 	AccountInfo(aid account.AccountID) (*db.Account, error)
 
@@ -120,6 +122,10 @@ type clientInfo struct {
 	tier         int64
 	score        int32
 	bonds        []*db.Bond // only confirmed and active, not pending
+
+	// apiVer and caps are negotiated on connect and do not change.
+	apiVer uint16
+	caps   msgjson.Capabilities
 }
 
 // not thread-safe
@@ -238,6 +244,9 @@ type AuthManager struct {
 	bondExpiry time.Duration // a bond is expired when time.Until(lockTime) < bondExpiry
 	bondAssets map[uint32]*msgjson.BondAsset
 
+	apiVer uint16
+	caps   msgjson.Capabilities
+
 	freeCancels      bool
 	penaltyThreshold int32
 	cancelThresh     float64
@@ -398,6 +407,12 @@ type Config struct {
 	// PenaltyThreshold defines the score deficit at which a user's bond is
 	// revoked.
 	PenaltyThreshold uint32
+
+	// APIVersion is the server's API version, and Capabilities are the
+	// optional protocol features the server supports. The API version and
+	// capabilities used with each client are negotiated on connect.
+	APIVersion   uint16
+	Capabilities msgjson.Capabilities
 }
 
 // NewAuthManager is the constructor for an AuthManager.
@@ -439,6 +454,8 @@ func NewAuthManager(cfg *Config) *AuthManager {
 		preimgOutcomes:   make(map[account.AccountID]*latestPreimageOutcomes),
 		orderOutcomes:    make(map[account.AccountID]*latestOrders),
 		txDataSources:    cfg.TxDataSources,
+		apiVer:           cfg.APIVersion,
+		caps:             cfg.Capabilities,
 	}
 
 	// Unauthenticated
@@ -447,6 +464,7 @@ func NewAuthManager(cfg *Config) *AuthManager {
 	cfg.Route(msgjson.PreValidateBondRoute, auth.handlePreValidateBond)
 	cfg.Route(msgjson.MatchStatusRoute, auth.handleMatchStatus)
 	cfg.Route(msgjson.OrderStatusRoute, auth.handleOrderStatus)
+	cfg.Route(msgjson.ReputationRoute, auth.handleReputation)
 	return auth
 }
 
@@ -761,17 +779,100 @@ func (auth *AuthManager) integrateOutcomes(
 		score += ViolationPreimageMiss.Score() * piMissCount
 	}
 	if !auth.freeCancels {
-		totalOrds, cancels := orderOutcomes.counts() // completions := totalOrds - cancels
-		if totalOrds > auth.GraceLimit() {
-			cancelRate := float64(cancels) / float64(totalOrds)
-			if cancelRate > auth.cancelThresh {
-				score += ViolationCancelRate.Score()
-			}
+		_, _, cancelScore := auth.cancelRateScore(orderOutcomes)
+		score += cancelScore
+	}
+	return
+}
+
+// cancelRateScore computes the score for the user's cancellation rate. The
+// score is only negative if there are more than GraceLimit orders and the
+// cancellation rate exceeds the threshold.
+func (auth *AuthManager) cancelRateScore(orderOutcomes *latestOrders) (totalOrds, cancels int, score int32) {
+	totalOrds, cancels = orderOutcomes.counts() // completions := totalOrds - cancels
+	if totalOrds > auth.GraceLimit() {
+		cancelRate := float64(cancels) / float64(totalOrds)
+		if cancelRate > auth.cancelThresh {
+			score = ViolationCancelRate.Score()
 		}
 	}
 	return
 }
 
+// reputationBreakdown itemizes the outcomes that make up the user's score. The
+// outcomes are loaded from the DB if the user is not connected.
+func (auth *AuthManager) reputationBreakdown(user account.AccountID, bondTier int64) (*msgjson.ReputationResult, error) {
+	res := &msgjson.ReputationResult{
+		PenaltyThreshold: auth.penaltyThreshold,
+		MatchLimit:       ScoringMatchLimit,
+		OrderLimit:       scoringOrderLimit,
+	}
+
+	var matches []*matchOutcome
+	var preimages []*preimageOutcome
+	var score int32
+	breakdown := func(matchOutcomes *latestMatchOutcomes, preimgOutcomes *latestPreimageOutcomes, orderOutcomes *latestOrders) {
+		score, _, _ = auth.integrateOutcomes(matchOutcomes, preimgOutcomes, orderOutcomes)
+		if matchOutcomes != nil {
+			matches = matchOutcomes.list()
+		}
+		if preimgOutcomes != nil {
+			preimages = preimgOutcomes.list()
+		}
+		if !auth.freeCancels {
+			totalOrds, cancels, cancelScore := auth.cancelRateScore(orderOutcomes)
+			res.CancelRate = &msgjson.CancelRateScore{
+				Orders:     uint32(totalOrds),
+				Cancels:    uint32(cancels),
+				GraceLimit: uint32(auth.GraceLimit()),
+				Threshold:  auth.cancelThresh,
+				Score:      cancelScore,
+			}
+		}
+	}
+
+	auth.violationMtx.Lock()
+	matchOutcomes, found := auth.matchOutcomes[user]
+	if found {
+		breakdown(matchOutcomes, auth.preimgOutcomes[user], auth.orderOutcomes[user])
+	}
+	auth.violationMtx.Unlock()
+	if !found {
+		matchOutcomes, preimgOutcomes, orderOutcomes, err := auth.loadUserOutcomes(user)
+		if err != nil {
+			return nil, err
+		}
+		breakdown(matchOutcomes, preimgOutcomes, orderOutcomes)
+	}
+
+	res.Reputation = auth.userReputation(bondTier, score)
+	res.Matches = make([]*msgjson.ScoredMatch, 0, len(matches))
+	for _, mo := range matches {
+		res.Matches = append(res.Matches, &msgjson.ScoredMatch{
+			MatchID: mo.mid[:],
+			BaseID:  mo.base,
+			QuoteID: mo.quote,
+			Stamp:   uint64(mo.time),
+			Value:   mo.value,
+			Outcome: mo.outcome.String(),
+			Score:   mo.outcome.Score(),
+		})
+	}
+	res.PreimageOrders = uint32(len(preimages))
+	res.PreimageMisses = make([]*msgjson.ScoredOrder, 0)
+	for _, po := range preimages {
+		if !po.miss {
+			continue
+		}
+		res.PreimageMisses = append(res.PreimageMisses, &msgjson.ScoredOrder{
+			OrderID: po.oid[:],
+			Stamp:   uint64(po.time),
+			Score:   ViolationPreimageMiss.Score(),
+		})
+	}
+	return res, nil
+}
+
 // userScore computes an authenticated user's score from their recent order and
 // match outcomes. They must have entries in the outcome maps. Use loadUserScore
 // to compute score from history in DB. This must be called with the
@@ -1111,18 +1212,88 @@ func (auth *AuthManager) ForgiveMatchFail(user account.AccountID, mid order.Matc
 	return
 }
 
-// CreatePrepaidBonds generates pre-paid bonds.
-func (auth *AuthManager) CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error) {
-	coinIDs := make([][]byte, n)
+// CreatePrepaidBonds generates a batch of pre-paid bonds. The batch ID may be
+// used to look up the redemption status of the bonds or to revoke them.
+func (auth *AuthManager) CreatePrepaidBonds(n int, strength uint32, durSecs int64) (batch string, coinIDs [][]byte, err error) {
+	coinIDs = make([][]byte, n)
 	const prepaidBondIDLength = 16
 	for i := 0; i < n; i++ {
 		coinIDs[i] = encode.RandomBytes(prepaidBondIDLength)
 	}
+	const batchIDLength = 8
+	batch = hex.EncodeToString(encode.RandomBytes(batchIDLength))
 	lockTime := time.Now().Add(auth.bondExpiry).Add(time.Duration(durSecs) * time.Second)
-	if err := auth.storage.StorePrepaidBonds(coinIDs, strength, lockTime.Unix()); err != nil {
+	if err := auth.storage.StorePrepaidBonds(coinIDs, strength, lockTime.Unix(), batch); err != nil {
+		return "", nil, err
+	}
+	return batch, coinIDs, nil
+}
+
+// Pre-paid bond statuses.
+const (
+	PrepaidBondUnredeemed = "unredeemed"
+	PrepaidBondRedeemed   = "redeemed"
+	PrepaidBondRevoked    = "revoked"
+	PrepaidBondExpired    = "expired"
+)
+
+// PrepaidBond is a JSON-friendly version of db.PrepaidBond, with the bond's
+// current redemption status.
+type PrepaidBond struct {
+	Code     dex.Bytes `json:"code"`
+	Batch    string    `json:"batch"`
+	Strength uint32    `json:"strength"`
+	Created  int64     `json:"created"`
+	// Expiry is when the bond expires. Unredeemed bonds can no longer be
+	// redeemed within a day of expiry.
+	Expiry     int64     `json:"expiry"`
+	Status     string    `json:"status"`
+	RedeemedBy dex.Bytes `json:"redeemedBy,omitempty"`
+	Redeemed   int64     `json:"redeemed,omitempty"`
+}
+
+// PrepaidBonds retrieves the pre-paid bonds in a batch, or all pre-paid bonds
+// if batch is empty.
+func (auth *AuthManager) PrepaidBonds(batch string) ([]*PrepaidBond, error) {
+	dbBonds, err := auth.storage.PrepaidBonds(batch)
+	if err != nil {
 		return nil, err
 	}
-	return coinIDs, nil
+	now := time.Now()
+	bonds := make([]*PrepaidBond, 0, len(dbBonds))
+	for _, b := range dbBonds {
+		expireTime := time.Unix(b.LockTime, 0).Add(-auth.bondExpiry)
+		bond := &PrepaidBond{
+			Code:       b.CoinID,
+			Batch:      b.Batch,
+			Strength:   b.Strength,
+			Created:    b.Created,
+			Expiry:     expireTime.Unix(),
+			RedeemedBy: b.RedeemedBy,
+			Redeemed:   b.Redeemed,
+		}
+		switch {
+		case b.RedeemedBy != nil:
+			bond.Status = PrepaidBondRedeemed
+		case b.Revoked:
+			bond.Status = PrepaidBondRevoked
+		case expireTime.Sub(now) < prepaidBondMinRemaining:
+			bond.Status = PrepaidBondExpired
+		default:
+			bond.Status = PrepaidBondUnredeemed
+		}
+		bonds = append(bonds, bond)
+	}
+	return bonds, nil
+}
+
+// RevokePrepaidBonds revokes unredeemed pre-paid bonds so that they can no
+// longer be redeemed. If coinIDs is empty, all unredeemed bonds in the batch
+// are revoked. The number of revoked bonds is returned.
+func (auth *AuthManager) RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error) {
+	auth.prepaidBondMtx.Lock()
+	defer auth.prepaidBondMtx.Unlock()
+	return auth.storage.RevokePrepaidBonds(batch, coinIDs)
 }
 
 // TODO: a way to manipulate/forgive cancellation rate violation.
@@ -1134,6 +1305,18 @@ func (auth *AuthManager) user(user account.AccountID) *clientInfo {
 	return auth.users[user]
 }
 
+// UserAPI returns the API version and capabilities negotiated with a
+// connected user. Messages that not all clients understand should only be sent
+// to users with the corresponding capability. ok is false if the user is not
+// connected.
+func (auth *AuthManager) UserAPI(user account.AccountID) (apiVer uint16, caps msgjson.Capabilities, ok bool) {
+	client := auth.user(user)
+	if client == nil {
+		return 0, 0, false
+	}
+	return client.apiVer, client.caps, true
+}
+
 // conn gets the clientInfo for the specified connection ID.
 func (auth *AuthManager) conn(conn comms.Link) *clientInfo {
 	auth.connMtx.RLock()
@@ -1566,10 +1749,13 @@ func (auth *AuthManager) handleConnect(conn comms.Link, msg *msgjson.Message) *m
 	auth.orderOutcomes[user] = latestFinished
 	auth.violationMtx.Unlock()
 
+	apiVer, caps := msgjson.NegotiateAPI(auth.apiVer, auth.caps, connect.APIVersion, connect.Capabilities)
 	client := &clientInfo{
 		acct:         acctInfo,
 		conn:         conn,
 		respHandlers: respHandlers,
+		apiVer:       apiVer,
+		caps:         caps,
 	}
 
 	// Get the list of active orders for this user.
@@ -1687,6 +1873,8 @@ func (auth *AuthManager) handleConnect(conn comms.Link, msg *msgjson.Message) *m
 		Score:               score,
 		ActiveBonds:         msgBonds,
 		Reputation:          rep,
+		APIVersion:          apiVer,
+		Capabilities:        caps,
 	}
 	respMsg, err := msgjson.NewResponse(msg.ID, resp, nil)
 	if err != nil {
@@ -1704,8 +1892,8 @@ func (auth *AuthManager) handleConnect(conn comms.Link, msg *msgjson.Message) *m
 	}
 
 	log.Infof("Authenticated account %v from %v with %d active orders, %d active matches, tier = %v, "+
-		"bond tier = %v, score = %v",
-		user, conn.Addr(), len(msgOrderStatuses), len(msgMatches), client.tier, bondTier, score)
+		"bond tier = %v, score = %v, API version = %d, capabilities = %#x",
+		user, conn.Addr(), len(msgOrderStatuses), len(msgMatches), client.tier, bondTier, score, apiVer, caps)
 	auth.addClient(client)
 
 	return nil
@@ -2024,6 +2212,38 @@ func (auth *AuthManager) handleOrderStatus(conn comms.Link, msg *msgjson.Message
 	return nil
 }
 
+// handleReputation handles requests to the 'reputation' route. The user's
+// score is itemized so that clients can explain changes to their tier.
+func (auth *AuthManager) handleReputation(conn comms.Link, msg *msgjson.Message) *msgjson.Error {
+	client := auth.conn(conn)
+	if client == nil {
+		return msgjson.NewError(msgjson.UnauthorizedConnection,
+			"cannot use route 'reputation' on an unauthorized connection")
+	}
+
+	client.mtx.Lock()
+	bondTier := client.bondTier()
+	client.mtx.Unlock()
+
+	res, err := auth.reputationBreakdown(client.acct.ID, bondTier)
+	if err != nil {
+		log.Errorf("Failed to compute reputation breakdown for user %v: %v", client.acct.ID, err)
+		return msgjson.NewError(msgjson.RPCInternalError, "DB error")
+	}
+
+	resp, err := msgjson.NewResponse(msg.ID, res, nil)
+	if err != nil {
+		log.Errorf("NewResponse error: %v", err)
+		return msgjson.NewError(msgjson.RPCInternalError, "Internal error")
+	}
+
+	err = conn.Send(resp)
+	if err != nil {
+		log.Error("error sending reputation response: " + err.Error())
+	}
+	return nil
+}
+
 func coinIDString(assetID uint32, coinID []byte) string {
 	s, err := asset.DecodeCoinID(assetID, coinID)
 	if err != nil {
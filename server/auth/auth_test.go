@@ -63,6 +63,7 @@ type TStorage struct {
 	regErr              error
 	payErr              error
 	bonds               []*db.Bond
+	prepaidBonds        []*db.PrepaidBond
 	ratio               ratioData
 }
 
@@ -81,10 +82,16 @@ func (s *TStorage) DeleteBond(assetID uint32, coinID []byte) error
 func (s *TStorage) FetchPrepaidBond([]byte) (uint32, int64, error) {
 	return 1, time.Now().Add(time.Hour * 48).Unix(), nil
 }
-func (s *TStorage) DeletePrepaidBond(coinID []byte) (err error) { return nil }
-func (s *TStorage) StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error {
+func (s *TStorage) RedeemPrepaidBond(coinID []byte, aid account.AccountID) error { return nil }
+func (s *TStorage) StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64, batch string) error {
 	return nil
 }
+func (s *TStorage) PrepaidBonds(batch string) ([]*db.PrepaidBond, error) {
+	return s.prepaidBonds, nil
+}
+func (s *TStorage) RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error) {
+	return 0, nil
+}
 func (s *TStorage) CompletedAndAtFaultMatchStats(aid account.AccountID, lastN int) ([]*db.MatchOutcome, error) {
 	return s.userMatchOutcomes, nil
 }
@@ -188,6 +195,18 @@ func (c *TRPCClient) SendRaw(b []byte) error {
 	c.sends = append(c.sends, msg)
 	return nil
 }
+func (c *TRPCClient) CBOR() bool { return false }
+func (c *TRPCClient) SendRawCBOR(b []byte) error {
+	if c.sendRawErr != nil {
+		return c.sendRawErr
+	}
+	msg, err := msgjson.DecodeCBORMessage(b)
+	if err != nil {
+		return err
+	}
+	c.sends = append(c.sends, msg)
+	return nil
+}
 func (c *TRPCClient) SendError(id uint64, msg *msgjson.Error) {
 }
 func (c *TRPCClient) Request(msg *msgjson.Message, f func(comms.Link, *msgjson.Message), _ time.Duration, _ func()) error {
@@ -954,6 +973,52 @@ func TestConnect(t *testing.T) {
 	}
 }
 
+func TestConnectNegotiation(t *testing.T) {
+	const capA, capB, capC msgjson.Capabilities = 1 << 0, 1 << 1, 1 << 2
+	defer func(apiVer uint16, caps msgjson.Capabilities) {
+		rig.mgr.apiVer, rig.mgr.caps = apiVer, caps
+	}(rig.mgr.apiVer, rig.mgr.caps)
+	rig.mgr.apiVer, rig.mgr.caps = 2, capA|capB
+
+	tests := []struct {
+		name       string
+		clientVer  uint16
+		clientCaps msgjson.Capabilities
+		expVer     uint16
+		expCaps    msgjson.Capabilities
+	}{
+		{"legacy client", 0, 0, 0, 0},
+		{"older client", 1, capA, 1, capA},
+		{"newer client", 3, capA | capB | capC, 2, capA | capB},
+		{"disjoint capabilities", 2, capC, 2, 0},
+	}
+	for _, tt := range tests {
+		user := tNewUser(t)
+		rig.signer.sig = user.randomSignature()
+		rig.storage.acct = &account.Account{ID: user.acctID, PubKey: user.privKey.PubKey()}
+		connect := &msgjson.Connect{
+			AccountID:    user.acctID[:],
+			APIVersion:   tt.clientVer,
+			Time:         uint64(time.Now().UnixMilli()),
+			Capabilities: tt.clientCaps,
+		}
+		connect.SetSig(signMsg(user.privKey, connect.Serialize()))
+		msg, _ := msgjson.NewRequest(comms.NextID(), msgjson.ConnectRoute, connect)
+		if rpcErr := rig.mgr.handleConnect(user.conn, msg); rpcErr != nil {
+			t.Fatalf("%s: handleConnect error: %v", tt.name, rpcErr)
+		}
+		result := extractConnectResult(t, user.conn.getSend())
+		if result.APIVersion != tt.expVer || result.Capabilities != tt.expCaps {
+			t.Fatalf("%s: expected version %d and capabilities %#x, got %d and %#x", tt.name,
+				tt.expVer, tt.expCaps, result.APIVersion, result.Capabilities)
+		}
+		apiVer, caps, ok := rig.mgr.UserAPI(user.acctID)
+		if !ok || apiVer != tt.expVer || caps != tt.expCaps {
+			t.Fatalf("%s: wrong UserAPI %d, %#x, %t", tt.name, apiVer, caps, ok)
+		}
+	}
+}
+
 func TestAccountErrors(t *testing.T) {
 	user := tNewUser(t)
 	rig.signer.sig = user.randomSignature()
@@ -1520,6 +1585,98 @@ func TestOrderStatus(t *testing.T) {
 	}
 }
 
+func TestReputation(t *testing.T) {
+	wantScore := setViolations()
+	defer clearViolations()
+	defer func() { rig.storage.userPreimageResults = nil }()
+	user := tNewUser(t)
+	rig.signer.sig = user.randomSignature()
+	connectUser(t, user)
+
+	req, _ := msgjson.NewRequest(1, msgjson.ReputationRoute, nil)
+	msgErr := rig.mgr.handleReputation(user.conn, req)
+	if msgErr != nil {
+		t.Fatalf("handleReputation error: %v", msgErr)
+	}
+	resp := user.conn.getSend()
+	if resp == nil {
+		t.Fatalf("no reputation sent")
+	}
+	var res msgjson.ReputationResult
+	if err := resp.UnmarshalResult(&res); err != nil {
+		t.Fatalf("UnmarshalResult error: %v", err)
+	}
+
+	if res.Reputation == nil || res.Reputation.Score != wantScore {
+		t.Fatalf("wrong reputation %+v, wanted score %d", res.Reputation, wantScore)
+	}
+	if len(res.Matches) != len(rig.storage.userMatchOutcomes) {
+		t.Fatalf("expected %d matches, got %d", len(rig.storage.userMatchOutcomes), len(res.Matches))
+	}
+	var matchScore int32
+	for i, m := range res.Matches {
+		if i > 0 && m.Stamp > res.Matches[i-1].Stamp {
+			t.Fatalf("matches not sorted newest first")
+		}
+		matchScore += m.Score
+	}
+	if res.PreimageOrders != uint32(len(rig.storage.userPreimageResults)) {
+		t.Fatalf("expected %d preimage orders, got %d", len(rig.storage.userPreimageResults), res.PreimageOrders)
+	}
+	if len(res.PreimageMisses) != 1 {
+		t.Fatalf("expected 1 preimage miss, got %d", len(res.PreimageMisses))
+	}
+	var cancelScore int32
+	if res.CancelRate != nil {
+		cancelScore = res.CancelRate.Score
+	}
+	if sum := matchScore + res.PreimageMisses[0].Score + cancelScore; sum != wantScore {
+		t.Fatalf("itemized scores sum to %d, wanted %d", sum, wantScore)
+	}
+
+	// Unauthorized connection
+	msgErr = rig.mgr.handleReputation(tNewUser(t).conn, req)
+	if msgErr == nil || msgErr.Code != msgjson.UnauthorizedConnection {
+		t.Fatalf("wrong error for unauthorized connection: %v", msgErr)
+	}
+}
+
+func TestPrepaidBonds(t *testing.T) {
+	now := time.Now()
+	bondExpiry := rig.mgr.bondExpiry
+	lockTime := func(untilExpiry time.Duration) int64 {
+		return now.Add(bondExpiry + untilExpiry).Unix()
+	}
+	user := tNewUser(t)
+	rig.storage.prepaidBonds = []*db.PrepaidBond{
+		{CoinID: []byte{1}, Batch: "a", LockTime: lockTime(time.Hour * 48)},
+		{CoinID: []byte{2}, Batch: "a", LockTime: lockTime(time.Hour * 48), RedeemedBy: user.acctID[:], Redeemed: now.Unix()},
+		{CoinID: []byte{3}, Batch: "a", LockTime: lockTime(time.Hour * 48), Revoked: true},
+		{CoinID: []byte{4}, Batch: "a", LockTime: lockTime(time.Hour)},
+		// Redemption takes precedence over expiry.
+		{CoinID: []byte{5}, Batch: "a", LockTime: lockTime(-time.Hour), RedeemedBy: user.acctID[:]},
+	}
+	defer func() { rig.storage.prepaidBonds = nil }()
+
+	bonds, err := rig.mgr.PrepaidBonds("a")
+	if err != nil {
+		t.Fatalf("PrepaidBonds error: %v", err)
+	}
+	expStatuses := []string{PrepaidBondUnredeemed, PrepaidBondRedeemed, PrepaidBondRevoked,
+		PrepaidBondExpired, PrepaidBondRedeemed}
+	if len(bonds) != len(expStatuses) {
+		t.Fatalf("expected %d bonds, got %d", len(expStatuses), len(bonds))
+	}
+	for i, b := range bonds {
+		if b.Status != expStatuses[i] {
+			t.Fatalf("bond %d: wanted status %q, got %q", i, expStatuses[i], b.Status)
+		}
+		if b.Expiry != rig.storage.prepaidBonds[i].LockTime-int64(bondExpiry.Seconds()) {
+			t.Fatalf("bond %d: wrong expiry %d", i, b.Expiry)
+		}
+	}
+}
+
 func Test_checkSigS256(t *testing.T) {
 	sig := []byte{0x30, 0, 0x02, 0x01, 9, 0x2, 0x01, 10}
 	ecdsa.ParseDERSignature(sig) // panic on line 132: sigStr[2] != 0x02 after trimming to sigStr[:(1+2)]
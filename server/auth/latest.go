@@ -87,6 +87,18 @@ func (la *latestMatchOutcomes) binViolations() map[Violation]int64 {
 	return bins
 }
 
+// list returns the outcomes, newest first.
+func (la *latestMatchOutcomes) list() []*matchOutcome {
+	la.mtx.Lock()
+	defer la.mtx.Unlock()
+
+	outcomes := make([]*matchOutcome, 0, len(la.outcomes))
+	for i := len(la.outcomes) - 1; i >= 0; i-- {
+		outcomes = append(outcomes, la.outcomes[i])
+	}
+	return outcomes
+}
+
 type preimageOutcome struct {
 	time int64
 	oid  order.OrderID
@@ -147,6 +159,18 @@ func (la *latestPreimageOutcomes) add(po *preimageOutcome) {
 	}
 }
 
+// list returns the outcomes, newest first.
+func (la *latestPreimageOutcomes) list() []*preimageOutcome {
+	la.mtx.Lock()
+	defer la.mtx.Unlock()
+
+	outcomes := make([]*preimageOutcome, 0, len(la.outcomes))
+	for i := len(la.outcomes) - 1; i >= 0; i-- {
+		outcomes = append(outcomes, la.outcomes[i])
+	}
+	return outcomes
+}
+
 func (la *latestPreimageOutcomes) misses() (misses int32) {
 	la.mtx.Lock()
 	defer la.mtx.Unlock()
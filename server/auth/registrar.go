@@ -359,6 +359,10 @@ func (auth *AuthManager) storeBondAndRespond(conn comms.Link, bond *db.Bond, acc
 	}
 }
 
+// prepaidBondMinRemaining is the minimum time until expiry that a pre-paid
+// bond must have to be redeemed.
+const prepaidBondMinRemaining = time.Hour * 24
+
 func (auth *AuthManager) processPrepaidBond(conn comms.Link, msg *msgjson.Message, acct *account.Account, coinID []byte) *msgjson.Error {
 	auth.prepaidBondMtx.Lock()
 	defer auth.prepaidBondMtx.Unlock()
@@ -369,7 +373,7 @@ func (auth *AuthManager) processPrepaidBond(conn comms.Link, msg *msgjson.Messag
 
 	lockTime := time.Unix(lockTimeI, 0)
 	expireTime := lockTime.Add(-auth.bondExpiry)
-	if time.Until(expireTime) < time.Hour*24 {
+	if time.Until(expireTime) < prepaidBondMinRemaining {
 		return msgjson.NewError(msgjson.BondError, "pre-paid bond is too old")
 	}
 
@@ -410,8 +414,8 @@ func (auth *AuthManager) processPrepaidBond(conn comms.Link, msg *msgjson.Messag
 		}
 	}
 
-	if err := auth.storage.DeletePrepaidBond(coinID); err != nil {
-		log.Errorf("Error deleting pre-paid bond id = %s from database: %v", dex.Bytes(coinID), err)
+	if err := auth.storage.RedeemPrepaidBond(coinID, acct.ID); err != nil {
+		log.Errorf("Error marking pre-paid bond id = %s as redeemed: %v", dex.Bytes(coinID), err)
 	}
 
 	rep := auth.addBond(acct.ID, dbBond)
@@ -98,6 +98,9 @@ type dexConf struct {
 	AdminSrvNoTLS    bool
 	NoResumeSwaps    bool
 	DisableDataAPI   bool
+	NoCompression    bool
+	NoCBOR           bool
+	EnableQUIC       bool
 	NodeRelayAddr    string
 	ValidateMarkets  bool
 }
@@ -151,6 +154,12 @@ type flagsData struct {
 
 	DisableDataAPI bool `long:"nodata" description:"Disable the HTTP data API."`
 
+	NoCompression bool `long:"nowscompression" description:"Do not negotiate websocket compression with clients. Compression reduces bandwidth at the cost of CPU time."`
+
+	NoCBOR bool `long:"nocbor" description:"Do not send CBOR-encoded order books, book updates, and candles to clients that accept them. All messages are JSON-encoded."`
+
+	EnableQUIC bool `long:"quic" description:"Also accept websocket connections over QUIC on the UDP ports of the listen addresses. Requires TLS."`
+
 	NodeRelayAddr string `long:"noderelayaddr" description:"The public address by which node sources should connect to the node relay"`
 
 	ValidateMarkets bool `long:"validate" description:"Validate the market configuration and quit"`
@@ -560,6 +569,9 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		AdminSrvNoTLS:    cfg.AdminSrvNoTLS,
 		NoResumeSwaps:    cfg.NoResumeSwaps,
 		DisableDataAPI:   cfg.DisableDataAPI,
+		NoCompression:    cfg.NoCompression,
+		NoCBOR:           cfg.NoCBOR,
+		EnableQUIC:       cfg.EnableQUIC,
 		NodeRelayAddr:    cfg.NodeRelayAddr,
 		ValidateMarkets:  cfg.ValidateMarkets,
 	}
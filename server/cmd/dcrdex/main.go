@@ -137,13 +137,16 @@ func mainCore(ctx context.Context) error {
 		PenaltyThreshold: cfg.PenaltyThreshold,
 		DEXPrivKey:       privKey,
 		CommsCfg: &dexsrv.RPCConfig{
-			RPCCert:           cfg.RPCCert,
-			NoTLS:             cfg.NoTLS,
-			RPCKey:            cfg.RPCKey,
-			ListenAddrs:       cfg.RPCListen,
-			AltDNSNames:       cfg.AltDNSNames,
-			DisableDataAPI:    cfg.DisableDataAPI,
-			HiddenServiceAddr: cfg.HiddenService,
+			RPCCert:            cfg.RPCCert,
+			NoTLS:              cfg.NoTLS,
+			RPCKey:             cfg.RPCKey,
+			ListenAddrs:        cfg.RPCListen,
+			AltDNSNames:        cfg.AltDNSNames,
+			DisableDataAPI:     cfg.DisableDataAPI,
+			DisableCompression: cfg.NoCompression,
+			DisableCBOR:        cfg.NoCBOR,
+			EnableQUIC:         cfg.EnableQUIC,
+			HiddenServiceAddr:  cfg.HiddenService,
 		},
 		NoResumeSwaps: cfg.NoResumeSwaps,
 		NodeRelayAddr: cfg.NodeRelayAddr,
@@ -5,6 +5,7 @@ package comms
 
 import (
 	"encoding/json"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -36,6 +37,12 @@ type Link interface {
 	// msgjson.Message to the peer. Can be used to avoid marshalling the
 	// same message multiple times.
 	SendRaw(b []byte) error
+	// CBOR is true if the peer accepts CBOR-encoded messages. See
+	// msgjson.CBORSubprotocol.
+	CBOR() bool
+	// SendRawCBOR is like SendRaw for a CBOR-encoded msgjson.Message. The
+	// peer must accept CBOR.
+	SendRawCBOR(b []byte) error
 	// SendError sends the msgjson.Error to the peer, with reference to a
 	// request message ID.
 	SendError(id uint64, rpcErr *msgjson.Error)
@@ -175,6 +182,10 @@ func (s *Server) handleMessage(c *wsLink, msg *msgjson.Message) *msgjson.Error {
 			thing = new(msgjson.CandlesRequest)
 		case msgjson.OrderBookRoute:
 			thing = new(msgjson.OrderBookSubscription)
+		case msgjson.TradesRoute:
+			thing = new(msgjson.TradesRequest)
+		case msgjson.DepthRoute:
+			thing = new(msgjson.DepthRequest)
 		}
 		if thing != nil {
 			err := msg.Unmarshal(thing)
@@ -186,11 +197,19 @@ func (s *Server) handleMessage(c *wsLink, msg *msgjson.Message) *msgjson.Error {
 		// Process request.
 		resp, err := httpHandler(thing)
 		if err != nil {
+			if errors.Is(err, ErrTooManyRequests) {
+				return msgjson.NewError(msgjson.TooManyRequestsError, "handler error: %v", err)
+			}
 			return msgjson.NewError(msgjson.HTTPRouteError, "handler error: %v", err)
 		}
 
-		// Respond.
-		msg, err := msgjson.NewResponse(msg.ID, resp, nil)
+		// Respond. The data API responses can be large, so they are
+		// CBOR-encoded if the client accepts it.
+		newResponse := msgjson.NewResponse
+		if c.CBOR() {
+			newResponse = msgjson.NewResponseCBOR
+		}
+		msg, err := newResponse(msg.ID, resp, nil)
 		if err == nil {
 			err = c.Send(msg)
 		}
@@ -87,6 +87,11 @@ var (
 
 var idCounter uint64
 
+// ErrTooManyRequests may be wrapped in the error returned by an HTTPHandler
+// that applies its own rate limits. The request is answered with a
+// http.StatusTooManyRequests or msgjson.TooManyRequestsError error.
+const ErrTooManyRequests = dex.ErrorKind("too many requests")
+
 // ipRateLimiter is used to track an IPs HTTP request rate.
 type ipRateLimiter struct {
 	*rate.Limiter
@@ -173,6 +178,19 @@ type RPCConfig struct {
 	AltDNSNames []string
 	// DisableDataAPI will disable all traffic to the HTTP data API routes.
 	DisableDataAPI bool
+	// DisableCompression disables the negotiation of permessage-deflate
+	// compression on websocket connections. With compression, large messages
+	// such as order books use less bandwidth, at the cost of CPU time.
+	DisableCompression bool
+	// DisableCBOR disables the negotiation of CBOR encoding on websocket
+	// connections. With CBOR, order books, book updates, and candles are sent
+	// to clients that accept them as smaller, faster to decode, CBOR-encoded
+	// messages.
+	DisableCBOR bool
+	// EnableQUIC also accepts websocket connections over QUIC on the UDP
+	// ports of the ListenAddrs, and advertises them to clients. QUIC requires
+	// TLS, so it is not used with NoTLS.
+	EnableQUIC bool
 }
 
 // allower is satisfied by rate.Limiter.
@@ -218,6 +236,7 @@ func newRouteLimiter() *routeLimiter {
 			// Status checking of matches and orders
 			msgjson.MatchStatusRoute: statusLimiter,
 			msgjson.OrderStatusRoute: statusLimiter,
+			msgjson.ReputationRoute:  statusLimiter,
 			// Order submission
 			msgjson.LimitRoute:  orderLimiter,
 			msgjson.MarketRoute: orderLimiter,
@@ -230,6 +249,9 @@ func newRouteLimiter() *routeLimiter {
 			msgjson.ConfigRoute:  infoLimiter,
 			msgjson.SpotsRoute:   infoLimiter,
 			msgjson.CandlesRoute: infoLimiter,
+			msgjson.TradesRoute:  infoLimiter,
+			msgjson.TickersRoute: infoLimiter,
+			msgjson.DepthRoute:   infoLimiter,
 		},
 	}
 }
@@ -268,6 +290,16 @@ type Server struct {
 
 	dataEnabled uint32 // atomic
 
+	// compression is true if websocket compression is negotiated with clients
+	// that support it.
+	compression bool
+	// cbor is true if CBOR encoding is negotiated with clients that support
+	// it. See msgjson.CBORSubprotocol.
+	cbor bool
+	// altSvc are the Alt-Svc headers that advertise QUIC to the clients of
+	// each listener, by listener address.
+	altSvc map[string]string
+
 	// rpcRoutes maps message routes to the handlers.
 	rpcRoutes map[string]MsgHandler
 	// httpRoutes maps HTTP routes to the handlers.
@@ -336,6 +368,7 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
+	altSvc := make(map[string]string)
 	parseListener := func(network, addr string) (err error) {
 		var listener net.Listener
 		if cfg.NoTLS {
@@ -347,6 +380,23 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 			return fmt.Errorf("cannot listen on %s: %w", addr, err)
 		}
 		listeners = append(listeners, listener)
+		if cfg.NoTLS || !cfg.EnableQUIC {
+			return nil
+		}
+		// Use the TCP listener's port, which may have been chosen by the
+		// system.
+		udpNetwork := strings.Replace(network, "tcp", "udp", 1)
+		quicListener, err := ws.ListenQUIC(udpNetwork, listener.Addr().String(), tlsConfig)
+		if err != nil {
+			return fmt.Errorf("cannot listen for QUIC on %s: %w", addr, err)
+		}
+		listeners = append(listeners, quicListener)
+		_, port, err := net.SplitHostPort(quicListener.Addr().String())
+		if err != nil {
+			return err
+		}
+		altSvc[listener.Addr().String()] = ws.QUICAltSvc(port)
+		altSvc[quicListener.Addr().String()] = ws.QUICAltSvc(port)
 		return nil
 	}
 
@@ -381,6 +431,9 @@ func NewServer(cfg *RPCConfig) (*Server, error) {
 		v6Prefixes:  make(map[dex.IPKey]int),
 		quarantine:  make(map[dex.IPKey]time.Time),
 		dataEnabled: dataEnabled,
+		compression: !cfg.DisableCompression,
+		cbor:        !cfg.DisableCBOR,
+		altSvc:      altSvc,
 		rpcRoutes:   make(map[string]MsgHandler),
 		httpRoutes:  make(map[string]HTTPHandler),
 	}, nil
@@ -414,7 +467,11 @@ func (s *Server) Run(ctx context.Context) {
 			return
 		}
 
-		wsConn, err := ws.NewConnection(w, r, pongWait)
+		connCfg := &ws.ConnectionConfig{Compression: s.compression, CBOR: s.cbor}
+		if l, ok := r.Context().Value(ctxListener).(net.Listener); ok && s.altSvc[l.Addr().String()] != "" {
+			connCfg.ResponseHeader = http.Header{"Alt-Svc": {s.altSvc[l.Addr().String()]}}
+		}
+		wsConn, err := ws.NewConnectionWithConfig(w, r, pongWait, connCfg)
 		if err != nil {
 			if errors.Is(err, ws.ErrHandshake) {
 				log.Debug(err)
@@ -836,7 +893,11 @@ func (s *Server) NewRouteHandler(route string) func(w http.ResponseWriter, r *ht
 	return func(w http.ResponseWriter, r *http.Request) {
 		resp, err := handler(r.Context().Value(CtxThing))
 		if err != nil {
-			writeJSONWithStatus(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+			code := http.StatusBadRequest
+			if errors.Is(err, ErrTooManyRequests) {
+				code = http.StatusTooManyRequests
+			}
+			writeJSONWithStatus(w, map[string]string{"error": err.Error()}, code)
 			return
 		}
 		writeJSONWithStatus(w, resp, http.StatusOK)
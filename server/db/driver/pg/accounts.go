@@ -13,6 +13,7 @@ import (
 	"decred.org/dcrdex/server/db"
 	"decred.org/dcrdex/server/db/driver/pg/internal"
 	"github.com/decred/dcrd/dcrutil/v4" // TODO: consider a move to "crypto/sha256" instead of dcrutil.Hash160
+	"github.com/lib/pq"
 )
 
 // Account retrieves the account pubkey, active bonds, and if the account has a
@@ -100,20 +101,86 @@ func (a *Archiver) FetchPrepaidBond(coinID []byte) (strength uint32, lockTime in
 	return
 }
 
-func (a *Archiver) DeletePrepaidBond(coinID []byte) (err error) {
-	stmt := fmt.Sprintf(internal.DeletePrepaidBond, prepaidBondsTableName)
-	_, err = a.db.ExecContext(a.ctx, stmt, coinID)
-	return
+func (a *Archiver) RedeemPrepaidBond(coinID []byte, aid account.AccountID) error {
+	stmt := fmt.Sprintf(internal.RedeemPrepaidBond, prepaidBondsTableName)
+	res, err := a.db.ExecContext(a.ctx, stmt, coinID, aid, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+	N, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if N != 1 {
+		return fmt.Errorf("updated %d rows, expected 1", N)
+	}
+	return nil
 }
 
-func (a *Archiver) StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error {
+func (a *Archiver) StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64, batch string) error {
 	stmt := fmt.Sprintf(internal.InsertPrepaidBond, prepaidBondsTableName)
+	tx, err := a.db.BeginTx(a.ctx, nil)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
 	for i := range coinIDs {
-		if _, err := a.db.ExecContext(a.ctx, stmt, coinIDs[i], strength, lockTime); err != nil {
+		if _, err := tx.Exec(stmt, coinIDs[i], strength, lockTime, batch, now); err != nil {
+			_ = tx.Rollback()
 			return err
 		}
 	}
-	return nil
+	return tx.Commit()
+}
+
+func (a *Archiver) PrepaidBonds(batch string) ([]*db.PrepaidBond, error) {
+	var rows *sql.Rows
+	var err error
+	if batch == "" {
+		stmt := fmt.Sprintf(internal.SelectPrepaidBonds, prepaidBondsTableName)
+		rows, err = a.db.QueryContext(a.ctx, stmt)
+	} else {
+		stmt := fmt.Sprintf(internal.SelectPrepaidBondsBatch, prepaidBondsTableName)
+		rows, err = a.db.QueryContext(a.ctx, stmt, batch)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bonds []*db.PrepaidBond
+	for rows.Next() {
+		var b db.PrepaidBond
+		err = rows.Scan(&b.CoinID, &b.Strength, &b.LockTime, &b.Batch, &b.Created,
+			&b.RedeemedBy, &b.Redeemed, &b.Revoked)
+		if err != nil {
+			return nil, err
+		}
+		bonds = append(bonds, &b)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return bonds, nil
+}
+
+func (a *Archiver) RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error) {
+	var res sql.Result
+	var err error
+	if len(coinIDs) > 0 {
+		stmt := fmt.Sprintf(internal.RevokePrepaidBonds, prepaidBondsTableName)
+		res, err = a.db.ExecContext(a.ctx, stmt, pq.ByteaArray(coinIDs))
+	} else {
+		if batch == "" {
+			return 0, errors.New("no batch or coin IDs specified")
+		}
+		stmt := fmt.Sprintf(internal.RevokePrepaidBondsBatch, prepaidBondsTableName)
+		res, err = a.db.ExecContext(a.ctx, stmt, batch)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 // KeyIndex returns the current child index for the an xpub. If it is not
@@ -57,4 +57,20 @@ func TestCandles(t *testing.T) {
 	if cache.Last().MatchVolume != 1 {
 		t.Fatalf("Overwrite failed")
 	}
+
+	cs, err := archie.Candles(baseID, quoteID, candleDur, candleDur*2, 5)
+	if err != nil {
+		t.Fatalf("Candles error: %v", err)
+	}
+	if len(cs) != 2 || cs[0].EndStamp != candleDur || cs[1].EndStamp != candleDur*2 {
+		t.Fatalf("Wrong candles returned: %+v", cs)
+	}
+
+	cs, err = archie.Candles(baseID, quoteID, candleDur, candleDur*2-1, 5)
+	if err != nil {
+		t.Fatalf("Candles (before) error: %v", err)
+	}
+	if len(cs) != 1 || cs[0].EndStamp != candleDur {
+		t.Fatalf("Wrong candles returned for earlier before stamp: %+v", cs)
+	}
 }
@@ -201,6 +201,55 @@ func (a *Archiver) LastCandleEndStamp(base, quote uint32, candleDur uint64) (uin
 	return uint64(endStamp), nil
 }
 
+// Candles retrieves up to n stored candles of the specified duration that end
+// at or before the before stamp, sorted by ascending time.
+func (a *Archiver) Candles(base, quote uint32, candleDur, before uint64, n int) ([]*candles.Candle, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+
+	tableName := fullCandlesTableName(a.dbName, marketSchema, candleDur)
+	stmt := fmt.Sprintf(internal.SelectCandlesBefore, tableName)
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	rows, err := a.db.QueryContext(ctx, stmt, before, n)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+	defer rows.Close()
+
+	cs := make([]*candles.Candle, 0, n)
+	var endStamp, matchVol, quoteVol, highRate, lowRate, startRate, endRate fastUint64
+	for rows.Next() {
+		err = rows.Scan(&endStamp, &matchVol, &quoteVol, &highRate, &lowRate, &startRate, &endRate)
+		if err != nil {
+			return nil, fmt.Errorf("Scan: %w", err)
+		}
+		cs = append(cs, &candles.Candle{
+			StartStamp:  uint64(endStamp) - candleDur,
+			EndStamp:    uint64(endStamp),
+			MatchVolume: uint64(matchVol),
+			QuoteVolume: uint64(quoteVol),
+			HighRate:    uint64(highRate),
+			LowRate:     uint64(lowRate),
+			StartRate:   uint64(startRate),
+			EndRate:     uint64(endRate),
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Newest first from the query. Reverse for ascending time.
+	for i, j := 0, len(cs)-1; i < j; i, j = i+1, j-1 {
+		cs[i], cs[j] = cs[j], cs[i]
+	}
+	return cs, nil
+}
+
 // InsertCandles inserts new candles for a market and candle duration.
 func (a *Archiver) InsertCandles(base, quote uint32, candleDur uint64, cs []*candles.Candle) error {
 	marketSchema, err := a.marketSchema(base, quote)
@@ -81,12 +81,52 @@ const (
 		coin_id BYTEA PRIMARY KEY,
 		version INT2 DEFAULT 0,
 		strength int4,
-		lock_time INT8
+		lock_time INT8,
+		batch_id TEXT DEFAULT '',
+		created INT8 DEFAULT 0,
+		redeemed_by BYTEA,       -- account ID, NULL until redeemed
+		redeemed_time INT8 DEFAULT 0,
+		revoked BOOL DEFAULT FALSE
 	);`
 
-	SelectPrepaidBond = `SELECT strength, lock_time FROM %s WHERE coin_id = $1;`
-
-	DeletePrepaidBond = `DELETE FROM %s WHERE coin_id = $1;`
-
-	InsertPrepaidBond = `INSERT INTO %s (coin_id, strength, lock_time) VALUES ($1, $2, $3);`
+	// AddPrepaidBondsTrackingColumns adds the columns used to track batches,
+	// redemption, and revocation of pre-paid bonds to a v0 prepaid_bonds
+	// table.
+	AddPrepaidBondsTrackingColumns = `ALTER TABLE %s
+		ADD COLUMN IF NOT EXISTS batch_id TEXT DEFAULT '',
+		ADD COLUMN IF NOT EXISTS created INT8 DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS redeemed_by BYTEA,
+		ADD COLUMN IF NOT EXISTS redeemed_time INT8 DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS revoked BOOL DEFAULT FALSE;`
+
+	// SelectPrepaidBond retrieves a pre-paid bond that may still be redeemed.
+	SelectPrepaidBond = `SELECT strength, lock_time FROM %s
+		WHERE coin_id = $1 AND redeemed_by IS NULL AND NOT revoked;`
+
+	// RedeemPrepaidBond marks a pre-paid bond as redeemed by an account.
+	RedeemPrepaidBond = `UPDATE %s SET redeemed_by = $2, redeemed_time = $3
+		WHERE coin_id = $1 AND redeemed_by IS NULL AND NOT revoked;`
+
+	InsertPrepaidBond = `INSERT INTO %s (coin_id, strength, lock_time, batch_id, created)
+		VALUES ($1, $2, $3, $4, $5);`
+
+	// SelectPrepaidBonds retrieves all pre-paid bonds, oldest first.
+	SelectPrepaidBonds = `SELECT coin_id, strength, lock_time, batch_id, created,
+			redeemed_by, redeemed_time, revoked
+		FROM %s ORDER BY created, coin_id;`
+
+	// SelectPrepaidBondsBatch retrieves the pre-paid bonds in a batch.
+	SelectPrepaidBondsBatch = `SELECT coin_id, strength, lock_time, batch_id, created,
+			redeemed_by, redeemed_time, revoked
+		FROM %s WHERE batch_id = $1 ORDER BY created, coin_id;`
+
+	// RevokePrepaidBonds revokes the unredeemed pre-paid bonds in the provided
+	// array of coin IDs.
+	RevokePrepaidBonds = `UPDATE %s SET revoked = TRUE
+		WHERE coin_id = ANY($1) AND redeemed_by IS NULL AND NOT revoked;`
+
+	// RevokePrepaidBondsBatch revokes all unredeemed pre-paid bonds in a
+	// batch.
+	RevokePrepaidBondsBatch = `UPDATE %s SET revoked = TRUE
+		WHERE batch_id = $1 AND redeemed_by IS NULL AND NOT revoked;`
 )
@@ -87,6 +87,15 @@ const (
 	ORDER BY end_stamp
 	LIMIT $1;`
 
+	// SelectCandlesBefore selects the most recent candles ending at or before
+	// the specified time, newest first.
+	SelectCandlesBefore = `SELECT end_stamp, match_volume, quote_volume,
+		high_rate, low_rate, start_rate, end_rate
+	FROM %s
+	WHERE end_stamp <= $1
+	ORDER BY end_stamp DESC
+	LIMIT $2;`
+
 	SelectLastEndStamp = `SELECT (end_stamp)
 		FROM %s
 		ORDER BY end_stamp
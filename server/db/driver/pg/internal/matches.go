@@ -62,6 +62,24 @@ const (
 	RetrieveMatchStatsByEpoch = `SELECT quantity, rate, takerSell FROM %s
 		WHERE takerSell IS NOT NULL AND epochIdx = $1 AND epochDur = $2;`
 
+	// RetrieveMarketTrades selects the most recent trade matches, newest
+	// first. The match time is the end of the match's epoch.
+	RetrieveMarketTrades = `SELECT matchid, (epochIdx+1)*epochDur AS stamp, quantity, rate, takerSell
+		FROM %s
+		WHERE takerSell IS NOT NULL
+		ORDER BY stamp DESC, matchid DESC
+		LIMIT $1;`
+
+	// RetrieveMarketTradesBefore is like RetrieveMarketTrades, but only selects
+	// matches that come before the match with the specified ID.
+	RetrieveMarketTradesBefore = `SELECT matchid, (epochIdx+1)*epochDur AS stamp, quantity, rate, takerSell
+		FROM %[1]s
+		WHERE takerSell IS NOT NULL
+			AND ((epochIdx+1)*epochDur, matchid) <
+				(SELECT (epochIdx+1)*epochDur, matchid FROM %[1]s WHERE matchid = $2)
+		ORDER BY stamp DESC, matchid DESC
+		LIMIT $1;`
+
 	RetrieveSwapData = `SELECT status, sigMatchAckMaker, sigMatchAckTaker,
 		aContractCoinID, aContract, aContractTime, bSigAckOfAContract,
 		bContractCoinID, bContract, bContractTime, aSigAckOfBContract,
@@ -99,3 +99,23 @@ func createMarketTables(db *sql.DB, marketName string) error {
 func marketSchema(marketName string) string {
 	return strings.ReplaceAll(marketName, ".", "TKN")
 }
+
+// PrepareMarket ensures that the tables required by the market are ready, and
+// sets the market's configuration. This is used for markets that are added or
+// modified while the DEX is running. If the market's lot size is changed, its
+// book is flushed.
+func (a *Archiver) PrepareMarket(mkt *dex.MarketInfo) error {
+	purgeMarkets, err := prepareMarkets(a.db, []*dex.MarketInfo{mkt})
+	if err != nil {
+		return err
+	}
+	a.marketsMtx.Lock()
+	a.markets[marketSchema(mkt.Name)] = mkt
+	a.marketsMtx.Unlock()
+	for _, staleMarket := range purgeMarkets {
+		if err := a.flushStaleBook(staleMarket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -31,7 +31,7 @@ func (a *Archiver) matchTableName(match *order.Match) (string, error) {
 // can actually be forgiven (inactive, not already forgiven, and not in
 // MatchComplete status).
 func (a *Archiver) ForgiveMatchFail(mid order.MatchID) (bool, error) {
-	for schema := range a.markets {
+	for schema := range a.marketSchemas() {
 		stmt := fmt.Sprintf(internal.ForgiveMatchFail, fullMatchesTableName(a.dbName, schema))
 		N, err := sqlExec(a.db, stmt, mid)
 		if err != nil { // not just no rows updated
@@ -49,7 +49,7 @@ func (a *Archiver) ForgiveMatchFail(mid order.MatchID) (bool, error) {
 func (a *Archiver) ActiveSwaps() ([]*db.SwapDataFull, error) {
 	var sd []*db.SwapDataFull
 
-	for schema, mkt := range a.markets {
+	for schema, mkt := range a.marketSchemas() {
 		matchesTableName := fullMatchesTableName(a.dbName, schema)
 		ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
 		matches, swapData, err := activeSwaps(ctx, a.db, matchesTableName)
@@ -140,7 +140,7 @@ func activeSwaps(ctx context.Context, dbe *sql.DB, tableName string) (matches []
 func (a *Archiver) CompletedAndAtFaultMatchStats(aid account.AccountID, lastN int) ([]*db.MatchOutcome, error) {
 	var outcomes []*db.MatchOutcome
 
-	for schema, mkt := range a.markets {
+	for schema, mkt := range a.marketSchemas() {
 		matchesTableName := fullMatchesTableName(a.dbName, schema)
 		ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
 		matchOutcomes, err := completedAndAtFaultMatches(ctx, a.db, matchesTableName, aid, lastN, mkt.Base, mkt.Quote)
@@ -166,7 +166,7 @@ func (a *Archiver) CompletedAndAtFaultMatchStats(aid account.AccountID, lastN in
 func (a *Archiver) UserMatchFails(aid account.AccountID, lastN int) ([]*db.MatchFail, error) {
 	var fails []*db.MatchFail
 
-	for schema := range a.markets {
+	for schema := range a.marketSchemas() {
 		matchesTableName := fullMatchesTableName(a.dbName, schema)
 		ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
 		marketFails, err := atFaultMatches(ctx, a.db, matchesTableName, aid, lastN)
@@ -462,7 +462,7 @@ func (a *Archiver) AllActiveUserMatches(aid account.AccountID) ([]*db.MatchData,
 	defer cancel()
 
 	var matches []*db.MatchData
-	for schema := range a.markets {
+	for schema := range a.marketSchemas() {
 		matchesTableName := fullMatchesTableName(a.dbName, schema)
 		mdM, err := userMatches(ctx, a.db, matchesTableName, aid, false)
 		if err != nil {
@@ -493,6 +493,46 @@ func (a *Archiver) MatchStatuses(aid account.AccountID, base, quote uint32, matc
 
 }
 
+// MarketTrades retrieves up to n of the most recent trade matches for a market,
+// newest first. If before is non-nil, only matches older than the specified
+// match are returned. Cancel order matches are not included.
+func (a *Archiver) MarketTrades(base, quote uint32, before *order.MatchID, n int) ([]*db.Trade, error) {
+	marketSchema, err := a.marketSchema(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	matchesTableName := fullMatchesTableName(a.dbName, marketSchema)
+
+	ctx, cancel := context.WithTimeout(a.ctx, a.queryTimeout)
+	defer cancel()
+
+	var rows *sql.Rows
+	if before == nil {
+		stmt := fmt.Sprintf(internal.RetrieveMarketTrades, matchesTableName)
+		rows, err = a.db.QueryContext(ctx, stmt, n)
+	} else {
+		stmt := fmt.Sprintf(internal.RetrieveMarketTradesBefore, matchesTableName)
+		rows, err = a.db.QueryContext(ctx, stmt, n, *before)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	trades := make([]*db.Trade, 0, n)
+	for rows.Next() {
+		var t db.Trade
+		var stamp, qty, rate fastUint64
+		if err = rows.Scan(&t.MatchID, &stamp, &qty, &rate, &t.TakerSell); err != nil {
+			return nil, err
+		}
+		t.Stamp, t.Quantity, t.Rate = uint64(stamp), uint64(qty), uint64(rate)
+		trades = append(trades, &t)
+	}
+
+	return trades, rows.Err()
+}
+
 func upsertMatch(dbe sqlExecutor, tableName string, match *order.Match) (int64, error) {
 	var takerAddr string
 	tt := match.Taker.Trade()
@@ -67,9 +67,11 @@ type Archiver struct {
 	queryTimeout time.Duration
 	db           *sql.DB
 	dbName       string
-	markets      map[string]*dex.MarketInfo
 	tables       archiverTables
 
+	marketsMtx sync.RWMutex
+	markets    map[string]*dex.MarketInfo
+
 	fatalMtx sync.RWMutex
 	fatal    chan struct{}
 	fatalErr error
@@ -180,21 +182,51 @@ func NewArchiver(ctx context.Context, cfg *Config) (*Archiver, error) {
 		return nil, err
 	}
 	for _, staleMarket := range purgeMarkets {
-		mkt := archiver.markets[staleMarket]
-		if mkt == nil { // shouldn't happen
-			return nil, fmt.Errorf("unrecognized market %v", staleMarket)
-		}
-		unbookedSells, unbookedBuys, err := archiver.FlushBook(mkt.Base, mkt.Quote)
-		if err != nil {
-			return nil, fmt.Errorf("failed to flush book for market %v: %w", staleMarket, err)
+		if err := archiver.flushStaleBook(staleMarket); err != nil {
+			return nil, err
 		}
-		log.Infof("Flushed %d sell orders and %d buy orders from market %v with a changed lot size.",
-			len(unbookedSells), len(unbookedBuys), staleMarket)
 	}
 
 	return archiver, nil
 }
 
+// flushStaleBook flushes the book of the market with the schema name, which
+// has a changed lot size.
+func (a *Archiver) flushStaleBook(schema string) error {
+	mkt := a.marketInfo(schema)
+	if mkt == nil { // shouldn't happen
+		return fmt.Errorf("unrecognized market %v", schema)
+	}
+	unbookedSells, unbookedBuys, err := a.FlushBook(mkt.Base, mkt.Quote)
+	if err != nil {
+		return fmt.Errorf("failed to flush book for market %v: %w", schema, err)
+	}
+	log.Infof("Flushed %d sell orders and %d buy orders from market %v with a changed lot size.",
+		len(unbookedSells), len(unbookedBuys), schema)
+	return nil
+}
+
+// marketInfo returns the configuration of the market with the schema name, or
+// nil if the market is unknown.
+func (a *Archiver) marketInfo(schema string) *dex.MarketInfo {
+	a.marketsMtx.RLock()
+	defer a.marketsMtx.RUnlock()
+	return a.markets[schema]
+}
+
+// marketSchemas returns a copy of the markets map, keyed by schema name. Markets
+// may be added at runtime with PrepareMarket, so use this to iterate the
+// markets.
+func (a *Archiver) marketSchemas() map[string]*dex.MarketInfo {
+	a.marketsMtx.RLock()
+	defer a.marketsMtx.RUnlock()
+	mkts := make(map[string]*dex.MarketInfo, len(a.markets))
+	for schema, mkt := range a.markets {
+		mkts[schema] = mkt
+	}
+	return mkts
+}
+
 // Close closes the underlying DB connection.
 func (a *Archiver) Close() error {
 	return a.db.Close()
@@ -206,8 +238,7 @@ func (a *Archiver) marketSchema(base, quote uint32) (string, error) {
 		return "", err
 	}
 	schema := marketSchema(marketName)
-	_, found := a.markets[schema]
-	if !found {
+	if a.marketInfo(schema) == nil {
 		return "", db.ArchiveError{
 			Code:   db.ErrUnsupportedMarket,
 			Detail: fmt.Sprintf(`archiver does not support the market "%s"`, schema),
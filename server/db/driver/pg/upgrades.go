@@ -17,7 +17,7 @@ import (
 	"decred.org/dcrdex/server/db/driver/pg/internal"
 )
 
-const dbVersion = 6
+const dbVersion = 7
 
 // The number of upgrades defined MUST be equal to dbVersion.
 var upgrades = []func(db *sql.Tx) error{
@@ -48,6 +48,10 @@ var upgrades = []func(db *sql.Tx) error{
 	// old_fee_coin column to the accounts table for when a manual refund is
 	// processed.
 	v6Upgrade,
+
+	// v7 upgrade adds batch, creation time, redemption, and revocation
+	// columns to the prepaid_bonds table.
+	v7Upgrade,
 }
 
 // v1Upgrade adds the schema_version column and removes the state_hash column
@@ -357,6 +361,18 @@ func v6Upgrade(tx *sql.Tx) error {
 	return nil
 }
 
+// v7Upgrade adds the columns used to track pre-paid bond batches and their
+// redemption status. Pre-paid bonds redeemed prior to this upgrade were deleted
+// from the table, so any remaining are unredeemed.
+func v7Upgrade(tx *sql.Tx) error {
+	namespacedPrepaidBondsTable := publicSchema + "." + prepaidBondsTableName
+	_, err := tx.Exec(fmt.Sprintf(internal.AddPrepaidBondsTrackingColumns, namespacedPrepaidBondsTable))
+	if err != nil {
+		return fmt.Errorf("failed to add prepaid_bonds tracking columns: %w", err)
+	}
+	return nil
+}
+
 // DBVersion retrieves the database version from the meta table.
 func DBVersion(db *sql.DB) (ver uint32, err error) {
 	err = db.QueryRow(internal.SelectDBVersion).Scan(&ver)
@@ -86,8 +86,16 @@ type DEXArchivist interface {
 	// LoadEpochStats reads all market epoch history from the database.
 	LoadEpochStats(uint32, uint32, []*candles.Cache) error
 	LastCandleEndStamp(base, quote uint32, candleDur uint64) (uint64, error)
+	// Candles retrieves up to n stored candles of the specified duration that
+	// end at or before the before stamp, sorted by ascending time.
+	Candles(base, quote uint32, candleDur, before uint64, n int) ([]*candles.Candle, error)
 	InsertCandles(base, quote uint32, dur uint64, cs []*candles.Candle) error
 
+	// PrepareMarket ensures the storage for a market that is added or modified
+	// while the DEX is running is ready. If the market's lot size is changed,
+	// its book is flushed.
+	PrepareMarket(mkt *dex.MarketInfo) error
+
 	OrderArchiver
 	AccountArchiver
 	KeyIndexer
@@ -236,6 +244,22 @@ type Bond struct {
 	// Data []byte
 }
 
+// PrepaidBond is an operator-issued pre-paid bond code. Codes are minted in
+// batches and are never deleted, so that their redemption status can be
+// tracked.
+type PrepaidBond struct {
+	CoinID   []byte
+	Strength uint32
+	LockTime int64
+	Batch    string
+	Created  int64 // unix seconds
+	// RedeemedBy is the account that redeemed the bond, or nil if the bond
+	// has not been redeemed.
+	RedeemedBy []byte
+	Redeemed   int64 // unix seconds
+	Revoked    bool
+}
+
 // AccountArchiver is the interface required for storage and retrieval of all
 // account data.
 type AccountArchiver interface {
@@ -260,9 +284,20 @@ type AccountArchiver interface {
 	// DeleteBond deletes a bond which should generally be expired.
 	DeleteBond(assetID uint32, coinID []byte) error
 
+	// FetchPrepaidBond retrieves a pre-paid bond that has been neither
+	// redeemed nor revoked.
 	FetchPrepaidBond(bondCoinID []byte) (strength uint32, lockTime int64, err error)
-	DeletePrepaidBond(coinID []byte) error
-	StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64) error
+	// RedeemPrepaidBond marks a pre-paid bond as redeemed by the account.
+	RedeemPrepaidBond(coinID []byte, acct account.AccountID) error
+	// StorePrepaidBonds stores a new batch of pre-paid bonds.
+	StorePrepaidBonds(coinIDs [][]byte, strength uint32, lockTime int64, batch string) error
+	// PrepaidBonds retrieves the pre-paid bonds in a batch, or all pre-paid
+	// bonds if batch is empty.
+	PrepaidBonds(batch string) ([]*PrepaidBond, error)
+	// RevokePrepaidBonds revokes unredeemed pre-paid bonds, either those with
+	// the specified coin IDs or, if coinIDs is empty, all in the batch. The
+	// number of bonds revoked is returned.
+	RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error)
 
 	// AccountInfo returns data for an account.
 	AccountInfo(account.AccountID) (*Account, error)
@@ -383,6 +418,15 @@ type MatchFail struct {
 	Status order.MatchStatus
 }
 
+// Trade is a public record of a trade match.
+type Trade struct {
+	MatchID   order.MatchID
+	Stamp     uint64 // ms, the end of the match's epoch
+	Quantity  uint64
+	Rate      uint64
+	TakerSell bool
+}
+
 // MatchArchiver is the interface required for storage and retrieval of all
 // match data.
 type MatchArchiver interface {
@@ -396,6 +440,10 @@ type MatchArchiver interface {
 	MarketMatches(base, quote uint32) ([]*MatchDataWithCoins, error)
 	MarketMatchesStreaming(base, quote uint32, includeInactive bool, N int64, f func(*MatchDataWithCoins) error) (int, error)
 	MatchStatuses(aid account.AccountID, base, quote uint32, matchIDs []order.MatchID) ([]*MatchStatus, error)
+	// MarketTrades retrieves up to n of the most recent trade matches for a
+	// market, newest first. If before is non-nil, only matches older than
+	// the specified match are returned.
+	MarketTrades(base, quote uint32, before *order.MatchID, n int) ([]*Trade, error)
 }
 
 // SwapArchiver is the interface required for storage and retrieval of swap
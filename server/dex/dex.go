@@ -5,6 +5,7 @@ package dex
 
 import (
 	"context"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -51,6 +52,9 @@ const (
 	APIVersion = V1APIVersion
 )
 
+// Capabilities are the optional protocol features supported by the server.
+const Capabilities = msgjson.CapBookChecksum | msgjson.CapReputation
+
 // Asset represents an asset in the Config file.
 type Asset struct {
 	Symbol      string `json:"bip44symbol"`
@@ -454,15 +458,25 @@ func (ss *subsystem) stop() {
 // components of the DEX.
 type DEX struct {
 	network     dex.Network
-	markets     map[string]*market.Market
+	markets     *marketSet
 	assets      map[uint32]*swap.SwapperAsset
 	storage     db.DEXArchivist
 	authMgr     *auth.AuthManager
 	swapper     *swap.Swapper
 	orderRouter *market.OrderRouter
 	bookRouter  *market.BookRouter
-	subsystems  []subsystem
+	dataAPI     *apidata.DataAPI
 	server      *comms.Server
+	// newMarket creates a Market with the DEX's assets and subsystems.
+	newMarket func(mktInf *dex.MarketInfo) (*market.Market, error)
+
+	// marketsMtx serializes changes to the markets, and guards the
+	// subsystems, which change when markets are added, modified, or delisted.
+	marketsMtx     sync.Mutex
+	subsystems     []subsystem
+	pendingChanges map[string]string // market name => change description
+	stopping       bool
+	wg             sync.WaitGroup
 
 	configRespMtx sync.RWMutex
 	configResp    *configResponse
@@ -548,11 +562,16 @@ func (cr *configResponse) remarshal() {
 // completed their shutdown.
 func (dm *DEX) Stop() {
 	log.Infof("Stopping all DEX subsystems.")
-	for _, ss := range dm.subsystems {
+	dm.marketsMtx.Lock()
+	dm.stopping = true // no more market changes
+	subsystems := dm.subsystems
+	dm.marketsMtx.Unlock()
+	for _, ss := range subsystems {
 		log.Infof("Stopping %s...", ss.name)
 		ss.stop()
 		log.Infof("%s is now shut down.", ss.name)
 	}
+	dm.wg.Wait()
 	log.Infof("Stopping storage...")
 	if err := dm.storage.Close(); err != nil {
 		log.Errorf("DEXArchivist.Close: %v", err)
@@ -886,9 +905,9 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	}
 
 	// Create the user order unbook dispatcher for the AuthManager.
-	markets := make(map[string]*market.Market, len(cfg.Markets))
+	markets := newMarketSet()
 	userUnbookFun := func(user account.AccountID) {
-		for _, mkt := range markets {
+		for _, mkt := range markets.all() {
 			mkt.UnbookUserOrders(user)
 		}
 	}
@@ -940,6 +959,8 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		PenaltyThreshold: cfg.PenaltyThreshold,
 		TxDataSources:    txDataSources,
 		Route:            server.Route,
+		APIVersion:       APIVersion,
+		Capabilities:     Capabilities,
 	}
 
 	authMgr := auth.NewAuthManager(&authCfg)
@@ -958,7 +979,12 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			log.Errorf("bad market for order %v: %v", ord.ID(), err)
 			return
 		}
-		markets[name].SwapDone(ord, match, fail)
+		mkt := markets.get(name)
+		if mkt == nil {
+			log.Warnf("swap done for order %v of delisted market %s", ord.ID(), name)
+			return
+		}
+		mkt.SwapDone(ord, match, fail)
 	}
 
 	// Create the swapper.
@@ -1011,8 +1037,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 
 	// Markets
 	var orderRouter *market.OrderRouter
-	usersWithOrders := make(map[account.AccountID]struct{})
-	for _, mktInf := range cfg.Markets {
+	newMarket := func(mktInf *dex.MarketInfo) (*market.Market, error) {
 		// nilness of the coin locker signals account-based asset.
 		var baseCoinLocker, quoteCoinLocker coinlock.CoinLocker
 		b, q := backedAssets[mktInf.Base], backedAssets[mktInf.Quote]
@@ -1029,7 +1054,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		quoteMinLotSize, _, _ := asset.Minimums(mktInf.Quote, q.Asset.MaxFeeRate)
 		minRate := calc.MinimumMarketRate(mktInf.LotSize, quoteMinLotSize)
 
-		mkt, err := market.NewMarket(&market.Config{
+		return market.NewMarket(&market.Config{
 			MarketInfo:      mktInf,
 			Storage:         storage,
 			Swapper:         swapper,
@@ -1045,10 +1070,14 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 			},
 			MinimumRate: minRate,
 		})
+	}
+	usersWithOrders := make(map[account.AccountID]struct{})
+	for _, mktInf := range cfg.Markets {
+		mkt, err := newMarket(mktInf)
 		if err != nil {
 			return nil, fmt.Errorf("NewMarket failed: %w", err)
 		}
-		markets[mktInf.Name] = mkt
+		markets.set(mktInf.Name, mkt)
 		marketTunnels[mktInf.Name] = mkt
 		pendingAccounters[mktInf.Name] = mkt
 		log.Infof("Preparing historical market data API for market %v...", mktInf.Name)
@@ -1082,23 +1111,11 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	now := time.Now().UnixMilli()
 	bookSources := make(map[string]market.BookSource, len(cfg.Markets))
 	cfgMarkets := make([]*msgjson.Market, 0, len(cfg.Markets))
-	for name, mkt := range markets {
+	for name, mkt := range markets.all() {
 		startEpochIdx := 1 + now/int64(mkt.EpochDuration())
 		mkt.SetStartEpochIdx(startEpochIdx)
 		bookSources[name] = mkt
-		cfgMarkets = append(cfgMarkets, &msgjson.Market{
-			Name:            name,
-			Base:            mkt.Base(),
-			Quote:           mkt.Quote(),
-			LotSize:         mkt.LotSize(),
-			RateStep:        mkt.RateStep(),
-			EpochLen:        mkt.EpochDuration(),
-			MarketBuyBuffer: mkt.MarketBuyBuffer(),
-			ParcelSize:      mkt.ParcelSize(),
-			MarketStatus: msgjson.MarketStatus{
-				StartEpoch: uint64(startEpochIdx),
-			},
-		})
+		cfgMarkets = append(cfgMarkets, marketConfig(name, mkt, startEpochIdx))
 	}
 
 	// Book router
@@ -1109,7 +1126,7 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 	dataAPI.SetBookSource(bookRouter)
 
 	// Market, now that book router is running.
-	for name, mkt := range markets {
+	for name, mkt := range markets.all() {
 		startSubSys(marketSubSysName(name), mkt)
 	}
 
@@ -1142,9 +1159,13 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		storage:     storage,
 		orderRouter: orderRouter,
 		bookRouter:  bookRouter,
+		dataAPI:     dataAPI,
+		newMarket:   newMarket,
 		subsystems:  subsystems,
 		server:      server,
 		configResp:  cfgResp,
+
+		pendingChanges: make(map[string]string),
 	}
 
 	server.RegisterHTTP(msgjson.ConfigRoute, dexMgr.handleDEXConfig)
@@ -1164,6 +1185,9 @@ func NewDEX(ctx context.Context, cfg *DexConf) (*DEX, error) {
 		rr.With(candleParamsParser).Get("/candles/{baseSymbol}/{quoteSymbol}/{binSize}", server.NewRouteHandler(msgjson.CandlesRoute))
 		rr.With(candleParamsParser).Get("/candles/{baseSymbol}/{quoteSymbol}/{binSize}/{count}", server.NewRouteHandler(msgjson.CandlesRoute))
 		rr.With(orderBookParamsParser).Get("/orderbook/{baseSymbol}/{quoteSymbol}", server.NewRouteHandler(msgjson.OrderBookRoute))
+		rr.Get("/tickers", server.NewRouteHandler(msgjson.TickersRoute))
+		rr.With(tradesParamsParser).Get("/trades/{baseSymbol}/{quoteSymbol}", server.NewRouteHandler(msgjson.TradesRoute))
+		rr.With(depthParamsParser).Get("/depth/{baseSymbol}/{quoteSymbol}", server.NewRouteHandler(msgjson.DepthRoute))
 	})
 
 	startSubSys("Comms Server", server)
@@ -1186,7 +1210,7 @@ func (dm *DEX) Asset(id uint32) (*asset.BackedAsset, error) {
 // the optimal fee rates for new swaps for for the specified asset. That is,
 // values above 1 increase the fee rate, while values below 1 decrease it.
 func (dm *DEX) SetFeeRateScale(assetID uint32, scale float64) {
-	for _, mkt := range dm.markets {
+	for _, mkt := range dm.markets.all() {
 		if mkt.Base() == assetID || mkt.Quote() == assetID {
 			mkt.SetFeeRateScale(assetID, scale)
 		}
@@ -1197,7 +1221,7 @@ func (dm *DEX) SetFeeRateScale(assetID uint32, scale float64) {
 // rate scale factor, which is 1.0 by default.
 func (dm *DEX) ScaleFeeRate(assetID uint32, rate uint64) uint64 {
 	// Any market will have the rate. Just find the first one.
-	for _, mkt := range dm.markets {
+	for _, mkt := range dm.markets.all() {
 		if mkt.Base() == assetID || mkt.Quote() == assetID {
 			return mkt.ScaleFeeRate(assetID, rate)
 		}
@@ -1215,7 +1239,7 @@ func (dm *DEX) ConfigMsg() json.RawMessage {
 // TODO: for just market running status, the DEX manager should use its
 // knowledge of Market subsystem state.
 func (dm *DEX) MarketRunning(mktName string) (found, running bool) {
-	mkt := dm.markets[mktName]
+	mkt := dm.markets.get(mktName)
 	if mkt == nil {
 		return
 	}
@@ -1225,7 +1249,7 @@ func (dm *DEX) MarketRunning(mktName string) (found, running bool) {
 // MarketStatus returns the market.Status for the named market. If the market is
 // unknown to the DEX, nil is returned.
 func (dm *DEX) MarketStatus(mktName string) *market.Status {
-	mkt := dm.markets[mktName]
+	mkt := dm.markets.get(mktName)
 	if mkt == nil {
 		return nil
 	}
@@ -1235,8 +1259,9 @@ func (dm *DEX) MarketStatus(mktName string) *market.Status {
 // MarketStatuses returns a map of market names to market.Status for all known
 // markets.
 func (dm *DEX) MarketStatuses() map[string]*market.Status {
-	statuses := make(map[string]*market.Status, len(dm.markets))
-	for name, mkt := range dm.markets {
+	markets := dm.markets.all()
+	statuses := make(map[string]*market.Status, len(markets))
+	for name, mkt := range markets {
 		statuses[name] = mkt.Status()
 	}
 	return statuses
@@ -1250,6 +1275,16 @@ func (dm *DEX) MarketStatuses() map[string]*market.Status {
 func (dm *DEX) SuspendMarket(name string, tSusp time.Time, persistBooks bool) (suspEpoch *market.SuspendEpoch, err error) {
 	name = strings.ToLower(name)
 
+	dm.marketsMtx.Lock()
+	defer dm.marketsMtx.Unlock()
+	if change, found := dm.pendingChanges[name]; found {
+		return nil, fmt.Errorf("market %s has a pending %s", name, change)
+	}
+	return dm.suspendMarket(name, tSusp, persistBooks)
+}
+
+// suspendMarket is SuspendMarket with the marketsMtx locked.
+func (dm *DEX) suspendMarket(name string, tSusp time.Time, persistBooks bool) (suspEpoch *market.SuspendEpoch, err error) {
 	// Locate the (running) subsystem for this market.
 	i := dm.findSubsys(marketSubSysName(name))
 	if i == -1 {
@@ -1290,6 +1325,8 @@ func (dm *DEX) SuspendMarket(name string, tSusp time.Time, persistBooks bool) (s
 	return
 }
 
+// findSubsys returns the index of the named subsystem, or -1 if it is not
+// found. The marketsMtx MUST be locked.
 func (dm *DEX) findSubsys(name string) int {
 	for i := range dm.subsystems {
 		if dm.subsystems[i].name == name {
@@ -1304,7 +1341,19 @@ func (dm *DEX) findSubsys(name string) int {
 // duration, as the market only starts at the beginning of an epoch.
 func (dm *DEX) ResumeMarket(name string, asSoonAs time.Time) (startEpoch int64, startTime time.Time, err error) {
 	name = strings.ToLower(name)
-	mkt := dm.markets[name]
+
+	dm.marketsMtx.Lock()
+	defer dm.marketsMtx.Unlock()
+	if change, found := dm.pendingChanges[name]; found {
+		err = fmt.Errorf("market %s has a pending %s", name, change)
+		return
+	}
+	return dm.resumeMarket(name, asSoonAs)
+}
+
+// resumeMarket is ResumeMarket with the marketsMtx locked.
+func (dm *DEX) resumeMarket(name string, asSoonAs time.Time) (startEpoch int64, startTime time.Time, err error) {
+	mkt := dm.markets.get(name)
 	if mkt == nil {
 		err = fmt.Errorf("unknown market %s", name)
 		return
@@ -1376,10 +1425,22 @@ func (dm *DEX) ForgiveMatchFail(aid account.AccountID, mid order.MatchID) (forgi
 	return dm.authMgr.ForgiveMatchFail(aid, mid)
 }
 
-func (dm *DEX) CreatePrepaidBonds(n int, strength uint32, durSecs int64) ([][]byte, error) {
+func (dm *DEX) CreatePrepaidBonds(n int, strength uint32, durSecs int64) (batch string, coinIDs [][]byte, err error) {
 	return dm.authMgr.CreatePrepaidBonds(n, strength, durSecs)
 }
 
+// PrepaidBonds retrieves the pre-paid bonds in a batch, or all pre-paid bonds
+// if batch is empty, with their redemption status.
+func (dm *DEX) PrepaidBonds(batch string) ([]*auth.PrepaidBond, error) {
+	return dm.authMgr.PrepaidBonds(batch)
+}
+
+// RevokePrepaidBonds revokes unredeemed pre-paid bonds. If coinIDs is empty,
+// all unredeemed bonds in the batch are revoked.
+func (dm *DEX) RevokePrepaidBonds(batch string, coinIDs [][]byte) (int64, error) {
+	return dm.authMgr.RevokePrepaidBonds(batch, coinIDs)
+}
+
 func (dm *DEX) AccountMatchOutcomesN(aid account.AccountID, n int) ([]*auth.MatchOutcome, error) {
 	return dm.authMgr.AccountMatchOutcomesN(aid, n)
 }
@@ -1522,7 +1583,8 @@ func (dm *DEX) EnableDataAPI(yes bool) {
 }
 
 // candleParamsParser is middleware for the /candles routes. Parses the
-// *msgjson.CandlesRequest from the URL parameters.
+// *msgjson.CandlesRequest from the URL parameters and the optional "before"
+// query parameter, a time in milliseconds.
 func candleParamsParser(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		baseID, quoteID, errMsg := parseBaseQuoteIDs(r)
@@ -1548,11 +1610,21 @@ func candleParamsParser(next http.Handler) http.Handler {
 				return
 			}
 		}
+
+		var before uint64
+		if beforeStr := r.URL.Query().Get("before"); beforeStr != "" {
+			before, err = strconv.ParseUint(beforeStr, 10, 64)
+			if err != nil {
+				http.Error(w, "before unparseable", http.StatusBadRequest)
+				return
+			}
+		}
 		ctx := context.WithValue(r.Context(), comms.CtxThing, &msgjson.CandlesRequest{
 			BaseID:     baseID,
 			QuoteID:    quoteID,
 			BinSize:    binSize,
 			NumCandles: count,
+			Before:     before,
 		})
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -1575,6 +1647,69 @@ func orderBookParamsParser(next http.Handler) http.Handler {
 	})
 }
 
+// tradesParamsParser is middleware for the /trades route. Parses the
+// *msgjson.TradesRequest from the URL parameters and the optional "before" (a
+// hex-encoded trade ID) and "limit" query parameters.
+func tradesParamsParser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		baseID, quoteID, errMsg := parseBaseQuoteIDs(r)
+		if errMsg != "" {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		req := &msgjson.TradesRequest{
+			BaseID:  baseID,
+			QuoteID: quoteID,
+		}
+		var err error
+		query := r.URL.Query()
+		if beforeStr := query.Get("before"); beforeStr != "" {
+			req.Before, err = hex.DecodeString(beforeStr)
+			if err != nil {
+				http.Error(w, "before unparseable", http.StatusBadRequest)
+				return
+			}
+		}
+		if limitStr := query.Get("limit"); limitStr != "" {
+			req.Limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				http.Error(w, "limit unparseable", http.StatusBadRequest)
+				return
+			}
+		}
+		ctx := context.WithValue(r.Context(), comms.CtxThing, req)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// depthParamsParser is middleware for the /depth route. Parses the
+// *msgjson.DepthRequest from the URL parameters and the optional "levels"
+// query parameter.
+func depthParamsParser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		baseID, quoteID, errMsg := parseBaseQuoteIDs(r)
+		if errMsg != "" {
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		var levels int
+		if levelsStr := r.URL.Query().Get("levels"); levelsStr != "" {
+			var err error
+			levels, err = strconv.Atoi(levelsStr)
+			if err != nil {
+				http.Error(w, "levels unparseable", http.StatusBadRequest)
+				return
+			}
+		}
+		ctx := context.WithValue(r.Context(), comms.CtxThing, &msgjson.DepthRequest{
+			BaseID:  baseID,
+			QuoteID: quoteID,
+			Levels:  levels,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 // parseBaseQuoteIDs parses the "baseSymbol" and "quoteSymbol" URL parameters
 // from the request.
 func parseBaseQuoteIDs(r *http.Request) (baseID, quoteID uint32, errMsg string) {
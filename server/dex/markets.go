@@ -0,0 +1,560 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package dex
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/server/market"
+)
+
+// Markets may be added, modified, and delisted while the DEX is running. These
+// changes are not written to the markets configuration file, so the operator
+// should update the file to keep the changes when dcrdex is restarted.
+//
+// A modification or delisting takes effect when the market is suspended at the
+// scheduled time. A modified market is replaced by a new Market with the new
+// configuration, which resumes in the next epoch. The book is purged if the lot
+// size or rate step is changed, since the booked orders may be invalid for the
+// new configuration. A delisted market's book is purged, and the market is
+// removed. Connected clients are sent the updated configuration with a
+// config_update notification each time the markets change.
+
+// marketSet is the set of the DEX's markets. Markets may be added, replaced,
+// or removed while the DEX is running.
+type marketSet struct {
+	mtx     sync.RWMutex
+	markets map[string]*market.Market
+}
+
+func newMarketSet() *marketSet {
+	return &marketSet{
+		markets: make(map[string]*market.Market),
+	}
+}
+
+// get returns the named market, or nil if it is unknown.
+func (ms *marketSet) get(name string) *market.Market {
+	ms.mtx.RLock()
+	defer ms.mtx.RUnlock()
+	return ms.markets[name]
+}
+
+// all returns a copy of the markets map.
+func (ms *marketSet) all() map[string]*market.Market {
+	ms.mtx.RLock()
+	defer ms.mtx.RUnlock()
+	mkts := make(map[string]*market.Market, len(ms.markets))
+	for name, mkt := range ms.markets {
+		mkts[name] = mkt
+	}
+	return mkts
+}
+
+// set adds or replaces the named market.
+func (ms *marketSet) set(name string, mkt *market.Market) {
+	ms.mtx.Lock()
+	ms.markets[name] = mkt
+	ms.mtx.Unlock()
+}
+
+// remove removes the named market.
+func (ms *marketSet) remove(name string) {
+	ms.mtx.Lock()
+	delete(ms.markets, name)
+	ms.mtx.Unlock()
+}
+
+// MarketChange is a change to a market's configuration. Nil fields are not
+// changed.
+type MarketChange struct {
+	LotSize       *uint64 `json:"lotSize,omitempty"`
+	RateStep      *uint64 `json:"rateStep,omitempty"`
+	EpochDuration *uint64 `json:"epochDuration,omitempty"`
+	ParcelSize    *uint32 `json:"parcelSize,omitempty"`
+}
+
+// apply returns a copy of the MarketInfo with the change applied.
+func (mc *MarketChange) apply(mktInf *dex.MarketInfo) *dex.MarketInfo {
+	newInf := *mktInf
+	if mc.LotSize != nil {
+		newInf.LotSize = *mc.LotSize
+	}
+	if mc.RateStep != nil {
+		newInf.RateStep = *mc.RateStep
+	}
+	if mc.EpochDuration != nil {
+		newInf.EpochDuration = *mc.EpochDuration
+	}
+	if mc.ParcelSize != nil {
+		newInf.ParcelSize = *mc.ParcelSize
+	}
+	return &newInf
+}
+
+// validateMarketInfo checks the market parameters that may be set by the
+// operator while the DEX is running.
+func validateMarketInfo(mktInf *dex.MarketInfo) error {
+	switch {
+	case mktInf.LotSize == 0:
+		return fmt.Errorf("lot size cannot be zero")
+	case mktInf.RateStep == 0:
+		return fmt.Errorf("rate step cannot be zero")
+	case mktInf.EpochDuration == 0:
+		return fmt.Errorf("epoch duration cannot be zero")
+	case mktInf.ParcelSize == 0:
+		return fmt.Errorf("parcel size cannot be zero")
+	}
+	return nil
+}
+
+// marketConfig is the config response entry for the market.
+func marketConfig(name string, mkt *market.Market, startEpochIdx int64) *msgjson.Market {
+	return &msgjson.Market{
+		Name:            name,
+		Base:            mkt.Base(),
+		Quote:           mkt.Quote(),
+		LotSize:         mkt.LotSize(),
+		RateStep:        mkt.RateStep(),
+		EpochLen:        mkt.EpochDuration(),
+		MarketBuyBuffer: mkt.MarketBuyBuffer(),
+		ParcelSize:      mkt.ParcelSize(),
+		MarketStatus: msgjson.MarketStatus{
+			StartEpoch: uint64(startEpochIdx),
+		},
+	}
+}
+
+// setMarket adds the market to the config response, or replaces the market's
+// entry.
+func (cr *configResponse) setMarket(mkt *msgjson.Market) {
+	defer cr.remarshal()
+	for i, m := range cr.configMsg.Markets {
+		if m.Name == mkt.Name {
+			cr.configMsg.Markets[i] = mkt
+			return
+		}
+	}
+	cr.configMsg.Markets = append(cr.configMsg.Markets, mkt)
+}
+
+// setMktChange sets or clears the scheduled change for the market.
+func (cr *configResponse) setMktChange(name string, change *msgjson.MarketChange) {
+	for _, mkt := range cr.configMsg.Markets {
+		if mkt.Name == name {
+			mkt.ScheduledChange = change
+			cr.remarshal()
+			return
+		}
+	}
+	log.Errorf("Failed to update scheduled change for market %q", name)
+}
+
+// setMktMaintenance sets the maintenance window for the market.
+func (cr *configResponse) setMktMaintenance(name string, mt *msgjson.Maintenance) {
+	for _, mkt := range cr.configMsg.Markets {
+		if mkt.Name == name {
+			mkt.Maintenance = mt
+			cr.remarshal()
+			return
+		}
+	}
+	log.Errorf("Failed to update maintenance window for market %q", name)
+}
+
+// clearMktMaintenance clears the maintenance window for the market if it is
+// still mt. It returns true if the window was cleared.
+func (cr *configResponse) clearMktMaintenance(name string, mt *msgjson.Maintenance) bool {
+	for _, mkt := range cr.configMsg.Markets {
+		if mkt.Name == name && mkt.Maintenance == mt {
+			mkt.Maintenance = nil
+			cr.remarshal()
+			return true
+		}
+	}
+	return false
+}
+
+// removeMarket removes the market from the config response.
+func (cr *configResponse) removeMarket(name string) {
+	for i, m := range cr.configMsg.Markets {
+		if m.Name == name {
+			cr.configMsg.Markets = append(cr.configMsg.Markets[:i], cr.configMsg.Markets[i+1:]...)
+			cr.remarshal()
+			return
+		}
+	}
+}
+
+// broadcastConfig sends the current config response to all connected clients
+// in a config_update notification. The configRespMtx MUST be locked.
+func (dm *DEX) broadcastConfig() {
+	note, err := msgjson.NewNotification(msgjson.ConfigUpdateRoute, dm.configResp.configEnc)
+	if err != nil {
+		log.Errorf("Failed to create config update notification: %v", err)
+		return
+	}
+	dm.server.Broadcast(note)
+}
+
+// startMarket starts the market in the given epoch, and adds it to the markets,
+// the routers, and the config response, replacing any existing market with the
+// same name. The marketsMtx MUST be locked.
+func (dm *DEX) startMarket(name string, mkt *market.Market, startEpoch int64) {
+	mkt.SetStartEpochIdx(startEpoch)
+	dm.markets.set(name, mkt)
+	dm.orderRouter.SetMarket(name, mkt)
+	dm.bookRouter.SetBookSource(name, mkt)
+
+	ssw := dex.NewStartStopWaiter(mkt)
+	ssw.Start(context.Background()) // stopped with Stop
+	if i := dm.findSubsys(marketSubSysName(name)); i != -1 {
+		dm.subsystems[i].ssw = ssw
+	} else {
+		// Top of the stack, stopped before the subsystems the market uses.
+		dm.subsystems = append([]subsystem{{name: marketSubSysName(name), ssw: ssw}}, dm.subsystems...)
+	}
+
+	dm.configRespMtx.Lock()
+	dm.configResp.setMarket(marketConfig(name, mkt, startEpoch))
+	dm.broadcastConfig()
+	dm.configRespMtx.Unlock()
+}
+
+// AddMarket creates a new market and starts it as early as the given time. The
+// market's assets must be configured. Connected clients are notified of the
+// new market with a config_update notification.
+func (dm *DEX) AddMarket(mktInf *dex.MarketInfo, asSoonAs time.Time) (startEpoch int64, startTime time.Time, err error) {
+	name, err := dex.MarketName(mktInf.Base, mktInf.Quote)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if err = validateMarketInfo(mktInf); err != nil {
+		return 0, time.Time{}, err
+	}
+	for _, assetID := range []uint32{mktInf.Base, mktInf.Quote} {
+		if _, found := dm.assets[assetID]; !found {
+			return 0, time.Time{}, fmt.Errorf("asset %s is not configured", dex.BipIDSymbol(assetID))
+		}
+	}
+	mktInf.Name = name
+
+	dm.marketsMtx.Lock()
+	defer dm.marketsMtx.Unlock()
+	if dm.stopping {
+		return 0, time.Time{}, fmt.Errorf("DEX is stopping")
+	}
+	if dm.markets.get(name) != nil {
+		return 0, time.Time{}, fmt.Errorf("market %s already exists", name)
+	}
+	if change, found := dm.pendingChanges[name]; found {
+		return 0, time.Time{}, fmt.Errorf("market %s has a pending %s", name, change)
+	}
+
+	if err = dm.storage.PrepareMarket(mktInf); err != nil {
+		return 0, time.Time{}, fmt.Errorf("error preparing storage for market %s: %w", name, err)
+	}
+	mkt, err := dm.newMarket(mktInf)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("NewMarket failed: %w", err)
+	}
+	if err = dm.dataAPI.AddMarketSource(mkt); err != nil {
+		return 0, time.Time{}, fmt.Errorf("DataSource.AddMarketSource: %w", err)
+	}
+
+	startEpoch = mkt.ResumeEpoch(asSoonAs)
+	startTime = time.UnixMilli(startEpoch * int64(mktInf.EpochDuration))
+	dm.startMarket(name, mkt, startEpoch)
+
+	log.Infof("Added market %s, starting at epoch %d (%v). Update the markets "+
+		"configuration file to keep the market when dcrdex is restarted.", mktInf, startEpoch, startTime)
+	return startEpoch, startTime, nil
+}
+
+// ModifyMarket schedules a change to a market's configuration. The market is
+// suspended as early as the given time, and then resumes in the next epoch
+// with the new configuration. The book is purged if the lot size or rate step
+// is changed. Clients are notified of the suspension with a TradeSuspension
+// notification, and of the scheduled change with a config_update notification
+// so that they may prepare for it, e.g. by adjusting bot configurations before
+// the new lot size takes effect. Clients are sent the new configuration with
+// another config_update notification when the market resumes.
+func (dm *DEX) ModifyMarket(name string, change *MarketChange, asSoonAs time.Time) (*market.SuspendEpoch, error) {
+	name = strings.ToLower(name)
+
+	dm.marketsMtx.Lock()
+	defer dm.marketsMtx.Unlock()
+	if dm.stopping {
+		return nil, fmt.Errorf("DEX is stopping")
+	}
+	mkt := dm.markets.get(name)
+	if mkt == nil {
+		return nil, fmt.Errorf("unknown market %s", name)
+	}
+	if change, found := dm.pendingChanges[name]; found {
+		return nil, fmt.Errorf("market %s has a pending %s", name, change)
+	}
+
+	oldInf := mkt.Info()
+	newInf := change.apply(oldInf)
+	if *newInf == *oldInf {
+		return nil, fmt.Errorf("no change to market %s", name)
+	}
+	if err := validateMarketInfo(newInf); err != nil {
+		return nil, err
+	}
+
+	persist := newInf.LotSize == oldInf.LotSize && newInf.RateStep == oldInf.RateStep
+	suspEpoch, err := dm.suspendMarket(name, asSoonAs, persist)
+	if err != nil {
+		return nil, err
+	}
+
+	dm.configRespMtx.Lock()
+	dm.configResp.setMktChange(name, scheduledChange(oldInf, newInf, uint64(suspEpoch.Idx)))
+	dm.broadcastConfig()
+	dm.configRespMtx.Unlock()
+
+	dm.afterSuspend(name, "modification", func() error {
+		err := dm.replaceMarket(name, mkt, newInf)
+		if err != nil {
+			dm.configRespMtx.Lock()
+			dm.configResp.setMktChange(name, nil)
+			dm.broadcastConfig()
+			dm.configRespMtx.Unlock()
+		}
+		return err
+	})
+	return suspEpoch, nil
+}
+
+// scheduledChange describes the changed parameters for the config response.
+func scheduledChange(oldInf, newInf *dex.MarketInfo, finalEpoch uint64) *msgjson.MarketChange {
+	change := &msgjson.MarketChange{FinalEpoch: finalEpoch}
+	if newInf.LotSize != oldInf.LotSize {
+		change.LotSize = newInf.LotSize
+	}
+	if newInf.RateStep != oldInf.RateStep {
+		change.RateStep = newInf.RateStep
+	}
+	if newInf.EpochDuration != oldInf.EpochDuration {
+		change.EpochLen = newInf.EpochDuration
+	}
+	if newInf.ParcelSize != oldInf.ParcelSize {
+		change.ParcelSize = newInf.ParcelSize
+	}
+	return change
+}
+
+// replaceMarket replaces the stopped market with a new Market with the new
+// configuration, which starts in the next epoch. The marketsMtx MUST be locked.
+func (dm *DEX) replaceMarket(name string, oldMkt *market.Market, mktInf *dex.MarketInfo) error {
+	// The new Market loads any persisted book orders, and locks their coins.
+	oldMkt.UnlockBookCoins()
+
+	if err := dm.storage.PrepareMarket(mktInf); err != nil {
+		return fmt.Errorf("error preparing storage: %w", err)
+	}
+	mkt, err := dm.newMarket(mktInf)
+	if err != nil {
+		return fmt.Errorf("NewMarket failed: %w", err)
+	}
+	for _, assetID := range []uint32{mktInf.Base, mktInf.Quote} {
+		if scale := oldMkt.FeeRateScale(assetID); scale != 0 {
+			mkt.SetFeeRateScale(assetID, scale)
+		}
+	}
+	if err = dm.dataAPI.AddMarketSource(mkt); err != nil {
+		return fmt.Errorf("DataSource.AddMarketSource: %w", err)
+	}
+
+	startEpoch := mkt.ResumeEpoch(time.Time{})
+	startTimeMS := startEpoch * int64(mktInf.EpochDuration)
+	dm.startMarket(name, mkt, startEpoch)
+
+	// Also send the TradeResumption notification that is sent when a market is
+	// resumed, since clients expect one after a suspension.
+	note, errMsg := msgjson.NewNotification(msgjson.ResumptionRoute, msgjson.TradeResumption{
+		MarketID:   name,
+		ResumeTime: uint64(startTimeMS),
+		StartEpoch: uint64(startEpoch),
+	})
+	if errMsg != nil {
+		log.Errorf("Failed to create resume notification: %v", errMsg)
+	} else {
+		dm.server.Broadcast(note)
+	}
+
+	log.Infof("Modified market %s, resuming at epoch %d (%v). Update the markets "+
+		"configuration file to keep the changes when dcrdex is restarted.",
+		mktInf, startEpoch, time.UnixMilli(startTimeMS))
+	return nil
+}
+
+// DelistMarket schedules the removal of a market. The market is suspended as
+// early as the given time, purging the book, and then removed. Clients are
+// notified of the suspension with a TradeSuspension notification, and of the
+// removal with a config_update notification. Swaps in progress for the market
+// are unaffected.
+func (dm *DEX) DelistMarket(name string, asSoonAs time.Time) (*market.SuspendEpoch, error) {
+	name = strings.ToLower(name)
+
+	dm.marketsMtx.Lock()
+	defer dm.marketsMtx.Unlock()
+	if dm.stopping {
+		return nil, fmt.Errorf("DEX is stopping")
+	}
+	if dm.markets.get(name) == nil {
+		return nil, fmt.Errorf("unknown market %s", name)
+	}
+	if change, found := dm.pendingChanges[name]; found {
+		return nil, fmt.Errorf("market %s has a pending %s", name, change)
+	}
+
+	suspEpoch, err := dm.suspendMarket(name, asSoonAs, false)
+	if err != nil {
+		return nil, err
+	}
+
+	dm.afterSuspend(name, "delisting", func() error {
+		dm.markets.remove(name)
+		dm.orderRouter.RemoveMarket(name)
+		dm.bookRouter.RemoveBook(name)
+		if i := dm.findSubsys(marketSubSysName(name)); i != -1 {
+			dm.subsystems = append(dm.subsystems[:i], dm.subsystems[i+1:]...)
+		}
+
+		dm.configRespMtx.Lock()
+		dm.configResp.removeMarket(name)
+		dm.broadcastConfig()
+		dm.configRespMtx.Unlock()
+
+		log.Infof("Delisted market %s. Update the markets configuration file to "+
+			"keep the market delisted when dcrdex is restarted.", name)
+		return nil
+	})
+	return suspEpoch, nil
+}
+
+// ScheduleMaintenance schedules a maintenance window for a market. The market is
+// suspended as early as the start time, and then resumes as early as the end
+// time. The window and the reason for the maintenance are sent to connected
+// clients with a config_update notification ahead of the suspension, and
+// remain in the market's configuration until trading resumes.
+func (dm *DEX) ScheduleMaintenance(name string, start, end time.Time, persist bool, reason string) (*msgjson.Maintenance, error) {
+	name = strings.ToLower(name)
+	if !end.After(start) || !end.After(time.Now()) {
+		return nil, fmt.Errorf("maintenance must end after it starts")
+	}
+
+	dm.marketsMtx.Lock()
+	defer dm.marketsMtx.Unlock()
+	if dm.stopping {
+		return nil, fmt.Errorf("DEX is stopping")
+	}
+	if dm.markets.get(name) == nil {
+		return nil, fmt.Errorf("unknown market %s", name)
+	}
+	if change, found := dm.pendingChanges[name]; found {
+		return nil, fmt.Errorf("market %s has a pending %s", name, change)
+	}
+
+	suspEpoch, err := dm.suspendMarket(name, start, persist)
+	if err != nil {
+		return nil, err
+	}
+	if !end.After(suspEpoch.End) {
+		// The market can't resume before it is suspended.
+		end = suspEpoch.End
+	}
+
+	mt := &msgjson.Maintenance{
+		FinalEpoch:  uint64(suspEpoch.Idx),
+		SuspendTime: uint64(suspEpoch.End.UnixMilli()),
+		ResumeTime:  uint64(end.UnixMilli()),
+		Persist:     persist,
+		Reason:      reason,
+	}
+	dm.configRespMtx.Lock()
+	dm.configResp.setMktMaintenance(name, mt)
+	dm.broadcastConfig()
+	dm.configRespMtx.Unlock()
+
+	clearMaintenance := func() {
+		dm.configRespMtx.Lock()
+		if dm.configResp.clearMktMaintenance(name, mt) {
+			dm.broadcastConfig()
+		}
+		dm.configRespMtx.Unlock()
+	}
+
+	dm.afterSuspend(name, "maintenance", func() error {
+		_, startTime, err := dm.resumeMarket(name, end)
+		if err != nil {
+			clearMaintenance()
+			return err
+		}
+		time.AfterFunc(time.Until(startTime), func() {
+			dm.marketsMtx.Lock()
+			defer dm.marketsMtx.Unlock()
+			if !dm.stopping {
+				clearMaintenance()
+			}
+		})
+		log.Infof("Market %s maintenance ends at %v.", name, startTime)
+		return nil
+	})
+
+	log.Infof("Scheduled maintenance of market %s from %v to %v: %s", name, suspEpoch.End, end, reason)
+	return mt, nil
+}
+
+// PendingMarketChanges returns descriptions of the scheduled market changes
+// that are waiting for the markets to be suspended, keyed by market name.
+func (dm *DEX) PendingMarketChanges() map[string]string {
+	dm.marketsMtx.Lock()
+	defer dm.marketsMtx.Unlock()
+	changes := make(map[string]string, len(dm.pendingChanges))
+	for name, change := range dm.pendingChanges {
+		changes[name] = change
+	}
+	return changes
+}
+
+// afterSuspend records a pending change for the market, and launches a
+// goroutine that makes the change with the marketsMtx locked once the market
+// has been suspended. The change is abandoned if the DEX is stopped first. The
+// marketsMtx MUST be locked.
+func (dm *DEX) afterSuspend(name, desc string, do func() error) {
+	i := dm.findSubsys(marketSubSysName(name))
+	if i == -1 { // checked by suspendMarket
+		log.Errorf("market subsystem %s not found", name)
+		return
+	}
+	ssw := dm.subsystems[i].ssw
+	dm.pendingChanges[name] = desc
+
+	dm.wg.Add(1)
+	go func() {
+		defer dm.wg.Done()
+		ssw.WaitForShutdown()
+
+		dm.marketsMtx.Lock()
+		defer dm.marketsMtx.Unlock()
+		delete(dm.pendingChanges, name)
+		if dm.stopping {
+			log.Warnf("Abandoning %s of market %s for shutdown.", desc, name)
+			return
+		}
+		if err := do(); err != nil {
+			log.Errorf("Failed %s of market %s. The market remains suspended: %v", desc, name, err)
+		}
+	}()
+}
@@ -203,6 +203,21 @@ type msgBook struct {
 	source        BookSource
 	baseID        uint32
 	quoteID       uint32
+
+	// stop and done are set when the book's monitoring loop is started.
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+func newMsgBook(name string, src BookSource, subs *subscribers) *msgBook {
+	return &msgBook{
+		name:    name,
+		orders:  make(map[order.OrderID]*msgjson.BookOrderNote),
+		subs:    subs,
+		source:  src,
+		baseID:  src.Base(),
+		quoteID: src.Quote(),
+	}
 }
 
 func (book *msgBook) setEpoch(idx int64) {
@@ -259,6 +274,17 @@ func (book *msgBook) update(lo *order.LimitOrder) *msgjson.BookOrderNote {
 	return msgOrder
 }
 
+// checksum computes the order.BookChecksum of the booked orders.
+func (book *msgBook) checksum() order.BookChecksum {
+	book.mtx.RLock()
+	defer book.mtx.RUnlock()
+	var csum order.BookChecksum
+	for oid, o := range book.orders {
+		csum.Add(oid, o.Quantity)
+	}
+	return csum
+}
+
 // Remove the order from the order book.
 func (book *msgBook) remove(lo *order.LimitOrder) {
 	book.mtx.Lock()
@@ -283,9 +309,12 @@ func (book *msgBook) addBulkOrders(epoch int64, orderSets ...[]*order.LimitOrder
 // of subscribers, and maintaining an intermediate copy of the orderbook in
 // message payload format for quick, full-book syncing.
 type BookRouter struct {
-	books     map[string]*msgBook
 	feeSource FeeSource
 
+	booksMtx sync.RWMutex
+	books    map[string]*msgBook
+	ctx      context.Context // set by Run
+
 	priceFeeders *subscribers
 	spotsMtx     sync.RWMutex
 	spots        map[string]*msgjson.Spot
@@ -308,15 +337,7 @@ func NewBookRouter(sources map[string]BookSource, feeSource FeeSource, route fun
 		subs := &subscribers{
 			conns: make(map[uint64]comms.Link),
 		}
-		book := &msgBook{
-			name:    mkt,
-			orders:  make(map[order.OrderID]*msgjson.BookOrderNote),
-			subs:    subs,
-			source:  src,
-			baseID:  src.Base(),
-			quoteID: src.Quote(),
-		}
-		router.books[mkt] = book
+		router.books[mkt] = newMsgBook(mkt, src, subs)
 	}
 	route(msgjson.OrderBookRoute, router.handleOrderBook)
 	route(msgjson.UnsubOrderBookRoute, router.handleUnsubOrderBook)
@@ -328,15 +349,81 @@ func NewBookRouter(sources map[string]BookSource, feeSource FeeSource, route fun
 
 // Run implements dex.Runner, and is blocking.
 func (r *BookRouter) Run(ctx context.Context) {
-	var wg sync.WaitGroup
+	r.booksMtx.Lock()
+	r.ctx = ctx
 	for _, b := range r.books {
-		wg.Add(1)
-		go func(b *msgBook) {
-			r.runBook(ctx, b)
-			wg.Done()
-		}(b)
+		r.startBook(b)
+	}
+	r.booksMtx.Unlock()
+
+	<-ctx.Done()
+	// Wait for the books' loops to return. Once the context is cancelled, no
+	// more books are started.
+	r.booksMtx.Lock()
+	for _, b := range r.books {
+		r.stopBook(b)
+	}
+	r.booksMtx.Unlock()
+}
+
+// startBook starts the monitoring loop for the book. The booksMtx MUST be
+// locked.
+func (r *BookRouter) startBook(book *msgBook) {
+	if r.ctx == nil || r.ctx.Err() != nil {
+		return // not running
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	book.stop = cancel
+	book.done = make(chan struct{})
+	go func() {
+		defer close(book.done)
+		r.runBook(ctx, book)
+	}()
+}
+
+// stopBook stops the monitoring loop for the book, if it was started, and waits
+// for it to return. The booksMtx MUST be locked.
+func (r *BookRouter) stopBook(book *msgBook) {
+	if book.stop == nil {
+		return
 	}
-	wg.Wait()
+	book.stop()
+	<-book.done
+}
+
+// SetBookSource adds the book for a market, or replaces the source of a
+// market's book, as when the market's configuration is modified while the DEX
+// is running. The subscribers to a replaced book are retained.
+func (r *BookRouter) SetBookSource(mktName string, src BookSource) {
+	r.booksMtx.Lock()
+	defer r.booksMtx.Unlock()
+	subs := &subscribers{
+		conns: make(map[uint64]comms.Link),
+	}
+	if oldBook := r.books[mktName]; oldBook != nil {
+		r.stopBook(oldBook)
+		subs = oldBook.subs
+	}
+	book := newMsgBook(mktName, src, subs)
+	r.books[mktName] = book
+	r.startBook(book)
+}
+
+// RemoveBook removes the book of a delisted market.
+func (r *BookRouter) RemoveBook(mktName string) {
+	r.booksMtx.Lock()
+	defer r.booksMtx.Unlock()
+	if book := r.books[mktName]; book != nil {
+		r.stopBook(book)
+		delete(r.books, mktName)
+	}
+}
+
+// book returns the book for the named market.
+func (r *BookRouter) book(mktName string) *msgBook {
+	r.booksMtx.RLock()
+	defer r.booksMtx.RUnlock()
+	return r.books[mktName]
 }
 
 // runBook is a monitoring loop for an order book.
@@ -435,6 +522,10 @@ out:
 				}
 				book.addRecentMatches(matchesWithTimestamp)
 
+				// The book is not modified between the last sequenced note
+				// and the checksum since this goroutine does both.
+				csum := book.checksum()
+
 				note = &msgjson.EpochReportNote{
 					MarketID:     book.name,
 					Epoch:        uint64(sigData.epochIdx),
@@ -451,6 +542,8 @@ out:
 						EndRate:     stats.EndRate,
 					},
 					MatchSummary: sigData.matches,
+					BookSeq:      subs.lastSeq(),
+					BookChecksum: csum[:],
 				}
 
 			case sigDataEpochOrder:
@@ -549,7 +642,7 @@ out:
 
 // Book creates a copy of the book as a *msgjson.OrderBook.
 func (r *BookRouter) Book(mktName string) (*msgjson.OrderBook, error) {
-	book := r.books[mktName]
+	book := r.book(mktName)
 	if book == nil {
 		return nil, fmt.Errorf("market %s unknown", mktName)
 	}
@@ -567,7 +660,11 @@ func (r *BookRouter) sendBook(conn comms.Link, book *msgBook, msgID uint64) {
 		conn.SendError(msgID, msgjson.NewError(msgjson.MarketNotRunningError, "market not running"))
 		return
 	}
-	msg, err := msgjson.NewResponse(msgID, msgOB, nil)
+	newResponse := msgjson.NewResponse
+	if conn.CBOR() {
+		newResponse = msgjson.NewResponseCBOR
+	}
+	msg, err := newResponse(msgID, msgOB, nil)
 	if err != nil {
 		log.Errorf("error encoding 'orderbook' response: %v", err)
 		return
@@ -627,8 +724,8 @@ func (r *BookRouter) handleOrderBook(conn comms.Link, msg *msgjson.Message) *msg
 			Message: "market name error: " + err.Error(),
 		}
 	}
-	book, found := r.books[mkt]
-	if !found {
+	book := r.book(mkt)
+	if book == nil {
 		return &msgjson.Error{
 			Code:    msgjson.UnknownMarket,
 			Message: "unknown market",
@@ -651,7 +748,7 @@ func (r *BookRouter) handleUnsubOrderBook(conn comms.Link, msg *msgjson.Message)
 			Message: "error parsing unsub_orderbook request",
 		}
 	}
-	book := r.books[unsub.MarketID]
+	book := r.book(unsub.MarketID)
 	if book == nil {
 		return &msgjson.Error{
 			Code:    msgjson.UnknownMarket,
@@ -737,10 +834,35 @@ func (r *BookRouter) sendNote(route string, subs *subscribers, note any) {
 		log.Errorf("unable to marshal notification-type Message: %v", err)
 		return
 	}
+	// The CBOR encoding is only created if there is a subscriber that
+	// accepts it.
+	var cborB []byte
+	encodeCBOR := func() ([]byte, error) {
+		if cborB != nil {
+			return cborB, nil
+		}
+		msg, err := msgjson.NewNotificationCBOR(route, note)
+		if err != nil {
+			return nil, err
+		}
+		cborB, err = msg.EncodeCBOR()
+		return cborB, err
+	}
 
 	var deletes []uint64
 	subs.mtx.RLock()
 	for _, conn := range subs.conns {
+		if conn.CBOR() {
+			cb, err := encodeCBOR()
+			if err == nil {
+				if err := conn.SendRawCBOR(cb); err != nil {
+					deletes = append(deletes, conn.ID())
+				}
+				continue
+			}
+			// Fall back to JSON.
+			log.Errorf("unable to CBOR-encode %s notification: %v", route, err)
+		}
 		err := conn.SendRaw(b)
 		if err != nil {
 			deletes = append(deletes, conn.ID())
@@ -659,6 +659,12 @@ func (m *Market) Running() bool {
 	}
 }
 
+// Info returns a copy of the Market's configuration.
+func (m *Market) Info() *dex.MarketInfo {
+	mktInf := *m.marketInfo
+	return &mktInf
+}
+
 // EpochDuration returns the Market's epoch duration in milliseconds.
 func (m *Market) EpochDuration() uint64 {
 	return m.marketInfo.EpochDuration
@@ -1274,6 +1280,25 @@ func (m *Market) Book() (epoch int64, buys, sells []*order.LimitOrder) {
 	return
 }
 
+// UnlockBookCoins unlocks the funding coins of all booked orders. This is used
+// when a stopped Market is replaced by a new Market that loads the same booked
+// orders from storage, as when the market's configuration is modified, since
+// the new Market locks the coins again.
+func (m *Market) UnlockBookCoins() {
+	m.bookMtx.Lock()
+	defer m.bookMtx.Unlock()
+	if m.coinLockerBase != nil {
+		for _, lo := range m.book.SellOrders() {
+			m.coinLockerBase.UnlockOrderCoins(lo.ID())
+		}
+	}
+	if m.coinLockerQuote != nil {
+		for _, lo := range m.book.BuyOrders() {
+			m.coinLockerQuote.UnlockOrderCoins(lo.ID())
+		}
+	}
+}
+
 // PurgeBook flushes all booked orders from the in-memory book and persistent
 // storage. In terms of storage, this means changing orders with status booked
 // to status revoked.
@@ -2736,6 +2761,20 @@ func (m *Market) SetFeeRateScale(assetID uint32, scale float64) {
 	m.feeScalesMtx.Unlock()
 }
 
+// FeeRateScale returns the swap fee rate scale factor set for the asset with
+// SetFeeRateScale, or zero if it was not set.
+func (m *Market) FeeRateScale(assetID uint32) float64 {
+	m.feeScalesMtx.RLock()
+	defer m.feeScalesMtx.RUnlock()
+	switch assetID {
+	case m.marketInfo.Base:
+		return m.feeScales.base
+	case m.marketInfo.Quote:
+		return m.feeScales.quote
+	}
+	return 0
+}
+
 // ScaleFeeRate scales the provided fee rate with the given asset's swap fee
 // rate scale factor, which is 1.0 by default.
 func (m *Market) ScaleFeeRate(assetID uint32, feeRate uint64) uint64 {
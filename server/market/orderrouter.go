@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sync"
 	"time"
 
 	"decred.org/dcrdex/dex"
@@ -149,11 +150,13 @@ type MatchSwapper interface {
 type OrderRouter struct {
 	auth        AuthManager
 	assets      map[uint32]*asset.BackedAsset
-	tunnels     map[string]MarketTunnel
 	latencyQ    *wait.TickerQueue
 	feeSource   FeeSource
 	dexBalancer *DEXBalancer
 	swapper     MatchSwapper
+
+	tunnelsMtx sync.RWMutex
+	tunnels    map[string]MarketTunnel
 }
 
 // OrderRouterConfig is the configuration settings for an OrderRouter.
@@ -187,6 +190,41 @@ func (r *OrderRouter) Run(ctx context.Context) {
 	r.latencyQ.Run(ctx)
 }
 
+// SetMarket adds a market, or replaces the MarketTunnel of a market whose
+// configuration is modified while the DEX is running.
+func (r *OrderRouter) SetMarket(mktName string, tunnel MarketTunnel) {
+	r.tunnelsMtx.Lock()
+	r.tunnels[mktName] = tunnel
+	r.tunnelsMtx.Unlock()
+}
+
+// RemoveMarket removes a delisted market. Orders for the market will be
+// rejected as for an unknown market.
+func (r *OrderRouter) RemoveMarket(mktName string) {
+	r.tunnelsMtx.Lock()
+	delete(r.tunnels, mktName)
+	r.tunnelsMtx.Unlock()
+}
+
+// tunnel returns the MarketTunnel for the named market.
+func (r *OrderRouter) tunnel(mktName string) (MarketTunnel, bool) {
+	r.tunnelsMtx.RLock()
+	defer r.tunnelsMtx.RUnlock()
+	tunnel, found := r.tunnels[mktName]
+	return tunnel, found
+}
+
+// allTunnels returns a copy of the tunnels map.
+func (r *OrderRouter) allTunnels() map[string]MarketTunnel {
+	r.tunnelsMtx.RLock()
+	defer r.tunnelsMtx.RUnlock()
+	tunnels := make(map[string]MarketTunnel, len(r.tunnels))
+	for mktName, tunnel := range r.tunnels {
+		tunnels[mktName] = tunnel
+	}
+	return tunnels
+}
+
 func (r *OrderRouter) respondError(reqID uint64, user account.AccountID, msgErr *msgjson.Error) {
 	log.Debugf("Error going to user %v: %s", user, msgErr)
 	msg, err := msgjson.NewResponse(reqID, nil, msgErr)
@@ -494,7 +532,7 @@ func (r *OrderRouter) processTrade(oRecord *orderRecord, tunnel MarketTunnel, as
 
 	// Use this as a chance to check user's existing market orders.
 	// TODO: check all markets?
-	for mktName, tunnel := range r.tunnels {
+	for mktName, tunnel := range r.allTunnels() {
 		unbookedUnfunded := tunnel.CheckUnfilled(assets.funding.ID, oRecord.order.User())
 		for _, badLo := range unbookedUnfunded {
 			log.Infof("Unbooked unfunded order %v from market %s for user %v", badLo, mktName, oRecord.order.User())
@@ -725,7 +763,7 @@ func (r *OrderRouter) CheckParcelLimit(user account.AccountID, targetMarketName
 
 	var otherMarketParcels float64
 	var settlingQty uint64
-	for mktName, mkt := range r.tunnels {
+	for mktName, mkt := range r.allTunnels() {
 		if mktName == targetMarketName {
 			settlingQty = settlingQuantities[mktName]
 			continue
@@ -881,7 +919,7 @@ func (r *OrderRouter) extractMarket(prefix *msgjson.Prefix) (MarketTunnel, *msgj
 	if err != nil {
 		return nil, msgjson.NewError(msgjson.UnknownMarketError, "asset lookup error: %v", err.Error())
 	}
-	tunnel, found := r.tunnels[mktName]
+	tunnel, found := r.tunnel(mktName)
 	if !found {
 		return nil, msgjson.NewError(msgjson.UnknownMarketError, "unknown market %s", mktName)
 	}
@@ -904,7 +942,7 @@ type SuspendEpoch struct {
 // blocking order submission according to the schedule rather than just checking
 // Market.Running prior to submitting incoming orders to the Market.
 func (r *OrderRouter) SuspendMarket(mktName string, asSoonAs time.Time, persistBooks bool) *SuspendEpoch {
-	mkt, found := r.tunnels[mktName]
+	mkt, found := r.tunnel(mktName)
 	if !found {
 		return nil
 	}
@@ -919,8 +957,9 @@ func (r *OrderRouter) SuspendMarket(mktName string, asSoonAs time.Time, persistB
 // Suspend is like SuspendMarket, but for all known markets.
 func (r *OrderRouter) Suspend(asSoonAs time.Time, persistBooks bool) map[string]*SuspendEpoch {
 
-	suspendTimes := make(map[string]*SuspendEpoch, len(r.tunnels))
-	for name, mkt := range r.tunnels {
+	tunnels := r.allTunnels()
+	suspendTimes := make(map[string]*SuspendEpoch, len(tunnels))
+	for name, mkt := range tunnels {
 		idx, ts := mkt.Suspend(asSoonAs, persistBooks)
 		suspendTimes[name] = &SuspendEpoch{Idx: idx, End: ts}
 	}
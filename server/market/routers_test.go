@@ -1519,6 +1519,7 @@ type TLink struct {
 	on          uint32
 	closed      chan struct{}
 	sendRawErr  error
+	cbor        bool
 }
 
 var linkCounter uint64
@@ -1562,6 +1563,21 @@ func (conn *TLink) SendRaw(b []byte) error {
 	conn.sendTrigger <- struct{}{}
 	return nil
 }
+func (conn *TLink) CBOR() bool { return conn.cbor }
+func (conn *TLink) SendRawCBOR(b []byte) error {
+	conn.mtx.Lock()
+	defer conn.mtx.Unlock()
+	if conn.sendRawErr != nil {
+		return conn.sendRawErr
+	}
+	msg, err := msgjson.DecodeCBORMessage(b)
+	if err != nil {
+		return err
+	}
+	conn.sends = append(conn.sends, msg)
+	conn.sendTrigger <- struct{}{}
+	return nil
+}
 func (conn *TLink) SendError(id uint64, msgErr *msgjson.Error) {
 	msg, err := msgjson.NewResponse(id, nil, msgErr)
 	if err != nil {
@@ -1669,8 +1685,11 @@ func getEpochNoteFromLink(t *testing.T, link *TLink) *msgjson.EpochOrderNote {
 	if noteMsg == nil {
 		t.Fatalf("no epoch notification sent")
 	}
+	if noteMsg.IsCBOR() != link.cbor {
+		t.Fatalf("wrong notification encoding, CBOR = %t", noteMsg.IsCBOR())
+	}
 	epochNote := new(msgjson.EpochOrderNote)
-	err := json.Unmarshal(noteMsg.Payload, epochNote)
+	err := noteMsg.Unmarshal(epochNote)
 	if err != nil {
 		t.Fatalf("error unmarshaling epoch notification: %v", err)
 	}
@@ -1683,8 +1702,11 @@ func getBookNoteFromLink(t *testing.T, link *TLink) *msgjson.BookOrderNote {
 	if noteMsg == nil {
 		t.Fatalf("no epoch notification sent")
 	}
+	if noteMsg.IsCBOR() != link.cbor {
+		t.Fatalf("wrong notification encoding, CBOR = %t", noteMsg.IsCBOR())
+	}
 	bookNote := new(msgjson.BookOrderNote)
-	err := json.Unmarshal(noteMsg.Payload, bookNote)
+	err := noteMsg.Unmarshal(bookNote)
 	if err != nil {
 		t.Fatalf("error unmarshaling epoch notification: %v", err)
 	}
@@ -1697,8 +1719,11 @@ func getUpdateRemainingNoteFromLink(t *testing.T, link *TLink) *msgjson.UpdateRe
 	if noteMsg == nil {
 		t.Fatalf("no epoch notification sent")
 	}
+	if noteMsg.IsCBOR() != link.cbor {
+		t.Fatalf("wrong notification encoding, CBOR = %t", noteMsg.IsCBOR())
+	}
 	urNote := new(msgjson.UpdateRemainingNote)
-	err := json.Unmarshal(noteMsg.Payload, urNote)
+	err := noteMsg.Unmarshal(urNote)
 	if err != nil {
 		t.Fatalf("error unmarshaling epoch notification: %v", err)
 	}
@@ -1711,8 +1736,11 @@ func getUnbookNoteFromLink(t *testing.T, link *TLink) *msgjson.UnbookOrderNote {
 	if noteMsg == nil {
 		t.Fatalf("no epoch notification sent")
 	}
+	if noteMsg.IsCBOR() != link.cbor {
+		t.Fatalf("wrong notification encoding, CBOR = %t", noteMsg.IsCBOR())
+	}
 	unbookNote := new(msgjson.UnbookOrderNote)
-	err := json.Unmarshal(noteMsg.Payload, unbookNote)
+	err := noteMsg.Unmarshal(unbookNote)
 	if err != nil {
 		t.Fatalf("error unmarshaling epoch notification: %v", err)
 	}
@@ -1745,17 +1773,12 @@ func TestRouter(t *testing.T) {
 		if respMsg.ID != msgID {
 			t.Fatalf("(%s): wrong ID for response. wanted %d, got %d", tag, msgID, respMsg.ID)
 		}
-		resp, err := respMsg.Response()
-		if err != nil {
-			t.Fatalf("(%s): error parsing response: %v", tag, err)
-		}
-		if resp.Error != nil {
-			t.Fatalf("response is error: %v", resp.Error)
+		if respMsg.IsCBOR() != conn.cbor {
+			t.Fatalf("(%s): wrong response encoding, CBOR = %t", tag, respMsg.IsCBOR())
 		}
 		book := new(msgjson.OrderBook)
-		err = json.Unmarshal(resp.Result, book)
-		if err != nil {
-			t.Fatalf("(%s): unmarshal error: %v", tag, err)
+		if err := respMsg.UnmarshalResult(book); err != nil {
+			t.Fatalf("(%s): error parsing response: %v", tag, err)
 		}
 		if len(book.Orders) != 16 {
 			t.Fatalf("(%s): expected 16 orders, received %d", tag, len(book.Orders))
@@ -1818,8 +1841,10 @@ func TestRouter(t *testing.T) {
 	orders := checkResponse("first link, market 1", mktName1, sub.ID, link1)
 	checkBook(src1, msgjson.StandingOrderNum, "first link, market 1", orders...)
 
-	// Another subscriber to the same market should behave identically.
+	// Another subscriber to the same market should behave identically, except
+	// that it accepts CBOR-encoded messages.
 	link2, sub := newSubscriber(mkt1)
+	link2.cbor = true
 	if err := router.handleOrderBook(link2, sub); err != nil {
 		t.Fatalf("handleOrderBook: %v", err)
 	}